@@ -1,6 +1,7 @@
 package babylonclient
 
 import (
+	"bytes"
 	"context"
 	"encoding/hex"
 	"errors"
@@ -9,7 +10,6 @@ import (
 	"strings"
 	"time"
 
-	sdkErr "cosmossdk.io/errors"
 	sdkmath "cosmossdk.io/math"
 	"github.com/avast/retry-go/v4"
 	bbntypes "github.com/babylonchain/babylon/types"
@@ -19,7 +19,7 @@ import (
 	"github.com/babylonchain/btc-staker/stakercfg"
 	"github.com/babylonchain/btc-staker/stakerdb"
 	"github.com/babylonchain/btc-staker/utils"
-	bbnclient "github.com/babylonchain/rpc-client/client"
+	"github.com/babylonchain/rpc-client/query"
 	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/btcsuite/btcd/btcec/v2/schnorr"
 	"github.com/btcsuite/btcd/btcutil"
@@ -37,6 +37,9 @@ import (
 	"go.uber.org/zap"
 )
 
+// number of delegations retrieved in one query when scanning all delegations
+const delegationsQueryPageSize = 100
+
 var (
 	// TODO: Maybe configurable?
 	RtyAttNum = uint(5)
@@ -56,10 +59,11 @@ var (
 )
 
 type BabylonController struct {
-	bbnClient *bbnclient.Client
-	cfg       *stakercfg.BBNConfig
-	btcParams *chaincfg.Params
-	logger    *logrus.Logger
+	queryClient *query.QueryClient
+	memoSender  *memoSender
+	cfg         *stakercfg.BBNConfig
+	btcParams   *chaincfg.Params
+	logger      *logrus.Logger
 }
 
 var _ BabylonClient = (*BabylonController)(nil)
@@ -67,6 +71,8 @@ var _ BabylonClient = (*BabylonController)(nil)
 func NewBabylonController(
 	cfg *stakercfg.BBNConfig,
 	btcParams *chaincfg.Params,
+	// nil if babylon node is connected directly
+	proxy *stakercfg.ProxyConfig,
 	logger *logrus.Logger,
 	clientLogger *zap.Logger,
 ) (*BabylonController, error) {
@@ -77,10 +83,15 @@ func NewBabylonController(
 		return nil, err
 	}
 
-	bc, err := bbnclient.New(
-		&babylonConfig,
-		clientLogger,
-	)
+	ms, err := newMemoSender(&babylonConfig, cfg.FeeGranter, cfg.FeePayer, proxy, logger, clientLogger)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// queries use rpc client of memo sender, so that they go through the same
+	// connection as transactions
+	qc, err := query.NewWithClient(ms.provider.RPCClient, babylonConfig.Timeout)
 
 	if err != nil {
 		return nil, err
@@ -88,7 +99,8 @@ func NewBabylonController(
 
 	// wrap to our type
 	client := &BabylonController{
-		bc,
+		qc,
+		ms,
 		cfg,
 		btcParams,
 		logger,
@@ -110,11 +122,18 @@ type StakingTrackerResponse struct {
 type FinalityProviderInfo struct {
 	BabylonPk secp256k1.PubKey
 	BtcPk     btcec.PublicKey
+	// empty if finality provider did not register description
+	Moniker    string
+	Commission sdkmath.LegacyDec
+	Slashed    bool
 }
 
 type FinalityProvidersClientResponse struct {
 	FinalityProviders []FinalityProviderInfo
-	Total             uint64
+	// only counted when querying by offset
+	Total uint64
+	// key of the next page, empty if there are no more finality providers
+	NextKey []byte
 }
 
 type FinalityProviderClientResponse struct {
@@ -123,7 +142,7 @@ type FinalityProviderClientResponse struct {
 
 // Copied from vigilante. Weirdly, there is only Stop function (no Start function ?)
 func (bc *BabylonController) Stop() error {
-	return bc.bbnClient.Stop()
+	return bc.memoSender.stop()
 }
 
 func (bc *BabylonController) Params() (*StakingParams, error) {
@@ -133,7 +152,7 @@ func (bc *BabylonController) Params() (*StakingParams, error) {
 	var bccParams *bcctypes.Params
 	if err := retry.Do(func() error {
 
-		response, err := bc.bbnClient.BTCCheckpointParams()
+		response, err := bc.queryClient.BTCCheckpointParams()
 		if err != nil {
 			return err
 		}
@@ -167,15 +186,15 @@ func (bc *BabylonController) Params() (*StakingParams, error) {
 		return nil, err
 	}
 
-	if bccParams.CheckpointFinalizationTimeout > math.MaxUint16 {
-		return nil, fmt.Errorf("checkpoint finalization timeout is bigger than uint16: %w", ErrInvalidValueReceivedFromBabylonNode)
-	}
-
-	minUnbondingTime := sdkmath.Max[uint16](
-		uint16(bccParams.CheckpointFinalizationTimeout),
+	unbondingTime, err := minUnbondingTime(
+		bccParams.CheckpointFinalizationTimeout,
 		stakingTrackerParams.MinUnbodningTime,
 	)
 
+	if err != nil {
+		return nil, err
+	}
+
 	return &StakingParams{
 		ConfirmationTimeBlocks:    uint32(bccParams.BtcConfirmationDepth),
 		FinalizationTimeoutBlocks: uint32(bccParams.CheckpointFinalizationTimeout),
@@ -184,7 +203,7 @@ func (bc *BabylonController) Params() (*StakingParams, error) {
 		MinSlashingTxFeeSat:       stakingTrackerParams.MinSlashingFee,
 		SlashingRate:              stakingTrackerParams.SlashingRate,
 		CovenantQuruomThreshold:   stakingTrackerParams.CovenantQuruomThreshold,
-		MinUnbondingTime:          minUnbondingTime,
+		MinUnbondingTime:          unbondingTime,
 	}, nil
 }
 
@@ -194,7 +213,7 @@ func (bc *BabylonController) GetKeyAddress() sdk.AccAddress {
 	// and we should panic.
 	// This is checked at the start of BabylonController, so if it fails something is really wrong
 
-	keyRec, err := bc.bbnClient.GetKeyring().Key(bc.cfg.Key)
+	keyRec, err := bc.memoSender.provider.Keybase.Key(bc.cfg.Key)
 
 	if err != nil {
 		panic(fmt.Sprintf("Failed to get key address: %s", err))
@@ -216,7 +235,7 @@ func (bc *BabylonController) getTxSigner() string {
 }
 
 func (bc *BabylonController) getPubKeyInternal() (*secp256k1.PubKey, error) {
-	record, err := bc.bbnClient.GetKeyring().KeyByAddress(bc.GetKeyAddress())
+	record, err := bc.memoSender.provider.Keybase.KeyByAddress(bc.GetKeyAddress())
 
 	if err != nil {
 		return nil, err
@@ -247,7 +266,7 @@ func (bc *BabylonController) GetPubKey() *secp256k1.PubKey {
 }
 
 func (bc *BabylonController) Sign(msg []byte) ([]byte, error) {
-	sign, kt, err := bc.bbnClient.GetKeyring().SignByAddress(bc.GetKeyAddress(), msg, signing.SignMode_SIGN_MODE_DIRECT)
+	sign, kt, err := bc.memoSender.provider.Keybase.SignByAddress(bc.GetKeyAddress(), msg, signing.SignMode_SIGN_MODE_DIRECT)
 
 	if err != nil {
 		return nil, err
@@ -275,10 +294,14 @@ type DelegationData struct {
 	StakerBtcPk                          *btcec.PublicKey
 	BabylonPop                           *stakerdb.ProofOfPossession
 	Ud                                   *UndelegationData
+	// memo of babylon transaction, not part of delegation message
+	Memo string
 }
 
 type UndelegationData struct {
-	UnbondingTransaction         *wire.MsgTx
+	UnbondingTransaction *wire.MsgTx
+	// index of unbonding output in UnbondingTransaction
+	UnbondingOutputIdx           uint32
 	UnbondingTxValue             btcutil.Amount
 	UnbondingTxUnbondingTime     uint16
 	SlashUnbondingTransaction    *wire.MsgTx
@@ -288,6 +311,8 @@ type UndelegationData struct {
 type UndelegationRequest struct {
 	StakingTxHash      chainhash.Hash
 	StakerUnbondingSig *schnorr.Signature
+	// memo of babylon transaction
+	Memo string
 }
 
 type CovenantSignatureInfo struct {
@@ -301,11 +326,78 @@ type UndelegationInfo struct {
 	UnbondingTime               uint16
 }
 
+// DelegationStatus is status of delegation as reported by babylon
+type DelegationStatus int
+
+const (
+	// DelegationStatusPending delegation does not have enough covenant signatures
+	DelegationStatusPending DelegationStatus = iota
+	DelegationStatusActive
+	// DelegationStatusUnbonded delegation was unbonded early or its timelock
+	// passed
+	DelegationStatusUnbonded
+)
+
+func (s DelegationStatus) String() string {
+	switch s {
+	case DelegationStatusPending:
+		return "PENDING"
+	case DelegationStatusActive:
+		return "ACTIVE"
+	case DelegationStatusUnbonded:
+		return "UNBONDED"
+	default:
+		return fmt.Sprintf("UNKNOWN(%d)", int(s))
+	}
+}
+
+// delegationStatusFromResponse converts status description returned by babylon
+// to delegation status. Nodes which do not report status description only report
+// whether delegation is active, so unbonded delegations are reported as pending.
+// Status descriptions unknown to the daemon (e.g. introduced by newer babylon
+// version) are reported as pending, which makes the daemon wait instead of acting
+// on delegation, and false is returned.
+func delegationStatusFromResponse(active bool, statusDesc string) (DelegationStatus, bool) {
+	switch statusDesc {
+	case btcstypes.BTCDelegationStatus_PENDING.String():
+		return DelegationStatusPending, true
+	case btcstypes.BTCDelegationStatus_ACTIVE.String():
+		return DelegationStatusActive, true
+	case btcstypes.BTCDelegationStatus_UNBONDED.String():
+		return DelegationStatusUnbonded, true
+	case "":
+		if active {
+			return DelegationStatusActive, true
+		}
+
+		return DelegationStatusPending, true
+	default:
+		return DelegationStatusPending, false
+	}
+}
+
 type DelegationInfo struct {
-	Active           bool
+	Active bool
+	Status DelegationStatus
+	// btc heights between which delegation has voting power
+	StartHeight uint64
+	EndHeight   uint64
+	TotalSat    uint64
+	// number of covenant signatures over slashing transaction of the delegation
+	NumCovenantSigs  int
 	UndelegationInfo *UndelegationInfo
 }
 
+// StakerDelegation is delegation registered on babylon together with data necessary
+// to reconstruct its staking output
+type StakerDelegation struct {
+	StakingTx        *wire.MsgTx
+	StakingOutputIdx uint32
+	StakingTime      uint16
+	FpBtcPks         []*btcec.PublicKey
+	Pop              *BabylonPop
+}
+
 func delegationDataToMsg(signer string, dg *DelegationData) (*btcstypes.MsgCreateBTCDelegation, error) {
 	if dg == nil {
 		return nil, fmt.Errorf("nil delegation data")
@@ -398,9 +490,25 @@ func delegationDataToMsg(signer string, dg *DelegationData) (*btcstypes.MsgCreat
 
 func (bc *BabylonController) reliablySendMsgs(
 	msgs []sdk.Msg,
+	memo string,
 ) (*pv.RelayerTxResponse, error) {
-	// TODO Empty errors ??
-	return bc.bbnClient.ReliablySendMsgs(context.Background(), msgs, []*sdkErr.Error{}, []*sdkErr.Error{})
+	return bc.memoSender.reliablySendMsgs(context.Background(), msgs, memo)
+}
+
+// BuildDelegationMsg returns serialized delegation message, without sending it to
+// babylon. If signer is empty, address of the controller key is used.
+func (bc *BabylonController) BuildDelegationMsg(signer string, dg *DelegationData) ([]byte, error) {
+	if signer == "" {
+		signer = bc.getTxSigner()
+	}
+
+	delegateMsg, err := delegationDataToMsg(signer, dg)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return delegateMsg.Marshal()
 }
 
 // TODO: for now return sdk.TxResponse, it will ease up debugging/testing
@@ -412,7 +520,23 @@ func (bc *BabylonController) Delegate(dg *DelegationData) (*pv.RelayerTxResponse
 		return nil, err
 	}
 
-	return bc.reliablySendMsgs([]sdk.Msg{delegateMsg})
+	return bc.reliablySendMsgs([]sdk.Msg{delegateMsg}, dg.Memo)
+}
+
+func (bc *BabylonController) DelegateBatch(dgs []*DelegationData, memo string) (*pv.RelayerTxResponse, error) {
+	msgs := make([]sdk.Msg, len(dgs))
+
+	for i, dg := range dgs {
+		delegateMsg, err := delegationDataToMsg(bc.getTxSigner(), dg)
+
+		if err != nil {
+			return nil, err
+		}
+
+		msgs[i] = delegateMsg
+	}
+
+	return bc.reliablySendMsgs(msgs, memo)
 }
 
 func (bc *BabylonController) Undelegate(
@@ -427,7 +551,7 @@ func (bc *BabylonController) Undelegate(
 		UnbondingTxSig: ubSig,
 	}
 
-	return bc.reliablySendMsgs([]sdk.Msg{msg})
+	return bc.reliablySendMsgs([]sdk.Msg{msg}, req.Memo)
 }
 
 func getQueryContext(timeout time.Duration) (context.Context, context.CancelFunc) {
@@ -439,7 +563,7 @@ func (bc *BabylonController) QueryStakingTracker() (*StakingTrackerResponse, err
 	ctx, cancel := getQueryContext(bc.cfg.Timeout)
 	defer cancel()
 
-	clientCtx := client.Context{Client: bc.bbnClient.RPCClient}
+	clientCtx := client.Context{Client: bc.queryClient.RPCClient}
 	queryClient := btcstypes.NewQueryClient(clientCtx)
 
 	response, err := queryClient.Params(ctx, &btcstypes.QueryParamsRequest{})
@@ -489,13 +613,22 @@ func (bc *BabylonController) QueryStakingTracker() (*StakingTrackerResponse, err
 	}, nil
 }
 
+// QueryFinalityProviders returns page of registered finality providers, including
+// slashed ones. Page is selected either by offset or by key returned with the
+// previous page. Key based paging does not skip or repeat finality providers when
+// new ones are registered between queries.
 func (bc *BabylonController) QueryFinalityProviders(
 	limit uint64,
-	offset uint64) (*FinalityProvidersClientResponse, error) {
+	offset uint64,
+	pageKey []byte) (*FinalityProvidersClientResponse, error) {
+	if len(pageKey) > 0 && offset > 0 {
+		return nil, fmt.Errorf("cannot query finality providers by both offset and page key")
+	}
+
 	ctx, cancel := getQueryContext(bc.cfg.Timeout)
 	defer cancel()
 
-	clientCtx := client.Context{Client: bc.bbnClient.RPCClient}
+	clientCtx := client.Context{Client: bc.queryClient.RPCClient}
 	queryClient := btcstypes.NewQueryClient(clientCtx)
 
 	var response *btcstypes.QueryFinalityProvidersResponse
@@ -504,9 +637,10 @@ func (bc *BabylonController) QueryFinalityProviders(
 			ctx,
 			&btcstypes.QueryFinalityProvidersRequest{
 				Pagination: &bq.PageRequest{
+					Key:        pageKey,
 					Offset:     offset,
 					Limit:      limit,
-					CountTotal: true,
+					CountTotal: len(pageKey) == 0,
 				},
 			},
 		)
@@ -527,11 +661,6 @@ func (bc *BabylonController) QueryFinalityProviders(
 
 	var finalityProviders []FinalityProviderInfo
 	for _, finalityProvider := range response.FinalityProviders {
-		// TODO: We actually need to use a query for ActiveFinalityProviders
-		// instead of checking for the slashing condition
-		if finalityProvider.SlashedBabylonHeight > 0 {
-			continue
-		}
 		fpBtcKey, err := finalityProvider.BtcPk.ToBTCPK()
 		if err != nil {
 			return nil, fmt.Errorf("query finality providers error: %w", err)
@@ -539,17 +668,35 @@ func (bc *BabylonController) QueryFinalityProviders(
 		fpBabylonPk := finalityProvider.BabylonPk
 
 		fpInfo := FinalityProviderInfo{
-			BabylonPk: *fpBabylonPk,
-			BtcPk:     *fpBtcKey,
+			BabylonPk:  *fpBabylonPk,
+			BtcPk:      *fpBtcKey,
+			Commission: sdkmath.LegacyZeroDec(),
+			// TODO: We actually need to use a query for ActiveFinalityProviders
+			// instead of checking for the slashing condition
+			Slashed: finalityProvider.SlashedBabylonHeight > 0,
+		}
+
+		if finalityProvider.Description != nil {
+			fpInfo.Moniker = finalityProvider.Description.Moniker
+		}
+
+		if finalityProvider.Commission != nil {
+			fpInfo.Commission = *finalityProvider.Commission
 		}
 
 		finalityProviders = append(finalityProviders, fpInfo)
 	}
 
-	return &FinalityProvidersClientResponse{
+	resp := &FinalityProvidersClientResponse{
 		FinalityProviders: finalityProviders,
-		Total:             response.Pagination.Total,
-	}, nil
+	}
+
+	if response.Pagination != nil {
+		resp.Total = response.Pagination.Total
+		resp.NextKey = response.Pagination.NextKey
+	}
+
+	return resp, nil
 }
 
 func (bc *BabylonController) QueryFinalityProvider(btcPubKey *btcec.PublicKey) (*FinalityProviderClientResponse, error) {
@@ -560,7 +707,7 @@ func (bc *BabylonController) QueryFinalityProvider(btcPubKey *btcec.PublicKey) (
 	ctx, cancel := getQueryContext(bc.cfg.Timeout)
 	defer cancel()
 
-	clientCtx := client.Context{Client: bc.bbnClient.RPCClient}
+	clientCtx := client.Context{Client: bc.queryClient.RPCClient}
 	queryClient := btcstypes.NewQueryClient(clientCtx)
 
 	hexPubKey := hex.EncodeToString(schnorr.SerializePubKey(btcPubKey))
@@ -604,11 +751,17 @@ func (bc *BabylonController) QueryFinalityProvider(btcPubKey *btcec.PublicKey) (
 		return nil, fmt.Errorf("received malformed btc pk in babylon response: %w", err)
 	}
 
+	fpInfo := FinalityProviderInfo{
+		BabylonPk: *response.FinalityProvider.BabylonPk,
+		BtcPk:     *btcPk,
+	}
+
+	if response.FinalityProvider.Description != nil {
+		fpInfo.Moniker = response.FinalityProvider.Description.Moniker
+	}
+
 	return &FinalityProviderClientResponse{
-		FinalityProvider: FinalityProviderInfo{
-			BabylonPk: *response.FinalityProvider.BabylonPk,
-			BtcPk:     *btcPk,
-		},
+		FinalityProvider: fpInfo,
 	}, nil
 }
 
@@ -616,7 +769,7 @@ func (bc *BabylonController) QueryHeaderDepth(headerHash *chainhash.Hash) (uint6
 	ctx, cancel := getQueryContext(bc.cfg.Timeout)
 	defer cancel()
 
-	clientCtx := client.Context{Client: bc.bbnClient.RPCClient}
+	clientCtx := client.Context{Client: bc.queryClient.RPCClient}
 	queryClient := btclctypes.NewQueryClient(clientCtx)
 
 	var response *btclctypes.QueryHeaderDepthResponse
@@ -655,7 +808,7 @@ func (bc *BabylonController) InsertBtcBlockHeaders(headers []*wire.BlockHeader)
 		Headers: chainToChainBytes(headers),
 	}
 
-	return bc.reliablySendMsgs([]sdk.Msg{msg})
+	return bc.reliablySendMsgs([]sdk.Msg{msg}, "")
 }
 
 func chainToChainBytes(chain []*wire.BlockHeader) []bbntypes.BTCHeaderBytes {
@@ -680,11 +833,11 @@ func (bc *BabylonController) RegisterFinalityProvider(
 		Pop:         pop,
 	}
 
-	return bc.reliablySendMsgs([]sdk.Msg{registerMsg})
+	return bc.reliablySendMsgs([]sdk.Msg{registerMsg}, "")
 }
 
 func (bc *BabylonController) QueryDelegationInfo(stakingTxHash *chainhash.Hash) (*DelegationInfo, error) {
-	clientCtx := client.Context{Client: bc.bbnClient.RPCClient}
+	clientCtx := client.Context{Client: bc.queryClient.RPCClient}
 	queryClient := btcstypes.NewQueryClient(clientCtx)
 
 	ctx, cancel := getQueryContext(bc.cfg.Timeout)
@@ -750,8 +903,22 @@ func (bc *BabylonController) QueryDelegationInfo(stakingTxHash *chainhash.Hash)
 			}
 		}
 
+		status, known := delegationStatusFromResponse(resp.Active, resp.StatusDesc)
+
+		if !known {
+			bc.logger.WithFields(logrus.Fields{
+				"stakingTxHash": stakingTxHash,
+				"status":        resp.StatusDesc,
+			}).Warn("Babylon reported unknown delegation status. Treating delegation as pending")
+		}
+
 		di = &DelegationInfo{
 			Active:           resp.Active,
+			Status:           status,
+			StartHeight:      resp.StartHeight,
+			EndHeight:        resp.EndHeight,
+			TotalSat:         resp.TotalSat,
+			NumCovenantSigs:  len(resp.CovenantSigs),
 			UndelegationInfo: udi,
 		}
 		return nil
@@ -768,6 +935,127 @@ func (bc *BabylonController) QueryDelegationInfo(stakingTxHash *chainhash.Hash)
 	return di, nil
 }
 
+func btcDelegationToStakerDelegation(del *btcstypes.BTCDelegation) (*StakerDelegation, error) {
+	stakingTx, err := bbntypes.NewBTCTxFromBytes(del.StakingTx)
+
+	if err != nil {
+		return nil, fmt.Errorf("malformed staking transaction: %s: %w", err.Error(), ErrInvalidValueReceivedFromBabylonNode)
+	}
+
+	if int(del.StakingOutputIdx) >= len(stakingTx.TxOut) {
+		return nil, fmt.Errorf("invalid staking output index %d: %w", del.StakingOutputIdx, ErrInvalidValueReceivedFromBabylonNode)
+	}
+
+	if del.EndHeight < del.StartHeight || del.EndHeight-del.StartHeight > math.MaxUint16 {
+		return nil, fmt.Errorf("invalid delegation period [%d, %d]: %w", del.StartHeight, del.EndHeight, ErrInvalidValueReceivedFromBabylonNode)
+	}
+
+	var fpBtcPks []*btcec.PublicKey
+
+	for _, fpPk := range del.FpBtcPkList {
+		fpBtcPk, err := fpPk.ToBTCPK()
+
+		if err != nil {
+			return nil, fmt.Errorf("malformed finality provider pk: %s: %w", err.Error(), ErrInvalidValueReceivedFromBabylonNode)
+		}
+
+		fpBtcPks = append(fpBtcPks, fpBtcPk)
+	}
+
+	if del.Pop == nil {
+		return nil, fmt.Errorf("delegation without proof of possession: %w", ErrInvalidValueReceivedFromBabylonNode)
+	}
+
+	popType, err := IntToPopType(int(del.Pop.BtcSigType))
+
+	if err != nil {
+		return nil, fmt.Errorf("malformed proof of possession: %s: %w", err.Error(), ErrInvalidValueReceivedFromBabylonNode)
+	}
+
+	pop, err := NewBabylonPop(popType, del.Pop.BabylonSig, del.Pop.BtcSig)
+
+	if err != nil {
+		return nil, fmt.Errorf("malformed proof of possession: %s: %w", err.Error(), ErrInvalidValueReceivedFromBabylonNode)
+	}
+
+	return &StakerDelegation{
+		StakingTx:        stakingTx,
+		StakingOutputIdx: del.StakingOutputIdx,
+		// babylon sets end height of delegation to start height + staking time
+		StakingTime: uint16(del.EndHeight - del.StartHeight),
+		FpBtcPks:    fpBtcPks,
+		Pop:         pop,
+	}, nil
+}
+
+// QueryDelegationsByStakerKey returns all delegations on babylon made with given staker
+// btc key. Babylon does not index delegations by staker key, so this scans through
+// all delegations and should only be used by rare maintenance operations.
+func (bc *BabylonController) QueryDelegationsByStakerKey(stakerKey *btcec.PublicKey) ([]*StakerDelegation, error) {
+	clientCtx := client.Context{Client: bc.queryClient.RPCClient}
+	queryClient := btcstypes.NewQueryClient(clientCtx)
+
+	stakerKeyBytes := schnorr.SerializePubKey(stakerKey)
+
+	var delegations []*StakerDelegation
+	var nextKey []byte
+
+	for {
+		var response *btcstypes.QueryBTCDelegationsResponse
+
+		ctx, cancel := getQueryContext(bc.cfg.Timeout)
+
+		err := retry.Do(func() error {
+			resp, err := queryClient.BTCDelegations(ctx, &btcstypes.QueryBTCDelegationsRequest{
+				Status: btcstypes.BTCDelegationStatus_ANY,
+				Pagination: &bq.PageRequest{
+					Key:   nextKey,
+					Limit: delegationsQueryPageSize,
+				},
+			})
+			if err != nil {
+				return err
+			}
+			response = resp
+			return nil
+		}, RtyAtt, RtyDel, RtyErr, retry.OnRetry(func(n uint, err error) {
+			bc.logger.WithFields(logrus.Fields{
+				"attempt":      n + 1,
+				"max_attempts": RtyAttNum,
+				"error":        err,
+			}).Error("Failed to query babylon for the list of delegations")
+		}))
+
+		cancel()
+
+		if err != nil {
+			return nil, err
+		}
+
+		for _, del := range response.BtcDelegations {
+			if del.BtcPk == nil || !bytes.Equal(*del.BtcPk, stakerKeyBytes) {
+				continue
+			}
+
+			stakerDelegation, err := btcDelegationToStakerDelegation(del)
+
+			if err != nil {
+				return nil, err
+			}
+
+			delegations = append(delegations, stakerDelegation)
+		}
+
+		if response.Pagination == nil || len(response.Pagination.NextKey) == 0 {
+			break
+		}
+
+		nextKey = response.Pagination.NextKey
+	}
+
+	return delegations, nil
+}
+
 func (bc *BabylonController) IsTxAlreadyPartOfDelegation(stakingTxHash *chainhash.Hash) (bool, error) {
 	_, err := bc.QueryDelegationInfo(stakingTxHash)
 
@@ -800,14 +1088,14 @@ func (bc *BabylonController) SubmitCovenantSig(
 		SlashingUnbondingTxSigs: slashUnbondingAdaptorSigs,
 	}
 
-	return bc.reliablySendMsgs([]sdk.Msg{msg})
+	return bc.reliablySendMsgs([]sdk.Msg{msg}, "")
 }
 
 func (bc *BabylonController) QueryPendingBTCDelegations() ([]*btcstypes.BTCDelegation, error) {
 	ctx, cancel := getQueryContext(bc.cfg.Timeout)
 	defer cancel()
 
-	clientCtx := client.Context{Client: bc.bbnClient.RPCClient}
+	clientCtx := client.Context{Client: bc.queryClient.RPCClient}
 	queryClient := btcstypes.NewQueryClient(clientCtx)
 
 	// query all the unsigned delegations
@@ -0,0 +1,128 @@
+package babylonclient
+
+import (
+	"context"
+	"fmt"
+
+	sdkmath "cosmossdk.io/math"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// feeDenom returns denom in which transaction fees are paid, it is the denom of
+// the first configured gas price
+func feeDenom(gasPricesStr string) (string, error) {
+	gasPrices, err := sdk.ParseDecCoins(gasPricesStr)
+
+	if err != nil {
+		return "", fmt.Errorf("invalid gas prices %s: %w", gasPricesStr, err)
+	}
+
+	if len(gasPrices) == 0 {
+		return "", fmt.Errorf("gas prices are not configured")
+	}
+
+	return gasPrices[0].Denom, nil
+}
+
+// QueryAccountBalance returns balance of the account paying transaction fees in
+// the denom used to pay them. It is the controller key account, unless fee granter
+// or fee payer is configured.
+func (bc *BabylonController) QueryAccountBalance() (sdk.Coin, error) {
+	denom, err := feeDenom(bc.cfg.GasPrices)
+
+	if err != nil {
+		return sdk.Coin{}, err
+	}
+
+	address := bc.getTxSigner()
+
+	if fees := bc.memoSender.fees; fees != nil {
+		address, err = bc.memoSender.provider.EncodeBech32AccAddr(fees.feesPaidBy(bc.GetKeyAddress()))
+
+		if err != nil {
+			return sdk.Coin{}, err
+		}
+	}
+
+	ctx, cancel := getQueryContext(bc.cfg.Timeout)
+	defer cancel()
+
+	balances, err := bc.memoSender.provider.QueryBalanceWithAddress(ctx, address)
+
+	if err != nil {
+		return sdk.Coin{}, err
+	}
+
+	return sdk.NewCoin(denom, balances.AmountOf(denom)), nil
+}
+
+// EstimateDelegationFee simulates delegation transaction and returns fee which
+// would be paid for it with configured gas prices
+func (bc *BabylonController) EstimateDelegationFee(dg *DelegationData) (sdk.Coin, error) {
+	denom, err := feeDenom(bc.cfg.GasPrices)
+
+	if err != nil {
+		return sdk.Coin{}, err
+	}
+
+	delegateMsg, err := delegationDataToMsg(bc.getTxSigner(), dg)
+
+	if err != nil {
+		return sdk.Coin{}, err
+	}
+
+	ctx, cancel := getQueryContext(bc.cfg.Timeout)
+	defer cancel()
+
+	gas, err := bc.memoSender.simulateGas(ctx, []sdk.Msg{delegateMsg}, dg.Memo)
+
+	if err != nil {
+		return sdk.Coin{}, err
+	}
+
+	gasPrices, err := sdk.ParseDecCoins(bc.cfg.GasPrices)
+
+	if err != nil {
+		return sdk.Coin{}, err
+	}
+
+	// same rounding as used by cosmos tx factory
+	fee := gasPrices.AmountOf(denom).Mul(sdkmath.LegacyNewDec(int64(gas))).Ceil().RoundInt()
+
+	return sdk.NewCoin(denom, fee), nil
+}
+
+// simulateGas returns gas which would be used by transaction with given messages,
+// adjusted by configured gas adjustment
+func (s *memoSender) simulateGas(ctx context.Context, msgs []sdk.Msg, memo string) (uint64, error) {
+	var (
+		gas    uint64
+		simErr error
+	)
+
+	krErr := s.accessKeyWithLock(func() {
+		if s.fees != nil {
+			gas, simErr = s.simulateWithFeeAccounts(ctx, msgs, memo)
+			return
+		}
+
+		txf, err := s.provider.PrepareFactory(s.provider.TxFactory(), s.provider.Key())
+
+		if err != nil {
+			simErr = err
+			return
+		}
+
+		_, gas, simErr = s.provider.CalculateGas(ctx, txf.WithMemo(memo), s.provider.Key(), msgs...)
+	})
+
+	if krErr != nil {
+		return 0, krErr
+	}
+
+	if simErr != nil {
+		return 0, simErr
+	}
+
+	return gas, nil
+}
@@ -0,0 +1,217 @@
+package babylonclient
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// BatchConfig configures batching of delegations. Undelegations are always sent
+// in their own babylon transaction, as each of them is preceded by a query of
+// delegation state on babylon and they are rare compared to delegations.
+type BatchConfig struct {
+	// whether delegations are sent to babylon in batches
+	Enabled bool
+	// maximum number of delegations sent in single babylon transaction, at least 2
+	// if batching is enabled
+	MaxSize uint32
+	// how long sender waits for more delegations after receiving the first
+	// delegation of the batch
+	Window time.Duration
+}
+
+// BatchStats are statistics of babylon transactions carrying delegations. With
+// batching disabled, every transaction carries single delegation.
+type BatchStats struct {
+	Transactions uint64
+	Delegations  uint64
+	// number of batches which failed and were resent as individual transactions
+	FallbackBatches uint64
+}
+
+// AverageSize returns average number of delegations sent in single babylon
+// transaction, zero if no transaction was sent yet
+func (s BatchStats) AverageSize() float64 {
+	if s.Transactions == 0 {
+		return 0
+	}
+
+	return float64(s.Delegations) / float64(s.Transactions)
+}
+
+type batchStatsRecorder struct {
+	mu    sync.Mutex
+	stats BatchStats
+}
+
+func (r *batchStatsRecorder) recordTransaction(numDelegations int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.stats.Transactions++
+	r.stats.Delegations += uint64(numDelegations)
+}
+
+func (r *batchStatsRecorder) recordFallback() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.stats.FallbackBatches++
+}
+
+func (r *batchStatsRecorder) get() BatchStats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.stats
+}
+
+// groupByMemo splits requests into groups which can be sent in single babylon
+// transaction. Cosmos transaction has single memo, so only delegations with the
+// same memo are sent together. Order of requests is preserved within groups.
+func groupByMemo(reqs []*sendDelegationRequest) [][]*sendDelegationRequest {
+	var groups [][]*sendDelegationRequest
+	groupIdx := make(map[string]int)
+
+	for _, req := range reqs {
+		idx, ok := groupIdx[req.dg.Memo]
+
+		if !ok {
+			idx = len(groups)
+			groupIdx[req.dg.Memo] = idx
+			groups = append(groups, nil)
+		}
+
+		groups[idx] = append(groups[idx], req)
+	}
+
+	return groups
+}
+
+// sendDelegations sends delegations collected during batch window. Delegations
+// which babylon is not ready for are failed individually, the rest is sent in
+// as few transactions as possible.
+func (m *BabylonMsgSender) sendDelegations(reqs []*sendDelegationRequest) {
+	ready := make([]*sendDelegationRequest, 0, len(reqs))
+
+	for _, req := range reqs {
+		if err := m.breaker.allow(); err != nil {
+			req.ErrorChan() <- err
+			continue
+		}
+
+		if err := m.isBabylonBtcLcReady(req.requiredInclusionBlockDepth, req.dg); err != nil {
+			m.logger.WithFields(logrus.Fields{
+				"btcTxHash": req.dg.StakingTransaction.TxHash(),
+				"err":       err,
+			}).Error("Cannot send delegation request to babylon")
+
+			req.ErrorChan() <- err
+			continue
+		}
+
+		ready = append(ready, req)
+	}
+
+	for _, group := range groupByMemo(ready) {
+		// previous group might have opened the circuit
+		if err := m.breaker.allow(); err != nil {
+			for _, req := range group {
+				req.ErrorChan() <- err
+			}
+			continue
+		}
+
+		if len(group) == 1 {
+			m.sendDelegation(group[0])
+			continue
+		}
+
+		m.sendDelegationBatch(group)
+	}
+}
+
+// sendDelegationBatch sends delegations in single babylon transaction. Failure of
+// any message fails the whole transaction, so if batch fails, delegations are
+// resent one by one, to fail only the offending ones.
+func (m *BabylonMsgSender) sendDelegationBatch(reqs []*sendDelegationRequest) {
+	dgs := make([]*DelegationData, len(reqs))
+	for i, req := range reqs {
+		dgs[i] = req.dg
+	}
+
+	txResp, err := m.cl.DelegateBatch(dgs, reqs[0].dg.Memo)
+	m.recordWriteResult(err)
+
+	if err == nil {
+		m.batchStats.recordTransaction(len(reqs))
+
+		m.logger.WithFields(logrus.Fields{
+			"babylonTxHash":  txResp.TxHash,
+			"numDelegations": len(reqs),
+		}).Info("Sent batch of delegations to babylon")
+
+		for _, req := range reqs {
+			req.ResultChan() <- txResp
+		}
+		return
+	}
+
+	m.batchStats.recordFallback()
+
+	logger := m.logger.WithFields(logrus.Fields{
+		"numDelegations": len(reqs),
+		"err":            err,
+	})
+
+	var submitErr *BabylonSubmitError
+	if errors.As(err, &submitErr) {
+		logger = logger.WithFields(submitErrorFields(submitErr))
+	}
+
+	logger.Warn("Failed to send batch of delegations to babylon. Sending delegations individually")
+
+	for _, req := range reqs {
+		if err := m.breaker.allow(); err != nil {
+			req.ErrorChan() <- err
+			continue
+		}
+
+		m.sendDelegation(req)
+	}
+}
+
+// sendDelegation sends single delegation in its own babylon transaction and
+// replies to the request
+func (m *BabylonMsgSender) sendDelegation(req *sendDelegationRequest) {
+	stakingTxHash := req.dg.StakingTransaction.TxHash()
+
+	txResp, err := m.cl.Delegate(req.dg)
+	m.recordWriteResult(err)
+
+	if err != nil {
+		var submitErr *BabylonSubmitError
+		if errors.As(err, &submitErr) {
+			m.logger.WithFields(submitErrorFields(submitErr)).WithFields(logrus.Fields{
+				"btcTxHash": stakingTxHash,
+			}).Error("Invalid delegation data sent to babylon")
+		}
+
+		m.logger.WithFields(logrus.Fields{
+			"btcTxHash": stakingTxHash,
+			"err":       err,
+		}).Error("Error while sending delegation data to babylon")
+
+		req.ErrorChan() <- fmt.Errorf("failed to send delegation for tx with hash: %s: %w", stakingTxHash.String(), err)
+		return
+	}
+
+	m.batchStats.recordTransaction(1)
+
+	req.ResultChan() <- txResp
+}
+
+// BatchStats returns statistics of babylon transactions carrying delegations
+func (m *BabylonMsgSender) BatchStats() BatchStats {
+	return m.batchStats.get()
+}
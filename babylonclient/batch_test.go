@@ -0,0 +1,242 @@
+package babylonclient
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/babylonchain/btc-staker/utils"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	pv "github.com/cosmos/relayer/v2/relayer/provider"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+// batchingBabylonClient records sent delegations, batch containing delegation
+// from invalid set fails execution as a whole, calling any other method than
+// the ones implemented panics
+type batchingBabylonClient struct {
+	BabylonClient
+	invalid      map[chainhash.Hash]bool
+	batches      [][]chainhash.Hash
+	batchMemos   []string
+	singles      []chainhash.Hash
+	nextTxNumber int
+}
+
+func (c *batchingBabylonClient) QueryHeaderDepth(_ *chainhash.Hash) (uint64, error) {
+	return 100, nil
+}
+
+func (c *batchingBabylonClient) txResponse() *pv.RelayerTxResponse {
+	c.nextTxNumber++
+	return &pv.RelayerTxResponse{TxHash: fmt.Sprintf("tx-%d", c.nextTxNumber)}
+}
+
+func (c *batchingBabylonClient) Delegate(dg *DelegationData) (*pv.RelayerTxResponse, error) {
+	hash := dg.StakingTransaction.TxHash()
+	c.singles = append(c.singles, hash)
+
+	if c.invalid[hash] {
+		return &pv.RelayerTxResponse{Code: 1}, ErrInvalidBabylonExecution
+	}
+
+	return c.txResponse(), nil
+}
+
+func (c *batchingBabylonClient) DelegateBatch(dgs []*DelegationData, memo string) (*pv.RelayerTxResponse, error) {
+	var hashes []chainhash.Hash
+	failed := false
+
+	for _, dg := range dgs {
+		hash := dg.StakingTransaction.TxHash()
+		hashes = append(hashes, hash)
+		failed = failed || c.invalid[hash]
+	}
+
+	c.batches = append(c.batches, hashes)
+	c.batchMemos = append(c.batchMemos, memo)
+
+	if failed {
+		return &pv.RelayerTxResponse{Code: 1}, ErrInvalidBabylonExecution
+	}
+
+	return c.txResponse(), nil
+}
+
+func testDelegation(lockTime uint32, memo string) *DelegationData {
+	tx := wire.NewMsgTx(2)
+	tx.LockTime = lockTime
+
+	return &DelegationData{
+		StakingTransaction:                   tx,
+		StakingTransactionInclusionBlockHash: &chainhash.Hash{},
+		Memo:                                 memo,
+	}
+}
+
+type delegationResult struct {
+	resp *pv.RelayerTxResponse
+	err  error
+}
+
+// sendConcurrently sends all delegations at the same time and waits for all results
+func sendConcurrently(sender *BabylonMsgSender, dgs []*DelegationData) []delegationResult {
+	results := make([]delegationResult, len(dgs))
+
+	var wg sync.WaitGroup
+	for i, dg := range dgs {
+		wg.Add(1)
+		go func(i int, dg *DelegationData) {
+			defer wg.Done()
+			resp, err := sender.SendDelegation(dg, 1)
+			results[i] = delegationResult{resp: resp, err: err}
+		}(i, dg)
+	}
+	wg.Wait()
+
+	return results
+}
+
+func newTestBatchSender(client BabylonClient, clock utils.Clock, maxSize uint32) *BabylonMsgSender {
+	return NewBabylonMsgSender(client, logrus.New(), CircuitBreakerConfig{
+		FailureThreshold: 100,
+		Cooldown:         time.Minute,
+	}, BatchConfig{
+		Enabled: true,
+		MaxSize: maxSize,
+		Window:  time.Hour,
+	}, clock)
+}
+
+func TestDelegationsAreSentInSingleBatch(t *testing.T) {
+	client := &batchingBabylonClient{}
+	sender := newTestBatchSender(client, utils.NewFakeClock(time.Unix(1700000000, 0)), 3)
+	sender.Start()
+	defer sender.Stop()
+
+	dgs := []*DelegationData{testDelegation(1, "m"), testDelegation(2, "m"), testDelegation(3, "m")}
+
+	// batch is full, so it is sent without waiting for the window to elapse
+	results := sendConcurrently(sender, dgs)
+
+	require.Len(t, client.batches, 1)
+	require.Len(t, client.batches[0], 3)
+	require.Equal(t, "m", client.batchMemos[0])
+	require.Empty(t, client.singles)
+
+	for _, r := range results {
+		require.NoError(t, r.err)
+		require.Equal(t, results[0].resp.TxHash, r.resp.TxHash)
+	}
+
+	stats := sender.BatchStats()
+	require.Equal(t, uint64(1), stats.Transactions)
+	require.Equal(t, uint64(3), stats.Delegations)
+	require.Equal(t, float64(3), stats.AverageSize())
+}
+
+func TestDelegationsWithDifferentMemosAreNotBatched(t *testing.T) {
+	client := &batchingBabylonClient{}
+	sender := newTestBatchSender(client, utils.NewFakeClock(time.Unix(1700000000, 0)), 3)
+	sender.Start()
+	defer sender.Stop()
+
+	dgs := []*DelegationData{testDelegation(1, "a"), testDelegation(2, "b"), testDelegation(3, "a")}
+
+	for _, r := range sendConcurrently(sender, dgs) {
+		require.NoError(t, r.err)
+	}
+
+	require.Len(t, client.batches, 1)
+	require.Len(t, client.batches[0], 2)
+	require.Equal(t, "a", client.batchMemos[0])
+	require.Equal(t, []chainhash.Hash{dgs[1].StakingTransaction.TxHash()}, client.singles)
+
+	stats := sender.BatchStats()
+	require.Equal(t, uint64(2), stats.Transactions)
+	require.Equal(t, 1.5, stats.AverageSize())
+}
+
+func TestFailedBatchIsResentIndividually(t *testing.T) {
+	dgs := []*DelegationData{testDelegation(1, ""), testDelegation(2, ""), testDelegation(3, "")}
+	invalidHash := dgs[1].StakingTransaction.TxHash()
+
+	client := &batchingBabylonClient{
+		invalid: map[chainhash.Hash]bool{invalidHash: true},
+	}
+	sender := newTestBatchSender(client, utils.NewFakeClock(time.Unix(1700000000, 0)), 3)
+	sender.Start()
+	defer sender.Stop()
+
+	results := sendConcurrently(sender, dgs)
+
+	require.Len(t, client.batches, 1)
+	require.Len(t, client.singles, 3)
+
+	// only the offending delegation fails
+	for i, r := range results {
+		if dgs[i].StakingTransaction.TxHash() == invalidHash {
+			require.ErrorIs(t, r.err, ErrInvalidBabylonExecution)
+			continue
+		}
+		require.NoError(t, r.err)
+	}
+
+	stats := sender.BatchStats()
+	require.Equal(t, uint64(2), stats.Transactions)
+	require.Equal(t, uint64(2), stats.Delegations)
+	require.Equal(t, uint64(1), stats.FallbackBatches)
+}
+
+func TestBatchIsSentWhenWindowElapses(t *testing.T) {
+	client := &batchingBabylonClient{}
+	clock := utils.NewFakeClock(time.Unix(1700000000, 0))
+	sender := newTestBatchSender(client, clock, 10)
+	sender.Start()
+	defer sender.Stop()
+
+	resultChan := make(chan []delegationResult)
+	go func() {
+		resultChan <- sendConcurrently(sender, []*DelegationData{testDelegation(1, "")})
+	}()
+
+	require.Eventually(t, func() bool {
+		return clock.NumWaiters() == 1
+	}, time.Second, time.Millisecond)
+
+	select {
+	case <-resultChan:
+		t.Fatal("delegation sent before batch window elapsed")
+	default:
+	}
+
+	clock.Advance(time.Hour)
+
+	results := <-resultChan
+	require.NoError(t, results[0].err)
+	require.Len(t, client.singles, 1)
+	require.Empty(t, client.batches)
+}
+
+func TestDelegationsAreSentImmediatelyWithoutBatching(t *testing.T) {
+	client := &batchingBabylonClient{}
+	sender := NewBabylonMsgSender(client, logrus.New(), CircuitBreakerConfig{
+		FailureThreshold: 100,
+		Cooldown:         time.Minute,
+	}, BatchConfig{}, utils.NewFakeClock(time.Unix(1700000000, 0)))
+	sender.Start()
+	defer sender.Stop()
+
+	dgs := []*DelegationData{testDelegation(1, ""), testDelegation(2, "")}
+
+	for _, r := range sendConcurrently(sender, dgs) {
+		require.NoError(t, r.err)
+	}
+
+	require.Empty(t, client.batches)
+	require.Len(t, client.singles, 2)
+	require.Equal(t, float64(1), sender.BatchStats().AverageSize())
+}
@@ -0,0 +1,150 @@
+package babylonclient
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/babylonchain/btc-staker/utils"
+)
+
+// ErrBabylonCircuitOpen is returned instead of sending transaction to babylon while
+// circuit breaker is open after repeated failures
+var ErrBabylonCircuitOpen = errors.New("babylon circuit breaker is open, sending transactions to babylon is suspended")
+
+type CircuitState int
+
+const (
+	// transactions are sent to babylon
+	CircuitClosed CircuitState = iota
+	// transactions are rejected without contacting babylon
+	CircuitOpen
+	// cooldown elapsed, next transaction is sent to babylon to probe whether it
+	// recovered
+	CircuitHalfOpen
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+type CircuitBreakerConfig struct {
+	// number of consecutive failures after which circuit opens
+	FailureThreshold uint32
+	// how long circuit stays open before probing babylon again
+	Cooldown time.Duration
+}
+
+type CircuitBreakerStatus struct {
+	State               CircuitState
+	ConsecutiveFailures uint32
+	TotalFailures       uint64
+	// time when circuit was opened last time, zero if it was never opened
+	OpenedAt time.Time
+}
+
+// circuitBreaker stops sending transactions to babylon after repeated failures,
+// so that babylon outage does not exhaust retries of all pending delegations
+type circuitBreaker struct {
+	mu                  sync.Mutex
+	cfg                 CircuitBreakerConfig
+	clock               utils.Clock
+	state               CircuitState
+	consecutiveFailures uint32
+	totalFailures       uint64
+	openedAt            time.Time
+}
+
+func newCircuitBreaker(cfg CircuitBreakerConfig, clock utils.Clock) *circuitBreaker {
+	return &circuitBreaker{
+		cfg:   cfg,
+		clock: clock,
+		state: CircuitClosed,
+	}
+}
+
+// allow returns ErrBabylonCircuitOpen if transaction must not be sent to babylon.
+// Once cooldown elapses, circuit becomes half-open and transaction is allowed as
+// a probe.
+func (b *circuitBreaker) allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state != CircuitOpen {
+		return nil
+	}
+
+	if b.clock.Now().Before(b.openedAt.Add(b.cfg.Cooldown)) {
+		return ErrBabylonCircuitOpen
+	}
+
+	b.state = CircuitHalfOpen
+
+	return nil
+}
+
+func (b *circuitBreaker) onSuccess() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.state = CircuitClosed
+	b.consecutiveFailures = 0
+}
+
+// onFailure returns true if circuit was opened by this failure
+func (b *circuitBreaker) onFailure() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.consecutiveFailures++
+	b.totalFailures++
+
+	// failed probe opens circuit again for whole cooldown
+	if b.state == CircuitHalfOpen || (b.state == CircuitClosed && b.consecutiveFailures >= b.cfg.FailureThreshold) {
+		b.state = CircuitOpen
+		b.openedAt = b.clock.Now()
+		return true
+	}
+
+	return false
+}
+
+// retryIn returns how long until transaction can be sent to babylon again, zero
+// if transactions are allowed now
+func (b *circuitBreaker) retryIn() time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state != CircuitOpen {
+		return 0
+	}
+
+	remaining := b.openedAt.Add(b.cfg.Cooldown).Sub(b.clock.Now())
+
+	if remaining < 0 {
+		return 0
+	}
+
+	return remaining
+}
+
+func (b *circuitBreaker) status() *CircuitBreakerStatus {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return &CircuitBreakerStatus{
+		State:               b.state,
+		ConsecutiveFailures: b.consecutiveFailures,
+		TotalFailures:       b.totalFailures,
+		OpenedAt:            b.openedAt,
+	}
+}
@@ -0,0 +1,99 @@
+package babylonclient
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/babylonchain/btc-staker/utils"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	pv "github.com/cosmos/relayer/v2/relayer/provider"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+// scriptedBabylonClient fails or succeeds delegations in scripted order, calling
+// any other method than the ones implemented panics
+type scriptedBabylonClient struct {
+	BabylonClient
+	delegateResults []error
+	delegateCalls   int
+}
+
+func (c *scriptedBabylonClient) QueryHeaderDepth(_ *chainhash.Hash) (uint64, error) {
+	return 100, nil
+}
+
+func (c *scriptedBabylonClient) Delegate(_ *DelegationData) (*pv.RelayerTxResponse, error) {
+	err := c.delegateResults[c.delegateCalls]
+	c.delegateCalls++
+
+	return &pv.RelayerTxResponse{}, err
+}
+
+func TestCircuitBreakerTransitions(t *testing.T) {
+	errUnavailable := errors.New("babylon unavailable")
+
+	clock := utils.NewFakeClock(time.Unix(1700000000, 0))
+	client := &scriptedBabylonClient{
+		delegateResults: []error{
+			errUnavailable,
+			errUnavailable,
+			errUnavailable,
+			// failed execution means babylon is reachable
+			ErrInvalidBabylonExecution,
+		},
+	}
+
+	sender := NewBabylonMsgSender(client, logrus.New(), CircuitBreakerConfig{
+		FailureThreshold: 2,
+		Cooldown:         time.Minute,
+	}, BatchConfig{}, clock)
+	sender.Start()
+	defer sender.Stop()
+
+	dg := &DelegationData{
+		StakingTransaction:                   wire.NewMsgTx(2),
+		StakingTransactionInclusionBlockHash: &chainhash.Hash{},
+	}
+
+	send := func() error {
+		_, err := sender.SendDelegation(dg, 1)
+		return err
+	}
+
+	// failures below threshold keep circuit closed
+	require.ErrorIs(t, send(), errUnavailable)
+	require.Equal(t, CircuitClosed, sender.CircuitStatus().State)
+
+	// closed -> open
+	require.ErrorIs(t, send(), errUnavailable)
+	require.Equal(t, CircuitOpen, sender.CircuitStatus().State)
+	require.Equal(t, time.Minute, sender.CircuitRetryIn())
+
+	// open circuit rejects delegations without contacting babylon
+	require.ErrorIs(t, send(), ErrBabylonCircuitOpen)
+	require.Equal(t, 2, client.delegateCalls)
+
+	// open -> half-open after cooldown
+	clock.Advance(time.Minute)
+	require.Zero(t, sender.CircuitRetryIn())
+	require.NoError(t, sender.breaker.allow())
+	require.Equal(t, CircuitHalfOpen, sender.CircuitStatus().State)
+
+	// failed probe: half-open -> open
+	require.ErrorIs(t, send(), errUnavailable)
+	require.Equal(t, 3, client.delegateCalls)
+	require.Equal(t, CircuitOpen, sender.CircuitStatus().State)
+	require.ErrorIs(t, send(), ErrBabylonCircuitOpen)
+
+	// successful probe: half-open -> closed
+	clock.Advance(time.Minute)
+	require.ErrorIs(t, send(), ErrInvalidBabylonExecution)
+
+	status := sender.CircuitStatus()
+	require.Equal(t, CircuitClosed, status.State)
+	require.Zero(t, status.ConsecutiveFailures)
+	require.Equal(t, uint64(3), status.TotalFailures)
+}
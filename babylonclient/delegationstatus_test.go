@@ -0,0 +1,32 @@
+package babylonclient
+
+import (
+	"testing"
+
+	btcstypes "github.com/babylonchain/babylon/x/btcstaking/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDelegationStatusFromResponse(t *testing.T) {
+	tests := []struct {
+		active     bool
+		statusDesc string
+		status     DelegationStatus
+		known      bool
+	}{
+		{false, btcstypes.BTCDelegationStatus_PENDING.String(), DelegationStatusPending, true},
+		{true, btcstypes.BTCDelegationStatus_ACTIVE.String(), DelegationStatusActive, true},
+		{false, btcstypes.BTCDelegationStatus_UNBONDED.String(), DelegationStatusUnbonded, true},
+		// node not reporting status description
+		{true, "", DelegationStatusActive, true},
+		{false, "", DelegationStatusPending, true},
+		// status introduced by newer babylon version
+		{false, "VERIFIED", DelegationStatusPending, false},
+	}
+
+	for _, tt := range tests {
+		status, known := delegationStatusFromResponse(tt.active, tt.statusDesc)
+		require.Equal(t, tt.status, status, tt.statusDesc)
+		require.Equal(t, tt.known, known, tt.statusDesc)
+	}
+}
@@ -0,0 +1,490 @@
+package babylonclient
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+
+	sdkmath "cosmossdk.io/math"
+	bbntypes "github.com/babylonchain/babylon/types"
+	btcstypes "github.com/babylonchain/babylon/x/btcstaking/types"
+	"github.com/babylonchain/btc-staker/stakercfg"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/cosmos/cosmos-sdk/codec"
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	gogoproto "github.com/cosmos/gogoproto/proto"
+	pv "github.com/cosmos/relayer/v2/relayer/provider"
+	"github.com/sirupsen/logrus"
+)
+
+// DryRunTxHashPrefix is the prefix of synthetic hashes returned for messages which
+// were not sent to babylon in dry run mode
+const DryRunTxHashPrefix = "DRYRUN"
+
+// default max memo size of cosmos chains, used when fixture does not provide it
+const defaultDryRunMaxMemoCharacters = 256
+
+// IsDryRunTxHash returns true if babylon transaction hash was created in dry run
+// mode, i.e. the message was never sent to babylon
+func IsDryRunTxHash(txHash string) bool {
+	return strings.HasPrefix(txHash, DryRunTxHashPrefix)
+}
+
+// DryRunFixture describes babylon state served in dry run mode instead of
+// querying babylon node
+type DryRunFixture struct {
+	Params            DryRunParamsFixture             `json:"params"`
+	FinalityProviders []DryRunFinalityProviderFixture `json:"finality_providers"`
+	// balance of babylon account in fee denom, zero if empty
+	AccountBalance string `json:"account_balance,omitempty"`
+	// 0 means default max memo size of cosmos chains
+	MaxMemoCharacters uint64 `json:"max_memo_characters,omitempty"`
+}
+
+type DryRunParamsFixture struct {
+	ConfirmationTimeBlocks    uint32 `json:"confirmation_time_blocks"`
+	FinalizationTimeoutBlocks uint32 `json:"finalization_timeout_blocks"`
+	MinSlashingTxFeeSat       int64  `json:"min_slashing_tx_fee_sat"`
+	// hex encoded BIP340 public keys
+	CovenantPks      []string `json:"covenant_pks"`
+	CovenantQuorum   uint32   `json:"covenant_quorum"`
+	SlashingAddress  string   `json:"slashing_address"`
+	SlashingRate     string   `json:"slashing_rate"`
+	MinUnbondingTime uint16   `json:"min_unbonding_time"`
+}
+
+type DryRunFinalityProviderFixture struct {
+	// hex encoded BIP340 public key
+	BtcPk string `json:"btc_pk"`
+	// hex encoded compressed secp256k1 public key
+	BabylonPk  string `json:"babylon_pk"`
+	Moniker    string `json:"moniker,omitempty"`
+	Commission string `json:"commission,omitempty"`
+	Slashed    bool   `json:"slashed,omitempty"`
+}
+
+// dryRunState is babylon state parsed from fixture
+type dryRunState struct {
+	params            *StakingParams
+	finalityProviders []FinalityProviderInfo
+	accountBalance    sdk.Coin
+	maxMemoCharacters uint64
+}
+
+// dryRunMessage is the content of the file written for every message in dry
+// run mode
+type dryRunMessage struct {
+	TxHash  string `json:"tx_hash"`
+	MsgType string `json:"msg_type"`
+	Memo    string `json:"memo,omitempty"`
+	// hex encoded protobuf serialization of the message
+	MsgHex string          `json:"msg_hex"`
+	Msg    json.RawMessage `json:"msg"`
+}
+
+// DryRunBabylonClient wraps babylon client, so that messages are validated and
+// written to output directory instead of being sent to babylon. Queries are
+// served by wrapped client, or from fixture file if it is configured.
+type DryRunBabylonClient struct {
+	BabylonClient
+	cfg       *stakercfg.BBNConfig
+	outputDir string
+	// nil if queries are served by wrapped client
+	state  *dryRunState
+	logger *logrus.Logger
+}
+
+var _ BabylonClient = (*DryRunBabylonClient)(nil)
+
+func NewDryRunBabylonClient(
+	cl BabylonClient,
+	cfg *stakercfg.BBNConfig,
+	btcParams *chaincfg.Params,
+	logger *logrus.Logger,
+) (*DryRunBabylonClient, error) {
+	if err := os.MkdirAll(cfg.DryRunOutputDir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create dry run output directory: %w", err)
+	}
+
+	client := &DryRunBabylonClient{
+		BabylonClient: cl,
+		cfg:           cfg,
+		outputDir:     cfg.DryRunOutputDir,
+		logger:        logger,
+	}
+
+	if cfg.DryRunFixtureFile != "" {
+		state, err := loadDryRunFixture(cfg.DryRunFixtureFile, cfg.GasPrices, btcParams)
+
+		if err != nil {
+			return nil, err
+		}
+
+		client.state = state
+	}
+
+	return client, nil
+}
+
+func parseBIP340PubKey(pkHex string) (*btcec.PublicKey, error) {
+	pkBytes, err := hex.DecodeString(pkHex)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return schnorr.ParsePubKey(pkBytes)
+}
+
+func loadDryRunFixture(path string, gasPrices string, btcParams *chaincfg.Params) (*dryRunState, error) {
+	content, err := os.ReadFile(path)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to read dry run fixture file: %w", err)
+	}
+
+	var fixture DryRunFixture
+	if err := json.Unmarshal(content, &fixture); err != nil {
+		return nil, fmt.Errorf("failed to parse dry run fixture file: %w", err)
+	}
+
+	p := fixture.Params
+
+	covenantPks := make([]*btcec.PublicKey, len(p.CovenantPks))
+	for i, pkHex := range p.CovenantPks {
+		covenantPks[i], err = parseBIP340PubKey(pkHex)
+
+		if err != nil {
+			return nil, fmt.Errorf("invalid covenant key %s in dry run fixture: %w", pkHex, err)
+		}
+	}
+
+	slashingAddress, err := btcutil.DecodeAddress(p.SlashingAddress, btcParams)
+
+	if err != nil {
+		return nil, fmt.Errorf("invalid slashing address in dry run fixture: %w", err)
+	}
+
+	slashingRate, err := sdkmath.LegacyNewDecFromStr(p.SlashingRate)
+
+	if err != nil {
+		return nil, fmt.Errorf("invalid slashing rate in dry run fixture: %w", err)
+	}
+
+	state := &dryRunState{
+		params: &StakingParams{
+			ConfirmationTimeBlocks:    p.ConfirmationTimeBlocks,
+			FinalizationTimeoutBlocks: p.FinalizationTimeoutBlocks,
+			MinSlashingTxFeeSat:       btcutil.Amount(p.MinSlashingTxFeeSat),
+			CovenantPks:               covenantPks,
+			SlashingAddress:           slashingAddress,
+			SlashingRate:              slashingRate,
+			CovenantQuruomThreshold:   p.CovenantQuorum,
+			MinUnbondingTime:          p.MinUnbondingTime,
+		},
+		maxMemoCharacters: fixture.MaxMemoCharacters,
+	}
+
+	if state.maxMemoCharacters == 0 {
+		state.maxMemoCharacters = defaultDryRunMaxMemoCharacters
+	}
+
+	for _, fp := range fixture.FinalityProviders {
+		btcPk, err := parseBIP340PubKey(fp.BtcPk)
+
+		if err != nil {
+			return nil, fmt.Errorf("invalid finality provider btc key %s in dry run fixture: %w", fp.BtcPk, err)
+		}
+
+		babylonPk, err := hex.DecodeString(fp.BabylonPk)
+
+		if err != nil || len(babylonPk) != secp256k1.PubKeySize {
+			return nil, fmt.Errorf("invalid finality provider babylon key %s in dry run fixture", fp.BabylonPk)
+		}
+
+		commission := sdkmath.LegacyZeroDec()
+		if fp.Commission != "" {
+			commission, err = sdkmath.LegacyNewDecFromStr(fp.Commission)
+
+			if err != nil {
+				return nil, fmt.Errorf("invalid finality provider commission in dry run fixture: %w", err)
+			}
+		}
+
+		state.finalityProviders = append(state.finalityProviders, FinalityProviderInfo{
+			BabylonPk:  secp256k1.PubKey{Key: babylonPk},
+			BtcPk:      *btcPk,
+			Moniker:    fp.Moniker,
+			Commission: commission,
+			Slashed:    fp.Slashed,
+		})
+	}
+
+	denom, err := feeDenom(gasPrices)
+
+	if err != nil {
+		return nil, err
+	}
+
+	state.accountBalance = sdk.NewInt64Coin(denom, 0)
+
+	if fixture.AccountBalance != "" {
+		state.accountBalance, err = sdk.ParseCoinNormalized(fixture.AccountBalance)
+
+		if err != nil {
+			return nil, fmt.Errorf("invalid account balance in dry run fixture: %w", err)
+		}
+	}
+
+	return state, nil
+}
+
+func (c *DryRunBabylonClient) txSigner() string {
+	return sdk.MustBech32ifyAddressBytes(c.cfg.AccountPrefix, c.GetKeyAddress())
+}
+
+// writeMsg validates message and writes it to output directory. Returned hash is
+// synthetic, it is derived from serialized message.
+func (c *DryRunBabylonClient) writeMsg(name string, msg sdk.Msg, memo string) (string, error) {
+	if m, ok := msg.(sdk.HasValidateBasic); ok {
+		if err := m.ValidateBasic(); err != nil {
+			return "", fmt.Errorf("invalid message: %w", err)
+		}
+	}
+
+	msgBytes, err := gogoproto.Marshal(msg)
+
+	if err != nil {
+		return "", err
+	}
+
+	msgJson, err := codec.ProtoMarshalJSON(msg, nil)
+
+	if err != nil {
+		return "", err
+	}
+
+	hash := sha256.Sum256(msgBytes)
+	txHash := DryRunTxHashPrefix + strings.ToUpper(hex.EncodeToString(hash[:]))
+
+	content, err := json.MarshalIndent(dryRunMessage{
+		TxHash:  txHash,
+		MsgType: sdk.MsgTypeURL(msg),
+		Memo:    memo,
+		MsgHex:  hex.EncodeToString(msgBytes),
+		Msg:     msgJson,
+	}, "", "  ")
+
+	if err != nil {
+		return "", err
+	}
+
+	path := filepath.Join(c.outputDir, name+".json")
+
+	if err := os.WriteFile(path, content, 0600); err != nil {
+		return "", fmt.Errorf("failed to write dry run message: %w", err)
+	}
+
+	c.logger.WithFields(logrus.Fields{
+		"babylonTxHash": txHash,
+		"msgType":       sdk.MsgTypeURL(msg),
+		"path":          path,
+	}).Info("Dry run: message written to file instead of being sent to babylon")
+
+	return txHash, nil
+}
+
+func (c *DryRunBabylonClient) Delegate(dg *DelegationData) (*pv.RelayerTxResponse, error) {
+	msg, err := delegationDataToMsg(c.txSigner(), dg)
+
+	if err != nil {
+		return nil, err
+	}
+
+	name := fmt.Sprintf("delegation-%s", dg.StakingTransaction.TxHash())
+
+	txHash, err := c.writeMsg(name, msg, dg.Memo)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &pv.RelayerTxResponse{TxHash: txHash, Code: 0}, nil
+}
+
+// DelegateBatch writes each delegation of the batch to its own file, as dry run
+// output is reviewed message by message. Returned hash is hash of the last message.
+func (c *DryRunBabylonClient) DelegateBatch(dgs []*DelegationData, memo string) (*pv.RelayerTxResponse, error) {
+	var resp *pv.RelayerTxResponse
+
+	for _, dg := range dgs {
+		msg, err := delegationDataToMsg(c.txSigner(), dg)
+
+		if err != nil {
+			return nil, err
+		}
+
+		name := fmt.Sprintf("delegation-%s", dg.StakingTransaction.TxHash())
+
+		txHash, err := c.writeMsg(name, msg, memo)
+
+		if err != nil {
+			return nil, err
+		}
+
+		resp = &pv.RelayerTxResponse{TxHash: txHash, Code: 0}
+	}
+
+	return resp, nil
+}
+
+func (c *DryRunBabylonClient) Undelegate(req *UndelegationRequest) (*pv.RelayerTxResponse, error) {
+	msg := &btcstypes.MsgBTCUndelegate{
+		Signer:         c.txSigner(),
+		StakingTxHash:  req.StakingTxHash.String(),
+		UnbondingTxSig: bbntypes.NewBIP340SignatureFromBTCSig(req.StakerUnbondingSig),
+	}
+
+	name := fmt.Sprintf("undelegation-%s", req.StakingTxHash)
+
+	txHash, err := c.writeMsg(name, msg, req.Memo)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &pv.RelayerTxResponse{TxHash: txHash, Code: 0}, nil
+}
+
+// EstimateDelegationFee returns zero fee, as delegations are not paid in dry run
+// mode
+func (c *DryRunBabylonClient) EstimateDelegationFee(_ *DelegationData) (sdk.Coin, error) {
+	denom, err := feeDenom(c.cfg.GasPrices)
+
+	if err != nil {
+		return sdk.Coin{}, err
+	}
+
+	return sdk.NewInt64Coin(denom, 0), nil
+}
+
+// QueryFeeAllowance returns grant without limits, as messages are not paid in dry
+// run mode
+func (c *DryRunBabylonClient) QueryFeeAllowance() (*FeeAllowance, error) {
+	return &FeeAllowance{}, nil
+}
+
+func (c *DryRunBabylonClient) Params() (*StakingParams, error) {
+	if c.state == nil {
+		return c.BabylonClient.Params()
+	}
+
+	return c.state.params, nil
+}
+
+func (c *DryRunBabylonClient) QueryFinalityProviders(limit uint64, offset uint64, pageKey []byte) (*FinalityProvidersClientResponse, error) {
+	if c.state == nil {
+		return c.BabylonClient.QueryFinalityProviders(limit, offset, pageKey)
+	}
+
+	if len(pageKey) > 0 {
+		return nil, fmt.Errorf("paging by key is not supported in dry run mode with fixture")
+	}
+
+	fps := c.state.finalityProviders
+	total := uint64(len(fps))
+
+	if offset >= total {
+		return &FinalityProvidersClientResponse{Total: total}, nil
+	}
+
+	end := total
+	if limit > 0 && offset+limit < total {
+		end = offset + limit
+	}
+
+	return &FinalityProvidersClientResponse{
+		FinalityProviders: fps[offset:end],
+		Total:             total,
+	}, nil
+}
+
+func (c *DryRunBabylonClient) QueryFinalityProvider(btcPubKey *btcec.PublicKey) (*FinalityProviderClientResponse, error) {
+	if c.state == nil {
+		return c.BabylonClient.QueryFinalityProvider(btcPubKey)
+	}
+
+	for _, fp := range c.state.finalityProviders {
+		if !fp.BtcPk.IsEqual(btcPubKey) {
+			continue
+		}
+
+		if fp.Slashed {
+			return nil, ErrFinalityProviderIsSlashed
+		}
+
+		return &FinalityProviderClientResponse{FinalityProvider: fp}, nil
+	}
+
+	return nil, ErrFinalityProviderDoesNotExist
+}
+
+// QueryHeaderDepth with fixture returns max depth, as there is no babylon light
+// client which could lag behind btc node
+func (c *DryRunBabylonClient) QueryHeaderDepth(headerHash *chainhash.Hash) (uint64, error) {
+	if c.state == nil {
+		return c.BabylonClient.QueryHeaderDepth(headerHash)
+	}
+
+	return math.MaxUint64, nil
+}
+
+func (c *DryRunBabylonClient) IsTxAlreadyPartOfDelegation(stakingTxHash *chainhash.Hash) (bool, error) {
+	if c.state == nil {
+		return c.BabylonClient.IsTxAlreadyPartOfDelegation(stakingTxHash)
+	}
+
+	return false, nil
+}
+
+func (c *DryRunBabylonClient) QueryDelegationInfo(stakingTxHash *chainhash.Hash) (*DelegationInfo, error) {
+	if c.state == nil {
+		return c.BabylonClient.QueryDelegationInfo(stakingTxHash)
+	}
+
+	return nil, ErrDelegationNotFound
+}
+
+func (c *DryRunBabylonClient) QueryDelegationsByStakerKey(stakerKey *btcec.PublicKey) ([]*StakerDelegation, error) {
+	if c.state == nil {
+		return c.BabylonClient.QueryDelegationsByStakerKey(stakerKey)
+	}
+
+	return nil, nil
+}
+
+func (c *DryRunBabylonClient) MaxMemoCharacters() (uint64, error) {
+	if c.state == nil {
+		return c.BabylonClient.MaxMemoCharacters()
+	}
+
+	return c.state.maxMemoCharacters, nil
+}
+
+func (c *DryRunBabylonClient) QueryAccountBalance() (sdk.Coin, error) {
+	if c.state == nil {
+		return c.BabylonClient.QueryAccountBalance()
+	}
+
+	return c.state.accountBalance, nil
+}
@@ -0,0 +1,108 @@
+package babylonclient
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/babylonchain/btc-staker/stakercfg"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+func randomBIP340Key(t *testing.T) (*btcec.PublicKey, string) {
+	priv, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	return priv.PubKey(), hex.EncodeToString(schnorr.SerializePubKey(priv.PubKey()))
+}
+
+func TestDryRunClientServesFixture(t *testing.T) {
+	covenantPk, covenantPkHex := randomBIP340Key(t)
+	fpPk, fpPkHex := randomBIP340Key(t)
+	slashedFpPk, slashedFpPkHex := randomBIP340Key(t)
+
+	slashingAddress, err := btcutil.NewAddressPubKey(covenantPk.SerializeCompressed(), &chaincfg.SimNetParams)
+	require.NoError(t, err)
+
+	babylonPkHex := hex.EncodeToString(secp256k1.GenPrivKey().PubKey().Bytes())
+
+	fixture := DryRunFixture{
+		Params: DryRunParamsFixture{
+			ConfirmationTimeBlocks:    2,
+			FinalizationTimeoutBlocks: 5,
+			MinSlashingTxFeeSat:       1000,
+			CovenantPks:               []string{covenantPkHex},
+			CovenantQuorum:            1,
+			SlashingAddress:           slashingAddress.EncodeAddress(),
+			SlashingRate:              "0.1",
+			MinUnbondingTime:          10,
+		},
+		FinalityProviders: []DryRunFinalityProviderFixture{
+			{BtcPk: fpPkHex, BabylonPk: babylonPkHex, Moniker: "fp", Commission: "0.05"},
+			{BtcPk: slashedFpPkHex, BabylonPk: babylonPkHex, Slashed: true},
+		},
+		AccountBalance: "500ubbn",
+	}
+
+	dir := t.TempDir()
+	fixturePath := filepath.Join(dir, "fixture.json")
+	content, err := json.Marshal(fixture)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(fixturePath, content, 0600))
+
+	cfg := stakercfg.DefaultBBNConfig()
+	cfg.GasPrices = "0.002ubbn"
+	cfg.DryRun = true
+	cfg.DryRunOutputDir = filepath.Join(dir, "out")
+	cfg.DryRunFixtureFile = fixturePath
+
+	client, err := NewDryRunBabylonClient(GetMockClient(), &cfg, &chaincfg.SimNetParams, logrus.New())
+	require.NoError(t, err)
+	require.DirExists(t, cfg.DryRunOutputDir)
+
+	params, err := client.Params()
+	require.NoError(t, err)
+	require.Equal(t, uint32(2), params.ConfirmationTimeBlocks)
+	require.Equal(t, uint16(10), params.MinUnbondingTime)
+	require.True(t, covenantPk.IsEqual(params.CovenantPks[0]))
+	require.Equal(t, "0.100000000000000000", params.SlashingRate.String())
+
+	fps, err := client.QueryFinalityProviders(1, 1, nil)
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), fps.Total)
+	require.Len(t, fps.FinalityProviders, 1)
+	require.True(t, fps.FinalityProviders[0].Slashed)
+
+	fp, err := client.QueryFinalityProvider(fpPk)
+	require.NoError(t, err)
+	require.Equal(t, "fp", fp.FinalityProvider.Moniker)
+
+	_, err = client.QueryFinalityProvider(slashedFpPk)
+	require.ErrorIs(t, err, ErrFinalityProviderIsSlashed)
+
+	balance, err := client.QueryAccountBalance()
+	require.NoError(t, err)
+	require.Equal(t, "500ubbn", balance.String())
+
+	fee, err := client.EstimateDelegationFee(nil)
+	require.NoError(t, err)
+	require.True(t, fee.IsZero())
+
+	memoSize, err := client.MaxMemoCharacters()
+	require.NoError(t, err)
+	require.Equal(t, uint64(defaultDryRunMaxMemoCharacters), memoSize)
+}
+
+func TestIsDryRunTxHash(t *testing.T) {
+	require.True(t, IsDryRunTxHash(DryRunTxHashPrefix+"AB01"))
+	require.False(t, IsDryRunTxHash("AB01"))
+	require.False(t, IsDryRunTxHash(""))
+}
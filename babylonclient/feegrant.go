@@ -0,0 +1,694 @@
+package babylonclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"cosmossdk.io/x/feegrant"
+	"github.com/avast/retry-go/v4"
+	btcstypes "github.com/babylonchain/babylon/x/btcstaking/types"
+	abci "github.com/cometbft/cometbft/abci/types"
+	coretypes "github.com/cometbft/cometbft/rpc/core/types"
+	"github.com/cosmos/cosmos-sdk/client"
+	"github.com/cosmos/cosmos-sdk/client/tx"
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	txtypes "github.com/cosmos/cosmos-sdk/types/tx"
+	"github.com/cosmos/cosmos-sdk/types/tx/signing"
+	authsigning "github.com/cosmos/cosmos-sdk/x/auth/signing"
+	pv "github.com/cosmos/relayer/v2/relayer/provider"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// interval of polling babylon node for inclusion of transaction in block
+	txInclusionPollInterval = 500 * time.Millisecond
+)
+
+var (
+	// ErrFeeGrantNotFound is returned when there is no fee grant from configured fee
+	// granter to the account paying fees
+	ErrFeeGrantNotFound = errors.New("babylon fee grant not found")
+	// ErrFeeGrantExhausted is returned when babylon rejected transaction because fee
+	// grant paying its fees is spent, expired or revoked. Transaction can be sent
+	// again once the grant is renewed.
+	ErrFeeGrantExhausted = errors.New("babylon fee grant exhausted")
+)
+
+// feeGrantErrors are messages of errors returned by babylon when fee grant cannot
+// pay fees of transaction. Grant which was spent or expired is removed by babylon
+// when it is used, so later transactions fail as grant is not found.
+var feeGrantErrors = []string{
+	feegrant.ErrFeeLimitExceeded.Error(),
+	feegrant.ErrFeeLimitExpired.Error(),
+	"fee-grant not found",
+}
+
+// classifyFeeGrantError wraps err in ErrFeeGrantExhausted if it was caused by fee
+// grant which cannot pay fees of transaction
+func classifyFeeGrantError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	for _, msg := range feeGrantErrors {
+		if strings.Contains(err.Error(), msg) {
+			return fmt.Errorf("%w: %w", ErrFeeGrantExhausted, err)
+		}
+	}
+
+	return err
+}
+
+// feeAccounts are babylon accounts paying fees of transactions instead of the
+// controller key
+type feeAccounts struct {
+	// account which granted fee allowance, nil if fees are not paid from grant
+	granter sdk.AccAddress
+	// account paying fees, nil if fees are paid by controller key. Fee payer must
+	// sign transactions, so its key must be in the keyring.
+	payer        sdk.AccAddress
+	payerKeyName string
+}
+
+// newFeeAccounts parses fee granter and fee payer addresses. Nil is returned if
+// neither is configured, so that transactions are sent by babylon provider.
+func newFeeAccounts(
+	granter, payer string,
+	accountPrefix string,
+	signer sdk.AccAddress,
+	keyByAddress func(addr sdk.AccAddress) (string, error),
+) (*feeAccounts, error) {
+	if granter == "" && payer == "" {
+		return nil, nil
+	}
+
+	accounts := &feeAccounts{}
+
+	if granter != "" {
+		granterBytes, err := sdk.GetFromBech32(granter, accountPrefix)
+
+		if err != nil {
+			return nil, fmt.Errorf("invalid fee granter address %s: %w", granter, err)
+		}
+
+		accounts.granter = granterBytes
+	}
+
+	// fees paid by the signer itself do not need separate fee payer
+	if payer != "" {
+		payerBytes, err := sdk.GetFromBech32(payer, accountPrefix)
+
+		if err != nil {
+			return nil, fmt.Errorf("invalid fee payer address %s: %w", payer, err)
+		}
+
+		if !signer.Equals(sdk.AccAddress(payerBytes)) {
+			keyName, err := keyByAddress(payerBytes)
+
+			if err != nil {
+				return nil, fmt.Errorf("key of fee payer %s not found in keyring: %w", payer, err)
+			}
+
+			accounts.payer = payerBytes
+			accounts.payerKeyName = keyName
+		}
+	}
+
+	if accounts.granter != nil && accounts.granter.Equals(accounts.grantee(signer)) {
+		return nil, fmt.Errorf("fee granter %s cannot grant fees to itself", granter)
+	}
+
+	return accounts, nil
+}
+
+// grantee returns account to which fee grant must be given, fee grant is used by
+// the account paying fees
+func (f *feeAccounts) grantee(signer sdk.AccAddress) sdk.AccAddress {
+	if f.payer != nil {
+		return f.payer
+	}
+
+	return signer
+}
+
+// feesPaidBy returns account whose funds pay fees of transactions
+func (f *feeAccounts) feesPaidBy(signer sdk.AccAddress) sdk.AccAddress {
+	if f.granter != nil {
+		return f.granter
+	}
+
+	return f.grantee(signer)
+}
+
+func (f *feeAccounts) apply(txf tx.Factory) tx.Factory {
+	if f.granter != nil {
+		txf = txf.WithFeeGranter(f.granter)
+	}
+
+	if f.payer != nil {
+		txf = txf.WithFeePayer(f.payer)
+	}
+
+	return txf
+}
+
+// feeTxSigner is account signing transaction sent with fee accounts
+type feeTxSigner struct {
+	keyName       string
+	pubKey        cryptotypes.PubKey
+	accountNumber uint64
+	sequence      uint64
+}
+
+// prepareFeeTx returns tx factory with fee accounts set, and signers of the
+// transaction. Controller key signs messages, fee payer signs as the last signer.
+func (s *memoSender) prepareFeeTx(memo string) (tx.Factory, []feeTxSigner, error) {
+	txf, err := s.provider.PrepareFactory(s.provider.TxFactory(), s.provider.Key())
+
+	if err != nil {
+		return tx.Factory{}, nil, err
+	}
+
+	txf = s.fees.apply(txf.WithMemo(memo))
+
+	signerKey, err := s.provider.Keybase.Key(s.provider.Key())
+
+	if err != nil {
+		return tx.Factory{}, nil, err
+	}
+
+	signerPubKey, err := signerKey.GetPubKey()
+
+	if err != nil {
+		return tx.Factory{}, nil, err
+	}
+
+	signers := []feeTxSigner{{
+		keyName:       s.provider.Key(),
+		pubKey:        signerPubKey,
+		accountNumber: txf.AccountNumber(),
+		sequence:      txf.Sequence(),
+	}}
+
+	if s.fees.payer == nil {
+		return txf, signers, nil
+	}
+
+	payerKey, err := s.provider.Keybase.Key(s.fees.payerKeyName)
+
+	if err != nil {
+		return tx.Factory{}, nil, err
+	}
+
+	payerPubKey, err := payerKey.GetPubKey()
+
+	if err != nil {
+		return tx.Factory{}, nil, err
+	}
+
+	cliCtx := client.Context{}.WithClient(s.provider.RPCClient).
+		WithInterfaceRegistry(s.provider.Cdc.InterfaceRegistry).
+		WithChainID(s.provider.PCfg.ChainID).
+		WithCodec(s.provider.Cdc.Marshaler)
+
+	payerNum, payerSeq, err := txf.AccountRetriever().GetAccountNumberSequence(cliCtx, s.fees.payer)
+
+	if err != nil {
+		return tx.Factory{}, nil, fmt.Errorf("failed to retrieve fee payer account: %w", err)
+	}
+
+	signers = append(signers, feeTxSigner{
+		keyName:       s.fees.payerKeyName,
+		pubKey:        payerPubKey,
+		accountNumber: payerNum,
+		sequence:      payerSeq,
+	})
+
+	return txf, signers, nil
+}
+
+func (s *memoSender) signMode(txf tx.Factory) (signing.SignMode, error) {
+	if signMode := txf.SignMode(); signMode != signing.SignMode_SIGN_MODE_UNSPECIFIED {
+		return signMode, nil
+	}
+
+	return authsigning.APISignModeToInternal(s.provider.Cdc.TxConfig.SignModeHandler().DefaultMode())
+}
+
+// emptySignatures returns signatures without signature bytes. They are used in
+// simulation, and to set signer infos of all signers, which are part of sign bytes
+// in direct sign mode.
+func emptySignatures(signers []feeTxSigner, signMode signing.SignMode) []signing.SignatureV2 {
+	sigs := make([]signing.SignatureV2, len(signers))
+
+	for i, signer := range signers {
+		sigs[i] = signing.SignatureV2{
+			PubKey:   signer.pubKey,
+			Data:     &signing.SingleSignatureData{SignMode: signMode},
+			Sequence: signer.sequence,
+		}
+	}
+
+	return sigs
+}
+
+// simulateFeeTx returns gas used by transaction signed by given signers, adjusted
+// by configured gas adjustment
+func (s *memoSender) simulateFeeTx(
+	ctx context.Context,
+	txf tx.Factory,
+	signers []feeTxSigner,
+	msgs []sdk.Msg,
+) (uint64, error) {
+	signMode, err := s.signMode(txf)
+
+	if err != nil {
+		return 0, err
+	}
+
+	txb, err := txf.BuildUnsignedTx(msgs...)
+
+	if err != nil {
+		return 0, err
+	}
+
+	if err := txb.SetSignatures(emptySignatures(signers, signMode)...); err != nil {
+		return 0, err
+	}
+
+	txBytes, err := s.provider.Cdc.TxConfig.TxEncoder()(txb.GetTx())
+
+	if err != nil {
+		return 0, err
+	}
+
+	simReq := txtypes.SimulateRequest{TxBytes: txBytes}
+	reqBytes, err := simReq.Marshal()
+
+	if err != nil {
+		return 0, err
+	}
+
+	res, err := s.provider.QueryABCI(ctx, abci.RequestQuery{
+		Path: "/cosmos.tx.v1beta1.Service/Simulate",
+		Data: reqBytes,
+	})
+
+	if err != nil {
+		return 0, classifyFeeGrantError(err)
+	}
+
+	var simRes txtypes.SimulateResponse
+	if err := simRes.Unmarshal(res.Value); err != nil {
+		return 0, err
+	}
+
+	return s.provider.AdjustEstimatedGas(simRes.GasInfo.GasUsed)
+}
+
+// signFeeTx signs transaction by all signers. Cosmos tx factory only supports
+// transactions with single signer in direct sign mode, so transaction is signed
+// directly with the keyring.
+func (s *memoSender) signFeeTx(
+	ctx context.Context,
+	txf tx.Factory,
+	txb client.TxBuilder,
+	signers []feeTxSigner,
+) error {
+	signMode, err := s.signMode(txf)
+
+	if err != nil {
+		return err
+	}
+
+	sigs := emptySignatures(signers, signMode)
+
+	if err := txb.SetSignatures(sigs...); err != nil {
+		return err
+	}
+
+	for i, signer := range signers {
+		signerData := authsigning.SignerData{
+			ChainID:       txf.ChainID(),
+			AccountNumber: signer.accountNumber,
+			Sequence:      signer.sequence,
+			PubKey:        signer.pubKey,
+			Address:       sdk.AccAddress(signer.pubKey.Address()).String(),
+		}
+
+		bytesToSign, err := authsigning.GetSignBytesAdapter(
+			ctx,
+			s.provider.Cdc.TxConfig.SignModeHandler(),
+			signMode,
+			signerData,
+			txb.GetTx(),
+		)
+
+		if err != nil {
+			return err
+		}
+
+		sigBytes, _, err := s.provider.Keybase.Sign(signer.keyName, bytesToSign, signMode)
+
+		if err != nil {
+			return err
+		}
+
+		sigs[i].Data = &signing.SingleSignatureData{
+			SignMode:  signMode,
+			Signature: sigBytes,
+		}
+	}
+
+	return txb.SetSignatures(sigs...)
+}
+
+// buildFeeTx builds and signs transaction with fees paid by fee accounts
+func (s *memoSender) buildFeeTx(ctx context.Context, msgs []sdk.Msg, memo string) ([]byte, error) {
+	done := s.provider.SetSDKContext()
+	defer done()
+
+	txf, signers, err := s.prepareFeeTx(memo)
+
+	if err != nil {
+		return nil, err
+	}
+
+	gas, err := s.simulateFeeTx(ctx, txf, signers, msgs)
+
+	if err != nil {
+		return nil, err
+	}
+
+	txb, err := txf.WithGas(gas).BuildUnsignedTx(msgs...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.signFeeTx(ctx, txf, txb, signers); err != nil {
+		return nil, err
+	}
+
+	return s.provider.Cdc.TxConfig.TxEncoder()(txb.GetTx())
+}
+
+// simulateWithFeeAccounts returns gas used by transaction with fees paid by fee
+// accounts, adjusted by configured gas adjustment
+func (s *memoSender) simulateWithFeeAccounts(ctx context.Context, msgs []sdk.Msg, memo string) (uint64, error) {
+	done := s.provider.SetSDKContext()
+	defer done()
+
+	txf, signers, err := s.prepareFeeTx(memo)
+
+	if err != nil {
+		return 0, err
+	}
+
+	return s.simulateFeeTx(ctx, txf, signers, msgs)
+}
+
+// broadcastFeeTx sends transaction with fees paid by fee accounts to babylon
+// mempool and returns its hash
+func (s *memoSender) broadcastFeeTx(ctx context.Context, msgs []sdk.Msg, memo string) ([]byte, error) {
+	txBytes, err := s.buildFeeTx(ctx, msgs, memo)
+
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := s.provider.RPCClient.BroadcastTxSync(ctx, txBytes)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if res.Code != 0 {
+		return nil, classifyFeeGrantError(&BabylonSubmitError{
+			Codespace: res.Codespace,
+			Code:      res.Code,
+			RawLog:    res.Log,
+			TxHash:    res.Hash.String(),
+		})
+	}
+
+	return res.Hash, nil
+}
+
+// waitForTxInclusion polls babylon node until transaction is included in block,
+// at most for configured block timeout
+func (s *memoSender) waitForTxInclusion(ctx context.Context, txHash []byte) (*coretypes.ResultTx, error) {
+	timeout := time.After(s.blockTimeout)
+
+	for {
+		select {
+		case <-time.After(txInclusionPollInterval):
+			res, err := s.provider.RPCClient.Tx(ctx, txHash, false)
+
+			if err == nil {
+				return res, nil
+			}
+		case <-timeout:
+			return nil, fmt.Errorf("transaction %X was not included in babylon block after %s", txHash, s.blockTimeout)
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// reliablySendFeeTx sends messages in transaction with fees paid by fee accounts
+// and waits until transaction is included in block. Transactions are sent one at
+// a time, so that account sequence of signers is known from their accounts.
+func (s *memoSender) reliablySendFeeTx(
+	ctx context.Context,
+	msgs []sdk.Msg,
+	memo string,
+) (*pv.RelayerTxResponse, error) {
+	s.feeTxMu.Lock()
+	defer s.feeTxMu.Unlock()
+
+	var txHash []byte
+
+	if err := retry.Do(func() error {
+		var sendErr error
+		krErr := s.accessKeyWithLock(func() {
+			txHash, sendErr = s.broadcastFeeTx(ctx, msgs, memo)
+		})
+		if krErr != nil {
+			return retry.Unrecoverable(krErr)
+		}
+		// retrying is pointless until fee grant is renewed, or if babylon will
+		// reject the same transaction again
+		if errors.Is(sendErr, ErrFeeGrantExhausted) || IsTerminalSubmitError(sendErr) {
+			return retry.Unrecoverable(sendErr)
+		}
+		return sendErr
+	}, retry.Context(ctx), RtyAtt, RtyDel, RtyErr, retry.OnRetry(func(n uint, err error) {
+		s.logger.WithFields(logrus.Fields{
+			"attempt":      n + 1,
+			"max_attempts": RtyAttNum,
+			"error":        err,
+		}).Debug("Failed to send transaction to babylon, retrying")
+	})); err != nil {
+		return nil, err
+	}
+
+	res, err := s.waitForTxInclusion(ctx, txHash)
+
+	if err != nil {
+		return nil, err
+	}
+
+	rlyResp := &pv.RelayerTxResponse{
+		Height:    res.Height,
+		TxHash:    res.Hash.String(),
+		Codespace: res.TxResult.Codespace,
+		Code:      res.TxResult.Code,
+		Data:      fmt.Sprintf("%X", res.TxResult.Data),
+	}
+
+	for _, event := range res.TxResult.Events {
+		attributes := make(map[string]string, len(event.Attributes))
+		for _, attribute := range event.Attributes {
+			attributes[attribute.Key] = attribute.Value
+		}
+
+		rlyResp.Events = append(rlyResp.Events, pv.RelayerEvent{
+			EventType:  event.Type,
+			Attributes: attributes,
+		})
+	}
+
+	if rlyResp.Code != 0 {
+		return rlyResp, classifyFeeGrantError(&BabylonSubmitError{
+			Codespace: rlyResp.Codespace,
+			Code:      rlyResp.Code,
+			RawLog:    res.TxResult.Log,
+			Executed:  true,
+			TxHash:    rlyResp.TxHash,
+			Height:    rlyResp.Height,
+		})
+	}
+
+	return rlyResp, nil
+}
+
+// stakingMsgTypeURLs are type urls of messages sent by staker with fees paid by
+// fee accounts
+var stakingMsgTypeURLs = []string{
+	sdk.MsgTypeURL(&btcstypes.MsgCreateBTCDelegation{}),
+	sdk.MsgTypeURL(&btcstypes.MsgBTCUndelegate{}),
+}
+
+// FeeAllowance is fee grant from configured fee granter to the account paying
+// fees of babylon transactions
+type FeeAllowance struct {
+	Granter string
+	Grantee string
+	// amount which can still be spent on fees, nil if grant has no spend limit.
+	// For periodic grants it is amount which can be spent in current period.
+	SpendLimit sdk.Coins
+	// nil if grant does not expire
+	Expiration *time.Time
+	// type urls of messages fees of which can be paid, empty if any message is
+	// allowed
+	AllowedMessages []string
+}
+
+// AllowsMessage returns true if grant can pay fees of message with given type url
+func (a *FeeAllowance) AllowsMessage(msgTypeURL string) bool {
+	if len(a.AllowedMessages) == 0 {
+		return true
+	}
+
+	for _, allowed := range a.AllowedMessages {
+		if allowed == msgTypeURL {
+			return true
+		}
+	}
+
+	return false
+}
+
+// DisallowedStakingMessages returns type urls of messages sent by staker, fees of
+// which cannot be paid by the grant
+func (a *FeeAllowance) DisallowedStakingMessages() []string {
+	var disallowed []string
+
+	for _, msgTypeURL := range stakingMsgTypeURLs {
+		if !a.AllowsMessage(msgTypeURL) {
+			disallowed = append(disallowed, msgTypeURL)
+		}
+	}
+
+	return disallowed
+}
+
+// Expired returns true if grant expired at given time
+func (a *FeeAllowance) Expired(now time.Time) bool {
+	return a.Expiration != nil && !now.Before(*a.Expiration)
+}
+
+func unpackFeeAllowance(registry codectypes.InterfaceRegistry, any *codectypes.Any) (feegrant.FeeAllowanceI, error) {
+	var allowance feegrant.FeeAllowanceI
+
+	if err := registry.UnpackAny(any, &allowance); err != nil {
+		return nil, err
+	}
+
+	return allowance, nil
+}
+
+func feeAllowanceFromGrant(
+	registry codectypes.InterfaceRegistry,
+	allowance feegrant.FeeAllowanceI,
+	result *FeeAllowance,
+) error {
+	switch a := allowance.(type) {
+	case *feegrant.BasicAllowance:
+		result.SpendLimit = a.SpendLimit
+		result.Expiration = a.Expiration
+	case *feegrant.PeriodicAllowance:
+		result.SpendLimit = a.PeriodCanSpend
+		result.Expiration = a.Basic.Expiration
+	case *feegrant.AllowedMsgAllowance:
+		inner, err := unpackFeeAllowance(registry, a.Allowance)
+
+		if err != nil {
+			return err
+		}
+
+		result.AllowedMessages = a.AllowedMessages
+
+		return feeAllowanceFromGrant(registry, inner, result)
+	default:
+		return fmt.Errorf("unsupported fee allowance type %T", allowance)
+	}
+
+	return nil
+}
+
+// QueryFeeAllowance returns fee grant from configured fee granter to the account
+// paying fees. ErrFeeGrantNotFound is returned if there is no such grant.
+func (bc *BabylonController) QueryFeeAllowance() (*FeeAllowance, error) {
+	fees := bc.memoSender.fees
+
+	if fees == nil || fees.granter == nil {
+		return nil, fmt.Errorf("babylon fee granter is not configured")
+	}
+
+	granter, err := bc.memoSender.provider.EncodeBech32AccAddr(fees.granter)
+
+	if err != nil {
+		return nil, err
+	}
+
+	grantee, err := bc.memoSender.provider.EncodeBech32AccAddr(fees.grantee(bc.GetKeyAddress()))
+
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := getQueryContext(bc.cfg.Timeout)
+	defer cancel()
+
+	clientCtx := client.Context{Client: bc.queryClient.RPCClient}
+	queryClient := feegrant.NewQueryClient(clientCtx)
+
+	response, err := queryClient.Allowance(ctx, &feegrant.QueryAllowanceRequest{
+		Granter: granter,
+		Grantee: grantee,
+	})
+
+	if err != nil {
+		if strings.Contains(err.Error(), "fee-grant not found") {
+			return nil, fmt.Errorf("%w: granter: %s, grantee: %s", ErrFeeGrantNotFound, granter, grantee)
+		}
+
+		return nil, err
+	}
+
+	registry := bc.memoSender.provider.Cdc.InterfaceRegistry
+
+	allowance, err := unpackFeeAllowance(registry, response.Allowance.Allowance)
+
+	if err != nil {
+		return nil, err
+	}
+
+	result := &FeeAllowance{
+		Granter: granter,
+		Grantee: grantee,
+	}
+
+	if err := feeAllowanceFromGrant(registry, allowance, result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
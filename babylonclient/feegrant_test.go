@@ -0,0 +1,99 @@
+package babylonclient
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"cosmossdk.io/x/feegrant"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClassifyFeeGrantError(t *testing.T) {
+	exhausted := []error{
+		fmt.Errorf("bbn1granter does not allow to pay fees for bbn1staker: %w", feegrant.ErrFeeLimitExceeded),
+		errors.New("transaction rejected by babylon: codespace: feegrant, code: 3, log: fee allowance expired"),
+		errors.New("rpc error: code = Unknown desc = fee-grant not found: not found"),
+	}
+
+	for _, err := range exhausted {
+		require.ErrorIs(t, classifyFeeGrantError(err), ErrFeeGrantExhausted, err.Error())
+	}
+
+	other := errors.New("account sequence mismatch, expected 10, got 9: incorrect account sequence")
+	require.Equal(t, other, classifyFeeGrantError(other))
+	require.NoError(t, classifyFeeGrantError(nil))
+}
+
+func TestNewFeeAccounts(t *testing.T) {
+	signer := sdk.AccAddress([]byte("signer______________"))
+	granter := sdk.AccAddress([]byte("granter_____________"))
+	payer := sdk.AccAddress([]byte("payer_______________"))
+
+	bech32 := func(addr sdk.AccAddress) string {
+		s, err := sdk.Bech32ifyAddressBytes("bbn", addr)
+		require.NoError(t, err)
+		return s
+	}
+
+	keyByAddress := func(addr sdk.AccAddress) (string, error) {
+		if addr.Equals(payer) {
+			return "payer-key", nil
+		}
+
+		return "", errors.New("key not found")
+	}
+
+	accounts, err := newFeeAccounts("", "", "bbn", signer, keyByAddress)
+	require.NoError(t, err)
+	require.Nil(t, accounts)
+
+	accounts, err = newFeeAccounts(bech32(granter), "", "bbn", signer, keyByAddress)
+	require.NoError(t, err)
+	require.Equal(t, signer, accounts.grantee(signer))
+	require.Equal(t, granter, accounts.feesPaidBy(signer))
+
+	accounts, err = newFeeAccounts(bech32(granter), bech32(payer), "bbn", signer, keyByAddress)
+	require.NoError(t, err)
+	require.Equal(t, "payer-key", accounts.payerKeyName)
+	require.Equal(t, payer, accounts.grantee(signer))
+	require.Equal(t, granter, accounts.feesPaidBy(signer))
+
+	accounts, err = newFeeAccounts("", bech32(payer), "bbn", signer, keyByAddress)
+	require.NoError(t, err)
+	require.Equal(t, payer, accounts.feesPaidBy(signer))
+
+	// signer paying its own fees does not sign twice
+	accounts, err = newFeeAccounts(bech32(granter), bech32(signer), "bbn", signer, keyByAddress)
+	require.NoError(t, err)
+	require.Nil(t, accounts.payer)
+
+	// fee payer must be able to sign
+	_, err = newFeeAccounts("", bech32(granter), "bbn", signer, keyByAddress)
+	require.Error(t, err)
+
+	_, err = newFeeAccounts(bech32(signer), "", "bbn", signer, keyByAddress)
+	require.Error(t, err)
+
+	_, err = newFeeAccounts("cosmos1invalid", "", "bbn", signer, keyByAddress)
+	require.Error(t, err)
+}
+
+func TestFeeAllowance(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	expiration := now.Add(time.Hour)
+
+	unlimited := &FeeAllowance{}
+	require.Empty(t, unlimited.DisallowedStakingMessages())
+	require.False(t, unlimited.Expired(now))
+
+	restricted := &FeeAllowance{
+		Expiration:      &expiration,
+		AllowedMessages: []string{stakingMsgTypeURLs[0]},
+	}
+	require.Equal(t, stakingMsgTypeURLs[1:], restricted.DisallowedStakingMessages())
+	require.False(t, restricted.Expired(now))
+	require.True(t, restricted.Expired(expiration))
+}
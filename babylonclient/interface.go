@@ -50,12 +50,24 @@ type BabylonClient interface {
 	SingleKeyKeyring
 	Params() (*StakingParams, error)
 	Delegate(dg *DelegationData) (*pv.RelayerTxResponse, error)
+	// DelegateBatch sends all delegations as messages of single babylon transaction
+	// with given memo. Failure of any message fails the whole transaction.
+	DelegateBatch(dgs []*DelegationData, memo string) (*pv.RelayerTxResponse, error)
+	BuildDelegationMsg(signer string, dg *DelegationData) ([]byte, error)
 	Undelegate(req *UndelegationRequest) (*pv.RelayerTxResponse, error)
-	QueryFinalityProviders(limit uint64, offset uint64) (*FinalityProvidersClientResponse, error)
+	QueryFinalityProviders(limit uint64, offset uint64, pageKey []byte) (*FinalityProvidersClientResponse, error)
 	QueryFinalityProvider(btcPubKey *btcec.PublicKey) (*FinalityProviderClientResponse, error)
 	QueryHeaderDepth(headerHash *chainhash.Hash) (uint64, error)
 	IsTxAlreadyPartOfDelegation(stakingTxHash *chainhash.Hash) (bool, error)
 	QueryDelegationInfo(stakingTxHash *chainhash.Hash) (*DelegationInfo, error)
+	QueryDelegationsByStakerKey(stakerKey *btcec.PublicKey) ([]*StakerDelegation, error)
+	MaxMemoCharacters() (uint64, error)
+	// QueryAccountBalance returns balance of the account used to pay fees
+	QueryAccountBalance() (sdk.Coin, error)
+	// EstimateDelegationFee returns fee which would be paid for sending delegation
+	EstimateDelegationFee(dg *DelegationData) (sdk.Coin, error)
+	// QueryFeeAllowance returns fee grant from configured fee granter
+	QueryFeeAllowance() (*FeeAllowance, error)
 }
 
 type MockBabylonClient struct {
@@ -109,7 +121,31 @@ func (m *MockBabylonClient) Delegate(dg *DelegationData) (*pv.RelayerTxResponse,
 	return &pv.RelayerTxResponse{Code: 0}, nil
 }
 
-func (m *MockBabylonClient) QueryFinalityProviders(limit uint64, offset uint64) (*FinalityProvidersClientResponse, error) {
+func (m *MockBabylonClient) DelegateBatch(dgs []*DelegationData, _ string) (*pv.RelayerTxResponse, error) {
+	for _, dg := range dgs {
+		if _, err := m.Delegate(dg); err != nil {
+			return nil, err
+		}
+	}
+
+	return &pv.RelayerTxResponse{Code: 0}, nil
+}
+
+func (m *MockBabylonClient) BuildDelegationMsg(signer string, dg *DelegationData) ([]byte, error) {
+	if signer == "" {
+		signer = "signer"
+	}
+
+	msg, err := delegationDataToMsg(signer, dg)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return msg.Marshal()
+}
+
+func (m *MockBabylonClient) QueryFinalityProviders(limit uint64, offset uint64, pageKey []byte) (*FinalityProvidersClientResponse, error) {
 	return &FinalityProvidersClientResponse{
 		FinalityProviders: []FinalityProviderInfo{*m.ActiveFinalityProvider},
 		Total:             1,
@@ -139,11 +175,33 @@ func (m *MockBabylonClient) QueryDelegationInfo(stakingTxHash *chainhash.Hash) (
 	return nil, fmt.Errorf("delegation do not exist")
 }
 
+func (m *MockBabylonClient) QueryDelegationsByStakerKey(stakerKey *btcec.PublicKey) ([]*StakerDelegation, error) {
+	return nil, nil
+}
+
 func (m *MockBabylonClient) Undelegate(
 	req *UndelegationRequest) (*pv.RelayerTxResponse, error) {
 	return &pv.RelayerTxResponse{Code: 0}, nil
 }
 
+func (m *MockBabylonClient) MaxMemoCharacters() (uint64, error) {
+	// default max memo size of cosmos chains
+	return 256, nil
+}
+
+func (m *MockBabylonClient) QueryAccountBalance() (sdk.Coin, error) {
+	return sdk.NewInt64Coin("ubbn", 1000000000), nil
+}
+
+func (m *MockBabylonClient) EstimateDelegationFee(dg *DelegationData) (sdk.Coin, error) {
+	return sdk.NewInt64Coin("ubbn", 1000), nil
+}
+
+func (m *MockBabylonClient) QueryFeeAllowance() (*FeeAllowance, error) {
+	// grant without limits
+	return &FeeAllowance{}, nil
+}
+
 func GetMockClient() *MockBabylonClient {
 	covenantPk, err := btcec.NewPrivateKey()
 	if err != nil {
@@ -163,8 +221,9 @@ func GetMockClient() *MockBabylonClient {
 	fpBabylonPubKey := fpBabylonPrivKey.PubKey().(*secp256k1.PubKey)
 
 	vi := FinalityProviderInfo{
-		BabylonPk: *fpBabylonPubKey,
-		BtcPk:     *fpBtcPrivKey.PubKey(),
+		BabylonPk:  *fpBabylonPubKey,
+		BtcPk:      *fpBtcPrivKey.PubKey(),
+		Commission: sdkmath.LegacyZeroDec(),
 	}
 
 	return &MockBabylonClient{
@@ -0,0 +1,277 @@
+package babylonclient
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"path"
+	"sync"
+	"time"
+
+	"github.com/avast/retry-go/v4"
+	bbnapp "github.com/babylonchain/babylon/app"
+	"github.com/babylonchain/btc-staker/stakercfg"
+	bbnclient "github.com/babylonchain/rpc-client/client"
+	bbncfg "github.com/babylonchain/rpc-client/config"
+	rpchttp "github.com/cometbft/cometbft/rpc/client/http"
+	"github.com/cosmos/cosmos-sdk/client"
+	"github.com/cosmos/cosmos-sdk/crypto/keyring"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	"github.com/cosmos/relayer/v2/relayer/chains/cosmos"
+	pv "github.com/cosmos/relayer/v2/relayer/provider"
+	"github.com/juju/fslock"
+	"github.com/sirupsen/logrus"
+	"go.uber.org/zap"
+)
+
+// memoSender sends transactions to babylon with cosmos tx memo. Babylon rpc client
+// always sends transactions with empty memo, so memo sender uses its own cosmos
+// provider created from the same config. All transactions and queries of the
+// controller go through provider of memo sender, so that account sequence is
+// tracked by single provider.
+// If fee granter or fee payer is configured, transactions are built and signed by
+// memo sender itself, as provider can only use fee grants of keys in the keyring.
+type memoSender struct {
+	provider     *cosmos.CosmosProvider
+	logger       *logrus.Logger
+	blockTimeout time.Duration
+	// nil if fees are paid by the controller key
+	fees *feeAccounts
+	// transactions with fee accounts are sent one at a time
+	feeTxMu sync.Mutex
+}
+
+func newMemoSender(
+	cfg *bbncfg.BabylonConfig,
+	feeGranter string,
+	feePayer string,
+	// nil if babylon node is connected directly
+	proxy *stakercfg.ProxyConfig,
+	logger *logrus.Logger,
+	clientLogger *zap.Logger,
+) (*memoSender, error) {
+	// same setup as in babylon rpc client
+	encCfg := bbnapp.GetEncodingConfig()
+
+	cosmosConfig := cfg.ToCosmosProviderConfig()
+	provider, err := cosmosConfig.NewProvider(
+		clientLogger,
+		"",
+		true,
+		"babylon",
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	cp := provider.(*cosmos.CosmosProvider)
+	cp.PCfg.KeyDirectory = cfg.KeyDirectory
+	cp.Cdc = cosmos.Codec{
+		InterfaceRegistry: encCfg.InterfaceRegistry,
+		Marshaler:         encCfg.Codec,
+		TxConfig:          encCfg.TxConfig,
+		Amino:             encCfg.Amino,
+	}
+
+	if proxy != nil {
+		err = initProxiedProvider(cp, proxy)
+	} else {
+		err = cp.Init(context.Background())
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	signer, err := cp.GetKeyAddressForKey(cp.PCfg.Key)
+
+	if err != nil {
+		return nil, err
+	}
+
+	fees, err := newFeeAccounts(
+		feeGranter,
+		feePayer,
+		cfg.AccountPrefix,
+		signer,
+		func(addr sdk.AccAddress) (string, error) {
+			record, err := cp.Keybase.KeyByAddress(addr)
+
+			if err != nil {
+				return "", err
+			}
+
+			return record.Name, nil
+		},
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &memoSender{
+		provider:     cp,
+		logger:       logger,
+		blockTimeout: cfg.BlockTimeout,
+		fees:         fees,
+	}, nil
+}
+
+// initProxiedProvider initializes provider the same way as cp.Init does, except
+// that rpc client connects to babylon node through the proxy. Light client
+// provider is not needed to send transactions, so it is not created, as it would
+// connect directly.
+func initProxiedProvider(cp *cosmos.CosmosProvider, proxy *stakercfg.ProxyConfig) error {
+	keybase, err := keyring.New(
+		cp.PCfg.ChainID,
+		cp.PCfg.KeyringBackend,
+		cp.PCfg.KeyDirectory,
+		cp.Input,
+		cp.Cdc.Marshaler,
+		cp.KeyringOptions...,
+	)
+	if err != nil {
+		return err
+	}
+
+	timeout, err := time.ParseDuration(cp.PCfg.Timeout)
+	if err != nil {
+		return err
+	}
+
+	httpClient := &http.Client{
+		Transport: &http.Transport{
+			// same as in cometbft default client, prevents gzip bomb attacks
+			DisableCompression: true,
+			DialContext:        proxy.Dialer().DialContext,
+		},
+		Timeout: timeout,
+	}
+
+	rpcClient, err := rpchttp.NewWithClient(cp.PCfg.RPCAddr, "/websocket", httpClient)
+	if err != nil {
+		return err
+	}
+
+	cp.RPCClient = rpcClient
+	cp.Keybase = keybase
+
+	return nil
+}
+
+func (s *memoSender) stop() error {
+	if !s.provider.RPCClient.IsRunning() {
+		return nil
+	}
+
+	return s.provider.RPCClient.Stop()
+}
+
+// accessKeyWithLock uses the same lock file as babylon rpc client to guard
+// concurrent access to the keyring
+func (s *memoSender) accessKeyWithLock(accessFunc func()) error {
+	lockFilePath := path.Join(s.provider.PCfg.KeyDirectory, "keys.lock")
+	lock := fslock.New(lockFilePath)
+	if err := lock.Lock(); err != nil {
+		return fmt.Errorf("failed to acquire file system lock (%s): %w", lockFilePath, err)
+	}
+
+	accessFunc()
+
+	if err := lock.Unlock(); err != nil {
+		return fmt.Errorf("error unlocking file system lock (%s), please manually delete", lockFilePath)
+	}
+
+	return nil
+}
+
+// reliablySendMsgs sends messages in single transaction with given memo and waits
+// until transaction is included in block
+func (s *memoSender) reliablySendMsgs(
+	ctx context.Context,
+	msgs []sdk.Msg,
+	memo string,
+) (*pv.RelayerTxResponse, error) {
+	if s.fees != nil {
+		return s.reliablySendFeeTx(ctx, msgs, memo)
+	}
+
+	var (
+		rlyResp     *pv.RelayerTxResponse
+		callbackErr error
+		wg          sync.WaitGroup
+	)
+
+	callback := func(rtr *pv.RelayerTxResponse, err error) {
+		rlyResp = rtr
+		callbackErr = err
+		wg.Done()
+	}
+
+	relayerMsgs := bbnclient.ToProviderMsgs(msgs)
+
+	wg.Add(1)
+
+	if err := retry.Do(func() error {
+		var sendMsgErr error
+		krErr := s.accessKeyWithLock(func() {
+			sendMsgErr = s.provider.SendMessagesToMempool(ctx, relayerMsgs, memo, ctx, []func(*pv.RelayerTxResponse, error){callback})
+		})
+		if krErr != nil {
+			return retry.Unrecoverable(krErr)
+		}
+		// transaction was rejected by babylon node before entering mempool
+		sendMsgErr = newSubmitErrorFromRelayer(sendMsgErr, false)
+		if IsTerminalSubmitError(sendMsgErr) {
+			return retry.Unrecoverable(sendMsgErr)
+		}
+		return sendMsgErr
+	}, retry.Context(ctx), RtyAtt, RtyDel, RtyErr, retry.OnRetry(func(n uint, err error) {
+		s.logger.WithFields(logrus.Fields{
+			"attempt":      n + 1,
+			"max_attempts": RtyAttNum,
+			"error":        err,
+		}).Debug("Failed to send transaction to babylon, retrying")
+	})); err != nil {
+		return nil, err
+	}
+
+	// callback is called once transaction is included in block or waiting for
+	// inclusion fails
+	wg.Wait()
+
+	if callbackErr != nil {
+		// relayer does not return response of failed transaction, only its error
+		return nil, newSubmitErrorFromRelayer(callbackErr, true)
+	}
+
+	if rlyResp.Code != 0 {
+		return rlyResp, &BabylonSubmitError{
+			Codespace: rlyResp.Codespace,
+			Code:      rlyResp.Code,
+			RawLog:    fmt.Sprintf("transaction failed with code: %d", rlyResp.Code),
+			Executed:  true,
+			TxHash:    rlyResp.TxHash,
+			Height:    rlyResp.Height,
+		}
+	}
+
+	return rlyResp, nil
+}
+
+// MaxMemoCharacters returns max memo size accepted by babylon chain
+func (bc *BabylonController) MaxMemoCharacters() (uint64, error) {
+	ctx, cancel := getQueryContext(bc.cfg.Timeout)
+	defer cancel()
+
+	clientCtx := client.Context{Client: bc.queryClient.RPCClient}
+	queryClient := authtypes.NewQueryClient(clientCtx)
+
+	response, err := queryClient.Params(ctx, &authtypes.QueryParamsRequest{})
+	if err != nil {
+		return 0, err
+	}
+
+	return response.Params.MaxMemoCharacters, nil
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"sync"
+	"time"
 
 	pv "github.com/cosmos/relayer/v2/relayer/provider"
 
@@ -53,6 +54,8 @@ func newSendUndelegationRequest(
 // It makes sure:
 // - that babylon is ready for either delgetion or undelegation
 // - only one messegae is sent to babylon at a time
+// - if batching is enabled, delegations arriving within batch window are sent in
+// single babylon transaction
 type BabylonMsgSender struct {
 	startOnce sync.Once
 	stopOnce  sync.Once
@@ -61,6 +64,10 @@ type BabylonMsgSender struct {
 
 	cl                          BabylonClient
 	logger                      *logrus.Logger
+	breaker                     *circuitBreaker
+	clock                       utils.Clock
+	batchCfg                    BatchConfig
+	batchStats                  batchStatsRecorder
 	sendDelegationRequestChan   chan *sendDelegationRequest
 	sendUndelegationRequestChan chan *sendUndelegationRequest
 }
@@ -68,11 +75,17 @@ type BabylonMsgSender struct {
 func NewBabylonMsgSender(
 	cl BabylonClient,
 	logger *logrus.Logger,
+	breakerCfg CircuitBreakerConfig,
+	batchCfg BatchConfig,
+	clock utils.Clock,
 ) *BabylonMsgSender {
 	return &BabylonMsgSender{
 		quit:                        make(chan struct{}),
 		cl:                          cl,
 		logger:                      logger,
+		breaker:                     newCircuitBreaker(breakerCfg, clock),
+		clock:                       clock,
+		batchCfg:                    batchCfg,
 		sendDelegationRequestChan:   make(chan *sendDelegationRequest),
 		sendUndelegationRequestChan: make(chan *sendUndelegationRequest),
 	}
@@ -119,49 +132,44 @@ func (b *BabylonMsgSender) isBabylonBtcLcReady(
 
 func (m *BabylonMsgSender) handleSentToBabylon() {
 	defer m.wg.Done()
+
+	// delegations collected while batch window is open, callers waiting for them
+	// are released by quit
+	var batch []*sendDelegationRequest
+	var batchWindow <-chan time.Time
+
 	for {
 		select {
 		case req := <-m.sendDelegationRequestChan:
-			stakingTxHash := req.dg.StakingTransaction.TxHash()
-
-			err := m.isBabylonBtcLcReady(
-				req.requiredInclusionBlockDepth,
-				req.dg,
-			)
-
-			if err != nil {
-				m.logger.WithFields(logrus.Fields{
-					"btcTxHash": stakingTxHash,
-					"err":       err,
-				}).Error("Cannot send delegation request to babylon")
-
-				req.ErrorChan() <- err
+			if !m.batchCfg.Enabled {
+				m.sendDelegations([]*sendDelegationRequest{req})
 				continue
 			}
 
-			txResp, err := m.cl.Delegate(req.dg)
-
-			if err != nil {
-				if errors.Is(err, ErrInvalidBabylonExecution) {
-					m.logger.WithFields(logrus.Fields{
-						"btcTxHash":          stakingTxHash,
-						"babylonTxHash":      txResp.TxHash,
-						"babylonBlockHeight": txResp.Height,
-						"babylonErrorCode":   txResp.Code,
-					}).Error("Invalid delegation data sent to babylon")
-				}
+			batch = append(batch, req)
 
-				m.logger.WithFields(logrus.Fields{
-					"btcTxHash": stakingTxHash,
-					"err":       err,
-				}).Error("Error while sending delegation data to babylon")
+			if uint32(len(batch)) >= m.batchCfg.MaxSize {
+				m.sendDelegations(batch)
+				batch = nil
+				batchWindow = nil
+				continue
+			}
 
-				req.ErrorChan() <- fmt.Errorf("failed to send delegation for tx with hash: %s: %w", stakingTxHash.String(), err)
+			if batchWindow == nil {
+				batchWindow = m.clock.After(m.batchCfg.Window)
 			}
 
-			req.ResultChan() <- txResp
+		case <-batchWindow:
+			m.sendDelegations(batch)
+			batch = nil
+			batchWindow = nil
 
 		case req := <-m.sendUndelegationRequestChan:
+			if err := m.breaker.allow(); err != nil {
+				req.ErrorChan() <- err
+				continue
+			}
+
 			di, err := m.cl.QueryDelegationInfo(req.stakingTxHash)
 
 			if err != nil {
@@ -180,17 +188,16 @@ func (m *BabylonMsgSender) handleSentToBabylon() {
 			}
 
 			txResp, err := m.cl.Undelegate(req.ur)
+			m.recordWriteResult(err)
 
 			if err != nil {
-				if errors.Is(err, ErrInvalidBabylonExecution) {
+				var submitErr *BabylonSubmitError
+				if errors.As(err, &submitErr) {
 					// Additional logging if for some reason we send unbonding request which was
-					// accepted by babylon, but failed execution
-					m.logger.WithFields(logrus.Fields{
-						"btcTxHash":          req.stakingTxHash.String(),
-						"babylonTxHash":      txResp.TxHash,
-						"babylonBlockHeight": txResp.Height,
-						"babylonErrorCode":   txResp.Code,
-					}).Error("Invalid delegation data sent to babylon")
+					// rejected by babylon
+					m.logger.WithFields(submitErrorFields(submitErr)).WithFields(logrus.Fields{
+						"btcTxHash": req.stakingTxHash.String(),
+					}).Error("Invalid undelegation data sent to babylon")
 				}
 
 				m.logger.WithFields(logrus.Fields{
@@ -210,6 +217,35 @@ func (m *BabylonMsgSender) handleSentToBabylon() {
 	}
 }
 
+// recordWriteResult updates circuit breaker with result of sending transaction to
+// babylon. Transaction which was included in block but failed execution, or was
+// rejected due to exhausted fee grant, means babylon is reachable, so it is not
+// counted as failure.
+func (m *BabylonMsgSender) recordWriteResult(err error) {
+	if err == nil || errors.Is(err, ErrInvalidBabylonExecution) || errors.Is(err, ErrFeeGrantExhausted) {
+		m.breaker.onSuccess()
+		return
+	}
+
+	if m.breaker.onFailure() {
+		m.logger.WithFields(logrus.Fields{
+			"err":      err,
+			"cooldown": m.breaker.cfg.Cooldown,
+		}).Error("Too many consecutive failures of sending transactions to babylon. Circuit breaker opened")
+	}
+}
+
+// CircuitStatus returns state of circuit breaker guarding babylon writes
+func (m *BabylonMsgSender) CircuitStatus() *CircuitBreakerStatus {
+	return m.breaker.status()
+}
+
+// CircuitRetryIn returns how long until transactions can be sent to babylon again,
+// zero if circuit breaker allows sending now
+func (m *BabylonMsgSender) CircuitRetryIn() time.Duration {
+	return m.breaker.retryIn()
+}
+
 func (m *BabylonMsgSender) SendDelegation(
 	dg *DelegationData,
 	requiredInclusionBlockDepth uint64,
@@ -1,15 +1,29 @@
 package babylonclient
 
 import (
+	"errors"
 	"fmt"
 
 	bbn "github.com/babylonchain/babylon/types"
 	btcstypes "github.com/babylonchain/babylon/x/btcstaking/types"
 	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
 	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/cometbft/cometbft/crypto/tmhash"
 	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
 )
 
+var (
+	ErrPopBabylonSigInvalid = errors.New("babylon signature over btc public key is invalid")
+	ErrPopBtcSigMalformed   = errors.New("btc signature is malformed")
+	ErrPopBtcSigInvalid     = errors.New("btc signature does not verify over tmhash of babylon signature")
+	ErrPopUnknownType       = errors.New("unknown pop type")
+)
+
+// ecdsaCompactSigSize is the size of compact recoverable ecdsa signature, used
+// by bitcoin message signing
+const ecdsaCompactSigSize = 65
+
 type BabylonBtcPopType int
 
 const (
@@ -80,26 +94,83 @@ func (pop *BabylonPop) ToBtcStakingPop() (*btcstypes.ProofOfPossession, error) {
 	}, nil
 }
 
-func (pop *BabylonPop) ValidatePop(
-	babylonPk *secp256k1.PubKey,
+// VerifyForKeys performs full verification of the pop against provided keys.
+// Verification is done in steps, and error returned indicates which of them failed:
+//  1. babylon key signature over 32 bytes of x-only btc public key - ErrPopBabylonSigInvalid
+//  2. btc signature can be parsed for given pop type - ErrPopBtcSigMalformed
+//  3. btc signature verifies over tmhash(babylon signature) - ErrPopBtcSigInvalid
+//
+// Network is only used by BIP322 pop, as this type of signature is done over
+// btc address.
+func (pop *BabylonPop) VerifyForKeys(
 	btcPk *btcec.PublicKey,
+	babylonPk *secp256k1.PubKey,
 	net *chaincfg.Params,
 ) error {
 	if babylonPk == nil || btcPk == nil || net == nil {
 		return fmt.Errorf("cannot validate pop with nil parameters")
 	}
 
-	bPop, err := pop.ToBtcStakingPop()
-
-	if err != nil {
-		return err
+	// 1. Check babylon signature over btc public key
+	if !babylonPk.VerifySignature(schnorr.SerializePubKey(btcPk), pop.BabylonEcdsaSigOverBtcPk) {
+		return ErrPopBabylonSigInvalid
 	}
 
-	btcPkBabylonFormat := bbn.NewBIP340PubKeyFromBTCPK(btcPk)
+	babylonSigHash := tmhash.Sum(pop.BabylonEcdsaSigOverBtcPk)
+
+	switch pop.popType {
+	case SchnorrType:
+		// 2. Check btc signature is valid bip340 signature
+		btcSig, err := schnorr.ParseSignature(pop.BtcSig)
+
+		if err != nil {
+			return fmt.Errorf("%w: %s", ErrPopBtcSigMalformed, err.Error())
+		}
+
+		// 3. Check btc signature over hash of babylon signature
+		if !btcSig.Verify(babylonSigHash, btcPk) {
+			return ErrPopBtcSigInvalid
+		}
+
+		return nil
+	case EcdsaType, Bip322Type:
+		// 2. Ecdsa signatures are compact signatures over bitcoin signed message,
+		// bip322 signatures are serialized witnesses of arbitrary length
+		if pop.popType == EcdsaType && len(pop.BtcSig) != ecdsaCompactSigSize {
+			return fmt.Errorf(
+				"%w: ecdsa signature must have %d bytes, got %d",
+				ErrPopBtcSigMalformed,
+				ecdsaCompactSigSize,
+				len(pop.BtcSig),
+			)
+		}
+
+		bPop, err := pop.ToBtcStakingPop()
+
+		if err != nil {
+			return err
+		}
+
+		// 3. Babylon signature is already checked, so any failure here is due to
+		// btc signature
+		if err := bPop.Verify(
+			babylonPk,
+			bbn.NewBIP340PubKeyFromBTCPK(btcPk),
+			net,
+		); err != nil {
+			return fmt.Errorf("%w: %s", ErrPopBtcSigInvalid, err.Error())
+		}
+
+		return nil
+	default:
+		return ErrPopUnknownType
+	}
+}
 
-	return bPop.Verify(
-		babylonPk,
-		btcPkBabylonFormat,
-		net,
-	)
+func (pop *BabylonPop) ValidatePop(
+	babylonPk *secp256k1.PubKey,
+	btcPk *btcec.PublicKey,
+	net *chaincfg.Params,
+) error {
+	return pop.VerifyForKeys(btcPk, babylonPk, net)
 }
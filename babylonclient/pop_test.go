@@ -0,0 +1,118 @@
+package babylonclient
+
+import (
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/cometbft/cometbft/crypto/tmhash"
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	"github.com/stretchr/testify/require"
+)
+
+type testPopKeys struct {
+	btcKey     *btcec.PrivateKey
+	babylonKey *secp256k1.PrivKey
+}
+
+func newTestPopKeys(t *testing.T) *testPopKeys {
+	btcKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	return &testPopKeys{
+		btcKey:     btcKey,
+		babylonKey: secp256k1.GenPrivKey(),
+	}
+}
+
+// schnorrPop creates valid bip340 pop of the keys
+func (k *testPopKeys) schnorrPop(t *testing.T) *BabylonPop {
+	babylonSig, err := k.babylonKey.Sign(schnorr.SerializePubKey(k.btcKey.PubKey()))
+	require.NoError(t, err)
+
+	btcSig, err := schnorr.Sign(k.btcKey, tmhash.Sum(babylonSig))
+	require.NoError(t, err)
+
+	pop, err := NewBabylonPop(SchnorrType, babylonSig, btcSig.Serialize())
+	require.NoError(t, err)
+
+	return pop
+}
+
+func (k *testPopKeys) verify(pop *BabylonPop) error {
+	return pop.VerifyForKeys(k.btcKey.PubKey(), k.babylonKey.PubKey().(*secp256k1.PubKey), &chaincfg.RegressionNetParams)
+}
+
+func TestVerifyPopForKeys(t *testing.T) {
+	keys := newTestPopKeys(t)
+
+	require.NoError(t, keys.verify(keys.schnorrPop(t)))
+}
+
+func TestVerifyPopForKeysErrors(t *testing.T) {
+	keys := newTestPopKeys(t)
+	otherKeys := newTestPopKeys(t)
+
+	tests := []struct {
+		name   string
+		tamper func(t *testing.T, pop *BabylonPop)
+		err    error
+	}{
+		{
+			name: "babylon signature over other btc key",
+			tamper: func(t *testing.T, pop *BabylonPop) {
+				pop.BabylonEcdsaSigOverBtcPk = otherKeys.schnorrPop(t).BabylonEcdsaSigOverBtcPk
+			},
+			err: ErrPopBabylonSigInvalid,
+		},
+		{
+			name: "truncated btc signature",
+			tamper: func(_ *testing.T, pop *BabylonPop) {
+				pop.BtcSig = pop.BtcSig[:len(pop.BtcSig)-1]
+			},
+			err: ErrPopBtcSigMalformed,
+		},
+		{
+			name: "ecdsa signature of invalid size",
+			tamper: func(_ *testing.T, pop *BabylonPop) {
+				pop.popType = EcdsaType
+			},
+			err: ErrPopBtcSigMalformed,
+		},
+		{
+			name: "btc signature over other message",
+			tamper: func(t *testing.T, pop *BabylonPop) {
+				btcSig, err := schnorr.Sign(keys.btcKey, tmhash.Sum([]byte("other message")))
+				require.NoError(t, err)
+				pop.BtcSig = btcSig.Serialize()
+			},
+			err: ErrPopBtcSigInvalid,
+		},
+		{
+			name: "btc signature by other key",
+			tamper: func(t *testing.T, pop *BabylonPop) {
+				btcSig, err := schnorr.Sign(otherKeys.btcKey, tmhash.Sum(pop.BabylonEcdsaSigOverBtcPk))
+				require.NoError(t, err)
+				pop.BtcSig = btcSig.Serialize()
+			},
+			err: ErrPopBtcSigInvalid,
+		},
+		{
+			name: "unknown pop type",
+			tamper: func(_ *testing.T, pop *BabylonPop) {
+				pop.popType = EcdsaType + 1
+			},
+			err: ErrPopUnknownType,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pop := keys.schnorrPop(t)
+			tt.tamper(t, pop)
+
+			require.ErrorIs(t, keys.verify(pop), tt.err)
+		})
+	}
+}
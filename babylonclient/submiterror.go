@@ -0,0 +1,203 @@
+package babylonclient
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+
+	errorsmod "cosmossdk.io/errors"
+	"cosmossdk.io/x/feegrant"
+	btclctypes "github.com/babylonchain/babylon/x/btclightclient/types"
+	btcstypes "github.com/babylonchain/babylon/x/btcstaking/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// SubmitErrorClass tells whether message rejected by babylon can be sent again
+type SubmitErrorClass int
+
+const (
+	// SubmitErrorUnknown error code is not in the classification table
+	SubmitErrorUnknown SubmitErrorClass = iota
+	// SubmitErrorRetryable message can succeed when sent again later e.g. once
+	// btc light client of babylon catches up or account sequence is refreshed
+	SubmitErrorRetryable
+	// SubmitErrorTerminal message is invalid and will be rejected again
+	SubmitErrorTerminal
+)
+
+func (c SubmitErrorClass) String() string {
+	switch c {
+	case SubmitErrorRetryable:
+		return "retryable"
+	case SubmitErrorTerminal:
+		return "terminal"
+	default:
+		return "unknown"
+	}
+}
+
+type submitErrorKey struct {
+	codespace string
+	code      uint32
+}
+
+func keyOf(err *errorsmod.Error) submitErrorKey {
+	return submitErrorKey{codespace: err.Codespace(), code: err.ABCICode()}
+}
+
+// submitErrorClasses classifies known (codespace, code) pairs returned by babylon
+// when it rejects delegation or undelegation
+var submitErrorClasses = map[submitErrorKey]SubmitErrorClass{
+	// btc light client of babylon does not know block with staking transaction yet
+	keyOf(btclctypes.ErrHeaderDoesNotExist): SubmitErrorRetryable,
+
+	// transaction was not accepted due to state of the account or node
+	keyOf(sdkerrors.ErrWrongSequence):     SubmitErrorRetryable,
+	keyOf(sdkerrors.ErrInsufficientFee):   SubmitErrorRetryable,
+	keyOf(sdkerrors.ErrInsufficientFunds): SubmitErrorRetryable,
+	keyOf(sdkerrors.ErrOutOfGas):          SubmitErrorRetryable,
+	keyOf(sdkerrors.ErrMempoolIsFull):     SubmitErrorRetryable,
+	keyOf(sdkerrors.ErrTxInMempoolCache):  SubmitErrorRetryable,
+	keyOf(feegrant.ErrFeeLimitExceeded):   SubmitErrorRetryable,
+	keyOf(feegrant.ErrFeeLimitExpired):    SubmitErrorRetryable,
+
+	// message itself is invalid
+	keyOf(btcstypes.ErrReusedStakingTx):          SubmitErrorTerminal,
+	keyOf(btcstypes.ErrInvalidProofOfPossession): SubmitErrorTerminal,
+	keyOf(btcstypes.ErrInvalidStakingTx):         SubmitErrorTerminal,
+	keyOf(btcstypes.ErrInvalidSlashingTx):        SubmitErrorTerminal,
+	keyOf(btcstypes.ErrInvalidUnbondingTx):       SubmitErrorTerminal,
+	keyOf(btcstypes.ErrInvalidCovenantPK):        SubmitErrorTerminal,
+	keyOf(btcstypes.ErrFpNotFound):               SubmitErrorTerminal,
+	keyOf(btcstypes.ErrFpAlreadySlashed):         SubmitErrorTerminal,
+	keyOf(btcstypes.ErrBTCDelegationNotFound):    SubmitErrorTerminal,
+	keyOf(sdkerrors.ErrTxTooLarge):               SubmitErrorTerminal,
+	keyOf(sdkerrors.ErrMemoTooLarge):             SubmitErrorTerminal,
+	keyOf(sdkerrors.ErrUnauthorized):             SubmitErrorTerminal,
+	keyOf(sdkerrors.ErrInvalidAddress):           SubmitErrorTerminal,
+}
+
+// ClassifySubmitError returns class of babylon error with given codespace and code
+func ClassifySubmitError(codespace string, code uint32) SubmitErrorClass {
+	return submitErrorClasses[submitErrorKey{codespace: codespace, code: code}]
+}
+
+// BabylonSubmitError is returned when babylon rejects transaction sent by staker,
+// either before accepting it to mempool or when executing it in block. It keeps
+// raw ABCI error, so that failures with codes unknown to staker can still be
+// diagnosed.
+type BabylonSubmitError struct {
+	Codespace string
+	Code      uint32
+	// log of the failure returned by babylon node, or description of the error
+	// if node did not return the log
+	RawLog string
+	// Executed is true if transaction was included in block and failed
+	// execution, false if it was rejected before entering mempool
+	Executed bool
+	// empty if it is not known
+	TxHash string
+	// height of babylon block which included failed transaction, 0 if it is not
+	// known
+	Height int64
+}
+
+func (e *BabylonSubmitError) Error() string {
+	return fmt.Sprintf(
+		"%s: codespace: %s, code: %d, log: %s",
+		ErrInvalidBabylonExecution, e.Codespace, e.Code, e.RawLog,
+	)
+}
+
+// Unwrap allows matching submit errors with ErrInvalidBabylonExecution, as they
+// mean babylon was reachable, but rejected the transaction
+func (e *BabylonSubmitError) Unwrap() error { return ErrInvalidBabylonExecution }
+
+// Class returns class of the error from the classification table
+func (e *BabylonSubmitError) Class() SubmitErrorClass {
+	return ClassifySubmitError(e.Codespace, e.Code)
+}
+
+// Terminal returns true if sending the same message again cannot succeed.
+// Unknown errors of transactions executed in block are treated as terminal, as
+// babylon already charged fees for them and would most likely fail them again,
+// while unknown errors of transactions rejected before inclusion are retried.
+func (e *BabylonSubmitError) Terminal() bool {
+	switch e.Class() {
+	case SubmitErrorTerminal:
+		return true
+	case SubmitErrorRetryable:
+		return false
+	default:
+		return e.Executed
+	}
+}
+
+// relayer reports errors not registered in the process in this format
+var relayerTxErrorRegex = regexp.MustCompile(`codespace: (\S*), code: (\d+), log: (?s)(.*)$`)
+
+// newSubmitErrorFromRelayer converts error returned by relayer, when transaction
+// was rejected or failed execution, to BabylonSubmitError. Other errors, e.g
+// timeouts of waiting for inclusion, are returned unchanged.
+func newSubmitErrorFromRelayer(err error, executed bool) error {
+	if err == nil {
+		return nil
+	}
+
+	var abciErr *errorsmod.Error
+
+	if errors.As(err, &abciErr) {
+		return &BabylonSubmitError{
+			Codespace: abciErr.Codespace(),
+			Code:      abciErr.ABCICode(),
+			// relayer does not return log for errors registered in the process
+			RawLog:   err.Error(),
+			Executed: executed,
+		}
+	}
+
+	match := relayerTxErrorRegex.FindStringSubmatch(err.Error())
+
+	if match == nil {
+		return err
+	}
+
+	code, parseErr := strconv.ParseUint(match[2], 10, 32)
+
+	if parseErr != nil {
+		return err
+	}
+
+	return &BabylonSubmitError{
+		Codespace: match[1],
+		Code:      uint32(code),
+		RawLog:    match[3],
+		Executed:  executed,
+	}
+}
+
+// submitErrorFields returns log fields describing submit error
+func submitErrorFields(e *BabylonSubmitError) logrus.Fields {
+	return logrus.Fields{
+		"babylonTxHash":      e.TxHash,
+		"babylonBlockHeight": e.Height,
+		"babylonCodespace":   e.Codespace,
+		"babylonErrorCode":   e.Code,
+		"babylonRawLog":      e.RawLog,
+		"errorClass":         e.Class().String(),
+	}
+}
+
+// IsTerminalSubmitError returns true if err means that babylon will reject the
+// same message again
+func IsTerminalSubmitError(err error) bool {
+	var submitErr *BabylonSubmitError
+
+	if errors.As(err, &submitErr) {
+		return submitErr.Terminal()
+	}
+
+	return errors.Is(err, ErrInvalidBabylonExecution)
+}
@@ -0,0 +1,95 @@
+package babylonclient
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	btcstypes "github.com/babylonchain/babylon/x/btcstaking/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewSubmitErrorFromRelayer(t *testing.T) {
+	// error registered in the process is returned by relayer wrapped
+	registered := fmt.Errorf("failed to send tx: %w", btcstypes.ErrReusedStakingTx.Wrap("already delegated"))
+
+	err := newSubmitErrorFromRelayer(registered, true)
+
+	var submitErr *BabylonSubmitError
+	require.True(t, errors.As(err, &submitErr))
+	require.Equal(t, btcstypes.ModuleName, submitErr.Codespace)
+	require.Equal(t, btcstypes.ErrReusedStakingTx.ABCICode(), submitErr.Code)
+	require.True(t, submitErr.Executed)
+	require.Equal(t, SubmitErrorTerminal, submitErr.Class())
+	require.True(t, submitErr.Terminal())
+	require.ErrorIs(t, err, ErrInvalidBabylonExecution)
+
+	// unregistered error is reported by relayer only as formatted string
+	unregistered := errors.New("transaction failed with code: codespace: somemodule, code: 42, log: something\nwent wrong")
+
+	err = newSubmitErrorFromRelayer(unregistered, false)
+
+	require.True(t, errors.As(err, &submitErr))
+	require.Equal(t, "somemodule", submitErr.Codespace)
+	require.Equal(t, uint32(42), submitErr.Code)
+	require.Equal(t, "something\nwent wrong", submitErr.RawLog)
+	require.Equal(t, SubmitErrorUnknown, submitErr.Class())
+
+	// other errors are returned unchanged
+	timeout := errors.New("timed out waiting for tx to be included in a block")
+	require.Equal(t, timeout, newSubmitErrorFromRelayer(timeout, false))
+	require.NoError(t, newSubmitErrorFromRelayer(nil, false))
+}
+
+func TestIsTerminalSubmitError(t *testing.T) {
+	testCases := []struct {
+		name     string
+		err      error
+		terminal bool
+	}{
+		{
+			name: "terminal code",
+			err: &BabylonSubmitError{
+				Codespace: btcstypes.ModuleName,
+				Code:      btcstypes.ErrInvalidStakingTx.ABCICode(),
+			},
+			terminal: true,
+		},
+		{
+			name: "retryable code executed in block",
+			err: &BabylonSubmitError{
+				Codespace: sdkerrors.ErrOutOfGas.Codespace(),
+				Code:      sdkerrors.ErrOutOfGas.ABCICode(),
+				Executed:  true,
+			},
+			terminal: false,
+		},
+		{
+			name:     "unknown code rejected before inclusion",
+			err:      &BabylonSubmitError{Codespace: "somemodule", Code: 42},
+			terminal: false,
+		},
+		{
+			name:     "unknown code executed in block",
+			err:      fmt.Errorf("wrapped: %w", &BabylonSubmitError{Codespace: "somemodule", Code: 42, Executed: true}),
+			terminal: true,
+		},
+		{
+			name:     "untyped execution error",
+			err:      ErrInvalidBabylonExecution,
+			terminal: true,
+		},
+		{
+			name:     "other error",
+			err:      errors.New("connection refused"),
+			terminal: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.terminal, IsTerminalSubmitError(tc.err))
+		})
+	}
+}
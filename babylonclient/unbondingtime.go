@@ -0,0 +1,24 @@
+package babylonclient
+
+import (
+	"fmt"
+	"math"
+)
+
+// minUnbondingTime returns minimal unbonding time accepted by babylon, which must
+// be larger than both checkpoint finalization timeout and min unbonding time from
+// btc staking params. Babylon versions which do not define min unbonding time
+// param return zero, in which case only finalization timeout is used.
+func minUnbondingTime(checkpointFinalizationTimeout uint64, paramsMinUnbondingTime uint16) (uint16, error) {
+	if checkpointFinalizationTimeout > math.MaxUint16 {
+		return 0, fmt.Errorf("checkpoint finalization timeout is bigger than uint16: %w", ErrInvalidValueReceivedFromBabylonNode)
+	}
+
+	finalizationTimeout := uint16(checkpointFinalizationTimeout)
+
+	if paramsMinUnbondingTime > finalizationTimeout {
+		return paramsMinUnbondingTime, nil
+	}
+
+	return finalizationTimeout, nil
+}
@@ -0,0 +1,26 @@
+package babylonclient
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMinUnbondingTime(t *testing.T) {
+	// param not defined by babylon, finalization timeout is used
+	unbondingTime, err := minUnbondingTime(100, 0)
+	require.NoError(t, err)
+	require.Equal(t, uint16(100), unbondingTime)
+
+	unbondingTime, err = minUnbondingTime(100, 200)
+	require.NoError(t, err)
+	require.Equal(t, uint16(200), unbondingTime)
+
+	unbondingTime, err = minUnbondingTime(math.MaxUint16, 200)
+	require.NoError(t, err)
+	require.Equal(t, uint16(math.MaxUint16), unbondingTime)
+
+	_, err = minUnbondingTime(math.MaxUint16+1, 200)
+	require.ErrorIs(t, err, ErrInvalidValueReceivedFromBabylonNode)
+}
@@ -1,14 +1,23 @@
 package main
 
 import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path"
 
 	babylonApp "github.com/babylonchain/babylon/app"
+	"github.com/babylonchain/btc-staker/babylonclient"
+	"github.com/babylonchain/btc-staker/staker"
 	"github.com/babylonchain/btc-staker/stakercfg"
+	dc "github.com/babylonchain/btc-staker/stakerservice/client"
+	"github.com/babylonchain/btc-staker/utils"
 	"github.com/cosmos/cosmos-sdk/crypto/hd"
 	"github.com/cosmos/cosmos-sdk/crypto/keyring"
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
 	"github.com/cosmos/go-bip39"
 	"github.com/jessevdk/go-flags"
 	"github.com/urfave/cli"
@@ -23,6 +32,15 @@ var adminCommands = []cli.Command{
 		Subcommands: []cli.Command{
 			dumpCfgCommand,
 			createCosmosKeyringCommand,
+			verifyPopCommand,
+			recoverDbCommand,
+			migrateDbCommand,
+			checkDbIntegrityCommand,
+			repairStakingOutputCommand,
+			retryUnbondingSignaturesCommand,
+			abortUnbondingCommand,
+			migrateDataDirCommand,
+			quickstartCommand,
 		},
 	},
 }
@@ -126,7 +144,7 @@ func createKey(name string, kr keyring.Keyring) (*keyring.Record, error) {
 	return record, nil
 }
 
-func createKeyRing(c *cli.Context) error {
+func newKeyring(chainId, backend, keyDir string) (keyring.Keyring, error) {
 	keyringOptions := []keyring.Option{}
 	keyringOptions = append(keyringOptions, func(options *keyring.Options) {
 		options.SupportedAlgos = keyring.SigningAlgoList{hd.Secp256k1}
@@ -135,18 +153,22 @@ func createKeyRing(c *cli.Context) error {
 
 	app := babylonApp.NewTmpBabylonApp()
 
-	chainId := c.String(chainIdFlag)
-	backend := c.String(keyringBackendFlag)
-	keyName := c.String(keyNameFlag)
-	keyDir := c.String(keyringDir)
-
-	kb, err := keyring.New(
+	return keyring.New(
 		chainId,
 		backend,
 		keyDir,
 		nil,
 		app.AppCodec(),
 		keyringOptions...)
+}
+
+func createKeyRing(c *cli.Context) error {
+	chainId := c.String(chainIdFlag)
+	backend := c.String(keyringBackendFlag)
+	keyName := c.String(keyNameFlag)
+	keyDir := c.String(keyringDir)
+
+	kb, err := newKeyring(chainId, backend, keyDir)
 
 	if err != nil {
 		return err
@@ -201,3 +223,260 @@ var createCosmosKeyringCommand = cli.Command{
 	},
 	Action: createKeyRing,
 }
+
+const (
+	popFileFlag     = "pop-file"
+	popTypeFlag     = "pop-type"
+	babylonSigFlag  = "babylon-sig"
+	btcSigFlag      = "btc-sig"
+	stakerBtcPkFlag = "staker-btc-pk"
+	babylonPkFlag   = "babylon-pk"
+)
+
+const (
+	popStepDecode    = "decode_input"
+	popStepBabylon   = "babylon_sig_over_btc_pk"
+	popStepBtcFormat = "btc_sig_format"
+	popStepBtcVerify = "btc_sig_over_babylon_sig_hash"
+)
+
+// popFileData is the format of json file accepted by verify-pop command. All
+// byte fields are hex encoded.
+type popFileData struct {
+	PopType    int    `json:"pop_type"`
+	BabylonSig string `json:"babylon_sig"`
+	BtcSig     string `json:"btc_sig"`
+	BtcPk      string `json:"staker_btc_pk"`
+	BabylonPk  string `json:"babylon_pk"`
+}
+
+type verifyPopResponse struct {
+	Valid      bool   `json:"valid"`
+	FailedStep string `json:"failed_step,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+var verifyPopCommand = cli.Command{
+	Name:      "verify-pop",
+	ShortName: "vp",
+	Usage: "Verify proof of possession of staker btc and babylon keys, and print which verification step failed." +
+		" Pop can be provided either by flags or by json file.",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  popFileFlag,
+			Usage: "Path to json file with pop data. If set, other pop flags are ignored",
+		},
+		cli.IntFlag{
+			Name:  popTypeFlag,
+			Usage: "Type of btc signature in pop (0 - bip340 schnorr, 1 - bip322, 2 - ecdsa)",
+		},
+		cli.StringFlag{
+			Name:  babylonSigFlag,
+			Usage: "Hex encoded signature of babylon key over x-only staker btc public key",
+		},
+		cli.StringFlag{
+			Name:  btcSigFlag,
+			Usage: "Hex encoded signature of staker btc key over tmhash of babylon signature",
+		},
+		cli.StringFlag{
+			Name:  stakerBtcPkFlag,
+			Usage: "Hex encoded x-only staker btc public key",
+		},
+		cli.StringFlag{
+			Name:  babylonPkFlag,
+			Usage: "Hex encoded compressed staker babylon public key",
+		},
+	},
+	Action: verifyPop,
+}
+
+func readPopData(c *cli.Context) (*popFileData, error) {
+	popFile := c.String(popFileFlag)
+
+	if popFile == "" {
+		return &popFileData{
+			PopType:    c.Int(popTypeFlag),
+			BabylonSig: c.String(babylonSigFlag),
+			BtcSig:     c.String(btcSigFlag),
+			BtcPk:      c.String(stakerBtcPkFlag),
+			BabylonPk:  c.String(babylonPkFlag),
+		}, nil
+	}
+
+	fileBytes, err := os.ReadFile(popFile)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var data popFileData
+
+	if err := json.Unmarshal(fileBytes, &data); err != nil {
+		return nil, fmt.Errorf("invalid pop file: %w", err)
+	}
+
+	return &data, nil
+}
+
+func decodePopData(data *popFileData) (*babylonclient.BabylonPop, *secp256k1.PubKey, error) {
+	popType, err := babylonclient.IntToPopType(data.PopType)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	babylonSig, err := hex.DecodeString(data.BabylonSig)
+
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid babylon signature: %w", err)
+	}
+
+	btcSig, err := hex.DecodeString(data.BtcSig)
+
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid btc signature: %w", err)
+	}
+
+	pop, err := babylonclient.NewBabylonPop(popType, babylonSig, btcSig)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	babylonPkBytes, err := hex.DecodeString(data.BabylonPk)
+
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid babylon public key: %w", err)
+	}
+
+	if len(babylonPkBytes) != secp256k1.PubKeySize {
+		return nil, nil, fmt.Errorf("babylon public key must have %d bytes", secp256k1.PubKeySize)
+	}
+
+	return pop, &secp256k1.PubKey{Key: babylonPkBytes}, nil
+}
+
+func popFailedStep(err error) string {
+	switch {
+	case errors.Is(err, babylonclient.ErrPopBabylonSigInvalid):
+		return popStepBabylon
+	case errors.Is(err, babylonclient.ErrPopBtcSigMalformed):
+		return popStepBtcFormat
+	case errors.Is(err, babylonclient.ErrPopBtcSigInvalid):
+		return popStepBtcVerify
+	default:
+		return popStepDecode
+	}
+}
+
+func verifyPop(c *cli.Context) error {
+	net, err := utils.GetBtcNetworkParams(c.GlobalString(btcNetworkFlag))
+
+	if err != nil {
+		return err
+	}
+
+	data, err := readPopData(c)
+
+	if err != nil {
+		return err
+	}
+
+	failed := func(step string, err error) error {
+		printRespJSON(&verifyPopResponse{
+			Valid:      false,
+			FailedStep: step,
+			Error:      err.Error(),
+		})
+		return cli.NewExitError("pop verification failed", 1)
+	}
+
+	pop, babylonPk, err := decodePopData(data)
+
+	if err != nil {
+		return failed(popStepDecode, err)
+	}
+
+	btcPk, err := staker.ParseSchnorrPk(data.BtcPk)
+
+	if err != nil {
+		return failed(popStepDecode, fmt.Errorf("invalid staker btc public key: %w", err))
+	}
+
+	if err := pop.VerifyForKeys(btcPk, babylonPk, net); err != nil {
+		return failed(popFailedStep(err), err)
+	}
+
+	printRespJSON(&verifyPopResponse{Valid: true})
+
+	return nil
+}
+
+const (
+	dryRunFlag            = "dry-run"
+	rescanStartHeightFlag = "rescan-start-height"
+	targetDbPathFlag      = "target-db-path"
+)
+
+var recoverDbCommand = cli.Command{
+	Name:      "recover-db",
+	ShortName: "rdb",
+	Usage: "Recover tracked staking transactions of given staker address from wallet and Babylon data" +
+		" after loss of staker database. Requires staker daemon to be running. Transactions are written to a new" +
+		" database file, stop the daemon and replace its database with it to use recovered transactions.",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  stakingDaemonAddressFlag,
+			Usage: "Full address of the staker daemon in format tcp:://<host>:<port>",
+			Value: defaultStakingDaemonAddress,
+		},
+		cli.StringFlag{
+			Name:     stakerAddressFlag,
+			Usage:    "BTC address of the staker whose staking transactions should be recovered",
+			Required: true,
+		},
+		cli.StringFlag{
+			Name:  targetDbPathFlag,
+			Usage: "Path on daemon host of the new database file to which transactions are recovered. File must not hold any transactions. Required unless dry run is set",
+		},
+		cli.BoolFlag{
+			Name:  dryRunFlag,
+			Usage: "Only print which staking transactions would be recovered, without writing them to database",
+		},
+		cli.IntFlag{
+			Name:  rescanStartHeightFlag,
+			Usage: "Height from which wallet is rescanned for transactions not found on btc. If not set, daemon estimates it from its config",
+		},
+	},
+	Action: recoverDb,
+}
+
+func recoverDb(ctx *cli.Context) error {
+	daemonAddress := ctx.String(stakingDaemonAddressFlag)
+	client, err := dc.NewStakerServiceJsonRpcClient(daemonAddress)
+	if err != nil {
+		return err
+	}
+
+	var rescanStartHeight *int
+	if ctx.IsSet(rescanStartHeightFlag) {
+		height := ctx.Int(rescanStartHeightFlag)
+		rescanStartHeight = &height
+	}
+
+	result, err := client.RecoverDb(
+		context.Background(),
+		ctx.String(stakerAddressFlag),
+		ctx.String(targetDbPathFlag),
+		ctx.Bool(dryRunFlag),
+		rescanStartHeight,
+	)
+
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(result)
+
+	return nil
+}
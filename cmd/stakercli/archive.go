@@ -0,0 +1,56 @@
+package main
+
+import (
+	"context"
+
+	dc "github.com/babylonchain/btc-staker/stakerservice/client"
+	"github.com/urfave/cli"
+)
+
+const (
+	olderThanFlag     = "older-than"
+	archiveStatesFlag = "states"
+)
+
+var archiveTransactionsCmd = cli.Command{
+	Name:  "archive-transactions",
+	Usage: "Move transactions which reached terminal state long ago out of daemon working set. Archived transactions are listed only with --include-archived",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  stakingDaemonAddressFlag,
+			Usage: "full address of the staker daemon in format tcp:://<host>:<port>",
+			Value: defaultStakingDaemonAddress,
+		},
+		cli.StringFlag{
+			Name:     olderThanFlag,
+			Usage:    "archive only transactions which are in terminal state for longer than given duration e.g 90d, 8w or 720h",
+			Required: true,
+		},
+		cli.StringFlag{
+			Name:  archiveStatesFlag,
+			Usage: "comma separated terminal states of archived transactions, one of spent, conflicted",
+			Value: "spent",
+		},
+	},
+	Action: archiveTransactions,
+}
+
+func archiveTransactions(ctx *cli.Context) error {
+	daemonAddress := ctx.String(stakingDaemonAddressFlag)
+	client, err := dc.NewStakerServiceJsonRpcClient(daemonAddress)
+	if err != nil {
+		return err
+	}
+
+	sctx := context.Background()
+
+	result, err := client.ArchiveTransactions(sctx, ctx.String(olderThanFlag), ctx.String(archiveStatesFlag))
+
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(result)
+
+	return nil
+}
@@ -1,11 +1,21 @@
 package main
 
 import (
+	"bufio"
 	"context"
+	"errors"
+	"fmt"
+	"os"
 	"strconv"
+	"strings"
+	"time"
 
+	"github.com/babylonchain/btc-staker/staker"
 	scfg "github.com/babylonchain/btc-staker/stakercfg"
+	service "github.com/babylonchain/btc-staker/stakerservice"
 	dc "github.com/babylonchain/btc-staker/stakerservice/client"
+	"github.com/babylonchain/btc-staker/utils"
+	"github.com/btcsuite/btcd/btcutil"
 	"github.com/urfave/cli"
 )
 
@@ -17,15 +27,48 @@ var daemonCommands = []cli.Command{
 		Category:  "Daemon commands",
 		Subcommands: []cli.Command{
 			checkDaemonHealthCmd,
+			pauseBroadcastsCmd,
+			resumeBroadcastsCmd,
+			whitelistCmd,
+			stakedValueCapCmd,
 			listOutputsCmd,
+			keysCmd,
+			pendingChangeCmd,
+			consolidateUtxosCmd,
 			babylonFinalityProvidersCmd,
+			listProvidersCmd,
+			providerExposureCmd,
+			statsCmd,
+			stateMachineCmd,
 			getStakeOutputCmd,
 			stakeCmd,
+			stakingRequestStatusCmd,
 			unstakeCmd,
 			stakingDetailsCmd,
+			txDetailsCmd,
+			babylonInfoCmd,
 			listStakingTransactionsCmd,
 			withdrawableTransactionsCmd,
 			unbondCmd,
+			compareExitOptionsCmd,
+			buildDelegationMsgCmd,
+			markDelegationSubmittedCmd,
+			cancelPendingDelegationCmd,
+			setLabelCmd,
+			setNoteCmd,
+			getNoteCmd,
+			searchTransactionsCmd,
+			setAutoRenewCmd,
+			reconcileCmd,
+			pendingOperationsCmd,
+			debugStateCmd,
+			recoveryStatusCmd,
+			stakingParamsCmd,
+			auditLogCmd,
+			exportHistoryCmd,
+			latencyReportCmd,
+			archiveTransactionsCmd,
+			rescanStatusCmd,
 		},
 	},
 }
@@ -39,6 +82,26 @@ const (
 	stakingTransactionHashFlag = "staking-transaction-hash"
 	feeRateFlag                = "fee-rate"
 	stakerPubKeyFlag           = "staker-pubkey"
+	lastReportFlag             = "last-report"
+	asyncFlag                  = "async"
+	requestIdFlag              = "request-id"
+	signerFlag                 = "signer"
+	babylonTxHashFlag          = "babylon-tx-hash"
+	verboseFlag                = "verbose"
+	maxFeeFlag                 = "max-fee"
+	yesFlag                    = "yes"
+	confTargetFlag             = "conf-target"
+	fromSeqFlag                = "from-seq"
+	fpPkFlag                   = "finality-provider-pk"
+	autoSweepFlag              = "auto-sweep"
+	memoFlag                   = "memo"
+	labelFlag                  = "label"
+	requiredDepthFlag          = "required-depth"
+	targetCountFlag            = "target-count"
+	autoRenewFlag              = "auto-renew"
+	disableFlag                = "disable"
+	freshPopFlag               = "fresh-pop"
+	includeArchivedFlag        = "include-archived"
 )
 
 var (
@@ -59,6 +122,32 @@ var checkDaemonHealthCmd = cli.Command{
 	Action: checkHealth,
 }
 
+var pauseBroadcastsCmd = cli.Command{
+	Name:  "pause-broadcasts",
+	Usage: "Stop daemon from sending any transactions to BTC until broadcasts are resumed. Already sent transactions are still tracked",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  stakingDaemonAddressFlag,
+			Usage: "Full address of the staker daemon in format tcp:://<host>:<port>",
+			Value: defaultStakingDaemonAddress,
+		},
+	},
+	Action: pauseBroadcasts,
+}
+
+var resumeBroadcastsCmd = cli.Command{
+	Name:  "resume-broadcasts",
+	Usage: "Allow daemon to send transactions to BTC again after broadcasts were paused",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  stakingDaemonAddressFlag,
+			Usage: "Full address of the staker daemon in format tcp:://<host>:<port>",
+			Value: defaultStakingDaemonAddress,
+		},
+	},
+	Action: resumeBroadcasts,
+}
+
 var listOutputsCmd = cli.Command{
 	Name:      "list-outputs",
 	ShortName: "lo",
@@ -73,6 +162,57 @@ var listOutputsCmd = cli.Command{
 	Action: listOutputs,
 }
 
+var keysCmd = cli.Command{
+	Name:      "keys",
+	ShortName: "k",
+	Usage:     "Show babylon key and btc wallet addresses controlled by the daemon. Only public data is shown, so wallet may stay locked.",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  stakingDaemonAddressFlag,
+			Usage: "Full address of the staker daemon in format tcp:://<host>:<port>",
+			Value: defaultStakingDaemonAddress,
+		},
+	},
+	Action: keys,
+}
+
+var pendingChangeCmd = cli.Command{
+	Name:      "pending-change",
+	ShortName: "pc",
+	Usage:     "Show change of staking transactions which are not yet confirmed on btc.",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  stakingDaemonAddressFlag,
+			Usage: "Full address of the staker daemon in format tcp:://<host>:<port>",
+			Value: defaultStakingDaemonAddress,
+		},
+	},
+	Action: pendingChange,
+}
+
+var consolidateUtxosCmd = cli.Command{
+	Name:      "consolidate-utxos",
+	ShortName: "cu",
+	Usage:     "Spend the smallest utxos of connected wallet into single output, so that stakes are not funded from too many inputs.",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  stakingDaemonAddressFlag,
+			Usage: "Full address of the staker daemon in format tcp:://<host>:<port>",
+			Value: defaultStakingDaemonAddress,
+		},
+		cli.IntFlag{
+			Name:  targetCountFlag,
+			Usage: "The maximum number of utxos left in wallet after consolidation",
+			Value: 1,
+		},
+		cli.StringFlag{
+			Name:  feeRateFlag,
+			Usage: "fee rate to pay for consolidation tx per kb e.g 2000sat or 0.00002btc. Rate without unit is in satoshis",
+		},
+	},
+	Action: consolidateUtxos,
+}
+
 var babylonFinalityProvidersCmd = cli.Command{
 	Name:      "babylon-finality-providers",
 	ShortName: "bfp",
@@ -112,9 +252,9 @@ var getStakeOutputCmd = cli.Command{
 			Usage:    "BTC public key of the staker",
 			Required: true,
 		},
-		cli.Int64Flag{
+		cli.StringFlag{
 			Name:     stakingAmountFlag,
-			Usage:    "Staking amount in satoshis",
+			Usage:    "Staking amount with unit e.g 0.5btc or 50000000sat. Amount without unit is in satoshis",
 			Required: true,
 		},
 		cli.StringSliceFlag{
@@ -146,9 +286,9 @@ var stakeCmd = cli.Command{
 			Usage:    "BTC address of the staker in hex",
 			Required: true,
 		},
-		cli.Int64Flag{
+		cli.StringFlag{
 			Name:     stakingAmountFlag,
-			Usage:    "Staking amount in satoshis",
+			Usage:    "Staking amount with unit e.g 0.5btc or 50000000sat. Amount without unit is in satoshis",
 			Required: true,
 		},
 		cli.StringSliceFlag{
@@ -156,15 +296,66 @@ var stakeCmd = cli.Command{
 			Usage:    "BTC public keys of the finality providers in hex",
 			Required: true,
 		},
-		cli.Int64Flag{
+		cli.StringFlag{
 			Name:     stakingTimeBlocksFlag,
-			Usage:    "Staking time in BTC blocks",
+			Usage:    "Staking time in BTC blocks, or duration with unit e.g 30d or 8w converted to blocks using expected block rate of the daemon",
 			Required: true,
 		},
+		cli.BoolFlag{
+			Name:  asyncFlag,
+			Usage: "Return immediately with request id instead of waiting for staking transaction to be sent",
+		},
+		cli.BoolFlag{
+			Name:  yesFlag,
+			Usage: "Do not ask for confirmation before staking",
+		},
+		cli.IntFlag{
+			Name:  confTargetFlag,
+			Usage: "Number of blocks in which staking transaction should be confirmed, used to estimate its fee. Daemon default is used if not set",
+		},
+		cli.StringFlag{
+			Name:  memoFlag,
+			Usage: "Memo of babylon transaction submitting the delegation. Supports {stakingTxHash} and {stakerAddress} variables. Daemon default is used if not set",
+		},
+		cli.StringFlag{
+			Name:  labelFlag,
+			Usage: "Label used to organize delegations, e.g client or strategy name. Allowed characters are letters, digits and -_.:/",
+		},
+		cli.IntFlag{
+			Name:  requiredDepthFlag,
+			Usage: "Depth on btc chain which staking transaction must reach before delegation is sent to babylon. Must not be lower than depth required by babylon and daemon must have allowdepthoverride option enabled. Babylon depth is used if not set",
+		},
+		cli.BoolFlag{
+			Name:  autoRenewFlag,
+			Usage: "Renew delegation automatically before it expires, by restaking to the same finality providers for the same staking time",
+		},
+		cli.BoolFlag{
+			Name:  freshPopFlag,
+			Usage: "Generate new proof of possession instead of reusing the one cached for staker address",
+		},
 	},
 	Action: stake,
 }
 
+var stakingRequestStatusCmd = cli.Command{
+	Name:      "staking-request-status",
+	ShortName: "srs",
+	Usage:     "Displays status of asynchronous staking request with given id",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  stakingDaemonAddressFlag,
+			Usage: "full address of the staker daemon in format tcp:://<host>:<port>",
+			Value: defaultStakingDaemonAddress,
+		},
+		cli.StringFlag{
+			Name:     requestIdFlag,
+			Usage:    "Id of the staking request returned by asynchronous stake command",
+			Required: true,
+		},
+	},
+	Action: stakingRequestStatus,
+}
+
 var unstakeCmd = cli.Command{
 	Name:      "unstake",
 	ShortName: "ust",
@@ -180,6 +371,14 @@ var unstakeCmd = cli.Command{
 			Usage:    "Hash of original staking transaction in bitcoin hex format",
 			Required: true,
 		},
+		cli.Float64Flag{
+			Name:  maxFeeFlag,
+			Usage: "Maximum fee of spend transaction as a fraction of stake value e.g 0.05. Spend is rejected if fee is higher",
+		},
+		cli.IntFlag{
+			Name:  confTargetFlag,
+			Usage: "Number of blocks in which spend transaction should be confirmed, used to estimate its fee. Daemon default is used if not set",
+		},
 	},
 	Action: unstake,
 }
@@ -199,14 +398,41 @@ var unbondCmd = cli.Command{
 			Usage:    "Hash of original staking transaction in bitcoin hex format",
 			Required: true,
 		},
-		cli.IntFlag{
+		cli.StringFlag{
 			Name:  feeRateFlag,
-			Usage: "fee rate to pay for unbonding tx in sats/kb",
+			Usage: "fee rate used by --dry-run to estimate unbonding fee of delegation not yet sent to babylon, either per vbyte e.g 2sat/vb, or per 1000 vbytes e.g 2000sat or 0.00002btc. Rate without unit is in satoshis per 1000 vbytes. Unbonding tx fee is fixed when delegation is sent to babylon, so the rate is ignored when unbonding",
+		},
+		cli.BoolFlag{
+			Name:  dryRunFlag,
+			Usage: "Only show unbonding amounts and estimated timeline, without unbonding",
+		},
+		cli.BoolFlag{
+			Name:  autoSweepFlag,
+			Usage: "Spend unbonded funds automatically once unbonding timelock expires. Use --auto-sweep=false to disable sweep enabled in daemon config",
 		},
 	},
 	Action: unbond,
 }
 
+var compareExitOptionsCmd = cli.Command{
+	Name:      "compare-exit-options",
+	ShortName: "ceo",
+	Usage:     "Compares fees, amounts received and estimated time of unbonding with waiting until staking timelock expires",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  stakingDaemonAddressFlag,
+			Usage: "full address of the staker daemon in format tcp:://<host>:<port>",
+			Value: defaultStakingDaemonAddress,
+		},
+		cli.StringFlag{
+			Name:     stakingTransactionHashFlag,
+			Usage:    "Hash of original staking transaction in bitcoin hex format",
+			Required: true,
+		},
+	},
+	Action: compareExitOptions,
+}
+
 var stakingDetailsCmd = cli.Command{
 	Name:      "staking-details",
 	ShortName: "sds",
@@ -226,9 +452,138 @@ var stakingDetailsCmd = cli.Command{
 	Action: stakingDetails,
 }
 
+var babylonInfoCmd = cli.Command{
+	Name:      "babylon-info",
+	ShortName: "bi",
+	Usage:     "Displays what babylon knows about delegation of staking transaction, together with its local state",
+	ArgsUsage: "<staking-tx-hash>",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  stakingDaemonAddressFlag,
+			Usage: "full address of the staker daemon in format tcp:://<host>:<port>",
+			Value: defaultStakingDaemonAddress,
+		},
+	},
+	Action: babylonInfo,
+}
+
+var buildDelegationMsgCmd = cli.Command{
+	Name:      "build-delegation-msg",
+	ShortName: "bdm",
+	Usage:     "Build delegation message for staking transaction confirmed on btc, without submitting it to Babylon. Requires daemon running with externaldelegations enabled",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  stakingDaemonAddressFlag,
+			Usage: "full address of the staker daemon in format tcp:://<host>:<port>",
+			Value: defaultStakingDaemonAddress,
+		},
+		cli.StringFlag{
+			Name:     stakingTransactionHashFlag,
+			Usage:    "Hash of original staking transaction in bitcoin hex format",
+			Required: true,
+		},
+		cli.StringFlag{
+			Name:  signerFlag,
+			Usage: "Babylon address which will submit the message. If not provided, address of daemon Babylon key is used",
+		},
+	},
+	Action: buildDelegationMsg,
+}
+
+var markDelegationSubmittedCmd = cli.Command{
+	Name:      "mark-delegation-submitted",
+	ShortName: "mds",
+	Usage:     "Notify daemon that delegation of staking transaction was submitted to Babylon externally",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  stakingDaemonAddressFlag,
+			Usage: "full address of the staker daemon in format tcp:://<host>:<port>",
+			Value: defaultStakingDaemonAddress,
+		},
+		cli.StringFlag{
+			Name:     stakingTransactionHashFlag,
+			Usage:    "Hash of original staking transaction in bitcoin hex format",
+			Required: true,
+		},
+		cli.StringFlag{
+			Name:  babylonTxHashFlag,
+			Usage: "Hash of Babylon transaction which included the delegation",
+		},
+	},
+	Action: markDelegationSubmitted,
+}
+
+var cancelPendingDelegationCmd = cli.Command{
+	Name:      "cancel-pending-delegation",
+	ShortName: "cpd",
+	Usage: "Cancel delegation of staking transaction confirmed on btc, which was not sent to Babylon yet." +
+		" Staked funds can only be withdrawn once staking timelock expires." +
+		" Refused if delegation is being sent to Babylon or was already sent.",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  stakingDaemonAddressFlag,
+			Usage: "full address of the staker daemon in format tcp:://<host>:<port>",
+			Value: defaultStakingDaemonAddress,
+		},
+		cli.StringFlag{
+			Name:     stakingTransactionHashFlag,
+			Usage:    "Hash of original staking transaction in bitcoin hex format",
+			Required: true,
+		},
+	},
+	Action: cancelPendingDelegation,
+}
+
+var setLabelCmd = cli.Command{
+	Name:      "set-label",
+	ShortName: "sl",
+	Usage:     "Assign label to staking transaction, empty label removes current label",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  stakingDaemonAddressFlag,
+			Usage: "full address of the staker daemon in format tcp:://<host>:<port>",
+			Value: defaultStakingDaemonAddress,
+		},
+		cli.StringFlag{
+			Name:     stakingTransactionHashFlag,
+			Usage:    "Hash of original staking transaction in bitcoin hex format",
+			Required: true,
+		},
+		cli.StringFlag{
+			Name:  labelFlag,
+			Usage: "Label of staking transaction. Allowed characters are letters, digits and -_.:/",
+		},
+	},
+	Action: setLabel,
+}
+
+var setAutoRenewCmd = cli.Command{
+	Name:      "set-auto-renew",
+	ShortName: "sar",
+	Usage:     "Enable or disable automatic renewal of delegation before it expires",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  stakingDaemonAddressFlag,
+			Usage: "full address of the staker daemon in format tcp:://<host>:<port>",
+			Value: defaultStakingDaemonAddress,
+		},
+		cli.StringFlag{
+			Name:     stakingTransactionHashFlag,
+			Usage:    "Hash of original staking transaction in bitcoin hex format",
+			Required: true,
+		},
+		cli.BoolFlag{
+			Name:  disableFlag,
+			Usage: "Disable automatic renewal instead of enabling it",
+		},
+	},
+	Action: setAutoRenew,
+}
+
 var listStakingTransactionsCmd = cli.Command{
 	Name:      "list-staking-transactions",
 	ShortName: "lst",
+	Aliases:   []string{"list"},
 	Usage:     "List current staking transactions in db",
 	Flags: []cli.Flag{
 		cli.StringFlag{
@@ -246,6 +601,35 @@ var listStakingTransactionsCmd = cli.Command{
 			Usage: "maximum number of transactions to return",
 			Value: 100,
 		},
+		cli.BoolFlag{
+			Name:  verboseFlag,
+			Usage: "include raw transactions and scripts in the response",
+		},
+		cli.StringFlag{
+			Name:  labelFlag,
+			Usage: "return only transactions with given label",
+		},
+		cli.StringFlag{
+			Name:  stateFlag,
+			Usage: "return only transactions in given state e.g DELEGATION_ACTIVE",
+		},
+		cli.BoolFlag{
+			Name:  includeArchivedFlag,
+			Usage: "include archived transactions",
+		},
+		cli.BoolFlag{
+			Name:  tableFlag,
+			Usage: "print compact table of all transactions after offset instead of json, limit is not used",
+		},
+		cli.BoolFlag{
+			Name:  watchFlag,
+			Usage: "refresh table every interval, highlighting transactions which state changed",
+		},
+		cli.DurationFlag{
+			Name:  watchIntervalFlag,
+			Usage: "interval between refreshes in watch mode",
+			Value: 5 * time.Second,
+		},
 	},
 	Action: listStakingTransactions,
 }
@@ -274,31 +658,215 @@ var withdrawableTransactionsCmd = cli.Command{
 	Action: withdrawableTransactions,
 }
 
-func checkHealth(ctx *cli.Context) error {
-	daemonAddress := ctx.String(stakingDaemonAddressFlag)
-	client, err := dc.NewStakerServiceJsonRpcClient(daemonAddress)
-	if err != nil {
-		return err
-	}
-
-	sctx := context.Background()
+var reconcileCmd = cli.Command{
+	Name:      "reconcile",
+	ShortName: "rec",
+	Usage:     "Compare state of delegations in staker database against Babylon and report discrepancies",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  stakingDaemonAddressFlag,
+			Usage: "full address of the staker daemon in format tcp:://<host>:<port>",
+			Value: defaultStakingDaemonAddress,
+		},
+		cli.BoolFlag{
+			Name:  lastReportFlag,
+			Usage: "Only print report of the last finished reconciliation instead of running a new one",
+		},
+	},
+	Action: reconcile,
+}
 
-	health, err := client.Health(sctx)
+var pendingOperationsCmd = cli.Command{
+	Name:      "pending-operations",
+	ShortName: "po",
+	Usage:     "Show operations in progress: delegations being sent to Babylon or waiting in backlog, confirmation waits and retried operations",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  stakingDaemonAddressFlag,
+			Usage: "full address of the staker daemon in format tcp:://<host>:<port>",
+			Value: defaultStakingDaemonAddress,
+		},
+	},
+	Action: pendingOperations,
+}
 
-	if err != nil {
-		return err
-	}
+var debugStateCmd = cli.Command{
+	Name:      "debug-state",
+	ShortName: "ds",
+	Usage:     "Show internal state of the daemon used to diagnose stalls: channel lengths, running goroutines and the oldest in-flight operations. Requires daemon with debugrpc option enabled",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  stakingDaemonAddressFlag,
+			Usage: "full address of the staker daemon in format tcp:://<host>:<port>",
+			Value: defaultStakingDaemonAddress,
+		},
+	},
+	Action: debugState,
+}
 
-	printRespJSON(health)
+var stakingParamsCmd = cli.Command{
+	Name:      "staking-params",
+	ShortName: "sp",
+	Usage:     "Show current Babylon staking params and estimated fee of staking transaction",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  stakingDaemonAddressFlag,
+			Usage: "full address of the staker daemon in format tcp:://<host>:<port>",
+			Value: defaultStakingDaemonAddress,
+		},
+	},
+	Action: stakingParams,
+}
 
-	return nil
+var recoveryStatusCmd = cli.Command{
+	Name:      "recovery-status",
+	ShortName: "rs",
+	Usage:     "List transactions which status could not be checked during daemon startup and are still being retried",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  stakingDaemonAddressFlag,
+			Usage: "full address of the staker daemon in format tcp:://<host>:<port>",
+			Value: defaultStakingDaemonAddress,
+		},
+	},
+	Action: recoveryStatus,
 }
 
-func listOutputs(ctx *cli.Context) error {
-	daemonAddress := ctx.String(stakingDaemonAddressFlag)
-	client, err := dc.NewStakerServiceJsonRpcClient(daemonAddress)
-	if err != nil {
-		return err
+var auditLogCmd = cli.Command{
+	Name:      "audit-log",
+	ShortName: "al",
+	Usage:     "List fund moving operations performed by the daemon, as recorded in its audit log",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  stakingDaemonAddressFlag,
+			Usage: "full address of the staker daemon in format tcp:://<host>:<port>",
+			Value: defaultStakingDaemonAddress,
+		},
+		cli.IntFlag{
+			Name:  fromSeqFlag,
+			Usage: "sequence number of the first entry to return",
+			Value: 1,
+		},
+		cli.IntFlag{
+			Name:  limitFlag,
+			Usage: "maximum number of entries to return",
+			Value: 100,
+		},
+	},
+	Action: auditLog,
+}
+
+var providerExposureCmd = cli.Command{
+	Name:      "exposure",
+	ShortName: "exp",
+	Usage:     "Show amount staked to each finality provider by active delegations",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  stakingDaemonAddressFlag,
+			Usage: "full address of the staker daemon in format tcp:://<host>:<port>",
+			Value: defaultStakingDaemonAddress,
+		},
+		cli.StringFlag{
+			Name:  fpPkFlag,
+			Usage: "only show exposure to finality provider with given BTC public key",
+		},
+	},
+	Action: providerExposure,
+}
+
+var statsCmd = cli.Command{
+	Name:  "stats",
+	Usage: "Show number of transactions in each state, age of the oldest transaction in each state and amounts locked and withdrawn by staker",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  stakingDaemonAddressFlag,
+			Usage: "full address of the staker daemon in format tcp:://<host>:<port>",
+			Value: defaultStakingDaemonAddress,
+		},
+	},
+	Action: stats,
+}
+
+var stateMachineCmd = cli.Command{
+	Name:  "state-machine",
+	Usage: "Show states of tracked staking transactions and transitions allowed between them",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  stakingDaemonAddressFlag,
+			Usage: "full address of the staker daemon in format tcp:://<host>:<port>",
+			Value: defaultStakingDaemonAddress,
+		},
+	},
+	Action: stateMachine,
+}
+
+var rescanStatusCmd = cli.Command{
+	Name:      "rescan-status",
+	ShortName: "rss",
+	Usage:     "Show wallet rescans started by the daemon to find watched or recovered transactions, which are still in progress",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  stakingDaemonAddressFlag,
+			Usage: "full address of the staker daemon in format tcp:://<host>:<port>",
+			Value: defaultStakingDaemonAddress,
+		},
+	},
+	Action: rescanStatus,
+}
+
+func checkHealth(ctx *cli.Context) error {
+	daemonAddress := ctx.String(stakingDaemonAddressFlag)
+	client, err := dc.NewStakerServiceJsonRpcClient(daemonAddress)
+	if err != nil {
+		return err
+	}
+
+	sctx := context.Background()
+
+	health, err := client.Health(sctx)
+
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(health)
+
+	return nil
+}
+
+func pauseBroadcasts(ctx *cli.Context) error {
+	return setBroadcastEnabled(ctx, false)
+}
+
+func resumeBroadcasts(ctx *cli.Context) error {
+	return setBroadcastEnabled(ctx, true)
+}
+
+func setBroadcastEnabled(ctx *cli.Context, enabled bool) error {
+	daemonAddress := ctx.String(stakingDaemonAddressFlag)
+	client, err := dc.NewStakerServiceJsonRpcClient(daemonAddress)
+	if err != nil {
+		return err
+	}
+
+	sctx := context.Background()
+
+	result, err := client.SetBroadcastEnabled(sctx, enabled)
+
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(result)
+
+	return nil
+}
+
+func listOutputs(ctx *cli.Context) error {
+	daemonAddress := ctx.String(stakingDaemonAddressFlag)
+	client, err := dc.NewStakerServiceJsonRpcClient(daemonAddress)
+	if err != nil {
+		return err
 	}
 
 	sctx := context.Background()
@@ -309,12 +877,521 @@ func listOutputs(ctx *cli.Context) error {
 		return err
 	}
 
-	printRespJSON(outputs)
+	printRespJSON(outputs)
+
+	return nil
+}
+
+func keys(ctx *cli.Context) error {
+	daemonAddress := ctx.String(stakingDaemonAddressFlag)
+	client, err := dc.NewStakerServiceJsonRpcClient(daemonAddress)
+	if err != nil {
+		return err
+	}
+
+	sctx := context.Background()
+
+	keys, err := client.Keys(sctx)
+
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(keys)
+
+	return nil
+}
+
+func pendingChange(ctx *cli.Context) error {
+	daemonAddress := ctx.String(stakingDaemonAddressFlag)
+	client, err := dc.NewStakerServiceJsonRpcClient(daemonAddress)
+	if err != nil {
+		return err
+	}
+
+	sctx := context.Background()
+
+	pending, err := client.PendingChange(sctx)
+
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(pending)
+
+	return nil
+}
+
+func consolidateUtxos(ctx *cli.Context) error {
+	daemonAddress := ctx.String(stakingDaemonAddressFlag)
+	client, err := dc.NewStakerServiceJsonRpcClient(daemonAddress)
+	if err != nil {
+		return err
+	}
+
+	sctx := context.Background()
+
+	var fr *int = nil
+	if ctx.IsSet(feeRateFlag) {
+		feeRate, err := parseAmountFlag(ctx, feeRateFlag)
+		if err != nil {
+			return err
+		}
+
+		if feeRate > 0 {
+			rate := int(feeRate)
+			fr = &rate
+		}
+	}
+
+	result, err := client.ConsolidateUtxos(sctx, ctx.Int(targetCountFlag), fr)
+
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(result)
+
+	return nil
+}
+
+func babylonFinalityProviders(ctx *cli.Context) error {
+	daemonAddress := ctx.String(stakingDaemonAddressFlag)
+	client, err := dc.NewStakerServiceJsonRpcClient(daemonAddress)
+	if err != nil {
+		return err
+	}
+
+	sctx := context.Background()
+
+	offset := ctx.Int(offsetFlag)
+
+	if offset < 0 {
+		return cli.NewExitError("Offset must be non-negative", 1)
+	}
+
+	limit := ctx.Int(limitFlag)
+
+	if limit < 0 {
+		return cli.NewExitError("Limit must be non-negative", 1)
+	}
+
+	finalityProviders, err := client.BabylonFinalityProviders(sctx, &offset, &limit)
+
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(finalityProviders)
+
+	return nil
+}
+
+func getStakeOutput(ctx *cli.Context) error {
+	daemonAddress := ctx.String(stakingDaemonAddressFlag)
+
+	client, err := dc.NewStakerServiceJsonRpcClient(daemonAddress)
+	if err != nil {
+		return err
+	}
+	sctx := context.Background()
+
+	stakerKey := ctx.String(stakerPubKeyFlag)
+	stakingTimeBlocks := ctx.Int64(stakingTimeBlocksFlag)
+
+	stakingAmount, err := parseAmountFlag(ctx, stakingAmountFlag)
+	if err != nil {
+		return err
+	}
+
+	fpPks, err := normalizeFpPks(ctx.StringSlice(fpPksFlag))
+	if err != nil {
+		return err
+	}
+
+	results, err := client.GetStakeOutput(sctx, stakerKey, int64(stakingAmount), fpPks, stakingTimeBlocks)
+	if err != nil {
+		return err
+	}
+	printRespJSON(results)
+
+	return nil
+}
+
+// parseAmountFlag parses flag value with btc amount, with optional unit suffix
+func parseAmountFlag(ctx *cli.Context, flag string) (btcutil.Amount, error) {
+	amount, err := utils.ParseBtcAmount(ctx.String(flag))
+
+	if err != nil {
+		return 0, cli.NewExitError(fmt.Sprintf("Invalid %s: %s", flag, err), 1)
+	}
+
+	return amount, nil
+}
+
+// normalizeFpPks parses finality provider keys in any supported encoding and
+// returns them in canonical x-only form
+func normalizeFpPks(fpPks []string) ([]string, error) {
+	normalized := make([]string, len(fpPks))
+
+	for i, fpPk := range fpPks {
+		pk, err := staker.ParsePublicKey(fpPk)
+
+		if err != nil {
+			return nil, cli.NewExitError(fmt.Sprintf("Invalid finality provider public key %s: %s", fpPk, err), 1)
+		}
+
+		normalized[i] = staker.XOnlyKeyHex(pk)
+	}
+
+	return normalized, nil
+}
+
+// askForConfirmation asks user to confirm the action on stdin, only explicit yes
+// is treated as confirmation
+func askForConfirmation(question string) (bool, error) {
+	fmt.Fprintf(os.Stderr, "%s [y/N]: ", question)
+
+	answer, err := bufio.NewReader(os.Stdin).ReadString('\n')
+
+	if err != nil {
+		return false, err
+	}
+
+	answer = strings.ToLower(strings.TrimSpace(answer))
+
+	return answer == "y" || answer == "yes", nil
+}
+
+// confirmLongLock asks user to confirm staking time above maximum staking time of
+// the daemon, and returns whether long lock of funds is acknowledged. Staking
+// time is acknowledged without asking if --yes is set.
+func confirmLongLock(ctx *cli.Context, stakingTimeBlocks int64, params *service.StakingParamsResponse) (bool, error) {
+	maxStakingTime, err := strconv.ParseUint(params.MaxStakingTimeBlocks, 10, 32)
+	if err != nil {
+		return false, err
+	}
+
+	if !staker.ExceedsStakingTimeLimit(uint64(stakingTimeBlocks), uint32(maxStakingTime)) {
+		return false, nil
+	}
+
+	if ctx.Bool(yesFlag) {
+		return true, nil
+	}
+
+	blocksPerHour, err := strconv.ParseUint(params.ExpectedBlocksPerHour, 10, 32)
+	if err != nil {
+		return false, err
+	}
+
+	confirmed, err := askForConfirmation(fmt.Sprintf(
+		"Staking time %d blocks exceeds maximum staking time %d blocks, this locks funds for %s, continue?",
+		stakingTimeBlocks,
+		maxStakingTime,
+		staker.LockDays(staker.BlocksToDuration(uint64(stakingTimeBlocks), uint32(blocksPerHour))),
+	))
+	if err != nil {
+		return false, err
+	}
+
+	if !confirmed {
+		return false, cli.NewExitError("Staking aborted", 1)
+	}
+
+	return true, nil
+}
+
+// printStakingSummary prints summary of staking request to stderr, so that
+// stdout only contains json response
+func printStakingSummary(
+	stakerAddress string,
+	stakingAmount btcutil.Amount,
+	fpPks []string,
+	stakingTimeBlocks int64,
+	params *service.StakingParamsResponse,
+	funding *service.StakeFundingEstimateResponse,
+) {
+	fmt.Fprintln(os.Stderr, "Staking summary:")
+	fmt.Fprintf(os.Stderr, "  Staker address:        %s\n", stakerAddress)
+	fmt.Fprintf(os.Stderr, "  Amount:                %s (%d sat)\n", stakingAmount, int64(stakingAmount))
+	fmt.Fprintf(os.Stderr, "  Finality providers:    %s\n", strings.Join(fpPks, ", "))
+	fmt.Fprintf(os.Stderr, "  Staking time:          %d blocks (minimum: %s)\n", stakingTimeBlocks, params.MinStakingTimeBlocks)
+	fmt.Fprintf(os.Stderr, "  Estimated fee:         %s sat (fee rate: %s sat/kb)\n", funding.FeeSat, funding.FeeRateSatPerKb)
+	fmt.Fprintf(os.Stderr, "  Wallet inputs:         %d (change: %s sat)\n", len(funding.Inputs), funding.ChangeSat)
+}
+
+// parseStakingTimeFlag converts staking time given by the user to blocks, using
+// the same block rate and minimum staking time as the daemon
+func parseStakingTimeFlag(stakingTime string, params *service.StakingParamsResponse) (int64, error) {
+	minStakingTime, err := strconv.ParseUint(params.MinStakingTimeBlocks, 10, 32)
+	if err != nil {
+		return 0, err
+	}
+
+	blocksPerHour, err := strconv.ParseUint(params.ExpectedBlocksPerHour, 10, 32)
+	if err != nil {
+		return 0, err
+	}
+
+	blocks, err := staker.StakingTimeToBlocks(stakingTime, uint32(blocksPerHour))
+	if err != nil {
+		return 0, err
+	}
+
+	stakingTimeBlocks, err := staker.ValidateStakingTime(blocks, uint32(minStakingTime))
+	if err != nil {
+		return 0, err
+	}
+
+	return int64(stakingTimeBlocks), nil
+}
+
+func stake(ctx *cli.Context) error {
+	stakerAddress := ctx.String(stakerAddressFlag)
+	fpPks := ctx.StringSlice(fpPksFlag)
+	stakingTime := ctx.String(stakingTimeBlocksFlag)
+
+	stakingAmount, err := parseAmountFlag(ctx, stakingAmountFlag)
+	if err != nil {
+		return err
+	}
+
+	if stakingAmount <= 0 {
+		return cli.NewExitError("Staking amount must be positive", 1)
+	}
+
+	// validate keys locally, before contacting daemon
+	fpPks, err = normalizeFpPks(fpPks)
+	if err != nil {
+		return err
+	}
+
+	daemonAddress := ctx.String(stakingDaemonAddressFlag)
+	client, err := dc.NewStakerServiceJsonRpcClient(daemonAddress)
+	if err != nil {
+		return err
+	}
+
+	sctx := context.Background()
+
+	params, err := client.StakingParams(sctx)
+	if err != nil {
+		return err
+	}
+
+	stakingTimeBlocks, err := parseStakingTimeFlag(stakingTime, params)
+	if err != nil {
+		return cli.NewExitError(fmt.Sprintf("Invalid %s: %s", stakingTimeBlocksFlag, err), 1)
+	}
+
+	confTarget := confTargetFromFlag(ctx)
+
+	// fails the same way as staking would, if wallet cannot fund the stake
+	funding, err := client.EstimateStakeFunding(sctx, stakerAddress, int64(stakingAmount), confTarget)
+	if err != nil {
+		return err
+	}
+
+	printStakingSummary(stakerAddress, stakingAmount, fpPks, stakingTimeBlocks, params, funding)
+
+	acknowledgeLongLock, err := confirmLongLock(ctx, stakingTimeBlocks, params)
+	if err != nil {
+		return err
+	}
+
+	if !ctx.Bool(yesFlag) {
+		confirmed, err := askForConfirmation("Proceed with staking?")
+		if err != nil {
+			return err
+		}
+
+		if !confirmed {
+			return cli.NewExitError("Staking aborted", 1)
+		}
+	}
+
+	var memo *string
+	if ctx.IsSet(memoFlag) {
+		m := ctx.String(memoFlag)
+		memo = &m
+	}
+
+	var label *string
+	if ctx.IsSet(labelFlag) {
+		l := ctx.String(labelFlag)
+		label = &l
+	}
+
+	var requiredDepth *int
+	if ctx.IsSet(requiredDepthFlag) {
+		d := ctx.Int(requiredDepthFlag)
+		requiredDepth = &d
+	}
+
+	if ctx.Bool(asyncFlag) {
+		results, err := client.StakeAsync(sctx, stakerAddress, int64(stakingAmount), fpPks, stakingTimeBlocks, confTarget, memo, label, requiredDepth, acknowledgeLongLock, ctx.Bool(autoRenewFlag), ctx.Bool(freshPopFlag))
+		if err != nil {
+			return err
+		}
+
+		printRespJSON(results)
+
+		return nil
+	}
+
+	results, err := client.Stake(sctx, stakerAddress, int64(stakingAmount), fpPks, stakingTimeBlocks, confTarget, memo, label, requiredDepth, acknowledgeLongLock, ctx.Bool(autoRenewFlag), ctx.Bool(freshPopFlag))
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(results)
+
+	return nil
+}
+
+// confTargetFromFlag returns confirmation target set by the user, or nil if daemon
+// default should be used
+func confTargetFromFlag(ctx *cli.Context) *int {
+	if !ctx.IsSet(confTargetFlag) {
+		return nil
+	}
+
+	confTarget := ctx.Int(confTargetFlag)
+	return &confTarget
+}
+
+func stakingRequestStatus(ctx *cli.Context) error {
+	daemonAddress := ctx.String(stakingDaemonAddressFlag)
+	client, err := dc.NewStakerServiceJsonRpcClient(daemonAddress)
+	if err != nil {
+		return err
+	}
+
+	sctx := context.Background()
+
+	result, err := client.StakingRequestStatus(sctx, ctx.String(requestIdFlag))
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(result)
+
+	return nil
+}
+
+func unstake(ctx *cli.Context) error {
+	daemonAddress := ctx.String(stakingDaemonAddressFlag)
+	client, err := dc.NewStakerServiceJsonRpcClient(daemonAddress)
+	if err != nil {
+		return err
+	}
+
+	sctx := context.Background()
+
+	stakingTransactionHash := ctx.String(stakingTransactionHashFlag)
+
+	var maxFee *float64
+	if ctx.IsSet(maxFeeFlag) {
+		fraction := ctx.Float64(maxFeeFlag)
+		maxFee = &fraction
+	}
+
+	result, err := client.SpendStakingTransaction(sctx, stakingTransactionHash, maxFee, confTargetFromFlag(ctx))
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(result)
+
+	return nil
+}
+
+func compareExitOptions(ctx *cli.Context) error {
+	daemonAddress := ctx.String(stakingDaemonAddressFlag)
+	client, err := dc.NewStakerServiceJsonRpcClient(daemonAddress)
+	if err != nil {
+		return err
+	}
+
+	sctx := context.Background()
+
+	stakingTransactionHash := ctx.String(stakingTransactionHashFlag)
+
+	result, err := client.CompareExitOptions(sctx, stakingTransactionHash)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(result)
+
+	return nil
+}
+
+func unbond(ctx *cli.Context) error {
+	daemonAddress := ctx.String(stakingDaemonAddressFlag)
+	client, err := dc.NewStakerServiceJsonRpcClient(daemonAddress)
+	if err != nil {
+		return err
+	}
+
+	sctx := context.Background()
+
+	stakingTransactionHash := ctx.String(stakingTransactionHashFlag)
+
+	var fr *int = nil
+	var frSatPerVbyte *float64 = nil
+	if ctx.IsSet(feeRateFlag) {
+		satPerVbyte, perVbyte, err := utils.ParseFeeRateSatPerVbyte(ctx.String(feeRateFlag))
+		if err != nil {
+			return cli.NewExitError(fmt.Sprintf("Invalid %s: %s", feeRateFlag, err), 1)
+		}
+
+		if perVbyte {
+			if satPerVbyte > 0 {
+				frSatPerVbyte = &satPerVbyte
+			}
+		} else {
+			feeRate, err := parseAmountFlag(ctx, feeRateFlag)
+			if err != nil {
+				return err
+			}
+
+			if feeRate > 0 {
+				rate := int(feeRate)
+				fr = &rate
+			}
+		}
+	}
+
+	if ctx.Bool(dryRunFlag) {
+		preview, err := client.PreviewUnbonding(sctx, stakingTransactionHash, fr, frSatPerVbyte)
+		if err != nil {
+			return err
+		}
+
+		printRespJSON(preview)
+
+		return nil
+	}
+
+	var autoSweep *bool = nil
+	if ctx.IsSet(autoSweepFlag) {
+		sweep := ctx.Bool(autoSweepFlag)
+		autoSweep = &sweep
+	}
+
+	result, err := client.UnbondStaking(sctx, stakingTransactionHash, fr, frSatPerVbyte, autoSweep)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(result)
 
 	return nil
 }
 
-func babylonFinalityProviders(ctx *cli.Context) error {
+func stakingDetails(ctx *cli.Context) error {
 	daemonAddress := ctx.String(stakingDaemonAddressFlag)
 	client, err := dc.NewStakerServiceJsonRpcClient(daemonAddress)
 	if err != nil {
@@ -323,53 +1400,66 @@ func babylonFinalityProviders(ctx *cli.Context) error {
 
 	sctx := context.Background()
 
-	offset := ctx.Int(offsetFlag)
+	stakingTransactionHash := ctx.String(stakingTransactionHashFlag)
 
-	if offset < 0 {
-		return cli.NewExitError("Offset must be non-negative", 1)
+	result, err := client.StakingDetails(sctx, stakingTransactionHash)
+	if err != nil {
+		return err
 	}
 
-	limit := ctx.Int(limitFlag)
+	printRespJSON(result)
 
-	if limit < 0 {
-		return cli.NewExitError("Limit must be non-negative", 1)
+	return nil
+}
+
+func babylonInfo(ctx *cli.Context) error {
+	stakingTxHash := ctx.Args().First()
+
+	if stakingTxHash == "" {
+		return errors.New("staking transaction hash is required")
 	}
 
-	finalityProviders, err := client.BabylonFinalityProviders(sctx, &offset, &limit)
+	daemonAddress := ctx.String(stakingDaemonAddressFlag)
+	client, err := dc.NewStakerServiceJsonRpcClient(daemonAddress)
+	if err != nil {
+		return err
+	}
+
+	sctx := context.Background()
 
+	result, err := client.BabylonDelegationInfo(sctx, stakingTxHash)
 	if err != nil {
 		return err
 	}
 
-	printRespJSON(finalityProviders)
+	printRespJSON(result)
 
 	return nil
 }
 
-func getStakeOutput(ctx *cli.Context) error {
+func buildDelegationMsg(ctx *cli.Context) error {
 	daemonAddress := ctx.String(stakingDaemonAddressFlag)
-
 	client, err := dc.NewStakerServiceJsonRpcClient(daemonAddress)
 	if err != nil {
 		return err
 	}
+
 	sctx := context.Background()
 
-	stakerKey := ctx.String(stakerPubKeyFlag)
-	stakingAmount := ctx.Int64(stakingAmountFlag)
-	fpPks := ctx.StringSlice(fpPksFlag)
-	stakingTimeBlocks := ctx.Int64(stakingTimeBlocksFlag)
+	stakingTransactionHash := ctx.String(stakingTransactionHashFlag)
+	signer := ctx.String(signerFlag)
 
-	results, err := client.GetStakeOutput(sctx, stakerKey, stakingAmount, fpPks, stakingTimeBlocks)
+	result, err := client.BuildDelegationMsg(sctx, stakingTransactionHash, signer)
 	if err != nil {
 		return err
 	}
-	printRespJSON(results)
+
+	printRespJSON(result)
 
 	return nil
 }
 
-func stake(ctx *cli.Context) error {
+func markDelegationSubmitted(ctx *cli.Context) error {
 	daemonAddress := ctx.String(stakingDaemonAddressFlag)
 	client, err := dc.NewStakerServiceJsonRpcClient(daemonAddress)
 	if err != nil {
@@ -378,22 +1468,20 @@ func stake(ctx *cli.Context) error {
 
 	sctx := context.Background()
 
-	stakerAddress := ctx.String(stakerAddressFlag)
-	stakingAmount := ctx.Int64(stakingAmountFlag)
-	fpPks := ctx.StringSlice(fpPksFlag)
-	stakingTimeBlocks := ctx.Int64(stakingTimeFlag)
+	stakingTransactionHash := ctx.String(stakingTransactionHashFlag)
+	babylonTxHash := ctx.String(babylonTxHashFlag)
 
-	results, err := client.Stake(sctx, stakerAddress, stakingAmount, fpPks, stakingTimeBlocks)
+	result, err := client.MarkDelegationSubmitted(sctx, stakingTransactionHash, babylonTxHash)
 	if err != nil {
 		return err
 	}
 
-	printRespJSON(results)
+	printRespJSON(result)
 
 	return nil
 }
 
-func unstake(ctx *cli.Context) error {
+func cancelPendingDelegation(ctx *cli.Context) error {
 	daemonAddress := ctx.String(stakingDaemonAddressFlag)
 	client, err := dc.NewStakerServiceJsonRpcClient(daemonAddress)
 	if err != nil {
@@ -404,7 +1492,7 @@ func unstake(ctx *cli.Context) error {
 
 	stakingTransactionHash := ctx.String(stakingTransactionHashFlag)
 
-	result, err := client.SpendStakingTransaction(sctx, stakingTransactionHash)
+	result, err := client.CancelPendingDelegation(sctx, stakingTransactionHash)
 	if err != nil {
 		return err
 	}
@@ -414,7 +1502,7 @@ func unstake(ctx *cli.Context) error {
 	return nil
 }
 
-func unbond(ctx *cli.Context) error {
+func setLabel(ctx *cli.Context) error {
 	daemonAddress := ctx.String(stakingDaemonAddressFlag)
 	client, err := dc.NewStakerServiceJsonRpcClient(daemonAddress)
 	if err != nil {
@@ -424,19 +1512,9 @@ func unbond(ctx *cli.Context) error {
 	sctx := context.Background()
 
 	stakingTransactionHash := ctx.String(stakingTransactionHashFlag)
+	label := ctx.String(labelFlag)
 
-	feeRate := ctx.Int(feeRateFlag)
-
-	if feeRate < 0 {
-		return cli.NewExitError("Fee rate must be non-negative", 1)
-	}
-
-	var fr *int = nil
-	if feeRate > 0 {
-		fr = &feeRate
-	}
-
-	result, err := client.UnbondStaking(sctx, stakingTransactionHash, fr)
+	result, err := client.SetTransactionLabel(sctx, stakingTransactionHash, label)
 	if err != nil {
 		return err
 	}
@@ -446,7 +1524,7 @@ func unbond(ctx *cli.Context) error {
 	return nil
 }
 
-func stakingDetails(ctx *cli.Context) error {
+func setAutoRenew(ctx *cli.Context) error {
 	daemonAddress := ctx.String(stakingDaemonAddressFlag)
 	client, err := dc.NewStakerServiceJsonRpcClient(daemonAddress)
 	if err != nil {
@@ -457,7 +1535,7 @@ func stakingDetails(ctx *cli.Context) error {
 
 	stakingTransactionHash := ctx.String(stakingTransactionHashFlag)
 
-	result, err := client.StakingDetails(sctx, stakingTransactionHash)
+	result, err := client.SetAutoRenew(sctx, stakingTransactionHash, !ctx.Bool(disableFlag))
 	if err != nil {
 		return err
 	}
@@ -488,7 +1566,60 @@ func listStakingTransactions(ctx *cli.Context) error {
 		return cli.NewExitError("Limit must be non-negative", 1)
 	}
 
-	transactions, err := client.ListStakingTransactions(sctx, &offset, &limit)
+	var verbosity int
+	if ctx.Bool(verboseFlag) {
+		verbosity = 1
+	}
+
+	var label *string
+	if ctx.IsSet(labelFlag) {
+		l := ctx.String(labelFlag)
+		label = &l
+	}
+
+	var state *string
+	if ctx.IsSet(stateFlag) {
+		st := ctx.String(stateFlag)
+		state = &st
+	}
+
+	var includeArchived *bool
+	if ctx.Bool(includeArchivedFlag) {
+		archived := true
+		includeArchived = &archived
+	}
+
+	if ctx.Bool(tableFlag) || ctx.Bool(watchFlag) {
+		filter := listFilter{
+			offset:          offset,
+			pageSize:        listPageSize,
+			label:           label,
+			state:           state,
+			includeArchived: includeArchived,
+		}
+
+		color := isTerminal(os.Stdout)
+
+		if ctx.Bool(watchFlag) {
+			interval := ctx.Duration(watchIntervalFlag)
+
+			if interval <= 0 {
+				return cli.NewExitError("Interval must be positive", 1)
+			}
+
+			return watchStakingTransactions(client, filter, interval, os.Stdout, color)
+		}
+
+		rows, err := fetchListRows(sctx, client, filter)
+
+		if err != nil {
+			return err
+		}
+
+		return renderListTable(os.Stdout, rows, nil, color)
+	}
+
+	transactions, err := client.ListStakingTransactions(sctx, &offset, &limit, &verbosity, label, state, includeArchived)
 
 	if err != nil {
 		return err
@@ -530,3 +1661,227 @@ func withdrawableTransactions(ctx *cli.Context) error {
 
 	return nil
 }
+
+func reconcile(ctx *cli.Context) error {
+	daemonAddress := ctx.String(stakingDaemonAddressFlag)
+	client, err := dc.NewStakerServiceJsonRpcClient(daemonAddress)
+	if err != nil {
+		return err
+	}
+
+	sctx := context.Background()
+
+	var report *service.ReconciliationReportResponse
+
+	if ctx.Bool(lastReportFlag) {
+		report, err = client.ReconciliationReport(sctx)
+	} else {
+		report, err = client.Reconcile(sctx)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(report)
+
+	return nil
+}
+
+func pendingOperations(ctx *cli.Context) error {
+	daemonAddress := ctx.String(stakingDaemonAddressFlag)
+	client, err := dc.NewStakerServiceJsonRpcClient(daemonAddress)
+	if err != nil {
+		return err
+	}
+
+	sctx := context.Background()
+
+	pending, err := client.PendingOperations(sctx)
+
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(pending)
+
+	return nil
+}
+
+func debugState(ctx *cli.Context) error {
+	daemonAddress := ctx.String(stakingDaemonAddressFlag)
+	client, err := dc.NewStakerServiceJsonRpcClient(daemonAddress)
+	if err != nil {
+		return err
+	}
+
+	sctx := context.Background()
+
+	state, err := client.DebugState(sctx)
+
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(state)
+
+	return nil
+}
+
+func stakingParams(ctx *cli.Context) error {
+	daemonAddress := ctx.String(stakingDaemonAddressFlag)
+	client, err := dc.NewStakerServiceJsonRpcClient(daemonAddress)
+	if err != nil {
+		return err
+	}
+
+	sctx := context.Background()
+
+	params, err := client.StakingParams(sctx)
+
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(params)
+
+	return nil
+}
+
+func recoveryStatus(ctx *cli.Context) error {
+	daemonAddress := ctx.String(stakingDaemonAddressFlag)
+	client, err := dc.NewStakerServiceJsonRpcClient(daemonAddress)
+	if err != nil {
+		return err
+	}
+
+	sctx := context.Background()
+
+	status, err := client.RecoveryStatus(sctx)
+
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(status)
+
+	return nil
+}
+
+func auditLog(ctx *cli.Context) error {
+	daemonAddress := ctx.String(stakingDaemonAddressFlag)
+	client, err := dc.NewStakerServiceJsonRpcClient(daemonAddress)
+	if err != nil {
+		return err
+	}
+
+	sctx := context.Background()
+
+	fromSeq := ctx.Int(fromSeqFlag)
+
+	if fromSeq < 0 {
+		return cli.NewExitError("Sequence number must be non-negative", 1)
+	}
+
+	limit := ctx.Int(limitFlag)
+
+	if limit < 0 {
+		return cli.NewExitError("Limit must be non-negative", 1)
+	}
+
+	entries, err := client.QueryAuditLog(sctx, &fromSeq, &limit)
+
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(entries)
+
+	return nil
+}
+
+func providerExposure(ctx *cli.Context) error {
+	daemonAddress := ctx.String(stakingDaemonAddressFlag)
+	client, err := dc.NewStakerServiceJsonRpcClient(daemonAddress)
+	if err != nil {
+		return err
+	}
+
+	sctx := context.Background()
+
+	var fpPk *string
+	if ctx.IsSet(fpPkFlag) {
+		pk := ctx.String(fpPkFlag)
+		fpPk = &pk
+	}
+
+	exposure, err := client.ProviderExposure(sctx, fpPk)
+
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(exposure)
+
+	return nil
+}
+
+func stats(ctx *cli.Context) error {
+	daemonAddress := ctx.String(stakingDaemonAddressFlag)
+	client, err := dc.NewStakerServiceJsonRpcClient(daemonAddress)
+	if err != nil {
+		return err
+	}
+
+	sctx := context.Background()
+
+	daemonStats, err := client.Stats(sctx)
+
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(daemonStats)
+
+	return nil
+}
+
+func stateMachine(ctx *cli.Context) error {
+	daemonAddress := ctx.String(stakingDaemonAddressFlag)
+	client, err := dc.NewStakerServiceJsonRpcClient(daemonAddress)
+	if err != nil {
+		return err
+	}
+
+	sctx := context.Background()
+
+	definition, err := client.StateMachine(sctx)
+
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(definition)
+
+	return nil
+}
+
+func rescanStatus(ctx *cli.Context) error {
+	daemonAddress := ctx.String(stakingDaemonAddressFlag)
+	client, err := dc.NewStakerServiceJsonRpcClient(daemonAddress)
+	if err != nil {
+		return err
+	}
+
+	sctx := context.Background()
+
+	status, err := client.RescanStatus(sctx)
+
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(status)
+
+	return nil
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+
+	scfg "github.com/babylonchain/btc-staker/stakercfg"
+	"github.com/babylonchain/btc-staker/stakerdb"
+	service "github.com/babylonchain/btc-staker/stakerservice"
+	dc "github.com/babylonchain/btc-staker/stakerservice/client"
+	"github.com/urfave/cli"
+)
+
+var checkDbIntegrityCommand = cli.Command{
+	Name:      "check-db-integrity",
+	ShortName: "cdi",
+	Usage:     "Reports tracked transactions whose stored data is inconsistent with their state",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  stakingDaemonAddressFlag,
+			Usage: "full address of the staker daemon in format tcp:://<host>:<port>",
+			Value: defaultStakingDaemonAddress,
+		},
+		cli.BoolFlag{
+			Name:  jsonFlag,
+			Usage: "Print issues as json instead of table",
+		},
+		cli.StringFlag{
+			Name:  dbFlag,
+			Usage: "Path to staker database file. If set, database is checked directly instead of through the daemon, which must be stopped",
+		},
+	},
+	Action: checkDbIntegrity,
+}
+
+func checkDbIntegrity(ctx *cli.Context) error {
+	var (
+		resp *service.DbIntegrityResponse
+		err  error
+	)
+
+	if ctx.IsSet(dbFlag) {
+		resp, err = offlineDbIntegrity(ctx.String(dbFlag))
+	} else {
+		resp, err = daemonDbIntegrity(ctx.String(stakingDaemonAddressFlag))
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if ctx.Bool(jsonFlag) {
+		printRespJSON(resp)
+		return nil
+	}
+
+	if len(resp.Issues) == 0 {
+		fmt.Println("No integrity issues found")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "STAKING TX HASH\tSTATE\tCORRUPTED\tISSUE")
+
+	for _, issue := range resp.Issues {
+		fmt.Fprintf(w, "%s\t%s\t%t\t%s\n", issue.StakingTxHash, issue.StakingState, issue.Corrupted, issue.Description)
+	}
+
+	return w.Flush()
+}
+
+var repairStakingOutputCommand = cli.Command{
+	Name:      "repair-staking-output",
+	ShortName: "rso",
+	Usage: "Repair record of staking transaction flagged as corrupted, because it points at wrong output of staking transaction." +
+		" Record is pointed at the output matching its staking script. Requires staker daemon to be running.",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  stakingDaemonAddressFlag,
+			Usage: "full address of the staker daemon in format tcp:://<host>:<port>",
+			Value: defaultStakingDaemonAddress,
+		},
+		cli.StringFlag{
+			Name:     stakingTransactionHashFlag,
+			Usage:    "Hash of original staking transaction in bitcoin hex format",
+			Required: true,
+		},
+	},
+	Action: repairStakingOutput,
+}
+
+func repairStakingOutput(ctx *cli.Context) error {
+	client, err := dc.NewStakerServiceJsonRpcClient(ctx.String(stakingDaemonAddressFlag))
+	if err != nil {
+		return err
+	}
+
+	result, err := client.RepairStakingOutput(context.Background(), ctx.String(stakingTransactionHashFlag))
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(result)
+
+	return nil
+}
+
+func daemonDbIntegrity(daemonAddress string) (*service.DbIntegrityResponse, error) {
+	client, err := dc.NewStakerServiceJsonRpcClient(daemonAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	return client.DbIntegrity(context.Background())
+}
+
+func offlineDbIntegrity(dbPath string) (*service.DbIntegrityResponse, error) {
+	// opening not existing database would create a new empty one
+	if _, err := os.Stat(dbPath); err != nil {
+		return nil, fmt.Errorf("cannot open staker database: %w", err)
+	}
+
+	dbConfig := scfg.DefaultDBConfig()
+	dbConfig.DBPath = filepath.Dir(dbPath)
+	dbConfig.DBFileName = filepath.Base(dbPath)
+	dbConfig.DBTimeout = offlineDbTimeout
+
+	backend, err := scfg.GetDbBackend(&dbConfig)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open staker database, make sure stakerd is stopped: %w", err)
+	}
+	defer backend.Close()
+
+	store, err := stakerdb.NewTrackedTransactionStore(backend)
+	if err != nil {
+		return nil, err
+	}
+
+	issues, err := store.CheckIntegrity()
+	if err != nil {
+		return nil, err
+	}
+
+	return service.NewDbIntegrityResponse(issues), nil
+}
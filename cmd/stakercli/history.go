@@ -0,0 +1,139 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"os"
+	"time"
+
+	dc "github.com/babylonchain/btc-staker/stakerservice/client"
+	"github.com/urfave/cli"
+)
+
+const (
+	historyFromFlag   = "from"
+	historyToFlag     = "to"
+	historyFormatFlag = "format"
+
+	historyDateLayout = "2006-01-02"
+	historyFormatJSON = "json"
+	historyFormatCSV  = "csv"
+)
+
+var exportHistoryCmd = cli.Command{
+	Name:      "export-history",
+	ShortName: "eh",
+	Usage:     "Export history of staking, unbonding and withdrawal events with amounts and fees, e.g. for tax reporting",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  stakingDaemonAddressFlag,
+			Usage: "full address of the staker daemon in format tcp:://<host>:<port>",
+			Value: defaultStakingDaemonAddress,
+		},
+		cli.StringFlag{
+			Name:  historyFromFlag,
+			Usage: "first day of exported range in format YYYY-MM-DD (UTC). If not set, range starts with first event",
+		},
+		cli.StringFlag{
+			Name:  historyToFlag,
+			Usage: "last day of exported range in format YYYY-MM-DD (UTC), inclusive. If not set, range ends with last event",
+		},
+		cli.StringFlag{
+			Name:  historyFormatFlag,
+			Usage: "output format, one of: csv, json",
+			Value: historyFormatJSON,
+		},
+	},
+	Action: exportHistory,
+}
+
+// parseHistoryDay parses day given on command line and returns it in format
+// accepted by the daemon. Days are shifted by given offset, which allows to make
+// end of the range inclusive.
+func parseHistoryDay(day string, offset time.Duration) (string, error) {
+	if day == "" {
+		return "", nil
+	}
+
+	t, err := time.Parse(historyDateLayout, day)
+
+	if err != nil {
+		return "", fmt.Errorf("invalid date %s, expected format YYYY-MM-DD: %w", day, err)
+	}
+
+	return t.Add(offset).Format(time.RFC3339), nil
+}
+
+func exportHistory(ctx *cli.Context) error {
+	format := ctx.String(historyFormatFlag)
+
+	if format != historyFormatJSON && format != historyFormatCSV {
+		return cli.NewExitError(fmt.Sprintf("Unknown format %s, expected one of: csv, json", format), 1)
+	}
+
+	from, err := parseHistoryDay(ctx.String(historyFromFlag), 0)
+
+	if err != nil {
+		return cli.NewExitError(err.Error(), 1)
+	}
+
+	to, err := parseHistoryDay(ctx.String(historyToFlag), 24*time.Hour)
+
+	if err != nil {
+		return cli.NewExitError(err.Error(), 1)
+	}
+
+	daemonAddress := ctx.String(stakingDaemonAddressFlag)
+	client, err := dc.NewStakerServiceJsonRpcClient(daemonAddress)
+	if err != nil {
+		return err
+	}
+
+	sctx := context.Background()
+
+	history, err := client.History(sctx, from, to)
+
+	if err != nil {
+		return err
+	}
+
+	if format == historyFormatJSON {
+		printRespJSON(history)
+		return nil
+	}
+
+	w := csv.NewWriter(os.Stdout)
+
+	if err := w.Write([]string{
+		"time",
+		"time_source",
+		"type",
+		"staking_tx_hash",
+		"tx_hash",
+		"block_height",
+		"amount_sat",
+		"fee_sat",
+	}); err != nil {
+		return err
+	}
+
+	for _, e := range history.Events {
+		if err := w.Write([]string{
+			e.Time,
+			e.TimeSource,
+			e.Type,
+			e.StakingTxHash,
+			e.TxHash,
+			e.BlockHeight,
+			e.Amount,
+			e.Fee,
+		}); err != nil {
+			return err
+		}
+	}
+
+	w.Flush()
+
+	return w.Error()
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	dc "github.com/babylonchain/btc-staker/stakerservice/client"
+	"github.com/urfave/cli"
+)
+
+var latencyReportCmd = cli.Command{
+	Name:  "latency-report",
+	Usage: "Show p50 and p95 of times between staking transaction lifecycle transitions, e.g. from broadcast to confirmation",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  stakingDaemonAddressFlag,
+			Usage: "full address of the staker daemon in format tcp:://<host>:<port>",
+			Value: defaultStakingDaemonAddress,
+		},
+		cli.StringFlag{
+			Name:  historyFromFlag,
+			Usage: "first day of reported range in format YYYY-MM-DD (UTC). If not set, range starts with first transition",
+		},
+		cli.StringFlag{
+			Name:  historyToFlag,
+			Usage: "last day of reported range in format YYYY-MM-DD (UTC), inclusive. If not set, range ends with last transition",
+		},
+	},
+	Action: latencyReport,
+}
+
+func latencyReport(ctx *cli.Context) error {
+	from, err := parseHistoryDay(ctx.String(historyFromFlag), 0)
+
+	if err != nil {
+		return cli.NewExitError(err.Error(), 1)
+	}
+
+	to, err := parseHistoryDay(ctx.String(historyToFlag), 24*time.Hour)
+
+	if err != nil {
+		return cli.NewExitError(err.Error(), 1)
+	}
+
+	daemonAddress := ctx.String(stakingDaemonAddressFlag)
+	client, err := dc.NewStakerServiceJsonRpcClient(daemonAddress)
+	if err != nil {
+		return err
+	}
+
+	sctx := context.Background()
+
+	report, err := client.LatencyReport(sctx, from, to)
+
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(report)
+
+	return nil
+}
@@ -0,0 +1,283 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	service "github.com/babylonchain/btc-staker/stakerservice"
+)
+
+const (
+	tableFlag         = "table"
+	watchFlag         = "watch"
+	watchIntervalFlag = "interval"
+	stateFlag         = "state"
+
+	// number of transactions requested from daemon in single list call
+	listPageSize = 50
+	// number of characters of staking transaction hash shown in table
+	listHashPrefixLen = 12
+
+	clearScreen    = "\x1b[H\x1b[2J"
+	highlightStart = "\x1b[1;33m"
+	highlightEnd   = "\x1b[0m"
+)
+
+// stakingTransactionsLister is part of daemon client used to build compact list
+// of staking transactions
+type stakingTransactionsLister interface {
+	ListStakingTransactions(
+		ctx context.Context,
+		offset *int,
+		limit *int,
+		verbosity *int,
+		label *string,
+		state *string,
+		includeArchived *bool,
+	) (*service.ListStakingTransactionsResponse, error)
+	TransactionDetails(ctx context.Context, txHash string) (*service.TransactionDetailsResponse, error)
+}
+
+type listFilter struct {
+	// index of transaction after which listing starts
+	offset          int
+	pageSize        int
+	label           *string
+	state           *string
+	includeArchived *bool
+}
+
+// listRow is single row of compact list of staking transactions. Values which
+// are not known are rendered as -.
+type listRow struct {
+	stakingTxHash  string
+	amount         string
+	state          string
+	confirmations  string
+	withdrawableIn string
+}
+
+func newListRow(d *service.TransactionDetailsResponse) listRow {
+	row := listRow{
+		stakingTxHash:  d.StakingTxHash,
+		amount:         d.StakingValue,
+		state:          d.StakingState,
+		confirmations:  "-",
+		withdrawableIn: "-",
+	}
+
+	currentHeight, err := strconv.ParseUint(d.CurrentBtcHeight, 10, 32)
+
+	if err != nil {
+		// current height is not known, so confirmations cannot be counted
+		return row
+	}
+
+	if d.Confirmation != nil {
+		confirmationHeight, err := strconv.ParseUint(d.Confirmation.BlockHeight, 10, 32)
+
+		if err == nil && currentHeight >= confirmationHeight {
+			row.confirmations = strconv.FormatUint(currentHeight-confirmationHeight+1, 10)
+		}
+	}
+
+	if d.Withdrawable != nil && *d.Withdrawable {
+		row.withdrawableIn = "now"
+		return row
+	}
+
+	withdrawableHeight, err := strconv.ParseUint(d.WithdrawableHeight, 10, 32)
+
+	if err == nil && withdrawableHeight > currentHeight+1 {
+		// withdrawal can be included in block at withdrawable height, which is
+		// mined after the blocks before it
+		row.withdrawableIn = fmt.Sprintf("%d blocks", withdrawableHeight-currentHeight-1)
+	}
+
+	return row
+}
+
+// fetchListRows pages through staking transactions matching the filter, and
+// builds table row of each of them from its details
+func fetchListRows(ctx context.Context, lister stakingTransactionsLister, filter listFilter) ([]listRow, error) {
+	var rows []listRow
+
+	offset := filter.offset
+
+	for {
+		limit := filter.pageSize
+
+		resp, err := lister.ListStakingTransactions(ctx, &offset, &limit, nil, filter.label, filter.state, filter.includeArchived)
+
+		if err != nil {
+			return nil, err
+		}
+
+		for _, tx := range resp.Transactions {
+			details, err := lister.TransactionDetails(ctx, tx.StakingTxHash)
+
+			if err != nil {
+				return nil, err
+			}
+
+			rows = append(rows, newListRow(details))
+		}
+
+		if len(resp.Transactions) < filter.pageSize {
+			return rows, nil
+		}
+
+		// offset is index of transaction, so next page starts after the last
+		// returned one
+		lastIdx := resp.Transactions[len(resp.Transactions)-1].TransactionIdx
+
+		offset, err = strconv.Atoi(lastIdx)
+
+		if err != nil {
+			return nil, fmt.Errorf("invalid transaction index %s: %w", lastIdx, err)
+		}
+	}
+}
+
+// listRowStates returns state of each listed transaction, used to find rows
+// which changed on the next refresh
+func listRowStates(rows []listRow) map[string]string {
+	states := make(map[string]string, len(rows))
+
+	for _, r := range rows {
+		states[r.stakingTxHash] = r.state
+	}
+
+	return states
+}
+
+func shortHash(hash string) string {
+	if len(hash) <= listHashPrefixLen {
+		return hash
+	}
+
+	return hash[:listHashPrefixLen]
+}
+
+// renderListTable writes compact table of staking transactions. Rows of
+// transactions which state changed since previous refresh, or which were not
+// listed before, are marked with * and highlighted if color is enabled. Nothing
+// is marked if previous is nil i.e on the first refresh.
+func renderListTable(out io.Writer, rows []listRow, previous map[string]string, color bool) error {
+	var buf bytes.Buffer
+
+	w := tabwriter.NewWriter(&buf, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(w, " \tHASH\tAMOUNT\tSTATE\tCONFIRMATIONS\tWITHDRAWABLE IN")
+
+	changed := make([]bool, len(rows))
+
+	for i, r := range rows {
+		marker := " "
+
+		if previous != nil {
+			if prevState, ok := previous[r.stakingTxHash]; !ok || prevState != r.state {
+				changed[i] = true
+				marker = "*"
+			}
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n",
+			marker, shortHash(r.stakingTxHash), r.amount, r.state, r.confirmations, r.withdrawableIn)
+	}
+
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	// highlighting is applied to aligned lines, as escape codes would break
+	// alignment of tabwriter
+	lines := strings.SplitAfter(buf.String(), "\n")
+
+	for i, line := range lines {
+		// first line is header
+		if color && i > 0 && i <= len(rows) && changed[i-1] {
+			line = highlightStart + strings.TrimSuffix(line, "\n") + highlightEnd + "\n"
+		}
+
+		if _, err := io.WriteString(out, line); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// renderWatchView writes single refresh of watch mode
+func renderWatchView(
+	out io.Writer,
+	rows []listRow,
+	previous map[string]string,
+	interval time.Duration,
+	now time.Time,
+	color bool,
+) error {
+	fmt.Fprintf(out, "Every %s, updated at %s. Transactions: %d\n\n",
+		interval, now.UTC().Format(time.RFC3339), len(rows))
+
+	return renderListTable(out, rows, previous, color)
+}
+
+// watchStakingTransactions re-renders table of staking transactions every
+// interval until interrupted. Failed refreshes are reported and retried, as
+// daemon could be restarted while it is watched.
+func watchStakingTransactions(
+	lister stakingTransactionsLister,
+	filter listFilter,
+	interval time.Duration,
+	out io.Writer,
+	color bool,
+) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	var previous map[string]string
+
+	for {
+		rows, err := fetchListRows(ctx, lister, filter)
+
+		fmt.Fprint(out, clearScreen)
+
+		if err != nil {
+			fmt.Fprintf(out, "Every %s, updated at %s. Failed to list transactions: %v\n",
+				interval, time.Now().UTC().Format(time.RFC3339), err)
+		} else {
+			if err := renderWatchView(out, rows, previous, interval, time.Now(), color); err != nil {
+				return err
+			}
+
+			previous = listRowStates(rows)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(interval):
+		}
+	}
+}
+
+// isTerminal returns true if file is attached to terminal, in which case output
+// can be highlighted
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+
+	if err != nil {
+		return false
+	}
+
+	return info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,211 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	service "github.com/babylonchain/btc-staker/stakerservice"
+	"github.com/stretchr/testify/require"
+)
+
+var updateGolden = flag.Bool("update", false, "update golden files")
+
+func requireGolden(t *testing.T, name string, actual []byte) {
+	t.Helper()
+
+	path := filepath.Join("testdata", name+".golden")
+
+	if *updateGolden {
+		require.NoError(t, os.WriteFile(path, actual, 0o644))
+	}
+
+	expected, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, string(expected), string(actual))
+}
+
+type testLister struct {
+	transactions []service.StakingDetails
+	details      map[string]*service.TransactionDetailsResponse
+	// offsets of list calls
+	offsets []int
+}
+
+func (l *testLister) ListStakingTransactions(
+	_ context.Context,
+	offset *int,
+	limit *int,
+	_ *int,
+	_ *string,
+	_ *string,
+	_ *bool,
+) (*service.ListStakingTransactionsResponse, error) {
+	l.offsets = append(l.offsets, *offset)
+
+	var page []service.StakingDetails
+
+	for _, tx := range l.transactions {
+		idx, err := strconv.Atoi(tx.TransactionIdx)
+		if err != nil {
+			return nil, err
+		}
+
+		if idx > *offset && len(page) < *limit {
+			page = append(page, tx)
+		}
+	}
+
+	return &service.ListStakingTransactionsResponse{
+		Transactions:          page,
+		TotalTransactionCount: strconv.Itoa(len(l.transactions)),
+	}, nil
+}
+
+func (l *testLister) TransactionDetails(_ context.Context, txHash string) (*service.TransactionDetailsResponse, error) {
+	details, ok := l.details[txHash]
+
+	if !ok {
+		return nil, fmt.Errorf("transaction %s not found", txHash)
+	}
+
+	return details, nil
+}
+
+func testHash(i int) string {
+	return strings.Repeat(strconv.Itoa(i), 64)
+}
+
+func boolPtr(b bool) *bool {
+	return &b
+}
+
+func testDetails() []*service.TransactionDetailsResponse {
+	return []*service.TransactionDetailsResponse{
+		{
+			StakingTxHash: testHash(1),
+			StakingState:  "SENT_TO_BTC",
+			StakingValue:  "100000",
+		},
+		{
+			StakingTxHash:      testHash(2),
+			StakingState:       "DELEGATION_ACTIVE",
+			StakingValue:       "2500000",
+			Confirmation:       &service.BtcConfirmationDetails{BlockHeight: "990"},
+			WithdrawableHeight: "1990",
+			Withdrawable:       boolPtr(false),
+			CurrentBtcHeight:   "1000",
+		},
+		{
+			StakingTxHash:      testHash(3),
+			StakingState:       "UNBONDING_CONFIRMED_ON_BTC",
+			StakingValue:       "50000",
+			Confirmation:       &service.BtcConfirmationDetails{BlockHeight: "500"},
+			WithdrawableHeight: "1000",
+			Withdrawable:       boolPtr(true),
+			CurrentBtcHeight:   "1000",
+		},
+		{
+			StakingTxHash:    testHash(4),
+			StakingState:     "SPENT_ON_BTC",
+			StakingValue:     "75000",
+			Confirmation:     &service.BtcConfirmationDetails{BlockHeight: "100"},
+			CurrentBtcHeight: "1000",
+		},
+	}
+}
+
+func testListRows() []listRow {
+	var rows []listRow
+
+	for _, d := range testDetails() {
+		rows = append(rows, newListRow(d))
+	}
+
+	return rows
+}
+
+func TestNewListRow(t *testing.T) {
+	rows := testListRows()
+
+	require.Equal(t, listRow{
+		stakingTxHash:  testHash(1),
+		amount:         "100000",
+		state:          "SENT_TO_BTC",
+		confirmations:  "-",
+		withdrawableIn: "-",
+	}, rows[0])
+
+	require.Equal(t, "11", rows[1].confirmations)
+	require.Equal(t, "989 blocks", rows[1].withdrawableIn)
+
+	require.Equal(t, "501", rows[2].confirmations)
+	require.Equal(t, "now", rows[2].withdrawableIn)
+
+	require.Equal(t, "901", rows[3].confirmations)
+	require.Equal(t, "-", rows[3].withdrawableIn)
+}
+
+func TestFetchListRowsPaginates(t *testing.T) {
+	lister := &testLister{details: make(map[string]*service.TransactionDetailsResponse)}
+
+	for i, d := range testDetails() {
+		// indexes are not contiguous when transactions are filtered
+		lister.transactions = append(lister.transactions, service.StakingDetails{
+			StakingTxHash:  d.StakingTxHash,
+			TransactionIdx: strconv.Itoa(2*i + 1),
+		})
+		lister.details[d.StakingTxHash] = d
+	}
+
+	rows, err := fetchListRows(context.Background(), lister, listFilter{pageSize: 3})
+	require.NoError(t, err)
+	require.Equal(t, testListRows(), rows)
+	require.Equal(t, []int{0, 5}, lister.offsets)
+
+	// last page is full, so one more empty page is requested
+	lister.offsets = nil
+	rows, err = fetchListRows(context.Background(), lister, listFilter{offset: 1, pageSize: 3})
+	require.NoError(t, err)
+	require.Equal(t, testListRows()[1:], rows)
+	require.Equal(t, []int{1, 7}, lister.offsets)
+
+	delete(lister.details, testHash(2))
+	_, err = fetchListRows(context.Background(), lister, listFilter{pageSize: 3})
+	require.Error(t, err)
+}
+
+func TestRenderListTable(t *testing.T) {
+	rows := testListRows()
+
+	var buf bytes.Buffer
+	require.NoError(t, renderListTable(&buf, rows, nil, true))
+	requireGolden(t, "list_table", buf.Bytes())
+
+	// second transaction changed state and last one was not listed before
+	previous := listRowStates(rows[:3])
+	previous[testHash(2)] = "SENT_TO_BABYLON"
+
+	buf.Reset()
+	require.NoError(t, renderListTable(&buf, rows, previous, false))
+	requireGolden(t, "list_table_changed", buf.Bytes())
+
+	buf.Reset()
+	require.NoError(t, renderListTable(&buf, rows, previous, true))
+	requireGolden(t, "list_table_changed_color", buf.Bytes())
+}
+
+func TestRenderWatchView(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	var buf bytes.Buffer
+	require.NoError(t, renderWatchView(&buf, testListRows(), nil, 5*time.Second, now, false))
+	requireGolden(t, "list_watch", buf.Bytes())
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	scfg "github.com/babylonchain/btc-staker/stakercfg"
+	"github.com/babylonchain/btc-staker/stakerdb"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/urfave/cli"
+)
+
+const (
+	networkFlag = "network"
+	dbPathFlag  = "dbpath"
+	logDirFlag  = "logdir"
+)
+
+var migrateDataDirCommand = cli.Command{
+	Name:      "migrate-data-dir",
+	ShortName: "mdd",
+	Usage:     "Move database and logs of staker from directory shared by all networks to the directory of given network. Daemon must be stopped during migration.",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:     networkFlag,
+			Usage:    "network of the stored data as configured in stakerd, one of: regtest, testnet, simnet, signet",
+			Required: true,
+		},
+		cli.StringFlag{
+			Name:  dbPathFlag,
+			Usage: "directory with the database file, as configured by dbpath option of stakerd",
+			Value: scfg.DefaultDBConfig().DBPath,
+		},
+		cli.StringFlag{
+			Name:  logDirFlag,
+			Usage: "directory with the log files, as configured by logdir option of stakerd",
+			Value: scfg.DefaultConfig().LogDir,
+		},
+	},
+	Action: migrateDataDir,
+}
+
+func migrateDataDir(ctx *cli.Context) error {
+	network := ctx.String(networkFlag)
+
+	params, err := scfg.NetParams(&scfg.ChainConfig{Network: network})
+	if err != nil {
+		return cli.NewExitError(err.Error(), 1)
+	}
+
+	dbPath := scfg.CleanAndExpandPath(ctx.String(dbPathFlag))
+	dbFileName := scfg.DefaultDBConfig().DBFileName
+	legacyDbFile := filepath.Join(dbPath, dbFileName)
+	networkDbPath := scfg.NetworkDir(dbPath, network)
+	dbFile := filepath.Join(networkDbPath, dbFileName)
+
+	if !scfg.FileExists(legacyDbFile) {
+		return cli.NewExitError(fmt.Sprintf("database %s does not exist, there is nothing to migrate", legacyDbFile), 1)
+	}
+
+	if scfg.FileExists(dbFile) {
+		return cli.NewExitError(fmt.Sprintf("database %s already exists", dbFile), 1)
+	}
+
+	// data are moved only if they belong to given network. Network is recorded
+	// in the database, so that it cannot be opened by daemon running on other
+	// network later
+	if err := recordDbNetwork(dbPath, dbFileName, network, params); err != nil {
+		return cli.NewExitError(fmt.Sprintf("cannot migrate database %s: %s", legacyDbFile, err), 1)
+	}
+
+	if err := os.MkdirAll(networkDbPath, 0700); err != nil {
+		return err
+	}
+
+	if err := os.Rename(legacyDbFile, dbFile); err != nil {
+		return err
+	}
+
+	fmt.Printf("Moved database %s to %s\n", legacyDbFile, dbFile)
+
+	logDir := scfg.CleanAndExpandPath(ctx.String(logDirFlag))
+
+	return moveLogs(logDir, scfg.NetworkDir(logDir, network))
+}
+
+func recordDbNetwork(dbPath, dbFileName, network string, params *chaincfg.Params) error {
+	dbConfig := scfg.DefaultDBConfig()
+	dbConfig.DBPath = dbPath
+	dbConfig.DBFileName = dbFileName
+	dbConfig.DBTimeout = offlineDbTimeout
+
+	backend, err := scfg.GetDbBackend(&dbConfig)
+	if err != nil {
+		return fmt.Errorf("cannot open staker database, make sure stakerd is stopped: %w", err)
+	}
+	defer backend.Close()
+
+	store, err := stakerdb.NewTrackedTransactionStore(backend)
+	if err != nil {
+		return err
+	}
+
+	return store.CheckNetwork(network, params)
+}
+
+// moveLogs moves log files from logDir to networkLogDir. Subdirectories, which
+// hold logs of other networks, are left in place.
+func moveLogs(logDir, networkLogDir string) error {
+	entries, err := os.ReadDir(logDir)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(networkLogDir, 0700); err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		src := filepath.Join(logDir, entry.Name())
+		dst := filepath.Join(networkLogDir, entry.Name())
+
+		if scfg.FileExists(dst) {
+			fmt.Printf("Log file %s already exists, leaving %s in place\n", dst, src)
+			continue
+		}
+
+		if err := os.Rename(src, dst); err != nil {
+			return err
+		}
+
+		fmt.Printf("Moved log file %s to %s\n", src, dst)
+	}
+
+	return nil
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	scfg "github.com/babylonchain/btc-staker/stakercfg"
+	"github.com/babylonchain/btc-staker/stakerdb"
+	"github.com/urfave/cli"
+)
+
+const (
+	migrateToFlag           = "to"
+	postgresDsnFlag         = "postgres-dsn"
+	postgresTablePrefixFlag = "postgres-table-prefix"
+)
+
+var migrateDbCommand = cli.Command{
+	Name:      "migrate-db",
+	ShortName: "mdb",
+	Usage:     "Copy staker database between bbolt file and postgres. Daemon must be stopped during migration.",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:     migrateToFlag,
+			Usage:    "backend to which data is copied, one of: postgres, bbolt. Data is copied from the other backend",
+			Required: true,
+		},
+		cli.StringFlag{
+			Name:  dbFlag,
+			Usage: "path to bbolt database file. Must exist when migrating to postgres, and must not exist when migrating to bbolt",
+			Value: filepath.Join(
+				scfg.NetworkDir(scfg.DefaultDBConfig().DBPath, scfg.DefaultChainConfig().Network),
+				scfg.DefaultDBConfig().DBFileName,
+			),
+		},
+		cli.StringFlag{
+			Name:     postgresDsnFlag,
+			Usage:    "postgres connection string",
+			Required: true,
+		},
+		cli.StringFlag{
+			Name:  postgresTablePrefixFlag,
+			Usage: "prefix of the tables used by the daemon in postgres",
+			Value: scfg.DefaultDBConfig().Postgres.TablePrefix,
+		},
+	},
+	Action: migrateDb,
+}
+
+func migrateDb(ctx *cli.Context) error {
+	to := ctx.String(migrateToFlag)
+	dbPath := ctx.String(dbFlag)
+
+	boltConfig := scfg.DefaultDBConfig()
+	boltConfig.DBPath = filepath.Dir(dbPath)
+	boltConfig.DBFileName = filepath.Base(dbPath)
+	boltConfig.DBTimeout = offlineDbTimeout
+
+	postgresConfig := scfg.DefaultDBConfig()
+	postgresConfig.Backend = scfg.PostgresDbBackend
+	postgresConfig.Postgres.Dsn = ctx.String(postgresDsnFlag)
+	postgresConfig.Postgres.TablePrefix = ctx.String(postgresTablePrefixFlag)
+
+	_, statErr := os.Stat(dbPath)
+
+	var srcConfig, dstConfig *scfg.DBConfig
+
+	switch to {
+	case scfg.PostgresDbBackend:
+		// opening not existing database would create a new empty one
+		if statErr != nil {
+			return cli.NewExitError(fmt.Sprintf("cannot open staker database: %s", statErr), 1)
+		}
+
+		srcConfig, dstConfig = &boltConfig, &postgresConfig
+	case scfg.BoltDbBackend:
+		if statErr == nil {
+			return cli.NewExitError(fmt.Sprintf("database file %s already exists", dbPath), 1)
+		}
+
+		srcConfig, dstConfig = &postgresConfig, &boltConfig
+	default:
+		return cli.NewExitError(fmt.Sprintf("Unknown backend %s, expected one of: postgres, bbolt", to), 1)
+	}
+
+	src, err := scfg.GetDbBackend(srcConfig)
+	if err != nil {
+		return fmt.Errorf("cannot open source database, make sure stakerd is stopped: %w", err)
+	}
+	defer src.Close()
+
+	dst, err := scfg.GetDbBackend(dstConfig)
+	if err != nil {
+		return fmt.Errorf("cannot open destination database: %w", err)
+	}
+	defer dst.Close()
+
+	if err := stakerdb.CopyDatabase(src, dst); err != nil {
+		return fmt.Errorf("failed to copy database: %w", err)
+	}
+
+	fmt.Printf("Database copied to %s. Set dbconfig.backend=%s in stakerd config to use it.\n", to, to)
+
+	return nil
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	dc "github.com/babylonchain/btc-staker/stakerservice/client"
+	"github.com/urfave/cli"
+)
+
+const (
+	noteFlag     = "note"
+	noteFileFlag = "note-file"
+	queryFlag    = "query"
+)
+
+var setNoteCmd = cli.Command{
+	Name:  "set-note",
+	Usage: "Assign free-form note to staking transaction, empty note removes current note. Changes of notes are recorded in audit log",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  stakingDaemonAddressFlag,
+			Usage: "full address of the staker daemon in format tcp:://<host>:<port>",
+			Value: defaultStakingDaemonAddress,
+		},
+		cli.StringFlag{
+			Name:     stakingTransactionHashFlag,
+			Usage:    "Hash of original staking transaction in bitcoin hex format",
+			Required: true,
+		},
+		cli.StringFlag{
+			Name:  noteFlag,
+			Usage: "Note of staking transaction, at most 4096 bytes",
+		},
+		cli.StringFlag{
+			Name:  noteFileFlag,
+			Usage: "Path to file with note of staking transaction, - reads note from standard input",
+		},
+	},
+	Action: setNote,
+}
+
+var getNoteCmd = cli.Command{
+	Name:  "get-note",
+	Usage: "Get note of staking transaction",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  stakingDaemonAddressFlag,
+			Usage: "full address of the staker daemon in format tcp:://<host>:<port>",
+			Value: defaultStakingDaemonAddress,
+		},
+		cli.StringFlag{
+			Name:     stakingTransactionHashFlag,
+			Usage:    "Hash of original staking transaction in bitcoin hex format",
+			Required: true,
+		},
+	},
+	Action: getNote,
+}
+
+var searchTransactionsCmd = cli.Command{
+	Name:  "search-transactions",
+	Usage: "Search staking transactions, including archived ones, which label or note contains given text, ignoring case",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  stakingDaemonAddressFlag,
+			Usage: "full address of the staker daemon in format tcp:://<host>:<port>",
+			Value: defaultStakingDaemonAddress,
+		},
+		cli.StringFlag{
+			Name:     queryFlag,
+			Usage:    "Text searched in labels and notes",
+			Required: true,
+		},
+	},
+	Action: searchTransactions,
+}
+
+func readNote(ctx *cli.Context) (string, error) {
+	if ctx.IsSet(noteFlag) && ctx.IsSet(noteFileFlag) {
+		return "", fmt.Errorf("only one of --%s and --%s can be set", noteFlag, noteFileFlag)
+	}
+
+	if !ctx.IsSet(noteFileFlag) {
+		return ctx.String(noteFlag), nil
+	}
+
+	var (
+		note []byte
+		err  error
+	)
+
+	if path := ctx.String(noteFileFlag); path == "-" {
+		note, err = io.ReadAll(os.Stdin)
+	} else {
+		note, err = os.ReadFile(path)
+	}
+
+	if err != nil {
+		return "", fmt.Errorf("failed to read note: %w", err)
+	}
+
+	return string(note), nil
+}
+
+func setNote(ctx *cli.Context) error {
+	note, err := readNote(ctx)
+	if err != nil {
+		return err
+	}
+
+	daemonAddress := ctx.String(stakingDaemonAddressFlag)
+	client, err := dc.NewStakerServiceJsonRpcClient(daemonAddress)
+	if err != nil {
+		return err
+	}
+
+	sctx := context.Background()
+
+	result, err := client.SetTransactionNote(sctx, ctx.String(stakingTransactionHashFlag), note)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(result)
+
+	return nil
+}
+
+func getNote(ctx *cli.Context) error {
+	daemonAddress := ctx.String(stakingDaemonAddressFlag)
+	client, err := dc.NewStakerServiceJsonRpcClient(daemonAddress)
+	if err != nil {
+		return err
+	}
+
+	sctx := context.Background()
+
+	result, err := client.GetTransactionNote(sctx, ctx.String(stakingTransactionHashFlag))
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(result)
+
+	return nil
+}
+
+func searchTransactions(ctx *cli.Context) error {
+	daemonAddress := ctx.String(stakingDaemonAddressFlag)
+	client, err := dc.NewStakerServiceJsonRpcClient(daemonAddress)
+	if err != nil {
+		return err
+	}
+
+	sctx := context.Background()
+
+	result, err := client.SearchTransactions(sctx, ctx.String(queryFlag))
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(result)
+
+	return nil
+}
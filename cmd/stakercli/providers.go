@@ -0,0 +1,118 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	service "github.com/babylonchain/btc-staker/stakerservice"
+	dc "github.com/babylonchain/btc-staker/stakerservice/client"
+	"github.com/urfave/cli"
+)
+
+const (
+	allFlag            = "all"
+	pageKeyFlag        = "page-key"
+	includeSlashedFlag = "include-slashed"
+)
+
+var listProvidersCmd = cli.Command{
+	Name:      "list-providers",
+	ShortName: "lp",
+	Usage:     "List finality providers registered on Babylon chain",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  stakingDaemonAddressFlag,
+			Usage: "full address of the staker daemon in format tcp:://<host>:<port>",
+			Value: defaultStakingDaemonAddress,
+		},
+		cli.IntFlag{
+			Name:  limitFlag,
+			Usage: "maximum number of finality providers queried in one page",
+			Value: 100,
+		},
+		cli.StringFlag{
+			Name:  pageKeyFlag,
+			Usage: "key of the page to list, as returned with the previous page",
+		},
+		cli.BoolFlag{
+			Name:  allFlag,
+			Usage: "list all finality providers, following pages until the last one",
+		},
+		cli.BoolFlag{
+			Name:  includeSlashedFlag,
+			Usage: "include slashed finality providers",
+		},
+		cli.BoolFlag{
+			Name:  jsonFlag,
+			Usage: "print result as json instead of table",
+		},
+	},
+	Action: listProviders,
+}
+
+func listProviders(ctx *cli.Context) error {
+	daemonAddress := ctx.String(stakingDaemonAddressFlag)
+	client, err := dc.NewStakerServiceJsonRpcClient(daemonAddress)
+	if err != nil {
+		return err
+	}
+
+	sctx := context.Background()
+
+	limit := ctx.Int(limitFlag)
+
+	if limit <= 0 {
+		return cli.NewExitError("Limit must be positive", 1)
+	}
+
+	pageKey := ctx.String(pageKeyFlag)
+
+	result := &service.FinalityProvidersResponse{}
+
+	for {
+		page, err := client.BabylonFinalityProvidersPage(sctx, nil, &limit, &pageKey, ctx.Bool(includeSlashedFlag))
+
+		if err != nil {
+			return err
+		}
+
+		result.FinalityProviders = append(result.FinalityProviders, page.FinalityProviders...)
+		result.TotalFinalityProvidersCount = page.TotalFinalityProvidersCount
+		result.NextPageKey = page.NextPageKey
+
+		if !ctx.Bool(allFlag) || page.NextPageKey == "" {
+			break
+		}
+
+		pageKey = page.NextPageKey
+	}
+
+	if ctx.Bool(jsonFlag) {
+		printRespJSON(result)
+		return nil
+	}
+
+	return printProvidersTable(result)
+}
+
+func printProvidersTable(resp *service.FinalityProvidersResponse) error {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(w, "BTC PUBLIC KEY\tMONIKER\tCOMMISSION\tSTATUS")
+
+	for _, fp := range resp.FinalityProviders {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", fp.BtcPublicKey, fp.Moniker, fp.Commission, fp.Status)
+	}
+
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	if resp.NextPageKey != "" {
+		fmt.Printf("\nNext page key: %s\n", resp.NextPageKey)
+	}
+
+	return nil
+}
@@ -0,0 +1,561 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/babylonchain/btc-staker/proto"
+	scfg "github.com/babylonchain/btc-staker/stakercfg"
+	dc "github.com/babylonchain/btc-staker/stakerservice/client"
+	"github.com/babylonchain/btc-staker/types"
+	"github.com/babylonchain/btc-staker/walletcontroller"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/rpcclient"
+	"github.com/jessevdk/go-flags"
+	"github.com/urfave/cli"
+)
+
+const (
+	regtestFlag    = "regtest"
+	configFileFlag = "config-file"
+	fromStepFlag   = "from-step"
+	amountFlag     = "amount"
+	timeoutFlag    = "timeout"
+
+	quickstartStateFileName = "quickstart.json"
+	quickstartPollInterval  = 2 * time.Second
+	defaultQuickstartAmount = 1000000
+)
+
+var quickstartCommand = cli.Command{
+	Name:      "quickstart",
+	ShortName: "qs",
+	Usage: "Run example staking flow end to end: create keyring, fund wallet by mining blocks, stake to the first finality provider" +
+		" and wait until delegation is sent to babylon. Requires bitcoind regtest node and babylon devnet configured in stakerd config." +
+		" Staker daemon must be running from the provider step on.",
+	Flags: []cli.Flag{
+		cli.BoolFlag{
+			Name:  regtestFlag,
+			Usage: "Confirm that the flow runs on regtest, where blocks can be mined on demand. Required",
+		},
+		cli.StringFlag{
+			Name:  configFileFlag,
+			Usage: "Path to stakerd config file",
+			Value: scfg.DefaultConfigFile,
+		},
+		cli.StringFlag{
+			Name:  stakingDaemonAddressFlag,
+			Usage: "full address of the staker daemon in format tcp:://<host>:<port>",
+			Value: defaultStakingDaemonAddress,
+		},
+		cli.StringFlag{
+			Name:  fromStepFlag,
+			Usage: "Resume flow from given step, results of previous steps are read from quickstart.json in stakerd directory. One of: " + quickstartStepNames(),
+			Value: quickstartSteps[0].name,
+		},
+		cli.Int64Flag{
+			Name:  amountFlag,
+			Usage: "Staked amount in satoshis",
+			Value: defaultQuickstartAmount,
+		},
+		cli.Int64Flag{
+			Name:  stakingTimeFlag,
+			Usage: "Staking time in btc blocks, minimum staking time from babylon params if not set",
+		},
+		cli.DurationFlag{
+			Name:  timeoutFlag,
+			Usage: "Maximum time to wait in every step waiting for wallet or daemon",
+			Value: 10 * time.Minute,
+		},
+	},
+	Action: runQuickstart,
+}
+
+// quickstartState holds results of finished steps, so that the flow can be
+// resumed from any step
+type quickstartState struct {
+	StakerAddress      string `json:"staker_address,omitempty"`
+	FundingBlockHash   string `json:"funding_block_hash,omitempty"`
+	FinalityProviderPk string `json:"finality_provider_pk,omitempty"`
+	StakingTxHash      string `json:"staking_tx_hash,omitempty"`
+}
+
+type quickstartStep struct {
+	name string
+	// run executes the step and returns summary of its result
+	run func(q *quickstart) (string, error)
+}
+
+var quickstartSteps = []quickstartStep{
+	{name: "keyring", run: (*quickstart).ensureKeyring},
+	{name: "fund", run: (*quickstart).fundWallet},
+	{name: "maturity", run: (*quickstart).waitForMaturity},
+	{name: "provider", run: (*quickstart).pickFinalityProvider},
+	{name: "stake", run: (*quickstart).stake},
+	{name: "confirm", run: (*quickstart).mineUntilConfirmed},
+	{name: "delegate", run: (*quickstart).waitForDelegation},
+}
+
+func quickstartStepNames() string {
+	names := ""
+	for i, step := range quickstartSteps {
+		if i > 0 {
+			names += ", "
+		}
+		names += step.name
+	}
+	return names
+}
+
+func quickstartStepIndex(name string) (int, error) {
+	for i, step := range quickstartSteps {
+		if step.name == name {
+			return i, nil
+		}
+	}
+
+	return 0, fmt.Errorf("unknown quickstart step %q, expected one of: %s", name, quickstartStepNames())
+}
+
+// checkQuickstartNetwork refuses to run anywhere but on regtest, as the flow mines
+// blocks and stakes real funds otherwise
+func checkQuickstartNetwork(params *chaincfg.Params) error {
+	if params.Name == chaincfg.MainNetParams.Name {
+		return fmt.Errorf("refusing to run quickstart on mainnet")
+	}
+
+	if params.Name != chaincfg.RegressionNetParams.Name {
+		return fmt.Errorf("quickstart only runs on regtest, configured network is %s", params.Name)
+	}
+
+	return nil
+}
+
+type quickstart struct {
+	cfg           *scfg.Config
+	daemonAddress string
+	amount        int64
+	stakingTime   int64
+	timeout       time.Duration
+	statePath     string
+	state         quickstartState
+
+	wallet *walletcontroller.RpcWalletController
+	node   *rpcclient.Client
+	daemon *dc.StakerServiceJsonRpcClient
+}
+
+func loadStakerdConfig(configFile string) (*scfg.Config, error) {
+	cfg := scfg.DefaultConfig()
+	fileParser := flags.NewParser(&cfg, flags.Default)
+
+	if err := flags.NewIniParser(fileParser).ParseFile(scfg.CleanAndExpandPath(configFile)); err != nil {
+		return nil, fmt.Errorf("failed to read stakerd config %s: %w", configFile, err)
+	}
+
+	return scfg.ValidateConfig(cfg)
+}
+
+func runQuickstart(ctx *cli.Context) error {
+	if !ctx.Bool(regtestFlag) {
+		return cli.NewExitError(fmt.Sprintf("quickstart only supports regtest, run it with --%s", regtestFlag), 1)
+	}
+
+	fromStep, err := quickstartStepIndex(ctx.String(fromStepFlag))
+	if err != nil {
+		return cli.NewExitError(err.Error(), 1)
+	}
+
+	cfg, err := loadStakerdConfig(ctx.String(configFileFlag))
+	if err != nil {
+		return cli.NewExitError(err.Error(), 1)
+	}
+
+	if err := checkQuickstartNetwork(&cfg.ActiveNetParams); err != nil {
+		return cli.NewExitError(err.Error(), 1)
+	}
+
+	if cfg.BtcNodeBackendConfig.ActiveNodeBackend != types.BitcoindNodeBackend {
+		return cli.NewExitError("quickstart requires bitcoind node backend to mine blocks", 1)
+	}
+
+	q := &quickstart{
+		cfg:           cfg,
+		daemonAddress: ctx.String(stakingDaemonAddressFlag),
+		amount:        ctx.Int64(amountFlag),
+		stakingTime:   ctx.Int64(stakingTimeFlag),
+		timeout:       ctx.Duration(timeoutFlag),
+		statePath:     filepath.Join(cfg.StakerdDir, quickstartStateFileName),
+	}
+
+	if fromStep > 0 {
+		if err := q.loadState(); err != nil {
+			return cli.NewExitError(fmt.Sprintf("cannot resume from step %s: %s", quickstartSteps[fromStep].name, err), 1)
+		}
+	}
+
+	if err := q.connectBtc(); err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "STEP\tDURATION\tRESULT")
+
+	for i, step := range quickstartSteps {
+		if i < fromStep {
+			fmt.Fprintf(w, "%s\t-\tskipped\n", step.name)
+			continue
+		}
+
+		fmt.Printf("Running step %s...\n", step.name)
+
+		start := time.Now()
+		result, err := step.run(q)
+		duration := time.Since(start).Round(time.Millisecond)
+
+		if err != nil {
+			fmt.Fprintf(w, "%s\t%s\tfailed: %s\n", step.name, duration, err)
+			_ = w.Flush()
+			return cli.NewExitError(
+				fmt.Sprintf("step %s failed. Fix the problem and resume with --%s %s", step.name, fromStepFlag, step.name),
+				1,
+			)
+		}
+
+		if err := q.saveState(); err != nil {
+			return err
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\n", step.name, duration, result)
+	}
+
+	fmt.Println()
+	return w.Flush()
+}
+
+func (q *quickstart) loadState() error {
+	stateBytes, err := os.ReadFile(q.statePath)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(stateBytes, &q.state)
+}
+
+func (q *quickstart) saveState() error {
+	stateBytes, err := json.MarshalIndent(q.state, "", "    ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(q.statePath, stateBytes, 0600)
+}
+
+func (q *quickstart) connectBtc() error {
+	wallet, err := walletcontroller.NewRpcWalletController(q.cfg)
+	if err != nil {
+		return err
+	}
+
+	bitcoind := q.cfg.BtcNodeBackendConfig.Bitcoind
+
+	node, err := rpcclient.New(&rpcclient.ConnConfig{
+		Host:         bitcoind.RPCHost,
+		User:         bitcoind.RPCUser,
+		Pass:         bitcoind.RPCPass,
+		DisableTLS:   true,
+		HTTPPostMode: true,
+	}, nil)
+	if err != nil {
+		return err
+	}
+
+	q.wallet = wallet
+	q.node = node
+	return nil
+}
+
+func (q *quickstart) connectDaemon() error {
+	if q.daemon != nil {
+		return nil
+	}
+
+	client, err := dc.NewStakerServiceJsonRpcClient(q.daemonAddress)
+	if err != nil {
+		return err
+	}
+
+	if _, err := client.Health(context.Background()); err != nil {
+		return fmt.Errorf("staker daemon at %s is not reachable, start stakerd with the same config: %w", q.daemonAddress, err)
+	}
+
+	q.daemon = client
+	return nil
+}
+
+// poll calls check until it returns true or timeout passes. If mine is set, block
+// is mined before every check.
+func (q *quickstart) poll(mine bool, check func() (bool, error)) error {
+	deadline := time.Now().Add(q.timeout)
+
+	for {
+		if mine {
+			if _, err := q.mine(1); err != nil {
+				return err
+			}
+		}
+
+		done, err := check()
+		if err != nil {
+			return err
+		}
+
+		if done {
+			return nil
+		}
+
+		if time.Now().After(deadline) {
+			return fmt.Errorf("timed out after %s", q.timeout)
+		}
+
+		time.Sleep(quickstartPollInterval)
+	}
+}
+
+func (q *quickstart) stakerAddress() (btcutil.Address, error) {
+	if q.state.StakerAddress == "" {
+		return nil, fmt.Errorf("staker address is not known, run step fund first")
+	}
+
+	return btcutil.DecodeAddress(q.state.StakerAddress, &q.cfg.ActiveNetParams)
+}
+
+func (q *quickstart) mine(numBlocks int64) (string, error) {
+	address, err := q.stakerAddress()
+	if err != nil {
+		return "", err
+	}
+
+	hashes, err := q.node.GenerateToAddress(numBlocks, address, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to mine blocks: %w", err)
+	}
+
+	return hashes[len(hashes)-1].String(), nil
+}
+
+func (q *quickstart) ensureKeyring() (string, error) {
+	bbnCfg := q.cfg.BabylonConfig
+
+	kb, err := newKeyring(bbnCfg.ChainID, bbnCfg.KeyringBackend, bbnCfg.KeyDirectory)
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := kb.Key(bbnCfg.Key); err == nil {
+		return fmt.Sprintf("key %s already exists in %s", bbnCfg.Key, bbnCfg.KeyDirectory), nil
+	}
+
+	if _, err := createKey(bbnCfg.Key, kb); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("created key %s in %s", bbnCfg.Key, bbnCfg.KeyDirectory), nil
+}
+
+func (q *quickstart) fundWallet() (string, error) {
+	// addresses returned by rpc client are decoded with mainnet params, so the
+	// address is requested as raw string
+	params, err := rawParams("", "bech32")
+	if err != nil {
+		return "", err
+	}
+
+	result, err := q.wallet.RawRequest("getnewaddress", params)
+	if err != nil {
+		return "", fmt.Errorf("failed to get new wallet address: %w", err)
+	}
+
+	var address string
+	if err := json.Unmarshal(result, &address); err != nil {
+		return "", err
+	}
+
+	q.state.StakerAddress = address
+
+	blockHash, err := q.mine(1)
+	if err != nil {
+		return "", err
+	}
+
+	q.state.FundingBlockHash = blockHash
+
+	return fmt.Sprintf("mined block %s to address %s", blockHash, address), nil
+}
+
+func (q *quickstart) waitForMaturity() (string, error) {
+	if _, err := q.mine(int64(q.cfg.ActiveNetParams.CoinbaseMaturity)); err != nil {
+		return "", err
+	}
+
+	var balance btcutil.Amount
+
+	err := q.poll(false, func() (bool, error) {
+		outputs, err := q.wallet.ListOutputs(true)
+		if err != nil {
+			return false, err
+		}
+
+		balance = 0
+		for _, output := range outputs {
+			if output.Address == q.state.StakerAddress {
+				balance += output.Amount
+			}
+		}
+
+		return balance > 0, nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("address %s has spendable balance %s", q.state.StakerAddress, balance), nil
+}
+
+func (q *quickstart) pickFinalityProvider() (string, error) {
+	if err := q.connectDaemon(); err != nil {
+		return "", err
+	}
+
+	limit := 1
+	resp, err := q.daemon.BabylonFinalityProviders(context.Background(), nil, &limit)
+	if err != nil {
+		return "", err
+	}
+
+	if len(resp.FinalityProviders) == 0 {
+		return "", fmt.Errorf("there are no finality providers registered on babylon")
+	}
+
+	fp := resp.FinalityProviders[0]
+	q.state.FinalityProviderPk = fp.BtcPublicKey
+
+	return fmt.Sprintf("finality provider %s %s", fp.BtcPublicKey, fp.Moniker), nil
+}
+
+func (q *quickstart) stake() (string, error) {
+	if q.state.FinalityProviderPk == "" {
+		return "", fmt.Errorf("finality provider is not known, run step provider first")
+	}
+
+	if err := q.connectDaemon(); err != nil {
+		return "", err
+	}
+
+	stakingTime := q.stakingTime
+
+	if stakingTime == 0 {
+		params, err := q.daemon.StakingParams(context.Background())
+		if err != nil {
+			return "", err
+		}
+
+		stakingTime, err = strconv.ParseInt(params.MinStakingTimeBlocks, 10, 64)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	resp, err := q.daemon.Stake(
+		context.Background(),
+		q.state.StakerAddress,
+		q.amount,
+		[]string{q.state.FinalityProviderPk},
+		stakingTime,
+		nil,
+		nil,
+		nil,
+		nil,
+		false,
+		false,
+		false,
+	)
+	if err != nil {
+		return "", err
+	}
+
+	q.state.StakingTxHash = resp.TxHash
+
+	return fmt.Sprintf("staking transaction %s, %d satoshis for %d blocks", resp.TxHash, q.amount, stakingTime), nil
+}
+
+// waitForState mines blocks until staking transaction reaches one of given states
+func (q *quickstart) waitForState(states ...proto.TransactionState) (string, error) {
+	if q.state.StakingTxHash == "" {
+		return "", fmt.Errorf("staking transaction is not known, run step stake first")
+	}
+
+	if err := q.connectDaemon(); err != nil {
+		return "", err
+	}
+
+	var currentState string
+
+	err := q.poll(true, func() (bool, error) {
+		details, err := q.daemon.StakingDetails(context.Background(), q.state.StakingTxHash)
+		if err != nil {
+			return false, err
+		}
+
+		currentState = details.StakingState
+
+		for _, state := range states {
+			if currentState == state.String() {
+				return true, nil
+			}
+		}
+
+		return false, nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("staking transaction %s in state %s: %w", q.state.StakingTxHash, currentState, err)
+	}
+
+	return fmt.Sprintf("staking transaction %s in state %s", q.state.StakingTxHash, currentState), nil
+}
+
+func (q *quickstart) mineUntilConfirmed() (string, error) {
+	return q.waitForState(
+		proto.TransactionState_CONFIRMED_ON_BTC,
+		proto.TransactionState_SENT_TO_BABYLON,
+		proto.TransactionState_DELEGATION_ACTIVE,
+	)
+}
+
+func (q *quickstart) waitForDelegation() (string, error) {
+	return q.waitForState(
+		proto.TransactionState_SENT_TO_BABYLON,
+		proto.TransactionState_DELEGATION_ACTIVE,
+	)
+}
+
+func rawParams(params ...interface{}) ([]json.RawMessage, error) {
+	rawParams := make([]json.RawMessage, 0, len(params))
+
+	for _, param := range params {
+		marshalled, err := json.Marshal(param)
+		if err != nil {
+			return nil, err
+		}
+		rawParams = append(rawParams, marshalled)
+	}
+
+	return rawParams, nil
+}
@@ -0,0 +1,26 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuickstartRunsOnlyOnRegtest(t *testing.T) {
+	require.EqualError(t, checkQuickstartNetwork(&chaincfg.MainNetParams), "refusing to run quickstart on mainnet")
+	require.Error(t, checkQuickstartNetwork(&chaincfg.TestNet3Params))
+	require.Error(t, checkQuickstartNetwork(&chaincfg.SigNetParams))
+	require.NoError(t, checkQuickstartNetwork(&chaincfg.RegressionNetParams))
+}
+
+func TestQuickstartStepIndex(t *testing.T) {
+	for i, step := range quickstartSteps {
+		idx, err := quickstartStepIndex(step.name)
+		require.NoError(t, err)
+		require.Equal(t, i, idx)
+	}
+
+	_, err := quickstartStepIndex("unknown")
+	require.Error(t, err)
+}
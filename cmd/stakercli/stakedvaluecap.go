@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+
+	dc "github.com/babylonchain/btc-staker/stakerservice/client"
+	"github.com/urfave/cli"
+)
+
+const (
+	maxStakedValueFlag = "max-value"
+	windowBlocksFlag   = "window-blocks"
+)
+
+var stakedValueCapCmd = cli.Command{
+	Name:  "staked-value-cap",
+	Usage: "Show or override cap of value staked within rolling window of btc blocks.",
+	Subcommands: []cli.Command{
+		stakedValueCapShowCmd,
+		stakedValueCapOverrideCmd,
+	},
+}
+
+var stakedValueCapShowCmd = cli.Command{
+	Name:  "show",
+	Usage: "Show staked value cap, value staked within the window and number of rejected stakes",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  stakingDaemonAddressFlag,
+			Usage: "Full address of the staker daemon in format tcp:://<host>:<port>",
+			Value: defaultStakingDaemonAddress,
+		},
+	},
+	Action: stakedValueCapShow,
+}
+
+var stakedValueCapOverrideCmd = cli.Command{
+	Name: "override",
+	Usage: "Replace configured staked value cap until daemon restarts. Daemon only accepts override over unix socket " +
+		"or connection authenticated with rpc credentials",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  stakingDaemonAddressFlag,
+			Usage: "Full address of the staker daemon in format tcp:://<host>:<port>",
+			Value: defaultStakingDaemonAddress,
+		},
+		cli.Int64Flag{
+			Name:     maxStakedValueFlag,
+			Usage:    "Maximum value of staking transactions sent within the window in satoshis, 0 disables the cap",
+			Required: true,
+		},
+		cli.Uint64Flag{
+			Name:     windowBlocksFlag,
+			Usage:    "Number of btc blocks in rolling window",
+			Required: true,
+		},
+	},
+	Action: stakedValueCapOverride,
+}
+
+func stakedValueCapShow(ctx *cli.Context) error {
+	daemonAddress := ctx.String(stakingDaemonAddressFlag)
+	client, err := dc.NewStakerServiceJsonRpcClient(daemonAddress)
+	if err != nil {
+		return err
+	}
+
+	sctx := context.Background()
+
+	result, err := client.StakedValueCap(sctx)
+
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(result)
+
+	return nil
+}
+
+func stakedValueCapOverride(ctx *cli.Context) error {
+	daemonAddress := ctx.String(stakingDaemonAddressFlag)
+	client, err := dc.NewStakerServiceJsonRpcClient(daemonAddress)
+	if err != nil {
+		return err
+	}
+
+	sctx := context.Background()
+
+	result, err := client.OverrideStakedValueCap(
+		sctx,
+		ctx.Int64(maxStakedValueFlag),
+		uint32(ctx.Uint64(windowBlocksFlag)),
+	)
+
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(result)
+
+	return nil
+}
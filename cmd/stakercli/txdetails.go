@@ -0,0 +1,195 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/babylonchain/btc-staker/staker"
+	scfg "github.com/babylonchain/btc-staker/stakercfg"
+	"github.com/babylonchain/btc-staker/stakerdb"
+	service "github.com/babylonchain/btc-staker/stakerservice"
+	dc "github.com/babylonchain/btc-staker/stakerservice/client"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/urfave/cli"
+)
+
+const (
+	jsonFlag = "json"
+	dbFlag   = "db"
+
+	// how long offline mode waits for database lock held by running daemon
+	offlineDbTimeout = 2 * time.Second
+)
+
+var txDetailsCmd = cli.Command{
+	Name:      "tx-details",
+	ShortName: "txd",
+	Usage:     "Displays everything known about single staking transaction",
+	ArgsUsage: "<staking-tx-hash>",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  stakingDaemonAddressFlag,
+			Usage: "full address of the staker daemon in format tcp:://<host>:<port>",
+			Value: defaultStakingDaemonAddress,
+		},
+		cli.BoolFlag{
+			Name:  jsonFlag,
+			Usage: "Print details as json instead of table",
+		},
+		cli.StringFlag{
+			Name:  dbFlag,
+			Usage: "Path to staker database file. If set, details are read directly from the database instead of the daemon, which must be stopped. Live chain data is not available in this mode",
+		},
+	},
+	Action: txDetails,
+}
+
+func txDetails(ctx *cli.Context) error {
+	stakingTxHash := ctx.Args().First()
+
+	if stakingTxHash == "" {
+		return errors.New("staking transaction hash is required")
+	}
+
+	var (
+		details *service.TransactionDetailsResponse
+		err     error
+	)
+
+	if ctx.IsSet(dbFlag) {
+		details, err = offlineTransactionDetails(ctx.String(dbFlag), stakingTxHash)
+	} else {
+		details, err = daemonTransactionDetails(ctx.String(stakingDaemonAddressFlag), stakingTxHash)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if ctx.Bool(jsonFlag) {
+		printRespJSON(details)
+		return nil
+	}
+
+	return printTransactionDetailsTable(details)
+}
+
+func daemonTransactionDetails(daemonAddress, stakingTxHash string) (*service.TransactionDetailsResponse, error) {
+	client, err := dc.NewStakerServiceJsonRpcClient(daemonAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	return client.TransactionDetails(context.Background(), stakingTxHash)
+}
+
+func offlineTransactionDetails(dbPath, stakingTxHash string) (*service.TransactionDetailsResponse, error) {
+	txHash, err := chainhash.NewHashFromStr(stakingTxHash)
+	if err != nil {
+		return nil, err
+	}
+
+	// opening not existing database would create a new empty one
+	if _, err := os.Stat(dbPath); err != nil {
+		return nil, fmt.Errorf("cannot open staker database: %w", err)
+	}
+
+	dbConfig := scfg.DefaultDBConfig()
+	dbConfig.DBPath = filepath.Dir(dbPath)
+	dbConfig.DBFileName = filepath.Base(dbPath)
+	dbConfig.DBTimeout = offlineDbTimeout
+
+	backend, err := scfg.GetDbBackend(&dbConfig)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open staker database, make sure stakerd is stopped: %w", err)
+	}
+	defer backend.Close()
+
+	store, err := stakerdb.NewTrackedTransactionStore(backend)
+	if err != nil {
+		return nil, err
+	}
+
+	details, err := staker.StoredTransactionDetails(store, txHash)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.NewTransactionDetailsResponse(details), nil
+}
+
+func printTransactionDetailsTable(d *service.TransactionDetailsResponse) error {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+
+	row := func(name, value string) {
+		if value != "" {
+			fmt.Fprintf(w, "%s\t%s\n", name, value)
+		}
+	}
+
+	row("Staking tx hash", d.StakingTxHash)
+	row("State", d.StakingState)
+	row("Watched", fmt.Sprintf("%t", d.Watched))
+	row("Staker address", d.StakerAddress)
+	row("Staking value", d.StakingValue)
+	row("Staking output index", d.StakingOutputIdx)
+
+	if d.Change != nil {
+		row("Change", fmt.Sprintf("%s to %s (output %s)", d.Change.Amount, d.Change.Address, d.Change.OutputIdx))
+	}
+
+	for i, fp := range d.FinalityProviders {
+		provider := fp.BtcPk
+		if fp.Moniker != "" {
+			provider = fmt.Sprintf("%s (%s)", fp.BtcPk, fp.Moniker)
+		}
+
+		row(fmt.Sprintf("Finality provider %d", i+1), provider)
+	}
+
+	if d.Confirmation != nil {
+		row("Confirmation block", fmt.Sprintf("%s (%s)", d.Confirmation.BlockHeight, d.Confirmation.BlockHash))
+	}
+
+	row("Babylon tx hash", d.BabylonTxHash)
+	row("Babylon memo", d.BabylonMemo)
+
+	if d.Unbonding != nil {
+		row("Unbonding tx hash", d.Unbonding.UnbondingTxHash)
+		row("Unbonding value", d.Unbonding.UnbondingValue)
+		row("Unbonding time blocks", d.Unbonding.UnbondingTimeBlocks)
+		row("Covenant signatures", d.Unbonding.CovenantSignatures)
+
+		if d.Unbonding.Confirmation != nil {
+			row("Unbonding confirmation block", fmt.Sprintf("%s (%s)", d.Unbonding.Confirmation.BlockHeight, d.Unbonding.Confirmation.BlockHash))
+		}
+	}
+
+	row("Withdrawable height", d.WithdrawableHeight)
+	row("Current btc height", d.CurrentBtcHeight)
+
+	if d.Withdrawable != nil {
+		row("Withdrawable", fmt.Sprintf("%t", *d.Withdrawable))
+	}
+
+	script := d.StakingScript
+	row("Staking script", script.PkScriptHex)
+	row("  Staker btc pk", script.StakerBtcPk)
+	row("  Finality provider pks", strings.Join(script.FinalityProviderPks, ", "))
+	row("  Staking time blocks", script.StakingTimeBlocks)
+	row("  Params version", script.ParamsVersion)
+	row("  Covenant pks", strings.Join(script.CovenantPks, ", "))
+	row("  Covenant quorum", script.CovenantQuorum)
+
+	if script.Verified != nil {
+		row("  Verified", fmt.Sprintf("%t", *script.Verified))
+	}
+
+	return w.Flush()
+}
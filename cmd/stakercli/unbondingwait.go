@@ -0,0 +1,81 @@
+package main
+
+import (
+	"context"
+
+	dc "github.com/babylonchain/btc-staker/stakerservice/client"
+	"github.com/urfave/cli"
+)
+
+var retryUnbondingSignaturesCommand = cli.Command{
+	Name:      "retry-unbonding-signatures",
+	ShortName: "rus",
+	Usage: "Resume frequent checks of covenant unbonding signatures of delegation in UNBONDING_SIGNATURES_TIMEOUT state," +
+		" or which waiting for signatures was aborted. Timeout is restarted. Requires staker daemon to be running.",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  stakingDaemonAddressFlag,
+			Usage: "full address of the staker daemon in format tcp:://<host>:<port>",
+			Value: defaultStakingDaemonAddress,
+		},
+		cli.StringFlag{
+			Name:     stakingTransactionHashFlag,
+			Usage:    "Hash of original staking transaction in bitcoin hex format",
+			Required: true,
+		},
+	},
+	Action: retryUnbondingSignatures,
+}
+
+var abortUnbondingCommand = cli.Command{
+	Name:      "abort-unbonding",
+	ShortName: "au",
+	Usage: "Stop waiting for covenant unbonding signatures of delegation in UNBONDING_SIGNATURES_TIMEOUT state." +
+		" Delegation is moved back to SENT_TO_BABYLON state and staked funds can be withdrawn once staking timelock expires." +
+		" Refused if unbonding transaction was sent to btc. Requires staker daemon to be running.",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  stakingDaemonAddressFlag,
+			Usage: "full address of the staker daemon in format tcp:://<host>:<port>",
+			Value: defaultStakingDaemonAddress,
+		},
+		cli.StringFlag{
+			Name:     stakingTransactionHashFlag,
+			Usage:    "Hash of original staking transaction in bitcoin hex format",
+			Required: true,
+		},
+	},
+	Action: abortUnbonding,
+}
+
+func retryUnbondingSignatures(ctx *cli.Context) error {
+	client, err := dc.NewStakerServiceJsonRpcClient(ctx.String(stakingDaemonAddressFlag))
+	if err != nil {
+		return err
+	}
+
+	result, err := client.RetryUnbondingSignatures(context.Background(), ctx.String(stakingTransactionHashFlag))
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(result)
+
+	return nil
+}
+
+func abortUnbonding(ctx *cli.Context) error {
+	client, err := dc.NewStakerServiceJsonRpcClient(ctx.String(stakingDaemonAddressFlag))
+	if err != nil {
+		return err
+	}
+
+	result, err := client.AbortUnbonding(context.Background(), ctx.String(stakingTransactionHashFlag))
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(result)
+
+	return nil
+}
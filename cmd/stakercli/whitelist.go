@@ -0,0 +1,131 @@
+package main
+
+import (
+	"context"
+
+	dc "github.com/babylonchain/btc-staker/stakerservice/client"
+	"github.com/urfave/cli"
+)
+
+const (
+	whitelistAddressFlag = "address"
+)
+
+var whitelistCmd = cli.Command{
+	Name:  "whitelist",
+	Usage: "Manage addresses to which staked funds can be spent, in addition to staker address.",
+	Subcommands: []cli.Command{
+		whitelistListCmd,
+		whitelistAddCmd,
+		whitelistRemoveCmd,
+	},
+}
+
+var whitelistListCmd = cli.Command{
+	Name:  "list",
+	Usage: "List whitelisted spend destinations from config and database",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  stakingDaemonAddressFlag,
+			Usage: "Full address of the staker daemon in format tcp:://<host>:<port>",
+			Value: defaultStakingDaemonAddress,
+		},
+	},
+	Action: whitelistList,
+}
+
+var whitelistAddCmd = cli.Command{
+	Name:  "add",
+	Usage: "Add spend destination to whitelist stored in database",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  stakingDaemonAddressFlag,
+			Usage: "Full address of the staker daemon in format tcp:://<host>:<port>",
+			Value: defaultStakingDaemonAddress,
+		},
+		cli.StringFlag{
+			Name:     whitelistAddressFlag,
+			Usage:    "BTC address to whitelist",
+			Required: true,
+		},
+	},
+	Action: whitelistAdd,
+}
+
+var whitelistRemoveCmd = cli.Command{
+	Name:  "remove",
+	Usage: "Remove spend destination from whitelist stored in database. Addresses from config can only be removed by changing config",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  stakingDaemonAddressFlag,
+			Usage: "Full address of the staker daemon in format tcp:://<host>:<port>",
+			Value: defaultStakingDaemonAddress,
+		},
+		cli.StringFlag{
+			Name:     whitelistAddressFlag,
+			Usage:    "BTC address to remove from whitelist",
+			Required: true,
+		},
+	},
+	Action: whitelistRemove,
+}
+
+func whitelistList(ctx *cli.Context) error {
+	daemonAddress := ctx.String(stakingDaemonAddressFlag)
+	client, err := dc.NewStakerServiceJsonRpcClient(daemonAddress)
+	if err != nil {
+		return err
+	}
+
+	sctx := context.Background()
+
+	result, err := client.SpendWhitelist(sctx)
+
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(result)
+
+	return nil
+}
+
+func whitelistAdd(ctx *cli.Context) error {
+	daemonAddress := ctx.String(stakingDaemonAddressFlag)
+	client, err := dc.NewStakerServiceJsonRpcClient(daemonAddress)
+	if err != nil {
+		return err
+	}
+
+	sctx := context.Background()
+
+	result, err := client.AddWhitelistedAddress(sctx, ctx.String(whitelistAddressFlag))
+
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(result)
+
+	return nil
+}
+
+func whitelistRemove(ctx *cli.Context) error {
+	daemonAddress := ctx.String(stakingDaemonAddressFlag)
+	client, err := dc.NewStakerServiceJsonRpcClient(daemonAddress)
+	if err != nil {
+		return err
+	}
+
+	sctx := context.Background()
+
+	result, err := client.RemoveWhitelistedAddress(sctx, ctx.String(whitelistAddressFlag))
+
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(result)
+
+	return nil
+}
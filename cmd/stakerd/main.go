@@ -4,7 +4,9 @@ import (
 	"fmt"
 	"net/http"
 	"os"
+	ossignal "os/signal"
 	"runtime/pprof"
+	"syscall"
 
 	staker "github.com/babylonchain/btc-staker/staker"
 	scfg "github.com/babylonchain/btc-staker/stakercfg"
@@ -12,8 +14,64 @@ import (
 
 	"github.com/jessevdk/go-flags"
 	"github.com/lightningnetwork/lnd/signal"
+	"github.com/sirupsen/logrus"
 )
 
+const (
+	// exit code of errors which are not startup errors
+	genericErrorExitCode = 1
+	// exit code used when second signal interrupts graceful shutdown
+	forcedShutdownExitCode = 20
+)
+
+// exit codes of startup errors, so that process supervisor can distinguish
+// transient failures from failures which require operator attention
+var startupErrorExitCodes = map[staker.StartupErrorCategory]int{
+	staker.NodeBackendUnavailable: 10,
+	staker.BabylonUnavailable:     11,
+	staker.WalletUnavailable:      12,
+	staker.DatabaseCorrupt:        13,
+	staker.ConfigInvalid:          14,
+	staker.RecoveryFailed:         15,
+}
+
+// exitWithError logs startup errors as single structured entry and exits with
+// exit code of their category
+func exitWithError(logger *logrus.Logger, err error) {
+	category, ok := staker.StartupErrorCategoryOf(err)
+
+	if !ok {
+		_, _ = fmt.Fprintln(os.Stderr, err)
+		os.Exit(genericErrorExitCode)
+	}
+
+	exitCode := startupErrorExitCodes[category]
+
+	logger.WithFields(logrus.Fields{
+		"category": category.String(),
+		"exitCode": exitCode,
+		"detail":   err.Error(),
+	}).Error("startup_failed")
+
+	os.Exit(exitCode)
+}
+
+// forceExitOnSecondSignal exits immediately if signal is received while staker
+// is already shutting down gracefully. First signal is handled by shutdown
+// interceptor.
+func forceExitOnSecondSignal() {
+	signals := make(chan os.Signal, 2)
+	ossignal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+
+	go func() {
+		<-signals
+		sig := <-signals
+
+		_, _ = fmt.Fprintf(os.Stderr, "Received %v during graceful shutdown, exiting immediately\n", sig)
+		os.Exit(forcedShutdownExitCode)
+	}()
+}
+
 func main() {
 	// Hook interceptor for os signals.
 	shutdownInterceptor, err := signal.Intercept()
@@ -22,6 +80,8 @@ func main() {
 		os.Exit(1)
 	}
 
+	forceExitOnSecondSignal()
+
 	cfg, cfgLogger, zapLogger, err := scfg.LoadConfig()
 
 	if err != nil {
@@ -29,7 +89,7 @@ func main() {
 			// Print error if not due to help request.
 			err = fmt.Errorf("failed to load config: %w", err)
 			_, _ = fmt.Fprintln(os.Stderr, err)
-			os.Exit(1)
+			os.Exit(startupErrorExitCodes[staker.ConfigInvalid])
 		}
 
 		// Help was requested, exit normally.
@@ -63,22 +123,19 @@ func main() {
 	dbBackend, err := scfg.GetDbBackend(cfg.DBConfig)
 
 	if err != nil {
-		err = fmt.Errorf("failed to load db backend: %w", err)
-		_, _ = fmt.Fprintln(os.Stderr, err)
-		os.Exit(1)
+		exitWithError(cfgLogger, staker.NewStartupError(staker.DatabaseCorrupt, fmt.Errorf("failed to load db backend: %w", err)))
 	}
 
 	// TODO: consider moving this to stakerservice
-	staker, err := staker.NewStakerAppFromConfig(cfg, cfgLogger, zapLogger, dbBackend)
+	stakerApp, err := staker.NewStakerAppFromConfig(cfg, cfgLogger, zapLogger, dbBackend)
 
 	if err != nil {
-		cfgLogger.Errorf("failed to create staker app: %v", err)
-		os.Exit(1)
+		exitWithError(cfgLogger, fmt.Errorf("failed to create staker app: %w", err))
 	}
 
 	service := service.NewStakerService(
 		cfg,
-		staker,
+		stakerApp,
 		cfgLogger,
 		shutdownInterceptor,
 		dbBackend,
@@ -86,7 +143,6 @@ func main() {
 
 	err = service.RunUntilShutdown()
 	if err != nil {
-		_, _ = fmt.Fprintln(os.Stderr, err)
-		os.Exit(1)
+		exitWithError(cfgLogger, err)
 	}
 }
@@ -39,6 +39,7 @@ import (
 	"github.com/btcsuite/btcd/chaincfg"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/btcsuite/btcd/integration/rpctest"
+	"github.com/btcsuite/btcd/mempool"
 	"github.com/btcsuite/btcd/rpcclient"
 	"github.com/btcsuite/btcd/txscript"
 	"github.com/btcsuite/btcd/wire"
@@ -327,7 +328,7 @@ func StartManager(
 	stakerApp, err := staker.NewStakerAppFromConfig(cfg, logger, zapLogger, dbbackend)
 	require.NoError(t, err)
 	// we require separate client to send BTC headers to babylon node (interface does not need this method?)
-	bl, err := babylonclient.NewBabylonController(cfg.BabylonConfig, &cfg.ActiveNetParams, logger, zapLogger)
+	bl, err := babylonclient.NewBabylonController(cfg.BabylonConfig, &cfg.ActiveNetParams, nil, logger, zapLogger)
 	require.NoError(t, err)
 
 	initBtcWalletClient(
@@ -457,7 +458,7 @@ func ImportWalletSpendingKey(
 	wifKey, err := btcutil.NewWIF(privKey, simnetParams, true)
 	require.NoError(t, err)
 
-	err = walletClient.UnlockWallet(int64(3))
+	err = walletClient.UnlockWallet(context.Background(), int64(3))
 
 	if err != nil {
 		return err
@@ -527,7 +528,7 @@ func GetAllMinedBtcHeadersSinceGenesis(t *testing.T, h *rpctest.Harness) []*wire
 }
 
 func (tm *TestManager) createAndRegisterFinalityProvider(t *testing.T, testStakingData *testStakingData) {
-	resp, err := tm.BabylonClient.QueryFinalityProviders(100, 0)
+	resp, err := tm.BabylonClient.QueryFinalityProviders(100, 0, nil)
 	require.NoError(t, err)
 	// No providers yet
 	require.Len(t, resp.FinalityProviders, 0)
@@ -554,7 +555,7 @@ func (tm *TestManager) createAndRegisterFinalityProvider(t *testing.T, testStaki
 		pop,
 	)
 
-	resp, err = tm.BabylonClient.QueryFinalityProviders(100, 0)
+	resp, err = tm.BabylonClient.QueryFinalityProviders(100, 0, nil)
 	require.NoError(t, err)
 	// After registration we should have one finality provider
 	require.Len(t, resp.FinalityProviders, 1)
@@ -588,6 +589,13 @@ func (tm *TestManager) sendStakingTx(t *testing.T, testStakingData *testStakingD
 		testStakingData.StakingAmount,
 		[]string{fpKey},
 		int64(testStakingData.StakingTime),
+		nil,
+		nil,
+		nil,
+		nil,
+		false,
+		false,
+		false,
 	)
 	require.NoError(t, err)
 	txHash := res.TxHash
@@ -624,6 +632,13 @@ func (tm *TestManager) sendMultipleStakingTx(t *testing.T, testStakingData []*te
 			data.StakingAmount,
 			[]string{fpKey},
 			int64(data.StakingTime),
+			nil,
+			nil,
+			nil,
+			nil,
+			false,
+			false,
+			false,
 		)
 		require.NoError(t, err)
 		txHash, err := chainhash.NewHashFromStr(res.TxHash)
@@ -667,10 +682,11 @@ func (tm *TestManager) sendWatchedStakingTx(
 
 	require.NoError(t, err)
 
-	err = tm.Sa.Wallet().UnlockWallet(20)
+	err = tm.Sa.Wallet().UnlockWallet(context.Background(), 20)
 	require.NoError(t, err)
 
 	tx, err := tm.Sa.Wallet().CreateAndSignTx(
+		context.Background(),
 		[]*wire.TxOut{stakingInfo.StakingOutput},
 		2000,
 		tm.MinerAddr,
@@ -800,6 +816,10 @@ func (tm *TestManager) sendWatchedStakingTx(
 		int(unbondingTme),
 		// Use schnor verification
 		int(btcstypes.BTCSigType_BIP340),
+		nil,
+		nil,
+		nil,
+		nil,
 	)
 	require.NoError(t, err)
 
@@ -812,7 +832,7 @@ func (tm *TestManager) sendWatchedStakingTx(
 }
 
 func (tm *TestManager) spendStakingTxWithHash(t *testing.T, stakingTxHash *chainhash.Hash) (*chainhash.Hash, *btcutil.Amount) {
-	res, err := tm.StakerClient.SpendStakingTransaction(context.Background(), stakingTxHash.String())
+	res, err := tm.StakerClient.SpendStakingTransaction(context.Background(), stakingTxHash.String(), nil, nil)
 	require.NoError(t, err)
 	spendTxHash, err := chainhash.NewHashFromStr(res.TxHash)
 	require.NoError(t, err)
@@ -1024,6 +1044,13 @@ func TestStakingFailures(t *testing.T) {
 		testStakingData.StakingAmount,
 		[]string{fpKey, fpKey},
 		int64(testStakingData.StakingTime),
+		nil,
+		nil,
+		nil,
+		nil,
+		false,
+		false,
+		false,
 	)
 	require.Error(t, err)
 
@@ -1034,10 +1061,68 @@ func TestStakingFailures(t *testing.T) {
 		testStakingData.StakingAmount,
 		[]string{},
 		int64(testStakingData.StakingTime),
+		nil,
+		nil,
+		nil,
+		nil,
+		false,
+		false,
+		false,
 	)
 	require.Error(t, err)
 }
 
+func TestFundTxEstimateMatchesSignedTx(t *testing.T) {
+	numMatureOutputs := uint32(200)
+	tm := StartManager(t, numMatureOutputs, 2, nil)
+	defer tm.Stop(t)
+
+	cl := tm.Sa.BabylonController()
+	params, err := cl.Params()
+	require.NoError(t, err)
+	stakingTime := uint16(staker.GetMinStakingTime(params))
+
+	testStakingData := tm.getTestStakingData(t, tm.WalletPrivKey.PubKey(), stakingTime, 10000)
+
+	stakingInfo, err := staking.BuildStakingInfo(
+		testStakingData.StakerKey,
+		[]*btcec.PublicKey{testStakingData.FinalityProviderBtcKey},
+		params.CovenantPks,
+		params.CovenantQuruomThreshold,
+		testStakingData.StakingTime,
+		btcutil.Amount(testStakingData.StakingAmount),
+		simnetParams,
+	)
+	require.NoError(t, err)
+
+	outputs := []*wire.TxOut{stakingInfo.StakingOutput}
+
+	// estimate does not need unlocked wallet
+	estimate, err := tm.Sa.Wallet().FundTxEstimate(outputs, 2000, tm.MinerAddr)
+	require.NoError(t, err)
+
+	err = tm.Sa.Wallet().UnlockWallet(context.Background(), 20)
+	require.NoError(t, err)
+
+	tx, err := tm.Sa.Wallet().CreateAndSignTx(context.Background(), outputs, 2000, tm.MinerAddr)
+	require.NoError(t, err)
+
+	require.Len(t, tx.TxIn, len(estimate.Inputs))
+
+	var inputsTotal, outputsTotal btcutil.Amount
+	for i, in := range tx.TxIn {
+		require.Equal(t, estimate.Inputs[i].OutPoint, in.PreviousOutPoint)
+		inputsTotal += estimate.Inputs[i].Amount
+	}
+
+	for _, out := range tx.TxOut {
+		outputsTotal += btcutil.Amount(out.Value)
+	}
+
+	require.Equal(t, estimate.Fee, inputsTotal-outputsTotal)
+	require.LessOrEqual(t, uint32(mempool.GetTxVirtualSize(btcutil.NewTx(tx))), estimate.MaxVsize)
+}
+
 func TestSendingStakingTransaction(t *testing.T) {
 	// need to have at least 300 block on testnet as only then segwit is activated.
 	// Mature output is out which has 100 confirmations, which means 200mature outputs
@@ -1106,7 +1191,7 @@ func TestSendingStakingTransaction(t *testing.T) {
 
 	offset := 0
 	limit := 10
-	transactionsResult, err := tm.StakerClient.ListStakingTransactions(context.Background(), &offset, &limit)
+	transactionsResult, err := tm.StakerClient.ListStakingTransactions(context.Background(), &offset, &limit, nil, nil, nil, nil)
 	require.NoError(t, err)
 	require.Len(t, transactionsResult.Transactions, 1)
 	require.Equal(t, transactionsResult.TotalTransactionCount, "1")
@@ -1301,7 +1386,7 @@ func TestStakingUnbonding(t *testing.T) {
 	tm.waitForStakingTxState(t, txHash, proto.TransactionState_DELEGATION_ACTIVE)
 
 	feeRate := 2000
-	resp, err := tm.StakerClient.UnbondStaking(context.Background(), txHash.String(), &feeRate)
+	resp, err := tm.StakerClient.UnbondStaking(context.Background(), txHash.String(), &feeRate, nil, nil)
 	require.NoError(t, err)
 
 	unbondingTxHash, err := chainhash.NewHashFromStr(resp.UnbondingTxHash)
@@ -1376,7 +1461,7 @@ func TestUnbondingRestartWaitingForSignatures(t *testing.T) {
 	tm.waitForStakingTxState(t, txHash, proto.TransactionState_DELEGATION_ACTIVE)
 
 	feeRate := 2000
-	unbondResponse, err := tm.StakerClient.UnbondStaking(context.Background(), txHash.String(), &feeRate)
+	unbondResponse, err := tm.StakerClient.UnbondStaking(context.Background(), txHash.String(), &feeRate, nil, nil)
 	require.NoError(t, err)
 	unbondingTxHash, err := chainhash.NewHashFromStr(unbondResponse.UnbondingTxHash)
 	require.NoError(t, err)
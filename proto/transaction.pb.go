@@ -29,25 +29,52 @@ const (
 	TransactionState_DELEGATION_ACTIVE          TransactionState = 3
 	TransactionState_UNBONDING_CONFIRMED_ON_BTC TransactionState = 4
 	TransactionState_SPENT_ON_BTC               TransactionState = 5
+	// staking transaction was confirmed on btc, but could not be found on btc
+	// chain during startup. Requires manual intervention.
+	TransactionState_MISSING_ON_BTC TransactionState = 6
+	// delegation was sent to babylon, but babylon reports it as unbonded before
+	// it became active locally e.g because its timelock passed. Staked funds can
+	// be withdrawn once staking timelock expires.
+	TransactionState_DELEGATION_EXPIRED TransactionState = 7
+	// delegation was sent to babylon, but covenant unbonding signatures were not
+	// received within configured timeout. Babylon is still polled, at slower
+	// rate, until signatures are received or waiting is aborted.
+	TransactionState_UNBONDING_SIGNATURES_TIMEOUT TransactionState = 8
+	// input of staking transaction was spent by other transaction before staking
+	// transaction was confirmed, so it can never be confirmed
+	TransactionState_CONFLICTED TransactionState = 9
+	// operator cancelled delegation before it was sent to babylon. Staked funds
+	// can only be withdrawn once staking timelock expires.
+	TransactionState_DELEGATION_CANCELLED TransactionState = 10
 )
 
 // Enum value maps for TransactionState.
 var (
 	TransactionState_name = map[int32]string{
-		0: "SENT_TO_BTC",
-		1: "CONFIRMED_ON_BTC",
-		2: "SENT_TO_BABYLON",
-		3: "DELEGATION_ACTIVE",
-		4: "UNBONDING_CONFIRMED_ON_BTC",
-		5: "SPENT_ON_BTC",
+		0:  "SENT_TO_BTC",
+		1:  "CONFIRMED_ON_BTC",
+		2:  "SENT_TO_BABYLON",
+		3:  "DELEGATION_ACTIVE",
+		4:  "UNBONDING_CONFIRMED_ON_BTC",
+		5:  "SPENT_ON_BTC",
+		6:  "MISSING_ON_BTC",
+		7:  "DELEGATION_EXPIRED",
+		8:  "UNBONDING_SIGNATURES_TIMEOUT",
+		9:  "CONFLICTED",
+		10: "DELEGATION_CANCELLED",
 	}
 	TransactionState_value = map[string]int32{
-		"SENT_TO_BTC":                0,
-		"CONFIRMED_ON_BTC":           1,
-		"SENT_TO_BABYLON":            2,
-		"DELEGATION_ACTIVE":          3,
-		"UNBONDING_CONFIRMED_ON_BTC": 4,
-		"SPENT_ON_BTC":               5,
+		"SENT_TO_BTC":                  0,
+		"CONFIRMED_ON_BTC":             1,
+		"SENT_TO_BABYLON":              2,
+		"DELEGATION_ACTIVE":            3,
+		"UNBONDING_CONFIRMED_ON_BTC":   4,
+		"SPENT_ON_BTC":                 5,
+		"MISSING_ON_BTC":               6,
+		"DELEGATION_EXPIRED":           7,
+		"UNBONDING_SIGNATURES_TIMEOUT": 8,
+		"CONFLICTED":                   9,
+		"DELEGATION_CANCELLED":         10,
 	}
 )
 
@@ -78,6 +105,211 @@ func (TransactionState) EnumDescriptor() ([]byte, []int) {
 	return file_transaction_proto_rawDescGZIP(), []int{0}
 }
 
+type DiscrepancyType int32
+
+const (
+	// delegation tracked locally is not found on babylon
+	DiscrepancyType_MISSING_ON_BABYLON DiscrepancyType = 0
+	// local state of the delegation does not match its status on babylon
+	DiscrepancyType_STATUS_MISMATCH DiscrepancyType = 1
+	// babylon has undelegation data which is not tracked locally
+	DiscrepancyType_UNDELEGATION_NOT_TRACKED DiscrepancyType = 2
+	// delegation sent to babylon expired before it became active locally, local
+	// state was moved to DELEGATION_EXPIRED
+	DiscrepancyType_DELEGATION_EXPIRED_ON_BABYLON DiscrepancyType = 3
+)
+
+// Enum value maps for DiscrepancyType.
+var (
+	DiscrepancyType_name = map[int32]string{
+		0: "MISSING_ON_BABYLON",
+		1: "STATUS_MISMATCH",
+		2: "UNDELEGATION_NOT_TRACKED",
+		3: "DELEGATION_EXPIRED_ON_BABYLON",
+	}
+	DiscrepancyType_value = map[string]int32{
+		"MISSING_ON_BABYLON":            0,
+		"STATUS_MISMATCH":               1,
+		"UNDELEGATION_NOT_TRACKED":      2,
+		"DELEGATION_EXPIRED_ON_BABYLON": 3,
+	}
+)
+
+func (x DiscrepancyType) Enum() *DiscrepancyType {
+	p := new(DiscrepancyType)
+	*p = x
+	return p
+}
+
+func (x DiscrepancyType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (DiscrepancyType) Descriptor() protoreflect.EnumDescriptor {
+	return file_transaction_proto_enumTypes[1].Descriptor()
+}
+
+func (DiscrepancyType) Type() protoreflect.EnumType {
+	return &file_transaction_proto_enumTypes[1]
+}
+
+func (x DiscrepancyType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use DiscrepancyType.Descriptor instead.
+func (DiscrepancyType) EnumDescriptor() ([]byte, []int) {
+	return file_transaction_proto_rawDescGZIP(), []int{1}
+}
+
+type StakingRequestStatus int32
+
+const (
+	// request is being processed i.e staking transaction is being created, signed
+	// and sent to btc
+	StakingRequestStatus_STAKING_REQUEST_PENDING StakingRequestStatus = 0
+	// staking transaction was sent to btc
+	StakingRequestStatus_STAKING_REQUEST_BROADCAST StakingRequestStatus = 1
+	StakingRequestStatus_STAKING_REQUEST_FAILED    StakingRequestStatus = 2
+)
+
+// Enum value maps for StakingRequestStatus.
+var (
+	StakingRequestStatus_name = map[int32]string{
+		0: "STAKING_REQUEST_PENDING",
+		1: "STAKING_REQUEST_BROADCAST",
+		2: "STAKING_REQUEST_FAILED",
+	}
+	StakingRequestStatus_value = map[string]int32{
+		"STAKING_REQUEST_PENDING":   0,
+		"STAKING_REQUEST_BROADCAST": 1,
+		"STAKING_REQUEST_FAILED":    2,
+	}
+)
+
+func (x StakingRequestStatus) Enum() *StakingRequestStatus {
+	p := new(StakingRequestStatus)
+	*p = x
+	return p
+}
+
+func (x StakingRequestStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (StakingRequestStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_transaction_proto_enumTypes[2].Descriptor()
+}
+
+func (StakingRequestStatus) Type() protoreflect.EnumType {
+	return &file_transaction_proto_enumTypes[2]
+}
+
+func (x StakingRequestStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use StakingRequestStatus.Descriptor instead.
+func (StakingRequestStatus) EnumDescriptor() ([]byte, []int) {
+	return file_transaction_proto_rawDescGZIP(), []int{2}
+}
+
+type AuditEntryType int32
+
+const (
+	// operation was requested, entry is written before operation is performed
+	AuditEntryType_AUDIT_OPERATION_REQUESTED AuditEntryType = 0
+	// operation finished, either successfully or with an error
+	AuditEntryType_AUDIT_OPERATION_FINISHED AuditEntryType = 1
+)
+
+// Enum value maps for AuditEntryType.
+var (
+	AuditEntryType_name = map[int32]string{
+		0: "AUDIT_OPERATION_REQUESTED",
+		1: "AUDIT_OPERATION_FINISHED",
+	}
+	AuditEntryType_value = map[string]int32{
+		"AUDIT_OPERATION_REQUESTED": 0,
+		"AUDIT_OPERATION_FINISHED":  1,
+	}
+)
+
+func (x AuditEntryType) Enum() *AuditEntryType {
+	p := new(AuditEntryType)
+	*p = x
+	return p
+}
+
+func (x AuditEntryType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (AuditEntryType) Descriptor() protoreflect.EnumDescriptor {
+	return file_transaction_proto_enumTypes[3].Descriptor()
+}
+
+func (AuditEntryType) Type() protoreflect.EnumType {
+	return &file_transaction_proto_enumTypes[3]
+}
+
+func (x AuditEntryType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use AuditEntryType.Descriptor instead.
+func (AuditEntryType) EnumDescriptor() ([]byte, []int) {
+	return file_transaction_proto_rawDescGZIP(), []int{3}
+}
+
+type EventIntentType int32
+
+const (
+	// covenant signatures over unbonding transaction were received from babylon
+	EventIntentType_EVENT_INTENT_UNBONDING_SIGNATURES_RECEIVED EventIntentType = 0
+	// unbonding transaction was confirmed on btc
+	EventIntentType_EVENT_INTENT_UNBONDING_CONFIRMED_ON_BTC EventIntentType = 1
+)
+
+// Enum value maps for EventIntentType.
+var (
+	EventIntentType_name = map[int32]string{
+		0: "EVENT_INTENT_UNBONDING_SIGNATURES_RECEIVED",
+		1: "EVENT_INTENT_UNBONDING_CONFIRMED_ON_BTC",
+	}
+	EventIntentType_value = map[string]int32{
+		"EVENT_INTENT_UNBONDING_SIGNATURES_RECEIVED": 0,
+		"EVENT_INTENT_UNBONDING_CONFIRMED_ON_BTC":    1,
+	}
+)
+
+func (x EventIntentType) Enum() *EventIntentType {
+	p := new(EventIntentType)
+	*p = x
+	return p
+}
+
+func (x EventIntentType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (EventIntentType) Descriptor() protoreflect.EnumDescriptor {
+	return file_transaction_proto_enumTypes[4].Descriptor()
+}
+
+func (EventIntentType) Type() protoreflect.EnumType {
+	return &file_transaction_proto_enumTypes[4]
+}
+
+func (x EventIntentType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use EventIntentType.Descriptor instead.
+func (EventIntentType) EnumDescriptor() ([]byte, []int) {
+	return file_transaction_proto_rawDescGZIP(), []int{4}
+}
+
 type WatchedTxData struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -94,6 +326,8 @@ type WatchedTxData struct {
 	SlashingUnbondingTransaction    []byte `protobuf:"bytes,6,opt,name=slashing_unbonding_transaction,json=slashingUnbondingTransaction,proto3" json:"slashing_unbonding_transaction,omitempty"`
 	SlashingUnbondingTransactionSig []byte `protobuf:"bytes,7,opt,name=slashing_unbonding_transaction_sig,json=slashingUnbondingTransactionSig,proto3" json:"slashing_unbonding_transaction_sig,omitempty"`
 	UnbondingTime                   uint32 `protobuf:"varint,8,opt,name=unbonding_time,json=unbondingTime,proto3" json:"unbonding_time,omitempty"`
+	// index of unbonding output in unbonding transaction
+	UnbondingOutputIdx uint32 `protobuf:"varint,9,opt,name=unbonding_output_idx,json=unbondingOutputIdx,proto3" json:"unbonding_output_idx,omitempty"`
 }
 
 func (x *WatchedTxData) Reset() {
@@ -184,6 +418,13 @@ func (x *WatchedTxData) GetUnbondingTime() uint32 {
 	return 0
 }
 
+func (x *WatchedTxData) GetUnbondingOutputIdx() uint32 {
+	if x != nil {
+		return x.UnbondingOutputIdx
+	}
+	return 0
+}
+
 // Contains information about btc confirmation
 type BTCConfirmationInfo struct {
 	state         protoimpl.MessageState
@@ -192,6 +433,8 @@ type BTCConfirmationInfo struct {
 
 	BlockHeight uint32 `protobuf:"varint,1,opt,name=block_height,json=blockHeight,proto3" json:"block_height,omitempty"`
 	BlockHash   []byte `protobuf:"bytes,2,opt,name=block_hash,json=blockHash,proto3" json:"block_hash,omitempty"`
+	// unix time from header of the inclusion block, 0 if it is not known
+	BlockTimestamp int64 `protobuf:"varint,3,opt,name=block_timestamp,json=blockTimestamp,proto3" json:"block_timestamp,omitempty"`
 }
 
 func (x *BTCConfirmationInfo) Reset() {
@@ -240,6 +483,13 @@ func (x *BTCConfirmationInfo) GetBlockHash() []byte {
 	return nil
 }
 
+func (x *BTCConfirmationInfo) GetBlockTimestamp() int64 {
+	if x != nil {
+		return x.BlockTimestamp
+	}
+	return 0
+}
+
 type CovenantSig struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -306,6 +556,12 @@ type UnbondingTxData struct {
 	// this data is only filed if tracked transactions state is >= UNBONDING_CONFIRMED_ON_BTC,
 	// and it is filled as last piece of information in unbonding process
 	UnbondingTxBtcConfirmationInfo *BTCConfirmationInfo `protobuf:"bytes,4,opt,name=unbonding_tx_btc_confirmation_info,json=unbondingTxBtcConfirmationInfo,proto3" json:"unbonding_tx_btc_confirmation_info,omitempty"`
+	// unix time when unbonding transaction was sent to btc, 0 if it was not sent
+	// or was sent before this was tracked
+	UnbondingTxSentAt int64 `protobuf:"varint,5,opt,name=unbonding_tx_sent_at,json=unbondingTxSentAt,proto3" json:"unbonding_tx_sent_at,omitempty"`
+	// index of unbonding output in unbonding transaction. Transactions stored
+	// before it was tracked always have unbonding output at index 0
+	UnbondingOutputIdx uint32 `protobuf:"varint,6,opt,name=unbonding_output_idx,json=unbondingOutputIdx,proto3" json:"unbonding_output_idx,omitempty"`
 }
 
 func (x *UnbondingTxData) Reset() {
@@ -368,6 +624,150 @@ func (x *UnbondingTxData) GetUnbondingTxBtcConfirmationInfo() *BTCConfirmationIn
 	return nil
 }
 
+func (x *UnbondingTxData) GetUnbondingTxSentAt() int64 {
+	if x != nil {
+		return x.UnbondingTxSentAt
+	}
+	return 0
+}
+
+func (x *UnbondingTxData) GetUnbondingOutputIdx() uint32 {
+	if x != nil {
+		return x.UnbondingOutputIdx
+	}
+	return 0
+}
+
+// Transaction spending staking or unbonding output, sent by staker
+type SpendTxData struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SpendTxHash []byte `protobuf:"bytes,1,opt,name=spend_tx_hash,json=spendTxHash,proto3" json:"spend_tx_hash,omitempty"`
+	// value of the spent output
+	SpentValue int64 `protobuf:"varint,2,opt,name=spent_value,json=spentValue,proto3" json:"spent_value,omitempty"`
+	Fee        int64 `protobuf:"varint,3,opt,name=fee,proto3" json:"fee,omitempty"`
+	// this data is only filled once spend transaction is confirmed
+	SpendTxBtcConfirmationInfo *BTCConfirmationInfo `protobuf:"bytes,4,opt,name=spend_tx_btc_confirmation_info,json=spendTxBtcConfirmationInfo,proto3" json:"spend_tx_btc_confirmation_info,omitempty"`
+}
+
+func (x *SpendTxData) Reset() {
+	*x = SpendTxData{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_transaction_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SpendTxData) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SpendTxData) ProtoMessage() {}
+
+func (x *SpendTxData) ProtoReflect() protoreflect.Message {
+	mi := &file_transaction_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SpendTxData.ProtoReflect.Descriptor instead.
+func (*SpendTxData) Descriptor() ([]byte, []int) {
+	return file_transaction_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *SpendTxData) GetSpendTxHash() []byte {
+	if x != nil {
+		return x.SpendTxHash
+	}
+	return nil
+}
+
+func (x *SpendTxData) GetSpentValue() int64 {
+	if x != nil {
+		return x.SpentValue
+	}
+	return 0
+}
+
+func (x *SpendTxData) GetFee() int64 {
+	if x != nil {
+		return x.Fee
+	}
+	return 0
+}
+
+func (x *SpendTxData) GetSpendTxBtcConfirmationInfo() *BTCConfirmationInfo {
+	if x != nil {
+		return x.SpendTxBtcConfirmationInfo
+	}
+	return nil
+}
+
+type StateTransition struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	State TransactionState `protobuf:"varint,1,opt,name=state,proto3,enum=proto.TransactionState" json:"state,omitempty"`
+	// unix time of the transition
+	ChangedAt int64 `protobuf:"varint,2,opt,name=changed_at,json=changedAt,proto3" json:"changed_at,omitempty"`
+}
+
+func (x *StateTransition) Reset() {
+	*x = StateTransition{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_transaction_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StateTransition) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StateTransition) ProtoMessage() {}
+
+func (x *StateTransition) ProtoReflect() protoreflect.Message {
+	mi := &file_transaction_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StateTransition.ProtoReflect.Descriptor instead.
+func (*StateTransition) Descriptor() ([]byte, []int) {
+	return file_transaction_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *StateTransition) GetState() TransactionState {
+	if x != nil {
+		return x.State
+	}
+	return TransactionState_SENT_TO_BTC
+}
+
+func (x *StateTransition) GetChangedAt() int64 {
+	if x != nil {
+		return x.ChangedAt
+	}
+	return 0
+}
+
 type TrackedTransaction struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -392,12 +792,76 @@ type TrackedTransaction struct {
 	Watched                      bool                 `protobuf:"varint,12,opt,name=watched,proto3" json:"watched,omitempty"`
 	// this data is only filled if tracked transactions state is >= SENT_TO_BABYLON
 	UnbondingTxData *UnbondingTxData `protobuf:"bytes,13,opt,name=unbonding_tx_data,json=unbondingTxData,proto3" json:"unbonding_tx_data,omitempty"`
+	// version of babylon staking params under which staking transaction was created.
+	// 0 means transaction was created before params versions were tracked
+	ParamsVersion uint32 `protobuf:"varint,14,opt,name=params_version,json=paramsVersion,proto3" json:"params_version,omitempty"`
+	// labels of transactions created by staker for this staking transaction
+	TxLabels []*TxLabel `protobuf:"bytes,15,rep,name=tx_labels,json=txLabels,proto3" json:"tx_labels,omitempty"`
+	// change output of staking transaction created by staker, empty for watched
+	// transactions and transactions without change
+	ChangeOutput *ChangeOutput `protobuf:"bytes,16,opt,name=change_output,json=changeOutput,proto3" json:"change_output,omitempty"`
+	// hash of babylon transaction which submitted the delegation, empty if
+	// delegation was sent before hashes were tracked or was found on babylon
+	// during startup
+	BabylonTxHash string `protobuf:"bytes,17,opt,name=babylon_tx_hash,json=babylonTxHash,proto3" json:"babylon_tx_hash,omitempty"`
+	// memo of babylon transaction which submits the delegation. Before delegation
+	// is sent it holds memo requested when staking, afterwards memo which was used
+	BabylonMemo string `protobuf:"bytes,18,opt,name=babylon_memo,json=babylonMemo,proto3" json:"babylon_memo,omitempty"`
+	// unix time of the last change of state, 0 if transaction was not changed
+	// since changes were tracked
+	StateChangedAt int64 `protobuf:"varint,19,opt,name=state_changed_at,json=stateChangedAt,proto3" json:"state_changed_at,omitempty"`
+	// states through which transaction went, in order. Transitions made before
+	// history was tracked are missing
+	StateHistory []*StateTransition `protobuf:"bytes,20,rep,name=state_history,json=stateHistory,proto3" json:"state_history,omitempty"`
+	// transaction sent by staker to spend staked funds, empty if funds were not
+	// spent or were spent before spend transactions were tracked
+	SpendTxData *SpendTxData `protobuf:"bytes,21,opt,name=spend_tx_data,json=spendTxData,proto3" json:"spend_tx_data,omitempty"`
+	// label assigned by user to organize delegations, empty if not labeled
+	Label string `protobuf:"bytes,22,opt,name=label,proto3" json:"label,omitempty"`
+	// depth on btc chain which staking transaction must reach before delegation
+	// is sent to babylon, 0 if transaction was stored before depth was tracked
+	RequiredDepth uint32 `protobuf:"varint,23,opt,name=required_depth,json=requiredDepth,proto3" json:"required_depth,omitempty"`
+	// proof of inclusion of staking transaction in btc block, empty if it was
+	// not recorded when transaction was confirmed
+	StakingTxInclusionProof *InclusionProof `protobuf:"bytes,24,opt,name=staking_tx_inclusion_proof,json=stakingTxInclusionProof,proto3" json:"staking_tx_inclusion_proof,omitempty"`
+	// reason why record was flagged as corrupted, empty if it is not corrupted.
+	// Staker refuses to spend staking output of corrupted transaction until the
+	// record is repaired
+	CorruptionReason string `protobuf:"bytes,25,opt,name=corruption_reason,json=corruptionReason,proto3" json:"corruption_reason,omitempty"`
+	// staking output is spent into new staking output to the same finality
+	// providers shortly before its timelock expires
+	AutoRenew bool `protobuf:"varint,26,opt,name=auto_renew,json=autoRenew,proto3" json:"auto_renew,omitempty"`
+	// staking transaction which renewed this one, empty if it was not renewed
+	RenewalTxHash []byte `protobuf:"bytes,27,opt,name=renewal_tx_hash,json=renewalTxHash,proto3" json:"renewal_tx_hash,omitempty"`
+	// staking transaction renewed by this one, empty if it is not a renewal
+	RenewedFromTxHash []byte `protobuf:"bytes,28,opt,name=renewed_from_tx_hash,json=renewedFromTxHash,proto3" json:"renewed_from_tx_hash,omitempty"`
+	// reason why automatic renewal was skipped, empty if it was not skipped
+	AutoRenewSkipReason string `protobuf:"bytes,29,opt,name=auto_renew_skip_reason,json=autoRenewSkipReason,proto3" json:"auto_renew_skip_reason,omitempty"`
+	// fee estimates used to build transactions of this staking transaction, empty
+	// if transaction was not built by staker or was built before estimates were
+	// recorded
+	StakingFeeEstimate   *FeeEstimate `protobuf:"bytes,30,opt,name=staking_fee_estimate,json=stakingFeeEstimate,proto3" json:"staking_fee_estimate,omitempty"`
+	UnbondingFeeEstimate *FeeEstimate `protobuf:"bytes,31,opt,name=unbonding_fee_estimate,json=unbondingFeeEstimate,proto3" json:"unbonding_fee_estimate,omitempty"`
+	SpendFeeEstimate     *FeeEstimate `protobuf:"bytes,32,opt,name=spend_fee_estimate,json=spendFeeEstimate,proto3" json:"spend_fee_estimate,omitempty"`
+	// waiting for covenant unbonding signatures was aborted by operator, so
+	// babylon is not polled for them. Staked funds can be withdrawn once staking
+	// timelock expires
+	UnbondingWaitAborted bool `protobuf:"varint,33,opt,name=unbonding_wait_aborted,json=unbondingWaitAborted,proto3" json:"unbonding_wait_aborted,omitempty"`
+	// transaction which spent input of staking transaction, set only in
+	// CONFLICTED state
+	ConflictingTxHash []byte `protobuf:"bytes,34,opt,name=conflicting_tx_hash,json=conflictingTxHash,proto3" json:"conflicting_tx_hash,omitempty"`
+	// best btc block height known to staker when it sent staking transaction, 0
+	// for watched transactions and transactions sent before it was recorded
+	BroadcastHeight uint32 `protobuf:"varint,35,opt,name=broadcast_height,json=broadcastHeight,proto3" json:"broadcast_height,omitempty"`
+	// last rejection of delegation by babylon, empty if delegation was not
+	// rejected or was submitted afterwards
+	BabylonSubmitFailure *BabylonSubmitFailure `protobuf:"bytes,36,opt,name=babylon_submit_failure,json=babylonSubmitFailure,proto3" json:"babylon_submit_failure,omitempty"`
 }
 
 func (x *TrackedTransaction) Reset() {
 	*x = TrackedTransaction{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_transaction_proto_msgTypes[4]
+		mi := &file_transaction_proto_msgTypes[6]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -410,7 +874,7 @@ func (x *TrackedTransaction) String() string {
 func (*TrackedTransaction) ProtoMessage() {}
 
 func (x *TrackedTransaction) ProtoReflect() protoreflect.Message {
-	mi := &file_transaction_proto_msgTypes[4]
+	mi := &file_transaction_proto_msgTypes[6]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -423,7 +887,7 @@ func (x *TrackedTransaction) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use TrackedTransaction.ProtoReflect.Descriptor instead.
 func (*TrackedTransaction) Descriptor() ([]byte, []int) {
-	return file_transaction_proto_rawDescGZIP(), []int{4}
+	return file_transaction_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *TrackedTransaction) GetTrackedTransactionIdx() uint64 {
@@ -517,128 +981,1727 @@ func (x *TrackedTransaction) GetUnbondingTxData() *UnbondingTxData {
 	return nil
 }
 
-var File_transaction_proto protoreflect.FileDescriptor
-
-var file_transaction_proto_rawDesc = []byte{
-	0x0a, 0x11, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x70, 0x72,
-	0x6f, 0x74, 0x6f, 0x12, 0x05, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xbb, 0x03, 0x0a, 0x0d, 0x57,
-	0x61, 0x74, 0x63, 0x68, 0x65, 0x64, 0x54, 0x78, 0x44, 0x61, 0x74, 0x61, 0x12, 0x31, 0x0a, 0x14,
-	0x73, 0x6c, 0x61, 0x73, 0x68, 0x69, 0x6e, 0x67, 0x5f, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63,
-	0x74, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x13, 0x73, 0x6c, 0x61, 0x73,
-	0x68, 0x69, 0x6e, 0x67, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12,
-	0x38, 0x0a, 0x18, 0x73, 0x6c, 0x61, 0x73, 0x68, 0x69, 0x6e, 0x67, 0x5f, 0x74, 0x72, 0x61, 0x6e,
-	0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x73, 0x69, 0x67, 0x18, 0x02, 0x20, 0x01, 0x28,
-	0x0c, 0x52, 0x16, 0x73, 0x6c, 0x61, 0x73, 0x68, 0x69, 0x6e, 0x67, 0x54, 0x72, 0x61, 0x6e, 0x73,
-	0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x69, 0x67, 0x12, 0x2a, 0x0a, 0x11, 0x73, 0x74, 0x61,
-	0x6b, 0x65, 0x72, 0x5f, 0x62, 0x61, 0x62, 0x79, 0x6c, 0x6f, 0x6e, 0x5f, 0x70, 0x6b, 0x18, 0x03,
-	0x20, 0x01, 0x28, 0x0c, 0x52, 0x0f, 0x73, 0x74, 0x61, 0x6b, 0x65, 0x72, 0x42, 0x61, 0x62, 0x79,
-	0x6c, 0x6f, 0x6e, 0x50, 0x6b, 0x12, 0x22, 0x0a, 0x0d, 0x73, 0x74, 0x61, 0x6b, 0x65, 0x72, 0x5f,
-	0x62, 0x74, 0x63, 0x5f, 0x70, 0x6b, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0b, 0x73, 0x74,
-	0x61, 0x6b, 0x65, 0x72, 0x42, 0x74, 0x63, 0x50, 0x6b, 0x12, 0x33, 0x0a, 0x15, 0x75, 0x6e, 0x62,
-	0x6f, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x5f, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69,
-	0x6f, 0x6e, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x14, 0x75, 0x6e, 0x62, 0x6f, 0x6e, 0x64,
-	0x69, 0x6e, 0x67, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x44,
-	0x0a, 0x1e, 0x73, 0x6c, 0x61, 0x73, 0x68, 0x69, 0x6e, 0x67, 0x5f, 0x75, 0x6e, 0x62, 0x6f, 0x6e,
-	0x64, 0x69, 0x6e, 0x67, 0x5f, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e,
-	0x18, 0x06, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x1c, 0x73, 0x6c, 0x61, 0x73, 0x68, 0x69, 0x6e, 0x67,
-	0x55, 0x6e, 0x62, 0x6f, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63,
-	0x74, 0x69, 0x6f, 0x6e, 0x12, 0x4b, 0x0a, 0x22, 0x73, 0x6c, 0x61, 0x73, 0x68, 0x69, 0x6e, 0x67,
-	0x5f, 0x75, 0x6e, 0x62, 0x6f, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x5f, 0x74, 0x72, 0x61, 0x6e, 0x73,
-	0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x73, 0x69, 0x67, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0c,
-	0x52, 0x1f, 0x73, 0x6c, 0x61, 0x73, 0x68, 0x69, 0x6e, 0x67, 0x55, 0x6e, 0x62, 0x6f, 0x6e, 0x64,
-	0x69, 0x6e, 0x67, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x69,
-	0x67, 0x12, 0x25, 0x0a, 0x0e, 0x75, 0x6e, 0x62, 0x6f, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x5f, 0x74,
-	0x69, 0x6d, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0d, 0x75, 0x6e, 0x62, 0x6f, 0x6e,
-	0x64, 0x69, 0x6e, 0x67, 0x54, 0x69, 0x6d, 0x65, 0x22, 0x57, 0x0a, 0x13, 0x42, 0x54, 0x43, 0x43,
-	0x6f, 0x6e, 0x66, 0x69, 0x72, 0x6d, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x6e, 0x66, 0x6f, 0x12,
-	0x21, 0x0a, 0x0c, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x5f, 0x68, 0x65, 0x69, 0x67, 0x68, 0x74, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0b, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x48, 0x65, 0x69, 0x67,
-	0x68, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x5f, 0x68, 0x61, 0x73, 0x68,
-	0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x48, 0x61, 0x73,
-	0x68, 0x22, 0x5f, 0x0a, 0x0b, 0x43, 0x6f, 0x76, 0x65, 0x6e, 0x61, 0x6e, 0x74, 0x53, 0x69, 0x67,
-	0x12, 0x21, 0x0a, 0x0c, 0x63, 0x6f, 0x76, 0x65, 0x6e, 0x61, 0x6e, 0x74, 0x5f, 0x73, 0x69, 0x67,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0b, 0x63, 0x6f, 0x76, 0x65, 0x6e, 0x61, 0x6e, 0x74,
-	0x53, 0x69, 0x67, 0x12, 0x2d, 0x0a, 0x13, 0x63, 0x6f, 0x76, 0x65, 0x6e, 0x61, 0x6e, 0x74, 0x5f,
-	0x73, 0x69, 0x67, 0x5f, 0x62, 0x74, 0x63, 0x5f, 0x70, 0x6b, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c,
-	0x52, 0x10, 0x63, 0x6f, 0x76, 0x65, 0x6e, 0x61, 0x6e, 0x74, 0x53, 0x69, 0x67, 0x42, 0x74, 0x63,
-	0x50, 0x6b, 0x22, 0x9a, 0x02, 0x0a, 0x0f, 0x55, 0x6e, 0x62, 0x6f, 0x6e, 0x64, 0x69, 0x6e, 0x67,
-	0x54, 0x78, 0x44, 0x61, 0x74, 0x61, 0x12, 0x33, 0x0a, 0x15, 0x75, 0x6e, 0x62, 0x6f, 0x6e, 0x64,
-	0x69, 0x6e, 0x67, 0x5f, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x14, 0x75, 0x6e, 0x62, 0x6f, 0x6e, 0x64, 0x69, 0x6e, 0x67,
-	0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x25, 0x0a, 0x0e, 0x75,
-	0x6e, 0x62, 0x6f, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x02, 0x20,
-	0x01, 0x28, 0x0d, 0x52, 0x0d, 0x75, 0x6e, 0x62, 0x6f, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x54, 0x69,
-	0x6d, 0x65, 0x12, 0x43, 0x0a, 0x13, 0x63, 0x6f, 0x76, 0x65, 0x6e, 0x61, 0x6e, 0x74, 0x5f, 0x73,
-	0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32,
-	0x12, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x43, 0x6f, 0x76, 0x65, 0x6e, 0x61, 0x6e, 0x74,
-	0x53, 0x69, 0x67, 0x52, 0x12, 0x63, 0x6f, 0x76, 0x65, 0x6e, 0x61, 0x6e, 0x74, 0x53, 0x69, 0x67,
-	0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x73, 0x12, 0x66, 0x0a, 0x22, 0x75, 0x6e, 0x62, 0x6f, 0x6e,
-	0x64, 0x69, 0x6e, 0x67, 0x5f, 0x74, 0x78, 0x5f, 0x62, 0x74, 0x63, 0x5f, 0x63, 0x6f, 0x6e, 0x66,
-	0x69, 0x72, 0x6d, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x6e, 0x66, 0x6f, 0x18, 0x04, 0x20,
-	0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x42, 0x54, 0x43, 0x43,
-	0x6f, 0x6e, 0x66, 0x69, 0x72, 0x6d, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x6e, 0x66, 0x6f, 0x52,
-	0x1e, 0x75, 0x6e, 0x62, 0x6f, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x54, 0x78, 0x42, 0x74, 0x63, 0x43,
-	0x6f, 0x6e, 0x66, 0x69, 0x72, 0x6d, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x6e, 0x66, 0x6f, 0x22,
-	0xa1, 0x05, 0x0a, 0x12, 0x54, 0x72, 0x61, 0x63, 0x6b, 0x65, 0x64, 0x54, 0x72, 0x61, 0x6e, 0x73,
-	0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x36, 0x0a, 0x17, 0x74, 0x72, 0x61, 0x63, 0x6b, 0x65,
-	0x64, 0x5f, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x64,
-	0x78, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x15, 0x74, 0x72, 0x61, 0x63, 0x6b, 0x65, 0x64,
-	0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x78, 0x12, 0x2f,
-	0x0a, 0x13, 0x73, 0x74, 0x61, 0x6b, 0x69, 0x6e, 0x67, 0x5f, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x61,
-	0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x12, 0x73, 0x74, 0x61,
-	0x6b, 0x69, 0x6e, 0x67, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12,
-	0x2c, 0x0a, 0x12, 0x73, 0x74, 0x61, 0x6b, 0x69, 0x6e, 0x67, 0x5f, 0x6f, 0x75, 0x74, 0x70, 0x75,
-	0x74, 0x5f, 0x69, 0x64, 0x78, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x10, 0x73, 0x74, 0x61,
-	0x6b, 0x69, 0x6e, 0x67, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x49, 0x64, 0x78, 0x12, 0x25, 0x0a,
-	0x0e, 0x73, 0x74, 0x61, 0x6b, 0x65, 0x72, 0x5f, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x18,
-	0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x73, 0x74, 0x61, 0x6b, 0x65, 0x72, 0x41, 0x64, 0x64,
-	0x72, 0x65, 0x73, 0x73, 0x12, 0x21, 0x0a, 0x0c, 0x73, 0x74, 0x61, 0x6b, 0x69, 0x6e, 0x67, 0x5f,
-	0x74, 0x69, 0x6d, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0b, 0x73, 0x74, 0x61, 0x6b,
-	0x69, 0x6e, 0x67, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x3b, 0x0a, 0x1a, 0x66, 0x69, 0x6e, 0x61, 0x6c,
-	0x69, 0x74, 0x79, 0x5f, 0x70, 0x72, 0x6f, 0x76, 0x69, 0x64, 0x65, 0x72, 0x73, 0x5f, 0x62, 0x74,
-	0x63, 0x5f, 0x70, 0x6b, 0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x0c, 0x52, 0x17, 0x66, 0x69, 0x6e,
-	0x61, 0x6c, 0x69, 0x74, 0x79, 0x50, 0x72, 0x6f, 0x76, 0x69, 0x64, 0x65, 0x72, 0x73, 0x42, 0x74,
-	0x63, 0x50, 0x6b, 0x73, 0x12, 0x62, 0x0a, 0x20, 0x73, 0x74, 0x61, 0x6b, 0x69, 0x6e, 0x67, 0x5f,
-	0x74, 0x78, 0x5f, 0x62, 0x74, 0x63, 0x5f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x72, 0x6d, 0x61, 0x74,
-	0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x6e, 0x66, 0x6f, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a,
-	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x42, 0x54, 0x43, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x72,
-	0x6d, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x1c, 0x73, 0x74, 0x61, 0x6b,
-	0x69, 0x6e, 0x67, 0x54, 0x78, 0x42, 0x74, 0x63, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x72, 0x6d, 0x61,
-	0x74, 0x69, 0x6f, 0x6e, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x20, 0x0a, 0x0c, 0x62, 0x74, 0x63, 0x5f,
-	0x73, 0x69, 0x67, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0a,
-	0x62, 0x74, 0x63, 0x53, 0x69, 0x67, 0x54, 0x79, 0x70, 0x65, 0x12, 0x2b, 0x0a, 0x12, 0x62, 0x61,
-	0x62, 0x79, 0x6c, 0x6f, 0x6e, 0x5f, 0x73, 0x69, 0x67, 0x5f, 0x62, 0x74, 0x63, 0x5f, 0x70, 0x6b,
-	0x18, 0x09, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0f, 0x62, 0x61, 0x62, 0x79, 0x6c, 0x6f, 0x6e, 0x53,
-	0x69, 0x67, 0x42, 0x74, 0x63, 0x50, 0x6b, 0x12, 0x2d, 0x0a, 0x13, 0x62, 0x74, 0x63, 0x5f, 0x73,
-	0x69, 0x67, 0x5f, 0x62, 0x61, 0x62, 0x79, 0x6c, 0x6f, 0x6e, 0x5f, 0x73, 0x69, 0x67, 0x18, 0x0a,
-	0x20, 0x01, 0x28, 0x0c, 0x52, 0x10, 0x62, 0x74, 0x63, 0x53, 0x69, 0x67, 0x42, 0x61, 0x62, 0x79,
-	0x6c, 0x6f, 0x6e, 0x53, 0x69, 0x67, 0x12, 0x2d, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18,
-	0x0b, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x17, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x54, 0x72,
-	0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x05,
-	0x73, 0x74, 0x61, 0x74, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x77, 0x61, 0x74, 0x63, 0x68, 0x65, 0x64,
-	0x18, 0x0c, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x77, 0x61, 0x74, 0x63, 0x68, 0x65, 0x64, 0x12,
-	0x42, 0x0a, 0x11, 0x75, 0x6e, 0x62, 0x6f, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x5f, 0x74, 0x78, 0x5f,
-	0x64, 0x61, 0x74, 0x61, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x70, 0x72, 0x6f,
-	0x74, 0x6f, 0x2e, 0x55, 0x6e, 0x62, 0x6f, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x54, 0x78, 0x44, 0x61,
-	0x74, 0x61, 0x52, 0x0f, 0x75, 0x6e, 0x62, 0x6f, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x54, 0x78, 0x44,
-	0x61, 0x74, 0x61, 0x2a, 0x97, 0x01, 0x0a, 0x10, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74,
-	0x69, 0x6f, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x0f, 0x0a, 0x0b, 0x53, 0x45, 0x4e, 0x54,
-	0x5f, 0x54, 0x4f, 0x5f, 0x42, 0x54, 0x43, 0x10, 0x00, 0x12, 0x14, 0x0a, 0x10, 0x43, 0x4f, 0x4e,
-	0x46, 0x49, 0x52, 0x4d, 0x45, 0x44, 0x5f, 0x4f, 0x4e, 0x5f, 0x42, 0x54, 0x43, 0x10, 0x01, 0x12,
-	0x13, 0x0a, 0x0f, 0x53, 0x45, 0x4e, 0x54, 0x5f, 0x54, 0x4f, 0x5f, 0x42, 0x41, 0x42, 0x59, 0x4c,
-	0x4f, 0x4e, 0x10, 0x02, 0x12, 0x15, 0x0a, 0x11, 0x44, 0x45, 0x4c, 0x45, 0x47, 0x41, 0x54, 0x49,
-	0x4f, 0x4e, 0x5f, 0x41, 0x43, 0x54, 0x49, 0x56, 0x45, 0x10, 0x03, 0x12, 0x1e, 0x0a, 0x1a, 0x55,
-	0x4e, 0x42, 0x4f, 0x4e, 0x44, 0x49, 0x4e, 0x47, 0x5f, 0x43, 0x4f, 0x4e, 0x46, 0x49, 0x52, 0x4d,
-	0x45, 0x44, 0x5f, 0x4f, 0x4e, 0x5f, 0x42, 0x54, 0x43, 0x10, 0x04, 0x12, 0x10, 0x0a, 0x0c, 0x53,
-	0x50, 0x45, 0x4e, 0x54, 0x5f, 0x4f, 0x4e, 0x5f, 0x42, 0x54, 0x43, 0x10, 0x05, 0x42, 0x2a, 0x5a,
-	0x28, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x62, 0x61, 0x62, 0x79,
-	0x6c, 0x6f, 0x6e, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x2f, 0x62, 0x74, 0x63, 0x2d, 0x73, 0x74, 0x61,
-	0x6b, 0x65, 0x72, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f,
-	0x33,
+func (x *TrackedTransaction) GetParamsVersion() uint32 {
+	if x != nil {
+		return x.ParamsVersion
+	}
+	return 0
 }
 
-var (
-	file_transaction_proto_rawDescOnce sync.Once
+func (x *TrackedTransaction) GetTxLabels() []*TxLabel {
+	if x != nil {
+		return x.TxLabels
+	}
+	return nil
+}
+
+func (x *TrackedTransaction) GetChangeOutput() *ChangeOutput {
+	if x != nil {
+		return x.ChangeOutput
+	}
+	return nil
+}
+
+func (x *TrackedTransaction) GetBabylonTxHash() string {
+	if x != nil {
+		return x.BabylonTxHash
+	}
+	return ""
+}
+
+func (x *TrackedTransaction) GetBabylonMemo() string {
+	if x != nil {
+		return x.BabylonMemo
+	}
+	return ""
+}
+
+func (x *TrackedTransaction) GetStateChangedAt() int64 {
+	if x != nil {
+		return x.StateChangedAt
+	}
+	return 0
+}
+
+func (x *TrackedTransaction) GetStateHistory() []*StateTransition {
+	if x != nil {
+		return x.StateHistory
+	}
+	return nil
+}
+
+func (x *TrackedTransaction) GetSpendTxData() *SpendTxData {
+	if x != nil {
+		return x.SpendTxData
+	}
+	return nil
+}
+
+func (x *TrackedTransaction) GetLabel() string {
+	if x != nil {
+		return x.Label
+	}
+	return ""
+}
+
+func (x *TrackedTransaction) GetRequiredDepth() uint32 {
+	if x != nil {
+		return x.RequiredDepth
+	}
+	return 0
+}
+
+func (x *TrackedTransaction) GetStakingTxInclusionProof() *InclusionProof {
+	if x != nil {
+		return x.StakingTxInclusionProof
+	}
+	return nil
+}
+
+func (x *TrackedTransaction) GetCorruptionReason() string {
+	if x != nil {
+		return x.CorruptionReason
+	}
+	return ""
+}
+
+func (x *TrackedTransaction) GetAutoRenew() bool {
+	if x != nil {
+		return x.AutoRenew
+	}
+	return false
+}
+
+func (x *TrackedTransaction) GetRenewalTxHash() []byte {
+	if x != nil {
+		return x.RenewalTxHash
+	}
+	return nil
+}
+
+func (x *TrackedTransaction) GetRenewedFromTxHash() []byte {
+	if x != nil {
+		return x.RenewedFromTxHash
+	}
+	return nil
+}
+
+func (x *TrackedTransaction) GetAutoRenewSkipReason() string {
+	if x != nil {
+		return x.AutoRenewSkipReason
+	}
+	return ""
+}
+
+func (x *TrackedTransaction) GetStakingFeeEstimate() *FeeEstimate {
+	if x != nil {
+		return x.StakingFeeEstimate
+	}
+	return nil
+}
+
+func (x *TrackedTransaction) GetUnbondingFeeEstimate() *FeeEstimate {
+	if x != nil {
+		return x.UnbondingFeeEstimate
+	}
+	return nil
+}
+
+func (x *TrackedTransaction) GetSpendFeeEstimate() *FeeEstimate {
+	if x != nil {
+		return x.SpendFeeEstimate
+	}
+	return nil
+}
+
+func (x *TrackedTransaction) GetUnbondingWaitAborted() bool {
+	if x != nil {
+		return x.UnbondingWaitAborted
+	}
+	return false
+}
+
+func (x *TrackedTransaction) GetConflictingTxHash() []byte {
+	if x != nil {
+		return x.ConflictingTxHash
+	}
+	return nil
+}
+
+func (x *TrackedTransaction) GetBroadcastHeight() uint32 {
+	if x != nil {
+		return x.BroadcastHeight
+	}
+	return 0
+}
+
+func (x *TrackedTransaction) GetBabylonSubmitFailure() *BabylonSubmitFailure {
+	if x != nil {
+		return x.BabylonSubmitFailure
+	}
+	return nil
+}
+
+// Rejection of message sent by staker to babylon, with raw ABCI error returned
+// by babylon
+type BabylonSubmitFailure struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// babylon operation which failed e.g. send_delegation
+	Operation string `protobuf:"bytes,1,opt,name=operation,proto3" json:"operation,omitempty"`
+	Codespace string `protobuf:"bytes,2,opt,name=codespace,proto3" json:"codespace,omitempty"`
+	Code      uint32 `protobuf:"varint,3,opt,name=code,proto3" json:"code,omitempty"`
+	RawLog    string `protobuf:"bytes,4,opt,name=raw_log,json=rawLog,proto3" json:"raw_log,omitempty"`
+	// true if transaction was included in block and failed execution, false if
+	// it was rejected before entering mempool
+	Executed bool `protobuf:"varint,5,opt,name=executed,proto3" json:"executed,omitempty"`
+	// hash and height of failed babylon transaction, empty if not known
+	BabylonTxHash string `protobuf:"bytes,6,opt,name=babylon_tx_hash,json=babylonTxHash,proto3" json:"babylon_tx_hash,omitempty"`
+	BabylonHeight int64  `protobuf:"varint,7,opt,name=babylon_height,json=babylonHeight,proto3" json:"babylon_height,omitempty"`
+	FailedAt      int64  `protobuf:"varint,8,opt,name=failed_at,json=failedAt,proto3" json:"failed_at,omitempty"`
+}
+
+func (x *BabylonSubmitFailure) Reset() {
+	*x = BabylonSubmitFailure{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_transaction_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BabylonSubmitFailure) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BabylonSubmitFailure) ProtoMessage() {}
+
+func (x *BabylonSubmitFailure) ProtoReflect() protoreflect.Message {
+	mi := &file_transaction_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BabylonSubmitFailure.ProtoReflect.Descriptor instead.
+func (*BabylonSubmitFailure) Descriptor() ([]byte, []int) {
+	return file_transaction_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *BabylonSubmitFailure) GetOperation() string {
+	if x != nil {
+		return x.Operation
+	}
+	return ""
+}
+
+func (x *BabylonSubmitFailure) GetCodespace() string {
+	if x != nil {
+		return x.Codespace
+	}
+	return ""
+}
+
+func (x *BabylonSubmitFailure) GetCode() uint32 {
+	if x != nil {
+		return x.Code
+	}
+	return 0
+}
+
+func (x *BabylonSubmitFailure) GetRawLog() string {
+	if x != nil {
+		return x.RawLog
+	}
+	return ""
+}
+
+func (x *BabylonSubmitFailure) GetExecuted() bool {
+	if x != nil {
+		return x.Executed
+	}
+	return false
+}
+
+func (x *BabylonSubmitFailure) GetBabylonTxHash() string {
+	if x != nil {
+		return x.BabylonTxHash
+	}
+	return ""
+}
+
+func (x *BabylonSubmitFailure) GetBabylonHeight() int64 {
+	if x != nil {
+		return x.BabylonHeight
+	}
+	return 0
+}
+
+func (x *BabylonSubmitFailure) GetFailedAt() int64 {
+	if x != nil {
+		return x.FailedAt
+	}
+	return 0
+}
+
+// Fee estimate consumed by staker when building transaction
+type FeeEstimate struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// type of estimator which provided the estimate
+	Estimator  string `protobuf:"bytes,1,opt,name=estimator,proto3" json:"estimator,omitempty"`
+	ConfTarget uint32 `protobuf:"varint,2,opt,name=conf_target,json=confTarget,proto3" json:"conf_target,omitempty"`
+	// fee rate in sat/kvB estimated by btc node or configured in static
+	// estimator, 0 if estimation failed
+	EstimatedFeeRate uint64 `protobuf:"varint,3,opt,name=estimated_fee_rate,json=estimatedFeeRate,proto3" json:"estimated_fee_rate,omitempty"`
+	// configured fee rate in sat/kvB used instead of estimated one, because
+	// estimation failed or estimate was out of configured bounds. 0 if estimated
+	// fee rate was used
+	FallbackFeeRate uint64 `protobuf:"varint,4,opt,name=fallback_fee_rate,json=fallbackFeeRate,proto3" json:"fallback_fee_rate,omitempty"`
+	// unix time of the estimate
+	EstimatedAt int64 `protobuf:"varint,5,opt,name=estimated_at,json=estimatedAt,proto3" json:"estimated_at,omitempty"`
+}
+
+func (x *FeeEstimate) Reset() {
+	*x = FeeEstimate{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_transaction_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FeeEstimate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FeeEstimate) ProtoMessage() {}
+
+func (x *FeeEstimate) ProtoReflect() protoreflect.Message {
+	mi := &file_transaction_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FeeEstimate.ProtoReflect.Descriptor instead.
+func (*FeeEstimate) Descriptor() ([]byte, []int) {
+	return file_transaction_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *FeeEstimate) GetEstimator() string {
+	if x != nil {
+		return x.Estimator
+	}
+	return ""
+}
+
+func (x *FeeEstimate) GetConfTarget() uint32 {
+	if x != nil {
+		return x.ConfTarget
+	}
+	return 0
+}
+
+func (x *FeeEstimate) GetEstimatedFeeRate() uint64 {
+	if x != nil {
+		return x.EstimatedFeeRate
+	}
+	return 0
+}
+
+func (x *FeeEstimate) GetFallbackFeeRate() uint64 {
+	if x != nil {
+		return x.FallbackFeeRate
+	}
+	return 0
+}
+
+func (x *FeeEstimate) GetEstimatedAt() int64 {
+	if x != nil {
+		return x.EstimatedAt
+	}
+	return 0
+}
+
+type InclusionProof struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// serialized header of block which includes the transaction
+	BlockHeader []byte `protobuf:"bytes,1,opt,name=block_header,json=blockHeader,proto3" json:"block_header,omitempty"`
+	// index of the transaction in the block
+	TxIndex uint32 `protobuf:"varint,2,opt,name=tx_index,json=txIndex,proto3" json:"tx_index,omitempty"`
+	// merkle nodes proving inclusion of the transaction in the block
+	MerkleProof []byte `protobuf:"bytes,3,opt,name=merkle_proof,json=merkleProof,proto3" json:"merkle_proof,omitempty"`
+}
+
+func (x *InclusionProof) Reset() {
+	*x = InclusionProof{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_transaction_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *InclusionProof) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InclusionProof) ProtoMessage() {}
+
+func (x *InclusionProof) ProtoReflect() protoreflect.Message {
+	mi := &file_transaction_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InclusionProof.ProtoReflect.Descriptor instead.
+func (*InclusionProof) Descriptor() ([]byte, []int) {
+	return file_transaction_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *InclusionProof) GetBlockHeader() []byte {
+	if x != nil {
+		return x.BlockHeader
+	}
+	return nil
+}
+
+func (x *InclusionProof) GetTxIndex() uint32 {
+	if x != nil {
+		return x.TxIndex
+	}
+	return 0
+}
+
+func (x *InclusionProof) GetMerkleProof() []byte {
+	if x != nil {
+		return x.MerkleProof
+	}
+	return nil
+}
+
+type ChangeOutput struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	OutputIdx uint32 `protobuf:"varint,1,opt,name=output_idx,json=outputIdx,proto3" json:"output_idx,omitempty"`
+	Amount    int64  `protobuf:"varint,2,opt,name=amount,proto3" json:"amount,omitempty"`
+	Address   string `protobuf:"bytes,3,opt,name=address,proto3" json:"address,omitempty"`
+}
+
+func (x *ChangeOutput) Reset() {
+	*x = ChangeOutput{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_transaction_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ChangeOutput) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChangeOutput) ProtoMessage() {}
+
+func (x *ChangeOutput) ProtoReflect() protoreflect.Message {
+	mi := &file_transaction_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChangeOutput.ProtoReflect.Descriptor instead.
+func (*ChangeOutput) Descriptor() ([]byte, []int) {
+	return file_transaction_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ChangeOutput) GetOutputIdx() uint32 {
+	if x != nil {
+		return x.OutputIdx
+	}
+	return 0
+}
+
+func (x *ChangeOutput) GetAmount() int64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *ChangeOutput) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+type TxLabel struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	TxHash []byte `protobuf:"bytes,1,opt,name=tx_hash,json=txHash,proto3" json:"tx_hash,omitempty"`
+	Label  string `protobuf:"bytes,2,opt,name=label,proto3" json:"label,omitempty"`
+}
+
+func (x *TxLabel) Reset() {
+	*x = TxLabel{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_transaction_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TxLabel) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TxLabel) ProtoMessage() {}
+
+func (x *TxLabel) ProtoReflect() protoreflect.Message {
+	mi := &file_transaction_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TxLabel.ProtoReflect.Descriptor instead.
+func (*TxLabel) Descriptor() ([]byte, []int) {
+	return file_transaction_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *TxLabel) GetTxHash() []byte {
+	if x != nil {
+		return x.TxHash
+	}
+	return nil
+}
+
+func (x *TxLabel) GetLabel() string {
+	if x != nil {
+		return x.Label
+	}
+	return ""
+}
+
+type ReconciliationDiscrepancy struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	StakingTxHash   []byte           `protobuf:"bytes,1,opt,name=staking_tx_hash,json=stakingTxHash,proto3" json:"staking_tx_hash,omitempty"`
+	LocalState      TransactionState `protobuf:"varint,2,opt,name=local_state,json=localState,proto3,enum=proto.TransactionState" json:"local_state,omitempty"`
+	DiscrepancyType DiscrepancyType  `protobuf:"varint,3,opt,name=discrepancy_type,json=discrepancyType,proto3,enum=proto.DiscrepancyType" json:"discrepancy_type,omitempty"`
+	Description     string           `protobuf:"bytes,4,opt,name=description,proto3" json:"description,omitempty"`
+}
+
+func (x *ReconciliationDiscrepancy) Reset() {
+	*x = ReconciliationDiscrepancy{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_transaction_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ReconciliationDiscrepancy) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReconciliationDiscrepancy) ProtoMessage() {}
+
+func (x *ReconciliationDiscrepancy) ProtoReflect() protoreflect.Message {
+	mi := &file_transaction_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReconciliationDiscrepancy.ProtoReflect.Descriptor instead.
+func (*ReconciliationDiscrepancy) Descriptor() ([]byte, []int) {
+	return file_transaction_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ReconciliationDiscrepancy) GetStakingTxHash() []byte {
+	if x != nil {
+		return x.StakingTxHash
+	}
+	return nil
+}
+
+func (x *ReconciliationDiscrepancy) GetLocalState() TransactionState {
+	if x != nil {
+		return x.LocalState
+	}
+	return TransactionState_SENT_TO_BTC
+}
+
+func (x *ReconciliationDiscrepancy) GetDiscrepancyType() DiscrepancyType {
+	if x != nil {
+		return x.DiscrepancyType
+	}
+	return DiscrepancyType_MISSING_ON_BABYLON
+}
+
+func (x *ReconciliationDiscrepancy) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+type ReconciliationReport struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// unix timestamp in seconds of the moment report was created
+	CreatedAt           int64                        `protobuf:"varint,1,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	CheckedTransactions uint64                       `protobuf:"varint,2,opt,name=checked_transactions,json=checkedTransactions,proto3" json:"checked_transactions,omitempty"`
+	Discrepancies       []*ReconciliationDiscrepancy `protobuf:"bytes,3,rep,name=discrepancies,proto3" json:"discrepancies,omitempty"`
+}
+
+func (x *ReconciliationReport) Reset() {
+	*x = ReconciliationReport{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_transaction_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ReconciliationReport) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReconciliationReport) ProtoMessage() {}
+
+func (x *ReconciliationReport) ProtoReflect() protoreflect.Message {
+	mi := &file_transaction_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReconciliationReport.ProtoReflect.Descriptor instead.
+func (*ReconciliationReport) Descriptor() ([]byte, []int) {
+	return file_transaction_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *ReconciliationReport) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
+func (x *ReconciliationReport) GetCheckedTransactions() uint64 {
+	if x != nil {
+		return x.CheckedTransactions
+	}
+	return 0
+}
+
+func (x *ReconciliationReport) GetDiscrepancies() []*ReconciliationDiscrepancy {
+	if x != nil {
+		return x.Discrepancies
+	}
+	return nil
+}
+
+type StakingRequestRecord struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	RequestId string               `protobuf:"bytes,1,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	Status    StakingRequestStatus `protobuf:"varint,2,opt,name=status,proto3,enum=proto.StakingRequestStatus" json:"status,omitempty"`
+	// only filled if status is STAKING_REQUEST_BROADCAST
+	StakingTxHash []byte `protobuf:"bytes,3,opt,name=staking_tx_hash,json=stakingTxHash,proto3" json:"staking_tx_hash,omitempty"`
+	// only filled if status is STAKING_REQUEST_FAILED
+	Error string `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`
+	// unix timestamp in seconds of the last status update
+	UpdatedAt int64 `protobuf:"varint,5,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+}
+
+func (x *StakingRequestRecord) Reset() {
+	*x = StakingRequestRecord{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_transaction_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StakingRequestRecord) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StakingRequestRecord) ProtoMessage() {}
+
+func (x *StakingRequestRecord) ProtoReflect() protoreflect.Message {
+	mi := &file_transaction_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StakingRequestRecord.ProtoReflect.Descriptor instead.
+func (*StakingRequestRecord) Descriptor() ([]byte, []int) {
+	return file_transaction_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *StakingRequestRecord) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+func (x *StakingRequestRecord) GetStatus() StakingRequestStatus {
+	if x != nil {
+		return x.Status
+	}
+	return StakingRequestStatus_STAKING_REQUEST_PENDING
+}
+
+func (x *StakingRequestRecord) GetStakingTxHash() []byte {
+	if x != nil {
+		return x.StakingTxHash
+	}
+	return nil
+}
+
+func (x *StakingRequestRecord) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *StakingRequestRecord) GetUpdatedAt() int64 {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return 0
+}
+
+// Snapshot of babylon staking params which are commited to in staking output
+type StakingParamsSnapshot struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Version        uint32   `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	CovenantPks    [][]byte `protobuf:"bytes,2,rep,name=covenant_pks,json=covenantPks,proto3" json:"covenant_pks,omitempty"`
+	CovenantQuorum uint32   `protobuf:"varint,3,opt,name=covenant_quorum,json=covenantQuorum,proto3" json:"covenant_quorum,omitempty"`
+}
+
+func (x *StakingParamsSnapshot) Reset() {
+	*x = StakingParamsSnapshot{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_transaction_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StakingParamsSnapshot) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StakingParamsSnapshot) ProtoMessage() {}
+
+func (x *StakingParamsSnapshot) ProtoReflect() protoreflect.Message {
+	mi := &file_transaction_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StakingParamsSnapshot.ProtoReflect.Descriptor instead.
+func (*StakingParamsSnapshot) Descriptor() ([]byte, []int) {
+	return file_transaction_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *StakingParamsSnapshot) GetVersion() uint32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *StakingParamsSnapshot) GetCovenantPks() [][]byte {
+	if x != nil {
+		return x.CovenantPks
+	}
+	return nil
+}
+
+func (x *StakingParamsSnapshot) GetCovenantQuorum() uint32 {
+	if x != nil {
+		return x.CovenantQuorum
+	}
+	return 0
+}
+
+type AuditLogEntry struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Seq  uint64         `protobuf:"varint,1,opt,name=seq,proto3" json:"seq,omitempty"`
+	Type AuditEntryType `protobuf:"varint,2,opt,name=type,proto3,enum=proto.AuditEntryType" json:"type,omitempty"`
+	// unix timestamp in seconds of the moment entry was written
+	Timestamp int64 `protobuf:"varint,3,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	// identity of the caller e.g remote address of rpc client
+	Caller string `protobuf:"bytes,4,opt,name=caller,proto3" json:"caller,omitempty"`
+	Method string `protobuf:"bytes,5,opt,name=method,proto3" json:"method,omitempty"`
+	// json encoded arguments of the operation
+	Args string `protobuf:"bytes,6,opt,name=args,proto3" json:"args,omitempty"`
+	// only filled if type is AUDIT_OPERATION_FINISHED, sequence number of the
+	// entry which recorded the request
+	RequestSeq uint64 `protobuf:"varint,7,opt,name=request_seq,json=requestSeq,proto3" json:"request_seq,omitempty"`
+	// only filled if operation finished successfully and sent btc transaction
+	TxHash []byte `protobuf:"bytes,8,opt,name=tx_hash,json=txHash,proto3" json:"tx_hash,omitempty"`
+	// only filled if operation finished successfully and started asynchronous
+	// staking request
+	StakingRequestId string `protobuf:"bytes,9,opt,name=staking_request_id,json=stakingRequestId,proto3" json:"staking_request_id,omitempty"`
+	// only filled if operation failed
+	Error string `protobuf:"bytes,10,opt,name=error,proto3" json:"error,omitempty"`
+	// sha256 hash of the previous serialized entry, empty for the first entry
+	PrevHash []byte `protobuf:"bytes,11,opt,name=prev_hash,json=prevHash,proto3" json:"prev_hash,omitempty"`
+}
+
+func (x *AuditLogEntry) Reset() {
+	*x = AuditLogEntry{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_transaction_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AuditLogEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AuditLogEntry) ProtoMessage() {}
+
+func (x *AuditLogEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_transaction_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AuditLogEntry.ProtoReflect.Descriptor instead.
+func (*AuditLogEntry) Descriptor() ([]byte, []int) {
+	return file_transaction_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *AuditLogEntry) GetSeq() uint64 {
+	if x != nil {
+		return x.Seq
+	}
+	return 0
+}
+
+func (x *AuditLogEntry) GetType() AuditEntryType {
+	if x != nil {
+		return x.Type
+	}
+	return AuditEntryType_AUDIT_OPERATION_REQUESTED
+}
+
+func (x *AuditLogEntry) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *AuditLogEntry) GetCaller() string {
+	if x != nil {
+		return x.Caller
+	}
+	return ""
+}
+
+func (x *AuditLogEntry) GetMethod() string {
+	if x != nil {
+		return x.Method
+	}
+	return ""
+}
+
+func (x *AuditLogEntry) GetArgs() string {
+	if x != nil {
+		return x.Args
+	}
+	return ""
+}
+
+func (x *AuditLogEntry) GetRequestSeq() uint64 {
+	if x != nil {
+		return x.RequestSeq
+	}
+	return 0
+}
+
+func (x *AuditLogEntry) GetTxHash() []byte {
+	if x != nil {
+		return x.TxHash
+	}
+	return nil
+}
+
+func (x *AuditLogEntry) GetStakingRequestId() string {
+	if x != nil {
+		return x.StakingRequestId
+	}
+	return ""
+}
+
+func (x *AuditLogEntry) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *AuditLogEntry) GetPrevHash() []byte {
+	if x != nil {
+		return x.PrevHash
+	}
+	return nil
+}
+
+// Intent to spend unbonded funds automatically once unbonding timelock expires
+type SweepIntent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// address to which unbonded funds are sent
+	DestinationAddress string `protobuf:"bytes,1,opt,name=destination_address,json=destinationAddress,proto3" json:"destination_address,omitempty"`
+	// hash of the sent sweep transaction, empty until sweep is sent
+	SweepTxHash []byte `protobuf:"bytes,2,opt,name=sweep_tx_hash,json=sweepTxHash,proto3" json:"sweep_tx_hash,omitempty"`
+}
+
+func (x *SweepIntent) Reset() {
+	*x = SweepIntent{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_transaction_proto_msgTypes[17]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SweepIntent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SweepIntent) ProtoMessage() {}
+
+func (x *SweepIntent) ProtoReflect() protoreflect.Message {
+	mi := &file_transaction_proto_msgTypes[17]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SweepIntent.ProtoReflect.Descriptor instead.
+func (*SweepIntent) Descriptor() ([]byte, []int) {
+	return file_transaction_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *SweepIntent) GetDestinationAddress() string {
+	if x != nil {
+		return x.DestinationAddress
+	}
+	return ""
+}
+
+func (x *SweepIntent) GetSweepTxHash() []byte {
+	if x != nil {
+		return x.SweepTxHash
+	}
+	return nil
+}
+
+// Data of internal event, which depends on event type
+type EventIntentPayload struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// only set for EVENT_INTENT_UNBONDING_SIGNATURES_RECEIVED
+	CovenantSignatures []*CovenantSig `protobuf:"bytes,1,rep,name=covenant_signatures,json=covenantSignatures,proto3" json:"covenant_signatures,omitempty"`
+	// only set for EVENT_INTENT_UNBONDING_CONFIRMED_ON_BTC
+	BtcConfirmationInfo *BTCConfirmationInfo `protobuf:"bytes,2,opt,name=btc_confirmation_info,json=btcConfirmationInfo,proto3" json:"btc_confirmation_info,omitempty"`
+}
+
+func (x *EventIntentPayload) Reset() {
+	*x = EventIntentPayload{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_transaction_proto_msgTypes[18]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *EventIntentPayload) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EventIntentPayload) ProtoMessage() {}
+
+func (x *EventIntentPayload) ProtoReflect() protoreflect.Message {
+	mi := &file_transaction_proto_msgTypes[18]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EventIntentPayload.ProtoReflect.Descriptor instead.
+func (*EventIntentPayload) Descriptor() ([]byte, []int) {
+	return file_transaction_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *EventIntentPayload) GetCovenantSignatures() []*CovenantSig {
+	if x != nil {
+		return x.CovenantSignatures
+	}
+	return nil
+}
+
+func (x *EventIntentPayload) GetBtcConfirmationInfo() *BTCConfirmationInfo {
+	if x != nil {
+		return x.BtcConfirmationInfo
+	}
+	return nil
+}
+
+// Internal event which was produced, but its state transition was not committed
+// yet. It is replayed on startup if daemon stopped before processing the event.
+type EventIntent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Type EventIntentType `protobuf:"varint,1,opt,name=type,proto3,enum=proto.EventIntentType" json:"type,omitempty"`
+	// serialized EventIntentPayload
+	Payload []byte `protobuf:"bytes,2,opt,name=payload,proto3" json:"payload,omitempty"`
+	// sha256 hash of payload
+	PayloadDigest []byte `protobuf:"bytes,3,opt,name=payload_digest,json=payloadDigest,proto3" json:"payload_digest,omitempty"`
+}
+
+func (x *EventIntent) Reset() {
+	*x = EventIntent{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_transaction_proto_msgTypes[19]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *EventIntent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EventIntent) ProtoMessage() {}
+
+func (x *EventIntent) ProtoReflect() protoreflect.Message {
+	mi := &file_transaction_proto_msgTypes[19]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EventIntent.ProtoReflect.Descriptor instead.
+func (*EventIntent) Descriptor() ([]byte, []int) {
+	return file_transaction_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *EventIntent) GetType() EventIntentType {
+	if x != nil {
+		return x.Type
+	}
+	return EventIntentType_EVENT_INTENT_UNBONDING_SIGNATURES_RECEIVED
+}
+
+func (x *EventIntent) GetPayload() []byte {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+func (x *EventIntent) GetPayloadDigest() []byte {
+	if x != nil {
+		return x.PayloadDigest
+	}
+	return nil
+}
+
+// Proof of possession generated for staker address, reused by subsequent stakes
+// from the same address while babylon key does not change
+type CachedPop struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// babylon public key for which proof of possession was generated
+	BabylonPubKey    []byte `protobuf:"bytes,1,opt,name=babylon_pub_key,json=babylonPubKey,proto3" json:"babylon_pub_key,omitempty"`
+	BtcSigType       uint32 `protobuf:"varint,2,opt,name=btc_sig_type,json=btcSigType,proto3" json:"btc_sig_type,omitempty"`
+	BabylonSigBtcPk  []byte `protobuf:"bytes,3,opt,name=babylon_sig_btc_pk,json=babylonSigBtcPk,proto3" json:"babylon_sig_btc_pk,omitempty"`
+	BtcSigBabylonSig []byte `protobuf:"bytes,4,opt,name=btc_sig_babylon_sig,json=btcSigBabylonSig,proto3" json:"btc_sig_babylon_sig,omitempty"`
+}
+
+func (x *CachedPop) Reset() {
+	*x = CachedPop{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_transaction_proto_msgTypes[20]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CachedPop) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CachedPop) ProtoMessage() {}
+
+func (x *CachedPop) ProtoReflect() protoreflect.Message {
+	mi := &file_transaction_proto_msgTypes[20]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CachedPop.ProtoReflect.Descriptor instead.
+func (*CachedPop) Descriptor() ([]byte, []int) {
+	return file_transaction_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *CachedPop) GetBabylonPubKey() []byte {
+	if x != nil {
+		return x.BabylonPubKey
+	}
+	return nil
+}
+
+func (x *CachedPop) GetBtcSigType() uint32 {
+	if x != nil {
+		return x.BtcSigType
+	}
+	return 0
+}
+
+func (x *CachedPop) GetBabylonSigBtcPk() []byte {
+	if x != nil {
+		return x.BabylonSigBtcPk
+	}
+	return nil
+}
+
+func (x *CachedPop) GetBtcSigBabylonSig() []byte {
+	if x != nil {
+		return x.BtcSigBabylonSig
+	}
+	return nil
+}
+
+// Archiving of terminal transactions, kept until all transactions are scanned so
+// that interrupted archiving can be resumed
+type ArchiveJob struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// transactions which entered their state before this unix time are archived
+	OlderThan int64              `protobuf:"varint,1,opt,name=older_than,json=olderThan,proto3" json:"older_than,omitempty"`
+	States    []TransactionState `protobuf:"varint,2,rep,packed,name=states,proto3,enum=proto.TransactionState" json:"states,omitempty"`
+	// key of the last scanned transaction, archiving resumes after it
+	LastScannedKey uint64 `protobuf:"varint,3,opt,name=last_scanned_key,json=lastScannedKey,proto3" json:"last_scanned_key,omitempty"`
+	// number of transactions archived so far
+	Archived  uint64 `protobuf:"varint,4,opt,name=archived,proto3" json:"archived,omitempty"`
+	StartedAt int64  `protobuf:"varint,5,opt,name=started_at,json=startedAt,proto3" json:"started_at,omitempty"`
+}
+
+func (x *ArchiveJob) Reset() {
+	*x = ArchiveJob{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_transaction_proto_msgTypes[21]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ArchiveJob) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ArchiveJob) ProtoMessage() {}
+
+func (x *ArchiveJob) ProtoReflect() protoreflect.Message {
+	mi := &file_transaction_proto_msgTypes[21]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ArchiveJob.ProtoReflect.Descriptor instead.
+func (*ArchiveJob) Descriptor() ([]byte, []int) {
+	return file_transaction_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *ArchiveJob) GetOlderThan() int64 {
+	if x != nil {
+		return x.OlderThan
+	}
+	return 0
+}
+
+func (x *ArchiveJob) GetStates() []TransactionState {
+	if x != nil {
+		return x.States
+	}
+	return nil
+}
+
+func (x *ArchiveJob) GetLastScannedKey() uint64 {
+	if x != nil {
+		return x.LastScannedKey
+	}
+	return 0
+}
+
+func (x *ArchiveJob) GetArchived() uint64 {
+	if x != nil {
+		return x.Archived
+	}
+	return 0
+}
+
+func (x *ArchiveJob) GetStartedAt() int64 {
+	if x != nil {
+		return x.StartedAt
+	}
+	return 0
+}
+
+var File_transaction_proto protoreflect.FileDescriptor
+
+var file_transaction_proto_rawDesc = []byte{
+	0x0a, 0x11, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x12, 0x05, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xed, 0x03, 0x0a, 0x0d, 0x57,
+	0x61, 0x74, 0x63, 0x68, 0x65, 0x64, 0x54, 0x78, 0x44, 0x61, 0x74, 0x61, 0x12, 0x31, 0x0a, 0x14,
+	0x73, 0x6c, 0x61, 0x73, 0x68, 0x69, 0x6e, 0x67, 0x5f, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63,
+	0x74, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x13, 0x73, 0x6c, 0x61, 0x73,
+	0x68, 0x69, 0x6e, 0x67, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12,
+	0x38, 0x0a, 0x18, 0x73, 0x6c, 0x61, 0x73, 0x68, 0x69, 0x6e, 0x67, 0x5f, 0x74, 0x72, 0x61, 0x6e,
+	0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x73, 0x69, 0x67, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0c, 0x52, 0x16, 0x73, 0x6c, 0x61, 0x73, 0x68, 0x69, 0x6e, 0x67, 0x54, 0x72, 0x61, 0x6e, 0x73,
+	0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x69, 0x67, 0x12, 0x2a, 0x0a, 0x11, 0x73, 0x74, 0x61,
+	0x6b, 0x65, 0x72, 0x5f, 0x62, 0x61, 0x62, 0x79, 0x6c, 0x6f, 0x6e, 0x5f, 0x70, 0x6b, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x0c, 0x52, 0x0f, 0x73, 0x74, 0x61, 0x6b, 0x65, 0x72, 0x42, 0x61, 0x62, 0x79,
+	0x6c, 0x6f, 0x6e, 0x50, 0x6b, 0x12, 0x22, 0x0a, 0x0d, 0x73, 0x74, 0x61, 0x6b, 0x65, 0x72, 0x5f,
+	0x62, 0x74, 0x63, 0x5f, 0x70, 0x6b, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0b, 0x73, 0x74,
+	0x61, 0x6b, 0x65, 0x72, 0x42, 0x74, 0x63, 0x50, 0x6b, 0x12, 0x33, 0x0a, 0x15, 0x75, 0x6e, 0x62,
+	0x6f, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x5f, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69,
+	0x6f, 0x6e, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x14, 0x75, 0x6e, 0x62, 0x6f, 0x6e, 0x64,
+	0x69, 0x6e, 0x67, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x44,
+	0x0a, 0x1e, 0x73, 0x6c, 0x61, 0x73, 0x68, 0x69, 0x6e, 0x67, 0x5f, 0x75, 0x6e, 0x62, 0x6f, 0x6e,
+	0x64, 0x69, 0x6e, 0x67, 0x5f, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e,
+	0x18, 0x06, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x1c, 0x73, 0x6c, 0x61, 0x73, 0x68, 0x69, 0x6e, 0x67,
+	0x55, 0x6e, 0x62, 0x6f, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63,
+	0x74, 0x69, 0x6f, 0x6e, 0x12, 0x4b, 0x0a, 0x22, 0x73, 0x6c, 0x61, 0x73, 0x68, 0x69, 0x6e, 0x67,
+	0x5f, 0x75, 0x6e, 0x62, 0x6f, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x5f, 0x74, 0x72, 0x61, 0x6e, 0x73,
+	0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x73, 0x69, 0x67, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0c,
+	0x52, 0x1f, 0x73, 0x6c, 0x61, 0x73, 0x68, 0x69, 0x6e, 0x67, 0x55, 0x6e, 0x62, 0x6f, 0x6e, 0x64,
+	0x69, 0x6e, 0x67, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x69,
+	0x67, 0x12, 0x25, 0x0a, 0x0e, 0x75, 0x6e, 0x62, 0x6f, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x5f, 0x74,
+	0x69, 0x6d, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0d, 0x75, 0x6e, 0x62, 0x6f, 0x6e,
+	0x64, 0x69, 0x6e, 0x67, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x30, 0x0a, 0x14, 0x75, 0x6e, 0x62, 0x6f,
+	0x6e, 0x64, 0x69, 0x6e, 0x67, 0x5f, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x5f, 0x69, 0x64, 0x78,
+	0x18, 0x09, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x12, 0x75, 0x6e, 0x62, 0x6f, 0x6e, 0x64, 0x69, 0x6e,
+	0x67, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x49, 0x64, 0x78, 0x22, 0x80, 0x01, 0x0a, 0x13, 0x42,
+	0x54, 0x43, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x72, 0x6d, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x6e,
+	0x66, 0x6f, 0x12, 0x21, 0x0a, 0x0c, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x5f, 0x68, 0x65, 0x69, 0x67,
+	0x68, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0b, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x48,
+	0x65, 0x69, 0x67, 0x68, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x5f, 0x68,
+	0x61, 0x73, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x62, 0x6c, 0x6f, 0x63, 0x6b,
+	0x48, 0x61, 0x73, 0x68, 0x12, 0x27, 0x0a, 0x0f, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x5f, 0x74, 0x69,
+	0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0e, 0x62,
+	0x6c, 0x6f, 0x63, 0x6b, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x22, 0x5f, 0x0a,
+	0x0b, 0x43, 0x6f, 0x76, 0x65, 0x6e, 0x61, 0x6e, 0x74, 0x53, 0x69, 0x67, 0x12, 0x21, 0x0a, 0x0c,
+	0x63, 0x6f, 0x76, 0x65, 0x6e, 0x61, 0x6e, 0x74, 0x5f, 0x73, 0x69, 0x67, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0c, 0x52, 0x0b, 0x63, 0x6f, 0x76, 0x65, 0x6e, 0x61, 0x6e, 0x74, 0x53, 0x69, 0x67, 0x12,
+	0x2d, 0x0a, 0x13, 0x63, 0x6f, 0x76, 0x65, 0x6e, 0x61, 0x6e, 0x74, 0x5f, 0x73, 0x69, 0x67, 0x5f,
+	0x62, 0x74, 0x63, 0x5f, 0x70, 0x6b, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x10, 0x63, 0x6f,
+	0x76, 0x65, 0x6e, 0x61, 0x6e, 0x74, 0x53, 0x69, 0x67, 0x42, 0x74, 0x63, 0x50, 0x6b, 0x22, 0xfd,
+	0x02, 0x0a, 0x0f, 0x55, 0x6e, 0x62, 0x6f, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x54, 0x78, 0x44, 0x61,
+	0x74, 0x61, 0x12, 0x33, 0x0a, 0x15, 0x75, 0x6e, 0x62, 0x6f, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x5f,
+	0x74, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0c, 0x52, 0x14, 0x75, 0x6e, 0x62, 0x6f, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x54, 0x72, 0x61, 0x6e,
+	0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x25, 0x0a, 0x0e, 0x75, 0x6e, 0x62, 0x6f, 0x6e,
+	0x64, 0x69, 0x6e, 0x67, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52,
+	0x0d, 0x75, 0x6e, 0x62, 0x6f, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x43,
+	0x0a, 0x13, 0x63, 0x6f, 0x76, 0x65, 0x6e, 0x61, 0x6e, 0x74, 0x5f, 0x73, 0x69, 0x67, 0x6e, 0x61,
+	0x74, 0x75, 0x72, 0x65, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x2e, 0x43, 0x6f, 0x76, 0x65, 0x6e, 0x61, 0x6e, 0x74, 0x53, 0x69, 0x67, 0x52,
+	0x12, 0x63, 0x6f, 0x76, 0x65, 0x6e, 0x61, 0x6e, 0x74, 0x53, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75,
+	0x72, 0x65, 0x73, 0x12, 0x66, 0x0a, 0x22, 0x75, 0x6e, 0x62, 0x6f, 0x6e, 0x64, 0x69, 0x6e, 0x67,
+	0x5f, 0x74, 0x78, 0x5f, 0x62, 0x74, 0x63, 0x5f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x72, 0x6d, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x6e, 0x66, 0x6f, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x1a, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x42, 0x54, 0x43, 0x43, 0x6f, 0x6e, 0x66, 0x69,
+	0x72, 0x6d, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x1e, 0x75, 0x6e, 0x62,
+	0x6f, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x54, 0x78, 0x42, 0x74, 0x63, 0x43, 0x6f, 0x6e, 0x66, 0x69,
+	0x72, 0x6d, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x2f, 0x0a, 0x14, 0x75,
+	0x6e, 0x62, 0x6f, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x5f, 0x74, 0x78, 0x5f, 0x73, 0x65, 0x6e, 0x74,
+	0x5f, 0x61, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x11, 0x75, 0x6e, 0x62, 0x6f, 0x6e,
+	0x64, 0x69, 0x6e, 0x67, 0x54, 0x78, 0x53, 0x65, 0x6e, 0x74, 0x41, 0x74, 0x12, 0x30, 0x0a, 0x14,
+	0x75, 0x6e, 0x62, 0x6f, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x5f, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74,
+	0x5f, 0x69, 0x64, 0x78, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x12, 0x75, 0x6e, 0x62, 0x6f,
+	0x6e, 0x64, 0x69, 0x6e, 0x67, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x49, 0x64, 0x78, 0x22, 0xc4,
+	0x01, 0x0a, 0x0b, 0x53, 0x70, 0x65, 0x6e, 0x64, 0x54, 0x78, 0x44, 0x61, 0x74, 0x61, 0x12, 0x22,
+	0x0a, 0x0d, 0x73, 0x70, 0x65, 0x6e, 0x64, 0x5f, 0x74, 0x78, 0x5f, 0x68, 0x61, 0x73, 0x68, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0b, 0x73, 0x70, 0x65, 0x6e, 0x64, 0x54, 0x78, 0x48, 0x61,
+	0x73, 0x68, 0x12, 0x1f, 0x0a, 0x0b, 0x73, 0x70, 0x65, 0x6e, 0x74, 0x5f, 0x76, 0x61, 0x6c, 0x75,
+	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0a, 0x73, 0x70, 0x65, 0x6e, 0x74, 0x56, 0x61,
+	0x6c, 0x75, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x66, 0x65, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x03, 0x66, 0x65, 0x65, 0x12, 0x5e, 0x0a, 0x1e, 0x73, 0x70, 0x65, 0x6e, 0x64, 0x5f, 0x74,
+	0x78, 0x5f, 0x62, 0x74, 0x63, 0x5f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x72, 0x6d, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x5f, 0x69, 0x6e, 0x66, 0x6f, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x42, 0x54, 0x43, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x72, 0x6d,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x1a, 0x73, 0x70, 0x65, 0x6e, 0x64,
+	0x54, 0x78, 0x42, 0x74, 0x63, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x72, 0x6d, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x49, 0x6e, 0x66, 0x6f, 0x22, 0x5f, 0x0a, 0x0f, 0x53, 0x74, 0x61, 0x74, 0x65, 0x54, 0x72,
+	0x61, 0x6e, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x2d, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x74,
+	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x17, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e,
+	0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x65,
+	0x52, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x63, 0x68, 0x61, 0x6e, 0x67,
+	0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x63, 0x68, 0x61,
+	0x6e, 0x67, 0x65, 0x64, 0x41, 0x74, 0x22, 0xba, 0x0e, 0x0a, 0x12, 0x54, 0x72, 0x61, 0x63, 0x6b,
+	0x65, 0x64, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x36, 0x0a,
+	0x17, 0x74, 0x72, 0x61, 0x63, 0x6b, 0x65, 0x64, 0x5f, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63,
+	0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x78, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x15,
+	0x74, 0x72, 0x61, 0x63, 0x6b, 0x65, 0x64, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69,
+	0x6f, 0x6e, 0x49, 0x64, 0x78, 0x12, 0x2f, 0x0a, 0x13, 0x73, 0x74, 0x61, 0x6b, 0x69, 0x6e, 0x67,
+	0x5f, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x0c, 0x52, 0x12, 0x73, 0x74, 0x61, 0x6b, 0x69, 0x6e, 0x67, 0x54, 0x72, 0x61, 0x6e, 0x73,
+	0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x2c, 0x0a, 0x12, 0x73, 0x74, 0x61, 0x6b, 0x69, 0x6e,
+	0x67, 0x5f, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x5f, 0x69, 0x64, 0x78, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x0d, 0x52, 0x10, 0x73, 0x74, 0x61, 0x6b, 0x69, 0x6e, 0x67, 0x4f, 0x75, 0x74, 0x70, 0x75,
+	0x74, 0x49, 0x64, 0x78, 0x12, 0x25, 0x0a, 0x0e, 0x73, 0x74, 0x61, 0x6b, 0x65, 0x72, 0x5f, 0x61,
+	0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x73, 0x74,
+	0x61, 0x6b, 0x65, 0x72, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x12, 0x21, 0x0a, 0x0c, 0x73,
+	0x74, 0x61, 0x6b, 0x69, 0x6e, 0x67, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28,
+	0x0d, 0x52, 0x0b, 0x73, 0x74, 0x61, 0x6b, 0x69, 0x6e, 0x67, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x3b,
+	0x0a, 0x1a, 0x66, 0x69, 0x6e, 0x61, 0x6c, 0x69, 0x74, 0x79, 0x5f, 0x70, 0x72, 0x6f, 0x76, 0x69,
+	0x64, 0x65, 0x72, 0x73, 0x5f, 0x62, 0x74, 0x63, 0x5f, 0x70, 0x6b, 0x73, 0x18, 0x06, 0x20, 0x03,
+	0x28, 0x0c, 0x52, 0x17, 0x66, 0x69, 0x6e, 0x61, 0x6c, 0x69, 0x74, 0x79, 0x50, 0x72, 0x6f, 0x76,
+	0x69, 0x64, 0x65, 0x72, 0x73, 0x42, 0x74, 0x63, 0x50, 0x6b, 0x73, 0x12, 0x62, 0x0a, 0x20, 0x73,
+	0x74, 0x61, 0x6b, 0x69, 0x6e, 0x67, 0x5f, 0x74, 0x78, 0x5f, 0x62, 0x74, 0x63, 0x5f, 0x63, 0x6f,
+	0x6e, 0x66, 0x69, 0x72, 0x6d, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x6e, 0x66, 0x6f, 0x18,
+	0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x42, 0x54,
+	0x43, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x72, 0x6d, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x6e, 0x66,
+	0x6f, 0x52, 0x1c, 0x73, 0x74, 0x61, 0x6b, 0x69, 0x6e, 0x67, 0x54, 0x78, 0x42, 0x74, 0x63, 0x43,
+	0x6f, 0x6e, 0x66, 0x69, 0x72, 0x6d, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x6e, 0x66, 0x6f, 0x12,
+	0x20, 0x0a, 0x0c, 0x62, 0x74, 0x63, 0x5f, 0x73, 0x69, 0x67, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18,
+	0x08, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0a, 0x62, 0x74, 0x63, 0x53, 0x69, 0x67, 0x54, 0x79, 0x70,
+	0x65, 0x12, 0x2b, 0x0a, 0x12, 0x62, 0x61, 0x62, 0x79, 0x6c, 0x6f, 0x6e, 0x5f, 0x73, 0x69, 0x67,
+	0x5f, 0x62, 0x74, 0x63, 0x5f, 0x70, 0x6b, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0f, 0x62,
+	0x61, 0x62, 0x79, 0x6c, 0x6f, 0x6e, 0x53, 0x69, 0x67, 0x42, 0x74, 0x63, 0x50, 0x6b, 0x12, 0x2d,
+	0x0a, 0x13, 0x62, 0x74, 0x63, 0x5f, 0x73, 0x69, 0x67, 0x5f, 0x62, 0x61, 0x62, 0x79, 0x6c, 0x6f,
+	0x6e, 0x5f, 0x73, 0x69, 0x67, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x10, 0x62, 0x74, 0x63,
+	0x53, 0x69, 0x67, 0x42, 0x61, 0x62, 0x79, 0x6c, 0x6f, 0x6e, 0x53, 0x69, 0x67, 0x12, 0x2d, 0x0a,
+	0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x17, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e,
+	0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x12, 0x18, 0x0a, 0x07,
+	0x77, 0x61, 0x74, 0x63, 0x68, 0x65, 0x64, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x77,
+	0x61, 0x74, 0x63, 0x68, 0x65, 0x64, 0x12, 0x42, 0x0a, 0x11, 0x75, 0x6e, 0x62, 0x6f, 0x6e, 0x64,
+	0x69, 0x6e, 0x67, 0x5f, 0x74, 0x78, 0x5f, 0x64, 0x61, 0x74, 0x61, 0x18, 0x0d, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x16, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x55, 0x6e, 0x62, 0x6f, 0x6e, 0x64,
+	0x69, 0x6e, 0x67, 0x54, 0x78, 0x44, 0x61, 0x74, 0x61, 0x52, 0x0f, 0x75, 0x6e, 0x62, 0x6f, 0x6e,
+	0x64, 0x69, 0x6e, 0x67, 0x54, 0x78, 0x44, 0x61, 0x74, 0x61, 0x12, 0x25, 0x0a, 0x0e, 0x70, 0x61,
+	0x72, 0x61, 0x6d, 0x73, 0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x0e, 0x20, 0x01,
+	0x28, 0x0d, 0x52, 0x0d, 0x70, 0x61, 0x72, 0x61, 0x6d, 0x73, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f,
+	0x6e, 0x12, 0x2b, 0x0a, 0x09, 0x74, 0x78, 0x5f, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x73, 0x18, 0x0f,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x54, 0x78, 0x4c,
+	0x61, 0x62, 0x65, 0x6c, 0x52, 0x08, 0x74, 0x78, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x73, 0x12, 0x38,
+	0x0a, 0x0d, 0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x5f, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x18,
+	0x10, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x43, 0x68,
+	0x61, 0x6e, 0x67, 0x65, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x52, 0x0c, 0x63, 0x68, 0x61, 0x6e,
+	0x67, 0x65, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x12, 0x26, 0x0a, 0x0f, 0x62, 0x61, 0x62, 0x79,
+	0x6c, 0x6f, 0x6e, 0x5f, 0x74, 0x78, 0x5f, 0x68, 0x61, 0x73, 0x68, 0x18, 0x11, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0d, 0x62, 0x61, 0x62, 0x79, 0x6c, 0x6f, 0x6e, 0x54, 0x78, 0x48, 0x61, 0x73, 0x68,
+	0x12, 0x21, 0x0a, 0x0c, 0x62, 0x61, 0x62, 0x79, 0x6c, 0x6f, 0x6e, 0x5f, 0x6d, 0x65, 0x6d, 0x6f,
+	0x18, 0x12, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x62, 0x61, 0x62, 0x79, 0x6c, 0x6f, 0x6e, 0x4d,
+	0x65, 0x6d, 0x6f, 0x12, 0x28, 0x0a, 0x10, 0x73, 0x74, 0x61, 0x74, 0x65, 0x5f, 0x63, 0x68, 0x61,
+	0x6e, 0x67, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x13, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0e, 0x73,
+	0x74, 0x61, 0x74, 0x65, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x64, 0x41, 0x74, 0x12, 0x3b, 0x0a,
+	0x0d, 0x73, 0x74, 0x61, 0x74, 0x65, 0x5f, 0x68, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x18, 0x14,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x53, 0x74, 0x61,
+	0x74, 0x65, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0c, 0x73, 0x74,
+	0x61, 0x74, 0x65, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x12, 0x36, 0x0a, 0x0d, 0x73, 0x70,
+	0x65, 0x6e, 0x64, 0x5f, 0x74, 0x78, 0x5f, 0x64, 0x61, 0x74, 0x61, 0x18, 0x15, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x12, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x53, 0x70, 0x65, 0x6e, 0x64, 0x54,
+	0x78, 0x44, 0x61, 0x74, 0x61, 0x52, 0x0b, 0x73, 0x70, 0x65, 0x6e, 0x64, 0x54, 0x78, 0x44, 0x61,
+	0x74, 0x61, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x18, 0x16, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x05, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x12, 0x25, 0x0a, 0x0e, 0x72, 0x65, 0x71, 0x75,
+	0x69, 0x72, 0x65, 0x64, 0x5f, 0x64, 0x65, 0x70, 0x74, 0x68, 0x18, 0x17, 0x20, 0x01, 0x28, 0x0d,
+	0x52, 0x0d, 0x72, 0x65, 0x71, 0x75, 0x69, 0x72, 0x65, 0x64, 0x44, 0x65, 0x70, 0x74, 0x68, 0x12,
+	0x52, 0x0a, 0x1a, 0x73, 0x74, 0x61, 0x6b, 0x69, 0x6e, 0x67, 0x5f, 0x74, 0x78, 0x5f, 0x69, 0x6e,
+	0x63, 0x6c, 0x75, 0x73, 0x69, 0x6f, 0x6e, 0x5f, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x18, 0x18, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x49, 0x6e, 0x63, 0x6c,
+	0x75, 0x73, 0x69, 0x6f, 0x6e, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x52, 0x17, 0x73, 0x74, 0x61, 0x6b,
+	0x69, 0x6e, 0x67, 0x54, 0x78, 0x49, 0x6e, 0x63, 0x6c, 0x75, 0x73, 0x69, 0x6f, 0x6e, 0x50, 0x72,
+	0x6f, 0x6f, 0x66, 0x12, 0x2b, 0x0a, 0x11, 0x63, 0x6f, 0x72, 0x72, 0x75, 0x70, 0x74, 0x69, 0x6f,
+	0x6e, 0x5f, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x18, 0x19, 0x20, 0x01, 0x28, 0x09, 0x52, 0x10,
+	0x63, 0x6f, 0x72, 0x72, 0x75, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x61, 0x73, 0x6f, 0x6e,
+	0x12, 0x1d, 0x0a, 0x0a, 0x61, 0x75, 0x74, 0x6f, 0x5f, 0x72, 0x65, 0x6e, 0x65, 0x77, 0x18, 0x1a,
+	0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x61, 0x75, 0x74, 0x6f, 0x52, 0x65, 0x6e, 0x65, 0x77, 0x12,
+	0x26, 0x0a, 0x0f, 0x72, 0x65, 0x6e, 0x65, 0x77, 0x61, 0x6c, 0x5f, 0x74, 0x78, 0x5f, 0x68, 0x61,
+	0x73, 0x68, 0x18, 0x1b, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0d, 0x72, 0x65, 0x6e, 0x65, 0x77, 0x61,
+	0x6c, 0x54, 0x78, 0x48, 0x61, 0x73, 0x68, 0x12, 0x2f, 0x0a, 0x14, 0x72, 0x65, 0x6e, 0x65, 0x77,
+	0x65, 0x64, 0x5f, 0x66, 0x72, 0x6f, 0x6d, 0x5f, 0x74, 0x78, 0x5f, 0x68, 0x61, 0x73, 0x68, 0x18,
+	0x1c, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x11, 0x72, 0x65, 0x6e, 0x65, 0x77, 0x65, 0x64, 0x46, 0x72,
+	0x6f, 0x6d, 0x54, 0x78, 0x48, 0x61, 0x73, 0x68, 0x12, 0x33, 0x0a, 0x16, 0x61, 0x75, 0x74, 0x6f,
+	0x5f, 0x72, 0x65, 0x6e, 0x65, 0x77, 0x5f, 0x73, 0x6b, 0x69, 0x70, 0x5f, 0x72, 0x65, 0x61, 0x73,
+	0x6f, 0x6e, 0x18, 0x1d, 0x20, 0x01, 0x28, 0x09, 0x52, 0x13, 0x61, 0x75, 0x74, 0x6f, 0x52, 0x65,
+	0x6e, 0x65, 0x77, 0x53, 0x6b, 0x69, 0x70, 0x52, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x12, 0x44, 0x0a,
+	0x14, 0x73, 0x74, 0x61, 0x6b, 0x69, 0x6e, 0x67, 0x5f, 0x66, 0x65, 0x65, 0x5f, 0x65, 0x73, 0x74,
+	0x69, 0x6d, 0x61, 0x74, 0x65, 0x18, 0x1e, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x2e, 0x46, 0x65, 0x65, 0x45, 0x73, 0x74, 0x69, 0x6d, 0x61, 0x74, 0x65, 0x52,
+	0x12, 0x73, 0x74, 0x61, 0x6b, 0x69, 0x6e, 0x67, 0x46, 0x65, 0x65, 0x45, 0x73, 0x74, 0x69, 0x6d,
+	0x61, 0x74, 0x65, 0x12, 0x48, 0x0a, 0x16, 0x75, 0x6e, 0x62, 0x6f, 0x6e, 0x64, 0x69, 0x6e, 0x67,
+	0x5f, 0x66, 0x65, 0x65, 0x5f, 0x65, 0x73, 0x74, 0x69, 0x6d, 0x61, 0x74, 0x65, 0x18, 0x1f, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x46, 0x65, 0x65, 0x45,
+	0x73, 0x74, 0x69, 0x6d, 0x61, 0x74, 0x65, 0x52, 0x14, 0x75, 0x6e, 0x62, 0x6f, 0x6e, 0x64, 0x69,
+	0x6e, 0x67, 0x46, 0x65, 0x65, 0x45, 0x73, 0x74, 0x69, 0x6d, 0x61, 0x74, 0x65, 0x12, 0x40, 0x0a,
+	0x12, 0x73, 0x70, 0x65, 0x6e, 0x64, 0x5f, 0x66, 0x65, 0x65, 0x5f, 0x65, 0x73, 0x74, 0x69, 0x6d,
+	0x61, 0x74, 0x65, 0x18, 0x20, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x2e, 0x46, 0x65, 0x65, 0x45, 0x73, 0x74, 0x69, 0x6d, 0x61, 0x74, 0x65, 0x52, 0x10, 0x73,
+	0x70, 0x65, 0x6e, 0x64, 0x46, 0x65, 0x65, 0x45, 0x73, 0x74, 0x69, 0x6d, 0x61, 0x74, 0x65, 0x12,
+	0x34, 0x0a, 0x16, 0x75, 0x6e, 0x62, 0x6f, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x5f, 0x77, 0x61, 0x69,
+	0x74, 0x5f, 0x61, 0x62, 0x6f, 0x72, 0x74, 0x65, 0x64, 0x18, 0x21, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x14, 0x75, 0x6e, 0x62, 0x6f, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x57, 0x61, 0x69, 0x74, 0x41, 0x62,
+	0x6f, 0x72, 0x74, 0x65, 0x64, 0x12, 0x2e, 0x0a, 0x13, 0x63, 0x6f, 0x6e, 0x66, 0x6c, 0x69, 0x63,
+	0x74, 0x69, 0x6e, 0x67, 0x5f, 0x74, 0x78, 0x5f, 0x68, 0x61, 0x73, 0x68, 0x18, 0x22, 0x20, 0x01,
+	0x28, 0x0c, 0x52, 0x11, 0x63, 0x6f, 0x6e, 0x66, 0x6c, 0x69, 0x63, 0x74, 0x69, 0x6e, 0x67, 0x54,
+	0x78, 0x48, 0x61, 0x73, 0x68, 0x12, 0x29, 0x0a, 0x10, 0x62, 0x72, 0x6f, 0x61, 0x64, 0x63, 0x61,
+	0x73, 0x74, 0x5f, 0x68, 0x65, 0x69, 0x67, 0x68, 0x74, 0x18, 0x23, 0x20, 0x01, 0x28, 0x0d, 0x52,
+	0x0f, 0x62, 0x72, 0x6f, 0x61, 0x64, 0x63, 0x61, 0x73, 0x74, 0x48, 0x65, 0x69, 0x67, 0x68, 0x74,
+	0x12, 0x51, 0x0a, 0x16, 0x62, 0x61, 0x62, 0x79, 0x6c, 0x6f, 0x6e, 0x5f, 0x73, 0x75, 0x62, 0x6d,
+	0x69, 0x74, 0x5f, 0x66, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x18, 0x24, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x1b, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x42, 0x61, 0x62, 0x79, 0x6c, 0x6f, 0x6e,
+	0x53, 0x75, 0x62, 0x6d, 0x69, 0x74, 0x46, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x52, 0x14, 0x62,
+	0x61, 0x62, 0x79, 0x6c, 0x6f, 0x6e, 0x53, 0x75, 0x62, 0x6d, 0x69, 0x74, 0x46, 0x61, 0x69, 0x6c,
+	0x75, 0x72, 0x65, 0x22, 0x87, 0x02, 0x0a, 0x14, 0x42, 0x61, 0x62, 0x79, 0x6c, 0x6f, 0x6e, 0x53,
+	0x75, 0x62, 0x6d, 0x69, 0x74, 0x46, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x12, 0x1c, 0x0a, 0x09,
+	0x6f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x09, 0x6f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1c, 0x0a, 0x09, 0x63, 0x6f,
+	0x64, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x63,
+	0x6f, 0x64, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x63, 0x6f, 0x64, 0x65,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x04, 0x63, 0x6f, 0x64, 0x65, 0x12, 0x17, 0x0a, 0x07,
+	0x72, 0x61, 0x77, 0x5f, 0x6c, 0x6f, 0x67, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x72,
+	0x61, 0x77, 0x4c, 0x6f, 0x67, 0x12, 0x1a, 0x0a, 0x08, 0x65, 0x78, 0x65, 0x63, 0x75, 0x74, 0x65,
+	0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x65, 0x78, 0x65, 0x63, 0x75, 0x74, 0x65,
+	0x64, 0x12, 0x26, 0x0a, 0x0f, 0x62, 0x61, 0x62, 0x79, 0x6c, 0x6f, 0x6e, 0x5f, 0x74, 0x78, 0x5f,
+	0x68, 0x61, 0x73, 0x68, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x62, 0x61, 0x62, 0x79,
+	0x6c, 0x6f, 0x6e, 0x54, 0x78, 0x48, 0x61, 0x73, 0x68, 0x12, 0x25, 0x0a, 0x0e, 0x62, 0x61, 0x62,
+	0x79, 0x6c, 0x6f, 0x6e, 0x5f, 0x68, 0x65, 0x69, 0x67, 0x68, 0x74, 0x18, 0x07, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x0d, 0x62, 0x61, 0x62, 0x79, 0x6c, 0x6f, 0x6e, 0x48, 0x65, 0x69, 0x67, 0x68, 0x74,
+	0x12, 0x1b, 0x0a, 0x09, 0x66, 0x61, 0x69, 0x6c, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x08, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x08, 0x66, 0x61, 0x69, 0x6c, 0x65, 0x64, 0x41, 0x74, 0x22, 0xc9, 0x01,
+	0x0a, 0x0b, 0x46, 0x65, 0x65, 0x45, 0x73, 0x74, 0x69, 0x6d, 0x61, 0x74, 0x65, 0x12, 0x1c, 0x0a,
+	0x09, 0x65, 0x73, 0x74, 0x69, 0x6d, 0x61, 0x74, 0x6f, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x09, 0x65, 0x73, 0x74, 0x69, 0x6d, 0x61, 0x74, 0x6f, 0x72, 0x12, 0x1f, 0x0a, 0x0b, 0x63,
+	0x6f, 0x6e, 0x66, 0x5f, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d,
+	0x52, 0x0a, 0x63, 0x6f, 0x6e, 0x66, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x12, 0x2c, 0x0a, 0x12,
+	0x65, 0x73, 0x74, 0x69, 0x6d, 0x61, 0x74, 0x65, 0x64, 0x5f, 0x66, 0x65, 0x65, 0x5f, 0x72, 0x61,
+	0x74, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x10, 0x65, 0x73, 0x74, 0x69, 0x6d, 0x61,
+	0x74, 0x65, 0x64, 0x46, 0x65, 0x65, 0x52, 0x61, 0x74, 0x65, 0x12, 0x2a, 0x0a, 0x11, 0x66, 0x61,
+	0x6c, 0x6c, 0x62, 0x61, 0x63, 0x6b, 0x5f, 0x66, 0x65, 0x65, 0x5f, 0x72, 0x61, 0x74, 0x65, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0f, 0x66, 0x61, 0x6c, 0x6c, 0x62, 0x61, 0x63, 0x6b, 0x46,
+	0x65, 0x65, 0x52, 0x61, 0x74, 0x65, 0x12, 0x21, 0x0a, 0x0c, 0x65, 0x73, 0x74, 0x69, 0x6d, 0x61,
+	0x74, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0b, 0x65, 0x73,
+	0x74, 0x69, 0x6d, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74, 0x22, 0x71, 0x0a, 0x0e, 0x49, 0x6e, 0x63,
+	0x6c, 0x75, 0x73, 0x69, 0x6f, 0x6e, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x12, 0x21, 0x0a, 0x0c, 0x62,
+	0x6c, 0x6f, 0x63, 0x6b, 0x5f, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0c, 0x52, 0x0b, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x12, 0x19,
+	0x0a, 0x08, 0x74, 0x78, 0x5f, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d,
+	0x52, 0x07, 0x74, 0x78, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x12, 0x21, 0x0a, 0x0c, 0x6d, 0x65, 0x72,
+	0x6b, 0x6c, 0x65, 0x5f, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52,
+	0x0b, 0x6d, 0x65, 0x72, 0x6b, 0x6c, 0x65, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x22, 0x5f, 0x0a, 0x0c,
+	0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x12, 0x1d, 0x0a, 0x0a,
+	0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x5f, 0x69, 0x64, 0x78, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d,
+	0x52, 0x09, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x49, 0x64, 0x78, 0x12, 0x16, 0x0a, 0x06, 0x61,
+	0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x61, 0x6d, 0x6f,
+	0x75, 0x6e, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x22, 0x38, 0x0a,
+	0x07, 0x54, 0x78, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x12, 0x17, 0x0a, 0x07, 0x74, 0x78, 0x5f, 0x68,
+	0x61, 0x73, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x74, 0x78, 0x48, 0x61, 0x73,
+	0x68, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x05, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x22, 0xe2, 0x01, 0x0a, 0x19, 0x52, 0x65, 0x63, 0x6f,
+	0x6e, 0x63, 0x69, 0x6c, 0x69, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x44, 0x69, 0x73, 0x63, 0x72, 0x65,
+	0x70, 0x61, 0x6e, 0x63, 0x79, 0x12, 0x26, 0x0a, 0x0f, 0x73, 0x74, 0x61, 0x6b, 0x69, 0x6e, 0x67,
+	0x5f, 0x74, 0x78, 0x5f, 0x68, 0x61, 0x73, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0d,
+	0x73, 0x74, 0x61, 0x6b, 0x69, 0x6e, 0x67, 0x54, 0x78, 0x48, 0x61, 0x73, 0x68, 0x12, 0x38, 0x0a,
+	0x0b, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x0e, 0x32, 0x17, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x54, 0x72, 0x61, 0x6e, 0x73,
+	0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x0a, 0x6c, 0x6f, 0x63,
+	0x61, 0x6c, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x41, 0x0a, 0x10, 0x64, 0x69, 0x73, 0x63, 0x72,
+	0x65, 0x70, 0x61, 0x6e, 0x63, 0x79, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x0e, 0x32, 0x16, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x44, 0x69, 0x73, 0x63, 0x72, 0x65,
+	0x70, 0x61, 0x6e, 0x63, 0x79, 0x54, 0x79, 0x70, 0x65, 0x52, 0x0f, 0x64, 0x69, 0x73, 0x63, 0x72,
+	0x65, 0x70, 0x61, 0x6e, 0x63, 0x79, 0x54, 0x79, 0x70, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x64, 0x65,
+	0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0b, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0xb0, 0x01, 0x0a,
+	0x14, 0x52, 0x65, 0x63, 0x6f, 0x6e, 0x63, 0x69, 0x6c, 0x69, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52,
+	0x65, 0x70, 0x6f, 0x72, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64,
+	0x5f, 0x61, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x63, 0x72, 0x65, 0x61, 0x74,
+	0x65, 0x64, 0x41, 0x74, 0x12, 0x31, 0x0a, 0x14, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x65, 0x64, 0x5f,
+	0x74, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x04, 0x52, 0x13, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x65, 0x64, 0x54, 0x72, 0x61, 0x6e, 0x73,
+	0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x46, 0x0a, 0x0d, 0x64, 0x69, 0x73, 0x63, 0x72,
+	0x65, 0x70, 0x61, 0x6e, 0x63, 0x69, 0x65, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x20,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x52, 0x65, 0x63, 0x6f, 0x6e, 0x63, 0x69, 0x6c, 0x69,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x44, 0x69, 0x73, 0x63, 0x72, 0x65, 0x70, 0x61, 0x6e, 0x63, 0x79,
+	0x52, 0x0d, 0x64, 0x69, 0x73, 0x63, 0x72, 0x65, 0x70, 0x61, 0x6e, 0x63, 0x69, 0x65, 0x73, 0x22,
+	0xc7, 0x01, 0x0a, 0x14, 0x53, 0x74, 0x61, 0x6b, 0x69, 0x6e, 0x67, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x72, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x72, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x49, 0x64, 0x12, 0x33, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75,
+	0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1b, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e,
+	0x53, 0x74, 0x61, 0x6b, 0x69, 0x6e, 0x67, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x53, 0x74,
+	0x61, 0x74, 0x75, 0x73, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x26, 0x0a, 0x0f,
+	0x73, 0x74, 0x61, 0x6b, 0x69, 0x6e, 0x67, 0x5f, 0x74, 0x78, 0x5f, 0x68, 0x61, 0x73, 0x68, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0d, 0x73, 0x74, 0x61, 0x6b, 0x69, 0x6e, 0x67, 0x54, 0x78,
+	0x48, 0x61, 0x73, 0x68, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x1d, 0x0a, 0x0a, 0x75, 0x70,
+	0x64, 0x61, 0x74, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09,
+	0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74, 0x22, 0x7d, 0x0a, 0x15, 0x53, 0x74, 0x61,
+	0x6b, 0x69, 0x6e, 0x67, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x73, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68,
+	0x6f, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0d, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x21, 0x0a, 0x0c,
+	0x63, 0x6f, 0x76, 0x65, 0x6e, 0x61, 0x6e, 0x74, 0x5f, 0x70, 0x6b, 0x73, 0x18, 0x02, 0x20, 0x03,
+	0x28, 0x0c, 0x52, 0x0b, 0x63, 0x6f, 0x76, 0x65, 0x6e, 0x61, 0x6e, 0x74, 0x50, 0x6b, 0x73, 0x12,
+	0x27, 0x0a, 0x0f, 0x63, 0x6f, 0x76, 0x65, 0x6e, 0x61, 0x6e, 0x74, 0x5f, 0x71, 0x75, 0x6f, 0x72,
+	0x75, 0x6d, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0e, 0x63, 0x6f, 0x76, 0x65, 0x6e, 0x61,
+	0x6e, 0x74, 0x51, 0x75, 0x6f, 0x72, 0x75, 0x6d, 0x22, 0xc9, 0x02, 0x0a, 0x0d, 0x41, 0x75, 0x64,
+	0x69, 0x74, 0x4c, 0x6f, 0x67, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x73, 0x65,
+	0x71, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x03, 0x73, 0x65, 0x71, 0x12, 0x29, 0x0a, 0x04,
+	0x74, 0x79, 0x70, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x15, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x2e, 0x41, 0x75, 0x64, 0x69, 0x74, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x54, 0x79, 0x70,
+	0x65, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73,
+	0x74, 0x61, 0x6d, 0x70, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x74, 0x69, 0x6d, 0x65,
+	0x73, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x16, 0x0a, 0x06, 0x63, 0x61, 0x6c, 0x6c, 0x65, 0x72, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x63, 0x61, 0x6c, 0x6c, 0x65, 0x72, 0x12, 0x16, 0x0a,
+	0x06, 0x6d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x6d,
+	0x65, 0x74, 0x68, 0x6f, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x61, 0x72, 0x67, 0x73, 0x18, 0x06, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x04, 0x61, 0x72, 0x67, 0x73, 0x12, 0x1f, 0x0a, 0x0b, 0x72, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x5f, 0x73, 0x65, 0x71, 0x18, 0x07, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0a,
+	0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x53, 0x65, 0x71, 0x12, 0x17, 0x0a, 0x07, 0x74, 0x78,
+	0x5f, 0x68, 0x61, 0x73, 0x68, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x74, 0x78, 0x48,
+	0x61, 0x73, 0x68, 0x12, 0x2c, 0x0a, 0x12, 0x73, 0x74, 0x61, 0x6b, 0x69, 0x6e, 0x67, 0x5f, 0x72,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x09, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x10, 0x73, 0x74, 0x61, 0x6b, 0x69, 0x6e, 0x67, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x49,
+	0x64, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x1b, 0x0a, 0x09, 0x70, 0x72, 0x65, 0x76, 0x5f,
+	0x68, 0x61, 0x73, 0x68, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x08, 0x70, 0x72, 0x65, 0x76,
+	0x48, 0x61, 0x73, 0x68, 0x22, 0x62, 0x0a, 0x0b, 0x53, 0x77, 0x65, 0x65, 0x70, 0x49, 0x6e, 0x74,
+	0x65, 0x6e, 0x74, 0x12, 0x2f, 0x0a, 0x13, 0x64, 0x65, 0x73, 0x74, 0x69, 0x6e, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x5f, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x12, 0x64, 0x65, 0x73, 0x74, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x41, 0x64, 0x64,
+	0x72, 0x65, 0x73, 0x73, 0x12, 0x22, 0x0a, 0x0d, 0x73, 0x77, 0x65, 0x65, 0x70, 0x5f, 0x74, 0x78,
+	0x5f, 0x68, 0x61, 0x73, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0b, 0x73, 0x77, 0x65,
+	0x65, 0x70, 0x54, 0x78, 0x48, 0x61, 0x73, 0x68, 0x22, 0xa9, 0x01, 0x0a, 0x12, 0x45, 0x76, 0x65,
+	0x6e, 0x74, 0x49, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x50, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x12,
+	0x43, 0x0a, 0x13, 0x63, 0x6f, 0x76, 0x65, 0x6e, 0x61, 0x6e, 0x74, 0x5f, 0x73, 0x69, 0x67, 0x6e,
+	0x61, 0x74, 0x75, 0x72, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x43, 0x6f, 0x76, 0x65, 0x6e, 0x61, 0x6e, 0x74, 0x53, 0x69, 0x67,
+	0x52, 0x12, 0x63, 0x6f, 0x76, 0x65, 0x6e, 0x61, 0x6e, 0x74, 0x53, 0x69, 0x67, 0x6e, 0x61, 0x74,
+	0x75, 0x72, 0x65, 0x73, 0x12, 0x4e, 0x0a, 0x15, 0x62, 0x74, 0x63, 0x5f, 0x63, 0x6f, 0x6e, 0x66,
+	0x69, 0x72, 0x6d, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x6e, 0x66, 0x6f, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x42, 0x54, 0x43, 0x43,
+	0x6f, 0x6e, 0x66, 0x69, 0x72, 0x6d, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x6e, 0x66, 0x6f, 0x52,
+	0x13, 0x62, 0x74, 0x63, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x72, 0x6d, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x49, 0x6e, 0x66, 0x6f, 0x22, 0x7a, 0x0a, 0x0b, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x49, 0x6e, 0x74,
+	0x65, 0x6e, 0x74, 0x12, 0x2a, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0e, 0x32, 0x16, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x49,
+	0x6e, 0x74, 0x65, 0x6e, 0x74, 0x54, 0x79, 0x70, 0x65, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12,
+	0x18, 0x0a, 0x07, 0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c,
+	0x52, 0x07, 0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x12, 0x25, 0x0a, 0x0e, 0x70, 0x61, 0x79,
+	0x6c, 0x6f, 0x61, 0x64, 0x5f, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x0c, 0x52, 0x0d, 0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x44, 0x69, 0x67, 0x65, 0x73, 0x74,
+	0x22, 0xb1, 0x01, 0x0a, 0x09, 0x43, 0x61, 0x63, 0x68, 0x65, 0x64, 0x50, 0x6f, 0x70, 0x12, 0x26,
+	0x0a, 0x0f, 0x62, 0x61, 0x62, 0x79, 0x6c, 0x6f, 0x6e, 0x5f, 0x70, 0x75, 0x62, 0x5f, 0x6b, 0x65,
+	0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0d, 0x62, 0x61, 0x62, 0x79, 0x6c, 0x6f, 0x6e,
+	0x50, 0x75, 0x62, 0x4b, 0x65, 0x79, 0x12, 0x20, 0x0a, 0x0c, 0x62, 0x74, 0x63, 0x5f, 0x73, 0x69,
+	0x67, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0a, 0x62, 0x74,
+	0x63, 0x53, 0x69, 0x67, 0x54, 0x79, 0x70, 0x65, 0x12, 0x2b, 0x0a, 0x12, 0x62, 0x61, 0x62, 0x79,
+	0x6c, 0x6f, 0x6e, 0x5f, 0x73, 0x69, 0x67, 0x5f, 0x62, 0x74, 0x63, 0x5f, 0x70, 0x6b, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x0c, 0x52, 0x0f, 0x62, 0x61, 0x62, 0x79, 0x6c, 0x6f, 0x6e, 0x53, 0x69, 0x67,
+	0x42, 0x74, 0x63, 0x50, 0x6b, 0x12, 0x2d, 0x0a, 0x13, 0x62, 0x74, 0x63, 0x5f, 0x73, 0x69, 0x67,
+	0x5f, 0x62, 0x61, 0x62, 0x79, 0x6c, 0x6f, 0x6e, 0x5f, 0x73, 0x69, 0x67, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x0c, 0x52, 0x10, 0x62, 0x74, 0x63, 0x53, 0x69, 0x67, 0x42, 0x61, 0x62, 0x79, 0x6c, 0x6f,
+	0x6e, 0x53, 0x69, 0x67, 0x22, 0xc1, 0x01, 0x0a, 0x0a, 0x41, 0x72, 0x63, 0x68, 0x69, 0x76, 0x65,
+	0x4a, 0x6f, 0x62, 0x12, 0x1d, 0x0a, 0x0a, 0x6f, 0x6c, 0x64, 0x65, 0x72, 0x5f, 0x74, 0x68, 0x61,
+	0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x6f, 0x6c, 0x64, 0x65, 0x72, 0x54, 0x68,
+	0x61, 0x6e, 0x12, 0x2f, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03,
+	0x28, 0x0e, 0x32, 0x17, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x54, 0x72, 0x61, 0x6e, 0x73,
+	0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x06, 0x73, 0x74, 0x61,
+	0x74, 0x65, 0x73, 0x12, 0x28, 0x0a, 0x10, 0x6c, 0x61, 0x73, 0x74, 0x5f, 0x73, 0x63, 0x61, 0x6e,
+	0x6e, 0x65, 0x64, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0e, 0x6c,
+	0x61, 0x73, 0x74, 0x53, 0x63, 0x61, 0x6e, 0x6e, 0x65, 0x64, 0x4b, 0x65, 0x79, 0x12, 0x1a, 0x0a,
+	0x08, 0x61, 0x72, 0x63, 0x68, 0x69, 0x76, 0x65, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x04, 0x52,
+	0x08, 0x61, 0x72, 0x63, 0x68, 0x69, 0x76, 0x65, 0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x74, 0x61,
+	0x72, 0x74, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x73,
+	0x74, 0x61, 0x72, 0x74, 0x65, 0x64, 0x41, 0x74, 0x2a, 0x8f, 0x02, 0x0a, 0x10, 0x54, 0x72, 0x61,
+	0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x0f, 0x0a,
+	0x0b, 0x53, 0x45, 0x4e, 0x54, 0x5f, 0x54, 0x4f, 0x5f, 0x42, 0x54, 0x43, 0x10, 0x00, 0x12, 0x14,
+	0x0a, 0x10, 0x43, 0x4f, 0x4e, 0x46, 0x49, 0x52, 0x4d, 0x45, 0x44, 0x5f, 0x4f, 0x4e, 0x5f, 0x42,
+	0x54, 0x43, 0x10, 0x01, 0x12, 0x13, 0x0a, 0x0f, 0x53, 0x45, 0x4e, 0x54, 0x5f, 0x54, 0x4f, 0x5f,
+	0x42, 0x41, 0x42, 0x59, 0x4c, 0x4f, 0x4e, 0x10, 0x02, 0x12, 0x15, 0x0a, 0x11, 0x44, 0x45, 0x4c,
+	0x45, 0x47, 0x41, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x41, 0x43, 0x54, 0x49, 0x56, 0x45, 0x10, 0x03,
+	0x12, 0x1e, 0x0a, 0x1a, 0x55, 0x4e, 0x42, 0x4f, 0x4e, 0x44, 0x49, 0x4e, 0x47, 0x5f, 0x43, 0x4f,
+	0x4e, 0x46, 0x49, 0x52, 0x4d, 0x45, 0x44, 0x5f, 0x4f, 0x4e, 0x5f, 0x42, 0x54, 0x43, 0x10, 0x04,
+	0x12, 0x10, 0x0a, 0x0c, 0x53, 0x50, 0x45, 0x4e, 0x54, 0x5f, 0x4f, 0x4e, 0x5f, 0x42, 0x54, 0x43,
+	0x10, 0x05, 0x12, 0x12, 0x0a, 0x0e, 0x4d, 0x49, 0x53, 0x53, 0x49, 0x4e, 0x47, 0x5f, 0x4f, 0x4e,
+	0x5f, 0x42, 0x54, 0x43, 0x10, 0x06, 0x12, 0x16, 0x0a, 0x12, 0x44, 0x45, 0x4c, 0x45, 0x47, 0x41,
+	0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x45, 0x58, 0x50, 0x49, 0x52, 0x45, 0x44, 0x10, 0x07, 0x12, 0x20,
+	0x0a, 0x1c, 0x55, 0x4e, 0x42, 0x4f, 0x4e, 0x44, 0x49, 0x4e, 0x47, 0x5f, 0x53, 0x49, 0x47, 0x4e,
+	0x41, 0x54, 0x55, 0x52, 0x45, 0x53, 0x5f, 0x54, 0x49, 0x4d, 0x45, 0x4f, 0x55, 0x54, 0x10, 0x08,
+	0x12, 0x0e, 0x0a, 0x0a, 0x43, 0x4f, 0x4e, 0x46, 0x4c, 0x49, 0x43, 0x54, 0x45, 0x44, 0x10, 0x09,
+	0x12, 0x18, 0x0a, 0x14, 0x44, 0x45, 0x4c, 0x45, 0x47, 0x41, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x43,
+	0x41, 0x4e, 0x43, 0x45, 0x4c, 0x4c, 0x45, 0x44, 0x10, 0x0a, 0x2a, 0x7f, 0x0a, 0x0f, 0x44, 0x69,
+	0x73, 0x63, 0x72, 0x65, 0x70, 0x61, 0x6e, 0x63, 0x79, 0x54, 0x79, 0x70, 0x65, 0x12, 0x16, 0x0a,
+	0x12, 0x4d, 0x49, 0x53, 0x53, 0x49, 0x4e, 0x47, 0x5f, 0x4f, 0x4e, 0x5f, 0x42, 0x41, 0x42, 0x59,
+	0x4c, 0x4f, 0x4e, 0x10, 0x00, 0x12, 0x13, 0x0a, 0x0f, 0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x5f,
+	0x4d, 0x49, 0x53, 0x4d, 0x41, 0x54, 0x43, 0x48, 0x10, 0x01, 0x12, 0x1c, 0x0a, 0x18, 0x55, 0x4e,
+	0x44, 0x45, 0x4c, 0x45, 0x47, 0x41, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x4e, 0x4f, 0x54, 0x5f, 0x54,
+	0x52, 0x41, 0x43, 0x4b, 0x45, 0x44, 0x10, 0x02, 0x12, 0x21, 0x0a, 0x1d, 0x44, 0x45, 0x4c, 0x45,
+	0x47, 0x41, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x45, 0x58, 0x50, 0x49, 0x52, 0x45, 0x44, 0x5f, 0x4f,
+	0x4e, 0x5f, 0x42, 0x41, 0x42, 0x59, 0x4c, 0x4f, 0x4e, 0x10, 0x03, 0x2a, 0x6e, 0x0a, 0x14, 0x53,
+	0x74, 0x61, 0x6b, 0x69, 0x6e, 0x67, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x53, 0x74, 0x61,
+	0x74, 0x75, 0x73, 0x12, 0x1b, 0x0a, 0x17, 0x53, 0x54, 0x41, 0x4b, 0x49, 0x4e, 0x47, 0x5f, 0x52,
+	0x45, 0x51, 0x55, 0x45, 0x53, 0x54, 0x5f, 0x50, 0x45, 0x4e, 0x44, 0x49, 0x4e, 0x47, 0x10, 0x00,
+	0x12, 0x1d, 0x0a, 0x19, 0x53, 0x54, 0x41, 0x4b, 0x49, 0x4e, 0x47, 0x5f, 0x52, 0x45, 0x51, 0x55,
+	0x45, 0x53, 0x54, 0x5f, 0x42, 0x52, 0x4f, 0x41, 0x44, 0x43, 0x41, 0x53, 0x54, 0x10, 0x01, 0x12,
+	0x1a, 0x0a, 0x16, 0x53, 0x54, 0x41, 0x4b, 0x49, 0x4e, 0x47, 0x5f, 0x52, 0x45, 0x51, 0x55, 0x45,
+	0x53, 0x54, 0x5f, 0x46, 0x41, 0x49, 0x4c, 0x45, 0x44, 0x10, 0x02, 0x2a, 0x4d, 0x0a, 0x0e, 0x41,
+	0x75, 0x64, 0x69, 0x74, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x54, 0x79, 0x70, 0x65, 0x12, 0x1d, 0x0a,
+	0x19, 0x41, 0x55, 0x44, 0x49, 0x54, 0x5f, 0x4f, 0x50, 0x45, 0x52, 0x41, 0x54, 0x49, 0x4f, 0x4e,
+	0x5f, 0x52, 0x45, 0x51, 0x55, 0x45, 0x53, 0x54, 0x45, 0x44, 0x10, 0x00, 0x12, 0x1c, 0x0a, 0x18,
+	0x41, 0x55, 0x44, 0x49, 0x54, 0x5f, 0x4f, 0x50, 0x45, 0x52, 0x41, 0x54, 0x49, 0x4f, 0x4e, 0x5f,
+	0x46, 0x49, 0x4e, 0x49, 0x53, 0x48, 0x45, 0x44, 0x10, 0x01, 0x2a, 0x6e, 0x0a, 0x0f, 0x45, 0x76,
+	0x65, 0x6e, 0x74, 0x49, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x54, 0x79, 0x70, 0x65, 0x12, 0x2e, 0x0a,
+	0x2a, 0x45, 0x56, 0x45, 0x4e, 0x54, 0x5f, 0x49, 0x4e, 0x54, 0x45, 0x4e, 0x54, 0x5f, 0x55, 0x4e,
+	0x42, 0x4f, 0x4e, 0x44, 0x49, 0x4e, 0x47, 0x5f, 0x53, 0x49, 0x47, 0x4e, 0x41, 0x54, 0x55, 0x52,
+	0x45, 0x53, 0x5f, 0x52, 0x45, 0x43, 0x45, 0x49, 0x56, 0x45, 0x44, 0x10, 0x00, 0x12, 0x2b, 0x0a,
+	0x27, 0x45, 0x56, 0x45, 0x4e, 0x54, 0x5f, 0x49, 0x4e, 0x54, 0x45, 0x4e, 0x54, 0x5f, 0x55, 0x4e,
+	0x42, 0x4f, 0x4e, 0x44, 0x49, 0x4e, 0x47, 0x5f, 0x43, 0x4f, 0x4e, 0x46, 0x49, 0x52, 0x4d, 0x45,
+	0x44, 0x5f, 0x4f, 0x4e, 0x5f, 0x42, 0x54, 0x43, 0x10, 0x01, 0x42, 0x2a, 0x5a, 0x28, 0x67, 0x69,
+	0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x62, 0x61, 0x62, 0x79, 0x6c, 0x6f, 0x6e,
+	0x63, 0x68, 0x61, 0x69, 0x6e, 0x2f, 0x62, 0x74, 0x63, 0x2d, 0x73, 0x74, 0x61, 0x6b, 0x65, 0x72,
+	0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_transaction_proto_rawDescOnce sync.Once
 	file_transaction_proto_rawDescData = file_transaction_proto_rawDesc
 )
 
@@ -649,27 +2712,68 @@ func file_transaction_proto_rawDescGZIP() []byte {
 	return file_transaction_proto_rawDescData
 }
 
-var file_transaction_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_transaction_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
+var file_transaction_proto_enumTypes = make([]protoimpl.EnumInfo, 5)
+var file_transaction_proto_msgTypes = make([]protoimpl.MessageInfo, 22)
 var file_transaction_proto_goTypes = []interface{}{
-	(TransactionState)(0),       // 0: proto.TransactionState
-	(*WatchedTxData)(nil),       // 1: proto.WatchedTxData
-	(*BTCConfirmationInfo)(nil), // 2: proto.BTCConfirmationInfo
-	(*CovenantSig)(nil),         // 3: proto.CovenantSig
-	(*UnbondingTxData)(nil),     // 4: proto.UnbondingTxData
-	(*TrackedTransaction)(nil),  // 5: proto.TrackedTransaction
+	(TransactionState)(0),             // 0: proto.TransactionState
+	(DiscrepancyType)(0),              // 1: proto.DiscrepancyType
+	(StakingRequestStatus)(0),         // 2: proto.StakingRequestStatus
+	(AuditEntryType)(0),               // 3: proto.AuditEntryType
+	(EventIntentType)(0),              // 4: proto.EventIntentType
+	(*WatchedTxData)(nil),             // 5: proto.WatchedTxData
+	(*BTCConfirmationInfo)(nil),       // 6: proto.BTCConfirmationInfo
+	(*CovenantSig)(nil),               // 7: proto.CovenantSig
+	(*UnbondingTxData)(nil),           // 8: proto.UnbondingTxData
+	(*SpendTxData)(nil),               // 9: proto.SpendTxData
+	(*StateTransition)(nil),           // 10: proto.StateTransition
+	(*TrackedTransaction)(nil),        // 11: proto.TrackedTransaction
+	(*BabylonSubmitFailure)(nil),      // 12: proto.BabylonSubmitFailure
+	(*FeeEstimate)(nil),               // 13: proto.FeeEstimate
+	(*InclusionProof)(nil),            // 14: proto.InclusionProof
+	(*ChangeOutput)(nil),              // 15: proto.ChangeOutput
+	(*TxLabel)(nil),                   // 16: proto.TxLabel
+	(*ReconciliationDiscrepancy)(nil), // 17: proto.ReconciliationDiscrepancy
+	(*ReconciliationReport)(nil),      // 18: proto.ReconciliationReport
+	(*StakingRequestRecord)(nil),      // 19: proto.StakingRequestRecord
+	(*StakingParamsSnapshot)(nil),     // 20: proto.StakingParamsSnapshot
+	(*AuditLogEntry)(nil),             // 21: proto.AuditLogEntry
+	(*SweepIntent)(nil),               // 22: proto.SweepIntent
+	(*EventIntentPayload)(nil),        // 23: proto.EventIntentPayload
+	(*EventIntent)(nil),               // 24: proto.EventIntent
+	(*CachedPop)(nil),                 // 25: proto.CachedPop
+	(*ArchiveJob)(nil),                // 26: proto.ArchiveJob
 }
 var file_transaction_proto_depIdxs = []int32{
-	3, // 0: proto.UnbondingTxData.covenant_signatures:type_name -> proto.CovenantSig
-	2, // 1: proto.UnbondingTxData.unbonding_tx_btc_confirmation_info:type_name -> proto.BTCConfirmationInfo
-	2, // 2: proto.TrackedTransaction.staking_tx_btc_confirmation_info:type_name -> proto.BTCConfirmationInfo
-	0, // 3: proto.TrackedTransaction.state:type_name -> proto.TransactionState
-	4, // 4: proto.TrackedTransaction.unbonding_tx_data:type_name -> proto.UnbondingTxData
-	5, // [5:5] is the sub-list for method output_type
-	5, // [5:5] is the sub-list for method input_type
-	5, // [5:5] is the sub-list for extension type_name
-	5, // [5:5] is the sub-list for extension extendee
-	0, // [0:5] is the sub-list for field type_name
+	7,  // 0: proto.UnbondingTxData.covenant_signatures:type_name -> proto.CovenantSig
+	6,  // 1: proto.UnbondingTxData.unbonding_tx_btc_confirmation_info:type_name -> proto.BTCConfirmationInfo
+	6,  // 2: proto.SpendTxData.spend_tx_btc_confirmation_info:type_name -> proto.BTCConfirmationInfo
+	0,  // 3: proto.StateTransition.state:type_name -> proto.TransactionState
+	6,  // 4: proto.TrackedTransaction.staking_tx_btc_confirmation_info:type_name -> proto.BTCConfirmationInfo
+	0,  // 5: proto.TrackedTransaction.state:type_name -> proto.TransactionState
+	8,  // 6: proto.TrackedTransaction.unbonding_tx_data:type_name -> proto.UnbondingTxData
+	16, // 7: proto.TrackedTransaction.tx_labels:type_name -> proto.TxLabel
+	15, // 8: proto.TrackedTransaction.change_output:type_name -> proto.ChangeOutput
+	10, // 9: proto.TrackedTransaction.state_history:type_name -> proto.StateTransition
+	9,  // 10: proto.TrackedTransaction.spend_tx_data:type_name -> proto.SpendTxData
+	14, // 11: proto.TrackedTransaction.staking_tx_inclusion_proof:type_name -> proto.InclusionProof
+	13, // 12: proto.TrackedTransaction.staking_fee_estimate:type_name -> proto.FeeEstimate
+	13, // 13: proto.TrackedTransaction.unbonding_fee_estimate:type_name -> proto.FeeEstimate
+	13, // 14: proto.TrackedTransaction.spend_fee_estimate:type_name -> proto.FeeEstimate
+	12, // 15: proto.TrackedTransaction.babylon_submit_failure:type_name -> proto.BabylonSubmitFailure
+	0,  // 16: proto.ReconciliationDiscrepancy.local_state:type_name -> proto.TransactionState
+	1,  // 17: proto.ReconciliationDiscrepancy.discrepancy_type:type_name -> proto.DiscrepancyType
+	17, // 18: proto.ReconciliationReport.discrepancies:type_name -> proto.ReconciliationDiscrepancy
+	2,  // 19: proto.StakingRequestRecord.status:type_name -> proto.StakingRequestStatus
+	3,  // 20: proto.AuditLogEntry.type:type_name -> proto.AuditEntryType
+	7,  // 21: proto.EventIntentPayload.covenant_signatures:type_name -> proto.CovenantSig
+	6,  // 22: proto.EventIntentPayload.btc_confirmation_info:type_name -> proto.BTCConfirmationInfo
+	4,  // 23: proto.EventIntent.type:type_name -> proto.EventIntentType
+	0,  // 24: proto.ArchiveJob.states:type_name -> proto.TransactionState
+	25, // [25:25] is the sub-list for method output_type
+	25, // [25:25] is the sub-list for method input_type
+	25, // [25:25] is the sub-list for extension type_name
+	25, // [25:25] is the sub-list for extension extendee
+	0,  // [0:25] is the sub-list for field type_name
 }
 
 func init() { file_transaction_proto_init() }
@@ -727,6 +2831,30 @@ func file_transaction_proto_init() {
 			}
 		}
 		file_transaction_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SpendTxData); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_transaction_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StateTransition); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_transaction_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*TrackedTransaction); i {
 			case 0:
 				return &v.state
@@ -738,14 +2866,194 @@ func file_transaction_proto_init() {
 				return nil
 			}
 		}
+		file_transaction_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BabylonSubmitFailure); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_transaction_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FeeEstimate); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_transaction_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*InclusionProof); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_transaction_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ChangeOutput); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_transaction_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TxLabel); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_transaction_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ReconciliationDiscrepancy); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_transaction_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ReconciliationReport); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_transaction_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StakingRequestRecord); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_transaction_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StakingParamsSnapshot); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_transaction_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AuditLogEntry); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_transaction_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SweepIntent); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_transaction_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*EventIntentPayload); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_transaction_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*EventIntent); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_transaction_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CachedPop); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_transaction_proto_msgTypes[21].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ArchiveJob); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_transaction_proto_rawDesc,
-			NumEnums:      1,
-			NumMessages:   5,
+			NumEnums:      5,
+			NumMessages:   22,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
@@ -0,0 +1,340 @@
+// Package sdk is Go client of staker daemon json rpc api. Contrary to
+// stakerservice/client, its methods accept typed arguments, return typed errors,
+// and retry requests which failed because daemon could not be reached.
+package sdk
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	jsonrpcclient "github.com/cometbft/cometbft/rpc/jsonrpc/client"
+)
+
+const (
+	defaultTimeout      = 30 * time.Second
+	defaultPollInterval = 5 * time.Second
+
+	idempotencyKeySize = 16
+
+	// connections kept open between requests, so that busy client does not
+	// dial daemon for every request
+	maxIdleConnsPerHost = 16
+	idleConnTimeout     = 90 * time.Second
+	tlsHandshakeTimeout = 10 * time.Second
+)
+
+// RetryPolicy controls retries of requests which failed before daemon responded
+// e.g because it was restarting. Requests rejected by daemon are never retried.
+type RetryPolicy struct {
+	// number of attempts of each request including the first one. Values lower
+	// than two disable retries.
+	MaxAttempts int
+	// backoff before the first retry, doubled on every next retry
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+}
+
+// DefaultRetryPolicy returns retry policy used by clients created without
+// WithRetryPolicy option
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: 500 * time.Millisecond,
+		MaxBackoff:     5 * time.Second,
+	}
+}
+
+func (p RetryPolicy) backoff(retry int) time.Duration {
+	backoff := p.InitialBackoff
+
+	for i := 0; i < retry && backoff < p.MaxBackoff; i++ {
+		backoff *= 2
+	}
+
+	if p.MaxBackoff > 0 && backoff > p.MaxBackoff {
+		return p.MaxBackoff
+	}
+
+	return backoff
+}
+
+type options struct {
+	timeout      time.Duration
+	pollInterval time.Duration
+	user         string
+	pass         string
+	authToken    string
+	tlsConfig    *tls.Config
+	retryPolicy  RetryPolicy
+}
+
+func defaultOptions() options {
+	return options{
+		timeout:      defaultTimeout,
+		pollInterval: defaultPollInterval,
+		retryPolicy:  DefaultRetryPolicy(),
+	}
+}
+
+// Option configures Client
+type Option func(*options)
+
+// WithTimeout sets timeout of single attempt of request. Zero disables timeout,
+// so only deadline of context passed to the request applies.
+func WithTimeout(timeout time.Duration) Option {
+	return func(o *options) {
+		o.timeout = timeout
+	}
+}
+
+// WithBasicAuth sets credentials required by tcp listeners of daemon with
+// configured rpcuser and rpcpass
+func WithBasicAuth(user, pass string) Option {
+	return func(o *options) {
+		o.user = user
+		o.pass = pass
+	}
+}
+
+// WithAuthToken sends token as bearer token with every request. Daemon itself
+// only authenticates with basic credentials, so token is only useful when daemon
+// is exposed behind authenticating proxy.
+func WithAuthToken(token string) Option {
+	return func(o *options) {
+		o.authToken = token
+	}
+}
+
+// WithTLSConfig configures client of daemon TLS listener, with address in
+// https://<host>:<port> format. It allows to trust self signed certificate of
+// the daemon.
+func WithTLSConfig(tlsConfig *tls.Config) Option {
+	return func(o *options) {
+		o.tlsConfig = tlsConfig
+	}
+}
+
+// WithRetryPolicy replaces default retry policy
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(o *options) {
+		o.retryPolicy = policy
+	}
+}
+
+// WithPollInterval sets how often WaitForState queries state of transaction
+func WithPollInterval(interval time.Duration) Option {
+	return func(o *options) {
+		o.pollInterval = interval
+	}
+}
+
+// Client of staker daemon. It is safe for concurrent use.
+type Client struct {
+	rpc  *jsonrpcclient.Client
+	opts options
+}
+
+// NewClient creates client of staker daemon listening on address e.g
+// tcp://localhost:15812, unix:///var/run/stakerd.sock or https://host:15812
+func NewClient(address string, opts ...Option) (*Client, error) {
+	o := defaultOptions()
+
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	httpClient, err := jsonrpcclient.DefaultHTTPClient(address)
+	if err != nil {
+		return nil, err
+	}
+
+	transport, ok := httpClient.Transport.(*http.Transport)
+	if !ok {
+		return nil, fmt.Errorf("unexpected http transport type %T", httpClient.Transport)
+	}
+
+	// connections are kept alive by default transport, but only two of them
+	// are reused per host
+	transport.MaxIdleConns = maxIdleConnsPerHost
+	transport.MaxIdleConnsPerHost = maxIdleConnsPerHost
+	transport.IdleConnTimeout = idleConnTimeout
+	transport.TLSHandshakeTimeout = tlsHandshakeTimeout
+
+	if o.tlsConfig != nil {
+		transport.TLSClientConfig = o.tlsConfig
+	}
+
+	httpClient.Transport = &authTransport{
+		next:      httpClient.Transport,
+		user:      o.user,
+		pass:      o.pass,
+		authToken: o.authToken,
+	}
+
+	rpc, err := jsonrpcclient.NewWithHTTPClient(address, httpClient)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Client{
+		rpc:  rpc,
+		opts: o,
+	}, nil
+}
+
+// authTransport adds configured credentials to requests, and reports requests
+// rejected for missing or wrong credentials as ErrUnauthorized
+type authTransport struct {
+	next      http.RoundTripper
+	user      string
+	pass      string
+	authToken string
+}
+
+func (t *authTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.user != "" || t.pass != "" || t.authToken != "" {
+		// request must not be modified by round tripper
+		req = req.Clone(req.Context())
+
+		if t.user != "" || t.pass != "" {
+			req.SetBasicAuth(t.user, t.pass)
+		}
+
+		if t.authToken != "" {
+			req.Header.Set("Authorization", "Bearer "+t.authToken)
+		}
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		_ = resp.Body.Close()
+		return nil, ErrUnauthorized
+	}
+
+	return resp, nil
+}
+
+// callKind describes whether request can be safely retried
+type callKind int
+
+const (
+	// request does not change state of daemon, or changes it in a way which
+	// does not depend on number of calls
+	idempotentCall callKind = iota
+	// request moves funds, so it is retried with the same idempotency key,
+	// which lets daemon answer retries without repeating the operation
+	keyedCall
+	// request which is never retried
+	onceCall
+)
+
+type idempotencyKeyCtxKey struct{}
+
+// ContextWithIdempotencyKey sets idempotency key sent with request which moves
+// funds i.e Stake, StakeAsync, SpendStake and Unbond. Without it, client
+// generates new key for every request, which only deduplicates retries made by
+// the client itself. Setting the key allows to deduplicate retries made by the
+// application. Key must be unique per operation, as daemon returns result of
+// the first request with given key to all later requests with it. Daemon keeps
+// keys in memory only, so they are forgotten when daemon restarts.
+func ContextWithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKeyCtxKey{}, key)
+}
+
+func idempotencyKey(ctx context.Context) (string, error) {
+	if key, ok := ctx.Value(idempotencyKeyCtxKey{}).(string); ok && key != "" {
+		return key, nil
+	}
+
+	key := make([]byte, idempotencyKeySize)
+
+	if _, err := rand.Read(key); err != nil {
+		return "", fmt.Errorf("failed to generate idempotency key: %w", err)
+	}
+
+	return hex.EncodeToString(key), nil
+}
+
+func (c *Client) call(
+	ctx context.Context,
+	kind callKind,
+	method string,
+	params map[string]interface{},
+	result interface{},
+) error {
+	if params == nil {
+		params = make(map[string]interface{})
+	}
+
+	if kind == keyedCall {
+		key, err := idempotencyKey(ctx)
+		if err != nil {
+			return err
+		}
+
+		params["idempotencyKey"] = key
+	}
+
+	attempts := c.opts.retryPolicy.MaxAttempts
+
+	if attempts < 1 || kind == onceCall {
+		attempts = 1
+	}
+
+	var err error
+
+	for attempt := 0; attempt < attempts; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(c.opts.retryPolicy.backoff(attempt - 1)):
+			}
+		}
+
+		err = c.callOnce(ctx, method, params, result)
+
+		if !c.retryable(ctx, err) {
+			break
+		}
+	}
+
+	return mapError(err)
+}
+
+func (c *Client) callOnce(ctx context.Context, method string, params map[string]interface{}, result interface{}) error {
+	if c.opts.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.opts.timeout)
+		defer cancel()
+	}
+
+	_, err := c.rpc.Call(ctx, method, params, result)
+
+	return err
+}
+
+// retryable returns true if request failed before daemon responded to it
+func (c *Client) retryable(ctx context.Context, err error) bool {
+	if err == nil || ctx.Err() != nil {
+		return false
+	}
+
+	var rpcErr *RPCError
+
+	if asRPCError(err, &rpcErr) {
+		return false
+	}
+
+	return !errors.Is(err, ErrUnauthorized)
+}
@@ -0,0 +1,343 @@
+package sdk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/babylonchain/btc-staker/proto"
+	service "github.com/babylonchain/btc-staker/stakerservice"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	rpctypes "github.com/cometbft/cometbft/rpc/jsonrpc/types"
+	"github.com/stretchr/testify/require"
+)
+
+// testDaemonHandler answers n-th request received by test daemon. Nil response
+// makes daemon unavailable for that request.
+type testDaemonHandler func(n int, method string, params map[string]json.RawMessage) *rpctypes.RPCResponse
+
+type testDaemon struct {
+	t       *testing.T
+	handler testDaemonHandler
+
+	mu       sync.Mutex
+	requests []map[string]json.RawMessage
+}
+
+func newTestDaemon(t *testing.T, handler testDaemonHandler) (*testDaemon, *httptest.Server) {
+	d := &testDaemon{t: t, handler: handler}
+
+	server := httptest.NewServer(d)
+	t.Cleanup(server.Close)
+
+	return d, server
+}
+
+func (d *testDaemon) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req rpctypes.RPCRequest
+	require.NoError(d.t, json.NewDecoder(r.Body).Decode(&req))
+
+	params := make(map[string]json.RawMessage)
+	require.NoError(d.t, json.Unmarshal(req.Params, &params))
+
+	d.mu.Lock()
+	n := len(d.requests)
+	d.requests = append(d.requests, params)
+	d.mu.Unlock()
+
+	resp := d.handler(n, req.Method, params)
+
+	if resp == nil {
+		http.Error(w, "daemon is restarting", http.StatusServiceUnavailable)
+		return
+	}
+
+	resp.ID = req.ID
+
+	w.Header().Set("Content-Type", "application/json")
+	require.NoError(d.t, json.NewEncoder(w).Encode(resp))
+}
+
+func (d *testDaemon) receivedRequests() []map[string]json.RawMessage {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return append([]map[string]json.RawMessage(nil), d.requests...)
+}
+
+func successResponse(result interface{}) *rpctypes.RPCResponse {
+	resp := rpctypes.NewRPCSuccessResponse(nil, result)
+	return &resp
+}
+
+func handlerErrorResponse(err error) *rpctypes.RPCResponse {
+	resp := rpctypes.RPCInternalError(nil, err)
+	return &resp
+}
+
+func newTestClient(t *testing.T, server *httptest.Server, opts ...Option) *Client {
+	fastRetries := WithRetryPolicy(RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     time.Millisecond,
+	})
+
+	client, err := NewClient(server.URL, append([]Option{fastRetries}, opts...)...)
+	require.NoError(t, err)
+
+	return client
+}
+
+func testStakeRequest(t *testing.T) StakeRequest {
+	privKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	addr, err := btcutil.NewAddressTaproot(make([]byte, 32), &chaincfg.RegressionNetParams)
+	require.NoError(t, err)
+
+	return StakeRequest{
+		StakerAddress:     addr,
+		Amount:            btcutil.Amount(100000),
+		FinalityProviders: []*btcec.PublicKey{privKey.PubKey()},
+		StakingTimeBlocks: 1000,
+	}
+}
+
+func TestStakeRetriedWithTheSameIdempotencyKey(t *testing.T) {
+	d, server := newTestDaemon(t, func(n int, method string, params map[string]json.RawMessage) *rpctypes.RPCResponse {
+		if n < 2 {
+			return nil
+		}
+
+		return successResponse(&service.ResultStake{TxHash: "hash"})
+	})
+
+	result, err := newTestClient(t, server).Stake(context.Background(), testStakeRequest(t))
+	require.NoError(t, err)
+	require.Equal(t, "hash", result.TxHash)
+
+	requests := d.receivedRequests()
+	require.Len(t, requests, 3)
+
+	key := requests[0]["idempotencyKey"]
+	require.NotEmpty(t, key)
+
+	for _, req := range requests {
+		require.Equal(t, key, req["idempotencyKey"])
+	}
+}
+
+func TestIdempotencyKeyFromContext(t *testing.T) {
+	d, server := newTestDaemon(t, func(n int, method string, params map[string]json.RawMessage) *rpctypes.RPCResponse {
+		return successResponse(&service.ResultStake{TxHash: "hash"})
+	})
+
+	client := newTestClient(t, server)
+	ctx := ContextWithIdempotencyKey(context.Background(), "operation-1")
+
+	_, err := client.Stake(ctx, testStakeRequest(t))
+	require.NoError(t, err)
+	_, err = client.Stake(ctx, testStakeRequest(t))
+	require.NoError(t, err)
+
+	for _, req := range d.receivedRequests() {
+		require.JSONEq(t, `"operation-1"`, string(req["idempotencyKey"]))
+	}
+}
+
+func TestRequestsRejectedByDaemonAreNotRetried(t *testing.T) {
+	d, server := newTestDaemon(t, func(n int, method string, params map[string]json.RawMessage) *rpctypes.RPCResponse {
+		return handlerErrorResponse(ErrBroadcastsPaused)
+	})
+
+	_, err := newTestClient(t, server).Stake(context.Background(), testStakeRequest(t))
+	require.Error(t, err)
+	require.ErrorIs(t, err, ErrBroadcastsPaused)
+	require.ErrorIs(t, err, ErrRequestFailed)
+	require.False(t, errors.Is(err, ErrWalletNotSynced))
+
+	var rpcErr *RPCError
+	require.True(t, errors.As(err, &rpcErr))
+	require.Equal(t, codeInternalError, rpcErr.Code)
+
+	require.Len(t, d.receivedRequests(), 1)
+}
+
+func TestErrorCodesAreMapped(t *testing.T) {
+	tests := []struct {
+		name     string
+		response func() rpctypes.RPCResponse
+		expected error
+	}{
+		{
+			name:     "method not found",
+			response: func() rpctypes.RPCResponse { return rpctypes.RPCMethodNotFoundError(nil) },
+			expected: ErrMethodNotFound,
+		},
+		{
+			name:     "invalid params",
+			response: func() rpctypes.RPCResponse { return rpctypes.RPCInvalidParamsError(nil, errors.New("bad param")) },
+			expected: ErrInvalidParams,
+		},
+		{
+			name:     "invalid request",
+			response: func() rpctypes.RPCResponse { return rpctypes.RPCInvalidRequestError(nil, errors.New("bad request")) },
+			expected: ErrInvalidRequest,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, server := newTestDaemon(t, func(n int, method string, params map[string]json.RawMessage) *rpctypes.RPCResponse {
+				resp := tc.response()
+				return &resp
+			})
+
+			_, err := newTestClient(t, server).Health(context.Background())
+			require.ErrorIs(t, err, tc.expected)
+			require.False(t, errors.Is(err, ErrRequestFailed))
+		})
+	}
+}
+
+func TestNonIdempotentRequestsAreNotRetried(t *testing.T) {
+	d, server := newTestDaemon(t, func(n int, method string, params map[string]json.RawMessage) *rpctypes.RPCResponse {
+		return nil
+	})
+
+	addr, err := btcutil.NewAddressTaproot(make([]byte, 32), &chaincfg.RegressionNetParams)
+	require.NoError(t, err)
+
+	_, err = newTestClient(t, server).RecoverDb(context.Background(), addr, "", false, nil)
+	require.Error(t, err)
+	require.Len(t, d.receivedRequests(), 1)
+}
+
+func TestUnavailableDaemonFailsAfterAllAttempts(t *testing.T) {
+	d, server := newTestDaemon(t, func(n int, method string, params map[string]json.RawMessage) *rpctypes.RPCResponse {
+		return nil
+	})
+
+	_, err := newTestClient(t, server).Health(context.Background())
+	require.Error(t, err)
+	require.Len(t, d.receivedRequests(), 3)
+}
+
+func TestAuthentication(t *testing.T) {
+	handler := func(n int, method string, params map[string]json.RawMessage) *rpctypes.RPCResponse {
+		return successResponse(&service.ResultHealth{})
+	}
+
+	d := &testDaemon{t: t, handler: handler}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, pass, ok := r.BasicAuth()
+		if !ok || user != "user" || pass != "pass" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		d.ServeHTTP(w, r)
+	}))
+	t.Cleanup(server.Close)
+
+	_, err := newTestClient(t, server).Health(context.Background())
+	require.ErrorIs(t, err, ErrUnauthorized)
+
+	_, err = newTestClient(t, server, WithBasicAuth("user", "wrong")).Health(context.Background())
+	require.ErrorIs(t, err, ErrUnauthorized)
+
+	_, err = newTestClient(t, server, WithBasicAuth("user", "pass")).Health(context.Background())
+	require.NoError(t, err)
+
+	// rejected requests never reached the daemon, and were not retried
+	require.Len(t, d.receivedRequests(), 1)
+}
+
+func TestWaitForState(t *testing.T) {
+	txHash := chainhash.HashH([]byte("staking tx"))
+
+	tests := []struct {
+		name        string
+		states      []proto.TransactionState
+		target      proto.TransactionState
+		unreachable bool
+	}{
+		{
+			name: "target state reached",
+			states: []proto.TransactionState{
+				proto.TransactionState_SENT_TO_BTC,
+				proto.TransactionState_CONFIRMED_ON_BTC,
+				proto.TransactionState_SENT_TO_BABYLON,
+			},
+			target: proto.TransactionState_SENT_TO_BABYLON,
+		},
+		{
+			name: "transaction moved to state from which target cannot be reached",
+			states: []proto.TransactionState{
+				proto.TransactionState_CONFIRMED_ON_BTC,
+				proto.TransactionState_MISSING_ON_BTC,
+			},
+			target:      proto.TransactionState_DELEGATION_ACTIVE,
+			unreachable: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			d, server := newTestDaemon(t, func(n int, method string, params map[string]json.RawMessage) *rpctypes.RPCResponse {
+				require.Equal(t, "transaction_details", method)
+				require.JSONEq(t, `"`+txHash.String()+`"`, string(params["stakingTxHash"]))
+
+				if n >= len(tc.states) {
+					n = len(tc.states) - 1
+				}
+
+				return successResponse(&service.TransactionDetailsResponse{
+					StakingTxHash: txHash.String(),
+					StakingState:  tc.states[n].String(),
+				})
+			})
+
+			client := newTestClient(t, server, WithPollInterval(time.Millisecond))
+
+			details, err := client.WaitForState(context.Background(), txHash, tc.target)
+
+			if tc.unreachable {
+				require.ErrorIs(t, err, ErrStateUnreachable)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tc.target.String(), details.StakingState)
+			}
+
+			require.Len(t, d.receivedRequests(), len(tc.states))
+		})
+	}
+}
+
+func TestWaitForStateStopsWithContext(t *testing.T) {
+	txHash := chainhash.HashH([]byte("staking tx"))
+
+	_, server := newTestDaemon(t, func(n int, method string, params map[string]json.RawMessage) *rpctypes.RPCResponse {
+		return successResponse(&service.TransactionDetailsResponse{
+			StakingTxHash: txHash.String(),
+			StakingState:  proto.TransactionState_SENT_TO_BTC.String(),
+		})
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	client := newTestClient(t, server, WithPollInterval(5*time.Millisecond))
+
+	_, err := client.WaitForState(ctx, txHash, proto.TransactionState_DELEGATION_ACTIVE)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
@@ -0,0 +1,188 @@
+package sdk
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/babylonchain/btc-staker/staker"
+	"github.com/babylonchain/btc-staker/stakerdb"
+	service "github.com/babylonchain/btc-staker/stakerservice"
+	rpctypes "github.com/cometbft/cometbft/rpc/jsonrpc/types"
+)
+
+// json rpc error codes used by daemon
+const (
+	codeInvalidRequest = -32600
+	codeMethodNotFound = -32601
+	codeInvalidParams  = -32602
+	codeInternalError  = -32603
+)
+
+var (
+	// ErrUnauthorized daemon rejected credentials of the client
+	ErrUnauthorized = errors.New("unauthorized, check credentials of staker daemon")
+
+	// ErrInvalidRequest daemon could not parse the request
+	ErrInvalidRequest = errors.New("invalid json rpc request")
+
+	// ErrMethodNotFound daemon does not support requested method, most probably
+	// because it is older than the client
+	ErrMethodNotFound = errors.New("method not supported by staker daemon")
+
+	// ErrInvalidParams request params could not be decoded by daemon
+	ErrInvalidParams = errors.New("invalid request params")
+
+	// ErrRequestFailed daemon decoded the request, but failed to handle it. It is
+	// matched by all errors returned by request handlers of daemon.
+	ErrRequestFailed = errors.New("staker daemon failed to handle request")
+
+	// ErrStateUnreachable transaction moved to state from which state awaited by
+	// WaitForState cannot be reached
+	ErrStateUnreachable = errors.New("transaction can no longer reach requested state")
+)
+
+// Errors returned by daemon, which can be matched with errors.Is against
+// RPCError
+var (
+	ErrTransactionNotFound         = stakerdb.ErrTransactionNotFound
+	ErrStakingRequestNotFound      = stakerdb.ErrStakingRequestNotFound
+	ErrInvalidTransactionState     = stakerdb.ErrInvalidTransactionState
+	ErrInvalidLabel                = stakerdb.ErrInvalidLabel
+	ErrInvalidNote                 = stakerdb.ErrInvalidNote
+	ErrTransactionArchived         = stakerdb.ErrTransactionArchived
+	ErrArchiveInProgress           = stakerdb.ErrArchiveInProgress
+	ErrStateNotArchivable          = stakerdb.ErrStateNotArchivable
+	ErrRecoveryTargetNotEmpty      = stakerdb.ErrRecoveryTargetNotEmpty
+	ErrWalletNotSynced             = staker.ErrWalletNotSynced
+	ErrBroadcastsPaused            = staker.ErrBroadcastsPaused
+	ErrMaxActiveDelegationsReached = staker.ErrMaxActiveDelegationsReached
+	ErrStakingTimeAboveLimit       = staker.ErrStakingTimeAboveLimit
+	ErrInvalidFinalityProviderKey  = staker.ErrInvalidFinalityProviderKey
+	ErrAutoRenewNotAllowed         = staker.ErrAutoRenewNotAllowed
+	ErrWatchedTxNotSignable        = staker.ErrWatchedTxNotSignable
+	ErrIdempotencyKeyReused        = service.ErrIdempotencyKeyReused
+
+	// errors of invalid slashing transactions in watch staking requests
+	ErrSlashingTxWrongInput          = staker.ErrSlashingTxWrongInput
+	ErrSlashingTxWrongSlashingOutput = staker.ErrSlashingTxWrongSlashingOutput
+	ErrSlashingTxFeeTooLow           = staker.ErrSlashingTxFeeTooLow
+	ErrInvalidSlashingPathScript     = staker.ErrInvalidSlashingPathScript
+	ErrInvalidSlashingTxSig          = staker.ErrInvalidSlashingTxSig
+
+	// errors of retrying and aborting waits for covenant unbonding signatures
+	ErrUnbondingSigsNotTimedOut = staker.ErrUnbondingSigsNotTimedOut
+	ErrUnbondingTxSent          = staker.ErrUnbondingTxSent
+
+	// errors of cancelling delegations not sent to babylon
+	ErrDelegationSubmitted          = staker.ErrDelegationSubmitted
+	ErrDelegationSubmissionInFlight = staker.ErrDelegationSubmissionInFlight
+
+	// errors of staked value cap and its override
+	ErrStakedValueCapReached  = staker.ErrStakedValueCapReached
+	ErrAuthenticationRequired = service.ErrAuthenticationRequired
+)
+
+var codeErrors = map[error]int{
+	ErrInvalidRequest: codeInvalidRequest,
+	ErrMethodNotFound: codeMethodNotFound,
+	ErrInvalidParams:  codeInvalidParams,
+	ErrRequestFailed:  codeInternalError,
+}
+
+// only message of error returned by daemon is sent to the client, so daemon
+// errors are recognized by their message
+var daemonErrors = []error{
+	ErrTransactionNotFound,
+	ErrStakingRequestNotFound,
+	ErrInvalidTransactionState,
+	ErrInvalidLabel,
+	ErrInvalidNote,
+	ErrTransactionArchived,
+	ErrArchiveInProgress,
+	ErrStateNotArchivable,
+	ErrRecoveryTargetNotEmpty,
+	ErrWalletNotSynced,
+	ErrBroadcastsPaused,
+	ErrMaxActiveDelegationsReached,
+	ErrStakingTimeAboveLimit,
+	ErrInvalidFinalityProviderKey,
+	ErrAutoRenewNotAllowed,
+	ErrWatchedTxNotSignable,
+	ErrSlashingTxWrongInput,
+	ErrSlashingTxWrongSlashingOutput,
+	ErrSlashingTxFeeTooLow,
+	ErrInvalidSlashingPathScript,
+	ErrInvalidSlashingTxSig,
+	ErrUnbondingSigsNotTimedOut,
+	ErrUnbondingTxSent,
+	ErrDelegationSubmitted,
+	ErrDelegationSubmissionInFlight,
+	ErrIdempotencyKeyReused,
+	ErrStakedValueCapReached,
+	ErrAuthenticationRequired,
+}
+
+// RPCError is error response of daemon
+type RPCError struct {
+	Code    int
+	Message string
+	// error returned by request handler of daemon
+	Data string
+}
+
+func (e *RPCError) Error() string {
+	if e.Data == "" {
+		return fmt.Sprintf("staker daemon error %d: %s", e.Code, e.Message)
+	}
+
+	return fmt.Sprintf("staker daemon error %d: %s: %s", e.Code, e.Message, e.Data)
+}
+
+// Is matches error against errors mapped from json rpc error codes, and against
+// known errors returned by daemon
+func (e *RPCError) Is(target error) bool {
+	if code, ok := codeErrors[target]; ok {
+		return e.Code == code
+	}
+
+	for _, daemonErr := range daemonErrors {
+		if target == daemonErr {
+			return strings.Contains(e.Data, daemonErr.Error())
+		}
+	}
+
+	return false
+}
+
+func asRPCError(err error, target **RPCError) bool {
+	var cometErr *rpctypes.RPCError
+
+	if !errors.As(err, &cometErr) {
+		return false
+	}
+
+	*target = &RPCError{
+		Code:    cometErr.Code,
+		Message: cometErr.Message,
+		Data:    cometErr.Data,
+	}
+
+	return true
+}
+
+// mapError replaces error response of daemon with RPCError. Other errors are
+// returned unchanged.
+func mapError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var rpcErr *RPCError
+
+	if asRPCError(err, &rpcErr) {
+		return rpcErr
+	}
+
+	return err
+}
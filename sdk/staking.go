@@ -0,0 +1,987 @@
+package sdk
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/babylonchain/btc-staker/babylonclient"
+	"github.com/babylonchain/btc-staker/proto"
+	service "github.com/babylonchain/btc-staker/stakerservice"
+	"github.com/babylonchain/btc-staker/utils"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+)
+
+// StakeRequest describes new stake. Optional fields are left nil to use daemon
+// defaults.
+type StakeRequest struct {
+	StakerAddress     btcutil.Address
+	Amount            btcutil.Amount
+	FinalityProviders []*btcec.PublicKey
+	// staking time in blocks, ignored if StakingDuration is set
+	StakingTimeBlocks uint16
+	// staking time converted to blocks by daemon using its expected number of
+	// blocks per hour
+	StakingDuration *time.Duration
+	ConfTarget      *uint32
+	Memo            *string
+	Label           *string
+	RequiredDepth   *uint32
+	// allows staking time above maximum staking time of the daemon
+	AcknowledgeLongLock bool
+	// renew delegation automatically when its timelock expires
+	AutoRenew bool
+	// generate new proof of possession instead of reusing one cached by daemon
+	// for staker address
+	FreshPop bool
+}
+
+func (r *StakeRequest) params() (map[string]interface{}, error) {
+	if r.StakerAddress == nil {
+		return nil, fmt.Errorf("staker address is required")
+	}
+
+	params := make(map[string]interface{})
+	params["stakerAddress"] = r.StakerAddress.EncodeAddress()
+	params["stakingAmount"] = int64(r.Amount)
+	params["fpBtcPks"] = xOnlyKeysHex(r.FinalityProviders)
+	params["stakingTimeBlocks"] = int64(r.StakingTimeBlocks)
+
+	if r.StakingDuration != nil {
+		duration := r.StakingDuration.String()
+		params["stakingDuration"] = &duration
+	}
+
+	setOptionalUint32(params, "confTarget", r.ConfTarget)
+
+	if r.Memo != nil {
+		params["memo"] = r.Memo
+	}
+
+	if r.Label != nil {
+		params["label"] = r.Label
+	}
+
+	setOptionalUint32(params, "requiredDepth", r.RequiredDepth)
+
+	if r.AcknowledgeLongLock {
+		params["acknowledgeLongLock"] = true
+	}
+
+	if r.AutoRenew {
+		params["autoRenew"] = true
+	}
+
+	if r.FreshPop {
+		params["freshPop"] = true
+	}
+
+	return params, nil
+}
+
+// WatchStakingRequest describes staking transaction created and sent outside of
+// the daemon, which daemon should track and delegate
+type WatchStakingRequest struct {
+	StakingTx           *wire.MsgTx
+	StakingTime         uint16
+	StakingValue        btcutil.Amount
+	StakerBtcPk         *btcec.PublicKey
+	FinalityProviders   []*btcec.PublicKey
+	SlashingTx          *wire.MsgTx
+	SlashingTxSig       *schnorr.Signature
+	StakerBabylonPk     []byte
+	StakerAddress       btcutil.Address
+	StakerBabylonSig    []byte
+	StakerBtcSig        []byte
+	UnbondingTx         *wire.MsgTx
+	SlashUnbondingTx    *wire.MsgTx
+	SlashUnbondingTxSig *schnorr.Signature
+	UnbondingTime       uint16
+	PopType             babylonclient.BabylonBtcPopType
+	RescanStartHeight   *uint32
+	Label               *string
+	RequiredDepth       *uint32
+	// height from which staking transaction is awaited if daemon can't find it
+	// by its hash, e.g. when it was included before wallet was created
+	InclusionHeightHint *uint32
+}
+
+func (r *WatchStakingRequest) params() (map[string]interface{}, error) {
+	if r.StakerAddress == nil || r.StakerBtcPk == nil || r.SlashingTxSig == nil || r.SlashUnbondingTxSig == nil {
+		return nil, fmt.Errorf("staker address, staker key and slashing signatures are required")
+	}
+
+	params := make(map[string]interface{})
+
+	for name, tx := range map[string]*wire.MsgTx{
+		"stakingTx":        r.StakingTx,
+		"slashingTx":       r.SlashingTx,
+		"unbondingTx":      r.UnbondingTx,
+		"slashUnbondingTx": r.SlashUnbondingTx,
+	} {
+		if tx == nil {
+			return nil, fmt.Errorf("%s is required", name)
+		}
+
+		txHex, err := txToHex(tx)
+		if err != nil {
+			return nil, err
+		}
+
+		params[name] = txHex
+	}
+
+	params["stakingTime"] = int(r.StakingTime)
+	params["stakingValue"] = int(r.StakingValue)
+	params["stakerBtcPk"] = hex.EncodeToString(schnorr.SerializePubKey(r.StakerBtcPk))
+	params["fpBtcPks"] = xOnlyKeysHex(r.FinalityProviders)
+	params["slashingTxSig"] = hex.EncodeToString(r.SlashingTxSig.Serialize())
+	params["stakerBabylonPk"] = hex.EncodeToString(r.StakerBabylonPk)
+	params["stakerAddress"] = r.StakerAddress.EncodeAddress()
+	params["stakerBabylonSig"] = hex.EncodeToString(r.StakerBabylonSig)
+	params["stakerBtcSig"] = hex.EncodeToString(r.StakerBtcSig)
+	params["slashUnbondingTxSig"] = hex.EncodeToString(r.SlashUnbondingTxSig.Serialize())
+	params["unbondingTime"] = int(r.UnbondingTime)
+	params["popType"] = int(r.PopType)
+
+	setOptionalUint32(params, "rescanStartHeight", r.RescanStartHeight)
+
+	if r.Label != nil {
+		params["label"] = r.Label
+	}
+
+	setOptionalUint32(params, "requiredDepth", r.RequiredDepth)
+	setOptionalUint32(params, "inclusionHeightHint", r.InclusionHeightHint)
+
+	return params, nil
+}
+
+// Page selects page of listed items. Zero limit selects default page size of
+// daemon.
+type Page struct {
+	Offset int
+	Limit  int
+}
+
+func (p Page) setParams(params map[string]interface{}) {
+	if p.Offset != 0 {
+		params["offset"] = p.Offset
+	}
+
+	if p.Limit != 0 {
+		params["limit"] = p.Limit
+	}
+}
+
+// ListQuery filters listed staking transactions
+type ListQuery struct {
+	Page
+	Verbose bool
+	Label   *string
+	State   *proto.TransactionState
+	// include archived transactions
+	IncludeArchived bool
+}
+
+// FinalityProvidersQuery selects page of finality providers registered on
+// babylon, either by offset or by page key returned with previous page
+type FinalityProvidersQuery struct {
+	Page
+	PageKey        *string
+	IncludeSlashed bool
+}
+
+// SpendStakeOptions are optional params of spending staking output
+type SpendStakeOptions struct {
+	// maximum fee as a fraction of stake value
+	MaxFee     *float64
+	ConfTarget *uint32
+}
+
+// UnbondOptions are optional params of unbonding. Unbonding transaction fee is
+// fixed when delegation is sent to babylon, so fee rates are only validated and
+// reported back as ignored in response warning.
+type UnbondOptions struct {
+	// fee rate per kvB (1000 virtual bytes) of unbonding transaction
+	FeeRate *btcutil.Amount
+	// fee rate per vB of unbonding transaction, can't be set together with FeeRate
+	FeeRateSatPerVbyte *float64
+	AutoSweep          *bool
+}
+
+func setOptionalUint32(params map[string]interface{}, name string, v *uint32) {
+	if v != nil {
+		value := int(*v)
+		params[name] = &value
+	}
+}
+
+func xOnlyKeysHex(keys []*btcec.PublicKey) []string {
+	keysHex := make([]string, len(keys))
+
+	for i, k := range keys {
+		keysHex[i] = hex.EncodeToString(schnorr.SerializePubKey(k))
+	}
+
+	return keysHex
+}
+
+func txToHex(tx *wire.MsgTx) (string, error) {
+	txBytes, err := utils.SerializeBtcTransaction(tx)
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(txBytes), nil
+}
+
+func txHashParams(txHash chainhash.Hash) map[string]interface{} {
+	return map[string]interface{}{
+		"stakingTxHash": txHash.String(),
+	}
+}
+
+func (c *Client) Health(ctx context.Context) (*service.ResultHealth, error) {
+	result := new(service.ResultHealth)
+	if err := c.call(ctx, idempotentCall, "health", nil, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c *Client) StateMachine(ctx context.Context) (*service.StateMachineResponse, error) {
+	result := new(service.StateMachineResponse)
+	if err := c.call(ctx, idempotentCall, "state_machine", nil, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c *Client) SetBroadcastEnabled(ctx context.Context, enabled bool) (*service.BroadcastStatusResponse, error) {
+	result := new(service.BroadcastStatusResponse)
+
+	params := map[string]interface{}{
+		"enabled": enabled,
+	}
+
+	if err := c.call(ctx, idempotentCall, "set_broadcast_enabled", params, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c *Client) SpendWhitelist(ctx context.Context) (*service.SpendWhitelistResponse, error) {
+	result := new(service.SpendWhitelistResponse)
+	if err := c.call(ctx, idempotentCall, "spend_whitelist", nil, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c *Client) AddWhitelistedAddress(ctx context.Context, address btcutil.Address) (*service.SpendWhitelistResponse, error) {
+	result := new(service.SpendWhitelistResponse)
+
+	params := map[string]interface{}{
+		"address": address.EncodeAddress(),
+	}
+
+	if err := c.call(ctx, idempotentCall, "add_whitelisted_address", params, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c *Client) RemoveWhitelistedAddress(ctx context.Context, address btcutil.Address) (*service.SpendWhitelistResponse, error) {
+	result := new(service.SpendWhitelistResponse)
+
+	params := map[string]interface{}{
+		"address": address.EncodeAddress(),
+	}
+
+	if err := c.call(ctx, idempotentCall, "remove_whitelisted_address", params, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// StakedValueCap returns cap of value staked within rolling window of btc blocks
+// and its current usage
+func (c *Client) StakedValueCap(ctx context.Context) (*service.StakedValueCapResponse, error) {
+	result := new(service.StakedValueCapResponse)
+	if err := c.call(ctx, idempotentCall, "staked_value_cap", nil, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// OverrideStakedValueCap replaces configured staked value cap until daemon
+// restarts. Zero maxValue disables the cap. Daemon only accepts the override
+// over unix socket or connection authenticated with rpc credentials.
+func (c *Client) OverrideStakedValueCap(
+	ctx context.Context,
+	maxValue btcutil.Amount,
+	windowBlocks uint32,
+) (*service.StakedValueCapResponse, error) {
+	result := new(service.StakedValueCapResponse)
+
+	params := map[string]interface{}{
+		"maxValue":     int64(maxValue),
+		"windowBlocks": windowBlocks,
+	}
+
+	if err := c.call(ctx, idempotentCall, "override_staked_value_cap", params, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// GetStakeOutput returns staking output and scripts of stake with given params,
+// without creating staking transaction
+func (c *Client) GetStakeOutput(
+	ctx context.Context,
+	stakerKey *btcec.PublicKey,
+	amount btcutil.Amount,
+	finalityProviders []*btcec.PublicKey,
+	stakingTimeBlocks uint16,
+) (*service.ResultStakeOutput, error) {
+	result := new(service.ResultStakeOutput)
+
+	params := map[string]interface{}{
+		"stakerKey":         hex.EncodeToString(schnorr.SerializePubKey(stakerKey)),
+		"stakingAmount":     int64(amount),
+		"fpBtcPks":          xOnlyKeysHex(finalityProviders),
+		"stakingTimeBlocks": int64(stakingTimeBlocks),
+	}
+
+	if err := c.call(ctx, idempotentCall, "getStakeOutput", params, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Stake creates and sends staking transaction, and returns once it is sent to
+// btc
+func (c *Client) Stake(ctx context.Context, req StakeRequest) (*service.ResultStake, error) {
+	params, err := req.params()
+	if err != nil {
+		return nil, err
+	}
+
+	result := new(service.ResultStake)
+	if err := c.call(ctx, keyedCall, "stake", params, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// StakeAsync validates stake and returns id of staking request without waiting
+// until staking transaction is sent. Status of the request is returned by
+// StakingRequestStatus.
+func (c *Client) StakeAsync(ctx context.Context, req StakeRequest) (*service.ResultStakeAsync, error) {
+	params, err := req.params()
+	if err != nil {
+		return nil, err
+	}
+
+	result := new(service.ResultStakeAsync)
+	if err := c.call(ctx, keyedCall, "stake_async", params, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c *Client) StakingRequestStatus(ctx context.Context, requestId string) (*service.StakingRequestStatusResponse, error) {
+	result := new(service.StakingRequestStatusResponse)
+
+	params := map[string]interface{}{
+		"requestId": requestId,
+	}
+
+	if err := c.call(ctx, idempotentCall, "staking_request_status", params, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c *Client) StakingParams(ctx context.Context) (*service.StakingParamsResponse, error) {
+	result := new(service.StakingParamsResponse)
+	if err := c.call(ctx, idempotentCall, "staking_params", nil, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// EstimateStakeFunding previews wallet utxos and fee of staking transaction of
+// given amount, without unlocking the wallet. Nil confTarget uses daemon default.
+func (c *Client) EstimateStakeFunding(
+	ctx context.Context,
+	stakerAddress btcutil.Address,
+	stakingAmount btcutil.Amount,
+	confTarget *uint32,
+) (*service.StakeFundingEstimateResponse, error) {
+	result := new(service.StakeFundingEstimateResponse)
+
+	params := make(map[string]interface{})
+	params["stakerAddress"] = stakerAddress.EncodeAddress()
+	params["stakingAmount"] = int64(stakingAmount)
+
+	setOptionalUint32(params, "confTarget", confTarget)
+
+	if err := c.call(ctx, idempotentCall, "estimate_stake_funding", params, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c *Client) StakingDetails(ctx context.Context, txHash chainhash.Hash) (*service.StakingDetails, error) {
+	result := new(service.StakingDetails)
+	if err := c.call(ctx, idempotentCall, "staking_details", txHashParams(txHash), result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c *Client) TransactionDetails(ctx context.Context, txHash chainhash.Hash) (*service.TransactionDetailsResponse, error) {
+	result := new(service.TransactionDetailsResponse)
+	if err := c.call(ctx, idempotentCall, "transaction_details", txHashParams(txHash), result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c *Client) ConfirmationProgress(ctx context.Context, txHash chainhash.Hash) (*service.ConfirmationProgressResponse, error) {
+	result := new(service.ConfirmationProgressResponse)
+	if err := c.call(ctx, idempotentCall, "confirmation_progress", txHashParams(txHash), result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c *Client) BabylonDelegationInfo(ctx context.Context, txHash chainhash.Hash) (*service.BabylonDelegationInfoResponse, error) {
+	result := new(service.BabylonDelegationInfoResponse)
+	if err := c.call(ctx, idempotentCall, "babylon_delegation_info", txHashParams(txHash), result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// SpendStake withdraws staked funds once staking or unbonding timelock expired
+func (c *Client) SpendStake(ctx context.Context, txHash chainhash.Hash, opts SpendStakeOptions) (*service.SpendTxDetails, error) {
+	result := new(service.SpendTxDetails)
+
+	params := txHashParams(txHash)
+
+	if opts.MaxFee != nil {
+		params["maxFee"] = opts.MaxFee
+	}
+
+	setOptionalUint32(params, "confTarget", opts.ConfTarget)
+
+	if err := c.call(ctx, keyedCall, "spend_stake", params, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c *Client) ListStakingTransactions(ctx context.Context, q ListQuery) (*service.ListStakingTransactionsResponse, error) {
+	result := new(service.ListStakingTransactionsResponse)
+
+	params := make(map[string]interface{})
+	q.setParams(params)
+
+	if q.Verbose {
+		params["verbosity"] = 1
+	}
+
+	if q.Label != nil {
+		params["label"] = q.Label
+	}
+
+	if q.State != nil {
+		params["state"] = q.State.String()
+	}
+
+	if q.IncludeArchived {
+		params["includeArchived"] = true
+	}
+
+	if err := c.call(ctx, idempotentCall, "list_staking_transactions", params, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Unbond sends unbonding transaction of active delegation to btc
+func (c *Client) Unbond(ctx context.Context, txHash chainhash.Hash, opts UnbondOptions) (*service.UnbondingResponse, error) {
+	result := new(service.UnbondingResponse)
+
+	params := txHashParams(txHash)
+
+	if opts.FeeRate != nil {
+		params["feeRate"] = int(*opts.FeeRate)
+	}
+
+	if opts.FeeRateSatPerVbyte != nil {
+		params["feeRateSatPerVbyte"] = *opts.FeeRateSatPerVbyte
+	}
+
+	if opts.AutoSweep != nil {
+		params["autoSweep"] = opts.AutoSweep
+	}
+
+	if err := c.call(ctx, keyedCall, "unbond_staking", params, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// PreviewUnbonding returns amounts and estimated timeline of unbonding without
+// unbonding. Fee rate is per kvB, nil fee rate selects rate which unbonding
+// would use.
+func (c *Client) PreviewUnbonding(ctx context.Context, txHash chainhash.Hash, feeRate *btcutil.Amount) (*service.UnbondingPreviewResponse, error) {
+	result := new(service.UnbondingPreviewResponse)
+
+	params := txHashParams(txHash)
+
+	if feeRate != nil {
+		params["feeRate"] = int(*feeRate)
+	}
+
+	if err := c.call(ctx, idempotentCall, "preview_unbonding", params, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c *Client) CompareExitOptions(ctx context.Context, txHash chainhash.Hash) (*service.CompareExitOptionsResponse, error) {
+	result := new(service.CompareExitOptionsResponse)
+	if err := c.call(ctx, idempotentCall, "compare_exit_options", txHashParams(txHash), result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c *Client) WithdrawableTransactions(ctx context.Context, page Page) (*service.WithdrawableTransactionsResponse, error) {
+	result := new(service.WithdrawableTransactionsResponse)
+
+	params := make(map[string]interface{})
+	page.setParams(params)
+
+	if err := c.call(ctx, idempotentCall, "withdrawable_transactions", params, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c *Client) BuildDelegationMsg(ctx context.Context, txHash chainhash.Hash, signer string) (*service.BuildDelegationMsgResponse, error) {
+	result := new(service.BuildDelegationMsgResponse)
+
+	params := txHashParams(txHash)
+	params["signer"] = signer
+
+	if err := c.call(ctx, idempotentCall, "build_delegation_msg", params, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c *Client) MarkDelegationSubmitted(ctx context.Context, txHash chainhash.Hash, babylonTxHash string) (*service.MarkDelegationSubmittedResponse, error) {
+	result := new(service.MarkDelegationSubmittedResponse)
+
+	params := txHashParams(txHash)
+	params["babylonTxHash"] = babylonTxHash
+
+	if err := c.call(ctx, onceCall, "mark_delegation_submitted", params, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// CancelPendingDelegation cancels delegation of staking transaction confirmed on
+// btc, which was not sent to babylon yet. Staked funds can be withdrawn once
+// staking timelock expires.
+func (c *Client) CancelPendingDelegation(ctx context.Context, txHash chainhash.Hash) (*service.CancelPendingDelegationResponse, error) {
+	result := new(service.CancelPendingDelegationResponse)
+	if err := c.call(ctx, onceCall, "cancel_pending_delegation", txHashParams(txHash), result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c *Client) SetTransactionLabel(ctx context.Context, txHash chainhash.Hash, label string) (*service.SetTransactionLabelResponse, error) {
+	result := new(service.SetTransactionLabelResponse)
+
+	params := txHashParams(txHash)
+	params["label"] = label
+
+	if err := c.call(ctx, idempotentCall, "set_transaction_label", params, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// SetTransactionNote assigns free-form note to staking transaction, empty note
+// removes current note
+func (c *Client) SetTransactionNote(ctx context.Context, txHash chainhash.Hash, note string) (*service.TransactionNoteResponse, error) {
+	result := new(service.TransactionNoteResponse)
+
+	params := txHashParams(txHash)
+	params["note"] = note
+
+	if err := c.call(ctx, idempotentCall, "set_transaction_note", params, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// GetTransactionNote returns note of staking transaction
+func (c *Client) GetTransactionNote(ctx context.Context, txHash chainhash.Hash) (*service.TransactionNoteResponse, error) {
+	result := new(service.TransactionNoteResponse)
+
+	if err := c.call(ctx, idempotentCall, "get_transaction_note", txHashParams(txHash), result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// SearchTransactions returns staking transactions which label or note contains
+// query, ignoring case
+func (c *Client) SearchTransactions(ctx context.Context, query string) (*service.SearchTransactionsResponse, error) {
+	result := new(service.SearchTransactionsResponse)
+
+	params := map[string]interface{}{
+		"query": query,
+	}
+
+	if err := c.call(ctx, idempotentCall, "search_transactions", params, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// SetAutoRenew enables or disables automatic renewal of staking transaction
+func (c *Client) SetAutoRenew(ctx context.Context, txHash chainhash.Hash, autoRenew bool) (*service.SetAutoRenewResponse, error) {
+	result := new(service.SetAutoRenewResponse)
+
+	params := txHashParams(txHash)
+	params["autoRenew"] = autoRenew
+
+	if err := c.call(ctx, idempotentCall, "set_auto_renew", params, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// WatchStaking starts tracking staking transaction created outside of the daemon
+func (c *Client) WatchStaking(ctx context.Context, req WatchStakingRequest) (*service.ResultStake, error) {
+	params, err := req.params()
+	if err != nil {
+		return nil, err
+	}
+
+	result := new(service.ResultStake)
+	if err := c.call(ctx, onceCall, "watch_staking_tx", params, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c *Client) ListOutputs(ctx context.Context) (*service.OutputsResponse, error) {
+	result := new(service.OutputsResponse)
+	if err := c.call(ctx, idempotentCall, "list_outputs", nil, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Keys returns public keys and addresses controlled by the daemon
+func (c *Client) Keys(ctx context.Context) (*service.KeysResponse, error) {
+	result := new(service.KeysResponse)
+	if err := c.call(ctx, idempotentCall, "keys", nil, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c *Client) PendingChange(ctx context.Context) (*service.PendingChangeResponse, error) {
+	result := new(service.PendingChangeResponse)
+	if err := c.call(ctx, idempotentCall, "pending_change", nil, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// ConsolidateUtxos spends the smallest wallet utxos into single output, so that at
+// most targetCount utxos remain in the wallet. Nil fee rate selects rate estimated
+// for staking transactions.
+func (c *Client) ConsolidateUtxos(ctx context.Context, targetCount int, feeRate *btcutil.Amount) (*service.ConsolidateUtxosResponse, error) {
+	result := new(service.ConsolidateUtxosResponse)
+
+	params := map[string]interface{}{
+		"targetCount": targetCount,
+	}
+
+	if feeRate != nil {
+		params["feeRate"] = int(*feeRate)
+	}
+
+	if err := c.call(ctx, onceCall, "consolidate_utxos", params, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c *Client) BabylonFinalityProviders(ctx context.Context, q FinalityProvidersQuery) (*service.FinalityProvidersResponse, error) {
+	result := new(service.FinalityProvidersResponse)
+
+	params := make(map[string]interface{})
+	q.setParams(params)
+
+	if q.PageKey != nil {
+		params["pageKey"] = q.PageKey
+	}
+
+	params["includeSlashed"] = q.IncludeSlashed
+
+	if err := c.call(ctx, idempotentCall, "babylon_finality_providers", params, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// ProviderExposure returns amounts delegated to finality providers. Nil key
+// selects all finality providers.
+func (c *Client) ProviderExposure(ctx context.Context, fpKey *btcec.PublicKey) (*service.ProviderExposureResponse, error) {
+	result := new(service.ProviderExposureResponse)
+
+	params := make(map[string]interface{})
+
+	if fpKey != nil {
+		params["fpBtcPk"] = hex.EncodeToString(schnorr.SerializePubKey(fpKey))
+	}
+
+	if err := c.call(ctx, idempotentCall, "provider_exposure", params, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c *Client) Stats(ctx context.Context) (*service.DaemonStatsResponse, error) {
+	result := new(service.DaemonStatsResponse)
+	if err := c.call(ctx, idempotentCall, "stats", nil, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c *Client) Reconcile(ctx context.Context) (*service.ReconciliationReportResponse, error) {
+	result := new(service.ReconciliationReportResponse)
+	if err := c.call(ctx, idempotentCall, "reconcile", nil, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c *Client) ReconciliationReport(ctx context.Context) (*service.ReconciliationReportResponse, error) {
+	result := new(service.ReconciliationReportResponse)
+	if err := c.call(ctx, idempotentCall, "reconciliation_report", nil, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// DbIntegrity returns tracked transactions whose stored data is inconsistent
+// with their state
+func (c *Client) DbIntegrity(ctx context.Context) (*service.DbIntegrityResponse, error) {
+	result := new(service.DbIntegrityResponse)
+	if err := c.call(ctx, idempotentCall, "db_integrity", nil, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// RepairStakingOutput points record of transaction flagged as corrupted at the
+// output of staking transaction matching its staking script
+func (c *Client) RepairStakingOutput(ctx context.Context, txHash chainhash.Hash) (*service.RepairStakingOutputResponse, error) {
+	result := new(service.RepairStakingOutputResponse)
+	if err := c.call(ctx, idempotentCall, "repair_staking_output", txHashParams(txHash), result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// RetryUnbondingSignatures resumes frequent checks of covenant unbonding
+// signatures of delegation, which timed out waiting for them or which waiting was
+// aborted
+func (c *Client) RetryUnbondingSignatures(ctx context.Context, txHash chainhash.Hash) (*service.RetryUnbondingSignaturesResponse, error) {
+	result := new(service.RetryUnbondingSignaturesResponse)
+	if err := c.call(ctx, idempotentCall, "retry_unbonding_signatures", txHashParams(txHash), result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// AbortUnbonding stops waiting for covenant unbonding signatures of delegation
+// which timed out waiting for them, so that staked funds are withdrawn once
+// staking timelock expires
+func (c *Client) AbortUnbonding(ctx context.Context, txHash chainhash.Hash) (*service.AbortUnbondingResponse, error) {
+	result := new(service.AbortUnbondingResponse)
+	if err := c.call(ctx, idempotentCall, "abort_unbonding", txHashParams(txHash), result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// RecoverDb rebuilds database of tracked transactions from btc and babylon data.
+// Transactions are written to a new database at targetDbPath on daemon host,
+// which is ignored in dry run. Nil rescan start height selects height chosen by
+// daemon.
+func (c *Client) RecoverDb(
+	ctx context.Context,
+	stakerAddress btcutil.Address,
+	targetDbPath string,
+	dryRun bool,
+	rescanStartHeight *uint32,
+) (*service.RecoverDbResponse, error) {
+	result := new(service.RecoverDbResponse)
+
+	params := map[string]interface{}{
+		"stakerAddress": stakerAddress.EncodeAddress(),
+		"targetDbPath":  targetDbPath,
+		"dryRun":        dryRun,
+	}
+
+	setOptionalUint32(params, "rescanStartHeight", rescanStartHeight)
+
+	if err := c.call(ctx, onceCall, "recover_db", params, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c *Client) RescanStatus(ctx context.Context) (*service.RescanStatusResponse, error) {
+	result := new(service.RescanStatusResponse)
+	if err := c.call(ctx, idempotentCall, "rescan_status", nil, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c *Client) PendingOperations(ctx context.Context) (*service.PendingOperationsResponse, error) {
+	result := new(service.PendingOperationsResponse)
+	if err := c.call(ctx, idempotentCall, "pending_operations", nil, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// DebugState returns internal state of the daemon. Daemon must have debug rpc
+// enabled.
+func (c *Client) DebugState(ctx context.Context) (*service.DebugStateResponse, error) {
+	result := new(service.DebugStateResponse)
+	if err := c.call(ctx, idempotentCall, "debug_state", nil, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c *Client) RecoveryStatus(ctx context.Context) (*service.RecoveryStatusResponse, error) {
+	result := new(service.RecoveryStatusResponse)
+	if err := c.call(ctx, idempotentCall, "recovery_status", nil, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// AuditLog returns entries of audit log starting from given sequence number.
+// Zero limit selects default page size of daemon.
+func (c *Client) AuditLog(ctx context.Context, fromSeq uint64, limit int) (*service.AuditLogResponse, error) {
+	result := new(service.AuditLogResponse)
+
+	params := map[string]interface{}{
+		"fromSeq": int(fromSeq),
+	}
+
+	if limit != 0 {
+		params["limit"] = limit
+	}
+
+	if err := c.call(ctx, idempotentCall, "audit_log", params, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// History returns staking, unbonding and withdrawal events in time range. Zero
+// time leaves range unbounded on its side.
+func (c *Client) History(ctx context.Context, from, to time.Time) (*service.HistoryResponse, error) {
+	result := new(service.HistoryResponse)
+
+	params := make(map[string]interface{})
+
+	if !from.IsZero() {
+		params["from"] = from.UTC().Format(time.RFC3339)
+	}
+
+	if !to.IsZero() {
+		params["to"] = to.UTC().Format(time.RFC3339)
+	}
+
+	if err := c.call(ctx, idempotentCall, "history", params, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// LatencyReport returns p50 and p95 of lengths of staking transaction lifecycle
+// intervals which ended in time range. Zero time leaves range unbounded on its
+// side.
+func (c *Client) LatencyReport(ctx context.Context, from, to time.Time) (*service.LatencyReportResponse, error) {
+	result := new(service.LatencyReportResponse)
+
+	params := make(map[string]interface{})
+
+	if !from.IsZero() {
+		params["from"] = from.UTC().Format(time.RFC3339)
+	}
+
+	if !to.IsZero() {
+		params["to"] = to.UTC().Format(time.RFC3339)
+	}
+
+	if err := c.call(ctx, idempotentCall, "latency_report", params, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// ArchiveTransactions moves transactions in given terminal states, which reached
+// them before olderThan ago, out of the working set of daemon. Requires
+// authenticated client.
+func (c *Client) ArchiveTransactions(
+	ctx context.Context,
+	olderThan time.Duration,
+	states ...proto.TransactionState,
+) (*service.ArchiveTransactionsResponse, error) {
+	result := new(service.ArchiveTransactionsResponse)
+
+	stateNames := make([]string, len(states))
+	for i, state := range states {
+		stateNames[i] = state.String()
+	}
+
+	params := make(map[string]interface{})
+	params["olderThan"] = olderThan.String()
+	params["states"] = strings.Join(stateNames, ",")
+
+	if err := c.call(ctx, idempotentCall, "archive_transactions", params, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
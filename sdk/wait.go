@@ -0,0 +1,86 @@
+package sdk
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/babylonchain/btc-staker/proto"
+	"github.com/babylonchain/btc-staker/stakerdb"
+	service "github.com/babylonchain/btc-staker/stakerservice"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+)
+
+// reachableStates returns states from which transaction can still move to target
+// state, including target state itself
+func reachableStates(target proto.TransactionState) map[proto.TransactionState]bool {
+	// reversed edges of state machine
+	movedFrom := make(map[proto.TransactionState][]proto.TransactionState)
+
+	for _, def := range stakerdb.StateMachine() {
+		for _, next := range def.AllowedTransitions {
+			movedFrom[next] = append(movedFrom[next], def.State)
+		}
+	}
+
+	reachable := map[proto.TransactionState]bool{target: true}
+	queue := []proto.TransactionState{target}
+
+	for len(queue) > 0 {
+		state := queue[0]
+		queue = queue[1:]
+
+		for _, prev := range movedFrom[state] {
+			if !reachable[prev] {
+				reachable[prev] = true
+				queue = append(queue, prev)
+			}
+		}
+	}
+
+	return reachable
+}
+
+// WaitForState polls daemon until staking transaction reaches target state, and
+// returns its details in that state. ErrStateUnreachable is returned as soon as
+// transaction moves to state from which target state cannot be reached, and
+// ErrTransactionNotFound if daemon does not track the transaction. Polling
+// interval is set by WithPollInterval option, and wait is bounded by ctx only.
+func (c *Client) WaitForState(
+	ctx context.Context,
+	txHash chainhash.Hash,
+	target proto.TransactionState,
+) (*service.TransactionDetailsResponse, error) {
+	reachable := reachableStates(target)
+
+	ticker := time.NewTicker(c.opts.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		details, err := c.TransactionDetails(ctx, txHash)
+		if err != nil {
+			return nil, err
+		}
+
+		stateValue, ok := proto.TransactionState_value[details.StakingState]
+		if !ok {
+			return nil, fmt.Errorf("daemon returned unknown transaction state: %s", details.StakingState)
+		}
+
+		state := proto.TransactionState(stateValue)
+
+		if state == target {
+			return details, nil
+		}
+
+		if !reachable[state] {
+			return details, fmt.Errorf("%w: transaction %s is in state %s", ErrStateUnreachable, txHash, state)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
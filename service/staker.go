@@ -1,81 +1,261 @@
+// Package service calls staker daemon with plain string arguments. New code
+// should prefer Client of sdk package, which accepts typed arguments and exposes
+// whole api of the daemon.
 package service
 
 import (
 	"context"
 	"errors"
+	"fmt"
+	"sync"
 
+	"github.com/babylonchain/btc-staker/sdk"
+	"github.com/babylonchain/btc-staker/staker"
 	service "github.com/babylonchain/btc-staker/stakerservice"
-	dc "github.com/babylonchain/btc-staker/stakerservice/client"
+	"github.com/babylonchain/btc-staker/utils"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
 )
 
-func Stake(daemonAddress string, stakerAddress string, stakingAmount int64, fpPks []string, stakingTimeBlocks int64) (*service.ResultStake, error) {
-	client, err := dc.NewStakerServiceJsonRpcClient(daemonAddress)
+// networks which addresses are accepted by helpers of this package, which do not
+// know network of the daemon
+var networks = []*chaincfg.Params{
+	&chaincfg.MainNetParams,
+	&chaincfg.TestNet3Params,
+	&chaincfg.SigNetParams,
+	&chaincfg.RegressionNetParams,
+	&chaincfg.SimNetParams,
+}
+
+func decodeAddress(address string) (btcutil.Address, error) {
+	for _, net := range networks {
+		if addr, err := btcutil.DecodeAddress(address, net); err == nil {
+			return addr, nil
+		}
+	}
+
+	return nil, fmt.Errorf("invalid staker address: %s", address)
+}
+
+func stakingTimeBlocksToUint16(stakingTimeBlocks int64) (uint16, error) {
+	if stakingTimeBlocks <= 0 || stakingTimeBlocks > int64(^uint16(0)) {
+		return 0, fmt.Errorf("staking time must be between 1 and %d blocks", ^uint16(0))
+	}
+
+	return uint16(stakingTimeBlocks), nil
+}
+
+// Service calls staker daemon with plain string arguments. It keeps single client
+// of the daemon, so that connections to the daemon are reused between calls. It
+// is safe for concurrent use.
+type Service struct {
+	client *sdk.Client
+}
+
+// NewService creates service calling daemon listening on address e.g
+// tcp://localhost:15812. Options configure underlying sdk client e.g its
+// credentials and retries.
+func NewService(daemonAddress string, opts ...sdk.Option) (*Service, error) {
+	client, err := sdk.NewClient(daemonAddress, opts...)
 	if err != nil {
 		return nil, err
 	}
 
-	sctx := context.Background()
+	return &Service{client: client}, nil
+}
 
-	results, err := client.Stake(sctx, stakerAddress, stakingAmount, fpPks, stakingTimeBlocks)
+// Stake stakes given amount, amount can be specified with unit e.g 0.5btc or
+// 50000000sat. Amount without unit is in satoshis.
+func (s *Service) Stake(
+	ctx context.Context,
+	stakerAddress string,
+	stakingAmount string,
+	fpPks []string,
+	stakingTimeBlocks int64,
+) (*service.ResultStake, error) {
+	amount, err := utils.ParseBtcAmount(stakingAmount)
 	if err != nil {
 		return nil, err
 	}
 
-	return results, nil
+	stakerAddr, err := decodeAddress(stakerAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	fpKeys, err := staker.ParseFinalityProviderPks(fpPks)
+	if err != nil {
+		return nil, err
+	}
+
+	stakingTime, err := stakingTimeBlocksToUint16(stakingTimeBlocks)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.client.Stake(ctx, sdk.StakeRequest{
+		StakerAddress:     stakerAddr,
+		Amount:            amount,
+		FinalityProviders: fpKeys,
+		StakingTimeBlocks: stakingTime,
+	})
 }
 
-func Unbond(daemonAddress string, stakingTransactionHash string, feeRate int) (*service.UnbondingResponse, error) {
-	client, err := dc.NewStakerServiceJsonRpcClient(daemonAddress)
+// Unbond unbonds staking transaction. Fee rate is in sat/kvB i.e per 1000
+// virtual bytes, use UnbondSatPerVbyte for fee rate in sat/vB. Zero fee rate
+// selects fee rate estimated by daemon.
+func (s *Service) Unbond(ctx context.Context, stakingTransactionHash string, feeRate int) (*service.UnbondingResponse, error) {
+	txHash, err := chainhash.NewHashFromStr(stakingTransactionHash)
 	if err != nil {
 		return nil, err
 	}
 
-	sctx := context.Background()
+	if feeRate < 0 {
+		return nil, errors.New("fee rate must be non-negative")
+	}
+
+	var opts sdk.UnbondOptions
+	if feeRate > 0 {
+		fr := btcutil.Amount(feeRate)
+		opts.FeeRate = &fr
+	}
+
+	return s.client.Unbond(ctx, *txHash, opts)
+}
+
+// UnbondSatPerVbyte unbonds staking transaction. Unbonding transaction fee is
+// fixed when delegation is sent to babylon, so non-zero fee rate in sat/vB is
+// ignored and response carries warning.
+func (s *Service) UnbondSatPerVbyte(ctx context.Context, stakingTransactionHash string, feeRate float64) (*service.UnbondingResponse, error) {
+	txHash, err := chainhash.NewHashFromStr(stakingTransactionHash)
+	if err != nil {
+		return nil, err
+	}
 
 	if feeRate < 0 {
 		return nil, errors.New("fee rate must be non-negative")
 	}
 
-	var fr *int = nil
+	var opts sdk.UnbondOptions
 	if feeRate > 0 {
-		fr = &feeRate
+		opts.FeeRateSatPerVbyte = &feeRate
 	}
 
-	result, err := client.UnbondStaking(sctx, stakingTransactionHash, fr)
+	return s.client.Unbond(ctx, *txHash, opts)
+}
+
+// Unstake withdraws staked funds once staking or unbonding timelock expired.
+func (s *Service) Unstake(ctx context.Context, stakingTransactionHash string) (*service.SpendTxDetails, error) {
+	txHash, err := chainhash.NewHashFromStr(stakingTransactionHash)
 	if err != nil {
 		return nil, err
 	}
 
-	return result, nil
+	return s.client.SpendStake(ctx, *txHash, sdk.SpendStakeOptions{})
 }
 
-func Unstake(daemonAddress string, stakingTransactionHash string) (*service.SpendTxDetails, error) {
-	client, err := dc.NewStakerServiceJsonRpcClient(daemonAddress)
+// GetStakeOutput returns staking output of stake with given params.
+func (s *Service) GetStakeOutput(
+	ctx context.Context,
+	stakerKey string,
+	stakingAmount string,
+	fpPks []string,
+	stakingTimeBlocks int64,
+) (*service.ResultStakeOutput, error) {
+	amount, err := utils.ParseBtcAmount(stakingAmount)
 	if err != nil {
 		return nil, err
 	}
 
-	sctx := context.Background()
+	stakerPk, err := staker.ParsePublicKey(stakerKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid staker key: %w", err)
+	}
+
+	fpKeys, err := staker.ParseFinalityProviderPks(fpPks)
+	if err != nil {
+		return nil, err
+	}
 
-	result, err := client.SpendStakingTransaction(sctx, stakingTransactionHash)
+	stakingTime, err := stakingTimeBlocksToUint16(stakingTimeBlocks)
 	if err != nil {
 		return nil, err
 	}
 
-	return result, nil
+	return s.client.GetStakeOutput(ctx, stakerPk, amount, fpKeys, stakingTime)
 }
 
-func GetStakeOutput(daemonAddress string, stakerKey string, stakingAmount int64, fpPks []string, stakingTimeBlocks int64) (*service.ResultStakeOutput, error) {
-	client, err := dc.NewStakerServiceJsonRpcClient(daemonAddress)
+var (
+	defaultServicesMu sync.Mutex
+	// services used by package level functions, created on first call with
+	// given daemon address
+	defaultServices = make(map[string]*Service)
+)
+
+func defaultService(daemonAddress string) (*Service, error) {
+	defaultServicesMu.Lock()
+	defer defaultServicesMu.Unlock()
+
+	if s, ok := defaultServices[daemonAddress]; ok {
+		return s, nil
+	}
+
+	s, err := NewService(daemonAddress)
 	if err != nil {
 		return nil, err
 	}
-	sctx := context.Background()
 
-	results, err := client.GetStakeOutput(sctx, stakerKey, stakingAmount, fpPks, stakingTimeBlocks)
+	defaultServices[daemonAddress] = s
+	return s, nil
+}
+
+// Stake stakes given amount, amount can be specified with unit e.g 0.5btc or
+// 50000000sat. Amount without unit is in satoshis.
+//
+// Deprecated: use Service.Stake, which accepts context.
+func Stake(daemonAddress string, stakerAddress string, stakingAmount string, fpPks []string, stakingTimeBlocks int64) (*service.ResultStake, error) {
+	s, err := defaultService(daemonAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.Stake(context.Background(), stakerAddress, stakingAmount, fpPks, stakingTimeBlocks)
+}
+
+// Unbond unbonds staking transaction. Fee rate is in sat/kvB, zero fee rate
+// selects fee rate estimated by daemon.
+//
+// Deprecated: use Service.Unbond, which accepts context.
+func Unbond(daemonAddress string, stakingTransactionHash string, feeRate int) (*service.UnbondingResponse, error) {
+	s, err := defaultService(daemonAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.Unbond(context.Background(), stakingTransactionHash, feeRate)
+}
+
+// Unstake withdraws staked funds once staking or unbonding timelock expired.
+//
+// Deprecated: use Service.Unstake, which accepts context.
+func Unstake(daemonAddress string, stakingTransactionHash string) (*service.SpendTxDetails, error) {
+	s, err := defaultService(daemonAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.Unstake(context.Background(), stakingTransactionHash)
+}
+
+// GetStakeOutput returns staking output of stake with given params.
+//
+// Deprecated: use Service.GetStakeOutput, which accepts context.
+func GetStakeOutput(daemonAddress string, stakerKey string, stakingAmount string, fpPks []string, stakingTimeBlocks int64) (*service.ResultStakeOutput, error) {
+	s, err := defaultService(daemonAddress)
 	if err != nil {
 		return nil, err
 	}
 
-	return results, nil
+	return s.GetStakeOutput(context.Background(), stakerKey, stakingAmount, fpPks, stakingTimeBlocks)
 }
@@ -0,0 +1,121 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	service "github.com/babylonchain/btc-staker/stakerservice"
+	rpctypes "github.com/cometbft/cometbft/rpc/jsonrpc/types"
+	"github.com/stretchr/testify/require"
+)
+
+const testStakingTxHash = "6bf442a2e864172cba73f642ced10c178f6b19097abde41608035fb26a601b10"
+
+// newTestDaemon starts json rpc stub of staker daemon which answers every request
+// with result, and counts connections opened by clients
+func newTestDaemon(
+	t *testing.T,
+	handler func(r *http.Request) interface{},
+) (*httptest.Server, *atomic.Int32) {
+	var connections atomic.Int32
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req rpctypes.RPCRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+
+		result := handler(r)
+
+		if result == nil {
+			// request was cancelled by client
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		require.NoError(t, json.NewEncoder(w).Encode(rpctypes.NewRPCSuccessResponse(req.ID, result)))
+	}))
+
+	server.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			connections.Add(1)
+		}
+	}
+
+	server.Start()
+	t.Cleanup(server.Close)
+
+	return server, &connections
+}
+
+func TestServiceReusesConnections(t *testing.T) {
+	server, connections := newTestDaemon(t, func(r *http.Request) interface{} {
+		return &service.SpendTxDetails{TxHash: testStakingTxHash}
+	})
+
+	s, err := NewService(server.URL)
+	require.NoError(t, err)
+
+	for i := 0; i < 10; i++ {
+		result, err := s.Unstake(context.Background(), testStakingTxHash)
+		require.NoError(t, err)
+		require.Equal(t, testStakingTxHash, result.TxHash)
+	}
+
+	require.Equal(t, int32(1), connections.Load())
+}
+
+func TestDeprecatedFunctionsReuseDefaultService(t *testing.T) {
+	server, connections := newTestDaemon(t, func(r *http.Request) interface{} {
+		return &service.SpendTxDetails{TxHash: testStakingTxHash}
+	})
+
+	for i := 0; i < 10; i++ {
+		_, err := Unstake(server.URL, testStakingTxHash)
+		require.NoError(t, err)
+	}
+
+	require.Equal(t, int32(1), connections.Load())
+
+	s1, err := defaultService(server.URL)
+	require.NoError(t, err)
+	s2, err := defaultService(server.URL)
+	require.NoError(t, err)
+	require.Same(t, s1, s2)
+}
+
+func TestServiceRequestIsCancelledWithContext(t *testing.T) {
+	received := make(chan struct{})
+	cancelledOnDaemon := make(chan struct{})
+
+	server, _ := newTestDaemon(t, func(r *http.Request) interface{} {
+		close(received)
+		// daemon never answers, request only ends when client cancels it
+		<-r.Context().Done()
+		close(cancelledOnDaemon)
+		return nil
+	})
+
+	s, err := NewService(server.URL)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	go func() {
+		<-received
+		cancel()
+	}()
+
+	_, err = s.Unstake(ctx, testStakingTxHash)
+	require.ErrorIs(t, err, context.Canceled)
+
+	select {
+	case <-cancelledOnDaemon:
+	case <-time.After(5 * time.Second):
+		t.Fatal("cancellation was not propagated to daemon")
+	}
+}
@@ -0,0 +1,113 @@
+package staker
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/babylonchain/btc-staker/proto"
+	"github.com/babylonchain/btc-staker/stakerdb"
+	"github.com/sirupsen/logrus"
+)
+
+// ErrArchiveInterrupted is returned when staker shuts down before archiving is
+// finished. Archiving continues on next start.
+var ErrArchiveInterrupted = errors.New("archiving interrupted by shutdown, it will be resumed on next start")
+
+// ParseArchiveAge parses minimum age of archived transactions given as duration
+// e.g 90d, 8w or 720h
+func ParseArchiveAge(s string) (time.Duration, error) {
+	age, err := parseStakingDuration(strings.TrimSpace(s))
+
+	if err != nil {
+		return 0, fmt.Errorf("invalid age %q, expected duration e.g 90d, 8w or 720h: %w", s, err)
+	}
+
+	if age < 0 {
+		return 0, fmt.Errorf("age must not be negative")
+	}
+
+	return age, nil
+}
+
+// ArchiveTransactions moves transactions in given terminal states, which are in
+// that state for longer than olderThan, to archive. Archived transactions are no
+// longer scanned nor listed by default, but can still be read by hash. It blocks
+// until all tracked transactions are scanned.
+func (app *StakerApp) ArchiveTransactions(
+	olderThan time.Duration,
+	states []proto.TransactionState,
+) (*stakerdb.ArchiveJob, error) {
+	if olderThan <= 0 {
+		return nil, fmt.Errorf("age of archived transactions must be positive")
+	}
+
+	job, err := app.txTracker.StartArchive(app.clock.Now().Add(-olderThan), states)
+
+	if err != nil {
+		return nil, err
+	}
+
+	app.logger.WithFields(logrus.Fields{
+		"olderThan": job.OlderThan,
+		"states":    job.States,
+	}).Info("Archiving transactions")
+
+	return app.runArchive()
+}
+
+// runArchive archives batches of transactions until pending archiving is done
+func (app *StakerApp) runArchive() (*stakerdb.ArchiveJob, error) {
+	for {
+		select {
+		case <-app.quit:
+			return nil, ErrArchiveInterrupted
+		default:
+		}
+
+		job, err := app.txTracker.ArchiveNextBatch()
+
+		if err != nil {
+			return nil, err
+		}
+
+		if job.Done {
+			app.logger.WithFields(logrus.Fields{
+				"archived": job.Archived,
+			}).Info("Archiving transactions finished")
+
+			return job, nil
+		}
+	}
+}
+
+// resumeArchive continues archiving interrupted by previous shutdown in
+// background
+func (app *StakerApp) resumeArchive() error {
+	job, err := app.txTracker.PendingArchive()
+
+	if err != nil {
+		return err
+	}
+
+	if job == nil {
+		return nil
+	}
+
+	app.logger.WithFields(logrus.Fields{
+		"olderThan": job.OlderThan,
+		"states":    job.States,
+		"archived":  job.Archived,
+	}).Info("Resuming interrupted archiving of transactions")
+
+	app.wg.Go(archiveGoroutine, func() {
+		if _, err := app.runArchive(); err != nil && !errors.Is(err, ErrArchiveInterrupted) {
+			app.logger.WithFields(logrus.Fields{
+				"err": err,
+			}).Error("Failed to resume archiving of transactions")
+		}
+	})
+
+	return nil
+}
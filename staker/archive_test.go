@@ -0,0 +1,69 @@
+package staker
+
+import (
+	"testing"
+	"time"
+
+	"github.com/babylonchain/btc-staker/proto"
+	"github.com/babylonchain/btc-staker/stakerdb"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/stretchr/testify/require"
+)
+
+func (d *testStakerDeps) addArchivedTransaction(t *testing.T) *chainhash.Hash {
+	txHash := d.addConfirmedTransaction(t)
+	require.NoError(t, d.tracker.SetTxSpentOnBtc(txHash, nil))
+
+	_, err := d.tracker.StartArchive(time.Now().Add(time.Hour), []proto.TransactionState{proto.TransactionState_SPENT_ON_BTC})
+	require.NoError(t, err)
+
+	for {
+		job, err := d.tracker.ArchiveNextBatch()
+		require.NoError(t, err)
+
+		if job.Done {
+			break
+		}
+	}
+
+	storedTx, err := d.tracker.GetTransaction(txHash)
+	require.NoError(t, err)
+	require.True(t, storedTx.Archived)
+
+	return txHash
+}
+
+func TestArchivedTransactionCannotBeSpentOrUnbonded(t *testing.T) {
+	deps := newTestStakerDeps(t)
+	txHash := deps.addArchivedTransaction(t)
+
+	app := deps.newApp(t)
+
+	_, _, err := app.SpendStake(txHash, nil, nil)
+	require.ErrorIs(t, err, stakerdb.ErrTransactionArchived)
+
+	_, err = app.UnbondStaking(*txHash, nil, nil)
+	require.ErrorIs(t, err, stakerdb.ErrTransactionArchived)
+}
+
+func TestStakingEventOfArchivedTransactionIsIgnored(t *testing.T) {
+	deps := newTestStakerDeps(t)
+	txHash := deps.addArchivedTransaction(t)
+
+	app := deps.newApp(t)
+
+	ev := &spendStakeTxConfirmedOnBtcEvent{
+		stakingTxHash:       *txHash,
+		spendTxConfirmation: &stakerdb.BtcConfirmationInfo{Height: 20},
+	}
+	err := app.txTracker.SetTxSpentOnBtc(txHash, ev.spendTxConfirmation)
+	require.ErrorIs(t, err, stakerdb.ErrTransactionArchived)
+
+	// must neither stop the app nor quarantine the transaction
+	app.handleStateTransitionError(ev, txHash, err)
+
+	storedTx, err := deps.tracker.GetTransaction(txHash)
+	require.NoError(t, err)
+	require.True(t, storedTx.Archived)
+	require.Equal(t, proto.TransactionState_SPENT_ON_BTC, storedTx.State)
+}
@@ -0,0 +1,65 @@
+package staker
+
+import (
+	"github.com/babylonchain/btc-staker/proto"
+	"github.com/babylonchain/btc-staker/stakerdb"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+)
+
+// AuditOperationOutcome describes result of fund moving operation recorded in
+// audit log
+type AuditOperationOutcome struct {
+	TxHash           *chainhash.Hash
+	StakingRequestId string
+	Err              error
+}
+
+// RecordAuditRequest records in audit log that fund moving operation was
+// requested. It must be called before operation is performed, and operation must
+// not be performed if recording fails. It returns sequence number of the entry.
+func (app *StakerApp) RecordAuditRequest(caller, method, args string) (uint64, error) {
+	entry, err := app.txTracker.AppendAuditEntry(&stakerdb.AuditLogEntry{
+		Type:      proto.AuditEntryType_AUDIT_OPERATION_REQUESTED,
+		Timestamp: app.clock.Now(),
+		Caller:    caller,
+		Method:    method,
+		Args:      args,
+	})
+
+	if err != nil {
+		return 0, err
+	}
+
+	return entry.Seq, nil
+}
+
+// RecordAuditOutcome records in audit log outcome of the operation which request
+// was recorded under requestSeq
+func (app *StakerApp) RecordAuditOutcome(
+	requestSeq uint64,
+	caller, method string,
+	outcome *AuditOperationOutcome,
+) error {
+	entry := &stakerdb.AuditLogEntry{
+		Type:             proto.AuditEntryType_AUDIT_OPERATION_FINISHED,
+		Timestamp:        app.clock.Now(),
+		Caller:           caller,
+		Method:           method,
+		RequestSeq:       requestSeq,
+		TxHash:           outcome.TxHash,
+		StakingRequestId: outcome.StakingRequestId,
+	}
+
+	if outcome.Err != nil {
+		entry.Error = outcome.Err.Error()
+	}
+
+	_, err := app.txTracker.AppendAuditEntry(entry)
+	return err
+}
+
+// QueryAuditLog returns at most limit entries of audit log, starting from entry
+// with sequence number fromSeq
+func (app *StakerApp) QueryAuditLog(fromSeq, limit uint64) ([]stakerdb.AuditLogEntry, error) {
+	return app.txTracker.QueryAuditLog(fromSeq, limit)
+}
@@ -0,0 +1,482 @@
+package staker
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	staking "github.com/babylonchain/babylon/btcstaking"
+	cl "github.com/babylonchain/btc-staker/babylonclient"
+	"github.com/babylonchain/btc-staker/stakerdb"
+	"github.com/babylonchain/btc-staker/utils"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// caller and method under which automatic renewals are recorded in audit log
+	autoRenewAuditCaller = "stakerd"
+	autoRenewAuditMethod = "auto_renew"
+)
+
+// ErrAutoRenewNotAllowed auto renewal cannot be enabled for the transaction
+var ErrAutoRenewNotAllowed = errors.New("auto renewal not allowed")
+
+// autoRenewWithdrawableHeight returns height of the first block which can include
+// renewal of the transaction. Returns false if transaction is not renewed
+// automatically or its staking output is no longer spendable by renewal.
+func autoRenewWithdrawableHeight(tx *stakerdb.StoredTransaction) (uint32, bool) {
+	if !tx.AutoRenew || tx.Watched || tx.CorruptionReason != "" {
+		return 0, false
+	}
+
+	// staking output is already being spent, by renewal or by other spend
+	if tx.SpendTxData != nil || tx.RenewalTxHash != nil {
+		return 0, false
+	}
+
+	if !tx.StakingTxConfirmedOnBtc() || tx.StakingTxConfirmationInfo == nil {
+		return 0, false
+	}
+
+	// unbonding transaction spends staking output once it is confirmed
+	if tx.UnbondingTxData != nil && !tx.UnbondingTxData.UnbondingTxSentAt.IsZero() {
+		return 0, false
+	}
+
+	return tx.WithdrawableHeight()
+}
+
+// renewalDue returns true if renewal window of transaction withdrawable at given
+// height is open
+func renewalDue(withdrawableHeight, leadBlocks, currentHeight uint32) bool {
+	return uint64(currentHeight)+uint64(leadBlocks) >= uint64(withdrawableHeight)
+}
+
+// renewalSpendable returns true if renewal transaction can be included in the
+// next block
+func renewalSpendable(withdrawableHeight, currentHeight uint32) bool {
+	return uint64(currentHeight)+1 >= uint64(withdrawableHeight)
+}
+
+// SetAutoRenew enables or disables automatic renewal of staking transaction
+func (app *StakerApp) SetAutoRenew(stakingTxHash *chainhash.Hash, enabled bool) error {
+	tx, err := app.txTracker.GetTransaction(stakingTxHash)
+
+	if err != nil {
+		return err
+	}
+
+	if enabled {
+		if tx.Watched {
+			return fmt.Errorf("%w: staking output of watched transaction cannot be spent by staker", ErrAutoRenewNotAllowed)
+		}
+
+		if stakerdb.IsTerminalState(tx.State) || tx.SpendTxData != nil {
+			return fmt.Errorf("%w: staking output was already spent", ErrAutoRenewNotAllowed)
+		}
+	}
+
+	if err := app.txTracker.SetAutoRenew(stakingTxHash, enabled); err != nil {
+		return err
+	}
+
+	app.logger.WithFields(logrus.Fields{
+		"stakingTxHash": stakingTxHash,
+		"autoRenew":     enabled,
+	}).Info("Changed auto renewal of staking transaction")
+
+	if enabled {
+		// renewal could be already due
+		app.notifyAutoRenewNewBlock()
+	}
+
+	return nil
+}
+
+// notifyAutoRenewNewBlock never blocks, renewals triggered by blocks are coalesced
+func (app *StakerApp) notifyAutoRenewNewBlock() {
+	select {
+	case app.autoRenewNewBlock <- struct{}{}:
+	default:
+	}
+}
+
+// autoRenewLoop checks on each new block whether renewal window of any
+// transaction with auto renewal opened
+func (app *StakerApp) autoRenewLoop() {
+	for {
+		select {
+		case <-app.autoRenewNewBlock:
+			// renewals are parked while broadcasts are paused, loop is notified
+			// again when broadcasts are resumed
+			if app.BroadcastsPaused() {
+				continue
+			}
+
+			app.renewDueTransactions(app.currentBestBlockHeight.Load())
+
+		case <-app.quit:
+			return
+		}
+	}
+}
+
+func (app *StakerApp) renewDueTransactions(currentHeight uint32) {
+	var due []stakerdb.StoredTransaction
+
+	err := app.txTracker.ScanTrackedTransactions(func(tx *stakerdb.StoredTransaction) error {
+		withdrawableHeight, ok := autoRenewWithdrawableHeight(tx)
+
+		if ok && renewalDue(withdrawableHeight, app.config.StakerConfig.AutoRenewLeadBlocks, currentHeight) {
+			due = append(due, *tx)
+		}
+
+		return nil
+	}, func() {
+		due = nil
+	})
+
+	if err != nil {
+		app.logger.WithFields(logrus.Fields{
+			"err": err,
+		}).Error("Failed to scan transactions with auto renewal")
+		return
+	}
+
+	for i := range due {
+		tx := &due[i]
+		stakingTxHash := tx.StakingTx.TxHash()
+
+		skipReason, err := app.renewalSkipReason(tx)
+
+		if err != nil {
+			// check is retried on next block
+			app.logger.WithFields(logrus.Fields{
+				"stakingTxHash": stakingTxHash,
+				"err":           err,
+			}).Warn("Failed to check whether staking transaction can be renewed")
+			continue
+		}
+
+		if skipReason != "" {
+			app.skipRenewal(&stakingTxHash, skipReason)
+			continue
+		}
+
+		withdrawableHeight, _ := tx.WithdrawableHeight()
+
+		if !renewalSpendable(withdrawableHeight, currentHeight) {
+			continue
+		}
+
+		if err := app.autoRenew(tx); err != nil {
+			// renewal is retried on next block
+			app.logger.WithFields(logrus.Fields{
+				"stakingTxHash": stakingTxHash,
+				"err":           err,
+			}).Warn("Failed to renew staking transaction")
+		}
+	}
+}
+
+// renewalSkipReason returns reason why delegation must not be renewed, or empty
+// string if it can be renewed. Babylon reports only slashing of finality
+// providers, so slashed providers are not delegated to again.
+func (app *StakerApp) renewalSkipReason(tx *stakerdb.StoredTransaction) (string, error) {
+	for _, fpPk := range tx.FinalityProvidersBtcPks {
+		err := app.finalityProviderExists(fpPk)
+
+		if errors.Is(err, cl.ErrFinalityProviderIsSlashed) || errors.Is(err, cl.ErrFinalityProviderDoesNotExist) {
+			return err.Error(), nil
+		}
+
+		if err != nil {
+			return "", err
+		}
+	}
+
+	params, err := app.babylonClient.Params()
+
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := ValidateStakingTime(uint64(tx.StakingTime), GetMinStakingTime(params)); err != nil {
+		return fmt.Sprintf("staking time is no longer valid: %s", err), nil
+	}
+
+	return "", nil
+}
+
+// skipRenewal disables auto renewal of transaction and alerts operator, funds
+// stay in staking output until they are spent manually
+func (app *StakerApp) skipRenewal(stakingTxHash *chainhash.Hash, reason string) {
+	logger := app.logger.WithFields(logrus.Fields{
+		"stakingTxHash": stakingTxHash,
+		"reason":        reason,
+	})
+
+	if err := app.txTracker.SkipAutoRenew(stakingTxHash, reason); err != nil {
+		logger.WithField("err", err).Error("Failed to record skipped renewal of staking transaction")
+		return
+	}
+
+	logger.Error("Skipped automatic renewal of staking transaction. Staked funds must be spent or restaked manually once timelock expires")
+}
+
+func (app *StakerApp) autoRenew(tx *stakerdb.StoredTransaction) error {
+	stakingTxHash := tx.StakingTx.TxHash()
+
+	args, err := json.Marshal(map[string]string{
+		"stakingTxHash": stakingTxHash.String(),
+	})
+
+	if err != nil {
+		return err
+	}
+
+	requestSeq, err := app.RecordAuditRequest(autoRenewAuditCaller, autoRenewAuditMethod, string(args))
+
+	if err != nil {
+		return fmt.Errorf("cannot record renewal in audit log: %w", err)
+	}
+
+	renewalTxHash, err := app.renewStake(tx)
+
+	if auditErr := app.RecordAuditOutcome(requestSeq, autoRenewAuditCaller, autoRenewAuditMethod, &AuditOperationOutcome{
+		TxHash: renewalTxHash,
+		Err:    err,
+	}); auditErr != nil {
+		app.logger.WithFields(logrus.Fields{
+			"requestSeq": requestSeq,
+			"err":        auditErr,
+		}).Error("Failed to record outcome of renewal in audit log")
+	}
+
+	return err
+}
+
+// renewStake spends matured staking output of the transaction directly into new
+// staking output to the same finality providers with the same staking time, and
+// starts delegating it. Renewal is tracked as new staking transaction linked to
+// the renewed one.
+func (app *StakerApp) renewStake(tx *stakerdb.StoredTransaction) (*chainhash.Hash, error) {
+	if err := checkSignable(tx); err != nil {
+		return nil, fmt.Errorf("cannot renew stake: %w", err)
+	}
+
+	if err := app.checkWalletEnabled(); err != nil {
+		return nil, err
+	}
+
+	if err := app.checkWalletSynced(); err != nil {
+		return nil, err
+	}
+
+	stakingTxHash := tx.StakingTx.TxHash()
+
+	stakerAddress, err := btcutil.DecodeAddress(tx.StakerAddress, app.network)
+
+	if err != nil {
+		return nil, fmt.Errorf("cannot renew stake. Error decoding staker address: %w", err)
+	}
+
+	params, err := app.babylonClient.Params()
+
+	if err != nil {
+		return nil, fmt.Errorf("cannot renew stake. Error getting params: %w", err)
+	}
+
+	requiredDepth, err := app.requiredConfirmationDepth(nil, params)
+
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := app.appQuitContext()
+	defer cancel()
+
+	signer := app.newSignerSession(ctx, stakerAddress)
+	defer signer.Close()
+
+	privKey, err := signer.PrivateKey()
+
+	if err != nil {
+		return nil, fmt.Errorf("cannot renew stake. Error getting private key: %w", err)
+	}
+
+	stakerPubKey := privKey.PubKey()
+
+	// renewed staking output is controlled by committee under which it was created,
+	// while new staking output is controlled by the current committee
+	covenantPks, covenantQuorum, err := app.stakingTxCovenantCommittee(&stakingTxHash, tx, stakerPubKey, params)
+
+	if err != nil {
+		return nil, fmt.Errorf("cannot renew stake: %w", err)
+	}
+
+	if err := validateFinalityProviderKeys(tx.FinalityProvidersBtcPks, stakerPubKey, params.CovenantPks); err != nil {
+		return nil, err
+	}
+
+	pop, err := app.stakerPop(signer, stakerAddress, stakerPubKey, false)
+
+	if err != nil {
+		return nil, err
+	}
+
+	stakingValue := btcutil.Amount(tx.StakingTx.TxOut[tx.StakingOutputIndex].Value)
+
+	// script of staking output does not depend on its value, which is known only
+	// once fee of renewal transaction is estimated
+	stakingInfo, err := staking.BuildStakingInfo(
+		stakerPubKey,
+		tx.FinalityProvidersBtcPks,
+		params.CovenantPks,
+		params.CovenantQuruomThreshold,
+		tx.StakingTime,
+		stakingValue,
+		app.network,
+	)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to build staking info: %w", err)
+	}
+
+	paramsVersion, err := app.txTracker.RegisterStakingParams(
+		params.CovenantPks,
+		params.CovenantQuruomThreshold,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	feeEstimate := app.estimateFee(app.config.StakerConfig.SpendTxConfTarget)
+
+	// renewal spends staking output into staking output controlled by the same
+	// staker, so spend destination whitelist does not apply
+	renewalInfo, err := createSpendStakeTxFromStoredTx(
+		stakerPubKey,
+		covenantPks,
+		covenantQuorum,
+		tx,
+		stakingInfo.StakingOutput.PkScript,
+		chainfee.SatPerKVByte(feeEstimate.FeeRate()),
+		app.network,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	renewedValue := btcutil.Amount(renewalInfo.spendStakeTx.TxOut[0].Value)
+
+	slashingFee, err := app.getSlashingFee(params, len(tx.FinalityProvidersBtcPks))
+
+	if err != nil {
+		return nil, err
+	}
+
+	if renewedValue <= slashingFee {
+		return nil, fmt.Errorf("renewed staking amount %d is less than minimum slashing fee %d",
+			renewedValue, slashingFee)
+	}
+
+	if err := signSpendStakeTx(renewalInfo, privKey); err != nil {
+		return nil, fmt.Errorf("cannot renew stake. %w", err)
+	}
+
+	renewalTx := renewalInfo.spendStakeTx
+	renewalTxHash := renewalTx.TxHash()
+
+	babylonMemo, err := app.delegationMemo("", &renewalTxHash, stakerAddress.EncodeAddress())
+
+	if err != nil {
+		return nil, err
+	}
+
+	// renewal keeps label of the renewed transaction and is renewed again
+	req := newOwnedStakingRequest(
+		stakerAddress,
+		renewalTx,
+		0,
+		stakingInfo.StakingOutput.PkScript,
+		tx.StakingTime,
+		renewedValue,
+		tx.FinalityProvidersBtcPks,
+		requiredDepth,
+		pop,
+		paramsVersion,
+		nil,
+		babylonMemo,
+		tx.Label,
+		true,
+	)
+	// renewal transaction is both spend of renewed staking output and new
+	// staking transaction
+	req.feeEstimate = feeEstimate
+	req.renewedFromTxHash = &stakingTxHash
+
+	sent, err := app.sendRenewal(&stakingTxHash, req, stakingValue, renewalInfo.calculatedFee)
+
+	if err != nil || !sent {
+		return nil, err
+	}
+
+	app.recordFeeEstimate(&stakingTxHash, stakerdb.SpendTxFeeEstimate, feeEstimate)
+
+	app.watchSpendTxConfirmation(&stakingTxHash, &renewalTxHash, stakingInfo.StakingOutput.PkScript)
+
+	app.logger.WithFields(logrus.Fields{
+		"stakingTxHash": stakingTxHash,
+		"renewalTxHash": renewalTxHash,
+		"stakeValue":    stakingValue,
+		"renewedValue":  renewedValue,
+		"fee":           renewalInfo.calculatedFee,
+	}).Info("Renewed staking transaction")
+
+	return &renewalTxHash, nil
+}
+
+// sendRenewal records renewal of staking transaction and sends renewal
+// transaction. Renewal is recorded before it is sent, so that transaction whose
+// renewal could not be recorded is never renewed, and renewal is never sent
+// twice. Returns false if staker is shutting down.
+func (app *StakerApp) sendRenewal(
+	stakingTxHash *chainhash.Hash,
+	req *stakingRequestedEvent,
+	spentValue btcutil.Amount,
+	fee btcutil.Amount,
+) (bool, error) {
+	if err := app.txTracker.SetTxRenewed(stakingTxHash, &req.stakingTxHash, spentValue, fee); err != nil {
+		return false, fmt.Errorf("cannot record renewal, renewal not sent: %w", err)
+	}
+
+	utils.PushOrQuit[*stakingRequestedEvent](
+		app.stakingRequestedEvChan,
+		req,
+		app.quit,
+	)
+
+	select {
+	case reqErr := <-req.errChan:
+		// renewal was not sent, so it can be retried
+		if err := app.txTracker.ClearTxRenewal(stakingTxHash, &req.stakingTxHash); err != nil {
+			app.logger.WithFields(logrus.Fields{
+				"stakingTxHash": stakingTxHash,
+				"renewalTxHash": req.stakingTxHash,
+				"err":           err,
+			}).Error("Failed to clear renewal which was not sent. Staking transaction will not be renewed automatically")
+		}
+
+		return false, reqErr
+	case <-req.successChan:
+		return true, nil
+	case <-app.quit:
+		return false, nil
+	}
+}
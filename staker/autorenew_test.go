@@ -0,0 +1,147 @@
+package staker
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+
+	"github.com/babylonchain/btc-staker/proto"
+	"github.com/babylonchain/btc-staker/stakerdb"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenewalWindow(t *testing.T) {
+	// window opens lead blocks before staking output becomes spendable
+	require.False(t, renewalDue(1000, 144, 855))
+	require.True(t, renewalDue(1000, 144, 856))
+	require.True(t, renewalDue(1000, 144, 1200))
+
+	// renewal can be included in the next block only once timelock allows it
+	require.False(t, renewalSpendable(1000, 998))
+	require.True(t, renewalSpendable(1000, 999))
+	require.True(t, renewalSpendable(1000, 1200))
+}
+
+func TestAutoRenewWithdrawableHeight(t *testing.T) {
+	tx := &stakerdb.StoredTransaction{
+		State:                     proto.TransactionState_DELEGATION_ACTIVE,
+		StakingTime:               500,
+		StakingTxConfirmationInfo: &stakerdb.BtcConfirmationInfo{Height: 100},
+	}
+
+	// auto renewal not enabled
+	_, ok := autoRenewWithdrawableHeight(tx)
+	require.False(t, ok)
+
+	tx.AutoRenew = true
+	height, ok := autoRenewWithdrawableHeight(tx)
+	require.True(t, ok)
+	require.Equal(t, uint32(600), height)
+
+	// already renewed
+	tx.RenewalTxHash = &chainhash.Hash{}
+	_, ok = autoRenewWithdrawableHeight(tx)
+	require.False(t, ok)
+
+	// staking output of watched transaction can't be spent by staker
+	tx.RenewalTxHash = nil
+	tx.Watched = true
+	_, ok = autoRenewWithdrawableHeight(tx)
+	require.False(t, ok)
+
+	// staking transaction not confirmed yet
+	tx.Watched = false
+	tx.State = proto.TransactionState_SENT_TO_BTC
+	_, ok = autoRenewWithdrawableHeight(tx)
+	require.False(t, ok)
+}
+
+// answerStakingRequests stands in for main loop and fails every staking request
+// with reqErr. Returns number of received requests.
+func answerStakingRequests(t *testing.T, app *StakerApp, reqErr error) *atomic.Int32 {
+	var received atomic.Int32
+	done := make(chan struct{})
+	t.Cleanup(func() { close(done) })
+
+	go func() {
+		for {
+			select {
+			case ev := <-app.stakingRequestedEvChan:
+				received.Add(1)
+				ev.errChan <- reqErr
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return &received
+}
+
+func testRenewalRequest(stakingTxHash *chainhash.Hash) *stakingRequestedEvent {
+	renewalTx := wire.NewMsgTx(2)
+	renewalTx.AddTxIn(wire.NewTxIn(wire.NewOutPoint(stakingTxHash, 0), nil, nil))
+	renewalTx.AddTxOut(wire.NewTxOut(900, []byte{0x51}))
+
+	return &stakingRequestedEvent{
+		stakingTxHash:     renewalTx.TxHash(),
+		stakingTx:         renewalTx,
+		renewedFromTxHash: stakingTxHash,
+		errChan:           make(chan error, 1),
+		successChan:       make(chan *chainhash.Hash, 1),
+	}
+}
+
+func TestRenewalNotSentIfNotRecorded(t *testing.T) {
+	deps := newTestStakerDeps(t)
+	txHash := deps.addConfirmedTransaction(t)
+	app := deps.newApp(t)
+	received := answerStakingRequests(t, app, errors.New("not sent"))
+
+	// renewal can't be recorded
+	require.NoError(t, deps.db.Close())
+
+	sent, err := app.sendRenewal(txHash, testRenewalRequest(txHash), 1000, 100)
+	require.Error(t, err)
+	require.False(t, sent)
+	require.Zero(t, received.Load())
+}
+
+func TestRenewalNotSentTwice(t *testing.T) {
+	deps := newTestStakerDeps(t)
+	txHash := deps.addConfirmedTransaction(t)
+	app := deps.newApp(t)
+	received := answerStakingRequests(t, app, errors.New("not sent"))
+
+	first := testRenewalRequest(txHash)
+	require.NoError(t, deps.tracker.SetTxRenewed(txHash, &first.stakingTxHash, 1000, 100))
+
+	_, err := app.sendRenewal(txHash, testRenewalRequest(txHash), 1000, 50)
+	require.ErrorIs(t, err, stakerdb.ErrAlreadyRenewed)
+	require.Zero(t, received.Load())
+}
+
+func TestRenewalClearedIfNotSent(t *testing.T) {
+	deps := newTestStakerDeps(t)
+	txHash := deps.addConfirmedTransaction(t)
+	require.NoError(t, deps.tracker.SetAutoRenew(txHash, true))
+	app := deps.newApp(t)
+
+	errNotSent := errors.New("not sent")
+	received := answerStakingRequests(t, app, errNotSent)
+
+	sent, err := app.sendRenewal(txHash, testRenewalRequest(txHash), 1000, 100)
+	require.ErrorIs(t, err, errNotSent)
+	require.False(t, sent)
+	require.Equal(t, int32(1), received.Load())
+
+	// renewal is retried
+	tx, err := deps.tracker.GetTransaction(txHash)
+	require.NoError(t, err)
+	require.Nil(t, tx.RenewalTxHash)
+	require.Nil(t, tx.SpendTxData)
+	_, ok := autoRenewWithdrawableHeight(tx)
+	require.True(t, ok)
+}
@@ -0,0 +1,379 @@
+package staker
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/babylonchain/btc-staker/stakerdb"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	notifier "github.com/lightningnetwork/lnd/chainntnfs"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// caller and method under which automatic sweeps are recorded in audit log
+	autoSweepAuditCaller = "stakerd"
+	autoSweepAuditMethod = "auto_sweep"
+)
+
+// autoSweepEnabled returns true if unbonded funds should be swept automatically.
+// autoSweep overrides value from config if provided.
+func (app *StakerApp) autoSweepEnabled(autoSweep *bool) bool {
+	if autoSweep != nil {
+		return *autoSweep
+	}
+
+	return app.config.StakerConfig.AutoSweepUnbondedFunds
+}
+
+// sweepDestination returns address to which unbonded funds are swept
+func (app *StakerApp) sweepDestination(stakerAddress btcutil.Address) (btcutil.Address, error) {
+	if app.config.StakerConfig.SweepAddress == "" {
+		return stakerAddress, nil
+	}
+
+	return btcutil.DecodeAddress(app.config.StakerConfig.SweepAddress, app.network)
+}
+
+// scheduleAutoSweep persists intent to sweep unbonded funds of the staking
+// transaction, so that sweep is performed even if staker is restarted before
+// unbonding timelock expires
+func (app *StakerApp) scheduleAutoSweep(
+	stakingTxHash *chainhash.Hash,
+	stakerAddress btcutil.Address,
+	tx *stakerdb.StoredTransaction,
+) error {
+	destAddress, err := app.sweepDestination(stakerAddress)
+
+	if err != nil {
+		return fmt.Errorf("cannot decode sweep address: %w", err)
+	}
+
+	// sweep to not whitelisted address would fail only after unbonding timelock
+	// expires, so it is rejected before unbonding is started
+	if err := app.checkSpendDestination(destAddress, stakerAddress); err != nil {
+		return err
+	}
+
+	if err := app.txTracker.SaveSweepIntent(&stakerdb.SweepIntent{
+		StakingTxHash:      *stakingTxHash,
+		DestinationAddress: destAddress.EncodeAddress(),
+	}); err != nil {
+		return fmt.Errorf("cannot save sweep intent: %w", err)
+	}
+
+	app.watchUnbondingOutputSpend(*stakingTxHash, tx.UnbondingTxData)
+
+	app.logger.WithFields(logrus.Fields{
+		"stakingTxHash": stakingTxHash,
+		"destAddress":   destAddress,
+	}).Info("Scheduled automatic sweep of unbonded funds")
+
+	return nil
+}
+
+// watchSweepIntents starts watching unbonding outputs of all stored sweep intents.
+// It is called on startup, as watches do not survive restarts.
+func (app *StakerApp) watchSweepIntents() error {
+	intents, err := app.txTracker.GetSweepIntents()
+
+	if err != nil {
+		return err
+	}
+
+	for _, intent := range intents {
+		tx, err := app.txTracker.GetTransaction(&intent.StakingTxHash)
+
+		if err != nil {
+			app.logger.WithFields(logrus.Fields{
+				"stakingTxHash": intent.StakingTxHash,
+				"err":           err,
+			}).Warn("Failed to get transaction of sweep intent")
+			continue
+		}
+
+		if tx.UnbondingTxData == nil {
+			continue
+		}
+
+		app.watchUnbondingOutputSpend(intent.StakingTxHash, tx.UnbondingTxData)
+	}
+
+	return nil
+}
+
+// watchUnbondingOutputSpend registers for spend of unbonding output, so that sweep
+// intent is removed once unbonded funds are spent, either by automatic sweep or
+// manually by the user
+func (app *StakerApp) watchUnbondingOutputSpend(
+	stakingTxHash chainhash.Hash,
+	unbondingData *stakerdb.UnbondingStoreData,
+) {
+	unbondingTxHash := unbondingData.UnbondingTx.TxHash()
+
+	// unbonding output cannot be spent before unbonding transaction is confirmed
+	heightHint := app.currentBestBlockHeight.Load()
+	if unbondingData.UnbondingTxConfirmationInfo != nil {
+		heightHint = unbondingData.UnbondingTxConfirmationInfo.Height
+	}
+
+	sub := app.notifications.reserve(unbondingTxHash, UnbondingOutputSpendPurpose, stakingTxHash, app.clock.Now())
+
+	if sub == nil {
+		// spend is already watched e.g sweep was scheduled again
+		return
+	}
+
+	spendEvent, err := app.notifier.RegisterSpendNtfn(
+		wire.NewOutPoint(&unbondingTxHash, unbondingData.UnbondingOutputIndex),
+		unbondingData.UnbondingOutput().PkScript,
+		heightHint,
+	)
+
+	if err != nil {
+		app.notifications.remove(sub)
+		// sweep intent is still removed when staking transaction reaches terminal
+		// state, only spends outside of staker are not detected
+		app.logger.WithFields(logrus.Fields{
+			"stakingTxHash": stakingTxHash,
+			"err":           err,
+		}).Warn("Failed to register for spend of unbonding output")
+		return
+	}
+
+	if !app.notifications.attach(sub, spendEvent.Cancel) {
+		return
+	}
+
+	app.wg.Go(unbondingOutputSpendGoroutine, func() {
+		app.waitForUnbondingOutputSpend(stakingTxHash, spendEvent, sub)
+	})
+}
+
+func (app *StakerApp) waitForUnbondingOutputSpend(
+	stakingTxHash chainhash.Hash,
+	spendEvent *notifier.SpendEvent,
+	sub *notificationSubscription,
+) {
+	defer app.notifications.remove(sub)
+
+	select {
+	case spend, ok := <-spendEvent.Spend:
+		if !ok {
+			return
+		}
+
+		app.unbondingOutputSpent(&stakingTxHash, spend.SpenderTxHash)
+
+	case <-sub.cancelled:
+		return
+
+	case <-app.quit:
+		return
+	}
+}
+
+func (app *StakerApp) unbondingOutputSpent(stakingTxHash, spenderTxHash *chainhash.Hash) {
+	intent, err := app.txTracker.GetSweepIntent(stakingTxHash)
+
+	if errors.Is(err, stakerdb.ErrSweepIntentNotFound) {
+		return
+	}
+
+	if err != nil {
+		app.logger.WithFields(logrus.Fields{
+			"stakingTxHash": stakingTxHash,
+			"err":           err,
+		}).Error("Failed to get sweep intent")
+		return
+	}
+
+	if err := app.txTracker.DeleteSweepIntent(stakingTxHash); err != nil {
+		app.logger.WithFields(logrus.Fields{
+			"stakingTxHash": stakingTxHash,
+			"err":           err,
+		}).Error("Failed to delete sweep intent")
+		return
+	}
+
+	fields := logrus.Fields{
+		"stakingTxHash": stakingTxHash,
+		"spenderTxHash": spenderTxHash,
+	}
+
+	if intent.SweepTxHash != nil && intent.SweepTxHash.IsEqual(spenderTxHash) {
+		app.logger.WithFields(fields).Info("Automatic sweep of unbonded funds confirmed")
+		return
+	}
+
+	app.logger.WithFields(fields).Info("Unbonded funds were spent by other transaction. Automatic sweep cancelled")
+}
+
+// notifyAutoSweepNewBlock never blocks, sweeps triggered by blocks are coalesced
+func (app *StakerApp) notifyAutoSweepNewBlock() {
+	select {
+	case app.autoSweepNewBlock <- struct{}{}:
+	default:
+	}
+}
+
+// autoSweepLoop checks on each new block whether unbonding timelock of any
+// scheduled sweep expired
+func (app *StakerApp) autoSweepLoop() {
+	for {
+		select {
+		case <-app.autoSweepNewBlock:
+			// sweeps are parked while broadcasts are paused, loop is notified
+			// again when broadcasts are resumed
+			if app.BroadcastsPaused() {
+				continue
+			}
+
+			app.sweepUnbondedFunds(app.currentBestBlockHeight.Load())
+
+		case <-app.quit:
+			return
+		}
+	}
+}
+
+func (app *StakerApp) sweepUnbondedFunds(currentHeight uint32) {
+	intents, err := app.txTracker.GetSweepIntents()
+
+	if err != nil {
+		app.logger.WithFields(logrus.Fields{
+			"err": err,
+		}).Error("Failed to get sweep intents")
+		return
+	}
+
+	for _, intent := range intents {
+		// Sweep which was already sent is not sent again. Intent is removed when
+		// spend of unbonding output is detected.
+		if intent.SweepTxHash != nil {
+			continue
+		}
+
+		tx, err := app.txTracker.GetTransaction(&intent.StakingTxHash)
+
+		if err != nil {
+			app.logger.WithFields(logrus.Fields{
+				"stakingTxHash": intent.StakingTxHash,
+				"err":           err,
+			}).Warn("Failed to get transaction of sweep intent")
+			continue
+		}
+
+		if stakerdb.IsTerminalState(tx.State) {
+			// funds were already spent through staker
+			if err := app.txTracker.DeleteSweepIntent(&intent.StakingTxHash); err != nil {
+				app.logger.WithFields(logrus.Fields{
+					"stakingTxHash": intent.StakingTxHash,
+					"err":           err,
+				}).Error("Failed to delete sweep intent")
+			}
+			continue
+		}
+
+		if tx.Watched {
+			// intents are only created for owned transactions, so this intent was
+			// not stored by staker. Staker cannot sign sweep of watched transaction.
+			app.logger.WithFields(logrus.Fields{
+				"stakingTxHash": intent.StakingTxHash,
+			}).Warn("Removing sweep intent of watched transaction. Unbonded funds can only be withdrawn by owner of staker key")
+
+			if err := app.txTracker.DeleteSweepIntent(&intent.StakingTxHash); err != nil {
+				app.logger.WithFields(logrus.Fields{
+					"stakingTxHash": intent.StakingTxHash,
+					"err":           err,
+				}).Error("Failed to delete sweep intent")
+			}
+			continue
+		}
+
+		if !tx.IsUnbonded() {
+			continue
+		}
+
+		unbondingData := tx.UnbondingTxData
+		unlockHeight := uint64(unbondingData.UnbondingTxConfirmationInfo.Height) + uint64(unbondingData.UnbondingTime)
+
+		if uint64(currentHeight) < unlockHeight {
+			continue
+		}
+
+		if err := app.sweep(intent); err != nil {
+			// sweep is retried on next block
+			app.logger.WithFields(logrus.Fields{
+				"stakingTxHash": intent.StakingTxHash,
+				"err":           err,
+			}).Warn("Failed to sweep unbonded funds")
+		}
+	}
+}
+
+func (app *StakerApp) sweep(intent *stakerdb.SweepIntent) error {
+	destAddress, err := btcutil.DecodeAddress(intent.DestinationAddress, app.network)
+
+	if err != nil {
+		return fmt.Errorf("cannot decode destination address: %w", err)
+	}
+
+	args, err := json.Marshal(map[string]string{
+		"stakingTxHash":      intent.StakingTxHash.String(),
+		"destinationAddress": intent.DestinationAddress,
+	})
+
+	if err != nil {
+		return err
+	}
+
+	requestSeq, err := app.RecordAuditRequest(autoSweepAuditCaller, autoSweepAuditMethod, string(args))
+
+	if err != nil {
+		return fmt.Errorf("cannot record sweep in audit log: %w", err)
+	}
+
+	sweepTxHash, sweptValue, err := app.spendStake(&intent.StakingTxHash, destAddress, nil, nil)
+
+	if auditErr := app.RecordAuditOutcome(requestSeq, autoSweepAuditCaller, autoSweepAuditMethod, &AuditOperationOutcome{
+		TxHash: sweepTxHash,
+		Err:    err,
+	}); auditErr != nil {
+		app.logger.WithFields(logrus.Fields{
+			"requestSeq": requestSeq,
+			"err":        auditErr,
+		}).Error("Failed to record outcome of sweep in audit log")
+	}
+
+	if err != nil {
+		return err
+	}
+
+	// app is quitting
+	if sweepTxHash == nil {
+		return nil
+	}
+
+	if err := app.txTracker.SetSweepTxHash(&intent.StakingTxHash, sweepTxHash); err != nil {
+		// sweep transaction is already sent, if it is not recorded, sending it again
+		// will fail as unbonding output is already spent in mempool
+		app.logger.WithFields(logrus.Fields{
+			"stakingTxHash": intent.StakingTxHash,
+			"sweepTxHash":   sweepTxHash,
+			"err":           err,
+		}).Error("Failed to record sent sweep transaction")
+	}
+
+	app.logger.WithFields(logrus.Fields{
+		"stakingTxHash": intent.StakingTxHash,
+		"sweepTxHash":   sweepTxHash,
+		"sweptValue":    sweptValue,
+		"destAddress":   destAddress,
+	}).Info("Sent automatic sweep of unbonded funds")
+
+	return nil
+}
@@ -0,0 +1,186 @@
+package staker
+
+import (
+	"testing"
+	"time"
+
+	staking "github.com/babylonchain/babylon/btcstaking"
+	"github.com/babylonchain/btc-staker/stakerdb"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testUnbondingHeight = 100
+	testUnbondingTime   = 20
+	// first height at which unbonded funds of test transaction can be spent
+	testUnlockHeight = testUnbondingHeight + testUnbondingTime
+)
+
+type autoSweepFixture struct {
+	deps          *testStakerDeps
+	wallet        *keyWallet
+	stakerAddress btcutil.Address
+	stakingTxHash *chainhash.Hash
+	// output locking unbonded funds
+	unbondingOutpoint wire.OutPoint
+}
+
+// newAutoSweepFixture creates staking transaction which unbonding transaction was
+// confirmed at testUnbondingHeight
+func newAutoSweepFixture(t *testing.T) *autoSweepFixture {
+	deps := newTestStakerDeps(t)
+
+	stakerKey := genPrivKey(t)
+	wallet := &keyWallet{testWallet: deps.wallet, privKey: stakerKey}
+	deps.wc = wallet
+
+	stakerAddress, err := btcutil.NewAddressWitnessPubKeyHash(
+		btcutil.Hash160(stakerKey.PubKey().SerializeCompressed()),
+		&deps.config.ActiveNetParams,
+	)
+	require.NoError(t, err)
+
+	stakingTxHash := deps.addSpendableTransaction(t, stakerKey, stakerAddress)
+
+	storedTx, err := deps.tracker.GetTransaction(stakingTxHash)
+	require.NoError(t, err)
+
+	unbondingInfo, err := staking.BuildUnbondingInfo(
+		stakerKey.PubKey(),
+		storedTx.FinalityProvidersBtcPks,
+		deps.babylon.params.CovenantPks,
+		deps.babylon.params.CovenantQuruomThreshold,
+		testUnbondingTime,
+		90000,
+		&deps.config.ActiveNetParams,
+	)
+	require.NoError(t, err)
+
+	unbondingTx := wire.NewMsgTx(2)
+	unbondingTx.AddTxIn(wire.NewTxIn(wire.NewOutPoint(stakingTxHash, 0), nil, nil))
+	unbondingTx.AddTxOut(unbondingInfo.UnbondingOutput)
+
+	require.NoError(t, deps.tracker.SetTxSentToBabylon(stakingTxHash, unbondingTx, 0, testUnbondingTime, "", ""))
+	require.NoError(t, deps.tracker.SetTxUnbondingConfirmedOnBtc(stakingTxHash, &chainhash.Hash{}, testUnbondingHeight, testClockStart))
+
+	return &autoSweepFixture{
+		deps:              deps,
+		wallet:            wallet,
+		stakerAddress:     stakerAddress,
+		stakingTxHash:     stakingTxHash,
+		unbondingOutpoint: wire.OutPoint{Hash: unbondingTx.TxHash(), Index: 0},
+	}
+}
+
+func (f *autoSweepFixture) newApp(t *testing.T) *StakerApp {
+	app := f.deps.newApp(t)
+	t.Cleanup(func() {
+		close(app.quit)
+		app.wg.Wait()
+	})
+
+	return app
+}
+
+func (f *autoSweepFixture) scheduleSweep(t *testing.T, app *StakerApp) {
+	storedTx, err := f.deps.tracker.GetTransaction(f.stakingTxHash)
+	require.NoError(t, err)
+
+	require.NoError(t, app.scheduleAutoSweep(f.stakingTxHash, f.stakerAddress, storedTx))
+}
+
+func (f *autoSweepFixture) requireIntentRemoved(t *testing.T) {
+	require.Eventually(t, func() bool {
+		_, err := f.deps.tracker.GetSweepIntent(f.stakingTxHash)
+		return err != nil
+	}, time.Second, time.Millisecond)
+
+	_, err := f.deps.tracker.GetSweepIntent(f.stakingTxHash)
+	require.ErrorIs(t, err, stakerdb.ErrSweepIntentNotFound)
+}
+
+// requireSweep checks that single sweep of unbonded funds to staker address was
+// sent, and returns its hash
+func (f *autoSweepFixture) requireSweep(t *testing.T) chainhash.Hash {
+	require.Len(t, f.wallet.sent, 1)
+	sweepTx := f.wallet.sent[0]
+	require.Equal(t, f.unbondingOutpoint, sweepTx.TxIn[0].PreviousOutPoint)
+
+	stakerScript, err := txscript.PayToAddrScript(f.stakerAddress)
+	require.NoError(t, err)
+	require.Equal(t, stakerScript, sweepTx.TxOut[0].PkScript)
+
+	return sweepTx.TxHash()
+}
+
+func TestAutoSweepAtUnlockHeight(t *testing.T) {
+	f := newAutoSweepFixture(t)
+	app := f.newApp(t)
+
+	f.scheduleSweep(t, app)
+
+	// unbonding timelock not expired yet
+	app.sweepUnbondedFunds(testUnlockHeight - 1)
+	require.Empty(t, f.wallet.sent)
+
+	intent, err := f.deps.tracker.GetSweepIntent(f.stakingTxHash)
+	require.NoError(t, err)
+	require.Nil(t, intent.SweepTxHash)
+
+	app.sweepUnbondedFunds(testUnlockHeight)
+	sweepTxHash := f.requireSweep(t)
+
+	intent, err = f.deps.tracker.GetSweepIntent(f.stakingTxHash)
+	require.NoError(t, err)
+	require.Equal(t, sweepTxHash, *intent.SweepTxHash)
+
+	// sent sweep is not sent again on next block
+	app.sweepUnbondedFunds(testUnlockHeight + 1)
+	require.Len(t, f.wallet.sent, 1)
+
+	// intent is removed once sweep spends unbonding output
+	require.Equal(t, 1, f.deps.notifier.spend(f.unbondingOutpoint, sweepTxHash))
+	f.requireIntentRemoved(t)
+}
+
+func TestManualSpendCancelsAutoSweep(t *testing.T) {
+	f := newAutoSweepFixture(t)
+	app := f.newApp(t)
+
+	f.scheduleSweep(t, app)
+
+	// user spent unbonded funds outside of staker
+	require.Equal(t, 1, f.deps.notifier.spend(f.unbondingOutpoint, chainhash.HashH([]byte("manual spend"))))
+	f.requireIntentRemoved(t)
+
+	app.sweepUnbondedFunds(testUnlockHeight)
+	require.Empty(t, f.wallet.sent)
+}
+
+func TestAutoSweepIntentSurvivesRestart(t *testing.T) {
+	f := newAutoSweepFixture(t)
+
+	stoppedApp := f.deps.newApp(t)
+	f.scheduleSweep(t, stoppedApp)
+	close(stoppedApp.quit)
+	stoppedApp.wg.Wait()
+
+	// restarted staker watches spend of unbonding output again and sweeps funds
+	// once timelock expires
+	app := f.newApp(t)
+	require.NoError(t, app.watchSweepIntents())
+
+	app.sweepUnbondedFunds(testUnlockHeight - 1)
+	require.Empty(t, f.wallet.sent)
+
+	app.sweepUnbondedFunds(testUnlockHeight)
+	sweepTxHash := f.requireSweep(t)
+
+	// spend was registered by both staker instances
+	require.Equal(t, 2, f.deps.notifier.spend(f.unbondingOutpoint, sweepTxHash))
+	f.requireIntentRemoved(t)
+}
@@ -0,0 +1,225 @@
+package staker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	sdkmath "cosmossdk.io/math"
+	cl "github.com/babylonchain/btc-staker/babylonclient"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/sirupsen/logrus"
+)
+
+// ErrInsufficientBabylonBalance is returned when babylon account cannot pay fee of
+// delegation transaction
+type ErrInsufficientBabylonBalance struct {
+	Balance     sdk.Coin
+	RequiredFee sdk.Coin
+}
+
+func (e *ErrInsufficientBabylonBalance) Error() string {
+	return fmt.Sprintf(
+		"insufficient babylon balance. balance: %s, required fee: %s",
+		e.Balance,
+		e.RequiredFee,
+	)
+}
+
+// babylonBalanceMonitor keeps last known balance of babylon account and
+// delegations which wait until the account is funded
+type babylonBalanceMonitor struct {
+	mu        sync.Mutex
+	balance   *sdk.Coin
+	checkedAt time.Time
+	// delegations parked due to insufficient balance or exhausted fee grant, with
+	// the reason
+	parked map[chainhash.Hash]string
+}
+
+func newBabylonBalanceMonitor() *babylonBalanceMonitor {
+	return &babylonBalanceMonitor{
+		parked: make(map[chainhash.Hash]string),
+	}
+}
+
+func (m *babylonBalanceMonitor) update(balance sdk.Coin, checkedAt time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.balance = &balance
+	m.checkedAt = checkedAt
+}
+
+func (m *babylonBalanceMonitor) park(stakingTxHash chainhash.Hash, reason string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.parked[stakingTxHash] = reason
+}
+
+func (m *babylonBalanceMonitor) unpark(stakingTxHash chainhash.Hash) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.parked, stakingTxHash)
+}
+
+// BabylonBalanceStatus is the last known balance of babylon account used to pay
+// fees of delegations
+type BabylonBalanceStatus struct {
+	// nil if balance was not retrieved yet
+	Balance   *sdk.Coin
+	Threshold uint64
+	Low       bool
+	CheckedAt time.Time
+	// delegations waiting until babylon account is funded or fee grant renewed,
+	// with the reason
+	ParkedDelegations map[chainhash.Hash]string
+}
+
+// BabylonBalanceStatus returns last known balance of babylon account
+func (app *StakerApp) BabylonBalanceStatus() *BabylonBalanceStatus {
+	m := app.babylonBalance
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	status := &BabylonBalanceStatus{
+		Threshold:         app.config.StakerConfig.BabylonLowBalanceThreshold,
+		CheckedAt:         m.checkedAt,
+		ParkedDelegations: make(map[chainhash.Hash]string, len(m.parked)),
+	}
+
+	for stakingTxHash, reason := range m.parked {
+		status.ParkedDelegations[stakingTxHash] = reason
+	}
+
+	if m.balance != nil {
+		balance := *m.balance
+		status.Balance = &balance
+		status.Low = app.isBabylonBalanceLow(balance)
+	}
+
+	return status
+}
+
+func (app *StakerApp) isBabylonBalanceLow(balance sdk.Coin) bool {
+	threshold := app.config.StakerConfig.BabylonLowBalanceThreshold
+
+	return threshold > 0 && balance.Amount.LT(sdkmath.NewIntFromUint64(threshold))
+}
+
+// checkBabylonBalance retrieves current balance of babylon account and warns if
+// it is below configured threshold
+func (app *StakerApp) checkBabylonBalance() (sdk.Coin, error) {
+	balance, err := app.babylonClient.QueryAccountBalance()
+
+	if err != nil {
+		return sdk.Coin{}, err
+	}
+
+	app.babylonBalance.update(balance, app.clock.Now())
+
+	if app.isBabylonBalanceLow(balance) {
+		app.logger.WithFields(logrus.Fields{
+			"address":   app.babylonClient.GetKeyAddress().String(),
+			"balance":   balance,
+			"threshold": app.config.StakerConfig.BabylonLowBalanceThreshold,
+		}).Warn("Babylon account balance is low. Fund the account to keep sending delegations")
+	}
+
+	return balance, nil
+}
+
+// babylonBalanceLoop periodically checks balance of babylon account, and fee grant
+// if it is configured
+func (app *StakerApp) babylonBalanceLoop() {
+	ticker := app.clock.NewTicker(app.config.StakerConfig.BabylonBalanceCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := app.checkBabylonBalance(); err != nil {
+			app.logger.WithFields(logrus.Fields{
+				"err": err,
+			}).Warn("Failed to check babylon account balance")
+		}
+
+		app.checkFeeGrantAllowance()
+
+		select {
+		case <-ticker.Chan():
+		case <-app.quit:
+			return
+		}
+	}
+}
+
+// checkDelegationFeeBalance returns ErrInsufficientBabylonBalance if babylon account
+// cannot pay simulated fee of the delegation
+func (app *StakerApp) checkDelegationFeeBalance(delegation *cl.DelegationData) error {
+	fee, err := app.babylonClient.EstimateDelegationFee(delegation)
+
+	if err != nil {
+		return fmt.Errorf("failed to estimate delegation fee: %w", err)
+	}
+
+	balance, err := app.checkBabylonBalance()
+
+	if err != nil {
+		return fmt.Errorf("failed to retrieve babylon account balance: %w", err)
+	}
+
+	if balance.Denom == fee.Denom && balance.Amount.LT(fee.Amount) {
+		return &ErrInsufficientBabylonBalance{
+			Balance:     balance,
+			RequiredFee: fee,
+		}
+	}
+
+	return nil
+}
+
+// waitForDelegationFeeBalance parks delegation while babylon account cannot pay its
+// fee, instead of failing send attempts. Failures to estimate fee or to retrieve
+// balance do not block sending, as the send attempt will report the actual error.
+// Returns error if context is done before account is funded.
+func (app *StakerApp) waitForDelegationFeeBalance(
+	ctx context.Context,
+	stakingTxHash chainhash.Hash,
+	delegation *cl.DelegationData,
+) error {
+	defer app.babylonBalance.unpark(stakingTxHash)
+
+	for {
+		err := app.checkDelegationFeeBalance(delegation)
+
+		var balanceErr *ErrInsufficientBabylonBalance
+		if !errors.As(err, &balanceErr) {
+			if err != nil {
+				app.logger.WithFields(logrus.Fields{
+					"stakingTxHash": stakingTxHash,
+					"err":           err,
+				}).Warn("Failed to check babylon balance before sending delegation")
+			}
+
+			return nil
+		}
+
+		app.babylonBalance.park(stakingTxHash, balanceErr.Error())
+
+		app.logger.WithFields(logrus.Fields{
+			"stakingTxHash": stakingTxHash,
+			"balance":       balanceErr.Balance,
+			"requiredFee":   balanceErr.RequiredFee,
+		}).Error("Insufficient babylon balance to send delegation. Waiting until account is funded")
+
+		select {
+		case <-app.clock.After(app.config.StakerConfig.BabylonBalanceCheckInterval):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package staker
+
+import (
+	"errors"
+
+	cl "github.com/babylonchain/btc-staker/babylonclient"
+	"github.com/babylonchain/btc-staker/proto"
+	"github.com/babylonchain/btc-staker/stakerdb"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+)
+
+// BabylonDelegation is what babylon knows about delegation of staking
+// transaction, together with local state of the transaction, so that callers
+// can see if they diverged
+type BabylonDelegation struct {
+	// whether staking transaction is tracked by staker, LocalState is only valid
+	// if it is
+	Tracked    bool
+	LocalState proto.TransactionState
+	// nil if delegation is not found on babylon
+	Info *cl.DelegationInfo
+}
+
+// BabylonDelegationInfo queries babylon for delegation of given staking
+// transaction. Delegation not found on babylon is not an error, as it is valid
+// result for transaction which was not yet delegated.
+func (app *StakerApp) BabylonDelegationInfo(stakingTxHash *chainhash.Hash) (*BabylonDelegation, error) {
+	result := &BabylonDelegation{}
+
+	tx, err := app.txTracker.GetTransaction(stakingTxHash)
+
+	switch {
+	case err == nil:
+		result.Tracked = true
+		result.LocalState = tx.State
+	case !errors.Is(err, stakerdb.ErrTransactionNotFound):
+		return nil, err
+	}
+
+	info, err := app.babylonClient.QueryDelegationInfo(stakingTxHash)
+
+	if err != nil && !errors.Is(err, cl.ErrDelegationNotFound) {
+		return nil, err
+	}
+
+	result.Info = info
+
+	return result, nil
+}
@@ -1,11 +1,12 @@
 package staker
 
 import (
+	"context"
 	"errors"
 	"fmt"
-	"time"
 
 	cl "github.com/babylonchain/btc-staker/babylonclient"
+	"github.com/babylonchain/btc-staker/proto"
 	"github.com/babylonchain/btc-staker/stakerdb"
 	"github.com/babylonchain/btc-staker/utils"
 	"github.com/btcsuite/btcd/btcec/v2"
@@ -20,24 +21,57 @@ import (
 // retrieving data from babylon chain, sending data to babylon chain, queuing data to be send etc.
 
 type sendDelegationRequest struct {
-	txHash                      chainhash.Hash
-	txIndex                     uint32
+	txHash             chainhash.Hash
+	txIndex            uint32
+	inclusionBlockHash chainhash.Hash
+	// nil if delegation is sent with inclusion proof recorded in database
 	inclusionBlock              *wire.MsgBlock
 	requiredInclusionBlockDepth uint64
 }
 
 func (app *StakerApp) buildOwnedDelegation(
+	ctx context.Context,
 	req *sendDelegationRequest,
 	stakerAddress btcutil.Address,
 	storedTx *stakerdb.StoredTransaction,
 	stakingTxInclusionProof []byte,
 ) (*cl.DelegationData, error) {
-	externalData, err := app.retrieveExternalDelegationData(stakerAddress)
+	if err := checkSignable(storedTx); err != nil {
+		return nil, err
+	}
+
+	signer := app.newSignerSession(ctx, stakerAddress)
+	defer signer.Close()
+
+	externalData, err := app.retrieveExternalDelegationData(signer)
+	if err != nil {
+		return nil, err
+	}
+
+	slashingFee, err := app.getSlashingFee(externalData.babylonParams, len(storedTx.FinalityProvidersBtcPks))
 	if err != nil {
 		return nil, err
 	}
 
-	slashingFee := app.getSlashingFee(externalData.babylonParams.MinSlashingTxFeeSat)
+	// checked before building slashing transaction, which uses the same time lock,
+	// so that params not fitting unbonding script are reported as regular error
+	unbondingTime, err := unbondingTimeLock(externalData.babylonParams)
+	if err != nil {
+		return nil, fmt.Errorf("error creating undelegation data: %w", err)
+	}
+
+	// checked before building slashing transaction, so that records not matching
+	// their staking transaction are flagged as corrupted instead of stopping the app
+	covenantPks, covenantQuorum, err := app.stakingTxCovenantCommittee(
+		&req.txHash,
+		storedTx,
+		externalData.stakerPrivKey.PubKey(),
+		externalData.babylonParams,
+	)
+
+	if err != nil {
+		return nil, fmt.Errorf("error creating undelegation data: %w", err)
+	}
 
 	slashingTx, slashingTxSig, err := buildSlashingTxAndSig(slashingFee, externalData, storedTx, app.network)
 	if err != nil {
@@ -51,20 +85,33 @@ func (app *StakerApp) buildOwnedDelegation(
 		}).Fatalf("Failed to build delegation data for already confirmed staking transaction")
 	}
 
+	// unbonding transaction spends staking output, so it requires signatures from
+	// quorum of the committee commited to in staking output. Covenant emulators
+	// only sign as part of current babylon committee.
+	activeCovenantMembers := numCommonKeys(covenantPks, externalData.babylonParams.CovenantPks)
+
+	if activeCovenantMembers < covenantQuorum {
+		return nil, fmt.Errorf(
+			"error creating undelegation data: only %d members of covenant committee controlling staking output are still part of babylon covenant committee, required quorum: %d",
+			activeCovenantMembers,
+			covenantQuorum,
+		)
+	}
+
 	// TODO: Option to use custom fee rate, as estimator uses pretty big value for fee
 	// in case of estimation failure (25 sat/byte)
-	unbondingTxFeeRatePerKb := btcutil.Amount(app.feeEstimator.EstimateFeePerKb())
+	unbondingFeeEstimate := app.estimateFee(app.config.StakerConfig.UnbondingTxConfTarget)
 
 	undelegationData, err := createUndelegationData(
 		storedTx,
 		externalData.stakerPrivKey,
-		externalData.babylonParams.CovenantPks,
-		externalData.babylonParams.CovenantQuruomThreshold,
+		covenantPks,
+		covenantQuorum,
 		externalData.babylonParams.SlashingAddress,
-		unbondingTxFeeRatePerKb,
+		unbondingFeeEstimate.FeeRate(),
 		// TODO: Possiblity to customize finalization time
-		uint16(externalData.babylonParams.MinUnbondingTime)+1,
-		app.getSlashingFee(externalData.babylonParams.MinSlashingTxFeeSat),
+		unbondingTime,
+		slashingFee,
 		externalData.babylonParams.SlashingRate,
 		app.network,
 	)
@@ -74,9 +121,13 @@ func (app *StakerApp) buildOwnedDelegation(
 		return nil, fmt.Errorf("error creating undelegation data: %w", err)
 	}
 
+	// unbonding transaction is built together with delegation, long before it
+	// is sent
+	app.recordFeeEstimate(&req.txHash, stakerdb.UnbondingTxFeeEstimate, unbondingFeeEstimate)
+
 	dg := createDelegationData(
 		externalData.stakerPrivKey.PubKey(),
-		req.inclusionBlock,
+		&req.inclusionBlockHash,
 		req.txIndex,
 		storedTx,
 		slashingTx,
@@ -90,11 +141,16 @@ func (app *StakerApp) buildOwnedDelegation(
 }
 
 func (app *StakerApp) buildDelegation(
+	ctx context.Context,
 	req *sendDelegationRequest,
 	stakerAddress btcutil.Address,
 	storedTx *stakerdb.StoredTransaction) (*cl.DelegationData, error) {
 
-	stakingTxInclusionProof := app.mustBuildInclusionProof(req)
+	stakingTxInclusionProof, err := app.stakingTxInclusionProof(req, storedTx)
+
+	if err != nil {
+		return nil, err
+	}
 
 	if storedTx.Watched {
 		watchedData, err := app.txTracker.GetWatchedTransactionData(&req.txHash)
@@ -111,7 +167,8 @@ func (app *StakerApp) buildDelegation(
 
 		undelegationData := cl.UndelegationData{
 			UnbondingTransaction:         watchedData.UnbondingTx,
-			UnbondingTxValue:             btcutil.Amount(watchedData.UnbondingTx.TxOut[0].Value),
+			UnbondingOutputIdx:           watchedData.UnbondingOutputIndex,
+			UnbondingTxValue:             btcutil.Amount(watchedData.UnbondingOutput().Value),
 			UnbondingTxUnbondingTime:     watchedData.UnbondingTime,
 			SlashUnbondingTransaction:    watchedData.SlashingUnbondingTx,
 			SlashUnbondingTransactionSig: watchedData.SlashingUnbondingTxSig,
@@ -119,7 +176,7 @@ func (app *StakerApp) buildDelegation(
 
 		dg := createDelegationData(
 			watchedData.StakerBtcPubKey,
-			req.inclusionBlock,
+			&req.inclusionBlockHash,
 			req.txIndex,
 			storedTx,
 			watchedData.SlashingTx,
@@ -131,6 +188,7 @@ func (app *StakerApp) buildDelegation(
 		return dg, nil
 	} else {
 		return app.buildOwnedDelegation(
+			ctx,
 			req,
 			stakerAddress,
 			storedTx,
@@ -139,17 +197,116 @@ func (app *StakerApp) buildDelegation(
 	}
 }
 
-// TODO for now we launch this handler indefinitly. At some point we may introduce
-// timeout, and if signatures are not find in this timeout, then we may submit
-// evidence that covenant members are censoring our staking transactions
-func (app *StakerApp) checkForUnbondingTxSignaturesOnBabylon(stakingTxHash *chainhash.Hash) {
-	checkSigTicker := time.NewTicker(app.config.StakerConfig.UnbondingTxCheckInterval)
-	defer checkSigTicker.Stop()
-	defer app.wg.Done()
+// stakingTxCovenantCommittee returns covenant committee which controls staking
+// output of given transaction. Usually this is the current babylon committee, but
+// if committee was rotated after staking transaction was created, all transactions
+// spending staking output must be built against committee from params version
+// under which staking transaction was created. Records whose staking output does
+// not match any of the committees are flagged as corrupted.
+func (app *StakerApp) stakingTxCovenantCommittee(
+	stakingTxHash *chainhash.Hash,
+	storedTx *stakerdb.StoredTransaction,
+	stakerPubKey *btcec.PublicKey,
+	currentParams *cl.StakingParams,
+) ([]*btcec.PublicKey, uint32, error) {
+	if err := app.checkStakingTxRecord(stakingTxHash, storedTx); err != nil {
+		return nil, 0, err
+	}
+
+	if stakingOutputCommitsTo(
+		storedTx,
+		stakerPubKey,
+		currentParams.CovenantPks,
+		currentParams.CovenantQuruomThreshold,
+		app.network,
+	) {
+		return currentParams.CovenantPks, currentParams.CovenantQuruomThreshold, nil
+	}
+
+	committees := []covenantCommittee{currentCommittee(currentParams)}
+
+	if storedTx.ParamsVersion == 0 {
+		// committee under which transaction was created is not known, so mismatch
+		// is only certain if other output matches current committee
+		if _, found := findStakingOutput(storedTx, stakerPubKey, committees, app.network); found {
+			return nil, 0, app.reportStakingOutputMismatch(stakingTxHash, storedTx, stakerPubKey, committees)
+		}
+
+		return nil, 0, fmt.Errorf(
+			"staking output of transaction %s does not match current covenant committee, and transaction was created before staking params were tracked",
+			stakingTxHash,
+		)
+	}
+
+	snapshot, err := app.txTracker.GetStakingParams(storedTx.ParamsVersion)
+
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to retrieve staking params version %d: %w", storedTx.ParamsVersion, err)
+	}
+
+	if !stakingOutputCommitsTo(
+		storedTx,
+		stakerPubKey,
+		snapshot.CovenantPks,
+		snapshot.CovenantQuorum,
+		app.network,
+	) {
+		committees = append(committees, covenantCommittee{pks: snapshot.CovenantPks, quorum: snapshot.CovenantQuorum})
+		return nil, 0, app.reportStakingOutputMismatch(stakingTxHash, storedTx, stakerPubKey, committees)
+	}
+
+	app.logger.WithFields(logrus.Fields{
+		"stakingTxHash":  stakingTxHash,
+		"paramsVersion":  storedTx.ParamsVersion,
+		"covenantQuorum": snapshot.CovenantQuorum,
+		"currentQuorum":  currentParams.CovenantQuruomThreshold,
+	}).Warn("Babylon covenant committee was rotated since staking transaction was created. Using committee controlling staking output")
+
+	return snapshot.CovenantPks, snapshot.CovenantQuorum, nil
+}
+
+// checkForUnbondingTxSignaturesOnBabylon polls babylon until covenant unbonding
+// signatures of delegation are received. If they are not received within
+// configured timeout, delegation is moved to UNBONDING_SIGNATURES_TIMEOUT state
+// and babylon is polled less often, until operator retries or aborts the wait.
+func (app *StakerApp) checkForUnbondingTxSignaturesOnBabylon(stakingTxHash *chainhash.Hash, timedOut bool) {
+	commands, added := app.unbondingWaits.add(*stakingTxHash)
+
+	if !added {
+		// signatures of this delegation are already waited for
+		return
+	}
+
+	defer app.unbondingWaits.remove(*stakingTxHash, commands)
+
+	checkSigTicker := app.unbondingSigsCheckTicker(timedOut)
+	defer func() {
+		checkSigTicker.Stop()
+	}()
+
+	timeout := app.config.StakerConfig.UnbondingSigsTimeout
+	deadline := app.clock.Now().Add(timeout)
+
+	// invalid signatures which were already reported, so that every invalid
+	// signature is reported only once
+	reportedInvalidSigs := make(map[string]struct{})
 
 	for {
 		select {
-		case <-checkSigTicker.C:
+		case <-checkSigTicker.Chan():
+			if !timedOut && timeout > 0 && !app.clock.Now().Before(deadline) {
+				timedOut = true
+
+				utils.PushOrQuit[*unbondingSignaturesTimeoutEvent](
+					app.unbondingSignaturesTimeoutEvChan,
+					&unbondingSignaturesTimeoutEvent{stakingTxHash: *stakingTxHash, timeout: timeout},
+					app.quit,
+				)
+
+				checkSigTicker.Stop()
+				checkSigTicker = app.unbondingSigsCheckTicker(true)
+			}
+
 			di, err := app.babylonClient.QueryDelegationInfo(stakingTxHash)
 
 			if err != nil {
@@ -171,6 +328,22 @@ func (app *StakerApp) checkForUnbondingTxSignaturesOnBabylon(stakingTxHash *chai
 				continue
 			}
 
+			if di.Status == cl.DelegationStatusUnbonded {
+				// delegation expired e.g because covenants did not sign it before
+				// its timelock passed, so there are no signatures to wait for
+				app.logger.WithFields(logrus.Fields{
+					"stakingTxHash": stakingTxHash,
+				}).Warn("Delegation was unbonded on babylon before it became active locally")
+
+				utils.PushOrQuit[*delegationExpiredOnBabylonEvent](
+					app.delegationExpiredOnBabylonEvChan,
+					&delegationExpiredOnBabylonEvent{stakingTxHash: *stakingTxHash},
+					app.quit,
+				)
+
+				return
+			}
+
 			if di.UndelegationInfo == nil {
 				// As we only start this handler when we are sure delegation received unbonding request
 				// this can only that:
@@ -195,16 +368,42 @@ func (app *StakerApp) checkForUnbondingTxSignaturesOnBabylon(stakingTxHash *chai
 
 			// we have enough signatures to submit unbonding tx this means that delegation is active
 			if len(di.UndelegationInfo.CovenantUnbondingSignatures) >= int(params.CovenantQuruomThreshold) {
+				validSigs, err := app.validCovenantUnbondingSignatures(
+					stakingTxHash,
+					di.UndelegationInfo.CovenantUnbondingSignatures,
+					params,
+					reportedInvalidSigs,
+				)
+
+				if err != nil {
+					app.logger.WithFields(logrus.Fields{
+						"stakingTxHash": stakingTxHash,
+						"err":           err,
+					}).Error("Failed to verify covenant unbonding signatures")
+					continue
+				}
+
+				if validSigs == nil {
+					// not enough valid signatures, keep polling babylon for corrected ones
+					continue
+				}
+
 				app.logger.WithFields(logrus.Fields{
 					"stakingTxHash": stakingTxHash,
-					"numSignatures": len(di.UndelegationInfo.CovenantUnbondingSignatures),
+					"numSignatures": len(validSigs),
 				}).Debug("Received enough covenant unbonding signatures on babylon")
 
 				req := &unbondingTxSignaturesConfirmedOnBabylonEvent{
 					stakingTxHash:               *stakingTxHash,
-					covenantUnbondingSignatures: di.UndelegationInfo.CovenantUnbondingSignatures,
+					covenantUnbondingSignatures: validSigs,
 				}
 
+				app.recordEventIntent(&stakerdb.EventIntent{
+					StakingTxHash:      *stakingTxHash,
+					Type:               proto.EventIntentType_EVENT_INTENT_UNBONDING_SIGNATURES_RECEIVED,
+					CovenantSignatures: babylonCovSigsToDbSigSigs(validSigs),
+				})
+
 				utils.PushOrQuit[*unbondingTxSignaturesConfirmedOnBabylonEvent](
 					app.unbondingTxSignaturesConfirmedOnBabylonEvChan,
 					req,
@@ -220,6 +419,18 @@ func (app *StakerApp) checkForUnbondingTxSignaturesOnBabylon(stakingTxHash *chai
 				}).Debug("Received not enough covenant unbonding signatures on babylon")
 			}
 
+		case _, ok := <-commands:
+			if !ok {
+				// operator aborted the wait
+				return
+			}
+
+			timedOut = false
+			deadline = app.clock.Now().Add(timeout)
+
+			checkSigTicker.Stop()
+			checkSigTicker = app.unbondingSigsCheckTicker(false)
+
 		case <-app.quit:
 			return
 		}
@@ -231,7 +442,14 @@ func (app *StakerApp) finalityProviderExists(fpPk *btcec.PublicKey) error {
 		return fmt.Errorf("provided finality provider public key is nil")
 	}
 
-	_, err := app.babylonClient.QueryFinalityProvider(fpPk)
+	// babylon identifies finality providers by x-only keys
+	fpPk, err := normalizeXOnlyKey(fpPk)
+
+	if err != nil {
+		return fmt.Errorf("invalid finality provider public key: %w", err)
+	}
+
+	_, err = app.babylonClient.QueryFinalityProvider(fpPk)
 
 	if err != nil {
 		return fmt.Errorf("error checking if finality provider exists on babylon chain: %w", err)
@@ -0,0 +1,156 @@
+package staker
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/sirupsen/logrus"
+)
+
+// ErrBroadcastsPaused is returned instead of sending transaction to btc while
+// broadcasts are paused by operator
+var ErrBroadcastsPaused = errors.New("broadcasting of btc transactions is paused")
+
+// broadcastSwitch allows operator to stop staker from sending any transaction to
+// btc, while tracking of already sent transactions continues
+type broadcastSwitch struct {
+	mu     sync.Mutex
+	paused bool
+	// closed when broadcasts are resumed
+	resumed chan struct{}
+}
+
+func newBroadcastSwitch() *broadcastSwitch {
+	return &broadcastSwitch{
+		resumed: make(chan struct{}),
+	}
+}
+
+func (s *broadcastSwitch) isPaused() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.paused
+}
+
+// set returns true if state of the switch changed
+func (s *broadcastSwitch) set(paused bool) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.paused == paused {
+		return false
+	}
+
+	s.paused = paused
+
+	if paused {
+		s.resumed = make(chan struct{})
+	} else {
+		close(s.resumed)
+	}
+
+	return true
+}
+
+// waitResumed blocks until broadcasts are not paused or context is done
+func (s *broadcastSwitch) waitResumed(ctx context.Context) error {
+	s.mu.Lock()
+	paused := s.paused
+	resumed := s.resumed
+	s.mu.Unlock()
+
+	if !paused {
+		return nil
+	}
+
+	select {
+	case <-resumed:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// loadBroadcastsPaused restores state of broadcast switch persisted in db
+func (app *StakerApp) loadBroadcastsPaused() error {
+	paused, err := app.txTracker.BroadcastsPaused()
+
+	if err != nil {
+		return err
+	}
+
+	app.broadcasts.set(paused)
+
+	if paused {
+		app.logger.Warn("Broadcasting of btc transactions is paused. Use resume-broadcasts to enable it")
+	}
+
+	return nil
+}
+
+// BroadcastsPaused returns true if staker does not send any transactions to btc
+func (app *StakerApp) BroadcastsPaused() bool {
+	return app.broadcasts.isPaused()
+}
+
+// SetBroadcastEnabled pauses or resumes sending transactions to btc. The setting
+// is persisted, so paused broadcasts stay paused after restart until they are
+// explicitly resumed.
+func (app *StakerApp) SetBroadcastEnabled(enabled bool) error {
+	if err := app.txTracker.SetBroadcastsPaused(!enabled); err != nil {
+		return err
+	}
+
+	if !app.broadcasts.set(!enabled) {
+		return nil
+	}
+
+	if enabled {
+		app.logger.Info("Broadcasting of btc transactions resumed")
+		// sweeps and renewals which became due while broadcasts were paused are
+		// sent right away
+		app.notifyAutoSweepNewBlock()
+		app.notifyAutoRenewNewBlock()
+	} else {
+		app.logger.Warn("Broadcasting of btc transactions paused")
+	}
+
+	return nil
+}
+
+// checkBroadcastsEnabled returns ErrBroadcastsPaused if broadcasts are paused, so
+// that requests which would send transaction to btc fail early
+func (app *StakerApp) checkBroadcastsEnabled() error {
+	if app.broadcasts.isPaused() {
+		return ErrBroadcastsPaused
+	}
+
+	return nil
+}
+
+// waitUntilBroadcastsEnabled parks background task while broadcasts are paused.
+// Returns error if context is done before broadcasts are resumed.
+func (app *StakerApp) waitUntilBroadcastsEnabled(ctx context.Context, stakingTxHash *chainhash.Hash) error {
+	if !app.broadcasts.isPaused() {
+		return nil
+	}
+
+	app.logger.WithFields(logrus.Fields{
+		"stakingTxHash": stakingTxHash,
+	}).Info("Broadcasting of btc transactions is paused. Waiting until it is resumed")
+
+	return app.broadcasts.waitResumed(ctx)
+}
+
+// sendRawTransaction is the only place where staker sends transactions to btc
+func (app *StakerApp) sendRawTransaction(tx *wire.MsgTx) (*chainhash.Hash, error) {
+	if err := app.checkBroadcastsEnabled(); err != nil {
+		return nil, err
+	}
+
+	return app.wc.SendRawTransaction(tx, true)
+}
@@ -0,0 +1,37 @@
+package staker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBroadcastSwitchParksUntilResumed(t *testing.T) {
+	s := newBroadcastSwitch()
+
+	// not paused switch does not block
+	require.NoError(t, s.waitResumed(context.Background()))
+
+	require.True(t, s.set(true))
+	require.False(t, s.set(true))
+	require.True(t, s.isPaused())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	require.ErrorIs(t, s.waitResumed(ctx), context.DeadlineExceeded)
+
+	resumed := make(chan error, 1)
+	go func() {
+		resumed <- s.waitResumed(context.Background())
+	}()
+
+	require.True(t, s.set(false))
+	require.NoError(t, <-resumed)
+	require.False(t, s.isPaused())
+
+	// switch can be paused again after resume
+	require.True(t, s.set(true))
+	require.True(t, s.isPaused())
+}
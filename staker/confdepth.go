@@ -0,0 +1,50 @@
+package staker
+
+import (
+	"errors"
+	"fmt"
+
+	cl "github.com/babylonchain/btc-staker/babylonclient"
+	"github.com/babylonchain/btc-staker/stakerdb"
+	"github.com/sirupsen/logrus"
+)
+
+var ErrInvalidDepthOverride = errors.New("invalid required depth override")
+
+// requiredConfirmationDepth returns depth on btc chain which staking transaction
+// must reach before delegation is sent to babylon. Override replaces depth from
+// babylon params, and as the same depth is reported to babylon, it must not be
+// lower than depth required by babylon.
+func (app *StakerApp) requiredConfirmationDepth(override *uint32, params *cl.StakingParams) (uint32, error) {
+	if override == nil {
+		return params.ConfirmationTimeBlocks, nil
+	}
+
+	if !app.config.StakerConfig.AllowDepthOverride {
+		return 0, fmt.Errorf("%w: depth overrides are disabled, enable them with allowdepthoverride option",
+			ErrInvalidDepthOverride)
+	}
+
+	if *override < params.ConfirmationTimeBlocks {
+		return 0, fmt.Errorf("%w: required depth %d is lower than depth %d required by babylon",
+			ErrInvalidDepthOverride, *override, params.ConfirmationTimeBlocks)
+	}
+
+	app.logger.WithFields(logrus.Fields{
+		"requiredDepth": *override,
+		"babylonDepth":  params.ConfirmationTimeBlocks,
+	}).Warn("Overriding depth required before delegation is sent to babylon")
+
+	return *override, nil
+}
+
+// storedRequiredDepth returns depth which stored staking transaction must reach
+// before delegation is sent to babylon. Babylon params could change since
+// transaction was stored, so depth is never lower than currently required.
+func storedRequiredDepth(tx *stakerdb.StoredTransaction, params *cl.StakingParams) uint32 {
+	if tx.RequiredDepth > params.ConfirmationTimeBlocks {
+		return tx.RequiredDepth
+	}
+
+	return params.ConfirmationTimeBlocks
+}
@@ -0,0 +1,54 @@
+package staker
+
+import (
+	"testing"
+
+	cl "github.com/babylonchain/btc-staker/babylonclient"
+	scfg "github.com/babylonchain/btc-staker/stakercfg"
+	"github.com/babylonchain/btc-staker/stakerdb"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequiredConfirmationDepth(t *testing.T) {
+	cfg := scfg.DefaultConfig()
+	app := &StakerApp{config: &cfg, logger: logrus.New()}
+	params := &cl.StakingParams{ConfirmationTimeBlocks: 10}
+
+	depth := func(d uint32) *uint32 {
+		return &d
+	}
+
+	// babylon depth is used without override
+	requiredDepth, err := app.requiredConfirmationDepth(nil, params)
+	require.NoError(t, err)
+	require.Equal(t, uint32(10), requiredDepth)
+
+	// overrides are disabled by default
+	_, err = app.requiredConfirmationDepth(depth(20), params)
+	require.ErrorIs(t, err, ErrInvalidDepthOverride)
+
+	cfg.StakerConfig.AllowDepthOverride = true
+
+	requiredDepth, err = app.requiredConfirmationDepth(depth(20), params)
+	require.NoError(t, err)
+	require.Equal(t, uint32(20), requiredDepth)
+
+	requiredDepth, err = app.requiredConfirmationDepth(depth(10), params)
+	require.NoError(t, err)
+	require.Equal(t, uint32(10), requiredDepth)
+
+	// depth lower than required by babylon is never allowed
+	_, err = app.requiredConfirmationDepth(depth(9), params)
+	require.ErrorIs(t, err, ErrInvalidDepthOverride)
+}
+
+func TestStoredRequiredDepth(t *testing.T) {
+	params := &cl.StakingParams{ConfirmationTimeBlocks: 10}
+
+	// transaction stored before depth was tracked
+	require.Equal(t, uint32(10), storedRequiredDepth(&stakerdb.StoredTransaction{}, params))
+	require.Equal(t, uint32(20), storedRequiredDepth(&stakerdb.StoredTransaction{RequiredDepth: 20}, params))
+	// babylon depth increased since transaction was stored
+	require.Equal(t, uint32(10), storedRequiredDepth(&stakerdb.StoredTransaction{RequiredDepth: 5}, params))
+}
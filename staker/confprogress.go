@@ -0,0 +1,174 @@
+package staker
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+)
+
+type ConfirmationType string
+
+const (
+	StakingTxConfirmation   ConfirmationType = "staking"
+	UnbondingTxConfirmation ConfirmationType = "unbonding"
+	SpendTxConfirmation     ConfirmationType = "spend"
+)
+
+// ConfirmationProgress describes progress of waiting for confirmations of
+// transaction related to staking transaction i.e staking transaction itself, its
+// unbonding transaction or transaction spending stake
+type ConfirmationProgress struct {
+	Type                  ConfirmationType
+	TxHash                chainhash.Hash
+	Confirmations         uint32
+	RequiredConfirmations uint32
+	// time of the last received update, or time when waiting started if no update
+	// was received yet
+	LastUpdate time.Time
+}
+
+// confirmationProgressTracker keeps progress of confirmation waits in progress,
+// indexed by hash of staking transaction. Progress is only kept in memory, and is
+// removed once transaction receives required number of confirmations.
+type confirmationProgressTracker struct {
+	mu       sync.Mutex
+	progress map[chainhash.Hash]map[ConfirmationType]*ConfirmationProgress
+}
+
+func newConfirmationProgressTracker() *confirmationProgressTracker {
+	return &confirmationProgressTracker{
+		progress: make(map[chainhash.Hash]map[ConfirmationType]*ConfirmationProgress),
+	}
+}
+
+func (t *confirmationProgressTracker) started(
+	stakingTxHash chainhash.Hash,
+	confType ConfirmationType,
+	txHash chainhash.Hash,
+	requiredConfirmations uint32,
+	now time.Time,
+) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	txProgress, ok := t.progress[stakingTxHash]
+
+	if !ok {
+		txProgress = make(map[ConfirmationType]*ConfirmationProgress)
+		t.progress[stakingTxHash] = txProgress
+	}
+
+	txProgress[confType] = &ConfirmationProgress{
+		Type:                  confType,
+		TxHash:                txHash,
+		RequiredConfirmations: requiredConfirmations,
+		LastUpdate:            now,
+	}
+}
+
+// update records number of confirmations left, as reported by chain notifier
+func (t *confirmationProgressTracker) update(
+	stakingTxHash chainhash.Hash,
+	confType ConfirmationType,
+	confirmationsLeft uint32,
+	now time.Time,
+) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	progress, ok := t.progress[stakingTxHash][confType]
+
+	if !ok {
+		return
+	}
+
+	if confirmationsLeft > progress.RequiredConfirmations {
+		confirmationsLeft = progress.RequiredConfirmations
+	}
+
+	progress.Confirmations = progress.RequiredConfirmations - confirmationsLeft
+	progress.LastUpdate = now
+}
+
+func (t *confirmationProgressTracker) finished(stakingTxHash chainhash.Hash, confType ConfirmationType) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	txProgress, ok := t.progress[stakingTxHash]
+
+	if !ok {
+		return
+	}
+
+	delete(txProgress, confType)
+
+	if len(txProgress) == 0 {
+		delete(t.progress, stakingTxHash)
+	}
+}
+
+// get returns copy of progress of confirmation waits related to staking
+// transaction, ordered by type
+func (t *confirmationProgressTracker) get(stakingTxHash chainhash.Hash) []ConfirmationProgress {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	txProgress := t.progress[stakingTxHash]
+	result := make([]ConfirmationProgress, 0, len(txProgress))
+
+	for _, p := range txProgress {
+		result = append(result, *p)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Type < result[j].Type
+	})
+
+	return result
+}
+
+// ConfirmationWait is confirmation wait in progress together with staking
+// transaction it relates to
+type ConfirmationWait struct {
+	StakingTxHash chainhash.Hash
+	ConfirmationProgress
+}
+
+// all returns copy of progress of all confirmation waits, ordered by time of the
+// last update
+func (t *confirmationProgressTracker) all() []ConfirmationWait {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var result []ConfirmationWait
+
+	for stakingTxHash, txProgress := range t.progress {
+		for _, p := range txProgress {
+			result = append(result, ConfirmationWait{
+				StakingTxHash:        stakingTxHash,
+				ConfirmationProgress: *p,
+			})
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].LastUpdate.Before(result[j].LastUpdate)
+	})
+
+	return result
+}
+
+// ConfirmationProgress returns progress of confirmation waits related to given
+// staking transaction. Empty result means that staker is not waiting for any
+// confirmations of this transaction at the moment.
+func (app *StakerApp) ConfirmationProgress(stakingTxHash *chainhash.Hash) ([]ConfirmationProgress, error) {
+	// make sure transaction is tracked, so that callers can distinguish unknown
+	// transaction from transaction without pending confirmations
+	if _, err := app.txTracker.GetTransaction(stakingTxHash); err != nil {
+		return nil, err
+	}
+
+	return app.confProgress.get(*stakingTxHash), nil
+}
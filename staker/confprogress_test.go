@@ -0,0 +1,36 @@
+package staker
+
+import (
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfirmationProgressTracksUpdates(t *testing.T) {
+	tracker := newConfirmationProgressTracker()
+	stakingTxHash := chainhash.HashH([]byte("staking tx"))
+	spendTxHash := chainhash.HashH([]byte("spend tx"))
+
+	tracker.started(stakingTxHash, StakingTxConfirmation, stakingTxHash, 6, testClockStart)
+	tracker.started(stakingTxHash, SpendTxConfirmation, spendTxHash, 3, testClockStart)
+
+	// notifier reports number of confirmations left
+	updateTime := testClockStart.Add(10 * time.Minute)
+	tracker.update(stakingTxHash, StakingTxConfirmation, 2, updateTime)
+
+	progress := tracker.get(stakingTxHash)
+	require.Len(t, progress, 2)
+	require.Equal(t, SpendTxConfirmation, progress[0].Type)
+	require.Equal(t, spendTxHash, progress[0].TxHash)
+	require.Equal(t, uint32(0), progress[0].Confirmations)
+	require.Equal(t, StakingTxConfirmation, progress[1].Type)
+	require.Equal(t, uint32(4), progress[1].Confirmations)
+	require.Equal(t, uint32(6), progress[1].RequiredConfirmations)
+	require.Equal(t, updateTime, progress[1].LastUpdate)
+
+	tracker.finished(stakingTxHash, SpendTxConfirmation)
+	tracker.finished(stakingTxHash, StakingTxConfirmation)
+	require.Empty(t, tracker.get(stakingTxHash))
+}
@@ -0,0 +1,206 @@
+package staker
+
+import (
+	"sync"
+	"time"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// initial interval between retries of failed confirmation registration
+	confRegistrationRetryInterval = 10 * time.Second
+	// maximum interval between retries of failed confirmation registration
+	maxConfRegistrationRetryInterval = 10 * time.Minute
+)
+
+// pendingConfRegistration is registration of confirmation notification for
+// transaction already sent to btc, which failed and needs to be retried
+type pendingConfRegistration struct {
+	stakingTxHash chainhash.Hash
+	// register registers confirmation notification and starts waiting for it,
+	// height hint used for registration must be captured before transaction was sent
+	register    func() error
+	attempts    uint32
+	nextAttempt time.Time
+}
+
+// pendingConfRegistrations keeps confirmation registrations which failed, keyed
+// by hash of transaction which confirmation we wait for
+type pendingConfRegistrations struct {
+	mu            sync.Mutex
+	registrations map[chainhash.Hash]*pendingConfRegistration
+	// notified on each new block, so that all pending registrations are retried
+	newBlock chan struct{}
+}
+
+func newPendingConfRegistrations() *pendingConfRegistrations {
+	return &pendingConfRegistrations{
+		registrations: make(map[chainhash.Hash]*pendingConfRegistration),
+		newBlock:      make(chan struct{}, 1),
+	}
+}
+
+// failed records failed registration, next attempt is scheduled with exponential
+// backoff
+func (p *pendingConfRegistrations) failed(
+	txHash chainhash.Hash,
+	stakingTxHash chainhash.Hash,
+	register func() error,
+	now time.Time,
+) *pendingConfRegistration {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	reg, ok := p.registrations[txHash]
+
+	if !ok {
+		reg = &pendingConfRegistration{
+			stakingTxHash: stakingTxHash,
+			register:      register,
+		}
+		p.registrations[txHash] = reg
+	}
+
+	interval := confRegistrationRetryInterval
+	for i := uint32(0); i < reg.attempts && interval < maxConfRegistrationRetryInterval; i++ {
+		interval *= 2
+	}
+
+	if interval > maxConfRegistrationRetryInterval {
+		interval = maxConfRegistrationRetryInterval
+	}
+
+	reg.attempts++
+	reg.nextAttempt = now.Add(interval)
+
+	result := *reg
+	return &result
+}
+
+func (p *pendingConfRegistrations) remove(txHash chainhash.Hash) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	delete(p.registrations, txHash)
+}
+
+func (p *pendingConfRegistrations) contains(txHash chainhash.Hash) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	_, ok := p.registrations[txHash]
+	return ok
+}
+
+func (p *pendingConfRegistrations) len() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return len(p.registrations)
+}
+
+// toRetry returns registrations which should be retried. If all is false, only
+// registrations which backoff elapsed are returned.
+func (p *pendingConfRegistrations) toRetry(now time.Time, all bool) map[chainhash.Hash]pendingConfRegistration {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	toRetry := make(map[chainhash.Hash]pendingConfRegistration)
+
+	for txHash, reg := range p.registrations {
+		if all || !now.Before(reg.nextAttempt) {
+			toRetry[txHash] = *reg
+		}
+	}
+
+	return toRetry
+}
+
+// notifyNewBlock never blocks, retries triggered by blocks are coalesced
+func (p *pendingConfRegistrations) notifyNewBlock() {
+	select {
+	case p.newBlock <- struct{}{}:
+	default:
+	}
+}
+
+// registerConfirmationOrRetry registers confirmation notification for transaction
+// which is already sent to btc. If registration fails, it is retried in background
+// as failing the request would make caller think transaction was not sent.
+// Returns true if registration succeeded immediately.
+func (app *StakerApp) registerConfirmationOrRetry(
+	txHash chainhash.Hash,
+	stakingTxHash chainhash.Hash,
+	register func() error,
+) bool {
+	err := register()
+
+	if err == nil {
+		return true
+	}
+
+	app.recordConfRegistrationFailure(txHash, stakingTxHash, register, err)
+	return false
+}
+
+func (app *StakerApp) recordConfRegistrationFailure(
+	txHash chainhash.Hash,
+	stakingTxHash chainhash.Hash,
+	register func() error,
+	err error,
+) {
+	reg := app.confRegistrations.failed(txHash, stakingTxHash, register, app.clock.Now())
+
+	app.logger.WithFields(logrus.Fields{
+		"txHash":        txHash,
+		"stakingTxHash": stakingTxHash,
+		"attempts":      reg.attempts,
+		"nextAttempt":   reg.nextAttempt,
+		"err":           err,
+	}).Warn("Failed to register for transaction confirmation. Registration will be retried")
+}
+
+func (app *StakerApp) retryConfRegistrations(all bool) {
+	for txHash, reg := range app.confRegistrations.toRetry(app.clock.Now(), all) {
+		if err := reg.register(); err != nil {
+			app.recordConfRegistrationFailure(txHash, reg.stakingTxHash, reg.register, err)
+			continue
+		}
+
+		app.logger.WithFields(logrus.Fields{
+			"txHash":        txHash,
+			"stakingTxHash": reg.stakingTxHash,
+			"attempts":      reg.attempts + 1,
+		}).Info("Successfully registered for transaction confirmation")
+
+		app.confRegistrations.remove(txHash)
+	}
+}
+
+// retryConfRegistrationsLoop retries failed confirmation registrations with
+// backoff, and all of them on each new block
+func (app *StakerApp) retryConfRegistrationsLoop() {
+	ticker := app.clock.NewTicker(confRegistrationRetryInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.Chan():
+			app.retryConfRegistrations(false)
+
+		case <-app.confRegistrations.newBlock:
+			app.retryConfRegistrations(true)
+
+		case <-app.quit:
+			return
+		}
+	}
+}
+
+// ConfirmationRegistrationPending returns true if transaction was sent to btc,
+// but staker failed to register for its confirmation and is still retrying
+func (app *StakerApp) ConfirmationRegistrationPending(txHash *chainhash.Hash) bool {
+	return app.confRegistrations.contains(*txHash)
+}
@@ -0,0 +1,85 @@
+package staker
+
+import (
+	"testing"
+	"time"
+
+	"github.com/babylonchain/btc-staker/utils"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFailedConfirmationRegistrationIsRetried(t *testing.T) {
+	tests := []struct {
+		name  string
+		retry func(app *StakerApp, clock *utils.FakeClock)
+	}{
+		{
+			name: "on new block",
+			retry: func(app *StakerApp, _ *utils.FakeClock) {
+				app.confRegistrations.notifyNewBlock()
+			},
+		},
+		{
+			name: "after backoff",
+			retry: func(_ *StakerApp, clock *utils.FakeClock) {
+				clock.Advance(confRegistrationRetryInterval)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deps := newTestStakerDeps(t)
+			deps.notifier.failConfRegistrations = 1
+
+			stakerKey := genPrivKey(t)
+			deps.wc = &keyWallet{testWallet: deps.wallet, privKey: stakerKey}
+
+			stakerAddress, err := btcutil.NewAddressWitnessPubKeyHash(
+				btcutil.Hash160(stakerKey.PubKey().SerializeCompressed()),
+				&deps.config.ActiveNetParams,
+			)
+			require.NoError(t, err)
+
+			stakingTxHash := deps.addSpendableTransaction(t, stakerKey, stakerAddress)
+
+			app := deps.newApp(t)
+			clock := app.clock.(*utils.FakeClock)
+
+			// spend transaction is already sent, so request succeeds and only
+			// reports pending registration
+			spendTxHash, _, err := app.SpendStake(stakingTxHash, nil, nil)
+			require.NoError(t, err)
+			require.True(t, app.ConfirmationRegistrationPending(spendTxHash))
+
+			_, registered := deps.notifier.confHeightHint(spendTxHash)
+			require.False(t, registered)
+
+			waiters := clock.NumWaiters()
+			retryDone := make(chan struct{})
+			go func() {
+				defer close(retryDone)
+				app.retryConfRegistrationsLoop()
+			}()
+			t.Cleanup(func() {
+				close(app.quit)
+				<-retryDone
+			})
+
+			// wait for retry loop to start its ticker
+			require.Eventually(t, func() bool {
+				return clock.NumWaiters() == waiters+1
+			}, time.Second, time.Millisecond)
+
+			tt.retry(app, clock)
+
+			require.Eventually(t, func() bool {
+				return !app.ConfirmationRegistrationPending(spendTxHash)
+			}, time.Second, time.Millisecond)
+
+			_, registered = deps.notifier.confHeightHint(spendTxHash)
+			require.True(t, registered)
+		})
+	}
+}
@@ -0,0 +1,203 @@
+package staker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/babylonchain/btc-staker/walletcontroller"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/sirupsen/logrus"
+)
+
+// ConsolidationResult describes transaction consolidating wallet utxos
+type ConsolidationResult struct {
+	TxHash    chainhash.Hash
+	NumInputs int
+	Amount    btcutil.Amount
+	Fee       btcutil.Amount
+}
+
+// stakingTxLimits are limits of staking transactions funded by wallet
+func (app *StakerApp) stakingTxLimits() walletcontroller.TxLimits {
+	return walletcontroller.TxLimits{
+		MaxInputs: app.config.StakerConfig.MaxStakingTxInputs,
+		MaxVsize:  app.config.StakerConfig.MaxStakingTxVsize,
+	}
+}
+
+// stakingOutputScriptPlaceholder has size of taproot staking output script, coin
+// selection only depends on size of the script
+var stakingOutputScriptPlaceholder = append([]byte{txscript.OP_1, txscript.OP_DATA_32}, make([]byte, 32)...)
+
+// estimateStakeFunding runs wallet coin selection of staking transaction of given
+// amount without signing, so it does not unlock the wallet. Returns error if
+// wallet cannot fund the transaction within staking transaction limits.
+func (app *StakerApp) estimateStakeFunding(
+	stakingAmount btcutil.Amount,
+	feeRate btcutil.Amount,
+	changeAddress btcutil.Address,
+) (*walletcontroller.FundingEstimate, error) {
+	estimate, err := app.wc.FundTxEstimate(
+		[]*wire.TxOut{wire.NewTxOut(int64(stakingAmount), stakingOutputScriptPlaceholder)},
+		feeRate,
+		changeAddress,
+	)
+
+	if err != nil {
+		return nil, fmt.Errorf("wallet cannot fund staking transaction: %w", err)
+	}
+
+	if err := estimate.CheckLimits(app.stakingTxLimits()); err != nil {
+		return nil, err
+	}
+
+	return estimate, nil
+}
+
+// StakingFundingEstimate describes staking transaction wallet would build for
+// the stake at current fee rate
+type StakingFundingEstimate struct {
+	FeeRate btcutil.Amount
+	*walletcontroller.FundingEstimate
+}
+
+// EstimateStakeFunding previews funding of staking transaction of given amount,
+// with staker address receiving the change. Signed staking transaction spends
+// the same inputs and pays the same fee, if wallet utxos and fee rate do not
+// change in the meantime.
+func (app *StakerApp) EstimateStakeFunding(
+	stakerAddress btcutil.Address,
+	stakingAmount btcutil.Amount,
+	confTarget *uint32,
+) (*StakingFundingEstimate, error) {
+	if err := app.checkWalletEnabled(); err != nil {
+		return nil, err
+	}
+
+	feeRate := app.estimateFee(
+		confTargetOrDefault(confTarget, app.config.StakerConfig.StakingTxConfTarget),
+	).FeeRate()
+
+	estimate, err := app.estimateStakeFunding(stakingAmount, feeRate, stakerAddress)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &StakingFundingEstimate{
+		FeeRate:         feeRate,
+		FundingEstimate: estimate,
+	}, nil
+}
+
+// ConsolidateUtxos spends the smallest spendable wallet utxos into single output,
+// so that at most targetCount utxos remain in the wallet. It helps to fund stakes
+// which were rejected due to staking transaction limits. If feeRate is nil, fee rate
+// is estimated for staking transaction confirmation target.
+// Consolidation transaction is not tracked by staker, it is only logged and
+// labeled in the wallet.
+func (app *StakerApp) ConsolidateUtxos(targetCount int, feeRate *btcutil.Amount) (*ConsolidationResult, error) {
+	// check we are not shutting down
+	select {
+	case <-app.quit:
+		return nil, nil
+
+	default:
+	}
+
+	if err := app.checkWalletEnabled(); err != nil {
+		return nil, err
+	}
+
+	if err := app.checkBroadcastsEnabled(); err != nil {
+		return nil, err
+	}
+
+	if err := app.checkWalletSynced(); err != nil {
+		return nil, err
+	}
+
+	utxos, err := app.wc.ListOutputs(true)
+
+	if err != nil {
+		return nil, fmt.Errorf("cannot list wallet utxos: %w", err)
+	}
+
+	var rate btcutil.Amount
+	if feeRate != nil {
+		rate = *feeRate
+	} else {
+		rate = app.estimateFee(app.config.StakerConfig.StakingTxConfTarget).FeeRate()
+	}
+
+	tx, err := walletcontroller.BuildConsolidationTx(utxos, targetCount, rate)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var inputsAmount btcutil.Amount
+	for _, utxo := range utxos {
+		for _, in := range tx.TxIn {
+			if in.PreviousOutPoint == utxo.OutPoint {
+				inputsAmount += utxo.Amount
+			}
+		}
+	}
+
+	ctx, cancel := app.appQuitContext()
+	defer cancel()
+
+	unlockCtx, cancelUnlock := context.WithTimeout(ctx, app.config.WalletRpcConfig.WalletRpcTimeout)
+	err = app.wc.UnlockWallet(unlockCtx, int64(app.config.WalletConfig.WalletUnlockTimeout.Seconds()))
+	cancelUnlock()
+
+	if err != nil {
+		return nil, err
+	}
+
+	signCtx, cancelSign := context.WithTimeout(ctx, app.config.WalletRpcConfig.WalletRpcTimeout)
+	signedTx, signed, err := app.wc.SignRawTransaction(signCtx, tx)
+	cancelSign()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !signed {
+		return nil, errors.New("not all inputs of consolidation transaction could be signed")
+	}
+
+	txHash, err := app.wc.SendRawTransaction(signedTx, false)
+
+	if err != nil {
+		return nil, fmt.Errorf("cannot send consolidation transaction: %w", err)
+	}
+
+	result := &ConsolidationResult{
+		TxHash:    *txHash,
+		NumInputs: len(signedTx.TxIn),
+		Amount:    btcutil.Amount(signedTx.TxOut[0].Value),
+		Fee:       inputsAmount - btcutil.Amount(signedTx.TxOut[0].Value),
+	}
+
+	app.logger.WithFields(logrus.Fields{
+		"txHash":    result.TxHash,
+		"numInputs": result.NumInputs,
+		"amount":    result.Amount,
+		"fee":       result.Fee,
+		"feeRate":   rate,
+	}).Info("Sent transaction consolidating wallet utxos")
+
+	label := app.txLabel(consolidationTxLabelPurpose, nil)
+	app.attachWalletTxLabel(app.logger.WithFields(logrus.Fields{
+		"txHash": txHash,
+		"label":  label,
+	}), txHash, label)
+
+	return result, nil
+}
@@ -0,0 +1,35 @@
+package staker
+
+import (
+	"github.com/babylonchain/btc-staker/stakerdb"
+	"github.com/sirupsen/logrus"
+)
+
+// CheckDbIntegrity returns tracked transactions whose stored data is inconsistent
+// with their state
+func (app *StakerApp) CheckDbIntegrity() ([]stakerdb.IntegrityIssue, error) {
+	return app.txTracker.CheckIntegrity()
+}
+
+// logDbIntegrityIssues reports inconsistent transactions found in db on startup.
+// They are not fixed automatically, as only operator can decide which of the
+// stored data is wrong.
+func (app *StakerApp) logDbIntegrityIssues() {
+	issues, err := app.CheckDbIntegrity()
+
+	if err != nil {
+		app.logger.WithFields(logrus.Fields{
+			"err": err,
+		}).Error("Failed to check integrity of staker db")
+		return
+	}
+
+	for _, issue := range issues {
+		app.logger.WithFields(logrus.Fields{
+			"stakingTxHash": issue.StakingTxHash,
+			"state":         issue.State,
+			"corrupted":     issue.Corrupted,
+			"issue":         issue.Description,
+		}).Warn("Stored transaction is inconsistent with its state")
+	}
+}
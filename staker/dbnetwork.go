@@ -0,0 +1,33 @@
+package staker
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	scfg "github.com/babylonchain/btc-staker/stakercfg"
+	"github.com/babylonchain/btc-staker/stakerdb"
+)
+
+// checkDbNetwork fails startup if db holds data of other btc network than the
+// configured one, as addresses stored in it would be misinterpreted
+func checkDbNetwork(config *scfg.Config, tracker *stakerdb.TrackedTransactionStore) error {
+	err := tracker.CheckNetwork(config.ChainConfig.Network, &config.ActiveNetParams)
+
+	if err == nil {
+		return nil
+	}
+
+	if !errors.Is(err, stakerdb.ErrNetworkMismatch) {
+		return NewStartupError(DatabaseCorrupt, err)
+	}
+
+	// point operator to the directory in which the db is expected to be
+	// stored, if network it belongs to is known
+	if dbNetwork, _ := tracker.Network(); dbNetwork != "" {
+		dataDir := scfg.NetworkDir(filepath.Dir(config.DBConfig.DBPath), dbNetwork)
+		err = fmt.Errorf("%w. Database of %s network belongs to %s", err, dbNetwork, dataDir)
+	}
+
+	return NewStartupError(ConfigInvalid, err)
+}
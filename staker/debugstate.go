@@ -0,0 +1,103 @@
+package staker
+
+import (
+	"sort"
+	"time"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+)
+
+// category of in-flight wallet rescans, other categories are operations of
+// retry loops
+const walletRescanCategory = "wallet_rescan"
+
+type ChannelState struct {
+	Name string
+	Len  int
+	Cap  int
+}
+
+// OldestOperation is the longest running in-flight operation of its category
+type OldestOperation struct {
+	Category      string
+	StakingTxHash chainhash.Hash
+	StartedAt     time.Time
+}
+
+// DebugState is snapshot of internal state of staker, used to diagnose stalls
+type DebugState struct {
+	Channels []ChannelState
+	// number of running background goroutines by purpose
+	Goroutines       map[string]int
+	OldestOperations []OldestOperation
+	BestBlockHeight  uint32
+	// zero if no block epoch event was received yet
+	LastBlockEventAt time.Time
+}
+
+// channelStates returns length and capacity of internal channels of staker
+func (app *StakerApp) channelStates() []ChannelState {
+	return []ChannelState{
+		{"stakingRequested", len(app.stakingRequestedEvChan), cap(app.stakingRequestedEvChan)},
+		{"stakingTxBtcConfirmed", len(app.stakingTxBtcConfirmedEvChan), cap(app.stakingTxBtcConfirmedEvChan)},
+		{"delegationSubmittedToBabylon", len(app.delegationSubmittedToBabylonEvChan), cap(app.delegationSubmittedToBabylonEvChan)},
+		{"unbondingTxSignaturesConfirmedOnBabylon", len(app.unbondingTxSignaturesConfirmedOnBabylonEvChan), cap(app.unbondingTxSignaturesConfirmedOnBabylonEvChan)},
+		{"unbondingTxConfirmedOnBtc", len(app.unbondingTxConfirmedOnBtcEvChan), cap(app.unbondingTxConfirmedOnBtcEvChan)},
+		{"spendStakeTxConfirmedOnBtc", len(app.spendStakeTxConfirmedOnBtcEvChan), cap(app.spendStakeTxConfirmedOnBtcEvChan)},
+		{"delegationExpiredOnBabylon", len(app.delegationExpiredOnBabylonEvChan), cap(app.delegationExpiredOnBabylonEvChan)},
+		{"unbondingSignaturesTimeout", len(app.unbondingSignaturesTimeoutEvChan), cap(app.unbondingSignaturesTimeoutEvChan)},
+		{"stakingTxInputConflict", len(app.stakingTxInputConflictEvChan), cap(app.stakingTxInputConflictEvChan)},
+		{"criticalError", len(app.criticalErrorEvChan), cap(app.criticalErrorEvChan)},
+		{"autoSweepNewBlock", len(app.autoSweepNewBlock), cap(app.autoSweepNewBlock)},
+		{"autoRenewNewBlock", len(app.autoRenewNewBlock), cap(app.autoRenewNewBlock)},
+	}
+}
+
+// oldestOperations returns the oldest in-flight operation of every category,
+// ordered by category
+func oldestOperations(loops []RetryLoop, rescans []WalletRescan) []OldestOperation {
+	oldest := make(map[string]OldestOperation)
+
+	add := func(op OldestOperation) {
+		if current, ok := oldest[op.Category]; !ok || op.StartedAt.Before(current.StartedAt) {
+			oldest[op.Category] = op
+		}
+	}
+
+	for _, loop := range loops {
+		add(OldestOperation{Category: loop.Operation, StakingTxHash: loop.StakingTxHash, StartedAt: loop.StartedAt})
+	}
+
+	for _, rescan := range rescans {
+		add(OldestOperation{Category: walletRescanCategory, StakingTxHash: rescan.TxHash, StartedAt: rescan.StartedAt})
+	}
+
+	ops := make([]OldestOperation, 0, len(oldest))
+
+	for _, op := range oldest {
+		ops = append(ops, op)
+	}
+
+	sort.Slice(ops, func(i, j int) bool {
+		return ops[i].Category < ops[j].Category
+	})
+
+	return ops
+}
+
+// DebugState returns snapshot of internal state of staker. It never blocks on
+// the main event loop, so it can be used while the loop is stalled.
+func (app *StakerApp) DebugState() *DebugState {
+	state := &DebugState{
+		Channels:         app.channelStates(),
+		Goroutines:       app.wg.counts(),
+		OldestOperations: oldestOperations(app.inFlight.list(), app.rescans.list()),
+		BestBlockHeight:  app.currentBestBlockHeight.Load(),
+	}
+
+	if lastBlockEventAt := app.lastBlockEventAt.Load(); lastBlockEventAt != 0 {
+		state.LastBlockEventAt = time.Unix(0, lastBlockEventAt)
+	}
+
+	return state
+}
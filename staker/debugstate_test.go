@@ -0,0 +1,49 @@
+package staker
+
+import (
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOldestOperations(t *testing.T) {
+	firstTxHash := chainhash.HashH([]byte("first staking tx"))
+	secondTxHash := chainhash.HashH([]byte("second staking tx"))
+
+	loops := []RetryLoop{
+		{Operation: sendDelegationOperation, StakingTxHash: firstTxHash, StartedAt: testClockStart.Add(time.Minute)},
+		{Operation: sendDelegationOperation, StakingTxHash: secondTxHash, StartedAt: testClockStart},
+		{Operation: sendUnbondingTxOperation, StakingTxHash: firstTxHash, StartedAt: testClockStart.Add(time.Hour)},
+	}
+	rescans := []WalletRescan{
+		{TxHash: secondTxHash, StartedAt: testClockStart.Add(2 * time.Hour)},
+	}
+
+	ops := oldestOperations(loops, rescans)
+	require.Equal(t, []OldestOperation{
+		{Category: sendDelegationOperation, StakingTxHash: secondTxHash, StartedAt: testClockStart},
+		{Category: sendUnbondingTxOperation, StakingTxHash: firstTxHash, StartedAt: testClockStart.Add(time.Hour)},
+		{Category: walletRescanCategory, StakingTxHash: secondTxHash, StartedAt: testClockStart.Add(2 * time.Hour)},
+	}, ops)
+
+	require.Empty(t, oldestOperations(nil, nil))
+}
+
+func TestGoroutineGroupCountsByPurpose(t *testing.T) {
+	var group goroutineGroup
+	release := make(chan struct{})
+
+	for i := 0; i < 2; i++ {
+		group.Go(blockHandlerGoroutine, func() { <-release })
+	}
+	group.Go(autoSweepGoroutine, func() { <-release })
+
+	require.Equal(t, map[string]int{blockHandlerGoroutine: 2, autoSweepGoroutine: 1}, group.counts())
+
+	close(release)
+	group.Wait()
+
+	require.Empty(t, group.counts())
+}
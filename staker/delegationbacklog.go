@@ -0,0 +1,108 @@
+package staker
+
+import (
+	"sync"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+)
+
+// delegationBacklog bounds number of delegations being sent to babylon at the
+// same time. Delegations over the limit wait in backlog, which only keeps staking
+// transaction hashes, as all the data necessary to build delegation can be
+// retrieved again from btc chain. Backlogged transactions are already in
+// CONFIRMED_ON_BTC state in database, so backlog is also restored after restart.
+type delegationBacklog struct {
+	mu          sync.Mutex
+	maxInFlight int
+	inFlight    int
+	backlog     []chainhash.Hash
+	// wakes up backlog drainer when slot is freed or new transaction is backlogged
+	wake chan struct{}
+}
+
+func newDelegationBacklog(maxInFlight int) *delegationBacklog {
+	return &delegationBacklog{
+		maxInFlight: maxInFlight,
+		wake:        make(chan struct{}, 1),
+	}
+}
+
+func (b *delegationBacklog) notify() {
+	select {
+	case b.wake <- struct{}{}:
+	default:
+	}
+}
+
+// tryAcquire reserves slot for sending new delegation. It fails if all slots are
+// taken or there are older delegations waiting in backlog.
+func (b *delegationBacklog) tryAcquire() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.inFlight >= b.maxInFlight || len(b.backlog) > 0 {
+		return false
+	}
+
+	b.inFlight++
+
+	return true
+}
+
+// release frees slot after delegation sending finished
+func (b *delegationBacklog) release() {
+	b.mu.Lock()
+	b.inFlight--
+	b.mu.Unlock()
+
+	b.notify()
+}
+
+func (b *delegationBacklog) push(stakingTxHash chainhash.Hash) {
+	b.mu.Lock()
+	b.backlog = append(b.backlog, stakingTxHash)
+	b.mu.Unlock()
+
+	b.notify()
+}
+
+// remove drops delegation from backlog. It returns false if delegation was not
+// backlogged.
+func (b *delegationBacklog) remove(stakingTxHash chainhash.Hash) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for i, txHash := range b.backlog {
+		if txHash == stakingTxHash {
+			b.backlog = append(b.backlog[:i], b.backlog[i+1:]...)
+			return true
+		}
+	}
+
+	return false
+}
+
+// next reserves slot for the oldest backlogged delegation. It returns false if
+// backlog is empty or all slots are taken.
+func (b *delegationBacklog) next() (chainhash.Hash, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if len(b.backlog) == 0 || b.inFlight >= b.maxInFlight {
+		return chainhash.Hash{}, false
+	}
+
+	stakingTxHash := b.backlog[0]
+	b.backlog = b.backlog[1:]
+	b.inFlight++
+
+	return stakingTxHash, true
+}
+
+// stats returns number of delegations being sent and number of backlogged delegations
+func (b *delegationBacklog) stats() (int, int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.inFlight, len(b.backlog)
+}
@@ -0,0 +1,95 @@
+package staker
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/babylonchain/btc-staker/proto"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMainLoopNotBlockedByDelegationBacklog(t *testing.T) {
+	const (
+		maxInFlight    = 2
+		numDelegations = 6
+	)
+
+	deps := newTestStakerDeps(t)
+	deps.config.StakerConfig.MaxInFlightDelegations = maxInFlight
+	// slow babylon node, which does not respond until unblocked
+	deps.babylon.stallDelegations = make(chan struct{})
+	unblockBabylon := sync.OnceFunc(func() { close(deps.babylon.stallDelegations) })
+
+	var stakingTxHashes []chainhash.Hash
+	for i := 0; i < numDelegations; i++ {
+		stakingTxHashes = append(stakingTxHashes, *deps.addWatchedTransaction(t))
+	}
+
+	app := deps.newApp(t)
+	require.NoError(t, app.Start())
+	t.Cleanup(func() {
+		_ = app.Stop()
+	})
+	// cleanups run in reverse order, so babylon is unblocked before app is stopped
+	t.Cleanup(unblockBabylon)
+
+	requireState := func(state proto.TransactionState) {
+		require.Eventually(t, func() bool {
+			for _, txHash := range stakingTxHashes {
+				storedTx, err := deps.tracker.GetTransaction(&txHash)
+				require.NoError(t, err)
+
+				if storedTx.State != state {
+					return false
+				}
+			}
+			return true
+		}, 5*time.Second, time.Millisecond)
+	}
+
+	// every confirmation is processed by main loop, even though delegations
+	// sent before are stuck on babylon
+	for _, txHash := range stakingTxHashes {
+		storedTx, err := deps.tracker.GetTransaction(&txHash)
+		require.NoError(t, err)
+
+		block := testInclusionBlock(t, storedTx.StakingTx)
+		ev := &stakingTxBtcConfirmedEvent{
+			stakingTxHash: txHash,
+			txIndex:       1,
+			blockDepth:    2,
+			blockHash:     block.BlockHash(),
+			blockHeight:   10,
+			tx:            storedTx.StakingTx,
+			inlusionBlock: block,
+		}
+
+		select {
+		case app.stakingTxBtcConfirmedEvChan <- ev:
+		case <-time.After(time.Second):
+			t.Fatalf("main loop blocked by delegation backlog")
+		}
+	}
+
+	requireState(proto.TransactionState_CONFIRMED_ON_BTC)
+
+	require.Eventually(t, func() bool {
+		inFlight, backlogged := app.delegationBacklog.stats()
+		return inFlight == maxInFlight && backlogged == numDelegations-maxInFlight
+	}, time.Second, time.Millisecond)
+	require.Empty(t, deps.babylon.delegatedTxs())
+
+	unblockBabylon()
+
+	requireState(proto.TransactionState_SENT_TO_BABYLON)
+
+	require.Eventually(t, func() bool {
+		inFlight, backlogged := app.delegationBacklog.stats()
+		return inFlight == 0 && backlogged == 0
+	}, time.Second, time.Millisecond)
+
+	// every delegation is sent exactly once
+	require.ElementsMatch(t, stakingTxHashes, deps.babylon.delegatedTxs())
+}
@@ -0,0 +1,161 @@
+package staker
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	cl "github.com/babylonchain/btc-staker/babylonclient"
+	"github.com/babylonchain/btc-staker/proto"
+	"github.com/babylonchain/btc-staker/stakerdb"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	// ErrDelegationCancelled delegation was cancelled by operator, so it is not
+	// sent to babylon
+	ErrDelegationCancelled = errors.New("delegation was cancelled")
+	// ErrDelegationSubmitted delegation was already sent to babylon, so it cannot
+	// be cancelled
+	ErrDelegationSubmitted = errors.New("delegation was already submitted to babylon")
+	// ErrDelegationSubmissionInFlight delegation is being sent to babylon at the
+	// moment, so it cannot be cancelled
+	ErrDelegationSubmissionInFlight = errors.New("delegation is being submitted to babylon")
+)
+
+// delegationSubmissions tracks delegations which are being sent to babylon, so
+// that delegation is never cancelled while babylon transaction carrying it can
+// still be included. Delegation is tracked from the moment it is handed to
+// babylon message sender until it is either rejected or recorded as sent to
+// babylon.
+type delegationSubmissions struct {
+	mu       sync.Mutex
+	inFlight map[chainhash.Hash]struct{}
+}
+
+func newDelegationSubmissions() *delegationSubmissions {
+	return &delegationSubmissions{
+		inFlight: make(map[chainhash.Hash]struct{}),
+	}
+}
+
+// begin starts tracking submission of delegation, if check passes. Check is run
+// under the same lock as cancellation, so delegation cannot be cancelled between
+// the check and the submission.
+func (s *delegationSubmissions) begin(stakingTxHash chainhash.Hash, check func() error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := check(); err != nil {
+		return err
+	}
+
+	s.inFlight[stakingTxHash] = struct{}{}
+
+	return nil
+}
+
+// finish stops tracking submission of delegation
+func (s *delegationSubmissions) finish(stakingTxHash chainhash.Hash) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.inFlight, stakingTxHash)
+}
+
+// cancel runs cancellation of delegation, unless delegation is being submitted
+func (s *delegationSubmissions) cancel(stakingTxHash chainhash.Hash, cancel func() error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.inFlight[stakingTxHash]; ok {
+		return fmt.Errorf("%w: %s", ErrDelegationSubmissionInFlight, stakingTxHash)
+	}
+
+	return cancel()
+}
+
+// sentToBabylon returns true if delegation of transaction in given state was
+// sent to babylon
+func sentToBabylon(state proto.TransactionState) bool {
+	switch state {
+	case proto.TransactionState_SENT_TO_BABYLON,
+		proto.TransactionState_UNBONDING_SIGNATURES_TIMEOUT,
+		proto.TransactionState_DELEGATION_ACTIVE,
+		proto.TransactionState_UNBONDING_CONFIRMED_ON_BTC,
+		proto.TransactionState_DELEGATION_EXPIRED:
+		return true
+	default:
+		return false
+	}
+}
+
+// checkDelegationNotCancelled returns ErrDelegationCancelled if delegation of
+// staking transaction was cancelled
+func (app *StakerApp) checkDelegationNotCancelled(stakingTxHash *chainhash.Hash) error {
+	tx, err := app.txTracker.GetTransaction(stakingTxHash)
+
+	if err != nil {
+		return err
+	}
+
+	if tx.State == proto.TransactionState_DELEGATION_CANCELLED {
+		return fmt.Errorf("%w: %s", ErrDelegationCancelled, stakingTxHash)
+	}
+
+	return nil
+}
+
+// CancelPendingDelegation cancels delegation of staking transaction, which is
+// confirmed on btc but was not sent to babylon yet e.g because babylon is
+// unreachable or sending it failed. Delegation is removed from backlog and moved
+// to DELEGATION_CANCELLED state. As there is no unbonding path without babylon,
+// staked funds can only be withdrawn once staking timelock expires. Cancellation
+// is refused if delegation is being sent to babylon or was already sent. As
+// delegation could have been sent before restart, babylon is asked as well, and
+// cancellation is refused unless babylon confirms it does not know delegation.
+func (app *StakerApp) CancelPendingDelegation(stakingTxHash *chainhash.Hash) error {
+	tx, err := app.txTracker.GetTransaction(stakingTxHash)
+
+	if err != nil {
+		return err
+	}
+
+	// staked funds of watched transaction could not be withdrawn by staker
+	if err := checkSignable(tx); err != nil {
+		return fmt.Errorf("cannot cancel delegation: %w", err)
+	}
+
+	err = app.delegationSubmissions.cancel(*stakingTxHash, func() error {
+		_, err := app.babylonClient.QueryDelegationInfo(stakingTxHash)
+
+		if err == nil {
+			return fmt.Errorf("%w: delegation found on babylon", ErrDelegationSubmitted)
+		}
+
+		if !errors.Is(err, cl.ErrDelegationNotFound) {
+			return fmt.Errorf("%w: could not check delegation on babylon: %v", ErrDelegationSubmitted, err)
+		}
+
+		return app.txTracker.SetTxDelegationCancelled(stakingTxHash)
+	})
+
+	var transitionErr *stakerdb.ErrInvalidStateTransition
+
+	if errors.As(err, &transitionErr) && sentToBabylon(transitionErr.From) {
+		return fmt.Errorf("%w: transaction is in state %s", ErrDelegationSubmitted, transitionErr.From)
+	}
+
+	if err != nil {
+		return fmt.Errorf("cannot cancel delegation: %w", err)
+	}
+
+	app.delegationBacklog.remove(*stakingTxHash)
+
+	app.logger.WithFields(logrus.Fields{
+		"stakingTxHash": stakingTxHash,
+	}).Warn("Delegation cancelled before it was sent to babylon. Staked funds can be withdrawn once staking timelock expires")
+
+	return nil
+}
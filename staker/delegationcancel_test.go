@@ -0,0 +1,99 @@
+package staker
+
+import (
+	"errors"
+	"testing"
+
+	cl "github.com/babylonchain/btc-staker/babylonclient"
+	"github.com/babylonchain/btc-staker/proto"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDelegationSubmissionsRefuseCancelInFlight(t *testing.T) {
+	submissions := newDelegationSubmissions()
+	txHash := chainhash.Hash{1}
+
+	cancelled := false
+	cancel := func() error {
+		cancelled = true
+		return nil
+	}
+
+	require.NoError(t, submissions.begin(txHash, func() error { return nil }))
+	require.ErrorIs(t, submissions.cancel(txHash, cancel), ErrDelegationSubmissionInFlight)
+	require.False(t, cancelled)
+
+	// other delegations can be cancelled
+	require.NoError(t, submissions.cancel(chainhash.Hash{2}, cancel))
+	require.True(t, cancelled)
+
+	// delegation rejected by babylon can be cancelled
+	submissions.finish(txHash)
+	cancelled = false
+	require.NoError(t, submissions.cancel(txHash, cancel))
+	require.True(t, cancelled)
+}
+
+func TestDelegationSubmissionsCheckedBeforeBegin(t *testing.T) {
+	submissions := newDelegationSubmissions()
+	txHash := chainhash.Hash{1}
+
+	errCancelled := errors.New("cancelled")
+	require.ErrorIs(t, submissions.begin(txHash, func() error { return errCancelled }), errCancelled)
+
+	// failed check does not block cancellation
+	require.NoError(t, submissions.cancel(txHash, func() error { return nil }))
+}
+
+func TestDelegationBacklogRemove(t *testing.T) {
+	backlog := newDelegationBacklog(0)
+
+	backlog.push(chainhash.Hash{1})
+	backlog.push(chainhash.Hash{2})
+	backlog.push(chainhash.Hash{3})
+
+	require.True(t, backlog.remove(chainhash.Hash{2}))
+	require.False(t, backlog.remove(chainhash.Hash{2}))
+	require.Equal(t, []chainhash.Hash{{1}, {3}}, backlog.backlog)
+}
+
+func TestSentToBabylon(t *testing.T) {
+	require.False(t, sentToBabylon(proto.TransactionState_SENT_TO_BTC))
+	require.False(t, sentToBabylon(proto.TransactionState_CONFIRMED_ON_BTC))
+	require.False(t, sentToBabylon(proto.TransactionState_DELEGATION_CANCELLED))
+	require.True(t, sentToBabylon(proto.TransactionState_SENT_TO_BABYLON))
+	require.True(t, sentToBabylon(proto.TransactionState_DELEGATION_ACTIVE))
+	require.True(t, sentToBabylon(proto.TransactionState_UNBONDING_SIGNATURES_TIMEOUT))
+}
+
+func TestCancelPendingDelegation(t *testing.T) {
+	deps := newTestStakerDeps(t)
+	txHash := deps.addConfirmedTransaction(t)
+	app := deps.newApp(t)
+
+	app.delegationBacklog.push(*txHash)
+
+	require.NoError(t, app.CancelPendingDelegation(txHash))
+
+	tx, err := deps.tracker.GetTransaction(txHash)
+	require.NoError(t, err)
+	require.Equal(t, proto.TransactionState_DELEGATION_CANCELLED, tx.State)
+	require.Empty(t, app.delegationBacklog.backlog)
+}
+
+func TestCancelPendingDelegationRefusedIfKnownToBabylon(t *testing.T) {
+	deps := newTestStakerDeps(t)
+	txHash := deps.addConfirmedTransaction(t)
+	// delegation was sent before restart, so it is not tracked as in flight
+	deps.babylon.delegations = map[chainhash.Hash]*cl.DelegationInfo{
+		*txHash: {Status: cl.DelegationStatusPending},
+	}
+	app := deps.newApp(t)
+
+	require.ErrorIs(t, app.CancelPendingDelegation(txHash), ErrDelegationSubmitted)
+
+	tx, err := deps.tracker.GetTransaction(txHash)
+	require.NoError(t, err)
+	require.Equal(t, proto.TransactionState_CONFIRMED_ON_BTC, tx.State)
+}
@@ -0,0 +1,58 @@
+package staker
+
+import (
+	"testing"
+	"time"
+
+	cl "github.com/babylonchain/btc-staker/babylonclient"
+	"github.com/babylonchain/btc-staker/proto"
+	"github.com/babylonchain/btc-staker/utils"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDelegationExpiredBeforeActivationIsWithdrawable(t *testing.T) {
+	deps := newTestStakerDeps(t)
+	deps.babylon.params = &cl.StakingParams{
+		ConfirmationTimeBlocks:    2,
+		FinalizationTimeoutBlocks: 5,
+		CovenantQuruomThreshold:   1,
+	}
+
+	stakingTxHash := deps.addSentToBabylonTransaction(t)
+	// covenants did not sign delegation before its timelock passed
+	deps.babylon.delegations = map[chainhash.Hash]*cl.DelegationInfo{
+		*stakingTxHash: {Status: cl.DelegationStatusUnbonded},
+	}
+
+	app := deps.newApp(t)
+	clock := app.clock.(*utils.FakeClock)
+	require.NoError(t, app.Start())
+	t.Cleanup(func() {
+		_ = app.Stop()
+	})
+
+	// delegation is polled for unbonding signatures after restart
+	checkInterval := deps.config.StakerConfig.UnbondingTxCheckInterval
+	require.Eventually(t, func() bool {
+		clock.Advance(checkInterval)
+
+		storedTx, err := deps.tracker.GetTransaction(stakingTxHash)
+		require.NoError(t, err)
+		return storedTx.State == proto.TransactionState_DELEGATION_EXPIRED
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// staking transaction confirmed at height 10 with staking time of 100 blocks,
+	// and best block is 100
+	withdrawable, err := app.WithdrawableTransactions(10, 0)
+	require.NoError(t, err)
+	require.Empty(t, withdrawable.Transactions)
+
+	app.currentBestBlockHeight.Store(200)
+
+	withdrawable, err = app.WithdrawableTransactions(10, 0)
+	require.NoError(t, err)
+	require.Len(t, withdrawable.Transactions, 1)
+	require.Equal(t, *stakingTxHash, withdrawable.Transactions[0].StakingTx.TxHash())
+	require.Equal(t, proto.TransactionState_DELEGATION_EXPIRED, withdrawable.Transactions[0].State)
+}
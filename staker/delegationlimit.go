@@ -0,0 +1,34 @@
+package staker
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrMaxActiveDelegationsReached is returned when new stake would exceed maximum
+// number of active delegations tracked by staker
+var ErrMaxActiveDelegationsReached = errors.New("maximum number of active delegations reached")
+
+// ActiveDelegations returns number of tracked delegations which staking
+// transactions were not spent yet
+func (app *StakerApp) ActiveDelegations() (uint64, error) {
+	return app.txTracker.CountActiveTransactions()
+}
+
+// checkActiveDelegationsLimit returns ErrMaxActiveDelegationsReached if staker
+// cannot track any more delegations
+func (app *StakerApp) checkActiveDelegationsLimit() error {
+	active, err := app.ActiveDelegations()
+
+	if err != nil {
+		return fmt.Errorf("failed to count active delegations: %w", err)
+	}
+
+	maxActive := uint64(app.config.StakerConfig.MaxActiveDelegations)
+
+	if active >= maxActive {
+		return fmt.Errorf("%w: %d active delegations, limit is %d", ErrMaxActiveDelegationsReached, active, maxActive)
+	}
+
+	return nil
+}
@@ -0,0 +1,117 @@
+package staker
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/babylonchain/btc-staker/proto"
+	"github.com/babylonchain/btc-staker/stakerdb"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/sirupsen/logrus"
+)
+
+// recordEventIntent stores event before it is pushed to the main event loop, so
+// that event lost when daemon stops before processing it is replayed on next
+// start. If intent cannot be stored, event is still delivered and startup scan
+// remains the only way to recover it.
+func (app *StakerApp) recordEventIntent(intent *stakerdb.EventIntent) {
+	if err := app.txTracker.SaveEventIntent(intent); err != nil {
+		app.logger.WithFields(logrus.Fields{
+			"stakingTxHash": intent.StakingTxHash,
+			"event":         intent.Type,
+			"err":           err,
+		}).Error("Failed to record event intent")
+	}
+}
+
+// completeEventIntent removes intent of the event once the main event loop is
+// done with it
+func (app *StakerApp) completeEventIntent(stakingTxHash *chainhash.Hash, eventType proto.EventIntentType) {
+	if err := app.txTracker.CompleteEventIntent(stakingTxHash, eventType); err != nil {
+		// intent is replayed on next start, which is a no-op as transition is
+		// already committed
+		app.logger.WithFields(logrus.Fields{
+			"stakingTxHash": stakingTxHash,
+			"event":         eventType,
+			"err":           err,
+		}).Error("Failed to complete event intent")
+	}
+}
+
+// replayEventIntents applies state transitions of events which were produced
+// but not processed before daemon stopped. It must be run before startup scan
+// of tracked transactions, and before the main event loop is started.
+func (app *StakerApp) replayEventIntents() error {
+	intents, err := app.txTracker.GetEventIntents()
+
+	if err != nil {
+		return fmt.Errorf("failed to load event intents: %w", err)
+	}
+
+	for _, intent := range intents {
+		if err := app.replayEventIntent(intent); err != nil {
+			return err
+		}
+
+		if err := app.txTracker.CompleteEventIntent(&intent.StakingTxHash, intent.Type); err != nil {
+			return fmt.Errorf("failed to complete replayed event intent: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (app *StakerApp) replayEventIntent(intent *stakerdb.EventIntent) error {
+	logger := app.logger.WithFields(logrus.Fields{
+		"stakingTxHash": intent.StakingTxHash,
+		"event":         intent.Type,
+	})
+
+	if intent.Corrupted {
+		logger.Warn("Event intent is corrupted. State of transaction will be recovered by startup scan")
+		return nil
+	}
+
+	var err error
+
+	switch intent.Type {
+	case proto.EventIntentType_EVENT_INTENT_UNBONDING_SIGNATURES_RECEIVED:
+		err = app.txTracker.SetTxUnbondingSignaturesReceived(&intent.StakingTxHash, intent.CovenantSignatures)
+	case proto.EventIntentType_EVENT_INTENT_UNBONDING_CONFIRMED_ON_BTC:
+		if intent.BtcConfirmation == nil {
+			logger.Warn("Event intent has no confirmation info. State of transaction will be recovered by startup scan")
+			return nil
+		}
+
+		err = app.txTracker.SetTxUnbondingConfirmedOnBtc(
+			&intent.StakingTxHash,
+			&intent.BtcConfirmation.BlockHash,
+			intent.BtcConfirmation.Height,
+			intent.BtcConfirmation.BlockTime,
+		)
+	default:
+		logger.Warn("Unknown event intent type. State of transaction will be recovered by startup scan")
+		return nil
+	}
+
+	switch {
+	case err == nil:
+		logger.Info("Replayed event which was not processed before shutdown")
+		return nil
+	case errors.Is(err, stakerdb.ErrAlreadyInState):
+		// daemon stopped after transition was committed, but before intent was
+		// completed
+		logger.Debug("Transition of event intent already committed")
+		return nil
+	case app.quarantineIfCorrupt(&intent.StakingTxHash, err):
+		return nil
+	case errors.Is(err, stakerdb.ErrTransactionNotFound),
+		errors.Is(err, stakerdb.ErrInvalidTransactionState),
+		errors.Is(err, stakerdb.ErrInvalidUnbondingDataUpdate),
+		errors.Is(err, stakerdb.ErrUnbondingDataNotFound):
+		logger.WithError(err).Warn("Event intent is not valid in current state of transaction. State of transaction will be recovered by startup scan")
+		return nil
+	default:
+		return fmt.Errorf("failed to replay event intent of transaction %s: %w", intent.StakingTxHash, err)
+	}
+}
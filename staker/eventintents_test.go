@@ -0,0 +1,191 @@
+package staker
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/babylonchain/btc-staker/proto"
+	"github.com/babylonchain/btc-staker/stakerdb"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/stretchr/testify/require"
+)
+
+// eventStep is one step of delivering internal event to the main event loop and
+// processing it there
+type eventStep func(app *StakerApp)
+
+// crashingProcessor runs steps of event processing and stops after the given
+// number of them, as if daemon was killed at that point
+type crashingProcessor struct {
+	crashAfter int
+}
+
+func (p *crashingProcessor) run(app *StakerApp, steps []eventStep) {
+	for i, step := range steps {
+		if i == p.crashAfter {
+			return
+		}
+
+		step(app)
+	}
+}
+
+func (d *testStakerDeps) addSentToBabylonTransaction(t testing.TB) *chainhash.Hash {
+	txHash := d.addConfirmedTransaction(t)
+
+	unbondingTx := wire.NewMsgTx(2)
+	unbondingTx.AddTxIn(wire.NewTxIn(wire.NewOutPoint(txHash, 0), nil, nil))
+	unbondingTx.AddTxOut(wire.NewTxOut(90000, []byte{1}))
+
+	require.NoError(t, d.tracker.SetTxSentToBabylon(txHash, unbondingTx, 0, 50, "", ""))
+
+	return txHash
+}
+
+func testCovenantSigs(t *testing.T) []stakerdb.PubKeySigPair {
+	privKey := genPrivKey(t)
+
+	sig, err := schnorr.Sign(privKey, chainhash.HashB([]byte("unbonding")))
+	require.NoError(t, err)
+
+	return []stakerdb.PubKeySigPair{{Signature: sig, PubKey: privKey.PubKey()}}
+}
+
+// unbondingSignaturesSteps mirrors delivery of signatures event by babylon poller
+// and its processing by the main event loop
+func unbondingSignaturesSteps(txHash *chainhash.Hash, sigs []stakerdb.PubKeySigPair) []eventStep {
+	eventType := proto.EventIntentType_EVENT_INTENT_UNBONDING_SIGNATURES_RECEIVED
+
+	return []eventStep{
+		func(app *StakerApp) {
+			app.recordEventIntent(&stakerdb.EventIntent{
+				StakingTxHash:      *txHash,
+				Type:               eventType,
+				CovenantSignatures: sigs,
+			})
+		},
+		func(app *StakerApp) {
+			_ = app.txTracker.SetTxUnbondingSignaturesReceived(txHash, sigs)
+		},
+		func(app *StakerApp) {
+			app.completeEventIntent(txHash, eventType)
+		},
+	}
+}
+
+// unbondingConfirmedSteps mirrors delivery of unbonding confirmation event by
+// btc notifier and its processing by the main event loop
+func unbondingConfirmedSteps(txHash *chainhash.Hash, conf *stakerdb.BtcConfirmationInfo) []eventStep {
+	eventType := proto.EventIntentType_EVENT_INTENT_UNBONDING_CONFIRMED_ON_BTC
+
+	return []eventStep{
+		func(app *StakerApp) {
+			app.recordEventIntent(&stakerdb.EventIntent{
+				StakingTxHash:   *txHash,
+				Type:            eventType,
+				BtcConfirmation: conf,
+			})
+		},
+		func(app *StakerApp) {
+			_ = app.txTracker.SetTxUnbondingConfirmedOnBtc(txHash, &conf.BlockHash, conf.Height, conf.BlockTime)
+		},
+		func(app *StakerApp) {
+			app.completeEventIntent(txHash, eventType)
+		},
+	}
+}
+
+func TestEventIntentsReplayedAfterCrash(t *testing.T) {
+	conf := &stakerdb.BtcConfirmationInfo{
+		Height:    200,
+		BlockHash: chainhash.HashH([]byte("block")),
+		BlockTime: time.Unix(1700000000, 0),
+	}
+
+	tests := []struct {
+		name string
+		// state of transaction before event
+		setup func(t *testing.T, d *testStakerDeps) *chainhash.Hash
+		steps func(t *testing.T, txHash *chainhash.Hash) []eventStep
+		// state of transaction once event is processed
+		finalState proto.TransactionState
+	}{
+		{
+			name:  "unbonding signatures received",
+			setup: func(t *testing.T, d *testStakerDeps) *chainhash.Hash { return d.addSentToBabylonTransaction(t) },
+			steps: func(t *testing.T, txHash *chainhash.Hash) []eventStep {
+				return unbondingSignaturesSteps(txHash, testCovenantSigs(t))
+			},
+			finalState: proto.TransactionState_DELEGATION_ACTIVE,
+		},
+		{
+			name: "unbonding confirmed on btc",
+			setup: func(t *testing.T, d *testStakerDeps) *chainhash.Hash {
+				txHash := d.addSentToBabylonTransaction(t)
+				require.NoError(t, d.tracker.SetTxUnbondingSignaturesReceived(txHash, testCovenantSigs(t)))
+				return txHash
+			},
+			steps: func(t *testing.T, txHash *chainhash.Hash) []eventStep {
+				return unbondingConfirmedSteps(txHash, conf)
+			},
+			finalState: proto.TransactionState_UNBONDING_CONFIRMED_ON_BTC,
+		},
+	}
+
+	for _, tc := range tests {
+		// crashing after all steps is a clean run
+		for crashAfter := 0; crashAfter <= 3; crashAfter++ {
+			t.Run(fmt.Sprintf("%s crash after %d steps", tc.name, crashAfter), func(t *testing.T) {
+				deps := newTestStakerDeps(t)
+				txHash := tc.setup(t, deps)
+
+				initial, err := deps.tracker.GetTransaction(txHash)
+				require.NoError(t, err)
+
+				(&crashingProcessor{crashAfter: crashAfter}).run(deps.newApp(t), tc.steps(t, txHash))
+
+				// restarted daemon replays intents over the same database
+				require.NoError(t, deps.newApp(t).replayEventIntents())
+
+				storedTx, err := deps.tracker.GetTransaction(txHash)
+				require.NoError(t, err)
+
+				if crashAfter == 0 {
+					// event was lost before it was recorded, only startup scan can
+					// recover it
+					require.Equal(t, initial.State, storedTx.State)
+				} else {
+					require.Equal(t, tc.finalState, storedTx.State)
+				}
+
+				if tc.finalState == proto.TransactionState_UNBONDING_CONFIRMED_ON_BTC && crashAfter > 0 {
+					require.Equal(t, conf, storedTx.UnbondingTxData.UnbondingTxConfirmationInfo)
+				}
+
+				intents, err := deps.tracker.GetEventIntents()
+				require.NoError(t, err)
+				require.Empty(t, intents)
+			})
+		}
+	}
+}
+
+func TestEventIntentOfUntrackedTransactionIsDropped(t *testing.T) {
+	deps := newTestStakerDeps(t)
+	txHash := chainhash.HashH([]byte("untracked"))
+
+	require.NoError(t, deps.tracker.SaveEventIntent(&stakerdb.EventIntent{
+		StakingTxHash:      txHash,
+		Type:               proto.EventIntentType_EVENT_INTENT_UNBONDING_SIGNATURES_RECEIVED,
+		CovenantSignatures: testCovenantSigs(t),
+	}))
+
+	require.NoError(t, deps.newApp(t).replayEventIntents())
+
+	intents, err := deps.tracker.GetEventIntents()
+	require.NoError(t, err)
+	require.Empty(t, intents)
+}
@@ -1,13 +1,18 @@
 package staker
 
 import (
+	"time"
+
 	cl "github.com/babylonchain/btc-staker/babylonclient"
+	"github.com/babylonchain/btc-staker/stakerdb"
+	"github.com/babylonchain/btc-staker/walletcontroller"
 	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/btcsuite/btcd/btcec/v2/schnorr"
 	"github.com/btcsuite/btcd/btcutil"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/btcsuite/btcd/wire"
 	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	notifier "github.com/lightningnetwork/lnd/chainntnfs"
 	"github.com/sirupsen/logrus"
 )
 
@@ -23,6 +28,9 @@ var _ StakingEvent = (*delegationSubmittedToBabylonEvent)(nil)
 var _ StakingEvent = (*unbondingTxSignaturesConfirmedOnBabylonEvent)(nil)
 var _ StakingEvent = (*unbondingTxConfirmedOnBtcEvent)(nil)
 var _ StakingEvent = (*spendStakeTxConfirmedOnBtcEvent)(nil)
+var _ StakingEvent = (*delegationExpiredOnBabylonEvent)(nil)
+var _ StakingEvent = (*unbondingSignaturesTimeoutEvent)(nil)
+var _ StakingEvent = (*stakingTxInputConflictEvent)(nil)
 var _ StakingEvent = (*criticalErrorEvent)(nil)
 
 type stakingRequestedEvent struct {
@@ -36,9 +44,17 @@ type stakingRequestedEvent struct {
 	fpBtcPks                []*btcec.PublicKey
 	requiredDepthOnBtcChain uint32
 	pop                     *cl.BabylonPop
+	paramsVersion           uint32
+	changeOutput            *stakerdb.ChangeOutput
+	babylonMemo             string
+	label                   string
+	autoRenew               bool
+	feeEstimate             *stakerdb.FeeEstimate
 	watchTxData             *watchTxData
 	errChan                 chan error
 	successChan             chan *chainhash.Hash
+	// staking transaction renewed by requested one, nil if it is not renewal
+	renewedFromTxHash *chainhash.Hash
 }
 
 func (req *stakingRequestedEvent) isWatched() bool {
@@ -55,6 +71,11 @@ func newOwnedStakingRequest(
 	fpBtcPks []*btcec.PublicKey,
 	confirmationTimeBlocks uint32,
 	pop *cl.BabylonPop,
+	paramsVersion uint32,
+	changeOutput *stakerdb.ChangeOutput,
+	babylonMemo string,
+	label string,
+	autoRenew bool,
 ) *stakingRequestedEvent {
 	return &stakingRequestedEvent{
 		stakerAddress:           stakerAddress,
@@ -67,6 +88,11 @@ func newOwnedStakingRequest(
 		fpBtcPks:                fpBtcPks,
 		requiredDepthOnBtcChain: confirmationTimeBlocks,
 		pop:                     pop,
+		paramsVersion:           paramsVersion,
+		changeOutput:            changeOutput,
+		babylonMemo:             babylonMemo,
+		label:                   label,
+		autoRenew:               autoRenew,
 		watchTxData:             nil,
 		errChan:                 make(chan error, 1),
 		successChan:             make(chan *chainhash.Hash, 1),
@@ -80,9 +106,16 @@ type watchTxData struct {
 	stakerBtcPk         *btcec.PublicKey
 	// unbonding related data
 	unbondingTx         *wire.MsgTx
+	unbondingOutputIdx  uint32
 	slashUnbondingTx    *wire.MsgTx
 	slashUnbondingTxSig *schnorr.Signature
 	unbondingTime       uint16
+	// where staking transaction was found on btc when watch was requested
+	btcTxStatus walletcontroller.TxStatus
+	btcTxInfo   *notifier.TxConfirmation
+	// height from which transaction which was not found is awaited, nil if
+	// not provided
+	inclusionHeightHint *uint32
 }
 
 func newWatchedStakingRequest(
@@ -95,14 +128,17 @@ func newWatchedStakingRequest(
 	fpBtcPks []*btcec.PublicKey,
 	confirmationTimeBlocks uint32,
 	pop *cl.BabylonPop,
+	paramsVersion uint32,
 	slashingTx *wire.MsgTx,
 	slashingTxSignature *schnorr.Signature,
 	stakerBabylonPubKey *secp256k1.PubKey,
 	stakerBtcPk *btcec.PublicKey,
 	unbondingTx *wire.MsgTx,
+	unbondingOutputIdx uint32,
 	slashUnbondingTx *wire.MsgTx,
 	slashUnbondingTxSig *schnorr.Signature,
 	unbondingTime uint16,
+	label string,
 ) *stakingRequestedEvent {
 	return &stakingRequestedEvent{
 		stakerAddress:           stakerAddress,
@@ -115,12 +151,15 @@ func newWatchedStakingRequest(
 		fpBtcPks:                fpBtcPks,
 		requiredDepthOnBtcChain: confirmationTimeBlocks,
 		pop:                     pop,
+		paramsVersion:           paramsVersion,
+		label:                   label,
 		watchTxData: &watchTxData{
 			slashingTx:          slashingTx,
 			slashingTxSig:       slashingTxSignature,
 			stakerBabylonPubKey: stakerBabylonPubKey,
 			stakerBtcPk:         stakerBtcPk,
 			unbondingTx:         unbondingTx,
+			unbondingOutputIdx:  unbondingOutputIdx,
 			slashUnbondingTx:    slashUnbondingTx,
 			slashUnbondingTxSig: slashUnbondingTxSig,
 			unbondingTime:       unbondingTime,
@@ -157,9 +196,14 @@ func (event *stakingTxBtcConfirmedEvent) EventDesc() string {
 }
 
 type delegationSubmittedToBabylonEvent struct {
-	stakingTxHash chainhash.Hash
-	unbondingTx   *wire.MsgTx
-	unbondingTime uint16
+	stakingTxHash      chainhash.Hash
+	unbondingTx        *wire.MsgTx
+	unbondingOutputIdx uint32
+	unbondingTime      uint16
+	// empty if hash of babylon transaction is not known
+	babylonTxHash string
+	// memo of babylon transaction, empty if not known
+	babylonMemo string
 }
 
 func (event *delegationSubmittedToBabylonEvent) EventId() chainhash.Hash {
@@ -187,6 +231,8 @@ type unbondingTxConfirmedOnBtcEvent struct {
 	stakingTxHash chainhash.Hash
 	blockHash     chainhash.Hash
 	blockHeight   uint32
+	// zero if inclusion block is not known
+	blockTime time.Time
 }
 
 func (event *unbondingTxConfirmedOnBtcEvent) EventId() chainhash.Hash {
@@ -198,7 +244,8 @@ func (event *unbondingTxConfirmedOnBtcEvent) EventDesc() string {
 }
 
 type spendStakeTxConfirmedOnBtcEvent struct {
-	stakingTxHash chainhash.Hash
+	stakingTxHash       chainhash.Hash
+	spendTxConfirmation *stakerdb.BtcConfirmationInfo
 }
 
 func (event *spendStakeTxConfirmedOnBtcEvent) EventId() chainhash.Hash {
@@ -209,6 +256,46 @@ func (event *spendStakeTxConfirmedOnBtcEvent) EventDesc() string {
 	return "SPEND_STAKE_TX_CONFIRMED_ON_BTC"
 }
 
+type delegationExpiredOnBabylonEvent struct {
+	stakingTxHash chainhash.Hash
+}
+
+func (event *delegationExpiredOnBabylonEvent) EventId() chainhash.Hash {
+	return event.stakingTxHash
+}
+
+func (event *delegationExpiredOnBabylonEvent) EventDesc() string {
+	return "DELEGATION_EXPIRED_ON_BABYLON"
+}
+
+type unbondingSignaturesTimeoutEvent struct {
+	stakingTxHash chainhash.Hash
+	timeout       time.Duration
+}
+
+func (event *unbondingSignaturesTimeoutEvent) EventId() chainhash.Hash {
+	return event.stakingTxHash
+}
+
+func (event *unbondingSignaturesTimeoutEvent) EventDesc() string {
+	return "UNBONDING_SIGNATURES_TIMEOUT"
+}
+
+type stakingTxInputConflictEvent struct {
+	stakingTxHash     chainhash.Hash
+	conflictingTxHash chainhash.Hash
+	// input of staking transaction spent by conflicting transaction
+	outpoint wire.OutPoint
+}
+
+func (event *stakingTxInputConflictEvent) EventId() chainhash.Hash {
+	return event.stakingTxHash
+}
+
+func (event *stakingTxInputConflictEvent) EventDesc() string {
+	return "STAKING_TX_INPUT_CONFLICT"
+}
+
 type criticalErrorEvent struct {
 	stakingTxHash     chainhash.Hash
 	err               error
@@ -236,3 +323,10 @@ func (app *StakerApp) logStakingEventProcessed(event StakingEvent) {
 		"event":   event.EventDesc(),
 	}).Debug("Processed staking event")
 }
+
+func (app *StakerApp) logDuplicateStakingEvent(event StakingEvent) {
+	app.logger.WithFields(logrus.Fields{
+		"eventId": event.EventId(),
+		"event":   event.EventDesc(),
+	}).Debug("Transaction already in target state, ignoring duplicate staking event")
+}
@@ -0,0 +1,141 @@
+package staker
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/babylonchain/btc-staker/proto"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+)
+
+// ExpiryExitOption is exit by waiting until staking timelock expires and
+// withdrawing funds from staking output directly
+type ExpiryExitOption struct {
+	// false if staking transaction is not confirmed yet, in which case remaining
+	// blocks are estimated assuming it is included in the next block
+	StakingTxConfirmed bool
+	// blocks which must be mined before withdrawal can be included in the next
+	// block, zero if staking timelock already expired
+	RemainingBlocks   uint32
+	RemainingEstimate time.Duration
+	// estimated fee of transaction withdrawing staked funds to staker address at
+	// current fee rate
+	WithdrawalFee btcutil.Amount
+	NetAmount     btcutil.Amount
+}
+
+// ExitOptions compares exits from delegation available to staker
+type ExitOptions struct {
+	StakingValue btcutil.Amount
+	Expiry       *ExpiryExitOption
+	// nil if delegation cannot be unbonded anymore
+	Unbonding *UnbondingPreview
+}
+
+// canBeUnbonded returns true if staker can still request unbonding of delegation
+// in given state, or will be able to once delegation is active
+func canBeUnbonded(state proto.TransactionState) bool {
+	switch state {
+	case proto.TransactionState_SENT_TO_BTC,
+		proto.TransactionState_CONFIRMED_ON_BTC,
+		proto.TransactionState_SENT_TO_BABYLON,
+		proto.TransactionState_UNBONDING_SIGNATURES_TIMEOUT,
+		proto.TransactionState_DELEGATION_ACTIVE:
+		return true
+	default:
+		return false
+	}
+}
+
+// CompareExitOptions estimates fees, amounts received and durations of unbonding
+// of delegation, and of waiting until its staking timelock expires. Fees are
+// computed the same way as fees of transactions which would be sent, at current
+// fee rates.
+func (app *StakerApp) CompareExitOptions(stakingTxHash chainhash.Hash) (*ExitOptions, error) {
+	tx, err := app.txTracker.GetTransaction(&stakingTxHash)
+
+	if err != nil {
+		return nil, fmt.Errorf("cannot compare exit options: %w", err)
+	}
+
+	if tx.Watched {
+		return nil, fmt.Errorf("cannot compare exit options of watched transaction")
+	}
+
+	if !canBeUnbonded(tx.State) &&
+		tx.State != proto.TransactionState_DELEGATION_EXPIRED &&
+		tx.State != proto.TransactionState_DELEGATION_CANCELLED {
+		return nil, fmt.Errorf("cannot compare exit options of transaction in state %s", tx.State)
+	}
+
+	stakerAddress, err := btcutil.DecodeAddress(tx.StakerAddress, app.network)
+
+	if err != nil {
+		return nil, fmt.Errorf("error decoding staker address: %s. Err: %v", tx.StakerAddress, err)
+	}
+
+	stakerAddressScript, err := txscript.PayToAddrScript(stakerAddress)
+
+	if err != nil {
+		return nil, err
+	}
+
+	stakingOutput := tx.StakingTx.TxOut[tx.StakingOutputIndex]
+
+	// the same transaction which would be sent when spending staking output after
+	// timelock expires
+	_, withdrawalFee, err := createSpendStakeTx(
+		stakerAddressScript,
+		stakingOutput,
+		tx.StakingOutputIndex,
+		&stakingTxHash,
+		tx.StakingTime,
+		app.estimateFeePerKb(app.config.StakerConfig.SpendTxConfTarget),
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	stakingValue := btcutil.Amount(stakingOutput.Value)
+
+	expiry := &ExpiryExitOption{
+		WithdrawalFee: *withdrawalFee,
+		NetAmount:     stakingValue - *withdrawalFee,
+	}
+
+	if expiryHeight, ok := tx.WithdrawableHeight(); ok {
+		expiry.StakingTxConfirmed = true
+
+		// withdrawal can be included in block at expiry height, which is mined
+		// after the blocks before it
+		nextBlockHeight := app.currentBestBlockHeight.Load() + 1
+
+		if expiryHeight > nextBlockHeight {
+			expiry.RemainingBlocks = expiryHeight - nextBlockHeight
+		}
+	} else {
+		expiry.RemainingBlocks = uint32(tx.StakingTime)
+	}
+
+	expiry.RemainingEstimate = app.blocksDuration(expiry.RemainingBlocks)
+
+	options := &ExitOptions{
+		StakingValue: stakingValue,
+		Expiry:       expiry,
+	}
+
+	if canBeUnbonded(tx.State) {
+		unbonding, err := app.PreviewUnbonding(stakingTxHash, nil)
+
+		if err != nil {
+			return nil, err
+		}
+
+		options.Unbonding = unbonding
+	}
+
+	return options, nil
+}
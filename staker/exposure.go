@@ -0,0 +1,115 @@
+package staker
+
+import (
+	"encoding/hex"
+	"errors"
+	"sort"
+
+	cl "github.com/babylonchain/btc-staker/babylonclient"
+	"github.com/babylonchain/btc-staker/proto"
+	"github.com/babylonchain/btc-staker/stakerdb"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/sirupsen/logrus"
+)
+
+// ProviderExposure is the sum of stake delegated to single finality provider.
+// Delegation to multiple finality providers is counted in full for each of them,
+// as whole stake can be slashed if any of them misbehaves.
+type ProviderExposure struct {
+	FpBtcPk *btcec.PublicKey
+	// empty if finality provider is not known to babylon or did not register
+	// moniker
+	Moniker        string
+	TotalStaked    btcutil.Amount
+	NumDelegations uint64
+}
+
+// isExposedState returns true for states in which delegation was sent to babylon
+// and staked funds are still locked in staking output
+func isExposedState(state proto.TransactionState) bool {
+	return state == proto.TransactionState_SENT_TO_BABYLON ||
+		state == proto.TransactionState_UNBONDING_SIGNATURES_TIMEOUT ||
+		state == proto.TransactionState_DELEGATION_ACTIVE
+}
+
+// ProviderExposure returns stake delegated to each finality provider, ordered
+// from the largest exposure. If fpBtcPk is provided, only exposure to this
+// finality provider is returned.
+func (app *StakerApp) ProviderExposure(fpBtcPk *btcec.PublicKey) ([]ProviderExposure, error) {
+	var exposures map[string]*ProviderExposure
+
+	reset := func() {
+		exposures = make(map[string]*ProviderExposure)
+	}
+	reset()
+
+	err := app.txTracker.ScanTrackedTransactions(func(tx *stakerdb.StoredTransaction) error {
+		if !isExposedState(tx.State) {
+			return nil
+		}
+
+		stakingValue := btcutil.Amount(tx.StakingTx.TxOut[tx.StakingOutputIndex].Value)
+
+		for _, pk := range tx.FinalityProvidersBtcPks {
+			if fpBtcPk != nil && !pk.IsEqual(fpBtcPk) {
+				continue
+			}
+
+			key := hex.EncodeToString(schnorr.SerializePubKey(pk))
+
+			exposure, ok := exposures[key]
+
+			if !ok {
+				exposure = &ProviderExposure{FpBtcPk: pk}
+				exposures[key] = exposure
+			}
+
+			exposure.TotalStaked += stakingValue
+			exposure.NumDelegations++
+		}
+
+		return nil
+	}, reset)
+
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]ProviderExposure, 0, len(exposures))
+
+	for _, exposure := range exposures {
+		exposure.Moniker = app.finalityProviderMoniker(exposure.FpBtcPk)
+		result = append(result, *exposure)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].TotalStaked > result[j].TotalStaked
+	})
+
+	return result, nil
+}
+
+// finalityProviderMoniker returns moniker of finality provider registered on
+// babylon. Report is still useful without monikers, so errors are only logged.
+func (app *StakerApp) finalityProviderMoniker(fpBtcPk *btcec.PublicKey) string {
+	resp, err := app.babylonClient.QueryFinalityProvider(fpBtcPk)
+
+	if err != nil {
+		logger := app.logger.WithFields(logrus.Fields{
+			"fpBtcPk": hex.EncodeToString(schnorr.SerializePubKey(fpBtcPk)),
+			"err":     err,
+		})
+
+		if errors.Is(err, cl.ErrFinalityProviderDoesNotExist) || errors.Is(err, cl.ErrFinalityProviderIsSlashed) {
+			logger.Debug("Finality provider not available on babylon. Skipping its moniker")
+		} else {
+			logger.Warn("Failed to query finality provider moniker")
+		}
+
+		return ""
+	}
+
+	return resp.FinalityProvider.Moniker
+}
@@ -0,0 +1,169 @@
+package staker
+
+import (
+	"errors"
+	"fmt"
+
+	cl "github.com/babylonchain/btc-staker/babylonclient"
+	"github.com/babylonchain/btc-staker/proto"
+	"github.com/babylonchain/btc-staker/stakerdb"
+	"github.com/babylonchain/btc-staker/utils"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/sirupsen/logrus"
+)
+
+// ErrExternalDelegationsDisabled is returned when delegation is built or marked as
+// submitted externally, while staker sends delegations to babylon on its own
+var ErrExternalDelegationsDisabled = errors.New("external delegations are disabled")
+
+type ExternalDelegation struct {
+	// serialized MsgCreateBTCDelegation
+	DelegationMsg []byte
+	Delegation    *cl.DelegationData
+}
+
+// confirmedStakingTx returns stored staking transaction, only if it is confirmed
+// on btc and its delegation was not yet sent to babylon
+func (app *StakerApp) confirmedStakingTx(stakingTxHash *chainhash.Hash) (*stakerdb.StoredTransaction, error) {
+	storedTx, err := app.txTracker.GetTransaction(stakingTxHash)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if storedTx.State != proto.TransactionState_CONFIRMED_ON_BTC {
+		return nil, fmt.Errorf(
+			"cannot use staking transaction %s in state %s, expected: %s",
+			stakingTxHash,
+			storedTx.State,
+			proto.TransactionState_CONFIRMED_ON_BTC,
+		)
+	}
+
+	return storedTx, nil
+}
+
+// BuildDelegationMsg builds delegation message for staking transaction confirmed
+// on btc, without sending it to babylon. If signer is empty, address of the
+// daemon babylon key is used as message signer.
+func (app *StakerApp) BuildDelegationMsg(stakingTxHash *chainhash.Hash, signer string) (*ExternalDelegation, error) {
+	if !app.config.StakerConfig.ExternalDelegations {
+		// staker sends delegation on its own, so externally submitted message would
+		// race with it
+		return nil, fmt.Errorf("cannot build delegation message: %w", ErrExternalDelegationsDisabled)
+	}
+
+	storedTx, err := app.confirmedStakingTx(stakingTxHash)
+
+	if err != nil {
+		return nil, err
+	}
+
+	stakerAddress, err := btcutil.DecodeAddress(storedTx.StakerAddress, app.network)
+
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := app.buildSendDelegationRequest(stakingTxHash, storedTx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := app.appQuitContext()
+	defer cancel()
+
+	delegation, err := app.buildDelegation(ctx, req, stakerAddress, storedTx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	msg, err := app.babylonClient.BuildDelegationMsg(signer, delegation)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &ExternalDelegation{
+		DelegationMsg: msg,
+		Delegation:    delegation,
+	}, nil
+}
+
+// MarkDelegationSubmitted is used to notify staker that delegation of staking
+// transaction was submitted to babylon externally. Staker verifies that delegation
+// is on babylon, and continues tracking it as if it was sent by staker itself.
+func (app *StakerApp) MarkDelegationSubmitted(stakingTxHash *chainhash.Hash, babylonTxHash string) error {
+	if !app.config.StakerConfig.ExternalDelegations {
+		// staker is already sending delegation on its own
+		return fmt.Errorf("cannot mark delegation as submitted: %w", ErrExternalDelegationsDisabled)
+	}
+
+	storedTx, err := app.confirmedStakingTx(stakingTxHash)
+
+	if err != nil {
+		return err
+	}
+
+	onBabylon, err := app.babylonClient.IsTxAlreadyPartOfDelegation(stakingTxHash)
+
+	if err != nil {
+		return err
+	}
+
+	if !onBabylon {
+		return fmt.Errorf("delegation of staking transaction %s not found on babylon", stakingTxHash)
+	}
+
+	delegationInfo, err := app.babylonClient.QueryDelegationInfo(stakingTxHash)
+
+	if err != nil {
+		return err
+	}
+
+	if delegationInfo.UndelegationInfo == nil {
+		return fmt.Errorf("delegation of staking transaction %s has no unbonding data", stakingTxHash)
+	}
+
+	params, err := app.babylonClient.Params()
+
+	if err != nil {
+		return err
+	}
+
+	unbondingOutputIdx, err := app.babylonUnbondingOutputIdx(
+		stakingTxHash,
+		storedTx,
+		delegationInfo.UndelegationInfo.UnbondingTransaction,
+		delegationInfo.UndelegationInfo.UnbondingTime,
+		params,
+	)
+
+	if err != nil {
+		return fmt.Errorf("invalid unbonding transaction of delegation found on babylon: %w", err)
+	}
+
+	app.logger.WithFields(logrus.Fields{
+		"stakingTxHash": stakingTxHash,
+		"babylonTxHash": babylonTxHash,
+	}).Info("Delegation submitted to babylon externally")
+
+	ev := &delegationSubmittedToBabylonEvent{
+		stakingTxHash:      *stakingTxHash,
+		unbondingTx:        delegationInfo.UndelegationInfo.UnbondingTransaction,
+		unbondingOutputIdx: unbondingOutputIdx,
+		unbondingTime:      delegationInfo.UndelegationInfo.UnbondingTime,
+		babylonTxHash:      babylonTxHash,
+	}
+
+	utils.PushOrQuit[*delegationSubmittedToBabylonEvent](
+		app.delegationSubmittedToBabylonEvChan,
+		ev,
+		app.quit,
+	)
+
+	return nil
+}
@@ -0,0 +1,162 @@
+package staker
+
+import (
+	"testing"
+	"time"
+
+	staking "github.com/babylonchain/babylon/btcstaking"
+	cl "github.com/babylonchain/btc-staker/babylonclient"
+	"github.com/babylonchain/btc-staker/proto"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	notifier "github.com/lightningnetwork/lnd/chainntnfs"
+	"github.com/stretchr/testify/require"
+)
+
+func (c *testBabylonClient) IsTxAlreadyPartOfDelegation(stakingTxHash *chainhash.Hash) (bool, error) {
+	_, ok := c.delegations[*stakingTxHash]
+	return ok, nil
+}
+
+func (c *testBabylonClient) BuildDelegationMsg(signer string, _ *cl.DelegationData) ([]byte, error) {
+	return []byte(signer), nil
+}
+
+func (w *keyWallet) AddressPublicKey(_ btcutil.Address) (*btcec.PublicKey, error) {
+	return w.privKey.PubKey(), nil
+}
+
+func TestExternalDelegationRequiresFlag(t *testing.T) {
+	deps := newTestStakerDeps(t)
+	txHash := deps.addConfirmedTransaction(t)
+
+	app := deps.newApp(t)
+
+	_, err := app.BuildDelegationMsg(txHash, "")
+	require.ErrorIs(t, err, ErrExternalDelegationsDisabled)
+
+	require.ErrorIs(t, app.MarkDelegationSubmitted(txHash, "babylon-tx"), ErrExternalDelegationsDisabled)
+
+	storedTx, err := deps.tracker.GetTransaction(txHash)
+	require.NoError(t, err)
+	require.Equal(t, proto.TransactionState_CONFIRMED_ON_BTC, storedTx.State)
+}
+
+func TestBuildDelegationMsg(t *testing.T) {
+	deps := newTestStakerDeps(t)
+	deps.config.StakerConfig.ExternalDelegations = true
+	deps.wallet.txsInChain = make(map[chainhash.Hash]*notifier.TxConfirmation)
+
+	txHash := deps.addWatchedTransaction(t)
+
+	app := deps.newApp(t)
+
+	// staking transaction not confirmed yet
+	_, err := app.BuildDelegationMsg(txHash, "bbn1signer")
+	require.Error(t, err)
+
+	storedTx, err := deps.tracker.GetTransaction(txHash)
+	require.NoError(t, err)
+
+	block := testInclusionBlock(t, storedTx.StakingTx)
+	blockHash := block.BlockHash()
+	require.NoError(t, deps.tracker.SetTxConfirmed(txHash, &blockHash, 10, block.Header.Timestamp))
+	deps.wallet.txsInChain[*txHash] = &notifier.TxConfirmation{
+		BlockHash:   &blockHash,
+		BlockHeight: 10,
+		TxIndex:     1,
+		Block:       block,
+	}
+
+	externalDelegation, err := app.BuildDelegationMsg(txHash, "bbn1signer")
+	require.NoError(t, err)
+	require.Equal(t, []byte("bbn1signer"), externalDelegation.DelegationMsg)
+	require.Equal(t, *txHash, externalDelegation.Delegation.StakingTransaction.TxHash())
+	require.Equal(t, blockHash, externalDelegation.Delegation.StakingTransactionInclusionBlockHash)
+
+	// message is only built, delegation is neither sent nor recorded
+	require.Empty(t, deps.babylon.delegatedTxs())
+
+	storedTx, err = deps.tracker.GetTransaction(txHash)
+	require.NoError(t, err)
+	require.Equal(t, proto.TransactionState_CONFIRMED_ON_BTC, storedTx.State)
+}
+
+func TestMarkDelegationSubmitted(t *testing.T) {
+	deps := newTestStakerDeps(t)
+	deps.config.StakerConfig.ExternalDelegations = true
+
+	stakerKey := genPrivKey(t)
+	deps.wc = &keyWallet{testWallet: deps.wallet, privKey: stakerKey}
+
+	stakerAddress, err := btcutil.NewAddressWitnessPubKeyHash(
+		btcutil.Hash160(stakerKey.PubKey().SerializeCompressed()),
+		&deps.config.ActiveNetParams,
+	)
+	require.NoError(t, err)
+
+	stakingTxHash := deps.addSpendableTransaction(t, stakerKey, stakerAddress)
+
+	app := deps.newApp(t)
+
+	// delegation was not submitted yet
+	err = app.MarkDelegationSubmitted(stakingTxHash, "babylon-tx")
+	require.ErrorContains(t, err, "not found on babylon")
+
+	storedTx, err := deps.tracker.GetTransaction(stakingTxHash)
+	require.NoError(t, err)
+	require.Equal(t, proto.TransactionState_CONFIRMED_ON_BTC, storedTx.State)
+
+	const unbondingTime = 50
+	unbondingInfo, err := staking.BuildUnbondingInfo(
+		stakerKey.PubKey(),
+		storedTx.FinalityProvidersBtcPks,
+		deps.babylon.params.CovenantPks,
+		deps.babylon.params.CovenantQuruomThreshold,
+		unbondingTime,
+		90000,
+		&deps.config.ActiveNetParams,
+	)
+	require.NoError(t, err)
+
+	unbondingTx := wire.NewMsgTx(2)
+	unbondingTx.AddTxIn(wire.NewTxIn(wire.NewOutPoint(stakingTxHash, 0), nil, nil))
+	unbondingTx.AddTxOut(unbondingInfo.UnbondingOutput)
+
+	deps.babylon.delegations = map[chainhash.Hash]*cl.DelegationInfo{
+		*stakingTxHash: {
+			Status: cl.DelegationStatusPending,
+			UndelegationInfo: &cl.UndelegationInfo{
+				UnbondingTransaction: unbondingTx,
+				UnbondingTime:        unbondingTime,
+			},
+		},
+	}
+
+	// stands in for main event loop
+	done := make(chan struct{})
+	defer close(done)
+	submitted := make(chan *delegationSubmittedToBabylonEvent, 1)
+	go func() {
+		select {
+		case ev := <-app.delegationSubmittedToBabylonEvChan:
+			submitted <- ev
+		case <-done:
+		}
+	}()
+
+	require.NoError(t, app.MarkDelegationSubmitted(stakingTxHash, "babylon-tx"))
+
+	select {
+	case ev := <-submitted:
+		require.Equal(t, *stakingTxHash, ev.stakingTxHash)
+		require.Equal(t, "babylon-tx", ev.babylonTxHash)
+		require.Equal(t, unbondingTx.TxHash(), ev.unbondingTx.TxHash())
+		require.Equal(t, uint32(0), ev.unbondingOutputIdx)
+		require.Equal(t, uint16(unbondingTime), ev.unbondingTime)
+	case <-time.After(time.Second):
+		t.Fatalf("submitted delegation was not reported to main loop")
+	}
+}
@@ -0,0 +1,103 @@
+package staker
+
+import (
+	"sync"
+	"time"
+
+	"github.com/babylonchain/btc-staker/stakerdb"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// fee estimates older than this are dropped from in-memory history
+	feeEstimateHistoryWindow = 24 * time.Hour
+	// bound on history size, in case estimates are requested very often
+	maxFeeEstimateHistoryLen = 1000
+)
+
+// feeEstimateHistory keeps recent fee estimates in memory, so that behaviour of
+// fee estimator can be inspected without external monitoring. Zero value is
+// ready to use.
+type feeEstimateHistory struct {
+	mu        sync.Mutex
+	estimates []stakerdb.FeeEstimate
+}
+
+// prune drops estimates older than history window. Must be called with mutex
+// held.
+func (h *feeEstimateHistory) prune(now time.Time) {
+	cutoff := now.Add(-feeEstimateHistoryWindow)
+
+	i := 0
+	for i < len(h.estimates) && h.estimates[i].EstimatedAt.Before(cutoff) {
+		i++
+	}
+
+	if len(h.estimates)-i > maxFeeEstimateHistoryLen {
+		i = len(h.estimates) - maxFeeEstimateHistoryLen
+	}
+
+	h.estimates = h.estimates[i:]
+}
+
+func (h *feeEstimateHistory) add(estimate stakerdb.FeeEstimate) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.estimates = append(h.estimates, estimate)
+	h.prune(estimate.EstimatedAt)
+}
+
+// list returns estimates from history window, oldest first
+func (h *feeEstimateHistory) list(now time.Time) []stakerdb.FeeEstimate {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.prune(now)
+
+	estimates := make([]stakerdb.FeeEstimate, len(h.estimates))
+	copy(estimates, h.estimates)
+
+	return estimates
+}
+
+// estimateFee returns fee estimate for given confirmation target and records it
+// in fee estimate history
+func (app *StakerApp) estimateFee(confTarget uint32) *stakerdb.FeeEstimate {
+	estimate := app.feeEstimator.Estimate(confTarget)
+	estimate.EstimatedAt = app.clock.Now()
+
+	app.feeEstimates.add(*estimate)
+
+	return estimate
+}
+
+// estimateFeePerKb returns fee rate for given confirmation target, when details
+// of the estimate are not needed
+func (app *StakerApp) estimateFeePerKb(confTarget uint32) chainfee.SatPerKVByte {
+	return chainfee.SatPerKVByte(app.estimateFee(confTarget).FeeRate())
+}
+
+// recordFeeEstimate stores fee estimate used to build transaction of given type
+// with the staking transaction. Estimates are only informative, so errors are
+// only logged.
+func (app *StakerApp) recordFeeEstimate(
+	stakingTxHash *chainhash.Hash,
+	txType stakerdb.FeeEstimateTxType,
+	estimate *stakerdb.FeeEstimate,
+) {
+	if err := app.txTracker.SetTxFeeEstimate(stakingTxHash, txType, estimate); err != nil {
+		app.logger.WithFields(logrus.Fields{
+			"stakingTxHash": stakingTxHash,
+			"txType":        txType,
+			"err":           err,
+		}).Warn("Failed to store fee estimate of transaction")
+	}
+}
+
+// FeeEstimates returns fee estimates made during the last 24 hours, oldest first
+func (app *StakerApp) FeeEstimates() []stakerdb.FeeEstimate {
+	return app.feeEstimates.list(app.clock.Now())
+}
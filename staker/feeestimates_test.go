@@ -0,0 +1,48 @@
+package staker
+
+import (
+	"testing"
+	"time"
+
+	"github.com/babylonchain/btc-staker/stakerdb"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFeeEstimateHistoryDropsOldEstimates(t *testing.T) {
+	var history feeEstimateHistory
+
+	history.add(stakerdb.FeeEstimate{ConfTarget: 1, EstimatedAt: testClockStart})
+	history.add(stakerdb.FeeEstimate{ConfTarget: 2, EstimatedAt: testClockStart.Add(time.Hour)})
+	history.add(stakerdb.FeeEstimate{ConfTarget: 3, EstimatedAt: testClockStart.Add(2 * time.Hour)})
+
+	require.Len(t, history.list(testClockStart.Add(2*time.Hour)), 3)
+
+	estimates := history.list(testClockStart.Add(feeEstimateHistoryWindow + time.Hour))
+	require.Len(t, estimates, 2)
+	require.Equal(t, uint32(2), estimates[0].ConfTarget)
+	require.Equal(t, uint32(3), estimates[1].ConfTarget)
+
+	require.Empty(t, history.list(testClockStart.Add(feeEstimateHistoryWindow+3*time.Hour)))
+}
+
+func TestFeeEstimateHistoryIsBounded(t *testing.T) {
+	var history feeEstimateHistory
+
+	for i := 0; i < maxFeeEstimateHistoryLen+10; i++ {
+		history.add(stakerdb.FeeEstimate{ConfTarget: uint32(i), EstimatedAt: testClockStart})
+	}
+
+	estimates := history.list(testClockStart)
+	require.Len(t, estimates, maxFeeEstimateHistoryLen)
+	require.Equal(t, uint32(10), estimates[0].ConfTarget)
+}
+
+func TestStaticFeeEstimate(t *testing.T) {
+	estimator := NewStaticBtcFeeEstimator(2000)
+
+	estimate := estimator.Estimate(3)
+	require.Equal(t, staticFeeEstimatorType, estimate.Estimator)
+	require.Equal(t, uint32(3), estimate.ConfTarget)
+	require.Zero(t, estimate.FallbackFeeRate)
+	require.Equal(t, int64(estimator.EstimateFeePerKb(3)), int64(estimate.FeeRate()))
+}
@@ -6,23 +6,39 @@ import (
 	"github.com/babylonchain/btc-staker/types"
 
 	scfg "github.com/babylonchain/btc-staker/stakercfg"
+	"github.com/babylonchain/btc-staker/stakerdb"
+	"github.com/btcsuite/btcd/btcutil"
 	"github.com/btcsuite/btcd/chaincfg"
 	"github.com/btcsuite/btcd/rpcclient"
 	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
 	"github.com/sirupsen/logrus"
 )
 
-const (
-	// DefaultNumBlockForEstimation Default number of blocks to use for fee estimation.
-	// 1 means we want our transactions to be confirmed in the next block.
-	// TODO: make this configurable ?
-	DefaultNumBlockForEstimation = 1
-)
-
 type FeeEstimator interface {
 	Start() error
 	Stop() error
-	EstimateFeePerKb() chainfee.SatPerKVByte
+	// EstimateFeePerKb returns fee rate required for transaction to be confirmed
+	// within confTarget blocks
+	EstimateFeePerKb(confTarget uint32) chainfee.SatPerKVByte
+	// Estimate returns the same fee rate as EstimateFeePerKb together with
+	// details how it was estimated. Time of the estimate is not filled.
+	Estimate(confTarget uint32) *stakerdb.FeeEstimate
+}
+
+// types of fee estimators recorded in fee estimates
+const (
+	dynamicFeeEstimatorType = "dynamic"
+	staticFeeEstimatorType  = "static"
+)
+
+// confTargetOrDefault returns confirmation target requested by the caller, or
+// default target from config if caller did not request any
+func confTargetOrDefault(requested *uint32, defaultTarget uint32) uint32 {
+	if requested != nil {
+		return *requested
+	}
+
+	return defaultTarget
 }
 
 type DynamicBtcFeeEstimator struct {
@@ -35,6 +51,8 @@ type DynamicBtcFeeEstimator struct {
 func NewDynamicBtcFeeEstimator(
 	cfg *scfg.BtcNodeBackendConfig,
 	_ *chaincfg.Params,
+	// nil if node is connected directly
+	proxy *scfg.ProxyConfig,
 	logger *logrus.Logger) (*DynamicBtcFeeEstimator, error) {
 
 	minFeeRate := chainfee.SatPerKVByte(cfg.MinFeeRate * 1000)
@@ -52,6 +70,10 @@ func NewDynamicBtcFeeEstimator(
 			HTTPPostMode:         true,
 		}
 
+		if proxy != nil {
+			proxy.ApplyToRpcClient(&rpcConfig)
+		}
+
 		// TODO: we should probably create our own estimator backend, as those from lnd
 		// have hardcoded loggers, so we do not log stuff to file as we want
 		est, err := chainfee.NewBitcoindEstimator(
@@ -86,6 +108,10 @@ func NewDynamicBtcFeeEstimator(
 			DisableAutoReconnect: false,
 		}
 
+		if proxy != nil {
+			proxy.ApplyToRpcClient(&rpcConfig)
+		}
+
 		est, err := chainfee.NewBtcdEstimator(
 			rpcConfig, maxFeeRate.FeePerKWeight(),
 		)
@@ -116,25 +142,38 @@ func (e *DynamicBtcFeeEstimator) Stop() error {
 	return e.estimator.Stop()
 }
 
-func (e *DynamicBtcFeeEstimator) EstimateFeePerKb() chainfee.SatPerKVByte {
-	fee, err := e.estimator.EstimateFeePerKW(DefaultNumBlockForEstimation)
+func (e *DynamicBtcFeeEstimator) EstimateFeePerKb(confTarget uint32) chainfee.SatPerKVByte {
+	return chainfee.SatPerKVByte(e.Estimate(confTarget).FeeRate())
+}
+
+func (e *DynamicBtcFeeEstimator) Estimate(confTarget uint32) *stakerdb.FeeEstimate {
+	estimate := &stakerdb.FeeEstimate{
+		Estimator:  dynamicFeeEstimatorType,
+		ConfTarget: confTarget,
+	}
+
+	fee, err := e.estimator.EstimateFeePerKW(confTarget)
 
 	if err != nil {
 		e.logger.WithFields(logrus.Fields{
-			"err":     err,
-			"default": e.MaxFeeRate,
+			"err":        err,
+			"confTarget": confTarget,
+			"default":    e.MaxFeeRate,
 		}).Error("Failed to estimate transaction fee using connected btc node. Using max fee from config")
-		return e.MaxFeeRate
+		estimate.FallbackFeeRate = btcutil.Amount(e.MaxFeeRate)
+		return estimate
 	}
 
 	estimatedFee := fee.FeePerKVByte()
+	estimate.EstimatedFeeRate = btcutil.Amount(estimatedFee)
 
 	if estimatedFee < e.MinFeeRate {
 		e.logger.WithFields(logrus.Fields{
 			"minFeeRate": e.MinFeeRate,
 			"estimated":  estimatedFee,
 		}).Debug("Estimated fee is lower than min fee rate. Using min fee rate")
-		return e.MinFeeRate
+		estimate.FallbackFeeRate = btcutil.Amount(e.MinFeeRate)
+		return estimate
 	}
 
 	if estimatedFee > e.MaxFeeRate {
@@ -142,16 +181,18 @@ func (e *DynamicBtcFeeEstimator) EstimateFeePerKb() chainfee.SatPerKVByte {
 			"maxFeeRate": e.MaxFeeRate,
 			"estimated":  estimatedFee,
 		}).Debug("Estimated fee is higher than max fee rate. Using max fee rate")
-		return e.MaxFeeRate
+		estimate.FallbackFeeRate = btcutil.Amount(e.MaxFeeRate)
+		return estimate
 	}
 
 	e.logger.WithFields(logrus.Fields{
 		"fee":        estimatedFee,
+		"confTarget": confTarget,
 		"maxFeeRate": e.MaxFeeRate,
 		"minFeeRate": e.MinFeeRate,
 	}).Debug("Using fee rate estimated by connected btc node")
 
-	return estimatedFee
+	return estimate
 }
 
 type StaticFeeEstimator struct {
@@ -174,6 +215,15 @@ func (e *StaticFeeEstimator) Stop() error {
 	return nil
 }
 
-func (e *StaticFeeEstimator) EstimateFeePerKb() chainfee.SatPerKVByte {
+// EstimateFeePerKb returns configured fee rate regardless of confirmation target
+func (e *StaticFeeEstimator) EstimateFeePerKb(_ uint32) chainfee.SatPerKVByte {
 	return e.DefaultFee
 }
+
+func (e *StaticFeeEstimator) Estimate(confTarget uint32) *stakerdb.FeeEstimate {
+	return &stakerdb.FeeEstimate{
+		Estimator:        staticFeeEstimatorType,
+		ConfTarget:       confTarget,
+		EstimatedFeeRate: btcutil.Amount(e.DefaultFee),
+	}
+}
@@ -0,0 +1,95 @@
+package staker
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	sdkmath "cosmossdk.io/math"
+	cl "github.com/babylonchain/btc-staker/babylonclient"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/sirupsen/logrus"
+)
+
+// checkFeeGrant validates that fee grant from configured fee granter exists and
+// can pay fees of staking messages. Staker cannot start without such grant, as
+// all delegations would be rejected by babylon.
+func (app *StakerApp) checkFeeGrant() error {
+	if app.config.BabylonConfig.FeeGranter == "" {
+		return nil
+	}
+
+	allowance, err := app.babylonClient.QueryFeeAllowance()
+
+	if errors.Is(err, cl.ErrFeeGrantNotFound) {
+		return NewStartupError(ConfigInvalid, err)
+	}
+
+	if err != nil {
+		return NewStartupError(BabylonUnavailable, fmt.Errorf("failed to query babylon fee grant: %w", err))
+	}
+
+	if allowance.Expired(app.clock.Now()) {
+		return NewStartupError(
+			ConfigInvalid,
+			fmt.Errorf("%w: grant from %s expired at %s", cl.ErrFeeGrantExhausted, allowance.Granter, allowance.Expiration),
+		)
+	}
+
+	if disallowed := allowance.DisallowedStakingMessages(); len(disallowed) > 0 {
+		return NewStartupError(
+			ConfigInvalid,
+			fmt.Errorf("babylon fee grant from %s does not allow messages: %s", allowance.Granter, strings.Join(disallowed, ", ")),
+		)
+	}
+
+	app.warnIfFeeGrantLow(allowance)
+
+	return nil
+}
+
+// warnIfFeeGrantLow warns if remaining spend limit of fee grant is below configured
+// low balance threshold
+func (app *StakerApp) warnIfFeeGrantLow(allowance *cl.FeeAllowance) {
+	threshold := app.config.StakerConfig.BabylonLowBalanceThreshold
+
+	// grant without spend limit is only limited by balance of granter
+	if threshold == 0 || allowance.SpendLimit == nil {
+		return
+	}
+
+	gasPrices, err := sdk.ParseDecCoins(app.config.BabylonConfig.GasPrices)
+
+	if err != nil || len(gasPrices) == 0 {
+		return
+	}
+
+	remaining := allowance.SpendLimit.AmountOf(gasPrices[0].Denom)
+
+	if remaining.LT(sdkmath.NewIntFromUint64(threshold)) {
+		app.logger.WithFields(logrus.Fields{
+			"granter":    allowance.Granter,
+			"grantee":    allowance.Grantee,
+			"spendLimit": allowance.SpendLimit,
+			"threshold":  threshold,
+		}).Warn("Babylon fee grant is near exhaustion. Renew the grant to keep sending delegations")
+	}
+}
+
+// checkFeeGrantAllowance periodically warns about fee grant near exhaustion
+func (app *StakerApp) checkFeeGrantAllowance() {
+	if app.config.BabylonConfig.FeeGranter == "" {
+		return
+	}
+
+	allowance, err := app.babylonClient.QueryFeeAllowance()
+
+	if err != nil {
+		app.logger.WithFields(logrus.Fields{
+			"err": err,
+		}).Warn("Failed to check babylon fee grant")
+		return
+	}
+
+	app.warnIfFeeGrantLow(allowance)
+}
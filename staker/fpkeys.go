@@ -0,0 +1,189 @@
+package staker
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+)
+
+// ErrInvalidFinalityProviderKey is wrapped by all errors returned when finality
+// provider key cannot be used in staking script
+var ErrInvalidFinalityProviderKey = errors.New("invalid finality provider key")
+
+// ErrMalformedFinalityProviderKey is returned when finality provider key is not
+// a valid point on secp256k1 curve, or cannot be used as x-only key
+type ErrMalformedFinalityProviderKey struct {
+	FpIdx  int
+	Reason string
+}
+
+func (e *ErrMalformedFinalityProviderKey) Error() string {
+	return fmt.Sprintf("finality provider key %d is malformed: %s", e.FpIdx, e.Reason)
+}
+
+func (e *ErrMalformedFinalityProviderKey) Unwrap() error { return ErrInvalidFinalityProviderKey }
+
+// ErrFinalityProviderKeyIsStakerKey is returned when finality provider key is
+// the same as staker key
+type ErrFinalityProviderKeyIsStakerKey struct {
+	FpBtcPk string
+}
+
+func (e *ErrFinalityProviderKeyIsStakerKey) Error() string {
+	return fmt.Sprintf("finality provider key %s is the same as staker key", e.FpBtcPk)
+}
+
+func (e *ErrFinalityProviderKeyIsStakerKey) Unwrap() error { return ErrInvalidFinalityProviderKey }
+
+// ErrFinalityProviderKeyIsCovenantKey is returned when finality provider key is
+// the same as key of one of covenant committee members
+type ErrFinalityProviderKeyIsCovenantKey struct {
+	FpBtcPk string
+}
+
+func (e *ErrFinalityProviderKeyIsCovenantKey) Error() string {
+	return fmt.Sprintf("finality provider key %s is the same as one of covenant keys", e.FpBtcPk)
+}
+
+func (e *ErrFinalityProviderKeyIsCovenantKey) Unwrap() error { return ErrInvalidFinalityProviderKey }
+
+// length of uncompressed serialization of public key, with 0x04 prefix
+const pubKeyBytesLenUncompressed = 65
+
+var (
+	// ErrInvalidPublicKeyHex is returned when public key is not a hex string
+	ErrInvalidPublicKeyHex = errors.New("public key is not valid hex")
+	// ErrInvalidPublicKeyLength is returned when public key is neither x-only,
+	// compressed nor uncompressed key
+	ErrInvalidPublicKeyLength = errors.New("invalid public key length")
+	// ErrInvalidPublicKeyPoint is returned when public key does not encode a point
+	// on secp256k1 curve
+	ErrInvalidPublicKeyPoint = errors.New("public key is not a valid secp256k1 point")
+)
+
+// ParsePublicKey parses hex encoded public key in x-only (32 bytes), compressed
+// (33 bytes) or uncompressed (65 bytes) form. Hex can have 0x prefix and is case
+// insensitive. Key is normalized to x-only form i.e to even y coordinate, as
+// keys are used in this form in staking scripts and on babylon.
+func ParsePublicKey(key string) (*btcec.PublicKey, error) {
+	trimmed := strings.TrimSpace(key)
+	trimmed = strings.TrimPrefix(strings.TrimPrefix(trimmed, "0x"), "0X")
+
+	keyBytes, err := hex.DecodeString(trimmed)
+
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPublicKeyHex, err)
+	}
+
+	var pk *btcec.PublicKey
+
+	switch len(keyBytes) {
+	case schnorr.PubKeyBytesLen:
+		pk, err = schnorr.ParsePubKey(keyBytes)
+	case btcec.PubKeyBytesLenCompressed, pubKeyBytesLenUncompressed:
+		pk, err = btcec.ParsePubKey(keyBytes)
+	default:
+		return nil, fmt.Errorf(
+			"%w: key has %d bytes, expected %d (x-only), %d (compressed) or %d (uncompressed)",
+			ErrInvalidPublicKeyLength,
+			len(keyBytes),
+			schnorr.PubKeyBytesLen,
+			btcec.PubKeyBytesLenCompressed,
+			pubKeyBytesLenUncompressed,
+		)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPublicKeyPoint, err)
+	}
+
+	return normalizeXOnlyKey(pk)
+}
+
+// ParseFinalityProviderPks parses finality provider keys with ParsePublicKey.
+// Returned error contains index of the first invalid key.
+func ParseFinalityProviderPks(keys []string) ([]*btcec.PublicKey, error) {
+	fpPks := make([]*btcec.PublicKey, 0, len(keys))
+
+	for i, key := range keys {
+		fpPk, err := ParsePublicKey(key)
+
+		if err != nil {
+			return nil, fmt.Errorf("invalid finality provider key %d: %w", i, err)
+		}
+
+		fpPks = append(fpPks, fpPk)
+	}
+
+	return fpPks, nil
+}
+
+// XOnlyKeyHex returns hex encoded x-only serialization of the key, which is the
+// canonical form of finality provider keys
+func XOnlyKeyHex(pk *btcec.PublicKey) string {
+	return xOnlyKey(pk)
+}
+
+// normalizeXOnlyKey returns key with the same x coordinate and even y coordinate
+func normalizeXOnlyKey(pk *btcec.PublicKey) (*btcec.PublicKey, error) {
+	normalized, err := schnorr.ParsePubKey(schnorr.SerializePubKey(pk))
+
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPublicKeyPoint, err)
+	}
+
+	return normalized, nil
+}
+
+// xOnlyKey returns x-only serialization of the key, which is the form in which
+// keys are used in staking scripts
+func xOnlyKey(pk *btcec.PublicKey) string {
+	return hex.EncodeToString(schnorr.SerializePubKey(pk))
+}
+
+// validateFinalityProviderKeys checks that finality provider keys can be used in
+// staking script, i.e. they are valid x-only keys which differ from staker key and
+// covenant keys. Keys are compared in x-only form, as keys differing only in y
+// coordinate parity are the same key in the script.
+func validateFinalityProviderKeys(
+	fpPks []*btcec.PublicKey,
+	stakerPk *btcec.PublicKey,
+	covenantPks []*btcec.PublicKey,
+) error {
+	covenantKeys := make(map[string]struct{}, len(covenantPks))
+	for _, pk := range covenantPks {
+		covenantKeys[xOnlyKey(pk)] = struct{}{}
+	}
+
+	stakerKey := xOnlyKey(stakerPk)
+
+	for i, fpPk := range fpPks {
+		if fpPk == nil {
+			return &ErrMalformedFinalityProviderKey{FpIdx: i, Reason: "key is nil"}
+		}
+
+		if !fpPk.IsOnCurve() {
+			return &ErrMalformedFinalityProviderKey{FpIdx: i, Reason: "key is not on secp256k1 curve"}
+		}
+
+		if _, err := schnorr.ParsePubKey(schnorr.SerializePubKey(fpPk)); err != nil {
+			return &ErrMalformedFinalityProviderKey{FpIdx: i, Reason: err.Error()}
+		}
+
+		fpKey := xOnlyKey(fpPk)
+
+		if fpKey == stakerKey {
+			return &ErrFinalityProviderKeyIsStakerKey{FpBtcPk: fpKey}
+		}
+
+		if _, found := covenantKeys[fpKey]; found {
+			return &ErrFinalityProviderKeyIsCovenantKey{FpBtcPk: fpKey}
+		}
+	}
+
+	return nil
+}
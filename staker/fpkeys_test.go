@@ -0,0 +1,103 @@
+package staker
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/stretchr/testify/require"
+)
+
+func genPubKey(t *testing.T) *btcec.PublicKey {
+	privKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+	return privKey.PubKey()
+}
+
+// oddParityKey returns key with the same x coordinate but opposite y parity
+func oddParityKey(t *testing.T, pk *btcec.PublicKey) *btcec.PublicKey {
+	serialized := pk.SerializeCompressed()
+	serialized[0] ^= 0x01
+	flipped, err := btcec.ParsePubKey(serialized)
+	require.NoError(t, err)
+	return flipped
+}
+
+func TestValidateFinalityProviderKeys(t *testing.T) {
+	stakerPk := genPubKey(t)
+	covenantPks := []*btcec.PublicKey{genPubKey(t), genPubKey(t)}
+	fpPk := genPubKey(t)
+
+	require.NoError(t, validateFinalityProviderKeys([]*btcec.PublicKey{fpPk}, stakerPk, covenantPks))
+
+	var stakerErr *ErrFinalityProviderKeyIsStakerKey
+	err := validateFinalityProviderKeys([]*btcec.PublicKey{fpPk, oddParityKey(t, stakerPk)}, stakerPk, covenantPks)
+	require.ErrorAs(t, err, &stakerErr)
+	require.ErrorIs(t, err, ErrInvalidFinalityProviderKey)
+
+	var covenantErr *ErrFinalityProviderKeyIsCovenantKey
+	err = validateFinalityProviderKeys([]*btcec.PublicKey{covenantPks[1]}, stakerPk, covenantPks)
+	require.ErrorAs(t, err, &covenantErr)
+
+	var x, y btcec.FieldVal
+	x.SetInt(1)
+	y.SetInt(1)
+	var malformedErr *ErrMalformedFinalityProviderKey
+	err = validateFinalityProviderKeys([]*btcec.PublicKey{fpPk, btcec.NewPublicKey(&x, &y)}, stakerPk, covenantPks)
+	require.ErrorAs(t, err, &malformedErr)
+	require.Equal(t, 1, malformedErr.FpIdx)
+
+	err = validateFinalityProviderKeys([]*btcec.PublicKey{nil}, stakerPk, covenantPks)
+	require.ErrorAs(t, err, &malformedErr)
+}
+
+func TestParsePublicKey(t *testing.T) {
+	// key with odd y coordinate, so that normalization is visible
+	pk := genPubKey(t)
+	if pk.SerializeCompressed()[0] == 0x02 {
+		pk = oddParityKey(t, pk)
+	}
+
+	xOnly := hex.EncodeToString(schnorr.SerializePubKey(pk))
+
+	encodings := []string{
+		xOnly,
+		strings.ToUpper(xOnly),
+		"0x" + xOnly,
+		"0X" + strings.ToUpper(xOnly),
+		hex.EncodeToString(pk.SerializeCompressed()),
+		"0x" + hex.EncodeToString(pk.SerializeUncompressed()),
+	}
+
+	for _, encoded := range encodings {
+		parsed, err := ParsePublicKey(encoded)
+		require.NoError(t, err, encoded)
+		require.Equal(t, xOnly, XOnlyKeyHex(parsed))
+		// keys are normalized to even y coordinate
+		require.Equal(t, byte(0x02), parsed.SerializeCompressed()[0])
+	}
+
+	_, err := ParsePublicKey("zz" + xOnly[2:])
+	require.ErrorIs(t, err, ErrInvalidPublicKeyHex)
+
+	_, err = ParsePublicKey(xOnly[2:])
+	require.ErrorIs(t, err, ErrInvalidPublicKeyLength)
+
+	// 0x05 is not valid prefix of compressed key
+	_, err = ParsePublicKey("05" + xOnly)
+	require.ErrorIs(t, err, ErrInvalidPublicKeyPoint)
+
+	// x coordinate of point not on the curve
+	_, err = ParsePublicKey(strings.Repeat("0", 64))
+	require.ErrorIs(t, err, ErrInvalidPublicKeyPoint)
+
+	fpPks, err := ParseFinalityProviderPks([]string{xOnly, "0x" + xOnly})
+	require.NoError(t, err)
+	require.Len(t, fpPks, 2)
+
+	_, err = ParseFinalityProviderPks([]string{xOnly, "0x"})
+	require.ErrorIs(t, err, ErrInvalidPublicKeyLength)
+	require.Contains(t, err.Error(), "key 1")
+}
@@ -0,0 +1,86 @@
+package staker
+
+import (
+	"sync"
+)
+
+// purposes of background goroutines of staker
+const (
+	blockHandlerGoroutine          = "block_handler"
+	stakingEventsGoroutine         = "staking_events"
+	reconciliationGoroutine        = "reconciliation"
+	delegationBacklogGoroutine     = "delegation_backlog"
+	confRegistrationRetryGoroutine = "conf_registration_retry"
+	autoSweepGoroutine             = "auto_sweep"
+	autoRenewGoroutine             = "auto_renew"
+	babylonBalanceGoroutine        = "babylon_balance"
+	startupCheckRetryGoroutine     = "startup_check_retry"
+	stakingTxConfGoroutine         = "staking_tx_confirmation"
+	unbondingSigsGoroutine         = "unbonding_signatures"
+	quitContextGoroutine           = "quit_context"
+	sendDelegationGoroutine        = "send_delegation"
+	sendUnbondingTxGoroutine       = "send_unbonding_tx"
+	spendConfGoroutine             = "spend_confirmation"
+	unbondingOutputSpendGoroutine  = "unbonding_output_spend"
+	stakingInputSpendGoroutine     = "staking_input_spend"
+	stakingRequestGoroutine        = "staking_request"
+	archiveGoroutine               = "archive"
+)
+
+// goroutineGroup is wait group of staker background goroutines, which also
+// counts running goroutines by their purpose, so that stalls can be diagnosed.
+// Zero value is ready to use.
+type goroutineGroup struct {
+	wg      sync.WaitGroup
+	mu      sync.Mutex
+	running map[string]int
+}
+
+// Go runs f in new goroutine registered under given purpose
+func (g *goroutineGroup) Go(purpose string, f func()) {
+	g.wg.Add(1)
+
+	g.mu.Lock()
+	if g.running == nil {
+		g.running = make(map[string]int)
+	}
+	g.running[purpose]++
+	g.mu.Unlock()
+
+	go func() {
+		defer g.wg.Done()
+		defer g.finished(purpose)
+
+		f()
+	}()
+}
+
+func (g *goroutineGroup) finished(purpose string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.running[purpose]--
+
+	if g.running[purpose] == 0 {
+		delete(g.running, purpose)
+	}
+}
+
+// Wait waits until all goroutines started through the group exit
+func (g *goroutineGroup) Wait() {
+	g.wg.Wait()
+}
+
+// counts returns number of running goroutines by purpose
+func (g *goroutineGroup) counts() map[string]int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	counts := make(map[string]int, len(g.running))
+
+	for purpose, n := range g.running {
+		counts[purpose] = n
+	}
+
+	return counts
+}
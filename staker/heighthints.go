@@ -0,0 +1,74 @@
+package staker
+
+import (
+	"github.com/babylonchain/btc-staker/stakerdb"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/kvdb"
+	"github.com/sirupsen/logrus"
+)
+
+func newHeightHintCache(db kvdb.Backend) (*channeldb.HeightHintCache, error) {
+	return channeldb.NewHeightHintCache(
+		channeldb.CacheConfig{
+			// TODO: Investigate this option. Lighting docs mention that this is necessary for some edge case
+			QueryDisable: false,
+		}, db,
+	)
+}
+
+// rebuildHeightHintCache backs up and drops height hints of the notifier. Failed
+// backup does not stop the rebuild, as hints which cannot be read are not worth
+// keeping.
+func rebuildHeightHintCache(db kvdb.Backend, logger *logrus.Logger) error {
+	if err := stakerdb.BackupHeightHintCache(db); err != nil {
+		logger.WithFields(logrus.Fields{
+			"err": err,
+		}).Warn("Failed to back up height hint cache, rebuilding it without backup")
+	}
+
+	return stakerdb.RebuildHeightHintCache(db)
+}
+
+// openHeightHintCache opens height hint cache of the notifier. Cache only
+// speeds up rescans for notifications, so if it cannot be opened, e.g. because
+// it was written by incompatible version of lnd, it is rebuilt from scratch
+// instead of failing startup. Notifier then starts rescans at heights stored
+// with tracked transactions.
+func openHeightHintCache(db kvdb.Backend, rebuild bool, logger *logrus.Logger) (*channeldb.HeightHintCache, error) {
+	if rebuild {
+		logger.Warn("Rebuilding height hint cache as requested by config")
+
+		if err := rebuildHeightHintCache(db, logger); err != nil {
+			return nil, err
+		}
+	}
+
+	hintCache, err := newHeightHintCache(db)
+
+	if err == nil {
+		return hintCache, nil
+	}
+
+	logger.WithFields(logrus.Fields{
+		"err": err,
+	}).Error("Height hint cache is corrupted or was written by incompatible version, rebuilding it from scratch. " +
+		"Old hints are backed up, btc notifications may take longer to arrive until cache is filled again")
+
+	if err := rebuildHeightHintCache(db, logger); err != nil {
+		return nil, err
+	}
+
+	return newHeightHintCache(db)
+}
+
+// mempoolTxHeightHint returns height from which notifier looks for confirmation
+// of staking transaction found in mempool. Broadcast height stored with the
+// transaction is used, so that confirmation is not missed even if height hint
+// cache was rebuilt and transaction got confirmed while registering.
+func mempoolTxHeightHint(tx *stakerdb.StoredTransaction, currentBestBlockHeight uint32) uint32 {
+	if tx.BroadcastHeight > 0 && tx.BroadcastHeight < currentBestBlockHeight {
+		return tx.BroadcastHeight
+	}
+
+	return currentBestBlockHeight
+}
@@ -0,0 +1,29 @@
+package staker
+
+import (
+	"testing"
+
+	"github.com/babylonchain/btc-staker/stakerdb"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMempoolTxHeightHint(t *testing.T) {
+	tests := []struct {
+		name            string
+		broadcastHeight uint32
+		bestHeight      uint32
+		expected        uint32
+	}{
+		{"broadcast height unknown", 0, 100, 100},
+		{"broadcast before current height", 90, 100, 90},
+		{"broadcast at current height", 100, 100, 100},
+		{"broadcast height ahead of notifier", 110, 100, 100},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx := &stakerdb.StoredTransaction{BroadcastHeight: tt.broadcastHeight}
+			require.Equal(t, tt.expected, mempoolTxHeightHint(tx, tt.bestHeight))
+		})
+	}
+}
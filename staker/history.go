@@ -0,0 +1,259 @@
+package staker
+
+import (
+	"bytes"
+	"sort"
+	"time"
+
+	"github.com/babylonchain/btc-staker/proto"
+	"github.com/babylonchain/btc-staker/stakerdb"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+)
+
+type HistoryEventType string
+
+const (
+	// staking transaction confirmed on btc
+	HistoryEventStaked HistoryEventType = "staked"
+	// delegation received covenant signatures on babylon
+	HistoryEventDelegationActive HistoryEventType = "delegation_active"
+	// unbonding transaction sent to btc
+	HistoryEventUnbondingBroadcast HistoryEventType = "unbonding_broadcast"
+	// unbonding transaction confirmed on btc
+	HistoryEventUnbonded HistoryEventType = "unbonded"
+	// staked or unbonded funds spent by staker
+	HistoryEventWithdrawn HistoryEventType = "withdrawn"
+	// staked funds spent into staking output of renewal staking transaction
+	HistoryEventRenewed HistoryEventType = "renewed"
+	// input of unconfirmed staking transaction spent by other transaction
+	HistoryEventConflicted HistoryEventType = "conflicted"
+)
+
+// HistoryEvent is a single event in the history of staking transaction
+type HistoryEvent struct {
+	Type          HistoryEventType
+	StakingTxHash chainhash.Hash
+	// btc transaction which caused the event, nil if it is not known
+	TxHash *chainhash.Hash
+	Time   time.Time
+	// true if time comes from header of inclusion block, false if it is local
+	// time when staker processed the event
+	IsBlockTime bool
+	// height of inclusion block, 0 if event is not tied to btc block
+	BlockHeight uint32
+	// value of staking output for staked and delegation active events, value of
+	// unbonding output for unbonding events, and value received by spend
+	// transaction for withdrawn and renewed events. TxHash of renewed event is
+	// hash of the renewal staking transaction.
+	Amount btcutil.Amount
+	// fee of the transaction which caused the event, nil if it is not known
+	Fee *btcutil.Amount
+}
+
+// stateEnteredAt returns time when transaction entered given state, or zero time
+// if it is not known
+func stateEnteredAt(tx *stakerdb.StoredTransaction, state proto.TransactionState) time.Time {
+	for _, transition := range tx.StateHistory {
+		if transition.State == state {
+			return transition.ChangedAt
+		}
+	}
+
+	// history is not available for transitions made before it was tracked
+	if tx.State == state {
+		return tx.StateChangedAt
+	}
+
+	return time.Time{}
+}
+
+// confirmedAt returns block time of confirmation if it is known, otherwise time
+// when transaction entered given state
+func confirmedAt(
+	tx *stakerdb.StoredTransaction,
+	confirmation *stakerdb.BtcConfirmationInfo,
+	state proto.TransactionState,
+) (time.Time, bool) {
+	if confirmation != nil && !confirmation.BlockTime.IsZero() {
+		return confirmation.BlockTime, true
+	}
+
+	return stateEnteredAt(tx, state), false
+}
+
+// blockTime returns time from header of the block, zero if block is not known
+func blockTime(block *wire.MsgBlock) time.Time {
+	if block == nil {
+		return time.Time{}
+	}
+
+	return block.Header.Timestamp
+}
+
+func txHashPtr(tx *wire.MsgTx) *chainhash.Hash {
+	hash := tx.TxHash()
+	return &hash
+}
+
+func transactionHistory(tx *stakerdb.StoredTransaction) []HistoryEvent {
+	var events []HistoryEvent
+
+	stakingTxHash := tx.StakingTx.TxHash()
+	stakingValue := btcutil.Amount(tx.StakingTx.TxOut[tx.StakingOutputIndex].Value)
+
+	if confirmation := tx.StakingTxConfirmationInfo; confirmation != nil {
+		t, isBlockTime := confirmedAt(tx, confirmation, proto.TransactionState_CONFIRMED_ON_BTC)
+
+		// fee of staking transaction is not known, as its inputs are not stored
+		events = append(events, HistoryEvent{
+			Type:          HistoryEventStaked,
+			StakingTxHash: stakingTxHash,
+			TxHash:        &stakingTxHash,
+			Time:          t,
+			IsBlockTime:   isBlockTime,
+			BlockHeight:   confirmation.Height,
+			Amount:        stakingValue,
+		})
+	}
+
+	if t := stateEnteredAt(tx, proto.TransactionState_DELEGATION_ACTIVE); !t.IsZero() {
+		events = append(events, HistoryEvent{
+			Type:          HistoryEventDelegationActive,
+			StakingTxHash: stakingTxHash,
+			TxHash:        &stakingTxHash,
+			Time:          t,
+			Amount:        stakingValue,
+		})
+	}
+
+	unbonded := false
+
+	if ud := tx.UnbondingTxData; ud != nil {
+		unbondingValue := btcutil.Amount(ud.UnbondingOutput().Value)
+		unbondingFee := stakingValue - unbondingValue
+		unbondingTxHash := txHashPtr(ud.UnbondingTx)
+
+		if !ud.UnbondingTxSentAt.IsZero() {
+			events = append(events, HistoryEvent{
+				Type:          HistoryEventUnbondingBroadcast,
+				StakingTxHash: stakingTxHash,
+				TxHash:        unbondingTxHash,
+				Time:          ud.UnbondingTxSentAt,
+				Amount:        unbondingValue,
+				Fee:           &unbondingFee,
+			})
+		}
+
+		if confirmation := ud.UnbondingTxConfirmationInfo; confirmation != nil {
+			unbonded = true
+			t, isBlockTime := confirmedAt(tx, confirmation, proto.TransactionState_UNBONDING_CONFIRMED_ON_BTC)
+
+			events = append(events, HistoryEvent{
+				Type:          HistoryEventUnbonded,
+				StakingTxHash: stakingTxHash,
+				TxHash:        unbondingTxHash,
+				Time:          t,
+				IsBlockTime:   isBlockTime,
+				BlockHeight:   confirmation.Height,
+				Amount:        unbondingValue,
+				Fee:           &unbondingFee,
+			})
+		}
+	}
+
+	if tx.State == proto.TransactionState_SPENT_ON_BTC {
+		withdrawn := HistoryEvent{
+			Type:          HistoryEventWithdrawn,
+			StakingTxHash: stakingTxHash,
+		}
+
+		if tx.RenewalTxHash != nil {
+			withdrawn.Type = HistoryEventRenewed
+		}
+
+		if sd := tx.SpendTxData; sd != nil {
+			fee := sd.Fee
+			withdrawn.TxHash = &sd.SpendTxHash
+			withdrawn.Amount = sd.SpentValue - sd.Fee
+			withdrawn.Fee = &fee
+			withdrawn.Time, withdrawn.IsBlockTime = confirmedAt(
+				tx, sd.SpendTxConfirmationInfo, proto.TransactionState_SPENT_ON_BTC,
+			)
+
+			if sd.SpendTxConfirmationInfo != nil {
+				withdrawn.BlockHeight = sd.SpendTxConfirmationInfo.Height
+			}
+		} else {
+			// spend was sent before spend transactions were tracked, only value
+			// of the spent output is known
+			withdrawn.Time = stateEnteredAt(tx, proto.TransactionState_SPENT_ON_BTC)
+			withdrawn.Amount = stakingValue
+
+			if unbonded {
+				withdrawn.Amount = btcutil.Amount(tx.UnbondingTxData.UnbondingOutput().Value)
+			}
+		}
+
+		events = append(events, withdrawn)
+	}
+
+	if tx.State == proto.TransactionState_CONFLICTED {
+		events = append(events, HistoryEvent{
+			Type:          HistoryEventConflicted,
+			StakingTxHash: stakingTxHash,
+			TxHash:        tx.ConflictingTxHash,
+			Time:          stateEnteredAt(tx, proto.TransactionState_CONFLICTED),
+			Amount:        stakingValue,
+		})
+	}
+
+	return events
+}
+
+// History returns events of all tracked transactions which happened in time range
+// [from, to), sorted by time. Zero from or to leaves the range unbounded on that
+// side. Events which time is not known, because they happened before history was
+// tracked, are not returned.
+func (app *StakerApp) History(from, to time.Time) ([]HistoryEvent, error) {
+	var events []HistoryEvent
+
+	reset := func() {
+		events = nil
+	}
+
+	err := app.txTracker.ScanTrackedTransactions(func(tx *stakerdb.StoredTransaction) error {
+		for _, ev := range transactionHistory(tx) {
+			if ev.Time.IsZero() {
+				continue
+			}
+
+			if !from.IsZero() && ev.Time.Before(from) {
+				continue
+			}
+
+			if !to.IsZero() && !ev.Time.Before(to) {
+				continue
+			}
+
+			events = append(events, ev)
+		}
+
+		return nil
+	}, reset)
+
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		if !events[i].Time.Equal(events[j].Time) {
+			return events[i].Time.Before(events[j].Time)
+		}
+
+		return bytes.Compare(events[i].StakingTxHash[:], events[j].StakingTxHash[:]) < 0
+	})
+
+	return events, nil
+}
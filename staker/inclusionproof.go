@@ -0,0 +1,116 @@
+package staker
+
+import (
+	"fmt"
+
+	cl "github.com/babylonchain/btc-staker/babylonclient"
+	"github.com/babylonchain/btc-staker/stakerdb"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/sirupsen/logrus"
+)
+
+// recordInclusionProof stores proof of inclusion of staking transaction in the
+// block which confirmed it, so that delegation can be re-sent after restart
+// without fetching the block again, which fails on pruned nodes. Failure is only
+// logged, as proof can still be regenerated from the block.
+func (app *StakerApp) recordInclusionProof(stakingTxHash *chainhash.Hash, block *wire.MsgBlock, txIndex uint32) {
+	proof, err := cl.GenerateProof(block, txIndex)
+
+	if err == nil {
+		err = app.txTracker.SetTxInclusionProof(stakingTxHash, &stakerdb.InclusionProof{
+			BlockHeader: block.Header,
+			TxIndex:     txIndex,
+			MerkleProof: proof,
+		})
+	}
+
+	if err != nil {
+		app.logger.WithFields(logrus.Fields{
+			"btcTxHash": stakingTxHash,
+			"err":       err,
+		}).Warn("Failed to record inclusion proof of confirmed staking transaction")
+	}
+}
+
+// recordedInclusionProof returns inclusion proof recorded when transaction was
+// confirmed, or nil if it was not recorded or does not prove inclusion of the
+// transaction in the block which confirmed it
+func (app *StakerApp) recordedInclusionProof(
+	stakingTxHash *chainhash.Hash,
+	storedTx *stakerdb.StoredTransaction,
+) *stakerdb.InclusionProof {
+	proof := storedTx.StakingTxInclusionProof
+
+	if proof == nil || storedTx.StakingTxConfirmationInfo == nil {
+		return nil
+	}
+
+	err := proof.Verify(stakingTxHash)
+
+	if blockHash := proof.BlockHeader.BlockHash(); err == nil && !blockHash.IsEqual(&storedTx.StakingTxConfirmationInfo.BlockHash) {
+		err = fmt.Errorf("%w: proof is for block %s, transaction was confirmed in block %s",
+			stakerdb.ErrInvalidInclusionProof, blockHash, storedTx.StakingTxConfirmationInfo.BlockHash)
+	}
+
+	if err != nil {
+		app.logger.WithFields(logrus.Fields{
+			"btcTxHash": stakingTxHash,
+			"err":       err,
+		}).Warn("Recorded inclusion proof of staking transaction is invalid, it will be regenerated from btc block")
+
+		return nil
+	}
+
+	return proof
+}
+
+// sendDelegationRequestFromRecordedProof builds request to send delegation from
+// recorded inclusion proof, without querying btc node. Nil is returned if proof
+// was not recorded or is invalid.
+func (app *StakerApp) sendDelegationRequestFromRecordedProof(
+	stakingTxHash *chainhash.Hash,
+	storedTx *stakerdb.StoredTransaction,
+	params *cl.StakingParams,
+) *sendDelegationRequest {
+	proof := app.recordedInclusionProof(stakingTxHash, storedTx)
+
+	if proof == nil {
+		return nil
+	}
+
+	return &sendDelegationRequest{
+		txHash:                      *stakingTxHash,
+		txIndex:                     proof.TxIndex,
+		inclusionBlockHash:          proof.BlockHeader.BlockHash(),
+		requiredInclusionBlockDepth: uint64(storedRequiredDepth(storedTx, params)),
+	}
+}
+
+// stakingTxInclusionProof returns proof of inclusion of staking transaction sent
+// to babylon with delegation. Recorded proof is preferred, and proof is only
+// regenerated from inclusion block if it was not recorded.
+func (app *StakerApp) stakingTxInclusionProof(
+	req *sendDelegationRequest,
+	storedTx *stakerdb.StoredTransaction,
+) ([]byte, error) {
+	if proof := app.recordedInclusionProof(&req.txHash, storedTx); proof != nil &&
+		proof.TxIndex == req.txIndex && proof.BlockHeader.BlockHash() == req.inclusionBlockHash {
+		return proof.MerkleProof, nil
+	}
+
+	if req.inclusionBlock == nil {
+		return nil, fmt.Errorf("cannot build inclusion proof of staking transaction %s: %w", req.txHash, stakerdb.ErrInvalidInclusionProof)
+	}
+
+	proof, err := cl.GenerateProof(req.inclusionBlock, req.txIndex)
+
+	if err != nil {
+		app.logger.WithFields(logrus.Fields{
+			"btcTxHash": req.txHash,
+			"err":       err,
+		}).Fatalf("Failed to build inclusion proof for already confirmed transaction")
+	}
+
+	return proof, nil
+}
@@ -0,0 +1,121 @@
+package staker
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	cl "github.com/babylonchain/btc-staker/babylonclient"
+	"github.com/btcsuite/btcd/blockchain"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	notifier "github.com/lightningnetwork/lnd/chainntnfs"
+	"github.com/stretchr/testify/require"
+)
+
+// addTransactionConfirmedInBlock adds staking transaction confirmed as second
+// transaction of returned block
+func (d *testStakerDeps) addTransactionConfirmedInBlock(t *testing.T) (*chainhash.Hash, *wire.MsgBlock) {
+	txHash := d.addSentToBtcTransaction(t)
+
+	storedTx, err := d.tracker.GetTransaction(txHash)
+	require.NoError(t, err)
+
+	block := testInclusionBlock(t, storedTx.StakingTx)
+
+	blockHash := block.BlockHash()
+	require.NoError(t, d.tracker.SetTxConfirmed(txHash, &blockHash, 10, block.Header.Timestamp))
+
+	return txHash, block
+}
+
+// testInclusionBlock returns block with given transaction as its second transaction
+func testInclusionBlock(t testing.TB, tx *wire.MsgTx) *wire.MsgBlock {
+	coinbase := wire.NewMsgTx(2)
+	coinbase.AddTxIn(wire.NewTxIn(&wire.OutPoint{Index: wire.MaxPrevOutIndex}, nil, nil))
+	coinbase.AddTxOut(wire.NewTxOut(50, []byte{0x51}))
+
+	block := wire.NewMsgBlock(&wire.BlockHeader{Version: 1, Timestamp: time.Unix(1700000000, 0)})
+	require.NoError(t, block.AddTransaction(coinbase))
+	require.NoError(t, block.AddTransaction(tx))
+
+	merkles := blockchain.BuildMerkleTreeStore(
+		[]*btcutil.Tx{btcutil.NewTx(coinbase), btcutil.NewTx(tx)},
+		false,
+	)
+	block.Header.MerkleRoot = *merkles[len(merkles)-1]
+
+	return block
+}
+
+func TestDelegationSentWithRecordedInclusionProof(t *testing.T) {
+	deps := newTestStakerDeps(t)
+	// pruned node can no longer serve the block
+	deps.wallet.txDetailsErr = errors.New("block not available (pruned data)")
+
+	txHash, block := deps.addTransactionConfirmedInBlock(t)
+
+	app := deps.newApp(t)
+
+	storedTx, err := deps.tracker.GetTransaction(txHash)
+	require.NoError(t, err)
+
+	// without recorded proof, block must be fetched from btc node
+	_, err = app.buildSendDelegationRequest(txHash, storedTx)
+	require.ErrorIs(t, err, deps.wallet.txDetailsErr)
+
+	app.recordInclusionProof(txHash, block, 1)
+
+	storedTx, err = deps.tracker.GetTransaction(txHash)
+	require.NoError(t, err)
+	require.NotNil(t, storedTx.StakingTxInclusionProof)
+
+	req, err := app.buildSendDelegationRequest(txHash, storedTx)
+	require.NoError(t, err)
+	require.Nil(t, req.inclusionBlock)
+	require.Equal(t, block.BlockHash(), req.inclusionBlockHash)
+	require.Equal(t, uint32(1), req.txIndex)
+
+	expectedProof, err := cl.GenerateProof(block, 1)
+	require.NoError(t, err)
+
+	proof, err := app.stakingTxInclusionProof(req, storedTx)
+	require.NoError(t, err)
+	require.Equal(t, expectedProof, proof)
+}
+
+func TestInvalidRecordedInclusionProofIsRegenerated(t *testing.T) {
+	deps := newTestStakerDeps(t)
+
+	txHash, block := deps.addTransactionConfirmedInBlock(t)
+
+	app := deps.newApp(t)
+	app.recordInclusionProof(txHash, block, 1)
+
+	storedTx, err := deps.tracker.GetTransaction(txHash)
+	require.NoError(t, err)
+
+	// simulate proof damaged in database
+	storedTx.StakingTxInclusionProof.MerkleProof[0] ^= 1
+	require.Nil(t, app.recordedInclusionProof(txHash, storedTx))
+
+	// without valid proof, block must be fetched from btc node again
+	_, err = app.buildSendDelegationRequest(txHash, storedTx)
+	require.Error(t, err)
+
+	deps.wallet.txsInChain = map[chainhash.Hash]*notifier.TxConfirmation{
+		*txHash: {BlockHeight: 10, TxIndex: 1, Block: block},
+	}
+
+	req, err := app.buildSendDelegationRequest(txHash, storedTx)
+	require.NoError(t, err)
+	require.Equal(t, block, req.inclusionBlock)
+
+	expectedProof, err := cl.GenerateProof(block, 1)
+	require.NoError(t, err)
+
+	proof, err := app.stakingTxInclusionProof(req, storedTx)
+	require.NoError(t, err)
+	require.Equal(t, expectedProof, proof)
+}
@@ -0,0 +1,174 @@
+package staker
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/sirupsen/logrus"
+)
+
+// operations which are retried in long running retry loops
+const (
+	sendUnbondingTxOperation              = "send_unbonding_tx"
+	registerUnbondingTxConfOperation      = "register_unbonding_tx_confirmation"
+	retrieveBackloggedDelegationOperation = "retrieve_backlogged_delegation"
+	sendDelegationOperation               = "send_delegation"
+)
+
+// RetryLoop is operation of staking transaction which is being retried until
+// it succeeds, runs out of attempts or staker shuts down
+type RetryLoop struct {
+	Operation     string
+	StakingTxHash chainhash.Hash
+	// number of failed attempts so far
+	Attempts  uint32
+	LastError string
+	StartedAt time.Time
+}
+
+type retryLoopKey struct {
+	operation     string
+	stakingTxHash chainhash.Hash
+}
+
+// inFlightRegistry keeps retry loops which are in progress. It is only kept in
+// memory, and is used both to report pending operations and to report what was
+// interrupted when staker shuts down.
+type inFlightRegistry struct {
+	mu    sync.Mutex
+	loops map[retryLoopKey]*RetryLoop
+}
+
+func newInFlightRegistry() *inFlightRegistry {
+	return &inFlightRegistry{
+		loops: make(map[retryLoopKey]*RetryLoop),
+	}
+}
+
+func (r *inFlightRegistry) started(operation string, stakingTxHash chainhash.Hash, now time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.loops[retryLoopKey{operation, stakingTxHash}] = &RetryLoop{
+		Operation:     operation,
+		StakingTxHash: stakingTxHash,
+		StartedAt:     now,
+	}
+}
+
+func (r *inFlightRegistry) failedAttempt(operation string, stakingTxHash chainhash.Hash, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	loop, ok := r.loops[retryLoopKey{operation, stakingTxHash}]
+
+	if !ok {
+		return
+	}
+
+	loop.Attempts++
+	if err != nil {
+		loop.LastError = err.Error()
+	}
+}
+
+func (r *inFlightRegistry) finished(operation string, stakingTxHash chainhash.Hash) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.loops, retryLoopKey{operation, stakingTxHash})
+}
+
+// list returns copy of all retry loops, ordered by start time
+func (r *inFlightRegistry) list() []RetryLoop {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	loops := make([]RetryLoop, 0, len(r.loops))
+
+	for _, loop := range r.loops {
+		loops = append(loops, *loop)
+	}
+
+	sort.Slice(loops, func(i, j int) bool {
+		return loops[i].StartedAt.Before(loops[j].StartedAt)
+	})
+
+	return loops
+}
+
+// trackRetryLoop registers retry loop of given operation. Returned function must
+// be called once loop finishes.
+func (app *StakerApp) trackRetryLoop(operation string, stakingTxHash chainhash.Hash) func() {
+	app.inFlight.started(operation, stakingTxHash, app.clock.Now())
+
+	return func() {
+		app.inFlight.finished(operation, stakingTxHash)
+	}
+}
+
+type PendingOperations struct {
+	InFlightDelegations   int
+	BackloggedDelegations int
+	// number of failed confirmation registrations waiting for retry
+	PendingConfRegistrations int
+	// number of failed startup checks waiting for retry
+	PendingStartupChecks int
+	ConfirmationWaits    []ConfirmationWait
+	RetryLoops           []RetryLoop
+	// chain notifier subscriptions owned by staker
+	NotificationSubscriptions []NotificationSubscription
+}
+
+// PendingOperations returns operations which staker is performing at the moment
+// or which wait to be performed
+func (app *StakerApp) PendingOperations() *PendingOperations {
+	inFlight, backlogged := app.delegationBacklog.stats()
+
+	return &PendingOperations{
+		InFlightDelegations:       inFlight,
+		BackloggedDelegations:     backlogged,
+		PendingConfRegistrations:  app.confRegistrations.len(),
+		PendingStartupChecks:      len(app.startupChecks.list()),
+		ConfirmationWaits:         app.confProgress.all(),
+		RetryLoops:                app.inFlight.list(),
+		NotificationSubscriptions: app.notifications.list(),
+	}
+}
+
+// LogPendingOperations logs summary of pending operations, so that operator can
+// see what is interrupted when staker shuts down
+func (app *StakerApp) LogPendingOperations() {
+	pending := app.PendingOperations()
+
+	app.logger.WithFields(logrus.Fields{
+		"inFlightDelegations":       pending.InFlightDelegations,
+		"backloggedDelegations":     pending.BackloggedDelegations,
+		"pendingConfRegistrations":  pending.PendingConfRegistrations,
+		"pendingStartupChecks":      pending.PendingStartupChecks,
+		"confirmationWaits":         len(pending.ConfirmationWaits),
+		"retryLoops":                len(pending.RetryLoops),
+		"notificationSubscriptions": len(pending.NotificationSubscriptions),
+	}).Info("Pending operations at shutdown")
+
+	for _, wait := range pending.ConfirmationWaits {
+		app.logger.WithFields(logrus.Fields{
+			"stakingTxHash":         wait.StakingTxHash,
+			"type":                  wait.Type,
+			"txHash":                wait.TxHash,
+			"confirmations":         wait.Confirmations,
+			"requiredConfirmations": wait.RequiredConfirmations,
+		}).Info("Interrupted confirmation wait")
+	}
+
+	for _, loop := range pending.RetryLoops {
+		app.logger.WithFields(logrus.Fields{
+			"stakingTxHash": loop.StakingTxHash,
+			"operation":     loop.Operation,
+			"attempts":      loop.Attempts,
+			"lastError":     loop.LastError,
+		}).Warn("Interrupted retry loop")
+	}
+}
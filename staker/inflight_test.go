@@ -0,0 +1,37 @@
+package staker
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInFlightRegistryTracksRetryLoops(t *testing.T) {
+	registry := newInFlightRegistry()
+	firstTxHash := chainhash.HashH([]byte("first staking tx"))
+	secondTxHash := chainhash.HashH([]byte("second staking tx"))
+
+	registry.started(sendDelegationOperation, secondTxHash, testClockStart.Add(time.Minute))
+	registry.started(sendUnbondingTxOperation, firstTxHash, testClockStart)
+
+	registry.failedAttempt(sendUnbondingTxOperation, firstTxHash, errors.New("first error"))
+	registry.failedAttempt(sendUnbondingTxOperation, firstTxHash, errors.New("second error"))
+	// attempts of loops which are not tracked are ignored
+	registry.failedAttempt(sendUnbondingTxOperation, secondTxHash, errors.New("not tracked"))
+
+	loops := registry.list()
+	require.Len(t, loops, 2)
+	require.Equal(t, sendUnbondingTxOperation, loops[0].Operation)
+	require.Equal(t, firstTxHash, loops[0].StakingTxHash)
+	require.Equal(t, uint32(2), loops[0].Attempts)
+	require.Equal(t, "second error", loops[0].LastError)
+	require.Equal(t, sendDelegationOperation, loops[1].Operation)
+	require.Equal(t, uint32(0), loops[1].Attempts)
+
+	registry.finished(sendUnbondingTxOperation, firstTxHash)
+	registry.finished(sendDelegationOperation, secondTxHash)
+	require.Empty(t, registry.list())
+}
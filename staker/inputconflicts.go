@@ -0,0 +1,112 @@
+package staker
+
+import (
+	"github.com/babylonchain/btc-staker/utils"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	notifier "github.com/lightningnetwork/lnd/chainntnfs"
+	"github.com/sirupsen/logrus"
+)
+
+// watchStakingTxInputs registers for spends of inputs of unconfirmed staking
+// transaction sent by staker, so that staking transaction which inputs were
+// double spent is not awaited forever. Subscription is cancelled once staking
+// transaction confirms, as from then on inputs are spent by staking transaction
+// itself.
+func (app *StakerApp) watchStakingTxInputs(
+	stakingTxHash chainhash.Hash,
+	stakingTx *wire.MsgTx,
+	heightHint uint32,
+) {
+	sub := app.notifications.reserve(stakingTxHash, StakingInputsSpendPurpose, stakingTxHash, app.clock.Now())
+
+	if sub == nil {
+		// inputs are already watched e.g startup check was retried
+		return
+	}
+
+	var spendEvents []*notifier.SpendEvent
+
+	for _, txIn := range stakingTx.TxIn {
+		// notifier requires script of spent output, which is recovered from
+		// signature of the input, as inputs are already spent in mempool
+		pkScript, err := txscript.ComputePkScript(txIn.SignatureScript, txIn.Witness)
+
+		if err != nil {
+			app.logger.WithFields(logrus.Fields{
+				"stakingTxHash": stakingTxHash,
+				"outpoint":      txIn.PreviousOutPoint,
+				"err":           err,
+			}).Warn("Failed to recover script of staking transaction input. Double spend of the input will not be detected")
+			continue
+		}
+
+		spendEvent, err := app.notifier.RegisterSpendNtfn(&txIn.PreviousOutPoint, pkScript.Script(), heightHint)
+
+		if err != nil {
+			app.logger.WithFields(logrus.Fields{
+				"stakingTxHash": stakingTxHash,
+				"outpoint":      txIn.PreviousOutPoint,
+				"err":           err,
+			}).Warn("Failed to register for spend of staking transaction input. Double spend of the input will not be detected")
+			continue
+		}
+
+		spendEvents = append(spendEvents, spendEvent)
+	}
+
+	if len(spendEvents) == 0 {
+		app.notifications.remove(sub)
+		return
+	}
+
+	cancelAll := func() {
+		for _, spendEvent := range spendEvents {
+			spendEvent.Cancel()
+		}
+	}
+
+	if !app.notifications.attach(sub, cancelAll) {
+		return
+	}
+
+	for i := range spendEvents {
+		spendEvent := spendEvents[i]
+
+		app.wg.Go(stakingInputSpendGoroutine, func() {
+			app.waitForStakingTxInputSpend(stakingTxHash, spendEvent, sub)
+		})
+	}
+}
+
+func (app *StakerApp) waitForStakingTxInputSpend(
+	stakingTxHash chainhash.Hash,
+	spendEvent *notifier.SpendEvent,
+	sub *notificationSubscription,
+) {
+	select {
+	case spend, ok := <-spendEvent.Spend:
+		if !ok || *spend.SpenderTxHash == stakingTxHash {
+			return
+		}
+
+		// subscription is cancelled by event handler, together with other
+		// subscriptions of staking transaction
+		utils.PushOrQuit[*stakingTxInputConflictEvent](
+			app.stakingTxInputConflictEvChan,
+			&stakingTxInputConflictEvent{
+				stakingTxHash:     stakingTxHash,
+				conflictingTxHash: *spend.SpenderTxHash,
+				outpoint:          *spend.SpentOutPoint,
+			},
+			app.quit,
+		)
+
+	case <-sub.cancelled:
+		return
+
+	case <-app.quit:
+		return
+	}
+}
@@ -0,0 +1,56 @@
+package staker
+
+import (
+	"github.com/babylonchain/btc-staker/walletcontroller"
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// StakerKeys are public keys and addresses controlled by the daemon. It never
+// contains private key material.
+type StakerKeys struct {
+	BabylonKeyName string
+	BabylonPubKey  *secp256k1.PubKey
+	BabylonAddress string
+	// WalletDisabled is true if daemon runs without btc wallet, in which case
+	// wallet fields are not set
+	WalletDisabled  bool
+	WalletLocked    bool
+	WalletAddresses []walletcontroller.WalletAddress
+}
+
+// Keys returns public keys and addresses of the babylon key and btc wallet used
+// by the daemon. Only public wallet data is queried, so wallet does not need to
+// be unlocked.
+func (app *StakerApp) Keys() (*StakerKeys, error) {
+	keys := &StakerKeys{
+		BabylonKeyName: app.config.BabylonConfig.Key,
+		BabylonPubKey:  app.babylonClient.GetPubKey(),
+		BabylonAddress: sdk.MustBech32ifyAddressBytes(
+			app.config.BabylonConfig.AccountPrefix,
+			app.babylonClient.GetKeyAddress(),
+		),
+	}
+
+	if err := app.checkWalletEnabled(); err != nil {
+		keys.WalletDisabled = true
+		return keys, nil
+	}
+
+	locked, err := app.wc.WalletLocked()
+
+	if err != nil {
+		return nil, err
+	}
+
+	addresses, err := app.wc.ListAddresses()
+
+	if err != nil {
+		return nil, err
+	}
+
+	keys.WalletLocked = locked
+	keys.WalletAddresses = addresses
+
+	return keys, nil
+}
@@ -0,0 +1,238 @@
+package staker
+
+import (
+	"sort"
+	"time"
+
+	"github.com/babylonchain/btc-staker/proto"
+	"github.com/babylonchain/btc-staker/stakerdb"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+)
+
+// LatencyInterval is measured part of staking transaction lifecycle
+type LatencyInterval string
+
+const (
+	// staking transaction sent to btc until block including it was mined
+	LatencyBroadcastToFirstConfirmation LatencyInterval = "broadcast_to_first_confirmation"
+	// staking transaction sent to btc until it reached required depth
+	LatencyBroadcastToRequiredDepth LatencyInterval = "broadcast_to_required_depth"
+	// staking transaction reached required depth until delegation was included
+	// on babylon
+	LatencyConfirmationToBabylonInclusion LatencyInterval = "confirmation_to_babylon_inclusion"
+	// delegation with unbonding request included on babylon until covenant
+	// unbonding signatures were received
+	LatencyUnbondingRequestToSignatures LatencyInterval = "unbonding_request_to_signatures"
+	// unbonding transaction could be sent, i.e signatures were received and staker
+	// requested unbonding, until unbonding transaction reached required depth
+	LatencySignaturesToUnbondingConfirmation LatencyInterval = "signatures_to_unbonding_confirmation"
+)
+
+// LatencyIntervals lists all measured intervals in lifecycle order
+var LatencyIntervals = []LatencyInterval{
+	LatencyBroadcastToFirstConfirmation,
+	LatencyBroadcastToRequiredDepth,
+	LatencyConfirmationToBabylonInclusion,
+	LatencyUnbondingRequestToSignatures,
+	LatencySignaturesToUnbondingConfirmation,
+}
+
+type latencySample struct {
+	interval LatencyInterval
+	duration time.Duration
+	// time at which interval ended, and state transition which ended it
+	endedAt  time.Time
+	endState proto.TransactionState
+}
+
+// transactionLatencies returns lengths of intervals of transaction lifecycle which
+// are completed. Intervals with unknown start or end are skipped, e.g. of
+// transitions made before state history was tracked. Watched transactions were
+// not broadcast by staker, so their broadcast intervals are not measured.
+func transactionLatencies(tx *stakerdb.StoredTransaction) []latencySample {
+	var samples []latencySample
+
+	add := func(interval LatencyInterval, start, end time.Time, endState proto.TransactionState) {
+		// block timestamps may be slightly before local time of broadcast
+		if start.IsZero() || end.IsZero() || end.Before(start) {
+			return
+		}
+
+		samples = append(samples, latencySample{
+			interval: interval,
+			duration: end.Sub(start),
+			endedAt:  end,
+			endState: endState,
+		})
+	}
+
+	sentToBtc := stateEnteredAt(tx, proto.TransactionState_SENT_TO_BTC)
+	confirmed := stateEnteredAt(tx, proto.TransactionState_CONFIRMED_ON_BTC)
+	sentToBabylon := stateEnteredAt(tx, proto.TransactionState_SENT_TO_BABYLON)
+	active := stateEnteredAt(tx, proto.TransactionState_DELEGATION_ACTIVE)
+
+	if !tx.Watched {
+		if tx.StakingTxConfirmationInfo != nil {
+			add(
+				LatencyBroadcastToFirstConfirmation,
+				sentToBtc,
+				tx.StakingTxConfirmationInfo.BlockTime,
+				proto.TransactionState_CONFIRMED_ON_BTC,
+			)
+		}
+
+		add(LatencyBroadcastToRequiredDepth, sentToBtc, confirmed, proto.TransactionState_CONFIRMED_ON_BTC)
+	}
+
+	add(LatencyConfirmationToBabylonInclusion, confirmed, sentToBabylon, proto.TransactionState_SENT_TO_BABYLON)
+	add(LatencyUnbondingRequestToSignatures, sentToBabylon, active, proto.TransactionState_DELEGATION_ACTIVE)
+
+	if ud := tx.UnbondingTxData; ud != nil && !ud.UnbondingTxSentAt.IsZero() {
+		// unbonding is only sent on request, after signatures were received
+		unbondingStart := active
+		if ud.UnbondingTxSentAt.After(unbondingStart) {
+			unbondingStart = ud.UnbondingTxSentAt
+		}
+
+		add(
+			LatencySignaturesToUnbondingConfirmation,
+			unbondingStart,
+			stateEnteredAt(tx, proto.TransactionState_UNBONDING_CONFIRMED_ON_BTC),
+			proto.TransactionState_UNBONDING_CONFIRMED_ON_BTC,
+		)
+	}
+
+	return samples
+}
+
+// latencyMetrics are Prometheus histograms of lengths of transaction lifecycle
+// intervals, labeled by interval
+type latencyMetrics struct {
+	histograms *prometheus.HistogramVec
+}
+
+func newLatencyMetrics(buckets []time.Duration) *latencyMetrics {
+	bounds := make([]float64, len(buckets))
+
+	for i, b := range buckets {
+		bounds[i] = b.Seconds()
+	}
+
+	return &latencyMetrics{
+		histograms: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "stakerd",
+			Name:      "transaction_latency_seconds",
+			Help:      "Time between transitions of staking transaction lifecycle",
+			Buckets:   bounds,
+		}, []string{"interval"}),
+	}
+}
+
+func (m *latencyMetrics) observe(sample latencySample) {
+	m.histograms.WithLabelValues(string(sample.interval)).Observe(sample.duration.Seconds())
+}
+
+// MetricsCollectors returns Prometheus collectors of staker metrics
+func (app *StakerApp) MetricsCollectors() []prometheus.Collector {
+	return []prometheus.Collector{
+		app.latencyMetrics.histograms,
+		app.reconciliationMetrics.checkedTransactions,
+		app.reconciliationMetrics.discrepancies,
+	}
+}
+
+// observeLatencies records lengths of intervals ended by transition of the
+// transaction to given state
+func (app *StakerApp) observeLatencies(txHash *chainhash.Hash, state proto.TransactionState) {
+	tx, err := app.txTracker.GetTransaction(txHash)
+
+	if err != nil {
+		app.logger.WithFields(logrus.Fields{
+			"stakingTxHash": txHash,
+			"err":           err,
+		}).Warn("Failed to get transaction to record its latencies")
+		return
+	}
+
+	for _, sample := range transactionLatencies(tx) {
+		if sample.endState == state {
+			app.latencyMetrics.observe(sample)
+		}
+	}
+}
+
+// LatencyStats are percentiles of lengths of single lifecycle interval
+type LatencyStats struct {
+	Interval LatencyInterval
+	// number of intervals ended within requested time range, percentiles are
+	// zero if there are none
+	Count int
+	P50   time.Duration
+	P95   time.Duration
+}
+
+// percentile returns nearest-rank percentile of sorted durations
+func percentile(sorted []time.Duration, p int) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+
+	rank := (p*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+
+	return sorted[rank-1]
+}
+
+// LatencyReport computes percentiles of lengths of lifecycle intervals which
+// ended within [from, to) from stored state history. Zero from or to leaves the
+// range open on that side.
+func (app *StakerApp) LatencyReport(from, to time.Time) ([]LatencyStats, error) {
+	durations := make(map[LatencyInterval][]time.Duration)
+
+	reset := func() {
+		durations = make(map[LatencyInterval][]time.Duration)
+	}
+
+	err := app.txTracker.ScanTrackedTransactions(func(tx *stakerdb.StoredTransaction) error {
+		for _, sample := range transactionLatencies(tx) {
+			if !from.IsZero() && sample.endedAt.Before(from) {
+				continue
+			}
+
+			if !to.IsZero() && !sample.endedAt.Before(to) {
+				continue
+			}
+
+			durations[sample.interval] = append(durations[sample.interval], sample.duration)
+		}
+
+		return nil
+	}, reset)
+
+	if err != nil {
+		return nil, err
+	}
+
+	stats := make([]LatencyStats, len(LatencyIntervals))
+
+	for i, interval := range LatencyIntervals {
+		d := durations[interval]
+
+		sort.Slice(d, func(a, b int) bool {
+			return d[a] < d[b]
+		})
+
+		stats[i] = LatencyStats{
+			Interval: interval,
+			Count:    len(d),
+			P50:      percentile(d, 50),
+			P95:      percentile(d, 95),
+		}
+	}
+
+	return stats, nil
+}
@@ -0,0 +1,97 @@
+package staker
+
+import (
+	"testing"
+	"time"
+
+	"github.com/babylonchain/btc-staker/proto"
+	"github.com/babylonchain/btc-staker/stakerdb"
+	"github.com/stretchr/testify/require"
+)
+
+func latenciesByInterval(tx *stakerdb.StoredTransaction) map[LatencyInterval]latencySample {
+	samples := make(map[LatencyInterval]latencySample)
+
+	for _, s := range transactionLatencies(tx) {
+		samples[s.interval] = s
+	}
+
+	return samples
+}
+
+func TestTransactionLatenciesOfFullLifecycle(t *testing.T) {
+	start := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) time.Time { return start.Add(d) }
+
+	tx := &stakerdb.StoredTransaction{
+		State: proto.TransactionState_UNBONDING_CONFIRMED_ON_BTC,
+		StateHistory: []stakerdb.StateTransition{
+			{State: proto.TransactionState_SENT_TO_BTC, ChangedAt: at(0)},
+			{State: proto.TransactionState_CONFIRMED_ON_BTC, ChangedAt: at(time.Hour)},
+			{State: proto.TransactionState_SENT_TO_BABYLON, ChangedAt: at(70 * time.Minute)},
+			{State: proto.TransactionState_DELEGATION_ACTIVE, ChangedAt: at(3 * time.Hour)},
+			{State: proto.TransactionState_UNBONDING_CONFIRMED_ON_BTC, ChangedAt: at(30 * time.Hour)},
+		},
+		StakingTxConfirmationInfo: &stakerdb.BtcConfirmationInfo{
+			Height:    100,
+			BlockTime: at(12 * time.Minute),
+		},
+		UnbondingTxData: &stakerdb.UnbondingStoreData{
+			UnbondingTxSentAt: at(24 * time.Hour),
+		},
+	}
+
+	samples := latenciesByInterval(tx)
+	require.Len(t, samples, len(LatencyIntervals))
+
+	expected := map[LatencyInterval]time.Duration{
+		LatencyBroadcastToFirstConfirmation:      12 * time.Minute,
+		LatencyBroadcastToRequiredDepth:          time.Hour,
+		LatencyConfirmationToBabylonInclusion:    10 * time.Minute,
+		LatencyUnbondingRequestToSignatures:      110 * time.Minute,
+		LatencySignaturesToUnbondingConfirmation: 6 * time.Hour,
+	}
+
+	for interval, duration := range expected {
+		require.Equal(t, duration, samples[interval].duration, interval)
+	}
+
+	require.Equal(t, proto.TransactionState_CONFIRMED_ON_BTC, samples[LatencyBroadcastToFirstConfirmation].endState)
+	require.Equal(t, at(30*time.Hour), samples[LatencySignaturesToUnbondingConfirmation].endedAt)
+}
+
+func TestTransactionLatenciesSkipsUnknownIntervals(t *testing.T) {
+	start := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	// watched transaction was not broadcast by staker, and its unbonding was not
+	// requested yet
+	tx := &stakerdb.StoredTransaction{
+		Watched: true,
+		State:   proto.TransactionState_DELEGATION_ACTIVE,
+		StateHistory: []stakerdb.StateTransition{
+			{State: proto.TransactionState_SENT_TO_BABYLON, ChangedAt: start},
+			{State: proto.TransactionState_DELEGATION_ACTIVE, ChangedAt: start.Add(time.Hour)},
+		},
+		StakingTxConfirmationInfo: &stakerdb.BtcConfirmationInfo{
+			Height:    100,
+			BlockTime: start.Add(-time.Hour),
+		},
+	}
+
+	samples := latenciesByInterval(tx)
+	require.Len(t, samples, 1)
+	require.Equal(t, time.Hour, samples[LatencyUnbondingRequestToSignatures].duration)
+}
+
+func TestPercentile(t *testing.T) {
+	require.Equal(t, time.Duration(0), percentile(nil, 50))
+
+	sorted := make([]time.Duration, 20)
+	for i := range sorted {
+		sorted[i] = time.Duration(i+1) * time.Minute
+	}
+
+	require.Equal(t, 10*time.Minute, percentile(sorted, 50))
+	require.Equal(t, 19*time.Minute, percentile(sorted, 95))
+	require.Equal(t, 5*time.Minute, percentile(sorted[4:5], 95))
+}
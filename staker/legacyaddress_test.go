@@ -0,0 +1,121 @@
+package staker
+
+import (
+	"context"
+	"testing"
+
+	staking "github.com/babylonchain/babylon/btcstaking"
+	cl "github.com/babylonchain/btc-staker/babylonclient"
+	"github.com/babylonchain/btc-staker/stakerdb"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/stretchr/testify/require"
+)
+
+// keyWallet holds single staker key and records sent transactions
+type keyWallet struct {
+	*testWallet
+	privKey *btcec.PrivateKey
+	sent    []*wire.MsgTx
+}
+
+func (w *keyWallet) UnlockWallet(_ context.Context, _ int64) error {
+	return nil
+}
+
+func (w *keyWallet) DumpPrivateKey(_ context.Context, _ btcutil.Address) (*btcec.PrivateKey, error) {
+	// session zeroes the key on close
+	privKey, _ := btcec.PrivKeyFromBytes(w.privKey.Serialize())
+	return privKey, nil
+}
+
+func (w *keyWallet) SendRawTransaction(tx *wire.MsgTx, _ bool) (*chainhash.Hash, error) {
+	w.sent = append(w.sent, tx)
+	txHash := tx.TxHash()
+	return &txHash, nil
+}
+
+func (w *keyWallet) SetTxLabel(_ *chainhash.Hash, _ string) error {
+	return nil
+}
+
+// addSpendableTransaction adds confirmed staking transaction which staking output
+// is spendable by given staker key
+func (d *testStakerDeps) addSpendableTransaction(
+	t *testing.T,
+	stakerKey *btcec.PrivateKey,
+	stakerAddress btcutil.Address,
+) *chainhash.Hash {
+	net := &d.config.ActiveNetParams
+
+	covenantPks := []*btcec.PublicKey{genPubKey(t), genPubKey(t)}
+	d.babylon.params = &cl.StakingParams{
+		ConfirmationTimeBlocks:    2,
+		FinalizationTimeoutBlocks: 5,
+		CovenantPks:               covenantPks,
+		CovenantQuruomThreshold:   1,
+	}
+
+	fpPks := []*btcec.PublicKey{genPubKey(t)}
+	stakingInfo, err := staking.BuildStakingInfo(stakerKey.PubKey(), fpPks, covenantPks, 1, 100, 100000, net)
+	require.NoError(t, err)
+
+	stakingTx := wire.NewMsgTx(2)
+	stakingTx.AddTxIn(wire.NewTxIn(&wire.OutPoint{}, nil, nil))
+	stakingTx.AddTxOut(stakingInfo.StakingOutput)
+
+	err = d.tracker.AddTransaction(
+		stakingTx,
+		0,
+		100,
+		fpPks,
+		&stakerdb.ProofOfPossession{
+			BabylonSigOverBtcPk:  []byte{1},
+			BtcSigOverBabylonSig: []byte{1},
+		},
+		stakerAddress,
+		stakerdb.AddTransactionOpts{},
+	)
+	require.NoError(t, err)
+
+	stakingTxHash := stakingTx.TxHash()
+	require.NoError(t, d.tracker.SetTxConfirmed(&stakingTxHash, &chainhash.Hash{}, 10, testClockStart))
+
+	return &stakingTxHash
+}
+
+func TestSpendStakeOfLegacyStakerAddress(t *testing.T) {
+	deps := newTestStakerDeps(t)
+
+	stakerKey := genPrivKey(t)
+	wallet := &keyWallet{testWallet: deps.wallet, privKey: stakerKey}
+	deps.wc = wallet
+
+	// p2pkh addresses are no longer accepted for new stakes, but stakes created
+	// with them before must remain spendable
+	stakerAddress, err := btcutil.NewAddressPubKeyHash(
+		btcutil.Hash160(stakerKey.PubKey().SerializeCompressed()),
+		&deps.config.ActiveNetParams,
+	)
+	require.NoError(t, err)
+
+	stakingTxHash := *deps.addSpendableTransaction(t, stakerKey, stakerAddress)
+
+	app := deps.newApp(t)
+
+	spendTxHash, _, err := app.SpendStake(&stakingTxHash, nil, nil)
+	require.NoError(t, err)
+
+	require.Len(t, wallet.sent, 1)
+	spendTx := wallet.sent[0]
+	require.Equal(t, *spendTxHash, spendTx.TxHash())
+
+	// funds are sent back to the legacy staker address
+	stakerScript, err := txscript.PayToAddrScript(stakerAddress)
+	require.NoError(t, err)
+	require.Equal(t, stakerScript, spendTx.TxOut[0].PkScript)
+	require.Equal(t, stakingTxHash, spendTx.TxIn[0].PreviousOutPoint.Hash)
+}
@@ -0,0 +1,61 @@
+package staker
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrStartCancelled is returned by Start when Stop is called before start finishes
+var ErrStartCancelled = errors.New("staker app stopped before finishing start")
+
+// LifecycleState is the state of staker app lifecycle. App moves only forward
+// through the states, i.e. stopped app cannot be started again.
+type LifecycleState int
+
+const (
+	// app is created, but Start was not called yet
+	LifecycleCreated LifecycleState = iota
+	// Start was called and did not return yet, or returned error. App must be
+	// stopped in both cases.
+	LifecycleStarting
+	// Start returned successfully
+	LifecycleRunning
+	// Stop was called and did not return yet
+	LifecycleStopping
+	// Stop returned, all background routines exited
+	LifecycleStopped
+)
+
+func (s LifecycleState) String() string {
+	switch s {
+	case LifecycleCreated:
+		return "created"
+	case LifecycleStarting:
+		return "starting"
+	case LifecycleRunning:
+		return "running"
+	case LifecycleStopping:
+		return "stopping"
+	case LifecycleStopped:
+		return "stopped"
+	default:
+		return fmt.Sprintf("unknown(%d)", int(s))
+	}
+}
+
+// ErrInvalidLifecycleState is returned by Start if app is not in created state
+type ErrInvalidLifecycleState struct {
+	State LifecycleState
+}
+
+func (e *ErrInvalidLifecycleState) Error() string {
+	return fmt.Sprintf("cannot start staker app in %s state", e.State)
+}
+
+// LifecycleState returns current state of the app lifecycle
+func (app *StakerApp) LifecycleState() LifecycleState {
+	app.lifecycleMu.Lock()
+	defer app.lifecycleMu.Unlock()
+
+	return app.lifecycleState
+}
@@ -0,0 +1,64 @@
+package staker
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+)
+
+func requireStartRejected(t *testing.T, app *StakerApp, state LifecycleState) {
+	var stateErr *ErrInvalidLifecycleState
+	require.ErrorAs(t, app.Start(), &stateErr)
+	require.Equal(t, state, stateErr.State)
+}
+
+func TestLifecycleStopRunningApp(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	deps := newTestStakerDeps(t)
+	app := deps.newApp(t)
+
+	require.NoError(t, app.Start())
+	require.Equal(t, LifecycleRunning, app.LifecycleState())
+	requireStartRejected(t, app, LifecycleRunning)
+
+	require.NoError(t, app.Stop())
+	require.Equal(t, LifecycleStopped, app.LifecycleState())
+	requireStartRejected(t, app, LifecycleStopped)
+
+	// stopping again is no-op
+	require.NoError(t, app.Stop())
+}
+
+func TestLifecycleStopCancelsStart(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	deps := newTestStakerDeps(t)
+	deps.notifier.noBestBlock = true
+	app := deps.newApp(t)
+
+	startErr := make(chan error, 1)
+	go func() {
+		startErr <- app.Start()
+	}()
+
+	require.Eventually(t, func() bool {
+		return app.LifecycleState() == LifecycleStarting
+	}, time.Second, time.Millisecond)
+
+	require.NoError(t, app.Stop())
+	require.ErrorIs(t, <-startErr, ErrStartCancelled)
+	require.Equal(t, LifecycleStopped, app.LifecycleState())
+	requireStartRejected(t, app, LifecycleStopped)
+}
+
+func TestLifecycleStopBeforeStart(t *testing.T) {
+	deps := newTestStakerDeps(t)
+	app := deps.newApp(t)
+
+	require.NoError(t, app.Stop())
+	require.Equal(t, LifecycleStopped, app.LifecycleState())
+	requireStartRejected(t, app, LifecycleStopped)
+}
@@ -0,0 +1,89 @@
+package staker
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	scfg "github.com/babylonchain/btc-staker/stakercfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// variables which can be used in delegation memo
+	memoStakingTxHashVar = "{stakingTxHash}"
+	memoStakerAddressVar = "{stakerAddress}"
+)
+
+// ErrMemoTooLong is returned when delegation memo exceeds max memo size of babylon
+// chain and staker is configured to reject such memos
+var ErrMemoTooLong = errors.New("delegation memo is too long")
+
+func renderDelegationMemo(template string, stakingTxHash *chainhash.Hash, stakerAddress string) string {
+	return strings.NewReplacer(
+		memoStakingTxHashVar, stakingTxHash.String(),
+		memoStakerAddressVar, stakerAddress,
+	).Replace(template)
+}
+
+// truncateMemo cuts memo to at most maxLen bytes, without splitting multi-byte
+// characters
+func truncateMemo(memo string, maxLen int) string {
+	if len(memo) <= maxLen {
+		return memo
+	}
+
+	memo = memo[:maxLen]
+
+	for len(memo) > 0 && !utf8.ValidString(memo) {
+		memo = memo[:len(memo)-1]
+	}
+
+	return memo
+}
+
+// delegationMemo renders memo of babylon transaction for given staking transaction.
+// Requested memo takes precedence over memo from config. Memo is checked against
+// max memo size of babylon chain, too long memo is truncated or rejected depending
+// on config.
+func (app *StakerApp) delegationMemo(
+	requestedMemo string,
+	stakingTxHash *chainhash.Hash,
+	stakerAddress string,
+) (string, error) {
+	template := app.config.StakerConfig.DelegationMemo
+
+	if requestedMemo != "" {
+		template = requestedMemo
+	}
+
+	if template == "" {
+		return "", nil
+	}
+
+	memo := renderDelegationMemo(template, stakingTxHash, stakerAddress)
+
+	maxLen, err := app.babylonClient.MaxMemoCharacters()
+
+	if err != nil {
+		return "", fmt.Errorf("failed to retrieve max memo size of babylon chain: %w", err)
+	}
+
+	if uint64(len(memo)) <= maxLen {
+		return memo, nil
+	}
+
+	if app.config.StakerConfig.MemoTooLongAction != scfg.MemoTooLongTruncate {
+		return "", fmt.Errorf("%w: memo has %d bytes, babylon accepts at most %d", ErrMemoTooLong, len(memo), maxLen)
+	}
+
+	app.logger.WithFields(logrus.Fields{
+		"stakingTxHash": stakingTxHash,
+		"memoLength":    len(memo),
+		"maxMemoLength": maxLen,
+	}).Warn("Delegation memo is too long. Memo truncated")
+
+	return truncateMemo(memo, int(maxLen)), nil
+}
@@ -0,0 +1,39 @@
+package staker
+
+import (
+	"testing"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderDelegationMemo(t *testing.T) {
+	hash := chainhash.Hash{1}
+
+	memo := renderDelegationMemo("stake {stakingTxHash} from {stakerAddress}", &hash, "bc1qaddr")
+	require.Equal(t, "stake "+hash.String()+" from bc1qaddr", memo)
+
+	require.Equal(t, "no variables", renderDelegationMemo("no variables", &hash, "bc1qaddr"))
+}
+
+func TestTruncateMemo(t *testing.T) {
+	tests := []struct {
+		name     string
+		memo     string
+		maxLen   int
+		expected string
+	}{
+		{"short memo is not changed", "memo", 10, "memo"},
+		{"exact length is not changed", "memo", 4, "memo"},
+		{"long memo is truncated", "long memo", 4, "long"},
+		// "ż" takes 2 bytes
+		{"multi-byte character is not split", "abż", 3, "ab"},
+		{"multi-byte character fits", "abż", 4, "abż"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expected, truncateMemo(tt.memo, tt.maxLen))
+		})
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"net"
 
 	"github.com/babylonchain/btc-staker/types"
+	"github.com/babylonchain/btc-staker/utils"
 
 	scfg "github.com/babylonchain/btc-staker/stakercfg"
 	"github.com/btcsuite/btcd/chaincfg"
@@ -15,10 +16,25 @@ import (
 	"github.com/lightningnetwork/lnd/chainntnfs/bitcoindnotify"
 	"github.com/lightningnetwork/lnd/chainntnfs/btcdnotify"
 	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/sirupsen/logrus"
 )
 
 type NodeBackend struct {
 	chainntnfs.ChainNotifier
+	// nil if bitcoind rpc is not connected through proxy
+	forwarder *utils.ProxyForwarder
+}
+
+func (nb *NodeBackend) Stop() error {
+	err := nb.ChainNotifier.Stop()
+
+	if nb.forwarder != nil {
+		if fwdErr := nb.forwarder.Close(); fwdErr != nil && err == nil {
+			err = fwdErr
+		}
+	}
+
+	return err
 }
 
 // TODO  This should be moved to a more appropriate place, most probably to config
@@ -35,18 +51,53 @@ func NewNodeBackend(
 	cfg *scfg.BtcNodeBackendConfig,
 	params *chaincfg.Params,
 	hintCache *channeldb.HeightHintCache,
+	// nil if node is connected directly
+	proxy *scfg.ProxyConfig,
+	logger *logrus.Logger,
 ) (*NodeBackend, error) {
 	switch cfg.ActiveNodeBackend {
 	case types.BitcoindNodeBackend:
+		rpcHost, err := scfg.NormalizeRpcHost(
+			cfg.Bitcoind.RPCHost, scfg.DefaultNodeRpcPort(types.BitcoindNodeBackend, params.Name),
+		)
+
+		if err != nil {
+			return nil, fmt.Errorf("invalid bitcoind rpc host: %w", err)
+		}
+
 		bitcoindCfg := &chain.BitcoindConfig{
 			ChainParams:        params,
-			Host:               cfg.Bitcoind.RPCHost,
+			Host:               rpcHost,
 			User:               cfg.Bitcoind.RPCUser,
 			Pass:               cfg.Bitcoind.RPCPass,
-			Dialer:             BuildDialer(cfg.Bitcoind.RPCHost),
+			Dialer:             BuildDialer(rpcHost),
 			PrunedModeMaxPeers: cfg.Bitcoind.PrunedNodeMaxPeers,
 		}
 
+		var forwarder *utils.ProxyForwarder
+
+		if proxy != nil {
+			dialer := proxy.Dialer()
+
+			// bitcoind connection creates its own rpc client, which can't be
+			// configured with proxy, so it connects to local forwarder instead
+			fwd, err := dialer.Forward(rpcHost, func(err error) {
+				logger.WithFields(logrus.Fields{
+					"err": err,
+				}).Error("Failed to connect to bitcoind through proxy")
+			})
+
+			if err != nil {
+				return nil, err
+			}
+
+			forwarder = fwd
+			bitcoindCfg.Host = forwarder.Addr()
+			bitcoindCfg.Dialer = func(string) (net.Conn, error) {
+				return dialer.Dial("tcp", rpcHost)
+			}
+		}
+
 		if cfg.Bitcoind.RPCPolling {
 			bitcoindCfg.PollingConfig = &chain.PollingConfig{
 				BlockPollingInterval:    cfg.Bitcoind.BlockPollingInterval,
@@ -63,12 +114,20 @@ func NewNodeBackend(
 			}
 		}
 
+		closeForwarder := func() {
+			if forwarder != nil {
+				_ = forwarder.Close()
+			}
+		}
+
 		bitcoindConn, err := chain.NewBitcoindConn(bitcoindCfg)
 		if err != nil {
+			closeForwarder()
 			return nil, err
 		}
 
 		if err := bitcoindConn.Start(); err != nil {
+			closeForwarder()
 			return nil, fmt.Errorf("unable to connect to "+
 				"bitcoind: %v", err)
 		}
@@ -80,12 +139,19 @@ func NewNodeBackend(
 
 		return &NodeBackend{
 			ChainNotifier: chainNotifier,
+			forwarder:     forwarder,
 		}, nil
 
 	case types.BtcdNodeBackend:
 		btcdUser := cfg.Btcd.RPCUser
 		btcdPass := cfg.Btcd.RPCPass
-		btcdHost := cfg.Btcd.RPCHost
+		btcdHost, err := scfg.NormalizeRpcHost(
+			cfg.Btcd.RPCHost, scfg.DefaultNodeRpcPort(types.BtcdNodeBackend, params.Name),
+		)
+
+		if err != nil {
+			return nil, fmt.Errorf("invalid btcd rpc host: %w", err)
+		}
 
 		cert, err := scfg.ReadCertFile(cfg.Btcd.RawRPCCert, cfg.Btcd.RPCCert)
 
@@ -104,6 +170,10 @@ func NewNodeBackend(
 			DisableAutoReconnect: false,
 		}
 
+		if proxy != nil {
+			proxy.ApplyToRpcClient(rpcConfig)
+		}
+
 		chainNotifier, err := btcdnotify.New(
 			rpcConfig, params, hintCache,
 			hintCache, blockcache.NewBlockCache(cfg.Btcd.BlockCacheSize),
@@ -0,0 +1,225 @@
+package staker
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/sirupsen/logrus"
+)
+
+// NotificationPurpose describes what staker waits for with chain notifier
+// subscription
+type NotificationPurpose string
+
+const (
+	StakingTxConfirmationPurpose   NotificationPurpose = "staking_confirmation"
+	UnbondingTxConfirmationPurpose NotificationPurpose = "unbonding_confirmation"
+	SpendTxConfirmationPurpose     NotificationPurpose = "spend_confirmation"
+	UnbondingOutputSpendPurpose    NotificationPurpose = "unbonding_output_spend"
+	StakingInputsSpendPurpose      NotificationPurpose = "staking_inputs_spend"
+)
+
+// NotificationSubscription is chain notifier subscription owned by staker
+type NotificationSubscription struct {
+	Purpose NotificationPurpose
+	// hash of transaction which confirmation or spend is awaited
+	TxHash        chainhash.Hash
+	StakingTxHash chainhash.Hash
+	RegisteredAt  time.Time
+}
+
+type notificationKey struct {
+	txHash  chainhash.Hash
+	purpose NotificationPurpose
+}
+
+type notificationSubscription struct {
+	key  notificationKey
+	info NotificationSubscription
+	// cancels subscription in chain notifier, nil until subscription is
+	// registered
+	cancelNtfn func()
+	// closed when subscription is cancelled, so that its waiter can exit
+	cancelled chan struct{}
+}
+
+// notificationRegistry owns chain notifier subscriptions of staker. There is at
+// most one subscription per transaction and purpose, so that the same transaction
+// is not registered twice e.g by startup checks and by their retries, and
+// subscriptions which are no longer needed can be cancelled once staking
+// transaction reaches terminal state.
+//
+// Subscription is first reserved, then registered in chain notifier without
+// holding the lock, and finally attached to its reservation. Reservation which
+// is cancelled before it is attached cancels the notifier subscription right away.
+type notificationRegistry struct {
+	mu            sync.Mutex
+	subscriptions map[notificationKey]*notificationSubscription
+}
+
+func newNotificationRegistry() *notificationRegistry {
+	return &notificationRegistry{
+		subscriptions: make(map[notificationKey]*notificationSubscription),
+	}
+}
+
+// reserve reserves subscription of given purpose for transaction. Returns nil if
+// such subscription is already reserved or registered.
+func (r *notificationRegistry) reserve(
+	txHash chainhash.Hash,
+	purpose NotificationPurpose,
+	stakingTxHash chainhash.Hash,
+	now time.Time,
+) *notificationSubscription {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := notificationKey{txHash: txHash, purpose: purpose}
+
+	if _, ok := r.subscriptions[key]; ok {
+		return nil
+	}
+
+	sub := &notificationSubscription{
+		key: key,
+		info: NotificationSubscription{
+			Purpose:       purpose,
+			TxHash:        txHash,
+			StakingTxHash: stakingTxHash,
+			RegisteredAt:  now,
+		},
+		cancelled: make(chan struct{}),
+	}
+
+	r.subscriptions[key] = sub
+	return sub
+}
+
+// attach attaches cancel function of registered notifier subscription to its
+// reservation. Returns false if reservation was already cancelled, in which case
+// notifier subscription is cancelled immediately.
+func (r *notificationRegistry) attach(sub *notificationSubscription, cancelNtfn func()) bool {
+	r.mu.Lock()
+
+	if r.subscriptions[sub.key] != sub {
+		r.mu.Unlock()
+		cancelNtfn()
+		return false
+	}
+
+	sub.cancelNtfn = cancelNtfn
+	r.mu.Unlock()
+
+	return true
+}
+
+// remove removes subscription and cancels it in chain notifier. It is safe to call
+// it multiple times, and after subscription was cancelled by registry.
+func (r *notificationRegistry) remove(sub *notificationSubscription) {
+	r.mu.Lock()
+
+	if r.subscriptions[sub.key] != sub {
+		r.mu.Unlock()
+		return
+	}
+
+	delete(r.subscriptions, sub.key)
+	r.mu.Unlock()
+
+	sub.cancel()
+}
+
+// cancel cancels subscription of given purpose for transaction. Returns false if
+// there is no such subscription.
+func (r *notificationRegistry) cancel(txHash chainhash.Hash, purpose NotificationPurpose) bool {
+	r.mu.Lock()
+
+	key := notificationKey{txHash: txHash, purpose: purpose}
+	sub, ok := r.subscriptions[key]
+
+	if !ok {
+		r.mu.Unlock()
+		return false
+	}
+
+	delete(r.subscriptions, key)
+	r.mu.Unlock()
+
+	sub.cancel()
+	return true
+}
+
+// cancelStakingTx cancels all subscriptions related to staking transaction.
+// Returns number of cancelled subscriptions.
+func (r *notificationRegistry) cancelStakingTx(stakingTxHash chainhash.Hash) int {
+	r.mu.Lock()
+
+	var toCancel []*notificationSubscription
+
+	for key, sub := range r.subscriptions {
+		if sub.info.StakingTxHash == stakingTxHash {
+			delete(r.subscriptions, key)
+			toCancel = append(toCancel, sub)
+		}
+	}
+
+	r.mu.Unlock()
+
+	for _, sub := range toCancel {
+		sub.cancel()
+	}
+
+	return len(toCancel)
+}
+
+// contains returns true if subscription of given purpose for transaction is
+// reserved or registered
+func (r *notificationRegistry) contains(txHash chainhash.Hash, purpose NotificationPurpose) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	_, ok := r.subscriptions[notificationKey{txHash: txHash, purpose: purpose}]
+	return ok
+}
+
+// list returns copy of all subscriptions, ordered by registration time
+func (r *notificationRegistry) list() []NotificationSubscription {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	subscriptions := make([]NotificationSubscription, 0, len(r.subscriptions))
+
+	for _, sub := range r.subscriptions {
+		subscriptions = append(subscriptions, sub.info)
+	}
+
+	sort.Slice(subscriptions, func(i, j int) bool {
+		return subscriptions[i].RegisteredAt.Before(subscriptions[j].RegisteredAt)
+	})
+
+	return subscriptions
+}
+
+// cancel must be called only by the caller which removed subscription from
+// registry, so that it is executed once
+func (s *notificationSubscription) cancel() {
+	close(s.cancelled)
+
+	if s.cancelNtfn != nil {
+		s.cancelNtfn()
+	}
+}
+
+// cancelNotifications cancels chain notifier subscriptions of staking transaction
+// which are no longer needed, as transaction reached terminal state or stopped
+// being tracked
+func (app *StakerApp) cancelNotifications(stakingTxHash *chainhash.Hash) {
+	if n := app.notifications.cancelStakingTx(*stakingTxHash); n > 0 {
+		app.logger.WithFields(logrus.Fields{
+			"stakingTxHash":    stakingTxHash,
+			"numSubscriptions": n,
+		}).Debug("Cancelled obsolete chain notifier subscriptions")
+	}
+}
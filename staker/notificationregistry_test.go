@@ -0,0 +1,151 @@
+package staker
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotificationRegistryRejectsDuplicates(t *testing.T) {
+	registry := newNotificationRegistry()
+	txHash := chainhash.HashH([]byte("staking tx"))
+	otherTxHash := chainhash.HashH([]byte("other staking tx"))
+
+	sub := registry.reserve(txHash, StakingTxConfirmationPurpose, txHash, testClockStart)
+	require.NotNil(t, sub)
+	// duplicate registration e.g by startup check retry
+	require.Nil(t, registry.reserve(txHash, StakingTxConfirmationPurpose, txHash, testClockStart))
+	require.NotNil(t, registry.reserve(otherTxHash, StakingTxConfirmationPurpose, otherTxHash, testClockStart))
+	// the same transaction can be watched for different purpose
+	require.NotNil(t, registry.reserve(txHash, UnbondingOutputSpendPurpose, txHash, testClockStart))
+
+	// after confirmation is received, transaction can be registered again
+	registry.remove(sub)
+	require.False(t, registry.contains(txHash, StakingTxConfirmationPurpose))
+	require.NotNil(t, registry.reserve(txHash, StakingTxConfirmationPurpose, txHash, testClockStart))
+}
+
+func TestNotificationRegistryCancelsStakingTxSubscriptions(t *testing.T) {
+	registry := newNotificationRegistry()
+	stakingTxHash := chainhash.HashH([]byte("staking tx"))
+	spendTxHash := chainhash.HashH([]byte("spend tx"))
+	otherTxHash := chainhash.HashH([]byte("other staking tx"))
+
+	var cancelled atomic.Int32
+	cancelNtfn := func() { cancelled.Add(1) }
+
+	spendSub := registry.reserve(spendTxHash, SpendTxConfirmationPurpose, stakingTxHash, testClockStart)
+	require.True(t, registry.attach(spendSub, cancelNtfn))
+	// registration still in progress
+	unbondingSub := registry.reserve(stakingTxHash, UnbondingTxConfirmationPurpose, stakingTxHash, testClockStart)
+	otherSub := registry.reserve(otherTxHash, StakingTxConfirmationPurpose, otherTxHash, testClockStart.Add(time.Second))
+	require.True(t, registry.attach(otherSub, cancelNtfn))
+
+	require.Equal(t, 2, registry.cancelStakingTx(stakingTxHash))
+	require.Equal(t, int32(1), cancelled.Load())
+	require.Len(t, registry.list(), 1)
+	require.Equal(t, otherTxHash, registry.list()[0].TxHash)
+
+	// waiters are notified about cancellation
+	select {
+	case <-spendSub.cancelled:
+	default:
+		t.Fatal("subscription not cancelled")
+	}
+
+	// subscription registered after its reservation was cancelled is cancelled
+	// right away
+	require.False(t, registry.attach(unbondingSub, cancelNtfn))
+	require.Equal(t, int32(2), cancelled.Load())
+
+	// waiter removing already cancelled subscription does not cancel it again
+	registry.remove(spendSub)
+	require.Equal(t, int32(2), cancelled.Load())
+}
+
+func TestNotificationRegistryCancelsSingleSubscription(t *testing.T) {
+	registry := newNotificationRegistry()
+	stakingTxHash := chainhash.HashH([]byte("staking tx"))
+
+	var cancelled atomic.Int32
+	cancelNtfn := func() { cancelled.Add(1) }
+
+	inputsSub := registry.reserve(stakingTxHash, StakingInputsSpendPurpose, stakingTxHash, testClockStart)
+	require.True(t, registry.attach(inputsSub, cancelNtfn))
+	confSub := registry.reserve(stakingTxHash, StakingTxConfirmationPurpose, stakingTxHash, testClockStart)
+	require.True(t, registry.attach(confSub, cancelNtfn))
+
+	// staking transaction confirmed, its inputs no longer need to be watched
+	require.True(t, registry.cancel(stakingTxHash, StakingInputsSpendPurpose))
+	require.False(t, registry.cancel(stakingTxHash, StakingInputsSpendPurpose))
+	require.Equal(t, int32(1), cancelled.Load())
+	require.True(t, registry.contains(stakingTxHash, StakingTxConfirmationPurpose))
+
+	select {
+	case <-inputsSub.cancelled:
+	default:
+		t.Fatal("subscription not cancelled")
+	}
+}
+
+func TestNotificationRegistryConcurrentRegistrations(t *testing.T) {
+	registry := newNotificationRegistry()
+	txHash := chainhash.HashH([]byte("staking tx"))
+
+	const numRegistrations = 50
+
+	var (
+		wg         sync.WaitGroup
+		registered atomic.Int32
+		cancelled  atomic.Int32
+		start      = make(chan struct{})
+	)
+
+	for i := 0; i < numRegistrations; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+
+			sub := registry.reserve(txHash, StakingTxConfirmationPurpose, txHash, testClockStart)
+
+			if sub == nil {
+				return
+			}
+
+			registered.Add(1)
+			registry.attach(sub, func() { cancelled.Add(1) })
+		}()
+	}
+
+	close(start)
+	wg.Wait()
+
+	require.Equal(t, int32(1), registered.Load())
+	require.Len(t, registry.list(), 1)
+
+	// concurrent cancellations by terminal state transition and by waiters cancel
+	// subscription exactly once
+	sub := registry.subscriptions[notificationKey{txHash: txHash, purpose: StakingTxConfirmationPurpose}]
+
+	for i := 0; i < numRegistrations; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			registry.cancelStakingTx(txHash)
+		}()
+		go func() {
+			defer wg.Done()
+			registry.remove(sub)
+		}()
+	}
+
+	wg.Wait()
+
+	require.Equal(t, int32(1), cancelled.Load())
+	require.Empty(t, registry.list())
+}
@@ -0,0 +1,79 @@
+package staker
+
+import (
+	"errors"
+
+	cl "github.com/babylonchain/btc-staker/babylonclient"
+	"github.com/babylonchain/btc-staker/stakerdb"
+	"github.com/babylonchain/btc-staker/walletcontroller"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/sirupsen/logrus"
+)
+
+// stakerPop returns proof of possession binding staker key to babylon key.
+// Proof only depends on the keys, so proof cached for staker address is reused
+// unless fresh proof is requested. Cached proof is verified before use, and if it
+// is invalid new proof is generated instead.
+func (app *StakerApp) stakerPop(
+	signer walletcontroller.SignerSession,
+	stakerAddress btcutil.Address,
+	stakerPubKey *btcec.PublicKey,
+	freshPop bool,
+) (*cl.BabylonPop, error) {
+	address := stakerAddress.EncodeAddress()
+	babylonPubKey := app.babylonClient.GetPubKey()
+
+	logger := app.logger.WithFields(logrus.Fields{
+		"stakerAddress": address,
+	})
+
+	if !freshPop {
+		pop, err := app.cachedPop(address, stakerPubKey)
+
+		switch {
+		case err == nil:
+			logger.Debug("Using cached proof of possession")
+			return pop, nil
+		case errors.Is(err, stakerdb.ErrCachedPopNotFound):
+		default:
+			logger.WithField("err", err).Warn("Cached proof of possession is invalid, generating new one")
+		}
+	}
+
+	pop, err := app.generatePop(signer)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := app.txTracker.SetCachedPop(address, babylonPubKey.Bytes(), babylonPopToDbPop(pop)); err != nil {
+		logger.WithField("err", err).Warn("Failed to cache proof of possession")
+	}
+
+	return pop, nil
+}
+
+// cachedPop returns proof of possession cached for staker address, after
+// verifying it against staker key and current babylon key
+func (app *StakerApp) cachedPop(address string, stakerPubKey *btcec.PublicKey) (*cl.BabylonPop, error) {
+	babylonPubKey := app.babylonClient.GetPubKey()
+
+	cached, err := app.txTracker.GetCachedPop(address, babylonPubKey.Bytes())
+
+	if err != nil {
+		return nil, err
+	}
+
+	pop, err := dbPopToBabylonPop(cached)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := pop.VerifyForKeys(stakerPubKey, babylonPubKey, app.network); err != nil {
+		return nil, err
+	}
+
+	return pop, nil
+}
@@ -0,0 +1,85 @@
+package staker
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"time"
+
+	scfg "github.com/babylonchain/btc-staker/stakercfg"
+	"github.com/babylonchain/btc-staker/types"
+)
+
+// Tor circuits take a while to build
+const proxyCheckTimeout = 30 * time.Second
+
+type proxiedTarget struct {
+	name     string
+	address  string
+	category StartupErrorCategory
+}
+
+// proxiedTargets returns addresses of targets connected through the proxy.
+// Targets whose address does not contain port are skipped, as their clients
+// pick the port on their own.
+func proxiedTargets(cfg *scfg.Config) []proxiedTarget {
+	var targets []proxiedTarget
+
+	add := func(name, address string, category StartupErrorCategory) {
+		if _, _, err := net.SplitHostPort(address); err != nil {
+			return
+		}
+
+		targets = append(targets, proxiedTarget{
+			name:     name,
+			address:  address,
+			category: category,
+		})
+	}
+
+	if cfg.ProxyConfig.Wallet && !cfg.WalletConfig.NoWallet {
+		add("btc wallet", cfg.WalletRpcConfig.Host, WalletUnavailable)
+	}
+
+	if cfg.ProxyConfig.BtcNode {
+		switch cfg.BtcNodeBackendConfig.ActiveNodeBackend {
+		case types.BitcoindNodeBackend:
+			add("btc node", cfg.BtcNodeBackendConfig.Bitcoind.RPCHost, NodeBackendUnavailable)
+		case types.BtcdNodeBackend:
+			add("btc node", cfg.BtcNodeBackendConfig.Btcd.RPCHost, NodeBackendUnavailable)
+		}
+	}
+
+	if cfg.ProxyConfig.Babylon {
+		if u, err := url.Parse(cfg.BabylonConfig.RPCAddr); err == nil {
+			add("babylon node", u.Host, BabylonUnavailable)
+		}
+	}
+
+	return targets
+}
+
+// checkProxiedTargets connects to every target connected through the proxy, so
+// that startup fails with error telling whether the proxy itself or the target
+// behind it is unreachable, instead of opaque errors of the rpc clients.
+func checkProxiedTargets(cfg *scfg.Config) error {
+	targets := proxiedTargets(cfg)
+
+	if len(targets) == 0 {
+		return nil
+	}
+
+	dialer := cfg.ProxyConfig.Dialer()
+
+	for _, target := range targets {
+		conn, err := dialer.DialTimeout("tcp", target.address, proxyCheckTimeout)
+
+		if err != nil {
+			return NewStartupError(target.category, fmt.Errorf("%s: %w", target.name, err))
+		}
+
+		_ = conn.Close()
+	}
+
+	return nil
+}
@@ -0,0 +1,40 @@
+package staker
+
+import (
+	"testing"
+
+	scfg "github.com/babylonchain/btc-staker/stakercfg"
+	"github.com/babylonchain/btc-staker/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProxiedTargets(t *testing.T) {
+	cfg := scfg.DefaultConfig()
+	cfg.BtcNodeBackendConfig.ActiveNodeBackend = types.BitcoindNodeBackend
+	cfg.WalletRpcConfig.Host = "wallet.onion:18556"
+	cfg.BtcNodeBackendConfig.Bitcoind.RPCHost = "node.onion:8332"
+	cfg.BabylonConfig.RPCAddr = "http://babylon.onion:26657"
+
+	require.Empty(t, proxiedTargets(&cfg))
+
+	cfg.ProxyConfig.BtcNode = true
+	cfg.ProxyConfig.Babylon = true
+
+	targets := proxiedTargets(&cfg)
+	require.Len(t, targets, 2)
+	require.Equal(t, "node.onion:8332", targets[0].address)
+	require.Equal(t, NodeBackendUnavailable, targets[0].category)
+	require.Equal(t, "babylon.onion:26657", targets[1].address)
+	require.Equal(t, BabylonUnavailable, targets[1].category)
+
+	// wallet of the node is reached through the node
+	cfg.ProxyConfig.Wallet = true
+	cfg.WalletConfig.NoWallet = true
+	require.Len(t, proxiedTargets(&cfg), 2)
+
+	cfg.WalletConfig.NoWallet = false
+	targets = proxiedTargets(&cfg)
+	require.Len(t, targets, 3)
+	require.Equal(t, "wallet.onion:18556", targets[0].address)
+	require.Equal(t, WalletUnavailable, targets[0].category)
+}
@@ -0,0 +1,250 @@
+package staker
+
+import (
+	"errors"
+	"fmt"
+
+	cl "github.com/babylonchain/btc-staker/babylonclient"
+	"github.com/babylonchain/btc-staker/proto"
+	"github.com/babylonchain/btc-staker/stakerdb"
+	"github.com/babylonchain/btc-staker/utils"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+)
+
+// reconciliationMetrics are Prometheus gauges with result of the last
+// reconciliation
+type reconciliationMetrics struct {
+	checkedTransactions prometheus.Gauge
+	discrepancies       *prometheus.GaugeVec
+}
+
+func newReconciliationMetrics() *reconciliationMetrics {
+	return &reconciliationMetrics{
+		checkedTransactions: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: "stakerd",
+			Name:      "reconciliation_checked_transactions",
+			Help:      "Number of transactions checked by the last reconciliation with babylon",
+		}),
+		discrepancies: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "stakerd",
+			Name:      "reconciliation_discrepancies",
+			Help:      "Number of discrepancies found by the last reconciliation with babylon",
+		}, []string{"type"}),
+	}
+}
+
+func (m *reconciliationMetrics) record(report *stakerdb.ReconciliationReport) {
+	m.checkedTransactions.Set(float64(report.NumCheckedTransactions))
+
+	counts := make(map[proto.DiscrepancyType]int)
+	for _, d := range report.Discrepancies {
+		counts[d.Type]++
+	}
+
+	// every type is set, so that types which are no longer found drop to zero
+	for value, name := range proto.DiscrepancyType_name {
+		m.discrepancies.WithLabelValues(name).Set(float64(counts[proto.DiscrepancyType(value)]))
+	}
+}
+
+type reconciliationCandidate struct {
+	stakingTxHash       chainhash.Hash
+	state               proto.TransactionState
+	hasUnbondingCovSigs bool
+}
+
+// needsReconciliation returns true for states in which delegation should
+// already be known to babylon
+func needsReconciliation(state proto.TransactionState) bool {
+	return state == proto.TransactionState_SENT_TO_BABYLON ||
+		state == proto.TransactionState_UNBONDING_SIGNATURES_TIMEOUT ||
+		state == proto.TransactionState_DELEGATION_ACTIVE ||
+		state == proto.TransactionState_UNBONDING_CONFIRMED_ON_BTC
+}
+
+func (app *StakerApp) reconciliationLoop() {
+	ticker := app.clock.NewTicker(app.config.StakerConfig.ReconciliationInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.Chan():
+			report, err := app.Reconcile()
+
+			if err != nil {
+				app.logger.WithFields(logrus.Fields{
+					"err": err,
+				}).Error("Failed to reconcile local delegations with babylon")
+				continue
+			}
+
+			app.logger.WithFields(logrus.Fields{
+				"checkedTransactions": report.NumCheckedTransactions,
+				"discrepancies":       len(report.Discrepancies),
+			}).Info("Finished reconciliation of local delegations with babylon")
+		case <-app.quit:
+			return
+		}
+	}
+}
+
+// Reconcile compares state of delegations in local database against babylon and
+// stores the report with found discrepancies. Discrepancies are only reported,
+// the only safe fixes i.e restarting delegation process for transactions
+// already on babylon and restarting waiting for unbonding signatures, are done by
+// startup checks and background tasks which are already running for
+// those transactions. The only exception are delegations which expired on
+// babylon before they became active locally, which are moved to
+// DELEGATION_EXPIRED state.
+func (app *StakerApp) Reconcile() (*stakerdb.ReconciliationReport, error) {
+	app.reconciliationMu.Lock()
+	defer app.reconciliationMu.Unlock()
+
+	params, err := app.babylonClient.Params()
+
+	if err != nil {
+		return nil, err
+	}
+
+	var candidates []*reconciliationCandidate
+
+	reset := func() {
+		candidates = make([]*reconciliationCandidate, 0)
+	}
+
+	// As in startup checks, only collect data in the scan to avoid long running
+	// read transaction while querying babylon
+	err = app.txTracker.ScanTrackedTransactions(func(tx *stakerdb.StoredTransaction) error {
+		if !needsReconciliation(tx.State) {
+			return nil
+		}
+
+		candidates = append(candidates, &reconciliationCandidate{
+			stakingTxHash:       tx.StakingTx.TxHash(),
+			state:               tx.State,
+			hasUnbondingCovSigs: tx.UnbondingTxData != nil && len(tx.UnbondingTxData.CovenantSignatures) > 0,
+		})
+		return nil
+	}, reset)
+
+	if err != nil {
+		return nil, err
+	}
+
+	report := &stakerdb.ReconciliationReport{
+		Discrepancies: make([]stakerdb.ReconciliationDiscrepancy, 0),
+	}
+
+	for i, c := range candidates {
+		if i > 0 {
+			// rate limit queries to not overload babylon node
+			select {
+			case <-app.clock.After(app.config.StakerConfig.ReconciliationQueryDelay):
+			case <-app.quit:
+				return nil, fmt.Errorf("staker app is shutting down")
+			}
+		}
+
+		discrepancy, err := app.reconcileDelegation(c, params)
+
+		if err != nil {
+			return nil, err
+		}
+
+		report.NumCheckedTransactions++
+
+		if discrepancy != nil {
+			app.logger.WithFields(logrus.Fields{
+				"stakingTxHash": c.stakingTxHash,
+				"localState":    c.state,
+				"discrepancy":   discrepancy.Type,
+			}).Warn(discrepancy.Description)
+
+			report.Discrepancies = append(report.Discrepancies, *discrepancy)
+		}
+	}
+
+	report.CreatedAt = app.clock.Now()
+
+	if err := app.txTracker.SaveReconciliationReport(report); err != nil {
+		return nil, err
+	}
+
+	app.reconciliationMetrics.record(report)
+
+	return report, nil
+}
+
+func (app *StakerApp) reconcileDelegation(
+	c *reconciliationCandidate,
+	params *cl.StakingParams,
+) (*stakerdb.ReconciliationDiscrepancy, error) {
+	newDiscrepancy := func(t proto.DiscrepancyType, desc string) *stakerdb.ReconciliationDiscrepancy {
+		return &stakerdb.ReconciliationDiscrepancy{
+			StakingTxHash: c.stakingTxHash,
+			LocalState:    c.state,
+			Type:          t,
+			Description:   desc,
+		}
+	}
+
+	di, err := app.babylonClient.QueryDelegationInfo(&c.stakingTxHash)
+
+	if err != nil {
+		if errors.Is(err, cl.ErrDelegationNotFound) {
+			return newDiscrepancy(
+				proto.DiscrepancyType_MISSING_ON_BABYLON,
+				"Delegation tracked locally does not exist on babylon",
+			), nil
+		}
+
+		return nil, err
+	}
+
+	switch c.state {
+	case proto.TransactionState_SENT_TO_BABYLON, proto.TransactionState_UNBONDING_SIGNATURES_TIMEOUT:
+		if di.Status == cl.DelegationStatusUnbonded {
+			utils.PushOrQuit[*delegationExpiredOnBabylonEvent](
+				app.delegationExpiredOnBabylonEvChan,
+				&delegationExpiredOnBabylonEvent{stakingTxHash: c.stakingTxHash},
+				app.quit,
+			)
+
+			return newDiscrepancy(
+				proto.DiscrepancyType_DELEGATION_EXPIRED_ON_BABYLON,
+				"Delegation expired on babylon before it became active locally. Staked funds can be withdrawn once staking timelock expires",
+			), nil
+		}
+
+		if di.UndelegationInfo != nil &&
+			len(di.UndelegationInfo.CovenantUnbondingSignatures) >= int(params.CovenantQuruomThreshold) &&
+			!c.hasUnbondingCovSigs {
+			return newDiscrepancy(
+				proto.DiscrepancyType_UNDELEGATION_NOT_TRACKED,
+				"Babylon has enough covenant unbonding signatures which are not stored locally",
+			), nil
+		}
+	case proto.TransactionState_DELEGATION_ACTIVE:
+		if !di.Active {
+			return newDiscrepancy(
+				proto.DiscrepancyType_STATUS_MISMATCH,
+				"Delegation is active locally but not on babylon. It may be expired or slashed",
+			), nil
+		}
+	case proto.TransactionState_UNBONDING_CONFIRMED_ON_BTC:
+		if di.Active {
+			return newDiscrepancy(
+				proto.DiscrepancyType_STATUS_MISMATCH,
+				"Unbonding transaction is confirmed on btc but delegation is still active on babylon",
+			), nil
+		}
+	}
+
+	return nil, nil
+}
+
+func (app *StakerApp) LastReconciliationReport() (*stakerdb.ReconciliationReport, error) {
+	return app.txTracker.GetLastReconciliationReport()
+}
@@ -0,0 +1,162 @@
+package staker
+
+import (
+	"testing"
+	"time"
+
+	cl "github.com/babylonchain/btc-staker/babylonclient"
+	"github.com/babylonchain/btc-staker/proto"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReconcileDiscrepancies(t *testing.T) {
+	sentToBabylon := func(t *testing.T, d *testStakerDeps) *chainhash.Hash {
+		return d.addSentToBabylonTransaction(t)
+	}
+
+	active := func(t *testing.T, d *testStakerDeps) *chainhash.Hash {
+		txHash := d.addSentToBabylonTransaction(t)
+		require.NoError(t, d.tracker.SetTxUnbondingSignaturesReceived(txHash, testCovenantSigs(t)))
+		return txHash
+	}
+
+	unbonded := func(t *testing.T, d *testStakerDeps) *chainhash.Hash {
+		txHash := d.addSentToBabylonTransaction(t)
+		require.NoError(t, d.tracker.SetTxUnbondingConfirmedOnBtc(txHash, &chainhash.Hash{}, 20, time.Time{}))
+		return txHash
+	}
+
+	tests := []struct {
+		name string
+		add  func(t *testing.T, d *testStakerDeps) *chainhash.Hash
+		// delegation on babylon, nil if babylon does not know it
+		delegation  *cl.DelegationInfo
+		checked     uint64
+		discrepancy *proto.DiscrepancyType
+		expired     bool
+	}{
+		{
+			name:        "delegation missing on babylon",
+			add:         sentToBabylon,
+			checked:     1,
+			discrepancy: proto.DiscrepancyType_MISSING_ON_BABYLON.Enum(),
+		},
+		{
+			name:        "delegation expired on babylon",
+			add:         sentToBabylon,
+			delegation:  &cl.DelegationInfo{Status: cl.DelegationStatusUnbonded},
+			checked:     1,
+			discrepancy: proto.DiscrepancyType_DELEGATION_EXPIRED_ON_BABYLON.Enum(),
+			expired:     true,
+		},
+		{
+			name: "unbonding signatures not tracked",
+			add:  sentToBabylon,
+			delegation: &cl.DelegationInfo{
+				Status: cl.DelegationStatusActive,
+				Active: true,
+				UndelegationInfo: &cl.UndelegationInfo{
+					CovenantUnbondingSignatures: []cl.CovenantSignatureInfo{{}},
+				},
+			},
+			checked:     1,
+			discrepancy: proto.DiscrepancyType_UNDELEGATION_NOT_TRACKED.Enum(),
+		},
+		{
+			name:        "active locally but not on babylon",
+			add:         active,
+			delegation:  &cl.DelegationInfo{Status: cl.DelegationStatusUnbonded},
+			checked:     1,
+			discrepancy: proto.DiscrepancyType_STATUS_MISMATCH.Enum(),
+		},
+		{
+			name:        "unbonded locally but active on babylon",
+			add:         unbonded,
+			delegation:  &cl.DelegationInfo{Status: cl.DelegationStatusActive, Active: true},
+			checked:     1,
+			discrepancy: proto.DiscrepancyType_STATUS_MISMATCH.Enum(),
+		},
+		{
+			name:       "consistent delegation",
+			add:        active,
+			delegation: &cl.DelegationInfo{Status: cl.DelegationStatusActive, Active: true},
+			checked:    1,
+		},
+		{
+			name:    "transaction not sent to babylon is not checked",
+			add:     func(t *testing.T, d *testStakerDeps) *chainhash.Hash { return d.addConfirmedTransaction(t) },
+			checked: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deps := newTestStakerDeps(t)
+			deps.babylon.params = &cl.StakingParams{
+				ConfirmationTimeBlocks:    2,
+				FinalizationTimeoutBlocks: 5,
+				CovenantQuruomThreshold:   1,
+			}
+
+			txHash := tt.add(t, deps)
+
+			if tt.delegation != nil {
+				deps.babylon.delegations = map[chainhash.Hash]*cl.DelegationInfo{*txHash: tt.delegation}
+			}
+
+			app := deps.newApp(t)
+
+			// stands in for main event loop
+			done := make(chan struct{})
+			defer close(done)
+			expired := make(chan chainhash.Hash, 1)
+			go func() {
+				select {
+				case ev := <-app.delegationExpiredOnBabylonEvChan:
+					expired <- ev.stakingTxHash
+				case <-done:
+				}
+			}()
+
+			report, err := app.Reconcile()
+			require.NoError(t, err)
+			require.Equal(t, tt.checked, report.NumCheckedTransactions)
+
+			if tt.discrepancy == nil {
+				require.Empty(t, report.Discrepancies)
+			} else {
+				require.Len(t, report.Discrepancies, 1)
+				require.Equal(t, *tt.discrepancy, report.Discrepancies[0].Type)
+				require.Equal(t, *txHash, report.Discrepancies[0].StakingTxHash)
+			}
+
+			if tt.expired {
+				select {
+				case expiredTxHash := <-expired:
+					require.Equal(t, *txHash, expiredTxHash)
+				case <-time.After(time.Second):
+					t.Fatalf("expired delegation was not reported to main loop")
+				}
+			} else {
+				require.Empty(t, expired)
+			}
+
+			stored, err := app.LastReconciliationReport()
+			require.NoError(t, err)
+			require.Equal(t, report.NumCheckedTransactions, stored.NumCheckedTransactions)
+			require.Equal(t, report.Discrepancies, stored.Discrepancies)
+
+			require.Equal(t, float64(tt.checked), testutil.ToFloat64(app.reconciliationMetrics.checkedTransactions))
+			for value, name := range proto.DiscrepancyType_name {
+				expected := 0.0
+				if tt.discrepancy != nil && *tt.discrepancy == proto.DiscrepancyType(value) {
+					expected = 1
+				}
+
+				require.Equal(t, expected, testutil.ToFloat64(app.reconciliationMetrics.discrepancies.WithLabelValues(name)), name)
+			}
+		})
+	}
+}
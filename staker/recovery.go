@@ -0,0 +1,420 @@
+package staker
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	cl "github.com/babylonchain/btc-staker/babylonclient"
+	"github.com/babylonchain/btc-staker/proto"
+	scfg "github.com/babylonchain/btc-staker/stakercfg"
+	"github.com/babylonchain/btc-staker/stakerdb"
+	"github.com/babylonchain/btc-staker/walletcontroller"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/sirupsen/logrus"
+)
+
+type RecoveredTransaction struct {
+	StakingTxHash chainhash.Hash
+	State         proto.TransactionState
+}
+
+type SkippedDelegation struct {
+	StakingTxHash chainhash.Hash
+	Reason        string
+}
+
+type RecoveryResult struct {
+	DryRun bool
+	// TargetDbPath is path of the database to which transactions were recovered,
+	// empty in dry run mode
+	TargetDbPath string
+	Recovered    []RecoveredTransaction
+	Skipped      []SkippedDelegation
+}
+
+func (r *RecoveryResult) skip(stakingTxHash chainhash.Hash, reason string) {
+	r.Skipped = append(r.Skipped, SkippedDelegation{
+		StakingTxHash: stakingTxHash,
+		Reason:        reason,
+	})
+}
+
+// btcConfirmationInfo returns confirmation info of transaction or nil if transaction
+// is not yet included in btc chain. Wallet is rescanned for transactions which are
+// not found.
+func (app *StakerApp) btcConfirmationInfo(
+	tx *wire.MsgTx,
+	outputIdx uint32,
+	rescanStartHeight *uint32,
+) (*stakerdb.BtcConfirmationInfo, error) {
+	txHash := tx.TxHash()
+
+	details, status, err := app.txDetailsWithRescan(&txHash, tx.TxOut[outputIdx].PkScript, rescanStartHeight)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if status != walletcontroller.TxInChain {
+		return nil, nil
+	}
+
+	return &stakerdb.BtcConfirmationInfo{
+		Height:    details.BlockHeight,
+		BlockHash: *details.BlockHash,
+		BlockTime: blockTime(details.Block),
+	}, nil
+}
+
+// outputSpent returns true if output is not in utxo set of the node, either
+// because it was spent or because transaction creating it is unknown to the node
+func (app *StakerApp) outputSpent(outpoint *wire.OutPoint) (bool, error) {
+	_, err := app.wc.UnspentOutput(outpoint)
+
+	if errors.Is(err, walletcontroller.ErrOutputNotFound) {
+		return true, nil
+	}
+
+	if err != nil {
+		return false, err
+	}
+
+	return false, nil
+}
+
+// recoveredStakeSpent returns true if funds of recovered stake were already
+// withdrawn, either from staking output or, if unbonding transaction is confirmed,
+// from unbonding output.
+func (app *StakerApp) recoveredStakeSpent(storedTx *stakerdb.StoredTransaction) (bool, error) {
+	if storedTx.State == proto.TransactionState_UNBONDING_CONFIRMED_ON_BTC {
+		unbondingTxHash := storedTx.UnbondingTxData.UnbondingTx.TxHash()
+		return app.outputSpent(wire.NewOutPoint(&unbondingTxHash, storedTx.UnbondingTxData.UnbondingOutputIndex))
+	}
+
+	stakingTxHash := storedTx.StakingTx.TxHash()
+	stakingSpent, err := app.outputSpent(wire.NewOutPoint(&stakingTxHash, storedTx.StakingOutputIndex))
+
+	if err != nil || !stakingSpent {
+		return false, err
+	}
+
+	if storedTx.UnbondingTxData == nil {
+		return true, nil
+	}
+
+	// staking output is also spent by unbonding transaction waiting in mempool,
+	// stake is then recovered as not yet unbonded and daemon waits for unbonding
+	// confirmation
+	unbondingTxHash := storedTx.UnbondingTxData.UnbondingTx.TxHash()
+	return app.outputSpent(wire.NewOutPoint(&unbondingTxHash, storedTx.UnbondingTxData.UnbondingOutputIndex))
+}
+
+// recoverDelegation rebuilds stored transaction for delegation found on babylon.
+// It returns empty reason if delegation can be recovered, otherwise reason why it
+// was skipped.
+func (app *StakerApp) recoverDelegation(
+	del *cl.StakerDelegation,
+	stakerAddress btcutil.Address,
+	stakerPubKey *btcec.PublicKey,
+	params *cl.StakingParams,
+	target *stakerdb.TrackedTransactionStore,
+	rescanStartHeight *uint32,
+) (*stakerdb.StoredTransaction, string, error) {
+	storedTx := &stakerdb.StoredTransaction{
+		StakingTx:               del.StakingTx,
+		StakingOutputIndex:      del.StakingOutputIdx,
+		StakingTime:             del.StakingTime,
+		FinalityProvidersBtcPks: del.FpBtcPks,
+		Pop:                     babylonPopToDbPop(del.Pop),
+		StakerAddress:           stakerAddress.EncodeAddress(),
+		State:                   proto.TransactionState_SENT_TO_BTC,
+	}
+
+	// staking output is only recovered if we are able to reconstruct it, as only
+	// then we are able to spend it
+	if !stakingOutputCommitsTo(
+		storedTx,
+		stakerPubKey,
+		params.CovenantPks,
+		params.CovenantQuruomThreshold,
+		app.network,
+	) {
+		return nil, "staking output cannot be reconstructed with current covenant committee", nil
+	}
+
+	stakingTxConfirmation, err := app.btcConfirmationInfo(del.StakingTx, del.StakingOutputIdx, rescanStartHeight)
+
+	if err != nil {
+		return nil, "", err
+	}
+
+	if stakingTxConfirmation == nil {
+		return nil, "staking transaction is not included in btc chain", nil
+	}
+
+	storedTx.StakingTxConfirmationInfo = stakingTxConfirmation
+	storedTx.State = proto.TransactionState_CONFIRMED_ON_BTC
+
+	if target != nil {
+		paramsVersion, err := target.RegisterStakingParams(
+			params.CovenantPks,
+			params.CovenantQuruomThreshold,
+		)
+
+		if err != nil {
+			return nil, "", err
+		}
+
+		storedTx.ParamsVersion = paramsVersion
+	}
+
+	stakingTxHash := del.StakingTx.TxHash()
+
+	delegationInfo, err := app.babylonClient.QueryDelegationInfo(&stakingTxHash)
+
+	if err != nil {
+		return nil, "", err
+	}
+
+	if delegationInfo.UndelegationInfo == nil {
+		// without unbonding data we are not able to track delegation further, daemon
+		// will check on babylon whether delegation needs to be sent
+		return app.markRecoveredStakeSpent(storedTx)
+	}
+
+	undelegation := delegationInfo.UndelegationInfo
+
+	unbondingOutputIdx, err := findUnbondingOutput(
+		undelegation.UnbondingTransaction,
+		stakerPubKey,
+		del.FpBtcPks,
+		params.CovenantPks,
+		params.CovenantQuruomThreshold,
+		undelegation.UnbondingTime,
+		app.network,
+	)
+
+	if err != nil {
+		return nil, "unbonding output cannot be reconstructed with current covenant committee", nil
+	}
+
+	storedTx.State = proto.TransactionState_SENT_TO_BABYLON
+	storedTx.UnbondingTxData = &stakerdb.UnbondingStoreData{
+		UnbondingTx:          undelegation.UnbondingTransaction,
+		UnbondingOutputIndex: unbondingOutputIdx,
+		UnbondingTime:        undelegation.UnbondingTime,
+	}
+
+	if len(undelegation.CovenantUnbondingSignatures) >= int(params.CovenantQuruomThreshold) {
+		storedTx.State = proto.TransactionState_DELEGATION_ACTIVE
+		storedTx.UnbondingTxData.CovenantSignatures = babylonCovSigsToDbSigSigs(
+			undelegation.CovenantUnbondingSignatures,
+		)
+	}
+
+	unbondingTxConfirmation, err := app.btcConfirmationInfo(undelegation.UnbondingTransaction, unbondingOutputIdx, rescanStartHeight)
+
+	if err != nil {
+		return nil, "", err
+	}
+
+	if unbondingTxConfirmation != nil {
+		storedTx.State = proto.TransactionState_UNBONDING_CONFIRMED_ON_BTC
+		storedTx.UnbondingTxData.UnbondingTxConfirmationInfo = unbondingTxConfirmation
+	}
+
+	return app.markRecoveredStakeSpent(storedTx)
+}
+
+func (app *StakerApp) markRecoveredStakeSpent(
+	storedTx *stakerdb.StoredTransaction,
+) (*stakerdb.StoredTransaction, string, error) {
+	spent, err := app.recoveredStakeSpent(storedTx)
+
+	if err != nil {
+		return nil, "", err
+	}
+
+	if spent {
+		storedTx.State = proto.TransactionState_SPENT_ON_BTC
+	}
+
+	return storedTx, "", nil
+}
+
+// openRecoveryTarget opens bbolt database at given path to which recovered
+// transactions are written. Database must not hold any transactions, so that
+// recovered data is not mixed with data of other daemon.
+func (app *StakerApp) openRecoveryTarget(dbPath string) (*stakerdb.TrackedTransactionStore, func(), error) {
+	if dbPath == "" {
+		return nil, nil, fmt.Errorf("target database path must be provided")
+	}
+
+	dbPath = scfg.CleanAndExpandPath(dbPath)
+
+	daemonDbConfig := app.config.DBConfig
+	if daemonDbConfig.Backend == "" || daemonDbConfig.Backend == scfg.BoltDbBackend {
+		daemonDbPath := filepath.Join(scfg.CleanAndExpandPath(daemonDbConfig.DBPath), daemonDbConfig.DBFileName)
+
+		// daemon keeps its database locked, so opening it would only time out
+		if dbPath == daemonDbPath {
+			return nil, nil, fmt.Errorf("target database cannot be database of running daemon")
+		}
+	}
+
+	dbConfig := scfg.DefaultDBConfig()
+	dbConfig.DBPath = filepath.Dir(dbPath)
+	dbConfig.DBFileName = filepath.Base(dbPath)
+
+	backend, err := scfg.GetDbBackend(&dbConfig)
+
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open target database: %w", err)
+	}
+
+	closeBackend := func() {
+		if err := backend.Close(); err != nil {
+			app.logger.WithError(err).Error("Failed to close recovery target database")
+		}
+	}
+
+	store, err := stakerdb.NewTrackedTransactionStore(backend)
+
+	if err != nil {
+		closeBackend()
+		return nil, nil, fmt.Errorf("failed to open target database: %w", err)
+	}
+
+	hasTransactions, err := store.HasTransactions()
+
+	if err != nil {
+		closeBackend()
+		return nil, nil, err
+	}
+
+	if hasTransactions {
+		closeBackend()
+		return nil, nil, fmt.Errorf("%s: %w", dbPath, stakerdb.ErrRecoveryTargetNotEmpty)
+	}
+
+	return store, closeBackend, nil
+}
+
+// RecoverDb restores tracked transactions of given staker address after database
+// loss. Staking transactions are found by matching transactions sent from the
+// connected wallet against delegations made on babylon with the staker key.
+// Staking transactions which were never delegated on babylon cannot be recovered,
+// as their staking outputs cannot be reconstructed. Transactions which staking
+// or unbonding outputs were already withdrawn are restored in the last state
+// which can be inferred from babylon and btc data.
+// Recovered transactions are written to a new bbolt database at targetDbPath,
+// which must not hold any transactions. Daemon database is never modified.
+// To use recovered data, operator stops the daemon and replaces its database
+// with the target one, so that recovered transactions are picked up by usual
+// startup checks.
+// In dry run mode, nothing is written and targetDbPath is ignored.
+// Transactions not found on btc are looked for by rescanning wallet from
+// rescanStartHeight, or from height estimated from config if it is nil.
+func (app *StakerApp) RecoverDb(
+	stakerAddress btcutil.Address,
+	targetDbPath string,
+	dryRun bool,
+	rescanStartHeight *uint32,
+) (*RecoveryResult, error) {
+	ctx, cancel := app.appQuitContext()
+	defer cancel()
+
+	result := &RecoveryResult{
+		DryRun: dryRun,
+	}
+
+	var target *stakerdb.TrackedTransactionStore
+
+	if !dryRun {
+		store, closeTarget, err := app.openRecoveryTarget(targetDbPath)
+
+		if err != nil {
+			return nil, err
+		}
+		defer closeTarget()
+
+		target = store
+		result.TargetDbPath = scfg.CleanAndExpandPath(targetDbPath)
+	}
+
+	signer := app.newSignerSession(ctx, stakerAddress)
+	defer signer.Close()
+
+	stakerPubKey, err := signer.PubKey()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve staker key: %w", err)
+	}
+
+	params, err := app.babylonClient.Params()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve babylon params: %w", err)
+	}
+
+	delegations, err := app.babylonClient.QueryDelegationsByStakerKey(stakerPubKey)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve staker delegations from babylon: %w", err)
+	}
+
+	sentTxs, err := app.wc.ListSentTransactions()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to list wallet transactions: %w", err)
+	}
+
+	walletTxs := make(map[chainhash.Hash]struct{}, len(sentTxs))
+
+	for _, txHash := range sentTxs {
+		walletTxs[txHash] = struct{}{}
+	}
+
+	for _, del := range delegations {
+		stakingTxHash := del.StakingTx.TxHash()
+
+		if _, ok := walletTxs[stakingTxHash]; !ok {
+			result.skip(stakingTxHash, "staking transaction was not sent from connected wallet")
+			continue
+		}
+
+		storedTx, reason, err := app.recoverDelegation(del, stakerAddress, stakerPubKey, params, target, rescanStartHeight)
+
+		if err != nil {
+			return nil, fmt.Errorf("failed to recover staking transaction %s: %w", stakingTxHash, err)
+		}
+
+		if reason != "" {
+			result.skip(stakingTxHash, reason)
+			continue
+		}
+
+		if target != nil {
+			if err := target.AddRecoveredTransaction(storedTx); err != nil {
+				return nil, fmt.Errorf("failed to store recovered staking transaction %s: %w", stakingTxHash, err)
+			}
+		}
+
+		app.logger.WithFields(logrus.Fields{
+			"stakingTxHash": stakingTxHash,
+			"state":         storedTx.State,
+			"dryRun":        dryRun,
+		}).Info("Recovered staking transaction")
+
+		result.Recovered = append(result.Recovered, RecoveredTransaction{
+			StakingTxHash: stakingTxHash,
+			State:         storedTx.State,
+		})
+	}
+
+	return result, nil
+}
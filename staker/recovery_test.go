@@ -0,0 +1,381 @@
+package staker
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	staking "github.com/babylonchain/babylon/btcstaking"
+	cl "github.com/babylonchain/btc-staker/babylonclient"
+	"github.com/babylonchain/btc-staker/proto"
+	scfg "github.com/babylonchain/btc-staker/stakercfg"
+	"github.com/babylonchain/btc-staker/stakerdb"
+	"github.com/babylonchain/btc-staker/walletcontroller"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	notifier "github.com/lightningnetwork/lnd/chainntnfs"
+	"github.com/stretchr/testify/require"
+)
+
+// recoveryWallet is wallet which sent staking transactions lost together with
+// the database
+type recoveryWallet struct {
+	*keyWallet
+	sentTxs []chainhash.Hash
+	// outputs in utxo set, other outputs are reported as spent
+	utxos map[wire.OutPoint]*wire.TxOut
+}
+
+func (w *recoveryWallet) ListSentTransactions() ([]chainhash.Hash, error) {
+	return w.sentTxs, nil
+}
+
+func (w *recoveryWallet) UnspentOutput(outpoint *wire.OutPoint) (*wire.TxOut, error) {
+	if output, ok := w.utxos[*outpoint]; ok {
+		return output, nil
+	}
+
+	return nil, walletcontroller.ErrOutputNotFound
+}
+
+func (w *recoveryWallet) ImportAddressWithRescan(_ []byte, _ uint32) error {
+	return nil
+}
+
+type recoveryFixture struct {
+	deps          *testStakerDeps
+	wallet        *recoveryWallet
+	stakerKey     *btcec.PrivateKey
+	stakerAddress btcutil.Address
+	covenantKey   *btcec.PrivateKey
+	// path of the database to which transactions are recovered
+	targetDbPath string
+}
+
+func newRecoveryFixture(t *testing.T) *recoveryFixture {
+	deps := newTestStakerDeps(t)
+	stakerKey := genPrivKey(t)
+	covenantKey := genPrivKey(t)
+
+	deps.babylon.params = &cl.StakingParams{
+		ConfirmationTimeBlocks:    2,
+		FinalizationTimeoutBlocks: 5,
+		CovenantPks:               []*btcec.PublicKey{covenantKey.PubKey()},
+		CovenantQuruomThreshold:   1,
+	}
+	deps.wallet.txsInChain = make(map[chainhash.Hash]*notifier.TxConfirmation)
+
+	wallet := &recoveryWallet{
+		keyWallet: &keyWallet{testWallet: deps.wallet, privKey: stakerKey},
+		utxos:     make(map[wire.OutPoint]*wire.TxOut),
+	}
+	deps.wc = wallet
+
+	stakerAddress, err := btcutil.NewAddressWitnessPubKeyHash(
+		btcutil.Hash160(stakerKey.PubKey().SerializeCompressed()),
+		&deps.config.ActiveNetParams,
+	)
+	require.NoError(t, err)
+
+	return &recoveryFixture{
+		deps:          deps,
+		wallet:        wallet,
+		stakerKey:     stakerKey,
+		stakerAddress: stakerAddress,
+		covenantKey:   covenantKey,
+		targetDbPath:  filepath.Join(t.TempDir(), "recovered.db"),
+	}
+}
+
+// addDelegation creates staking transaction sent from the wallet and delegated
+// on babylon with given covenant committee, together with its unbonding
+// transaction
+func (f *recoveryFixture) addDelegation(
+	t *testing.T,
+	covenantPks []*btcec.PublicKey,
+) (*wire.MsgTx, *wire.MsgTx) {
+	const (
+		stakingTime   = 100
+		unbondingTime = 10
+		stakingAmount = 100000
+	)
+
+	net := &f.deps.config.ActiveNetParams
+	fpPks := []*btcec.PublicKey{genPubKey(t)}
+
+	stakingInfo, err := staking.BuildStakingInfo(f.stakerKey.PubKey(), fpPks, covenantPks, 1, stakingTime, stakingAmount, net)
+	require.NoError(t, err)
+
+	stakingTx := wire.NewMsgTx(2)
+	inputHash := chainhash.HashH(genPubKey(t).SerializeCompressed())
+	stakingTx.AddTxIn(wire.NewTxIn(wire.NewOutPoint(&inputHash, 0), nil, nil))
+	stakingTx.AddTxOut(stakingInfo.StakingOutput)
+	stakingTxHash := stakingTx.TxHash()
+
+	unbondingInfo, err := staking.BuildUnbondingInfo(f.stakerKey.PubKey(), fpPks, covenantPks, 1, unbondingTime, stakingAmount-1000, net)
+	require.NoError(t, err)
+
+	unbondingTx := wire.NewMsgTx(2)
+	unbondingTx.AddTxIn(wire.NewTxIn(wire.NewOutPoint(&stakingTxHash, 0), nil, nil))
+	unbondingTx.AddTxOut(unbondingInfo.UnbondingOutput)
+
+	pop, err := cl.NewBabylonPop(cl.SchnorrType, []byte{1}, []byte{1})
+	require.NoError(t, err)
+
+	f.deps.babylon.stakerDelegations = append(f.deps.babylon.stakerDelegations, &cl.StakerDelegation{
+		StakingTx:        stakingTx,
+		StakingOutputIdx: 0,
+		StakingTime:      stakingTime,
+		FpBtcPks:         fpPks,
+		Pop:              pop,
+	})
+	f.wallet.sentTxs = append(f.wallet.sentTxs, stakingTxHash)
+
+	return stakingTx, unbondingTx
+}
+
+func (f *recoveryFixture) setDelegationInfo(stakingTx *wire.MsgTx, info *cl.DelegationInfo) {
+	if f.deps.babylon.delegations == nil {
+		f.deps.babylon.delegations = make(map[chainhash.Hash]*cl.DelegationInfo)
+	}
+
+	f.deps.babylon.delegations[stakingTx.TxHash()] = info
+}
+
+func (f *recoveryFixture) confirm(tx *wire.MsgTx, height uint32) {
+	f.deps.wallet.txsInChain[tx.TxHash()] = &notifier.TxConfirmation{
+		BlockHash:   &chainhash.Hash{},
+		BlockHeight: height,
+	}
+}
+
+func (f *recoveryFixture) addUtxo(tx *wire.MsgTx) {
+	f.wallet.utxos[*wire.NewOutPoint(txHashPtr(tx), 0)] = tx.TxOut[0]
+}
+
+func (f *recoveryFixture) recover(t *testing.T, dryRun bool) (*RecoveryResult, error) {
+	rescanStartHeight := uint32(1)
+	return f.deps.newApp(t).RecoverDb(f.stakerAddress, f.targetDbPath, dryRun, &rescanStartHeight)
+}
+
+// openRecoveredStore opens database written by recovery, after it was closed
+// by the daemon
+func openRecoveredStore(t *testing.T, dbPath string) *stakerdb.TrackedTransactionStore {
+	dbConfig := scfg.DefaultDBConfig()
+	dbConfig.DBPath = filepath.Dir(dbPath)
+	dbConfig.DBFileName = filepath.Base(dbPath)
+
+	db, err := scfg.GetDbBackend(&dbConfig)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = db.Close()
+	})
+
+	store, err := stakerdb.NewTrackedTransactionStore(db)
+	require.NoError(t, err)
+
+	return store
+}
+
+func TestRecoverDbInfersState(t *testing.T) {
+	tests := []struct {
+		name string
+		// babylon returns unbonding transaction of delegation
+		undelegation bool
+		covenantSigs bool
+		// unbonding transaction is included in btc chain
+		unbondingConfirmed bool
+		// outputs which are still in utxo set
+		stakingUnspent   bool
+		unbondingUnspent bool
+		state            proto.TransactionState
+	}{
+		{
+			name:           "staking transaction not yet delegated",
+			stakingUnspent: true,
+			state:          proto.TransactionState_CONFIRMED_ON_BTC,
+		},
+		{
+			name:  "staking output withdrawn without delegation",
+			state: proto.TransactionState_SPENT_ON_BTC,
+		},
+		{
+			name:           "delegation waiting for covenant signatures",
+			undelegation:   true,
+			stakingUnspent: true,
+			state:          proto.TransactionState_SENT_TO_BABYLON,
+		},
+		{
+			name:           "active delegation",
+			undelegation:   true,
+			covenantSigs:   true,
+			stakingUnspent: true,
+			state:          proto.TransactionState_DELEGATION_ACTIVE,
+		},
+		{
+			name:             "unbonding transaction in mempool",
+			undelegation:     true,
+			covenantSigs:     true,
+			unbondingUnspent: true,
+			state:            proto.TransactionState_DELEGATION_ACTIVE,
+		},
+		{
+			name:         "staking output withdrawn",
+			undelegation: true,
+			covenantSigs: true,
+			state:        proto.TransactionState_SPENT_ON_BTC,
+		},
+		{
+			name:               "unbonding transaction confirmed",
+			undelegation:       true,
+			covenantSigs:       true,
+			unbondingConfirmed: true,
+			unbondingUnspent:   true,
+			state:              proto.TransactionState_UNBONDING_CONFIRMED_ON_BTC,
+		},
+		{
+			name:               "unbonding output withdrawn",
+			undelegation:       true,
+			covenantSigs:       true,
+			unbondingConfirmed: true,
+			state:              proto.TransactionState_SPENT_ON_BTC,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newRecoveryFixture(t)
+
+			stakingTx, unbondingTx := f.addDelegation(t, []*btcec.PublicKey{f.covenantKey.PubKey()})
+			f.confirm(stakingTx, 10)
+
+			info := &cl.DelegationInfo{}
+			if tt.undelegation {
+				info.UndelegationInfo = &cl.UndelegationInfo{
+					UnbondingTransaction: unbondingTx,
+					UnbondingTime:        10,
+				}
+			}
+			if tt.covenantSigs {
+				for _, sig := range testCovenantSigs(t) {
+					info.UndelegationInfo.CovenantUnbondingSignatures = append(
+						info.UndelegationInfo.CovenantUnbondingSignatures,
+						cl.CovenantSignatureInfo{Signature: sig.Signature, PubKey: sig.PubKey},
+					)
+				}
+			}
+			f.setDelegationInfo(stakingTx, info)
+
+			if tt.unbondingConfirmed {
+				f.confirm(unbondingTx, 20)
+			}
+			if tt.stakingUnspent {
+				f.addUtxo(stakingTx)
+			}
+			if tt.unbondingUnspent {
+				f.addUtxo(unbondingTx)
+			}
+
+			result, err := f.recover(t, false)
+			require.NoError(t, err)
+			require.Empty(t, result.Skipped)
+			require.Equal(t, f.targetDbPath, result.TargetDbPath)
+
+			stakingTxHash := stakingTx.TxHash()
+			require.Equal(t, []RecoveredTransaction{{StakingTxHash: stakingTxHash, State: tt.state}}, result.Recovered)
+
+			// daemon database is left untouched
+			_, err = f.deps.tracker.GetTransaction(&stakingTxHash)
+			require.ErrorIs(t, err, stakerdb.ErrTransactionNotFound)
+
+			storedTx, err := openRecoveredStore(t, f.targetDbPath).GetTransaction(&stakingTxHash)
+			require.NoError(t, err)
+			require.Equal(t, tt.state, storedTx.State)
+			require.Equal(t, f.stakerAddress.EncodeAddress(), storedTx.StakerAddress)
+			require.Equal(t, uint32(10), storedTx.StakingTxConfirmationInfo.Height)
+
+			if tt.undelegation {
+				require.NotNil(t, storedTx.UnbondingTxData)
+				require.Equal(t, unbondingTx.TxHash(), storedTx.UnbondingTxData.UnbondingTx.TxHash())
+			}
+		})
+	}
+}
+
+func TestRecoverDbSkipsUnrecoverableDelegations(t *testing.T) {
+	f := newRecoveryFixture(t)
+
+	notFromWallet, _ := f.addDelegation(t, []*btcec.PublicKey{f.covenantKey.PubKey()})
+	f.wallet.sentTxs = nil
+
+	notConfirmed, _ := f.addDelegation(t, []*btcec.PublicKey{f.covenantKey.PubKey()})
+
+	// committee rotated since delegation was created
+	oldCommittee, _ := f.addDelegation(t, []*btcec.PublicKey{genPubKey(t)})
+	f.confirm(oldCommittee, 10)
+
+	result, err := f.recover(t, false)
+	require.NoError(t, err)
+	require.Empty(t, result.Recovered)
+
+	reasons := make(map[chainhash.Hash]string)
+	for _, skipped := range result.Skipped {
+		reasons[skipped.StakingTxHash] = skipped.Reason
+	}
+
+	require.Len(t, reasons, 3)
+	require.Contains(t, reasons[notFromWallet.TxHash()], "not sent from connected wallet")
+	require.Contains(t, reasons[notConfirmed.TxHash()], "not included in btc chain")
+	require.Contains(t, reasons[oldCommittee.TxHash()], "cannot be reconstructed")
+}
+
+func TestRecoverDbRefusesNonEmptyTarget(t *testing.T) {
+	f := newRecoveryFixture(t)
+
+	stakingTx, _ := f.addDelegation(t, []*btcec.PublicKey{f.covenantKey.PubKey()})
+	f.confirm(stakingTx, 10)
+	f.setDelegationInfo(stakingTx, &cl.DelegationInfo{})
+	f.addUtxo(stakingTx)
+
+	result, err := f.recover(t, false)
+	require.NoError(t, err)
+	require.Len(t, result.Recovered, 1)
+
+	_, err = f.recover(t, false)
+	require.ErrorIs(t, err, stakerdb.ErrRecoveryTargetNotEmpty)
+}
+
+func TestRecoverDbRefusesDaemonDatabase(t *testing.T) {
+	f := newRecoveryFixture(t)
+
+	dbConfig := scfg.DefaultDBConfig()
+	dbConfig.DBPath = t.TempDir()
+	f.deps.config.DBConfig = &dbConfig
+	f.targetDbPath = filepath.Join(dbConfig.DBPath, dbConfig.DBFileName)
+
+	_, err := f.recover(t, false)
+	require.ErrorContains(t, err, "database of running daemon")
+}
+
+func TestRecoverDbDryRunWritesNothing(t *testing.T) {
+	f := newRecoveryFixture(t)
+
+	stakingTx, _ := f.addDelegation(t, []*btcec.PublicKey{f.covenantKey.PubKey()})
+	f.confirm(stakingTx, 10)
+	f.setDelegationInfo(stakingTx, &cl.DelegationInfo{})
+	f.addUtxo(stakingTx)
+
+	result, err := f.recover(t, true)
+	require.NoError(t, err)
+	require.True(t, result.DryRun)
+	require.Empty(t, result.TargetDbPath)
+	require.Equal(t, []RecoveredTransaction{{
+		StakingTxHash: stakingTx.TxHash(),
+		State:         proto.TransactionState_CONFIRMED_ON_BTC,
+	}}, result.Recovered)
+
+	_, err = os.Stat(f.targetDbPath)
+	require.True(t, os.IsNotExist(err))
+}
@@ -0,0 +1,158 @@
+package staker
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/babylonchain/btc-staker/walletcontroller"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	notifier "github.com/lightningnetwork/lnd/chainntnfs"
+	"github.com/sirupsen/logrus"
+)
+
+type WalletRescan struct {
+	TxHash      chainhash.Hash
+	StartHeight uint32
+	StartedAt   time.Time
+}
+
+type RescanStatus struct {
+	Rescans []WalletRescan
+	// progress reported by wallet, nil if wallet does not report it or is not
+	// rescanning at the moment
+	WalletProgress *walletcontroller.RescanProgress
+}
+
+// walletRescans keeps rescans which are in progress, so that callers waiting for
+// them can check why their request takes long time
+type walletRescans struct {
+	mu      sync.Mutex
+	rescans map[chainhash.Hash]WalletRescan
+}
+
+func newWalletRescans() *walletRescans {
+	return &walletRescans{
+		rescans: make(map[chainhash.Hash]WalletRescan),
+	}
+}
+
+func (r *walletRescans) started(rescan WalletRescan) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.rescans[rescan.TxHash] = rescan
+}
+
+func (r *walletRescans) finished(txHash chainhash.Hash) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.rescans, txHash)
+}
+
+// list returns rescans in progress, ordered by start time
+func (r *walletRescans) list() []WalletRescan {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	rescans := make([]WalletRescan, 0, len(r.rescans))
+
+	for _, rescan := range r.rescans {
+		rescans = append(rescans, rescan)
+	}
+
+	sort.Slice(rescans, func(i, j int) bool {
+		return rescans[i].StartedAt.Before(rescans[j].StartedAt)
+	})
+
+	return rescans
+}
+
+// rescanStartHeight returns height from which wallet should be rescanned. If
+// caller did not provide start height, it is estimated as max rescan depth from
+// the current best block.
+func (app *StakerApp) rescanStartHeight(requested *uint32) (uint32, error) {
+	bestHeight := app.currentBestBlockHeight.Load()
+	maxBlocks := app.config.StakerConfig.MaxRescanBlocks
+
+	var lowestHeight uint32
+	if bestHeight > maxBlocks {
+		lowestHeight = bestHeight - maxBlocks
+	}
+
+	if requested == nil {
+		return lowestHeight, nil
+	}
+
+	if *requested < lowestHeight {
+		return 0, fmt.Errorf(
+			"rescan start height %d is more than %d blocks below best block %d",
+			*requested,
+			maxBlocks,
+			bestHeight,
+		)
+	}
+
+	return *requested, nil
+}
+
+// txDetailsWithRescan returns details of transaction. Transaction not created by
+// staker may be not found only because wallet never scanned for its outputs, so in
+// that case output script is imported to the wallet as watch-only and chain is
+// rescanned from start height, before transaction is checked again.
+func (app *StakerApp) txDetailsWithRescan(
+	txHash *chainhash.Hash,
+	pkScript []byte,
+	rescanStartHeight *uint32,
+) (*notifier.TxConfirmation, walletcontroller.TxStatus, error) {
+	details, status, err := app.wc.TxDetails(txHash, pkScript)
+
+	if err != nil || status != walletcontroller.TxNotFound {
+		return details, status, err
+	}
+
+	startHeight, err := app.rescanStartHeight(rescanStartHeight)
+
+	if err != nil {
+		return nil, walletcontroller.TxNotFound, err
+	}
+
+	logger := app.logger.WithFields(logrus.Fields{
+		"txHash":      txHash,
+		"startHeight": startHeight,
+	})
+
+	logger.Info("Transaction not found. Importing its output to wallet and rescanning chain")
+
+	app.rescans.started(WalletRescan{
+		TxHash:      *txHash,
+		StartHeight: startHeight,
+		StartedAt:   app.clock.Now(),
+	})
+	defer app.rescans.finished(*txHash)
+
+	if err := app.wc.ImportAddressWithRescan(pkScript, startHeight); err != nil {
+		return nil, walletcontroller.TxNotFound, fmt.Errorf("failed to rescan wallet for transaction %s: %w", txHash, err)
+	}
+
+	logger.Info("Finished rescanning chain")
+
+	return app.wc.TxDetails(txHash, pkScript)
+}
+
+// RescanStatus returns wallet rescans started by staker which are still in
+// progress, together with progress reported by the wallet
+func (app *StakerApp) RescanStatus() (*RescanStatus, error) {
+	progress, err := app.wc.RescanProgress()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &RescanStatus{
+		Rescans:        app.rescans.list(),
+		WalletProgress: progress,
+	}, nil
+}
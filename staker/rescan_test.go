@@ -0,0 +1,43 @@
+package staker
+
+import (
+	"testing"
+
+	scfg "github.com/babylonchain/btc-staker/stakercfg"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRescanStartHeight(t *testing.T) {
+	cfg := scfg.DefaultConfig()
+	cfg.StakerConfig.MaxRescanBlocks = 100
+
+	app := &StakerApp{config: &cfg}
+	app.currentBestBlockHeight.Store(1000)
+
+	height := func(h uint32) *uint32 {
+		return &h
+	}
+
+	// estimated from max rescan depth
+	startHeight, err := app.rescanStartHeight(nil)
+	require.NoError(t, err)
+	require.Equal(t, uint32(900), startHeight)
+
+	startHeight, err = app.rescanStartHeight(height(950))
+	require.NoError(t, err)
+	require.Equal(t, uint32(950), startHeight)
+
+	startHeight, err = app.rescanStartHeight(height(900))
+	require.NoError(t, err)
+	require.Equal(t, uint32(900), startHeight)
+
+	// rescan would be deeper than allowed
+	_, err = app.rescanStartHeight(height(899))
+	require.Error(t, err)
+
+	// chain shorter than max rescan depth is rescanned from genesis
+	app.currentBestBlockHeight.Store(50)
+	startHeight, err = app.rescanStartHeight(nil)
+	require.NoError(t, err)
+	require.Equal(t, uint32(0), startHeight)
+}
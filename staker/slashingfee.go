@@ -0,0 +1,126 @@
+package staker
+
+import (
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcwallet/wallet/txrules"
+)
+
+const (
+	// schnorr signature with sighash default
+	schnorrSigSize = 64
+
+	// OP_DATA_32 <32 byte x-only key> OP_CHECKSIG(VERIFY/ADD)
+	xOnlyKeySigScriptSize = 34
+
+	// control block of the deepest leaf of staking output script tree, which has
+	// three leaves: version byte, internal key and two hashes of merkle path
+	stakingControlBlockSize = 1 + 32 + 2*32
+
+	// version, number of inputs and outputs, lock time
+	txOverheadSize = 4 + 1 + 1 + 4
+
+	// outpoint, empty signature script and sequence
+	txInSize = 36 + 1 + 4
+
+	// segwit marker and flag, which are counted as witness data
+	segwitMarkerAndFlagSize = 2
+)
+
+// multiSigScriptSize returns size of script requiring signatures of quorum of
+// numKeys x-only keys. Single key is checked by OP_CHECKSIGVERIFY, more keys by
+// OP_CHECKSIGADD followed by quorum and OP_NUMEQUALVERIFY.
+func multiSigScriptSize(numKeys uint32, quorum uint32) int {
+	if numKeys <= 1 {
+		return xOnlyKeySigScriptSize
+	}
+
+	quorumScript, _ := txscript.NewScriptBuilder().AddInt64(int64(quorum)).Script()
+
+	return int(numKeys)*xOnlyKeySigScriptSize + len(quorumScript) + 1
+}
+
+func witnessItemSize(itemSize int) int {
+	return wire.VarIntSerializeSize(uint64(itemSize)) + itemSize
+}
+
+// slashingTxVSize estimates virtual size of slashing transaction, which spends
+// staking or unbonding output through slashing path, and pays to slashing
+// address and to staker change output. Estimate assumes that every finality
+// provider and covenant member signs, so it is an upper bound of the actual size.
+func slashingTxVSize(
+	slashingPkScript []byte,
+	numFinalityProviders uint32,
+	numCovenants uint32,
+	covenantQuorum uint32,
+) int64 {
+	slashingOutput := wire.NewTxOut(0, slashingPkScript)
+	// change output is always p2tr output locked by staker key and timelock
+	changeOutput := wire.NewTxOut(0, make([]byte, p2trScriptSize))
+
+	baseSize := txOverheadSize + txInSize + slashingOutput.SerializeSize() + changeOutput.SerializeSize()
+
+	// slashing path requires signatures of staker, finality providers and quorum
+	// of covenant members
+	scriptSize := xOnlyKeySigScriptSize +
+		multiSigScriptSize(numFinalityProviders, 1) +
+		multiSigScriptSize(numCovenants, covenantQuorum)
+
+	numWitnessItems := 1 + numFinalityProviders + numCovenants + 2
+
+	witnessSize := segwitMarkerAndFlagSize +
+		wire.VarIntSerializeSize(uint64(numWitnessItems)) +
+		int(1+numFinalityProviders+numCovenants)*witnessItemSize(schnorrSigSize) +
+		witnessItemSize(scriptSize) +
+		witnessItemSize(stakingControlBlockSize)
+
+	weight := baseSize*4 + witnessSize
+
+	return int64((weight + 3) / 4)
+}
+
+// requiredSlashingFee returns fee which slashing transactions paying to slashing
+// address must use. Fee required by babylon is raised if it does not pay internal
+// minimum fee rate for slashing transaction of estimated size.
+func requiredSlashingFee(
+	feeFromBabylon btcutil.Amount,
+	slashingAddress btcutil.Address,
+	numFinalityProviders uint32,
+	numCovenants uint32,
+	covenantQuorum uint32,
+) (btcutil.Amount, error) {
+	if slashingAddress == nil {
+		return 0, fmt.Errorf("slashing address not provided in babylon params")
+	}
+
+	slashingPkScript, err := txscript.PayToAddrScript(slashingAddress)
+
+	if err != nil {
+		return 0, fmt.Errorf("unsupported slashing address %s: %w", slashingAddress, err)
+	}
+
+	txSize := slashingTxVSize(slashingPkScript, numFinalityProviders, numCovenants, covenantQuorum)
+
+	fee := feeFromBabylon
+	minFee := txrules.FeeForSerializeSize(minSlashingFeeRatePerKb, int(txSize))
+
+	if fee < minFee {
+		fee = minFee
+	}
+
+	// slashing transaction is only broadcast by babylon when staker is slashed, at
+	// which point it is too late to bump its fee, so it must be relayable
+	if feeRate := fee * 1000 / btcutil.Amount(txSize); feeRate < MinFeePerKb {
+		return 0, fmt.Errorf(
+			"slashing tx fee %d sats for tx of %d vbytes is below minimum relay fee rate %d sats/kb",
+			int64(fee),
+			txSize,
+			int64(MinFeePerKb),
+		)
+	}
+
+	return fee, nil
+}
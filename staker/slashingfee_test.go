@@ -0,0 +1,102 @@
+package staker
+
+import (
+	"testing"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/mempool"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcwallet/wallet/txrules"
+	"github.com/stretchr/testify/require"
+)
+
+// buildTestSlashingTx builds slashing transaction with witness of the same shape as
+// witness of slashing path spend, so that its actual size can be compared with
+// the estimate
+func buildTestSlashingTx(
+	slashingPkScript []byte,
+	numFinalityProviders uint32,
+	numCovenants uint32,
+	covenantQuorum uint32,
+) *wire.MsgTx {
+	tx := wire.NewMsgTx(2)
+	tx.AddTxIn(wire.NewTxIn(&wire.OutPoint{}, nil, nil))
+	tx.AddTxOut(wire.NewTxOut(100000, slashingPkScript))
+	tx.AddTxOut(wire.NewTxOut(100000, make([]byte, p2trScriptSize)))
+
+	scriptSize := xOnlyKeySigScriptSize +
+		multiSigScriptSize(numFinalityProviders, 1) +
+		multiSigScriptSize(numCovenants, covenantQuorum)
+
+	var witness wire.TxWitness
+	for i := uint32(0); i < 1+numFinalityProviders+numCovenants; i++ {
+		witness = append(witness, make([]byte, schnorrSigSize))
+	}
+	witness = append(witness, make([]byte, scriptSize), make([]byte, stakingControlBlockSize))
+	tx.TxIn[0].Witness = witness
+
+	return tx
+}
+
+func TestSlashingFeeForSlashingAddressTypes(t *testing.T) {
+	net := &chaincfg.MainNetParams
+
+	p2pkh, err := btcutil.NewAddressPubKeyHash(make([]byte, 20), net)
+	require.NoError(t, err)
+	p2wpkh, err := btcutil.NewAddressWitnessPubKeyHash(make([]byte, 20), net)
+	require.NoError(t, err)
+	p2tr, err := btcutil.NewAddressTaproot(make([]byte, 32), net)
+	require.NoError(t, err)
+	p2sh, err := btcutil.NewAddressScriptHashFromHash(make([]byte, 20), net)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name            string
+		slashingAddress btcutil.Address
+		numFps          uint32
+		numCovenants    uint32
+		covenantQuorum  uint32
+		babylonFee      btcutil.Amount
+	}{
+		{"p2pkh", p2pkh, 1, 1, 1, 1000},
+		{"p2wpkh", p2wpkh, 1, 3, 2, 1000},
+		{"p2tr", p2tr, 1, 5, 3, 1000},
+		{"p2sh", p2sh, 2, 9, 6, 1000},
+		{"p2sh with large babylon fee", p2sh, 1, 9, 6, 100000},
+		{"p2tr with zero babylon fee", p2tr, 1, 1, 1, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pkScript, err := txscript.PayToAddrScript(tt.slashingAddress)
+			require.NoError(t, err)
+
+			estimatedSize := slashingTxVSize(pkScript, tt.numFps, tt.numCovenants, tt.covenantQuorum)
+			actualSize := mempool.GetTxVirtualSize(btcutil.NewTx(
+				buildTestSlashingTx(pkScript, tt.numFps, tt.numCovenants, tt.covenantQuorum),
+			))
+			require.Equal(t, actualSize, estimatedSize)
+
+			fee, err := requiredSlashingFee(
+				tt.babylonFee,
+				tt.slashingAddress,
+				tt.numFps,
+				tt.numCovenants,
+				tt.covenantQuorum,
+			)
+			require.NoError(t, err)
+
+			minFee := txrules.FeeForSerializeSize(minSlashingFeeRatePerKb, int(estimatedSize))
+
+			if tt.babylonFee >= minFee {
+				require.Equal(t, tt.babylonFee, fee)
+			} else {
+				require.Equal(t, minFee, fee)
+			}
+
+			require.GreaterOrEqual(t, fee*1000/btcutil.Amount(estimatedSize), MinFeePerKb)
+		})
+	}
+}
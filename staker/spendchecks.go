@@ -0,0 +1,70 @@
+package staker
+
+import (
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/mempool"
+	"github.com/btcsuite/btcd/wire"
+)
+
+// ErrSpendWouldBeDust is returned when, after subtracting the fee, output of spend
+// stake transaction would be below dust threshold of its destination script, and
+// transaction would be rejected by the network
+type ErrSpendWouldBeDust struct {
+	OutputValue btcutil.Amount
+	MinValue    btcutil.Amount
+}
+
+func (e *ErrSpendWouldBeDust) Error() string {
+	return fmt.Sprintf(
+		"spend stake tx output would be dust. output value: %s, minimum value: %s",
+		e.OutputValue,
+		e.MinValue,
+	)
+}
+
+// minNonDustValue returns minimum value of output with given script, which is not
+// considered dust with default relay fee
+func minNonDustValue(pkScript []byte) btcutil.Amount {
+	output := wire.NewTxOut(0, pkScript)
+	// mempool.IsDust is true when value * 1000 / threshold < relay fee, so minimum
+	// value is relay fee * threshold / 1000 rounded up
+	minValue := (int64(MinFeePerKb)*mempool.GetDustThreshold(output) + 999) / 1000
+	return btcutil.Amount(minValue)
+}
+
+// checkSpendOutputNotDust checks that output of spend stake transaction will be
+// accepted by the network
+func checkSpendOutputNotDust(output *wire.TxOut) error {
+	if mempool.IsDust(output, MinFeePerKb) {
+		return &ErrSpendWouldBeDust{
+			OutputValue: btcutil.Amount(output.Value),
+			MinValue:    minNonDustValue(output.PkScript),
+		}
+	}
+
+	return nil
+}
+
+// checkSpendFee checks that fee of spend stake transaction does not exceed given
+// fraction of the spent stake value. Nil fraction means there is no limit.
+func checkSpendFee(fee btcutil.Amount, stakeValue btcutil.Amount, maxFeeFraction *float64) error {
+	if maxFeeFraction == nil {
+		return nil
+	}
+
+	maxFee := btcutil.Amount(float64(stakeValue) * *maxFeeFraction)
+
+	if fee > maxFee {
+		return fmt.Errorf(
+			"spend stake tx fee %s exceeds maximum fee %s (%.4f of stake value %s)",
+			fee,
+			maxFee,
+			*maxFeeFraction,
+			stakeValue,
+		)
+	}
+
+	return nil
+}
@@ -0,0 +1,37 @@
+package staker
+
+import (
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckSpendOutputNotDust(t *testing.T) {
+	privKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	pkScript, err := txscript.PayToTaprootScript(privKey.PubKey())
+	require.NoError(t, err)
+
+	minValue := minNonDustValue(pkScript)
+	require.Positive(t, minValue)
+
+	require.NoError(t, checkSpendOutputNotDust(wire.NewTxOut(int64(minValue), pkScript)))
+
+	err = checkSpendOutputNotDust(wire.NewTxOut(int64(minValue)-1, pkScript))
+	var dustErr *ErrSpendWouldBeDust
+	require.ErrorAs(t, err, &dustErr)
+	require.Equal(t, minValue-1, dustErr.OutputValue)
+	require.Equal(t, minValue, dustErr.MinValue)
+}
+
+func TestCheckSpendFee(t *testing.T) {
+	maxFeeFraction := 0.1
+
+	require.NoError(t, checkSpendFee(100, 1000, nil))
+	require.NoError(t, checkSpendFee(100, 1000, &maxFeeFraction))
+	require.Error(t, checkSpendFee(101, 1000, &maxFeeFraction))
+}
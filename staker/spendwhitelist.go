@@ -0,0 +1,160 @@
+package staker
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/babylonchain/btc-staker/stakerdb"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// sources of whitelisted spend destinations
+	WhitelistSourceConfig = "config"
+	WhitelistSourceDb     = "db"
+)
+
+// ErrDestinationNotWhitelisted is returned when spend destination is neither staker
+// address nor whitelisted address, while spend destination whitelist is not empty
+type ErrDestinationNotWhitelisted struct {
+	Address string
+}
+
+func (e *ErrDestinationNotWhitelisted) Error() string {
+	return fmt.Sprintf("spend destination %s is not whitelisted", e.Address)
+}
+
+// WhitelistedAddress is an address to which staked funds can be spent
+type WhitelistedAddress struct {
+	Address string
+	// whether address comes from config or was added through rpc
+	Source string
+}
+
+// SpendWhitelist returns addresses from both config and db, sorted by address.
+// Address present in both is reported with config source, as it cannot be
+// removed at runtime.
+func (app *StakerApp) SpendWhitelist() ([]WhitelistedAddress, error) {
+	stored, err := app.txTracker.GetWhitelistedAddresses()
+
+	if err != nil {
+		return nil, err
+	}
+
+	sources := make(map[string]string)
+
+	for _, address := range stored {
+		sources[address] = WhitelistSourceDb
+	}
+
+	for _, address := range app.config.StakerConfig.SpendDestinationWhitelist {
+		sources[address] = WhitelistSourceConfig
+	}
+
+	whitelist := make([]WhitelistedAddress, 0, len(sources))
+
+	for address, source := range sources {
+		whitelist = append(whitelist, WhitelistedAddress{
+			Address: address,
+			Source:  source,
+		})
+	}
+
+	sort.Slice(whitelist, func(i, j int) bool {
+		return whitelist[i].Address < whitelist[j].Address
+	})
+
+	return whitelist, nil
+}
+
+// checkSpendDestination returns ErrDestinationNotWhitelisted if whitelist is not
+// empty and destination is neither staker address nor whitelisted. It must be
+// called before spend transaction is signed.
+func (app *StakerApp) checkSpendDestination(destAddress, stakerAddress btcutil.Address) error {
+	if destAddress.EncodeAddress() == stakerAddress.EncodeAddress() {
+		return nil
+	}
+
+	whitelist, err := app.SpendWhitelist()
+
+	if err != nil {
+		return fmt.Errorf("cannot retrieve spend destination whitelist: %w", err)
+	}
+
+	if len(whitelist) == 0 {
+		return nil
+	}
+
+	for _, whitelisted := range whitelist {
+		if whitelisted.Address == destAddress.EncodeAddress() {
+			return nil
+		}
+	}
+
+	return &ErrDestinationNotWhitelisted{Address: destAddress.EncodeAddress()}
+}
+
+func (app *StakerApp) decodeWhitelistAddress(address string) (btcutil.Address, error) {
+	decoded, err := btcutil.DecodeAddress(address, app.network)
+
+	if err != nil {
+		return nil, fmt.Errorf("invalid address %s: %w", address, err)
+	}
+
+	if !decoded.IsForNet(app.network) {
+		return nil, fmt.Errorf("address %s is not valid for network %s", address, app.network.Name)
+	}
+
+	return decoded, nil
+}
+
+// AddWhitelistedAddress adds address to the whitelist stored in db
+func (app *StakerApp) AddWhitelistedAddress(address string) error {
+	decoded, err := app.decodeWhitelistAddress(address)
+
+	if err != nil {
+		return err
+	}
+
+	if err := app.txTracker.AddWhitelistedAddress(decoded.EncodeAddress()); err != nil {
+		return err
+	}
+
+	app.logger.WithFields(logrus.Fields{
+		"address": decoded.EncodeAddress(),
+	}).Warn("Address added to spend destination whitelist")
+
+	return nil
+}
+
+// RemoveWhitelistedAddress removes address from the whitelist stored in db.
+// Addresses from config can only be removed by changing config.
+func (app *StakerApp) RemoveWhitelistedAddress(address string) error {
+	decoded, err := app.decodeWhitelistAddress(address)
+
+	if err != nil {
+		return err
+	}
+
+	for _, configured := range app.config.StakerConfig.SpendDestinationWhitelist {
+		if configured == decoded.EncodeAddress() {
+			return fmt.Errorf("address %s is whitelisted in config and cannot be removed at runtime", address)
+		}
+	}
+
+	if err := app.txTracker.RemoveWhitelistedAddress(decoded.EncodeAddress()); err != nil {
+		if errors.Is(err, stakerdb.ErrWhitelistedAddressNotFound) {
+			return fmt.Errorf("address %s is not whitelisted", address)
+		}
+
+		return err
+	}
+
+	app.logger.WithFields(logrus.Fields{
+		"address": decoded.EncodeAddress(),
+	}).Warn("Address removed from spend destination whitelist")
+
+	return nil
+}
@@ -0,0 +1,287 @@
+package staker
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/babylonchain/btc-staker/stakerdb"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/sirupsen/logrus"
+)
+
+// ErrStakedValueCapReached is returned when staking transaction would exceed
+// maximum value of staking transactions sent within rolling window of btc blocks
+var ErrStakedValueCapReached = errors.New("staked value cap reached")
+
+// ErrStakedValueCap describes stake rejected by staked value cap
+type ErrStakedValueCap struct {
+	Requested btcutil.Amount
+	// value of staking transactions sent within the window
+	InWindow     btcutil.Amount
+	Cap          btcutil.Amount
+	WindowBlocks uint32
+	// height of the first block at which the stake fits into the cap, 0 if the
+	// stake exceeds the cap on its own
+	FreesAtHeight uint32
+}
+
+func (e *ErrStakedValueCap) Error() string {
+	if e.FreesAtHeight == 0 {
+		return fmt.Sprintf(
+			"%s: stake of %s exceeds cap of %s per %d blocks",
+			ErrStakedValueCapReached, e.Requested, e.Cap, e.WindowBlocks,
+		)
+	}
+
+	return fmt.Sprintf(
+		"%s: %s staked within last %d blocks, stake of %s would exceed cap of %s. Capacity frees up at height %d",
+		ErrStakedValueCapReached, e.InWindow, e.WindowBlocks, e.Requested, e.Cap, e.FreesAtHeight,
+	)
+}
+
+func (e *ErrStakedValueCap) Unwrap() error { return ErrStakedValueCapReached }
+
+// StakedValueCapStatus describes staked value cap and its current usage
+type StakedValueCapStatus struct {
+	// zero if cap is disabled
+	Cap          btcutil.Amount
+	WindowBlocks uint32
+	// true if cap and window were overridden by operator
+	Overridden bool
+	InWindow   btcutil.Amount
+	// number of stakes rejected since staker started
+	Rejections uint64
+}
+
+type stakedValueEntry struct {
+	txHash chainhash.Hash
+	height uint32
+	amount btcutil.Amount
+}
+
+// stakedValueCap limits value of staking transactions sent within rolling window
+// of btc blocks, to limit damage done by compromised rpc caller. Sent staking
+// transactions are only tracked in memory, and are restored from db on startup.
+type stakedValueCap struct {
+	mu sync.Mutex
+	// configured cap and window, replaced by override
+	limit        btcutil.Amount
+	windowBlocks uint32
+	overridden   bool
+	// sent staking transactions ordered by broadcast height
+	entries    []stakedValueEntry
+	rejections uint64
+}
+
+func newStakedValueCap(limit btcutil.Amount, windowBlocks uint32) *stakedValueCap {
+	return &stakedValueCap{
+		limit:        limit,
+		windowBlocks: windowBlocks,
+	}
+}
+
+// prune drops transactions sent before the window ending at currentHeight. Must
+// be called with mutex held.
+func (c *stakedValueCap) prune(currentHeight uint32) {
+	i := 0
+	for i < len(c.entries) && c.entries[i].height+c.windowBlocks <= currentHeight {
+		i++
+	}
+
+	c.entries = c.entries[i:]
+}
+
+// inWindow returns value of transactions within the window. Must be called with
+// mutex held.
+func (c *stakedValueCap) inWindow() btcutil.Amount {
+	var total btcutil.Amount
+
+	for _, e := range c.entries {
+		total += e.amount
+	}
+
+	return total
+}
+
+// check returns ErrStakedValueCap if stake of given amount would exceed the cap at
+// currentHeight, and counts the rejection
+func (c *stakedValueCap) check(amount btcutil.Amount, currentHeight uint32) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.limit == 0 {
+		return nil
+	}
+
+	c.prune(currentHeight)
+
+	inWindow := c.inWindow()
+
+	if inWindow+amount <= c.limit {
+		return nil
+	}
+
+	c.rejections++
+
+	capErr := &ErrStakedValueCap{
+		Requested:    amount,
+		InWindow:     inWindow,
+		Cap:          c.limit,
+		WindowBlocks: c.windowBlocks,
+	}
+
+	if amount > c.limit {
+		return capErr
+	}
+
+	// capacity frees up as the oldest transactions leave the window
+	for _, e := range c.entries {
+		inWindow -= e.amount
+
+		if inWindow+amount <= c.limit {
+			capErr.FreesAtHeight = e.height + c.windowBlocks
+			break
+		}
+	}
+
+	return capErr
+}
+
+// add records staking transaction sent at given height. Transactions already
+// tracked are ignored.
+func (c *stakedValueCap) add(txHash chainhash.Hash, amount btcutil.Amount, height uint32) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, e := range c.entries {
+		if e.txHash == txHash {
+			return
+		}
+	}
+
+	c.entries = append(c.entries, stakedValueEntry{
+		txHash: txHash,
+		height: height,
+		amount: amount,
+	})
+
+	sort.SliceStable(c.entries, func(i, j int) bool {
+		return c.entries[i].height < c.entries[j].height
+	})
+}
+
+func (c *stakedValueCap) override(limit btcutil.Amount, windowBlocks uint32) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.limit = limit
+	c.windowBlocks = windowBlocks
+	c.overridden = true
+}
+
+func (c *stakedValueCap) status(currentHeight uint32) StakedValueCapStatus {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.prune(currentHeight)
+
+	return StakedValueCapStatus{
+		Cap:          c.limit,
+		WindowBlocks: c.windowBlocks,
+		Overridden:   c.overridden,
+		InWindow:     c.inWindow(),
+		Rejections:   c.rejections,
+	}
+}
+
+// loadStakedValueCap restores staking transactions sent within the window from
+// db, so that restart does not reset the cap
+func (app *StakerApp) loadStakedValueCap() error {
+	currentHeight := app.currentBestBlockHeight.Load()
+
+	reset := func() {
+		app.stakedValueCap.mu.Lock()
+		app.stakedValueCap.entries = nil
+		app.stakedValueCap.mu.Unlock()
+	}
+
+	err := app.txTracker.ScanTrackedTransactions(func(tx *stakerdb.StoredTransaction) error {
+		// watched transactions were not sent by staker, and broadcast height of
+		// older transactions is not known
+		if tx.Watched || tx.BroadcastHeight == 0 {
+			return nil
+		}
+
+		app.stakedValueCap.add(
+			tx.StakingTx.TxHash(),
+			btcutil.Amount(tx.StakingTx.TxOut[tx.StakingOutputIndex].Value),
+			tx.BroadcastHeight,
+		)
+
+		return nil
+	}, reset)
+
+	if err != nil {
+		return err
+	}
+
+	status := app.stakedValueCap.status(currentHeight)
+
+	if status.Cap > 0 {
+		app.logger.WithFields(logrus.Fields{
+			"cap":          status.Cap,
+			"windowBlocks": status.WindowBlocks,
+			"inWindow":     status.InWindow,
+		}).Info("Loaded value of recently sent staking transactions")
+	}
+
+	return nil
+}
+
+// checkStakedValueCap returns ErrStakedValueCap if staking transaction of given
+// value cannot be sent at current height
+func (app *StakerApp) checkStakedValueCap(amount btcutil.Amount) error {
+	err := app.stakedValueCap.check(amount, app.currentBestBlockHeight.Load())
+
+	var capErr *ErrStakedValueCap
+
+	if errors.As(err, &capErr) {
+		app.logger.WithFields(logrus.Fields{
+			"requested":     capErr.Requested,
+			"inWindow":      capErr.InWindow,
+			"cap":           capErr.Cap,
+			"freesAtHeight": capErr.FreesAtHeight,
+		}).Warn("Stake rejected by staked value cap")
+	}
+
+	return err
+}
+
+// StakedValueCap returns staked value cap and its current usage
+func (app *StakerApp) StakedValueCap() StakedValueCapStatus {
+	return app.stakedValueCap.status(app.currentBestBlockHeight.Load())
+}
+
+// OverrideStakedValueCap replaces configured staked value cap and window until
+// staker restarts. Zero cap disables the limit.
+func (app *StakerApp) OverrideStakedValueCap(limit btcutil.Amount, windowBlocks uint32) error {
+	if limit < 0 {
+		return fmt.Errorf("staked value cap must not be negative")
+	}
+
+	if windowBlocks == 0 {
+		return fmt.Errorf("staked value window must be greater than 0 blocks")
+	}
+
+	app.stakedValueCap.override(limit, windowBlocks)
+
+	app.logger.WithFields(logrus.Fields{
+		"cap":          limit,
+		"windowBlocks": windowBlocks,
+	}).Warn("Staked value cap overridden by operator")
+
+	return nil
+}
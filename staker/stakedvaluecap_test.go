@@ -0,0 +1,78 @@
+package staker
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStakedValueCapRejectsStakeAboveCap(t *testing.T) {
+	valueCap := newStakedValueCap(btcutil.Amount(1000), 6)
+
+	valueCap.add(chainhash.HashH([]byte("tx1")), btcutil.Amount(400), 100)
+	valueCap.add(chainhash.HashH([]byte("tx2")), btcutil.Amount(500), 102)
+
+	require.NoError(t, valueCap.check(btcutil.Amount(100), 103))
+
+	err := valueCap.check(btcutil.Amount(200), 103)
+	require.ErrorIs(t, err, ErrStakedValueCapReached)
+
+	var capErr *ErrStakedValueCap
+	require.True(t, errors.As(err, &capErr))
+	require.Equal(t, btcutil.Amount(900), capErr.InWindow)
+	// first transaction leaves the window at height 106
+	require.Equal(t, uint32(106), capErr.FreesAtHeight)
+
+	// stake which needs both transactions to leave the window
+	err = valueCap.check(btcutil.Amount(700), 103)
+	require.True(t, errors.As(err, &capErr))
+	require.Equal(t, uint32(108), capErr.FreesAtHeight)
+
+	// stake above the cap never fits
+	err = valueCap.check(btcutil.Amount(1001), 103)
+	require.True(t, errors.As(err, &capErr))
+	require.Equal(t, uint32(0), capErr.FreesAtHeight)
+
+	require.Equal(t, uint64(3), valueCap.status(103).Rejections)
+}
+
+func TestStakedValueCapFreesCapacityAfterWindow(t *testing.T) {
+	valueCap := newStakedValueCap(btcutil.Amount(1000), 6)
+
+	valueCap.add(chainhash.HashH([]byte("tx1")), btcutil.Amount(1000), 100)
+
+	require.Error(t, valueCap.check(btcutil.Amount(1), 105))
+	require.NoError(t, valueCap.check(btcutil.Amount(1000), 106))
+	require.Equal(t, btcutil.Amount(0), valueCap.status(106).InWindow)
+}
+
+func TestStakedValueCapIgnoresTrackedTransactions(t *testing.T) {
+	valueCap := newStakedValueCap(btcutil.Amount(1000), 6)
+	txHash := chainhash.HashH([]byte("tx1"))
+
+	valueCap.add(txHash, btcutil.Amount(600), 100)
+	valueCap.add(txHash, btcutil.Amount(600), 100)
+
+	require.Equal(t, btcutil.Amount(600), valueCap.status(100).InWindow)
+}
+
+func TestStakedValueCapOverride(t *testing.T) {
+	valueCap := newStakedValueCap(btcutil.Amount(0), 6)
+
+	valueCap.add(chainhash.HashH([]byte("tx1")), btcutil.Amount(600), 100)
+
+	// zero cap disables the limit
+	require.NoError(t, valueCap.check(btcutil.Amount(1_000_000), 100))
+
+	valueCap.override(btcutil.Amount(1000), 3)
+	require.Error(t, valueCap.check(btcutil.Amount(500), 102))
+	require.NoError(t, valueCap.check(btcutil.Amount(500), 103))
+
+	status := valueCap.status(103)
+	require.True(t, status.Overridden)
+	require.Equal(t, btcutil.Amount(1000), status.Cap)
+	require.Equal(t, uint32(3), status.WindowBlocks)
+}
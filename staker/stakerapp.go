@@ -32,7 +32,6 @@ import (
 	"github.com/cometbft/cometbft/crypto/tmhash"
 	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
 	notifier "github.com/lightningnetwork/lnd/chainntnfs"
-	"github.com/lightningnetwork/lnd/channeldb"
 	"github.com/lightningnetwork/lnd/kvdb"
 	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
 	"github.com/sirupsen/logrus"
@@ -60,9 +59,10 @@ var (
 	RtyErr            = retry.LastErrorOnly(true)
 )
 
-func longRetryOps(ctx context.Context, fixedDelay time.Duration, onRetryFn retry.OnRetryFunc) []retry.Option {
+func longRetryOps(ctx context.Context, clock utils.Clock, fixedDelay time.Duration, onRetryFn retry.OnRetryFunc) []retry.Option {
 	return []retry.Option{
 		retry.Context(ctx),
+		retry.WithTimer(clock),
 		retry.DelayType(retry.FixedDelay),
 		retry.Delay(fixedDelay),
 		longRetryAttempts,
@@ -71,8 +71,10 @@ func longRetryOps(ctx context.Context, fixedDelay time.Duration, onRetryFn retry
 	}
 }
 
-func (app *StakerApp) onLongRetryFunc(stakingTxHash *chainhash.Hash, msg string) retry.OnRetryFunc {
+func (app *StakerApp) onLongRetryFunc(stakingTxHash *chainhash.Hash, operation string, msg string) retry.OnRetryFunc {
 	return func(n uint, err error) {
+		app.inFlight.failedAttempt(operation, *stakingTxHash, err)
+
 		app.logger.WithFields(logrus.Fields{
 			"attempt":      n + 1,
 			"max_attempts": longRetryNum,
@@ -83,42 +85,29 @@ func (app *StakerApp) onLongRetryFunc(stakingTxHash *chainhash.Hash, msg string)
 }
 
 const (
-	// Internal slashing fee to adjust to in case babylon provide too small fee
-	// Slashing tx is around 113 bytes (depending on output address which we need to chose), with fee 8sats/b
-	// this gives us 904 satoshi fee. Lets round it 1000 satoshi
-	minSlashingFee = btcutil.Amount(1000)
+	// Internal slashing fee rate to adjust to in case babylon provide too small fee.
+	// Slashing tx size depends on slashing address and covenant committee, so fee is
+	// computed from size estimated by slashingTxVSize. 8 sats/vb
+	minSlashingFeeRatePerKb = btcutil.Amount(8000)
 
 	// after this many confirmations we consider transaction which spends staking tx as
 	// confirmed on btc
 	SpendStakeTxConfirmations = 3
 
-	// 2 hours seems like a reasonable timeout waiting for spend tx confirmations given
-	// probabilistic nature of bitcoin
-	timeoutWaitingForSpendConfirmation = 2 * time.Hour
-
-	defaultWalletUnlockTimeout = 15
-
-	// Actual virtual size of transaction which spends staking transaction through slashing
-	// path. In reality it highly depends on slashingAddress size:
-	// for p2pk - 222vb
-	// for p2wpkh - 177vb
-	// for p2tr - 189vb
-	// We are chosing 180vb as we expect slashing address will be one of the more recent
-	// address types.
+	// Approximate virtual size of transaction which spends staking transaction
+	// through script path to single p2tr output. It is only used to estimate fee of
+	// unbonding transaction, size of slashing transactions depends on slashing
+	// address and is estimated by slashingTxVSize.
 	// Transaction is quite big as witness to spend is composed of:
 	// 1. StakerSig
 	// 2. CovenantSig
-	// 3. FinalityProviderSig
-	// 4. StakingScript
-	// 5. Taproot control block
+	// 3. StakingScript
+	// 4. Taproot control block
 	slashingPathSpendTxVSize = 180
 
 	// Set minimum fee to 1 sat/byte, as in standard rules policy
 	MinFeePerKb = txrules.DefaultRelayFeePerKb
 
-	// If we fail to send unbonding tx to btc for any reason we will retry in this time
-	unbondingSendRetryTimeout = 1 * time.Minute
-
 	// after this many confirmations we treat unbonding transaction as confirmed on btc
 	// TODO: needs to consolidate what is safe confirmation for different types of transaction
 	// as currently we have different values for different types of transactions
@@ -126,11 +115,19 @@ const (
 )
 
 type StakerApp struct {
-	startOnce sync.Once
-	stopOnce  sync.Once
-	wg        sync.WaitGroup
-	quit      chan struct{}
-
+	lifecycleMu    sync.Mutex
+	lifecycleState LifecycleState
+	// cancelled by Stop, to interrupt start in progress
+	startCtx    context.Context
+	cancelStart context.CancelFunc
+	// closed when Start returns
+	startDone chan struct{}
+	// closed when Stop finishes
+	stopped chan struct{}
+	wg      goroutineGroup
+	quit    chan struct{}
+
+	clock            utils.Clock
 	babylonClient    cl.BabylonClient
 	wc               walletcontroller.WalletController
 	notifier         notifier.ChainNotifier
@@ -141,14 +138,81 @@ type StakerApp struct {
 	txTracker        *stakerdb.TrackedTransactionStore
 	babylonMsgSender *cl.BabylonMsgSender
 
+	// guards against running multiple reconciliations at the same time
+	reconciliationMu sync.Mutex
+
+	stakingRequests *stakingRequestCache
+
+	delegationBacklog *delegationBacklog
+
+	// delegations being sent to babylon, which cannot be cancelled
+	delegationSubmissions *delegationSubmissions
+
+	// chain notifier subscriptions, at most one per transaction and purpose
+	notifications *notificationRegistry
+
+	// confirmation registrations of already sent transactions which failed and
+	// are retried in background
+	confRegistrations *pendingConfRegistrations
+
+	// startup checks of transactions which failed and are retried in background
+	startupChecks *failedStartupChecks
+
+	// wallet rescans for transactions not created by staker
+	rescans *walletRescans
+
+	// notified on each new block, so that expired unbonding timelocks of
+	// scheduled sweeps are checked
+	autoSweepNewBlock chan struct{}
+
+	// notified on each new block, so that delegations with auto renewal which
+	// timelocks are about to expire are renewed
+	autoRenewNewBlock chan struct{}
+
+	// allows operator to pause sending transactions to btc
+	broadcasts *broadcastSwitch
+
+	// limits value of staking transactions sent within window of btc blocks
+	stakedValueCap *stakedValueCap
+
+	// histograms of lengths of transaction lifecycle intervals
+	latencyMetrics *latencyMetrics
+
+	// discrepancies found by the last reconciliation
+	reconciliationMetrics *reconciliationMetrics
+
+	// progress of confirmation waits, reported to clients
+	confProgress *confirmationProgressTracker
+
+	// retry loops in progress, reported to clients and on shutdown
+	inFlight *inFlightRegistry
+
+	// results of the last scan of transactions done to compute stats
+	statsCache statsScanCache
+
+	// delegations waiting for covenant unbonding signatures
+	unbondingWaits *unbondingWaits
+
+	// fee estimates made during the last day, reported in stats
+	feeEstimates feeEstimateHistory
+
+	// last known balance of babylon account and delegations waiting for funds
+	babylonBalance *babylonBalanceMonitor
+
 	stakingRequestedEvChan                        chan *stakingRequestedEvent
 	stakingTxBtcConfirmedEvChan                   chan *stakingTxBtcConfirmedEvent
 	delegationSubmittedToBabylonEvChan            chan *delegationSubmittedToBabylonEvent
 	unbondingTxSignaturesConfirmedOnBabylonEvChan chan *unbondingTxSignaturesConfirmedOnBabylonEvent
 	unbondingTxConfirmedOnBtcEvChan               chan *unbondingTxConfirmedOnBtcEvent
 	spendStakeTxConfirmedOnBtcEvChan              chan *spendStakeTxConfirmedOnBtcEvent
+	delegationExpiredOnBabylonEvChan              chan *delegationExpiredOnBabylonEvent
+	unbondingSignaturesTimeoutEvChan              chan *unbondingSignaturesTimeoutEvent
+	stakingTxInputConflictEvChan                  chan *stakingTxInputConflictEvent
 	criticalErrorEvChan                           chan *criticalErrorEvent
 	currentBestBlockHeight                        atomic.Uint32
+	// unix time in nanoseconds of the last block epoch event, zero if none was
+	// received yet
+	lastBlockEventAt atomic.Int64
 }
 
 func NewStakerAppFromConfig(
@@ -157,40 +221,82 @@ func NewStakerAppFromConfig(
 	rpcClientLogger *zap.Logger,
 	db kvdb.Backend,
 ) (*StakerApp, error) {
+	if err := checkProxiedTargets(config); err != nil {
+		return nil, err
+	}
+
 	// TODO: If we want to support multiple wallet types, this is most probably the place to decide
 	// on concrete implementation
-	walletClient, err := walletcontroller.NewRpcWalletController(config)
-	if err != nil {
-		return nil, err
+	var walletClient walletcontroller.WalletController
+	if config.WalletConfig.NoWallet {
+		nodeWallet, err := walletcontroller.NewNodeWalletController(config)
+		if err != nil {
+			return nil, NewStartupError(NodeBackendUnavailable, err)
+		}
+		walletClient = nodeWallet
+	} else {
+		rpcWallet, err := walletcontroller.NewRpcWalletController(config)
+		if err != nil {
+			return nil, NewStartupError(WalletUnavailable, err)
+		}
+		walletClient = rpcWallet
 	}
 
 	tracker, err := stakerdb.NewTrackedTransactionStore(db)
 
 	if err != nil {
+		return nil, NewStartupError(DatabaseCorrupt, err)
+	}
+
+	if err := checkDbNetwork(config, tracker); err != nil {
 		return nil, err
 	}
 
-	babylonClient, err := cl.NewBabylonController(config.BabylonConfig, &config.ActiveNetParams, logger, rpcClientLogger)
+	babylonController, err := cl.NewBabylonController(
+		config.BabylonConfig,
+		&config.ActiveNetParams,
+		config.ProxyConfig.BabylonProxy(),
+		logger,
+		rpcClientLogger,
+	)
 
 	if err != nil {
-		return nil, err
+		return nil, NewStartupError(BabylonUnavailable, err)
 	}
 
-	hintCache, err := channeldb.NewHeightHintCache(
-		channeldb.CacheConfig{
-			// TODO: Investigate this option. Lighting docs mention that this is necessary for some edge case
-			QueryDisable: false,
-		}, db,
-	)
+	var babylonClient cl.BabylonClient = babylonController
+
+	if config.BabylonConfig.DryRun {
+		dryRunClient, err := cl.NewDryRunBabylonClient(babylonController, config.BabylonConfig, &config.ActiveNetParams, logger)
+
+		if err != nil {
+			return nil, NewStartupError(ConfigInvalid, err)
+		}
+
+		logger.WithFields(logrus.Fields{
+			"outputDir":   config.BabylonConfig.DryRunOutputDir,
+			"fixtureFile": config.BabylonConfig.DryRunFixtureFile,
+		}).Warn("Babylon dry run mode enabled, messages will not be sent to babylon")
+
+		babylonClient = dryRunClient
+	}
+
+	hintCache, err := openHeightHintCache(db, config.RebuildHeightHints, logger)
 
 	if err != nil {
-		return nil, fmt.Errorf("unable to create height hint cache: %v", err)
+		return nil, NewStartupError(DatabaseCorrupt, fmt.Errorf("unable to create height hint cache: %v", err))
 	}
 
-	nodeNotifier, err := NewNodeBackend(config.BtcNodeBackendConfig, &config.ActiveNetParams, hintCache)
+	nodeNotifier, err := NewNodeBackend(
+		config.BtcNodeBackendConfig,
+		&config.ActiveNetParams,
+		hintCache,
+		config.ProxyConfig.BtcNodeProxy(),
+		logger,
+	)
 
 	if err != nil {
-		return nil, err
+		return nil, NewStartupError(NodeBackendUnavailable, err)
 	}
 
 	var feeEstimator FeeEstimator
@@ -198,15 +304,38 @@ func NewStakerAppFromConfig(
 	case types.StaticFeeEstimation:
 		feeEstimator = NewStaticBtcFeeEstimator(chainfee.SatPerKVByte(config.BtcNodeBackendConfig.MaxFeeRate * 1000))
 	case types.DynamicFeeEstimation:
-		feeEstimator, err = NewDynamicBtcFeeEstimator(config.BtcNodeBackendConfig, &config.ActiveNetParams, logger)
+		feeEstimator, err = NewDynamicBtcFeeEstimator(
+			config.BtcNodeBackendConfig,
+			&config.ActiveNetParams,
+			config.ProxyConfig.BtcNodeProxy(),
+			logger,
+		)
 		if err != nil {
-			return nil, err
+			return nil, NewStartupError(NodeBackendUnavailable, err)
 		}
 	default:
-		return nil, fmt.Errorf("unknown fee estimation mode: %d", config.BtcNodeBackendConfig.EstimationMode)
+		return nil, NewStartupError(
+			ConfigInvalid,
+			fmt.Errorf("unknown fee estimation mode: %d", config.BtcNodeBackendConfig.EstimationMode),
+		)
 	}
 
-	babylonMsgSender := cl.NewBabylonMsgSender(babylonClient, logger)
+	clock := utils.NewRealClock()
+
+	babylonMsgSender := cl.NewBabylonMsgSender(
+		babylonClient,
+		logger,
+		cl.CircuitBreakerConfig{
+			FailureThreshold: config.StakerConfig.BabylonCircuitFailures,
+			Cooldown:         config.StakerConfig.BabylonCircuitCooldown,
+		},
+		cl.BatchConfig{
+			Enabled: config.StakerConfig.BabylonBatchDelegations,
+			MaxSize: config.StakerConfig.BabylonBatchMaxSize,
+			Window:  config.StakerConfig.BabylonBatchWindow,
+		},
+		clock,
+	)
 
 	return NewStakerAppFromDeps(
 		config,
@@ -217,6 +346,7 @@ func NewStakerAppFromConfig(
 		feeEstimator,
 		tracker,
 		babylonMsgSender,
+		clock,
 	)
 }
 
@@ -229,8 +359,17 @@ func NewStakerAppFromDeps(
 	feeEestimator FeeEstimator,
 	tracker *stakerdb.TrackedTransactionStore,
 	babylonMsgSender *cl.BabylonMsgSender,
+	clock utils.Clock,
 ) (*StakerApp, error) {
+	startCtx, cancelStart := context.WithCancel(context.Background())
+
 	return &StakerApp{
+		lifecycleState:         LifecycleCreated,
+		startCtx:               startCtx,
+		cancelStart:            cancelStart,
+		startDone:              make(chan struct{}),
+		stopped:                make(chan struct{}),
+		clock:                  clock,
 		babylonClient:          cl,
 		wc:                     walletClient,
 		notifier:               nodeNotifier,
@@ -241,6 +380,23 @@ func NewStakerAppFromDeps(
 		config:                 config,
 		logger:                 logger,
 		quit:                   make(chan struct{}),
+		stakingRequests:        newStakingRequestCache(config.StakerConfig.MaxCachedStakingRequests),
+		startupChecks:          newFailedStartupChecks(config.StakerConfig.StartupCheckRetryInterval),
+		delegationBacklog:      newDelegationBacklog(config.StakerConfig.MaxInFlightDelegations),
+		delegationSubmissions:  newDelegationSubmissions(),
+		notifications:          newNotificationRegistry(),
+		confRegistrations:      newPendingConfRegistrations(),
+		rescans:                newWalletRescans(),
+		autoSweepNewBlock:      make(chan struct{}, 1),
+		autoRenewNewBlock:      make(chan struct{}, 1),
+		broadcasts:             newBroadcastSwitch(),
+		stakedValueCap:         newStakedValueCap(btcutil.Amount(config.StakerConfig.MaxStakedValuePerWindow), config.StakerConfig.StakedValueWindowBlocks),
+		latencyMetrics:         newLatencyMetrics(config.StakerConfig.LatencyBuckets),
+		reconciliationMetrics:  newReconciliationMetrics(),
+		babylonBalance:         newBabylonBalanceMonitor(),
+		confProgress:           newConfirmationProgressTracker(),
+		inFlight:               newInFlightRegistry(),
+		unbondingWaits:         newUnbondingWaits(),
 		stakingRequestedEvChan: make(chan *stakingRequestedEvent),
 		// event for when transaction is confirmed on BTC
 		stakingTxBtcConfirmedEvChan: make(chan *stakingTxBtcConfirmedEvent),
@@ -258,6 +414,18 @@ func NewStakerAppFromDeps(
 		// channel which receives confirmation that unbonding transaction was confirmed on BTC
 		unbondingTxConfirmedOnBtcEvChan: make(chan *unbondingTxConfirmedOnBtcEvent),
 
+		// channel which receives delegations which babylon reports as unbonded
+		// before they became active locally
+		delegationExpiredOnBabylonEvChan: make(chan *delegationExpiredOnBabylonEvent),
+
+		// channel which receives delegations which did not receive covenant
+		// unbonding signatures within configured timeout
+		unbondingSignaturesTimeoutEvChan: make(chan *unbondingSignaturesTimeoutEvent),
+
+		// channel which receives staking transactions which inputs were spent by
+		// other transaction before staking transaction was confirmed
+		stakingTxInputConflictEvChan: make(chan *stakingTxInputConflictEvent),
+
 		// channel which receives critical errors, critical errors are errors which we do not know
 		// how to handle, so we just log them. It is up to user to investigate, what had happend
 		// and report the situation
@@ -265,66 +433,185 @@ func NewStakerAppFromDeps(
 	}, nil
 }
 
+// Start starts the app. It can be called only once, on freshly created app,
+// otherwise ErrInvalidLifecycleState is returned. If Start fails, Stop must still
+// be called to release resources acquired during start.
 func (app *StakerApp) Start() error {
-	var startErr error
-	app.startOnce.Do(func() {
-		app.logger.Infof("Starting StakerApp")
+	app.lifecycleMu.Lock()
+	if app.lifecycleState != LifecycleCreated {
+		state := app.lifecycleState
+		app.lifecycleMu.Unlock()
+		return &ErrInvalidLifecycleState{State: state}
+	}
+	app.lifecycleState = LifecycleStarting
+	app.lifecycleMu.Unlock()
 
-		// TODO: This can take a long time as it connects to node. Maybe make it cancellable?
-		// although staker without node is not very useful
+	defer close(app.startDone)
 
-		app.logger.Infof("Connecting to node backend: %s", app.config.BtcNodeBackendConfig.Nodetype)
-		err := app.notifier.Start()
-		if err != nil {
-			startErr = err
-			return
-		}
+	err := app.start()
 
-		app.logger.Infof("Successfully connected to node backend: %s", app.config.BtcNodeBackendConfig.Nodetype)
+	app.lifecycleMu.Lock()
+	defer app.lifecycleMu.Unlock()
 
-		blockEventNotifier, err := app.notifier.RegisterBlockEpochNtfn(nil)
+	if err != nil {
+		return err
+	}
 
-		if err != nil {
-			startErr = err
-			return
-		}
+	// Stop was called while starting
+	if app.lifecycleState != LifecycleStarting {
+		return ErrStartCancelled
+	}
 
-		err = app.feeEstimator.Start()
+	app.lifecycleState = LifecycleRunning
 
-		if err != nil {
-			startErr = err
-			return
-		}
+	return nil
+}
 
-		// we registered for notifications with `nil`  so we should receive best block
-		// immeadiatly
-		select {
-		case block := <-blockEventNotifier.Epochs:
-			app.currentBestBlockHeight.Store(uint32(block.Height))
-		case <-app.quit:
-			startErr = errors.New("staker app quit before finishing start")
-			return
+func (app *StakerApp) start() error {
+	app.logger.Infof("Starting StakerApp")
+
+	if err := app.loadBroadcastsPaused(); err != nil {
+		return err
+	}
+
+	// TODO: This can take a long time as it connects to node. Maybe make it cancellable?
+	// although staker without node is not very useful
+
+	app.logger.Infof("Connecting to node backend: %s", app.config.BtcNodeBackendConfig.Nodetype)
+	err := app.notifier.Start()
+	if err != nil {
+		return NewStartupError(NodeBackendUnavailable, err)
+	}
+
+	app.logger.Infof("Successfully connected to node backend: %s", app.config.BtcNodeBackendConfig.Nodetype)
+
+	blockEventNotifier, err := app.notifier.RegisterBlockEpochNtfn(nil)
+
+	if err != nil {
+		return NewStartupError(NodeBackendUnavailable, err)
+	}
+
+	// registration is cancelled by block handler once it is started
+	blockHandlerStarted := false
+	defer func() {
+		if !blockHandlerStarted {
+			blockEventNotifier.Cancel()
 		}
+	}()
 
-		app.logger.Infof("Initial btc best block height is: %d", app.currentBestBlockHeight.Load())
+	if err := app.checkWallet(); err != nil {
+		return err
+	}
 
-		app.babylonMsgSender.Start()
+	if err := app.checkFeeGrant(); err != nil {
+		return err
+	}
 
-		app.wg.Add(2)
-		go app.handleNewBlocks(blockEventNotifier)
-		go app.handleStakingEvents()
+	err = app.feeEstimator.Start()
 
-		if err := app.checkTransactionsStatus(); err != nil {
-			startErr = err
-			return
+	if err != nil {
+		return NewStartupError(NodeBackendUnavailable, err)
+	}
+
+	// we registered for notifications with `nil`  so we should receive best block
+	// immeadiatly
+	select {
+	case block := <-blockEventNotifier.Epochs:
+		app.currentBestBlockHeight.Store(uint32(block.Height))
+	case <-app.startCtx.Done():
+		return ErrStartCancelled
+	}
+
+	app.logger.Infof("Initial btc best block height is: %d", app.currentBestBlockHeight.Load())
+
+	if err := app.loadStakedValueCap(); err != nil {
+		return err
+	}
+
+	// wallet can catch up while staker is running, so it only blocks moving funds
+	if !app.config.WalletConfig.NoWallet {
+		if err := app.checkWalletSynced(); err != nil {
+			app.logger.WithError(err).Warn("Stakes and spends are rejected until wallet is synced")
 		}
-	})
+	}
+
+	app.babylonMsgSender.Start()
+
+	app.pruneStakingRequests()
+
+	app.logDbIntegrityIssues()
+
+	app.validateStakingOutputs()
+
+	// events lost on previous shutdown are applied before the general scan of
+	// tracked transactions, which would otherwise reconstruct them
+	if err := app.replayEventIntents(); err != nil {
+		return err
+	}
+
+	blockHandlerStarted = true
+	app.wg.Go(blockHandlerGoroutine, func() { app.handleNewBlocks(blockEventNotifier) })
+	app.wg.Go(stakingEventsGoroutine, app.handleStakingEvents)
+	app.wg.Go(reconciliationGoroutine, app.reconciliationLoop)
+	app.wg.Go(delegationBacklogGoroutine, app.drainDelegationBacklog)
+	app.wg.Go(confRegistrationRetryGoroutine, app.retryConfRegistrationsLoop)
+	app.wg.Go(autoSweepGoroutine, app.autoSweepLoop)
+	app.wg.Go(autoRenewGoroutine, app.autoRenewLoop)
+	app.wg.Go(babylonBalanceGoroutine, app.babylonBalanceLoop)
+
+	if err := app.checkTransactionsStatus(); err != nil {
+		return err
+	}
+
+	if err := app.watchSweepIntents(); err != nil {
+		return err
+	}
 
-	return startErr
+	if err := app.resumeArchive(); err != nil {
+		return err
+	}
+
+	// timelocks could expire while staker was down
+	app.notifyAutoSweepNewBlock()
+	app.notifyAutoRenewNewBlock()
+
+	app.wg.Go(startupCheckRetryGoroutine, app.retryFailedStartupChecks)
+
+	return nil
+}
+
+// checkWallet checks that wallet is reachable and runs on configured network
+func (app *StakerApp) checkWallet() error {
+	if app.config.WalletConfig.NoWallet {
+		app.logger.Info("Running without btc wallet. Only watched staking transactions are supported")
+		return nil
+	}
+
+	if walletNetwork := app.wc.NetworkName(); walletNetwork != app.network.Name {
+		return NewStartupError(
+			ConfigInvalid,
+			fmt.Errorf("wallet network %s does not match configured network %s", walletNetwork, app.network.Name),
+		)
+	}
+
+	if err := app.wc.Ping(); err != nil {
+		return NewStartupError(WalletUnavailable, err)
+	}
+
+	return nil
+}
+
+// checkWalletEnabled returns walletcontroller.ErrWalletDisabled if daemon runs
+// without btc wallet
+func (app *StakerApp) checkWalletEnabled() error {
+	if app.config.WalletConfig.NoWallet {
+		return walletcontroller.ErrWalletDisabled
+	}
+
+	return nil
 }
 
 func (app *StakerApp) handleNewBlocks(blockNotifier *notifier.BlockEpochEvent) {
-	defer app.wg.Done()
 	defer blockNotifier.Cancel()
 	for {
 		select {
@@ -333,39 +620,66 @@ func (app *StakerApp) handleNewBlocks(blockNotifier *notifier.BlockEpochEvent) {
 				return
 			}
 			app.currentBestBlockHeight.Store(uint32(block.Height))
+			app.lastBlockEventAt.Store(app.clock.Now().UnixNano())
 
 			app.logger.WithFields(logrus.Fields{
 				"btcBlockHeight": block.Height,
 				"btcBlockHash":   block.Hash.String(),
 			}).Debug("Received new best btc block")
+
+			app.confRegistrations.notifyNewBlock()
+			app.notifyAutoSweepNewBlock()
+			app.notifyAutoRenewNewBlock()
 		case <-app.quit:
 			return
 		}
 	}
 }
 
+// Stop stops the app, cancelling start in progress. Once Stop returns, all
+// background routines of the app have exited. Calling Stop multiple times is
+// safe, subsequent calls wait until the app is stopped.
 func (app *StakerApp) Stop() error {
-	var stopErr error
-	app.stopOnce.Do(func() {
-		app.logger.Infof("Stopping StakerApp")
+	app.lifecycleMu.Lock()
+	switch app.lifecycleState {
+	case LifecycleCreated:
+		// nothing was started, there is nothing to stop
+		app.lifecycleState = LifecycleStopped
+		app.lifecycleMu.Unlock()
+		app.cancelStart()
 		close(app.quit)
-		app.wg.Wait()
+		close(app.stopped)
+		return nil
+	case LifecycleStopping, LifecycleStopped:
+		app.lifecycleMu.Unlock()
+		<-app.stopped
+		return nil
+	}
+	app.lifecycleState = LifecycleStopping
+	app.lifecycleMu.Unlock()
 
-		app.babylonMsgSender.Stop()
+	app.logger.Infof("Stopping StakerApp")
+	app.cancelStart()
+	close(app.quit)
 
-		err := app.feeEstimator.Stop()
+	// start must return before waiting for routines, so that it does not start
+	// new ones
+	<-app.startDone
+	app.wg.Wait()
 
-		if err != nil {
-			stopErr = err
-			return
-		}
+	app.babylonMsgSender.Stop()
+
+	stopErr := app.feeEstimator.Stop()
+
+	if err := app.notifier.Stop(); err != nil && stopErr == nil {
+		stopErr = err
+	}
+
+	app.lifecycleMu.Lock()
+	app.lifecycleState = LifecycleStopped
+	app.lifecycleMu.Unlock()
+	close(app.stopped)
 
-		err = app.notifier.Stop()
-		if err != nil {
-			stopErr = err
-			return
-		}
-	})
 	return stopErr
 }
 
@@ -391,8 +705,17 @@ func (app *StakerApp) waitForStakingTransactionConfirmation(
 	stakingTxHash *chainhash.Hash,
 	stakingTxPkScript []byte,
 	requiredBlockDepth uint32,
-	currentBestBlockHeight uint32,
+	heightHint uint32,
 ) error {
+	sub := app.notifications.reserve(*stakingTxHash, StakingTxConfirmationPurpose, *stakingTxHash, app.clock.Now())
+
+	if sub == nil {
+		app.logger.WithFields(logrus.Fields{
+			"stakingTxHash": stakingTxHash.String(),
+		}).Debug("Already waiting for tx confirmation")
+		return nil
+	}
+
 	app.logger.WithFields(logrus.Fields{
 		"stakingTxHash": stakingTxHash.String(),
 	}).Debug("Register waiting for tx confirmation")
@@ -401,14 +724,30 @@ func (app *StakerApp) waitForStakingTransactionConfirmation(
 		stakingTxHash,
 		stakingTxPkScript,
 		requiredBlockDepth+1,
-		currentBestBlockHeight,
+		heightHint,
 		notifier.WithIncludeBlock(),
 	)
 	if err != nil {
+		app.notifications.remove(sub)
 		return err
 	}
 
-	go app.waitForStakingTxConfirmation(*stakingTxHash, requiredBlockDepth, confEvent)
+	if !app.notifications.attach(sub, confEvent.Cancel) {
+		// transaction stopped being tracked while registering
+		return nil
+	}
+
+	app.confProgress.started(
+		*stakingTxHash,
+		StakingTxConfirmation,
+		*stakingTxHash,
+		requiredBlockDepth+1,
+		app.clock.Now(),
+	)
+
+	app.wg.Go(stakingTxConfGoroutine, func() {
+		app.waitForStakingTxConfirmation(*stakingTxHash, requiredBlockDepth, confEvent, sub)
+	})
 	return nil
 }
 
@@ -420,6 +759,8 @@ func (app *StakerApp) handleBtcTxInfo(
 	txStatus walletcontroller.TxStatus,
 	btcTxInfo *notifier.TxConfirmation) error {
 
+	requiredDepth := storedRequiredDepth(txInfo, params)
+
 	switch txStatus {
 	case walletcontroller.TxNotFound:
 		// Most probable reason this happened is transaction was included in btc chain (removed from mempool)
@@ -437,12 +778,16 @@ func (app *StakerApp) handleBtcTxInfo(
 		if err := app.waitForStakingTransactionConfirmation(
 			stakingTxHash,
 			txInfo.StakingTx.TxOut[txInfo.StakingOutputIndex].PkScript,
-			params.ConfirmationTimeBlocks,
-			currentBestBlockHeight,
+			requiredDepth,
+			mempoolTxHeightHint(txInfo, currentBestBlockHeight),
 		); err != nil {
 			return err
 		}
 
+		if !txInfo.Watched {
+			app.watchStakingTxInputs(*stakingTxHash, txInfo.StakingTx, currentBestBlockHeight)
+		}
+
 	case walletcontroller.TxInChain:
 		app.logger.WithFields(logrus.Fields{
 			"btcTxHash":              stakingTxHash,
@@ -451,21 +796,25 @@ func (app *StakerApp) handleBtcTxInfo(
 		}).Debug("Transaction found in chain")
 
 		if currentBestBlockHeight < btcTxInfo.BlockHeight {
-			// This is wierd case, we retrieved transaction from btc wallet, even though wallet best height
-			// is lower than block height of transaction.
-			// Log it as error so that user can investigate.
+			// Wallet is synced further than notifier. Block of the transaction is used
+			// as height hint, so that notifier finds the transaction once it catches up.
 			app.logger.WithFields(logrus.Fields{
 				"btcTxHash":              stakingTxHash,
 				"btcTxBlockHeight":       btcTxInfo.BlockHeight,
 				"currentBestBlockHeight": currentBestBlockHeight,
-			}).Error("Current best block height is lower than block height of transaction")
+			}).Warn("Wallet is ahead of btc notifier. Waiting for notifier to reach block of transaction")
 
-			return nil
+			return app.waitForStakingTransactionConfirmation(
+				stakingTxHash,
+				txInfo.StakingTx.TxOut[txInfo.StakingOutputIndex].PkScript,
+				requiredDepth,
+				btcTxInfo.BlockHeight,
+			)
 		}
 
 		blockDepth := currentBestBlockHeight - btcTxInfo.BlockHeight
 
-		if blockDepth >= params.ConfirmationTimeBlocks {
+		if blockDepth >= requiredDepth {
 			app.logger.WithFields(logrus.Fields{
 				"btcTxHash":              stakingTxHash,
 				"btcTxBlockHeight":       btcTxInfo.BlockHeight,
@@ -476,7 +825,7 @@ func (app *StakerApp) handleBtcTxInfo(
 			ev := &stakingTxBtcConfirmedEvent{
 				stakingTxHash: *stakingTxHash,
 				txIndex:       btcTxInfo.TxIndex,
-				blockDepth:    params.ConfirmationTimeBlocks,
+				blockDepth:    requiredDepth,
 				blockHash:     *btcTxInfo.BlockHash,
 				blockHeight:   btcTxInfo.BlockHeight,
 				tx:            txInfo.StakingTx,
@@ -496,11 +845,13 @@ func (app *StakerApp) handleBtcTxInfo(
 				"currentBestBlockHeight": currentBestBlockHeight,
 			}).Debug("Transaction not deep enough in btc chain to be sent to Babylon. Waiting for confirmation")
 
+			// transaction is already included, so notifier does not need to look for
+			// it in blocks below its inclusion block
 			if err := app.waitForStakingTransactionConfirmation(
 				stakingTxHash,
 				txInfo.StakingTx.TxOut[txInfo.StakingOutputIndex].PkScript,
-				params.ConfirmationTimeBlocks,
-				currentBestBlockHeight,
+				requiredDepth,
+				btcTxInfo.BlockHeight,
 			); err != nil {
 				return err
 			}
@@ -512,160 +863,302 @@ func (app *StakerApp) handleBtcTxInfo(
 // TODO: We should also handle case when btc node or babylon node lost data and start from scratch
 // i.e keep track what is last known block height on both chains and detect if after restart
 // for some reason they are behind staker
-func (app *StakerApp) checkTransactionsStatus() error {
-	stakingParams, err := app.babylonClient.Params()
+// checkSentToBtcTransaction checks whether staking transaction sent to btc was
+// confirmed while staker was down
+func (app *StakerApp) checkSentToBtcTransaction(
+	stakingTxHash *chainhash.Hash,
+	stakingParams *cl.StakingParams,
+) error {
+	tx, _, err := app.getTransactionAndStakerAddress(stakingTxHash)
 
 	if err != nil {
 		return err
 	}
 
-	// Keep track of all staking transactions which need checking. chainhash.Hash objects are not relativly small
-	// so it should not OOM even for larage database
-	var transactionsSentToBtc []*chainhash.Hash
-	var transactionConfirmedOnBtc []*chainhash.Hash
-	var transactionsOnBabylon []*stakingDbInfo
+	details, status, err := app.wc.TxDetails(stakingTxHash, tx.StakingTx.TxOut[tx.StakingOutputIndex].PkScript)
 
-	reset := func() {
-		transactionsSentToBtc = make([]*chainhash.Hash, 0)
-		transactionConfirmedOnBtc = make([]*chainhash.Hash, 0)
-		transactionsOnBabylon = make([]*stakingDbInfo, 0)
+	if err != nil {
+		return err
 	}
 
-	// In our scan we only record transactions which state need to be checked, as`ScanTrackedTransactions`
-	// is long running read transaction, it could dead lock with write transactions which we would need
-	// to use to update transaction state.
-	err = app.txTracker.ScanTrackedTransactions(func(tx *stakerdb.StoredTransaction) error {
-		// TODO : We need to have another stare like UnstakeTransaction sent and store
-		// info about transaction sent (hash) to check wheter it was confirmed after staker
-		// restarts
-		stakingTxHash := tx.StakingTx.TxHash()
-		switch tx.State {
-		case proto.TransactionState_SENT_TO_BTC:
-			transactionsSentToBtc = append(transactionsSentToBtc, &stakingTxHash)
-			return nil
-		case proto.TransactionState_CONFIRMED_ON_BTC:
-			transactionConfirmedOnBtc = append(transactionConfirmedOnBtc, &stakingTxHash)
-			return nil
-		// We need to check any transaction which was sent to babylon, as it could be
-		// that we sent undelegation msg, but restart happened before we could update
-		// database
-		case proto.TransactionState_SENT_TO_BABYLON:
-			// TODO: If we will have automatic unstaking, we should check wheter tx is expired
-			// and proceed with sending unstake transaction
-			transactionsOnBabylon = append(transactionsOnBabylon, &stakingDbInfo{
-				stakingTxHash:  &stakingTxHash,
-				stakingTxState: tx.State,
-			})
-			return nil
-		case proto.TransactionState_DELEGATION_ACTIVE:
-			// we recevied all necessary data from babylon nothing to do here
-			return nil
-		case proto.TransactionState_UNBONDING_CONFIRMED_ON_BTC:
-			// unbonding tx was sent to babylon, received all signatures and was confirmed on btc, nothing to do here
-			return nil
-		case proto.TransactionState_SPENT_ON_BTC:
-			// nothing to do, staking transaction is already spent
-			return nil
-		default:
-			return fmt.Errorf("unknown transaction state: %d", tx.State)
-		}
-	}, reset)
+	return app.handleBtcTxInfo(stakingTxHash, tx, stakingParams, app.currentBestBlockHeight.Load(), status, details)
+}
 
-	if err != nil {
+// checkConfirmedOnBtcTransaction checks whether delegation of staking transaction
+// confirmed on btc was sent to babylon, and sends it if it was not
+func (app *StakerApp) checkConfirmedOnBtcTransaction(
+	stakingTxHash *chainhash.Hash,
+	stakingParams *cl.StakingParams,
+) error {
+	delegationInfo, err := app.babylonClient.QueryDelegationInfo(stakingTxHash)
+
+	if err != nil && !errors.Is(err, cl.ErrDelegationNotFound) {
 		return err
 	}
 
-	for _, txHash := range transactionsSentToBtc {
-		stakingTxHash := txHash
-		tx, _ := app.mustGetTransactionAndStakerAddress(stakingTxHash)
-		details, status, err := app.wc.TxDetails(stakingTxHash, tx.StakingTx.TxOut[tx.StakingOutputIndex].PkScript)
+	// delegation is already on babylon restart delegation process from this point
+	if delegationInfo != nil {
+		app.logger.WithFields(logrus.Fields{
+			"btcTxHash": stakingTxHash,
+		}).Debug("Already confirmed transaction found on Babylon as part of delegation. Fix db state")
+
+		storedTx, err := app.txTracker.GetTransaction(stakingTxHash)
 
 		if err != nil {
-			// we got some communication err, return error and kill app startup
 			return err
 		}
 
-		err = app.handleBtcTxInfo(stakingTxHash, tx, stakingParams, app.currentBestBlockHeight.Load(), status, details)
+		unbondingOutputIdx, err := app.babylonUnbondingOutputIdx(
+			stakingTxHash,
+			storedTx,
+			delegationInfo.UndelegationInfo.UnbondingTransaction,
+			delegationInfo.UndelegationInfo.UnbondingTime,
+			stakingParams,
+		)
 
 		if err != nil {
-			return err
+			return fmt.Errorf("invalid unbonding transaction of delegation found on babylon: %w", err)
 		}
-	}
 
-	for _, txHash := range transactionConfirmedOnBtc {
-		stakingTxHash := txHash
+		ev := &delegationSubmittedToBabylonEvent{
+			stakingTxHash:      *stakingTxHash,
+			unbondingTx:        delegationInfo.UndelegationInfo.UnbondingTransaction,
+			unbondingOutputIdx: unbondingOutputIdx,
+			unbondingTime:      delegationInfo.UndelegationInfo.UnbondingTime,
+		}
 
-		delegationInfo, err := app.babylonClient.QueryDelegationInfo(stakingTxHash)
+		utils.PushOrQuit[*delegationSubmittedToBabylonEvent](
+			app.delegationSubmittedToBabylonEvChan,
+			ev,
+			app.quit,
+		)
 
-		if err != nil && !errors.Is(cl.ErrDelegationNotFound, err) {
-			return err
-		}
+		return nil
+	}
 
-		// delegation is already on babylon restart delegation process from this point
-		if delegationInfo != nil {
-			app.logger.WithFields(logrus.Fields{
-				"btcTxHash": stakingTxHash,
-			}).Debug("Already confirmed transaction found on Babylon as part of delegation. Fix db state")
+	// transaction which is not on babylon, is already confirmed on btc chain
+	// get all necessary info and send it to babylon
+	tx, stakerAddress, err := app.getTransactionAndStakerAddress(stakingTxHash)
 
-			ev := &delegationSubmittedToBabylonEvent{
-				stakingTxHash: *stakingTxHash,
-				unbondingTx:   delegationInfo.UndelegationInfo.UnbondingTransaction,
-				unbondingTime: delegationInfo.UndelegationInfo.UnbondingTime,
-			}
+	if err != nil {
+		return err
+	}
 
-			utils.PushOrQuit[*delegationSubmittedToBabylonEvent](
-				app.delegationSubmittedToBabylonEvChan,
-				ev,
-				app.quit,
-			)
-		} else {
-			// transaction which is not on babylon, is already confirmed on btc chain
-			// get all necessary info and send it to babylon
+	if req := app.sendDelegationRequestFromRecordedProof(stakingTxHash, tx, stakingParams); req != nil {
+		app.logger.WithFields(logrus.Fields{
+			"btcTxHash": stakingTxHash,
+		}).Debug("Already confirmed transaction not sent to babylon yet. Initiate sending with recorded inclusion proof")
 
-			tx, stakerAddress := app.mustGetTransactionAndStakerAddress(stakingTxHash)
-			details, status, err := app.wc.TxDetails(stakingTxHash, tx.StakingTx.TxOut[tx.StakingOutputIndex].PkScript)
+		app.scheduleDelegationSend(req, stakerAddress, tx)
 
-			if err != nil {
-				// we got some communication err, return error and kill app startup
-				return err
-			}
+		return nil
+	}
 
-			if status != walletcontroller.TxInChain {
-				// we have confirmed transaction which is not in chain. Most probably btc node
-				// we are connected to lost data
-				app.logger.WithFields(logrus.Fields{
-					"btcTxHash": stakingTxHash,
-				}).Error("Already confirmed transaction not found on btc chain.")
-				continue
-			}
+	details, status, err := app.wc.TxDetails(stakingTxHash, tx.StakingTx.TxOut[tx.StakingOutputIndex].PkScript)
 
-			app.logger.WithFields(logrus.Fields{
-				"btcTxHash":                    stakingTxHash,
-				"btcTxConfirmationBlockHeight": details.BlockHeight,
-			}).Debug("Already confirmed transaction not sent to babylon yet. Initiate sending")
+	if err != nil {
+		return err
+	}
 
-			req := &sendDelegationRequest{
-				txHash:                      *stakingTxHash,
-				txIndex:                     details.TxIndex,
-				inclusionBlock:              details.Block,
-				requiredInclusionBlockDepth: uint64(stakingParams.ConfirmationTimeBlocks),
+	if status != walletcontroller.TxInChain {
+		// we have confirmed transaction which is not in chain. Most probably btc node
+		// we are connected to lost data. Mark transaction so that it is not silently
+		// forgotten, and operator can investigate it
+		app.logger.WithFields(logrus.Fields{
+			"btcTxHash": stakingTxHash,
+		}).Error("Already confirmed transaction not found on btc chain. Marking it as missing on btc")
+
+		app.cancelNotifications(stakingTxHash)
+		return app.txTracker.SetTxMissingOnBtc(stakingTxHash)
+	}
+
+	app.logger.WithFields(logrus.Fields{
+		"btcTxHash":                    stakingTxHash,
+		"btcTxConfirmationBlockHeight": details.BlockHeight,
+	}).Debug("Already confirmed transaction not sent to babylon yet. Initiate sending")
+
+	req := &sendDelegationRequest{
+		txHash:                      *stakingTxHash,
+		txIndex:                     details.TxIndex,
+		inclusionBlockHash:          details.Block.BlockHash(),
+		inclusionBlock:              details.Block,
+		requiredInclusionBlockDepth: uint64(storedRequiredDepth(tx, stakingParams)),
+	}
+
+	app.scheduleDelegationSend(req, stakerAddress, tx)
+
+	return nil
+}
+
+// checkMissingOnBtcTransaction checks whether staking transaction, which btc node
+// lost before, is on btc chain again. Found transaction is moved back to
+// CONFIRMED_ON_BTC state and its delegation is sent to babylon.
+func (app *StakerApp) checkMissingOnBtcTransaction(
+	stakingTxHash *chainhash.Hash,
+	stakingParams *cl.StakingParams,
+) error {
+	tx, err := app.txTracker.GetTransaction(stakingTxHash)
+
+	if err != nil {
+		return err
+	}
+
+	details, status, err := app.wc.TxDetails(stakingTxHash, tx.StakingTx.TxOut[tx.StakingOutputIndex].PkScript)
+
+	if err != nil {
+		return err
+	}
+
+	if status != walletcontroller.TxInChain {
+		app.logger.WithFields(logrus.Fields{
+			"btcTxHash": stakingTxHash,
+		}).Debug("Transaction missing on btc still not found on btc chain")
+
+		return nil
+	}
+
+	app.logger.WithFields(logrus.Fields{
+		"btcTxHash":                    stakingTxHash,
+		"btcTxConfirmationBlockHeight": details.BlockHeight,
+	}).Info("Transaction missing on btc found on btc chain again. Moving it back to confirmed on btc")
+
+	blockHash := details.Block.BlockHash()
+	if err := app.txTracker.SetTxFoundOnBtc(stakingTxHash, &blockHash, details.BlockHeight, details.Block.Header.Timestamp); err != nil {
+		return err
+	}
+
+	// transaction is no longer missing, so failure of sending its delegation is
+	// retried as failure of confirmed transaction check
+	if err := app.checkConfirmedOnBtcTransaction(stakingTxHash, stakingParams); err != nil {
+		app.recordStartupCheckFailure(stakingTxHash, proto.TransactionState_CONFIRMED_ON_BTC, err)
+	}
+
+	return nil
+}
+
+func (app *StakerApp) checkTransactionsStatus() error {
+	stakingParams, err := app.babylonClient.Params()
+
+	if err != nil {
+		return NewStartupError(BabylonUnavailable, err)
+	}
+
+	// Keep track of all staking transactions which need checking. chainhash.Hash objects are not relativly small
+	// so it should not OOM even for larage database
+	var transactionsToCheck []startupCheckTask
+	var transactionsOnBabylon []*stakingDbInfo
+
+	reset := func() {
+		transactionsToCheck = make([]startupCheckTask, 0)
+		transactionsOnBabylon = make([]*stakingDbInfo, 0)
+	}
+
+	// In our scan we only record transactions which state need to be checked, as`ScanTrackedTransactions`
+	// is long running read transaction, it could dead lock with write transactions which we would need
+	// to use to update transaction state.
+	err = app.txTracker.ScanTrackedTransactions(func(tx *stakerdb.StoredTransaction) error {
+		// TODO : We need to have another stare like UnstakeTransaction sent and store
+		// info about transaction sent (hash) to check wheter it was confirmed after staker
+		// restarts
+		stakingTxHash := tx.StakingTx.TxHash()
+		switch tx.State {
+		case proto.TransactionState_SENT_TO_BTC,
+			proto.TransactionState_CONFIRMED_ON_BTC,
+			// btc node could have lost transaction only temporarily, e.g. while resyncing
+			proto.TransactionState_MISSING_ON_BTC:
+			transactionsToCheck = append(transactionsToCheck, startupCheckTask{
+				stakingTxHash: &stakingTxHash,
+				state:         tx.State,
+			})
+			return nil
+		// We need to check any transaction which was sent to babylon, as it could be
+		// that we sent undelegation msg, but restart happened before we could update
+		// database
+		case proto.TransactionState_SENT_TO_BABYLON, proto.TransactionState_UNBONDING_SIGNATURES_TIMEOUT:
+			if tx.UnbondingWaitAborted {
+				// operator stopped waiting for covenant signatures
+				return nil
 			}
 
-			app.wg.Add(1)
-			go app.sendDelegationToBabylonTask(req, stakerAddress, tx)
+			// TODO: If we will have automatic unstaking, we should check wheter tx is expired
+			// and proceed with sending unstake transaction
+			transactionsOnBabylon = append(transactionsOnBabylon, &stakingDbInfo{
+				stakingTxHash:  &stakingTxHash,
+				stakingTxState: tx.State,
+			})
+			return nil
+		case proto.TransactionState_DELEGATION_ACTIVE:
+			// we recevied all necessary data from babylon nothing to do here
+			return nil
+		case proto.TransactionState_UNBONDING_CONFIRMED_ON_BTC:
+			// unbonding tx was sent to babylon, received all signatures and was confirmed on btc, nothing to do here
+			return nil
+		case proto.TransactionState_SPENT_ON_BTC:
+			// nothing to do, staking transaction is already spent
+			return nil
+		case proto.TransactionState_CONFLICTED:
+			// inputs of staking transaction were double spent, it will never be confirmed
+			return nil
+		case proto.TransactionState_DELEGATION_EXPIRED:
+			// delegation is no longer on babylon, funds can only be withdrawn
+			return nil
+		case proto.TransactionState_DELEGATION_CANCELLED:
+			// delegation was never sent to babylon, funds can only be withdrawn
+			return nil
+		default:
+			return fmt.Errorf("unknown transaction state: %d", tx.State)
 		}
+	}, reset)
+
+	if err != nil {
+		return NewStartupError(DatabaseCorrupt, err)
+	}
+
+	// each check requires round trips to btc wallet or babylon node, so checks are
+	// run concurrently. Failed checks are retried in background after startup
+	numChecks := len(transactionsToCheck)
+	numFailed, lastErr := app.runStartupChecks(
+		transactionsToCheck,
+		stakingParams,
+		app.config.StakerConfig.StartupCheckConcurrency,
+	)
+
+	// large number of failures means problem with btc or babylon node rather than
+	// with particular transactions. Few failures are always retried in background,
+	// as they are not enough to tell the two apart
+	tooManyFailures := numFailed >= int(app.config.StakerConfig.MinStartupCheckFailures) &&
+		numFailed*100 > numChecks*int(app.config.StakerConfig.MaxStartupCheckFailurePercent)
+
+	if tooManyFailures {
+		return NewStartupError(
+			RecoveryFailed,
+			fmt.Errorf("%d out of %d transaction checks failed, last error: %w", numFailed, numChecks, lastErr),
+		)
+	}
+
+	if numFailed > 0 {
+		app.logger.WithFields(logrus.Fields{
+			"numFailed": numFailed,
+			"numChecks": numChecks,
+		}).Warn("Some of transaction checks failed. Retrying them in background")
 	}
 
 	for _, localInfo := range transactionsOnBabylon {
 		// we only can have one local states here
-		if localInfo.stakingTxState == proto.TransactionState_SENT_TO_BABYLON {
+		if localInfo.stakingTxState == proto.TransactionState_SENT_TO_BABYLON ||
+			localInfo.stakingTxState == proto.TransactionState_UNBONDING_SIGNATURES_TIMEOUT {
 			stakingTxHash := localInfo.stakingTxHash
+			timedOut := localInfo.stakingTxState == proto.TransactionState_UNBONDING_SIGNATURES_TIMEOUT
 			// we crashed after succesful send to babaylon, restart checking for unbonding signatures
-			app.wg.Add(1)
-			go app.checkForUnbondingTxSignaturesOnBabylon(stakingTxHash)
+			app.wg.Go(unbondingSigsGoroutine, func() {
+				app.checkForUnbondingTxSignaturesOnBabylon(stakingTxHash, timedOut)
+			})
 		} else {
 			// we should not have any other state here, so kill app
-			return fmt.Errorf("unexpected local transaction state: %s, expected: %s", localInfo.stakingTxState, proto.TransactionState_SENT_TO_BABYLON)
+			return NewStartupError(
+				DatabaseCorrupt,
+				fmt.Errorf("unexpected local transaction state: %s, expected: %s", localInfo.stakingTxState, proto.TransactionState_SENT_TO_BABYLON),
+			)
 		}
 	}
 
@@ -675,11 +1168,14 @@ func (app *StakerApp) checkTransactionsStatus() error {
 func (app *StakerApp) waitForStakingTxConfirmation(
 	txHash chainhash.Hash,
 	depthOnBtcChain uint32,
-	ev *notifier.ConfirmationEvent) {
+	ev *notifier.ConfirmationEvent,
+	sub *notificationSubscription) {
+	defer app.notifications.remove(sub)
+	defer app.confProgress.finished(txHash, StakingTxConfirmation)
+
 	// check we are not shutting down
 	select {
 	case <-app.quit:
-		ev.Cancel()
 		return
 
 	default:
@@ -689,7 +1185,11 @@ func (app *StakerApp) waitForStakingTxConfirmation(
 		// TODO add handling of more events like ev.NegativeConf which signals that
 		// transaction have beer reorged out of the chain
 		select {
-		case conf := <-ev.Confirmed:
+		case conf, ok := <-ev.Confirmed:
+			if !ok {
+				return
+			}
+
 			stakingEvent := &stakingTxBtcConfirmedEvent{
 				stakingTxHash: conf.Tx.TxHash(),
 				txIndex:       conf.TxIndex,
@@ -705,86 +1205,184 @@ func (app *StakerApp) waitForStakingTxConfirmation(
 				stakingEvent,
 				app.quit,
 			)
-			ev.Cancel()
 			return
 		case u := <-ev.Updates:
 			app.logger.WithFields(logrus.Fields{
 				"btcTxHash": txHash,
 				"confLeft":  u,
 			}).Debugf("Staking transaction received confirmation")
+			app.confProgress.update(txHash, StakingTxConfirmation, u, app.clock.Now())
+		case <-sub.cancelled:
+			return
 		case <-app.quit:
-			// app is quitting, cancel the event
-			ev.Cancel()
+			// app is quitting, subscription is cancelled on return
 			return
 		}
 	}
 }
 
-func (app *StakerApp) getSlashingFee(feeFromBabylon btcutil.Amount) btcutil.Amount {
-	if feeFromBabylon < minSlashingFee {
+// getSlashingFee returns fee of slashing transactions of delegation to given
+// number of finality providers
+func (app *StakerApp) getSlashingFee(params *cl.StakingParams, numFinalityProviders int) (btcutil.Amount, error) {
+	fee, err := requiredSlashingFee(
+		params.MinSlashingTxFeeSat,
+		params.SlashingAddress,
+		uint32(numFinalityProviders),
+		uint32(len(params.CovenantPks)),
+		params.CovenantQuruomThreshold,
+	)
+
+	if err != nil {
+		return 0, err
+	}
+
+	if fee > params.MinSlashingTxFeeSat {
 		app.logger.WithFields(logrus.Fields{
-			"babylonSlashingFee":  feeFromBabylon,
-			"internalSlashingFee": minSlashingFee,
-		}).Debug("Slashing fee received from Babylon is too small. Using internal minimum fee")
-		return minSlashingFee
+			"babylonSlashingFee":  params.MinSlashingTxFeeSat,
+			"internalSlashingFee": fee,
+			"slashingAddress":     params.SlashingAddress,
+		}).Debug("Slashing fee received from Babylon is too small for slashing tx size. Using internal minimum fee")
 	}
 
-	return feeFromBabylon
+	return fee, nil
 }
 
-// helper to retrieve transaction when we are sure it must be in the store
-func (app *StakerApp) mustGetTransactionAndStakerAddress(txHash *chainhash.Hash) (*stakerdb.StoredTransaction, btcutil.Address) {
-	ts, err := app.txTracker.GetTransaction(txHash)
-
-	if err != nil {
-		app.logger.Fatalf("Error getting transaction state for tx %s. Eff: %v", txHash, err)
+// quarantineIfCorrupt moves transaction to the corrupt transactions bucket if err
+// indicates that its stored record cannot be decoded. It returns true if err was
+// handled this way, and processing of the transaction should be abandoned.
+func (app *StakerApp) quarantineIfCorrupt(txHash *chainhash.Hash, err error) bool {
+	if !errors.Is(err, stakerdb.ErrCorruptedTransaction) {
+		return false
 	}
 
-	stakerAddress, err := btcutil.DecodeAddress(ts.StakerAddress, app.network)
+	logger := app.logger.WithFields(logrus.Fields{
+		"stakingTxHash": txHash,
+		"err":           err,
+	})
 
-	if err != nil {
-		app.logger.Fatalf("Error decoding staker address: %s. Err: %v", ts.StakerAddress, err)
+	if qErr := app.txTracker.QuarantineTransaction(txHash); qErr != nil {
+		logger.Fatalf("Failed to quarantine corrupted transaction: %v", qErr)
 	}
 
-	return ts, stakerAddress
+	app.cancelNotifications(txHash)
+
+	logger.Error("Stored transaction is corrupted. Moved it to corrupt transactions bucket and stopped tracking it")
+
+	return true
 }
 
-func (app *StakerApp) mustBuildInclusionProof(req *sendDelegationRequest) []byte {
-	proof, err := cl.GenerateProof(req.inclusionBlock, req.txIndex)
+// handleStateTransitionError handles error returned when staking event could not
+// be applied to stored transaction. Duplicate events and events not valid in
+// current state of transaction, e.g stale events for transaction which already
+// reached final state, are ignored. Only transactions whose records are
+// corrupted are quarantined, so that processing of other transactions continues.
+// Other errors, e.g database failures, stop the app.
+func (app *StakerApp) handleStateTransitionError(ev StakingEvent, txHash *chainhash.Hash, err error) {
+	if errors.Is(err, stakerdb.ErrAlreadyInState) {
+		// duplicate event, transaction was already moved to this state
+		app.logDuplicateStakingEvent(ev)
+		return
+	}
 
-	if err != nil {
-		app.logger.WithFields(logrus.Fields{
-			"btcTxHash": req.txHash,
-			"err":       err,
-		}).Fatalf("Failed to build inclusion proof for already confirmed transaction")
+	if app.quarantineIfCorrupt(txHash, err) {
+		return
 	}
 
-	return proof
+	logger := app.logger.WithFields(logrus.Fields{
+		"stakingTxHash": txHash,
+		"eventId":       ev.EventId(),
+		"event":         ev.EventDesc(),
+		"err":           err,
+	})
+
+	if errors.Is(err, stakerdb.ErrTransactionNotFound) {
+		// transaction could have been quarantined after the event was sent
+		logger.Error("Received staking event for transaction which is not tracked. Ignoring it")
+		return
+	}
+
+	if errors.Is(err, stakerdb.ErrTransactionArchived) {
+		// archived transactions are in terminal state, so there is nothing to update
+		logger.Warn("Received staking event for archived transaction. Ignoring it")
+		return
+	}
+
+	var transitionErr *stakerdb.ErrInvalidStateTransition
+
+	if errors.As(err, &transitionErr) &&
+		(stakerdb.IsTerminalState(transitionErr.From) ||
+			transitionErr.From == proto.TransactionState_DELEGATION_CANCELLED) {
+		// events can race with transition to final state e.g confirmation
+		// of conflicting transaction
+		logger.Warn("Received stale staking event for transaction in final state. Ignoring it")
+		return
+	}
+
+	if errors.Is(err, stakerdb.ErrInvalidTransactionState) {
+		logger.Error("Staking event is not valid in current state of transaction. Ignoring it")
+		return
+	}
+
+	if errors.Is(err, stakerdb.ErrInvalidUnbondingDataUpdate) ||
+		errors.Is(err, stakerdb.ErrUnbondingDataNotFound) {
+		if qErr := app.txTracker.QuarantineTransaction(txHash); qErr != nil {
+			logger.Fatalf("Failed to quarantine transaction in inconsistent state: %v", qErr)
+		}
+
+		app.cancelNotifications(txHash)
+
+		logger.Error("Unbonding data of transaction is inconsistent with staking event. Moved it to corrupt transactions bucket and stopped tracking it")
+		return
+	}
+
+	logger.Fatalf("Error setting state for tx %s: %s", txHash, err)
 }
 
-func (app *StakerApp) stakerPrivateKey(stakerAddress btcutil.Address) (*btcec.PrivateKey, error) {
-	err := app.wc.UnlockWallet(defaultWalletUnlockTimeout)
+// helper to retrieve transaction when we are sure it must be in the store. If its
+// record turns out to be corrupted, it is quarantined and error is returned.
+func (app *StakerApp) getTransactionAndStakerAddress(txHash *chainhash.Hash) (*stakerdb.StoredTransaction, btcutil.Address, error) {
+	ts, err := app.txTracker.GetTransaction(txHash)
+
+	if app.quarantineIfCorrupt(txHash, err) {
+		return nil, nil, err
+	}
 
 	if err != nil {
-		return nil, err
+		app.logger.Fatalf("Error getting transaction state for tx %s. Eff: %v", txHash, err)
 	}
 
-	privkey, err := app.wc.DumpPrivateKey(stakerAddress)
+	stakerAddress, err := btcutil.DecodeAddress(ts.StakerAddress, app.network)
 
 	if err != nil {
-		return nil, err
+		err = fmt.Errorf("%w: invalid staker address %s: %v", stakerdb.ErrCorruptedTransaction, ts.StakerAddress, err)
+		app.quarantineIfCorrupt(txHash, err)
+		return nil, nil, err
 	}
 
-	return privkey, nil
+	return ts, stakerAddress, nil
 }
 
-func (app *StakerApp) retrieveExternalDelegationData(stakerAddress btcutil.Address) (*externalDelegationData, error) {
+// newSignerSession creates session which retrieves staker key from the wallet
+// at most once. Wallet requests are abandoned when ctx is done, or when wallet
+// does not respond in configured time. Caller must close the session once
+// operation is finished.
+func (app *StakerApp) newSignerSession(ctx context.Context, stakerAddress btcutil.Address) walletcontroller.SignerSession {
+	return walletcontroller.NewDumpKeySignerSession(
+		ctx,
+		app.wc,
+		stakerAddress,
+		int64(app.config.WalletConfig.WalletUnlockTimeout.Seconds()),
+		app.config.WalletRpcConfig.WalletRpcTimeout,
+	)
+}
+
+func (app *StakerApp) retrieveExternalDelegationData(signer walletcontroller.SignerSession) (*externalDelegationData, error) {
 	params, err := app.babylonClient.Params()
 	if err != nil {
 		return nil, err
 	}
 
-	stakerPrivKey, err := app.stakerPrivateKey(stakerAddress)
+	stakerPrivKey, err := signer.PrivateKey()
 	if err != nil {
 		return nil, err
 	}
@@ -798,11 +1396,11 @@ func (app *StakerApp) retrieveExternalDelegationData(stakerAddress btcutil.Addre
 
 func (app *StakerApp) sendUnbondingTxToBtcWithWitness(
 	stakingTxHash *chainhash.Hash,
-	stakerAddress btcutil.Address,
+	signer walletcontroller.SignerSession,
 	storedTx *stakerdb.StoredTransaction,
 	unbondingData *stakerdb.UnbondingStoreData,
 ) error {
-	privkey, err := app.stakerPrivateKey(stakerAddress)
+	privkey, err := signer.PrivateKey()
 
 	if err != nil {
 		app.logger.WithFields(logrus.Fields{
@@ -812,18 +1410,24 @@ func (app *StakerApp) sendUnbondingTxToBtcWithWitness(
 		return err
 	}
 
-	// TODO: As covenant committee is static, consider quering it once and storing in database
 	params, err := app.babylonClient.Params()
 
 	if err != nil {
 		return err
 	}
 
+	covenantPks, covenantQuorum, err := app.stakingTxCovenantCommittee(stakingTxHash, storedTx, privkey.PubKey(), params)
+
+	if err != nil {
+		return err
+	}
+
 	witness, err := createWitnessToSendUnbondingTx(
 		privkey,
 		storedTx,
 		unbondingData,
-		params,
+		covenantPks,
+		covenantQuorum,
 		app.network,
 	)
 
@@ -839,54 +1443,127 @@ func (app *StakerApp) sendUnbondingTxToBtcWithWitness(
 
 	unbondingTx.TxIn[0].Witness = witness
 
-	_, err = app.wc.SendRawTransaction(unbondingTx, true)
+	unbondingTxHash, err := app.sendRawTransaction(unbondingTx)
 
 	if err != nil {
 		return err
 	}
 
+	app.labelTransaction(stakingTxHash, unbondingTxHash, unbondingTxLabelPurpose)
+
+	if err := app.txTracker.SetUnbondingTxSentToBtc(stakingTxHash); err != nil {
+		app.logger.WithFields(logrus.Fields{
+			"stakingTxHash": stakingTxHash,
+			"err":           err,
+		}).Warn("Failed to store time of sending unbonding transaction")
+	}
+
 	return nil
 }
 
 // sendUnbondingTxToBtc sends unbonding tx to btc and registers for inclusion notification.
 // It retries until it successfully sends unbonding tx to btc and registers for notification.or until program finishes
+// Returned subscription is nil if confirmation of unbonding tx is already awaited.
 // TODO: Investigate wheter some of the errors should be treated as fatal and abort whole process
 func (app *StakerApp) sendUnbondingTxToBtc(
 	ctx context.Context,
 	stakingTxHash *chainhash.Hash,
 	stakerAddress btcutil.Address,
 	storedTx *stakerdb.StoredTransaction,
-	unbondingData *stakerdb.UnbondingStoreData) (*notifier.ConfirmationEvent, error) {
+	unbondingData *stakerdb.UnbondingStoreData) (*notifier.ConfirmationEvent, *notificationSubscription, error) {
 
-	err := retry.Do(func() error {
-		return app.sendUnbondingTxToBtcWithWitness(
-			stakingTxHash,
-			stakerAddress,
-			storedTx,
-			unbondingData,
+	if err := checkSignable(storedTx); err != nil {
+		return nil, nil, err
+	}
+
+	// key is retrieved once for all send attempts and cleared as soon as
+	// unbonding tx is sent
+	signer := app.newSignerSession(ctx, stakerAddress)
+
+	sendDone := app.trackRetryLoop(sendUnbondingTxOperation, *stakingTxHash)
+
+	var err error
+	for {
+		err = retry.Do(func() error {
+			// time spent while broadcasts are paused does not count as failed attempts
+			if err := app.waitUntilBroadcastsEnabled(ctx, stakingTxHash); err != nil {
+				return retry.Unrecoverable(err)
+			}
+
+			err := app.sendUnbondingTxToBtcWithWitness(
+				stakingTxHash,
+				signer,
+				storedTx,
+				unbondingData,
+			)
+
+			if walletcontroller.IsWalletTimeout(err) {
+				return retry.Unrecoverable(err)
+			}
+
+			return err
+		},
+			longRetryOps(
+				ctx,
+				app.clock,
+				app.config.StakerConfig.UnbondingTxRetryInterval,
+				app.onLongRetryFunc(stakingTxHash, sendUnbondingTxOperation, "failed to send unbonding tx to btc"),
+			)...,
 		)
-	},
-		longRetryOps(
-			ctx,
-			unbondingSendRetryTimeout,
-			app.onLongRetryFunc(stakingTxHash, "failed to send unbonding tx to btc"),
-		)...,
-	)
+
+		if !walletcontroller.IsWalletTimeout(err) {
+			break
+		}
+
+		// unresponsive wallet is not a failure of unbonding itself, so sending is
+		// requeued without using up its retries
+		app.logger.WithFields(logrus.Fields{
+			"stakingTxHash": stakingTxHash,
+			"err":           err,
+		}).Warn("Wallet did not respond in time. Retrying to send unbonding tx to btc")
+
+		select {
+		case <-app.clock.After(app.config.StakerConfig.UnbondingTxRetryInterval):
+		case <-ctx.Done():
+		}
+
+		if ctx.Err() != nil {
+			err = ctx.Err()
+			break
+		}
+	}
+
+	sendDone()
+	signer.Close()
 
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	bestBlockAfterSend := app.currentBestBlockHeight.Load()
 	unbondingTxHash := unbondingData.UnbondingTx.TxHash()
 
+	sub := app.notifications.reserve(unbondingTxHash, UnbondingTxConfirmationPurpose, *stakingTxHash, app.clock.Now())
+
+	if sub == nil {
+		app.logger.WithFields(logrus.Fields{
+			"stakingTxHash":   stakingTxHash,
+			"unbondingTxHash": unbondingTxHash,
+		}).Debug("Already waiting for unbonding tx confirmation")
+		return nil, nil, nil
+	}
+
+	registerDone := app.trackRetryLoop(registerUnbondingTxConfOperation, *stakingTxHash)
+	defer registerDone()
+
 	var notificationEv *notifier.ConfirmationEvent
 	err = retry.Do(func() error {
 		ev, err := app.notifier.RegisterConfirmationsNtfn(
 			&unbondingTxHash,
-			unbondingData.UnbondingTx.TxOut[0].PkScript,
+			unbondingData.UnbondingOutput().PkScript,
 			UnbondingTxConfirmations,
 			bestBlockAfterSend,
+			notifier.WithIncludeBlock(),
 		)
 
 		if err != nil {
@@ -897,28 +1574,50 @@ func (app *StakerApp) sendUnbondingTxToBtc(
 	},
 		longRetryOps(
 			ctx,
-			unbondingSendRetryTimeout,
-			app.onLongRetryFunc(stakingTxHash, "failed to register for unbonding tx confirmation notification"),
+			app.clock,
+			app.config.StakerConfig.UnbondingTxRetryInterval,
+			app.onLongRetryFunc(stakingTxHash, registerUnbondingTxConfOperation, "failed to register for unbonding tx confirmation notification"),
 		)...,
 	)
 
 	if err != nil {
-		return nil, err
+		app.notifications.remove(sub)
+		return nil, nil, err
+	}
+
+	if !app.notifications.attach(sub, notificationEv.Cancel) {
+		// staking transaction stopped being tracked while registering
+		return nil, nil, nil
 	}
-	return notificationEv, nil
+
+	return notificationEv, sub, nil
 }
 
 func (app *StakerApp) waitForUnbondingTxConfirmation(
 	waitEv *notifier.ConfirmationEvent,
+	sub *notificationSubscription,
 	unbondingData *stakerdb.UnbondingStoreData,
 	stakingTxHash *chainhash.Hash,
 ) {
-	defer waitEv.Cancel()
+	defer app.notifications.remove(sub)
 	unbondingTxHash := unbondingData.UnbondingTx.TxHash()
 
+	app.confProgress.started(
+		*stakingTxHash,
+		UnbondingTxConfirmation,
+		unbondingTxHash,
+		UnbondingTxConfirmations,
+		app.clock.Now(),
+	)
+	defer app.confProgress.finished(*stakingTxHash, UnbondingTxConfirmation)
+
 	for {
 		select {
-		case conf := <-waitEv.Confirmed:
+		case conf, ok := <-waitEv.Confirmed:
+			if !ok {
+				return
+			}
+
 			app.logger.WithFields(logrus.Fields{
 				"stakingTxHash":   stakingTxHash,
 				"unbondingTxHash": unbondingTxHash,
@@ -930,8 +1629,19 @@ func (app *StakerApp) waitForUnbondingTxConfirmation(
 				stakingTxHash: *stakingTxHash,
 				blockHash:     *conf.BlockHash,
 				blockHeight:   conf.BlockHeight,
+				blockTime:     blockTime(conf.Block),
 			}
 
+			app.recordEventIntent(&stakerdb.EventIntent{
+				StakingTxHash: *stakingTxHash,
+				Type:          proto.EventIntentType_EVENT_INTENT_UNBONDING_CONFIRMED_ON_BTC,
+				BtcConfirmation: &stakerdb.BtcConfirmationInfo{
+					Height:    req.blockHeight,
+					BlockHash: req.blockHash,
+					BlockTime: req.blockTime,
+				},
+			})
+
 			utils.PushOrQuit[*unbondingTxConfirmedOnBtcEvent](
 				app.unbondingTxConfirmedOnBtcEvChan,
 				req,
@@ -944,6 +1654,9 @@ func (app *StakerApp) waitForUnbondingTxConfirmation(
 				"unbondingTxHash": unbondingTxHash,
 				"confLeft":        u,
 			}).Debugf("Unbonding transaction received confirmation")
+			app.confProgress.update(*stakingTxHash, UnbondingTxConfirmation, u, app.clock.Now())
+		case <-sub.cancelled:
+			return
 		case <-app.quit:
 			return
 		}
@@ -957,11 +1670,10 @@ func (app *StakerApp) sendUnbondingTxToBtcTask(
 	stakerAddress btcutil.Address,
 	storedTx *stakerdb.StoredTransaction,
 	unbondingData *stakerdb.UnbondingStoreData) {
-	defer app.wg.Done()
 	quitCtx, cancel := app.appQuitContext()
 	defer cancel()
 
-	waitEv, err := app.sendUnbondingTxToBtc(
+	waitEv, sub, err := app.sendUnbondingTxToBtc(
 		quitCtx,
 		stakingTxHash,
 		stakerAddress,
@@ -974,8 +1686,13 @@ func (app *StakerApp) sendUnbondingTxToBtcTask(
 		return
 	}
 
+	if sub == nil {
+		return
+	}
+
 	app.waitForUnbondingTxConfirmation(
 		waitEv,
+		sub,
 		unbondingData,
 		stakingTxHash,
 	)
@@ -984,27 +1701,52 @@ func (app *StakerApp) sendUnbondingTxToBtcTask(
 // context which will be cancelled when app is shutting down
 func (app *StakerApp) appQuitContext() (context.Context, func()) {
 	ctx, cancel := context.WithCancel(context.Background())
-	app.wg.Add(1)
-	go func() {
+	app.wg.Go(quitContextGoroutine, func() {
 		defer cancel()
-		defer app.wg.Done()
 
 		select {
 		case <-app.quit:
 
 		case <-ctx.Done():
 		}
-	}()
+	})
 
 	return ctx, cancel
 }
 
 func (app *StakerApp) buildAndSendDelegation(
+	ctx context.Context,
 	req *sendDelegationRequest,
 	stakerAddress btcutil.Address,
 	storedTx *stakerdb.StoredTransaction,
 ) (*pv.RelayerTxResponse, *cl.DelegationData, error) {
-	delegation, err := app.buildDelegation(req, stakerAddress, storedTx)
+	delegation, err := app.buildDelegation(ctx, req, stakerAddress, storedTx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// memo requested when staking is stored with transaction, other transactions
+	// use memo from config
+	memo := storedTx.BabylonMemo
+	if memo == "" {
+		memo, err = app.delegationMemo("", &req.txHash, storedTx.StakerAddress)
+
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	delegation.Memo = memo
+
+	if err := app.waitForDelegationFeeBalance(ctx, req.txHash, delegation); err != nil {
+		return nil, nil, err
+	}
+
+	// delegation cancelled while waiting for retry or fee balance is not sent
+	err = app.delegationSubmissions.begin(req.txHash, func() error {
+		return app.checkDelegationNotCancelled(&req.txHash)
+	})
+
 	if err != nil {
 		return nil, nil, err
 	}
@@ -1012,45 +1754,249 @@ func (app *StakerApp) buildAndSendDelegation(
 	resp, err := app.babylonMsgSender.SendDelegation(delegation, req.requiredInclusionBlockDepth)
 
 	if err != nil {
+		app.delegationSubmissions.finish(req.txHash)
 		return nil, nil, err
 	}
 
 	return resp, delegation, nil
 }
 
+// scheduleDelegationSend starts sending delegation to babylon if number of in-flight
+// delegations is below the limit, otherwise delegation is backlogged and will be
+// sent by backlog drainer when some of in-flight delegations finish
+func (app *StakerApp) scheduleDelegationSend(
+	req *sendDelegationRequest,
+	stakerAddress btcutil.Address,
+	storedTx *stakerdb.StoredTransaction,
+) {
+	if app.config.StakerConfig.ExternalDelegations {
+		app.logger.WithFields(logrus.Fields{
+			"stakingTxHash": req.txHash,
+		}).Info("Staking transaction confirmed on btc. Delegation must be submitted to babylon externally")
+		return
+	}
+
+	// while babylon writes are suspended, delegation waits in backlog without
+	// contacting babylon
+	if app.babylonMsgSender.CircuitRetryIn() == 0 && app.delegationBacklog.tryAcquire() {
+		app.wg.Go(sendDelegationGoroutine, func() {
+			app.sendDelegationToBabylonTask(req, stakerAddress, storedTx)
+		})
+		return
+	}
+
+	app.delegationBacklog.push(req.txHash)
+
+	inFlight, backlogged := app.delegationBacklog.stats()
+
+	app.logger.WithFields(logrus.Fields{
+		"stakingTxHash":         req.txHash,
+		"inFlightDelegations":   inFlight,
+		"backloggedDelegations": backlogged,
+	}).Warn("Too many delegations are being sent to babylon. Delegation backlogged")
+}
+
+// drainDelegationBacklog sends backlogged delegations whenever there is free slot
+// for in-flight delegation
+func (app *StakerApp) drainDelegationBacklog() {
+	// fires when circuit breaker guarding babylon writes allows probing babylon again
+	var circuitRetry <-chan time.Time
+
+	for {
+		select {
+		case <-app.delegationBacklog.wake:
+		case <-circuitRetry:
+		case <-app.quit:
+			return
+		}
+
+		circuitRetry = nil
+
+		if retryIn := app.babylonMsgSender.CircuitRetryIn(); retryIn > 0 {
+			circuitRetry = app.clock.After(retryIn)
+			continue
+		}
+
+		for {
+			stakingTxHash, ok := app.delegationBacklog.next()
+
+			if !ok {
+				break
+			}
+
+			app.wg.Go(sendDelegationGoroutine, func() {
+				app.sendBackloggedDelegationTask(stakingTxHash)
+			})
+		}
+	}
+}
+
+// buildSendDelegationRequest rebuilds request to send delegation from btc chain data
+func (app *StakerApp) buildSendDelegationRequest(
+	stakingTxHash *chainhash.Hash,
+	storedTx *stakerdb.StoredTransaction,
+) (*sendDelegationRequest, error) {
+	params, err := app.babylonClient.Params()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if req := app.sendDelegationRequestFromRecordedProof(stakingTxHash, storedTx, params); req != nil {
+		return req, nil
+	}
+
+	details, status, err := app.wc.TxDetails(
+		stakingTxHash,
+		storedTx.StakingTx.TxOut[storedTx.StakingOutputIndex].PkScript,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if status != walletcontroller.TxInChain {
+		return nil, retry.Unrecoverable(fmt.Errorf("staking transaction %s not found on btc chain", stakingTxHash))
+	}
+
+	return &sendDelegationRequest{
+		txHash:                      *stakingTxHash,
+		txIndex:                     details.TxIndex,
+		inclusionBlockHash:          details.Block.BlockHash(),
+		inclusionBlock:              details.Block,
+		requiredInclusionBlockDepth: uint64(storedRequiredDepth(storedTx, params)),
+	}, nil
+}
+
+func (app *StakerApp) sendBackloggedDelegationTask(stakingTxHash chainhash.Hash) {
+	defer app.delegationBacklog.release()
+
+	ctx, cancel := app.appQuitContext()
+	defer cancel()
+
+	storedTx, stakerAddress, err := app.getTransactionAndStakerAddress(&stakingTxHash)
+
+	if err != nil {
+		// transaction was quarantined, nothing more to do
+		return
+	}
+
+	retrieveDone := app.trackRetryLoop(retrieveBackloggedDelegationOperation, stakingTxHash)
+
+	var req *sendDelegationRequest
+	err = retry.Do(func() error {
+		r, err := app.buildSendDelegationRequest(&stakingTxHash, storedTx)
+
+		if err != nil {
+			return err
+		}
+
+		req = r
+		return nil
+	},
+		longRetryOps(
+			ctx,
+			app.clock,
+			app.config.StakerConfig.BabylonStallingInterval,
+			app.onLongRetryFunc(&stakingTxHash, retrieveBackloggedDelegationOperation, "Failed to retrieve data of backlogged delegation."),
+		)...,
+	)
+
+	retrieveDone()
+
+	if err != nil {
+		app.reportCriticialError(
+			stakingTxHash,
+			err,
+			"Failed to retrieve data of backlogged delegation.",
+		)
+		return
+	}
+
+	app.sendDelegationToBabylon(req, stakerAddress, storedTx)
+}
+
 func (app *StakerApp) sendDelegationToBabylonTask(
 	req *sendDelegationRequest,
 	stakerAddress btcutil.Address,
 	storedTx *stakerdb.StoredTransaction,
 ) {
-	defer app.wg.Done()
+	defer app.delegationBacklog.release()
 
+	app.sendDelegationToBabylon(req, stakerAddress, storedTx)
+}
+
+func (app *StakerApp) sendDelegationToBabylon(
+	req *sendDelegationRequest,
+	stakerAddress btcutil.Address,
+	storedTx *stakerdb.StoredTransaction,
+) {
 	// using app quit context to cancel retrying when app is shutting down
 	ctx, cancel := app.appQuitContext()
 	defer cancel()
 
+	sendDone := app.trackRetryLoop(sendDelegationOperation, req.txHash)
+
 	var delegationData *cl.DelegationData
+	var babylonTxHash string
 	err := retry.Do(func() error {
-		_, del, err := app.buildAndSendDelegation(req, stakerAddress, storedTx)
+		resp, del, err := app.buildAndSendDelegation(ctx, req, stakerAddress, storedTx)
 
 		if err != nil {
-			if errors.Is(err, cl.ErrInvalidBabylonExecution) {
+			// retried until fee grant is renewed, delegation is reported as parked
+			// in the meantime
+			if errors.Is(err, cl.ErrFeeGrantExhausted) {
+				app.babylonBalance.park(req.txHash, err.Error())
+			}
+
+			app.recordBabylonSubmitFailure(&req.txHash, sendDelegationOperation, err)
+
+			if cl.IsTerminalSubmitError(err) ||
+				errors.Is(err, ErrMemoTooLong) ||
+				errors.Is(err, ErrDelegationCancelled) ||
+				errors.Is(err, cl.ErrBabylonCircuitOpen) ||
+				walletcontroller.IsWalletTimeout(err) {
 				return retry.Unrecoverable(err)
 			}
 			return err
 		}
 
 		delegationData = del
+		babylonTxHash = resp.TxHash
 		return nil
 	},
 		longRetryOps(
 			ctx,
+			app.clock,
 			app.config.StakerConfig.BabylonStallingInterval,
-			app.onLongRetryFunc(&req.txHash, "Failed to deliver delegation to babylon due to error."),
+			app.onLongRetryFunc(&req.txHash, sendDelegationOperation, "Failed to deliver delegation to babylon due to error."),
 		)...,
 	)
 
-	if err != nil {
+	sendDone()
+	app.babylonBalance.unpark(req.txHash)
+
+	if errors.Is(err, cl.ErrBabylonCircuitOpen) {
+		// delegation is sent again from backlog once babylon recovers, without
+		// using up its retries
+		app.delegationBacklog.push(req.txHash)
+
+		app.logger.WithFields(logrus.Fields{
+			"stakingTxHash": req.txHash,
+		}).Warn("Sending transactions to babylon is suspended. Delegation backlogged")
+	} else if errors.Is(err, ErrDelegationCancelled) {
+		app.logger.WithFields(logrus.Fields{
+			"stakingTxHash": req.txHash,
+		}).Info("Delegation was cancelled. It is not sent to babylon")
+	} else if walletcontroller.IsWalletTimeout(err) {
+		// unresponsive wallet is not a failure of delegation itself
+		app.delegationBacklog.push(req.txHash)
+
+		app.logger.WithFields(logrus.Fields{
+			"stakingTxHash": req.txHash,
+			"err":           err,
+		}).Warn("Wallet did not respond in time. Delegation backlogged")
+	} else if err != nil {
 		app.reportCriticialError(
 			req.txHash,
 			err,
@@ -1059,9 +2005,12 @@ func (app *StakerApp) sendDelegationToBabylonTask(
 	} else {
 		// report success with the values we sent to Babylon
 		ev := &delegationSubmittedToBabylonEvent{
-			stakingTxHash: req.txHash,
-			unbondingTx:   delegationData.Ud.UnbondingTransaction,
-			unbondingTime: delegationData.Ud.UnbondingTxUnbondingTime,
+			stakingTxHash:      req.txHash,
+			unbondingTx:        delegationData.Ud.UnbondingTransaction,
+			unbondingOutputIdx: delegationData.Ud.UnbondingOutputIdx,
+			unbondingTime:      delegationData.Ud.UnbondingTxUnbondingTime,
+			babylonTxHash:      babylonTxHash,
+			babylonMemo:        delegationData.Memo,
 		}
 
 		utils.PushOrQuit[*delegationSubmittedToBabylonEvent](
@@ -1072,15 +2021,48 @@ func (app *StakerApp) sendDelegationToBabylonTask(
 	}
 }
 
+// recordBabylonSubmitFailure persists rejection of message sent to babylon on
+// record of staking transaction, so that it can be inspected after the fact.
+// Errors other than rejections by babylon are ignored.
+func (app *StakerApp) recordBabylonSubmitFailure(stakingTxHash *chainhash.Hash, operation string, err error) {
+	var submitErr *cl.BabylonSubmitError
+
+	if !errors.As(err, &submitErr) {
+		return
+	}
+
+	failure := &stakerdb.BabylonSubmitFailure{
+		Operation:     operation,
+		Codespace:     submitErr.Codespace,
+		Code:          submitErr.Code,
+		RawLog:        submitErr.RawLog,
+		Executed:      submitErr.Executed,
+		BabylonTxHash: submitErr.TxHash,
+		BabylonHeight: submitErr.Height,
+	}
+
+	if dbErr := app.txTracker.SetTxBabylonSubmitFailure(stakingTxHash, failure); dbErr != nil {
+		app.logger.WithFields(logrus.Fields{
+			"stakingTxHash": stakingTxHash,
+			"err":           dbErr,
+		}).Warn("Failed to record rejection of babylon transaction")
+	}
+}
+
 // main event loop for the staker app
 func (app *StakerApp) handleStakingEvents() {
-	defer app.wg.Done()
-
 	for {
 		select {
 		case ev := <-app.stakingRequestedEvChan:
 			app.logStakingEventReceived(ev)
 
+			// limit is checked again by the main loop, as concurrent requests
+			// could all pass the check done before building transaction
+			if err := app.checkActiveDelegationsLimit(); err != nil {
+				ev.errChan <- err
+				continue
+			}
+
 			bestBlockHeight := app.currentBestBlockHeight.Load()
 
 			if ev.isWatched() {
@@ -1091,14 +2073,18 @@ func (app *StakerApp) handleStakingEvents() {
 					ev.fpBtcPks,
 					babylonPopToDbPop(ev.pop),
 					ev.stakerAddress,
+					ev.paramsVersion,
 					ev.watchTxData.slashingTx,
 					ev.watchTxData.slashingTxSig,
 					ev.watchTxData.stakerBabylonPubKey,
 					ev.watchTxData.stakerBtcPk,
 					ev.watchTxData.unbondingTx,
+					ev.watchTxData.unbondingOutputIdx,
 					ev.watchTxData.slashUnbondingTx,
 					ev.watchTxData.slashUnbondingTxSig,
 					ev.watchTxData.unbondingTime,
+					ev.label,
+					ev.requiredDepthOnBtcChain,
 				)
 
 				if err != nil {
@@ -1106,13 +2092,22 @@ func (app *StakerApp) handleStakingEvents() {
 					continue
 				}
 			} else {
+				// cap is checked again by the main loop, as concurrent requests
+				// could all pass the check done before building transaction
+				if err := app.checkStakedValueCap(ev.stakingValue); err != nil {
+					ev.errChan <- err
+					continue
+				}
+
 				// in case of owend transaction we need to send it, and then add to our tracking db.
-				_, err := app.wc.SendRawTransaction(ev.stakingTx, true)
+				_, err := app.sendRawTransaction(ev.stakingTx)
 				if err != nil {
 					ev.errChan <- err
 					continue
 				}
 
+				app.stakedValueCap.add(ev.stakingTxHash, ev.stakingValue, bestBlockHeight)
+
 				err = app.txTracker.AddTransaction(
 					ev.stakingTx,
 					ev.stakingOutputIdx,
@@ -1120,22 +2115,70 @@ func (app *StakerApp) handleStakingEvents() {
 					ev.fpBtcPks,
 					babylonPopToDbPop(ev.pop),
 					ev.stakerAddress,
+					stakerdb.AddTransactionOpts{
+						ParamsVersion:     ev.paramsVersion,
+						ChangeOutput:      ev.changeOutput,
+						BabylonMemo:       ev.babylonMemo,
+						Label:             ev.label,
+						RequiredDepth:     ev.requiredDepthOnBtcChain,
+						AutoRenew:         ev.autoRenew,
+						BroadcastHeight:   bestBlockHeight,
+						RenewedFromTxHash: ev.renewedFromTxHash,
+					},
 				)
 
 				if err != nil {
 					ev.errChan <- err
 					continue
 				}
+
+				app.labelTransaction(&ev.stakingTxHash, &ev.stakingTxHash, stakingTxLabelPurpose)
+
+				if ev.feeEstimate != nil {
+					app.recordFeeEstimate(&ev.stakingTxHash, stakerdb.StakingTxFeeEstimate, ev.feeEstimate)
+				}
 			}
 
-			if err := app.waitForStakingTransactionConfirmation(
-				&ev.stakingTxHash,
-				ev.stakingOutputPkScript,
-				ev.requiredDepthOnBtcChain,
-				uint32(bestBlockHeight),
-			); err != nil {
-				ev.errChan <- err
-				continue
+			heightHint := uint32(bestBlockHeight)
+
+			if ev.isWatched() {
+				var confirmed bool
+
+				heightHint, confirmed = watchedTxConfirmationStart(
+					ev.watchTxData.btcTxStatus,
+					ev.watchTxData.btcTxInfo,
+					ev.watchTxData.inclusionHeightHint,
+					uint32(bestBlockHeight),
+					ev.requiredDepthOnBtcChain,
+				)
+
+				if confirmed {
+					app.logger.WithFields(logrus.Fields{
+						"btcTxHash":        ev.stakingTxHash,
+						"btcTxBlockHeight": heightHint,
+					}).Info("Watched staking tx already confirmed on btc with required depth")
+
+					app.emitWatchedTxConfirmed(ev)
+					ev.successChan <- &ev.stakingTxHash
+					app.logStakingEventProcessed(ev)
+					continue
+				}
+			}
+
+			// at this point transaction is already sent and stored, so failing to register
+			// for its confirmation must not fail the request, otherwise caller could
+			// stake again. Registration is retried in background instead.
+			app.registerConfirmationOrRetry(ev.stakingTxHash, ev.stakingTxHash, func() error {
+				return app.waitForStakingTransactionConfirmation(
+					&ev.stakingTxHash,
+					ev.stakingOutputPkScript,
+					ev.requiredDepthOnBtcChain,
+					heightHint,
+				)
+			})
+
+			if !ev.isWatched() {
+				app.watchStakingTxInputs(ev.stakingTxHash, ev.stakingTx, heightHint)
 			}
 
 			ev.successChan <- &ev.stakingTxHash
@@ -1148,76 +2191,165 @@ func (app *StakerApp) handleStakingEvents() {
 				&ev.stakingTxHash,
 				&ev.blockHash,
 				ev.blockHeight,
+				blockTime(ev.inlusionBlock),
 			); err != nil {
-				// TODO: handle this error somehow, it means we received confirmation for tx which we do not store
-				// which is seems like programming error. Maybe panic?
-				app.logger.Fatalf("Error setting state for tx %s: %s", ev.stakingTxHash, err)
+				app.handleStateTransitionError(ev, &ev.stakingTxHash, err)
+				continue
 			}
 
+			app.observeLatencies(&ev.stakingTxHash, proto.TransactionState_CONFIRMED_ON_BTC)
+
+			// inputs are now spent by staking transaction itself
+			app.notifications.cancel(ev.stakingTxHash, StakingInputsSpendPurpose)
+
+			app.recordInclusionProof(&ev.stakingTxHash, ev.inlusionBlock, ev.txIndex)
+
 			req := &sendDelegationRequest{
 				txHash:                      ev.stakingTxHash,
 				txIndex:                     ev.txIndex,
+				inclusionBlockHash:          ev.inlusionBlock.BlockHash(),
 				inclusionBlock:              ev.inlusionBlock,
 				requiredInclusionBlockDepth: uint64(ev.blockDepth),
 			}
 
-			storedTx, stakerAddress := app.mustGetTransactionAndStakerAddress(&ev.stakingTxHash)
+			storedTx, stakerAddress, err := app.getTransactionAndStakerAddress(&ev.stakingTxHash)
 
-			// TODO: Introduce max number of sendToDelegationToBabylonTasks. It should be tied to
-			// accepting new staking delegations i.e we will hit it we should stop accepting new stakingrequests
-			// as either babylon node is not healthy or we are constructing invalid delegations
-			app.wg.Add(1)
-			go app.sendDelegationToBabylonTask(req, stakerAddress, storedTx)
+			if err != nil {
+				// transaction was quarantined, nothing more to do
+				continue
+			}
+
+			// never blocks, so that slow babylon node does not stop processing of other events
+			app.scheduleDelegationSend(req, stakerAddress, storedTx)
 			app.logStakingEventProcessed(ev)
 
 		case ev := <-app.delegationSubmittedToBabylonEvChan:
 			app.logStakingEventReceived(ev)
-			if err := app.txTracker.SetTxSentToBabylon(&ev.stakingTxHash, ev.unbondingTx, ev.unbondingTime); err != nil {
-				// TODO: handle this error somehow, it means we received confirmation for tx which we do not store
-				// which is seems like programming error. Maybe panic?
-				app.logger.Fatalf("Error setting state for tx %s: %s", ev.stakingTxHash, err)
+			err := app.txTracker.SetTxSentToBabylon(
+				&ev.stakingTxHash,
+				ev.unbondingTx,
+				ev.unbondingOutputIdx,
+				ev.unbondingTime,
+				ev.babylonTxHash,
+				ev.babylonMemo,
+			)
+
+			// once delegation is recorded as sent, its state refuses cancellation
+			app.delegationSubmissions.finish(ev.stakingTxHash)
+
+			if err != nil {
+				app.handleStateTransitionError(ev, &ev.stakingTxHash, err)
+				continue
 			}
 
+			app.observeLatencies(&ev.stakingTxHash, proto.TransactionState_SENT_TO_BABYLON)
+
 			// start checking for covenant signatures on unbodning transactions
 			// when we receive them we treat delegation as active
-			app.wg.Add(1)
-			go app.checkForUnbondingTxSignaturesOnBabylon(&ev.stakingTxHash)
+			app.wg.Go(unbondingSigsGoroutine, func() {
+				app.checkForUnbondingTxSignaturesOnBabylon(&ev.stakingTxHash, false)
+			})
 
 			app.logStakingEventProcessed(ev)
 
 		case ev := <-app.unbondingTxSignaturesConfirmedOnBabylonEvChan:
 			app.logStakingEventReceived(ev)
 
-			if err := app.txTracker.SetTxUnbondingSignaturesReceived(
+			err := app.txTracker.SetTxUnbondingSignaturesReceived(
 				&ev.stakingTxHash,
 				babylonCovSigsToDbSigSigs(ev.covenantUnbondingSignatures),
-			); err != nil {
-				// TODO: handle this error somehow, it means we possilbly make invalid state transition
-				app.logger.Fatalf("Error setting state for tx %s: %s", &ev.stakingTxHash, err)
+			)
+
+			if err != nil {
+				app.handleStateTransitionError(ev, &ev.stakingTxHash, err)
 			}
 
+			app.completeEventIntent(&ev.stakingTxHash, proto.EventIntentType_EVENT_INTENT_UNBONDING_SIGNATURES_RECEIVED)
+
+			if err != nil {
+				continue
+			}
+
+			app.observeLatencies(&ev.stakingTxHash, proto.TransactionState_DELEGATION_ACTIVE)
+
 			app.logStakingEventProcessed(ev)
 
 		case ev := <-app.unbondingTxConfirmedOnBtcEvChan:
 			app.logStakingEventReceived(ev)
-			if err := app.txTracker.SetTxUnbondingConfirmedOnBtc(
+			err := app.txTracker.SetTxUnbondingConfirmedOnBtc(
 				&ev.stakingTxHash,
 				&ev.blockHash,
 				ev.blockHeight,
-			); err != nil {
-				// TODO: handle this error somehow, it means we received spend stake confirmation for tx which we do not store
-				// which is seems like programming error. Maybe panic?
-				app.logger.Fatalf("Error setting state for tx %s: %s", ev.stakingTxHash, err)
+				ev.blockTime,
+			)
+
+			if err != nil {
+				app.handleStateTransitionError(ev, &ev.stakingTxHash, err)
 			}
+
+			app.completeEventIntent(&ev.stakingTxHash, proto.EventIntentType_EVENT_INTENT_UNBONDING_CONFIRMED_ON_BTC)
+
+			if err != nil {
+				continue
+			}
+
+			app.observeLatencies(&ev.stakingTxHash, proto.TransactionState_UNBONDING_CONFIRMED_ON_BTC)
 			app.logStakingEventProcessed(ev)
 
 		case ev := <-app.spendStakeTxConfirmedOnBtcEvChan:
 			app.logStakingEventReceived(ev)
-			if err := app.txTracker.SetTxSpentOnBtc(&ev.stakingTxHash); err != nil {
-				// TODO: handle this error somehow, it means we received spend stake confirmation for tx which we do not store
-				// which is seems like programming error. Maybe panic?
-				app.logger.Fatalf("Error setting state for tx %s: %s", ev.stakingTxHash, err)
+			if err := app.txTracker.SetTxSpentOnBtc(&ev.stakingTxHash, ev.spendTxConfirmation); err != nil {
+				app.handleStateTransitionError(ev, &ev.stakingTxHash, err)
+				continue
+			}
+			// other spends of the stake can no longer confirm
+			app.cancelNotifications(&ev.stakingTxHash)
+			app.logStakingEventProcessed(ev)
+
+		case ev := <-app.delegationExpiredOnBabylonEvChan:
+			app.logStakingEventReceived(ev)
+			if err := app.txTracker.SetTxDelegationExpired(&ev.stakingTxHash); err != nil {
+				app.handleStateTransitionError(ev, &ev.stakingTxHash, err)
+				continue
 			}
+
+			app.logger.WithFields(logrus.Fields{
+				"stakingTxHash": ev.stakingTxHash,
+			}).Warn("Delegation expired on babylon before it became active. Staked funds can be withdrawn once staking timelock expires")
+
+			app.logStakingEventProcessed(ev)
+
+		case ev := <-app.unbondingSignaturesTimeoutEvChan:
+			app.logStakingEventReceived(ev)
+			if err := app.txTracker.SetTxUnbondingSignaturesTimeout(&ev.stakingTxHash); err != nil {
+				app.handleStateTransitionError(ev, &ev.stakingTxHash, err)
+				continue
+			}
+
+			app.logger.WithFields(logrus.Fields{
+				"stakingTxHash": ev.stakingTxHash,
+				"timeout":       ev.timeout,
+			}).Error("Covenant unbonding signatures were not received in time. Babylon is checked less often until waiting is retried or aborted")
+
+			app.logStakingEventProcessed(ev)
+
+		case ev := <-app.stakingTxInputConflictEvChan:
+			app.logStakingEventReceived(ev)
+			if err := app.txTracker.SetTxConflicted(&ev.stakingTxHash, &ev.conflictingTxHash); err != nil {
+				app.handleStateTransitionError(ev, &ev.stakingTxHash, err)
+				continue
+			}
+
+			// staking transaction can never be confirmed
+			app.cancelNotifications(&ev.stakingTxHash)
+			app.confRegistrations.remove(ev.stakingTxHash)
+
+			app.logger.WithFields(logrus.Fields{
+				"stakingTxHash":     ev.stakingTxHash,
+				"conflictingTxHash": ev.conflictingTxHash,
+				"outpoint":          ev.outpoint,
+			}).Error("Input of staking transaction was spent by other transaction. Staking transaction will never be confirmed")
+
 			app.logStakingEventProcessed(ev)
 
 		case ev := <-app.criticalErrorEvChan:
@@ -1263,12 +2395,27 @@ func (app *StakerApp) BabylonController() cl.BabylonClient {
 	return app.babylonClient
 }
 
+// BabylonCircuitStatus returns state of circuit breaker guarding sending of
+// transactions to babylon
+func (app *StakerApp) BabylonCircuitStatus() *cl.CircuitBreakerStatus {
+	return app.babylonMsgSender.CircuitStatus()
+}
+
+// BabylonBatchStats returns statistics of babylon transactions carrying delegations
+func (app *StakerApp) BabylonBatchStats() cl.BatchStats {
+	return app.babylonMsgSender.BatchStats()
+}
+
 // Generate proof of possessions for staker address.
 // Requires btc wallet to be unlocked!
-func (app *StakerApp) generatePop(stakerPrivKey *btcec.PrivateKey) (*cl.BabylonPop, error) {
+func (app *StakerApp) generatePop(signer walletcontroller.SignerSession) (*cl.BabylonPop, error) {
 	// build proof of possession, no point moving forward if staker does not have all
 	// the necessary keys
-	stakerKey := stakerPrivKey.PubKey()
+	stakerKey, err := signer.PubKey()
+
+	if err != nil {
+		return nil, err
+	}
 
 	encodedPubKey := schnorr.SerializePubKey(stakerKey)
 
@@ -1282,7 +2429,7 @@ func (app *StakerApp) generatePop(stakerPrivKey *btcec.PrivateKey) (*cl.BabylonP
 
 	babylonSigHash := tmhash.Sum(babylonSig)
 
-	btcSig, err := schnorr.Sign(stakerPrivKey, babylonSigHash)
+	btcSig, err := signer.SignSchnorr(babylonSigHash)
 
 	if err != nil {
 		return nil, err
@@ -1325,13 +2472,36 @@ func (app *StakerApp) WatchStaking(
 	slashUnbondingTx *wire.MsgTx,
 	slashUnbondingTxSig *schnorr.Signature,
 	unbondingTime uint16,
+	rescanStartHeight *uint32,
+	inclusionHeightHint *uint32,
+	label string,
+	requiredDepthOverride *uint32,
 ) (*chainhash.Hash, error) {
+	if err := app.checkActiveDelegationsLimit(); err != nil {
+		return nil, err
+	}
+
 	currentParams, err := app.babylonClient.Params()
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to watch staking tx. Failed to get params: %w", err)
 	}
 
+	requiredDepth, err := app.requiredConfirmationDepth(requiredDepthOverride, currentParams)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to watch staking tx: %w", err)
+	}
+
+	paramsVersion, err := app.txTracker.RegisterStakingParams(
+		currentParams.CovenantPks,
+		currentParams.CovenantQuruomThreshold,
+	)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to watch staking tx. Failed to register params: %w", err)
+	}
+
 	if len(fpPks) == 0 {
 		return nil, fmt.Errorf("no finality provider public keys provided")
 	}
@@ -1340,6 +2510,16 @@ func (app *StakerApp) WatchStaking(
 		return nil, fmt.Errorf("duplicate finality provider public keys provided")
 	}
 
+	// watched transaction already exists, so long staking time is only reported
+	if ExceedsStakingTimeLimit(uint64(stakingTime), app.config.StakerConfig.MaxStakingTimeBlocks) {
+		app.logger.WithFields(logrus.Fields{
+			"btxTxHash":      stakingTx.TxHash(),
+			"stakingTime":    stakingTime,
+			"maxStakingTime": app.config.StakerConfig.MaxStakingTimeBlocks,
+			"lockDuration":   LockDays(app.blocksDuration(uint32(stakingTime))),
+		}).Warn("Watched staking transaction has staking time above maximum staking time")
+	}
+
 	watchedRequest, err := parseWatchStakingRequest(
 		stakingTx,
 		stakingTime,
@@ -1355,7 +2535,10 @@ func (app *StakerApp) WatchStaking(
 		slashUnbondingTx,
 		slashUnbondingTxSig,
 		unbondingTime,
+		label,
+		requiredDepth,
 		currentParams,
+		paramsVersion,
 		app.network,
 	)
 
@@ -1370,6 +2553,38 @@ func (app *StakerApp) WatchStaking(
 		}
 	}
 
+	stakingTxHash := stakingTx.TxHash()
+
+	// make sure wallet knows about staking transaction, it is not an error if it
+	// was not found, as it may be not yet sent to btc
+	details, status, err := app.txDetailsWithRescan(
+		&stakingTxHash,
+		watchedRequest.stakingOutputPkScript,
+		rescanStartHeight,
+	)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to watch staking tx: %w", err)
+	}
+
+	if status == walletcontroller.TxNotFound {
+		// transaction which is already on btc was accepted by the network, so only
+		// transactions which were not yet sent are checked against relay policy
+		if err := app.checkWatchedStakingTxStandard(stakingTx); err != nil {
+			return nil, fmt.Errorf("failed to watch staking tx: %w", err)
+		}
+
+		app.logger.WithFields(logrus.Fields{
+			"btxTxHash": stakingTxHash,
+		}).Info("Watched staking tx not found on btc. Waiting for it to be sent")
+	}
+
+	// transaction may be already confirmed, in which case it is not awaited
+	// from current tip
+	watchedRequest.watchTxData.btcTxStatus = status
+	watchedRequest.watchTxData.btcTxInfo = details
+	watchedRequest.watchTxData.inclusionHeightHint = inclusionHeightHint
+
 	app.logger.WithFields(logrus.Fields{
 		"stakerAddress": stakerAddress,
 		"stakingAmount": watchedRequest.stakingTx.TxOut[watchedRequest.stakingOutputIdx].Value,
@@ -1431,17 +2646,19 @@ func (app *StakerApp) GetStakeOutput(
 		return nil, err
 	}
 
-	slashingFee := app.getSlashingFee(params.MinSlashingTxFeeSat)
+	slashingFee, err := app.getSlashingFee(params, len(fpPks))
+
+	if err != nil {
+		return nil, err
+	}
 
 	if stakingAmount <= slashingFee {
 		return nil, fmt.Errorf("staking amount %d is less than minimum slashing fee %d",
 			stakingAmount, slashingFee)
 	}
 
-	minStakingTime := GetMinStakingTime(params)
-	if uint32(stakingTimeBlocks) < minStakingTime {
-		return nil, fmt.Errorf("staking time %d is less than minimum staking time %d",
-			stakingTimeBlocks, minStakingTime)
+	if _, err := ValidateStakingTime(uint64(stakingTimeBlocks), GetMinStakingTime(params)); err != nil {
+		return nil, err
 	}
 
 	output, err := staking.BuildStakingInfo(
@@ -1466,6 +2683,13 @@ func (app *StakerApp) StakeFunds(
 	stakingAmount btcutil.Amount,
 	fpPks []*btcec.PublicKey,
 	stakingTimeBlocks uint16,
+	confTarget *uint32,
+	memo string,
+	label string,
+	requiredDepthOverride *uint32,
+	acknowledgeLongLock bool,
+	autoRenew bool,
+	freshPop bool,
 ) (*chainhash.Hash, error) {
 
 	// check we are not shutting down
@@ -1476,6 +2700,32 @@ func (app *StakerApp) StakeFunds(
 	default:
 	}
 
+	if err := app.checkWalletEnabled(); err != nil {
+		return nil, err
+	}
+
+	// reject unsupported addresses early, instead of failing during creation of
+	// delegation or unbonding data
+	if err := walletcontroller.ValidateStakerAddress(stakerAddress); err != nil {
+		return nil, err
+	}
+
+	if err := app.checkActiveDelegationsLimit(); err != nil {
+		return nil, err
+	}
+
+	if err := app.checkStakedValueCap(stakingAmount); err != nil {
+		return nil, err
+	}
+
+	if err := app.checkBroadcastsEnabled(); err != nil {
+		return nil, err
+	}
+
+	if err := app.checkWalletSynced(); err != nil {
+		return nil, err
+	}
+
 	if len(fpPks) == 0 {
 		return nil, fmt.Errorf("no finality providers public keys provided")
 	}
@@ -1496,44 +2746,72 @@ func (app *StakerApp) StakeFunds(
 		return nil, err
 	}
 
-	slashingFee := app.getSlashingFee(params.MinSlashingTxFeeSat)
+	slashingFee, err := app.getSlashingFee(params, len(fpPks))
+
+	if err != nil {
+		return nil, err
+	}
 
 	if stakingAmount <= slashingFee {
 		return nil, fmt.Errorf("staking amount %d is less than minimum slashing fee %d",
 			stakingAmount, slashingFee)
 	}
 
-	minStakingTime := GetMinStakingTime(params)
-	if uint32(stakingTimeBlocks) < minStakingTime {
-		return nil, fmt.Errorf("staking time %d is less than minimum staking time %d",
-			stakingTimeBlocks, minStakingTime)
+	if _, err := ValidateStakingTime(uint64(stakingTimeBlocks), GetMinStakingTime(params)); err != nil {
+		return nil, err
+	}
+
+	if err := app.checkStakingTimeLimit(stakingTimeBlocks, acknowledgeLongLock); err != nil {
+		return nil, err
 	}
 
-	// unlock wallet for the rest of the operations
-	// TODO consider unlock/lock with defer
-	err = app.wc.UnlockWallet(defaultWalletUnlockTimeout)
+	requiredDepth, err := app.requiredConfirmationDepth(requiredDepthOverride, params)
 
 	if err != nil {
 		return nil, err
 	}
 
+	feeEstimate := app.estimateFee(
+		confTargetOrDefault(confTarget, app.config.StakerConfig.StakingTxConfTarget),
+	)
+	feeRate := feeEstimate.FeeRate()
+
+	// fail before touching staker keys if wallet cannot fund the stake
+	if _, err := app.estimateStakeFunding(stakingAmount, feeRate, stakerAddress); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := app.appQuitContext()
+	defer cancel()
+
+	// retrieving staker key also unlocks wallet for the rest of the operations
+	signer := app.newSignerSession(ctx, stakerAddress)
+	defer signer.Close()
+
 	// build proof of possesion, no point moving forward if staker do not have all
 	// the necessary keys
-	stakerPrivKey, err := app.wc.DumpPrivateKey(stakerAddress)
+	stakerPubKey, err := signer.PubKey()
 
 	if err != nil {
 		return nil, err
 	}
 
-	// We build pop ourselves so no need to verify it
-	pop, err := app.generatePop(stakerPrivKey)
+	// script with such keys would be rejected by babylon only after staking tx
+	// is already on btc
+	if err := validateFinalityProviderKeys(fpPks, stakerPubKey, params.CovenantPks); err != nil {
+		return nil, err
+	}
+
+	// We build pop ourselves so no need to verify it, cached pop is verified
+	// before it is reused
+	pop, err := app.stakerPop(signer, stakerAddress, stakerPubKey, freshPop)
 
 	if err != nil {
 		return nil, err
 	}
 
 	stakingInfo, err := staking.BuildStakingInfo(
-		stakerPrivKey.PubKey(),
+		stakerPubKey,
 		fpPks,
 		params.CovenantPks,
 		params.CovenantQuruomThreshold,
@@ -1546,21 +2824,56 @@ func (app *StakerApp) StakeFunds(
 		return nil, fmt.Errorf("failed to build staking info: %w", err)
 	}
 
-	feeRate := app.feeEstimator.EstimateFeePerKb()
+	// remember covenant committee controlling staking output, in case it is
+	// rotated before we manage to send delegation to babylon
+	paramsVersion, err := app.txTracker.RegisterStakingParams(
+		params.CovenantPks,
+		params.CovenantQuruomThreshold,
+	)
+
+	if err != nil {
+		return nil, err
+	}
 
-	tx, err := app.wc.CreateAndSignTx([]*wire.TxOut{stakingInfo.StakingOutput}, btcutil.Amount(feeRate), stakerAddress)
+	signCtx, cancelSign := context.WithTimeout(ctx, app.config.WalletRpcConfig.WalletRpcTimeout)
+	tx, err := app.wc.CreateAndSignTx(signCtx, []*wire.TxOut{stakingInfo.StakingOutput}, feeRate, stakerAddress)
+	cancelSign()
 
 	if err != nil {
 		return nil, err
 	}
 
+	// wallet can fund large stake from many small utxos, which results in large
+	// transaction with unexpectedly high fee
+	if err := walletcontroller.CheckTxLimits(tx, app.stakingTxLimits()); err != nil {
+		return nil, err
+	}
+
 	app.logger.WithFields(logrus.Fields{
-		"stakerAddress": stakerAddress,
-		"stakingAmount": stakingInfo.StakingOutput,
-		"btxTxHash":     tx.TxHash(),
-		"fee":           feeRate,
+		"stakerAddress":       stakerAddress,
+		"stakingAmount":       stakingInfo.StakingOutput,
+		"btxTxHash":           tx.TxHash(),
+		"fee":                 feeRate,
+		"stakingTime":         stakingTimeBlocks,
+		"maxStakingTime":      app.config.StakerConfig.MaxStakingTimeBlocks,
+		"lockDuration":        LockDays(app.blocksDuration(uint32(stakingTimeBlocks))),
+		"acknowledgeLongLock": acknowledgeLongLock,
+		"autoRenew":           autoRenew,
 	}).Info("Created and signed staking transaction")
 
+	changeOutput, err := findChangeOutput(tx, 0, stakerAddress)
+
+	if err != nil {
+		return nil, err
+	}
+
+	stakingTxHash := tx.TxHash()
+	babylonMemo, err := app.delegationMemo(memo, &stakingTxHash, stakerAddress.EncodeAddress())
+
+	if err != nil {
+		return nil, err
+	}
+
 	req := newOwnedStakingRequest(
 		stakerAddress,
 		tx,
@@ -1569,9 +2882,15 @@ func (app *StakerApp) StakeFunds(
 		stakingTimeBlocks,
 		stakingAmount,
 		fpPks,
-		params.ConfirmationTimeBlocks,
+		requiredDepth,
 		pop,
+		paramsVersion,
+		changeOutput,
+		babylonMemo,
+		label,
+		autoRenew,
 	)
+	req.feeEstimate = feeEstimate
 
 	utils.PushOrQuit[*stakingRequestedEvent](
 		app.stakingRequestedEvChan,
@@ -1594,11 +2913,23 @@ func (app *StakerApp) StakeFunds(
 	}
 }
 
-func (app *StakerApp) StoredTransactions(limit, offset uint64) (*stakerdb.StoredTransactionQueryResult, error) {
+// StoredTransactions returns page of tracked transactions. If label is not empty,
+// only transactions with given label are returned.
+// StoredTransactions returns page of stored transactions. Empty label and nil
+// state do not filter transactions.
+func (app *StakerApp) StoredTransactions(
+	limit, offset uint64,
+	label string,
+	state *proto.TransactionState,
+	includeArchived bool,
+) (*stakerdb.StoredTransactionQueryResult, error) {
 	query := stakerdb.StoredTransactionQuery{
 		IndexOffset:        offset,
 		NumMaxTransactions: limit,
 		Reversed:           false,
+		Label:              label,
+		State:              state,
+		IncludeArchived:    includeArchived,
 	}
 	resp, err := app.txTracker.QueryStoredTransactions(query)
 	if err != nil {
@@ -1626,27 +2957,110 @@ func (app *StakerApp) GetStoredTransaction(txHash *chainhash.Hash) (*stakerdb.St
 	return app.txTracker.GetTransaction(txHash)
 }
 
+// SetTransactionLabel assigns label to tracked transaction. Empty label removes
+// label from transaction.
+func (app *StakerApp) SetTransactionLabel(txHash *chainhash.Hash, label string) error {
+	return app.txTracker.SetTransactionLabel(txHash, label)
+}
+
+// SetTransactionNote assigns free-form note to tracked transaction. Empty note
+// removes note from transaction.
+func (app *StakerApp) SetTransactionNote(txHash *chainhash.Hash, note string) error {
+	return app.txTracker.SetTransactionNote(txHash, note)
+}
+
+// GetTransactionNote returns note of tracked transaction, empty if transaction
+// has no note
+func (app *StakerApp) GetTransactionNote(txHash *chainhash.Hash) (string, error) {
+	return app.txTracker.GetTransactionNote(txHash)
+}
+
+// SearchTransactions returns tracked transactions which label or note contains
+// query
+func (app *StakerApp) SearchTransactions(query string) ([]stakerdb.TransactionSearchMatch, error) {
+	return app.txTracker.SearchTransactions(query)
+}
+
+func (app *StakerApp) GetWatchedTransactionData(txHash *chainhash.Hash) (*stakerdb.WatchedTransactionData, error) {
+	return app.txTracker.GetWatchedTransactionData(txHash)
+}
+
 func (app *StakerApp) ListUnspentOutputs() ([]walletcontroller.Utxo, error) {
 	return app.wc.ListOutputs(false)
 }
 
-func (app *StakerApp) waitForSpendConfirmation(stakingTxHash chainhash.Hash, ev *notifier.ConfirmationEvent) {
+type PendingChange struct {
+	Total btcutil.Amount
+	// staking transactions, which change is still unconfirmed
+	Transactions []stakerdb.StoredTransaction
+}
+
+// PendingChange returns change of staking transactions created by staker, which are
+// not yet confirmed on btc. Wallet treats such change as unconfirmed.
+func (app *StakerApp) PendingChange() (*PendingChange, error) {
+	var pending PendingChange
+
+	reset := func() {
+		pending = PendingChange{}
+	}
+
+	err := app.txTracker.ScanTrackedTransactions(func(tx *stakerdb.StoredTransaction) error {
+		if tx.State != proto.TransactionState_SENT_TO_BTC || tx.ChangeOutput == nil {
+			return nil
+		}
+
+		pending.Total += tx.ChangeOutput.Amount
+		pending.Transactions = append(pending.Transactions, *tx)
+		return nil
+	}, reset)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &pending, nil
+}
+
+func (app *StakerApp) waitForSpendConfirmation(
+	stakingTxHash chainhash.Hash,
+	spendTxHash chainhash.Hash,
+	ev *notifier.ConfirmationEvent,
+	sub *notificationSubscription,
+) {
+	defer app.notifications.remove(sub)
+
 	// check we are not shutting down
 	select {
 	case <-app.quit:
-		ev.Cancel()
 		return
 
 	default:
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), timeoutWaitingForSpendConfirmation)
-	defer cancel()
+	app.confProgress.started(
+		stakingTxHash,
+		SpendTxConfirmation,
+		spendTxHash,
+		SpendStakeTxConfirmations,
+		app.clock.Now(),
+	)
+	defer app.confProgress.finished(stakingTxHash, SpendTxConfirmation)
+
+	timeout := app.clock.After(app.config.StakerConfig.SpendTxConfTimeout)
 	for {
 		select {
-		case <-ev.Confirmed:
+		case conf, ok := <-ev.Confirmed:
+			if !ok {
+				return
+			}
+
 			stakingEvent := &spendStakeTxConfirmedOnBtcEvent{
-				stakingTxHash,
+				stakingTxHash: stakingTxHash,
+				spendTxConfirmation: &stakerdb.BtcConfirmationInfo{
+					Height:    conf.BlockHeight,
+					BlockHash: *conf.BlockHash,
+					BlockTime: blockTime(conf.Block),
+				},
 			}
 
 			// transaction which spends staking transaction is confirmed on BTC inform
@@ -1657,15 +3071,18 @@ func (app *StakerApp) waitForSpendConfirmation(stakingTxHash chainhash.Hash, ev
 				app.quit,
 			)
 
-			ev.Cancel()
 			return
-		case <-ctx.Done():
+		case u := <-ev.Updates:
+			app.confProgress.update(stakingTxHash, SpendTxConfirmation, u, app.clock.Now())
+		case <-timeout:
 			// we timed out waiting for confirmation, transaction is stuck in mempool
 			return
 
+		case <-sub.cancelled:
+			return
+
 		case <-app.quit:
-			// app is quitting, cancel the event
-			ev.Cancel()
+			// app is quitting, subscription is cancelled on return
 			return
 		}
 	}
@@ -1678,7 +3095,25 @@ func (app *StakerApp) waitForSpendConfirmation(stakingTxHash chainhash.Hash, ev
 // unbonding of his stake.
 // We find in which type of output stake is locked by checking state of staking transaction, and build
 // proper spend transaction based on that state.
-func (app *StakerApp) SpendStake(stakingTxHash *chainhash.Hash) (*chainhash.Hash, *btcutil.Amount, error) {
+// If maxFeeFraction is not nil, spend is rejected if its fee exceeds given fraction
+// of the spent output value. If confTarget is nil, confirmation target from config
+// is used to estimate fee.
+func (app *StakerApp) SpendStake(
+	stakingTxHash *chainhash.Hash,
+	maxFeeFraction *float64,
+	confTarget *uint32,
+) (*chainhash.Hash, *btcutil.Amount, error) {
+	return app.spendStake(stakingTxHash, nil, maxFeeFraction, confTarget)
+}
+
+// spendStake spends stake identified by stakingTxHash to destAddress. If destAddress
+// is nil, stake is spent back to staker address.
+func (app *StakerApp) spendStake(
+	stakingTxHash *chainhash.Hash,
+	destAddress btcutil.Address,
+	maxFeeFraction *float64,
+	confTarget *uint32,
+) (*chainhash.Hash, *btcutil.Amount, error) {
 	// check we are not shutting down
 	select {
 	case <-app.quit:
@@ -1687,6 +3122,10 @@ func (app *StakerApp) SpendStake(stakingTxHash *chainhash.Hash) (*chainhash.Hash
 	default:
 	}
 
+	if err := app.checkWalletEnabled(); err != nil {
+		return nil, nil, err
+	}
+
 	tx, err := app.txTracker.GetTransaction(stakingTxHash)
 
 	if err != nil {
@@ -1695,23 +3134,36 @@ func (app *StakerApp) SpendStake(stakingTxHash *chainhash.Hash) (*chainhash.Hash
 
 	// we cannont spend tx which is watch only.
 	// TODO. To make it possible additional endpoint is needed
-	if tx.Watched {
-		return nil, nil, fmt.Errorf("cannot spend staking which which is in watch only mode")
+	if err := checkSignable(tx); err != nil {
+		return nil, nil, fmt.Errorf("cannot spend staking output: %w", err)
 	}
 
-	// this coud happen if we stared staker on wrong network.
-	// TODO: consider storing data for different networks in different folders
-	// to avoid this
-	// Currently we spend funds from staking transaction to the same address. This
-	// could be improved by allowing user to specify destination address, although
-	// this destination address would need to control the expcted priv key to sign
-	// transaction
-	destAddress, err := btcutil.DecodeAddress(tx.StakerAddress, app.network)
+	if tx.Archived {
+		return nil, nil, fmt.Errorf("cannot spend staking output: %w", stakerdb.ErrTransactionArchived)
+	}
+
+	// network of db is checked on startup, so this can only happen if address
+	// was stored by some other tool
+	stakerAddress, err := btcutil.DecodeAddress(tx.StakerAddress, app.network)
 
 	if err != nil {
 		return nil, nil, fmt.Errorf("cannot spend staking output. Error decoding staker address: %w", err)
 	}
 
+	// Spend transaction is always signed by the key of staker address, only
+	// destination of funds can differ
+	if destAddress == nil {
+		destAddress = stakerAddress
+	}
+
+	if err := app.checkSpendDestination(destAddress, stakerAddress); err != nil {
+		return nil, nil, fmt.Errorf("cannot spend staking output: %w", err)
+	}
+
+	if err := app.checkWalletSynced(); err != nil {
+		return nil, nil, fmt.Errorf("cannot spend staking output: %w", err)
+	}
+
 	destAddressScript, err := txscript.PayToAddrScript(destAddress)
 
 	if err != nil {
@@ -1724,21 +3176,35 @@ func (app *StakerApp) SpendStake(stakingTxHash *chainhash.Hash) (*chainhash.Hash
 		return nil, nil, fmt.Errorf("cannot spend staking output. Error getting params: %w", err)
 	}
 
-	privKey, err := app.stakerPrivateKey(destAddress)
+	ctx, cancel := app.appQuitContext()
+	defer cancel()
+
+	signer := app.newSignerSession(ctx, stakerAddress)
+	defer signer.Close()
+
+	privKey, err := signer.PrivateKey()
 
 	if err != nil {
 		return nil, nil, fmt.Errorf("cannot spend staking output. Error getting private key: %w", err)
 	}
 
-	currentFeeRate := app.feeEstimator.EstimateFeePerKb()
+	covenantPks, covenantQuorum, err := app.stakingTxCovenantCommittee(stakingTxHash, tx, privKey.PubKey(), params)
+
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot spend staking output: %w", err)
+	}
+
+	feeEstimate := app.estimateFee(
+		confTargetOrDefault(confTarget, app.config.StakerConfig.SpendTxConfTarget),
+	)
 
 	spendStakeTxInfo, err := createSpendStakeTxFromStoredTx(
 		privKey.PubKey(),
-		params.CovenantPks,
-		params.CovenantQuruomThreshold,
+		covenantPks,
+		covenantQuorum,
 		tx,
 		destAddressScript,
-		currentFeeRate,
+		chainfee.SatPerKVByte(feeEstimate.FeeRate()),
 		app.network,
 	)
 
@@ -1746,36 +3212,45 @@ func (app *StakerApp) SpendStake(stakingTxHash *chainhash.Hash) (*chainhash.Hash
 		return nil, nil, err
 	}
 
-	stakerSig, err := staking.SignTxWithOneScriptSpendInputFromTapLeaf(
-		spendStakeTxInfo.spendStakeTx,
-		spendStakeTxInfo.fundingOutput,
-		privKey,
-		spendStakeTxInfo.fundingOutputSpendInfo.RevealedLeaf,
-	)
-
-	if err != nil {
-		return nil, nil, fmt.Errorf("cannot spend staking output. Error building signature: %w", err)
+	if err := checkSpendFee(
+		spendStakeTxInfo.calculatedFee,
+		btcutil.Amount(spendStakeTxInfo.fundingOutput.Value),
+		maxFeeFraction,
+	); err != nil {
+		return nil, nil, fmt.Errorf("cannot spend staking output: %w", err)
 	}
 
-	witness, err := spendStakeTxInfo.fundingOutputSpendInfo.CreateTimeLockPathWitness(
-		stakerSig,
-	)
-
-	if err != nil {
-		return nil, nil, fmt.Errorf("cannot spend staking output. Error building witness: %w", err)
+	if err := signSpendStakeTx(spendStakeTxInfo, privKey); err != nil {
+		return nil, nil, fmt.Errorf("cannot spend staking output. %w", err)
 	}
 
-	spendStakeTxInfo.spendStakeTx.TxIn[0].Witness = witness
-
 	// We do not check if transaction is spendable i.e the staking time has passed
 	// as this is validated in mempool so in of not meeting this time requirement
 	// we will receive error here: `transaction's sequence locks on inputs not met`
-	spendTxHash, err := app.wc.SendRawTransaction(spendStakeTxInfo.spendStakeTx, true)
+	spendTxHash, err := app.sendRawTransaction(spendStakeTxInfo.spendStakeTx)
 
 	if err != nil {
 		return nil, nil, fmt.Errorf("cannot spend staking output. Error sending tx: %w", err)
 	}
 
+	app.labelTransaction(stakingTxHash, spendTxHash, spendTxLabelPurpose)
+
+	if err := app.txTracker.SetSpendTxSent(
+		stakingTxHash,
+		spendTxHash,
+		btcutil.Amount(spendStakeTxInfo.fundingOutput.Value),
+		spendStakeTxInfo.calculatedFee,
+	); err != nil {
+		// spend is already sent, missing data only makes history incomplete
+		app.logger.WithFields(logrus.Fields{
+			"stakingTxHash": stakingTxHash,
+			"spendTxHash":   spendTxHash,
+			"err":           err,
+		}).Warn("Failed to store spend transaction data")
+	}
+
+	app.recordFeeEstimate(stakingTxHash, stakerdb.SpendTxFeeEstimate, feeEstimate)
+
 	spendTxValue := btcutil.Amount(spendStakeTxInfo.spendStakeTx.TxOut[0].Value)
 
 	app.logger.WithFields(logrus.Fields{
@@ -1783,33 +3258,117 @@ func (app *StakerApp) SpendStake(stakingTxHash *chainhash.Hash) (*chainhash.Hash
 		"spendTxHash":   spendTxHash,
 		"spendTxValue":  spendTxValue,
 		"fee":           spendStakeTxInfo.calculatedFee,
-		"stakerAddress": destAddress,
+		"stakerAddress": stakerAddress,
 		"destAddress":   destAddress,
 	}).Infof("Successfully sent transaction spending staking output")
 
-	confEvent, err := app.notifier.RegisterConfirmationsNtfn(
-		spendTxHash,
-		spendStakeTxInfo.spendStakeTx.TxOut[0].PkScript,
-		SpendStakeTxConfirmations,
-		app.currentBestBlockHeight.Load(),
+	app.watchSpendTxConfirmation(stakingTxHash, spendTxHash, spendStakeTxInfo.spendStakeTx.TxOut[0].PkScript)
+
+	return spendTxHash, &spendTxValue, nil
+}
+
+// signSpendStakeTx signs spend transaction built by createSpendStakeTxFromStoredTx
+// using timelock path of the staking output
+func signSpendStakeTx(info *spendStakeTxInfo, privKey *btcec.PrivateKey) error {
+	stakerSig, err := staking.SignTxWithOneScriptSpendInputFromTapLeaf(
+		info.spendStakeTx,
+		info.fundingOutput,
+		privKey,
+		info.fundingOutputSpendInfo.RevealedLeaf,
+	)
+
+	if err != nil {
+		return fmt.Errorf("error building signature: %w", err)
+	}
+
+	witness, err := info.fundingOutputSpendInfo.CreateTimeLockPathWitness(
+		stakerSig,
 	)
 
 	if err != nil {
-		return nil, nil, fmt.Errorf("spend tx sent. Error registering confirmation notifcation: %w", err)
+		return fmt.Errorf("error building witness: %w", err)
 	}
 
+	info.spendStakeTx.TxIn[0].Witness = witness
+	return nil
+}
+
+// watchSpendTxConfirmation waits for confirmation of sent transaction spending
+// staking output, after which staking transaction is marked as spent
+func (app *StakerApp) watchSpendTxConfirmation(
+	stakingTxHash *chainhash.Hash,
+	spendTxHash *chainhash.Hash,
+	spendTxPkScript []byte,
+) {
+	heightHint := app.currentBestBlockHeight.Load()
+
 	// We are gonna mark our staking transaction as spent on BTC network, only when
 	// we receive enough confirmations on btc network. This means that btc staker can send another
 	// tx which will spend this staking output concurrently. In that case the first one
 	// confirmed on btc networks which will mark our staking transaction as spent on BTC network.
 	// TODO: we can reconsider this approach in the future.
-	go app.waitForSpendConfirmation(*stakingTxHash, confEvent)
+	// Spend tx is already sent, so failing registration is retried in background
+	// instead of failing the request.
+	app.registerConfirmationOrRetry(*spendTxHash, *stakingTxHash, func() error {
+		sub := app.notifications.reserve(*spendTxHash, SpendTxConfirmationPurpose, *stakingTxHash, app.clock.Now())
 
-	return spendTxHash, &spendTxValue, nil
+		if sub == nil {
+			return nil
+		}
+
+		confEvent, err := app.notifier.RegisterConfirmationsNtfn(
+			spendTxHash,
+			spendTxPkScript,
+			SpendStakeTxConfirmations,
+			heightHint,
+			notifier.WithIncludeBlock(),
+		)
+
+		if err != nil {
+			app.notifications.remove(sub)
+			return err
+		}
+
+		if !app.notifications.attach(sub, confEvent.Cancel) {
+			return nil
+		}
+
+		app.wg.Go(spendConfGoroutine, func() {
+			app.waitForSpendConfirmation(*stakingTxHash, *spendTxHash, confEvent, sub)
+		})
+		return nil
+	})
 }
 
-func (app *StakerApp) ListActiveFinalityProviders(limit uint64, offset uint64) (*cl.FinalityProvidersClientResponse, error) {
-	return app.babylonClient.QueryFinalityProviders(limit, offset)
+// ListFinalityProviders returns page of finality providers registered on babylon.
+// Slashed finality providers are filtered out of the page unless includeSlashed is
+// set, so page can contain less finality providers than limit even if there are
+// more pages.
+func (app *StakerApp) ListFinalityProviders(
+	limit uint64,
+	offset uint64,
+	pageKey []byte,
+	includeSlashed bool,
+) (*cl.FinalityProvidersClientResponse, error) {
+	resp, err := app.babylonClient.QueryFinalityProviders(limit, offset, pageKey)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if includeSlashed {
+		return resp, nil
+	}
+
+	active := make([]cl.FinalityProviderInfo, 0, len(resp.FinalityProviders))
+	for _, fp := range resp.FinalityProviders {
+		if !fp.Slashed {
+			active = append(active, fp)
+		}
+	}
+	resp.FinalityProviders = active
+
+	return resp, nil
 }
 
 // Initiates whole unbonding process. Whole process looks like this:
@@ -1822,8 +3381,12 @@ func (app *StakerApp) ListActiveFinalityProviders(limit uint64, offset uint64) (
 // 5. After gathering all signatures, unbonding transaction is sent to bitcoin
 // This function returns control to the caller after step 3. Later is up to the caller
 // to check what is state of unbonding transaction
+// If autoSweep is true, unbonded funds are spent automatically once unbonding timelock
+// expires. If autoSweep is nil, value from config is used.
+// Fee rate is ignored, as unbonding transaction fee is fixed when delegation is
+// sent to babylon.
 func (app *StakerApp) UnbondStaking(
-	stakingTxHash chainhash.Hash, feeRate *btcutil.Amount) (*chainhash.Hash, error) {
+	stakingTxHash chainhash.Hash, feeRate *btcutil.Amount, autoSweep *bool) (*chainhash.Hash, error) {
 	// check we are not shutting down
 	select {
 	case <-app.quit:
@@ -1832,6 +3395,10 @@ func (app *StakerApp) UnbondStaking(
 	default:
 	}
 
+	if err := app.checkWalletEnabled(); err != nil {
+		return nil, err
+	}
+
 	// 1. Check staking tx is managed by staker program
 	tx, err := app.txTracker.GetTransaction(&stakingTxHash)
 
@@ -1840,28 +3407,56 @@ func (app *StakerApp) UnbondStaking(
 	}
 
 	// 2. Check tx is not watched and is in valid state
-	if tx.Watched {
-		return nil, fmt.Errorf("cannot unbond watched transaction")
+	if err := checkSignable(tx); err != nil {
+		return nil, fmt.Errorf("cannot unbond: %w", err)
+	}
+
+	if tx.Archived {
+		return nil, fmt.Errorf("cannot unbond: %w", stakerdb.ErrTransactionArchived)
 	}
 
 	if tx.State != proto.TransactionState_DELEGATION_ACTIVE {
 		return nil, fmt.Errorf("cannot unbond transaction which is not active")
 	}
 
+	if err := app.checkBroadcastsEnabled(); err != nil {
+		return nil, fmt.Errorf("cannot unbond: %w", err)
+	}
+
+	if err := app.checkWalletSynced(); err != nil {
+		return nil, fmt.Errorf("cannot unbond: %w", err)
+	}
+
 	stakerAddress, err := btcutil.DecodeAddress(tx.StakerAddress, app.network)
 
 	if err != nil {
 		return nil, fmt.Errorf("error decoding staker address: %s. Err: %v", tx.StakerAddress, err)
 	}
 
+	if feeRate != nil {
+		// unbonding tx was built and sent to babylon together with delegation,
+		// so requested fee rate cannot be applied anymore
+		app.logger.WithFields(logrus.Fields{
+			"stakingTxHash":    stakingTxHash,
+			"requestedFeeRate": *feeRate,
+		}).Warn("Ignoring fee rate requested for unbonding. Unbonding transaction fee was fixed when delegation was sent to babylon")
+	}
+
+	if app.autoSweepEnabled(autoSweep) {
+		if err := app.scheduleAutoSweep(&stakingTxHash, stakerAddress, tx); err != nil {
+			return nil, fmt.Errorf("cannot unbond: %w", err)
+		}
+	}
+
 	// TODO: Move this to event handler to avoid somebody starting multiple unbonding routines
-	app.wg.Add(1)
-	go app.sendUnbondingTxToBtcTask(
-		&stakingTxHash,
-		stakerAddress,
-		tx,
-		tx.UnbondingTxData,
-	)
+	app.wg.Go(sendUnbondingTxGoroutine, func() {
+		app.sendUnbondingTxToBtcTask(
+			&stakingTxHash,
+			stakerAddress,
+			tx,
+			tx.UnbondingTxData,
+		)
+	})
 
 	unbondingTxHash := tx.UnbondingTxData.UnbondingTx.TxHash()
 	return &unbondingTxHash, nil
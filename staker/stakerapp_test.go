@@ -0,0 +1,261 @@
+package staker
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/avast/retry-go/v4"
+	"github.com/babylonchain/btc-staker/proto"
+	scfg "github.com/babylonchain/btc-staker/stakercfg"
+	"github.com/babylonchain/btc-staker/stakerdb"
+	"github.com/babylonchain/btc-staker/utils"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	notifier "github.com/lightningnetwork/lnd/chainntnfs"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+var testClockStart = time.Unix(1700000000, 0)
+
+// advanceUntilDone advances fake clock by step each time tested code waits on it,
+// until done channel is closed.
+func advanceUntilDone(clock *utils.FakeClock, step time.Duration, done <-chan struct{}) {
+	for {
+		select {
+		case <-done:
+			return
+		default:
+		}
+
+		if clock.NumWaiters() > 0 {
+			clock.Advance(step)
+		} else {
+			runtime.Gosched()
+		}
+	}
+}
+
+func TestUnbondingSendRetry(t *testing.T) {
+	retryInterval := scfg.DefaultStakerConfig().UnbondingTxRetryInterval
+	errSend := errors.New("failed to send unbonding tx")
+
+	tests := []struct {
+		name            string
+		numFailures     uint
+		cancelCtx       bool
+		expectErr       error
+		expectAttempts  uint
+		expectTimeSpent time.Duration
+	}{
+		{
+			name:            "success at first attempt",
+			numFailures:     0,
+			expectAttempts:  1,
+			expectTimeSpent: 0,
+		},
+		{
+			name:            "success after few failures",
+			numFailures:     5,
+			expectAttempts:  6,
+			expectTimeSpent: 5 * retryInterval,
+		},
+		{
+			name:            "failure after all attempts",
+			numFailures:     longRetryNum,
+			expectErr:       errSend,
+			expectAttempts:  longRetryNum,
+			expectTimeSpent: time.Duration(longRetryNum-1) * retryInterval,
+		},
+		{
+			name:            "cancelled context",
+			numFailures:     longRetryNum,
+			cancelCtx:       true,
+			expectErr:       context.Canceled,
+			expectAttempts:  0,
+			expectTimeSpent: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock := utils.NewFakeClock(testClockStart)
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			if tt.cancelCtx {
+				cancel()
+			}
+
+			var attempts uint
+			var retryErr error
+			done := make(chan struct{})
+
+			go func() {
+				defer close(done)
+				retryErr = retry.Do(func() error {
+					attempts++
+					if attempts <= tt.numFailures {
+						return errSend
+					}
+					return nil
+				}, longRetryOps(ctx, clock, retryInterval, func(n uint, err error) {})...)
+			}()
+
+			advanceUntilDone(clock, retryInterval, done)
+
+			if tt.expectErr != nil {
+				require.ErrorIs(t, retryErr, tt.expectErr)
+			} else {
+				require.NoError(t, retryErr)
+			}
+			require.Equal(t, tt.expectAttempts, attempts)
+			require.Equal(t, tt.expectTimeSpent, clock.Now().Sub(testClockStart))
+		})
+	}
+}
+
+func TestSpendConfirmationTimeout(t *testing.T) {
+	timeout := scfg.DefaultStakerConfig().SpendTxConfTimeout
+
+	tests := []struct {
+		name          string
+		confirmAfter  time.Duration
+		quit          bool
+		cancel        bool
+		expectConfirm bool
+	}{
+		{
+			name:          "confirmed before timeout",
+			confirmAfter:  timeout - time.Second,
+			expectConfirm: true,
+		},
+		{
+			name:          "timed out before confirmation",
+			confirmAfter:  timeout,
+			expectConfirm: false,
+		},
+		{
+			name:          "app quit",
+			quit:          true,
+			expectConfirm: false,
+		},
+		{
+			name:          "stake spent by other transaction",
+			cancel:        true,
+			expectConfirm: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock := utils.NewFakeClock(testClockStart)
+			cfg := scfg.DefaultConfig()
+
+			app := &StakerApp{
+				clock:                            clock,
+				config:                           &cfg,
+				logger:                           logrus.New(),
+				quit:                             make(chan struct{}),
+				spendStakeTxConfirmedOnBtcEvChan: make(chan *spendStakeTxConfirmedOnBtcEvent, 1),
+				confProgress:                     newConfirmationProgressTracker(),
+				notifications:                    newNotificationRegistry(),
+			}
+
+			txHash := chainhash.HashH([]byte("staking tx"))
+			spendTxHash := chainhash.HashH([]byte("spend tx"))
+			ev := notifier.NewConfirmationEvent(SpendStakeTxConfirmations, func() {})
+			sub := app.notifications.reserve(spendTxHash, SpendTxConfirmationPurpose, txHash, testClockStart)
+			require.True(t, app.notifications.attach(sub, ev.Cancel))
+			done := make(chan struct{})
+
+			go func() {
+				defer close(done)
+				app.waitForSpendConfirmation(txHash, spendTxHash, ev, sub)
+			}()
+
+			if tt.quit {
+				close(app.quit)
+			} else if tt.cancel {
+				app.cancelNotifications(&txHash)
+			} else {
+				require.Eventually(t, func() bool {
+					return clock.NumWaiters() == 1
+				}, time.Second, time.Millisecond)
+
+				clock.Advance(tt.confirmAfter)
+
+				if tt.expectConfirm {
+					ev.Confirmed <- &notifier.TxConfirmation{}
+				}
+			}
+
+			<-done
+
+			select {
+			case confirmed := <-app.spendStakeTxConfirmedOnBtcEvChan:
+				require.True(t, tt.expectConfirm)
+				require.Equal(t, txHash, confirmed.stakingTxHash)
+			default:
+				require.False(t, tt.expectConfirm)
+			}
+
+			// subscription is released however waiting ends
+			require.Empty(t, app.notifications.list())
+		})
+	}
+}
+
+func TestStaleEventOfFinalTransactionIsIgnored(t *testing.T) {
+	tests := []struct {
+		name    string
+		toFinal func(d *testStakerDeps, txHash *chainhash.Hash) error
+		state   proto.TransactionState
+	}{
+		{
+			name: "conflicted",
+			toFinal: func(d *testStakerDeps, txHash *chainhash.Hash) error {
+				return d.tracker.SetTxConflicted(txHash, &chainhash.Hash{1})
+			},
+			state: proto.TransactionState_CONFLICTED,
+		},
+		{
+			name: "delegation cancelled",
+			toFinal: func(d *testStakerDeps, txHash *chainhash.Hash) error {
+				if err := d.tracker.SetTxConfirmed(txHash, &chainhash.Hash{}, 10, testClockStart); err != nil {
+					return err
+				}
+
+				return d.tracker.SetTxDelegationCancelled(txHash)
+			},
+			state: proto.TransactionState_DELEGATION_CANCELLED,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			deps := newTestStakerDeps(t)
+			txHash := deps.addSentToBtcTransaction(t)
+			require.NoError(t, tc.toFinal(deps, txHash))
+
+			app := deps.newApp(t)
+
+			// confirmation delivered after transaction reached final state
+			ev := &stakingTxBtcConfirmedEvent{
+				stakingTxHash: *txHash,
+				blockHeight:   20,
+			}
+			err := app.txTracker.SetTxConfirmed(txHash, &ev.blockHash, ev.blockHeight, testClockStart)
+			require.ErrorIs(t, err, stakerdb.ErrInvalidTransactionState)
+
+			// must neither stop the app nor quarantine the transaction
+			app.handleStateTransitionError(ev, txHash, err)
+
+			storedTx, err := deps.tracker.GetTransaction(txHash)
+			require.NoError(t, err)
+			require.Equal(t, tc.state, storedTx.State)
+		})
+	}
+}
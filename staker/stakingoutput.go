@@ -0,0 +1,275 @@
+package staker
+
+import (
+	"errors"
+	"fmt"
+
+	cl "github.com/babylonchain/btc-staker/babylonclient"
+	"github.com/babylonchain/btc-staker/proto"
+	"github.com/babylonchain/btc-staker/stakerdb"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/sirupsen/logrus"
+)
+
+// ErrTransactionCorrupted stored record does not match staking transaction it
+// describes. Staking output of such transaction is not spent until the record is
+// repaired, as witnesses built from the record would be invalid.
+var ErrTransactionCorrupted = errors.New("stored transaction is flagged as corrupted")
+
+type covenantCommittee struct {
+	pks    []*btcec.PublicKey
+	quorum uint32
+}
+
+// findStakingOutput returns index of output of staking transaction which matches
+// staking script built from the stored record with any of given committees
+func findStakingOutput(
+	storedTx *stakerdb.StoredTransaction,
+	stakerPubKey *btcec.PublicKey,
+	committees []covenantCommittee,
+	net *chaincfg.Params,
+) (uint32, bool) {
+	for idx := range storedTx.StakingTx.TxOut {
+		for _, committee := range committees {
+			if outputCommitsTo(storedTx, uint32(idx), stakerPubKey, committee.pks, committee.quorum, net) {
+				return uint32(idx), true
+			}
+		}
+	}
+
+	return 0, false
+}
+
+// markTransactionCorrupted flags stored transaction as corrupted and returns
+// error refusing the operation which found the corruption. Transaction stays
+// tracked, so that its state is still followed and visible to the operator.
+func (app *StakerApp) markTransactionCorrupted(stakingTxHash *chainhash.Hash, reason string) error {
+	logger := app.logger.WithFields(logrus.Fields{
+		"stakingTxHash": stakingTxHash,
+		"reason":        reason,
+	})
+
+	if err := app.txTracker.SetTxCorrupted(stakingTxHash, reason); err != nil {
+		logger.WithField("err", err).Error("Failed to flag stored transaction as corrupted")
+	} else {
+		logger.Error("Stored transaction does not match its staking transaction. Flagged it as corrupted, its staking output will not be spent until it is repaired")
+	}
+
+	return fmt.Errorf("%w: %s", ErrTransactionCorrupted, reason)
+}
+
+// checkStakingTxRecord refuses records already flagged as corrupted and flags
+// records whose staking transaction does not have the hash they are stored under
+func (app *StakerApp) checkStakingTxRecord(
+	stakingTxHash *chainhash.Hash,
+	storedTx *stakerdb.StoredTransaction,
+) error {
+	if storedTx.CorruptionReason != "" {
+		return fmt.Errorf("%w: %s", ErrTransactionCorrupted, storedTx.CorruptionReason)
+	}
+
+	if txHash := storedTx.StakingTx.TxHash(); !txHash.IsEqual(stakingTxHash) {
+		return app.markTransactionCorrupted(
+			stakingTxHash,
+			fmt.Sprintf("stored staking transaction has hash %s", txHash),
+		)
+	}
+
+	return nil
+}
+
+// reportStakingOutputMismatch flags transaction whose stored staking output does
+// not match staking script built from the record with any of given committees.
+// If other output of staking transaction matches, the record points at wrong
+// output, which can be repaired.
+func (app *StakerApp) reportStakingOutputMismatch(
+	stakingTxHash *chainhash.Hash,
+	storedTx *stakerdb.StoredTransaction,
+	stakerPubKey *btcec.PublicKey,
+	committees []covenantCommittee,
+) error {
+	if idx, found := findStakingOutput(storedTx, stakerPubKey, committees, app.network); found {
+		return app.markTransactionCorrupted(
+			stakingTxHash,
+			fmt.Sprintf(
+				"staking output index %d does not match staking script, output %d does. Repair the record with repair-staking-output",
+				storedTx.StakingOutputIndex,
+				idx,
+			),
+		)
+	}
+
+	return app.markTransactionCorrupted(
+		stakingTxHash,
+		fmt.Sprintf("no output of staking transaction matches staking script, staking output index is %d", storedTx.StakingOutputIndex),
+	)
+}
+
+// RepairStakingOutputIndex repairs record of transaction which points at wrong
+// output of staking transaction, by pointing it at the output matching its
+// staking script. It returns index of the matching output.
+func (app *StakerApp) RepairStakingOutputIndex(stakingTxHash *chainhash.Hash) (uint32, error) {
+	storedTx, err := app.txTracker.GetTransaction(stakingTxHash)
+
+	if err != nil {
+		return 0, err
+	}
+
+	if txHash := storedTx.StakingTx.TxHash(); !txHash.IsEqual(stakingTxHash) {
+		return 0, fmt.Errorf("stored staking transaction has hash %s, record can't be repaired", txHash)
+	}
+
+	stakerPubKey, err := app.stakingTxStakerPubKey(storedTx)
+
+	if err != nil {
+		return 0, fmt.Errorf("failed to retrieve staker key: %w", err)
+	}
+
+	params, err := app.babylonClient.Params()
+
+	if err != nil {
+		return 0, err
+	}
+
+	committees := []covenantCommittee{currentCommittee(params)}
+
+	if storedTx.ParamsVersion > 0 {
+		snapshot, err := app.txTracker.GetStakingParams(storedTx.ParamsVersion)
+
+		if err != nil {
+			return 0, fmt.Errorf("failed to retrieve staking params version %d: %w", storedTx.ParamsVersion, err)
+		}
+
+		committees = append(committees, covenantCommittee{pks: snapshot.CovenantPks, quorum: snapshot.CovenantQuorum})
+	}
+
+	idx, found := findStakingOutput(storedTx, stakerPubKey, committees, app.network)
+
+	if !found {
+		return 0, fmt.Errorf("no output of staking transaction %s matches staking script", stakingTxHash)
+	}
+
+	if err := app.txTracker.RepairStakingOutputIndex(stakingTxHash, idx); err != nil {
+		return 0, err
+	}
+
+	app.logger.WithFields(logrus.Fields{
+		"stakingTxHash":       stakingTxHash,
+		"previousOutputIndex": storedTx.StakingOutputIndex,
+		"outputIndex":         idx,
+	}).Info("Repaired staking output index of stored transaction")
+
+	return idx, nil
+}
+
+// babylonUnbondingOutputIdx returns index of unbonding output of unbonding
+// transaction received from babylon. Output must match unbonding script built
+// from the stored record, as it is later spent using witnesses built from it.
+func (app *StakerApp) babylonUnbondingOutputIdx(
+	stakingTxHash *chainhash.Hash,
+	storedTx *stakerdb.StoredTransaction,
+	unbondingTx *wire.MsgTx,
+	unbondingTime uint16,
+	params *cl.StakingParams,
+) (uint32, error) {
+	stakerPubKey, err := app.stakingTxStakerPubKey(storedTx)
+
+	if err != nil {
+		return 0, fmt.Errorf("failed to retrieve staker key: %w", err)
+	}
+
+	covenantPks, covenantQuorum, err := app.stakingTxCovenantCommittee(stakingTxHash, storedTx, stakerPubKey, params)
+
+	if err != nil {
+		return 0, err
+	}
+
+	return findUnbondingOutput(
+		unbondingTx,
+		stakerPubKey,
+		storedTx.FinalityProvidersBtcPks,
+		covenantPks,
+		covenantQuorum,
+		unbondingTime,
+		app.network,
+	)
+}
+
+// stakingOutputUnspent returns true if staking output of transaction in given
+// state can still be spent by staker
+func stakingOutputUnspent(state proto.TransactionState) bool {
+	switch state {
+	case proto.TransactionState_UNBONDING_CONFIRMED_ON_BTC,
+		proto.TransactionState_SPENT_ON_BTC,
+		proto.TransactionState_MISSING_ON_BTC,
+		proto.TransactionState_CONFLICTED:
+		return false
+	default:
+		return true
+	}
+}
+
+// validateStakingOutputs checks on startup that every tracked transaction with
+// unspent staking output points at output matching its staking script, so that
+// corrupted records are flagged before any operation tries to spend them.
+// Transactions whose staker key is not available are skipped, they are checked
+// before their staking output is spent.
+func (app *StakerApp) validateStakingOutputs() {
+	var toValidate []stakerdb.StoredTransaction
+
+	err := app.txTracker.ScanTrackedTransactions(func(tx *stakerdb.StoredTransaction) error {
+		if tx.CorruptionReason == "" && stakingOutputUnspent(tx.State) {
+			toValidate = append(toValidate, *tx)
+		}
+		return nil
+	}, func() {
+		toValidate = nil
+	})
+
+	if err != nil {
+		app.logger.WithField("err", err).Error("Failed to scan tracked transactions to validate staking outputs")
+		return
+	}
+
+	if len(toValidate) == 0 {
+		return
+	}
+
+	params, err := app.babylonClient.Params()
+
+	if err != nil {
+		app.logger.WithField("err", err).Warn("Failed to retrieve babylon params. Staking outputs are validated before they are spent")
+		return
+	}
+
+	for i := range toValidate {
+		storedTx := &toValidate[i]
+		stakingTxHash := storedTx.StakingTx.TxHash()
+
+		stakerPubKey, err := app.stakingTxStakerPubKey(storedTx)
+
+		if err != nil {
+			app.logger.WithFields(logrus.Fields{
+				"stakingTxHash": stakingTxHash,
+				"err":           err,
+			}).Debug("Staker key not available, skipping validation of staking output")
+			continue
+		}
+
+		if _, _, err := app.stakingTxCovenantCommittee(&stakingTxHash, storedTx, stakerPubKey, params); err != nil &&
+			!errors.Is(err, ErrTransactionCorrupted) {
+			app.logger.WithFields(logrus.Fields{
+				"stakingTxHash": stakingTxHash,
+				"err":           err,
+			}).Warn("Failed to validate staking output of stored transaction")
+		}
+	}
+}
+
+// currentCommittee returns covenant committee from current babylon params
+func currentCommittee(params *cl.StakingParams) covenantCommittee {
+	return covenantCommittee{pks: params.CovenantPks, quorum: params.CovenantQuruomThreshold}
+}
@@ -0,0 +1,72 @@
+package staker
+
+import (
+	"testing"
+
+	staking "github.com/babylonchain/babylon/btcstaking"
+	"github.com/babylonchain/btc-staker/stakerdb"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindStakingOutput(t *testing.T) {
+	net := &chaincfg.SimNetParams
+	stakerPk := genPubKey(t)
+	fpPks := []*btcec.PublicKey{genPubKey(t)}
+	current := covenantCommittee{pks: []*btcec.PublicKey{genPubKey(t), genPubKey(t)}, quorum: 1}
+	previous := covenantCommittee{pks: []*btcec.PublicKey{genPubKey(t), genPubKey(t)}, quorum: 2}
+
+	stakingInfo, err := staking.BuildStakingInfo(stakerPk, fpPks, previous.pks, previous.quorum, 100, 100000, net)
+	require.NoError(t, err)
+
+	// staking output after change output, while record points at change output
+	stakingTx := wire.NewMsgTx(2)
+	stakingTx.AddTxOut(wire.NewTxOut(5000, []byte{0x51}))
+	stakingTx.AddTxOut(stakingInfo.StakingOutput)
+
+	storedTx := &stakerdb.StoredTransaction{
+		StakingTx:               stakingTx,
+		StakingOutputIndex:      0,
+		StakingTime:             100,
+		FinalityProvidersBtcPks: fpPks,
+	}
+
+	require.False(t, stakingOutputCommitsTo(storedTx, stakerPk, previous.pks, previous.quorum, net))
+
+	_, found := findStakingOutput(storedTx, stakerPk, []covenantCommittee{current}, net)
+	require.False(t, found)
+
+	idx, found := findStakingOutput(storedTx, stakerPk, []covenantCommittee{current, previous}, net)
+	require.True(t, found)
+	require.Equal(t, uint32(1), idx)
+
+	_, found = findStakingOutput(storedTx, genPubKey(t), []covenantCommittee{current, previous}, net)
+	require.False(t, found)
+}
+
+func TestFindUnbondingOutput(t *testing.T) {
+	net := &chaincfg.SimNetParams
+	stakerPk := genPubKey(t)
+	fpPks := []*btcec.PublicKey{genPubKey(t)}
+	covenantPks := []*btcec.PublicKey{genPubKey(t), genPubKey(t)}
+
+	unbondingInfo, err := staking.BuildUnbondingInfo(stakerPk, fpPks, covenantPks, 1, 50, 90000, net)
+	require.NoError(t, err)
+
+	// unbonding output after zero value anchor output
+	unbondingTx := wire.NewMsgTx(2)
+	unbondingTx.AddTxOut(wire.NewTxOut(0, []byte{0x51}))
+	unbondingTx.AddTxOut(unbondingInfo.UnbondingOutput)
+
+	idx, err := findUnbondingOutput(unbondingTx, stakerPk, fpPks, covenantPks, 1, 50, net)
+	require.NoError(t, err)
+	require.Equal(t, uint32(1), idx)
+
+	_, err = findUnbondingOutput(unbondingTx, stakerPk, fpPks, covenantPks, 1, 51, net)
+	require.Error(t, err)
+
+	_, err = findUnbondingOutput(unbondingTx, genPubKey(t), fpPks, covenantPks, 1, 50, net)
+	require.Error(t, err)
+}
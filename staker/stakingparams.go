@@ -0,0 +1,67 @@
+package staker
+
+import (
+	cl "github.com/babylonchain/btc-staker/babylonclient"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcwallet/wallet/txrules"
+	"github.com/btcsuite/btcwallet/wallet/txsizes"
+)
+
+const (
+	// OP_1 OP_DATA_32 <32 byte key>
+	p2trScriptSize = 34
+	// OP_0 OP_DATA_20 <20 byte hash>
+	p2wpkhScriptSize = 22
+)
+
+type StakingParamsInfo struct {
+	Params         *cl.StakingParams
+	MinStakingTime uint32
+	// current fee rate used by staker for new transactions
+	FeeRate btcutil.Amount
+	// estimated fee of staking transaction funded by single wallet output
+	EstimatedStakingTxFee btcutil.Amount
+	ActiveDelegations     uint64
+	MaxActiveDelegations  uint32
+	// soft limit of staking time, 0 if disabled
+	MaxStakingTimeBlocks uint32
+}
+
+// estimateStakingTxFee estimates fee of staking transaction with one p2wpkh input,
+// staking output and p2wpkh change output. Actual fee depends on outputs selected
+// by the wallet.
+func estimateStakingTxFee(feeRate btcutil.Amount) btcutil.Amount {
+	// staking output is always p2tr output
+	stakingOutput := wire.NewTxOut(0, make([]byte, p2trScriptSize))
+	txSize := txsizes.EstimateVirtualSize(0, 0, 1, 0, []*wire.TxOut{stakingOutput}, p2wpkhScriptSize)
+	return txrules.FeeForSerializeSize(feeRate, txSize)
+}
+
+// StakingParams returns current babylon staking params, together with values
+// useful to validate new staking request
+func (app *StakerApp) StakingParams() (*StakingParamsInfo, error) {
+	params, err := app.babylonClient.Params()
+
+	if err != nil {
+		return nil, err
+	}
+
+	feeRate := app.estimateFee(app.config.StakerConfig.StakingTxConfTarget).FeeRate()
+
+	activeDelegations, err := app.ActiveDelegations()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &StakingParamsInfo{
+		Params:                params,
+		MinStakingTime:        GetMinStakingTime(params),
+		FeeRate:               feeRate,
+		EstimatedStakingTxFee: estimateStakingTxFee(feeRate),
+		ActiveDelegations:     activeDelegations,
+		MaxActiveDelegations:  app.config.StakerConfig.MaxActiveDelegations,
+		MaxStakingTimeBlocks:  app.config.StakerConfig.MaxStakingTimeBlocks,
+	}, nil
+}
@@ -0,0 +1,241 @@
+package staker
+
+import (
+	"container/list"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/babylonchain/btc-staker/proto"
+	"github.com/babylonchain/btc-staker/stakerdb"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/sirupsen/logrus"
+)
+
+const stakingRequestIdSize = 16
+
+// ErrStakingRequestInterrupted staker was shut down before asynchronous staking
+// request finished. Staking transaction may still have been sent, so stored
+// transactions must be checked before staking again.
+var ErrStakingRequestInterrupted = errors.New("staking request interrupted by shutdown, staking transaction may have been sent")
+
+// stakingRequestCache is bounded LRU cache of asynchronous staking requests.
+// Pending requests are only kept in memory, so they are lost on crash or when
+// evicted from the cache. Requests interrupted by shutdown are recorded as failed.
+type stakingRequestCache struct {
+	mu         sync.Mutex
+	maxEntries int
+	// most recently used requests are at the front
+	order   *list.List
+	entries map[string]*list.Element
+}
+
+func newStakingRequestCache(maxEntries int) *stakingRequestCache {
+	return &stakingRequestCache{
+		maxEntries: maxEntries,
+		order:      list.New(),
+		entries:    make(map[string]*list.Element),
+	}
+}
+
+func (c *stakingRequestCache) put(r *stakerdb.StakingRequestRecord) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.entries[r.RequestId]; ok {
+		el.Value = r
+		c.order.MoveToFront(el)
+		return
+	}
+
+	c.entries[r.RequestId] = c.order.PushFront(r)
+
+	for c.order.Len() > c.maxEntries {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*stakerdb.StakingRequestRecord).RequestId)
+	}
+}
+
+func (c *stakingRequestCache) get(requestId string) (*stakerdb.StakingRequestRecord, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.entries[requestId]
+
+	if !ok {
+		return nil, false
+	}
+
+	c.order.MoveToFront(el)
+
+	return el.Value.(*stakerdb.StakingRequestRecord), true
+}
+
+func newStakingRequestId() (string, error) {
+	id := make([]byte, stakingRequestIdSize)
+
+	if _, err := rand.Read(id); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(id), nil
+}
+
+func (app *StakerApp) pruneStakingRequests() {
+	numRemoved, err := app.txTracker.PruneStakingRequests(
+		app.clock.Now().Add(-app.config.StakerConfig.StakingRequestRetention),
+	)
+
+	if err != nil {
+		app.logger.WithFields(logrus.Fields{
+			"err": err,
+		}).Error("Failed to prune old staking requests")
+		return
+	}
+
+	if numRemoved > 0 {
+		app.logger.WithFields(logrus.Fields{
+			"numRemoved": numRemoved,
+		}).Debug("Pruned old staking requests")
+	}
+}
+
+// finishStakingRequest records result of StakeFunds call made for asynchronous
+// staking request
+func (app *StakerApp) finishStakingRequest(requestId string, stakingTxHash *chainhash.Hash, err error) {
+	if err == nil && stakingTxHash == nil {
+		// app is shutting down, request must not be left pending forever
+		err = ErrStakingRequestInterrupted
+	}
+
+	record := &stakerdb.StakingRequestRecord{
+		RequestId: requestId,
+		UpdatedAt: app.clock.Now(),
+	}
+
+	if err != nil {
+		record.Status = proto.StakingRequestStatus_STAKING_REQUEST_FAILED
+		record.Error = err.Error()
+	} else {
+		record.Status = proto.StakingRequestStatus_STAKING_REQUEST_BROADCAST
+		record.StakingTxHash = stakingTxHash
+	}
+
+	app.logger.WithFields(logrus.Fields{
+		"requestId": requestId,
+		"status":    record.Status,
+	}).Debug("Asynchronous staking request finished")
+
+	app.stakingRequests.put(record)
+
+	if err := app.txTracker.SaveStakingRequest(record); err != nil {
+		// result is still available in memory, so only log the error
+		app.logger.WithFields(logrus.Fields{
+			"requestId": record.RequestId,
+			"err":       err,
+		}).Error("Failed to persist staking request result")
+	}
+
+	app.pruneStakingRequests()
+}
+
+// StakeFundsAsync starts staking in the background and returns immediately with
+// request id, which can be used to poll for the result through StakingRequestStatus.
+func (app *StakerApp) StakeFundsAsync(
+	stakerAddress btcutil.Address,
+	stakingAmount btcutil.Amount,
+	fpPks []*btcec.PublicKey,
+	stakingTimeBlocks uint16,
+	confTarget *uint32,
+	memo string,
+	label string,
+	requiredDepthOverride *uint32,
+	acknowledgeLongLock bool,
+	autoRenew bool,
+	freshPop bool,
+) (string, error) {
+	// check we are not shutting down
+	select {
+	case <-app.quit:
+		return "", fmt.Errorf("staker app is shutting down")
+
+	default:
+	}
+
+	if err := app.checkWalletEnabled(); err != nil {
+		return "", err
+	}
+
+	if err := app.checkBroadcastsEnabled(); err != nil {
+		return "", err
+	}
+
+	if err := app.checkStakedValueCap(stakingAmount); err != nil {
+		return "", err
+	}
+
+	if err := app.checkWalletSynced(); err != nil {
+		return "", err
+	}
+
+	if err := app.checkStakingTimeLimit(stakingTimeBlocks, acknowledgeLongLock); err != nil {
+		return "", err
+	}
+
+	requestId, err := newStakingRequestId()
+
+	if err != nil {
+		return "", err
+	}
+
+	app.stakingRequests.put(&stakerdb.StakingRequestRecord{
+		RequestId: requestId,
+		Status:    proto.StakingRequestStatus_STAKING_REQUEST_PENDING,
+		UpdatedAt: app.clock.Now(),
+	})
+
+	app.wg.Go(stakingRequestGoroutine, func() {
+		stakingTxHash, err := app.StakeFunds(
+			stakerAddress,
+			stakingAmount,
+			fpPks,
+			stakingTimeBlocks,
+			confTarget,
+			memo,
+			label,
+			requiredDepthOverride,
+			acknowledgeLongLock,
+			autoRenew,
+			freshPop,
+		)
+
+		app.finishStakingRequest(requestId, stakingTxHash, err)
+	})
+
+	return requestId, nil
+}
+
+// StakingRequestStatus returns status of asynchronous staking request. Recent
+// requests are served from memory, finished requests are also retrieved from
+// database until their retention period passes.
+func (app *StakerApp) StakingRequestStatus(requestId string) (*stakerdb.StakingRequestRecord, error) {
+	if record, ok := app.stakingRequests.get(requestId); ok {
+		return record, nil
+	}
+
+	record, err := app.txTracker.GetStakingRequest(requestId)
+
+	if err != nil {
+		if errors.Is(err, stakerdb.ErrStakingRequestNotFound) {
+			return nil, fmt.Errorf("unknown staking request %s: %w", requestId, err)
+		}
+		return nil, err
+	}
+
+	return record, nil
+}
@@ -0,0 +1,91 @@
+package staker
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/babylonchain/btc-staker/proto"
+	"github.com/babylonchain/btc-staker/stakerdb"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStakingRequestCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	cache := newStakingRequestCache(2)
+
+	cache.put(&stakerdb.StakingRequestRecord{RequestId: "a"})
+	cache.put(&stakerdb.StakingRequestRecord{RequestId: "b"})
+
+	_, ok := cache.get("a")
+	require.True(t, ok)
+
+	cache.put(&stakerdb.StakingRequestRecord{RequestId: "c"})
+
+	_, ok = cache.get("b")
+	require.False(t, ok)
+
+	for _, requestId := range []string{"a", "c"} {
+		_, ok = cache.get(requestId)
+		require.True(t, ok)
+	}
+}
+
+func TestFinishedStakingRequestStatus(t *testing.T) {
+	stakingTxHash := chainhash.HashH([]byte("staking"))
+
+	tests := []struct {
+		name          string
+		stakingTxHash *chainhash.Hash
+		err           error
+		status        proto.StakingRequestStatus
+		errMsg        string
+	}{
+		{
+			name:          "staking transaction broadcast",
+			stakingTxHash: &stakingTxHash,
+			status:        proto.StakingRequestStatus_STAKING_REQUEST_BROADCAST,
+		},
+		{
+			name:   "staking failed",
+			err:    errors.New("not enough funds"),
+			status: proto.StakingRequestStatus_STAKING_REQUEST_FAILED,
+			errMsg: "not enough funds",
+		},
+		{
+			name:   "staking interrupted by shutdown",
+			status: proto.StakingRequestStatus_STAKING_REQUEST_FAILED,
+			errMsg: ErrStakingRequestInterrupted.Error(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deps := newTestStakerDeps(t)
+			app := deps.newApp(t)
+
+			const requestId = "request"
+			app.stakingRequests.put(&stakerdb.StakingRequestRecord{
+				RequestId: requestId,
+				Status:    proto.StakingRequestStatus_STAKING_REQUEST_PENDING,
+			})
+
+			app.finishStakingRequest(requestId, tt.stakingTxHash, tt.err)
+
+			// result is served from memory, and from database after restart
+			for _, app := range []*StakerApp{app, deps.newApp(t)} {
+				record, err := app.StakingRequestStatus(requestId)
+				require.NoError(t, err)
+				require.Equal(t, tt.status, record.Status)
+				require.Equal(t, tt.stakingTxHash, record.StakingTxHash)
+				require.Equal(t, tt.errMsg, record.Error)
+			}
+		})
+	}
+}
+
+func TestUnknownStakingRequestStatus(t *testing.T) {
+	app := newTestStakerDeps(t).newApp(t)
+
+	_, err := app.StakingRequestStatus("unknown")
+	require.ErrorIs(t, err, stakerdb.ErrStakingRequestNotFound)
+}
@@ -0,0 +1,140 @@
+package staker
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/bits"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrStakingTimeAboveLimit is returned when staking time exceeds configured
+// maximum staking time and request does not acknowledge long lock of funds
+var ErrStakingTimeAboveLimit = errors.New("staking time exceeds maximum staking time, acknowledge long lock of funds to stake anyway")
+
+var stakingTimeUnits = map[string]time.Duration{
+	"d": 24 * time.Hour,
+	"w": 7 * 24 * time.Hour,
+}
+
+// StakingTimeToBlocks parses staking time given either as number of blocks, or as
+// duration with unit e.g 30d, 8w or 36h. Duration is converted to number of
+// blocks using expected number of blocks per hour. Partial blocks are rounded up,
+// so that stake is locked for at least requested duration.
+func StakingTimeToBlocks(stakingTime string, blocksPerHour uint32) (uint64, error) {
+	stakingTime = strings.TrimSpace(stakingTime)
+
+	if blocks, err := strconv.ParseUint(stakingTime, 10, 64); err == nil {
+		return blocks, nil
+	}
+
+	if blocksPerHour == 0 {
+		return 0, fmt.Errorf("expected number of blocks per hour must be positive")
+	}
+
+	duration, err := parseStakingDuration(stakingTime)
+
+	if err != nil {
+		return 0, fmt.Errorf("invalid staking time %q, expected number of blocks or duration e.g 30d or 8w: %w", stakingTime, err)
+	}
+
+	if duration <= 0 {
+		return 0, fmt.Errorf("staking time must be positive")
+	}
+
+	// every hour has at least one block, so longer duration can never be valid
+	if duration > math.MaxUint16*time.Hour {
+		return 0, fmt.Errorf("staking time %s exceeds maximum staking time %d blocks", stakingTime, math.MaxUint16)
+	}
+
+	// ceil(duration * blocksPerHour / hour), product may overflow uint64
+	hi, lo := bits.Mul64(uint64(duration), uint64(blocksPerHour))
+	lo, carry := bits.Add64(lo, uint64(time.Hour)-1, 0)
+	blocks, _ := bits.Div64(hi+carry, lo, uint64(time.Hour))
+
+	return blocks, nil
+}
+
+func parseStakingDuration(s string) (time.Duration, error) {
+	for suffix, unit := range stakingTimeUnits {
+		if !strings.HasSuffix(s, suffix) {
+			continue
+		}
+
+		n, err := strconv.ParseUint(strings.TrimSuffix(s, suffix), 10, 32)
+
+		if err != nil {
+			return 0, err
+		}
+
+		if n > uint64(math.MaxInt64/unit) {
+			return 0, fmt.Errorf("duration too long")
+		}
+
+		return time.Duration(n) * unit, nil
+	}
+
+	return time.ParseDuration(s)
+}
+
+// ValidateStakingTime checks that staking time in blocks is not lower than
+// minimum staking time, and fits into staking script time lock
+func ValidateStakingTime(stakingTimeBlocks uint64, minStakingTime uint32) (uint16, error) {
+	if stakingTimeBlocks == 0 {
+		return 0, fmt.Errorf("staking time must be positive")
+	}
+
+	if stakingTimeBlocks < uint64(minStakingTime) {
+		return 0, fmt.Errorf("staking time %d is less than minimum staking time %d",
+			stakingTimeBlocks, minStakingTime)
+	}
+
+	if stakingTimeBlocks > math.MaxUint16 {
+		return 0, fmt.Errorf("staking time %d exceeds maximum staking time %d",
+			stakingTimeBlocks, math.MaxUint16)
+	}
+
+	return uint16(stakingTimeBlocks), nil
+}
+
+// ApproximateStakingEndTime estimates when staking time lock expires, assuming
+// staking transaction is included in the next block
+func (app *StakerApp) ApproximateStakingEndTime(stakingTimeBlocks uint16) time.Time {
+	return app.clock.Now().Add(app.blocksDuration(uint32(stakingTimeBlocks)))
+}
+
+// ExceedsStakingTimeLimit returns true if staking time is above soft limit of
+// staking time. Zero limit disables the check.
+func ExceedsStakingTimeLimit(stakingTimeBlocks uint64, maxStakingTimeBlocks uint32) bool {
+	return maxStakingTimeBlocks > 0 && stakingTimeBlocks > uint64(maxStakingTimeBlocks)
+}
+
+// BlocksToDuration estimates how long it takes to produce given number of blocks
+func BlocksToDuration(blocks uint64, blocksPerHour uint32) time.Duration {
+	return time.Duration(blocks) * time.Hour / time.Duration(blocksPerHour)
+}
+
+// LockDays formats approximate duration of lock of funds in days
+func LockDays(d time.Duration) string {
+	return fmt.Sprintf("~%d days", int64(math.Round(d.Hours()/24)))
+}
+
+// checkStakingTimeLimit rejects staking time above configured maximum staking
+// time, unless long lock of funds was acknowledged by the request
+func (app *StakerApp) checkStakingTimeLimit(stakingTimeBlocks uint16, acknowledgeLongLock bool) error {
+	maxStakingTime := app.config.StakerConfig.MaxStakingTimeBlocks
+
+	if !ExceedsStakingTimeLimit(uint64(stakingTimeBlocks), maxStakingTime) || acknowledgeLongLock {
+		return nil
+	}
+
+	return fmt.Errorf(
+		"%w: staking time %d blocks locks funds for %s, limit is %d blocks",
+		ErrStakingTimeAboveLimit,
+		stakingTimeBlocks,
+		LockDays(app.blocksDuration(uint32(stakingTimeBlocks))),
+		maxStakingTime,
+	)
+}
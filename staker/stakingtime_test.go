@@ -0,0 +1,104 @@
+package staker
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStakingTimeToBlocks(t *testing.T) {
+	tests := []struct {
+		name          string
+		stakingTime   string
+		blocksPerHour uint32
+		expected      uint64
+		expectErr     bool
+	}{
+		{"blocks", "1000", 6, 1000, false},
+		{"blocks do not depend on block rate", "1000", 0, 1000, false},
+		{"days", "30d", 6, 4320, false},
+		{"weeks", "8w", 6, 8064, false},
+		{"hours", "36h", 6, 216, false},
+		{"partial block is rounded up", "25m", 6, 3, false},
+		{"exact block is not rounded up", "20m", 6, 2, false},
+		{"single nanosecond needs one block", "1ns", 6, 1, false},
+		{"mixed units", "1h30m", 6, 9, false},
+		{"slower block rate", "3d", 1, 72, false},
+		{"zero duration", "0d", 6, 0, true},
+		{"negative duration", "-1h", 6, 0, true},
+		{"unknown unit", "3y", 6, 0, true},
+		{"missing number", "d", 6, 0, true},
+		{"duration longer than any valid staking time", "1000w", 6, 0, true},
+		{"duration overflow", "4000000000w", 6, 0, true},
+		{"zero block rate", "1d", 0, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			blocks, err := StakingTimeToBlocks(tt.stakingTime, tt.blocksPerHour)
+
+			if tt.expectErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, blocks)
+		})
+	}
+}
+
+func TestValidateStakingTime(t *testing.T) {
+	const minStakingTime = 100
+
+	tests := []struct {
+		name      string
+		blocks    uint64
+		expectErr bool
+	}{
+		{"exactly min staking time", minStakingTime, false},
+		{"one below min staking time", minStakingTime - 1, true},
+		{"exactly max staking time", math.MaxUint16, false},
+		{"one above max staking time", math.MaxUint16 + 1, true},
+		{"zero", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			blocks, err := ValidateStakingTime(tt.blocks, minStakingTime)
+
+			if tt.expectErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.blocks, uint64(blocks))
+		})
+	}
+}
+
+func TestDurationMatchingMinStakingTimeIsValid(t *testing.T) {
+	// 1d is exactly 144 blocks at 6 blocks per hour
+	blocks, err := StakingTimeToBlocks("1d", 6)
+	require.NoError(t, err)
+
+	_, err = ValidateStakingTime(blocks, 144)
+	require.NoError(t, err)
+
+	_, err = ValidateStakingTime(blocks, 145)
+	require.Error(t, err)
+}
+
+func TestExceedsStakingTimeLimit(t *testing.T) {
+	require.False(t, ExceedsStakingTimeLimit(10000, 10000))
+	require.True(t, ExceedsStakingTimeLimit(10001, 10000))
+	// zero limit disables the check
+	require.False(t, ExceedsStakingTimeLimit(math.MaxUint16, 0))
+}
+
+func TestLockDays(t *testing.T) {
+	// 65000 blocks at 6 blocks per hour
+	require.Equal(t, "~451 days", LockDays(BlocksToDuration(65000, 6)))
+}
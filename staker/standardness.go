@@ -0,0 +1,275 @@
+package staker
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/babylonchain/btc-staker/walletcontroller"
+	"github.com/btcsuite/btcd/blockchain"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/mempool"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+)
+
+const (
+	// standardness limits of bitcoin core relay policy
+	maxStandardTxWeight      = 400000
+	maxStandardSigScriptSize = 1650
+	minStandardTxVersion     = 1
+	maxStandardTxVersion     = 2
+)
+
+// ErrNonStandardStakingTx is wrapped by all errors returned when staking transaction
+// would not be relayed by btc nodes
+var ErrNonStandardStakingTx = errors.New("staking transaction is not standard")
+
+// ErrDustOutput is returned when output of staking transaction is below dust
+// threshold of its script
+type ErrDustOutput struct {
+	OutputIdx   int
+	OutputValue btcutil.Amount
+	MinValue    btcutil.Amount
+}
+
+func (e *ErrDustOutput) Error() string {
+	return fmt.Sprintf(
+		"output %d of staking tx is dust. output value: %s, minimum value: %s",
+		e.OutputIdx,
+		e.OutputValue,
+		e.MinValue,
+	)
+}
+
+func (e *ErrDustOutput) Unwrap() error { return ErrNonStandardStakingTx }
+
+// ErrNonStandardTxVersion is returned when staking transaction version is not
+// relayed by btc nodes
+type ErrNonStandardTxVersion struct {
+	Version int32
+}
+
+func (e *ErrNonStandardTxVersion) Error() string {
+	return fmt.Sprintf(
+		"staking tx version %d is not standard. version must be between %d and %d",
+		e.Version,
+		minStandardTxVersion,
+		maxStandardTxVersion,
+	)
+}
+
+func (e *ErrNonStandardTxVersion) Unwrap() error { return ErrNonStandardStakingTx }
+
+// ErrTxWeightTooHigh is returned when staking transaction is heavier than the
+// standard limit
+type ErrTxWeightTooHigh struct {
+	Weight    int64
+	MaxWeight int64
+}
+
+func (e *ErrTxWeightTooHigh) Error() string {
+	return fmt.Sprintf("staking tx weight %d exceeds maximum standard weight %d", e.Weight, e.MaxWeight)
+}
+
+func (e *ErrTxWeightTooHigh) Unwrap() error { return ErrNonStandardStakingTx }
+
+// ErrTxNotFinal is returned when lock time of staking transaction does not allow
+// it to be included in the next block
+type ErrTxNotFinal struct {
+	LockTime uint32
+}
+
+func (e *ErrTxNotFinal) Error() string {
+	return fmt.Sprintf("staking tx is not final. lock time: %d", e.LockTime)
+}
+
+func (e *ErrTxNotFinal) Unwrap() error { return ErrNonStandardStakingTx }
+
+// ErrNonStandardInput is returned when input of staking transaction is not
+// standard, or spends output which does not exist
+type ErrNonStandardInput struct {
+	InputIdx int
+	Reason   string
+}
+
+func (e *ErrNonStandardInput) Error() string {
+	return fmt.Sprintf("input %d of staking tx is not standard: %s", e.InputIdx, e.Reason)
+}
+
+func (e *ErrNonStandardInput) Unwrap() error { return ErrNonStandardStakingTx }
+
+// ErrFeeBelowMinRelayFee is returned when fee of staking transaction is below
+// minimum relay fee
+type ErrFeeBelowMinRelayFee struct {
+	Fee    btcutil.Amount
+	MinFee btcutil.Amount
+	VSize  int64
+}
+
+func (e *ErrFeeBelowMinRelayFee) Error() string {
+	return fmt.Sprintf(
+		"staking tx fee %s is below minimum relay fee %s for tx of %d vbytes",
+		e.Fee,
+		e.MinFee,
+		e.VSize,
+	)
+}
+
+func (e *ErrFeeBelowMinRelayFee) Unwrap() error { return ErrNonStandardStakingTx }
+
+// minRelayFee returns minimum fee of transaction with given virtual size
+func minRelayFee(vsize int64) btcutil.Amount {
+	return btcutil.Amount((vsize*int64(MinFeePerKb) + 999) / 1000)
+}
+
+// checkTxFinal checks lock time of transaction against block in which it could be
+// included earliest
+func checkTxFinal(tx *wire.MsgTx, nextBlockHeight uint32, now time.Time) error {
+	if tx.LockTime == 0 {
+		return nil
+	}
+
+	finalSequences := true
+	for _, in := range tx.TxIn {
+		if in.Sequence != wire.MaxTxInSequenceNum {
+			finalSequences = false
+			break
+		}
+	}
+
+	// lock time is ignored if all inputs have final sequence
+	if finalSequences {
+		return nil
+	}
+
+	var lockTimeReached bool
+	if tx.LockTime < txscript.LockTimeThreshold {
+		lockTimeReached = tx.LockTime < nextBlockHeight
+	} else {
+		// median time past is not known to the wallet, current time is its upper
+		// bound
+		lockTimeReached = int64(tx.LockTime) < now.Unix()
+	}
+
+	if !lockTimeReached {
+		return &ErrTxNotFinal{LockTime: tx.LockTime}
+	}
+
+	return nil
+}
+
+// checkStakingTxStandard checks that staking transaction spending given previous
+// outputs would be relayed by btc nodes with default policy. prevOuts must be in
+// the order of transaction inputs.
+func checkStakingTxStandard(
+	tx *wire.MsgTx,
+	prevOuts []*wire.TxOut,
+	nextBlockHeight uint32,
+	now time.Time,
+) error {
+	if tx.Version < minStandardTxVersion || tx.Version > maxStandardTxVersion {
+		return &ErrNonStandardTxVersion{Version: tx.Version}
+	}
+
+	if err := checkTxFinal(tx, nextBlockHeight, now); err != nil {
+		return err
+	}
+
+	btcTx := btcutil.NewTx(tx)
+
+	if weight := blockchain.GetTransactionWeight(btcTx); weight > maxStandardTxWeight {
+		return &ErrTxWeightTooHigh{
+			Weight:    weight,
+			MaxWeight: maxStandardTxWeight,
+		}
+	}
+
+	for i, out := range tx.TxOut {
+		// op_return outputs are unspendable and not subject to dust rules
+		if txscript.GetScriptClass(out.PkScript) == txscript.NullDataTy {
+			continue
+		}
+
+		if mempool.IsDust(out, MinFeePerKb) {
+			return &ErrDustOutput{
+				OutputIdx:   i,
+				OutputValue: btcutil.Amount(out.Value),
+				MinValue:    minNonDustValue(out.PkScript),
+			}
+		}
+	}
+
+	var inputsValue int64
+	for i, in := range tx.TxIn {
+		if len(in.SignatureScript) > maxStandardSigScriptSize {
+			return &ErrNonStandardInput{
+				InputIdx: i,
+				Reason: fmt.Sprintf(
+					"signature script size %d exceeds %d bytes",
+					len(in.SignatureScript),
+					maxStandardSigScriptSize,
+				),
+			}
+		}
+
+		if !txscript.IsPushOnlyScript(in.SignatureScript) {
+			return &ErrNonStandardInput{InputIdx: i, Reason: "signature script is not push only"}
+		}
+
+		if txscript.GetScriptClass(prevOuts[i].PkScript) == txscript.NonStandardTy {
+			return &ErrNonStandardInput{InputIdx: i, Reason: "spent output script is not standard"}
+		}
+
+		inputsValue += prevOuts[i].Value
+	}
+
+	var outputsValue int64
+	for _, out := range tx.TxOut {
+		outputsValue += out.Value
+	}
+
+	vsize := mempool.GetTxVirtualSize(btcTx)
+	fee := btcutil.Amount(inputsValue - outputsValue)
+
+	if minFee := minRelayFee(vsize); fee < minFee {
+		return &ErrFeeBelowMinRelayFee{
+			Fee:    fee,
+			MinFee: minFee,
+			VSize:  vsize,
+		}
+	}
+
+	return nil
+}
+
+// checkWatchedStakingTxStandard checks that watched staking transaction, which was
+// not yet sent to btc, would be accepted by btc nodes. Outputs spent by the
+// transaction are retrieved from the node, to compute its fee.
+func (app *StakerApp) checkWatchedStakingTxStandard(tx *wire.MsgTx) error {
+	prevOuts := make([]*wire.TxOut, len(tx.TxIn))
+
+	for i, in := range tx.TxIn {
+		prevOut, err := app.wc.UnspentOutput(&in.PreviousOutPoint)
+
+		if errors.Is(err, walletcontroller.ErrOutputNotFound) {
+			return &ErrNonStandardInput{
+				InputIdx: i,
+				Reason:   fmt.Sprintf("spent output %s does not exist or is already spent", in.PreviousOutPoint),
+			}
+		}
+
+		if err != nil {
+			return fmt.Errorf("failed to retrieve output spent by input %d: %w", i, err)
+		}
+
+		prevOuts[i] = prevOut
+	}
+
+	return checkStakingTxStandard(
+		tx,
+		prevOuts,
+		app.currentBestBlockHeight.Load()+1,
+		app.clock.Now(),
+	)
+}
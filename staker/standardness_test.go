@@ -0,0 +1,60 @@
+package staker
+
+import (
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckStakingTxStandard(t *testing.T) {
+	privKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	pkScript, err := txscript.PayToTaprootScript(privKey.PubKey())
+	require.NoError(t, err)
+
+	prevOuts := []*wire.TxOut{wire.NewTxOut(100000, pkScript)}
+	now := time.Now()
+
+	validTx := func() *wire.MsgTx {
+		tx := wire.NewMsgTx(2)
+		tx.AddTxIn(wire.NewTxIn(wire.NewOutPoint(&chainhash.Hash{1}, 0), nil, [][]byte{make([]byte, 64)}))
+		tx.AddTxOut(wire.NewTxOut(90000, pkScript))
+		return tx
+	}
+
+	require.NoError(t, checkStakingTxStandard(validTx(), prevOuts, 100, now))
+
+	tx := validTx()
+	tx.Version = 3
+	var versionErr *ErrNonStandardTxVersion
+	require.ErrorAs(t, checkStakingTxStandard(tx, prevOuts, 100, now), &versionErr)
+
+	tx = validTx()
+	tx.TxOut[0].Value = 1
+	var dustErr *ErrDustOutput
+	require.ErrorAs(t, checkStakingTxStandard(tx, prevOuts, 100, now), &dustErr)
+
+	tx = validTx()
+	tx.TxOut[0].Value = 100000
+	var feeErr *ErrFeeBelowMinRelayFee
+	require.ErrorAs(t, checkStakingTxStandard(tx, prevOuts, 100, now), &feeErr)
+	require.ErrorIs(t, feeErr, ErrNonStandardStakingTx)
+
+	tx = validTx()
+	tx.TxIn[0].SignatureScript = []byte{txscript.OP_CHECKSIG}
+	var inputErr *ErrNonStandardInput
+	require.ErrorAs(t, checkStakingTxStandard(tx, prevOuts, 100, now), &inputErr)
+
+	tx = validTx()
+	tx.LockTime = 100
+	tx.TxIn[0].Sequence = wire.MaxTxInSequenceNum - 1
+	var finalErr *ErrTxNotFinal
+	require.ErrorAs(t, checkStakingTxStandard(tx, prevOuts, 100, now), &finalErr)
+	require.NoError(t, checkStakingTxStandard(tx, prevOuts, 101, now))
+}
@@ -0,0 +1,281 @@
+package staker
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	cl "github.com/babylonchain/btc-staker/babylonclient"
+	"github.com/babylonchain/btc-staker/proto"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// maximum interval between retries of failed startup check
+	maxStartupCheckRetryInterval = 30 * time.Minute
+)
+
+type FailedStartupCheck struct {
+	StakingTxHash chainhash.Hash
+	// state of transaction at the time of the check
+	State       proto.TransactionState
+	Attempts    uint32
+	LastError   string
+	NextAttempt time.Time
+}
+
+// failedStartupChecks keeps startup checks of transactions which failed, until
+// they are successfully retried
+type failedStartupChecks struct {
+	mu            sync.Mutex
+	retryInterval time.Duration
+	checks        map[chainhash.Hash]*FailedStartupCheck
+}
+
+func newFailedStartupChecks(retryInterval time.Duration) *failedStartupChecks {
+	return &failedStartupChecks{
+		retryInterval: retryInterval,
+		checks:        make(map[chainhash.Hash]*FailedStartupCheck),
+	}
+}
+
+// failed records failure of the check, next attempt is scheduled with exponential
+// backoff
+func (c *failedStartupChecks) failed(
+	stakingTxHash chainhash.Hash,
+	state proto.TransactionState,
+	err error,
+	now time.Time,
+) *FailedStartupCheck {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	check, ok := c.checks[stakingTxHash]
+
+	if !ok {
+		check = &FailedStartupCheck{
+			StakingTxHash: stakingTxHash,
+		}
+		c.checks[stakingTxHash] = check
+	}
+
+	interval := c.retryInterval
+	for i := uint32(0); i < check.Attempts && interval < maxStartupCheckRetryInterval; i++ {
+		interval *= 2
+	}
+
+	if interval > maxStartupCheckRetryInterval {
+		interval = maxStartupCheckRetryInterval
+	}
+
+	check.State = state
+	check.Attempts++
+	check.LastError = err.Error()
+	check.NextAttempt = now.Add(interval)
+
+	result := *check
+	return &result
+}
+
+func (c *failedStartupChecks) remove(stakingTxHash chainhash.Hash) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.checks, stakingTxHash)
+}
+
+// due returns checks which should be retried at given time
+func (c *failedStartupChecks) due(now time.Time) []FailedStartupCheck {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var due []FailedStartupCheck
+
+	for _, check := range c.checks {
+		if !now.Before(check.NextAttempt) {
+			due = append(due, *check)
+		}
+	}
+
+	return due
+}
+
+// list returns all failed checks, ordered by time of the next attempt
+func (c *failedStartupChecks) list() []FailedStartupCheck {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	checks := make([]FailedStartupCheck, 0, len(c.checks))
+
+	for _, check := range c.checks {
+		checks = append(checks, *check)
+	}
+
+	sort.Slice(checks, func(i, j int) bool {
+		return checks[i].NextAttempt.Before(checks[j].NextAttempt)
+	})
+
+	return checks
+}
+
+func (app *StakerApp) recordStartupCheckFailure(
+	stakingTxHash *chainhash.Hash,
+	state proto.TransactionState,
+	err error,
+) {
+	check := app.startupChecks.failed(*stakingTxHash, state, err, app.clock.Now())
+
+	app.logger.WithFields(logrus.Fields{
+		"stakingTxHash": stakingTxHash,
+		"state":         state,
+		"attempts":      check.Attempts,
+		"nextAttempt":   check.NextAttempt,
+		"err":           err,
+	}).Warn("Failed to check transaction status")
+}
+
+// startupCheckTask is a check of status of single transaction, performed during
+// startup
+type startupCheckTask struct {
+	stakingTxHash *chainhash.Hash
+	state         proto.TransactionState
+}
+
+// checkTransactionStatus checks whether transaction in given state progressed
+// while staker was down
+func (app *StakerApp) checkTransactionStatus(
+	stakingTxHash *chainhash.Hash,
+	state proto.TransactionState,
+	params *cl.StakingParams,
+) error {
+	switch state {
+	case proto.TransactionState_SENT_TO_BTC:
+		return app.checkSentToBtcTransaction(stakingTxHash, params)
+	case proto.TransactionState_CONFIRMED_ON_BTC:
+		return app.checkConfirmedOnBtcTransaction(stakingTxHash, params)
+	case proto.TransactionState_MISSING_ON_BTC:
+		return app.checkMissingOnBtcTransaction(stakingTxHash, params)
+	default:
+		return nil
+	}
+}
+
+// runStartupChecks checks status of given transactions, running at most
+// concurrency checks at the same time. Checks of separate transactions are
+// independent, so failure of one check does not stop checking other
+// transactions. Failed checks are recorded to be retried in background. Returns
+// number of failed checks and the last error.
+func (app *StakerApp) runStartupChecks(
+	tasks []startupCheckTask,
+	params *cl.StakingParams,
+	concurrency int,
+) (int, error) {
+	var (
+		mu        sync.Mutex
+		numFailed int
+		lastErr   error
+		wg        sync.WaitGroup
+	)
+
+	taskChan := make(chan startupCheckTask)
+
+	worker := func() {
+		defer wg.Done()
+
+		for task := range taskChan {
+			err := app.checkTransactionStatus(task.stakingTxHash, task.state, params)
+
+			if err == nil {
+				continue
+			}
+
+			app.recordStartupCheckFailure(task.stakingTxHash, task.state, err)
+
+			mu.Lock()
+			numFailed++
+			lastErr = err
+			mu.Unlock()
+		}
+	}
+
+	if concurrency > len(tasks) {
+		concurrency = len(tasks)
+	}
+
+	wg.Add(concurrency)
+	for i := 0; i < concurrency; i++ {
+		go worker()
+	}
+
+	for _, task := range tasks {
+		taskChan <- task
+	}
+	close(taskChan)
+
+	wg.Wait()
+
+	return numFailed, lastErr
+}
+
+// retryStartupCheck retries check of given transaction, if transaction is still
+// in the same state as during the failed check
+func (app *StakerApp) retryStartupCheck(check *FailedStartupCheck) {
+	storedTx, err := app.txTracker.GetTransaction(&check.StakingTxHash)
+
+	if err != nil {
+		app.recordStartupCheckFailure(&check.StakingTxHash, check.State, err)
+		return
+	}
+
+	if storedTx.State != check.State {
+		// transaction was moved forward in the meantime, nothing to check
+		app.startupChecks.remove(check.StakingTxHash)
+		return
+	}
+
+	params, err := app.babylonClient.Params()
+
+	if err != nil {
+		app.recordStartupCheckFailure(&check.StakingTxHash, check.State, err)
+		return
+	}
+
+	if err := app.checkTransactionStatus(&check.StakingTxHash, check.State, params); err != nil {
+		app.recordStartupCheckFailure(&check.StakingTxHash, check.State, err)
+		return
+	}
+
+	app.logger.WithFields(logrus.Fields{
+		"stakingTxHash": check.StakingTxHash,
+		"attempts":      check.Attempts + 1,
+	}).Info("Successfully checked transaction status")
+
+	app.startupChecks.remove(check.StakingTxHash)
+}
+
+// retryFailedStartupChecks retries failed startup checks until all of them succeed
+// or app is shutting down
+func (app *StakerApp) retryFailedStartupChecks() {
+	ticker := app.clock.NewTicker(app.config.StakerConfig.StartupCheckRetryInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.Chan():
+			for _, check := range app.startupChecks.due(app.clock.Now()) {
+				check := check
+				app.retryStartupCheck(&check)
+			}
+
+		case <-app.quit:
+			return
+		}
+	}
+}
+
+// FailedStartupChecks returns transactions which status could not be checked
+// during startup, and are still being retried
+func (app *StakerApp) FailedStartupChecks() []FailedStartupCheck {
+	return app.startupChecks.list()
+}
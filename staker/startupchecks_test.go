@@ -0,0 +1,293 @@
+package staker
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/babylonchain/btc-staker/proto"
+	"github.com/babylonchain/btc-staker/utils"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	notifier "github.com/lightningnetwork/lnd/chainntnfs"
+	"github.com/stretchr/testify/require"
+)
+
+func (d *testStakerDeps) addConfirmedTransaction(t testing.TB) *chainhash.Hash {
+	txHash := d.addSentToBtcTransaction(t)
+	require.NoError(t, d.tracker.SetTxConfirmed(txHash, &chainhash.Hash{}, 10, time.Time{}))
+
+	return txHash
+}
+
+func TestAllConfirmedTransactionsAreSentToBabylonOnStartup(t *testing.T) {
+	deps := newTestStakerDeps(t)
+	deps.wallet.txsInChain = make(map[chainhash.Hash]*notifier.TxConfirmation)
+
+	var confirmed []chainhash.Hash
+
+	for i := 0; i < 3; i++ {
+		txHash := deps.addConfirmedTransaction(t)
+		confirmed = append(confirmed, *txHash)
+
+		deps.wallet.txsInChain[*txHash] = &notifier.TxConfirmation{
+			BlockHeight: 10,
+			Block:       wire.NewMsgBlock(&wire.BlockHeader{}),
+		}
+	}
+
+	app := deps.newApp(t)
+	// with no free in-flight slots, every scheduled delegation send is backlogged,
+	// which makes scheduled sends observable without babylon node
+	app.delegationBacklog = newDelegationBacklog(0)
+
+	require.NoError(t, app.checkTransactionsStatus())
+
+	require.ElementsMatch(t, confirmed, app.delegationBacklog.backlog)
+}
+
+func TestConfirmedTransactionMissingOnBtcIsMarked(t *testing.T) {
+	deps := newTestStakerDeps(t)
+	txHash := deps.addConfirmedTransaction(t)
+
+	app := deps.newApp(t)
+	// with no free in-flight slots, every scheduled delegation send is backlogged,
+	// which makes scheduled sends observable without babylon node
+	app.delegationBacklog = newDelegationBacklog(0)
+
+	require.NoError(t, app.checkTransactionsStatus())
+
+	storedTx, err := deps.tracker.GetTransaction(txHash)
+	require.NoError(t, err)
+	require.Equal(t, proto.TransactionState_MISSING_ON_BTC, storedTx.State)
+
+	// transaction still not on btc chain on next startup
+	require.NoError(t, app.checkTransactionsStatus())
+
+	storedTx, err = deps.tracker.GetTransaction(txHash)
+	require.NoError(t, err)
+	require.Equal(t, proto.TransactionState_MISSING_ON_BTC, storedTx.State)
+	require.Empty(t, app.delegationBacklog.backlog)
+
+	// btc node finished resyncing and knows the transaction again
+	block := wire.NewMsgBlock(&wire.BlockHeader{})
+	deps.wallet.txsInChain = map[chainhash.Hash]*notifier.TxConfirmation{
+		*txHash: {BlockHeight: 12, Block: block},
+	}
+
+	require.NoError(t, app.checkTransactionsStatus())
+
+	storedTx, err = deps.tracker.GetTransaction(txHash)
+	require.NoError(t, err)
+	require.Equal(t, proto.TransactionState_CONFIRMED_ON_BTC, storedTx.State)
+	require.Equal(t, uint32(12), storedTx.StakingTxConfirmationInfo.Height)
+	require.Equal(t, block.BlockHash(), storedTx.StakingTxConfirmationInfo.BlockHash)
+	require.Equal(t, []chainhash.Hash{*txHash}, app.delegationBacklog.backlog)
+}
+
+func TestSentTransactionConfirmedWhenWalletAheadOfNotifier(t *testing.T) {
+	deps := newTestStakerDeps(t)
+	deps.notifier.confirmed = make(chan *notifier.TxConfirmation, 1)
+	txHash := deps.addSentToBtcTransaction(t)
+
+	storedTx, err := deps.tracker.GetTransaction(txHash)
+	require.NoError(t, err)
+
+	// notifier best block is 100, wallet already sees transaction few blocks later
+	inclusionHeight := uint32(105)
+	deps.wallet.txsInChain = map[chainhash.Hash]*notifier.TxConfirmation{
+		*txHash: {
+			BlockHash:   &chainhash.Hash{},
+			BlockHeight: inclusionHeight,
+			Block:       wire.NewMsgBlock(&wire.BlockHeader{}),
+		},
+	}
+
+	app := deps.newApp(t)
+	app.delegationBacklog = newDelegationBacklog(0)
+	t.Cleanup(func() {
+		_ = app.Stop()
+	})
+	require.NoError(t, app.Start())
+
+	heightHint, registered := deps.notifier.confHeightHint(txHash)
+	require.True(t, registered)
+	require.Equal(t, inclusionHeight, heightHint)
+
+	// notifier catches up and delivers confirmation
+	deps.notifier.confirmed <- &notifier.TxConfirmation{
+		BlockHash:   &chainhash.Hash{},
+		BlockHeight: inclusionHeight,
+		Tx:          storedTx.StakingTx,
+		Block:       wire.NewMsgBlock(&wire.BlockHeader{}),
+	}
+
+	require.Eventually(t, func() bool {
+		tx, err := deps.tracker.GetTransaction(txHash)
+		return err == nil && tx.State == proto.TransactionState_CONFIRMED_ON_BTC
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestStartupCheckFailureThreshold(t *testing.T) {
+	tests := []struct {
+		name        string
+		numOk       int
+		numFailing  int
+		minFailures uint32
+		failed      bool
+	}{
+		{
+			name:        "single transient failure of single transaction",
+			numFailing:  1,
+			minFailures: 5,
+		},
+		{
+			name:        "failures above percentage but below minimum",
+			numOk:       1,
+			numFailing:  4,
+			minFailures: 5,
+		},
+		{
+			name:        "failures below percentage",
+			numOk:       6,
+			numFailing:  5,
+			minFailures: 5,
+		},
+		{
+			name:        "failures above percentage and minimum",
+			numOk:       1,
+			numFailing:  5,
+			minFailures: 5,
+			failed:      true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deps := newTestStakerDeps(t)
+			deps.config.StakerConfig.MaxStartupCheckFailurePercent = 50
+			deps.config.StakerConfig.MinStartupCheckFailures = tt.minFailures
+			deps.wallet.txDetailsErrs = make(map[chainhash.Hash]error)
+
+			for i := 0; i < tt.numOk; i++ {
+				deps.addSentToBtcTransaction(t)
+			}
+
+			var failing []chainhash.Hash
+			for i := 0; i < tt.numFailing; i++ {
+				txHash := deps.addSentToBtcTransaction(t)
+				deps.wallet.txDetailsErrs[*txHash] = errors.New("wallet unavailable")
+				failing = append(failing, *txHash)
+			}
+
+			app := deps.newApp(t)
+			err := app.checkTransactionsStatus()
+
+			if tt.failed {
+				category, ok := StartupErrorCategoryOf(err)
+				require.True(t, ok, "expected startup error, got: %v", err)
+				require.Equal(t, RecoveryFailed, category)
+				return
+			}
+
+			require.NoError(t, err)
+
+			// only failed checks are retried in background
+			var retried []chainhash.Hash
+			for _, check := range app.FailedStartupChecks() {
+				require.Equal(t, proto.TransactionState_SENT_TO_BTC, check.State)
+				require.Equal(t, uint32(1), check.Attempts)
+				require.Equal(t, "wallet unavailable", check.LastError)
+				retried = append(retried, check.StakingTxHash)
+			}
+			require.ElementsMatch(t, failing, retried)
+		})
+	}
+}
+
+func TestFailedStartupCheckIsRetried(t *testing.T) {
+	deps := newTestStakerDeps(t)
+	retryInterval := deps.config.StakerConfig.StartupCheckRetryInterval
+
+	txHash := deps.addSentToBtcTransaction(t)
+	deps.wallet.txDetailsErrs = map[chainhash.Hash]error{
+		*txHash: errors.New("wallet unavailable"),
+	}
+
+	app := deps.newApp(t)
+	require.NoError(t, app.checkTransactionsStatus())
+	require.Len(t, app.FailedStartupChecks(), 1)
+
+	clock := app.clock.(*utils.FakeClock)
+	waiters := clock.NumWaiters()
+	retryDone := make(chan struct{})
+	go func() {
+		defer close(retryDone)
+		app.retryFailedStartupChecks()
+	}()
+	t.Cleanup(func() {
+		close(app.quit)
+		<-retryDone
+	})
+
+	requireAttempts := func(attempts uint32) {
+		require.Eventually(t, func() bool {
+			checks := app.FailedStartupChecks()
+			return len(checks) == 1 && checks[0].Attempts == attempts
+		}, time.Second, time.Millisecond)
+	}
+
+	// wait for retry loop to start its ticker
+	require.Eventually(t, func() bool {
+		return clock.NumWaiters() == waiters+1
+	}, time.Second, time.Millisecond)
+
+	// wallet is still unavailable, next attempt is backed off
+	clock.Advance(retryInterval)
+	requireAttempts(2)
+
+	check := app.FailedStartupChecks()[0]
+	require.Equal(t, clock.Now().Add(2*retryInterval), check.NextAttempt)
+
+	// wallet recovers and check succeeds on its next attempt
+	delete(deps.wallet.txDetailsErrs, *txHash)
+	clock.Advance(2 * retryInterval)
+	require.Eventually(t, func() bool {
+		return len(app.FailedStartupChecks()) == 0
+	}, time.Second, time.Millisecond)
+}
+
+// BenchmarkStartupChecks measures startup checks of 1000 stored transactions,
+// against dependencies with simulated wallet round trip time
+func BenchmarkStartupChecks(b *testing.B) {
+	deps := newTestStakerDeps(b)
+	deps.wallet.txsInChain = make(map[chainhash.Hash]*notifier.TxConfirmation)
+	deps.wallet.txDetailsLatency = time.Millisecond
+
+	for i := 0; i < 1000; i++ {
+		txHash := deps.addConfirmedTransaction(b)
+
+		deps.wallet.txsInChain[*txHash] = &notifier.TxConfirmation{
+			BlockHeight: 10,
+			Block:       wire.NewMsgBlock(&wire.BlockHeader{}),
+		}
+	}
+
+	for _, concurrency := range []int{1, 4, 16} {
+		b.Run(fmt.Sprintf("concurrency=%d", concurrency), func(b *testing.B) {
+			deps.config.StakerConfig.StartupCheckConcurrency = concurrency
+
+			for i := 0; i < b.N; i++ {
+				b.StopTimer()
+				app := deps.newApp(b)
+				app.logger.SetOutput(io.Discard)
+				app.delegationBacklog = newDelegationBacklog(0)
+				b.StartTimer()
+
+				require.NoError(b, app.checkTransactionsStatus())
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package staker
+
+import (
+	"errors"
+	"fmt"
+)
+
+// StartupErrorCategory tells what kind of problem prevented staker from starting,
+// so that process supervisor can decide whether to restart staker or alert operator
+type StartupErrorCategory int
+
+const (
+	// btc node backend could not be reached
+	NodeBackendUnavailable StartupErrorCategory = iota + 1
+	// babylon node could not be reached
+	BabylonUnavailable
+	// btc wallet could not be reached
+	WalletUnavailable
+	// staker database could not be opened or read
+	DatabaseCorrupt
+	// configuration is invalid or does not match connected services
+	ConfigInvalid
+	// recovery of transactions tracked in database failed
+	RecoveryFailed
+)
+
+func (c StartupErrorCategory) String() string {
+	switch c {
+	case NodeBackendUnavailable:
+		return "NodeBackendUnavailable"
+	case BabylonUnavailable:
+		return "BabylonUnavailable"
+	case WalletUnavailable:
+		return "WalletUnavailable"
+	case DatabaseCorrupt:
+		return "DatabaseCorrupt"
+	case ConfigInvalid:
+		return "ConfigInvalid"
+	case RecoveryFailed:
+		return "RecoveryFailed"
+	default:
+		return fmt.Sprintf("Unknown(%d)", int(c))
+	}
+}
+
+// StartupError is returned when staker fails to start
+type StartupError struct {
+	Category StartupErrorCategory
+	Err      error
+}
+
+func (e *StartupError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Category, e.Err)
+}
+
+func (e *StartupError) Unwrap() error {
+	return e.Err
+}
+
+// NewStartupError wraps err as startup error of given category
+func NewStartupError(category StartupErrorCategory, err error) error {
+	return &StartupError{
+		Category: category,
+		Err:      err,
+	}
+}
+
+// StartupErrorCategoryOf returns category of startup error wrapped in err, false
+// if err is not a startup error
+func StartupErrorCategoryOf(err error) (StartupErrorCategory, bool) {
+	var startupErr *StartupError
+
+	if errors.As(err, &startupErr) {
+		return startupErr.Category, true
+	}
+
+	return 0, false
+}
@@ -0,0 +1,494 @@
+package staker
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	cl "github.com/babylonchain/btc-staker/babylonclient"
+	scfg "github.com/babylonchain/btc-staker/stakercfg"
+	"github.com/babylonchain/btc-staker/stakerdb"
+	"github.com/babylonchain/btc-staker/utils"
+	"github.com/babylonchain/btc-staker/walletcontroller"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	pv "github.com/cosmos/relayer/v2/relayer/provider"
+	notifier "github.com/lightningnetwork/lnd/chainntnfs"
+	"github.com/lightningnetwork/lnd/kvdb"
+	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+// only methods used during startup are implemented by test dependencies, calling
+// any other method panics
+
+type testNotifier struct {
+	notifier.ChainNotifier
+	startErr error
+	// if set, best block is never delivered, so start blocks until cancelled
+	noBestBlock bool
+
+	mu sync.Mutex
+	// height hints of registered confirmation notifications
+	confHeightHints map[chainhash.Hash]uint32
+	// confirmations delivered to all registered confirmation notifications
+	confirmed chan *notifier.TxConfirmation
+	// number of next confirmation registrations which fail
+	failConfRegistrations int
+	// spend notifications registered for outpoints
+	spends map[wire.OutPoint][]chan *notifier.SpendDetail
+}
+
+func (n *testNotifier) Start() error {
+	return n.startErr
+}
+
+func (n *testNotifier) Stop() error {
+	return nil
+}
+
+func (n *testNotifier) RegisterBlockEpochNtfn(_ *notifier.BlockEpoch) (*notifier.BlockEpochEvent, error) {
+	epochs := make(chan *notifier.BlockEpoch, 1)
+	if !n.noBestBlock {
+		epochs <- &notifier.BlockEpoch{Hash: &chainhash.Hash{}, Height: 100}
+	}
+
+	return &notifier.BlockEpochEvent{
+		Epochs: epochs,
+		Cancel: func() {},
+	}, nil
+}
+
+func (n *testNotifier) RegisterConfirmationsNtfn(
+	txid *chainhash.Hash,
+	_ []byte,
+	_ uint32,
+	heightHint uint32,
+	_ ...notifier.NotifierOption,
+) (*notifier.ConfirmationEvent, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.failConfRegistrations > 0 {
+		n.failConfRegistrations--
+		return nil, errors.New("notifier unavailable")
+	}
+
+	if n.confHeightHints == nil {
+		n.confHeightHints = make(map[chainhash.Hash]uint32)
+	}
+	n.confHeightHints[*txid] = heightHint
+
+	return &notifier.ConfirmationEvent{
+		Confirmed: n.confirmed,
+		Updates:   make(chan uint32),
+		Cancel:    func() {},
+	}, nil
+}
+
+func (n *testNotifier) RegisterSpendNtfn(
+	outpoint *wire.OutPoint,
+	_ []byte,
+	_ uint32,
+) (*notifier.SpendEvent, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.spends == nil {
+		n.spends = make(map[wire.OutPoint][]chan *notifier.SpendDetail)
+	}
+
+	spend := make(chan *notifier.SpendDetail, 1)
+	n.spends[*outpoint] = append(n.spends[*outpoint], spend)
+
+	return &notifier.SpendEvent{
+		Spend:  spend,
+		Reorg:  make(chan struct{}),
+		Done:   make(chan struct{}),
+		Cancel: func() {},
+	}, nil
+}
+
+// spend delivers spend of outpoint by spenderTxHash to all registered spend
+// notifications, and returns their number
+func (n *testNotifier) spend(outpoint wire.OutPoint, spenderTxHash chainhash.Hash) int {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	for _, spend := range n.spends[outpoint] {
+		spend <- &notifier.SpendDetail{
+			SpentOutPoint: &outpoint,
+			SpenderTxHash: &spenderTxHash,
+		}
+	}
+
+	return len(n.spends[outpoint])
+}
+
+func (n *testNotifier) confHeightHint(txid *chainhash.Hash) (uint32, bool) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	hint, ok := n.confHeightHints[*txid]
+	return hint, ok
+}
+
+type testWallet struct {
+	walletcontroller.WalletController
+	network      string
+	pingErr      error
+	txDetailsErr error
+	// errors returned by TxDetails for particular transactions
+	txDetailsErrs map[chainhash.Hash]error
+	// simulated round trip time of TxDetails calls
+	txDetailsLatency time.Duration
+	// transactions reported as included in chain
+	txsInChain map[chainhash.Hash]*notifier.TxConfirmation
+	// reported sync status, wallet at genesis if not set
+	syncStatus *walletcontroller.SyncStatus
+}
+
+func (w *testWallet) NetworkName() string {
+	return w.network
+}
+
+func (w *testWallet) Ping() error {
+	return w.pingErr
+}
+
+func (w *testWallet) TxDetails(txHash *chainhash.Hash, _ []byte) (*notifier.TxConfirmation, walletcontroller.TxStatus, error) {
+	time.Sleep(w.txDetailsLatency)
+
+	if err, ok := w.txDetailsErrs[*txHash]; ok {
+		return nil, walletcontroller.TxNotFound, err
+	}
+
+	if details, ok := w.txsInChain[*txHash]; ok {
+		return details, walletcontroller.TxInChain, nil
+	}
+
+	return nil, walletcontroller.TxNotFound, w.txDetailsErr
+}
+
+func (w *testWallet) SyncStatus() (*walletcontroller.SyncStatus, error) {
+	if w.syncStatus == nil {
+		return &walletcontroller.SyncStatus{}, nil
+	}
+
+	return w.syncStatus, nil
+}
+
+type testBabylonClient struct {
+	cl.BabylonClient
+	paramsErr error
+	// params returned instead of default ones, if set
+	params *cl.StakingParams
+	// delegations known to babylon, others are reported as not found
+	delegations map[chainhash.Hash]*cl.DelegationInfo
+	// delegations returned for any staker key
+	stakerDelegations []*cl.StakerDelegation
+	feeAllowance      *cl.FeeAllowance
+	feeAllowanceErr   error
+
+	// if set, delegations are not sent until it is closed
+	stallDelegations chan struct{}
+	mu               sync.Mutex
+	delegated        []chainhash.Hash
+}
+
+func (c *testBabylonClient) QueryHeaderDepth(_ *chainhash.Hash) (uint64, error) {
+	return 100, nil
+}
+
+func (c *testBabylonClient) EstimateDelegationFee(_ *cl.DelegationData) (sdk.Coin, error) {
+	return sdk.NewInt64Coin("ubbn", 1), nil
+}
+
+func (c *testBabylonClient) Delegate(dg *cl.DelegationData) (*pv.RelayerTxResponse, error) {
+	if c.stallDelegations != nil {
+		<-c.stallDelegations
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.delegated = append(c.delegated, dg.StakingTransaction.TxHash())
+
+	return &pv.RelayerTxResponse{TxHash: "babylon-tx"}, nil
+}
+
+func (c *testBabylonClient) delegatedTxs() []chainhash.Hash {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return append([]chainhash.Hash(nil), c.delegated...)
+}
+
+func (c *testBabylonClient) QueryFeeAllowance() (*cl.FeeAllowance, error) {
+	if c.feeAllowanceErr != nil {
+		return nil, c.feeAllowanceErr
+	}
+
+	if c.feeAllowance == nil {
+		return &cl.FeeAllowance{}, nil
+	}
+
+	return c.feeAllowance, nil
+}
+
+func (c *testBabylonClient) QueryDelegationInfo(stakingTxHash *chainhash.Hash) (*cl.DelegationInfo, error) {
+	if di, ok := c.delegations[*stakingTxHash]; ok {
+		return di, nil
+	}
+
+	// babylon client returns this error wrapped by retry logic
+	return nil, fmt.Errorf("query failed: %w", cl.ErrDelegationNotFound)
+}
+
+func (c *testBabylonClient) QueryDelegationsByStakerKey(_ *btcec.PublicKey) ([]*cl.StakerDelegation, error) {
+	return c.stakerDelegations, nil
+}
+
+func (c *testBabylonClient) QueryAccountBalance() (sdk.Coin, error) {
+	return sdk.NewInt64Coin("ubbn", 1000000000), nil
+}
+
+func (c *testBabylonClient) Params() (*cl.StakingParams, error) {
+	if c.paramsErr != nil {
+		return nil, c.paramsErr
+	}
+
+	if c.params != nil {
+		return c.params, nil
+	}
+
+	return &cl.StakingParams{
+		ConfirmationTimeBlocks:    2,
+		FinalizationTimeoutBlocks: 5,
+	}, nil
+}
+
+type testStakerDeps struct {
+	config   *scfg.Config
+	db       kvdb.Backend
+	tracker  *stakerdb.TrackedTransactionStore
+	notifier *testNotifier
+	wallet   *testWallet
+	babylon  *testBabylonClient
+	// wallet used by the app instead of wallet, if set
+	wc walletcontroller.WalletController
+}
+
+func newTestStakerDeps(t testing.TB) *testStakerDeps {
+	cfg := scfg.DefaultConfig()
+	cfg.ActiveNetParams = chaincfg.RegressionNetParams
+
+	dbConfig := scfg.DefaultDBConfig()
+	dbConfig.DBPath = t.TempDir()
+
+	db, err := scfg.GetDbBackend(&dbConfig)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = db.Close()
+	})
+
+	tracker, err := stakerdb.NewTrackedTransactionStore(db)
+	require.NoError(t, err)
+
+	return &testStakerDeps{
+		config:   &cfg,
+		db:       db,
+		tracker:  tracker,
+		notifier: &testNotifier{},
+		wallet:   &testWallet{network: cfg.ActiveNetParams.Name},
+		babylon:  &testBabylonClient{},
+	}
+}
+
+func (d *testStakerDeps) newApp(t testing.TB) *StakerApp {
+	logger := logrus.New()
+	clock := utils.NewFakeClock(testClockStart)
+
+	var wc walletcontroller.WalletController = d.wallet
+	if d.wc != nil {
+		wc = d.wc
+	}
+
+	app, err := NewStakerAppFromDeps(
+		d.config,
+		logger,
+		d.babylon,
+		wc,
+		d.notifier,
+		NewStaticBtcFeeEstimator(chainfee.SatPerKVByte(1000)),
+		d.tracker,
+		cl.NewBabylonMsgSender(d.babylon, logger, cl.CircuitBreakerConfig{
+			FailureThreshold: d.config.StakerConfig.BabylonCircuitFailures,
+			Cooldown:         d.config.StakerConfig.BabylonCircuitCooldown,
+		}, cl.BatchConfig{}, clock),
+		clock,
+	)
+	require.NoError(t, err)
+
+	return app
+}
+
+func (d *testStakerDeps) startApp(t *testing.T) error {
+	app := d.newApp(t)
+
+	t.Cleanup(func() {
+		_ = app.Stop()
+	})
+
+	return app.Start()
+}
+
+// addSentToBtcTransaction adds transaction which status is checked during startup
+func (d *testStakerDeps) addSentToBtcTransaction(t testing.TB) *chainhash.Hash {
+	privKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	stakerAddress, err := btcutil.NewAddressTaproot(
+		schnorr.SerializePubKey(privKey.PubKey()),
+		&d.config.ActiveNetParams,
+	)
+	require.NoError(t, err)
+
+	pkScript, err := txscript.PayToAddrScript(stakerAddress)
+	require.NoError(t, err)
+
+	stakingTx := wire.NewMsgTx(2)
+	stakingTx.AddTxIn(wire.NewTxIn(&wire.OutPoint{}, nil, nil))
+	stakingTx.AddTxOut(wire.NewTxOut(100000, pkScript))
+
+	err = d.tracker.AddTransaction(
+		stakingTx,
+		0,
+		100,
+		[]*btcec.PublicKey{privKey.PubKey()},
+		&stakerdb.ProofOfPossession{
+			BabylonSigOverBtcPk:  []byte{1},
+			BtcSigOverBabylonSig: []byte{1},
+		},
+		stakerAddress,
+		stakerdb.AddTransactionOpts{},
+	)
+	require.NoError(t, err)
+
+	stakingTxHash := stakingTx.TxHash()
+	return &stakingTxHash
+}
+
+func TestStartupErrorCategories(t *testing.T) {
+	errUnavailable := errors.New("service unavailable")
+
+	tests := []struct {
+		name     string
+		setup    func(t *testing.T, d *testStakerDeps)
+		category StartupErrorCategory
+	}{
+		{
+			name: "node backend unavailable",
+			setup: func(t *testing.T, d *testStakerDeps) {
+				d.notifier.startErr = errUnavailable
+			},
+			category: NodeBackendUnavailable,
+		},
+		{
+			name: "wallet on different network",
+			setup: func(t *testing.T, d *testStakerDeps) {
+				d.wallet.network = chaincfg.MainNetParams.Name
+			},
+			category: ConfigInvalid,
+		},
+		{
+			name: "wallet unavailable",
+			setup: func(t *testing.T, d *testStakerDeps) {
+				d.wallet.pingErr = errUnavailable
+			},
+			category: WalletUnavailable,
+		},
+		{
+			name: "babylon unavailable",
+			setup: func(t *testing.T, d *testStakerDeps) {
+				d.babylon.paramsErr = errUnavailable
+			},
+			category: BabylonUnavailable,
+		},
+		{
+			name: "fee grant not found",
+			setup: func(t *testing.T, d *testStakerDeps) {
+				d.config.BabylonConfig.FeeGranter = "bbn1granter"
+				d.babylon.feeAllowanceErr = fmt.Errorf("%w: granter: bbn1granter", cl.ErrFeeGrantNotFound)
+			},
+			category: ConfigInvalid,
+		},
+		{
+			name: "fee grant query failed",
+			setup: func(t *testing.T, d *testStakerDeps) {
+				d.config.BabylonConfig.FeeGranter = "bbn1granter"
+				d.babylon.feeAllowanceErr = errUnavailable
+			},
+			category: BabylonUnavailable,
+		},
+		{
+			name: "fee grant does not allow delegations",
+			setup: func(t *testing.T, d *testStakerDeps) {
+				d.config.BabylonConfig.FeeGranter = "bbn1granter"
+				d.babylon.feeAllowance = &cl.FeeAllowance{
+					AllowedMessages: []string{"/cosmos.bank.v1beta1.MsgSend"},
+				}
+			},
+			category: ConfigInvalid,
+		},
+		{
+			name: "fee grant expired",
+			setup: func(t *testing.T, d *testStakerDeps) {
+				expiration := testClockStart.Add(-time.Hour)
+				d.config.BabylonConfig.FeeGranter = "bbn1granter"
+				d.babylon.feeAllowance = &cl.FeeAllowance{Expiration: &expiration}
+			},
+			category: ConfigInvalid,
+		},
+		{
+			name: "database unreadable",
+			setup: func(t *testing.T, d *testStakerDeps) {
+				require.NoError(t, d.db.Close())
+			},
+			category: DatabaseCorrupt,
+		},
+		{
+			name: "transaction recovery failed",
+			setup: func(t *testing.T, d *testStakerDeps) {
+				d.addSentToBtcTransaction(t)
+				d.wallet.txDetailsErr = errUnavailable
+				d.config.StakerConfig.MaxStartupCheckFailurePercent = 0
+				d.config.StakerConfig.MinStartupCheckFailures = 1
+			},
+			category: RecoveryFailed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deps := newTestStakerDeps(t)
+			tt.setup(t, deps)
+
+			err := deps.startApp(t)
+			require.Error(t, err)
+
+			category, ok := StartupErrorCategoryOf(err)
+			require.True(t, ok, "expected startup error, got: %v", err)
+			require.Equal(t, tt.category, category)
+		})
+	}
+}
@@ -0,0 +1,176 @@
+package staker
+
+import (
+	"sync"
+	"time"
+
+	"github.com/babylonchain/btc-staker/proto"
+	"github.com/babylonchain/btc-staker/stakerdb"
+	"github.com/btcsuite/btcd/btcutil"
+)
+
+// Ages and amounts are computed by scanning all tracked transactions, so they are
+// cached for a while to allow dashboards to poll stats frequently
+const statsScanCacheTTL = 5 * time.Second
+
+// StateStats are statistics of tracked transactions in single state
+type StateStats struct {
+	// exact number of transactions, read from state counts index
+	Count uint64
+	// time since when the oldest transaction is in this state, zero if not known
+	OldestSince time.Time
+	// how long the oldest transaction is in this state, zero if not known
+	OldestAge time.Duration
+}
+
+// DaemonStats summarize all transactions tracked by staker. Counts are exact,
+// while ages and amounts may be up to statsScanCacheTTL stale. Amounts do not
+// include archived transactions.
+type DaemonStats struct {
+	States map[proto.TransactionState]*StateStats
+	// number of archived transactions in each state
+	Archived map[proto.TransactionState]uint64
+	// value of staking outputs of transactions which were not unbonded nor spent
+	LockedInStaking btcutil.Amount
+	// value of confirmed unbonding outputs which were not spent
+	LockedInUnbonding btcutil.Amount
+	// value of staking or unbonding outputs spent by staker
+	Withdrawn btcutil.Amount
+	// time when ages and amounts were computed
+	ScannedAt time.Time
+	// fee estimates made during the last day, oldest first
+	FeeEstimates []stakerdb.FeeEstimate
+}
+
+type scannedStats struct {
+	oldestSince       map[proto.TransactionState]time.Time
+	lockedInStaking   btcutil.Amount
+	lockedInUnbonding btcutil.Amount
+	withdrawn         btcutil.Amount
+	scannedAt         time.Time
+}
+
+type statsScanCache struct {
+	mu    sync.Mutex
+	stats *scannedStats
+}
+
+func scanStats(tracker *stakerdb.TrackedTransactionStore) (*scannedStats, error) {
+	var stats *scannedStats
+
+	reset := func() {
+		stats = &scannedStats{
+			oldestSince: make(map[proto.TransactionState]time.Time),
+		}
+	}
+	reset()
+
+	err := tracker.ScanTrackedTransactions(func(tx *stakerdb.StoredTransaction) error {
+		stakingValue := btcutil.Amount(tx.StakingTx.TxOut[tx.StakingOutputIndex].Value)
+
+		switch {
+		case tx.State == proto.TransactionState_SPENT_ON_BTC:
+			// funds are withdrawn from unbonding output if unbonding transaction
+			// was confirmed, otherwise from staking output
+			if tx.UnbondingTxData != nil && tx.UnbondingTxData.UnbondingTxConfirmationInfo != nil {
+				stats.withdrawn += btcutil.Amount(tx.UnbondingTxData.UnbondingOutput().Value)
+			} else {
+				stats.withdrawn += stakingValue
+			}
+		case tx.IsUnbonded():
+			stats.lockedInUnbonding += btcutil.Amount(tx.UnbondingTxData.UnbondingOutput().Value)
+		case !stakerdb.IsTerminalState(tx.State):
+			stats.lockedInStaking += stakingValue
+		}
+
+		if stakerdb.IsTerminalState(tx.State) || tx.StateChangedAt.IsZero() {
+			return nil
+		}
+
+		oldest, ok := stats.oldestSince[tx.State]
+
+		if !ok || tx.StateChangedAt.Before(oldest) {
+			stats.oldestSince[tx.State] = tx.StateChangedAt
+		}
+
+		return nil
+	}, reset)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
+
+// scannedStats returns cached results of the last scan, or scans transactions
+// again if cached results are too old
+func (app *StakerApp) scannedStats() (*scannedStats, error) {
+	app.statsCache.mu.Lock()
+	defer app.statsCache.mu.Unlock()
+
+	now := app.clock.Now()
+
+	if app.statsCache.stats != nil && now.Sub(app.statsCache.stats.scannedAt) < statsScanCacheTTL {
+		return app.statsCache.stats, nil
+	}
+
+	stats, err := scanStats(app.txTracker)
+
+	if err != nil {
+		return nil, err
+	}
+
+	stats.scannedAt = now
+	app.statsCache.stats = stats
+
+	return stats, nil
+}
+
+// Stats returns statistics of all tracked transactions
+func (app *StakerApp) Stats() (*DaemonStats, error) {
+	counts, err := app.txTracker.TransactionStateCounts()
+
+	if err != nil {
+		return nil, err
+	}
+
+	archived, err := app.txTracker.ArchivedStateCounts()
+
+	if err != nil {
+		return nil, err
+	}
+
+	scanned, err := app.scannedStats()
+
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &DaemonStats{
+		States:            make(map[proto.TransactionState]*StateStats),
+		Archived:          archived,
+		LockedInStaking:   scanned.lockedInStaking,
+		LockedInUnbonding: scanned.lockedInUnbonding,
+		Withdrawn:         scanned.withdrawn,
+		ScannedAt:         scanned.scannedAt,
+		FeeEstimates:      app.FeeEstimates(),
+	}
+
+	now := app.clock.Now()
+
+	for state, count := range counts {
+		stateStats := &StateStats{
+			Count:       count,
+			OldestSince: scanned.oldestSince[state],
+		}
+
+		if !stateStats.OldestSince.IsZero() {
+			stateStats.OldestAge = now.Sub(stateStats.OldestSince)
+		}
+
+		stats.States[state] = stateStats
+	}
+
+	return stats, nil
+}
@@ -0,0 +1,134 @@
+package staker
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/babylonchain/btc-staker/stakerdb"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+)
+
+// TransactionDetails gathers everything known about single staking transaction.
+// Fields marked as live data are only filled when details are retrieved from
+// running staker, and are empty when database is read offline.
+type TransactionDetails struct {
+	Tx *stakerdb.StoredTransaction
+	// note of operator, empty if transaction has no note
+	Note string
+	// staking params under which staking output was created, nil if transaction
+	// was created before staking params were tracked
+	Params *stakerdb.StakingParamsSnapshot
+	// only set for watched transactions
+	WatchedData *stakerdb.WatchedTransactionData
+	// staker key committed to in staking output, known from watched data or
+	// retrieved from wallet as live data
+	StakerBtcPk *btcec.PublicKey
+	// live data: monikers of finality providers in the same order as
+	// finality provider keys of stored transaction, empty if not known
+	FpMonikers []string
+	// live data: whether staking output was rebuilt from staker key and params
+	// and matches stored staking output, nil if it could not be checked
+	ScriptVerified *bool
+	// live data: best btc block height known to staker, 0 if not known
+	CurrentBtcHeight uint32
+}
+
+// StoredTransactionDetails returns details of staking transaction using only data
+// stored in staker database, so it can be used when staker is not running
+func StoredTransactionDetails(
+	store *stakerdb.TrackedTransactionStore,
+	stakingTxHash *chainhash.Hash,
+) (*TransactionDetails, error) {
+	tx, err := store.GetTransaction(stakingTxHash)
+
+	if err != nil {
+		return nil, err
+	}
+
+	note, err := store.GetTransactionNote(stakingTxHash)
+
+	if err != nil {
+		return nil, err
+	}
+
+	details := &TransactionDetails{
+		Tx:   tx,
+		Note: note,
+	}
+
+	if tx.ParamsVersion != 0 {
+		params, err := store.GetStakingParams(tx.ParamsVersion)
+
+		if err != nil && !errors.Is(err, stakerdb.ErrStakingParamsNotFound) {
+			return nil, fmt.Errorf("failed to retrieve staking params version %d: %w", tx.ParamsVersion, err)
+		}
+
+		details.Params = params
+	}
+
+	if tx.Watched {
+		watchedData, err := store.GetWatchedTransactionData(stakingTxHash)
+
+		if err != nil {
+			return nil, err
+		}
+
+		details.WatchedData = watchedData
+		details.StakerBtcPk = watchedData.StakerBtcPubKey
+	}
+
+	return details, nil
+}
+
+// TransactionDetails returns details of staking transaction including live data
+// from wallet, btc chain and babylon
+func (app *StakerApp) TransactionDetails(stakingTxHash *chainhash.Hash) (*TransactionDetails, error) {
+	details, err := StoredTransactionDetails(app.txTracker, stakingTxHash)
+
+	if err != nil {
+		return nil, err
+	}
+
+	details.CurrentBtcHeight = app.currentBestBlockHeight.Load()
+
+	for _, fpPk := range details.Tx.FinalityProvidersBtcPks {
+		details.FpMonikers = append(details.FpMonikers, app.finalityProviderMoniker(fpPk))
+	}
+
+	if details.StakerBtcPk == nil {
+		details.StakerBtcPk = app.stakerPubKey(details.Tx)
+	}
+
+	if details.StakerBtcPk != nil && details.Params != nil {
+		verified := stakingOutputCommitsTo(
+			details.Tx,
+			details.StakerBtcPk,
+			details.Params.CovenantPks,
+			details.Params.CovenantQuorum,
+			app.network,
+		)
+		details.ScriptVerified = &verified
+	}
+
+	return details, nil
+}
+
+// stakerPubKey returns public key of staker address of the transaction. Details
+// are still useful without it, so it returns nil on failure.
+func (app *StakerApp) stakerPubKey(tx *stakerdb.StoredTransaction) *btcec.PublicKey {
+	stakerAddress, err := btcutil.DecodeAddress(tx.StakerAddress, app.network)
+
+	if err != nil {
+		return nil
+	}
+
+	pk, err := app.wc.AddressPublicKey(stakerAddress)
+
+	if err != nil {
+		return nil
+	}
+
+	return pk
+}
@@ -0,0 +1,66 @@
+package staker
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/babylonchain/btc-staker/walletcontroller"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	stakingTxLabelPurpose   = "staking"
+	unbondingTxLabelPurpose = "unbonding"
+	spendTxLabelPurpose     = "spend"
+	// consolidation transactions do not belong to any delegation
+	consolidationTxLabelPurpose = "consolidation"
+)
+
+// txLabel builds label of transaction created by staker. Labels of transactions
+// other than staking transaction also include staking transaction hash, so that
+// it is possible to tell to which delegation they belong.
+func (app *StakerApp) txLabel(purpose string, stakingTxHash *chainhash.Hash) string {
+	if purpose == stakingTxLabelPurpose || purpose == consolidationTxLabelPurpose {
+		return fmt.Sprintf("%s:%s", app.config.StakerConfig.TxLabelPrefix, purpose)
+	}
+
+	return fmt.Sprintf("%s:%s:%s", app.config.StakerConfig.TxLabelPrefix, purpose, stakingTxHash)
+}
+
+// labelTransaction stores label of transaction created for given staking transaction
+// and attaches it to the wallet transaction if wallet backend supports it. Failing
+// to label transaction is not critical, so errors are only logged.
+func (app *StakerApp) labelTransaction(
+	stakingTxHash *chainhash.Hash,
+	txHash *chainhash.Hash,
+	purpose string,
+) {
+	label := app.txLabel(purpose, stakingTxHash)
+
+	logger := app.logger.WithFields(logrus.Fields{
+		"stakingTxHash": stakingTxHash,
+		"txHash":        txHash,
+		"label":         label,
+	})
+
+	if err := app.txTracker.SetTxLabel(stakingTxHash, txHash, label); err != nil {
+		logger.WithField("err", err).Warn("Failed to store transaction label")
+	}
+
+	app.attachWalletTxLabel(logger, txHash, label)
+}
+
+// attachWalletTxLabel attaches label to the wallet transaction if wallet backend
+// supports it, errors are only logged
+func (app *StakerApp) attachWalletTxLabel(logger *logrus.Entry, txHash *chainhash.Hash, label string) {
+	err := app.wc.SetTxLabel(txHash, label)
+
+	switch {
+	case err == nil:
+	case errors.Is(err, walletcontroller.ErrTxLabelsNotSupported):
+		logger.Debug("Wallet backend does not support transaction labels. Label not attached to wallet transaction")
+	default:
+		logger.WithField("err", err).Warn("Failed to attach label to wallet transaction")
+	}
+}
@@ -19,6 +19,7 @@ import (
 	"github.com/btcsuite/btcd/btcutil"
 	"github.com/btcsuite/btcd/chaincfg"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
 	"github.com/btcsuite/btcd/wire"
 	"github.com/btcsuite/btcwallet/wallet/txrules"
 	"github.com/btcsuite/btcwallet/wallet/txsizes"
@@ -42,6 +43,17 @@ func babylonPopToDbPop(pop *cl.BabylonPop) *stakerdb.ProofOfPossession {
 	}
 }
 
+// dbPopToBabylonPop converts stored pop back to babylon representation
+func dbPopToBabylonPop(pop *stakerdb.ProofOfPossession) (*cl.BabylonPop, error) {
+	popType, err := cl.IntToPopType(int(pop.BtcSigType))
+
+	if err != nil {
+		return nil, err
+	}
+
+	return cl.NewBabylonPop(popType, pop.BabylonSigOverBtcPk, pop.BtcSigOverBabylonSig)
+}
+
 func babylonCovSigToDbCovSig(covSig cl.CovenantSignatureInfo) stakerdb.PubKeySigPair {
 	return stakerdb.NewCovenantMemberSignature(covSig.Signature, covSig.PubKey)
 }
@@ -107,7 +119,10 @@ func buildSlashingTxAndSig(
 	net *chaincfg.Params,
 ) (*wire.MsgTx, *schnorr.Signature, error) {
 	stakerPubKey := delegationData.stakerPrivKey.PubKey()
-	lockSlashTxLockTime := delegationData.babylonParams.MinUnbondingTime + 1
+	lockSlashTxLockTime, err := unbondingTimeLock(delegationData.babylonParams)
+	if err != nil {
+		return nil, nil, err
+	}
 
 	slashingTx, err := staking.BuildSlashingTxFromStakingTxStrict(
 		storedTx.StakingTx,
@@ -160,7 +175,7 @@ func buildSlashingTxAndSig(
 
 func createDelegationData(
 	StakerBtcPk *btcec.PublicKey,
-	inclusionBlock *wire.MsgBlock,
+	inclusionBlockHash *chainhash.Hash,
 	stakingTxIdx uint32,
 	storedTx *stakerdb.StoredTransaction,
 	slashingTx *wire.MsgTx,
@@ -169,13 +184,11 @@ func createDelegationData(
 	stakingTxInclusionProof []byte,
 	undelegationData *cl.UndelegationData,
 ) *cl.DelegationData {
-	inclusionBlockHash := inclusionBlock.BlockHash()
-
 	dg := cl.DelegationData{
 		StakingTransaction:                   storedTx.StakingTx,
 		StakingTransactionIdx:                stakingTxIdx,
 		StakingTransactionInclusionProof:     stakingTxInclusionProof,
-		StakingTransactionInclusionBlockHash: &inclusionBlockHash,
+		StakingTransactionInclusionBlockHash: inclusionBlockHash,
 		StakingTime:                          storedTx.StakingTime,
 		StakingValue:                         btcutil.Amount(storedTx.StakingTx.TxOut[storedTx.StakingOutputIndex].Value),
 		FinalityProvidersBtcPks:              storedTx.FinalityProvidersBtcPks,
@@ -209,7 +222,8 @@ func createSpendStakeTx(
 	spendTx.AddTxIn(stakingOutputAsInput)
 	spendTx.AddTxOut(newOutput)
 
-	// transaction have 1 P2TR input and does not have any change
+	// transaction have 1 P2TR input and does not have any change. Output size is
+	// computed from destination script, so it is correct for any destination type
 	txSize := txsizes.EstimateVirtualSize(0, 1, 0, 0, []*wire.TxOut{newOutput}, 0)
 
 	fee := txrules.FeeForSerializeSize(btcutil.Amount(feeRate), txSize)
@@ -220,6 +234,12 @@ func createSpendStakeTx(
 		return nil, nil, fmt.Errorf("too big fee rate for spend stake tx. calculated fee: %d. funding output value: %d", fee, fundingOutput.Value)
 	}
 
+	// check dust before transaction is signed, otherwise it would be only rejected
+	// by the network
+	if err := checkSpendOutputNotDust(spendTx.TxOut[0]); err != nil {
+		return nil, nil, err
+	}
+
 	return spendTx, &fee, nil
 }
 
@@ -287,7 +307,7 @@ func createSpendStakeTxFromStoredTx(
 			covenantPublicKeys,
 			covenantThreshold,
 			data.UnbondingTime,
-			btcutil.Amount(data.UnbondingTx.TxOut[0].Value),
+			btcutil.Amount(data.UnbondingOutput().Value),
 			net,
 		)
 
@@ -305,9 +325,8 @@ func createSpendStakeTxFromStoredTx(
 
 		spendTx, calculatedFee, err := createSpendStakeTx(
 			destinationScript,
-			// unbonding tx has only one output
-			data.UnbondingTx.TxOut[0],
-			0,
+			data.UnbondingOutput(),
+			data.UnbondingOutputIndex,
 			&unbondingTxHash,
 			data.UnbondingTime,
 			feeRate,
@@ -318,7 +337,7 @@ func createSpendStakeTxFromStoredTx(
 
 		return &spendStakeTxInfo{
 			spendStakeTx:           spendTx,
-			fundingOutput:          data.UnbondingTx.TxOut[0],
+			fundingOutput:          data.UnbondingOutput(),
 			fundingOutputSpendInfo: unbondingTimeLockPathInfo,
 			calculatedFee:          *calculatedFee,
 		}, nil
@@ -327,6 +346,60 @@ func createSpendStakeTxFromStoredTx(
 	}
 }
 
+// findChangeOutput returns change output of staking transaction created by
+// the wallet, or nil if transaction has no change
+func findChangeOutput(
+	tx *wire.MsgTx,
+	stakingOutputIdx uint32,
+	changeAddress btcutil.Address,
+) (*stakerdb.ChangeOutput, error) {
+	changeScript, err := txscript.PayToAddrScript(changeAddress)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for i, out := range tx.TxOut {
+		if uint32(i) == stakingOutputIdx || !bytes.Equal(out.PkScript, changeScript) {
+			continue
+		}
+
+		return &stakerdb.ChangeOutput{
+			OutputIdx: uint32(i),
+			Amount:    btcutil.Amount(out.Value),
+			Address:   changeAddress.EncodeAddress(),
+		}, nil
+	}
+
+	return nil, nil
+}
+
+// unbondingOutputValueAndFee returns value of unbonding output and fee paid by
+// unbonding transaction spending staking output of given value
+func unbondingOutputValueAndFee(
+	stakingOutputValue btcutil.Amount,
+	feeRatePerKb btcutil.Amount,
+	slashingFee btcutil.Amount,
+) (btcutil.Amount, btcutil.Amount, error) {
+	unbondingTxFee := txrules.FeeForSerializeSize(feeRatePerKb, slashingPathSpendTxVSize)
+
+	unbondingOutputValue := stakingOutputValue - unbondingTxFee
+
+	if unbondingOutputValue <= 0 {
+		return 0, 0, fmt.Errorf(
+			"too large fee rate %d sats/kb. Staking output value:%d sats. Unbonding tx fee:%d sats", int64(feeRatePerKb), int64(stakingOutputValue), int64(unbondingTxFee),
+		)
+	}
+
+	if unbondingOutputValue <= slashingFee {
+		return 0, 0, fmt.Errorf(
+			"too large fee rate %d sats/kb. Unbonding output value %d sats. Slashing tx fee: %d sats", int64(feeRatePerKb), int64(unbondingOutputValue), int64(slashingFee),
+		)
+	}
+
+	return unbondingOutputValue, unbondingTxFee, nil
+}
+
 func createUndelegationData(
 	storedTx *stakerdb.StoredTransaction,
 	stakerPrivKey *btcec.PrivateKey,
@@ -343,23 +416,17 @@ func createUndelegationData(
 
 	stakingOutpout := storedTx.StakingTx.TxOut[storedTx.StakingOutputIndex]
 
-	unbondingTxFee := txrules.FeeForSerializeSize(feeRatePerKb, slashingPathSpendTxVSize)
-
-	unbondingOutputValue := stakingOutpout.Value - int64(unbondingTxFee)
-
-	stakerPubKey := stakerPrivKey.PubKey()
+	unbondingOutputValue, _, err := unbondingOutputValueAndFee(
+		btcutil.Amount(stakingOutpout.Value),
+		feeRatePerKb,
+		slashingFee,
+	)
 
-	if unbondingOutputValue <= 0 {
-		return nil, fmt.Errorf(
-			"too large fee rate %d sats/kb. Staking output value:%d sats. Unbonding tx fee:%d sats", int64(feeRatePerKb), stakingOutpout.Value, int64(unbondingTxFee),
-		)
+	if err != nil {
+		return nil, err
 	}
 
-	if unbondingOutputValue <= int64(slashingFee) {
-		return nil, fmt.Errorf(
-			"too large fee rate %d sats/kb. Unbonding output value %d sats. Slashing tx fee: %d sats", int64(feeRatePerKb), unbondingOutputValue, int64(slashingFee),
-		)
-	}
+	stakerPubKey := stakerPrivKey.PubKey()
 
 	unbondingInfo, err := staking.BuildUnbondingInfo(
 		stakerPubKey,
@@ -367,7 +434,7 @@ func createUndelegationData(
 		covenantPubKeys,
 		covenantThreshold,
 		unbondingTime,
-		btcutil.Amount(unbondingOutputValue),
+		unbondingOutputValue,
 		btcNetwork,
 	)
 
@@ -378,10 +445,11 @@ func createUndelegationData(
 	unbondingTx := wire.NewMsgTx(2)
 	unbondingTx.AddTxIn(wire.NewTxIn(wire.NewOutPoint(&stakingTxHash, storedTx.StakingOutputIndex), nil, nil))
 	unbondingTx.AddTxOut(unbondingInfo.UnbondingOutput)
+	unbondingOutputIdx := uint32(len(unbondingTx.TxOut) - 1)
 
 	slashUnbondingTx, err := staking.BuildSlashingTxFromStakingTxStrict(
 		unbondingTx,
-		0,
+		unbondingOutputIdx,
 		slashingAddress,
 		stakerPubKey,
 		unbondingTime,
@@ -413,7 +481,8 @@ func createUndelegationData(
 
 	return &cl.UndelegationData{
 		UnbondingTransaction:         unbondingTx,
-		UnbondingTxValue:             btcutil.Amount(unbondingOutputValue),
+		UnbondingOutputIdx:           unbondingOutputIdx,
+		UnbondingTxValue:             unbondingOutputValue,
 		UnbondingTxUnbondingTime:     unbondingTime,
 		SlashUnbondingTransaction:    slashUnbondingTx,
 		SlashUnbondingTransactionSig: slashUnbondingTxSignature,
@@ -424,7 +493,8 @@ func createWitnessToSendUnbondingTx(
 	stakerPrivKey *btcec.PrivateKey,
 	storedTx *stakerdb.StoredTransaction,
 	unbondingData *stakerdb.UnbondingStoreData,
-	params *cl.StakingParams,
+	covenantPks []*btcec.PublicKey,
+	covenantQuorum uint32,
 	net *chaincfg.Params,
 ) (wire.TxWitness, error) {
 	if storedTx.State < proto.TransactionState_DELEGATION_ACTIVE {
@@ -435,15 +505,15 @@ func createWitnessToSendUnbondingTx(
 		return nil, fmt.Errorf("cannot create witness for sending unbonding tx. Unbonding data does not contain unbonding transaction")
 	}
 
-	if len(unbondingData.CovenantSignatures) < int(params.CovenantQuruomThreshold) {
-		return nil, fmt.Errorf("cannot create witness for sending unbonding tx. Unbonding data does not contain all necessary signatures. Required: %d, received: %d", params.CovenantQuruomThreshold, len(unbondingData.CovenantSignatures))
+	if len(unbondingData.CovenantSignatures) < int(covenantQuorum) {
+		return nil, fmt.Errorf("cannot create witness for sending unbonding tx. Unbonding data does not contain all necessary signatures. Required: %d, received: %d", covenantQuorum, len(unbondingData.CovenantSignatures))
 	}
 
 	stakingInfo, err := staking.BuildStakingInfo(
 		stakerPrivKey.PubKey(),
 		storedTx.FinalityProvidersBtcPks,
-		params.CovenantPks,
-		params.CovenantQuruomThreshold,
+		covenantPks,
+		covenantQuorum,
 		storedTx.StakingTime,
 		btcutil.Amount(storedTx.StakingTx.TxOut[storedTx.StakingOutputIndex].Value),
 		net,
@@ -470,8 +540,39 @@ func createWitnessToSendUnbondingTx(
 		return nil, err
 	}
 
+	stakingOutput := storedTx.StakingTx.TxOut[storedTx.StakingOutputIndex]
+	unbondingScript := unbondingPathInfo.RevealedLeaf.Script
+
+	if err := verifyUnbondingSig(
+		unbondingData.UnbondingTx,
+		stakingOutput,
+		unbondingScript,
+		stakerPrivKey.PubKey(),
+		stakerUnbondingSig,
+	); err != nil {
+		return nil, &ErrInvalidStakerUnbondingSignature{
+			StakerPk: xOnlyKey(stakerPrivKey.PubKey()),
+			Reason:   err.Error(),
+		}
+	}
+
+	for _, sig := range unbondingData.CovenantSignatures {
+		if err := verifyUnbondingSig(
+			unbondingData.UnbondingTx,
+			stakingOutput,
+			unbondingScript,
+			sig.PubKey,
+			sig.Signature,
+		); err != nil {
+			return nil, &ErrInvalidCovenantUnbondingSignature{
+				CovenantPk: xOnlyKey(sig.PubKey),
+				Reason:     err.Error(),
+			}
+		}
+	}
+
 	covenantSigantures := createWitnessSignaturesForPubKeys(
-		params.CovenantPks,
+		covenantPks,
 		unbondingData.CovenantSignatures,
 	)
 
@@ -481,6 +582,98 @@ func createWitnessToSendUnbondingTx(
 	)
 }
 
+// stakingOutputCommitsTo checks whether staking output of stored transaction was
+// built with given covenant committee
+func stakingOutputCommitsTo(
+	storedTx *stakerdb.StoredTransaction,
+	stakerPubKey *btcec.PublicKey,
+	covenantPks []*btcec.PublicKey,
+	covenantQuorum uint32,
+	net *chaincfg.Params,
+) bool {
+	return outputCommitsTo(storedTx, storedTx.StakingOutputIndex, stakerPubKey, covenantPks, covenantQuorum, net)
+}
+
+// outputCommitsTo checks whether given output of staking transaction matches
+// staking script built from stored transaction with given covenant committee
+func outputCommitsTo(
+	storedTx *stakerdb.StoredTransaction,
+	outputIdx uint32,
+	stakerPubKey *btcec.PublicKey,
+	covenantPks []*btcec.PublicKey,
+	covenantQuorum uint32,
+	net *chaincfg.Params,
+) bool {
+	output := storedTx.StakingTx.TxOut[outputIdx]
+
+	stakingInfo, err := staking.BuildStakingInfo(
+		stakerPubKey,
+		storedTx.FinalityProvidersBtcPks,
+		covenantPks,
+		covenantQuorum,
+		storedTx.StakingTime,
+		btcutil.Amount(output.Value),
+		net,
+	)
+
+	if err != nil {
+		return false
+	}
+
+	return bytes.Equal(stakingInfo.StakingOutput.PkScript, output.PkScript)
+}
+
+// findUnbondingOutput returns index of output of unbonding transaction which
+// matches unbonding script built from given values
+func findUnbondingOutput(
+	unbondingTx *wire.MsgTx,
+	stakerPubKey *btcec.PublicKey,
+	fpPks []*btcec.PublicKey,
+	covenantPks []*btcec.PublicKey,
+	covenantQuorum uint32,
+	unbondingTime uint16,
+	net *chaincfg.Params,
+) (uint32, error) {
+	for idx, output := range unbondingTx.TxOut {
+		unbondingInfo, err := staking.BuildUnbondingInfo(
+			stakerPubKey,
+			fpPks,
+			covenantPks,
+			covenantQuorum,
+			unbondingTime,
+			btcutil.Amount(output.Value),
+			net,
+		)
+
+		// outputs which can't lock unbonded funds, e.g. zero value anchors
+		if err != nil {
+			continue
+		}
+
+		if bytes.Equal(unbondingInfo.UnbondingOutput.PkScript, output.PkScript) {
+			return uint32(idx), nil
+		}
+	}
+
+	return 0, fmt.Errorf("no output of unbonding transaction %s matches unbonding script", unbondingTx.TxHash())
+}
+
+// numCommonKeys returns number of keys from a which are also in b
+func numCommonKeys(a []*btcec.PublicKey, b []*btcec.PublicKey) uint32 {
+	var num uint32
+
+	for _, keyA := range a {
+		for _, keyB := range b {
+			if bytes.Equal(schnorr.SerializePubKey(keyA), schnorr.SerializePubKey(keyB)) {
+				num++
+				break
+			}
+		}
+	}
+
+	return num
+}
+
 func parseWatchStakingRequest(
 	stakingTx *wire.MsgTx,
 	stakingTime uint16,
@@ -496,9 +689,16 @@ func parseWatchStakingRequest(
 	slashUnbondingTx *wire.MsgTx,
 	slashUnbondingTxSig *schnorr.Signature,
 	unbondingTime uint16,
+	label string,
+	requiredDepth uint32,
 	currentParams *cl.StakingParams,
+	paramsVersion uint32,
 	network *chaincfg.Params,
 ) (*stakingRequestedEvent, error) {
+	if err := validateFinalityProviderKeys(fpBtcPks, stakerBtcPk, currentParams.CovenantPks); err != nil {
+		return nil, fmt.Errorf("failed to watch staking tx: %w", err)
+	}
+
 	stakingInfo, err := staking.BuildStakingInfo(
 		stakerBtcPk,
 		fpBtcPks,
@@ -522,7 +722,28 @@ func parseWatchStakingRequest(
 		return nil, fmt.Errorf("failed to watch staking tx. Unbonding time must be greater than min unbonding time. Unbonding time: %d, min unbonding time: %d", unbondingTime, currentParams.MinUnbondingTime)
 	}
 
-	// 2. Check wheter slashing tx match staking tx
+	stakingTxSlashingPathInfo, err := stakingInfo.SlashingPathSpendInfo()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to watch staking tx. Invalid staking path info: %w", err)
+	}
+
+	// 2. Check slashing tx spends staking output, pays to slashing address and is
+	// signed by staker key committed in slashing path script
+	err = checkWatchedSlashingTx(
+		slashingTx,
+		slashingTxSig,
+		stakingTx,
+		stakingOutputIdx,
+		stakingTxSlashingPathInfo.RevealedLeaf.Script,
+		currentParams.SlashingAddress,
+		currentParams.MinSlashingTxFeeSat,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to watch staking tx. Invalid slashing tx: %w", err)
+	}
+
+	// 3. Check slashing rate and change output of slashing tx
 	err = staking.CheckTransactions(
 		slashingTx,
 		stakingTx,
@@ -538,72 +759,44 @@ func parseWatchStakingRequest(
 		return nil, fmt.Errorf("failed to watch staking tx. Invalid transactions: %w", err)
 	}
 
-	stakingTxSlashingPathInfo, err := stakingInfo.SlashingPathSpendInfo()
-
-	if err != nil {
-		return nil, fmt.Errorf("failed to watch staking tx. Invalid staking path info: %w", err)
-	}
-
-	// 4. Check slashig tx sig is good. It implicitly verify staker pubkey, as script
-	// contain it.
-	err = staking.VerifyTransactionSigWithOutputData(
-		slashingTx,
-		stakingTx.TxOut[stakingOutputIdx].PkScript,
-		stakingTx.TxOut[stakingOutputIdx].Value,
-		stakingTxSlashingPathInfo.RevealedLeaf.Script,
-		stakerBtcPk,
-		slashingTxSig.Serialize(),
-	)
-	if err != nil {
-		return nil, fmt.Errorf("failed to watch staking tx. Invalid slashing tx sig: %w", err)
-	}
-
-	// 5. Validate pop
-	if err = pop.ValidatePop(stakerBabylonPk, stakerBtcPk, network); err != nil {
+	// 4. Validate pop
+	if err = pop.VerifyForKeys(stakerBtcPk, stakerBabylonPk, network); err != nil {
 		return nil, fmt.Errorf("failed to watch staking tx. Invalid pop: %w", err)
 	}
 
-	// 6. Validate unbonding related data
+	// 5. Validate unbonding related data
 	if err := btcstaking.IsSimpleTransfer(unbondingTx); err != nil {
 		return nil, fmt.Errorf("failed to watch staking tx. Invalid unbonding tx: %w", err)
 	}
 
-	unbondingTxValue := unbondingTx.TxOut[0].Value
-	unbondingTxPkScript := unbondingTx.TxOut[0].PkScript
-
-	unbondingValue := btcutil.Amount(unbondingTxValue)
-
-	unbondingInfo, err := staking.BuildUnbondingInfo(
+	unbondingOutputIdx, err := findUnbondingOutput(
+		unbondingTx,
 		stakerBtcPk,
 		fpBtcPks,
 		currentParams.CovenantPks,
 		currentParams.CovenantQuruomThreshold,
 		unbondingTime,
-		unbondingValue,
 		network,
 	)
 
 	if err != nil {
-		return nil, fmt.Errorf("failed to watch staking tx. Failed to build unbonding scripts: %w", err)
+		return nil, fmt.Errorf("failed to watch staking tx. Unbonding output does not match output produced from provided values: %w", err)
 	}
 
-	if unbondingInfo.UnbondingOutput.Value != unbondingTxValue || !bytes.Equal(unbondingInfo.UnbondingOutput.PkScript, unbondingTxPkScript) {
-		return nil, fmt.Errorf("failed to watch staking tx. Unbonding output does not match output produced from provided values")
-	}
+	unbondingOutput := unbondingTx.TxOut[unbondingOutputIdx]
 
-	err = staking.CheckTransactions(
-		slashUnbondingTx,
-		unbondingTx,
-		0,
-		int64(currentParams.MinSlashingTxFeeSat),
-		currentParams.SlashingRate,
-		currentParams.SlashingAddress,
+	unbondingInfo, err := staking.BuildUnbondingInfo(
 		stakerBtcPk,
+		fpBtcPks,
+		currentParams.CovenantPks,
+		currentParams.CovenantQuruomThreshold,
 		unbondingTime,
+		btcutil.Amount(unbondingOutput.Value),
 		network,
 	)
+
 	if err != nil {
-		return nil, fmt.Errorf("failed to watch staking tx. Invalid slash-unbonding transaction: %w", err)
+		return nil, fmt.Errorf("failed to watch staking tx. Failed to build unbonding scripts: %w", err)
 	}
 
 	unbondingSlashingInfo, err := unbondingInfo.SlashingPathSpendInfo()
@@ -612,19 +805,35 @@ func parseWatchStakingRequest(
 		return nil, fmt.Errorf("failed to watch staking tx. Invalid unbonding slashing path info: %w", err)
 	}
 
-	err = staking.VerifyTransactionSigWithOutputData(
+	err = checkWatchedSlashingTx(
 		slashUnbondingTx,
-		unbondingTx.TxOut[0].PkScript,
-		unbondingTx.TxOut[0].Value,
+		slashUnbondingTxSig,
+		unbondingTx,
+		unbondingOutputIdx,
 		unbondingSlashingInfo.RevealedLeaf.Script,
+		currentParams.SlashingAddress,
+		currentParams.MinSlashingTxFeeSat,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to watch staking tx. Invalid slash-unbonding transaction: %w", err)
+	}
+
+	err = staking.CheckTransactions(
+		slashUnbondingTx,
+		unbondingTx,
+		unbondingOutputIdx,
+		int64(currentParams.MinSlashingTxFeeSat),
+		currentParams.SlashingRate,
+		currentParams.SlashingAddress,
 		stakerBtcPk,
-		slashUnbondingTxSig.Serialize(),
+		unbondingTime,
+		network,
 	)
 	if err != nil {
-		return nil, fmt.Errorf("failed to watch staking tx. Invalid slashing tx sig: %w", err)
+		return nil, fmt.Errorf("failed to watch staking tx. Invalid slash-unbonding transaction: %w", err)
 	}
 
-	if unbondingTx.TxOut[0].Value >= stakingTx.TxOut[stakingOutputIdx].Value {
+	if unbondingOutput.Value >= stakingTx.TxOut[stakingOutputIdx].Value {
 		return nil, fmt.Errorf("failed to watch staking tx. Unbonding tx value must be less than staking output value")
 	}
 
@@ -644,16 +853,19 @@ func parseWatchStakingRequest(
 		stakingTime,
 		stakingValue,
 		fpBtcPks,
-		currentParams.ConfirmationTimeBlocks,
+		requiredDepth,
 		pop,
+		paramsVersion,
 		slashingTx,
 		slashingTxSig,
 		stakerBabylonPk,
 		stakerBtcPk,
 		unbondingTx,
+		unbondingOutputIdx,
 		slashUnbondingTx,
 		slashUnbondingTxSig,
 		unbondingTime,
+		label,
 	)
 
 	return req, nil
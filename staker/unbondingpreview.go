@@ -0,0 +1,176 @@
+package staker
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/babylonchain/btc-staker/proto"
+	"github.com/babylonchain/btc-staker/stakerdb"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+)
+
+type UnbondingPreview struct {
+	UnbondingValue btcutil.Amount
+	UnbondingFee   btcutil.Amount
+	// true if unbonding transaction was already built and sent to babylon as part
+	// of delegation, in which case its fee cannot be changed
+	UnbondingFeeFixed bool
+	// estimated fee of transaction withdrawing unbonded funds to staker address
+	WithdrawalFee       btcutil.Amount
+	NetAmount           btcutil.Amount
+	UnbondingTimeBlocks uint16
+	ConfirmationBlocks  uint32
+	// estimated time to receive covenant signatures, zero if they were already received
+	CovenantSigningEstimate time.Duration
+	// estimated time after which unbonding transaction is confirmed
+	ConfirmationEstimate time.Duration
+	// estimated time after which unbonded funds can be withdrawn
+	SpendableEstimate time.Duration
+}
+
+// UnbondingTxFee returns fee paid by unbonding transaction of given staking
+// transaction, and fee rate per kvB it pays. Unbonding transaction is built when
+// delegation is sent to babylon, so its fee cannot be changed afterwards.
+func UnbondingTxFee(tx *stakerdb.StoredTransaction) (btcutil.Amount, btcutil.Amount, error) {
+	if tx.UnbondingTxData == nil || tx.UnbondingTxData.UnbondingTx == nil {
+		return 0, 0, fmt.Errorf("unbonding transaction of staking transaction %s was not built yet", tx.StakingTx.TxHash())
+	}
+
+	stakingValue := btcutil.Amount(tx.StakingTx.TxOut[tx.StakingOutputIndex].Value)
+	fee := stakingValue - btcutil.Amount(tx.UnbondingTxData.UnbondingOutput().Value)
+
+	// fee was computed for size of signed unbonding transaction, see
+	// unbondingOutputValueAndFee
+	feeRate := fee * 1000 / slashingPathSpendTxVSize
+
+	return fee, feeRate, nil
+}
+
+func (app *StakerApp) blocksDuration(blocks uint32) time.Duration {
+	return BlocksToDuration(uint64(blocks), app.config.StakerConfig.BlocksPerHour)
+}
+
+// PreviewUnbonding computes amounts and estimated timeline of unbonding given
+// staking transaction, without signing or sending anything. Fee rate is only used
+// if unbonding transaction was not yet built as part of delegation.
+func (app *StakerApp) PreviewUnbonding(stakingTxHash chainhash.Hash, feeRate *btcutil.Amount) (*UnbondingPreview, error) {
+	tx, err := app.txTracker.GetTransaction(&stakingTxHash)
+
+	if err != nil {
+		return nil, fmt.Errorf("cannont preview unbonding: %w", err)
+	}
+
+	if tx.Watched {
+		return nil, fmt.Errorf("cannot preview unbonding of watched transaction")
+	}
+
+	if !canBeUnbonded(tx.State) {
+		return nil, fmt.Errorf("cannot preview unbonding of transaction in state %s", tx.State)
+	}
+
+	stakerAddress, err := btcutil.DecodeAddress(tx.StakerAddress, app.network)
+
+	if err != nil {
+		return nil, fmt.Errorf("error decoding staker address: %s. Err: %v", tx.StakerAddress, err)
+	}
+
+	stakingValue := btcutil.Amount(tx.StakingTx.TxOut[tx.StakingOutputIndex].Value)
+
+	preview := &UnbondingPreview{
+		ConfirmationBlocks: UnbondingTxConfirmations,
+	}
+
+	if tx.UnbondingTxData != nil {
+		preview.UnbondingValue = btcutil.Amount(tx.UnbondingTxData.UnbondingOutput().Value)
+		preview.UnbondingFee, _, err = UnbondingTxFee(tx)
+
+		if err != nil {
+			return nil, err
+		}
+
+		preview.UnbondingFeeFixed = true
+		preview.UnbondingTimeBlocks = tx.UnbondingTxData.UnbondingTime
+	} else {
+		params, err := app.babylonClient.Params()
+
+		if err != nil {
+			return nil, err
+		}
+
+		unbondingFeeRate := app.estimateFee(app.config.StakerConfig.UnbondingTxConfTarget).FeeRate()
+
+		if feeRate != nil {
+			unbondingFeeRate = *feeRate
+		}
+
+		slashingFee, err := app.getSlashingFee(params, len(tx.FinalityProvidersBtcPks))
+
+		if err != nil {
+			return nil, err
+		}
+
+		unbondingValue, unbondingFee, err := unbondingOutputValueAndFee(
+			stakingValue,
+			unbondingFeeRate,
+			slashingFee,
+		)
+
+		if err != nil {
+			return nil, err
+		}
+
+		// the same unbonding time as used when building delegation
+		unbondingTime, err := unbondingTimeLock(params)
+
+		if err != nil {
+			return nil, err
+		}
+
+		preview.UnbondingValue = unbondingValue
+		preview.UnbondingFee = unbondingFee
+		preview.UnbondingTimeBlocks = unbondingTime
+	}
+
+	stakerAddressScript, err := txscript.PayToAddrScript(stakerAddress)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// only fee of withdrawal transaction matters, so spent outpoint is irrelevant
+	_, withdrawalFee, err := createSpendStakeTx(
+		stakerAddressScript,
+		wire.NewTxOut(int64(preview.UnbondingValue), nil),
+		0,
+		&chainhash.Hash{},
+		preview.UnbondingTimeBlocks,
+		app.estimateFeePerKb(app.config.StakerConfig.SpendTxConfTarget),
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	preview.WithdrawalFee = *withdrawalFee
+	preview.NetAmount = preview.UnbondingValue - *withdrawalFee
+
+	if tx.State != proto.TransactionState_DELEGATION_ACTIVE {
+		preview.CovenantSigningEstimate = app.config.StakerConfig.CovenantSigningEstimate
+	}
+
+	preview.ConfirmationEstimate = preview.CovenantSigningEstimate + app.blocksDuration(preview.ConfirmationBlocks)
+
+	// unbonding time lock starts with inclusion of unbonding transaction, but staker
+	// allows withdrawal only after unbonding transaction is confirmed
+	spendableAfterBlocks := uint32(preview.UnbondingTimeBlocks)
+	if spendableAfterBlocks < preview.ConfirmationBlocks {
+		spendableAfterBlocks = preview.ConfirmationBlocks
+	}
+
+	preview.SpendableEstimate = preview.CovenantSigningEstimate + app.blocksDuration(spendableAfterBlocks)
+
+	return preview, nil
+}
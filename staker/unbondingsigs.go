@@ -0,0 +1,285 @@
+package staker
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	staking "github.com/babylonchain/babylon/btcstaking"
+	cl "github.com/babylonchain/btc-staker/babylonclient"
+	"github.com/babylonchain/btc-staker/stakerdb"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/sirupsen/logrus"
+)
+
+// ErrInvalidUnbondingSignature is wrapped by all errors returned when signature
+// over unbonding transaction does not allow to spend staking output
+var ErrInvalidUnbondingSignature = errors.New("invalid unbonding transaction signature")
+
+// ErrInvalidCovenantUnbondingSignature is returned when signature of covenant
+// member over unbonding transaction is invalid, or when it is made by key which is
+// not part of covenant committee controlling staking output
+type ErrInvalidCovenantUnbondingSignature struct {
+	CovenantPk string
+	// hex encoded signature, empty if signature is missing
+	Signature string
+	Reason    string
+}
+
+func (e *ErrInvalidCovenantUnbondingSignature) Error() string {
+	return fmt.Sprintf("covenant signature of key %s over unbonding transaction is invalid: %s", e.CovenantPk, e.Reason)
+}
+
+func (e *ErrInvalidCovenantUnbondingSignature) Unwrap() error { return ErrInvalidUnbondingSignature }
+
+// ErrInvalidStakerUnbondingSignature is returned when signature of staker over
+// unbonding transaction is invalid
+type ErrInvalidStakerUnbondingSignature struct {
+	StakerPk string
+	Reason   string
+}
+
+func (e *ErrInvalidStakerUnbondingSignature) Error() string {
+	return fmt.Sprintf("staker signature of key %s over unbonding transaction is invalid: %s", e.StakerPk, e.Reason)
+}
+
+func (e *ErrInvalidStakerUnbondingSignature) Unwrap() error { return ErrInvalidUnbondingSignature }
+
+// unbondingPathScript returns staking output of stored transaction and script of
+// its unbonding path
+func unbondingPathScript(
+	storedTx *stakerdb.StoredTransaction,
+	stakerPk *btcec.PublicKey,
+	covenantPks []*btcec.PublicKey,
+	covenantQuorum uint32,
+	net *chaincfg.Params,
+) (*wire.TxOut, []byte, error) {
+	stakingOutput := storedTx.StakingTx.TxOut[storedTx.StakingOutputIndex]
+
+	stakingInfo, err := staking.BuildStakingInfo(
+		stakerPk,
+		storedTx.FinalityProvidersBtcPks,
+		covenantPks,
+		covenantQuorum,
+		storedTx.StakingTime,
+		btcutil.Amount(stakingOutput.Value),
+		net,
+	)
+
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to build staking info: %w", err)
+	}
+
+	unbondingPathInfo, err := stakingInfo.UnbondingPathSpendInfo()
+
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to build unbonding path info: %w", err)
+	}
+
+	return stakingOutput, unbondingPathInfo.RevealedLeaf.Script, nil
+}
+
+func verifyUnbondingSig(
+	unbondingTx *wire.MsgTx,
+	stakingOutput *wire.TxOut,
+	script []byte,
+	pk *btcec.PublicKey,
+	sig *schnorr.Signature,
+) error {
+	if sig == nil {
+		return fmt.Errorf("signature is missing")
+	}
+
+	return staking.VerifyTransactionSigWithOutputData(
+		unbondingTx,
+		stakingOutput.PkScript,
+		stakingOutput.Value,
+		script,
+		pk,
+		sig.Serialize(),
+	)
+}
+
+func sigToHex(sig *schnorr.Signature) string {
+	if sig == nil {
+		return ""
+	}
+
+	return hex.EncodeToString(sig.Serialize())
+}
+
+func isCovenantMember(pk *btcec.PublicKey, covenantPks []*btcec.PublicKey) bool {
+	for _, covenantPk := range covenantPks {
+		if xOnlyKey(covenantPk) == xOnlyKey(pk) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// verifyCovenantUnbondingSignatures checks covenant signatures over unbonding
+// transaction against sighash of unbonding path of staking output. It returns
+// valid signatures and errors describing the invalid ones.
+func verifyCovenantUnbondingSignatures(
+	storedTx *stakerdb.StoredTransaction,
+	unbondingTx *wire.MsgTx,
+	stakerPk *btcec.PublicKey,
+	covenantPks []*btcec.PublicKey,
+	covenantQuorum uint32,
+	sigs []cl.CovenantSignatureInfo,
+	net *chaincfg.Params,
+) ([]cl.CovenantSignatureInfo, []*ErrInvalidCovenantUnbondingSignature, error) {
+	stakingOutput, script, err := unbondingPathScript(storedTx, stakerPk, covenantPks, covenantQuorum, net)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var (
+		valid   []cl.CovenantSignatureInfo
+		invalid []*ErrInvalidCovenantUnbondingSignature
+	)
+
+	for _, sig := range sigs {
+		if sig.PubKey == nil {
+			invalid = append(invalid, &ErrInvalidCovenantUnbondingSignature{
+				CovenantPk: "<nil>",
+				Reason:     "signature has no public key",
+			})
+			continue
+		}
+
+		if !isCovenantMember(sig.PubKey, covenantPks) {
+			invalid = append(invalid, &ErrInvalidCovenantUnbondingSignature{
+				CovenantPk: xOnlyKey(sig.PubKey),
+				Signature:  sigToHex(sig.Signature),
+				Reason:     "key is not a member of covenant committee controlling staking output",
+			})
+			continue
+		}
+
+		if err := verifyUnbondingSig(unbondingTx, stakingOutput, script, sig.PubKey, sig.Signature); err != nil {
+			invalid = append(invalid, &ErrInvalidCovenantUnbondingSignature{
+				CovenantPk: xOnlyKey(sig.PubKey),
+				Signature:  sigToHex(sig.Signature),
+				Reason:     err.Error(),
+			})
+			continue
+		}
+
+		valid = append(valid, sig)
+	}
+
+	return valid, invalid, nil
+}
+
+// stakingTxStakerPubKey returns btc public key of staker of the transaction, for
+// watched transactions it is the key provided when transaction was watched
+func (app *StakerApp) stakingTxStakerPubKey(storedTx *stakerdb.StoredTransaction) (*btcec.PublicKey, error) {
+	if storedTx.Watched {
+		stakingTxHash := storedTx.StakingTx.TxHash()
+		watchedData, err := app.txTracker.GetWatchedTransactionData(&stakingTxHash)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return watchedData.StakerBtcPubKey, nil
+	}
+
+	stakerAddress, err := btcutil.DecodeAddress(storedTx.StakerAddress, app.network)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return app.wc.AddressPublicKey(stakerAddress)
+}
+
+// validCovenantUnbondingSignatures verifies covenant signatures received from
+// babylon before they are stored. Every newly seen invalid signature is logged and
+// reported as critical error. It returns nil if there is not enough valid
+// signatures to reach covenant quorum.
+func (app *StakerApp) validCovenantUnbondingSignatures(
+	stakingTxHash *chainhash.Hash,
+	sigs []cl.CovenantSignatureInfo,
+	params *cl.StakingParams,
+	reportedInvalidSigs map[string]struct{},
+) ([]cl.CovenantSignatureInfo, error) {
+	storedTx, err := app.txTracker.GetTransaction(stakingTxHash)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if storedTx.UnbondingTxData == nil || storedTx.UnbondingTxData.UnbondingTx == nil {
+		return nil, fmt.Errorf("unbonding transaction of staking transaction %s is not known", stakingTxHash)
+	}
+
+	stakerPk, err := app.stakingTxStakerPubKey(storedTx)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve staker key: %w", err)
+	}
+
+	covenantPks, covenantQuorum, err := app.stakingTxCovenantCommittee(stakingTxHash, storedTx, stakerPk, params)
+
+	if err != nil {
+		return nil, err
+	}
+
+	valid, invalid, err := verifyCovenantUnbondingSignatures(
+		storedTx,
+		storedTx.UnbondingTxData.UnbondingTx,
+		stakerPk,
+		covenantPks,
+		covenantQuorum,
+		sigs,
+		app.network,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, sigErr := range invalid {
+		sigId := sigErr.CovenantPk + sigErr.Signature
+
+		if _, reported := reportedInvalidSigs[sigId]; reported {
+			continue
+		}
+
+		reportedInvalidSigs[sigId] = struct{}{}
+
+		app.logger.WithFields(logrus.Fields{
+			"stakingTxHash": stakingTxHash,
+			"covenantPk":    sigErr.CovenantPk,
+			"signature":     sigErr.Signature,
+			"reason":        sigErr.Reason,
+		}).Error("Babylon returned invalid covenant signature over unbonding transaction")
+
+		app.reportCriticialError(
+			*stakingTxHash,
+			sigErr,
+			"Invalid covenant unbonding signature received from babylon. Waiting for valid signatures",
+		)
+	}
+
+	if len(valid) < int(covenantQuorum) {
+		app.logger.WithFields(logrus.Fields{
+			"stakingTxHash":   stakingTxHash,
+			"validSignatures": len(valid),
+			"required":        covenantQuorum,
+		}).Debug("Received not enough valid covenant unbonding signatures on babylon")
+
+		return nil, nil
+	}
+
+	return valid, nil
+}
@@ -0,0 +1,104 @@
+package staker
+
+import (
+	"testing"
+
+	staking "github.com/babylonchain/babylon/btcstaking"
+	cl "github.com/babylonchain/btc-staker/babylonclient"
+	"github.com/babylonchain/btc-staker/proto"
+	"github.com/babylonchain/btc-staker/stakerdb"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/stretchr/testify/require"
+)
+
+func genPrivKey(t *testing.T) *btcec.PrivateKey {
+	privKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+	return privKey
+}
+
+func TestVerifyCovenantUnbondingSignatures(t *testing.T) {
+	net := &chaincfg.SimNetParams
+	stakerPriv := genPrivKey(t)
+	covenantPrivs := []*btcec.PrivateKey{genPrivKey(t), genPrivKey(t), genPrivKey(t)}
+	covenantPks := make([]*btcec.PublicKey, len(covenantPrivs))
+	for i, priv := range covenantPrivs {
+		covenantPks[i] = priv.PubKey()
+	}
+	fpPks := []*btcec.PublicKey{genPubKey(t)}
+
+	stakingInfo, err := staking.BuildStakingInfo(stakerPriv.PubKey(), fpPks, covenantPks, 2, 100, 100000, net)
+	require.NoError(t, err)
+
+	stakingTx := wire.NewMsgTx(2)
+	stakingTx.AddTxOut(stakingInfo.StakingOutput)
+	stakingTxHash := stakingTx.TxHash()
+
+	newUnbondingTx := func(value int64) *wire.MsgTx {
+		tx := wire.NewMsgTx(2)
+		tx.AddTxIn(wire.NewTxIn(wire.NewOutPoint(&stakingTxHash, 0), nil, nil))
+		tx.AddTxOut(wire.NewTxOut(value, stakingInfo.StakingOutput.PkScript))
+		return tx
+	}
+	unbondingTx := newUnbondingTx(90000)
+
+	unbondingPathInfo, err := stakingInfo.UnbondingPathSpendInfo()
+	require.NoError(t, err)
+
+	sign := func(tx *wire.MsgTx, priv *btcec.PrivateKey) *schnorr.Signature {
+		sig, err := staking.SignTxWithOneScriptSpendInputFromScript(
+			tx, stakingInfo.StakingOutput, priv, unbondingPathInfo.RevealedLeaf.Script,
+		)
+		require.NoError(t, err)
+		return sig
+	}
+
+	storedTx := &stakerdb.StoredTransaction{
+		StakingTx:               stakingTx,
+		StakingOutputIndex:      0,
+		StakingTime:             100,
+		FinalityProvidersBtcPks: fpPks,
+		State:                   proto.TransactionState_DELEGATION_ACTIVE,
+	}
+
+	outsider := genPrivKey(t)
+	sigs := []cl.CovenantSignatureInfo{
+		{PubKey: covenantPks[0], Signature: sign(unbondingTx, covenantPrivs[0])},
+		// signature over different transaction
+		{PubKey: covenantPks[1], Signature: sign(newUnbondingTx(80000), covenantPrivs[1])},
+		{PubKey: outsider.PubKey(), Signature: sign(unbondingTx, outsider)},
+	}
+
+	valid, invalid, err := verifyCovenantUnbondingSignatures(
+		storedTx, unbondingTx, stakerPriv.PubKey(), covenantPks, 2, sigs, net,
+	)
+	require.NoError(t, err)
+	require.Len(t, valid, 1)
+	require.True(t, valid[0].PubKey.IsEqual(covenantPks[0]))
+	require.Len(t, invalid, 2)
+	require.Equal(t, xOnlyKey(covenantPks[1]), invalid[0].CovenantPk)
+	require.Equal(t, xOnlyKey(outsider.PubKey()), invalid[1].CovenantPk)
+	require.ErrorIs(t, invalid[0], ErrInvalidUnbondingSignature)
+
+	// invalid stored signature is named when building witness
+	unbondingData := &stakerdb.UnbondingStoreData{
+		UnbondingTx: unbondingTx,
+		CovenantSignatures: []stakerdb.PubKeySigPair{
+			{PubKey: covenantPks[0], Signature: sigs[0].Signature},
+			{PubKey: covenantPks[1], Signature: sigs[1].Signature},
+		},
+	}
+
+	_, err = createWitnessToSendUnbondingTx(stakerPriv, storedTx, unbondingData, covenantPks, 2, net)
+	var covenantErr *ErrInvalidCovenantUnbondingSignature
+	require.ErrorAs(t, err, &covenantErr)
+	require.Equal(t, xOnlyKey(covenantPks[1]), covenantErr.CovenantPk)
+
+	unbondingData.CovenantSignatures[1].Signature = sign(unbondingTx, covenantPrivs[1])
+	witness, err := createWitnessToSendUnbondingTx(stakerPriv, storedTx, unbondingData, covenantPks, 2, net)
+	require.NoError(t, err)
+	require.NotEmpty(t, witness)
+}
@@ -0,0 +1,29 @@
+package staker
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	cl "github.com/babylonchain/btc-staker/babylonclient"
+)
+
+// ErrUnbondingTimeOutOfRange is returned when unbonding time required by babylon
+// cannot be encoded as relative time lock of unbonding script
+var ErrUnbondingTimeOutOfRange = errors.New("unbonding time out of range")
+
+// unbondingTimeLock returns time lock of unbonding output, and of slashing
+// transaction change output. Babylon requires it to be strictly larger than its
+// min unbonding time.
+func unbondingTimeLock(params *cl.StakingParams) (uint16, error) {
+	if params.MinUnbondingTime >= math.MaxUint16 {
+		return 0, fmt.Errorf(
+			"%w: babylon min unbonding time %d blocks leaves no valid time lock, max time lock is %d blocks",
+			ErrUnbondingTimeOutOfRange,
+			params.MinUnbondingTime,
+			math.MaxUint16,
+		)
+	}
+
+	return params.MinUnbondingTime + 1, nil
+}
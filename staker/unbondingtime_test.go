@@ -0,0 +1,23 @@
+package staker
+
+import (
+	"math"
+	"testing"
+
+	cl "github.com/babylonchain/btc-staker/babylonclient"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnbondingTimeLock(t *testing.T) {
+	timeLock, err := unbondingTimeLock(&cl.StakingParams{MinUnbondingTime: 100})
+	require.NoError(t, err)
+	require.Equal(t, uint16(101), timeLock)
+
+	timeLock, err = unbondingTimeLock(&cl.StakingParams{MinUnbondingTime: math.MaxUint16 - 1})
+	require.NoError(t, err)
+	require.Equal(t, uint16(math.MaxUint16), timeLock)
+
+	// time lock would not fit unbonding script instead of wrapping around to zero
+	_, err = unbondingTimeLock(&cl.StakingParams{MinUnbondingTime: math.MaxUint16})
+	require.ErrorIs(t, err, ErrUnbondingTimeOutOfRange)
+}
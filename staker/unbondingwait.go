@@ -0,0 +1,203 @@
+package staker
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/babylonchain/btc-staker/proto"
+	"github.com/babylonchain/btc-staker/stakerdb"
+	"github.com/babylonchain/btc-staker/utils"
+	"github.com/babylonchain/btc-staker/walletcontroller"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	// ErrUnbondingSigsNotTimedOut delegation is not in UNBONDING_SIGNATURES_TIMEOUT
+	// state, or waiting for its covenant unbonding signatures was not aborted
+	ErrUnbondingSigsNotTimedOut = errors.New("delegation did not time out waiting for covenant unbonding signatures")
+	// ErrUnbondingTxSent unbonding transaction of delegation was already sent to
+	// btc, so waiting for its covenant signatures cannot be aborted
+	ErrUnbondingTxSent = errors.New("unbonding transaction was sent to btc")
+)
+
+// unbondingWaits tracks goroutines waiting for covenant unbonding signatures of
+// delegations, so that every delegation is waited for only once and operator can
+// control the wait. Receiving from command channel of the wait restarts its
+// timeout, and closing it stops the wait.
+type unbondingWaits struct {
+	mu    sync.Mutex
+	waits map[chainhash.Hash]chan struct{}
+}
+
+func newUnbondingWaits() *unbondingWaits {
+	return &unbondingWaits{
+		waits: make(map[chainhash.Hash]chan struct{}),
+	}
+}
+
+// add registers wait for given delegation. False is returned if delegation is
+// already waited for.
+func (w *unbondingWaits) add(stakingTxHash chainhash.Hash) (chan struct{}, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if _, ok := w.waits[stakingTxHash]; ok {
+		return nil, false
+	}
+
+	commands := make(chan struct{}, 1)
+	w.waits[stakingTxHash] = commands
+
+	return commands, true
+}
+
+// remove unregisters finished wait. Wait registered again in the meantime is
+// kept.
+func (w *unbondingWaits) remove(stakingTxHash chainhash.Hash, commands chan struct{}) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.waits[stakingTxHash] == commands {
+		delete(w.waits, stakingTxHash)
+	}
+}
+
+// retry restarts timeout of the wait for given delegation. False is returned if
+// delegation is not waited for.
+func (w *unbondingWaits) retry(stakingTxHash chainhash.Hash) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	commands, ok := w.waits[stakingTxHash]
+
+	if !ok {
+		return false
+	}
+
+	select {
+	case commands <- struct{}{}:
+	default:
+		// retry is already pending
+	}
+
+	return true
+}
+
+// abort stops the wait for given delegation, if there is one
+func (w *unbondingWaits) abort(stakingTxHash chainhash.Hash) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if commands, ok := w.waits[stakingTxHash]; ok {
+		close(commands)
+		delete(w.waits, stakingTxHash)
+	}
+}
+
+// unbondingSigsCheckTicker returns ticker for checking covenant unbonding
+// signatures on babylon. Delegations which timed out waiting for signatures are
+// checked less often.
+func (app *StakerApp) unbondingSigsCheckTicker(timedOut bool) utils.Ticker {
+	if timedOut {
+		return app.clock.NewTicker(app.config.StakerConfig.UnbondingSigsSlowInterval)
+	}
+
+	return app.clock.NewTicker(app.config.StakerConfig.UnbondingTxCheckInterval)
+}
+
+// RetryUnbondingSignatures resumes frequent checks of covenant unbonding
+// signatures of delegation, which timed out waiting for them or which waiting was
+// aborted. Delegation is moved back to SENT_TO_BABYLON state and its timeout is
+// restarted.
+func (app *StakerApp) RetryUnbondingSignatures(stakingTxHash *chainhash.Hash) error {
+	// check we are not shutting down
+	select {
+	case <-app.quit:
+		return fmt.Errorf("staker app is shutting down")
+
+	default:
+	}
+
+	if err := app.txTracker.ResumeUnbondingWait(stakingTxHash); err != nil {
+		if errors.Is(err, stakerdb.ErrInvalidTransactionState) {
+			return fmt.Errorf("%w: %s", ErrUnbondingSigsNotTimedOut, err)
+		}
+
+		return err
+	}
+
+	if !app.unbondingWaits.retry(*stakingTxHash) {
+		app.wg.Go(unbondingSigsGoroutine, func() {
+			app.checkForUnbondingTxSignaturesOnBabylon(stakingTxHash, false)
+		})
+	}
+
+	app.logger.WithFields(logrus.Fields{
+		"stakingTxHash": stakingTxHash,
+	}).Info("Resumed waiting for covenant unbonding signatures")
+
+	return nil
+}
+
+// AbortUnbonding stops waiting for covenant unbonding signatures of delegation,
+// which timed out waiting for them. Delegation is moved back to SENT_TO_BABYLON
+// state, so that staked funds can be withdrawn once staking timelock expires.
+// Waiting is only aborted if unbonding transaction was never sent to btc.
+func (app *StakerApp) AbortUnbonding(stakingTxHash *chainhash.Hash) error {
+	tx, err := app.txTracker.GetTransaction(stakingTxHash)
+
+	if err != nil {
+		return err
+	}
+
+	if tx.State != proto.TransactionState_UNBONDING_SIGNATURES_TIMEOUT {
+		return fmt.Errorf("%w: transaction is in state %s", ErrUnbondingSigsNotTimedOut, tx.State)
+	}
+
+	unbondingData := tx.UnbondingTxData
+
+	if unbondingData == nil || unbondingData.UnbondingTx == nil {
+		return fmt.Errorf("cannot abort unbonding of transaction %s: %w", stakingTxHash, stakerdb.ErrUnbondingDataNotFound)
+	}
+
+	unbondingTxHash := unbondingData.UnbondingTx.TxHash()
+
+	if !unbondingData.UnbondingTxSentAt.IsZero() {
+		return fmt.Errorf("%w: %s", ErrUnbondingTxSent, unbondingTxHash)
+	}
+
+	if int(unbondingData.UnbondingOutputIndex) >= len(unbondingData.UnbondingTx.TxOut) {
+		return fmt.Errorf("invalid unbonding output index %d of transaction %s", unbondingData.UnbondingOutputIndex, stakingTxHash)
+	}
+
+	_, status, err := app.wc.TxDetails(
+		&unbondingTxHash,
+		unbondingData.UnbondingTx.TxOut[unbondingData.UnbondingOutputIndex].PkScript,
+	)
+
+	if err != nil {
+		return fmt.Errorf("cannot check whether unbonding transaction %s was sent: %w", unbondingTxHash, err)
+	}
+
+	if status != walletcontroller.TxNotFound {
+		return fmt.Errorf("%w: %s", ErrUnbondingTxSent, unbondingTxHash)
+	}
+
+	if err := app.txTracker.AbortUnbondingWait(stakingTxHash); err != nil {
+		if errors.Is(err, stakerdb.ErrInvalidTransactionState) {
+			return fmt.Errorf("%w: %s", ErrUnbondingSigsNotTimedOut, err)
+		}
+
+		return err
+	}
+
+	app.unbondingWaits.abort(*stakingTxHash)
+
+	app.logger.WithFields(logrus.Fields{
+		"stakingTxHash": stakingTxHash,
+	}).Warn("Aborted waiting for covenant unbonding signatures. Staked funds can be withdrawn once staking timelock expires")
+
+	return nil
+}
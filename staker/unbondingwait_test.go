@@ -0,0 +1,61 @@
+package staker
+
+import (
+	"testing"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnbondingWaitsRegistersDelegationOnce(t *testing.T) {
+	waits := newUnbondingWaits()
+	txHash := chainhash.HashH([]byte("staking tx"))
+
+	commands, added := waits.add(txHash)
+	require.True(t, added)
+
+	_, added = waits.add(txHash)
+	require.False(t, added)
+
+	waits.remove(txHash, commands)
+
+	_, added = waits.add(txHash)
+	require.True(t, added)
+}
+
+func TestUnbondingWaitsRetry(t *testing.T) {
+	waits := newUnbondingWaits()
+	txHash := chainhash.HashH([]byte("staking tx"))
+
+	require.False(t, waits.retry(txHash))
+
+	commands, _ := waits.add(txHash)
+
+	// pending retries are merged
+	require.True(t, waits.retry(txHash))
+	require.True(t, waits.retry(txHash))
+	require.Len(t, commands, 1)
+
+	_, ok := <-commands
+	require.True(t, ok)
+}
+
+func TestUnbondingWaitsAbort(t *testing.T) {
+	waits := newUnbondingWaits()
+	txHash := chainhash.HashH([]byte("staking tx"))
+
+	commands, _ := waits.add(txHash)
+	waits.abort(txHash)
+
+	_, ok := <-commands
+	require.False(t, ok)
+
+	// aborted delegation can be waited for again, and finishing aborted wait
+	// does not remove the new one
+	newCommands, added := waits.add(txHash)
+	require.True(t, added)
+
+	waits.remove(txHash, commands)
+	require.True(t, waits.retry(txHash))
+	require.Len(t, newCommands, 1)
+}
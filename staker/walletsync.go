@@ -0,0 +1,67 @@
+package staker
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/babylonchain/btc-staker/walletcontroller"
+)
+
+// ErrWalletNotSynced is returned by requests which would move wallet funds while
+// wallet is still syncing the chain. Until wallet is synced, it can select already
+// spent outputs and report transactions included in chain as not found.
+var ErrWalletNotSynced = errors.New("btc wallet is not synced with btc chain")
+
+// WalletSyncStatus is sync status of the wallet compared against best block
+// known to staker
+type WalletSyncStatus struct {
+	Wallet              *walletcontroller.SyncStatus
+	NodeBestBlockHeight uint32
+	// nil if wallet is synced
+	NotSyncedErr error
+}
+
+// WalletSyncStatus queries wallet for its sync status
+func (app *StakerApp) WalletSyncStatus() (*WalletSyncStatus, error) {
+	status, err := app.wc.SyncStatus()
+
+	if err != nil {
+		return nil, err
+	}
+
+	nodeHeight := app.currentBestBlockHeight.Load()
+
+	return &WalletSyncStatus{
+		Wallet:              status,
+		NodeBestBlockHeight: nodeHeight,
+		NotSyncedErr:        app.walletSyncError(status, nodeHeight),
+	}, nil
+}
+
+// checkWalletSynced returns ErrWalletNotSynced if wallet is syncing the chain or
+// lags behind best block known to staker more than allowed by config
+func (app *StakerApp) checkWalletSynced() error {
+	status, err := app.WalletSyncStatus()
+
+	if err != nil {
+		return fmt.Errorf("cannot check wallet sync status: %w", err)
+	}
+
+	return status.NotSyncedErr
+}
+
+func (app *StakerApp) walletSyncError(status *walletcontroller.SyncStatus, nodeHeight uint32) error {
+	switch {
+	case status.InitialBlockDownload:
+		return fmt.Errorf("%w: initial block download in progress, wallet height %d, node height %d",
+			ErrWalletNotSynced, status.BestBlockHeight, nodeHeight)
+	case status.Rescanning:
+		return fmt.Errorf("%w: wallet is rescanning the chain, wallet height %d, node height %d",
+			ErrWalletNotSynced, status.BestBlockHeight, nodeHeight)
+	case nodeHeight > status.BestBlockHeight+app.config.StakerConfig.MaxWalletSyncLag:
+		return fmt.Errorf("%w: wallet height %d lags behind node height %d more than %d blocks",
+			ErrWalletNotSynced, status.BestBlockHeight, nodeHeight, app.config.StakerConfig.MaxWalletSyncLag)
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,41 @@
+package staker
+
+import (
+	"testing"
+
+	scfg "github.com/babylonchain/btc-staker/stakercfg"
+	"github.com/babylonchain/btc-staker/walletcontroller"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckWalletSynced(t *testing.T) {
+	cfg := scfg.DefaultConfig()
+	cfg.StakerConfig.MaxWalletSyncLag = 2
+
+	wallet := &testWallet{}
+	app := &StakerApp{config: &cfg, wc: wallet}
+	app.currentBestBlockHeight.Store(100)
+
+	wallet.syncStatus = &walletcontroller.SyncStatus{BestBlockHeight: 100}
+	require.NoError(t, app.checkWalletSynced())
+
+	// wallet can lag by configured number of blocks
+	wallet.syncStatus = &walletcontroller.SyncStatus{BestBlockHeight: 98}
+	require.NoError(t, app.checkWalletSynced())
+
+	wallet.syncStatus = &walletcontroller.SyncStatus{BestBlockHeight: 97}
+	err := app.checkWalletSynced()
+	require.ErrorIs(t, err, ErrWalletNotSynced)
+	require.Contains(t, err.Error(), "97")
+	require.Contains(t, err.Error(), "100")
+
+	// wallet ahead of staker is synced
+	wallet.syncStatus = &walletcontroller.SyncStatus{BestBlockHeight: 101}
+	require.NoError(t, app.checkWalletSynced())
+
+	wallet.syncStatus = &walletcontroller.SyncStatus{BestBlockHeight: 100, InitialBlockDownload: true}
+	require.ErrorIs(t, app.checkWalletSynced(), ErrWalletNotSynced)
+
+	wallet.syncStatus = &walletcontroller.SyncStatus{BestBlockHeight: 100, Rescanning: true}
+	require.ErrorIs(t, app.checkWalletSynced(), ErrWalletNotSynced)
+}
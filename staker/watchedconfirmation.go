@@ -0,0 +1,63 @@
+package staker
+
+import (
+	"github.com/babylonchain/btc-staker/utils"
+	"github.com/babylonchain/btc-staker/walletcontroller"
+	notifier "github.com/lightningnetwork/lnd/chainntnfs"
+)
+
+// watchedTxConfirmationStart decides how confirmation of watched staking
+// transaction is awaited, based on where the transaction was found on btc when
+// watch was requested. It returns whether transaction is already confirmed with
+// required depth, and otherwise height hint from which notifier looks for it.
+// Transactions which are not found start from inclusionHeightHint if provided,
+// as some backends can't look up transactions by hash.
+func watchedTxConfirmationStart(
+	status walletcontroller.TxStatus,
+	details *notifier.TxConfirmation,
+	inclusionHeightHint *uint32,
+	bestBlockHeight uint32,
+	requiredDepth uint32,
+) (heightHint uint32, confirmed bool) {
+	if status == walletcontroller.TxInChain && details != nil {
+		// wallet can be synced further than notifier, in which case transaction
+		// is awaited from its block until notifier catches up
+		if bestBlockHeight >= details.BlockHeight && bestBlockHeight-details.BlockHeight >= requiredDepth {
+			return details.BlockHeight, true
+		}
+
+		return details.BlockHeight, false
+	}
+
+	if inclusionHeightHint != nil {
+		return *inclusionHeightHint, false
+	}
+
+	return bestBlockHeight, false
+}
+
+// emitWatchedTxConfirmed reports watched staking transaction, which is already
+// confirmed with required depth, as confirmed without waiting for notifier
+func (app *StakerApp) emitWatchedTxConfirmed(ev *stakingRequestedEvent) {
+	details := ev.watchTxData.btcTxInfo
+
+	confirmedEv := &stakingTxBtcConfirmedEvent{
+		stakingTxHash: ev.stakingTxHash,
+		txIndex:       details.TxIndex,
+		blockDepth:    ev.requiredDepthOnBtcChain,
+		blockHash:     *details.BlockHash,
+		blockHeight:   details.BlockHeight,
+		tx:            ev.stakingTx,
+		inlusionBlock: details.Block,
+	}
+
+	// pushed from separate goroutine, as it is called from main event loop
+	// which receives the event
+	app.wg.Go(stakingTxConfGoroutine, func() {
+		utils.PushOrQuit[*stakingTxBtcConfirmedEvent](
+			app.stakingTxBtcConfirmedEvChan,
+			confirmedEv,
+			app.quit,
+		)
+	})
+}
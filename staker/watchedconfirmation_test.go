@@ -0,0 +1,51 @@
+package staker
+
+import (
+	"testing"
+
+	"github.com/babylonchain/btc-staker/walletcontroller"
+	notifier "github.com/lightningnetwork/lnd/chainntnfs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatchedTxConfirmedWithRequiredDepth(t *testing.T) {
+	details := &notifier.TxConfirmation{BlockHeight: 100, TxIndex: 3}
+
+	// transaction confirmed long ago is not awaited from current tip
+	heightHint, confirmed := watchedTxConfirmationStart(walletcontroller.TxInChain, details, nil, 600, 6)
+	require.True(t, confirmed)
+	require.Equal(t, uint32(100), heightHint)
+
+	// exactly required depth
+	_, confirmed = watchedTxConfirmationStart(walletcontroller.TxInChain, details, nil, 106, 6)
+	require.True(t, confirmed)
+}
+
+func TestWatchedTxConfirmedBelowRequiredDepth(t *testing.T) {
+	details := &notifier.TxConfirmation{BlockHeight: 100, TxIndex: 3}
+
+	heightHint, confirmed := watchedTxConfirmationStart(walletcontroller.TxInChain, details, nil, 103, 6)
+	require.False(t, confirmed)
+	require.Equal(t, uint32(100), heightHint)
+
+	// wallet synced further than notifier
+	heightHint, confirmed = watchedTxConfirmationStart(walletcontroller.TxInChain, details, nil, 90, 6)
+	require.False(t, confirmed)
+	require.Equal(t, uint32(100), heightHint)
+}
+
+func TestWatchedTxNotFoundUsesInclusionHeightHint(t *testing.T) {
+	hint := uint32(50)
+
+	heightHint, confirmed := watchedTxConfirmationStart(walletcontroller.TxNotFound, nil, &hint, 600, 6)
+	require.False(t, confirmed)
+	require.Equal(t, hint, heightHint)
+
+	heightHint, confirmed = watchedTxConfirmationStart(walletcontroller.TxNotFound, nil, nil, 600, 6)
+	require.False(t, confirmed)
+	require.Equal(t, uint32(600), heightHint)
+
+	heightHint, confirmed = watchedTxConfirmationStart(walletcontroller.TxInMemPool, nil, nil, 600, 6)
+	require.False(t, confirmed)
+	require.Equal(t, uint32(600), heightHint)
+}
@@ -0,0 +1,44 @@
+package staker
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/babylonchain/btc-staker/proto"
+	"github.com/babylonchain/btc-staker/stakerdb"
+)
+
+// ErrWatchedTxNotSignable staker key of watched transaction is not controlled by
+// the wallet, so staker cannot sign transactions spending its outputs
+var ErrWatchedTxNotSignable = errors.New("staker cannot sign for watched transaction")
+
+// checkSignable returns ErrWatchedTxNotSignable if transaction is watched. It must
+// be checked before staker key is retrieved from the wallet, as watched
+// transactions are processed by the same recovery paths as owned ones.
+func checkSignable(tx *stakerdb.StoredTransaction) error {
+	if tx.Watched {
+		return fmt.Errorf("%w %s", ErrWatchedTxNotSignable, tx.StakingTx.TxHash())
+	}
+
+	return nil
+}
+
+// WatchedTxExternalAction returns action which owner of watched transaction must
+// take, because staker cannot sign for it. Empty if transaction is not watched,
+// or staker can progress it without staker key.
+func WatchedTxExternalAction(tx *stakerdb.StoredTransaction) string {
+	if !tx.Watched {
+		return ""
+	}
+
+	switch tx.State {
+	case proto.TransactionState_DELEGATION_ACTIVE:
+		return "staking output can only be unbonded or withdrawn by owner of staker key"
+	case proto.TransactionState_UNBONDING_CONFIRMED_ON_BTC:
+		return "unbonded funds can only be withdrawn by owner of staker key"
+	case proto.TransactionState_DELEGATION_EXPIRED:
+		return "staking output can only be withdrawn by owner of staker key"
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,236 @@
+package staker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/babylonchain/btc-staker/proto"
+	"github.com/babylonchain/btc-staker/stakerdb"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	notifier "github.com/lightningnetwork/lnd/chainntnfs"
+	"github.com/stretchr/testify/require"
+)
+
+// noSigningWallet fails the test if staker tries to retrieve staker key or to
+// sign with the wallet
+type noSigningWallet struct {
+	*testWallet
+	t testing.TB
+}
+
+func (w *noSigningWallet) UnlockWallet(_ context.Context, _ int64) error {
+	w.t.Errorf("unexpected wallet unlock")
+	return ErrWatchedTxNotSignable
+}
+
+func (w *noSigningWallet) DumpPrivateKey(_ context.Context, _ btcutil.Address) (*btcec.PrivateKey, error) {
+	w.t.Errorf("unexpected private key dump")
+	return nil, ErrWatchedTxNotSignable
+}
+
+func (w *noSigningWallet) SignRawTransaction(_ context.Context, _ *wire.MsgTx) (*wire.MsgTx, bool, error) {
+	w.t.Errorf("unexpected transaction signing")
+	return nil, false, ErrWatchedTxNotSignable
+}
+
+func (w *noSigningWallet) CreateAndSignTx(
+	_ context.Context,
+	_ []*wire.TxOut,
+	_ btcutil.Amount,
+	_ btcutil.Address,
+) (*wire.MsgTx, error) {
+	w.t.Errorf("unexpected transaction signing")
+	return nil, ErrWatchedTxNotSignable
+}
+
+// addWatchedTransaction adds watched transaction, which staker key is not known
+// to the wallet
+func (d *testStakerDeps) addWatchedTransaction(t *testing.T) *chainhash.Hash {
+	stakerKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	stakerAddress, err := btcutil.NewAddressTaproot(
+		schnorr.SerializePubKey(stakerKey.PubKey()),
+		&d.config.ActiveNetParams,
+	)
+	require.NoError(t, err)
+
+	pkScript, err := txscript.PayToAddrScript(stakerAddress)
+	require.NoError(t, err)
+
+	stakingTx := wire.NewMsgTx(2)
+	// input unique to staker key, so that every call adds different transaction
+	stakingTx.AddTxIn(wire.NewTxIn(&wire.OutPoint{Hash: chainhash.HashH(stakerKey.Serialize())}, nil, nil))
+	stakingTx.AddTxOut(wire.NewTxOut(100000, pkScript))
+	stakingTxHash := stakingTx.TxHash()
+
+	spendingTx := func(value int64) *wire.MsgTx {
+		tx := wire.NewMsgTx(2)
+		tx.AddTxIn(wire.NewTxIn(wire.NewOutPoint(&stakingTxHash, 0), nil, nil))
+		tx.AddTxOut(wire.NewTxOut(value, pkScript))
+		return tx
+	}
+
+	sig, err := schnorr.Sign(stakerKey, chainhash.HashB([]byte("slashing")))
+	require.NoError(t, err)
+
+	err = d.tracker.AddWatchedTransaction(
+		stakingTx,
+		0,
+		100,
+		[]*btcec.PublicKey{genPubKey(t)},
+		&stakerdb.ProofOfPossession{
+			BabylonSigOverBtcPk:  []byte{1},
+			BtcSigOverBabylonSig: []byte{1},
+		},
+		stakerAddress,
+		0,
+		spendingTx(90000),
+		sig,
+		secp256k1.GenPrivKey().PubKey().(*secp256k1.PubKey),
+		stakerKey.PubKey(),
+		spendingTx(95000),
+		0,
+		spendingTx(85000),
+		sig,
+		50,
+		"",
+		0,
+	)
+	require.NoError(t, err)
+
+	return &stakingTxHash
+}
+
+func TestWatchedTransactionsRecoveryDoesNotSign(t *testing.T) {
+	confirmedAt := time.Unix(1700000000, 0)
+
+	setConfirmed := func(t *testing.T, d *testStakerDeps, txHash *chainhash.Hash) {
+		require.NoError(t, d.tracker.SetTxConfirmed(txHash, &chainhash.Hash{}, 10, confirmedAt))
+	}
+
+	setSentToBabylon := func(t *testing.T, d *testStakerDeps, txHash *chainhash.Hash) {
+		setConfirmed(t, d, txHash)
+
+		watchedData, err := d.tracker.GetWatchedTransactionData(txHash)
+		require.NoError(t, err)
+		require.NoError(t, d.tracker.SetTxSentToBabylon(txHash, watchedData.UnbondingTx, 0, 50, "", ""))
+	}
+
+	setActive := func(t *testing.T, d *testStakerDeps, txHash *chainhash.Hash) {
+		setSentToBabylon(t, d, txHash)
+		require.NoError(t, d.tracker.SetTxUnbondingSignaturesReceived(txHash, testCovenantSigs(t)))
+	}
+
+	tests := []struct {
+		name  string
+		setup func(t *testing.T, d *testStakerDeps, txHash *chainhash.Hash)
+		state proto.TransactionState
+		// action reported to owner of the transaction
+		externalAction bool
+	}{
+		{
+			name:  "sent to btc",
+			setup: func(t *testing.T, d *testStakerDeps, txHash *chainhash.Hash) {},
+			state: proto.TransactionState_SENT_TO_BTC,
+		},
+		{
+			name: "confirmed on btc",
+			setup: func(t *testing.T, d *testStakerDeps, txHash *chainhash.Hash) {
+				setConfirmed(t, d, txHash)
+				d.wallet.txsInChain[*txHash] = &notifier.TxConfirmation{
+					BlockHeight: 10,
+					Block:       wire.NewMsgBlock(&wire.BlockHeader{}),
+				}
+			},
+			state: proto.TransactionState_CONFIRMED_ON_BTC,
+		},
+		{
+			name:  "sent to babylon",
+			setup: setSentToBabylon,
+			state: proto.TransactionState_SENT_TO_BABYLON,
+		},
+		{
+			name: "unbonding signatures timeout",
+			setup: func(t *testing.T, d *testStakerDeps, txHash *chainhash.Hash) {
+				setSentToBabylon(t, d, txHash)
+				require.NoError(t, d.tracker.SetTxUnbondingSignaturesTimeout(txHash))
+			},
+			state: proto.TransactionState_UNBONDING_SIGNATURES_TIMEOUT,
+		},
+		{
+			name:           "delegation active",
+			setup:          setActive,
+			state:          proto.TransactionState_DELEGATION_ACTIVE,
+			externalAction: true,
+		},
+		{
+			name: "unbonding confirmed on btc",
+			setup: func(t *testing.T, d *testStakerDeps, txHash *chainhash.Hash) {
+				setActive(t, d, txHash)
+				require.NoError(t, d.tracker.SetTxUnbondingConfirmedOnBtc(txHash, &chainhash.Hash{}, 20, confirmedAt))
+			},
+			state:          proto.TransactionState_UNBONDING_CONFIRMED_ON_BTC,
+			externalAction: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			deps := newTestStakerDeps(t)
+			deps.wallet.txsInChain = make(map[chainhash.Hash]*notifier.TxConfirmation)
+			deps.wc = &noSigningWallet{testWallet: deps.wallet, t: t}
+
+			txHash := deps.addWatchedTransaction(t)
+			tc.setup(t, deps, txHash)
+
+			// intent which could only be stored by other tool, as staker does
+			// not sweep watched transactions
+			require.NoError(t, deps.tracker.SaveSweepIntent(&stakerdb.SweepIntent{
+				StakingTxHash:      *txHash,
+				DestinationAddress: "bcrt1q56ehztys752uzg7fzpear08l5mw8w2kxgz7644",
+			}))
+
+			app := deps.newApp(t)
+			// delegation sends are backlogged, so that they do not reach babylon
+			app.delegationBacklog = newDelegationBacklog(0)
+
+			require.NoError(t, app.checkTransactionsStatus())
+			app.sweepUnbondedFunds(10000)
+
+			intents, err := deps.tracker.GetSweepIntents()
+			require.NoError(t, err)
+			require.Empty(t, intents)
+
+			storedTx, err := deps.tracker.GetTransaction(txHash)
+			require.NoError(t, err)
+			require.Equal(t, tc.state, storedTx.State)
+			require.Equal(t, tc.externalAction, WatchedTxExternalAction(storedTx) != "")
+
+			stakerAddress, err := btcutil.DecodeAddress(storedTx.StakerAddress, app.network)
+			require.NoError(t, err)
+
+			// every path which retrieves staker key refuses watched transaction
+			_, _, err = app.spendStake(txHash, stakerAddress, nil, nil)
+			require.ErrorIs(t, err, ErrWatchedTxNotSignable)
+
+			_, err = app.renewStake(storedTx)
+			require.ErrorIs(t, err, ErrWatchedTxNotSignable)
+
+			_, err = app.buildOwnedDelegation(context.Background(), &sendDelegationRequest{txHash: *txHash}, stakerAddress, storedTx, nil)
+			require.ErrorIs(t, err, ErrWatchedTxNotSignable)
+
+			if storedTx.UnbondingTxData != nil {
+				_, _, err = app.sendUnbondingTxToBtc(context.Background(), txHash, stakerAddress, storedTx, storedTx.UnbondingTxData)
+				require.ErrorIs(t, err, ErrWatchedTxNotSignable)
+			}
+		})
+	}
+}
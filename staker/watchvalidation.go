@@ -0,0 +1,137 @@
+package staker
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	staking "github.com/babylonchain/babylon/btcstaking"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+)
+
+var (
+	// ErrSlashingTxWrongInput slashing transaction does not spend exactly the
+	// output it is supposed to slash
+	ErrSlashingTxWrongInput = errors.New("slashing tx does not spend slashed output")
+	// ErrSlashingTxWrongSlashingOutput slashing transaction does not pay to
+	// slashing address from babylon params
+	ErrSlashingTxWrongSlashingOutput = errors.New("slashing tx does not pay to slashing address")
+	// ErrSlashingTxFeeTooLow slashing transaction pays less fee than required by
+	// babylon params
+	ErrSlashingTxFeeTooLow = errors.New("slashing tx fee is too low")
+	// ErrInvalidSlashingPathScript staker key can't be parsed from slashing path
+	// script
+	ErrInvalidSlashingPathScript = errors.New("invalid slashing path script")
+	// ErrInvalidSlashingTxSig slashing transaction signature is not valid
+	// signature of staker over slashing transaction spending slashed output
+	ErrInvalidSlashingTxSig = errors.New("invalid slashing tx signature")
+)
+
+// stakerKeyFromSlashingPathScript parses staker key from slashing path script,
+// which starts with staker key followed by OP_CHECKSIGVERIFY
+func stakerKeyFromSlashingPathScript(script []byte) (*btcec.PublicKey, error) {
+	tokenizer := txscript.MakeScriptTokenizer(0, script)
+
+	if !tokenizer.Next() || tokenizer.Opcode() != txscript.OP_DATA_32 {
+		return nil, fmt.Errorf("%w: script does not start with x-only public key", ErrInvalidSlashingPathScript)
+	}
+
+	keyBytes := tokenizer.Data()
+
+	if !tokenizer.Next() || tokenizer.Opcode() != txscript.OP_CHECKSIGVERIFY {
+		return nil, fmt.Errorf("%w: staker key is not followed by OP_CHECKSIGVERIFY", ErrInvalidSlashingPathScript)
+	}
+
+	key, err := schnorr.ParsePubKey(keyBytes)
+
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidSlashingPathScript, err)
+	}
+
+	return key, nil
+}
+
+// checkWatchedSlashingTx validates slashing transaction and signature provided in
+// watch request against output it slashes, so that delegation which would be
+// rejected by babylon is refused before its staking transaction is tracked.
+// Signature is verified against staker key committed in slashing path script.
+func checkWatchedSlashingTx(
+	slashingTx *wire.MsgTx,
+	slashingTxSig *schnorr.Signature,
+	fundingTx *wire.MsgTx,
+	fundingOutputIdx uint32,
+	slashingPathScript []byte,
+	slashingAddress btcutil.Address,
+	minSlashingFee btcutil.Amount,
+) error {
+	if int(fundingOutputIdx) >= len(fundingTx.TxOut) {
+		return fmt.Errorf("%w: output index %d out of range", ErrSlashingTxWrongInput, fundingOutputIdx)
+	}
+
+	fundingOutput := fundingTx.TxOut[fundingOutputIdx]
+
+	if len(slashingTx.TxIn) != 1 {
+		return fmt.Errorf("%w: slashing tx must have exactly one input, it has %d", ErrSlashingTxWrongInput, len(slashingTx.TxIn))
+	}
+
+	fundingTxHash := fundingTx.TxHash()
+	expectedOutpoint := wire.NewOutPoint(&fundingTxHash, fundingOutputIdx)
+
+	if slashingTx.TxIn[0].PreviousOutPoint != *expectedOutpoint {
+		return fmt.Errorf(
+			"%w: slashing tx spends %s, expected %s",
+			ErrSlashingTxWrongInput,
+			slashingTx.TxIn[0].PreviousOutPoint,
+			expectedOutpoint,
+		)
+	}
+
+	if slashingAddress == nil {
+		return fmt.Errorf("%w: slashing address not provided in babylon params", ErrSlashingTxWrongSlashingOutput)
+	}
+
+	slashingPkScript, err := txscript.PayToAddrScript(slashingAddress)
+
+	if err != nil {
+		return fmt.Errorf("%w: unsupported slashing address %s: %s", ErrSlashingTxWrongSlashingOutput, slashingAddress, err)
+	}
+
+	if len(slashingTx.TxOut) == 0 || !bytes.Equal(slashingTx.TxOut[0].PkScript, slashingPkScript) {
+		return fmt.Errorf("%w: first output must pay to %s", ErrSlashingTxWrongSlashingOutput, slashingAddress)
+	}
+
+	var outputsValue int64
+
+	for _, out := range slashingTx.TxOut {
+		outputsValue += out.Value
+	}
+
+	if fee := btcutil.Amount(fundingOutput.Value - outputsValue); fee < minSlashingFee {
+		return fmt.Errorf("%w: fee %s, minimum fee %s", ErrSlashingTxFeeTooLow, fee, minSlashingFee)
+	}
+
+	stakerPk, err := stakerKeyFromSlashingPathScript(slashingPathScript)
+
+	if err != nil {
+		return err
+	}
+
+	err = staking.VerifyTransactionSigWithOutputData(
+		slashingTx,
+		fundingOutput.PkScript,
+		fundingOutput.Value,
+		slashingPathScript,
+		stakerPk,
+		slashingTxSig.Serialize(),
+	)
+
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidSlashingTxSig, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,137 @@
+package staker
+
+import (
+	"testing"
+
+	sdkmath "cosmossdk.io/math"
+	staking "github.com/babylonchain/babylon/btcstaking"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/stretchr/testify/require"
+)
+
+type watchedSlashingTestData struct {
+	stakerPriv         *btcec.PrivateKey
+	stakingTx          *wire.MsgTx
+	slashingTx         *wire.MsgTx
+	slashingTxSig      *schnorr.Signature
+	slashingPathScript []byte
+	slashingAddress    btcutil.Address
+}
+
+func newWatchedSlashingTestData(t *testing.T) *watchedSlashingTestData {
+	net := &chaincfg.SimNetParams
+	stakerPriv := genPrivKey(t)
+	fpPks := []*btcec.PublicKey{genPubKey(t)}
+	covenantPks := []*btcec.PublicKey{genPubKey(t), genPubKey(t)}
+
+	slashingAddress, err := btcutil.NewAddressPubKeyHash(make([]byte, 20), net)
+	require.NoError(t, err)
+
+	stakingInfo, err := staking.BuildStakingInfo(stakerPriv.PubKey(), fpPks, covenantPks, 1, 100, 100000, net)
+	require.NoError(t, err)
+
+	// staking output after change output
+	stakingTx := wire.NewMsgTx(2)
+	stakingTx.AddTxIn(wire.NewTxIn(&wire.OutPoint{Index: 3}, nil, nil))
+	stakingTx.AddTxOut(wire.NewTxOut(5000, []byte{0x51}))
+	stakingTx.AddTxOut(stakingInfo.StakingOutput)
+
+	slashingTx, err := staking.BuildSlashingTxFromStakingTxStrict(
+		stakingTx, 1, slashingAddress, stakerPriv.PubKey(), 10, 2000, sdkmath.LegacyMustNewDecFromStr("0.1"), net,
+	)
+	require.NoError(t, err)
+
+	slashingPathInfo, err := stakingInfo.SlashingPathSpendInfo()
+	require.NoError(t, err)
+
+	sig, err := staking.SignTxWithOneScriptSpendInputFromScript(
+		slashingTx, stakingInfo.StakingOutput, stakerPriv, slashingPathInfo.RevealedLeaf.Script,
+	)
+	require.NoError(t, err)
+
+	return &watchedSlashingTestData{
+		stakerPriv:         stakerPriv,
+		stakingTx:          stakingTx,
+		slashingTx:         slashingTx,
+		slashingTxSig:      sig,
+		slashingPathScript: slashingPathInfo.RevealedLeaf.Script,
+		slashingAddress:    slashingAddress,
+	}
+}
+
+func (d *watchedSlashingTestData) check(outputIdx uint32, minFee btcutil.Amount) error {
+	return checkWatchedSlashingTx(
+		d.slashingTx, d.slashingTxSig, d.stakingTx, outputIdx, d.slashingPathScript, d.slashingAddress, minFee,
+	)
+}
+
+func TestCheckWatchedSlashingTxValid(t *testing.T) {
+	d := newWatchedSlashingTestData(t)
+
+	require.NoError(t, d.check(1, 2000))
+}
+
+func TestCheckWatchedSlashingTxWrongInput(t *testing.T) {
+	d := newWatchedSlashingTestData(t)
+
+	// slashing tx spends other output of staking tx
+	require.ErrorIs(t, d.check(0, 2000), ErrSlashingTxWrongInput)
+	require.ErrorIs(t, d.check(2, 2000), ErrSlashingTxWrongInput)
+
+	// slashing tx spends additional input
+	d.slashingTx.AddTxIn(wire.NewTxIn(&wire.OutPoint{Index: 5}, nil, nil))
+	require.ErrorIs(t, d.check(1, 2000), ErrSlashingTxWrongInput)
+}
+
+func TestCheckWatchedSlashingTxWrongSlashingOutput(t *testing.T) {
+	d := newWatchedSlashingTestData(t)
+
+	otherAddress, err := btcutil.NewAddressPubKeyHash(append(make([]byte, 19), 1), &chaincfg.SimNetParams)
+	require.NoError(t, err)
+
+	d.slashingAddress = otherAddress
+	require.ErrorIs(t, d.check(1, 2000), ErrSlashingTxWrongSlashingOutput)
+
+	d.slashingAddress = nil
+	require.ErrorIs(t, d.check(1, 2000), ErrSlashingTxWrongSlashingOutput)
+}
+
+func TestCheckWatchedSlashingTxFeeTooLow(t *testing.T) {
+	d := newWatchedSlashingTestData(t)
+
+	require.ErrorIs(t, d.check(1, 2001), ErrSlashingTxFeeTooLow)
+}
+
+func TestCheckWatchedSlashingTxInvalidScript(t *testing.T) {
+	d := newWatchedSlashingTestData(t)
+
+	d.slashingPathScript = []byte{txscript.OP_TRUE}
+	require.ErrorIs(t, d.check(1, 2000), ErrInvalidSlashingPathScript)
+}
+
+func TestCheckWatchedSlashingTxSigForDifferentOutput(t *testing.T) {
+	d := newWatchedSlashingTestData(t)
+	net := &chaincfg.SimNetParams
+
+	// staker signature of the same slashing tx, made for staking output with
+	// different staking time
+	otherInfo, err := staking.BuildStakingInfo(
+		d.stakerPriv.PubKey(), []*btcec.PublicKey{genPubKey(t)}, []*btcec.PublicKey{genPubKey(t)}, 1, 200, 100000, net,
+	)
+	require.NoError(t, err)
+
+	otherPathInfo, err := otherInfo.SlashingPathSpendInfo()
+	require.NoError(t, err)
+
+	d.slashingTxSig, err = staking.SignTxWithOneScriptSpendInputFromScript(
+		d.slashingTx, otherInfo.StakingOutput, d.stakerPriv, otherPathInfo.RevealedLeaf.Script,
+	)
+	require.NoError(t, err)
+
+	require.ErrorIs(t, d.check(1, 2000), ErrInvalidSlashingTxSig)
+}
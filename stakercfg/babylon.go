@@ -21,6 +21,16 @@ type BBNConfig struct {
 	BlockTimeout   time.Duration `long:"block-timeout" description:"block timeout when waiting for block events"`
 	OutputFormat   string        `long:"output-format" description:"default output when printint responses"`
 	SignModeStr    string        `long:"sign-mode" description:"sign mode to use"`
+	// Dry run mode is intended for testing staking pipeline against devnets. Messages
+	// are validated and written to output directory instead of being sent to babylon.
+	DryRun            bool   `long:"dryrun" description:"do not send messages to babylon, write them to dry run output directory instead"`
+	DryRunOutputDir   string `long:"dryrun-output-dir" description:"directory to which messages are written in dry run mode, defaults to dryrun directory in stakerd directory"`
+	DryRunFixtureFile string `long:"dryrun-fixture-file" description:"json file with staking params and finality providers served in dry run mode instead of querying babylon node"`
+	// Fees of delegation and undelegation transactions can be paid from fee grant of
+	// another account, or by separate fee payer account whose key is in the keyring.
+	// If both are set, fee grant must be given to the fee payer.
+	FeeGranter string `long:"fee-granter" description:"bech32 address of account which granted fee allowance used to pay fees of babylon transactions"`
+	FeePayer   string `long:"fee-payer" description:"bech32 address of account paying fees of babylon transactions, its key must be in the keyring"`
 }
 
 func DefaultBBNConfig() BBNConfig {
@@ -1,7 +1,6 @@
 package stakercfg
 
 import (
-	"encoding/hex"
 	"fmt"
 	"io"
 	"net"
@@ -17,6 +16,7 @@ import (
 
 	"github.com/btcsuite/btcd/btcutil"
 	"github.com/btcsuite/btcd/chaincfg"
+	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/jessevdk/go-flags"
 	"github.com/lightningnetwork/lnd/lncfg"
 	"github.com/sirupsen/logrus"
@@ -24,12 +24,15 @@ import (
 
 const (
 	defaultDataDirname     = "data"
+	defaultDryRunDirname   = "dryrun"
 	defaultTLSCertFilename = "tls.cert"
 	defaultTLSKeyFilename  = "tls.key"
 	defaultLogLevel        = "info"
 	defaultLogDirname      = "logs"
 	defaultLogFilename     = "stakerd.log"
 	DefaultRPCPort         = 15812
+	DefaultDashboardPort   = 15814
+	DefaultMetricsPort     = 15815
 	// DefaultAutogenValidity is the default validity of a self-signed
 	// certificate. The value corresponds to 14 months
 	// (14 months * 30 days * 24 hours).
@@ -41,6 +44,15 @@ const (
 	// we risk into having transactions rejected by the network due to low fee.
 	DefaultMinFeeRate = 2
 	DefaultMaxFeeRate = 25
+
+	// MaxConfTarget is the maximum confirmation target supported by fee
+	// estimation of btc nodes
+	MaxConfTarget = 1008
+
+	// MemoTooLongTruncate and MemoTooLongError are supported actions taken when
+	// delegation memo exceeds max memo size of babylon chain
+	MemoTooLongTruncate = "truncate"
+	MemoTooLongError    = "error"
 )
 
 var (
@@ -68,14 +80,17 @@ func DefaultChainConfig() ChainConfig {
 }
 
 type WalletConfig struct {
-	WalletName string `long:"walletname" description:"name of the wallet to sign Bitcoin transactions"`
-	WalletPass string `long:"walletpassphrase" description:"passphrase to unlock the wallet"`
+	WalletName          string        `long:"walletname" description:"name of the wallet to sign Bitcoin transactions"`
+	WalletPass          string        `long:"walletpassphrase" description:"passphrase to unlock the wallet"`
+	WalletUnlockTimeout time.Duration `long:"walletunlocktimeout" description:"for how long wallet is unlocked before each operation which requires signing"`
+	NoWallet            bool          `long:"nowallet" description:"run without btc wallet. Only watched staking transactions are supported, and transaction data is retrieved from the btc node, which must have transaction index enabled"`
 }
 
 func DefaultWalletConfig() WalletConfig {
 	return WalletConfig{
-		WalletName: "wallet",
-		WalletPass: "walletpass",
+		WalletName:          "wallet",
+		WalletPass:          "walletpass",
+		WalletUnlockTimeout: 15 * time.Second,
 	}
 }
 
@@ -84,19 +99,39 @@ type WalletRpcConfig struct {
 	User       string `long:"walletuser" description:"user auth for the wallet rpc server"`
 	Pass       string `long:"walletpassword" description:"password auth for the wallet rpc server"`
 	DisableTls bool   `long:"noclienttls" description:"disables tls for the wallet rpc client"`
+	// applies to requests unlocking wallet, dumping keys and signing transactions
+	WalletRpcTimeout time.Duration `long:"walletrpctimeout" description:"maximum time to wait for the wallet to respond to unlock, key retrieval and signing requests"`
 }
 
 func DefaultWalletRpcConfig() WalletRpcConfig {
 	return WalletRpcConfig{
-		DisableTls: true,
-		Host:       "localhost:18556",
-		User:       "rpcuser",
-		Pass:       "rpcpass",
+		DisableTls:       true,
+		Host:             "localhost:18556",
+		User:             "rpcuser",
+		Pass:             "rpcpass",
+		WalletRpcTimeout: 30 * time.Second,
 	}
 }
 
 type JsonRpcServerConfig struct {
-	RawRPCListeners []string `long:"rpclisten" description:"Add an interface/port/socket to listen for RPC connections"`
+	RawRPCListeners    []string `long:"rpclisten" description:"Add an interface/port/socket to listen for RPC connections e.g. tcp://0.0.0.0:15812 or unix:///var/run/stakerd.sock"`
+	RawRPCTLSListeners []string `long:"rpctlslisten" description:"Add an interface/port to listen for RPC connections over TLS"`
+	TLSCertPath        string   `long:"tlscertpath" description:"Path to TLS certificate used by TLS RPC listeners"`
+	TLSKeyPath         string   `long:"tlskeypath" description:"Path to TLS key used by TLS RPC listeners"`
+	RPCUser            string   `long:"rpcuser" description:"Username required from RPC connections to tcp listeners. Unix socket listeners are protected by socket file permissions instead"`
+	RPCPass            string   `long:"rpcpass" description:"Password required from RPC connections to tcp listeners"`
+	RPCSocketPerms     uint32   `long:"rpcsocketperms" base:"8" description:"File permissions of unix socket RPC listeners, in octal"`
+	DashboardAddr      string   `long:"dashboardaddr" description:"Interface/port on which read-only web dashboard is served e.g. localhost:15814. Dashboard requires the same credentials as RPC. Empty disables the dashboard"`
+	MetricsAddr        string   `long:"metricsaddr" description:"Interface/port on which Prometheus metrics are served under /metrics e.g. localhost:15815. Metrics require the same credentials as RPC. Empty disables metrics"`
+	DebugRpcEnabled    bool     `long:"debugrpc" description:"Enables debug_state RPC exposing internal channels and goroutines of the daemon, and pprof handlers under /debug/pprof/ of the dashboard listener"`
+}
+
+func DefaultJsonRpcServerConfig() JsonRpcServerConfig {
+	return JsonRpcServerConfig{
+		TLSCertPath:    filepath.Join(DefaultStakerdDir, defaultTLSCertFilename),
+		TLSKeyPath:     filepath.Join(DefaultStakerdDir, defaultTLSKeyFilename),
+		RPCSocketPerms: 0600,
+	}
 }
 
 type BtcNodeBackendConfig struct {
@@ -127,9 +162,74 @@ func DefaultBtcNodeBackendConfig() BtcNodeBackendConfig {
 }
 
 type StakerConfig struct {
-	BabylonStallingInterval  time.Duration `long:"babylonstallinginterval" description:"The interval for Babylon node BTC light client to catch up with the real chain before re-sending delegation request"`
-	UnbondingTxCheckInterval time.Duration `long:"unbondingtxcheckinterval" description:"The interval for staker whether delegation received all covenant signatures"`
-	ExitOnCriticalError      bool          `long:"exitoncriticalerror" description:"Exit stakerd on critical error"`
+	BabylonStallingInterval       time.Duration `long:"babylonstallinginterval" description:"The interval for Babylon node BTC light client to catch up with the real chain before re-sending delegation request"`
+	UnbondingTxCheckInterval      time.Duration `long:"unbondingtxcheckinterval" description:"The interval for staker whether delegation received all covenant signatures"`
+	ExitOnCriticalError           bool          `long:"exitoncriticalerror" description:"Exit stakerd on critical error"`
+	ReconciliationInterval        time.Duration `long:"reconciliationinterval" description:"The interval for comparing state of delegations in local database against Babylon"`
+	ReconciliationQueryDelay      time.Duration `long:"reconciliationquerydelay" description:"The delay between consecutive Babylon queries during reconciliation"`
+	MaxCachedStakingRequests      int           `long:"maxcachedstakingrequests" description:"The maximum number of asynchronous staking requests kept in memory"`
+	StakingRequestRetention       time.Duration `long:"stakingrequestretention" description:"How long results of finished asynchronous staking requests are kept in database"`
+	UnbondingTxRetryInterval      time.Duration `long:"unbondingtxretryinterval" description:"The interval between retries of sending unbonding transaction to BTC"`
+	SpendTxConfTimeout            time.Duration `long:"spendtxconftimeout" description:"For how long staker waits for confirmation of transaction spending staking output"`
+	MaxInFlightDelegations        int           `long:"maxinflightdelegations" description:"The maximum number of delegations being sent to Babylon at the same time. Further delegations wait in backlog"`
+	TxLabelPrefix                 string        `long:"txlabelprefix" description:"Prefix of labels attached to transactions created by staker"`
+	ExternalDelegations           bool          `long:"externaldelegations" description:"Do not send delegations to Babylon. Delegation messages are built on request and must be submitted to Babylon externally"`
+	StartupCheckRetryInterval     time.Duration `long:"startupcheckretryinterval" description:"The initial interval between retries of startup checks of transactions which failed. Interval is doubled after each failure"`
+	MaxStartupCheckFailurePercent uint32        `long:"maxstartupcheckfailurepercent" description:"The maximum percentage of failed startup checks of transactions. If more checks fail, staker does not start"`
+	MinStartupCheckFailures       uint32        `long:"minstartupcheckfailures" description:"The minimum number of failed startup checks of transactions for maxstartupcheckfailurepercent to apply. Fewer failures never stop staker from starting"`
+	StartupCheckConcurrency       int           `long:"startupcheckconcurrency" description:"The maximum number of transactions which status is checked at the same time during startup"`
+	AllowDepthOverride            bool          `long:"allowdepthoverride" description:"Allow staking requests to override depth on btc chain which staking transaction must reach before delegation is sent to Babylon. Overrides can only require more confirmations than Babylon params"`
+	BlocksPerHour                 uint32        `long:"blocksperhour" description:"The expected number of BTC blocks per hour, used to estimate durations of unbonding and to convert staking durations to blocks"`
+	CovenantSigningEstimate       time.Duration `long:"covenantsigningestimate" description:"The expected time for covenant committee to sign unbonding transaction, used to estimate duration of unbonding"`
+	StakingTxConfTarget           uint32        `long:"stakingtxconftarget" description:"The default number of blocks in which staking transaction should be confirmed, used to estimate its fee"`
+	UnbondingTxConfTarget         uint32        `long:"unbondingtxconftarget" description:"The default number of blocks in which unbonding transaction should be confirmed, used to estimate its fee"`
+	SpendTxConfTarget             uint32        `long:"spendtxconftarget" description:"The default number of blocks in which transaction spending staking output should be confirmed, used to estimate its fee"`
+	MaxWalletSyncLag              uint32        `long:"maxwalletsynclag" description:"The maximum number of blocks wallet can lag behind node backend. Stakes and spends are rejected while wallet lags more or is still syncing"`
+	MaxRescanBlocks               uint32        `long:"maxrescanblocks" description:"The maximum number of blocks wallet rescans when looking for imported or recovered transactions. Also used as rescan depth if start height is not provided"`
+	MaxActiveDelegations          uint32        `long:"maxactivedelegations" description:"The maximum number of delegations which staking transactions were not spent yet. New stakes are rejected when the limit is reached"`
+	MaxStakedValuePerWindow       uint64        `long:"maxstakedvalueperwindow" description:"The maximum value in satoshis of staking transactions sent by staker within stakedvaluewindowblocks blocks. Stakes which would exceed it are rejected. 0 disables the limit"`
+	StakedValueWindowBlocks       uint32        `long:"stakedvaluewindowblocks" description:"The number of btc blocks in rolling window in which value of sent staking transactions is limited by maxstakedvalueperwindow"`
+	MaxStakingTimeBlocks          uint32        `long:"maxstakingtimeblocks" description:"The staking time in blocks above which stakes are rejected, unless the request acknowledges long lock of funds. Watched transactions above the limit are only logged. 0 disables the limit"`
+	AutoSweepUnbondedFunds        bool          `long:"autosweepunbondedfunds" description:"Automatically spend unbonded funds once unbonding timelock expires. Can be overridden for each unbonding request"`
+	SweepAddress                  string        `long:"sweepaddress" description:"The address to which unbonded funds are swept automatically. If empty, funds are sent back to staker address"`
+	AutoRenewLeadBlocks           uint32        `long:"autorenewleadblocks" description:"The number of blocks before staking timelock expires from which finality providers of delegations with auto renewal are checked. Renewal transaction is sent in the first block allowed by the timelock"`
+	DelegationMemo                string        `long:"delegationmemo" description:"Memo attached to babylon transactions delegating or undelegating stake. Supports {stakingTxHash} and {stakerAddress} variables. Can be overridden for each staking request"`
+	MemoTooLongAction             string        `long:"memotoolongaction" description:"What to do with memo longer than max memo size of babylon chain {truncate, error}"`
+	BabylonLowBalanceThreshold    uint64        `long:"babylonlowbalancethreshold" description:"The balance of Babylon account, in units of the fee denom, below which staker warns about low balance. 0 disables the warning"`
+	BabylonBalanceCheckInterval   time.Duration `long:"babylonbalancecheckinterval" description:"The interval between periodic checks of Babylon account balance"`
+	BabylonCircuitFailures        uint32        `long:"babyloncircuitfailures" description:"The number of consecutive failures of sending transactions to Babylon after which sending is suspended for babyloncircuitcooldown"`
+	BabylonCircuitCooldown        time.Duration `long:"babyloncircuitcooldown" description:"For how long sending transactions to Babylon is suspended after repeated failures, before it is probed again"`
+	SpendDestinationWhitelist     []string      `long:"spenddestinationwhitelist" description:"The addresses to which staked funds can be spent, in addition to staker address. Can be specified multiple times. If empty and no addresses are whitelisted through rpc, spends are not restricted"`
+	MaxStakingTxInputs            uint32        `long:"maxstakingtxinputs" description:"The maximum number of inputs of staking transaction funded by wallet. Stakes which would need more inputs are rejected until wallet utxos are consolidated. 0 disables the limit"`
+	MaxStakingTxVsize             uint32        `long:"maxstakingtxvsize" description:"The maximum virtual size of staking transaction funded by wallet in vbytes. Stakes which would produce larger transaction are rejected until wallet utxos are consolidated. 0 disables the limit"`
+	BabylonBatchDelegations       bool          `long:"babylonbatchdelegations" description:"Send delegations which are ready at the same time in single Babylon transaction, to save fees. If batch transaction fails, its delegations are sent one by one"`
+	BabylonBatchMaxSize           uint32        `long:"babylonbatchmaxsize" description:"The maximum number of delegations sent in single Babylon transaction"`
+	BabylonBatchWindow            time.Duration `long:"babylonbatchwindow" description:"For how long staker waits for more delegations to add to the batch after first delegation is ready"`
+	UnbondingSigsTimeout          time.Duration `long:"unbondingsigstimeout" description:"For how long staker waits for covenant unbonding signatures of delegation sent to Babylon before it alerts operator and moves delegation to UNBONDING_SIGNATURES_TIMEOUT state. 0 disables the timeout"`
+	UnbondingSigsSlowInterval     time.Duration `long:"unbondingsigsslowinterval" description:"The interval for checking covenant unbonding signatures of delegations which timed out waiting for them"`
+
+	LatencyBuckets []time.Duration `long:"latencybuckets" description:"Upper bounds of buckets of histograms of transaction lifecycle latencies exported as metrics e.g. 10m. Can be specified multiple times"`
+}
+
+// DefaultLatencyBuckets returns buckets of latency histograms, ranging from single
+// btc block to days of waiting for babylon or covenants
+func DefaultLatencyBuckets() []time.Duration {
+	return []time.Duration{
+		time.Minute,
+		5 * time.Minute,
+		10 * time.Minute,
+		20 * time.Minute,
+		30 * time.Minute,
+		time.Hour,
+		2 * time.Hour,
+		4 * time.Hour,
+		8 * time.Hour,
+		12 * time.Hour,
+		24 * time.Hour,
+		48 * time.Hour,
+		96 * time.Hour,
+		7 * 24 * time.Hour,
+	}
 }
 
 func DefaultStakerConfig() StakerConfig {
@@ -137,6 +237,52 @@ func DefaultStakerConfig() StakerConfig {
 		BabylonStallingInterval:  1 * time.Minute,
 		UnbondingTxCheckInterval: 30 * time.Second,
 		ExitOnCriticalError:      true,
+		ReconciliationInterval:   24 * time.Hour,
+		ReconciliationQueryDelay: 100 * time.Millisecond,
+		MaxCachedStakingRequests: 1000,
+		StakingRequestRetention:  7 * 24 * time.Hour,
+		UnbondingTxRetryInterval: 1 * time.Minute,
+		// 2 hours seems like a reasonable timeout waiting for spend tx confirmations given
+		// probabilistic nature of bitcoin
+		SpendTxConfTimeout:            2 * time.Hour,
+		MaxInFlightDelegations:        100,
+		TxLabelPrefix:                 "btc-staker",
+		StartupCheckRetryInterval:     30 * time.Second,
+		MaxStartupCheckFailurePercent: 50,
+		MinStartupCheckFailures:       5,
+		StartupCheckConcurrency:       4,
+		BlocksPerHour:                 6,
+		CovenantSigningEstimate:       1 * time.Hour,
+		// staking can usually wait, while unbonding is often urgent
+		StakingTxConfTarget:   6,
+		UnbondingTxConfTarget: 1,
+		SpendTxConfTarget:     2,
+		// around 30 days of blocks
+		MaxRescanBlocks:      4320,
+		MaxActiveDelegations: 10000,
+		// around 1 hour of blocks
+		StakedValueWindowBlocks: 6,
+		// around 10 weeks of blocks
+		MaxStakingTimeBlocks: 10000,
+		MaxWalletSyncLag:     2,
+		MemoTooLongAction:    MemoTooLongError,
+		// 1 bbn
+		BabylonLowBalanceThreshold:  1000000,
+		BabylonBalanceCheckInterval: 10 * time.Minute,
+		BabylonCircuitFailures:      5,
+		BabylonCircuitCooldown:      5 * time.Minute,
+		MaxStakingTxInputs:          20,
+		// 20 p2tr inputs with staking and change outputs take around 1300 vbytes
+		MaxStakingTxVsize:   2000,
+		BabylonBatchMaxSize: 10,
+		BabylonBatchWindow:  3 * time.Second,
+		// around 1 day of blocks
+		AutoRenewLeadBlocks: 144,
+
+		// much longer than expected time for covenants to sign
+		UnbondingSigsTimeout:      24 * time.Hour,
+		UnbondingSigsSlowInterval: 10 * time.Minute,
+		LatencyBuckets:            DefaultLatencyBuckets(),
 	}
 }
 
@@ -144,12 +290,14 @@ type Config struct {
 	DebugLevel string `long:"debuglevel" description:"Logging level for all subsystems {trace, debug, info, warn, error, fatal}"`
 	StakerdDir string `long:"stakerddir" description:"The base directory that contains staker's data, logs, configuration file, etc."`
 	ConfigFile string `long:"configfile" description:"Path to configuration file"`
-	DataDir    string `long:"datadir" description:"The directory to store staker's data within"`
-	LogDir     string `long:"logdir" description:"Directory to log output."`
+	DataDir    string `long:"datadir" description:"The directory to store staker's data within. Data of each network is stored in its own subdirectory"`
+	LogDir     string `long:"logdir" description:"Directory to log output. Logs of each network are stored in its own subdirectory"`
 	CPUProfile string `long:"cpuprofile" description:"Write CPU profile to the specified file"`
 	Profile    string `long:"profile" description:"Enable HTTP profiling on either a port or host:port"`
 	DumpCfg    bool   `long:"dumpcfg" description:"If config filr does not exist, create it with current settings"`
 
+	RebuildHeightHints bool `long:"rebuild-height-hints" description:"Drop height hint cache of btc notifier on startup and rebuild it from scratch, backing up old hints. Cache is rebuilt automatically if it cannot be opened"`
+
 	WalletConfig *WalletConfig `group:"walletconfig" namespace:"walletconfig"`
 
 	WalletRpcConfig *WalletRpcConfig `group:"walletrpcconfig" namespace:"walletrpcconfig"`
@@ -164,11 +312,21 @@ type Config struct {
 
 	StakerConfig *StakerConfig `group:"stakerconfig" namespace:"stakerconfig"`
 
+	ProxyConfig *ProxyConfig `group:"proxy" namespace:"proxy"`
+
 	JsonRpcServerConfig *JsonRpcServerConfig
 
 	ActiveNetParams chaincfg.Params
 
 	RpcListeners []net.Addr
+
+	RpcTLSListeners []net.Addr
+
+	// nil if dashboard is disabled
+	DashboardListener net.Addr
+
+	// nil if metrics are disabled
+	MetricsListener net.Addr
 }
 
 func DefaultConfig() Config {
@@ -179,6 +337,8 @@ func DefaultConfig() Config {
 	bbnConfig := DefaultBBNConfig()
 	dbConfig := DefaultDBConfig()
 	stakerConfig := DefaultStakerConfig()
+	jsonRpcServerConfig := DefaultJsonRpcServerConfig()
+	proxyConfig := DefaultProxyConfig()
 	return Config{
 		StakerdDir:           DefaultStakerdDir,
 		ConfigFile:           DefaultConfigFile,
@@ -192,6 +352,8 @@ func DefaultConfig() Config {
 		BabylonConfig:        &bbnConfig,
 		DBConfig:             &dbConfig,
 		StakerConfig:         &stakerConfig,
+		ProxyConfig:          &proxyConfig,
+		JsonRpcServerConfig:  &jsonRpcServerConfig,
 	}
 }
 
@@ -340,6 +502,8 @@ func ValidateConfig(cfg Config) (*Config, error) {
 	if stakerdDir != DefaultStakerdDir {
 		cfg.DataDir = filepath.Join(stakerdDir, defaultDataDirname)
 		cfg.LogDir = filepath.Join(stakerdDir, defaultLogDirname)
+		cfg.JsonRpcServerConfig.TLSCertPath = filepath.Join(stakerdDir, defaultTLSCertFilename)
+		cfg.JsonRpcServerConfig.TLSKeyPath = filepath.Join(stakerdDir, defaultTLSKeyFilename)
 	}
 
 	funcName := "ValidateConfig"
@@ -372,44 +536,24 @@ func ValidateConfig(cfg Config) (*Config, error) {
 	// attempting to use them later on.
 	cfg.DataDir = CleanAndExpandPath(cfg.DataDir)
 	cfg.LogDir = CleanAndExpandPath(cfg.LogDir)
+	cfg.JsonRpcServerConfig.TLSCertPath = CleanAndExpandPath(cfg.JsonRpcServerConfig.TLSCertPath)
+	cfg.JsonRpcServerConfig.TLSKeyPath = CleanAndExpandPath(cfg.JsonRpcServerConfig.TLSKeyPath)
 
-	// Multiple networks can't be selected simultaneously.  Count number of
-	// network flags passed; assign active network params
-	// while we're at it.
-	switch cfg.ChainConfig.Network {
-	case "testnet":
-		cfg.ActiveNetParams = chaincfg.TestNet3Params
-	case "regtest":
-		cfg.ActiveNetParams = chaincfg.RegressionNetParams
-	case "simnet":
-		cfg.ActiveNetParams = chaincfg.SimNetParams
-	case "signet":
-		cfg.ActiveNetParams = chaincfg.SigNetParams
-
-		// Let the user overwrite the default signet parameters.
-		// The challenge defines the actual signet network to
-		// join and the seed nodes are needed for network
-		// discovery.
-		sigNetChallenge := chaincfg.DefaultSignetChallenge
-		sigNetSeeds := chaincfg.DefaultSignetDNSSeeds
-		if cfg.ChainConfig.SigNetChallenge != "" {
-			challenge, err := hex.DecodeString(
-				cfg.ChainConfig.SigNetChallenge,
-			)
-			if err != nil {
-				return nil, mkErr("Invalid signet challenge, "+
-					"hex decode failed: %v", err)
-			}
-			sigNetChallenge = challenge
-		}
+	netParams, err := NetParams(cfg.ChainConfig)
+	if err != nil {
+		return nil, mkErr("%v", err)
+	}
+	cfg.ActiveNetParams = *netParams
 
-		chainParams := chaincfg.CustomSignetParams(
-			sigNetChallenge, sigNetSeeds,
-		)
-		cfg.ActiveNetParams = chainParams
-	default:
-		return nil, mkErr(fmt.Sprintf("invalid network: %v",
-			cfg.ChainConfig.Network))
+	// Data of each network is stored in its own directory
+	baseDbPath := CleanAndExpandPath(cfg.DBConfig.DBPath)
+	baseLogDir := cfg.LogDir
+	cfg.DataDir = NetworkDir(cfg.DataDir, cfg.ChainConfig.Network)
+	cfg.LogDir = NetworkDir(cfg.LogDir, cfg.ChainConfig.Network)
+	cfg.DBConfig.DBPath = NetworkDir(baseDbPath, cfg.ChainConfig.Network)
+
+	if err := checkLegacyDbLayout(cfg.DBConfig, baseDbPath, baseLogDir, cfg.ChainConfig.Network); err != nil {
+		return nil, mkErr("%v", err)
 	}
 
 	nodeBackend, err := types.NewNodeBackend(cfg.BtcNodeBackendConfig.Nodetype)
@@ -445,7 +589,178 @@ func ValidateConfig(cfg Config) (*Config, error) {
 		return nil, mkErr(fmt.Sprintf("minfeerate must be less or equal maxfeerate. minfeerate: %d, maxfeerate: %d", cfg.BtcNodeBackendConfig.MinFeeRate, cfg.BtcNodeBackendConfig.MaxFeeRate))
 	}
 
-	// TODO: Validate node host and port
+	if cfg.StakerConfig.ReconciliationInterval <= 0 {
+		return nil, mkErr("reconciliationinterval must be greater than 0")
+	}
+
+	if cfg.StakerConfig.MaxCachedStakingRequests <= 0 {
+		return nil, mkErr("maxcachedstakingrequests must be greater than 0")
+	}
+
+	if cfg.StakerConfig.MaxInFlightDelegations <= 0 {
+		return nil, mkErr("maxinflightdelegations must be greater than 0")
+	}
+
+	if cfg.StakerConfig.TxLabelPrefix == "" {
+		return nil, mkErr("txlabelprefix must not be empty")
+	}
+
+	if cfg.StakerConfig.StartupCheckRetryInterval < time.Second {
+		return nil, mkErr("startupcheckretryinterval must be at least 1 second")
+	}
+
+	if cfg.StakerConfig.MaxStartupCheckFailurePercent > 100 {
+		return nil, mkErr("maxstartupcheckfailurepercent must not be greater than 100")
+	}
+
+	if cfg.StakerConfig.StartupCheckConcurrency <= 0 {
+		return nil, mkErr("startupcheckconcurrency must be greater than 0")
+	}
+
+	if cfg.StakerConfig.BlocksPerHour == 0 {
+		return nil, mkErr("blocksperhour must be greater than 0")
+	}
+
+	confTargets := []struct {
+		name   string
+		target uint32
+	}{
+		{"stakingtxconftarget", cfg.StakerConfig.StakingTxConfTarget},
+		{"unbondingtxconftarget", cfg.StakerConfig.UnbondingTxConfTarget},
+		{"spendtxconftarget", cfg.StakerConfig.SpendTxConfTarget},
+	}
+
+	for _, ct := range confTargets {
+		if ct.target == 0 || ct.target > MaxConfTarget {
+			return nil, mkErr("%s must be between 1 and %d", ct.name, MaxConfTarget)
+		}
+	}
+
+	if cfg.StakerConfig.MaxRescanBlocks == 0 {
+		return nil, mkErr("maxrescanblocks must be greater than 0")
+	}
+
+	if cfg.StakerConfig.MaxActiveDelegations == 0 {
+		return nil, mkErr("maxactivedelegations must be greater than 0")
+	}
+
+	if cfg.StakerConfig.StakedValueWindowBlocks == 0 {
+		return nil, mkErr("stakedvaluewindowblocks must be greater than 0")
+	}
+
+	if err := validateLatencyBuckets(cfg.StakerConfig.LatencyBuckets); err != nil {
+		return nil, mkErr("%v", err)
+	}
+
+	if cfg.StakerConfig.SweepAddress != "" {
+		sweepAddress, err := btcutil.DecodeAddress(cfg.StakerConfig.SweepAddress, &cfg.ActiveNetParams)
+		if err != nil {
+			return nil, mkErr("invalid sweepaddress: %v", err)
+		}
+
+		if !sweepAddress.IsForNet(&cfg.ActiveNetParams) {
+			return nil, mkErr("sweepaddress is not valid for network %s", cfg.ActiveNetParams.Name)
+		}
+	}
+
+	if cfg.StakerConfig.BabylonCircuitFailures == 0 {
+		return nil, mkErr("babyloncircuitfailures must be greater than 0")
+	}
+
+	if cfg.StakerConfig.BabylonCircuitCooldown <= 0 {
+		return nil, mkErr("babyloncircuitcooldown must be greater than 0")
+	}
+
+	if cfg.StakerConfig.BabylonBatchMaxSize == 0 {
+		return nil, mkErr("babylonbatchmaxsize must be greater than 0")
+	}
+
+	if cfg.StakerConfig.BabylonBatchDelegations && cfg.StakerConfig.BabylonBatchMaxSize < 2 {
+		return nil, mkErr("babylonbatchmaxsize must be at least 2 when babylonbatchdelegations is enabled")
+	}
+
+	if cfg.StakerConfig.BabylonBatchWindow <= 0 {
+		return nil, mkErr("babylonbatchwindow must be greater than 0")
+	}
+
+	if cfg.StakerConfig.UnbondingSigsTimeout < 0 {
+		return nil, mkErr("unbondingsigstimeout must not be negative")
+	}
+
+	if cfg.StakerConfig.UnbondingSigsSlowInterval <= 0 {
+		return nil, mkErr("unbondingsigsslowinterval must be greater than 0")
+	}
+
+	for i, address := range cfg.StakerConfig.SpendDestinationWhitelist {
+		whitelisted, err := btcutil.DecodeAddress(address, &cfg.ActiveNetParams)
+		if err != nil {
+			return nil, mkErr("invalid spenddestinationwhitelist address %s: %v", address, err)
+		}
+
+		if !whitelisted.IsForNet(&cfg.ActiveNetParams) {
+			return nil, mkErr("spenddestinationwhitelist address %s is not valid for network %s", address, cfg.ActiveNetParams.Name)
+		}
+
+		// addresses are compared in canonical encoding
+		cfg.StakerConfig.SpendDestinationWhitelist[i] = whitelisted.EncodeAddress()
+	}
+
+	switch cfg.StakerConfig.MemoTooLongAction {
+	case MemoTooLongTruncate, MemoTooLongError:
+	default:
+		return nil, mkErr("invalid memotoolongaction: %s", cfg.StakerConfig.MemoTooLongAction)
+	}
+
+	if cfg.StakerConfig.BabylonBalanceCheckInterval <= 0 {
+		return nil, mkErr("babylonbalancecheckinterval must be greater than 0")
+	}
+
+	if cfg.WalletConfig.WalletUnlockTimeout < time.Second {
+		return nil, mkErr("walletunlocktimeout must be at least 1 second")
+	}
+
+	if cfg.WalletRpcConfig.WalletRpcTimeout <= 0 {
+		return nil, mkErr("walletrpctimeout must be greater than 0")
+	}
+
+	if cfg.BabylonConfig.DryRun {
+		if cfg.BabylonConfig.DryRunOutputDir == "" {
+			cfg.BabylonConfig.DryRunOutputDir = filepath.Join(stakerdDir, defaultDryRunDirname)
+		}
+
+		cfg.BabylonConfig.DryRunOutputDir = CleanAndExpandPath(cfg.BabylonConfig.DryRunOutputDir)
+
+		if cfg.BabylonConfig.DryRunFixtureFile != "" {
+			cfg.BabylonConfig.DryRunFixtureFile = CleanAndExpandPath(cfg.BabylonConfig.DryRunFixtureFile)
+
+			if !FileExists(cfg.BabylonConfig.DryRunFixtureFile) {
+				return nil, mkErr("dryrun-fixture-file %s does not exist", cfg.BabylonConfig.DryRunFixtureFile)
+			}
+		}
+	} else if cfg.BabylonConfig.DryRunFixtureFile != "" {
+		return nil, mkErr("dryrun-fixture-file can only be used with dryrun")
+	}
+
+	if cfg.BabylonConfig.FeeGranter != "" {
+		if _, err := sdk.GetFromBech32(cfg.BabylonConfig.FeeGranter, cfg.BabylonConfig.AccountPrefix); err != nil {
+			return nil, mkErr("invalid babylon fee-granter address %s: %v", cfg.BabylonConfig.FeeGranter, err)
+		}
+	}
+
+	if cfg.BabylonConfig.FeePayer != "" {
+		if _, err := sdk.GetFromBech32(cfg.BabylonConfig.FeePayer, cfg.BabylonConfig.AccountPrefix); err != nil {
+			return nil, mkErr("invalid babylon fee-payer address %s: %v", cfg.BabylonConfig.FeePayer, err)
+		}
+	}
+
+	if cfg.BabylonConfig.FeeGranter != "" && cfg.BabylonConfig.FeeGranter == cfg.BabylonConfig.FeePayer {
+		return nil, mkErr("babylon fee-granter and fee-payer must be different accounts")
+	}
+
+	if err := normalizeEndpoints(&cfg); err != nil {
+		return nil, mkErr("%v", err)
+	}
+
 	// TODO: Validate babylon config!
 
 	// Validate profile port or host:port.
@@ -479,6 +794,9 @@ func ValidateConfig(cfg Config) (*Config, error) {
 	dirs := []string{
 		stakerdDir, cfg.DataDir, cfg.LogDir,
 	}
+	if cfg.BabylonConfig.DryRun {
+		dirs = append(dirs, cfg.BabylonConfig.DryRunOutputDir)
+	}
 	for _, dir := range dirs {
 		if err := makeDirectory(dir); err != nil {
 			return nil, err
@@ -487,7 +805,8 @@ func ValidateConfig(cfg Config) (*Config, error) {
 
 	// At least one RPCListener is required. So listen on localhost per
 	// default.
-	if len(cfg.JsonRpcServerConfig.RawRPCListeners) == 0 {
+	if len(cfg.JsonRpcServerConfig.RawRPCListeners) == 0 &&
+		len(cfg.JsonRpcServerConfig.RawRPCTLSListeners) == 0 {
 		addr := fmt.Sprintf("localhost:%d", DefaultRPCPort)
 		cfg.JsonRpcServerConfig.RawRPCListeners = append(
 			cfg.JsonRpcServerConfig.RawRPCListeners, addr,
@@ -511,12 +830,119 @@ func ValidateConfig(cfg Config) (*Config, error) {
 		return nil, mkErr("error normalizing RPC listen addrs: %v", err)
 	}
 
+	cfg.RpcTLSListeners, err = lncfg.NormalizeAddresses(
+		cfg.JsonRpcServerConfig.RawRPCTLSListeners, strconv.Itoa(DefaultRPCPort),
+		net.ResolveTCPAddr,
+	)
+
+	if err != nil {
+		return nil, mkErr("error normalizing RPC TLS listen addrs: %v", err)
+	}
+
+	if cfg.JsonRpcServerConfig.DashboardAddr != "" {
+		dashboardListeners, err := lncfg.NormalizeAddresses(
+			[]string{cfg.JsonRpcServerConfig.DashboardAddr}, strconv.Itoa(DefaultDashboardPort),
+			net.ResolveTCPAddr,
+		)
+
+		if err != nil {
+			return nil, mkErr("error normalizing dashboard addr: %v", err)
+		}
+
+		cfg.DashboardListener = dashboardListeners[0]
+	}
+
+	if cfg.JsonRpcServerConfig.MetricsAddr != "" {
+		metricsListeners, err := lncfg.NormalizeAddresses(
+			[]string{cfg.JsonRpcServerConfig.MetricsAddr}, strconv.Itoa(DefaultMetricsPort),
+			net.ResolveTCPAddr,
+		)
+
+		if err != nil {
+			return nil, mkErr("error normalizing metrics addr: %v", err)
+		}
+
+		cfg.MetricsListener = metricsListeners[0]
+	}
+
+	if err := validateJsonRpcServerConfig(&cfg); err != nil {
+		return nil, mkErr("%v", err)
+	}
+
+	if err := validateProxyConfig(&cfg); err != nil {
+		return nil, mkErr("%v", err)
+	}
+
 	// All good, return the sanitized result.
 	return &cfg, nil
 }
 
 // FileExists reports whether the named file or directory exists.
 // This function is taken from https://github.com/btcsuite/btcd
+func validateJsonRpcServerConfig(cfg *Config) error {
+	rpcCfg := cfg.JsonRpcServerConfig
+
+	if (rpcCfg.RPCUser == "") != (rpcCfg.RPCPass == "") {
+		return fmt.Errorf("rpcuser and rpcpass must be set together")
+	}
+
+	if rpcCfg.RPCSocketPerms > 0777 {
+		return fmt.Errorf("rpcsocketperms must be valid file permissions, got %o", rpcCfg.RPCSocketPerms)
+	}
+
+	if cfg.DashboardListener != nil {
+		if _, ok := cfg.DashboardListener.(*net.TCPAddr); !ok {
+			return fmt.Errorf("dashboardaddr only supports tcp addresses, got %s", cfg.DashboardListener)
+		}
+	}
+
+	if cfg.MetricsListener != nil {
+		if _, ok := cfg.MetricsListener.(*net.TCPAddr); !ok {
+			return fmt.Errorf("metricsaddr only supports tcp addresses, got %s", cfg.MetricsListener)
+		}
+	}
+
+	if len(cfg.RpcTLSListeners) == 0 {
+		return nil
+	}
+
+	for _, addr := range cfg.RpcTLSListeners {
+		if _, ok := addr.(*net.TCPAddr); !ok {
+			return fmt.Errorf("rpctlslisten only supports tcp addresses, got %s", addr)
+		}
+	}
+
+	if !FileExists(rpcCfg.TLSCertPath) {
+		return fmt.Errorf("tls certificate %s required by TLS RPC listeners does not exist", rpcCfg.TLSCertPath)
+	}
+
+	if !FileExists(rpcCfg.TLSKeyPath) {
+		return fmt.Errorf("tls key %s required by TLS RPC listeners does not exist", rpcCfg.TLSKeyPath)
+	}
+
+	return nil
+}
+
+// validateLatencyBuckets checks that latency histogram buckets are positive and
+// strictly increasing, as required by prometheus histograms
+func validateLatencyBuckets(buckets []time.Duration) error {
+	if len(buckets) == 0 {
+		return fmt.Errorf("at least one latencybuckets value is required")
+	}
+
+	for i, b := range buckets {
+		if b <= 0 {
+			return fmt.Errorf("latencybuckets must be positive, got %s", b)
+		}
+
+		if i > 0 && b <= buckets[i-1] {
+			return fmt.Errorf("latencybuckets must be strictly increasing, got %s after %s", b, buckets[i-1])
+		}
+	}
+
+	return nil
+}
+
 func FileExists(name string) bool {
 	if _, err := os.Stat(name); err != nil {
 		if os.IsNotExist(err) {
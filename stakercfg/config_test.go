@@ -0,0 +1,18 @@
+package stakercfg
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateLatencyBuckets(t *testing.T) {
+	require.NoError(t, validateLatencyBuckets(DefaultLatencyBuckets()))
+	require.NoError(t, validateLatencyBuckets([]time.Duration{time.Hour}))
+
+	require.Error(t, validateLatencyBuckets(nil))
+	require.Error(t, validateLatencyBuckets([]time.Duration{0, time.Hour}))
+	require.Error(t, validateLatencyBuckets([]time.Duration{time.Hour, time.Hour}))
+	require.Error(t, validateLatencyBuckets([]time.Duration{time.Hour, 10 * time.Minute}))
+}
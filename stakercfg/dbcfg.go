@@ -1,19 +1,44 @@
 package stakercfg
 
 import (
+	"context"
+	"fmt"
 	"time"
 
 	"github.com/lightningnetwork/lnd/kvdb"
+	"github.com/lightningnetwork/lnd/kvdb/postgres"
 )
 
 const (
 	defaultDbName = "staker.db"
+
+	// BoltDbBackend stores data in a local bbolt file
+	BoltDbBackend = "bbolt"
+	// PostgresDbBackend stores data in postgres database. It requires daemon to be
+	// built with kvdb_postgres build tag.
+	PostgresDbBackend = "postgres"
+
+	defaultPostgresTablePrefix = "staker"
 )
 
+type PostgresDBConfig struct {
+	Dsn            string        `long:"dsn" description:"Database connection string."`
+	Timeout        time.Duration `long:"timeout" description:"Database connection timeout. Set to zero to disable."`
+	MaxConnections int           `long:"maxconnections" description:"The maximum number of open connections to the database. Set to zero for unlimited."`
+	TablePrefix    string        `long:"tableprefix" description:"Prefix of the tables used by the daemon. Allows multiple daemons to share one database."`
+}
+
 type DBConfig struct {
+	// Backend selects where data is stored
+	Backend string `long:"backend" description:"The database backend to use." choice:"bbolt" choice:"postgres"`
+
+	// Postgres is the configuration of postgres backend, used only when
+	// Backend is postgres
+	Postgres *PostgresDBConfig `group:"postgres" namespace:"postgres"`
+
 	// DBPath is the directory path in which the database file should be
-	// stored.
-	DBPath string `long:"dbpath" description:"The directory path in which the database file should be stored."`
+	// stored. Database of each network is stored in its own subdirectory.
+	DBPath string `long:"dbpath" description:"The directory path in which the database file should be stored. Database of each network is stored in its own subdirectory."`
 
 	// DBFileName is the name of the database file.
 	DBFileName string `long:"dbfilename" description:"The name of the database file."`
@@ -42,6 +67,11 @@ type DBConfig struct {
 
 func DefaultDBConfig() DBConfig {
 	return DBConfig{
+		Backend: BoltDbBackend,
+		Postgres: &PostgresDBConfig{
+			Timeout:     kvdb.DefaultDBTimeout,
+			TablePrefix: defaultPostgresTablePrefix,
+		},
 		DBPath:            defaultDataDir,
 		DBFileName:        defaultDbName,
 		NoFreelistSync:    true,
@@ -62,7 +92,39 @@ func DBConfigToBoltBackenCondfig(db *DBConfig) kvdb.BoltBackendConfig {
 	}
 }
 
+func getPostgresBackend(cfg *PostgresDBConfig) (kvdb.Backend, error) {
+	if !kvdb.PostgresBackend {
+		return nil, fmt.Errorf("postgres db backend requires daemon to be built with kvdb_postgres build tag")
+	}
+
+	if cfg == nil || cfg.Dsn == "" {
+		return nil, fmt.Errorf("postgres db backend requires dsn to be set")
+	}
+
+	if cfg.TablePrefix == "" {
+		return nil, fmt.Errorf("postgres db backend requires table prefix to be set")
+	}
+
+	return kvdb.Open(
+		kvdb.PostgresBackendName,
+		context.Background(),
+		&postgres.Config{
+			Dsn:            cfg.Dsn,
+			Timeout:        cfg.Timeout,
+			MaxConnections: cfg.MaxConnections,
+		},
+		cfg.TablePrefix,
+	)
+}
+
 func GetDbBackend(cfg *DBConfig) (kvdb.Backend, error) {
-	boltConfig := DBConfigToBoltBackenCondfig(cfg)
-	return kvdb.GetBoltBackend(&boltConfig)
+	switch cfg.Backend {
+	case "", BoltDbBackend:
+		boltConfig := DBConfigToBoltBackenCondfig(cfg)
+		return kvdb.GetBoltBackend(&boltConfig)
+	case PostgresDbBackend:
+		return getPostgresBackend(cfg.Postgres)
+	default:
+		return nil, fmt.Errorf("unknown db backend %s", cfg.Backend)
+	}
 }
@@ -0,0 +1,281 @@
+package stakercfg
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/babylonchain/btc-staker/types"
+	"github.com/btcsuite/btcd/chaincfg"
+)
+
+// default rpc ports of bitcoind. Btcwallet uses the same ports, except simnet
+// which is not supported by bitcoind.
+var bitcoindRpcPorts = map[string]string{
+	chaincfg.MainNetParams.Name:       "8332",
+	chaincfg.TestNet3Params.Name:      "18332",
+	chaincfg.RegressionNetParams.Name: "18443",
+	chaincfg.SigNetParams.Name:        "38332",
+	chaincfg.SimNetParams.Name:        "18554",
+}
+
+// default rpc ports of btcd
+var btcdRpcPorts = map[string]string{
+	chaincfg.MainNetParams.Name:       "8334",
+	chaincfg.TestNet3Params.Name:      "18334",
+	chaincfg.RegressionNetParams.Name: "18334",
+	chaincfg.SigNetParams.Name:        "38332",
+	chaincfg.SimNetParams.Name:        "18556",
+}
+
+var endpointSchemes = map[string]struct{}{
+	"http":  {},
+	"https": {},
+	"tcp":   {},
+}
+
+// NormalizeEndpoint validates endpoint address given as host, host:port or
+// scheme://host:port/path, where host may be hostname, IPv4 or IPv6 address.
+// IPv6 addresses are returned in brackets and missing port is set to
+// defaultPort. If defaultPort is empty, missing port is left out, as it is
+// implied by scheme of the address.
+func NormalizeEndpoint(addr string, defaultPort string) (string, error) {
+	rest := strings.TrimSpace(addr)
+
+	if rest == "" {
+		return "", fmt.Errorf("address must not be empty")
+	}
+
+	var scheme, path string
+
+	if idx := strings.Index(rest, "://"); idx >= 0 {
+		scheme = strings.ToLower(rest[:idx])
+		rest = rest[idx+3:]
+
+		if _, ok := endpointSchemes[scheme]; !ok {
+			return "", fmt.Errorf("unsupported scheme %q in address %q", scheme, addr)
+		}
+
+		if idx := strings.Index(rest, "/"); idx >= 0 {
+			path = rest[idx:]
+			rest = rest[:idx]
+		}
+	}
+
+	host, port, err := splitEndpointHostPort(rest)
+
+	if err != nil {
+		return "", fmt.Errorf("invalid address %q: %w", addr, err)
+	}
+
+	if err := validateEndpointHost(host); err != nil {
+		return "", fmt.Errorf("invalid address %q: %w", addr, err)
+	}
+
+	if port == "" {
+		port = defaultPort
+	}
+
+	if port != "" {
+		if portNum, err := strconv.ParseUint(port, 10, 16); err != nil || portNum == 0 {
+			return "", fmt.Errorf("invalid port %q in address %q, port must be between 1 and 65535", port, addr)
+		}
+	}
+
+	var normalized string
+
+	switch {
+	case port != "":
+		normalized = net.JoinHostPort(host, port)
+	case strings.Contains(host, ":"):
+		normalized = "[" + host + "]"
+	default:
+		normalized = host
+	}
+
+	if scheme != "" {
+		normalized = scheme + "://" + normalized + path
+	}
+
+	return normalized, nil
+}
+
+// splitEndpointHostPort splits address into host and port, port is empty if
+// address does not specify it. Unlike net.SplitHostPort, address without port
+// and IPv6 address without brackets are accepted.
+func splitEndpointHostPort(hostPort string) (string, string, error) {
+	if strings.HasPrefix(hostPort, "[") {
+		end := strings.Index(hostPort, "]")
+
+		if end < 0 {
+			return "", "", fmt.Errorf("missing ']' in IPv6 address")
+		}
+
+		host := hostPort[1:end]
+
+		if net.ParseIP(host) == nil {
+			return "", "", fmt.Errorf("invalid IPv6 address %q", host)
+		}
+
+		switch rest := hostPort[end+1:]; {
+		case rest == "":
+			return host, "", nil
+		case strings.HasPrefix(rest, ":") && len(rest) > 1:
+			return host, rest[1:], nil
+		default:
+			return "", "", fmt.Errorf("unexpected %q after IPv6 address", rest)
+		}
+	}
+
+	switch strings.Count(hostPort, ":") {
+	case 0:
+		return hostPort, "", nil
+	case 1:
+		if strings.HasSuffix(hostPort, ":") {
+			return "", "", fmt.Errorf("missing port after ':'")
+		}
+
+		return net.SplitHostPort(hostPort)
+	default:
+		// IPv6 address can't be followed by port without brackets
+		if net.ParseIP(hostPort) == nil {
+			return "", "", fmt.Errorf("IPv6 address with port must be enclosed in brackets e.g. [::1]:8332")
+		}
+
+		return hostPort, "", nil
+	}
+}
+
+func validateEndpointHost(host string) error {
+	if host == "" {
+		return fmt.Errorf("missing host")
+	}
+
+	if net.ParseIP(host) != nil {
+		return nil
+	}
+
+	for _, label := range strings.Split(host, ".") {
+		if label == "" || strings.HasPrefix(label, "-") || strings.HasSuffix(label, "-") {
+			return fmt.Errorf("invalid hostname %q", host)
+		}
+
+		for _, c := range label {
+			isAlnum := (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+
+			if !isAlnum && c != '-' && c != '_' {
+				return fmt.Errorf("invalid character %q in hostname %q", c, host)
+			}
+		}
+	}
+
+	return nil
+}
+
+// NormalizeRpcHost normalizes address of btc rpc server to host:port format
+// expected by rpc clients, which add scheme themselves
+func NormalizeRpcHost(addr string, defaultPort string) (string, error) {
+	normalized, err := NormalizeEndpoint(addr, defaultPort)
+
+	if err != nil {
+		return "", err
+	}
+
+	if idx := strings.Index(normalized, "://"); idx >= 0 {
+		normalized = normalized[idx+3:]
+
+		if strings.Contains(normalized, "/") {
+			return "", fmt.Errorf("invalid address %q: path is not supported", addr)
+		}
+	}
+
+	return normalized, nil
+}
+
+// DefaultWalletRpcPort returns default rpc port of wallet on given network, which
+// is the same for both bitcoind and btcwallet
+func DefaultWalletRpcPort(network string) string {
+	return bitcoindRpcPorts[network]
+}
+
+// DefaultNodeRpcPort returns default rpc port of node backend on given network
+func DefaultNodeRpcPort(backend types.SupportedNodeBackend, network string) string {
+	switch backend {
+	case types.BtcdNodeBackend:
+		return btcdRpcPorts[network]
+	default:
+		return bitcoindRpcPorts[network]
+	}
+}
+
+// normalizeEndpoints validates addresses of btc wallet, btc node and babylon
+// node, and sets default ports of btc rpc servers if missing. Errors name the
+// config field with invalid address.
+func normalizeEndpoints(cfg *Config) error {
+	network := cfg.ActiveNetParams.Name
+
+	if !cfg.WalletConfig.NoWallet {
+		host, err := NormalizeRpcHost(
+			cfg.WalletRpcConfig.Host,
+			DefaultWalletRpcPort(network),
+		)
+
+		if err != nil {
+			return fmt.Errorf("walletrpcconfig.wallethost: %w", err)
+		}
+
+		cfg.WalletRpcConfig.Host = host
+	}
+
+	switch cfg.BtcNodeBackendConfig.ActiveNodeBackend {
+	case types.BitcoindNodeBackend:
+		host, err := NormalizeRpcHost(
+			cfg.BtcNodeBackendConfig.Bitcoind.RPCHost,
+			DefaultNodeRpcPort(types.BitcoindNodeBackend, network),
+		)
+
+		if err != nil {
+			return fmt.Errorf("btcnodebackend.bitcoind.rpchost: %w", err)
+		}
+
+		cfg.BtcNodeBackendConfig.Bitcoind.RPCHost = host
+	case types.BtcdNodeBackend:
+		host, err := NormalizeRpcHost(
+			cfg.BtcNodeBackendConfig.Btcd.RPCHost,
+			DefaultNodeRpcPort(types.BtcdNodeBackend, network),
+		)
+
+		if err != nil {
+			return fmt.Errorf("btcnodebackend.btcd.rpchost: %w", err)
+		}
+
+		cfg.BtcNodeBackendConfig.Btcd.RPCHost = host
+	}
+
+	// babylon clients need scheme to choose transport, and ports implied by
+	// schemes are kept, as public endpoints are usually served on them
+	babylonAddrs := []struct {
+		field string
+		addr  *string
+	}{
+		{"babylon.rpc-address", &cfg.BabylonConfig.RPCAddr},
+		{"babylon.grpc-address", &cfg.BabylonConfig.GRPCAddr},
+	}
+
+	for _, a := range babylonAddrs {
+		if !strings.Contains(*a.addr, "://") {
+			return fmt.Errorf("%s: address %q must include scheme e.g. http://", a.field, *a.addr)
+		}
+
+		addr, err := NormalizeEndpoint(*a.addr, "")
+
+		if err != nil {
+			return fmt.Errorf("%s: %w", a.field, err)
+		}
+
+		*a.addr = addr
+	}
+
+	return nil
+}
@@ -0,0 +1,192 @@
+package stakercfg
+
+import (
+	"testing"
+
+	"github.com/babylonchain/btc-staker/types"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeEndpoint(t *testing.T) {
+	tests := []struct {
+		name        string
+		addr        string
+		defaultPort string
+		expected    string
+		expectErr   bool
+	}{
+		{"ipv4 with port", "127.0.0.1:8332", "18332", "127.0.0.1:8332", false},
+		{"ipv4 without port", "127.0.0.1", "18332", "127.0.0.1:18332", false},
+		{"hostname with port", "btcd.example.com:12345", "8334", "btcd.example.com:12345", false},
+		{"hostname without port", "btcd.example.com", "8334", "btcd.example.com:8334", false},
+		{"bracketed ipv6 with port", "[::1]:8332", "18332", "[::1]:8332", false},
+		{"bracketed ipv6 without port", "[2001:db8::1]", "8332", "[2001:db8::1]:8332", false},
+		{"bare ipv6", "2001:db8::1", "8332", "[2001:db8::1]:8332", false},
+		{"scheme with port", "http://node.local:8332", "18332", "http://node.local:8332", false},
+		{"scheme without port", "https://node.local", "443", "https://node.local:443", false},
+		{"scheme with path", "https://[::1]/rpc", "", "https://[::1]/rpc", false},
+		{"no default port", "localhost", "", "localhost", false},
+		{"surrounding whitespace", " localhost:8332 ", "", "localhost:8332", false},
+		{"empty", "", "8332", "", true},
+		{"zero port", "localhost:0", "8332", "", true},
+		{"port out of range", "localhost:65536", "8332", "", true},
+		{"non numeric port", "localhost:rpc", "8332", "", true},
+		{"empty port", "localhost:", "8332", "", true},
+		{"missing host", ":8332", "8332", "", true},
+		{"ipv6 with port without brackets", "2001:db8::1:8332:x", "8332", "", true},
+		{"unclosed bracket", "[::1:8332", "8332", "", true},
+		{"unsupported scheme", "ftp://localhost:21", "", "", true},
+		{"invalid hostname", "bad host:8332", "", "", true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			normalized, err := NormalizeEndpoint(tc.addr, tc.defaultPort)
+
+			if tc.expectErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, normalized)
+
+			// normalization is idempotent
+			again, err := NormalizeEndpoint(normalized, tc.defaultPort)
+			require.NoError(t, err)
+			require.Equal(t, normalized, again)
+		})
+	}
+}
+
+func TestNormalizeRpcHostStripsScheme(t *testing.T) {
+	host, err := NormalizeRpcHost("http://[::1]", "18443")
+	require.NoError(t, err)
+	require.Equal(t, "[::1]:18443", host)
+
+	_, err = NormalizeRpcHost("http://localhost:8332/wallet", "")
+	require.Error(t, err)
+}
+
+func TestNormalizeEndpointsConfig(t *testing.T) {
+	newConfig := func(nodeBackend types.SupportedNodeBackend) *Config {
+		cfg := DefaultConfig()
+		cfg.ActiveNetParams = chaincfg.RegressionNetParams
+		cfg.BtcNodeBackendConfig.ActiveNodeBackend = nodeBackend
+		return &cfg
+	}
+
+	tests := []struct {
+		name        string
+		nodeBackend types.SupportedNodeBackend
+		setup       func(cfg *Config)
+		check       func(t *testing.T, cfg *Config)
+		errField    string
+	}{
+		{
+			name:        "wallet host without port",
+			nodeBackend: types.BitcoindNodeBackend,
+			setup:       func(cfg *Config) { cfg.WalletRpcConfig.Host = "wallet.internal" },
+			check: func(t *testing.T, cfg *Config) {
+				require.Equal(t, "wallet.internal:18443", cfg.WalletRpcConfig.Host)
+			},
+		},
+		{
+			name:        "wallet host ipv6",
+			nodeBackend: types.BitcoindNodeBackend,
+			setup:       func(cfg *Config) { cfg.WalletRpcConfig.Host = "fd00::2" },
+			check: func(t *testing.T, cfg *Config) {
+				require.Equal(t, "[fd00::2]:18443", cfg.WalletRpcConfig.Host)
+			},
+		},
+		{
+			name:        "invalid wallet host ignored without wallet",
+			nodeBackend: types.BitcoindNodeBackend,
+			setup: func(cfg *Config) {
+				cfg.WalletConfig.NoWallet = true
+				cfg.WalletRpcConfig.Host = "localhost:0"
+			},
+			check: func(t *testing.T, cfg *Config) {
+				require.Equal(t, "localhost:0", cfg.WalletRpcConfig.Host)
+			},
+		},
+		{
+			name:        "invalid wallet host",
+			nodeBackend: types.BitcoindNodeBackend,
+			setup:       func(cfg *Config) { cfg.WalletRpcConfig.Host = "localhost:0" },
+			errField:    "walletrpcconfig.wallethost",
+		},
+		{
+			name:        "bitcoind host with scheme and without port",
+			nodeBackend: types.BitcoindNodeBackend,
+			setup:       func(cfg *Config) { cfg.BtcNodeBackendConfig.Bitcoind.RPCHost = "http://bitcoind" },
+			check: func(t *testing.T, cfg *Config) {
+				require.Equal(t, "bitcoind:18443", cfg.BtcNodeBackendConfig.Bitcoind.RPCHost)
+			},
+		},
+		{
+			name:        "btcd host with non standard port",
+			nodeBackend: types.BtcdNodeBackend,
+			setup:       func(cfg *Config) { cfg.BtcNodeBackendConfig.Btcd.RPCHost = "btcd.example.com:28334" },
+			check: func(t *testing.T, cfg *Config) {
+				require.Equal(t, "btcd.example.com:28334", cfg.BtcNodeBackendConfig.Btcd.RPCHost)
+			},
+		},
+		{
+			name:        "btcd host without port",
+			nodeBackend: types.BtcdNodeBackend,
+			setup:       func(cfg *Config) { cfg.BtcNodeBackendConfig.Btcd.RPCHost = "[::1]" },
+			check: func(t *testing.T, cfg *Config) {
+				require.Equal(t, "[::1]:18334", cfg.BtcNodeBackendConfig.Btcd.RPCHost)
+			},
+		},
+		{
+			name:        "invalid btcd host",
+			nodeBackend: types.BtcdNodeBackend,
+			setup:       func(cfg *Config) { cfg.BtcNodeBackendConfig.Btcd.RPCHost = "::1:18334:1" },
+			errField:    "btcnodebackend.btcd.rpchost",
+		},
+		{
+			name:        "babylon addresses with ipv6",
+			nodeBackend: types.BitcoindNodeBackend,
+			setup: func(cfg *Config) {
+				cfg.BabylonConfig.RPCAddr = "http://[::1]:26657"
+				cfg.BabylonConfig.GRPCAddr = "https://grpc.example.com"
+			},
+			check: func(t *testing.T, cfg *Config) {
+				require.Equal(t, "http://[::1]:26657", cfg.BabylonConfig.RPCAddr)
+				require.Equal(t, "https://grpc.example.com", cfg.BabylonConfig.GRPCAddr)
+			},
+		},
+		{
+			name:        "babylon rpc address without scheme",
+			nodeBackend: types.BitcoindNodeBackend,
+			setup:       func(cfg *Config) { cfg.BabylonConfig.RPCAddr = "localhost:26657" },
+			errField:    "babylon.rpc-address",
+		},
+		{
+			name:        "invalid babylon grpc address",
+			nodeBackend: types.BitcoindNodeBackend,
+			setup:       func(cfg *Config) { cfg.BabylonConfig.GRPCAddr = "https://localhost:99999" },
+			errField:    "babylon.grpc-address",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := newConfig(tc.nodeBackend)
+			tc.setup(cfg)
+
+			err := normalizeEndpoints(cfg)
+
+			if tc.errField != "" {
+				require.ErrorContains(t, err, tc.errField)
+				return
+			}
+
+			require.NoError(t, err)
+			tc.check(t, cfg)
+		})
+	}
+}
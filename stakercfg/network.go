@@ -0,0 +1,73 @@
+package stakercfg
+
+import (
+	"encoding/hex"
+	"fmt"
+	"path/filepath"
+
+	"github.com/btcsuite/btcd/chaincfg"
+)
+
+// NetParams returns parameters of btc network selected in chain config
+func NetParams(cfg *ChainConfig) (*chaincfg.Params, error) {
+	switch cfg.Network {
+	case "testnet":
+		return &chaincfg.TestNet3Params, nil
+	case "regtest":
+		return &chaincfg.RegressionNetParams, nil
+	case "simnet":
+		return &chaincfg.SimNetParams, nil
+	case "signet":
+		// Let the user overwrite the default signet parameters.
+		// The challenge defines the actual signet network to
+		// join and the seed nodes are needed for network
+		// discovery.
+		sigNetChallenge := chaincfg.DefaultSignetChallenge
+		sigNetSeeds := chaincfg.DefaultSignetDNSSeeds
+		if cfg.SigNetChallenge != "" {
+			challenge, err := hex.DecodeString(cfg.SigNetChallenge)
+			if err != nil {
+				return nil, fmt.Errorf("Invalid signet challenge, "+
+					"hex decode failed: %v", err)
+			}
+			sigNetChallenge = challenge
+		}
+
+		chainParams := chaincfg.CustomSignetParams(
+			sigNetChallenge, sigNetSeeds,
+		)
+		return &chainParams, nil
+	default:
+		return nil, fmt.Errorf("invalid network: %v", cfg.Network)
+	}
+}
+
+// NetworkDir returns subdirectory of dir which holds data of given network. Data
+// of each network is kept separately, so that daemon switched to other network
+// does not misinterpret data of the previous one.
+func NetworkDir(dir string, network string) string {
+	return filepath.Join(dir, network)
+}
+
+// checkLegacyDbLayout returns error if bbolt database is stored directly in
+// configured db directory, as done by previous versions of staker, and there is
+// no database in the directory of the active network yet. Starting with empty
+// database would make daemon lose track of existing delegations.
+func checkLegacyDbLayout(dbCfg *DBConfig, baseDbPath, baseLogDir, network string) error {
+	if dbCfg.Backend != "" && dbCfg.Backend != BoltDbBackend {
+		return nil
+	}
+
+	legacyDbFile := filepath.Join(baseDbPath, dbCfg.DBFileName)
+	dbFile := filepath.Join(dbCfg.DBPath, dbCfg.DBFileName)
+
+	if !FileExists(legacyDbFile) || FileExists(dbFile) {
+		return nil
+	}
+
+	return fmt.Errorf(
+		"database %s is not stored in directory of %s network. Stop the daemon and "+
+			"move it to %s with: stakercli admin migrate-data-dir --network %s --dbpath %s --logdir %s",
+		legacyDbFile, network, dbFile, network, baseDbPath, baseLogDir,
+	)
+}
@@ -0,0 +1,110 @@
+package stakercfg
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+
+	"github.com/babylonchain/btc-staker/types"
+	"github.com/babylonchain/btc-staker/utils"
+	"github.com/btcsuite/btcd/rpcclient"
+)
+
+// ProxyConfig holds configuration of SOCKS5 proxy (e.g Tor) used for outbound
+// connections. Each target is proxied only if explicitly enabled.
+//
+//nolint:lll
+type ProxyConfig struct {
+	Address string `long:"address" description:"Address of SOCKS5 proxy in host:port format e.g 127.0.0.1:9050 for Tor"`
+	User    string `long:"user" description:"Username for proxy authentication, empty if proxy does not require authentication"`
+	Pass    string `long:"pass" default-mask:"-" description:"Password for proxy authentication"`
+	BtcNode bool   `long:"btcnode" description:"Connect to btc node backend, including fee estimation, through the proxy. With bitcoind backend requires bitcoind.rpcpolling, as zmq connections can't be proxied"`
+	Wallet  bool   `long:"wallet" description:"Connect to btc wallet through the proxy"`
+	Babylon bool   `long:"babylon" description:"Connect to babylon node through the proxy"`
+}
+
+func DefaultProxyConfig() ProxyConfig {
+	return ProxyConfig{}
+}
+
+func (c *ProxyConfig) anyTargetEnabled() bool {
+	return c.BtcNode || c.Wallet || c.Babylon
+}
+
+func (c *ProxyConfig) ifEnabled(targetEnabled bool) *ProxyConfig {
+	if !targetEnabled {
+		return nil
+	}
+
+	return c
+}
+
+// BtcNodeProxy returns proxy config if btc node is connected through the proxy,
+// nil otherwise
+func (c *ProxyConfig) BtcNodeProxy() *ProxyConfig {
+	return c.ifEnabled(c.BtcNode)
+}
+
+// WalletProxy returns proxy config if btc wallet is connected through the proxy,
+// nil otherwise
+func (c *ProxyConfig) WalletProxy() *ProxyConfig {
+	return c.ifEnabled(c.Wallet)
+}
+
+// BabylonProxy returns proxy config if babylon node is connected through the
+// proxy, nil otherwise
+func (c *ProxyConfig) BabylonProxy() *ProxyConfig {
+	return c.ifEnabled(c.Babylon)
+}
+
+// Dialer returns dialer connecting through the proxy
+func (c *ProxyConfig) Dialer() *utils.ProxyDialer {
+	return utils.NewProxyDialer(c.Address, c.User, c.Pass)
+}
+
+// ApplyToRpcClient configures btc rpc client connection to go through the proxy.
+// Http post mode clients take proxy url, websocket clients take proxy address and
+// credentials. In both cases host name of the target is resolved by the proxy.
+func (c *ProxyConfig) ApplyToRpcClient(connCfg *rpcclient.ConnConfig) {
+	if !connCfg.HTTPPostMode {
+		connCfg.Proxy = c.Address
+		connCfg.ProxyUser = c.User
+		connCfg.ProxyPass = c.Pass
+		return
+	}
+
+	proxyUrl := url.URL{
+		Scheme: "socks5",
+		Host:   c.Address,
+	}
+
+	if c.User != "" {
+		proxyUrl.User = url.UserPassword(c.User, c.Pass)
+	}
+
+	connCfg.Proxy = proxyUrl.String()
+}
+
+func validateProxyConfig(cfg *Config) error {
+	proxyCfg := cfg.ProxyConfig
+
+	if !proxyCfg.anyTargetEnabled() {
+		if proxyCfg.Address != "" {
+			return fmt.Errorf("proxy.address is set, but none of proxy.btcnode, proxy.wallet, proxy.babylon is enabled")
+		}
+
+		return nil
+	}
+
+	if _, _, err := net.SplitHostPort(proxyCfg.Address); err != nil {
+		return fmt.Errorf("invalid proxy.address %q, expected host:port: %w", proxyCfg.Address, err)
+	}
+
+	if proxyCfg.BtcNode &&
+		cfg.BtcNodeBackendConfig.ActiveNodeBackend == types.BitcoindNodeBackend &&
+		!cfg.BtcNodeBackendConfig.Bitcoind.RPCPolling {
+		return fmt.Errorf("proxy.btcnode requires bitcoind.rpcpolling, as zmq connections can't be proxied")
+	}
+
+	return nil
+}
@@ -0,0 +1,456 @@
+package stakerdb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"time"
+
+	"github.com/babylonchain/btc-staker/proto"
+	pm "google.golang.org/protobuf/proto"
+
+	"github.com/lightningnetwork/lnd/kvdb"
+)
+
+var (
+	// mapping uint64 -> proto.TrackedTransaction
+	// It holds archived transactions under the same keys they had in transactions
+	// bucket. Transaction index still points to them, so they can be found by
+	// hash, but they are not scanned with tracked transactions.
+	archivedTransactionsBucketName = []byte("archived")
+
+	// mapping proto.TransactionState -> uint64
+	// It holds number of archived transactions in each state
+	archivedStateCountsBucketName = []byte("archivedStateCounts")
+
+	// key of proto.ArchiveJob in settings bucket, present only while archiving
+	// is not finished
+	archiveJobKey = []byte("aj")
+)
+
+// maximum number of transactions scanned in single db transaction, so that
+// archiving does not block other writers for long
+var archiveBatchSize = 200
+
+// IsArchivableState returns true for states of transactions which can be
+// archived. Only terminal states are archivable, as archived transactions cannot
+// be modified anymore, e.g unbonded funds must still be spent.
+func IsArchivableState(state proto.TransactionState) bool {
+	return state == proto.TransactionState_SPENT_ON_BTC ||
+		state == proto.TransactionState_CONFLICTED
+}
+
+// ArchiveJob describes archiving of transactions, which is done in batches and
+// resumed after restart if it was interrupted
+type ArchiveJob struct {
+	// transactions which entered their state before this time are archived
+	OlderThan time.Time
+	States    []proto.TransactionState
+	// number of transactions archived so far
+	Archived  uint64
+	StartedAt time.Time
+	// true once all transactions were scanned
+	Done bool
+}
+
+func protoArchiveJobToArchiveJob(j *proto.ArchiveJob) *ArchiveJob {
+	return &ArchiveJob{
+		OlderThan: time.Unix(j.OlderThan, 0),
+		States:    j.States,
+		Archived:  j.Archived,
+		StartedAt: time.Unix(j.StartedAt, 0),
+	}
+}
+
+func getArchiveJob(settingsBucket kvdb.RBucket) (*proto.ArchiveJob, error) {
+	jobBytes := settingsBucket.Get(archiveJobKey)
+
+	if jobBytes == nil {
+		return nil, nil
+	}
+
+	var job proto.ArchiveJob
+	if err := pm.Unmarshal(jobBytes, &job); err != nil {
+		return nil, ErrCorruptedTransactionsDb
+	}
+
+	return &job, nil
+}
+
+// archivedSince returns time since when transaction is in its state. For
+// transactions which changed state before times of state changes were stored,
+// block time of confirmation which moved transaction to its state is used. Zero
+// time is returned if neither is known.
+func archivedSince(tx *StoredTransaction) time.Time {
+	if !tx.StateChangedAt.IsZero() {
+		return tx.StateChangedAt
+	}
+
+	var confirmation *BtcConfirmationInfo
+
+	switch {
+	case tx.State == proto.TransactionState_SPENT_ON_BTC && tx.SpendTxData != nil:
+		confirmation = tx.SpendTxData.SpendTxConfirmationInfo
+	case tx.State == proto.TransactionState_UNBONDING_CONFIRMED_ON_BTC && tx.UnbondingTxData != nil:
+		confirmation = tx.UnbondingTxData.UnbondingTxConfirmationInfo
+	}
+
+	if confirmation == nil {
+		return time.Time{}
+	}
+
+	return confirmation.BlockTime
+}
+
+// hasPendingWork returns true if staker still has to do something with the
+// transaction, i.e. sweep its unbonded funds or deliver its recorded event
+func hasPendingWork(tx kvdb.RTx, txHashBytes []byte) (bool, error) {
+	sweepIntentsBucket := tx.ReadBucket(sweepIntentsBucketName)
+	if sweepIntentsBucket == nil {
+		return false, ErrCorruptedTransactionsDb
+	}
+
+	if sweepIntentsBucket.Get(txHashBytes) != nil {
+		return true, nil
+	}
+
+	eventIntentsBucket := tx.ReadBucket(eventIntentsBucketName)
+	if eventIntentsBucket == nil {
+		return false, ErrCorruptedTransactionsDb
+	}
+
+	k, _ := eventIntentsBucket.ReadCursor().Seek(txHashBytes)
+
+	return k != nil && bytes.HasPrefix(k, txHashBytes), nil
+}
+
+// StartArchive starts archiving of transactions in given states, which entered
+// their state before olderThan. Transactions are moved to archive by subsequent
+// calls to ArchiveNextBatch. Only one archiving can run at a time.
+func (c *TrackedTransactionStore) StartArchive(
+	olderThan time.Time,
+	states []proto.TransactionState,
+) (*ArchiveJob, error) {
+	if len(states) == 0 {
+		return nil, fmt.Errorf("%w: at least one state to archive is required", ErrStateNotArchivable)
+	}
+
+	for _, state := range states {
+		if !IsArchivableState(state) {
+			return nil, fmt.Errorf("%w: %s", ErrStateNotArchivable, state)
+		}
+	}
+
+	job := &proto.ArchiveJob{
+		OlderThan: olderThan.Unix(),
+		States:    states,
+		StartedAt: c.now().Unix(),
+	}
+
+	marshalled, err := pm.Marshal(job)
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = kvdb.Batch(c.db, func(tx kvdb.RwTx) error {
+		settingsBucket := tx.ReadWriteBucket(settingsBucketName)
+
+		if settingsBucket == nil {
+			return ErrCorruptedTransactionsDb
+		}
+
+		if settingsBucket.Get(archiveJobKey) != nil {
+			return ErrArchiveInProgress
+		}
+
+		return settingsBucket.Put(archiveJobKey, marshalled)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return protoArchiveJobToArchiveJob(job), nil
+}
+
+// PendingArchive returns archiving which was started but not finished, nil if
+// there is none
+func (c *TrackedTransactionStore) PendingArchive() (*ArchiveJob, error) {
+	var job *ArchiveJob
+
+	err := c.db.View(func(tx kvdb.RTx) error {
+		settingsBucket := tx.ReadBucket(settingsBucketName)
+
+		if settingsBucket == nil {
+			return ErrCorruptedTransactionsDb
+		}
+
+		storedJob, err := getArchiveJob(settingsBucket)
+
+		if err != nil || storedJob == nil {
+			return err
+		}
+
+		job = protoArchiveJobToArchiveJob(storedJob)
+		return nil
+	}, func() {
+		job = nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return job, nil
+}
+
+// ArchiveNextBatch scans next batch of tracked transactions and moves the ones
+// matching pending archiving to archive. Progress is stored together with moved
+// transactions, so interrupted archiving continues where it stopped. Returned
+// job is done once all transactions were scanned, after which it is removed.
+// Transactions which still have sweep or event intents are not archived.
+func (c *TrackedTransactionStore) ArchiveNextBatch() (*ArchiveJob, error) {
+	var result *ArchiveJob
+
+	err := kvdb.Batch(c.db, func(tx kvdb.RwTx) error {
+		settingsBucket := tx.ReadWriteBucket(settingsBucketName)
+		if settingsBucket == nil {
+			return ErrCorruptedTransactionsDb
+		}
+
+		transactionsBucket := tx.ReadWriteBucket(transactionBucketName)
+		if transactionsBucket == nil {
+			return ErrCorruptedTransactionsDb
+		}
+
+		archiveBucket := tx.ReadWriteBucket(archivedTransactionsBucketName)
+		if archiveBucket == nil {
+			return ErrCorruptedTransactionsDb
+		}
+
+		job, err := getArchiveJob(settingsBucket)
+
+		if err != nil {
+			return err
+		}
+
+		if job == nil {
+			return ErrArchiveNotStarted
+		}
+
+		states := make(map[proto.TransactionState]struct{}, len(job.States))
+		for _, state := range job.States {
+			// job could have been started by older version, which allowed
+			// archiving non terminal states
+			if IsArchivableState(state) {
+				states[state] = struct{}{}
+			}
+		}
+
+		olderThan := time.Unix(job.OlderThan, 0)
+
+		type archivedTx struct {
+			key   []byte
+			value []byte
+			state proto.TransactionState
+		}
+
+		var toArchive []archivedTx
+		var scanned int
+
+		cursor := transactionsBucket.ReadCursor()
+		k, v := cursor.Seek(uint64KeyToBytes(job.LastScannedKey + 1))
+
+		for ; k != nil && scanned < archiveBatchSize; k, v = cursor.Next() {
+			scanned++
+			job.LastScannedKey = binary.BigEndian.Uint64(k)
+
+			// transactions which cannot be decoded are left for quarantine
+			_, storedTx, err := decodeStoredTransaction(v)
+
+			if err != nil {
+				continue
+			}
+
+			if _, ok := states[storedTx.State]; !ok {
+				continue
+			}
+
+			since := archivedSince(storedTx)
+
+			if since.IsZero() || !since.Before(olderThan) {
+				continue
+			}
+
+			stakingTxHash := storedTx.StakingTx.TxHash()
+			pending, err := hasPendingWork(tx, stakingTxHash[:])
+
+			if err != nil {
+				return err
+			}
+
+			if pending {
+				continue
+			}
+
+			// copy key and value, as they are only valid until bucket is modified
+			toArchive = append(toArchive, archivedTx{
+				key:   append([]byte(nil), k...),
+				value: append([]byte(nil), v...),
+				state: storedTx.State,
+			})
+		}
+
+		done := k == nil
+
+		for _, a := range toArchive {
+			if err := archiveBucket.Put(a.key, a.value); err != nil {
+				return err
+			}
+
+			if err := transactionsBucket.Delete(a.key); err != nil {
+				return err
+			}
+
+			if err := changeStateCount(tx, a.state, -1); err != nil {
+				return err
+			}
+
+			if err := changeCount(tx, archivedStateCountsBucketName, a.state, 1); err != nil {
+				return err
+			}
+		}
+
+		job.Archived += uint64(len(toArchive))
+
+		result = protoArchiveJobToArchiveJob(job)
+		result.Done = done
+
+		if done {
+			return settingsBucket.Delete(archiveJobKey)
+		}
+
+		marshalled, err := pm.Marshal(job)
+
+		if err != nil {
+			return err
+		}
+
+		return settingsBucket.Put(archiveJobKey, marshalled)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// ArchivedStateCounts returns number of archived transactions in each state.
+// States without any archived transactions are omitted.
+func (c *TrackedTransactionStore) ArchivedStateCounts() (map[proto.TransactionState]uint64, error) {
+	return c.readCounts(archivedStateCountsBucketName)
+}
+
+// archivedCount returns number of all archived transactions
+func archivedCount(tx kvdb.RTx) (uint64, error) {
+	countsBucket := tx.ReadBucket(archivedStateCountsBucketName)
+
+	if countsBucket == nil {
+		return 0, ErrCorruptedTransactionsDb
+	}
+
+	var total uint64
+
+	err := countsBucket.ForEach(func(_, v []byte) error {
+		total += binary.BigEndian.Uint64(v)
+		return nil
+	})
+
+	return total, err
+}
+
+// mergedCursor iterates over keys of two buckets in order, as if they were one
+// bucket. Keys of the buckets must not overlap.
+type mergedCursor struct {
+	a, b kvdb.RCursor
+	// key at which cursor is positioned, nil if it is not positioned
+	current []byte
+}
+
+func newMergedCursor(a, b kvdb.RCursor) *mergedCursor {
+	return &mergedCursor{a: a, b: b}
+}
+
+// pick positions cursor at the smaller of two keys, or the larger one if max is
+// set. Nil keys are ignored.
+func (c *mergedCursor) pick(ka, va, kb, vb []byte, max bool) ([]byte, []byte) {
+	switch {
+	case ka == nil && kb == nil:
+		c.current = nil
+		return nil, nil
+	case kb == nil:
+		c.current = ka
+		return ka, va
+	case ka == nil:
+		c.current = kb
+		return kb, vb
+	}
+
+	if (bytes.Compare(ka, kb) < 0) != max {
+		c.current = ka
+		return ka, va
+	}
+
+	c.current = kb
+	return kb, vb
+}
+
+func (c *mergedCursor) First() ([]byte, []byte) {
+	ka, va := c.a.First()
+	kb, vb := c.b.First()
+	return c.pick(ka, va, kb, vb, false)
+}
+
+func (c *mergedCursor) Last() ([]byte, []byte) {
+	ka, va := c.a.Last()
+	kb, vb := c.b.Last()
+	return c.pick(ka, va, kb, vb, true)
+}
+
+func (c *mergedCursor) Seek(seek []byte) ([]byte, []byte) {
+	ka, va := c.a.Seek(seek)
+	kb, vb := c.b.Seek(seek)
+	return c.pick(ka, va, kb, vb, false)
+}
+
+func (c *mergedCursor) Next() ([]byte, []byte) {
+	if c.current == nil {
+		return nil, nil
+	}
+
+	// smallest key greater than current one
+	return c.Seek(append(append([]byte(nil), c.current...), 0))
+}
+
+func (c *mergedCursor) Prev() ([]byte, []byte) {
+	if c.current == nil {
+		return nil, nil
+	}
+
+	current := append([]byte(nil), c.current...)
+
+	// largest key smaller than current one in given cursor
+	prev := func(cursor kvdb.RCursor) ([]byte, []byte) {
+		if k, _ := cursor.Seek(current); k == nil {
+			return cursor.Last()
+		}
+
+		return cursor.Prev()
+	}
+
+	ka, va := prev(c.a)
+	kb, vb := prev(c.b)
+	return c.pick(ka, va, kb, vb, true)
+}
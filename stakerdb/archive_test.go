@@ -0,0 +1,236 @@
+package stakerdb
+
+import (
+	"testing"
+	"time"
+
+	"github.com/babylonchain/btc-staker/proto"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/stretchr/testify/require"
+)
+
+func addSpentTestTransaction(t *testing.T, s *TrackedTransactionStore, value int64, label string) *chainhash.Hash {
+	txHash := addLabeledTestTransaction(t, s, value, label)
+	require.NoError(t, s.SetTxConfirmed(txHash, &chainhash.Hash{}, 10, time.Time{}))
+	require.NoError(t, s.SetTxSpentOnBtc(txHash, nil))
+	return txHash
+}
+
+func runArchive(t *testing.T, s *TrackedTransactionStore) *ArchiveJob {
+	for {
+		job, err := s.ArchiveNextBatch()
+		require.NoError(t, err)
+
+		if job.Done {
+			return job
+		}
+	}
+}
+
+func TestArchiveMovesOldTransactionsInRequestedStates(t *testing.T) {
+	s, _ := makeTestStore(t)
+
+	start := time.Unix(1700000000, 0)
+	s.now = func() time.Time { return start }
+
+	oldSpent := addSpentTestTransaction(t, s, 1000, "old")
+	confirmed := addLabeledTestTransaction(t, s, 2000, "old")
+	require.NoError(t, s.SetTxConfirmed(confirmed, &chainhash.Hash{}, 10, time.Time{}))
+
+	// spent transaction which unbonded funds are still to be swept
+	withIntent := addSpentTestTransaction(t, s, 3000, "")
+	require.NoError(t, s.SaveSweepIntent(&SweepIntent{StakingTxHash: *withIntent}))
+
+	s.now = func() time.Time { return start.Add(48 * time.Hour) }
+	recentSpent := addSpentTestTransaction(t, s, 4000, "")
+
+	job, err := s.StartArchive(start.Add(24*time.Hour), []proto.TransactionState{proto.TransactionState_SPENT_ON_BTC})
+	require.NoError(t, err)
+	require.False(t, job.Done)
+
+	job = runArchive(t, s)
+	require.Equal(t, uint64(1), job.Archived)
+
+	pending, err := s.PendingArchive()
+	require.NoError(t, err)
+	require.Nil(t, pending)
+
+	var scanned []chainhash.Hash
+	err = s.ScanTrackedTransactions(func(tx *StoredTransaction) error {
+		scanned = append(scanned, tx.StakingTx.TxHash())
+		return nil
+	}, func() { scanned = nil })
+	require.NoError(t, err)
+	require.ElementsMatch(t, []chainhash.Hash{*confirmed, *withIntent, *recentSpent}, scanned)
+
+	// archived transaction is still reachable by hash, but cannot be modified
+	archived, err := s.GetTransaction(oldSpent)
+	require.NoError(t, err)
+	require.True(t, archived.Archived)
+	require.Equal(t, proto.TransactionState_SPENT_ON_BTC, archived.State)
+
+	require.ErrorIs(t, s.SetTransactionLabel(oldSpent, "new"), ErrTransactionArchived)
+	require.ErrorIs(t, s.SetTxSpentOnBtc(oldSpent, nil), ErrTransactionArchived)
+	require.ErrorIs(t, s.QuarantineTransaction(oldSpent), ErrTransactionArchived)
+
+	counts, err := s.TransactionStateCounts()
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), counts[proto.TransactionState_SPENT_ON_BTC])
+
+	archivedCounts, err := s.ArchivedStateCounts()
+	require.NoError(t, err)
+	require.Equal(t, map[proto.TransactionState]uint64{
+		proto.TransactionState_SPENT_ON_BTC: 1,
+	}, archivedCounts)
+
+	issues, err := s.CheckIntegrity()
+	require.NoError(t, err)
+	require.Empty(t, issues)
+}
+
+func TestArchivedTransactionsAreQueriedOnlyOnRequest(t *testing.T) {
+	s, _ := makeTestStore(t)
+
+	start := time.Unix(1700000000, 0)
+	s.now = func() time.Time { return start }
+
+	var hashes []*chainhash.Hash
+	for i := 0; i < 6; i++ {
+		// every second transaction is archived
+		if i%2 == 0 {
+			hashes = append(hashes, addSpentTestTransaction(t, s, int64(1000+i), "lbl"))
+		} else {
+			hashes = append(hashes, addLabeledTestTransaction(t, s, int64(1000+i), "lbl"))
+		}
+	}
+
+	_, err := s.StartArchive(start.Add(time.Hour), []proto.TransactionState{proto.TransactionState_SPENT_ON_BTC})
+	require.NoError(t, err)
+	runArchive(t, s)
+
+	queryHashes := func(q StoredTransactionQuery) ([]chainhash.Hash, []bool, uint64) {
+		resp, err := s.QueryStoredTransactions(q)
+		require.NoError(t, err)
+
+		var txHashes []chainhash.Hash
+		var archived []bool
+		for _, tx := range resp.Transactions {
+			txHashes = append(txHashes, tx.StakingTx.TxHash())
+			archived = append(archived, tx.Archived)
+		}
+
+		return txHashes, archived, resp.Total
+	}
+
+	for _, label := range []string{"", "lbl"} {
+		q := DefaultStoredTransactionQuery()
+		q.Label = label
+
+		txHashes, _, total := queryHashes(q)
+		require.Equal(t, []chainhash.Hash{*hashes[1], *hashes[3], *hashes[5]}, txHashes)
+		require.Equal(t, uint64(3), total)
+
+		q.IncludeArchived = true
+		txHashes, archived, total := queryHashes(q)
+		require.Len(t, txHashes, 6)
+		require.Equal(t, uint64(6), total)
+
+		for i, h := range hashes {
+			require.Equal(t, *h, txHashes[i])
+			require.Equal(t, i%2 == 0, archived[i])
+		}
+	}
+
+	// pages of merged transactions and archive, in both directions
+	q := DefaultStoredTransactionQuery()
+	q.IncludeArchived = true
+	q.IndexOffset = 2
+	q.NumMaxTransactions = 3
+
+	txHashes, _, _ := queryHashes(q)
+	require.Equal(t, []chainhash.Hash{*hashes[2], *hashes[3], *hashes[4]}, txHashes)
+
+	q.Reversed = true
+	q.IndexOffset = 5
+	txHashes, _, _ = queryHashes(q)
+	require.Equal(t, []chainhash.Hash{*hashes[1], *hashes[2], *hashes[3]}, txHashes)
+}
+
+func TestArchiveResumesAfterInterruption(t *testing.T) {
+	s, backend := makeTestStore(t)
+
+	prevBatchSize := archiveBatchSize
+	archiveBatchSize = 1
+	t.Cleanup(func() { archiveBatchSize = prevBatchSize })
+
+	start := time.Unix(1700000000, 0)
+	s.now = func() time.Time { return start }
+
+	for i := 0; i < 3; i++ {
+		addSpentTestTransaction(t, s, int64(1000+i), "")
+	}
+
+	states := []proto.TransactionState{proto.TransactionState_SPENT_ON_BTC}
+
+	_, err := s.StartArchive(start.Add(time.Hour), states)
+	require.NoError(t, err)
+
+	job, err := s.ArchiveNextBatch()
+	require.NoError(t, err)
+	require.False(t, job.Done)
+	require.Equal(t, uint64(1), job.Archived)
+
+	_, err = s.StartArchive(start.Add(time.Hour), states)
+	require.ErrorIs(t, err, ErrArchiveInProgress)
+
+	// store opened after restart continues pending archiving
+	restarted, err := NewTrackedTransactionStore(backend)
+	require.NoError(t, err)
+
+	pending, err := restarted.PendingArchive()
+	require.NoError(t, err)
+	require.NotNil(t, pending)
+	require.Equal(t, uint64(1), pending.Archived)
+
+	job = runArchive(t, restarted)
+	require.Equal(t, uint64(3), job.Archived)
+
+	_, err = restarted.ArchiveNextBatch()
+	require.ErrorIs(t, err, ErrArchiveNotStarted)
+}
+
+func TestStartArchiveRejectsActiveStates(t *testing.T) {
+	s, _ := makeTestStore(t)
+
+	_, err := s.StartArchive(time.Now(), []proto.TransactionState{proto.TransactionState_DELEGATION_ACTIVE})
+	require.ErrorIs(t, err, ErrStateNotArchivable)
+
+	_, err = s.StartArchive(time.Now(), nil)
+	require.ErrorIs(t, err, ErrStateNotArchivable)
+}
+
+func TestUnbondedTransactionIsNotArchived(t *testing.T) {
+	s, _ := makeTestStore(t)
+
+	start := time.Unix(1700000000, 0)
+	s.now = func() time.Time { return start }
+
+	unbonded := addTestTransactionInState(t, s, 1000, proto.TransactionState_UNBONDING_CONFIRMED_ON_BTC)
+
+	_, err := s.StartArchive(start.Add(time.Hour), []proto.TransactionState{proto.TransactionState_UNBONDING_CONFIRMED_ON_BTC})
+	require.ErrorIs(t, err, ErrStateNotArchivable)
+
+	// archiving of terminal states leaves unbonded transaction in working set
+	_, err = s.StartArchive(start.Add(time.Hour), []proto.TransactionState{proto.TransactionState_SPENT_ON_BTC})
+	require.NoError(t, err)
+	job := runArchive(t, s)
+	require.Equal(t, uint64(0), job.Archived)
+
+	// unbonded funds can still be spent
+	require.NoError(t, s.SetTxSpentOnBtc(unbonded, nil))
+
+	tx, err := s.GetTransaction(unbonded)
+	require.NoError(t, err)
+	require.False(t, tx.Archived)
+	require.Equal(t, proto.TransactionState_SPENT_ON_BTC, tx.State)
+}
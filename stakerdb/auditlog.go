@@ -0,0 +1,231 @@
+package stakerdb
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"time"
+
+	"github.com/babylonchain/btc-staker/proto"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	pm "google.golang.org/protobuf/proto"
+
+	"github.com/lightningnetwork/lnd/kvdb"
+)
+
+var (
+	// mapping uint64 -> proto.AuditLogEntry
+	// It holds append only log of fund moving operations performed by staker. Each
+	// entry commits to the hash of the previous entry, so that removing or modifying
+	// entries can be detected.
+	auditLogBucketName = []byte("auditLog")
+)
+
+type AuditLogEntry struct {
+	Seq       uint64
+	Type      proto.AuditEntryType
+	Timestamp time.Time
+	Caller    string
+	Method    string
+	// json encoded arguments of the operation
+	Args string
+	// only set if type is AUDIT_OPERATION_FINISHED
+	RequestSeq uint64
+	// only set if operation sent btc transaction
+	TxHash *chainhash.Hash
+	// only set if operation started asynchronous staking request
+	StakingRequestId string
+	// only set if operation failed
+	Error    string
+	PrevHash []byte
+	// sha256 hash of serialized entry, not stored as part of the entry itself
+	Hash []byte
+}
+
+func auditLogEntryToProto(e *AuditLogEntry) *proto.AuditLogEntry {
+	var txHash []byte
+	if e.TxHash != nil {
+		txHash = e.TxHash.CloneBytes()
+	}
+
+	return &proto.AuditLogEntry{
+		Seq:              e.Seq,
+		Type:             e.Type,
+		Timestamp:        e.Timestamp.Unix(),
+		Caller:           e.Caller,
+		Method:           e.Method,
+		Args:             e.Args,
+		RequestSeq:       e.RequestSeq,
+		TxHash:           txHash,
+		StakingRequestId: e.StakingRequestId,
+		Error:            e.Error,
+		PrevHash:         e.PrevHash,
+	}
+}
+
+func protoAuditLogEntryToEntry(e *proto.AuditLogEntry, hash []byte) (*AuditLogEntry, error) {
+	var txHash *chainhash.Hash
+	if len(e.TxHash) > 0 {
+		h, err := chainhash.NewHash(e.TxHash)
+
+		if err != nil {
+			return nil, err
+		}
+
+		txHash = h
+	}
+
+	return &AuditLogEntry{
+		Seq:              e.Seq,
+		Type:             e.Type,
+		Timestamp:        time.Unix(e.Timestamp, 0),
+		Caller:           e.Caller,
+		Method:           e.Method,
+		Args:             e.Args,
+		RequestSeq:       e.RequestSeq,
+		TxHash:           txHash,
+		StakingRequestId: e.StakingRequestId,
+		Error:            e.Error,
+		PrevHash:         e.PrevHash,
+		Hash:             hash,
+	}, nil
+}
+
+func auditEntryHash(entryBytes []byte) []byte {
+	hash := sha256.Sum256(entryBytes)
+	return hash[:]
+}
+
+// AppendAuditEntry appends entry to the end of the audit log. Sequence number and
+// hash of the previous entry are assigned by the store, values provided by the
+// caller are ignored. It returns entry as it was stored.
+func (c *TrackedTransactionStore) AppendAuditEntry(entry *AuditLogEntry) (*AuditLogEntry, error) {
+	var stored *AuditLogEntry
+
+	err := kvdb.Update(c.db, func(tx kvdb.RwTx) error {
+		auditBucket := tx.ReadWriteBucket(auditLogBucketName)
+
+		if auditBucket == nil {
+			return ErrCorruptedTransactionsDb
+		}
+
+		var nextSeq uint64 = 1
+		var prevHash []byte
+
+		lastKey, lastEntryBytes := auditBucket.ReadWriteCursor().Last()
+
+		if lastKey != nil {
+			nextSeq = binary.BigEndian.Uint64(lastKey) + 1
+			prevHash = auditEntryHash(lastEntryBytes)
+		}
+
+		toStore := *entry
+		toStore.Seq = nextSeq
+		toStore.PrevHash = prevHash
+
+		entryBytes, err := pm.Marshal(auditLogEntryToProto(&toStore))
+
+		if err != nil {
+			return err
+		}
+
+		if err := auditBucket.Put(uint64KeyToBytes(nextSeq), entryBytes); err != nil {
+			return err
+		}
+
+		toStore.Hash = auditEntryHash(entryBytes)
+		stored = &toStore
+
+		return nil
+	}, func() {
+		stored = nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return stored, nil
+}
+
+// QueryAuditLog returns at most limit audit log entries, starting from entry with
+// sequence number fromSeq. Links between returned entries are verified, and
+// ErrAuditLogTampered is returned if any of them is broken.
+func (c *TrackedTransactionStore) QueryAuditLog(fromSeq uint64, limit uint64) ([]AuditLogEntry, error) {
+	var entries []AuditLogEntry
+
+	err := c.db.View(func(tx kvdb.RTx) error {
+		auditBucket := tx.ReadBucket(auditLogBucketName)
+
+		if auditBucket == nil {
+			return ErrCorruptedTransactionsDb
+		}
+
+		if fromSeq == 0 {
+			fromSeq = 1
+		}
+
+		cursor := auditBucket.ReadCursor()
+
+		// start from the entry preceding requested one, so that link of the first
+		// returned entry can be verified
+		var expectedPrevHash []byte
+		if fromSeq > 1 {
+			k, v := cursor.Seek(uint64KeyToBytes(fromSeq - 1))
+
+			if k == nil {
+				// requested entries are past the end of the log
+				return nil
+			}
+
+			if binary.BigEndian.Uint64(k) != fromSeq-1 {
+				return fmt.Errorf("entry %d is missing: %w", fromSeq-1, ErrAuditLogTampered)
+			}
+
+			expectedPrevHash = auditEntryHash(v)
+		}
+
+		expectedSeq := fromSeq
+
+		for k, v := cursor.Seek(uint64KeyToBytes(fromSeq)); k != nil && uint64(len(entries)) < limit; k, v = cursor.Next() {
+			var entryProto proto.AuditLogEntry
+
+			if err := pm.Unmarshal(v, &entryProto); err != nil {
+				return ErrCorruptedTransactionsDb
+			}
+
+			seq := binary.BigEndian.Uint64(k)
+
+			if seq != expectedSeq || entryProto.Seq != seq {
+				return fmt.Errorf("entry %d is missing: %w", expectedSeq, ErrAuditLogTampered)
+			}
+
+			if !bytes.Equal(entryProto.PrevHash, expectedPrevHash) {
+				return fmt.Errorf("entry %d does not match hash of the previous entry: %w", seq, ErrAuditLogTampered)
+			}
+
+			hash := auditEntryHash(v)
+
+			entry, err := protoAuditLogEntryToEntry(&entryProto, hash)
+
+			if err != nil {
+				return err
+			}
+
+			entries = append(entries, *entry)
+			expectedPrevHash = hash
+			expectedSeq++
+		}
+
+		return nil
+	}, func() {
+		entries = nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
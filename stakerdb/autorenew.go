@@ -0,0 +1,98 @@
+package stakerdb
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/babylonchain/btc-staker/proto"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+)
+
+// protoOptionalHash decodes hash which is not set in older records
+func protoOptionalHash(hashBytes []byte) (*chainhash.Hash, error) {
+	if len(hashBytes) == 0 {
+		return nil, nil
+	}
+
+	return chainhash.NewHash(hashBytes)
+}
+
+// SetAutoRenew enables or disables automatic renewal of staking transaction.
+// Enabling renewal clears reason why previous renewal was skipped.
+func (c *TrackedTransactionStore) SetAutoRenew(txHash *chainhash.Hash, enabled bool) error {
+	setAutoRenew := func(tx *proto.TrackedTransaction) error {
+		tx.AutoRenew = enabled
+
+		if enabled {
+			tx.AutoRenewSkipReason = ""
+		}
+
+		return nil
+	}
+
+	return c.setTxState(txHash, setAutoRenew)
+}
+
+// SkipAutoRenew disables automatic renewal of staking transaction and records
+// why it was skipped
+func (c *TrackedTransactionStore) SkipAutoRenew(txHash *chainhash.Hash, reason string) error {
+	if reason == "" {
+		return fmt.Errorf("skip reason must not be empty")
+	}
+
+	skip := func(tx *proto.TrackedTransaction) error {
+		tx.AutoRenew = false
+		tx.AutoRenewSkipReason = reason
+		return nil
+	}
+
+	return c.setTxState(txHash, skip)
+}
+
+// SetTxRenewed records that staking output of staking transaction is spent by
+// renewal staking transaction. It is recorded before renewal is broadcast, so
+// that transaction is never renewed twice. Link from renewal back to renewed
+// transaction is recorded when renewal is added to the store.
+func (c *TrackedTransactionStore) SetTxRenewed(
+	stakingTxHash *chainhash.Hash,
+	renewalTxHash *chainhash.Hash,
+	spentValue btcutil.Amount,
+	fee btcutil.Amount,
+) error {
+	setRenewed := func(tx *proto.TrackedTransaction) error {
+		if len(tx.RenewalTxHash) > 0 {
+			return fmt.Errorf("%w: staking transaction %s", ErrAlreadyRenewed, stakingTxHash)
+		}
+
+		tx.SpendTxData = &proto.SpendTxData{
+			SpendTxHash: renewalTxHash.CloneBytes(),
+			SpentValue:  int64(spentValue),
+			Fee:         int64(fee),
+		}
+		tx.RenewalTxHash = renewalTxHash.CloneBytes()
+		return nil
+	}
+
+	return c.setTxState(stakingTxHash, setRenewed)
+}
+
+// ClearTxRenewal removes renewal recorded by SetTxRenewed, once it is known that
+// renewal transaction was not sent. Renewal recorded with different renewal
+// transaction is kept.
+func (c *TrackedTransactionStore) ClearTxRenewal(
+	stakingTxHash *chainhash.Hash,
+	renewalTxHash *chainhash.Hash,
+) error {
+	clearRenewal := func(tx *proto.TrackedTransaction) error {
+		if !bytes.Equal(tx.RenewalTxHash, renewalTxHash[:]) {
+			return fmt.Errorf("staking transaction %s is not renewed by %s", stakingTxHash, renewalTxHash)
+		}
+
+		tx.SpendTxData = nil
+		tx.RenewalTxHash = nil
+		return nil
+	}
+
+	return c.setTxState(stakingTxHash, clearRenewal)
+}
@@ -0,0 +1,141 @@
+package stakerdb
+
+import (
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/stretchr/testify/require"
+)
+
+func addAutoRenewTestTx(t *testing.T, s *TrackedTransactionStore, value int64, autoRenew bool) *wire.MsgTx {
+	priv, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	addr, err := btcutil.NewAddressPubKeyHash(make([]byte, 20), &chaincfg.RegressionNetParams)
+	require.NoError(t, err)
+
+	btcTx := wire.NewMsgTx(2)
+	btcTx.AddTxIn(wire.NewTxIn(&wire.OutPoint{}, nil, nil))
+	btcTx.AddTxOut(wire.NewTxOut(value, []byte{0x51}))
+
+	err = s.AddTransaction(
+		btcTx,
+		0,
+		100,
+		[]*btcec.PublicKey{priv.PubKey()},
+		&ProofOfPossession{BabylonSigOverBtcPk: []byte{1}, BtcSigOverBabylonSig: []byte{1}},
+		addr,
+		AddTransactionOpts{
+			AutoRenew: autoRenew,
+		},
+	)
+	require.NoError(t, err)
+
+	return btcTx
+}
+
+func TestAutoRenewFlag(t *testing.T) {
+	s, _ := makeTestStore(t)
+
+	txHash := addAutoRenewTestTx(t, s, 1000, true).TxHash()
+
+	storedTx, err := s.GetTransaction(&txHash)
+	require.NoError(t, err)
+	require.True(t, storedTx.AutoRenew)
+
+	require.Error(t, s.SkipAutoRenew(&txHash, ""))
+	require.NoError(t, s.SkipAutoRenew(&txHash, "finality provider is slashed"))
+
+	storedTx, err = s.GetTransaction(&txHash)
+	require.NoError(t, err)
+	require.False(t, storedTx.AutoRenew)
+	require.Equal(t, "finality provider is slashed", storedTx.AutoRenewSkipReason)
+
+	// enabling renewal again clears skip reason
+	require.NoError(t, s.SetAutoRenew(&txHash, true))
+
+	storedTx, err = s.GetTransaction(&txHash)
+	require.NoError(t, err)
+	require.True(t, storedTx.AutoRenew)
+	require.Empty(t, storedTx.AutoRenewSkipReason)
+
+	require.NoError(t, s.SetAutoRenew(&txHash, false))
+
+	storedTx, err = s.GetTransaction(&txHash)
+	require.NoError(t, err)
+	require.False(t, storedTx.AutoRenew)
+}
+
+func TestSetTxRenewedLinksTransactions(t *testing.T) {
+	s, _ := makeTestStore(t)
+
+	oldTxHash := addAutoRenewTestTx(t, s, 1000, true).TxHash()
+
+	// renewal is recorded before renewal transaction is sent and tracked
+	newTx := wire.NewMsgTx(2)
+	newTx.AddTxIn(wire.NewTxIn(wire.NewOutPoint(&oldTxHash, 0), nil, nil))
+	newTx.AddTxOut(wire.NewTxOut(900, []byte{0x51}))
+	newTxHash := newTx.TxHash()
+
+	require.NoError(t, s.SetTxRenewed(&oldTxHash, &newTxHash, 1000, 100))
+
+	oldTx, err := s.GetTransaction(&oldTxHash)
+	require.NoError(t, err)
+	require.Equal(t, &newTxHash, oldTx.RenewalTxHash)
+	require.Nil(t, oldTx.RenewedFromTxHash)
+	require.NotNil(t, oldTx.SpendTxData)
+	require.Equal(t, newTxHash, oldTx.SpendTxData.SpendTxHash)
+	require.Equal(t, btcutil.Amount(100), oldTx.SpendTxData.Fee)
+
+	// transaction is never renewed twice
+	otherTxHash := chainhash.Hash{1}
+	require.ErrorIs(t, s.SetTxRenewed(&oldTxHash, &otherTxHash, 1000, 100), ErrAlreadyRenewed)
+
+	priv, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+	addr, err := btcutil.NewAddressPubKeyHash(make([]byte, 20), &chaincfg.RegressionNetParams)
+	require.NoError(t, err)
+
+	err = s.AddTransaction(
+		newTx,
+		0,
+		100,
+		[]*btcec.PublicKey{priv.PubKey()},
+		&ProofOfPossession{BabylonSigOverBtcPk: []byte{1}, BtcSigOverBabylonSig: []byte{1}},
+		addr,
+		AddTransactionOpts{RenewedFromTxHash: &oldTxHash},
+	)
+	require.NoError(t, err)
+
+	storedNewTx, err := s.GetTransaction(&newTxHash)
+	require.NoError(t, err)
+	require.Equal(t, &oldTxHash, storedNewTx.RenewedFromTxHash)
+	require.Nil(t, storedNewTx.RenewalTxHash)
+}
+
+func TestClearTxRenewal(t *testing.T) {
+	s, _ := makeTestStore(t)
+
+	oldTxHash := addAutoRenewTestTx(t, s, 1000, true).TxHash()
+	newTxHash := chainhash.Hash{1}
+
+	require.NoError(t, s.SetTxRenewed(&oldTxHash, &newTxHash, 1000, 100))
+
+	// renewal by other transaction is kept
+	otherTxHash := chainhash.Hash{2}
+	require.Error(t, s.ClearTxRenewal(&oldTxHash, &otherTxHash))
+
+	require.NoError(t, s.ClearTxRenewal(&oldTxHash, &newTxHash))
+
+	oldTx, err := s.GetTransaction(&oldTxHash)
+	require.NoError(t, err)
+	require.Nil(t, oldTx.RenewalTxHash)
+	require.Nil(t, oldTx.SpendTxData)
+
+	// renewal can be recorded again
+	require.NoError(t, s.SetTxRenewed(&oldTxHash, &newTxHash, 1000, 100))
+}
@@ -0,0 +1,66 @@
+package stakerdb
+
+import (
+	"fmt"
+
+	"github.com/lightningnetwork/lnd/kvdb"
+)
+
+type sequenceBucket interface {
+	Sequence() uint64
+}
+
+func copyBucket(src kvdb.RBucket, dst kvdb.RwBucket) error {
+	if seqBucket, ok := src.(sequenceBucket); ok {
+		if err := dst.SetSequence(seqBucket.Sequence()); err != nil {
+			return err
+		}
+	}
+
+	return src.ForEach(func(k, v []byte) error {
+		// nil value denotes nested bucket
+		if v != nil {
+			return dst.Put(k, v)
+		}
+
+		nestedDst, err := dst.CreateBucket(k)
+		if err != nil {
+			return err
+		}
+
+		return copyBucket(src.NestedReadBucket(k), nestedDst)
+	})
+}
+
+// CopyDatabase copies all buckets of src database to dst database, which is used
+// to migrate data between db backends. Destination database must be empty, to
+// avoid merging data of two daemons. Data is copied in one transaction, so either
+// the whole database is copied or dst is left empty.
+func CopyDatabase(src kvdb.Backend, dst kvdb.Backend) error {
+	return kvdb.View(src, func(srcTx kvdb.RTx) error {
+		return kvdb.Update(dst, func(dstTx kvdb.RwTx) error {
+			var dstBuckets int
+			err := dstTx.ForEachBucket(func(_ []byte) error {
+				dstBuckets++
+				return nil
+			})
+
+			if err != nil {
+				return err
+			}
+
+			if dstBuckets > 0 {
+				return fmt.Errorf("destination database is not empty")
+			}
+
+			return srcTx.ForEachBucket(func(key []byte) error {
+				dstBucket, err := dstTx.CreateTopLevelBucket(key)
+				if err != nil {
+					return err
+				}
+
+				return copyBucket(srcTx.ReadBucket(key), dstBucket)
+			})
+		}, func() {})
+	}, func() {})
+}
@@ -0,0 +1,39 @@
+package stakerdb
+
+import (
+	"testing"
+	"time"
+
+	"github.com/babylonchain/btc-staker/stakerdb/dbtest"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCopyDatabase(t *testing.T) {
+	src, srcBackend := makeTestStore(t)
+
+	first := addTestTransaction(t, src, 1000)
+	addTestTransaction(t, src, 2000)
+	require.NoError(t, src.SetTxConfirmed(first, &chainhash.Hash{}, 10, time.Time{}))
+
+	dstBackend := dbtest.NewBackend(t)
+	require.NoError(t, CopyDatabase(srcBackend, dstBackend))
+
+	dst, err := NewTrackedTransactionStore(dstBackend)
+	require.NoError(t, err)
+
+	srcTxs, err := src.QueryStoredTransactions(DefaultStoredTransactionQuery())
+	require.NoError(t, err)
+	dstTxs, err := dst.QueryStoredTransactions(DefaultStoredTransactionQuery())
+	require.NoError(t, err)
+	require.Equal(t, srcTxs, dstTxs)
+
+	srcCounts, err := src.TransactionStateCounts()
+	require.NoError(t, err)
+	dstCounts, err := dst.TransactionStateCounts()
+	require.NoError(t, err)
+	require.Equal(t, srcCounts, dstCounts)
+
+	// copying into non empty database would merge data of two daemons
+	require.Error(t, CopyDatabase(srcBackend, dstBackend))
+}
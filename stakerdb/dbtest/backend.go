@@ -0,0 +1,83 @@
+// Package dbtest provides database backends for store tests. Tests run against
+// bbolt by default. The same tests can be run against postgres by setting
+// STAKER_TEST_DB_BACKEND=postgres and STAKER_TEST_POSTGRES_DSN, and building tests
+// with kvdb_postgres build tag, e.g. by running make test-postgres.
+package dbtest
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"os"
+	"testing"
+
+	"github.com/babylonchain/btc-staker/stakercfg"
+	"github.com/lightningnetwork/lnd/kvdb"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	backendEnv     = "STAKER_TEST_DB_BACKEND"
+	postgresDsnEnv = "STAKER_TEST_POSTGRES_DSN"
+)
+
+// Config returns db config of the backend selected for tests
+func Config(t *testing.T) stakercfg.DBConfig {
+	cfg := stakercfg.DefaultDBConfig()
+	cfg.DBPath = t.TempDir()
+
+	if os.Getenv(backendEnv) != stakercfg.PostgresDbBackend {
+		return cfg
+	}
+
+	// each test uses its own tables, so tests do not see each other data
+	prefix := make([]byte, 8)
+	_, err := rand.Read(prefix)
+	require.NoError(t, err)
+
+	cfg.Backend = stakercfg.PostgresDbBackend
+	cfg.Postgres.Dsn = os.Getenv(postgresDsnEnv)
+	cfg.Postgres.TablePrefix = "test_" + hex.EncodeToString(prefix)
+
+	return cfg
+}
+
+// NewBackend opens backend selected for tests, which is cleared and closed when
+// test finishes
+func NewBackend(t *testing.T) kvdb.Backend {
+	cfg := Config(t)
+
+	backend, err := stakercfg.GetDbBackend(&cfg)
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		if cfg.Backend == stakercfg.PostgresDbBackend {
+			require.NoError(t, clearBackend(backend))
+		}
+		backend.Close()
+	})
+
+	return backend
+}
+
+// clearBackend removes all data of the test, tables itself are left in the database
+func clearBackend(backend kvdb.Backend) error {
+	return kvdb.Update(backend, func(tx kvdb.RwTx) error {
+		var buckets [][]byte
+		err := tx.ForEachBucket(func(key []byte) error {
+			buckets = append(buckets, append([]byte(nil), key...))
+			return nil
+		})
+
+		if err != nil {
+			return err
+		}
+
+		for _, bucket := range buckets {
+			if err := tx.DeleteTopLevelBucket(bucket); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}, func() {})
+}
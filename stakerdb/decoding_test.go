@@ -0,0 +1,193 @@
+package stakerdb
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/babylonchain/btc-staker/proto"
+	"github.com/babylonchain/btc-staker/stakerdb/dbtest"
+	"github.com/babylonchain/btc-staker/utils"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/kvdb"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protowire"
+	pm "google.golang.org/protobuf/proto"
+)
+
+func testBtcTx(t testing.TB) (*wire.MsgTx, []byte) {
+	tx := wire.NewMsgTx(2)
+	tx.AddTxIn(wire.NewTxIn(&wire.OutPoint{}, nil, nil))
+	tx.AddTxOut(wire.NewTxOut(100000, []byte{0x51}))
+
+	serialized, err := utils.SerializeBtcTransaction(tx)
+	require.NoError(t, err)
+
+	return tx, serialized
+}
+
+func testTrackedTransaction(t testing.TB) *proto.TrackedTransaction {
+	_, serializedTx := testBtcTx(t)
+
+	priv, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	sig, err := schnorr.Sign(priv, chainhash.HashB([]byte("unbonding")))
+	require.NoError(t, err)
+
+	return &proto.TrackedTransaction{
+		TrackedTransactionIdx:   1,
+		StakingTransaction:      serializedTx,
+		StakingOutputIdx:        0,
+		StakerAddress:           "address",
+		StakingTime:             100,
+		FinalityProvidersBtcPks: [][]byte{schnorr.SerializePubKey(priv.PubKey())},
+		State:                   proto.TransactionState_DELEGATION_ACTIVE,
+		StakingTxBtcConfirmationInfo: &proto.BTCConfirmationInfo{
+			BlockHeight: 10,
+			BlockHash:   chainhash.HashB([]byte("block")),
+		},
+		UnbondingTxData: &proto.UnbondingTxData{
+			UnbondingTransaction: serializedTx,
+			UnbondingTime:        10,
+			CovenantSignatures: []*proto.CovenantSig{
+				{
+					CovenantSig:      sig.Serialize(),
+					CovenantSigBtcPk: schnorr.SerializePubKey(priv.PubKey()),
+				},
+			},
+		},
+	}
+}
+
+// withUnknownField appends field which does not exist in current schema, as it
+// would be written by newer version of staker
+func withUnknownField(b []byte) []byte {
+	b = protowire.AppendTag(b, 1000, protowire.BytesType)
+	return protowire.AppendBytes(b, []byte("field from the future"))
+}
+
+func FuzzDecodeStoredTransaction(f *testing.F) {
+	valid, err := pm.Marshal(testTrackedTransaction(f))
+	require.NoError(f, err)
+
+	f.Add(valid)
+	f.Add(withUnknownField(valid))
+	f.Add(valid[:len(valid)/2])
+	f.Add([]byte{})
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		_, storedTx, err := decodeStoredTransaction(data)
+
+		if err != nil {
+			require.True(t, errors.Is(err, ErrCorruptedTransaction))
+			return
+		}
+
+		// decoded transaction must be safe to use by callers
+		require.Less(t, int(storedTx.StakingOutputIndex), len(storedTx.StakingTx.TxOut))
+
+		if storedTx.UnbondingTxData != nil {
+			require.NotEmpty(t, storedTx.UnbondingTxData.UnbondingTx.TxOut)
+		}
+	})
+}
+
+func FuzzDecodeUnbondingData(f *testing.F) {
+	valid, err := pm.Marshal(testTrackedTransaction(f).UnbondingTxData)
+	require.NoError(f, err)
+
+	f.Add(valid)
+	f.Add(withUnknownField(valid))
+	f.Add(valid[:len(valid)/2])
+	f.Add([]byte{})
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var unbondingDataProto proto.UnbondingTxData
+
+		if err := pm.Unmarshal(data, &unbondingDataProto); err != nil {
+			return
+		}
+
+		unbondingData, err := protoUnbondingDataToUnbondingStoreData(&unbondingDataProto)
+
+		if err != nil {
+			return
+		}
+
+		require.NotEmpty(t, unbondingData.UnbondingTx.TxOut)
+	})
+}
+
+func makeTestStore(t *testing.T) (*TrackedTransactionStore, kvdb.Backend) {
+	backend := dbtest.NewBackend(t)
+
+	store, err := NewTrackedTransactionStore(backend)
+	require.NoError(t, err)
+
+	return store, backend
+}
+
+func makeTestStoreWithRawTx(t *testing.T, txHash []byte, rawTx []byte) (*TrackedTransactionStore, kvdb.Backend) {
+	store, backend := makeTestStore(t)
+
+	err := kvdb.Update(backend, func(tx kvdb.RwTx) error {
+		if err := tx.ReadWriteBucket(transactionIndexName).Put(txHash, uint64KeyToBytes(1)); err != nil {
+			return err
+		}
+
+		return tx.ReadWriteBucket(transactionBucketName).Put(uint64KeyToBytes(1), rawTx)
+	}, func() {})
+	require.NoError(t, err)
+
+	return store, backend
+}
+
+func TestUnknownFieldsArePreservedOnUpdate(t *testing.T) {
+	txHash := chainhash.HashH([]byte("tx"))
+	ttx := testTrackedTransaction(t)
+
+	serialized, err := pm.Marshal(ttx)
+	require.NoError(t, err)
+
+	s, backend := makeTestStoreWithRawTx(t, txHash[:], withUnknownField(serialized))
+
+	require.NoError(t, s.SetTxSpentOnBtc(&txHash, nil))
+
+	var stored []byte
+	err = kvdb.View(backend, func(tx kvdb.RTx) error {
+		stored = append([]byte(nil), tx.ReadBucket(transactionBucketName).Get(uint64KeyToBytes(1))...)
+		return nil
+	}, func() {})
+	require.NoError(t, err)
+
+	var storedProto proto.TrackedTransaction
+	require.NoError(t, pm.Unmarshal(stored, &storedProto))
+	require.Equal(t, proto.TransactionState_SPENT_ON_BTC, storedProto.State)
+	require.True(t, bytes.Equal(withUnknownField(nil), storedProto.ProtoReflect().GetUnknown()))
+}
+
+func TestQuarantineCorruptedTransaction(t *testing.T) {
+	txHash := chainhash.HashH([]byte("tx"))
+	corrupted := []byte("not a transaction")
+
+	s, backend := makeTestStoreWithRawTx(t, txHash[:], corrupted)
+
+	_, err := s.GetTransaction(&txHash)
+	require.True(t, errors.Is(err, ErrCorruptedTransaction))
+
+	require.NoError(t, s.QuarantineTransaction(&txHash))
+
+	_, err = s.GetTransaction(&txHash)
+	require.True(t, errors.Is(err, ErrTransactionNotFound))
+
+	err = kvdb.View(backend, func(tx kvdb.RTx) error {
+		require.Nil(t, tx.ReadBucket(transactionBucketName).Get(uint64KeyToBytes(1)))
+		require.Equal(t, corrupted, tx.ReadBucket(corruptTransactionsBucketName).Get(txHash[:]))
+		return nil
+	}, func() {})
+	require.NoError(t, err)
+}
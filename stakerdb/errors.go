@@ -6,6 +6,10 @@ var (
 	// ErrCorruptedTransactionsDb For some reason, db on disk representation have changed
 	ErrCorruptedTransactionsDb = errors.New("transactions db is corrupted")
 
+	// ErrCorruptedTransaction Single stored transaction cannot be decoded, rest of
+	// the db is still usable
+	ErrCorruptedTransaction = errors.New("stored transaction is corrupted")
+
 	// ErrTransactionNotFound The transaction we try update is not found in db
 	ErrTransactionNotFound = errors.New("transaction not found")
 
@@ -15,7 +19,69 @@ var (
 	// ErrWatchedDataNotFound given watched data do not exists
 	ErrWatchedDataNotFound = errors.New("watched transaction data not found")
 
+	// ErrAlreadyInState transaction is already in the target state of the transition
+	ErrAlreadyInState = errors.New("transaction already in target state")
+
+	// ErrInvalidTransactionState transition is not allowed from current state of
+	// the transaction
+	ErrInvalidTransactionState = errors.New("invalid transaction state")
+
 	ErrInvalidUnbondingDataUpdate = errors.New("invalid unbonding data update")
 
 	ErrUnbondingDataNotFound = errors.New("unbonding transaction data not found")
+
+	// ErrReconciliationReportNotFound reconciliation was not run yet
+	ErrReconciliationReportNotFound = errors.New("reconciliation report not found")
+
+	// ErrStakingRequestNotFound staking request with given id is not known
+	ErrStakingRequestNotFound = errors.New("staking request not found")
+
+	// ErrStakingParamsNotFound staking params snapshot with given version is not known
+	ErrStakingParamsNotFound = errors.New("staking params not found")
+
+	// ErrCachedPopNotFound proof of possession for staker address and babylon key
+	// is not cached
+	ErrCachedPopNotFound = errors.New("cached proof of possession not found")
+
+	// ErrAuditLogTampered entries of audit log were removed or modified
+	ErrAuditLogTampered = errors.New("audit log was tampered with")
+
+	// ErrSweepIntentNotFound there is no intent to sweep unbonded funds of given
+	// staking transaction
+	ErrSweepIntentNotFound = errors.New("sweep intent not found")
+
+	// ErrWhitelistedAddressNotFound address is not in the stored spend destination
+	// whitelist
+	ErrWhitelistedAddressNotFound = errors.New("whitelisted address not found")
+
+	// ErrInvalidLabel label is too long or contains not allowed characters
+	ErrInvalidLabel = errors.New("invalid label")
+
+	// ErrInvalidNote note is too long or is not valid utf-8 text
+	ErrInvalidNote = errors.New("invalid note")
+
+	// ErrInvalidStakingOutputIndex staking transaction does not have output with
+	// given index
+	ErrInvalidStakingOutputIndex = errors.New("invalid staking output index")
+
+	// ErrTransactionArchived transaction was archived, archived transactions
+	// cannot be modified
+	ErrTransactionArchived = errors.New("transaction is archived")
+
+	// ErrAlreadyRenewed staking output of transaction is already spent by
+	// renewal
+	ErrAlreadyRenewed = errors.New("transaction already renewed")
+
+	// ErrArchiveInProgress archiving of transactions is already running
+	ErrArchiveInProgress = errors.New("archiving already in progress")
+
+	// ErrArchiveNotStarted there is no archiving to continue
+	ErrArchiveNotStarted = errors.New("archiving not started")
+
+	// ErrStateNotArchivable transactions in given state cannot be archived
+	ErrStateNotArchivable = errors.New("state cannot be archived")
+
+	// ErrRecoveryTargetNotEmpty database to which transactions are recovered
+	// already holds transactions
+	ErrRecoveryTargetNotEmpty = errors.New("recovery target database is not empty")
 )
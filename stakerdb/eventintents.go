@@ -0,0 +1,192 @@
+package stakerdb
+
+import (
+	"bytes"
+	"crypto/sha256"
+
+	"github.com/babylonchain/btc-staker/proto"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	pm "google.golang.org/protobuf/proto"
+
+	"github.com/lightningnetwork/lnd/kvdb"
+)
+
+var (
+	// mapping stakingTxHash || eventType -> proto.EventIntent
+	// It holds internal events which were produced, but which state transitions
+	// were not committed yet
+	eventIntentsBucketName = []byte("eventIntents")
+)
+
+// EventIntent is internal event recorded before it is delivered to the main
+// event loop. It is removed once state transition of the event is committed.
+type EventIntent struct {
+	StakingTxHash chainhash.Hash
+	Type          proto.EventIntentType
+	// only set for EVENT_INTENT_UNBONDING_SIGNATURES_RECEIVED
+	CovenantSignatures []PubKeySigPair
+	// only set for EVENT_INTENT_UNBONDING_CONFIRMED_ON_BTC
+	BtcConfirmation *BtcConfirmationInfo
+	// true if stored payload does not match its digest. Payload of corrupted
+	// intent is not decoded.
+	Corrupted bool
+}
+
+func eventIntentKey(stakingTxHash *chainhash.Hash, eventType proto.EventIntentType) []byte {
+	return append(stakingTxHash.CloneBytes(), byte(eventType))
+}
+
+func eventIntentToProto(i *EventIntent) (*proto.EventIntent, error) {
+	payload, err := pm.Marshal(&proto.EventIntentPayload{
+		CovenantSignatures:  covenantSigsToProto(i.CovenantSignatures),
+		BtcConfirmationInfo: btcConfirmationInfoToProto(i.BtcConfirmation),
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	digest := sha256.Sum256(payload)
+
+	return &proto.EventIntent{
+		Type:          i.Type,
+		Payload:       payload,
+		PayloadDigest: digest[:],
+	}, nil
+}
+
+func protoEventIntentToEventIntent(key []byte, i *proto.EventIntent) (*EventIntent, error) {
+	if len(key) != chainhash.HashSize+1 {
+		return nil, ErrCorruptedTransactionsDb
+	}
+
+	stakingTxHash, err := chainhash.NewHash(key[:chainhash.HashSize])
+
+	if err != nil {
+		return nil, err
+	}
+
+	intent := &EventIntent{
+		StakingTxHash: *stakingTxHash,
+		Type:          i.Type,
+	}
+
+	digest := sha256.Sum256(i.Payload)
+
+	if !bytes.Equal(digest[:], i.PayloadDigest) {
+		intent.Corrupted = true
+		return intent, nil
+	}
+
+	var payload proto.EventIntentPayload
+
+	if err := pm.Unmarshal(i.Payload, &payload); err != nil {
+		intent.Corrupted = true
+		return intent, nil
+	}
+
+	for _, sig := range payload.CovenantSignatures {
+		pair, err := covenantSigFromProto(sig)
+
+		if err != nil {
+			intent.Corrupted = true
+			return intent, nil
+		}
+
+		intent.CovenantSignatures = append(intent.CovenantSignatures, *pair)
+	}
+
+	confirmation, err := protoBtcConfirmationInfoToBtcConfirmationInfo(payload.BtcConfirmationInfo)
+
+	if err != nil {
+		intent.Corrupted = true
+		return intent, nil
+	}
+
+	intent.BtcConfirmation = confirmation
+
+	return intent, nil
+}
+
+// SaveEventIntent records internal event before it is delivered, overwriting
+// previous intent of the same type for the same transaction
+func (c *TrackedTransactionStore) SaveEventIntent(intent *EventIntent) error {
+	intentProto, err := eventIntentToProto(intent)
+
+	if err != nil {
+		return err
+	}
+
+	intentBytes, err := pm.Marshal(intentProto)
+
+	if err != nil {
+		return err
+	}
+
+	return kvdb.Batch(c.db, func(tx kvdb.RwTx) error {
+		intentsBucket := tx.ReadWriteBucket(eventIntentsBucketName)
+
+		if intentsBucket == nil {
+			return ErrCorruptedTransactionsDb
+		}
+
+		return intentsBucket.Put(eventIntentKey(&intent.StakingTxHash, intent.Type), intentBytes)
+	})
+}
+
+// CompleteEventIntent removes intent of the event after its state transition was
+// committed. Completing not existing intent is not an error.
+func (c *TrackedTransactionStore) CompleteEventIntent(
+	stakingTxHash *chainhash.Hash,
+	eventType proto.EventIntentType,
+) error {
+	return kvdb.Batch(c.db, func(tx kvdb.RwTx) error {
+		intentsBucket := tx.ReadWriteBucket(eventIntentsBucketName)
+
+		if intentsBucket == nil {
+			return ErrCorruptedTransactionsDb
+		}
+
+		return intentsBucket.Delete(eventIntentKey(stakingTxHash, eventType))
+	})
+}
+
+// GetEventIntents returns all intents which were not completed, ordered by
+// staking transaction hash and event type
+func (c *TrackedTransactionStore) GetEventIntents() ([]*EventIntent, error) {
+	var intents []*EventIntent
+
+	err := c.db.View(func(tx kvdb.RTx) error {
+		intentsBucket := tx.ReadBucket(eventIntentsBucketName)
+
+		if intentsBucket == nil {
+			return ErrCorruptedTransactionsDb
+		}
+
+		return intentsBucket.ForEach(func(k, v []byte) error {
+			var intentProto proto.EventIntent
+
+			if err := pm.Unmarshal(v, &intentProto); err != nil {
+				return ErrCorruptedTransactionsDb
+			}
+
+			intent, err := protoEventIntentToEventIntent(k, &intentProto)
+
+			if err != nil {
+				return err
+			}
+
+			intents = append(intents, intent)
+
+			return nil
+		})
+	}, func() {
+		intents = nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return intents, nil
+}
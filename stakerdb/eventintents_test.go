@@ -0,0 +1,142 @@
+package stakerdb
+
+import (
+	"testing"
+	"time"
+
+	"github.com/babylonchain/btc-staker/proto"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/lightningnetwork/lnd/kvdb"
+	"github.com/stretchr/testify/require"
+	pm "google.golang.org/protobuf/proto"
+)
+
+func TestEventIntentLifecycle(t *testing.T) {
+	s, _ := makeTestStore(t)
+
+	stakingTxHash := chainhash.HashH([]byte("staking"))
+
+	privKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	msgHash := chainhash.HashB([]byte("unbonding"))
+	sig, err := schnorr.Sign(privKey, msgHash)
+	require.NoError(t, err)
+
+	sigsIntent := &EventIntent{
+		StakingTxHash: stakingTxHash,
+		Type:          proto.EventIntentType_EVENT_INTENT_UNBONDING_SIGNATURES_RECEIVED,
+		CovenantSignatures: []PubKeySigPair{
+			{Signature: sig, PubKey: privKey.PubKey()},
+		},
+	}
+
+	confirmedIntent := &EventIntent{
+		StakingTxHash: stakingTxHash,
+		Type:          proto.EventIntentType_EVENT_INTENT_UNBONDING_CONFIRMED_ON_BTC,
+		BtcConfirmation: &BtcConfirmationInfo{
+			Height:    100,
+			BlockHash: chainhash.HashH([]byte("block")),
+			BlockTime: time.Unix(1700000000, 0),
+		},
+	}
+
+	require.NoError(t, s.SaveEventIntent(sigsIntent))
+	require.NoError(t, s.SaveEventIntent(confirmedIntent))
+
+	intents, err := s.GetEventIntents()
+	require.NoError(t, err)
+	require.Len(t, intents, 2)
+
+	require.False(t, intents[0].Corrupted)
+	require.Equal(t, sigsIntent.Type, intents[0].Type)
+	require.Equal(t, stakingTxHash, intents[0].StakingTxHash)
+	require.Len(t, intents[0].CovenantSignatures, 1)
+	require.True(t, intents[0].CovenantSignatures[0].Signature.IsEqual(sig))
+	require.Nil(t, intents[0].BtcConfirmation)
+
+	require.False(t, intents[1].Corrupted)
+	require.Equal(t, confirmedIntent.BtcConfirmation, intents[1].BtcConfirmation)
+	require.Empty(t, intents[1].CovenantSignatures)
+
+	require.NoError(t, s.CompleteEventIntent(&stakingTxHash, sigsIntent.Type))
+	// completing twice is not an error
+	require.NoError(t, s.CompleteEventIntent(&stakingTxHash, sigsIntent.Type))
+
+	intents, err = s.GetEventIntents()
+	require.NoError(t, err)
+	require.Len(t, intents, 1)
+	require.Equal(t, confirmedIntent.Type, intents[0].Type)
+}
+
+func TestEventIntentsSurviveReopen(t *testing.T) {
+	s, backend := makeTestStore(t)
+
+	stakingTxHash := chainhash.HashH([]byte("staking"))
+
+	require.NoError(t, s.SaveEventIntent(&EventIntent{
+		StakingTxHash:   stakingTxHash,
+		Type:            proto.EventIntentType_EVENT_INTENT_UNBONDING_CONFIRMED_ON_BTC,
+		BtcConfirmation: &BtcConfirmationInfo{Height: 100},
+	}))
+
+	// store is created again on restart over the same database
+	reopened, err := NewTrackedTransactionStore(backend)
+	require.NoError(t, err)
+
+	intents, err := reopened.GetEventIntents()
+	require.NoError(t, err)
+	require.Len(t, intents, 1)
+	require.Equal(t, uint32(100), intents[0].BtcConfirmation.Height)
+}
+
+func TestCorruptedEventIntentIsReported(t *testing.T) {
+	s, backend := makeTestStore(t)
+
+	stakingTxHash := chainhash.HashH([]byte("staking"))
+	eventType := proto.EventIntentType_EVENT_INTENT_UNBONDING_CONFIRMED_ON_BTC
+
+	require.NoError(t, s.SaveEventIntent(&EventIntent{
+		StakingTxHash:   stakingTxHash,
+		Type:            eventType,
+		BtcConfirmation: &BtcConfirmationInfo{Height: 100},
+	}))
+
+	// modify payload without updating its digest
+	err := kvdb.Update(backend, func(tx kvdb.RwTx) error {
+		bucket := tx.ReadWriteBucket(eventIntentsBucketName)
+		key := eventIntentKey(&stakingTxHash, eventType)
+
+		var intentProto proto.EventIntent
+		if err := pm.Unmarshal(bucket.Get(key), &intentProto); err != nil {
+			return err
+		}
+
+		intentProto.Payload = append(intentProto.Payload, 1)
+
+		intentBytes, err := pm.Marshal(&intentProto)
+		if err != nil {
+			return err
+		}
+
+		return bucket.Put(key, intentBytes)
+	}, func() {})
+	require.NoError(t, err)
+
+	intents, err := s.GetEventIntents()
+	require.NoError(t, err)
+	require.Len(t, intents, 1)
+	require.True(t, intents[0].Corrupted)
+	require.Equal(t, stakingTxHash, intents[0].StakingTxHash)
+	require.Equal(t, eventType, intents[0].Type)
+	require.Nil(t, intents[0].BtcConfirmation)
+
+	// corrupted intent can still be completed
+	require.NoError(t, s.CompleteEventIntent(&stakingTxHash, eventType))
+
+	intents, err = s.GetEventIntents()
+	require.NoError(t, err)
+	require.Empty(t, intents)
+}
@@ -0,0 +1,109 @@
+package stakerdb
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/babylonchain/btc-staker/proto"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+)
+
+// FeeEstimate is fee estimate consumed by staker when building transaction,
+// recorded so that fee paid by the transaction can be explained later
+type FeeEstimate struct {
+	// Estimator is type of estimator which provided the estimate
+	Estimator  string
+	ConfTarget uint32
+	// EstimatedFeeRate is fee rate per kvB estimated by btc node or configured
+	// in static estimator, zero if estimation failed
+	EstimatedFeeRate btcutil.Amount
+	// FallbackFeeRate is configured fee rate per kvB used instead of estimated
+	// one, zero if estimated fee rate was used
+	FallbackFeeRate btcutil.Amount
+	EstimatedAt     time.Time
+}
+
+// FeeRate returns fee rate per kvB used by staker
+func (e *FeeEstimate) FeeRate() btcutil.Amount {
+	if e.FallbackFeeRate != 0 {
+		return e.FallbackFeeRate
+	}
+
+	return e.EstimatedFeeRate
+}
+
+// FeeEstimateTxType is type of transaction built using fee estimate
+type FeeEstimateTxType int
+
+const (
+	StakingTxFeeEstimate FeeEstimateTxType = iota
+	UnbondingTxFeeEstimate
+	SpendTxFeeEstimate
+)
+
+func (t FeeEstimateTxType) String() string {
+	switch t {
+	case StakingTxFeeEstimate:
+		return "staking"
+	case UnbondingTxFeeEstimate:
+		return "unbonding"
+	case SpendTxFeeEstimate:
+		return "spend"
+	default:
+		return fmt.Sprintf("unknown(%d)", int(t))
+	}
+}
+
+func feeEstimateToProto(e *FeeEstimate) *proto.FeeEstimate {
+	return &proto.FeeEstimate{
+		Estimator:        e.Estimator,
+		ConfTarget:       e.ConfTarget,
+		EstimatedFeeRate: uint64(e.EstimatedFeeRate),
+		FallbackFeeRate:  uint64(e.FallbackFeeRate),
+		EstimatedAt:      e.EstimatedAt.Unix(),
+	}
+}
+
+func protoFeeEstimateToFeeEstimate(e *proto.FeeEstimate) *FeeEstimate {
+	if e == nil {
+		return nil
+	}
+
+	return &FeeEstimate{
+		Estimator:        e.Estimator,
+		ConfTarget:       e.ConfTarget,
+		EstimatedFeeRate: btcutil.Amount(e.EstimatedFeeRate),
+		FallbackFeeRate:  btcutil.Amount(e.FallbackFeeRate),
+		EstimatedAt:      time.Unix(e.EstimatedAt, 0),
+	}
+}
+
+// SetTxFeeEstimate records fee estimate used to build transaction of given type
+// for staking transaction. Previously recorded estimate of the same type is
+// overwritten, as only the last built transaction can be confirmed.
+func (c *TrackedTransactionStore) SetTxFeeEstimate(
+	stakingTxHash *chainhash.Hash,
+	txType FeeEstimateTxType,
+	estimate *FeeEstimate,
+) error {
+	if estimate == nil {
+		return fmt.Errorf("fee estimate must not be nil")
+	}
+
+	setFeeEstimate := func(tx *proto.TrackedTransaction) error {
+		switch txType {
+		case StakingTxFeeEstimate:
+			tx.StakingFeeEstimate = feeEstimateToProto(estimate)
+		case UnbondingTxFeeEstimate:
+			tx.UnbondingFeeEstimate = feeEstimateToProto(estimate)
+		case SpendTxFeeEstimate:
+			tx.SpendFeeEstimate = feeEstimateToProto(estimate)
+		default:
+			return fmt.Errorf("unknown fee estimate transaction type: %s", txType)
+		}
+		return nil
+	}
+
+	return c.setTxState(stakingTxHash, setFeeEstimate)
+}
@@ -0,0 +1,50 @@
+package stakerdb
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetTxFeeEstimate(t *testing.T) {
+	s, _ := makeTestStore(t)
+
+	txHash := addAutoRenewTestTx(t, s, 1000, false).TxHash()
+
+	storedTx, err := s.GetTransaction(&txHash)
+	require.NoError(t, err)
+	require.Nil(t, storedTx.StakingFeeEstimate)
+	require.Nil(t, storedTx.UnbondingFeeEstimate)
+	require.Nil(t, storedTx.SpendFeeEstimate)
+
+	estimatedAt := time.Unix(1700000000, 0)
+
+	clamped := &FeeEstimate{
+		Estimator:        "dynamic",
+		ConfTarget:       2,
+		EstimatedFeeRate: 200000,
+		FallbackFeeRate:  100000,
+		EstimatedAt:      estimatedAt,
+	}
+	require.NoError(t, s.SetTxFeeEstimate(&txHash, UnbondingTxFeeEstimate, clamped))
+
+	estimated := &FeeEstimate{
+		Estimator:        "dynamic",
+		ConfTarget:       6,
+		EstimatedFeeRate: 5000,
+		EstimatedAt:      estimatedAt,
+	}
+	require.NoError(t, s.SetTxFeeEstimate(&txHash, SpendTxFeeEstimate, estimated))
+
+	storedTx, err = s.GetTransaction(&txHash)
+	require.NoError(t, err)
+	require.Nil(t, storedTx.StakingFeeEstimate)
+	require.Equal(t, clamped, storedTx.UnbondingFeeEstimate)
+	require.Equal(t, estimated, storedTx.SpendFeeEstimate)
+
+	require.Equal(t, clamped.FallbackFeeRate, storedTx.UnbondingFeeEstimate.FeeRate())
+	require.Equal(t, estimated.EstimatedFeeRate, storedTx.SpendFeeEstimate.FeeRate())
+
+	require.Error(t, s.SetTxFeeEstimate(&txHash, SpendTxFeeEstimate, nil))
+}
@@ -0,0 +1,88 @@
+package stakerdb
+
+import (
+	"fmt"
+
+	"github.com/lightningnetwork/lnd/kvdb"
+)
+
+var (
+	// top level buckets of height hint cache of lnd notifier, which shares db
+	// with the store. Names must match buckets created by
+	// channeldb.NewHeightHintCache.
+	heightHintBucketNames = [][]byte{
+		[]byte("spend-hints"),
+		[]byte("confirm-hints"),
+	}
+
+	// backup of height hint bucket is stored under its name with this suffix
+	heightHintBackupSuffix = []byte("-backup")
+)
+
+func heightHintBackupName(name []byte) []byte {
+	return append(append([]byte(nil), name...), heightHintBackupSuffix...)
+}
+
+// BackupHeightHintCache copies height hints of notifier to backup buckets,
+// replacing previous backup
+func BackupHeightHintCache(db kvdb.Backend) error {
+	return kvdb.Update(db, func(tx kvdb.RwTx) error {
+		for _, name := range heightHintBucketNames {
+			bucket := tx.ReadBucket(name)
+
+			if bucket == nil {
+				continue
+			}
+
+			backupName := heightHintBackupName(name)
+
+			if tx.ReadBucket(backupName) != nil {
+				if err := tx.DeleteTopLevelBucket(backupName); err != nil {
+					return err
+				}
+			}
+
+			backupBucket, err := tx.CreateTopLevelBucket(backupName)
+
+			if err != nil {
+				return err
+			}
+
+			// hints are plain key value pairs, nested buckets are not expected
+			// in the cache and are skipped
+			err = bucket.ForEach(func(k, v []byte) error {
+				if v == nil {
+					return nil
+				}
+
+				return backupBucket.Put(k, v)
+			})
+
+			if err != nil {
+				return fmt.Errorf("failed to back up height hint bucket %s: %w", name, err)
+			}
+		}
+
+		return nil
+	}, func() {})
+}
+
+// RebuildHeightHintCache removes all height hints of notifier, so that
+// channeldb.NewHeightHintCache creates the cache from scratch. Height hints only
+// speed up rescans for notifications, and notifier falls back to height hints
+// given on registration when cache is empty.
+func RebuildHeightHintCache(db kvdb.Backend) error {
+	return kvdb.Update(db, func(tx kvdb.RwTx) error {
+		for _, name := range heightHintBucketNames {
+			if tx.ReadBucket(name) == nil {
+				continue
+			}
+
+			if err := tx.DeleteTopLevelBucket(name); err != nil {
+				return fmt.Errorf("failed to delete height hint bucket %s: %w", name, err)
+			}
+		}
+
+		return nil
+	}, func() {})
+}
@@ -0,0 +1,53 @@
+package stakerdb
+
+import (
+	"testing"
+
+	"github.com/lightningnetwork/lnd/chainntnfs"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/kvdb"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRebuildHeightHintCache(t *testing.T) {
+	s, db := makeTestStore(t)
+	txHash := addLabeledTestTransaction(t, s, 1000, "")
+
+	cache, err := channeldb.NewHeightHintCache(channeldb.CacheConfig{}, db)
+	require.NoError(t, err)
+
+	confRequest := chainntnfs.ConfRequest{TxID: *txHash}
+	require.NoError(t, cache.CommitConfirmHint(100, confRequest))
+
+	require.NoError(t, BackupHeightHintCache(db))
+	require.NoError(t, RebuildHeightHintCache(db))
+
+	cache, err = channeldb.NewHeightHintCache(channeldb.CacheConfig{}, db)
+	require.NoError(t, err)
+
+	_, err = cache.QueryConfirmHint(confRequest)
+	require.ErrorIs(t, err, chainntnfs.ErrConfirmHintNotFound)
+
+	// backup keeps the hint, while tracked transactions are left untouched
+	err = kvdb.View(db, func(tx kvdb.RTx) error {
+		backup := tx.ReadBucket(heightHintBackupName([]byte("confirm-hints")))
+		require.NotNil(t, backup)
+
+		numHints := 0
+		require.NoError(t, backup.ForEach(func(_, _ []byte) error {
+			numHints++
+			return nil
+		}))
+		require.Equal(t, 1, numHints)
+
+		return nil
+	}, func() {})
+	require.NoError(t, err)
+
+	_, err = s.GetTransaction(txHash)
+	require.NoError(t, err)
+
+	// rebuilding is repeatable
+	require.NoError(t, RebuildHeightHintCache(db))
+	require.NoError(t, RebuildHeightHintCache(db))
+}
@@ -0,0 +1,112 @@
+package stakerdb
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/babylonchain/btc-staker/proto"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+)
+
+// StateTransition is a change of tracked transaction state
+type StateTransition struct {
+	State     proto.TransactionState
+	ChangedAt time.Time
+}
+
+// SpendTxData describes transaction sent by staker to spend staked funds
+type SpendTxData struct {
+	SpendTxHash chainhash.Hash
+	// Value of the spent staking or unbonding output
+	SpentValue btcutil.Amount
+	Fee        btcutil.Amount
+	// Confirmation of spend transaction, nil until it is confirmed
+	SpendTxConfirmationInfo *BtcConfirmationInfo
+}
+
+// timeToUnix returns 0 for zero time, which is stored when time is not known
+func timeToUnix(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+
+	return t.Unix()
+}
+
+func protoStateHistoryToStateHistory(history []*proto.StateTransition) []StateTransition {
+	transitions := make([]StateTransition, len(history))
+
+	for i, t := range history {
+		transitions[i] = StateTransition{
+			State:     t.State,
+			ChangedAt: time.Unix(t.ChangedAt, 0),
+		}
+	}
+
+	return transitions
+}
+
+func protoSpendTxDataToSpendTxData(sd *proto.SpendTxData) (*SpendTxData, error) {
+	if sd == nil {
+		return nil, nil
+	}
+
+	spendTxHash, err := chainhash.NewHash(sd.SpendTxHash)
+
+	if err != nil {
+		return nil, err
+	}
+
+	confirmationInfo, err := protoBtcConfirmationInfoToBtcConfirmationInfo(sd.SpendTxBtcConfirmationInfo)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &SpendTxData{
+		SpendTxHash:             *spendTxHash,
+		SpentValue:              btcutil.Amount(sd.SpentValue),
+		Fee:                     btcutil.Amount(sd.Fee),
+		SpendTxConfirmationInfo: confirmationInfo,
+	}, nil
+}
+
+// SetUnbondingTxSentToBtc records time when unbonding transaction of given staking
+// transaction was sent to btc. Time of the first send is kept if unbonding
+// transaction is sent multiple times.
+func (c *TrackedTransactionStore) SetUnbondingTxSentToBtc(stakingTxHash *chainhash.Hash) error {
+	setUnbondingTxSent := func(tx *proto.TrackedTransaction) error {
+		if tx.UnbondingTxData == nil {
+			return fmt.Errorf("cannot set unbonding tx sent to btc, because unbonding tx data does not exist: %w", ErrUnbondingDataNotFound)
+		}
+
+		if tx.UnbondingTxData.UnbondingTxSentAt == 0 {
+			tx.UnbondingTxData.UnbondingTxSentAt = c.now().Unix()
+		}
+		return nil
+	}
+
+	return c.setTxState(stakingTxHash, setUnbondingTxSent)
+}
+
+// SetSpendTxSent records transaction sent by staker to spend staked funds of given
+// staking transaction. Previously recorded spend transaction is overwritten, as
+// only one spend can be confirmed.
+func (c *TrackedTransactionStore) SetSpendTxSent(
+	stakingTxHash *chainhash.Hash,
+	spendTxHash *chainhash.Hash,
+	spentValue btcutil.Amount,
+	fee btcutil.Amount,
+) error {
+	setSpendTxSent := func(tx *proto.TrackedTransaction) error {
+		tx.SpendTxData = &proto.SpendTxData{
+			SpendTxHash: spendTxHash.CloneBytes(),
+			SpentValue:  int64(spentValue),
+			Fee:         int64(fee),
+		}
+		return nil
+	}
+
+	return c.setTxState(stakingTxHash, setSpendTxSent)
+}
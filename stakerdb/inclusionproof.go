@@ -0,0 +1,128 @@
+package stakerdb
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/babylonchain/btc-staker/proto"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+)
+
+// ErrInvalidInclusionProof is returned when inclusion proof does not prove
+// inclusion of staking transaction in the block in which it was confirmed
+var ErrInvalidInclusionProof = errors.New("invalid inclusion proof")
+
+// InclusionProof proves inclusion of staking transaction in btc block. It is
+// recorded when transaction is confirmed, so that delegation can be sent to
+// babylon later without fetching the whole block from btc node again.
+type InclusionProof struct {
+	BlockHeader wire.BlockHeader
+	TxIndex     uint32
+	// concatenated hashes of merkle tree nodes on the path from transaction to
+	// merkle root of the block
+	MerkleProof []byte
+}
+
+// Verify checks that proof proves inclusion of transaction with given hash in
+// the block with recorded header
+func (p *InclusionProof) Verify(txHash *chainhash.Hash) error {
+	if len(p.MerkleProof)%chainhash.HashSize != 0 {
+		return fmt.Errorf("%w: merkle proof length %d is not multiple of hash size", ErrInvalidInclusionProof, len(p.MerkleProof))
+	}
+
+	current := *txHash
+	index := p.TxIndex
+
+	var concat [2 * chainhash.HashSize]byte
+
+	for i := 0; i < len(p.MerkleProof); i += chainhash.HashSize {
+		node := p.MerkleProof[i : i+chainhash.HashSize]
+
+		if index&1 == 0 {
+			copy(concat[:chainhash.HashSize], current[:])
+			copy(concat[chainhash.HashSize:], node)
+		} else {
+			copy(concat[:chainhash.HashSize], node)
+			copy(concat[chainhash.HashSize:], current[:])
+		}
+
+		current = chainhash.DoubleHashH(concat[:])
+		index >>= 1
+	}
+
+	if index != 0 {
+		return fmt.Errorf("%w: transaction index %d is outside of merkle tree", ErrInvalidInclusionProof, p.TxIndex)
+	}
+
+	if !current.IsEqual(&p.BlockHeader.MerkleRoot) {
+		return fmt.Errorf("%w: proof does not match merkle root of block %s", ErrInvalidInclusionProof, p.BlockHeader.BlockHash())
+	}
+
+	return nil
+}
+
+func inclusionProofToProto(p *InclusionProof) (*proto.InclusionProof, error) {
+	var header bytes.Buffer
+
+	if err := p.BlockHeader.Serialize(&header); err != nil {
+		return nil, err
+	}
+
+	return &proto.InclusionProof{
+		BlockHeader: header.Bytes(),
+		TxIndex:     p.TxIndex,
+		MerkleProof: p.MerkleProof,
+	}, nil
+}
+
+func protoInclusionProofToInclusionProof(p *proto.InclusionProof) (*InclusionProof, error) {
+	if p == nil {
+		return nil, nil
+	}
+
+	var header wire.BlockHeader
+
+	if err := header.Deserialize(bytes.NewReader(p.BlockHeader)); err != nil {
+		return nil, err
+	}
+
+	return &InclusionProof{
+		BlockHeader: header,
+		TxIndex:     p.TxIndex,
+		MerkleProof: p.MerkleProof,
+	}, nil
+}
+
+// SetTxInclusionProof records proof of inclusion of confirmed staking transaction
+// in the block in which it was confirmed. Proof which does not prove inclusion
+// of the transaction in that block is rejected.
+func (c *TrackedTransactionStore) SetTxInclusionProof(txHash *chainhash.Hash, inclusionProof *InclusionProof) error {
+	if err := inclusionProof.Verify(txHash); err != nil {
+		return err
+	}
+
+	protoProof, err := inclusionProofToProto(inclusionProof)
+
+	if err != nil {
+		return err
+	}
+
+	blockHash := inclusionProof.BlockHeader.BlockHash()
+
+	setInclusionProof := func(tx *proto.TrackedTransaction) error {
+		if tx.StakingTxBtcConfirmationInfo == nil {
+			return fmt.Errorf("cannot set inclusion proof of transaction which is not confirmed: %w", ErrInvalidTransactionState)
+		}
+
+		if !bytes.Equal(tx.StakingTxBtcConfirmationInfo.BlockHash, blockHash[:]) {
+			return fmt.Errorf("%w: proof is for block %s, which did not confirm the transaction", ErrInvalidInclusionProof, blockHash)
+		}
+
+		tx.StakingTxInclusionProof = protoProof
+		return nil
+	}
+
+	return c.setTxState(txHash, setInclusionProof)
+}
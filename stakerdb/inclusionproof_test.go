@@ -0,0 +1,164 @@
+package stakerdb
+
+import (
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/blockchain"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/stretchr/testify/require"
+)
+
+// testBlockWithTx returns block with given transaction at txIndex, among
+// numTxs transactions
+func testBlockWithTx(stakingTx *wire.MsgTx, txIndex, numTxs int) *wire.MsgBlock {
+	block := wire.NewMsgBlock(&wire.BlockHeader{Version: 1, Timestamp: time.Unix(1700000000, 0)})
+
+	for i := 0; i < numTxs; i++ {
+		if i == txIndex {
+			_ = block.AddTransaction(stakingTx)
+			continue
+		}
+
+		tx := wire.NewMsgTx(2)
+		tx.AddTxIn(wire.NewTxIn(&wire.OutPoint{Index: uint32(i)}, nil, nil))
+		tx.AddTxOut(wire.NewTxOut(int64(i), []byte{0x51}))
+		_ = block.AddTransaction(tx)
+	}
+
+	txs := make([]*btcutil.Tx, len(block.Transactions))
+	for i, tx := range block.Transactions {
+		txs[i] = btcutil.NewTx(tx)
+	}
+
+	merkles := blockchain.BuildMerkleTreeStore(txs, false)
+	block.Header.MerkleRoot = *merkles[len(merkles)-1]
+
+	return block
+}
+
+// testInclusionProof builds proof in the same format as proofs sent to babylon
+func testInclusionProof(block *wire.MsgBlock, txIndex int) *InclusionProof {
+	txs := make([]*btcutil.Tx, len(block.Transactions))
+	for i, tx := range block.Transactions {
+		txs[i] = btcutil.NewTx(tx)
+	}
+
+	merkles := blockchain.BuildMerkleTreeStore(txs, false)
+
+	var proof []byte
+
+	offset := 0
+	index := txIndex
+
+	// tree store holds levels of the tree from leaves to root, each level half
+	// the size of the previous one
+	for width := (len(merkles) + 1) / 2; width > 1; width /= 2 {
+		sibling := merkles[offset+(index^1)]
+
+		// odd node is hashed with itself
+		if sibling == nil {
+			sibling = merkles[offset+index]
+		}
+
+		proof = append(proof, sibling[:]...)
+		offset += width
+		index /= 2
+	}
+
+	return &InclusionProof{
+		BlockHeader: block.Header,
+		TxIndex:     uint32(txIndex),
+		MerkleProof: proof,
+	}
+}
+
+func TestInclusionProofVerification(t *testing.T) {
+	stakingTx, _ := testBtcTx(t)
+	txHash := stakingTx.TxHash()
+
+	for _, numTxs := range []int{1, 2, 5, 8} {
+		for txIndex := 0; txIndex < numTxs; txIndex++ {
+			block := testBlockWithTx(stakingTx, txIndex, numTxs)
+			proof := testInclusionProof(block, txIndex)
+
+			require.NoError(t, proof.Verify(&txHash), "txs: %d, index: %d", numTxs, txIndex)
+
+			otherTxHash := chainhash.HashH([]byte("other"))
+			require.ErrorIs(t, proof.Verify(&otherTxHash), ErrInvalidInclusionProof)
+
+			// last transaction of block with odd number of transactions is hashed
+			// with itself, so its position in the pair does not matter
+			if txIndex^1 < numTxs {
+				wrongIndex := *proof
+				wrongIndex.TxIndex = proof.TxIndex ^ 1
+				require.ErrorIs(t, wrongIndex.Verify(&txHash), ErrInvalidInclusionProof)
+			}
+
+			outsideTree := *proof
+			outsideTree.TxIndex = proof.TxIndex + uint32(1<<(len(proof.MerkleProof)/chainhash.HashSize))
+			require.ErrorIs(t, outsideTree.Verify(&txHash), ErrInvalidInclusionProof)
+
+			if len(proof.MerkleProof) > 0 {
+				tampered := *proof
+				tampered.MerkleProof = append([]byte(nil), proof.MerkleProof...)
+				tampered.MerkleProof[0] ^= 1
+				require.ErrorIs(t, tampered.Verify(&txHash), ErrInvalidInclusionProof)
+
+				truncated := *proof
+				truncated.MerkleProof = proof.MerkleProof[:len(proof.MerkleProof)-1]
+				require.ErrorIs(t, truncated.Verify(&txHash), ErrInvalidInclusionProof)
+			}
+		}
+	}
+}
+
+func TestSetTxInclusionProof(t *testing.T) {
+	s, _ := makeTestStore(t)
+
+	txHash := addTestTransaction(t, s, 1000)
+
+	storedTx, err := s.GetTransaction(txHash)
+	require.NoError(t, err)
+	require.Nil(t, storedTx.StakingTxInclusionProof)
+
+	block := testBlockWithTx(storedTx.StakingTx, 2, 5)
+	proof := testInclusionProof(block, 2)
+
+	// transaction is not confirmed yet
+	require.ErrorIs(t, s.SetTxInclusionProof(txHash, proof), ErrInvalidTransactionState)
+
+	otherBlockHash := chainhash.HashH([]byte("other block"))
+	require.NoError(t, s.SetTxConfirmed(txHash, &otherBlockHash, 100, time.Time{}))
+
+	// proof is for different block than the one which confirmed transaction
+	require.ErrorIs(t, s.SetTxInclusionProof(txHash, proof), ErrInvalidInclusionProof)
+
+	s, backend := makeTestStore(t)
+	txHash = addTestTransaction(t, s, 1000)
+	storedTx, err = s.GetTransaction(txHash)
+	require.NoError(t, err)
+
+	block = testBlockWithTx(storedTx.StakingTx, 2, 5)
+	proof = testInclusionProof(block, 2)
+	blockHash := block.BlockHash()
+
+	require.NoError(t, s.SetTxConfirmed(txHash, &blockHash, 100, time.Time{}))
+
+	invalidProof := *proof
+	invalidProof.TxIndex = 3
+	require.ErrorIs(t, s.SetTxInclusionProof(txHash, &invalidProof), ErrInvalidInclusionProof)
+
+	require.NoError(t, s.SetTxInclusionProof(txHash, proof))
+
+	// proof is kept after restart
+	reopened, err := NewTrackedTransactionStore(backend)
+	require.NoError(t, err)
+
+	storedTx, err = reopened.GetTransaction(txHash)
+	require.NoError(t, err)
+	require.Equal(t, proof, storedTx.StakingTxInclusionProof)
+	require.NoError(t, storedTx.StakingTxInclusionProof.Verify(txHash))
+}
@@ -0,0 +1,225 @@
+package stakerdb
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/babylonchain/btc-staker/proto"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	pm "google.golang.org/protobuf/proto"
+
+	"github.com/lightningnetwork/lnd/kvdb"
+)
+
+// IntegrityIssue describes tracked transaction whose stored data is inconsistent
+// with its state
+type IntegrityIssue struct {
+	StakingTxHash chainhash.Hash
+	// State of the transaction, SENT_TO_BTC if record cannot be decoded at all
+	State proto.TransactionState
+	// Corrupted is true if record cannot be decoded. Such records are quarantined
+	// by staker when they are accessed.
+	Corrupted   bool
+	Description string
+}
+
+// checkRecordIntegrity returns descriptions of all inconsistencies between state of
+// decoded transaction and data stored together with it
+func checkRecordIntegrity(
+	stakingTxHash *chainhash.Hash,
+	ttx *proto.TrackedTransaction,
+	storedTx *StoredTransaction,
+	hasWatchedData bool,
+) []string {
+	var issues []string
+
+	state := ttx.State
+
+	if _, known := allowedTransitions[state]; !known {
+		return []string{fmt.Sprintf("unknown state %d", state)}
+	}
+
+	if txHash := storedTx.StakingTx.TxHash(); !txHash.IsEqual(stakingTxHash) {
+		issues = append(issues, fmt.Sprintf("stored staking transaction has hash %s", txHash))
+	}
+
+	if ttx.CorruptionReason != "" {
+		issues = append(issues, fmt.Sprintf("record is flagged as corrupted: %s", ttx.CorruptionReason))
+	}
+
+	if ttx.ChangeOutput != nil && ttx.ChangeOutput.OutputIdx == ttx.StakingOutputIdx {
+		issues = append(issues, fmt.Sprintf("staking output %d is recorded as change output", ttx.StakingOutputIdx))
+	}
+
+	if n := len(ttx.StateHistory); n > 0 && ttx.StateHistory[n-1].State != state {
+		issues = append(issues, fmt.Sprintf("last state in history is %s", ttx.StateHistory[n-1].State))
+	}
+
+	confirmed := ttx.StakingTxBtcConfirmationInfo != nil
+
+	if state == proto.TransactionState_SENT_TO_BTC && confirmed {
+		issues = append(issues, "unconfirmed transaction has confirmation info")
+	}
+
+	if state != proto.TransactionState_SENT_TO_BTC && state != proto.TransactionState_CONFLICTED && !confirmed {
+		issues = append(issues, "confirmation info is missing")
+	}
+
+	if state == proto.TransactionState_CONFLICTED && confirmed {
+		issues = append(issues, "conflicted transaction has confirmation info")
+	}
+
+	if state == proto.TransactionState_CONFLICTED && len(ttx.ConflictingTxHash) == 0 {
+		issues = append(issues, "conflicting transaction hash is missing")
+	}
+
+	unbondingData := ttx.UnbondingTxData
+
+	switch state {
+	case proto.TransactionState_SENT_TO_BTC,
+		proto.TransactionState_CONFIRMED_ON_BTC,
+		proto.TransactionState_CONFLICTED,
+		proto.TransactionState_DELEGATION_CANCELLED:
+		// unbonding data is stored when delegation is sent to babylon
+		if unbondingData != nil {
+			issues = append(issues, "delegation not sent to babylon has unbonding data")
+		}
+	case proto.TransactionState_SENT_TO_BABYLON,
+		proto.TransactionState_DELEGATION_ACTIVE,
+		proto.TransactionState_UNBONDING_CONFIRMED_ON_BTC,
+		proto.TransactionState_DELEGATION_EXPIRED,
+		proto.TransactionState_UNBONDING_SIGNATURES_TIMEOUT:
+		if unbondingData == nil {
+			issues = append(issues, "unbonding data is missing")
+		}
+	}
+
+	if unbondingData != nil {
+		numSigs := len(unbondingData.CovenantSignatures)
+
+		if (state == proto.TransactionState_SENT_TO_BABYLON ||
+			state == proto.TransactionState_UNBONDING_SIGNATURES_TIMEOUT) && numSigs > 0 {
+			issues = append(issues, "inactive delegation has covenant signatures")
+		}
+
+		if state == proto.TransactionState_DELEGATION_ACTIVE && numSigs == 0 {
+			issues = append(issues, "active delegation does not have covenant signatures")
+		}
+
+		unbondingConfirmed := unbondingData.UnbondingTxBtcConfirmationInfo != nil
+
+		if state == proto.TransactionState_UNBONDING_CONFIRMED_ON_BTC && !unbondingConfirmed {
+			issues = append(issues, "unbonding confirmation info is missing")
+		}
+
+		if unbondingConfirmed &&
+			state != proto.TransactionState_UNBONDING_CONFIRMED_ON_BTC &&
+			state != proto.TransactionState_SPENT_ON_BTC {
+			issues = append(issues, "unbonding transaction is confirmed")
+		}
+	}
+
+	if proof := storedTx.StakingTxInclusionProof; proof != nil {
+		if err := proof.Verify(stakingTxHash); err != nil {
+			issues = append(issues, err.Error())
+		}
+
+		blockHash := proof.BlockHeader.BlockHash()
+		if confirmed && !bytes.Equal(ttx.StakingTxBtcConfirmationInfo.BlockHash, blockHash[:]) {
+			issues = append(issues, fmt.Sprintf("inclusion proof is for block %s, which did not confirm the transaction", blockHash))
+		}
+	}
+
+	if ttx.Watched && !hasWatchedData {
+		issues = append(issues, "watched transaction data is missing")
+	}
+
+	return issues
+}
+
+// CheckIntegrity checks that data stored with every tracked transaction is
+// consistent with its state and returns all found inconsistencies. It only reads
+// the database, so it can be run while staker is running.
+func (c *TrackedTransactionStore) CheckIntegrity() ([]IntegrityIssue, error) {
+	var issues []IntegrityIssue
+
+	err := c.db.View(func(tx kvdb.RTx) error {
+		transactionIdxBucket := tx.ReadBucket(transactionIndexName)
+
+		if transactionIdxBucket == nil {
+			return ErrCorruptedTransactionsDb
+		}
+
+		transactionsBucket := tx.ReadBucket(transactionBucketName)
+		if transactionsBucket == nil {
+			return ErrCorruptedTransactionsDb
+		}
+
+		watchedTxDataBucket := tx.ReadBucket(watchedTxDataBucketName)
+		if watchedTxDataBucket == nil {
+			return ErrCorruptedTransactionsDb
+		}
+
+		archiveBucket := tx.ReadBucket(archivedTransactionsBucketName)
+		if archiveBucket == nil {
+			return ErrCorruptedTransactionsDb
+		}
+
+		return transactionIdxBucket.ForEach(func(k, v []byte) error {
+			// index bucket also holds counter of transactions
+			if len(k) != chainhash.HashSize {
+				return nil
+			}
+
+			var stakingTxHash chainhash.Hash
+			copy(stakingTxHash[:], k)
+
+			report := func(state proto.TransactionState, corrupted bool, description string) {
+				issues = append(issues, IntegrityIssue{
+					StakingTxHash: stakingTxHash,
+					State:         state,
+					Corrupted:     corrupted,
+					Description:   description,
+				})
+			}
+
+			txBytes := transactionsBucket.Get(v)
+
+			if txBytes == nil {
+				txBytes = archiveBucket.Get(v)
+			}
+
+			if txBytes == nil {
+				report(proto.TransactionState_SENT_TO_BTC, true, "indexed transaction does not exist")
+				return nil
+			}
+
+			ttx, storedTx, err := decodeStoredTransaction(txBytes)
+
+			if err != nil {
+				// state can still be known, if only some of the fields are invalid
+				var partial proto.TrackedTransaction
+				_ = pm.Unmarshal(txBytes, &partial)
+
+				report(partial.State, true, err.Error())
+				return nil
+			}
+
+			hasWatchedData := watchedTxDataBucket.Get(k) != nil
+
+			for _, description := range checkRecordIntegrity(&stakingTxHash, ttx, storedTx, hasWatchedData) {
+				report(ttx.State, false, description)
+			}
+
+			return nil
+		})
+	}, func() {
+		issues = nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return issues, nil
+}
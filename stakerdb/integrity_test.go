@@ -0,0 +1,218 @@
+package stakerdb
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/babylonchain/btc-staker/proto"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/lightningnetwork/lnd/kvdb"
+	"github.com/stretchr/testify/require"
+	pm "google.golang.org/protobuf/proto"
+)
+
+func testCovenantSignatures(t *testing.T) []PubKeySigPair {
+	priv, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	sig, err := schnorr.Sign(priv, chainhash.HashB([]byte("unbonding")))
+	require.NoError(t, err)
+
+	return []PubKeySigPair{NewCovenantMemberSignature(sig, priv.PubKey())}
+}
+
+func storedTxBytes(t *testing.T, backend kvdb.Backend, txHash *chainhash.Hash) []byte {
+	var stored []byte
+	err := kvdb.View(backend, func(tx kvdb.RTx) error {
+		txKey := tx.ReadBucket(transactionIndexName).Get(txHash[:])
+		stored = append([]byte(nil), tx.ReadBucket(transactionBucketName).Get(txKey)...)
+		return nil
+	}, func() {})
+	require.NoError(t, err)
+
+	return stored
+}
+
+func TestSetTxUnbondingSignaturesReceivedIsAtomic(t *testing.T) {
+	s, backend := makeTestStore(t)
+	sigs := testCovenantSignatures(t)
+
+	txHash := addTestTransactionInState(t, s, 1000, proto.TransactionState_CONFIRMED_ON_BTC)
+	before := storedTxBytes(t, backend, txHash)
+
+	// without unbonding data neither signatures nor state are written
+	require.Error(t, s.SetTxUnbondingSignaturesReceived(txHash, sigs))
+	require.Equal(t, before, storedTxBytes(t, backend, txHash))
+
+	require.NoError(t, stateSetters[proto.TransactionState_SENT_TO_BABYLON](s, txHash))
+	require.NoError(t, s.SetTxUnbondingSignaturesReceived(txHash, sigs))
+
+	storedTx, err := s.GetTransaction(txHash)
+	require.NoError(t, err)
+	require.Equal(t, proto.TransactionState_DELEGATION_ACTIVE, storedTx.State)
+	require.Len(t, storedTx.UnbondingTxData.CovenantSignatures, 1)
+	require.True(t, sigs[0].Signature.IsEqual(storedTx.UnbondingTxData.CovenantSignatures[0].Signature))
+
+	// transition failing after it modified the record does not write anything
+	before = storedTxBytes(t, backend, txHash)
+	errTransition := errors.New("transition failed")
+
+	err = s.setTxState(txHash, func(tx *proto.TrackedTransaction) error {
+		tx.State = proto.TransactionState_UNBONDING_CONFIRMED_ON_BTC
+		tx.UnbondingTxData.CovenantSignatures = nil
+		return errTransition
+	})
+	require.ErrorIs(t, err, errTransition)
+	require.Equal(t, before, storedTxBytes(t, backend, txHash))
+
+	counts, err := s.TransactionStateCounts()
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), counts[proto.TransactionState_DELEGATION_ACTIVE])
+	require.Equal(t, uint64(0), counts[proto.TransactionState_UNBONDING_CONFIRMED_ON_BTC])
+}
+
+func TestCheckIntegrityOfConsistentStore(t *testing.T) {
+	s, _ := makeTestStore(t)
+
+	for state := range pathsToStates {
+		if state == proto.TransactionState_DELEGATION_ACTIVE {
+			continue
+		}
+		addTestTransactionInState(t, s, int64(1000+state), state)
+	}
+
+	active := addTestTransactionInState(t, s, 2000, proto.TransactionState_SENT_TO_BABYLON)
+	require.NoError(t, s.SetTxUnbondingSignaturesReceived(active, testCovenantSignatures(t)))
+
+	confirmed := addTestTransaction(t, s, 3000)
+	storedTx, err := s.GetTransaction(confirmed)
+	require.NoError(t, err)
+
+	block := testBlockWithTx(storedTx.StakingTx, 1, 3)
+	blockHash := block.BlockHash()
+	require.NoError(t, s.SetTxConfirmed(confirmed, &blockHash, 10, time.Time{}))
+	require.NoError(t, s.SetTxInclusionProof(confirmed, testInclusionProof(block, 1)))
+
+	issues, err := s.CheckIntegrity()
+	require.NoError(t, err)
+	require.Empty(t, issues)
+}
+
+func TestCheckIntegrityReportsInconsistentRecords(t *testing.T) {
+	tests := []struct {
+		name      string
+		modify    func(tx *proto.TrackedTransaction)
+		corrupted bool
+		issue     string
+	}{
+		{
+			name:   "active delegation without unbonding data",
+			modify: func(tx *proto.TrackedTransaction) { tx.UnbondingTxData = nil },
+			issue:  "unbonding data is missing",
+		},
+		{
+			name:   "active delegation without covenant signatures",
+			modify: func(tx *proto.TrackedTransaction) { tx.UnbondingTxData.CovenantSignatures = nil },
+			issue:  "active delegation does not have covenant signatures",
+		},
+		{
+			name:   "confirmed transaction without confirmation info",
+			modify: func(tx *proto.TrackedTransaction) { tx.StakingTxBtcConfirmationInfo = nil },
+			issue:  "confirmation info is missing",
+		},
+		{
+			name: "unbonding confirmed on btc without unbonding confirmation",
+			modify: func(tx *proto.TrackedTransaction) {
+				tx.State = proto.TransactionState_UNBONDING_CONFIRMED_ON_BTC
+			},
+			issue: "unbonding confirmation info is missing",
+		},
+		{
+			name: "state history not matching state",
+			modify: func(tx *proto.TrackedTransaction) {
+				tx.StateHistory = []*proto.StateTransition{{State: proto.TransactionState_SENT_TO_BABYLON}}
+			},
+			issue: "last state in history is SENT_TO_BABYLON",
+		},
+		{
+			name:   "watched transaction without watched data",
+			modify: func(tx *proto.TrackedTransaction) { tx.Watched = true },
+			issue:  "watched transaction data is missing",
+		},
+		{
+			name:   "transaction flagged as corrupted",
+			modify: func(tx *proto.TrackedTransaction) { tx.CorruptionReason = "staking output does not match" },
+			issue:  "record is flagged as corrupted: staking output does not match",
+		},
+		{
+			name: "staking output recorded as change output",
+			modify: func(tx *proto.TrackedTransaction) {
+				tx.ChangeOutput = &proto.ChangeOutput{OutputIdx: tx.StakingOutputIdx}
+			},
+			issue: "staking output 0 is recorded as change output",
+		},
+		{
+			name:      "transaction which cannot be decoded",
+			modify:    func(tx *proto.TrackedTransaction) { tx.StakingTransaction = []byte{1} },
+			corrupted: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			stakingTx, _ := testBtcTx(t)
+			txHash := stakingTx.TxHash()
+
+			ttx := testTrackedTransaction(t)
+			tc.modify(ttx)
+
+			serialized, err := pm.Marshal(ttx)
+			require.NoError(t, err)
+
+			s, _ := makeTestStoreWithRawTx(t, txHash[:], serialized)
+
+			issues, err := s.CheckIntegrity()
+			require.NoError(t, err)
+			require.Len(t, issues, 1)
+			require.Equal(t, txHash, issues[0].StakingTxHash)
+			require.Equal(t, ttx.State, issues[0].State)
+			require.Equal(t, tc.corrupted, issues[0].Corrupted)
+
+			if tc.issue != "" {
+				require.Equal(t, tc.issue, issues[0].Description)
+			}
+		})
+	}
+}
+
+func TestCheckIntegrityReportsBrokenIndex(t *testing.T) {
+	stakingTx, _ := testBtcTx(t)
+	txHash := stakingTx.TxHash()
+	otherTxHash := chainhash.HashH([]byte("other tx"))
+
+	serialized, err := pm.Marshal(testTrackedTransaction(t))
+	require.NoError(t, err)
+
+	// index points to transaction with different hash
+	s, backend := makeTestStoreWithRawTx(t, otherTxHash[:], serialized)
+
+	issues, err := s.CheckIntegrity()
+	require.NoError(t, err)
+	require.Len(t, issues, 1)
+	require.Equal(t, otherTxHash, issues[0].StakingTxHash)
+	require.Equal(t, "stored staking transaction has hash "+txHash.String(), issues[0].Description)
+
+	err = kvdb.Update(backend, func(tx kvdb.RwTx) error {
+		return tx.ReadWriteBucket(transactionBucketName).Delete(uint64KeyToBytes(1))
+	}, func() {})
+	require.NoError(t, err)
+
+	issues, err = s.CheckIntegrity()
+	require.NoError(t, err)
+	require.Len(t, issues, 1)
+	require.True(t, issues[0].Corrupted)
+	require.Equal(t, "indexed transaction does not exist", issues[0].Description)
+}
@@ -0,0 +1,177 @@
+package stakerdb
+
+import (
+	"fmt"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/lightningnetwork/lnd/kvdb"
+	pm "google.golang.org/protobuf/proto"
+)
+
+var (
+	// mapping label -> nested bucket of tracked transaction keys (uint64 -> empty)
+	// It allows to query transactions with given label without scanning all
+	// tracked transactions.
+	labelIndexBucketName = []byte("labelIdx")
+)
+
+// MaxLabelLength is the maximum length of delegation label in bytes
+const MaxLabelLength = 64
+
+func isAllowedLabelChar(c rune) bool {
+	switch {
+	case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		return true
+	case c == '-' || c == '_' || c == '.' || c == ':' || c == '/':
+		return true
+	default:
+		return false
+	}
+}
+
+// ValidateLabel checks that label can be assigned to delegation. Empty label is
+// valid and means that delegation is not labeled.
+func ValidateLabel(label string) error {
+	if len(label) > MaxLabelLength {
+		return fmt.Errorf("%w: label is longer than %d characters", ErrInvalidLabel, MaxLabelLength)
+	}
+
+	for _, c := range label {
+		if !isAllowedLabelChar(c) {
+			return fmt.Errorf(
+				"%w: label can only contain letters, digits and characters -_.:/, found %q",
+				ErrInvalidLabel,
+				c,
+			)
+		}
+	}
+
+	return nil
+}
+
+// addLabelIndex adds transaction with given key to the index of given label
+func addLabelIndex(rwTx kvdb.RwTx, label string, txKey []byte) error {
+	if label == "" {
+		return nil
+	}
+
+	labelsBucket := rwTx.ReadWriteBucket(labelIndexBucketName)
+
+	if labelsBucket == nil {
+		return ErrCorruptedTransactionsDb
+	}
+
+	labelBucket, err := labelsBucket.CreateBucketIfNotExists([]byte(label))
+
+	if err != nil {
+		return err
+	}
+
+	return labelBucket.Put(txKey, []byte{})
+}
+
+// removeLabelIndex removes transaction with given key from the index of given
+// label. Index of label without any transactions is removed.
+func removeLabelIndex(rwTx kvdb.RwTx, label string, txKey []byte) error {
+	if label == "" {
+		return nil
+	}
+
+	labelsBucket := rwTx.ReadWriteBucket(labelIndexBucketName)
+
+	if labelsBucket == nil {
+		return ErrCorruptedTransactionsDb
+	}
+
+	labelBucket := labelsBucket.NestedReadWriteBucket([]byte(label))
+
+	if labelBucket == nil {
+		return nil
+	}
+
+	if err := labelBucket.Delete(txKey); err != nil {
+		return err
+	}
+
+	if k, _ := labelBucket.ReadCursor().First(); k != nil {
+		return nil
+	}
+
+	return labelsBucket.DeleteNestedBucket([]byte(label))
+}
+
+// SetTransactionLabel assigns label to tracked transaction, replacing its
+// previous label. Empty label removes label from transaction.
+func (c *TrackedTransactionStore) SetTransactionLabel(txHash *chainhash.Hash, label string) error {
+	if err := ValidateLabel(label); err != nil {
+		return err
+	}
+
+	txHashBytes := txHash.CloneBytes()
+
+	return kvdb.Batch(c.db, func(tx kvdb.RwTx) error {
+		transactionIdxBucket := tx.ReadWriteBucket(transactionIndexName)
+
+		if transactionIdxBucket == nil {
+			return ErrCorruptedTransactionsDb
+		}
+
+		transactionsBucket := tx.ReadWriteBucket(transactionBucketName)
+		if transactionsBucket == nil {
+			return ErrCorruptedTransactionsDb
+		}
+
+		archiveBucket := tx.ReadBucket(archivedTransactionsBucketName)
+		if archiveBucket == nil {
+			return ErrCorruptedTransactionsDb
+		}
+
+		maybeTx, txKey, err := getTxByHash(txHashBytes, transactionIdxBucket, transactionsBucket, archiveBucket)
+
+		if err != nil {
+			return err
+		}
+
+		// copy key, as it is only valid until bucket is modified
+		txKey = append([]byte(nil), txKey...)
+
+		storedTx, _, err := decodeStoredTransaction(maybeTx)
+		if err != nil {
+			return err
+		}
+
+		if storedTx.Label == label {
+			return nil
+		}
+
+		if err := removeLabelIndex(tx, storedTx.Label, txKey); err != nil {
+			return err
+		}
+
+		if err := addLabelIndex(tx, label, txKey); err != nil {
+			return err
+		}
+
+		storedTx.Label = label
+
+		marshalled, err := pm.Marshal(storedTx)
+
+		if err != nil {
+			return err
+		}
+
+		return transactionsBucket.Put(txKey, marshalled)
+	})
+}
+
+// labelIndexBucket returns index of transactions with given label, nil if no
+// transaction has this label
+func labelIndexBucket(tx kvdb.RTx, label string) (kvdb.RBucket, error) {
+	labelsBucket := tx.ReadBucket(labelIndexBucketName)
+
+	if labelsBucket == nil {
+		return nil, ErrCorruptedTransactionsDb
+	}
+
+	return labelsBucket.NestedReadBucket([]byte(label)), nil
+}
@@ -0,0 +1,141 @@
+package stakerdb
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/babylonchain/btc-staker/proto"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/lightningnetwork/lnd/kvdb"
+	"github.com/stretchr/testify/require"
+)
+
+func queryLabel(t *testing.T, s *TrackedTransactionStore, label string, offset uint64, reversed bool) ([]chainhash.Hash, uint64) {
+	q := DefaultStoredTransactionQuery()
+	q.Label = label
+	q.IndexOffset = offset
+	q.Reversed = reversed
+
+	resp, err := s.QueryStoredTransactions(q)
+	require.NoError(t, err)
+
+	hashes := make([]chainhash.Hash, len(resp.Transactions))
+	for i, tx := range resp.Transactions {
+		require.Equal(t, label, tx.Label)
+		hashes[i] = tx.StakingTx.TxHash()
+	}
+
+	return hashes, resp.Total
+}
+
+func TestValidateLabel(t *testing.T) {
+	require.NoError(t, ValidateLabel(""))
+	require.NoError(t, ValidateLabel("client-1/strategy_a:v1.0"))
+	require.NoError(t, ValidateLabel(strings.Repeat("a", MaxLabelLength)))
+
+	require.ErrorIs(t, ValidateLabel(strings.Repeat("a", MaxLabelLength+1)), ErrInvalidLabel)
+	require.ErrorIs(t, ValidateLabel("with space"), ErrInvalidLabel)
+	require.ErrorIs(t, ValidateLabel("zażółć"), ErrInvalidLabel)
+}
+
+func TestQueryTransactionsByLabel(t *testing.T) {
+	s, backend := makeTestStore(t)
+
+	first := addLabeledTestTransaction(t, s, 1000, "alpha")
+	second := addLabeledTestTransaction(t, s, 2000, "beta")
+	third := addLabeledTestTransaction(t, s, 3000, "alpha")
+	unlabeled := addTestTransaction(t, s, 4000)
+
+	hashes, total := queryLabel(t, s, "alpha", 0, false)
+	require.Equal(t, []chainhash.Hash{*first, *third}, hashes)
+	require.Equal(t, uint64(2), total)
+
+	// offset is index of transaction, as in unfiltered queries
+	hashes, _ = queryLabel(t, s, "alpha", 1, false)
+	require.Equal(t, []chainhash.Hash{*third}, hashes)
+
+	hashes, _ = queryLabel(t, s, "alpha", 0, true)
+	require.Equal(t, []chainhash.Hash{*first, *third}, hashes)
+
+	hashes, total = queryLabel(t, s, "unknown", 0, false)
+	require.Empty(t, hashes)
+	require.Equal(t, uint64(0), total)
+
+	// relabeling moves transaction between indexes
+	require.NoError(t, s.SetTransactionLabel(second, "alpha"))
+	require.NoError(t, s.SetTransactionLabel(unlabeled, "alpha"))
+	require.NoError(t, s.SetTransactionLabel(first, ""))
+
+	hashes, total = queryLabel(t, s, "alpha", 0, false)
+	require.Equal(t, []chainhash.Hash{*second, *third, *unlabeled}, hashes)
+	require.Equal(t, uint64(3), total)
+
+	tx, err := s.GetTransaction(first)
+	require.NoError(t, err)
+	require.Empty(t, tx.Label)
+
+	// index of label without transactions is removed
+	err = kvdb.View(backend, func(tx kvdb.RTx) error {
+		require.Nil(t, tx.ReadBucket(labelIndexBucketName).NestedReadBucket([]byte("beta")))
+		return nil
+	}, func() {})
+	require.NoError(t, err)
+
+	// quarantined transaction is removed from index
+	require.NoError(t, s.QuarantineTransaction(third))
+
+	hashes, total = queryLabel(t, s, "alpha", 0, false)
+	require.Equal(t, []chainhash.Hash{*second, *unlabeled}, hashes)
+	require.Equal(t, uint64(2), total)
+
+	require.ErrorIs(t, s.SetTransactionLabel(second, "not valid"), ErrInvalidLabel)
+	require.ErrorIs(t, s.SetTransactionLabel(third, "alpha"), ErrTransactionNotFound)
+}
+
+func TestQueryTransactionsByState(t *testing.T) {
+	s, _ := makeTestStore(t)
+
+	first := addLabeledTestTransaction(t, s, 1000, "alpha")
+	second := addLabeledTestTransaction(t, s, 2000, "alpha")
+	third := addTestTransaction(t, s, 3000)
+
+	require.NoError(t, s.SetTxConfirmed(first, &chainhash.Hash{}, 10, time.Time{}))
+	require.NoError(t, s.SetTxConfirmed(third, &chainhash.Hash{}, 10, time.Time{}))
+
+	query := func(state proto.TransactionState, label string) []chainhash.Hash {
+		q := DefaultStoredTransactionQuery()
+		q.State = &state
+		q.Label = label
+
+		resp, err := s.QueryStoredTransactions(q)
+		require.NoError(t, err)
+
+		hashes := make([]chainhash.Hash, len(resp.Transactions))
+		for i, tx := range resp.Transactions {
+			require.Equal(t, state, tx.State)
+			hashes[i] = tx.StakingTx.TxHash()
+		}
+
+		return hashes
+	}
+
+	require.Equal(t, []chainhash.Hash{*first, *third}, query(proto.TransactionState_CONFIRMED_ON_BTC, ""))
+	require.Equal(t, []chainhash.Hash{*second}, query(proto.TransactionState_SENT_TO_BTC, ""))
+	require.Empty(t, query(proto.TransactionState_SPENT_ON_BTC, ""))
+
+	// state filter is combined with label filter
+	require.Equal(t, []chainhash.Hash{*first}, query(proto.TransactionState_CONFIRMED_ON_BTC, "alpha"))
+
+	// limit counts only matching transactions
+	state := proto.TransactionState_CONFIRMED_ON_BTC
+	q := DefaultStoredTransactionQuery()
+	q.State = &state
+	q.NumMaxTransactions = 1
+	q.IndexOffset = 1
+
+	resp, err := s.QueryStoredTransactions(q)
+	require.NoError(t, err)
+	require.Len(t, resp.Transactions, 1)
+	require.Equal(t, *third, resp.Transactions[0].StakingTx.TxHash())
+}
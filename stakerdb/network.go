@@ -0,0 +1,97 @@
+package stakerdb
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/babylonchain/btc-staker/proto"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg"
+	pm "google.golang.org/protobuf/proto"
+
+	"github.com/lightningnetwork/lnd/kvdb"
+)
+
+var (
+	// key for name of btc network to which data stored in db belong
+	networkKey = []byte("net")
+)
+
+// ErrNetworkMismatch db holds data of other btc network than the one staker runs on
+var ErrNetworkMismatch = errors.New("database belongs to different btc network")
+
+// CheckNetwork makes sure that db holds data of given btc network. Db created by
+// previous versions of staker does not record its network, so before network is
+// recorded for the first time, addresses of all tracked transactions are checked
+// to belong to it.
+func (c *TrackedTransactionStore) CheckNetwork(network string, params *chaincfg.Params) error {
+	return kvdb.Batch(c.db, func(tx kvdb.RwTx) error {
+		settingsBucket := tx.ReadWriteBucket(settingsBucketName)
+
+		if settingsBucket == nil {
+			return ErrCorruptedTransactionsDb
+		}
+
+		if dbNetwork := settingsBucket.Get(networkKey); dbNetwork != nil {
+			if string(dbNetwork) != network {
+				return fmt.Errorf("%w: database holds data of %s network, but staker runs on %s", ErrNetworkMismatch, dbNetwork, network)
+			}
+
+			return nil
+		}
+
+		transactionsBucket := tx.ReadBucket(transactionBucketName)
+
+		if transactionsBucket == nil {
+			return ErrCorruptedTransactionsDb
+		}
+
+		err := transactionsBucket.ForEach(func(_, v []byte) error {
+			var storedTx proto.TrackedTransaction
+
+			// transactions which cannot be decoded are quarantined on startup
+			if err := pm.Unmarshal(v, &storedTx); err != nil {
+				return nil
+			}
+
+			address, err := btcutil.DecodeAddress(storedTx.StakerAddress, params)
+
+			if err != nil || !address.IsForNet(params) {
+				return fmt.Errorf("%w: staker address %s is not valid on %s network", ErrNetworkMismatch, storedTx.StakerAddress, network)
+			}
+
+			return nil
+		})
+
+		if err != nil {
+			return err
+		}
+
+		return settingsBucket.Put(networkKey, []byte(network))
+	})
+}
+
+// Network returns name of btc network to which data stored in db belong, empty
+// if network was not recorded yet
+func (c *TrackedTransactionStore) Network() (string, error) {
+	var network string
+
+	err := c.db.View(func(tx kvdb.RTx) error {
+		settingsBucket := tx.ReadBucket(settingsBucketName)
+
+		if settingsBucket == nil {
+			return ErrCorruptedTransactionsDb
+		}
+
+		network = string(settingsBucket.Get(networkKey))
+		return nil
+	}, func() {
+		network = ""
+	})
+
+	if err != nil {
+		return "", err
+	}
+
+	return network, nil
+}
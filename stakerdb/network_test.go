@@ -0,0 +1,47 @@
+package stakerdb
+
+import (
+	"testing"
+
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckNetwork(t *testing.T) {
+	s, backend := makeTestStore(t)
+
+	network, err := s.Network()
+	require.NoError(t, err)
+	require.Empty(t, network)
+
+	// db without recorded network, with transactions of regtest network
+	addTestTransaction(t, s, 1000)
+
+	require.ErrorIs(t, s.CheckNetwork("simnet", &chaincfg.SimNetParams), ErrNetworkMismatch)
+
+	network, err = s.Network()
+	require.NoError(t, err)
+	require.Empty(t, network)
+
+	require.NoError(t, s.CheckNetwork("regtest", &chaincfg.RegressionNetParams))
+	require.NoError(t, s.CheckNetwork("regtest", &chaincfg.RegressionNetParams))
+
+	// recorded network survives restart
+	reopened, err := NewTrackedTransactionStore(backend)
+	require.NoError(t, err)
+
+	network, err = reopened.Network()
+	require.NoError(t, err)
+	require.Equal(t, "regtest", network)
+
+	require.ErrorIs(t, reopened.CheckNetwork("signet", &chaincfg.SigNetParams), ErrNetworkMismatch)
+}
+
+func TestCheckNetworkOfEmptyDb(t *testing.T) {
+	s, _ := makeTestStore(t)
+
+	require.NoError(t, s.CheckNetwork("signet", &chaincfg.SigNetParams))
+	// addresses of signet and testnet are the same, so only recorded network
+	// tells them apart
+	require.ErrorIs(t, s.CheckNetwork("testnet", &chaincfg.TestNet3Params), ErrNetworkMismatch)
+}
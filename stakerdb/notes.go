@@ -0,0 +1,263 @@
+package stakerdb
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/lightningnetwork/lnd/kvdb"
+)
+
+var (
+	// mapping staking tx hash -> note
+	// It holds free-form notes of operator. Notes are kept outside of tracked
+	// transaction records, so that records stay small.
+	notesBucketName = []byte("notes")
+)
+
+// MaxNoteLength is the maximum length of delegation note in bytes
+const MaxNoteLength = 4096
+
+// ValidateNote checks that note can be assigned to delegation. Empty note is
+// valid and means that delegation has no note.
+func ValidateNote(note string) error {
+	if len(note) > MaxNoteLength {
+		return fmt.Errorf("%w: note is longer than %d bytes", ErrInvalidNote, MaxNoteLength)
+	}
+
+	if !utf8.ValidString(note) {
+		return fmt.Errorf("%w: note is not valid utf-8 text", ErrInvalidNote)
+	}
+
+	return nil
+}
+
+// SetTransactionNote assigns note to tracked transaction, replacing its previous
+// note. Empty note removes note from transaction.
+func (c *TrackedTransactionStore) SetTransactionNote(txHash *chainhash.Hash, note string) error {
+	if err := ValidateNote(note); err != nil {
+		return err
+	}
+
+	txHashBytes := txHash.CloneBytes()
+
+	return kvdb.Batch(c.db, func(tx kvdb.RwTx) error {
+		transactionIdxBucket := tx.ReadBucket(transactionIndexName)
+		if transactionIdxBucket == nil {
+			return ErrCorruptedTransactionsDb
+		}
+
+		transactionsBucket := tx.ReadBucket(transactionBucketName)
+		if transactionsBucket == nil {
+			return ErrCorruptedTransactionsDb
+		}
+
+		archiveBucket := tx.ReadBucket(archivedTransactionsBucketName)
+		if archiveBucket == nil {
+			return ErrCorruptedTransactionsDb
+		}
+
+		notesBucket := tx.ReadWriteBucket(notesBucketName)
+		if notesBucket == nil {
+			return ErrCorruptedTransactionsDb
+		}
+
+		if _, _, err := getTxByHash(txHashBytes, transactionIdxBucket, transactionsBucket, archiveBucket); err != nil {
+			return err
+		}
+
+		if note == "" {
+			return notesBucket.Delete(txHashBytes)
+		}
+
+		return notesBucket.Put(txHashBytes, []byte(note))
+	})
+}
+
+// GetTransactionNote returns note of tracked transaction, empty if transaction
+// has no note
+func (c *TrackedTransactionStore) GetTransactionNote(txHash *chainhash.Hash) (string, error) {
+	var note string
+	txHashBytes := txHash.CloneBytes()
+
+	err := c.db.View(func(tx kvdb.RTx) error {
+		transactionIdxBucket := tx.ReadBucket(transactionIndexName)
+		if transactionIdxBucket == nil {
+			return ErrCorruptedTransactionsDb
+		}
+
+		notesBucket := tx.ReadBucket(notesBucketName)
+		if notesBucket == nil {
+			return ErrCorruptedTransactionsDb
+		}
+
+		if transactionIdxBucket.Get(txHashBytes) == nil {
+			return ErrTransactionNotFound
+		}
+
+		note = string(notesBucket.Get(txHashBytes))
+		return nil
+	}, func() {
+		note = ""
+	})
+
+	if err != nil {
+		return "", err
+	}
+
+	return note, nil
+}
+
+// TransactionSearchMatch is tracked transaction which label or note contains
+// searched text
+type TransactionSearchMatch struct {
+	StakingTxHash chainhash.Hash
+	Label         string
+	Note          string
+	Archived      bool
+	MatchedLabel  bool
+	MatchedNote   bool
+}
+
+// SearchTransactions returns transactions, including archived ones, which label
+// or note contains query. Search is case-insensitive and matches are returned
+// in the order in which transactions started to be tracked.
+func (c *TrackedTransactionStore) SearchTransactions(query string) ([]TransactionSearchMatch, error) {
+	if query == "" {
+		return nil, fmt.Errorf("search query must not be empty")
+	}
+
+	lowerQuery := strings.ToLower(query)
+
+	contains := func(text string) bool {
+		return strings.Contains(strings.ToLower(text), lowerQuery)
+	}
+
+	var matches []TransactionSearchMatch
+
+	err := c.db.View(func(tx kvdb.RTx) error {
+		transactionIdxBucket := tx.ReadBucket(transactionIndexName)
+		if transactionIdxBucket == nil {
+			return ErrCorruptedTransactionsDb
+		}
+
+		transactionsBucket := tx.ReadBucket(transactionBucketName)
+		if transactionsBucket == nil {
+			return ErrCorruptedTransactionsDb
+		}
+
+		archiveBucket := tx.ReadBucket(archivedTransactionsBucketName)
+		if archiveBucket == nil {
+			return ErrCorruptedTransactionsDb
+		}
+
+		labelsBucket := tx.ReadBucket(labelIndexBucketName)
+		if labelsBucket == nil {
+			return ErrCorruptedTransactionsDb
+		}
+
+		notesBucket := tx.ReadBucket(notesBucketName)
+		if notesBucket == nil {
+			return ErrCorruptedTransactionsDb
+		}
+
+		// keys of matching transactions, labels are only checked through label
+		// index, so that records of all tracked transactions are not decoded
+		matchedKeys := make(map[string]struct{})
+
+		err := labelsBucket.ForEach(func(label, _ []byte) error {
+			if !contains(string(label)) {
+				return nil
+			}
+
+			labelBucket := labelsBucket.NestedReadBucket(label)
+
+			if labelBucket == nil {
+				return ErrCorruptedTransactionsDb
+			}
+
+			return labelBucket.ForEach(func(txKey, _ []byte) error {
+				matchedKeys[string(txKey)] = struct{}{}
+				return nil
+			})
+		})
+
+		if err != nil {
+			return err
+		}
+
+		err = notesBucket.ForEach(func(txHash, note []byte) error {
+			if !contains(string(note)) {
+				return nil
+			}
+
+			txKey := transactionIdxBucket.Get(txHash)
+
+			// note of quarantined transaction, which is no longer tracked
+			if txKey == nil {
+				return nil
+			}
+
+			matchedKeys[string(txKey)] = struct{}{}
+			return nil
+		})
+
+		if err != nil {
+			return err
+		}
+
+		keys := make([][]byte, 0, len(matchedKeys))
+		for k := range matchedKeys {
+			keys = append(keys, []byte(k))
+		}
+
+		sort.Slice(keys, func(i, j int) bool {
+			return bytes.Compare(keys[i], keys[j]) < 0
+		})
+
+		for _, txKey := range keys {
+			archived := false
+			maybeTx := transactionsBucket.Get(txKey)
+
+			if maybeTx == nil {
+				maybeTx = archiveBucket.Get(txKey)
+				archived = true
+			}
+
+			if maybeTx == nil {
+				return ErrCorruptedTransactionsDb
+			}
+
+			_, storedTx, err := decodeStoredTransaction(maybeTx)
+
+			if err != nil {
+				return err
+			}
+
+			txHash := storedTx.StakingTx.TxHash()
+			note := string(notesBucket.Get(txHash[:]))
+
+			matches = append(matches, TransactionSearchMatch{
+				StakingTxHash: txHash,
+				Label:         storedTx.Label,
+				Note:          note,
+				Archived:      archived,
+				MatchedLabel:  storedTx.Label != "" && contains(storedTx.Label),
+				MatchedNote:   note != "" && contains(note),
+			})
+		}
+
+		return nil
+	}, func() {
+		matches = nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return matches, nil
+}
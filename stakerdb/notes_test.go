@@ -0,0 +1,131 @@
+package stakerdb
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/babylonchain/btc-staker/proto"
+	"github.com/babylonchain/btc-staker/stakerdb/dbtest"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/stretchr/testify/require"
+)
+
+func searchHashes(t *testing.T, s *TrackedTransactionStore, query string) []chainhash.Hash {
+	matches, err := s.SearchTransactions(query)
+	require.NoError(t, err)
+
+	hashes := make([]chainhash.Hash, len(matches))
+	for i, m := range matches {
+		hashes[i] = m.StakingTxHash
+	}
+
+	return hashes
+}
+
+func TestValidateNote(t *testing.T) {
+	require.NoError(t, ValidateNote(""))
+	require.NoError(t, ValidateNote("opened for client X per ticket 1234, unbond after Q3"))
+	require.NoError(t, ValidateNote(strings.Repeat("a", MaxNoteLength)))
+
+	require.ErrorIs(t, ValidateNote(strings.Repeat("a", MaxNoteLength+1)), ErrInvalidNote)
+	require.ErrorIs(t, ValidateNote(string([]byte{0xff, 0xfe})), ErrInvalidNote)
+}
+
+func TestTransactionNotes(t *testing.T) {
+	s, _ := makeTestStore(t)
+
+	txHash := addTestTransaction(t, s, 1000)
+
+	note, err := s.GetTransactionNote(txHash)
+	require.NoError(t, err)
+	require.Empty(t, note)
+
+	require.NoError(t, s.SetTransactionNote(txHash, "opened for client X"))
+	require.NoError(t, s.SetTransactionNote(txHash, "opened for client Y"))
+
+	note, err = s.GetTransactionNote(txHash)
+	require.NoError(t, err)
+	require.Equal(t, "opened for client Y", note)
+
+	require.NoError(t, s.SetTransactionNote(txHash, ""))
+
+	note, err = s.GetTransactionNote(txHash)
+	require.NoError(t, err)
+	require.Empty(t, note)
+
+	unknown := chainhash.Hash{1}
+	require.ErrorIs(t, s.SetTransactionNote(&unknown, "note"), ErrTransactionNotFound)
+	_, err = s.GetTransactionNote(&unknown)
+	require.ErrorIs(t, err, ErrTransactionNotFound)
+
+	require.ErrorIs(t, s.SetTransactionNote(txHash, strings.Repeat("a", MaxNoteLength+1)), ErrInvalidNote)
+}
+
+func TestSearchTransactions(t *testing.T) {
+	s, _ := makeTestStore(t)
+
+	start := time.Unix(1700000000, 0)
+	s.now = func() time.Time { return start }
+
+	archived := addSpentTestTransaction(t, s, 1000, "client-x")
+	noted := addTestTransaction(t, s, 2000)
+	both := addLabeledTestTransaction(t, s, 3000, "client-y")
+	other := addLabeledTestTransaction(t, s, 4000, "other")
+
+	require.NoError(t, s.SetTransactionNote(noted, "Opened for Client X per ticket 1234"))
+	require.NoError(t, s.SetTransactionNote(both, "unbond after Q3"))
+
+	s.now = func() time.Time { return start.Add(48 * time.Hour) }
+	_, err := s.StartArchive(start.Add(24*time.Hour), []proto.TransactionState{proto.TransactionState_SPENT_ON_BTC})
+	require.NoError(t, err)
+	runArchive(t, s)
+
+	// search is case-insensitive and covers both labels and notes of tracked
+	// and archived transactions
+	matches, err := s.SearchTransactions("CLIENT")
+	require.NoError(t, err)
+	require.Len(t, matches, 3)
+
+	require.Equal(t, *archived, matches[0].StakingTxHash)
+	require.True(t, matches[0].Archived)
+	require.True(t, matches[0].MatchedLabel)
+	require.False(t, matches[0].MatchedNote)
+
+	require.Equal(t, *noted, matches[1].StakingTxHash)
+	require.False(t, matches[1].MatchedLabel)
+	require.True(t, matches[1].MatchedNote)
+
+	require.Equal(t, *both, matches[2].StakingTxHash)
+	require.Equal(t, "unbond after Q3", matches[2].Note)
+	require.True(t, matches[2].MatchedLabel)
+	require.False(t, matches[2].MatchedNote)
+
+	require.Equal(t, []chainhash.Hash{*both}, searchHashes(t, s, "q3"))
+	require.Equal(t, []chainhash.Hash{*other}, searchHashes(t, s, "oth"))
+	require.Empty(t, searchHashes(t, s, "ticket 9999"))
+
+	_, err = s.SearchTransactions("")
+	require.Error(t, err)
+
+	// notes of quarantined transactions are not searched
+	require.NoError(t, s.QuarantineTransaction(noted))
+	require.Equal(t, []chainhash.Hash{*archived, *both}, searchHashes(t, s, "client"))
+}
+
+func TestCopyDatabaseCopiesNotes(t *testing.T) {
+	src, srcBackend := makeTestStore(t)
+
+	txHash := addTestTransaction(t, src, 1000)
+	require.NoError(t, src.SetTransactionNote(txHash, "unbond after Q3"))
+
+	dstBackend := dbtest.NewBackend(t)
+	require.NoError(t, CopyDatabase(srcBackend, dstBackend))
+
+	dst, err := NewTrackedTransactionStore(dstBackend)
+	require.NoError(t, err)
+
+	note, err := dst.GetTransactionNote(txHash)
+	require.NoError(t, err)
+	require.Equal(t, "unbond after Q3", note)
+}
@@ -0,0 +1,92 @@
+package stakerdb
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/babylonchain/btc-staker/proto"
+	"github.com/lightningnetwork/lnd/kvdb"
+	pm "google.golang.org/protobuf/proto"
+)
+
+var (
+	// mapping staker address -> proto.CachedPop
+	// It holds the last proof of possession generated for staker address
+	popCacheBucketName = []byte("popCache")
+)
+
+// GetCachedPop returns proof of possession cached for staker address. Proof
+// generated for other babylon key than the given one is not returned, as it was
+// invalidated by change of the key.
+func (c *TrackedTransactionStore) GetCachedPop(stakerAddress string, babylonPubKey []byte) (*ProofOfPossession, error) {
+	var pop *ProofOfPossession
+
+	err := c.db.View(func(tx kvdb.RTx) error {
+		popCacheBucket := tx.ReadBucket(popCacheBucketName)
+
+		if popCacheBucket == nil {
+			return ErrCorruptedTransactionsDb
+		}
+
+		cachedBytes := popCacheBucket.Get([]byte(stakerAddress))
+
+		if cachedBytes == nil {
+			return ErrCachedPopNotFound
+		}
+
+		var cached proto.CachedPop
+
+		if err := pm.Unmarshal(cachedBytes, &cached); err != nil {
+			return fmt.Errorf("failed to decode cached proof of possession of %s: %w", stakerAddress, err)
+		}
+
+		if !bytes.Equal(cached.BabylonPubKey, babylonPubKey) {
+			return ErrCachedPopNotFound
+		}
+
+		pop = &ProofOfPossession{
+			BtcSigType:           cached.BtcSigType,
+			BabylonSigOverBtcPk:  cached.BabylonSigBtcPk,
+			BtcSigOverBabylonSig: cached.BtcSigBabylonSig,
+		}
+
+		return nil
+	}, func() {
+		pop = nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return pop, nil
+}
+
+// SetCachedPop caches proof of possession generated for staker address and
+// babylon key, replacing proof previously cached for the address
+func (c *TrackedTransactionStore) SetCachedPop(
+	stakerAddress string,
+	babylonPubKey []byte,
+	pop *ProofOfPossession,
+) error {
+	cachedBytes, err := pm.Marshal(&proto.CachedPop{
+		BabylonPubKey:    babylonPubKey,
+		BtcSigType:       pop.BtcSigType,
+		BabylonSigBtcPk:  pop.BabylonSigOverBtcPk,
+		BtcSigBabylonSig: pop.BtcSigOverBabylonSig,
+	})
+
+	if err != nil {
+		return err
+	}
+
+	return kvdb.Batch(c.db, func(tx kvdb.RwTx) error {
+		popCacheBucket := tx.ReadWriteBucket(popCacheBucketName)
+
+		if popCacheBucket == nil {
+			return ErrCorruptedTransactionsDb
+		}
+
+		return popCacheBucket.Put([]byte(stakerAddress), cachedBytes)
+	})
+}
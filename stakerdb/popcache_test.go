@@ -0,0 +1,50 @@
+package stakerdb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPopCache(t *testing.T) {
+	s, _ := makeTestStore(t)
+
+	address := "bcrt1qstaker"
+	babylonKey := []byte{2, 1}
+	pop := &ProofOfPossession{
+		BtcSigType:           0,
+		BabylonSigOverBtcPk:  []byte{1, 2, 3},
+		BtcSigOverBabylonSig: []byte{4, 5, 6},
+	}
+
+	_, err := s.GetCachedPop(address, babylonKey)
+	require.ErrorIs(t, err, ErrCachedPopNotFound)
+
+	require.NoError(t, s.SetCachedPop(address, babylonKey, pop))
+
+	cached, err := s.GetCachedPop(address, babylonKey)
+	require.NoError(t, err)
+	require.Equal(t, pop, cached)
+
+	// proof generated for other babylon key is invalidated
+	_, err = s.GetCachedPop(address, []byte{2, 2})
+	require.ErrorIs(t, err, ErrCachedPopNotFound)
+
+	_, err = s.GetCachedPop("bcrt1qother", babylonKey)
+	require.ErrorIs(t, err, ErrCachedPopNotFound)
+
+	// new key replaces previously cached proof
+	newPop := &ProofOfPossession{
+		BtcSigType:           0,
+		BabylonSigOverBtcPk:  []byte{7},
+		BtcSigOverBabylonSig: []byte{8},
+	}
+	require.NoError(t, s.SetCachedPop(address, []byte{2, 2}, newPop))
+
+	_, err = s.GetCachedPop(address, babylonKey)
+	require.ErrorIs(t, err, ErrCachedPopNotFound)
+
+	cached, err = s.GetCachedPop(address, []byte{2, 2})
+	require.NoError(t, err)
+	require.Equal(t, newPop, cached)
+}
@@ -0,0 +1,138 @@
+package stakerdb
+
+import (
+	"time"
+
+	"github.com/babylonchain/btc-staker/proto"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	pm "google.golang.org/protobuf/proto"
+
+	"github.com/lightningnetwork/lnd/kvdb"
+)
+
+var (
+	// mapping lastReportKey -> proto.ReconciliationReport
+	// It holds result of the last reconciliation of local db against babylon
+	reconciliationBucketName = []byte("reconciliation")
+
+	// key for last reconciliation report
+	lastReportKey = []byte("lrk")
+)
+
+type ReconciliationDiscrepancy struct {
+	StakingTxHash chainhash.Hash
+	LocalState    proto.TransactionState
+	Type          proto.DiscrepancyType
+	Description   string
+}
+
+type ReconciliationReport struct {
+	CreatedAt              time.Time
+	NumCheckedTransactions uint64
+	Discrepancies          []ReconciliationDiscrepancy
+}
+
+func reconciliationReportToProto(r *ReconciliationReport) *proto.ReconciliationReport {
+	discrepancies := make([]*proto.ReconciliationDiscrepancy, len(r.Discrepancies))
+
+	for i, d := range r.Discrepancies {
+		discrepancies[i] = &proto.ReconciliationDiscrepancy{
+			StakingTxHash:   d.StakingTxHash.CloneBytes(),
+			LocalState:      d.LocalState,
+			DiscrepancyType: d.Type,
+			Description:     d.Description,
+		}
+	}
+
+	return &proto.ReconciliationReport{
+		CreatedAt:           r.CreatedAt.Unix(),
+		CheckedTransactions: r.NumCheckedTransactions,
+		Discrepancies:       discrepancies,
+	}
+}
+
+func protoReconciliationReportToReport(r *proto.ReconciliationReport) (*ReconciliationReport, error) {
+	discrepancies := make([]ReconciliationDiscrepancy, len(r.Discrepancies))
+
+	for i, d := range r.Discrepancies {
+		txHash, err := chainhash.NewHash(d.StakingTxHash)
+
+		if err != nil {
+			return nil, err
+		}
+
+		discrepancies[i] = ReconciliationDiscrepancy{
+			StakingTxHash: *txHash,
+			LocalState:    d.LocalState,
+			Type:          d.DiscrepancyType,
+			Description:   d.Description,
+		}
+	}
+
+	return &ReconciliationReport{
+		CreatedAt:              time.Unix(r.CreatedAt, 0),
+		NumCheckedTransactions: r.CheckedTransactions,
+		Discrepancies:          discrepancies,
+	}, nil
+}
+
+// SaveReconciliationReport overwrites last stored reconciliation report with
+// the provided one
+func (c *TrackedTransactionStore) SaveReconciliationReport(report *ReconciliationReport) error {
+	reportBytes, err := pm.Marshal(reconciliationReportToProto(report))
+
+	if err != nil {
+		return err
+	}
+
+	return kvdb.Batch(c.db, func(tx kvdb.RwTx) error {
+		reportBucket := tx.ReadWriteBucket(reconciliationBucketName)
+
+		if reportBucket == nil {
+			return ErrCorruptedTransactionsDb
+		}
+
+		return reportBucket.Put(lastReportKey, reportBytes)
+	})
+}
+
+// GetLastReconciliationReport returns report of the last finished reconciliation
+func (c *TrackedTransactionStore) GetLastReconciliationReport() (*ReconciliationReport, error) {
+	var report *ReconciliationReport
+
+	err := c.db.View(func(tx kvdb.RTx) error {
+		reportBucket := tx.ReadBucket(reconciliationBucketName)
+
+		if reportBucket == nil {
+			return ErrCorruptedTransactionsDb
+		}
+
+		maybeReport := reportBucket.Get(lastReportKey)
+
+		if maybeReport == nil {
+			return ErrReconciliationReportNotFound
+		}
+
+		var reportProto proto.ReconciliationReport
+
+		if err := pm.Unmarshal(maybeReport, &reportProto); err != nil {
+			return ErrCorruptedTransactionsDb
+		}
+
+		reportFromDb, err := protoReconciliationReportToReport(&reportProto)
+
+		if err != nil {
+			return err
+		}
+
+		report = reportFromDb
+
+		return nil
+	}, func() {})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
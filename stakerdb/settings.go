@@ -0,0 +1,68 @@
+package stakerdb
+
+import (
+	"github.com/lightningnetwork/lnd/kvdb"
+)
+
+var (
+	// mapping settingKey -> setting value
+	// It holds runtime settings changed by operator, which must survive restarts
+	settingsBucketName = []byte("settings")
+
+	// key for flag whether broadcasting of btc transactions is paused
+	broadcastsPausedKey = []byte("bp")
+)
+
+// SetBroadcastsPaused stores whether broadcasting of btc transactions is paused
+func (c *TrackedTransactionStore) SetBroadcastsPaused(paused bool) error {
+	value := []byte{0}
+	if paused {
+		value = []byte{1}
+	}
+
+	return kvdb.Batch(c.db, func(tx kvdb.RwTx) error {
+		settingsBucket := tx.ReadWriteBucket(settingsBucketName)
+
+		if settingsBucket == nil {
+			return ErrCorruptedTransactionsDb
+		}
+
+		return settingsBucket.Put(broadcastsPausedKey, value)
+	})
+}
+
+// BroadcastsPaused returns whether broadcasting of btc transactions is paused.
+// Broadcasts are enabled unless they were explicitly paused.
+func (c *TrackedTransactionStore) BroadcastsPaused() (bool, error) {
+	var paused bool
+
+	err := c.db.View(func(tx kvdb.RTx) error {
+		settingsBucket := tx.ReadBucket(settingsBucketName)
+
+		if settingsBucket == nil {
+			return ErrCorruptedTransactionsDb
+		}
+
+		value := settingsBucket.Get(broadcastsPausedKey)
+
+		if value == nil {
+			return nil
+		}
+
+		if len(value) != 1 {
+			return ErrCorruptedTransactionsDb
+		}
+
+		paused = value[0] == 1
+
+		return nil
+	}, func() {
+		paused = false
+	})
+
+	if err != nil {
+		return false, err
+	}
+
+	return paused, nil
+}
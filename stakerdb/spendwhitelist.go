@@ -0,0 +1,70 @@
+package stakerdb
+
+import (
+	"github.com/lightningnetwork/lnd/kvdb"
+)
+
+var (
+	// mapping address -> empty value
+	// It holds destination addresses of spends added by operator at runtime
+	spendWhitelistBucketName = []byte("spendWhitelist")
+)
+
+// AddWhitelistedAddress adds address to the stored spend destination whitelist.
+// Adding address which is already whitelisted is not an error.
+func (c *TrackedTransactionStore) AddWhitelistedAddress(address string) error {
+	return kvdb.Batch(c.db, func(tx kvdb.RwTx) error {
+		whitelistBucket := tx.ReadWriteBucket(spendWhitelistBucketName)
+
+		if whitelistBucket == nil {
+			return ErrCorruptedTransactionsDb
+		}
+
+		return whitelistBucket.Put([]byte(address), []byte{})
+	})
+}
+
+// RemoveWhitelistedAddress removes address from the stored spend destination
+// whitelist
+func (c *TrackedTransactionStore) RemoveWhitelistedAddress(address string) error {
+	return kvdb.Batch(c.db, func(tx kvdb.RwTx) error {
+		whitelistBucket := tx.ReadWriteBucket(spendWhitelistBucketName)
+
+		if whitelistBucket == nil {
+			return ErrCorruptedTransactionsDb
+		}
+
+		if whitelistBucket.Get([]byte(address)) == nil {
+			return ErrWhitelistedAddressNotFound
+		}
+
+		return whitelistBucket.Delete([]byte(address))
+	})
+}
+
+// GetWhitelistedAddresses returns all addresses from the stored spend destination
+// whitelist, sorted
+func (c *TrackedTransactionStore) GetWhitelistedAddresses() ([]string, error) {
+	var addresses []string
+
+	err := c.db.View(func(tx kvdb.RTx) error {
+		whitelistBucket := tx.ReadBucket(spendWhitelistBucketName)
+
+		if whitelistBucket == nil {
+			return ErrCorruptedTransactionsDb
+		}
+
+		return whitelistBucket.ForEach(func(k, _ []byte) error {
+			addresses = append(addresses, string(k))
+			return nil
+		})
+	}, func() {
+		addresses = nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return addresses, nil
+}
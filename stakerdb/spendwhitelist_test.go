@@ -0,0 +1,30 @@
+package stakerdb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSpendWhitelist(t *testing.T) {
+	s, _ := makeTestStore(t)
+
+	addresses, err := s.GetWhitelistedAddresses()
+	require.NoError(t, err)
+	require.Empty(t, addresses)
+
+	require.NoError(t, s.AddWhitelistedAddress("b"))
+	require.NoError(t, s.AddWhitelistedAddress("a"))
+	require.NoError(t, s.AddWhitelistedAddress("a"))
+
+	addresses, err = s.GetWhitelistedAddresses()
+	require.NoError(t, err)
+	require.Equal(t, []string{"a", "b"}, addresses)
+
+	require.NoError(t, s.RemoveWhitelistedAddress("a"))
+	require.ErrorIs(t, s.RemoveWhitelistedAddress("a"), ErrWhitelistedAddressNotFound)
+
+	addresses, err = s.GetWhitelistedAddresses()
+	require.NoError(t, err)
+	require.Equal(t, []string{"b"}, addresses)
+}
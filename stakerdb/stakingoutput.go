@@ -0,0 +1,63 @@
+package stakerdb
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/babylonchain/btc-staker/proto"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+)
+
+// SetTxCorrupted flags record of transaction as corrupted i.e not matching the
+// staking transaction it describes. Record stays tracked, but its staking output
+// must not be spent until the record is repaired.
+func (c *TrackedTransactionStore) SetTxCorrupted(txHash *chainhash.Hash, reason string) error {
+	if reason == "" {
+		return fmt.Errorf("corruption reason must not be empty")
+	}
+
+	setCorrupted := func(tx *proto.TrackedTransaction) error {
+		tx.CorruptionReason = reason
+		return nil
+	}
+
+	return c.setTxState(txHash, setCorrupted)
+}
+
+// RepairStakingOutputIndex points record of transaction at given staking output
+// and clears its corruption flag. If recorded change output is the new staking
+// output, indexes of the outputs were swapped, so change output is pointed at the
+// previous staking output.
+func (c *TrackedTransactionStore) RepairStakingOutputIndex(txHash *chainhash.Hash, outputIdx uint32) error {
+	repair := func(tx *proto.TrackedTransaction) error {
+		var stakingTx wire.MsgTx
+		if err := stakingTx.Deserialize(bytes.NewReader(tx.StakingTransaction)); err != nil {
+			return err
+		}
+
+		if int(outputIdx) >= len(stakingTx.TxOut) {
+			return fmt.Errorf(
+				"%w: %d, staking transaction has %d outputs",
+				ErrInvalidStakingOutputIndex,
+				outputIdx,
+				len(stakingTx.TxOut),
+			)
+		}
+
+		previousIdx := tx.StakingOutputIdx
+
+		if tx.ChangeOutput != nil &&
+			tx.ChangeOutput.OutputIdx == outputIdx &&
+			int(previousIdx) < len(stakingTx.TxOut) {
+			tx.ChangeOutput.OutputIdx = previousIdx
+			tx.ChangeOutput.Amount = stakingTx.TxOut[previousIdx].Value
+		}
+
+		tx.StakingOutputIdx = outputIdx
+		tx.CorruptionReason = ""
+		return nil
+	}
+
+	return c.setTxState(txHash, repair)
+}
@@ -0,0 +1,101 @@
+package stakerdb
+
+import (
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepairSwappedStakingOutputIndex(t *testing.T) {
+	s, _ := makeTestStore(t)
+
+	priv, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	addr, err := btcutil.NewAddressPubKeyHash(make([]byte, 20), &chaincfg.RegressionNetParams)
+	require.NoError(t, err)
+
+	btcTx := wire.NewMsgTx(2)
+	btcTx.AddTxIn(wire.NewTxIn(&wire.OutPoint{}, nil, nil))
+	btcTx.AddTxOut(wire.NewTxOut(1000, []byte{0x51}))
+	btcTx.AddTxOut(wire.NewTxOut(500, []byte{0x52}))
+	txHash := btcTx.TxHash()
+
+	// indexes of staking and change outputs are swapped
+	err = s.AddTransaction(
+		btcTx,
+		1,
+		100,
+		[]*btcec.PublicKey{priv.PubKey()},
+		&ProofOfPossession{BabylonSigOverBtcPk: []byte{1}, BtcSigOverBabylonSig: []byte{1}},
+		addr,
+		AddTransactionOpts{
+			ChangeOutput: &ChangeOutput{OutputIdx: 0, Amount: 1000, Address: addr.EncodeAddress()},
+		},
+	)
+	require.NoError(t, err)
+
+	require.Error(t, s.SetTxCorrupted(&txHash, ""))
+	require.NoError(t, s.SetTxCorrupted(&txHash, "staking output does not match"))
+
+	storedTx, err := s.GetTransaction(&txHash)
+	require.NoError(t, err)
+	require.Equal(t, "staking output does not match", storedTx.CorruptionReason)
+
+	require.ErrorIs(t, s.RepairStakingOutputIndex(&txHash, 2), ErrInvalidStakingOutputIndex)
+
+	require.NoError(t, s.RepairStakingOutputIndex(&txHash, 0))
+
+	storedTx, err = s.GetTransaction(&txHash)
+	require.NoError(t, err)
+	require.Empty(t, storedTx.CorruptionReason)
+	require.Equal(t, uint32(0), storedTx.StakingOutputIndex)
+	require.Equal(t, uint32(1), storedTx.ChangeOutput.OutputIdx)
+	require.Equal(t, btcutil.Amount(500), storedTx.ChangeOutput.Amount)
+}
+
+func TestUnbondingOutputIndex(t *testing.T) {
+	s, _ := makeTestStore(t)
+
+	priv, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	addr, err := btcutil.NewAddressPubKeyHash(make([]byte, 20), &chaincfg.RegressionNetParams)
+	require.NoError(t, err)
+
+	btcTx := wire.NewMsgTx(2)
+	btcTx.AddTxIn(wire.NewTxIn(&wire.OutPoint{}, nil, nil))
+	btcTx.AddTxOut(wire.NewTxOut(1000, []byte{0x51}))
+	txHash := btcTx.TxHash()
+
+	err = s.AddTransaction(
+		btcTx,
+		0,
+		100,
+		[]*btcec.PublicKey{priv.PubKey()},
+		&ProofOfPossession{BabylonSigOverBtcPk: []byte{1}, BtcSigOverBabylonSig: []byte{1}},
+		addr,
+		AddTransactionOpts{},
+	)
+	require.NoError(t, err)
+	require.NoError(t, s.SetTxConfirmed(&txHash, &txHash, 1, time.Time{}))
+
+	// unbonding output is not the first output of unbonding transaction
+	unbondingTx := wire.NewMsgTx(2)
+	unbondingTx.AddTxIn(wire.NewTxIn(wire.NewOutPoint(&txHash, 0), nil, nil))
+	unbondingTx.AddTxOut(wire.NewTxOut(330, []byte{0x51}))
+	unbondingTx.AddTxOut(wire.NewTxOut(600, []byte{0x52}))
+
+	require.Error(t, s.SetTxSentToBabylon(&txHash, unbondingTx, 2, 50, "", ""))
+	require.NoError(t, s.SetTxSentToBabylon(&txHash, unbondingTx, 1, 50, "", ""))
+
+	storedTx, err := s.GetTransaction(&txHash)
+	require.NoError(t, err)
+	require.Equal(t, uint32(1), storedTx.UnbondingTxData.UnbondingOutputIndex)
+	require.Equal(t, int64(600), storedTx.UnbondingTxData.UnbondingOutput().Value)
+}
@@ -0,0 +1,183 @@
+package stakerdb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"sort"
+
+	"github.com/babylonchain/btc-staker/proto"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	pm "google.golang.org/protobuf/proto"
+
+	"github.com/lightningnetwork/lnd/kvdb"
+)
+
+var (
+	// mapping uint64 -> proto.StakingParamsSnapshot
+	// It holds every distinct set of staking params under which staking transactions
+	// were created
+	stakingParamsBucketName = []byte("stakingParams")
+)
+
+// StakingParamsSnapshot is the part of babylon staking params which is commited
+// to in staking output, and which is necessary to spend it
+type StakingParamsSnapshot struct {
+	Version        uint32
+	CovenantPks    []*btcec.PublicKey
+	CovenantQuorum uint32
+}
+
+// covenant keys order is irrelevant for staking scripts, so they are stored sorted
+// to make comparison of snapshots simple
+func sortedSerializedKeys(keys []*btcec.PublicKey) [][]byte {
+	serialized := make([][]byte, len(keys))
+
+	for i, k := range keys {
+		serialized[i] = schnorr.SerializePubKey(k)
+	}
+
+	sort.Slice(serialized, func(i, j int) bool {
+		return bytes.Compare(serialized[i], serialized[j]) < 0
+	})
+
+	return serialized
+}
+
+func snapshotMatches(s *proto.StakingParamsSnapshot, covenantPks [][]byte, covenantQuorum uint32) bool {
+	if s.CovenantQuorum != covenantQuorum || len(s.CovenantPks) != len(covenantPks) {
+		return false
+	}
+
+	for i, pk := range s.CovenantPks {
+		if !bytes.Equal(pk, covenantPks[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func protoSnapshotToSnapshot(s *proto.StakingParamsSnapshot) (*StakingParamsSnapshot, error) {
+	covenantPks := make([]*btcec.PublicKey, len(s.CovenantPks))
+
+	for i, pk := range s.CovenantPks {
+		parsed, err := schnorr.ParsePubKey(pk)
+
+		if err != nil {
+			return nil, err
+		}
+
+		covenantPks[i] = parsed
+	}
+
+	return &StakingParamsSnapshot{
+		Version:        s.Version,
+		CovenantPks:    covenantPks,
+		CovenantQuorum: s.CovenantQuorum,
+	}, nil
+}
+
+// RegisterStakingParams returns version of the snapshot matching provided params.
+// If params differ from the latest stored snapshot, new snapshot is stored under
+// the next version. Versions start from 1.
+func (c *TrackedTransactionStore) RegisterStakingParams(
+	covenantPks []*btcec.PublicKey,
+	covenantQuorum uint32,
+) (uint32, error) {
+	serializedPks := sortedSerializedKeys(covenantPks)
+
+	var version uint32
+
+	err := kvdb.Batch(c.db, func(tx kvdb.RwTx) error {
+		paramsBucket := tx.ReadWriteBucket(stakingParamsBucketName)
+
+		if paramsBucket == nil {
+			return ErrCorruptedTransactionsDb
+		}
+
+		var nextVersion uint32 = 1
+
+		lastKey, lastSnapshotBytes := paramsBucket.ReadWriteCursor().Last()
+
+		if lastKey != nil {
+			var lastSnapshot proto.StakingParamsSnapshot
+
+			if err := pm.Unmarshal(lastSnapshotBytes, &lastSnapshot); err != nil {
+				return ErrCorruptedTransactionsDb
+			}
+
+			if snapshotMatches(&lastSnapshot, serializedPks, covenantQuorum) {
+				version = lastSnapshot.Version
+				return nil
+			}
+
+			nextVersion = uint32(binary.BigEndian.Uint64(lastKey)) + 1
+		}
+
+		snapshotBytes, err := pm.Marshal(&proto.StakingParamsSnapshot{
+			Version:        nextVersion,
+			CovenantPks:    serializedPks,
+			CovenantQuorum: covenantQuorum,
+		})
+
+		if err != nil {
+			return err
+		}
+
+		if err := paramsBucket.Put(uint64KeyToBytes(uint64(nextVersion)), snapshotBytes); err != nil {
+			return err
+		}
+
+		version = nextVersion
+
+		return nil
+	})
+
+	if err != nil {
+		return 0, err
+	}
+
+	return version, nil
+}
+
+// GetStakingParams returns staking params snapshot with given version
+func (c *TrackedTransactionStore) GetStakingParams(version uint32) (*StakingParamsSnapshot, error) {
+	var snapshot *StakingParamsSnapshot
+
+	err := c.db.View(func(tx kvdb.RTx) error {
+		paramsBucket := tx.ReadBucket(stakingParamsBucketName)
+
+		if paramsBucket == nil {
+			return ErrCorruptedTransactionsDb
+		}
+
+		maybeSnapshot := paramsBucket.Get(uint64KeyToBytes(uint64(version)))
+
+		if maybeSnapshot == nil {
+			return ErrStakingParamsNotFound
+		}
+
+		var snapshotProto proto.StakingParamsSnapshot
+
+		if err := pm.Unmarshal(maybeSnapshot, &snapshotProto); err != nil {
+			return ErrCorruptedTransactionsDb
+		}
+
+		snapshotFromDb, err := protoSnapshotToSnapshot(&snapshotProto)
+
+		if err != nil {
+			return err
+		}
+
+		snapshot = snapshotFromDb
+
+		return nil
+	}, func() {})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return snapshot, nil
+}
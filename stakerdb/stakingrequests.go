@@ -0,0 +1,247 @@
+package stakerdb
+
+import (
+	"encoding/binary"
+	"time"
+
+	"github.com/babylonchain/btc-staker/proto"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	pm "google.golang.org/protobuf/proto"
+
+	"github.com/lightningnetwork/lnd/kvdb"
+)
+
+var (
+	// mapping requestId -> proto.StakingRequestRecord
+	// It holds terminal results of asynchronous staking requests
+	stakingRequestsBucketName = []byte("stakingRequests")
+
+	// mapping updatedAt || requestId -> nil
+	// It orders staking requests by time of their last update, so that old
+	// requests are pruned without scanning all of them
+	stakingRequestsByTimeBucketName = []byte("stakingRequestsByTime")
+)
+
+func stakingRequestTimeKey(updatedAt int64, requestId []byte) []byte {
+	return append(uint64KeyToBytes(uint64(updatedAt)), requestId...)
+}
+
+// initStakingRequestsTimeIndex creates time index of staking requests stored by
+// previous versions of staker
+func initStakingRequestsTimeIndex(rwTx kvdb.RwTx) error {
+	if rwTx.ReadWriteBucket(stakingRequestsByTimeBucketName) != nil {
+		return nil
+	}
+
+	indexBucket, err := rwTx.CreateTopLevelBucket(stakingRequestsByTimeBucketName)
+	if err != nil {
+		return err
+	}
+
+	requestsBucket := rwTx.ReadWriteBucket(stakingRequestsBucketName)
+
+	if requestsBucket == nil {
+		return ErrCorruptedTransactionsDb
+	}
+
+	return requestsBucket.ForEach(func(k, v []byte) error {
+		var recordProto proto.StakingRequestRecord
+
+		if err := pm.Unmarshal(v, &recordProto); err != nil {
+			return ErrCorruptedTransactionsDb
+		}
+
+		return indexBucket.Put(stakingRequestTimeKey(recordProto.UpdatedAt, k), nil)
+	})
+}
+
+type StakingRequestRecord struct {
+	RequestId string
+	Status    proto.StakingRequestStatus
+	// only set if request status is STAKING_REQUEST_BROADCAST
+	StakingTxHash *chainhash.Hash
+	// only set if request status is STAKING_REQUEST_FAILED
+	Error     string
+	UpdatedAt time.Time
+}
+
+func stakingRequestRecordToProto(r *StakingRequestRecord) *proto.StakingRequestRecord {
+	var txHash []byte
+	if r.StakingTxHash != nil {
+		txHash = r.StakingTxHash.CloneBytes()
+	}
+
+	return &proto.StakingRequestRecord{
+		RequestId:     r.RequestId,
+		Status:        r.Status,
+		StakingTxHash: txHash,
+		Error:         r.Error,
+		UpdatedAt:     r.UpdatedAt.Unix(),
+	}
+}
+
+func protoStakingRequestRecordToRecord(r *proto.StakingRequestRecord) (*StakingRequestRecord, error) {
+	var txHash *chainhash.Hash
+	if len(r.StakingTxHash) > 0 {
+		hash, err := chainhash.NewHash(r.StakingTxHash)
+
+		if err != nil {
+			return nil, err
+		}
+
+		txHash = hash
+	}
+
+	return &StakingRequestRecord{
+		RequestId:     r.RequestId,
+		Status:        r.Status,
+		StakingTxHash: txHash,
+		Error:         r.Error,
+		UpdatedAt:     time.Unix(r.UpdatedAt, 0),
+	}, nil
+}
+
+func (c *TrackedTransactionStore) SaveStakingRequest(record *StakingRequestRecord) error {
+	recordBytes, err := pm.Marshal(stakingRequestRecordToProto(record))
+
+	if err != nil {
+		return err
+	}
+
+	requestId := []byte(record.RequestId)
+
+	return kvdb.Batch(c.db, func(tx kvdb.RwTx) error {
+		requestsBucket := tx.ReadWriteBucket(stakingRequestsBucketName)
+
+		if requestsBucket == nil {
+			return ErrCorruptedTransactionsDb
+		}
+
+		indexBucket := tx.ReadWriteBucket(stakingRequestsByTimeBucketName)
+
+		if indexBucket == nil {
+			return ErrCorruptedTransactionsDb
+		}
+
+		// record is updated, so it must be moved in time index
+		if maybeRecord := requestsBucket.Get(requestId); maybeRecord != nil {
+			var oldRecord proto.StakingRequestRecord
+
+			if err := pm.Unmarshal(maybeRecord, &oldRecord); err != nil {
+				return ErrCorruptedTransactionsDb
+			}
+
+			if err := indexBucket.Delete(stakingRequestTimeKey(oldRecord.UpdatedAt, requestId)); err != nil {
+				return err
+			}
+		}
+
+		if err := requestsBucket.Put(requestId, recordBytes); err != nil {
+			return err
+		}
+
+		return indexBucket.Put(stakingRequestTimeKey(record.UpdatedAt.Unix(), requestId), nil)
+	})
+}
+
+func (c *TrackedTransactionStore) GetStakingRequest(requestId string) (*StakingRequestRecord, error) {
+	var record *StakingRequestRecord
+
+	err := c.db.View(func(tx kvdb.RTx) error {
+		requestsBucket := tx.ReadBucket(stakingRequestsBucketName)
+
+		if requestsBucket == nil {
+			return ErrCorruptedTransactionsDb
+		}
+
+		maybeRecord := requestsBucket.Get([]byte(requestId))
+
+		if maybeRecord == nil {
+			return ErrStakingRequestNotFound
+		}
+
+		var recordProto proto.StakingRequestRecord
+
+		if err := pm.Unmarshal(maybeRecord, &recordProto); err != nil {
+			return ErrCorruptedTransactionsDb
+		}
+
+		recordFromDb, err := protoStakingRequestRecordToRecord(&recordProto)
+
+		if err != nil {
+			return err
+		}
+
+		record = recordFromDb
+
+		return nil
+	}, func() {})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return record, nil
+}
+
+// PruneStakingRequests removes all staking requests last updated before provided
+// time. It returns number of removed requests.
+func (c *TrackedTransactionStore) PruneStakingRequests(olderThan time.Time) (int, error) {
+	var numRemoved int
+
+	err := kvdb.Update(c.db, func(tx kvdb.RwTx) error {
+		requestsBucket := tx.ReadWriteBucket(stakingRequestsBucketName)
+
+		if requestsBucket == nil {
+			return ErrCorruptedTransactionsDb
+		}
+
+		indexBucket := tx.ReadWriteBucket(stakingRequestsByTimeBucketName)
+
+		if indexBucket == nil {
+			return ErrCorruptedTransactionsDb
+		}
+
+		var toRemove [][]byte
+
+		// index is ordered by update time, so only requests which are removed
+		// are visited
+		cursor := indexBucket.ReadCursor()
+		for k, _ := cursor.First(); k != nil; k, _ = cursor.Next() {
+			if len(k) < 8 {
+				return ErrCorruptedTransactionsDb
+			}
+
+			updatedAt := int64(binary.BigEndian.Uint64(k[:8]))
+
+			if !time.Unix(updatedAt, 0).Before(olderThan) {
+				break
+			}
+
+			// copy key, as it is only valid during iteration
+			toRemove = append(toRemove, append([]byte(nil), k...))
+		}
+
+		for _, k := range toRemove {
+			if err := requestsBucket.Delete(k[8:]); err != nil {
+				return err
+			}
+
+			if err := indexBucket.Delete(k); err != nil {
+				return err
+			}
+		}
+
+		numRemoved = len(toRemove)
+
+		return nil
+	}, func() {
+		numRemoved = 0
+	})
+
+	if err != nil {
+		return 0, err
+	}
+
+	return numRemoved, nil
+}
@@ -0,0 +1,104 @@
+package stakerdb
+
+import (
+	"testing"
+	"time"
+
+	"github.com/babylonchain/btc-staker/proto"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/lightningnetwork/lnd/kvdb"
+	"github.com/stretchr/testify/require"
+)
+
+func failedStakingRequest(requestId string, updatedAt int64) *StakingRequestRecord {
+	return &StakingRequestRecord{
+		RequestId: requestId,
+		Status:    proto.StakingRequestStatus_STAKING_REQUEST_FAILED,
+		Error:     "failed",
+		UpdatedAt: time.Unix(updatedAt, 0),
+	}
+}
+
+func TestSaveAndGetStakingRequest(t *testing.T) {
+	s, _ := makeTestStore(t)
+
+	_, err := s.GetStakingRequest("unknown")
+	require.ErrorIs(t, err, ErrStakingRequestNotFound)
+
+	stakingTxHash := chainhash.HashH([]byte("staking"))
+	broadcast := &StakingRequestRecord{
+		RequestId:     "broadcast",
+		Status:        proto.StakingRequestStatus_STAKING_REQUEST_BROADCAST,
+		StakingTxHash: &stakingTxHash,
+		UpdatedAt:     time.Unix(1000, 0),
+	}
+	require.NoError(t, s.SaveStakingRequest(broadcast))
+
+	failed := failedStakingRequest("failed", 1000)
+	require.NoError(t, s.SaveStakingRequest(failed))
+
+	record, err := s.GetStakingRequest("broadcast")
+	require.NoError(t, err)
+	require.Equal(t, broadcast, record)
+
+	record, err = s.GetStakingRequest("failed")
+	require.NoError(t, err)
+	require.Equal(t, failed, record)
+}
+
+func TestPruneStakingRequests(t *testing.T) {
+	s, _ := makeTestStore(t)
+
+	require.NoError(t, s.SaveStakingRequest(failedStakingRequest("old", 1000)))
+	require.NoError(t, s.SaveStakingRequest(failedStakingRequest("new", 3000)))
+	require.NoError(t, s.SaveStakingRequest(failedStakingRequest("updated", 1000)))
+	// update moves request in time index
+	require.NoError(t, s.SaveStakingRequest(failedStakingRequest("updated", 3000)))
+
+	numRemoved, err := s.PruneStakingRequests(time.Unix(2000, 0))
+	require.NoError(t, err)
+	require.Equal(t, 1, numRemoved)
+
+	_, err = s.GetStakingRequest("old")
+	require.ErrorIs(t, err, ErrStakingRequestNotFound)
+
+	for _, requestId := range []string{"new", "updated"} {
+		_, err = s.GetStakingRequest(requestId)
+		require.NoError(t, err)
+	}
+
+	// nothing left to prune, time index holds single entry per request
+	numRemoved, err = s.PruneStakingRequests(time.Unix(2000, 0))
+	require.NoError(t, err)
+	require.Equal(t, 0, numRemoved)
+
+	numRemoved, err = s.PruneStakingRequests(time.Unix(4000, 0))
+	require.NoError(t, err)
+	require.Equal(t, 2, numRemoved)
+}
+
+func TestStakingRequestsTimeIndexIsCreatedForOldDb(t *testing.T) {
+	s, backend := makeTestStore(t)
+
+	require.NoError(t, s.SaveStakingRequest(failedStakingRequest("old", 1000)))
+	require.NoError(t, s.SaveStakingRequest(failedStakingRequest("new", 3000)))
+
+	// database of previous version, without time index
+	err := kvdb.Update(backend, func(tx kvdb.RwTx) error {
+		return tx.DeleteTopLevelBucket(stakingRequestsByTimeBucketName)
+	}, func() {})
+	require.NoError(t, err)
+
+	s, err = NewTrackedTransactionStore(backend)
+	require.NoError(t, err)
+
+	numRemoved, err := s.PruneStakingRequests(time.Unix(2000, 0))
+	require.NoError(t, err)
+	require.Equal(t, 1, numRemoved)
+
+	_, err = s.GetStakingRequest("old")
+	require.ErrorIs(t, err, ErrStakingRequestNotFound)
+
+	_, err = s.GetStakingRequest("new")
+	require.NoError(t, err)
+}
@@ -0,0 +1,159 @@
+package stakerdb
+
+import (
+	"encoding/binary"
+
+	"github.com/babylonchain/btc-staker/proto"
+	pm "google.golang.org/protobuf/proto"
+
+	"github.com/lightningnetwork/lnd/kvdb"
+)
+
+var (
+	// mapping proto.TransactionState -> uint64
+	// It holds number of tracked transactions in each state. Counts are updated
+	// together with transactions, so they can be read without scanning all
+	// transactions.
+	stateCountsBucketName = []byte("stateCounts")
+)
+
+// IsTerminalState returns true for states after which staker does not do anything
+// with transaction anymore
+func IsTerminalState(state proto.TransactionState) bool {
+	return state == proto.TransactionState_SPENT_ON_BTC ||
+		state == proto.TransactionState_MISSING_ON_BTC ||
+		state == proto.TransactionState_CONFLICTED
+}
+
+func stateCountKey(state proto.TransactionState) []byte {
+	return uint64KeyToBytes(uint64(state))
+}
+
+// changeStateCount adds delta to number of transactions in given state. Count
+// never drops below zero.
+func changeStateCount(rwTx kvdb.RwTx, state proto.TransactionState, delta int64) error {
+	return changeCount(rwTx, stateCountsBucketName, state, delta)
+}
+
+// changeCount adds delta to count of given state in given counts bucket
+func changeCount(rwTx kvdb.RwTx, bucketName []byte, state proto.TransactionState, delta int64) error {
+	countsBucket := rwTx.ReadWriteBucket(bucketName)
+
+	if countsBucket == nil {
+		return ErrCorruptedTransactionsDb
+	}
+
+	key := stateCountKey(state)
+
+	var count uint64
+	if countBytes := countsBucket.Get(key); countBytes != nil {
+		count = binary.BigEndian.Uint64(countBytes)
+	}
+
+	if delta < 0 && uint64(-delta) > count {
+		count = 0
+	} else {
+		count = uint64(int64(count) + delta)
+	}
+
+	return countsBucket.Put(key, uint64KeyToBytes(count))
+}
+
+// initStateCounts creates state counts of databases created by previous versions
+// of staker. It is the only time when all transactions are scanned to count them.
+func initStateCounts(rwTx kvdb.RwTx) error {
+	if rwTx.ReadWriteBucket(stateCountsBucketName) != nil {
+		return nil
+	}
+
+	if _, err := rwTx.CreateTopLevelBucket(stateCountsBucketName); err != nil {
+		return err
+	}
+
+	transactionsBucket := rwTx.ReadWriteBucket(transactionBucketName)
+
+	if transactionsBucket == nil {
+		return ErrCorruptedTransactionsDb
+	}
+
+	counts := make(map[proto.TransactionState]int64)
+
+	err := transactionsBucket.ForEach(func(_, v []byte) error {
+		var storedTxProto proto.TrackedTransaction
+
+		// corrupted transactions are not counted, they are quarantined when
+		// staker tries to use them
+		if err := pm.Unmarshal(v, &storedTxProto); err != nil {
+			return nil
+		}
+
+		counts[storedTxProto.State]++
+		return nil
+	})
+
+	if err != nil {
+		return err
+	}
+
+	for state, count := range counts {
+		if err := changeStateCount(rwTx, state, count); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// TransactionStateCounts returns number of tracked transactions in each state.
+// States without any transactions are omitted.
+func (c *TrackedTransactionStore) TransactionStateCounts() (map[proto.TransactionState]uint64, error) {
+	return c.readCounts(stateCountsBucketName)
+}
+
+func (c *TrackedTransactionStore) readCounts(bucketName []byte) (map[proto.TransactionState]uint64, error) {
+	counts := make(map[proto.TransactionState]uint64)
+
+	err := c.db.View(func(tx kvdb.RTx) error {
+		countsBucket := tx.ReadBucket(bucketName)
+
+		if countsBucket == nil {
+			return ErrCorruptedTransactionsDb
+		}
+
+		return countsBucket.ForEach(func(k, v []byte) error {
+			if count := binary.BigEndian.Uint64(v); count > 0 {
+				counts[proto.TransactionState(binary.BigEndian.Uint64(k))] = count
+			}
+
+			return nil
+		})
+	}, func() {
+		counts = make(map[proto.TransactionState]uint64)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return counts, nil
+}
+
+// CountActiveTransactions returns number of tracked transactions which are not in
+// terminal state
+func (c *TrackedTransactionStore) CountActiveTransactions() (uint64, error) {
+	counts, err := c.TransactionStateCounts()
+
+	if err != nil {
+		return 0, err
+	}
+
+	var active uint64
+
+	for state, count := range counts {
+		if !IsTerminalState(state) {
+			active += count
+		}
+	}
+
+	return active, nil
+}
@@ -0,0 +1,131 @@
+package stakerdb
+
+import (
+	"testing"
+	"time"
+
+	"github.com/babylonchain/btc-staker/proto"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/kvdb"
+	"github.com/stretchr/testify/require"
+	pm "google.golang.org/protobuf/proto"
+)
+
+func addTestTransaction(t *testing.T, s *TrackedTransactionStore, value int64) *chainhash.Hash {
+	return addLabeledTestTransaction(t, s, value, "")
+}
+
+func addLabeledTestTransaction(t *testing.T, s *TrackedTransactionStore, value int64, label string) *chainhash.Hash {
+	priv, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	addr, err := btcutil.NewAddressPubKeyHash(make([]byte, 20), &chaincfg.RegressionNetParams)
+	require.NoError(t, err)
+
+	btcTx := wire.NewMsgTx(2)
+	btcTx.AddTxIn(wire.NewTxIn(&wire.OutPoint{}, nil, nil))
+	btcTx.AddTxOut(wire.NewTxOut(value, []byte{0x51}))
+
+	err = s.AddTransaction(
+		btcTx,
+		0,
+		100,
+		[]*btcec.PublicKey{priv.PubKey()},
+		&ProofOfPossession{BabylonSigOverBtcPk: []byte{1}, BtcSigOverBabylonSig: []byte{1}},
+		addr,
+		AddTransactionOpts{
+			Label: label,
+		},
+	)
+	require.NoError(t, err)
+
+	txHash := btcTx.TxHash()
+	return &txHash
+}
+
+func TestStateCountsFollowTransitions(t *testing.T) {
+	s, _ := makeTestStore(t)
+
+	first := addTestTransaction(t, s, 1000)
+	second := addTestTransaction(t, s, 2000)
+	addTestTransaction(t, s, 3000)
+
+	require.NoError(t, s.SetTxConfirmed(first, &chainhash.Hash{}, 10, time.Time{}))
+	require.NoError(t, s.SetTxConfirmed(second, &chainhash.Hash{}, 10, time.Time{}))
+	require.NoError(t, s.SetTxSpentOnBtc(second, nil))
+
+	// failed transition does not change counts
+	require.Error(t, s.SetTxSpentOnBtc(second, nil))
+
+	counts, err := s.TransactionStateCounts()
+	require.NoError(t, err)
+	require.Equal(t, map[proto.TransactionState]uint64{
+		proto.TransactionState_SENT_TO_BTC:      1,
+		proto.TransactionState_CONFIRMED_ON_BTC: 1,
+		proto.TransactionState_SPENT_ON_BTC:     1,
+	}, counts)
+
+	active, err := s.CountActiveTransactions()
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), active)
+}
+
+func TestStateChangeTimeIsRecorded(t *testing.T) {
+	s, _ := makeTestStore(t)
+
+	now := time.Unix(1000, 0)
+	s.now = func() time.Time { return now }
+
+	txHash := addTestTransaction(t, s, 1000)
+
+	tx, err := s.GetTransaction(txHash)
+	require.NoError(t, err)
+	require.True(t, now.Equal(tx.StateChangedAt))
+
+	now = time.Unix(2000, 0)
+	require.NoError(t, s.SetTxConfirmed(txHash, &chainhash.Hash{}, 10, time.Time{}))
+
+	tx, err = s.GetTransaction(txHash)
+	require.NoError(t, err)
+	require.True(t, now.Equal(tx.StateChangedAt))
+
+	// failed transition does not change the time
+	now = time.Unix(3000, 0)
+	require.Error(t, s.SetTxConfirmed(txHash, &chainhash.Hash{}, 10, time.Time{}))
+
+	tx, err = s.GetTransaction(txHash)
+	require.NoError(t, err)
+	require.True(t, time.Unix(2000, 0).Equal(tx.StateChangedAt))
+}
+
+func TestStateCountsAreCreatedForExistingDb(t *testing.T) {
+	ttx := testTrackedTransaction(t)
+	serialized, err := pm.Marshal(ttx)
+	require.NoError(t, err)
+
+	txHash := chainhash.HashH([]byte("tx"))
+	_, backend := makeTestStoreWithRawTx(t, txHash[:], serialized)
+
+	// simulate database created before state counts existed
+	err = kvdb.Update(backend, func(tx kvdb.RwTx) error {
+		return tx.DeleteTopLevelBucket(stateCountsBucketName)
+	}, func() {})
+	require.NoError(t, err)
+
+	s, err := NewTrackedTransactionStore(backend)
+	require.NoError(t, err)
+
+	counts, err := s.TransactionStateCounts()
+	require.NoError(t, err)
+	require.Equal(t, map[proto.TransactionState]uint64{ttx.State: 1}, counts)
+
+	require.NoError(t, s.QuarantineTransaction(&txHash))
+
+	active, err := s.CountActiveTransactions()
+	require.NoError(t, err)
+	require.Equal(t, uint64(0), active)
+}
@@ -0,0 +1,196 @@
+package stakerdb
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/babylonchain/btc-staker/proto"
+)
+
+// InitialTransactionState is the state in which transactions are added to the store
+const InitialTransactionState = proto.TransactionState_SENT_TO_BTC
+
+// allowedTransitions maps state of tracked transaction to states to which it can
+// move. States without allowed transitions are terminal. All state setters of the
+// store must consult this table.
+var allowedTransitions = map[proto.TransactionState][]proto.TransactionState{
+	proto.TransactionState_SENT_TO_BTC: {
+		proto.TransactionState_CONFIRMED_ON_BTC,
+		// input of staking transaction was spent by other transaction
+		proto.TransactionState_CONFLICTED,
+	},
+	proto.TransactionState_CONFIRMED_ON_BTC: {
+		proto.TransactionState_SENT_TO_BABYLON,
+		// staked funds can be withdrawn if delegation was never sent to babylon
+		proto.TransactionState_SPENT_ON_BTC,
+		proto.TransactionState_MISSING_ON_BTC,
+		// operator cancelled delegation before it was sent to babylon
+		proto.TransactionState_DELEGATION_CANCELLED,
+	},
+	proto.TransactionState_SENT_TO_BABYLON: {
+		proto.TransactionState_DELEGATION_ACTIVE,
+		// unbonding transaction can be confirmed before staker learns about
+		// covenant signatures
+		proto.TransactionState_UNBONDING_CONFIRMED_ON_BTC,
+		// staked funds can be withdrawn if covenants never sign the delegation
+		proto.TransactionState_SPENT_ON_BTC,
+		// babylon can unbond delegation, which never became active e.g because
+		// its timelock passed
+		proto.TransactionState_DELEGATION_EXPIRED,
+		// covenant unbonding signatures were not received in time
+		proto.TransactionState_UNBONDING_SIGNATURES_TIMEOUT,
+	},
+	proto.TransactionState_UNBONDING_SIGNATURES_TIMEOUT: {
+		// signatures can still be received after timeout
+		proto.TransactionState_DELEGATION_ACTIVE,
+		proto.TransactionState_UNBONDING_CONFIRMED_ON_BTC,
+		proto.TransactionState_SPENT_ON_BTC,
+		proto.TransactionState_DELEGATION_EXPIRED,
+	},
+	proto.TransactionState_DELEGATION_ACTIVE: {
+		proto.TransactionState_UNBONDING_CONFIRMED_ON_BTC,
+		proto.TransactionState_SPENT_ON_BTC,
+	},
+	proto.TransactionState_UNBONDING_CONFIRMED_ON_BTC: {
+		proto.TransactionState_SPENT_ON_BTC,
+	},
+	proto.TransactionState_DELEGATION_EXPIRED: {
+		proto.TransactionState_SPENT_ON_BTC,
+	},
+	proto.TransactionState_DELEGATION_CANCELLED: {
+		proto.TransactionState_SPENT_ON_BTC,
+	},
+	proto.TransactionState_SPENT_ON_BTC: {},
+	// transaction requires manual intervention
+	proto.TransactionState_MISSING_ON_BTC: {},
+	// staking transaction can never be confirmed
+	proto.TransactionState_CONFLICTED: {},
+}
+
+// allowedReverts maps state of tracked transaction to state to which operator or
+// staker can revert it. Reverts are kept out of allowedTransitions, so that transitions
+// stay acyclic and stale transitions are still detected by checkTransition.
+var allowedReverts = map[proto.TransactionState]proto.TransactionState{
+	// waiting for covenant unbonding signatures is resumed or aborted
+	proto.TransactionState_UNBONDING_SIGNATURES_TIMEOUT: proto.TransactionState_SENT_TO_BABYLON,
+	// transaction lost by btc node is found on btc chain again
+	proto.TransactionState_MISSING_ON_BTC: proto.TransactionState_CONFIRMED_ON_BTC,
+}
+
+// ErrInvalidStateTransition is returned when transaction cannot move from its
+// current state to the requested one
+type ErrInvalidStateTransition struct {
+	From proto.TransactionState
+	To   proto.TransactionState
+}
+
+func (e *ErrInvalidStateTransition) Error() string {
+	return fmt.Sprintf("invalid state transition from %s to %s", e.From, e.To)
+}
+
+func (e *ErrInvalidStateTransition) Unwrap() error { return ErrInvalidTransactionState }
+
+// StateDefinition describes one state of tracked transaction lifecycle
+type StateDefinition struct {
+	State              proto.TransactionState
+	AllowedTransitions []proto.TransactionState
+	// state to which operator can revert transaction, nil if it cannot be
+	// reverted
+	RevertsTo *proto.TransactionState
+}
+
+// Terminal returns true if transaction cannot leave the state
+func (d *StateDefinition) Terminal() bool {
+	return len(d.AllowedTransitions) == 0
+}
+
+// StateMachine returns definition of all states of tracked transaction lifecycle,
+// sorted by state number
+func StateMachine() []StateDefinition {
+	definitions := make([]StateDefinition, 0, len(allowedTransitions))
+
+	for state, transitions := range allowedTransitions {
+		definition := StateDefinition{
+			State:              state,
+			AllowedTransitions: append([]proto.TransactionState(nil), transitions...),
+		}
+
+		if revertTo, ok := allowedReverts[state]; ok {
+			definition.RevertsTo = &revertTo
+		}
+
+		definitions = append(definitions, definition)
+	}
+
+	sort.Slice(definitions, func(i, j int) bool {
+		return definitions[i].State < definitions[j].State
+	})
+
+	return definitions
+}
+
+// IsTransitionAllowed returns true if transaction can move from one state to
+// another in a single transition
+func IsTransitionAllowed(from, to proto.TransactionState) bool {
+	for _, allowed := range allowedTransitions[from] {
+		if allowed == to {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isReachable returns true if state to can be reached from state from in one or
+// more transitions
+func isReachable(from, to proto.TransactionState) bool {
+	visited := map[proto.TransactionState]bool{from: true}
+	queue := []proto.TransactionState{from}
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		for _, next := range allowedTransitions[current] {
+			if next == to {
+				return true
+			}
+
+			if !visited[next] {
+				visited[next] = true
+				queue = append(queue, next)
+			}
+		}
+	}
+
+	return false
+}
+
+// checkTransition returns ErrAlreadyInState if transaction is already in the
+// target state or moved past it, so that duplicate or stale transitions are not
+// applied, and ErrInvalidStateTransition if transition is not allowed
+func checkTransition(tx *proto.TrackedTransaction, target proto.TransactionState) error {
+	if tx.State == target {
+		return fmt.Errorf("transaction is already in state %s: %w", target, ErrAlreadyInState)
+	}
+
+	if isReachable(target, tx.State) {
+		return fmt.Errorf("transaction in state %s already moved past state %s: %w", tx.State, target, ErrAlreadyInState)
+	}
+
+	if !IsTransitionAllowed(tx.State, target) {
+		return &ErrInvalidStateTransition{From: tx.State, To: target}
+	}
+
+	return nil
+}
+
+// checkRevert returns ErrInvalidStateTransition if transaction cannot be reverted
+// to the target state
+func checkRevert(tx *proto.TrackedTransaction, target proto.TransactionState) error {
+	if revertTo, ok := allowedReverts[tx.State]; !ok || revertTo != target {
+		return &ErrInvalidStateTransition{From: tx.State, To: target}
+	}
+
+	return nil
+}
@@ -0,0 +1,366 @@
+package stakerdb
+
+import (
+	"testing"
+	"time"
+
+	"github.com/babylonchain/btc-staker/proto"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/stretchr/testify/require"
+)
+
+type stateSetter func(s *TrackedTransactionStore, txHash *chainhash.Hash) error
+
+// stateSetters maps each state to the store setter moving transaction to it
+var stateSetters = map[proto.TransactionState]stateSetter{
+	proto.TransactionState_CONFIRMED_ON_BTC: func(s *TrackedTransactionStore, txHash *chainhash.Hash) error {
+		return s.SetTxConfirmed(txHash, &chainhash.Hash{}, 10, time.Time{})
+	},
+	proto.TransactionState_SENT_TO_BABYLON: func(s *TrackedTransactionStore, txHash *chainhash.Hash) error {
+		unbondingTx := wire.NewMsgTx(2)
+		unbondingTx.AddTxIn(wire.NewTxIn(wire.NewOutPoint(txHash, 0), nil, nil))
+		unbondingTx.AddTxOut(wire.NewTxOut(500, []byte{0x51}))
+		return s.SetTxSentToBabylon(txHash, unbondingTx, 0, 100, "", "")
+	},
+	proto.TransactionState_DELEGATION_ACTIVE: func(s *TrackedTransactionStore, txHash *chainhash.Hash) error {
+		return s.SetTxUnbondingSignaturesReceived(txHash, nil)
+	},
+	proto.TransactionState_UNBONDING_CONFIRMED_ON_BTC: func(s *TrackedTransactionStore, txHash *chainhash.Hash) error {
+		return s.SetTxUnbondingConfirmedOnBtc(txHash, &chainhash.Hash{}, 20, time.Time{})
+	},
+	proto.TransactionState_SPENT_ON_BTC: func(s *TrackedTransactionStore, txHash *chainhash.Hash) error {
+		return s.SetTxSpentOnBtc(txHash, nil)
+	},
+	proto.TransactionState_MISSING_ON_BTC: func(s *TrackedTransactionStore, txHash *chainhash.Hash) error {
+		return s.SetTxMissingOnBtc(txHash)
+	},
+	proto.TransactionState_DELEGATION_EXPIRED: func(s *TrackedTransactionStore, txHash *chainhash.Hash) error {
+		return s.SetTxDelegationExpired(txHash)
+	},
+	proto.TransactionState_UNBONDING_SIGNATURES_TIMEOUT: func(s *TrackedTransactionStore, txHash *chainhash.Hash) error {
+		return s.SetTxUnbondingSignaturesTimeout(txHash)
+	},
+	proto.TransactionState_CONFLICTED: func(s *TrackedTransactionStore, txHash *chainhash.Hash) error {
+		return s.SetTxConflicted(txHash, &chainhash.Hash{1})
+	},
+	proto.TransactionState_DELEGATION_CANCELLED: func(s *TrackedTransactionStore, txHash *chainhash.Hash) error {
+		return s.SetTxDelegationCancelled(txHash)
+	},
+}
+
+// pathsToStates lists transitions moving new transaction to given state
+var pathsToStates = map[proto.TransactionState][]proto.TransactionState{
+	proto.TransactionState_SENT_TO_BTC: {},
+	proto.TransactionState_CONFIRMED_ON_BTC: {
+		proto.TransactionState_CONFIRMED_ON_BTC,
+	},
+	proto.TransactionState_SENT_TO_BABYLON: {
+		proto.TransactionState_CONFIRMED_ON_BTC,
+		proto.TransactionState_SENT_TO_BABYLON,
+	},
+	proto.TransactionState_DELEGATION_ACTIVE: {
+		proto.TransactionState_CONFIRMED_ON_BTC,
+		proto.TransactionState_SENT_TO_BABYLON,
+		proto.TransactionState_DELEGATION_ACTIVE,
+	},
+	proto.TransactionState_UNBONDING_CONFIRMED_ON_BTC: {
+		proto.TransactionState_CONFIRMED_ON_BTC,
+		proto.TransactionState_SENT_TO_BABYLON,
+		proto.TransactionState_UNBONDING_CONFIRMED_ON_BTC,
+	},
+	proto.TransactionState_SPENT_ON_BTC: {
+		proto.TransactionState_CONFIRMED_ON_BTC,
+		proto.TransactionState_SPENT_ON_BTC,
+	},
+	proto.TransactionState_MISSING_ON_BTC: {
+		proto.TransactionState_CONFIRMED_ON_BTC,
+		proto.TransactionState_MISSING_ON_BTC,
+	},
+	proto.TransactionState_DELEGATION_EXPIRED: {
+		proto.TransactionState_CONFIRMED_ON_BTC,
+		proto.TransactionState_SENT_TO_BABYLON,
+		proto.TransactionState_DELEGATION_EXPIRED,
+	},
+	proto.TransactionState_UNBONDING_SIGNATURES_TIMEOUT: {
+		proto.TransactionState_CONFIRMED_ON_BTC,
+		proto.TransactionState_SENT_TO_BABYLON,
+		proto.TransactionState_UNBONDING_SIGNATURES_TIMEOUT,
+	},
+	proto.TransactionState_CONFLICTED: {
+		proto.TransactionState_CONFLICTED,
+	},
+	proto.TransactionState_DELEGATION_CANCELLED: {
+		proto.TransactionState_CONFIRMED_ON_BTC,
+		proto.TransactionState_DELEGATION_CANCELLED,
+	},
+}
+
+func addTestTransactionInState(
+	t *testing.T,
+	s *TrackedTransactionStore,
+	value int64,
+	state proto.TransactionState,
+) *chainhash.Hash {
+	txHash := addTestTransaction(t, s, value)
+
+	for _, next := range pathsToStates[state] {
+		require.NoError(t, stateSetters[next](s, txHash))
+	}
+
+	tx, err := s.GetTransaction(txHash)
+	require.NoError(t, err)
+	require.Equal(t, state, tx.State)
+
+	return txHash
+}
+
+func TestStateMachineDefinition(t *testing.T) {
+	definition := StateMachine()
+	require.Len(t, definition, len(proto.TransactionState_name))
+
+	for i, state := range definition {
+		require.Equal(t, proto.TransactionState(i), state.State)
+	}
+
+	require.False(t, definition[InitialTransactionState].Terminal())
+	require.True(t, definition[proto.TransactionState_SPENT_ON_BTC].Terminal())
+	require.True(t, definition[proto.TransactionState_MISSING_ON_BTC].Terminal())
+	require.True(t, definition[proto.TransactionState_CONFLICTED].Terminal())
+}
+
+func TestAllowedStateTransitions(t *testing.T) {
+	s, _ := makeTestStore(t)
+	value := int64(1000)
+
+	for _, definition := range StateMachine() {
+		for _, to := range definition.AllowedTransitions {
+			from := definition.State
+
+			t.Run(from.String()+"->"+to.String(), func(t *testing.T) {
+				value++
+				txHash := addTestTransactionInState(t, s, value, from)
+
+				require.NoError(t, stateSetters[to](s, txHash))
+
+				tx, err := s.GetTransaction(txHash)
+				require.NoError(t, err)
+				require.Equal(t, to, tx.State)
+			})
+		}
+	}
+}
+
+func TestInvalidStateTransitions(t *testing.T) {
+	tests := []struct {
+		name           string
+		from           proto.TransactionState
+		to             proto.TransactionState
+		alreadyInState bool
+	}{
+		{
+			name: "delegation sent before staking transaction confirmed",
+			from: proto.TransactionState_SENT_TO_BTC,
+			to:   proto.TransactionState_SENT_TO_BABYLON,
+		},
+		{
+			name: "unconfirmed staking transaction spent",
+			from: proto.TransactionState_SENT_TO_BTC,
+			to:   proto.TransactionState_SPENT_ON_BTC,
+		},
+		{
+			name: "unconfirmed staking transaction missing",
+			from: proto.TransactionState_SENT_TO_BTC,
+			to:   proto.TransactionState_MISSING_ON_BTC,
+		},
+		{
+			name: "delegation activated before sent to babylon",
+			from: proto.TransactionState_CONFIRMED_ON_BTC,
+			to:   proto.TransactionState_DELEGATION_ACTIVE,
+		},
+		{
+			name: "missing transaction sent to babylon",
+			from: proto.TransactionState_MISSING_ON_BTC,
+			to:   proto.TransactionState_SENT_TO_BABYLON,
+		},
+		{
+			name: "sent to babylon transaction missing",
+			from: proto.TransactionState_SENT_TO_BABYLON,
+			to:   proto.TransactionState_MISSING_ON_BTC,
+		},
+		{
+			name: "missing transaction spent",
+			from: proto.TransactionState_MISSING_ON_BTC,
+			to:   proto.TransactionState_SPENT_ON_BTC,
+		},
+		{
+			name: "active delegation expired",
+			from: proto.TransactionState_DELEGATION_ACTIVE,
+			to:   proto.TransactionState_DELEGATION_EXPIRED,
+		},
+		{
+			name: "expired delegation activated",
+			from: proto.TransactionState_DELEGATION_EXPIRED,
+			to:   proto.TransactionState_DELEGATION_ACTIVE,
+		},
+		{
+			name:           "active delegation timed out",
+			from:           proto.TransactionState_DELEGATION_ACTIVE,
+			to:             proto.TransactionState_UNBONDING_SIGNATURES_TIMEOUT,
+			alreadyInState: true,
+		},
+		{
+			name:           "timed out delegation sent to babylon again",
+			from:           proto.TransactionState_UNBONDING_SIGNATURES_TIMEOUT,
+			to:             proto.TransactionState_SENT_TO_BABYLON,
+			alreadyInState: true,
+		},
+		{
+			name:           "spent transaction unbonded",
+			from:           proto.TransactionState_SPENT_ON_BTC,
+			to:             proto.TransactionState_UNBONDING_CONFIRMED_ON_BTC,
+			alreadyInState: true,
+		},
+		{
+			name:           "spent transaction confirmed again",
+			from:           proto.TransactionState_SPENT_ON_BTC,
+			to:             proto.TransactionState_CONFIRMED_ON_BTC,
+			alreadyInState: true,
+		},
+		{
+			name:           "unbonded delegation activated",
+			from:           proto.TransactionState_UNBONDING_CONFIRMED_ON_BTC,
+			to:             proto.TransactionState_DELEGATION_ACTIVE,
+			alreadyInState: true,
+		},
+		{
+			name: "confirmed transaction conflicted",
+			from: proto.TransactionState_CONFIRMED_ON_BTC,
+			to:   proto.TransactionState_CONFLICTED,
+		},
+		{
+			name: "conflicted transaction confirmed",
+			from: proto.TransactionState_CONFLICTED,
+			to:   proto.TransactionState_CONFIRMED_ON_BTC,
+		},
+		{
+			name: "delegation sent to babylon cancelled",
+			from: proto.TransactionState_SENT_TO_BABYLON,
+			to:   proto.TransactionState_DELEGATION_CANCELLED,
+		},
+		{
+			name: "cancelled delegation sent to babylon",
+			from: proto.TransactionState_DELEGATION_CANCELLED,
+			to:   proto.TransactionState_SENT_TO_BABYLON,
+		},
+		{
+			name:           "duplicate confirmation",
+			from:           proto.TransactionState_CONFIRMED_ON_BTC,
+			to:             proto.TransactionState_CONFIRMED_ON_BTC,
+			alreadyInState: true,
+		},
+	}
+
+	s, _ := makeTestStore(t)
+	value := int64(1000)
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			require.False(t, IsTransitionAllowed(tc.from, tc.to))
+
+			value++
+			txHash := addTestTransactionInState(t, s, value, tc.from)
+
+			err := stateSetters[tc.to](s, txHash)
+
+			if tc.alreadyInState {
+				require.ErrorIs(t, err, ErrAlreadyInState)
+			} else {
+				var transitionErr *ErrInvalidStateTransition
+				require.ErrorAs(t, err, &transitionErr)
+				require.Equal(t, tc.from, transitionErr.From)
+				require.Equal(t, tc.to, transitionErr.To)
+				require.ErrorIs(t, err, ErrInvalidTransactionState)
+			}
+
+			tx, err := s.GetTransaction(txHash)
+			require.NoError(t, err)
+			require.Equal(t, tc.from, tx.State)
+		})
+	}
+}
+
+func TestExpiredDelegationIsWithdrawable(t *testing.T) {
+	s, _ := makeTestStore(t)
+
+	// staking transaction is confirmed at height 10 with staking time 100
+	txHash := addTestTransactionInState(t, s, 1000, proto.TransactionState_DELEGATION_EXPIRED)
+
+	q := DefaultStoredTransactionQuery()
+	resp, err := s.QueryStoredTransactions(q.WithdrawableTransactionsFilter(50))
+	require.NoError(t, err)
+	require.Empty(t, resp.Transactions)
+
+	q = DefaultStoredTransactionQuery()
+	resp, err = s.QueryStoredTransactions(q.WithdrawableTransactionsFilter(110))
+	require.NoError(t, err)
+	require.Len(t, resp.Transactions, 1)
+	require.Equal(t, *txHash, resp.Transactions[0].StakingTx.TxHash())
+}
+
+func TestCancelledDelegationIsWithdrawable(t *testing.T) {
+	s, _ := makeTestStore(t)
+
+	// staking transaction is confirmed at height 10 with staking time 100
+	txHash := addTestTransactionInState(t, s, 1000, proto.TransactionState_DELEGATION_CANCELLED)
+
+	q := DefaultStoredTransactionQuery()
+	resp, err := s.QueryStoredTransactions(q.WithdrawableTransactionsFilter(50))
+	require.NoError(t, err)
+	require.Empty(t, resp.Transactions)
+
+	q = DefaultStoredTransactionQuery()
+	resp, err = s.QueryStoredTransactions(q.WithdrawableTransactionsFilter(110))
+	require.NoError(t, err)
+	require.Len(t, resp.Transactions, 1)
+	require.Equal(t, *txHash, resp.Transactions[0].StakingTx.TxHash())
+
+	require.NoError(t, stateSetters[proto.TransactionState_SPENT_ON_BTC](s, txHash))
+}
+
+func TestConflictedTransactionStoresConflictingTx(t *testing.T) {
+	s, _ := makeTestStore(t)
+
+	txHash := addTestTransactionInState(t, s, 1000, proto.TransactionState_CONFLICTED)
+
+	tx, err := s.GetTransaction(txHash)
+	require.NoError(t, err)
+	require.Equal(t, chainhash.Hash{1}, *tx.ConflictingTxHash)
+
+	issues, err := s.CheckIntegrity()
+	require.NoError(t, err)
+	require.Empty(t, issues)
+}
+
+func TestMissingTransactionFoundOnBtc(t *testing.T) {
+	s, _ := makeTestStore(t)
+
+	txHash := addTestTransactionInState(t, s, 1000, proto.TransactionState_MISSING_ON_BTC)
+
+	// transaction was reorged into other block
+	blockHash := chainhash.Hash{2}
+	require.NoError(t, s.SetTxFoundOnBtc(txHash, &blockHash, 12, time.Time{}))
+
+	tx, err := s.GetTransaction(txHash)
+	require.NoError(t, err)
+	require.Equal(t, proto.TransactionState_CONFIRMED_ON_BTC, tx.State)
+	require.Equal(t, blockHash, tx.StakingTxConfirmationInfo.BlockHash)
+	require.Equal(t, uint32(12), tx.StakingTxConfirmationInfo.Height)
+
+	// only missing transaction can be found again
+	var transitionErr *ErrInvalidStateTransition
+	require.ErrorAs(t, s.SetTxFoundOnBtc(txHash, &blockHash, 12, time.Time{}), &transitionErr)
+
+	// found transaction is sent to babylon as any other confirmed transaction
+	require.NoError(t, stateSetters[proto.TransactionState_SENT_TO_BABYLON](s, txHash))
+}
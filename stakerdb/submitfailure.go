@@ -0,0 +1,78 @@
+package stakerdb
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/babylonchain/btc-staker/proto"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+)
+
+// BabylonSubmitFailure is the last rejection of delegation by babylon, with raw
+// ABCI error returned by babylon node
+type BabylonSubmitFailure struct {
+	// Operation is babylon operation which failed e.g. send_delegation
+	Operation string
+	Codespace string
+	Code      uint32
+	RawLog    string
+	// Executed is true if babylon transaction was included in block and failed
+	// execution, false if it was rejected before entering mempool
+	Executed bool
+	// hash and height of failed babylon transaction, empty if not known
+	BabylonTxHash string
+	BabylonHeight int64
+	FailedAt      time.Time
+}
+
+func babylonSubmitFailureToProto(f *BabylonSubmitFailure, failedAt time.Time) *proto.BabylonSubmitFailure {
+	return &proto.BabylonSubmitFailure{
+		Operation:     f.Operation,
+		Codespace:     f.Codespace,
+		Code:          f.Code,
+		RawLog:        f.RawLog,
+		Executed:      f.Executed,
+		BabylonTxHash: f.BabylonTxHash,
+		BabylonHeight: f.BabylonHeight,
+		FailedAt:      failedAt.Unix(),
+	}
+}
+
+func protoBabylonSubmitFailureToBabylonSubmitFailure(f *proto.BabylonSubmitFailure) *BabylonSubmitFailure {
+	if f == nil {
+		return nil
+	}
+
+	return &BabylonSubmitFailure{
+		Operation:     f.Operation,
+		Codespace:     f.Codespace,
+		Code:          f.Code,
+		RawLog:        f.RawLog,
+		Executed:      f.Executed,
+		BabylonTxHash: f.BabylonTxHash,
+		BabylonHeight: f.BabylonHeight,
+		FailedAt:      time.Unix(f.FailedAt, 0),
+	}
+}
+
+// SetTxBabylonSubmitFailure records rejection of message sent to babylon for
+// staking transaction, overwriting previously recorded one. FailedAt of the
+// failure is set to the current time. Failure is cleared once delegation is
+// accepted by babylon.
+func (c *TrackedTransactionStore) SetTxBabylonSubmitFailure(
+	stakingTxHash *chainhash.Hash,
+	failure *BabylonSubmitFailure,
+) error {
+	if failure == nil {
+		return fmt.Errorf("babylon submit failure must not be nil")
+	}
+
+	failedAt := c.now()
+
+	setFailure := func(tx *proto.TrackedTransaction) error {
+		tx.BabylonSubmitFailure = babylonSubmitFailureToProto(failure, failedAt)
+		return nil
+	}
+
+	return c.setTxState(stakingTxHash, setFailure)
+}
@@ -0,0 +1,49 @@
+package stakerdb
+
+import (
+	"testing"
+	"time"
+
+	"github.com/babylonchain/btc-staker/proto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetTxBabylonSubmitFailure(t *testing.T) {
+	s, _ := makeTestStore(t)
+
+	failedAt := time.Unix(1700000000, 0)
+	s.now = func() time.Time { return failedAt }
+
+	txHash := addTestTransactionInState(t, s, 1000, proto.TransactionState_CONFIRMED_ON_BTC)
+
+	storedTx, err := s.GetTransaction(txHash)
+	require.NoError(t, err)
+	require.Nil(t, storedTx.BabylonSubmitFailure)
+
+	failure := &BabylonSubmitFailure{
+		Operation:     "send_delegation",
+		Codespace:     "btcstaking",
+		Code:          1105,
+		RawLog:        "the staking tx is already used",
+		Executed:      true,
+		BabylonTxHash: "ABCD",
+		BabylonHeight: 120,
+	}
+	require.NoError(t, s.SetTxBabylonSubmitFailure(txHash, failure))
+
+	storedTx, err = s.GetTransaction(txHash)
+	require.NoError(t, err)
+
+	expected := *failure
+	expected.FailedAt = failedAt
+	require.Equal(t, &expected, storedTx.BabylonSubmitFailure)
+
+	// failure is cleared once delegation is accepted by babylon
+	require.NoError(t, stateSetters[proto.TransactionState_SENT_TO_BABYLON](s, txHash))
+
+	storedTx, err = s.GetTransaction(txHash)
+	require.NoError(t, err)
+	require.Nil(t, storedTx.BabylonSubmitFailure)
+
+	require.Error(t, s.SetTxBabylonSubmitFailure(txHash, nil))
+}
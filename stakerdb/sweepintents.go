@@ -0,0 +1,208 @@
+package stakerdb
+
+import (
+	"github.com/babylonchain/btc-staker/proto"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	pm "google.golang.org/protobuf/proto"
+
+	"github.com/lightningnetwork/lnd/kvdb"
+)
+
+var (
+	// mapping stakingTxHash -> proto.SweepIntent
+	// It holds staking transactions which unbonded funds should be spent
+	// automatically once unbonding timelock expires
+	sweepIntentsBucketName = []byte("sweepIntents")
+)
+
+type SweepIntent struct {
+	StakingTxHash      chainhash.Hash
+	DestinationAddress string
+	// only set after sweep transaction was sent
+	SweepTxHash *chainhash.Hash
+}
+
+func sweepIntentToProto(i *SweepIntent) *proto.SweepIntent {
+	var sweepTxHash []byte
+	if i.SweepTxHash != nil {
+		sweepTxHash = i.SweepTxHash.CloneBytes()
+	}
+
+	return &proto.SweepIntent{
+		DestinationAddress: i.DestinationAddress,
+		SweepTxHash:        sweepTxHash,
+	}
+}
+
+func protoSweepIntentToSweepIntent(stakingTxHashBytes []byte, i *proto.SweepIntent) (*SweepIntent, error) {
+	stakingTxHash, err := chainhash.NewHash(stakingTxHashBytes)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var sweepTxHash *chainhash.Hash
+	if len(i.SweepTxHash) > 0 {
+		hash, err := chainhash.NewHash(i.SweepTxHash)
+
+		if err != nil {
+			return nil, err
+		}
+
+		sweepTxHash = hash
+	}
+
+	return &SweepIntent{
+		StakingTxHash:      *stakingTxHash,
+		DestinationAddress: i.DestinationAddress,
+		SweepTxHash:        sweepTxHash,
+	}, nil
+}
+
+// SaveSweepIntent stores intent to sweep unbonded funds of the staking transaction,
+// overwriting previous intent for the same transaction
+func (c *TrackedTransactionStore) SaveSweepIntent(intent *SweepIntent) error {
+	intentBytes, err := pm.Marshal(sweepIntentToProto(intent))
+
+	if err != nil {
+		return err
+	}
+
+	return kvdb.Batch(c.db, func(tx kvdb.RwTx) error {
+		intentsBucket := tx.ReadWriteBucket(sweepIntentsBucketName)
+
+		if intentsBucket == nil {
+			return ErrCorruptedTransactionsDb
+		}
+
+		return intentsBucket.Put(intent.StakingTxHash.CloneBytes(), intentBytes)
+	})
+}
+
+// SetSweepTxHash records that sweep transaction with given hash was sent for
+// the staking transaction
+func (c *TrackedTransactionStore) SetSweepTxHash(stakingTxHash, sweepTxHash *chainhash.Hash) error {
+	return kvdb.Batch(c.db, func(tx kvdb.RwTx) error {
+		intentsBucket := tx.ReadWriteBucket(sweepIntentsBucketName)
+
+		if intentsBucket == nil {
+			return ErrCorruptedTransactionsDb
+		}
+
+		maybeIntent := intentsBucket.Get(stakingTxHash.CloneBytes())
+
+		if maybeIntent == nil {
+			return ErrSweepIntentNotFound
+		}
+
+		var intentProto proto.SweepIntent
+
+		if err := pm.Unmarshal(maybeIntent, &intentProto); err != nil {
+			return ErrCorruptedTransactionsDb
+		}
+
+		intentProto.SweepTxHash = sweepTxHash.CloneBytes()
+
+		intentBytes, err := pm.Marshal(&intentProto)
+
+		if err != nil {
+			return err
+		}
+
+		return intentsBucket.Put(stakingTxHash.CloneBytes(), intentBytes)
+	})
+}
+
+// DeleteSweepIntent removes intent to sweep unbonded funds of the staking
+// transaction. Removing not existing intent is not an error.
+func (c *TrackedTransactionStore) DeleteSweepIntent(stakingTxHash *chainhash.Hash) error {
+	return kvdb.Batch(c.db, func(tx kvdb.RwTx) error {
+		intentsBucket := tx.ReadWriteBucket(sweepIntentsBucketName)
+
+		if intentsBucket == nil {
+			return ErrCorruptedTransactionsDb
+		}
+
+		return intentsBucket.Delete(stakingTxHash.CloneBytes())
+	})
+}
+
+// GetSweepIntent returns intent to sweep unbonded funds of the staking transaction
+func (c *TrackedTransactionStore) GetSweepIntent(stakingTxHash *chainhash.Hash) (*SweepIntent, error) {
+	var intent *SweepIntent
+
+	err := c.db.View(func(tx kvdb.RTx) error {
+		intentsBucket := tx.ReadBucket(sweepIntentsBucketName)
+
+		if intentsBucket == nil {
+			return ErrCorruptedTransactionsDb
+		}
+
+		maybeIntent := intentsBucket.Get(stakingTxHash.CloneBytes())
+
+		if maybeIntent == nil {
+			return ErrSweepIntentNotFound
+		}
+
+		var intentProto proto.SweepIntent
+
+		if err := pm.Unmarshal(maybeIntent, &intentProto); err != nil {
+			return ErrCorruptedTransactionsDb
+		}
+
+		intentFromDb, err := protoSweepIntentToSweepIntent(stakingTxHash.CloneBytes(), &intentProto)
+
+		if err != nil {
+			return err
+		}
+
+		intent = intentFromDb
+
+		return nil
+	}, func() {})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return intent, nil
+}
+
+// GetSweepIntents returns all stored intents to sweep unbonded funds
+func (c *TrackedTransactionStore) GetSweepIntents() ([]*SweepIntent, error) {
+	var intents []*SweepIntent
+
+	err := c.db.View(func(tx kvdb.RTx) error {
+		intentsBucket := tx.ReadBucket(sweepIntentsBucketName)
+
+		if intentsBucket == nil {
+			return ErrCorruptedTransactionsDb
+		}
+
+		return intentsBucket.ForEach(func(k, v []byte) error {
+			var intentProto proto.SweepIntent
+
+			if err := pm.Unmarshal(v, &intentProto); err != nil {
+				return ErrCorruptedTransactionsDb
+			}
+
+			intent, err := protoSweepIntentToSweepIntent(k, &intentProto)
+
+			if err != nil {
+				return err
+			}
+
+			intents = append(intents, intent)
+
+			return nil
+		})
+	}, func() {
+		intents = nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return intents, nil
+}
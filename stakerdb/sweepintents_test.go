@@ -0,0 +1,46 @@
+package stakerdb
+
+import (
+	"testing"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSweepIntentLifecycle(t *testing.T) {
+	s, _ := makeTestStore(t)
+
+	stakingTxHash := chainhash.HashH([]byte("staking"))
+	sweepTxHash := chainhash.HashH([]byte("sweep"))
+
+	err := s.SetSweepTxHash(&stakingTxHash, &sweepTxHash)
+	require.ErrorIs(t, err, ErrSweepIntentNotFound)
+
+	require.NoError(t, s.SaveSweepIntent(&SweepIntent{
+		StakingTxHash:      stakingTxHash,
+		DestinationAddress: "address",
+	}))
+
+	intents, err := s.GetSweepIntents()
+	require.NoError(t, err)
+	require.Len(t, intents, 1)
+	require.Equal(t, stakingTxHash, intents[0].StakingTxHash)
+	require.Equal(t, "address", intents[0].DestinationAddress)
+	require.Nil(t, intents[0].SweepTxHash)
+
+	require.NoError(t, s.SetSweepTxHash(&stakingTxHash, &sweepTxHash))
+
+	intent, err := s.GetSweepIntent(&stakingTxHash)
+	require.NoError(t, err)
+	require.Equal(t, "address", intent.DestinationAddress)
+	require.Equal(t, &sweepTxHash, intent.SweepTxHash)
+
+	require.NoError(t, s.DeleteSweepIntent(&stakingTxHash))
+
+	_, err = s.GetSweepIntent(&stakingTxHash)
+	require.ErrorIs(t, err, ErrSweepIntentNotFound)
+
+	intents, err = s.GetSweepIntents()
+	require.NoError(t, err)
+	require.Empty(t, intents)
+}
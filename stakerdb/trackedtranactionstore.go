@@ -3,8 +3,10 @@ package stakerdb
 import (
 	"bytes"
 	"encoding/binary"
+	"errors"
 	"fmt"
 	"math"
+	"time"
 
 	"github.com/babylonchain/btc-staker/proto"
 	"github.com/babylonchain/btc-staker/utils"
@@ -32,6 +34,12 @@ var (
 	// It holds additional data for staking transaction in watch only mode
 	watchedTxDataBucketName = []byte("watched")
 
+	// mapping txHash -> raw bytes of proto.TrackedTransaction
+	// It holds transactions which could not be decoded. They are moved out of
+	// transactions bucket so that they do not stop processing of other
+	// transactions, and kept for manual inspection.
+	corruptTransactionsBucketName = []byte("corrupt")
+
 	// key for next transaction
 	numTxKey = []byte("ntk")
 )
@@ -40,6 +48,8 @@ type StoredTransactionScanFn func(tx *StoredTransaction) error
 
 type TrackedTransactionStore struct {
 	db kvdb.Backend
+	// source of timestamps of state changes
+	now func() time.Time
 }
 
 type ProofOfPossession struct {
@@ -112,6 +122,8 @@ func covenantSigFromProto(c *proto.CovenantSig) (*PubKeySigPair, error) {
 type BtcConfirmationInfo struct {
 	Height    uint32
 	BlockHash chainhash.Hash
+	// Time from header of the inclusion block, zero if it is not known
+	BlockTime time.Time
 }
 
 type StoredTransaction struct {
@@ -128,13 +140,88 @@ type StoredTransaction struct {
 	State           proto.TransactionState
 	Watched         bool
 	UnbondingTxData *UnbondingStoreData
+	// Version of staking params snapshot under which transaction was created,
+	// 0 if transaction was created before versions were tracked
+	ParamsVersion uint32
+	TxLabels      []TxLabel
+	// Change output of staking transaction, nil for watched transactions and
+	// transactions without change
+	ChangeOutput *ChangeOutput
+	// Hash of babylon transaction which submitted the delegation, empty if
+	// it is not known
+	BabylonTxHash string
+	// Memo of babylon transaction which submits the delegation, requested memo
+	// until delegation is sent
+	BabylonMemo string
+	// Time of the last change of state, zero if it is not known
+	StateChangedAt time.Time
+	// States through which transaction went, in order. Transitions made before
+	// history was tracked are missing.
+	StateHistory []StateTransition
+	// Transaction sent by staker to spend staked funds, nil if it is not known
+	SpendTxData *SpendTxData
+	// Label assigned by user to organize delegations, empty if not labeled
+	Label string
+	// RequiredDepth is depth on btc chain which staking transaction must reach
+	// before delegation is sent to babylon, 0 if it is not known
+	RequiredDepth uint32
+	// Proof of inclusion of staking transaction in the block which confirmed it,
+	// nil if it was not recorded
+	StakingTxInclusionProof *InclusionProof
+	// CorruptionReason is reason why record was flagged as corrupted, empty if
+	// it is not corrupted
+	CorruptionReason string
+	// AutoRenew is true if staking output is automatically spent into new
+	// staking output when its timelock expires
+	AutoRenew bool
+	// Staking transaction which renewed this one, nil if it was not renewed
+	RenewalTxHash *chainhash.Hash
+	// Staking transaction renewed by this one, nil if it is not a renewal
+	RenewedFromTxHash *chainhash.Hash
+	// Reason why automatic renewal was skipped, empty if it was not skipped
+	AutoRenewSkipReason string
+	// Fee estimates used to build staking, unbonding and spend transaction, nil
+	// if they were not recorded
+	StakingFeeEstimate   *FeeEstimate
+	UnbondingFeeEstimate *FeeEstimate
+	SpendFeeEstimate     *FeeEstimate
+	// UnbondingWaitAborted is true if operator aborted waiting for covenant
+	// unbonding signatures
+	UnbondingWaitAborted bool
+	// Transaction which spent input of staking transaction before it was
+	// confirmed, nil if transaction is not conflicted
+	ConflictingTxHash *chainhash.Hash
+	// best btc block height when staker sent staking transaction, 0 if not known
+	BroadcastHeight uint32
+	// Last rejection of delegation by babylon, nil if delegation was not
+	// rejected or was accepted afterwards
+	BabylonSubmitFailure *BabylonSubmitFailure
+	// Archived is true if transaction was moved to archive. Archived transactions
+	// are immutable and are not scanned with tracked transactions.
+	Archived bool
+}
+
+type ChangeOutput struct {
+	OutputIdx uint32
+	Amount    btcutil.Amount
+	Address   string
+}
+
+// TxLabel is a label of transaction created by staker, which identifies the
+// purpose of transaction
+type TxLabel struct {
+	TxHash chainhash.Hash
+	Label  string
 }
 
 // StakingTxConfirmedOnBtc returns true only if staking transaction was sent and confirmed on bitcoin
 func (t *StoredTransaction) StakingTxConfirmedOnBtc() bool {
 	return t.State == proto.TransactionState_SENT_TO_BABYLON ||
 		t.State == proto.TransactionState_DELEGATION_ACTIVE ||
-		t.State == proto.TransactionState_CONFIRMED_ON_BTC
+		t.State == proto.TransactionState_CONFIRMED_ON_BTC ||
+		t.State == proto.TransactionState_DELEGATION_EXPIRED ||
+		t.State == proto.TransactionState_UNBONDING_SIGNATURES_TIMEOUT ||
+		t.State == proto.TransactionState_DELEGATION_CANCELLED
 }
 
 // IsUnbonded returns true only if unbonding transaction was sent and confirmed on bitcoin
@@ -142,6 +229,21 @@ func (t *StoredTransaction) IsUnbonded() bool {
 	return t.State == proto.TransactionState_UNBONDING_CONFIRMED_ON_BTC
 }
 
+// WithdrawableHeight returns height of the first block which can include
+// transaction withdrawing staked funds. Returns false if funds are not locked in
+// confirmed staking or unbonding output.
+func (t *StoredTransaction) WithdrawableHeight() (uint32, bool) {
+	if t.StakingTxConfirmedOnBtc() && t.StakingTxConfirmationInfo != nil {
+		return t.StakingTxConfirmationInfo.Height + uint32(t.StakingTime), true
+	}
+
+	if t.IsUnbonded() && t.UnbondingTxData != nil && t.UnbondingTxData.UnbondingTxConfirmationInfo != nil {
+		return t.UnbondingTxData.UnbondingTxConfirmationInfo.Height + uint32(t.UnbondingTxData.UnbondingTime), true
+	}
+
+	return 0, false
+}
+
 type WatchedTransactionData struct {
 	SlashingTx          *wire.MsgTx
 	SlashingTxSig       *schnorr.Signature
@@ -152,23 +254,60 @@ type WatchedTransactionData struct {
 	SlashingUnbondingTx    *wire.MsgTx
 	SlashingUnbondingTxSig *schnorr.Signature
 	UnbondingTime          uint16
+	// Index of unbonding output in UnbondingTx
+	UnbondingOutputIndex uint32
+}
+
+// UnbondingOutput returns output of watched unbonding transaction which locks
+// unbonded funds
+func (d *WatchedTransactionData) UnbondingOutput() *wire.TxOut {
+	return d.UnbondingTx.TxOut[d.UnbondingOutputIndex]
 }
 
 type UnbondingStoreData struct {
-	UnbondingTx                 *wire.MsgTx
+	UnbondingTx *wire.MsgTx
+	// Index of unbonding output in UnbondingTx
+	UnbondingOutputIndex        uint32
 	UnbondingTime               uint16
 	CovenantSignatures          []PubKeySigPair
 	UnbondingTxConfirmationInfo *BtcConfirmationInfo
+	// Time when unbonding transaction was sent to btc, zero if it is not known
+	UnbondingTxSentAt time.Time
+}
+
+// UnbondingOutput returns output of unbonding transaction which locks unbonded
+// funds
+func (d *UnbondingStoreData) UnbondingOutput() *wire.TxOut {
+	return d.UnbondingTx.TxOut[d.UnbondingOutputIndex]
+}
+
+// checkUnbondingOutputIndex checks that unbonding transaction has output with
+// given index
+func checkUnbondingOutputIndex(unbondingTx *wire.MsgTx, unbondingOutputIdx uint32) error {
+	if int(unbondingOutputIdx) >= len(unbondingTx.TxOut) {
+		return fmt.Errorf(
+			"unbonding output index %d out of range, unbonding transaction has %d outputs",
+			unbondingOutputIdx,
+			len(unbondingTx.TxOut),
+		)
+	}
+
+	return nil
 }
 
 func newInitialUnbondingTxData(
 	unbondingTx *wire.MsgTx,
+	unbondingOutputIdx uint32,
 	unbondingTime uint16,
 ) (*proto.UnbondingTxData, error) {
 	if unbondingTx == nil {
 		return nil, fmt.Errorf("cannot create unbonding tx data without unbonding tx")
 	}
 
+	if err := checkUnbondingOutputIndex(unbondingTx, unbondingOutputIdx); err != nil {
+		return nil, fmt.Errorf("cannot create unbonding tx data: %w", err)
+	}
+
 	serializedTx, err := utils.SerializeBtcTransaction(unbondingTx)
 
 	if err != nil {
@@ -180,6 +319,7 @@ func newInitialUnbondingTxData(
 		UnbondingTime:                  uint32(unbondingTime),
 		CovenantSignatures:             make([]*proto.CovenantSig, 0),
 		UnbondingTxBtcConfirmationInfo: nil,
+		UnbondingOutputIdx:             unbondingOutputIdx,
 	}
 
 	return unbondingData, nil
@@ -195,6 +335,15 @@ type StoredTransactionQuery struct {
 
 	Reversed bool
 
+	// Label returns only transactions with given label, if it is not empty
+	Label string
+
+	// State returns only transactions in given state, if it is not nil
+	State *proto.TransactionState
+
+	// IncludeArchived returns also archived transactions
+	IncludeArchived bool
+
 	withdrawableTransactionsFilter *WithdrawableTransactionsFilter
 }
 
@@ -224,7 +373,10 @@ type StoredTransactionQueryResult struct {
 func NewTrackedTransactionStore(db kvdb.Backend) (*TrackedTransactionStore,
 	error) {
 
-	store := &TrackedTransactionStore{db}
+	store := &TrackedTransactionStore{
+		db:  db,
+		now: time.Now,
+	}
 	if err := store.initBuckets(); err != nil {
 		return nil, err
 	}
@@ -249,7 +401,81 @@ func (c *TrackedTransactionStore) initBuckets() error {
 			return err
 		}
 
-		return nil
+		_, err = tx.CreateTopLevelBucket(reconciliationBucketName)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.CreateTopLevelBucket(stakingRequestsBucketName)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.CreateTopLevelBucket(stakingParamsBucketName)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.CreateTopLevelBucket(auditLogBucketName)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.CreateTopLevelBucket(corruptTransactionsBucketName)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.CreateTopLevelBucket(sweepIntentsBucketName)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.CreateTopLevelBucket(settingsBucketName)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.CreateTopLevelBucket(spendWhitelistBucketName)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.CreateTopLevelBucket(labelIndexBucketName)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.CreateTopLevelBucket(eventIntentsBucketName)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.CreateTopLevelBucket(popCacheBucketName)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.CreateTopLevelBucket(archivedTransactionsBucketName)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.CreateTopLevelBucket(archivedStateCountsBucketName)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.CreateTopLevelBucket(notesBucketName)
+		if err != nil {
+			return err
+		}
+
+		if err := initStakingRequestsTimeIndex(tx); err != nil {
+			return err
+		}
+
+		return initStateCounts(tx)
 	})
 }
 
@@ -264,9 +490,16 @@ func protoBtcConfirmationInfoToBtcConfirmationInfo(ci *proto.BTCConfirmationInfo
 		return nil, err
 	}
 
+	var blockTime time.Time
+
+	if ci.BlockTimestamp != 0 {
+		blockTime = time.Unix(ci.BlockTimestamp, 0)
+	}
+
 	return &BtcConfirmationInfo{
 		Height:    ci.BlockHeight,
 		BlockHash: *hash,
+		BlockTime: blockTime,
 	}, nil
 
 }
@@ -280,6 +513,10 @@ func protoUnbondingDataToUnbondingStoreData(ud *proto.UnbondingTxData) (*Unbondi
 		return nil, err
 	}
 
+	if err := checkUnbondingOutputIndex(&unbondingTx, ud.UnbondingOutputIdx); err != nil {
+		return nil, err
+	}
+
 	if ud.UnbondingTime > math.MaxUint16 {
 		return nil, fmt.Errorf("unbonding time is too large. Max value is %d", math.MaxUint16)
 	}
@@ -302,11 +539,19 @@ func protoUnbondingDataToUnbondingStoreData(ud *proto.UnbondingTxData) (*Unbondi
 		return nil, err
 	}
 
+	var unbondingTxSentAt time.Time
+
+	if ud.UnbondingTxSentAt != 0 {
+		unbondingTxSentAt = time.Unix(ud.UnbondingTxSentAt, 0)
+	}
+
 	return &UnbondingStoreData{
 		UnbondingTx:                 &unbondingTx,
+		UnbondingOutputIndex:        ud.UnbondingOutputIdx,
 		UnbondingTime:               uint16(ud.UnbondingTime),
 		CovenantSignatures:          sigs,
 		UnbondingTxConfirmationInfo: unbondingTxConfirmationInfo,
+		UnbondingTxSentAt:           unbondingTxSentAt,
 	}, nil
 }
 
@@ -318,6 +563,14 @@ func protoTxToStoredTransaction(ttx *proto.TrackedTransaction) (*StoredTransacti
 		return nil, err
 	}
 
+	if int(ttx.StakingOutputIdx) >= len(stakingTx.TxOut) {
+		return nil, fmt.Errorf(
+			"staking output index %d out of range, staking transaction has %d outputs",
+			ttx.StakingOutputIdx,
+			len(stakingTx.TxOut),
+		)
+	}
+
 	var utd *UnbondingStoreData = nil
 
 	if ttx.UnbondingTxData != nil {
@@ -350,6 +603,67 @@ func protoTxToStoredTransaction(ttx *proto.TrackedTransaction) (*StoredTransacti
 		}
 	}
 
+	txLabels := make([]TxLabel, len(ttx.TxLabels))
+
+	for i, l := range ttx.TxLabels {
+		txHash, err := chainhash.NewHash(l.TxHash)
+
+		if err != nil {
+			return nil, err
+		}
+
+		txLabels[i] = TxLabel{
+			TxHash: *txHash,
+			Label:  l.Label,
+		}
+	}
+
+	var stateChangedAt time.Time
+
+	if ttx.StateChangedAt != 0 {
+		stateChangedAt = time.Unix(ttx.StateChangedAt, 0)
+	}
+
+	spendTxData, err := protoSpendTxDataToSpendTxData(ttx.SpendTxData)
+
+	if err != nil {
+		return nil, err
+	}
+
+	inclusionProof, err := protoInclusionProofToInclusionProof(ttx.StakingTxInclusionProof)
+
+	if err != nil {
+		return nil, err
+	}
+
+	renewalTxHash, err := protoOptionalHash(ttx.RenewalTxHash)
+
+	if err != nil {
+		return nil, err
+	}
+
+	renewedFromTxHash, err := protoOptionalHash(ttx.RenewedFromTxHash)
+
+	if err != nil {
+		return nil, err
+	}
+
+	conflictingTxHash, err := protoOptionalHash(ttx.ConflictingTxHash)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var changeOutput *ChangeOutput
+
+	if ttx.ChangeOutput != nil {
+		changeOutput = &ChangeOutput{
+			OutputIdx: ttx.ChangeOutput.OutputIdx,
+			Amount:    btcutil.Amount(ttx.ChangeOutput.Amount),
+			Address:   ttx.ChangeOutput.Address,
+		}
+	}
+
 	return &StoredTransaction{
 		StoredTransactionIdx:      ttx.TrackedTransactionIdx,
 		StakingTx:                 &stakingTx,
@@ -366,9 +680,53 @@ func protoTxToStoredTransaction(ttx *proto.TrackedTransaction) (*StoredTransacti
 		State:           ttx.State,
 		Watched:         ttx.Watched,
 		UnbondingTxData: utd,
+		ParamsVersion:   ttx.ParamsVersion,
+		TxLabels:        txLabels,
+		ChangeOutput:    changeOutput,
+		BabylonTxHash:   ttx.BabylonTxHash,
+		BabylonMemo:     ttx.BabylonMemo,
+		StateChangedAt:  stateChangedAt,
+		StateHistory:    protoStateHistoryToStateHistory(ttx.StateHistory),
+		SpendTxData:     spendTxData,
+		Label:           ttx.Label,
+		RequiredDepth:   ttx.RequiredDepth,
+
+		StakingTxInclusionProof: inclusionProof,
+		CorruptionReason:        ttx.CorruptionReason,
+		AutoRenew:               ttx.AutoRenew,
+		RenewalTxHash:           renewalTxHash,
+		RenewedFromTxHash:       renewedFromTxHash,
+		AutoRenewSkipReason:     ttx.AutoRenewSkipReason,
+		StakingFeeEstimate:      protoFeeEstimateToFeeEstimate(ttx.StakingFeeEstimate),
+		UnbondingFeeEstimate:    protoFeeEstimateToFeeEstimate(ttx.UnbondingFeeEstimate),
+		SpendFeeEstimate:        protoFeeEstimateToFeeEstimate(ttx.SpendFeeEstimate),
+		UnbondingWaitAborted:    ttx.UnbondingWaitAborted,
+		ConflictingTxHash:       conflictingTxHash,
+		BroadcastHeight:         ttx.BroadcastHeight,
+		BabylonSubmitFailure:    protoBabylonSubmitFailureToBabylonSubmitFailure(ttx.BabylonSubmitFailure),
 	}, nil
 }
 
+// decodeStoredTransaction decodes transaction stored in transactions bucket. Any
+// failure is reported as ErrCorruptedTransaction. Fields unknown to this version
+// of staker are kept by unmarshalling, so that they are not lost when record is
+// updated.
+func decodeStoredTransaction(txBytes []byte) (*proto.TrackedTransaction, *StoredTransaction, error) {
+	var storedTxProto proto.TrackedTransaction
+
+	if err := pm.Unmarshal(txBytes, &storedTxProto); err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrCorruptedTransaction, err)
+	}
+
+	storedTx, err := protoTxToStoredTransaction(&storedTxProto)
+
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrCorruptedTransaction, err)
+	}
+
+	return &storedTxProto, storedTx, nil
+}
+
 func protoWatchedDataToWatchedTransactionData(wd *proto.WatchedTxData) (*WatchedTransactionData, error) {
 	var slashingTx wire.MsgTx
 	err := slashingTx.Deserialize(bytes.NewReader(wd.SlashingTransaction))
@@ -398,6 +756,10 @@ func protoWatchedDataToWatchedTransactionData(wd *proto.WatchedTxData) (*Watched
 		return nil, err
 	}
 
+	if err := checkUnbondingOutputIndex(&unbondingTx, wd.UnbondingOutputIdx); err != nil {
+		return nil, err
+	}
+
 	var slashingUnbondingTx wire.MsgTx
 	err = slashingUnbondingTx.Deserialize(bytes.NewReader(wd.SlashingUnbondingTransaction))
 	if err != nil {
@@ -427,6 +789,7 @@ func protoWatchedDataToWatchedTransactionData(wd *proto.WatchedTxData) (*Watched
 		SlashingUnbondingTx:    &slashingUnbondingTx,
 		SlashingUnbondingTxSig: slashUnbondingTxSig,
 		UnbondingTime:          unbondingTime,
+		UnbondingOutputIndex:   wd.UnbondingOutputIdx,
 	}, nil
 }
 
@@ -455,11 +818,13 @@ func getNumTx(txIdxBucket walletdb.ReadBucket) uint64 {
 	return nextTxKey(txIdxBucket) - 1
 }
 
-// getTxByHash retruns transaction and transaction key if transaction with given hash exsits
+// getTxByHash retruns transaction and transaction key if transaction with given hash exsits.
+// ErrTransactionArchived is returned if transaction was moved to archive bucket.
 func getTxByHash(
 	txHashBytes []byte,
 	txIndexBucket walletdb.ReadBucket,
-	txBucket walletdb.ReadBucket) ([]byte, []byte, error) {
+	txBucket walletdb.ReadBucket,
+	archiveBucket walletdb.ReadBucket) ([]byte, []byte, error) {
 	txKey := txIndexBucket.Get(txHashBytes)
 
 	if txKey == nil {
@@ -468,6 +833,10 @@ func getTxByHash(
 
 	maybeTx := txBucket.Get(txKey)
 
+	if maybeTx == nil && archiveBucket.Get(txKey) != nil {
+		return nil, txKey, ErrTransactionArchived
+	}
+
 	if maybeTx == nil {
 		// if we have index, but do not have transaction, it means something weird happened
 		// and we have corrupted db
@@ -512,6 +881,14 @@ func saveTrackedTransaction(
 		return err
 	}
 
+	if err := changeStateCount(rwTx, tx.State, 1); err != nil {
+		return err
+	}
+
+	if err := addLabelIndex(rwTx, tx.Label, nextTxKeyBytes); err != nil {
+		return err
+	}
+
 	if watchedTxData != nil {
 		watchedTxBucket := rwTx.ReadWriteBucket(watchedTxDataBucketName)
 		if watchedTxBucket == nil {
@@ -540,6 +917,21 @@ func (c *TrackedTransactionStore) addTransactionInternal(
 	tt *proto.TrackedTransaction,
 	wd *proto.WatchedTxData,
 ) error {
+	// never store transaction which could not be read back
+	if _, err := protoTxToStoredTransaction(tt); err != nil {
+		return fmt.Errorf("invalid transaction: %w", err)
+	}
+
+	if err := ValidateLabel(tt.Label); err != nil {
+		return err
+	}
+
+	tt.StateChangedAt = c.now().Unix()
+	tt.StateHistory = append(tt.StateHistory, &proto.StateTransition{
+		State:     tt.State,
+		ChangedAt: tt.StateChangedAt,
+	})
+
 	return kvdb.Batch(c.db, func(tx kvdb.RwTx) error {
 		transactionsBucketIdxBucket := tx.ReadWriteBucket(transactionIndexName)
 
@@ -562,6 +954,26 @@ func (c *TrackedTransactionStore) addTransactionInternal(
 	})
 }
 
+// AddTransactionOpts holds optional data of staking transaction added by
+// AddTransaction. Zero value means none of the data is known.
+type AddTransactionOpts struct {
+	// version of babylon staking params under which transaction was created
+	ParamsVersion uint32
+	// change output created by staker, nil if transaction has no change
+	ChangeOutput *ChangeOutput
+	// memo requested for babylon transaction which submits the delegation
+	BabylonMemo string
+	Label       string
+	// depth on btc chain which transaction must reach before delegation is sent
+	RequiredDepth uint32
+	AutoRenew     bool
+	// best btc block height known to staker when it sent the transaction
+	BroadcastHeight uint32
+	// staking transaction which staking output is spent by this transaction, if
+	// it is renewal
+	RenewedFromTxHash *chainhash.Hash
+}
+
 func (c *TrackedTransactionStore) AddTransaction(
 	btcTx *wire.MsgTx,
 	stakingOutputIndex uint32,
@@ -569,6 +981,7 @@ func (c *TrackedTransactionStore) AddTransaction(
 	fpPubKeys []*btcec.PublicKey,
 	pop *ProofOfPossession,
 	stakerAddress btcutil.Address,
+	opts AddTransactionOpts,
 ) error {
 	txHash := btcTx.TxHash()
 	txHashBytes := txHash[:]
@@ -603,6 +1016,24 @@ func (c *TrackedTransactionStore) AddTransaction(
 		State:                        proto.TransactionState_SENT_TO_BTC,
 		Watched:                      false,
 		UnbondingTxData:              nil,
+		ParamsVersion:                opts.ParamsVersion,
+		BabylonMemo:                  opts.BabylonMemo,
+		Label:                        opts.Label,
+		RequiredDepth:                opts.RequiredDepth,
+		AutoRenew:                    opts.AutoRenew,
+		BroadcastHeight:              opts.BroadcastHeight,
+	}
+
+	if opts.RenewedFromTxHash != nil {
+		msg.RenewedFromTxHash = opts.RenewedFromTxHash.CloneBytes()
+	}
+
+	if opts.ChangeOutput != nil {
+		msg.ChangeOutput = &proto.ChangeOutput{
+			OutputIdx: opts.ChangeOutput.OutputIdx,
+			Amount:    int64(opts.ChangeOutput.Amount),
+			Address:   opts.ChangeOutput.Address,
+		}
 	}
 
 	return c.addTransactionInternal(
@@ -617,14 +1048,18 @@ func (c *TrackedTransactionStore) AddWatchedTransaction(
 	fpPubKeys []*btcec.PublicKey,
 	pop *ProofOfPossession,
 	stakerAddress btcutil.Address,
+	paramsVersion uint32,
 	slashingTx *wire.MsgTx,
 	slashingTxSig *schnorr.Signature,
 	stakerBabylonPk *secp256k1.PubKey,
 	stakerBtcPk *btcec.PublicKey,
 	unbondingTx *wire.MsgTx,
+	unbondingOutputIdx uint32,
 	slashUnbondingTx *wire.MsgTx,
 	slashUnbondingTxSig *schnorr.Signature,
 	unbondingTime uint16,
+	label string,
+	requiredDepth uint32,
 ) error {
 	txHash := btcTx.TxHash()
 	txHashBytes := txHash[:]
@@ -659,6 +1094,9 @@ func (c *TrackedTransactionStore) AddWatchedTransaction(
 		State:                        proto.TransactionState_SENT_TO_BTC,
 		Watched:                      true,
 		UnbondingTxData:              nil,
+		ParamsVersion:                paramsVersion,
+		Label:                        label,
+		RequiredDepth:                requiredDepth,
 	}
 
 	serializedSlashingtx, err := utils.SerializeBtcTransaction(slashingTx)
@@ -668,6 +1106,10 @@ func (c *TrackedTransactionStore) AddWatchedTransaction(
 
 	serializedSig := slashingTxSig.Serialize()
 
+	if err := checkUnbondingOutputIndex(unbondingTx, unbondingOutputIdx); err != nil {
+		return err
+	}
+
 	serializedUnbondingTx, err := utils.SerializeBtcTransaction(unbondingTx)
 	if err != nil {
 		return err
@@ -690,6 +1132,7 @@ func (c *TrackedTransactionStore) AddWatchedTransaction(
 		SlashingUnbondingTransaction:    serializedSlashUnbondingTx,
 		SlashingUnbondingTransactionSig: serializedSlashUnbondingTxSig,
 		UnbondingTime:                   uint32(unbondingTime),
+		UnbondingOutputIdx:              unbondingOutputIdx,
 	}
 
 	return c.addTransactionInternal(
@@ -697,6 +1140,104 @@ func (c *TrackedTransactionStore) AddWatchedTransaction(
 	)
 }
 
+func btcConfirmationInfoToProto(ci *BtcConfirmationInfo) *proto.BTCConfirmationInfo {
+	if ci == nil {
+		return nil
+	}
+
+	return &proto.BTCConfirmationInfo{
+		BlockHash:      ci.BlockHash.CloneBytes(),
+		BlockHeight:    ci.Height,
+		BlockTimestamp: timeToUnix(ci.BlockTime),
+	}
+}
+
+// AddRecoveredTransaction adds transaction restored from btc and babylon data after
+// database loss. Contrary to AddTransaction, transaction can be added in any
+// state, together with its confirmation and unbonding data.
+func (c *TrackedTransactionStore) AddRecoveredTransaction(storedTx *StoredTransaction) error {
+	txHash := storedTx.StakingTx.TxHash()
+	txHashBytes := txHash[:]
+	serializedTx, err := utils.SerializeBtcTransaction(storedTx.StakingTx)
+
+	if err != nil {
+		return err
+	}
+
+	if len(storedTx.FinalityProvidersBtcPks) == 0 {
+		return fmt.Errorf("cannot add transaction without finality providers public keys")
+	}
+
+	if storedTx.Pop == nil {
+		return fmt.Errorf("cannot add transaction without proof of possession")
+	}
+
+	if storedTx.State >= proto.TransactionState_CONFIRMED_ON_BTC && storedTx.StakingTxConfirmationInfo == nil {
+		return fmt.Errorf("cannot add transaction in state %s without confirmation info", storedTx.State)
+	}
+
+	// stake spent on btc may be recovered without unbonding data, as it is not
+	// needed anymore
+	needsUnbondingData := storedTx.State == proto.TransactionState_SENT_TO_BABYLON ||
+		storedTx.State == proto.TransactionState_DELEGATION_ACTIVE ||
+		storedTx.State == proto.TransactionState_UNBONDING_CONFIRMED_ON_BTC
+
+	if needsUnbondingData && storedTx.UnbondingTxData == nil {
+		return fmt.Errorf("cannot add transaction in state %s without unbonding data: %w", storedTx.State, ErrUnbondingDataNotFound)
+	}
+
+	var fpPubKeysBytes [][]byte = make([][]byte, len(storedTx.FinalityProvidersBtcPks))
+
+	for i, pk := range storedTx.FinalityProvidersBtcPks {
+		fpPubKeysBytes[i] = schnorr.SerializePubKey(pk)
+	}
+
+	var unbondingData *proto.UnbondingTxData = nil
+
+	if storedTx.UnbondingTxData != nil {
+		unbondingData, err = newInitialUnbondingTxData(
+			storedTx.UnbondingTxData.UnbondingTx,
+			storedTx.UnbondingTxData.UnbondingOutputIndex,
+			storedTx.UnbondingTxData.UnbondingTime,
+		)
+
+		if err != nil {
+			return err
+		}
+
+		unbondingData.CovenantSignatures = covenantSigsToProto(storedTx.UnbondingTxData.CovenantSignatures)
+		unbondingData.UnbondingTxBtcConfirmationInfo = btcConfirmationInfoToProto(
+			storedTx.UnbondingTxData.UnbondingTxConfirmationInfo,
+		)
+	}
+
+	msg := proto.TrackedTransaction{
+		// Setting it to 0, proper number will be filled by `addTransactionInternal`
+		TrackedTransactionIdx:        0,
+		StakingTransaction:           serializedTx,
+		StakingOutputIdx:             storedTx.StakingOutputIndex,
+		StakerAddress:                storedTx.StakerAddress,
+		StakingTime:                  uint32(storedTx.StakingTime),
+		FinalityProvidersBtcPks:      fpPubKeysBytes,
+		StakingTxBtcConfirmationInfo: btcConfirmationInfoToProto(storedTx.StakingTxConfirmationInfo),
+		BtcSigType:                   storedTx.Pop.BtcSigType,
+		BabylonSigBtcPk:              storedTx.Pop.BabylonSigOverBtcPk,
+		BtcSigBabylonSig:             storedTx.Pop.BtcSigOverBabylonSig,
+		State:                        storedTx.State,
+		Watched:                      false,
+		UnbondingTxData:              unbondingData,
+		ParamsVersion:                storedTx.ParamsVersion,
+	}
+
+	return c.addTransactionInternal(
+		txHashBytes, &msg, nil,
+	)
+}
+
+// setTxState applies stateTransitionFn to stored transaction and writes result,
+// together with state counts and history, in a single db transaction. Setters
+// must set all data associated with the new state in stateTransitionFn, so that
+// crash cannot leave transaction in new state with only part of its data.
 func (c *TrackedTransactionStore) setTxState(
 	txHash *chainhash.Hash,
 	stateTransitionFn func(*proto.TrackedTransaction) error,
@@ -715,23 +1256,48 @@ func (c *TrackedTransactionStore) setTxState(
 			return ErrCorruptedTransactionsDb
 		}
 
-		maybeTx, txKey, err := getTxByHash(txHashBytes, transactionIdxBucket, transactionsBucket)
+		archiveBucket := tx.ReadBucket(archivedTransactionsBucketName)
+		if archiveBucket == nil {
+			return ErrCorruptedTransactionsDb
+		}
+
+		maybeTx, txKey, err := getTxByHash(txHashBytes, transactionIdxBucket, transactionsBucket, archiveBucket)
 
 		if err != nil {
 			return err
 		}
 
-		var storedTx proto.TrackedTransaction
-		err = pm.Unmarshal(maybeTx, &storedTx)
+		// transition is applied to the decoded proto message, instead of message
+		// rebuilt from StoredTransaction, so that fields unknown to this version
+		// of staker are written back unchanged
+		storedTx, _, err := decodeStoredTransaction(maybeTx)
 		if err != nil {
-			return ErrCorruptedTransactionsDb
+			return err
 		}
 
-		if err := stateTransitionFn(&storedTx); err != nil {
+		prevState := storedTx.State
+
+		if err := stateTransitionFn(storedTx); err != nil {
 			return err
 		}
 
-		marshalled, err := pm.Marshal(&storedTx)
+		if storedTx.State != prevState {
+			storedTx.StateChangedAt = c.now().Unix()
+			storedTx.StateHistory = append(storedTx.StateHistory, &proto.StateTransition{
+				State:     storedTx.State,
+				ChangedAt: storedTx.StateChangedAt,
+			})
+
+			if err := changeStateCount(tx, prevState, -1); err != nil {
+				return err
+			}
+
+			if err := changeStateCount(tx, storedTx.State, 1); err != nil {
+				return err
+			}
+		}
+
+		marshalled, err := pm.Marshal(storedTx)
 
 		if err != nil {
 			return err
@@ -751,12 +1317,18 @@ func (c *TrackedTransactionStore) SetTxConfirmed(
 	txHash *chainhash.Hash,
 	blockHash *chainhash.Hash,
 	blockHeight uint32,
+	blockTime time.Time,
 ) error {
 	setTxConfirmed := func(tx *proto.TrackedTransaction) error {
+		if err := checkTransition(tx, proto.TransactionState_CONFIRMED_ON_BTC); err != nil {
+			return err
+		}
+
 		tx.State = proto.TransactionState_CONFIRMED_ON_BTC
 		tx.StakingTxBtcConfirmationInfo = &proto.BTCConfirmationInfo{
-			BlockHash:   blockHash.CloneBytes(),
-			BlockHeight: blockHeight,
+			BlockHash:      blockHash.CloneBytes(),
+			BlockHeight:    blockHeight,
+			BlockTimestamp: timeToUnix(blockTime),
 		}
 		return nil
 	}
@@ -764,44 +1336,190 @@ func (c *TrackedTransactionStore) SetTxConfirmed(
 	return c.setTxState(txHash, setTxConfirmed)
 }
 
+// SetTxSentToBabylon marks delegation of transaction as sent to babylon.
+// babylonTxHash may be empty if hash of babylon transaction is not known.
 func (c *TrackedTransactionStore) SetTxSentToBabylon(
 	txHash *chainhash.Hash,
 	unbondingTx *wire.MsgTx,
+	unbondingOutputIdx uint32,
 	unbondingTime uint16,
+	babylonTxHash string,
+	babylonMemo string,
 ) error {
-	update, err := newInitialUnbondingTxData(unbondingTx, unbondingTime)
+	update, err := newInitialUnbondingTxData(unbondingTx, unbondingOutputIdx, unbondingTime)
 
 	if err != nil {
 		return err
 	}
 
 	setTxSentToBabylon := func(tx *proto.TrackedTransaction) error {
+		if err := checkTransition(tx, proto.TransactionState_SENT_TO_BABYLON); err != nil {
+			return err
+		}
+
 		if tx.UnbondingTxData != nil {
 			return fmt.Errorf("cannot set unbonding started, because unbonding tx data already exists: %w", ErrInvalidUnbondingDataUpdate)
 		}
 
 		tx.State = proto.TransactionState_SENT_TO_BABYLON
 		tx.UnbondingTxData = update
+		tx.BabylonTxHash = babylonTxHash
+		tx.BabylonMemo = babylonMemo
+		tx.BabylonSubmitFailure = nil
 		return nil
 	}
 
 	return c.setTxState(txHash, setTxSentToBabylon)
 }
 
-func (c *TrackedTransactionStore) SetTxSpentOnBtc(txHash *chainhash.Hash) error {
+// SetTxSpentOnBtc marks staked funds as spent. spendTxConfirmation is stored as
+// part of spend transaction data, if the data exists. It can be nil if it is not
+// known.
+func (c *TrackedTransactionStore) SetTxSpentOnBtc(
+	txHash *chainhash.Hash,
+	spendTxConfirmation *BtcConfirmationInfo,
+) error {
 	setTxSpentOnBtc := func(tx *proto.TrackedTransaction) error {
+		if err := checkTransition(tx, proto.TransactionState_SPENT_ON_BTC); err != nil {
+			return err
+		}
+
 		tx.State = proto.TransactionState_SPENT_ON_BTC
+
+		if tx.SpendTxData != nil && spendTxConfirmation != nil {
+			tx.SpendTxData.SpendTxBtcConfirmationInfo = btcConfirmationInfoToProto(spendTxConfirmation)
+		}
 		return nil
 	}
 
 	return c.setTxState(txHash, setTxSpentOnBtc)
 }
 
+// SetTxDelegationExpired marks delegation, which babylon reports as unbonded
+// before it became active locally. Staked funds stay in staking output.
+func (c *TrackedTransactionStore) SetTxDelegationExpired(txHash *chainhash.Hash) error {
+	setTxDelegationExpired := func(tx *proto.TrackedTransaction) error {
+		if err := checkTransition(tx, proto.TransactionState_DELEGATION_EXPIRED); err != nil {
+			return err
+		}
+
+		tx.State = proto.TransactionState_DELEGATION_EXPIRED
+		return nil
+	}
+
+	return c.setTxState(txHash, setTxDelegationExpired)
+}
+
+// SetTxDelegationCancelled marks confirmed staking transaction, which delegation
+// was cancelled by operator before it was sent to babylon. Staked funds stay in
+// staking output and can only be withdrawn once staking timelock expires.
+func (c *TrackedTransactionStore) SetTxDelegationCancelled(txHash *chainhash.Hash) error {
+	setTxDelegationCancelled := func(tx *proto.TrackedTransaction) error {
+		if err := checkTransition(tx, proto.TransactionState_DELEGATION_CANCELLED); err != nil {
+			return err
+		}
+
+		tx.State = proto.TransactionState_DELEGATION_CANCELLED
+		return nil
+	}
+
+	return c.setTxState(txHash, setTxDelegationCancelled)
+}
+
+// SetTxMissingOnBtc marks confirmed staking transaction which cannot be found on
+// btc chain anymore, so that it is visible to operator instead of being silently
+// forgotten
+func (c *TrackedTransactionStore) SetTxMissingOnBtc(txHash *chainhash.Hash) error {
+	setTxMissingOnBtc := func(tx *proto.TrackedTransaction) error {
+		if err := checkTransition(tx, proto.TransactionState_MISSING_ON_BTC); err != nil {
+			return err
+		}
+
+		tx.State = proto.TransactionState_MISSING_ON_BTC
+		return nil
+	}
+
+	return c.setTxState(txHash, setTxMissingOnBtc)
+}
+
+// SetTxFoundOnBtc moves staking transaction marked as missing on btc chain back
+// to CONFIRMED_ON_BTC state, once it is found in given block
+func (c *TrackedTransactionStore) SetTxFoundOnBtc(
+	txHash *chainhash.Hash,
+	blockHash *chainhash.Hash,
+	blockHeight uint32,
+	blockTime time.Time,
+) error {
+	setTxFoundOnBtc := func(tx *proto.TrackedTransaction) error {
+		if err := checkRevert(tx, proto.TransactionState_CONFIRMED_ON_BTC); err != nil {
+			return err
+		}
+
+		tx.State = proto.TransactionState_CONFIRMED_ON_BTC
+		tx.StakingTxBtcConfirmationInfo = &proto.BTCConfirmationInfo{
+			BlockHash:      blockHash.CloneBytes(),
+			BlockHeight:    blockHeight,
+			BlockTimestamp: timeToUnix(blockTime),
+		}
+		return nil
+	}
+
+	return c.setTxState(txHash, setTxFoundOnBtc)
+}
+
+// SetTxConflicted marks staking transaction as conflicted, when one of its inputs
+// was spent by conflictingTxHash before the staking transaction was confirmed
+func (c *TrackedTransactionStore) SetTxConflicted(
+	txHash *chainhash.Hash,
+	conflictingTxHash *chainhash.Hash,
+) error {
+	setTxConflicted := func(tx *proto.TrackedTransaction) error {
+		if err := checkTransition(tx, proto.TransactionState_CONFLICTED); err != nil {
+			return err
+		}
+
+		tx.State = proto.TransactionState_CONFLICTED
+		tx.ConflictingTxHash = conflictingTxHash.CloneBytes()
+		return nil
+	}
+
+	return c.setTxState(txHash, setTxConflicted)
+}
+
+// SetTxLabel stores label of transaction created by staker for given staking
+// transaction. Label of already labeled transaction is overwritten.
+func (c *TrackedTransactionStore) SetTxLabel(
+	stakingTxHash *chainhash.Hash,
+	txHash *chainhash.Hash,
+	label string,
+) error {
+	setTxLabel := func(tx *proto.TrackedTransaction) error {
+		for _, l := range tx.TxLabels {
+			if bytes.Equal(l.TxHash, txHash[:]) {
+				l.Label = label
+				return nil
+			}
+		}
+
+		tx.TxLabels = append(tx.TxLabels, &proto.TxLabel{
+			TxHash: txHash.CloneBytes(),
+			Label:  label,
+		})
+		return nil
+	}
+
+	return c.setTxState(stakingTxHash, setTxLabel)
+}
+
 func (c *TrackedTransactionStore) SetTxUnbondingSignaturesReceived(
 	txHash *chainhash.Hash,
 	covenantSignatures []PubKeySigPair,
 ) error {
 	setUnbondingSignaturesReceived := func(tx *proto.TrackedTransaction) error {
+		if err := checkTransition(tx, proto.TransactionState_DELEGATION_ACTIVE); err != nil {
+			return err
+		}
+
 		if tx.UnbondingTxData == nil {
 			return fmt.Errorf("cannot set unbonding signatures received, because unbonding tx data does not exist: %w", ErrUnbondingDataNotFound)
 		}
@@ -822,16 +1540,22 @@ func (c *TrackedTransactionStore) SetTxUnbondingConfirmedOnBtc(
 	txHash *chainhash.Hash,
 	blockHash *chainhash.Hash,
 	blockHeight uint32,
+	blockTime time.Time,
 ) error {
 	setUnbondingConfirmedOnBtc := func(tx *proto.TrackedTransaction) error {
+		if err := checkTransition(tx, proto.TransactionState_UNBONDING_CONFIRMED_ON_BTC); err != nil {
+			return err
+		}
+
 		if tx.UnbondingTxData == nil {
 			return fmt.Errorf("cannot set unbonding confirmed on btc, because unbonding tx data does not exist: %w", ErrUnbondingDataNotFound)
 		}
 
 		tx.State = proto.TransactionState_UNBONDING_CONFIRMED_ON_BTC
 		tx.UnbondingTxData.UnbondingTxBtcConfirmationInfo = &proto.BTCConfirmationInfo{
-			BlockHash:   blockHash.CloneBytes(),
-			BlockHeight: blockHeight,
+			BlockHash:      blockHash.CloneBytes(),
+			BlockHeight:    blockHeight,
+			BlockTimestamp: timeToUnix(blockTime),
 		}
 		return nil
 	}
@@ -855,24 +1579,28 @@ func (c *TrackedTransactionStore) GetTransaction(txHash *chainhash.Hash) (*Store
 			return ErrCorruptedTransactionsDb
 		}
 
-		maybeTx, _, err := getTxByHash(txHashBytes, transactionIdxBucket, transactionsBucket)
-
-		if err != nil {
-			return err
+		archiveBucket := tx.ReadBucket(archivedTransactionsBucketName)
+		if archiveBucket == nil {
+			return ErrCorruptedTransactionsDb
 		}
 
-		var storedTxProto proto.TrackedTransaction
-		err = pm.Unmarshal(maybeTx, &storedTxProto)
-		if err != nil {
-			return ErrCorruptedTransactionsDb
+		maybeTx, txKey, err := getTxByHash(txHashBytes, transactionIdxBucket, transactionsBucket, archiveBucket)
+
+		archived := errors.Is(err, ErrTransactionArchived)
+
+		if archived {
+			maybeTx = archiveBucket.Get(txKey)
+		} else if err != nil {
+			return err
 		}
 
-		txFromDb, err := protoTxToStoredTransaction(&storedTxProto)
+		_, txFromDb, err := decodeStoredTransaction(maybeTx)
 
 		if err != nil {
 			return err
 		}
 
+		txFromDb.Archived = archived
 		storedTx = txFromDb
 		return nil
 	}, func() {})
@@ -884,6 +1612,77 @@ func (c *TrackedTransactionStore) GetTransaction(txHash *chainhash.Hash) (*Store
 	return storedTx, nil
 }
 
+// QuarantineTransaction moves stored transaction, which cannot be decoded or is in
+// inconsistent state, to the bucket with corrupt transactions. Afterwards
+// transaction is no longer tracked and it is reported as not found.
+func (c *TrackedTransactionStore) QuarantineTransaction(txHash *chainhash.Hash) error {
+	txHashBytes := txHash.CloneBytes()
+
+	return kvdb.Batch(c.db, func(tx kvdb.RwTx) error {
+		transactionIdxBucket := tx.ReadWriteBucket(transactionIndexName)
+
+		if transactionIdxBucket == nil {
+			return ErrCorruptedTransactionsDb
+		}
+
+		transactionsBucket := tx.ReadWriteBucket(transactionBucketName)
+		if transactionsBucket == nil {
+			return ErrCorruptedTransactionsDb
+		}
+
+		corruptBucket := tx.ReadWriteBucket(corruptTransactionsBucketName)
+		if corruptBucket == nil {
+			return ErrCorruptedTransactionsDb
+		}
+
+		archiveBucket := tx.ReadBucket(archivedTransactionsBucketName)
+		if archiveBucket == nil {
+			return ErrCorruptedTransactionsDb
+		}
+
+		txKey := transactionIdxBucket.Get(txHashBytes)
+
+		if txKey == nil {
+			return ErrTransactionNotFound
+		}
+
+		// archived transactions are never modified, so they are not quarantined
+		if archiveBucket.Get(txKey) != nil {
+			return ErrTransactionArchived
+		}
+
+		// copy key and value, as they are only valid until bucket is modified
+		txKey = append([]byte(nil), txKey...)
+
+		// if index exists but transaction does not, there is nothing to keep and
+		// only index entry is removed
+		if maybeTx := transactionsBucket.Get(txKey); maybeTx != nil {
+			// state of transaction which cannot be decoded at all is unknown, so
+			// its count cannot be updated
+			var storedTxProto proto.TrackedTransaction
+			if err := pm.Unmarshal(maybeTx, &storedTxProto); err == nil {
+				if err := changeStateCount(tx, storedTxProto.State, -1); err != nil {
+					return err
+				}
+
+				if err := removeLabelIndex(tx, storedTxProto.Label, txKey); err != nil {
+					return err
+				}
+			}
+
+			if err := corruptBucket.Put(txHashBytes, append([]byte(nil), maybeTx...)); err != nil {
+				return err
+			}
+
+			if err := transactionsBucket.Delete(txKey); err != nil {
+				return err
+			}
+		}
+
+		return transactionIdxBucket.Delete(txHashBytes)
+	})
+}
+
 func (c *TrackedTransactionStore) GetWatchedTransactionData(txHash *chainhash.Hash) (*WatchedTransactionData, error) {
 	var watchedData *WatchedTransactionData
 	txHashBytes := txHash.CloneBytes()
@@ -961,6 +1760,11 @@ func (c *TrackedTransactionStore) QueryStoredTransactions(q StoredTransactionQue
 			return ErrCorruptedTransactionsDb
 		}
 
+		archiveBucket := tx.ReadBucket(archivedTransactionsBucketName)
+		if archiveBucket == nil {
+			return ErrCorruptedTransactionsDb
+		}
+
 		numTransactions := getNumTx(transactionIdxBucket)
 
 		if numTransactions == 0 {
@@ -969,27 +1773,94 @@ func (c *TrackedTransactionStore) QueryStoredTransactions(q StoredTransactionQue
 
 		resp.Total = numTransactions
 
+		var cursor kvdb.RCursor = transactionsBucket.ReadCursor()
+
+		if q.IncludeArchived {
+			cursor = newMergedCursor(cursor, archiveBucket.ReadCursor())
+		} else {
+			archived, err := archivedCount(tx)
+
+			if err != nil {
+				return err
+			}
+
+			if archived < resp.Total {
+				resp.Total -= archived
+			} else {
+				resp.Total = 0
+			}
+		}
+
+		// with label filter, pages are read from the label index, whose keys are
+		// the same as keys of transactions bucket
+		if q.Label != "" {
+			labelBucket, err := labelIndexBucket(tx, q.Label)
+
+			if err != nil {
+				return err
+			}
+
+			resp.Total = 0
+
+			if labelBucket == nil {
+				return nil
+			}
+
+			err = labelBucket.ForEach(func(k, _ []byte) error {
+				if !q.IncludeArchived && archiveBucket.Get(k) != nil {
+					return nil
+				}
+
+				resp.Total++
+				return nil
+			})
+
+			if err != nil {
+				return err
+			}
+
+			cursor = labelBucket.ReadCursor()
+		}
+
 		paginator := newPaginator(
-			transactionsBucket.ReadCursor(), q.Reversed, q.IndexOffset,
+			cursor, q.Reversed, q.IndexOffset,
 			q.NumMaxTransactions,
 		)
 
 		accumulateTransactions := func(key, transaction []byte) (bool, error) {
-			protoTx := proto.TrackedTransaction{}
+			archived := false
 
-			err := pm.Unmarshal(transaction, &protoTx)
-			if err != nil {
-				return false, err
+			if q.Label != "" {
+				transaction = transactionsBucket.Get(key)
+
+				if transaction == nil && q.IncludeArchived {
+					transaction = archiveBucket.Get(key)
+					archived = transaction != nil
+				}
+
+				// index entry of quarantined or archived transaction
+				if transaction == nil {
+					return false, nil
+				}
+			} else if q.IncludeArchived {
+				archived = archiveBucket.Get(key) != nil
 			}
 
-			txFromDb, err := protoTxToStoredTransaction(&protoTx)
+			_, txFromDb, err := decodeStoredTransaction(transaction)
 
 			if err != nil {
 				return false, err
 			}
 
+			txFromDb.Archived = archived
+
+			if q.State != nil && txFromDb.State != *q.State {
+				return false, nil
+			}
+
 			// we have query only for withdrawable transaction i.e transactions which
-			// either in SENT_TO_BABYLON or DELEGATION_ACTIVE or UNBONDING_CONFIRMED_ON_BTC state and which timelock has expired
+			// either in SENT_TO_BABYLON or DELEGATION_ACTIVE or DELEGATION_EXPIRED or UNBONDING_SIGNATURES_TIMEOUT
+			// or DELEGATION_CANCELLED or UNBONDING_CONFIRMED_ON_BTC state and which timelock has expired
 			if q.withdrawableTransactionsFilter != nil {
 				var confirmationHeight uint32
 				var scriptTimeLock uint16
@@ -1053,6 +1924,33 @@ func (c *TrackedTransactionStore) QueryStoredTransactions(q StoredTransactionQue
 	return resp, nil
 }
 
+// HasTransactions returns true if store holds any transaction, either tracked
+// or archived
+func (c *TrackedTransactionStore) HasTransactions() (bool, error) {
+	hasTransactions := false
+
+	err := kvdb.View(c.db, func(tx kvdb.RTx) error {
+		transactionIdxBucket := tx.ReadBucket(transactionIndexName)
+
+		if transactionIdxBucket == nil {
+			return ErrCorruptedTransactionsDb
+		}
+
+		k, _ := transactionIdxBucket.ReadCursor().First()
+		hasTransactions = k != nil
+
+		return nil
+	}, func() {
+		hasTransactions = false
+	})
+
+	if err != nil {
+		return false, err
+	}
+
+	return hasTransactions, nil
+}
+
 func (c *TrackedTransactionStore) ScanTrackedTransactions(scanFunc StoredTransactionScanFn, reset func()) error {
 	return kvdb.View(c.db, func(tx kvdb.RTx) error {
 		transactionsBucket := tx.ReadBucket(transactionBucketName)
@@ -1062,14 +1960,7 @@ func (c *TrackedTransactionStore) ScanTrackedTransactions(scanFunc StoredTransac
 		}
 
 		return transactionsBucket.ForEach(func(k, v []byte) error {
-			var storedTxProto proto.TrackedTransaction
-			err := pm.Unmarshal(v, &storedTxProto)
-
-			if err != nil {
-				return ErrCorruptedTransactionsDb
-			}
-
-			txFromDb, err := protoTxToStoredTransaction(&storedTxProto)
+			_, txFromDb, err := decodeStoredTransaction(v)
 
 			if err != nil {
 				return err
@@ -9,8 +9,8 @@ import (
 
 	"github.com/babylonchain/babylon/testutil/datagen"
 	"github.com/babylonchain/btc-staker/proto"
-	"github.com/babylonchain/btc-staker/stakercfg"
 	"github.com/babylonchain/btc-staker/stakerdb"
+	"github.com/babylonchain/btc-staker/stakerdb/dbtest"
 	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/btcsuite/btcd/btcec/v2/schnorr"
 	"github.com/btcsuite/btcd/btcutil"
@@ -20,20 +20,7 @@ import (
 )
 
 func MakeTestStore(t *testing.T) *stakerdb.TrackedTransactionStore {
-	// First, create a temporary directory to be used for the duration of
-	// this test.
-	tempDirName := t.TempDir()
-
-	cfg := stakercfg.DefaultDBConfig()
-
-	cfg.DBPath = tempDirName
-
-	backend, err := stakercfg.GetDbBackend(&cfg)
-	require.NoError(t, err)
-
-	t.Cleanup(func() {
-		backend.Close()
-	})
+	backend := dbtest.NewBackend(t)
 
 	store, err := stakerdb.NewTrackedTransactionStore(backend)
 	require.NoError(t, err)
@@ -61,7 +48,7 @@ func pubKeysSliceEqual(pk1, pk2 []*btcec.PublicKey) bool {
 
 func genStoredTransaction(t *testing.T, r *rand.Rand, maxStakingTime uint16) *stakerdb.StoredTransaction {
 	btcTx := datagen.GenRandomTx(r)
-	outputIdx := r.Uint32()
+	outputIdx := uint32(r.Intn(len(btcTx.TxOut)))
 	priv, err := btcec.NewPrivateKey()
 	require.NoError(t, err)
 	stakingTime := r.Int31n(int32(maxStakingTime)) + 1
@@ -77,6 +64,15 @@ func genStoredTransaction(t *testing.T, r *rand.Rand, maxStakingTime uint16) *st
 		fpBtcPks[i] = priv.PubKey()
 	}
 
+	var changeOutput *stakerdb.ChangeOutput
+	if r.Intn(2) == 0 {
+		changeOutput = &stakerdb.ChangeOutput{
+			OutputIdx: r.Uint32(),
+			Amount:    btcutil.Amount(r.Int63n(1000000) + 1),
+			Address:   stakerAddr.String(),
+		}
+	}
+
 	return &stakerdb.StoredTransaction{
 		StakingTx:               btcTx,
 		StakingOutputIndex:      outputIdx,
@@ -87,6 +83,8 @@ func genStoredTransaction(t *testing.T, r *rand.Rand, maxStakingTime uint16) *st
 			BtcSigOverBabylonSig: datagen.GenRandomByteArray(r, 64),
 		},
 		StakerAddress: stakerAddr.String(),
+		ParamsVersion: uint32(r.Int31n(10)),
+		ChangeOutput:  changeOutput,
 	}
 }
 
@@ -131,6 +129,10 @@ func FuzzStoringTxs(f *testing.F) {
 				storedTx.FinalityProvidersBtcPks,
 				storedTx.Pop,
 				stakerAddr,
+				stakerdb.AddTransactionOpts{
+					ParamsVersion: storedTx.ParamsVersion,
+					ChangeOutput:  storedTx.ChangeOutput,
+				},
 			)
 			require.NoError(t, err)
 		}
@@ -145,6 +147,7 @@ func FuzzStoringTxs(f *testing.F) {
 			require.True(t, pubKeysSliceEqual(storedTx.FinalityProvidersBtcPks, tx.FinalityProvidersBtcPks))
 			require.Equal(t, storedTx.Pop, tx.Pop)
 			require.Equal(t, storedTx.StakerAddress, tx.StakerAddress)
+			require.Equal(t, storedTx.ParamsVersion, tx.ParamsVersion)
 			require.Equal(t, expectedIdx, tx.StoredTransactionIdx)
 			expectedIdx++
 		}
@@ -190,6 +193,10 @@ func TestStateTransitions(t *testing.T) {
 		tx.FinalityProvidersBtcPks,
 		tx.Pop,
 		stakerAddr,
+		stakerdb.AddTransactionOpts{
+			ParamsVersion: tx.ParamsVersion,
+			ChangeOutput:  tx.ChangeOutput,
+		},
 	)
 	require.NoError(t, err)
 
@@ -202,7 +209,7 @@ func TestStateTransitions(t *testing.T) {
 	hash := datagen.GenRandomBtcdHash(r)
 	height := r.Uint32()
 
-	err = s.SetTxConfirmed(&txHash, &hash, height)
+	err = s.SetTxConfirmed(&txHash, &hash, height, time.Time{})
 	require.NoError(t, err)
 	storedTx, err = s.GetTransaction(&txHash)
 	require.NoError(t, err)
@@ -212,14 +219,14 @@ func TestStateTransitions(t *testing.T) {
 	require.Equal(t, height, storedTx.StakingTxConfirmationInfo.Height)
 
 	// Sent to Babylon
-	err = s.SetTxSentToBabylon(&txHash, tx.StakingTx, tx.StakingTime)
+	err = s.SetTxSentToBabylon(&txHash, tx.StakingTx, 0, tx.StakingTime, "", "")
 	require.NoError(t, err)
 	storedTx, err = s.GetTransaction(&txHash)
 	require.NoError(t, err)
 	require.Equal(t, proto.TransactionState_SENT_TO_BABYLON, storedTx.State)
 
 	// Spent on BTC
-	err = s.SetTxSpentOnBtc(&txHash)
+	err = s.SetTxSpentOnBtc(&txHash, nil)
 	require.NoError(t, err)
 	storedTx, err = s.GetTransaction(&txHash)
 	require.NoError(t, err)
@@ -229,6 +236,124 @@ func TestStateTransitions(t *testing.T) {
 	require.Equal(t, tx.StakingTime, storedTx.UnbondingTxData.UnbondingTime)
 }
 
+func TestStateHistoryAndSpendData(t *testing.T) {
+	r := rand.New(rand.NewSource(time.Now().UnixNano()))
+	s := MakeTestStore(t)
+	tx := genStoredTransaction(t, r, 200)
+	stakerAddr, err := btcutil.DecodeAddress(tx.StakerAddress, &chaincfg.MainNetParams)
+	require.NoError(t, err)
+	txHash := tx.StakingTx.TxHash()
+	err = s.AddTransaction(
+		tx.StakingTx,
+		tx.StakingOutputIndex,
+		tx.StakingTime,
+		tx.FinalityProvidersBtcPks,
+		tx.Pop,
+		stakerAddr,
+		stakerdb.AddTransactionOpts{
+			ParamsVersion: tx.ParamsVersion,
+			ChangeOutput:  tx.ChangeOutput,
+		},
+	)
+	require.NoError(t, err)
+
+	blockHash := datagen.GenRandomBtcdHash(r)
+	blockTime := time.Unix(r.Int63n(1<<32), 0)
+	err = s.SetTxConfirmed(&txHash, &blockHash, r.Uint32(), blockTime)
+	require.NoError(t, err)
+
+	spendTxHash := datagen.GenRandomBtcdHash(r)
+	spentValue := btcutil.Amount(r.Int63n(1000000) + 1000)
+	fee := btcutil.Amount(r.Int63n(1000))
+	err = s.SetSpendTxSent(&txHash, &spendTxHash, spentValue, fee)
+	require.NoError(t, err)
+
+	spendBlockHash := datagen.GenRandomBtcdHash(r)
+	spendConfirmation := &stakerdb.BtcConfirmationInfo{
+		Height:    r.Uint32(),
+		BlockHash: spendBlockHash,
+		BlockTime: blockTime.Add(time.Hour),
+	}
+	err = s.SetTxSpentOnBtc(&txHash, spendConfirmation)
+	require.NoError(t, err)
+
+	storedTx, err := s.GetTransaction(&txHash)
+	require.NoError(t, err)
+	require.True(t, blockTime.Equal(storedTx.StakingTxConfirmationInfo.BlockTime))
+
+	require.Len(t, storedTx.StateHistory, 3)
+	require.Equal(t, proto.TransactionState_SENT_TO_BTC, storedTx.StateHistory[0].State)
+	require.Equal(t, proto.TransactionState_CONFIRMED_ON_BTC, storedTx.StateHistory[1].State)
+	require.Equal(t, proto.TransactionState_SPENT_ON_BTC, storedTx.StateHistory[2].State)
+	for _, transition := range storedTx.StateHistory {
+		require.False(t, transition.ChangedAt.IsZero())
+	}
+
+	require.NotNil(t, storedTx.SpendTxData)
+	require.True(t, spendTxHash.IsEqual(&storedTx.SpendTxData.SpendTxHash))
+	require.Equal(t, spentValue, storedTx.SpendTxData.SpentValue)
+	require.Equal(t, fee, storedTx.SpendTxData.Fee)
+	require.NotNil(t, storedTx.SpendTxData.SpendTxConfirmationInfo)
+	require.Equal(t, spendConfirmation.Height, storedTx.SpendTxData.SpendTxConfirmationInfo.Height)
+	require.True(t, spendConfirmation.BlockTime.Equal(storedTx.SpendTxData.SpendTxConfirmationInfo.BlockTime))
+}
+
+func TestDuplicateStateTransitions(t *testing.T) {
+	r := rand.New(rand.NewSource(time.Now().UnixNano()))
+	s := MakeTestStore(t)
+	tx := genStoredTransaction(t, r, 200)
+	stakerAddr, err := btcutil.DecodeAddress(tx.StakerAddress, &chaincfg.MainNetParams)
+	require.NoError(t, err)
+	txHash := tx.StakingTx.TxHash()
+	err = s.AddTransaction(
+		tx.StakingTx,
+		tx.StakingOutputIndex,
+		tx.StakingTime,
+		tx.FinalityProvidersBtcPks,
+		tx.Pop,
+		stakerAddr,
+		stakerdb.AddTransactionOpts{
+			ParamsVersion: tx.ParamsVersion,
+			ChangeOutput:  tx.ChangeOutput,
+		},
+	)
+	require.NoError(t, err)
+
+	hash := datagen.GenRandomBtcdHash(r)
+	height := r.Uint32()
+	err = s.SetTxConfirmed(&txHash, &hash, height, time.Time{})
+	require.NoError(t, err)
+
+	// duplicate confirmation must not overwrite confirmation info
+	duplicateHash := datagen.GenRandomBtcdHash(r)
+	err = s.SetTxConfirmed(&txHash, &duplicateHash, height+1, time.Time{})
+	require.ErrorIs(t, err, stakerdb.ErrAlreadyInState)
+	storedTx, err := s.GetTransaction(&txHash)
+	require.NoError(t, err)
+	require.Equal(t, proto.TransactionState_CONFIRMED_ON_BTC, storedTx.State)
+	require.True(t, hash.IsEqual(&storedTx.StakingTxConfirmationInfo.BlockHash))
+	require.Equal(t, height, storedTx.StakingTxConfirmationInfo.Height)
+
+	err = s.SetTxSentToBabylon(&txHash, tx.StakingTx, 0, tx.StakingTime, "", "")
+	require.NoError(t, err)
+	err = s.SetTxSentToBabylon(&txHash, tx.StakingTx, 0, tx.StakingTime, "", "")
+	require.ErrorIs(t, err, stakerdb.ErrAlreadyInState)
+
+	err = s.SetTxUnbondingConfirmedOnBtc(&txHash, &hash, height, time.Time{})
+	require.NoError(t, err)
+	err = s.SetTxUnbondingConfirmedOnBtc(&txHash, &hash, height, time.Time{})
+	require.ErrorIs(t, err, stakerdb.ErrAlreadyInState)
+
+	err = s.SetTxSpentOnBtc(&txHash, nil)
+	require.NoError(t, err)
+	err = s.SetTxSpentOnBtc(&txHash, nil)
+	require.ErrorIs(t, err, stakerdb.ErrAlreadyInState)
+
+	storedTx, err = s.GetTransaction(&txHash)
+	require.NoError(t, err)
+	require.Equal(t, proto.TransactionState_SPENT_ON_BTC, storedTx.State)
+}
+
 func TestPaginator(t *testing.T) {
 	r := rand.New(rand.NewSource(time.Now().UnixNano()))
 	s := MakeTestStore(t)
@@ -247,6 +372,10 @@ func TestPaginator(t *testing.T) {
 			storedTx.FinalityProvidersBtcPks,
 			storedTx.Pop,
 			stakerAddr,
+			stakerdb.AddTransactionOpts{
+				ParamsVersion: storedTx.ParamsVersion,
+				ChangeOutput:  storedTx.ChangeOutput,
+			},
 		)
 		require.NoError(t, err)
 	}
@@ -317,6 +446,10 @@ func FuzzQuerySpendableTx(f *testing.F) {
 				storedTx.FinalityProvidersBtcPks,
 				storedTx.Pop,
 				stakerAddr,
+				stakerdb.AddTransactionOpts{
+					ParamsVersion: storedTx.ParamsVersion,
+					ChangeOutput:  storedTx.ChangeOutput,
+				},
 			)
 			require.NoError(t, err)
 		}
@@ -343,7 +476,7 @@ func FuzzQuerySpendableTx(f *testing.F) {
 
 		for _, storedTx := range stored {
 			txHash := storedTx.StakingTx.TxHash()
-			err := s.SetTxConfirmed(&txHash, &txHash, confirmationBlock)
+			err := s.SetTxConfirmed(&txHash, &txHash, confirmationBlock, time.Time{})
 			require.NoError(t, err)
 		}
 
@@ -357,7 +490,10 @@ func FuzzQuerySpendableTx(f *testing.F) {
 			err := s.SetTxSentToBabylon(
 				&txHash,
 				storedTx.StakingTx,
+				0,
 				storedTx.StakingTime,
+				"",
+				"",
 			)
 			require.NoError(t, err)
 		}
@@ -373,6 +509,7 @@ func FuzzQuerySpendableTx(f *testing.F) {
 				&txHash,
 				&txHash,
 				confirmationBlock,
+				time.Time{},
 			)
 			require.NoError(t, err)
 		}
@@ -384,3 +521,152 @@ func FuzzQuerySpendableTx(f *testing.F) {
 		require.Equal(t, storedResult.Total, uint64(maxCreatedTx))
 	})
 }
+
+func TestRegisteringStakingParams(t *testing.T) {
+	s := MakeTestStore(t)
+
+	genKeys := func(n int) []*btcec.PublicKey {
+		keys := make([]*btcec.PublicKey, n)
+		for i := 0; i < n; i++ {
+			priv, err := btcec.NewPrivateKey()
+			require.NoError(t, err)
+			keys[i] = priv.PubKey()
+		}
+		return keys
+	}
+
+	committee := genKeys(3)
+
+	_, err := s.GetStakingParams(1)
+	require.ErrorIs(t, err, stakerdb.ErrStakingParamsNotFound)
+
+	version, err := s.RegisterStakingParams(committee, 2)
+	require.NoError(t, err)
+	require.Equal(t, uint32(1), version)
+
+	// the same committee in different order is the same params version
+	reordered := []*btcec.PublicKey{committee[2], committee[0], committee[1]}
+	version, err = s.RegisterStakingParams(reordered, 2)
+	require.NoError(t, err)
+	require.Equal(t, uint32(1), version)
+
+	// quorum change creates new version
+	version, err = s.RegisterStakingParams(committee, 3)
+	require.NoError(t, err)
+	require.Equal(t, uint32(2), version)
+
+	// rotated committee creates new version
+	rotated := append(committee[:2:2], genKeys(1)...)
+	version, err = s.RegisterStakingParams(rotated, 3)
+	require.NoError(t, err)
+	require.Equal(t, uint32(3), version)
+
+	snapshot, err := s.GetStakingParams(1)
+	require.NoError(t, err)
+	require.Equal(t, uint32(1), snapshot.Version)
+	require.Equal(t, uint32(2), snapshot.CovenantQuorum)
+	require.Len(t, snapshot.CovenantPks, len(committee))
+	for _, pk := range committee {
+		found := false
+		for _, snapshotPk := range snapshot.CovenantPks {
+			if bytes.Equal(schnorr.SerializePubKey(pk), schnorr.SerializePubKey(snapshotPk)) {
+				found = true
+			}
+		}
+		require.True(t, found)
+	}
+}
+
+func TestAuditLog(t *testing.T) {
+	s := MakeTestStore(t)
+
+	entries, err := s.QueryAuditLog(1, 10)
+	require.NoError(t, err)
+	require.Empty(t, entries)
+
+	txHash := chainhash.Hash{1}
+
+	request, err := s.AppendAuditEntry(&stakerdb.AuditLogEntry{
+		Type:      proto.AuditEntryType_AUDIT_OPERATION_REQUESTED,
+		Timestamp: time.Unix(1000, 0),
+		Caller:    "127.0.0.1:5000",
+		Method:    "stake",
+		Args:      `{"stakingAmount":10000}`,
+		// sequence number and previous hash are assigned by the store
+		Seq:      100,
+		PrevHash: []byte{1},
+	})
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), request.Seq)
+	require.Empty(t, request.PrevHash)
+
+	outcome, err := s.AppendAuditEntry(&stakerdb.AuditLogEntry{
+		Type:       proto.AuditEntryType_AUDIT_OPERATION_FINISHED,
+		Timestamp:  time.Unix(1001, 0),
+		Caller:     "127.0.0.1:5000",
+		Method:     "stake",
+		RequestSeq: request.Seq,
+		TxHash:     &txHash,
+	})
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), outcome.Seq)
+	require.Equal(t, request.Hash, outcome.PrevHash)
+
+	entries, err = s.QueryAuditLog(1, 10)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	require.Equal(t, *request, entries[0])
+	require.Equal(t, *outcome, entries[1])
+
+	entries, err = s.QueryAuditLog(2, 10)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	require.Equal(t, *outcome, entries[0])
+
+	entries, err = s.QueryAuditLog(1, 1)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	require.Equal(t, *request, entries[0])
+
+	entries, err = s.QueryAuditLog(3, 10)
+	require.NoError(t, err)
+	require.Empty(t, entries)
+}
+
+func TestAddRecoveredTransaction(t *testing.T) {
+	r := rand.New(rand.NewSource(time.Now().UnixNano()))
+	s := MakeTestStore(t)
+
+	hasTransactions, err := s.HasTransactions()
+	require.NoError(t, err)
+	require.False(t, hasTransactions)
+
+	confirmation := &stakerdb.BtcConfirmationInfo{
+		Height:    10,
+		BlockHash: datagen.GenRandomBtcdHash(r),
+		BlockTime: time.Unix(1000, 0),
+	}
+
+	// delegation on babylon cannot be tracked without unbonding data
+	sentToBabylon := genStoredTransaction(t, r, 200)
+	sentToBabylon.State = proto.TransactionState_SENT_TO_BABYLON
+	sentToBabylon.StakingTxConfirmationInfo = confirmation
+	err = s.AddRecoveredTransaction(sentToBabylon)
+	require.ErrorIs(t, err, stakerdb.ErrUnbondingDataNotFound)
+
+	// spent stake does not need it anymore
+	spent := genStoredTransaction(t, r, 200)
+	spent.State = proto.TransactionState_SPENT_ON_BTC
+	spent.StakingTxConfirmationInfo = confirmation
+	require.NoError(t, s.AddRecoveredTransaction(spent))
+
+	spentTxHash := spent.StakingTx.TxHash()
+	storedTx, err := s.GetTransaction(&spentTxHash)
+	require.NoError(t, err)
+	require.Equal(t, proto.TransactionState_SPENT_ON_BTC, storedTx.State)
+	require.Nil(t, storedTx.UnbondingTxData)
+
+	hasTransactions, err = s.HasTransactions()
+	require.NoError(t, err)
+	require.True(t, hasTransactions)
+}
@@ -0,0 +1,61 @@
+package stakerdb
+
+import (
+	"github.com/babylonchain/btc-staker/proto"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+)
+
+// SetTxUnbondingSignaturesTimeout marks delegation which did not receive covenant
+// unbonding signatures within configured timeout
+func (c *TrackedTransactionStore) SetTxUnbondingSignaturesTimeout(txHash *chainhash.Hash) error {
+	setTxUnbondingSignaturesTimeout := func(tx *proto.TrackedTransaction) error {
+		if err := checkTransition(tx, proto.TransactionState_UNBONDING_SIGNATURES_TIMEOUT); err != nil {
+			return err
+		}
+
+		tx.State = proto.TransactionState_UNBONDING_SIGNATURES_TIMEOUT
+		return nil
+	}
+
+	return c.setTxState(txHash, setTxUnbondingSignaturesTimeout)
+}
+
+// ResumeUnbondingWait moves delegation, which timed out waiting for covenant
+// unbonding signatures or which waiting was aborted, back to SENT_TO_BABYLON
+// state, so that staker waits for signatures again
+func (c *TrackedTransactionStore) ResumeUnbondingWait(txHash *chainhash.Hash) error {
+	resumeUnbondingWait := func(tx *proto.TrackedTransaction) error {
+		if tx.State == proto.TransactionState_SENT_TO_BABYLON && tx.UnbondingWaitAborted {
+			tx.UnbondingWaitAborted = false
+			return nil
+		}
+
+		if err := checkRevert(tx, proto.TransactionState_SENT_TO_BABYLON); err != nil {
+			return err
+		}
+
+		tx.State = proto.TransactionState_SENT_TO_BABYLON
+		tx.UnbondingWaitAborted = false
+		return nil
+	}
+
+	return c.setTxState(txHash, resumeUnbondingWait)
+}
+
+// AbortUnbondingWait moves delegation, which timed out waiting for covenant
+// unbonding signatures, back to SENT_TO_BABYLON state and records that staker
+// should not wait for signatures anymore. Staked funds stay in staking output
+// and can be withdrawn once staking timelock expires.
+func (c *TrackedTransactionStore) AbortUnbondingWait(txHash *chainhash.Hash) error {
+	abortUnbondingWait := func(tx *proto.TrackedTransaction) error {
+		if err := checkRevert(tx, proto.TransactionState_SENT_TO_BABYLON); err != nil {
+			return err
+		}
+
+		tx.State = proto.TransactionState_SENT_TO_BABYLON
+		tx.UnbondingWaitAborted = true
+		return nil
+	}
+
+	return c.setTxState(txHash, abortUnbondingWait)
+}
@@ -0,0 +1,61 @@
+package stakerdb
+
+import (
+	"testing"
+
+	"github.com/babylonchain/btc-staker/proto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResumeUnbondingWait(t *testing.T) {
+	s, _ := makeTestStore(t)
+
+	txHash := addTestTransactionInState(t, s, 1000, proto.TransactionState_UNBONDING_SIGNATURES_TIMEOUT)
+
+	require.NoError(t, s.ResumeUnbondingWait(txHash))
+
+	tx, err := s.GetTransaction(txHash)
+	require.NoError(t, err)
+	require.Equal(t, proto.TransactionState_SENT_TO_BABYLON, tx.State)
+	require.False(t, tx.UnbondingWaitAborted)
+
+	// waiting which was not aborted nor timed out cannot be resumed
+	var transitionErr *ErrInvalidStateTransition
+	require.ErrorAs(t, s.ResumeUnbondingWait(txHash), &transitionErr)
+
+	// delegation can time out again
+	require.NoError(t, s.SetTxUnbondingSignaturesTimeout(txHash))
+}
+
+func TestAbortUnbondingWait(t *testing.T) {
+	s, _ := makeTestStore(t)
+
+	txHash := addTestTransactionInState(t, s, 1000, proto.TransactionState_UNBONDING_SIGNATURES_TIMEOUT)
+
+	require.NoError(t, s.AbortUnbondingWait(txHash))
+
+	tx, err := s.GetTransaction(txHash)
+	require.NoError(t, err)
+	require.Equal(t, proto.TransactionState_SENT_TO_BABYLON, tx.State)
+	require.True(t, tx.UnbondingWaitAborted)
+
+	// only timed out waiting can be aborted
+	require.ErrorIs(t, s.AbortUnbondingWait(txHash), ErrInvalidTransactionState)
+
+	// aborted waiting can be resumed
+	require.NoError(t, s.ResumeUnbondingWait(txHash))
+
+	tx, err = s.GetTransaction(txHash)
+	require.NoError(t, err)
+	require.Equal(t, proto.TransactionState_SENT_TO_BABYLON, tx.State)
+	require.False(t, tx.UnbondingWaitAborted)
+}
+
+func TestActiveDelegationUnbondingWaitCannotBeAborted(t *testing.T) {
+	s, _ := makeTestStore(t)
+
+	txHash := addTestTransactionInState(t, s, 1000, proto.TransactionState_DELEGATION_ACTIVE)
+
+	require.ErrorIs(t, s.AbortUnbondingWait(txHash), ErrInvalidTransactionState)
+	require.ErrorIs(t, s.ResumeUnbondingWait(txHash), ErrInvalidTransactionState)
+}
@@ -0,0 +1,112 @@
+package stakerservice
+
+import (
+	"embed"
+	"io/fs"
+	"net"
+	"net/http"
+	"net/http/pprof"
+
+	"github.com/cometbft/cometbft/libs/log"
+	rpc "github.com/cometbft/cometbft/rpc/jsonrpc/server"
+)
+
+//go:embed dashboard
+var dashboardFiles embed.FS
+
+// dashboardRPCs are rpcs available to dashboard. Dashboard is read only, so none
+// of them can change state of the daemon.
+var dashboardRPCs = []string{
+	"health",
+	"stats",
+	"staking_params",
+	"list_staking_transactions",
+	"withdrawable_transactions",
+	"pending_operations",
+}
+
+// dashboardHandler returns handler serving static dashboard page, and read only
+// subset of json rpc under /api/ path. Dashboard requires the same credentials as
+// tcp rpc listeners.
+func (s *StakerService) dashboardHandler(routes RoutesMap, logger log.Logger) (http.Handler, error) {
+	static, err := fs.Sub(dashboardFiles, "dashboard")
+
+	if err != nil {
+		return nil, err
+	}
+
+	apiRoutes := make(RoutesMap, len(dashboardRPCs))
+	for _, name := range dashboardRPCs {
+		apiRoutes[name] = routes[name]
+	}
+
+	apiMux := http.NewServeMux()
+	rpc.RegisterRPCFuncs(apiMux, apiRoutes, logger)
+
+	mux := http.NewServeMux()
+	mux.Handle("/api/", http.StripPrefix("/api", apiMux))
+	mux.Handle("/", http.FileServer(http.FS(static)))
+
+	if s.config.JsonRpcServerConfig.DebugRpcEnabled {
+		registerPprofHandlers(mux)
+	}
+
+	var handler http.Handler = dashboardHeadersHandler(mux)
+
+	user := s.config.JsonRpcServerConfig.RPCUser
+	pass := s.config.JsonRpcServerConfig.RPCPass
+
+	if user != "" {
+		handler = basicAuthHandler(handler, user, pass)
+	}
+
+	return handler, nil
+}
+
+// registerPprofHandlers serves runtime profiles under /debug/pprof/
+func registerPprofHandlers(mux *http.ServeMux) {
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+}
+
+// dashboardHeadersHandler forbids loading anything not served by the dashboard
+// itself, and embedding dashboard in pages of other sites
+func dashboardHeadersHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Security-Policy", "default-src 'self'; frame-ancestors 'none'")
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// startDashboard starts serving dashboard on configured address. Returned listener
+// must be closed on shutdown.
+func (s *StakerService) startDashboard(routes RoutesMap, logger log.Logger, config *rpc.Config) (net.Listener, error) {
+	handler, err := s.dashboardHandler(routes, logger)
+
+	if err != nil {
+		return nil, err
+	}
+
+	address := s.config.DashboardListener.Network() + "://" + s.config.DashboardListener.String()
+
+	listener, err := rpc.Listen(address, config.MaxOpenConnections)
+
+	if err != nil {
+		return nil, err
+	}
+
+	go func() {
+		s.logger.Debug("Starting dashboard HTTP server ", "address", address)
+
+		err := rpc.Serve(listener, handler, logger, config)
+
+		s.logger.Error("Dashboard HTTP server stopped ", "err", err)
+	}()
+
+	return listener, nil
+}
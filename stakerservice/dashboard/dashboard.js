@@ -0,0 +1,174 @@
+"use strict";
+
+// Dashboard is read only. It only calls rpcs exposed by daemon under api/ path,
+// and polls them periodically.
+const refreshIntervalMs = 10000;
+const pageSize = 100;
+
+let offset = 0;
+let requestId = 0;
+
+async function call(method, params) {
+  requestId++;
+
+  const resp = await fetch("api/", {
+    method: "POST",
+    headers: { "Content-Type": "application/json" },
+    body: JSON.stringify({ jsonrpc: "2.0", id: requestId, method: method, params: params || {} }),
+  });
+
+  if (!resp.ok) {
+    throw new Error(method + ": " + resp.status + " " + resp.statusText);
+  }
+
+  const body = await resp.json();
+
+  if (body.error) {
+    throw new Error(method + ": " + body.error.message + " " + (body.error.data || ""));
+  }
+
+  return body.result;
+}
+
+function el(tag, text, className) {
+  const e = document.createElement(tag);
+  if (text !== undefined) {
+    e.textContent = text;
+  }
+  if (className) {
+    e.className = className;
+  }
+  return e;
+}
+
+function fillList(id, entries) {
+  const dl = document.getElementById(id);
+  dl.replaceChildren();
+
+  for (const [name, value, warn] of entries) {
+    dl.append(el("dt", name), el("dd", value, warn ? "warn" : undefined));
+  }
+}
+
+function fillTable(id, rows) {
+  const tbody = document.getElementById(id);
+  tbody.replaceChildren();
+
+  for (const row of rows) {
+    const tr = el("tr");
+    for (const cell of row) {
+      tr.append(cell instanceof Node ? cell : el("td", cell));
+    }
+    tbody.append(tr);
+  }
+}
+
+function renderHealth(health) {
+  const sync = health.wallet_sync;
+
+  fillList("health", [
+    ["Broadcasts", health.broadcasts_paused ? "paused" : "enabled", health.broadcasts_paused],
+    ["Wallet", sync.synced ? "synced" : "not synced " + (sync.error || ""), !sync.synced],
+    ["Babylon balance", health.babylon_balance || "unknown", health.babylon_balance_low],
+    ["Babylon circuit", health.babylon_circuit.state, health.babylon_circuit.state !== "closed"],
+    ["Parked delegations", String((health.parked_delegations || []).length), false],
+  ]);
+}
+
+function renderStats(stats) {
+  fillList("amounts", [
+    ["Locked in staking (sat)", stats.locked_in_staking],
+    ["Locked in unbonding (sat)", stats.locked_in_unbonding],
+    ["Withdrawn (sat)", stats.withdrawn],
+  ]);
+
+  fillTable(
+    "states",
+    (stats.states || []).map((s) => [s.state, s.count, s.oldest_since || ""]),
+  );
+}
+
+function renderParams(params) {
+  fillList("params", [
+    ["Confirmation depth (blocks)", params.confirmation_time_blocks],
+    ["Min staking time (blocks)", params.min_staking_time_blocks],
+    ["Min unbonding time (blocks)", params.min_unbonding_time_blocks],
+    ["Covenant quorum", params.covenant_quorum],
+    ["Active delegations", params.active_delegations + " / " + params.max_active_delegations],
+  ]);
+}
+
+function renderDelegations(list, withdrawable, pending) {
+  const withdrawableHashes = new Set((withdrawable.transactions || []).map((t) => t.staking_tx_hash));
+  const waits = new Map();
+
+  for (const w of pending.confirmation_waits || []) {
+    const progress = w.type + " " + w.confirmations + "/" + w.required_confirmations;
+    waits.set(w.staking_tx_hash, (waits.has(w.staking_tx_hash) ? waits.get(w.staking_tx_hash) + ", " : "") + progress);
+  }
+
+  const transactions = list.transactions || [];
+
+  fillTable(
+    "delegations",
+    transactions.map((t) => [
+      el("td", t.staking_tx_hash, "hash"),
+      t.label || "",
+      t.staking_state,
+      t.staking_value || "",
+      waits.get(t.staking_tx_hash) || "",
+      withdrawableHashes.has(t.staking_tx_hash) ? "yes" : "",
+    ]),
+  );
+
+  const numWithdrawable = transactions.filter((t) => withdrawableHashes.has(t.staking_tx_hash)).length;
+  document.getElementById("withdrawable-summary").textContent =
+    numWithdrawable + " of delegations below can be withdrawn";
+
+  const total = Number(list.total_transaction_count);
+  const last = Math.min(offset + pageSize, total);
+
+  document.getElementById("page").textContent = (total === 0 ? 0 : offset + 1) + "-" + last + " of " + total;
+  document.getElementById("prev").disabled = offset === 0;
+  document.getElementById("next").disabled = last >= total;
+}
+
+async function refresh() {
+  const error = document.getElementById("error");
+
+  try {
+    const [health, stats, params, list, withdrawable, pending] = await Promise.all([
+      call("health"),
+      call("stats"),
+      call("staking_params"),
+      // 64-bit integers are passed as strings
+      call("list_staking_transactions", { offset: String(offset), limit: String(pageSize), verbosity: "1" }),
+      call("withdrawable_transactions", { offset: String(offset), limit: String(pageSize) }),
+      call("pending_operations"),
+    ]);
+
+    renderHealth(health);
+    renderStats(stats);
+    renderParams(params);
+    renderDelegations(list, withdrawable, pending);
+
+    error.hidden = true;
+    document.getElementById("updated").textContent = "updated " + new Date().toLocaleTimeString();
+  } catch (e) {
+    error.textContent = e.message;
+    error.hidden = false;
+  }
+}
+
+document.getElementById("prev").addEventListener("click", () => {
+  offset = Math.max(0, offset - pageSize);
+  refresh();
+});
+
+document.getElementById("next").addEventListener("click", () => {
+  offset += pageSize;
+  refresh();
+});
+
+refresh();
+setInterval(refresh, refreshIntervalMs);
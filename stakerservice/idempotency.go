@@ -0,0 +1,143 @@
+package stakerservice
+
+import (
+	"container/list"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// idempotencyCacheSize is number of idempotency keys of mutating requests, which
+// results are kept to answer retries of the same requests
+const idempotencyCacheSize = 1000
+
+// maxIdempotencyKeyLen limits memory used by keys sent by clients
+const maxIdempotencyKeyLen = 128
+
+// ErrIdempotencyKeyReused is returned when request reuses idempotency key of
+// request to different method
+var ErrIdempotencyKeyReused = errors.New("idempotency key was already used for different method")
+
+type idempotentRequest struct {
+	key    string
+	method string
+	// closed once request finished
+	done   chan struct{}
+	result interface{}
+}
+
+// idempotencyCache is bounded LRU cache of mutating requests sent with
+// idempotency key. Keys are only kept in memory, so retries are not deduplicated
+// across daemon restarts.
+type idempotencyCache struct {
+	mu         sync.Mutex
+	maxEntries int
+	// most recently used requests are at the front
+	order   *list.List
+	entries map[string]*list.Element
+}
+
+func newIdempotencyCache(maxEntries int) *idempotencyCache {
+	return &idempotencyCache{
+		maxEntries: maxEntries,
+		order:      list.New(),
+		entries:    make(map[string]*list.Element),
+	}
+}
+
+// start returns request registered with given key and true if it was already
+// registered by previous request, or registers new request
+func (c *idempotencyCache) start(key, method string) (*idempotentRequest, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.entries[key]; ok {
+		req := el.Value.(*idempotentRequest)
+
+		if req.method != method {
+			return nil, false, ErrIdempotencyKeyReused
+		}
+
+		c.order.MoveToFront(el)
+
+		return req, true, nil
+	}
+
+	req := &idempotentRequest{
+		key:    key,
+		method: method,
+		done:   make(chan struct{}),
+	}
+
+	c.entries[key] = c.order.PushFront(req)
+
+	for c.order.Len() > c.maxEntries {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*idempotentRequest).key)
+	}
+
+	return req, false, nil
+}
+
+// forget removes failed request, so that it can be retried with the same key
+func (c *idempotencyCache) forget(req *idempotentRequest) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.entries[req.key]; ok && el.Value == req {
+		c.order.Remove(el)
+		delete(c.entries, req.key)
+	}
+}
+
+// runIdempotent runs fn at most once for requests with the same idempotency key.
+// Retry received while first request is running waits for it, and retry received
+// after it succeeded gets its result. Failed requests are forgotten, so they can
+// be retried with the same key. Requests without key always run fn.
+func runIdempotent[T any](
+	c *idempotencyCache,
+	key *string,
+	method string,
+	fn func() (T, error),
+) (T, error) {
+	if key == nil || *key == "" {
+		return fn()
+	}
+
+	var empty T
+
+	if len(*key) > maxIdempotencyKeyLen {
+		return empty, fmt.Errorf("idempotency key must be at most %d characters long", maxIdempotencyKeyLen)
+	}
+
+	for {
+		req, existing, err := c.start(*key, method)
+
+		if err != nil {
+			return empty, err
+		}
+
+		if !existing {
+			result, err := fn()
+
+			if err != nil {
+				c.forget(req)
+			} else {
+				req.result = result
+			}
+
+			close(req.done)
+
+			return result, err
+		}
+
+		<-req.done
+
+		if req.result != nil {
+			return req.result.(T), nil
+		}
+
+		// previous request failed and was forgotten, so this one runs it again
+	}
+}
@@ -0,0 +1,143 @@
+package stakerservice
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+
+	rpc "github.com/cometbft/cometbft/rpc/jsonrpc/server"
+	rpctypes "github.com/cometbft/cometbft/rpc/jsonrpc/types"
+)
+
+// ErrAuthenticationRequired is returned by admin methods called over tcp listener
+// without configured rpc credentials
+var ErrAuthenticationRequired = errors.New("method requires authenticated connection, configure rpcuser or use unix socket")
+
+type authenticatedCtxKey struct{}
+
+// rpcListener is single address on which json rpc server listens
+type rpcListener struct {
+	addr   net.Addr
+	useTLS bool
+}
+
+func (l *rpcListener) address() string {
+	return l.addr.Network() + "://" + l.addr.String()
+}
+
+func (l *rpcListener) isUnixSocket() bool {
+	_, ok := l.addr.(*net.UnixAddr)
+	return ok
+}
+
+func (s *StakerService) rpcListeners() []rpcListener {
+	var listeners []rpcListener
+
+	for _, addr := range s.config.RpcListeners {
+		listeners = append(listeners, rpcListener{addr: addr})
+	}
+
+	for _, addr := range s.config.RpcTLSListeners {
+		listeners = append(listeners, rpcListener{addr: addr, useTLS: true})
+	}
+
+	return listeners
+}
+
+// listen opens listener on given address. Socket file of unix socket listener
+// gets configured permissions, and is removed when listener is closed.
+func (s *StakerService) listen(l *rpcListener, maxOpenConnections int) (net.Listener, error) {
+	if !l.isUnixSocket() {
+		return rpc.Listen(l.address(), maxOpenConnections)
+	}
+
+	socketPath := l.addr.String()
+
+	// socket file left by stakerd which did not shut down cleanly would prevent
+	// listening on the same path
+	if info, err := os.Stat(socketPath); err == nil {
+		if info.Mode()&os.ModeSocket == 0 {
+			return nil, fmt.Errorf("cannot listen on %s, file exists and is not a socket", socketPath)
+		}
+
+		if err := os.Remove(socketPath); err != nil {
+			return nil, fmt.Errorf("cannot remove stale socket %s: %w", socketPath, err)
+		}
+	}
+
+	listener, err := rpc.Listen(l.address(), maxOpenConnections)
+	if err != nil {
+		return nil, err
+	}
+
+	perms := os.FileMode(s.config.JsonRpcServerConfig.RPCSocketPerms)
+
+	if err := os.Chmod(socketPath, perms); err != nil {
+		_ = listener.Close()
+		return nil, fmt.Errorf("cannot set permissions of socket %s: %w", socketPath, err)
+	}
+
+	return listener, nil
+}
+
+// handler returns handler serving json rpc on given listener. Connections to tcp
+// listeners must authenticate if credentials are configured, while access to unix
+// socket listeners is controlled by permissions of the socket file.
+func (s *StakerService) handler(l *rpcListener, mux http.Handler) http.Handler {
+	user := s.config.JsonRpcServerConfig.RPCUser
+	pass := s.config.JsonRpcServerConfig.RPCPass
+
+	if l.isUnixSocket() {
+		return markAuthenticated(mux)
+	}
+
+	if user == "" {
+		return mux
+	}
+
+	return basicAuthHandler(markAuthenticated(mux), user, pass)
+}
+
+// markAuthenticated marks requests which reached handler as coming from
+// authenticated caller
+func markAuthenticated(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), authenticatedCtxKey{}, true)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// requireAuthenticated returns ErrAuthenticationRequired unless request was
+// authenticated by basic auth or came over unix socket
+func requireAuthenticated(ctx *rpctypes.Context) error {
+	if ctx == nil || ctx.HTTPReq == nil {
+		return ErrAuthenticationRequired
+	}
+
+	if authenticated, ok := ctx.HTTPReq.Context().Value(authenticatedCtxKey{}).(bool); ok && authenticated {
+		return nil
+	}
+
+	return ErrAuthenticationRequired
+}
+
+func basicAuthHandler(next http.Handler, user, pass string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reqUser, reqPass, ok := r.BasicAuth()
+
+		userMatches := subtle.ConstantTimeCompare([]byte(reqUser), []byte(user)) == 1
+		passMatches := subtle.ConstantTimeCompare([]byte(reqPass), []byte(pass)) == 1
+
+		if !ok || !userMatches || !passMatches {
+			w.Header().Set("WWW-Authenticate", `Basic realm="stakerd"`)
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,65 @@
+package stakerservice
+
+import (
+	"net"
+	"net/http"
+
+	"github.com/cometbft/cometbft/libs/log"
+	rpc "github.com/cometbft/cometbft/rpc/jsonrpc/server"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// metricsHandler returns handler serving staker metrics in Prometheus format under
+// /metrics path. Metrics require the same credentials as tcp rpc listeners.
+func (s *StakerService) metricsHandler() (http.Handler, error) {
+	registry := prometheus.NewRegistry()
+
+	for _, c := range s.staker.MetricsCollectors() {
+		if err := registry.Register(c); err != nil {
+			return nil, err
+		}
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
+
+	var handler http.Handler = mux
+
+	user := s.config.JsonRpcServerConfig.RPCUser
+	pass := s.config.JsonRpcServerConfig.RPCPass
+
+	if user != "" {
+		handler = basicAuthHandler(handler, user, pass)
+	}
+
+	return handler, nil
+}
+
+// startMetrics starts serving metrics on configured address. Returned listener
+// must be closed on shutdown.
+func (s *StakerService) startMetrics(logger log.Logger, config *rpc.Config) (net.Listener, error) {
+	handler, err := s.metricsHandler()
+
+	if err != nil {
+		return nil, err
+	}
+
+	address := s.config.MetricsListener.Network() + "://" + s.config.MetricsListener.String()
+
+	listener, err := rpc.Listen(address, config.MaxOpenConnections)
+
+	if err != nil {
+		return nil, err
+	}
+
+	go func() {
+		s.logger.Debug("Starting metrics HTTP server ", "address", address)
+
+		err := rpc.Serve(listener, handler, logger, config)
+
+		s.logger.Error("Metrics HTTP server stopped ", "err", err)
+	}()
+
+	return listener, nil
+}
@@ -2,19 +2,24 @@ package stakerservice
 
 import (
 	"bytes"
+	"encoding/base64"
 	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"math"
-	"net"
 	"net/http"
+	"sort"
 	"strconv"
 	"strings"
 	"sync/atomic"
+	"time"
 
 	"github.com/babylonchain/btc-staker/babylonclient"
+	"github.com/babylonchain/btc-staker/proto"
 	str "github.com/babylonchain/btc-staker/staker"
 	scfg "github.com/babylonchain/btc-staker/stakercfg"
 	"github.com/babylonchain/btc-staker/stakerdb"
+	"github.com/babylonchain/btc-staker/utils"
 	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/btcsuite/btcd/btcec/v2/schnorr"
 	"github.com/btcsuite/btcd/btcutil"
@@ -46,6 +51,7 @@ type StakerService struct {
 	logger      *logrus.Logger
 	db          kvdb.Backend
 	interceptor signal.Interceptor
+	idempotency *idempotencyCache
 }
 
 func NewStakerService(
@@ -61,568 +67,2721 @@ func NewStakerService(
 		logger:      l,
 		interceptor: sig,
 		db:          db,
+		idempotency: newIdempotencyCache(idempotencyCacheSize),
 	}
 }
 
 func storedTxToStakingDetails(storedTx *stakerdb.StoredTransaction) StakingDetails {
+	var txLabels []TxLabelDetails
+
+	for _, l := range storedTx.TxLabels {
+		txLabels = append(txLabels, TxLabelDetails{
+			TxHash: l.TxHash.String(),
+			Label:  l.Label,
+		})
+	}
+
+	var change *ChangeOutputDetails
+
+	if storedTx.ChangeOutput != nil {
+		details := changeOutputToDetails(storedTx.ChangeOutput)
+		change = &details
+	}
+
+	var renewalTxHash, renewedFromTxHash, conflictingTxHash string
+
+	if storedTx.RenewalTxHash != nil {
+		renewalTxHash = storedTx.RenewalTxHash.String()
+	}
+
+	if storedTx.RenewedFromTxHash != nil {
+		renewedFromTxHash = storedTx.RenewedFromTxHash.String()
+	}
+
+	if storedTx.ConflictingTxHash != nil {
+		conflictingTxHash = storedTx.ConflictingTxHash.String()
+	}
+
 	return StakingDetails{
 		StakingTxHash:  storedTx.StakingTx.TxHash().String(),
 		StakerAddress:  storedTx.StakerAddress,
 		StakingState:   storedTx.State.String(),
 		Watched:        storedTx.Watched,
 		TransactionIdx: strconv.FormatUint(storedTx.StoredTransactionIdx, 10),
+		TxLabels:       txLabels,
+		Change:         change,
+		Label:          storedTx.Label,
+
+		CorruptionReason:    storedTx.CorruptionReason,
+		AutoRenew:           storedTx.AutoRenew,
+		AutoRenewSkipReason: storedTx.AutoRenewSkipReason,
+		RenewalTxHash:       renewalTxHash,
+		RenewedFromTxHash:   renewedFromTxHash,
+
+		StakingFeeEstimate:   optionalFeeEstimateDetails(storedTx.StakingFeeEstimate),
+		UnbondingFeeEstimate: optionalFeeEstimateDetails(storedTx.UnbondingFeeEstimate),
+		SpendFeeEstimate:     optionalFeeEstimateDetails(storedTx.SpendFeeEstimate),
+		BabylonSubmitFailure: optionalBabylonSubmitFailureDetails(storedTx.BabylonSubmitFailure),
+		UnbondingWaitAborted: storedTx.UnbondingWaitAborted,
+		ConflictingTxHash:    conflictingTxHash,
+		ExternalAction:       str.WatchedTxExternalAction(storedTx),
+		Archived:             storedTx.Archived,
+	}
+}
+
+func feeEstimateDetails(estimate *stakerdb.FeeEstimate) FeeEstimateDetails {
+	details := FeeEstimateDetails{
+		Estimator:        estimate.Estimator,
+		ConfTarget:       strconv.FormatUint(uint64(estimate.ConfTarget), 10),
+		FeeRate:          strconv.FormatInt(int64(estimate.FeeRate()), 10),
+		EstimatedFeeRate: strconv.FormatInt(int64(estimate.EstimatedFeeRate), 10),
+		EstimatedAt:      estimate.EstimatedAt.UTC().Format(time.RFC3339),
+	}
+
+	if estimate.FallbackFeeRate != 0 {
+		details.FallbackFeeRate = strconv.FormatInt(int64(estimate.FallbackFeeRate), 10)
+	}
+
+	return details
+}
+
+func optionalFeeEstimateDetails(estimate *stakerdb.FeeEstimate) *FeeEstimateDetails {
+	if estimate == nil {
+		return nil
+	}
+
+	details := feeEstimateDetails(estimate)
+	return &details
+}
+
+func optionalBabylonSubmitFailureDetails(failure *stakerdb.BabylonSubmitFailure) *BabylonSubmitFailureDetails {
+	if failure == nil {
+		return nil
+	}
+
+	details := &BabylonSubmitFailureDetails{
+		Operation: failure.Operation,
+		Codespace: failure.Codespace,
+		Code:      strconv.FormatUint(uint64(failure.Code), 10),
+		RawLog:    failure.RawLog,
+		Executed:  failure.Executed,
+		Class:     babylonclient.ClassifySubmitError(failure.Codespace, failure.Code).String(),
+		FailedAt:  failure.FailedAt.UTC().Format(time.RFC3339),
+
+		BabylonTxHash: failure.BabylonTxHash,
+	}
+
+	if failure.BabylonHeight != 0 {
+		details.BabylonHeight = strconv.FormatInt(failure.BabylonHeight, 10)
+	}
+
+	return details
+}
+
+// addRawTransactionDetails fills staking details with serialized transactions and
+// scripts of the stored transaction
+func (s *StakerService) addRawTransactionDetails(
+	details *StakingDetails,
+	storedTx *stakerdb.StoredTransaction,
+) error {
+	stakingTx, err := utils.SerializeBtcTransaction(storedTx.StakingTx)
+
+	if err != nil {
+		return err
+	}
+
+	stakingOutput := storedTx.StakingTx.TxOut[storedTx.StakingOutputIndex]
+
+	details.StakingTxHex = hex.EncodeToString(stakingTx)
+	details.StakingScriptHex = hex.EncodeToString(stakingOutput.PkScript)
+	details.StakingOutputIdx = strconv.FormatUint(uint64(storedTx.StakingOutputIndex), 10)
+	details.StakingValue = strconv.FormatInt(stakingOutput.Value, 10)
+
+	var unbondingTx *wire.MsgTx
+	var unbondingOutputIdx uint32
+
+	if storedTx.UnbondingTxData != nil {
+		unbondingTx = storedTx.UnbondingTxData.UnbondingTx
+		unbondingOutputIdx = storedTx.UnbondingTxData.UnbondingOutputIndex
+	}
+
+	if storedTx.Watched {
+		stakingTxHash := storedTx.StakingTx.TxHash()
+		watchedData, err := s.staker.GetWatchedTransactionData(&stakingTxHash)
+
+		if err != nil {
+			return err
+		}
+
+		details.StakerBabylonPubKey = hex.EncodeToString(watchedData.StakerBabylonPubKey.Bytes())
+
+		if unbondingTx == nil {
+			unbondingTx = watchedData.UnbondingTx
+			unbondingOutputIdx = watchedData.UnbondingOutputIndex
+		}
+	}
+
+	if unbondingTx != nil {
+		serializedUnbondingTx, err := utils.SerializeBtcTransaction(unbondingTx)
+
+		if err != nil {
+			return err
+		}
+
+		details.UnbondingTxHex = hex.EncodeToString(serializedUnbondingTx)
+		details.UnbondingScriptHex = hex.EncodeToString(unbondingTx.TxOut[unbondingOutputIdx].PkScript)
+	}
+
+	return nil
+}
+
+func changeOutputToDetails(c *stakerdb.ChangeOutput) ChangeOutputDetails {
+	return ChangeOutputDetails{
+		OutputIdx: strconv.FormatUint(uint64(c.OutputIdx), 10),
+		Amount:    c.Amount.String(),
+		Address:   c.Address,
+	}
+}
+
+func walletSyncDetails(status *str.WalletSyncStatus, err error) WalletSyncDetails {
+	if err != nil {
+		return WalletSyncDetails{Error: err.Error()}
+	}
+
+	details := WalletSyncDetails{
+		Synced:                status.NotSyncedErr == nil,
+		WalletBestBlockHeight: strconv.FormatUint(uint64(status.Wallet.BestBlockHeight), 10),
+		WalletBestBlockHash:   status.Wallet.BestBlockHash.String(),
+		NodeBestBlockHeight:   strconv.FormatUint(uint64(status.NodeBestBlockHeight), 10),
+		InitialBlockDownload:  status.Wallet.InitialBlockDownload,
+		Rescanning:            status.Wallet.Rescanning,
+	}
+
+	if status.NotSyncedErr != nil {
+		details.Error = status.NotSyncedErr.Error()
+	}
+
+	return details
+}
+
+func (s *StakerService) health(_ *rpctypes.Context) (*ResultHealth, error) {
+	balance := s.staker.BabylonBalanceStatus()
+	circuit := s.staker.BabylonCircuitStatus()
+
+	result := &ResultHealth{
+		BroadcastsPaused:           s.staker.BroadcastsPaused(),
+		BabylonLowBalanceThreshold: strconv.FormatUint(balance.Threshold, 10),
+		BabylonBalanceLow:          balance.Low,
+		ParkedDelegations:          []ParkedDelegation{},
+		BabylonCircuit: BabylonCircuitDetails{
+			State:               circuit.State.String(),
+			ConsecutiveFailures: strconv.FormatUint(uint64(circuit.ConsecutiveFailures), 10),
+			TotalFailures:       strconv.FormatUint(circuit.TotalFailures, 10),
+		},
+	}
+
+	if !circuit.OpenedAt.IsZero() {
+		result.BabylonCircuit.LastOpenedAt = circuit.OpenedAt.UTC().Format(time.RFC3339)
+	}
+
+	result.WalletSync = walletSyncDetails(s.staker.WalletSyncStatus())
+
+	if balance.Balance != nil {
+		result.BabylonBalance = balance.Balance.String()
+		result.BabylonBalanceCheckedAt = balance.CheckedAt.UTC().Format(time.RFC3339)
+	}
+
+	for stakingTxHash, reason := range balance.ParkedDelegations {
+		result.ParkedDelegations = append(result.ParkedDelegations, ParkedDelegation{
+			StakingTxHash: stakingTxHash.String(),
+			Reason:        reason,
+		})
+	}
+
+	sort.Slice(result.ParkedDelegations, func(i, j int) bool {
+		return result.ParkedDelegations[i].StakingTxHash < result.ParkedDelegations[j].StakingTxHash
+	})
+
+	return result, nil
+}
+
+// setBroadcastEnabled pauses or resumes sending transactions to btc, tracking of
+// already sent transactions continues while broadcasts are paused
+func (s *StakerService) setBroadcastEnabled(_ *rpctypes.Context, enabled bool) (*BroadcastStatusResponse, error) {
+	if err := s.staker.SetBroadcastEnabled(enabled); err != nil {
+		return nil, err
+	}
+
+	return &BroadcastStatusResponse{
+		BroadcastsPaused: s.staker.BroadcastsPaused(),
+	}, nil
+}
+
+func (s *StakerService) spendWhitelistResponse() (*SpendWhitelistResponse, error) {
+	whitelist, err := s.staker.SpendWhitelist()
+
+	if err != nil {
+		return nil, err
+	}
+
+	addresses := make([]WhitelistedAddressDetails, len(whitelist))
+
+	for i, w := range whitelist {
+		addresses[i] = WhitelistedAddressDetails{
+			Address: w.Address,
+			Source:  w.Source,
+		}
+	}
+
+	return &SpendWhitelistResponse{
+		Addresses: addresses,
+	}, nil
+}
+
+func (s *StakerService) spendWhitelist(_ *rpctypes.Context) (*SpendWhitelistResponse, error) {
+	return s.spendWhitelistResponse()
+}
+
+func (s *StakerService) addWhitelistedAddress(ctx *rpctypes.Context, address string) (*SpendWhitelistResponse, error) {
+	args := auditArgs{
+		"address": address,
+	}
+
+	err := s.runAudited(ctx, "add_whitelisted_address", args, func() (*str.AuditOperationOutcome, error) {
+		return nil, s.staker.AddWhitelistedAddress(address)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return s.spendWhitelistResponse()
+}
+
+func (s *StakerService) removeWhitelistedAddress(ctx *rpctypes.Context, address string) (*SpendWhitelistResponse, error) {
+	args := auditArgs{
+		"address": address,
+	}
+
+	err := s.runAudited(ctx, "remove_whitelisted_address", args, func() (*str.AuditOperationOutcome, error) {
+		return nil, s.staker.RemoveWhitelistedAddress(address)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return s.spendWhitelistResponse()
+}
+
+func (s *StakerService) stakedValueCapResponse() *StakedValueCapResponse {
+	status := s.staker.StakedValueCap()
+
+	return &StakedValueCapResponse{
+		Cap:          strconv.FormatInt(int64(status.Cap), 10),
+		WindowBlocks: strconv.FormatUint(uint64(status.WindowBlocks), 10),
+		InWindow:     strconv.FormatInt(int64(status.InWindow), 10),
+		Overridden:   status.Overridden,
+		Rejections:   strconv.FormatUint(status.Rejections, 10),
+	}
+}
+
+func (s *StakerService) stakedValueCap(_ *rpctypes.Context) (*StakedValueCapResponse, error) {
+	return s.stakedValueCapResponse(), nil
+}
+
+// overrideStakedValueCap replaces configured staked value cap until daemon
+// restarts. As it can lift the limit protecting against compromised rpc caller,
+// it is only allowed over authenticated connections.
+func (s *StakerService) overrideStakedValueCap(
+	ctx *rpctypes.Context,
+	maxValue int64,
+	windowBlocks uint32,
+) (*StakedValueCapResponse, error) {
+	if err := requireAuthenticated(ctx); err != nil {
+		return nil, err
+	}
+
+	args := auditArgs{
+		"maxValue":     maxValue,
+		"windowBlocks": windowBlocks,
+	}
+
+	err := s.runAudited(ctx, "override_staked_value_cap", args, func() (*str.AuditOperationOutcome, error) {
+		return nil, s.staker.OverrideStakedValueCap(btcutil.Amount(maxValue), windowBlocks)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return s.stakedValueCapResponse(), nil
+}
+
+func (s *StakerService) getStakeOutput(_ *rpctypes.Context,
+	stakerPk string,
+	stakingAmount int64,
+	fpBtcPks []string,
+	stakingTimeBlocks int64,
+) (*ResultStakeOutput, error) {
+	if stakingAmount <= 0 {
+		return nil, fmt.Errorf("staking amount must be positive")
+	}
+
+	amount := btcutil.Amount(stakingAmount)
+
+	stakerPkBytes, err := hex.DecodeString(stakerPk)
+	if err != nil {
+		return nil, err
+	}
+	stakerPubKey, err := schnorr.ParsePubKey(stakerPkBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	fpPubKeys, err := str.ParseFinalityProviderPks(fpBtcPks)
+	if err != nil {
+		return nil, err
+	}
+
+	if stakingTimeBlocks <= 0 || stakingTimeBlocks > math.MaxUint16 {
+		return nil, fmt.Errorf("staking time must be positive and lower than %d", math.MaxUint16)
+	}
+
+	stakingTimeUint16 := uint16(stakingTimeBlocks)
+
+	taprootAddr, err := s.staker.GetStakeOutput(stakerPubKey, amount, fpPubKeys, stakingTimeUint16)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ResultStakeOutput{
+		OutputAddress:       taprootAddr.EncodeAddress(),
+		FinalityProviderPks: xOnlyKeysHex(fpPubKeys),
+	}, nil
+}
+
+// xOnlyKeysHex returns keys in canonical form, so that callers learn in which
+// form keys were used
+func xOnlyKeysHex(pks []*btcec.PublicKey) []string {
+	keys := make([]string, len(pks))
+
+	for i, pk := range pks {
+		keys[i] = str.XOnlyKeyHex(pk)
+	}
+
+	return keys
+}
+
+type stakeRequest struct {
+	stakerAddress btcutil.Address
+	amount        btcutil.Amount
+	fpPubKeys     []*btcec.PublicKey
+	stakingTime   uint16
+	confTarget    *uint32
+	// empty if memo from config should be used
+	memo string
+	// empty if delegation should not be labeled
+	label string
+	// nil if depth from babylon params should be used
+	requiredDepth *uint32
+	// allows staking time above maximum staking time
+	acknowledgeLongLock bool
+	// renew delegation automatically when its timelock expires
+	autoRenew bool
+	// generate new proof of possession instead of using cached one
+	freshPop bool
+}
+
+// parseConfTarget validates confirmation target requested by the caller. Nil is
+// returned if caller did not request any, so that default from config is used.
+func parseConfTarget(confTarget *int) (*uint32, error) {
+	if confTarget == nil {
+		return nil, nil
+	}
+
+	if *confTarget <= 0 || *confTarget > scfg.MaxConfTarget {
+		return nil, fmt.Errorf("confirmation target must be between 1 and %d", scfg.MaxConfTarget)
+	}
+
+	target := uint32(*confTarget)
+	return &target, nil
+}
+
+// parseStakingTime returns staking time in blocks, given either directly or as
+// duration. Minimum staking time is validated by staker, as it depends on current
+// babylon params.
+func (s *StakerService) parseStakingTime(stakingTimeBlocks int64, stakingDuration *string) (uint16, error) {
+	if stakingDuration == nil {
+		if stakingTimeBlocks < 0 {
+			return 0, fmt.Errorf("staking time must be positive")
+		}
+
+		return str.ValidateStakingTime(uint64(stakingTimeBlocks), 0)
+	}
+
+	if stakingTimeBlocks != 0 {
+		return 0, fmt.Errorf("only one of staking time in blocks and staking duration can be provided")
+	}
+
+	blocks, err := str.StakingTimeToBlocks(*stakingDuration, s.config.StakerConfig.BlocksPerHour)
+	if err != nil {
+		return 0, err
+	}
+
+	return str.ValidateStakingTime(blocks, 0)
+}
+
+func (s *StakerService) parseStakeRequest(
+	stakerAddress string,
+	stakingAmount int64,
+	fpBtcPks []string,
+	stakingTimeBlocks int64,
+	stakingDuration *string,
+	confTarget *int,
+	memo *string,
+	label *string,
+	requiredDepth *int,
+	acknowledgeLongLock *bool,
+	autoRenew *bool,
+	freshPop *bool,
+) (*stakeRequest, error) {
+	if stakingAmount <= 0 {
+		return nil, fmt.Errorf("staking amount must be positive")
+	}
+
+	amount := btcutil.Amount(stakingAmount)
+
+	stakerAddr, err := btcutil.DecodeAddress(stakerAddress, &s.config.ActiveNetParams)
+	if err != nil {
+		return nil, err
 	}
+
+	fpPubKeys, err := str.ParseFinalityProviderPks(fpBtcPks)
+	if err != nil {
+		return nil, err
+	}
+
+	stakingTime, err := s.parseStakingTime(stakingTimeBlocks, stakingDuration)
+	if err != nil {
+		return nil, err
+	}
+
+	target, err := parseConfTarget(confTarget)
+	if err != nil {
+		return nil, err
+	}
+
+	depth, err := parseRequiredDepth(requiredDepth)
+	if err != nil {
+		return nil, err
+	}
+
+	req := &stakeRequest{
+		stakerAddress: stakerAddr,
+		amount:        amount,
+		fpPubKeys:     fpPubKeys,
+		stakingTime:   stakingTime,
+		confTarget:    target,
+		requiredDepth: depth,
+	}
+
+	if memo != nil {
+		req.memo = *memo
+	}
+
+	if label != nil {
+		if err := stakerdb.ValidateLabel(*label); err != nil {
+			return nil, err
+		}
+
+		req.label = *label
+	}
+
+	if acknowledgeLongLock != nil {
+		req.acknowledgeLongLock = *acknowledgeLongLock
+	}
+
+	if autoRenew != nil {
+		req.autoRenew = *autoRenew
+	}
+
+	if freshPop != nil {
+		req.freshPop = *freshPop
+	}
+
+	return req, nil
+}
+
+// auditArgs are arguments of fund moving operation, as they are recorded in
+// audit log
+type auditArgs map[string]interface{}
+
+// runAudited runs fund moving operation, recording it in audit log. Request is
+// recorded before operation is run, so if it cannot be recorded, operation fails
+// without moving any funds.
+func (s *StakerService) runAudited(
+	ctx *rpctypes.Context,
+	method string,
+	args auditArgs,
+	op func() (*str.AuditOperationOutcome, error),
+) error {
+	caller := ctx.RemoteAddr()
+
+	argsJson, err := json.Marshal(args)
+	if err != nil {
+		return err
+	}
+
+	requestSeq, err := s.staker.RecordAuditRequest(caller, method, string(argsJson))
+	if err != nil {
+		return fmt.Errorf("cannot record %s request in audit log: %w", method, err)
+	}
+
+	outcome, opErr := op()
+
+	if outcome == nil {
+		outcome = &str.AuditOperationOutcome{}
+	}
+	outcome.Err = opErr
+
+	if err := s.staker.RecordAuditOutcome(requestSeq, caller, method, outcome); err != nil {
+		// operation was already performed, so only log the error
+		s.logger.WithFields(logrus.Fields{
+			"method":     method,
+			"requestSeq": requestSeq,
+			"err":        err,
+		}).Error("Failed to record outcome of operation in audit log")
+	}
+
+	return opErr
+}
+
+func (s *StakerService) stake(ctx *rpctypes.Context,
+	stakerAddress string,
+	stakingAmount int64,
+	fpBtcPks []string,
+	stakingTimeBlocks int64,
+	confTarget *int,
+	stakingDuration *string,
+	memo *string,
+	label *string,
+	requiredDepth *int,
+	acknowledgeLongLock *bool,
+	autoRenew *bool,
+	freshPop *bool,
+	idempotencyKey *string,
+) (*ResultStake, error) {
+	return runIdempotent(s.idempotency, idempotencyKey, "stake", func() (*ResultStake, error) {
+		req, err := s.parseStakeRequest(stakerAddress, stakingAmount, fpBtcPks, stakingTimeBlocks, stakingDuration, confTarget, memo, label, requiredDepth, acknowledgeLongLock, autoRenew, freshPop)
+		if err != nil {
+			return nil, err
+		}
+
+		args := auditArgs{
+			"stakerAddress":       stakerAddress,
+			"stakingAmount":       stakingAmount,
+			"fpBtcPks":            fpBtcPks,
+			"stakingTimeBlocks":   stakingTimeBlocks,
+			"stakingDuration":     stakingDuration,
+			"confTarget":          confTarget,
+			"memo":                memo,
+			"label":               label,
+			"requiredDepth":       requiredDepth,
+			"acknowledgeLongLock": acknowledgeLongLock,
+			"autoRenew":           autoRenew,
+			"freshPop":            freshPop,
+		}
+
+		var stakingTxHash *chainhash.Hash
+
+		err = s.runAudited(ctx, "stake", args, func() (*str.AuditOperationOutcome, error) {
+			stakingTxHash, err = s.staker.StakeFunds(req.stakerAddress, req.amount, req.fpPubKeys, req.stakingTime, req.confTarget, req.memo, req.label, req.requiredDepth, req.acknowledgeLongLock, req.autoRenew, req.freshPop)
+			return &str.AuditOperationOutcome{TxHash: stakingTxHash}, err
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		return &ResultStake{
+			TxHash:                          stakingTxHash.String(),
+			ConfirmationRegistrationPending: s.staker.ConfirmationRegistrationPending(stakingTxHash),
+			StakingTimeBlocks:               strconv.FormatUint(uint64(req.stakingTime), 10),
+			ApproximateEndTime:              s.staker.ApproximateStakingEndTime(req.stakingTime).UTC().Format(time.RFC3339),
+			FinalityProviderPks:             xOnlyKeysHex(req.fpPubKeys),
+		}, nil
+	})
+}
+
+func (s *StakerService) stakeAsync(ctx *rpctypes.Context,
+	stakerAddress string,
+	stakingAmount int64,
+	fpBtcPks []string,
+	stakingTimeBlocks int64,
+	confTarget *int,
+	stakingDuration *string,
+	memo *string,
+	label *string,
+	requiredDepth *int,
+	acknowledgeLongLock *bool,
+	autoRenew *bool,
+	freshPop *bool,
+	idempotencyKey *string,
+) (*ResultStakeAsync, error) {
+	return runIdempotent(s.idempotency, idempotencyKey, "stake_async", func() (*ResultStakeAsync, error) {
+		req, err := s.parseStakeRequest(stakerAddress, stakingAmount, fpBtcPks, stakingTimeBlocks, stakingDuration, confTarget, memo, label, requiredDepth, acknowledgeLongLock, autoRenew, freshPop)
+		if err != nil {
+			return nil, err
+		}
+
+		args := auditArgs{
+			"stakerAddress":       stakerAddress,
+			"stakingAmount":       stakingAmount,
+			"fpBtcPks":            fpBtcPks,
+			"stakingTimeBlocks":   stakingTimeBlocks,
+			"stakingDuration":     stakingDuration,
+			"confTarget":          confTarget,
+			"memo":                memo,
+			"label":               label,
+			"requiredDepth":       requiredDepth,
+			"acknowledgeLongLock": acknowledgeLongLock,
+			"autoRenew":           autoRenew,
+			"freshPop":            freshPop,
+		}
+
+		var requestId string
+
+		err = s.runAudited(ctx, "stake_async", args, func() (*str.AuditOperationOutcome, error) {
+			requestId, err = s.staker.StakeFundsAsync(req.stakerAddress, req.amount, req.fpPubKeys, req.stakingTime, req.confTarget, req.memo, req.label, req.requiredDepth, req.acknowledgeLongLock, req.autoRenew, req.freshPop)
+			return &str.AuditOperationOutcome{StakingRequestId: requestId}, err
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		return &ResultStakeAsync{
+			RequestId:           requestId,
+			StakingTimeBlocks:   strconv.FormatUint(uint64(req.stakingTime), 10),
+			ApproximateEndTime:  s.staker.ApproximateStakingEndTime(req.stakingTime).UTC().Format(time.RFC3339),
+			FinalityProviderPks: xOnlyKeysHex(req.fpPubKeys),
+		}, nil
+	})
+}
+
+func (s *StakerService) stakingRequestStatus(_ *rpctypes.Context, requestId string) (*StakingRequestStatusResponse, error) {
+	record, err := s.staker.StakingRequestStatus(requestId)
+	if err != nil {
+		return nil, err
+	}
+
+	var txHash string
+	if record.StakingTxHash != nil {
+		txHash = record.StakingTxHash.String()
+	}
+
+	return &StakingRequestStatusResponse{
+		RequestId: record.RequestId,
+		Status:    record.Status.String(),
+		TxHash:    txHash,
+		Error:     record.Error,
+		UpdatedAt: record.UpdatedAt.UTC().Format(time.RFC3339),
+	}, nil
+}
+
+func (s *StakerService) stakingDetails(_ *rpctypes.Context,
+	stakingTxHash string) (*StakingDetails, error) {
+
+	txHash, err := chainhash.NewHashFromStr(stakingTxHash)
+	if err != nil {
+		return nil, err
+	}
+
+	storedTx, err := s.staker.GetStoredTransaction(txHash)
+	if err != nil {
+		return nil, err
+	}
+
+	details := storedTxToStakingDetails(storedTx)
+
+	if err := s.addRawTransactionDetails(&details, storedTx); err != nil {
+		return nil, err
+	}
+
+	progress, err := s.staker.ConfirmationProgress(txHash)
+	if err != nil {
+		return nil, err
+	}
+
+	details.ConfirmationProgress = confirmationProgressToDetails(progress)
+
+	return &details, nil
+}
+
+func confirmationProgressToDetails(progress []str.ConfirmationProgress) []ConfirmationProgressDetails {
+	details := make([]ConfirmationProgressDetails, len(progress))
+
+	for i, p := range progress {
+		details[i] = ConfirmationProgressDetails{
+			Type:                  string(p.Type),
+			TxHash:                p.TxHash.String(),
+			Confirmations:         strconv.FormatUint(uint64(p.Confirmations), 10),
+			RequiredConfirmations: strconv.FormatUint(uint64(p.RequiredConfirmations), 10),
+			LastUpdate:            p.LastUpdate.UTC().Format(time.RFC3339),
+		}
+	}
+
+	return details
+}
+
+// confirmationProgress returns progress of confirmation waits related to staking
+// transaction, so that clients can show progress while transactions are confirming
+func (s *StakerService) confirmationProgress(_ *rpctypes.Context, stakingTxHash string) (*ConfirmationProgressResponse, error) {
+	txHash, err := chainhash.NewHashFromStr(stakingTxHash)
+	if err != nil {
+		return nil, err
+	}
+
+	progress, err := s.staker.ConfirmationProgress(txHash)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ConfirmationProgressResponse{
+		StakingTxHash: txHash.String(),
+		Progress:      confirmationProgressToDetails(progress),
+	}, nil
+}
+
+func (s *StakerService) babylonDelegationInfo(_ *rpctypes.Context, stakingTxHash string) (*BabylonDelegationInfoResponse, error) {
+	txHash, err := chainhash.NewHashFromStr(stakingTxHash)
+	if err != nil {
+		return nil, err
+	}
+
+	delegation, err := s.staker.BabylonDelegationInfo(txHash)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &BabylonDelegationInfoResponse{
+		StakingTxHash:  txHash.String(),
+		FoundOnBabylon: delegation.Info != nil,
+	}
+
+	if delegation.Tracked {
+		resp.LocalState = delegation.LocalState.String()
+	}
+
+	if delegation.Info == nil {
+		return resp, nil
+	}
+
+	info := delegation.Info
+
+	resp.Delegation = &BabylonDelegationDetails{
+		Status:             info.Status.String(),
+		Active:             info.Active,
+		StartHeight:        strconv.FormatUint(info.StartHeight, 10),
+		EndHeight:          strconv.FormatUint(info.EndHeight, 10),
+		TotalSat:           strconv.FormatUint(info.TotalSat, 10),
+		CovenantSignatures: strconv.Itoa(info.NumCovenantSigs),
+	}
+
+	if ud := info.UndelegationInfo; ud != nil {
+		resp.Delegation.Undelegation = &BabylonUndelegationDetails{
+			UnbondingTxHash:             ud.UnbondingTransaction.TxHash().String(),
+			UnbondingTimeBlocks:         strconv.FormatUint(uint64(ud.UnbondingTime), 10),
+			CovenantUnbondingSignatures: strconv.Itoa(len(ud.CovenantUnbondingSignatures)),
+		}
+	}
+
+	return resp, nil
+}
+
+func (s *StakerService) spendStake(ctx *rpctypes.Context,
+	stakingTxHash string, maxFee *float64, confTarget *int, idempotencyKey *string) (*SpendTxDetails, error) {
+	return runIdempotent(s.idempotency, idempotencyKey, "spend_stake", func() (*SpendTxDetails, error) {
+		txHash, err := chainhash.NewHashFromStr(stakingTxHash)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if maxFee != nil && (*maxFee <= 0 || *maxFee > 1) {
+			return nil, fmt.Errorf("max fee must be a fraction of stake value in range (0, 1]")
+		}
+
+		target, err := parseConfTarget(confTarget)
+
+		if err != nil {
+			return nil, err
+		}
+
+		args := auditArgs{
+			"stakingTxHash": stakingTxHash,
+			"maxFee":        maxFee,
+			"confTarget":    confTarget,
+		}
+
+		var spendTxHash *chainhash.Hash
+		var value *btcutil.Amount
+
+		err = s.runAudited(ctx, "spend_stake", args, func() (*str.AuditOperationOutcome, error) {
+			spendTxHash, value, err = s.staker.SpendStake(txHash, maxFee, target)
+			return &str.AuditOperationOutcome{TxHash: spendTxHash}, err
+		})
+
+		if err != nil {
+			return nil, err
+		}
+
+		txValue := strconv.FormatInt(int64(*value), 10)
+
+		return &SpendTxDetails{
+			TxHash:                          spendTxHash.String(),
+			TxValue:                         txValue,
+			ConfirmationRegistrationPending: s.staker.ConfirmationRegistrationPending(spendTxHash),
+		}, nil
+	})
+}
+
+func (s *StakerService) listOutputs(_ *rpctypes.Context) (*OutputsResponse, error) {
+
+	outputs, err := s.staker.ListUnspentOutputs()
+
+	if err != nil {
+		return nil, err
+	}
+
+	var outputDetails []OutputDetail
+
+	for _, output := range outputs {
+		outputDetails = append(outputDetails, OutputDetail{
+			Address: output.Address,
+			Amount:  output.Amount.String(),
+		})
+	}
+
+	return &OutputsResponse{
+		Outputs: outputDetails,
+	}, nil
+}
+
+// keys returns public keys and addresses controlled by the daemon
+func (s *StakerService) keys(_ *rpctypes.Context) (*KeysResponse, error) {
+	keys, err := s.staker.Keys()
+
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &KeysResponse{
+		BabylonKeyName:   keys.BabylonKeyName,
+		BabylonPublicKey: hex.EncodeToString(keys.BabylonPubKey.Bytes()),
+		BabylonAddress:   keys.BabylonAddress,
+		WalletDisabled:   keys.WalletDisabled,
+		WalletLocked:     keys.WalletLocked,
+		WalletAddresses:  []WalletAddressDetail{},
+	}
+
+	for _, address := range keys.WalletAddresses {
+		resp.WalletAddresses = append(resp.WalletAddresses, WalletAddressDetail{
+			Address:   address.Address.EncodeAddress(),
+			Spendable: address.Spendable,
+		})
+	}
+
+	return resp, nil
+}
+
+type PageParams struct {
+	Offset uint64
+	Limit  uint64
+}
+
+func getPageParams(offsetPtr *int, limitPtr *int) PageParams {
+	var limit uint64
+
+	if limitPtr == nil {
+		limit = defaultLimit
+	} else {
+		limit = uint64(*limitPtr)
+	}
+
+	if limit > maxLimit {
+		limit = maxLimit
+	}
+
+	var offset uint64
+
+	if offsetPtr == nil {
+		offset = defaultOffset
+	} else {
+		offset = uint64(*offsetPtr)
+	}
+
+	return PageParams{
+		Offset: offset,
+		Limit:  limit,
+	}
+}
+
+func (s *StakerService) providers(
+	_ *rpctypes.Context,
+	offset, limit *int,
+	pageKey *string,
+	includeSlashed *bool,
+) (*FinalityProvidersResponse, error) {
+
+	pageParams := getPageParams(offset, limit)
+
+	var key []byte
+	if pageKey != nil && *pageKey != "" {
+		if pageParams.Offset > 0 {
+			return nil, fmt.Errorf("offset and page key cannot be used together")
+		}
+
+		decoded, err := base64.StdEncoding.DecodeString(*pageKey)
+
+		if err != nil {
+			return nil, fmt.Errorf("invalid page key: %w", err)
+		}
+
+		key = decoded
+	}
+
+	providersResp, err := s.staker.ListFinalityProviders(
+		pageParams.Limit,
+		pageParams.Offset,
+		key,
+		includeSlashed != nil && *includeSlashed,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var providerInfos []FinalityProviderInfoResponse
+
+	for _, provider := range providersResp.FinalityProviders {
+		status := "active"
+		if provider.Slashed {
+			status = "slashed"
+		}
+
+		v := FinalityProviderInfoResponse{
+			BabylonPublicKey: hex.EncodeToString(provider.BabylonPk.Key),
+			BtcPublicKey:     hex.EncodeToString(schnorr.SerializePubKey(&provider.BtcPk)),
+			Moniker:          provider.Moniker,
+			Commission:       provider.Commission.String(),
+			Status:           status,
+		}
+
+		providerInfos = append(providerInfos, v)
+	}
+
+	totalCount := strconv.FormatUint(providersResp.Total, 10)
+
+	return &FinalityProvidersResponse{
+		FinalityProviders:           providerInfos,
+		TotalFinalityProvidersCount: totalCount,
+		NextPageKey:                 base64.StdEncoding.EncodeToString(providersResp.NextKey),
+	}, nil
+}
+
+// providerExposure returns stake delegated to each finality provider by active
+// delegations. If fpBtcPk is provided, only exposure to this finality provider is
+// returned.
+func (s *StakerService) providerExposure(_ *rpctypes.Context, fpBtcPk *string) (*ProviderExposureResponse, error) {
+	var fpKey *btcec.PublicKey
+
+	if fpBtcPk != nil {
+		pk, err := str.ParsePublicKey(*fpBtcPk)
+
+		if err != nil {
+			return nil, fmt.Errorf("invalid finality provider public key: %w", err)
+		}
+
+		fpKey = pk
+	}
+
+	exposures, err := s.staker.ProviderExposure(fpKey)
+
+	if err != nil {
+		return nil, err
+	}
+
+	providers := make([]ProviderExposureDetails, len(exposures))
+
+	for i, e := range exposures {
+		providers[i] = ProviderExposureDetails{
+			BtcPublicKey:   hex.EncodeToString(schnorr.SerializePubKey(e.FpBtcPk)),
+			Moniker:        e.Moniker,
+			TotalStaked:    strconv.FormatInt(int64(e.TotalStaked), 10),
+			NumDelegations: strconv.FormatUint(e.NumDelegations, 10),
+		}
+	}
+
+	return &ProviderExposureResponse{
+		Providers: providers,
+	}, nil
+}
+
+// stats returns number of transactions in each state together with amounts of
+// funds locked and withdrawn by staker
+func (s *StakerService) stats(_ *rpctypes.Context) (*DaemonStatsResponse, error) {
+	stats, err := s.staker.Stats()
+
+	if err != nil {
+		return nil, err
+	}
+
+	states := make([]proto.TransactionState, 0, len(proto.TransactionState_name))
+
+	for state := range proto.TransactionState_name {
+		states = append(states, proto.TransactionState(state))
+	}
+
+	sort.Slice(states, func(i, j int) bool {
+		return states[i] < states[j]
+	})
+
+	resp := &DaemonStatsResponse{
+		States:            make([]StateStatsDetails, len(states)),
+		LockedInStaking:   strconv.FormatInt(int64(stats.LockedInStaking), 10),
+		LockedInUnbonding: strconv.FormatInt(int64(stats.LockedInUnbonding), 10),
+		Withdrawn:         strconv.FormatInt(int64(stats.Withdrawn), 10),
+		UpdatedAt:         stats.ScannedAt.UTC().Format(time.RFC3339),
+		FeeEstimates:      make([]FeeEstimateDetails, len(stats.FeeEstimates)),
+	}
+
+	for i := range stats.FeeEstimates {
+		resp.FeeEstimates[i] = feeEstimateDetails(&stats.FeeEstimates[i])
+	}
+
+	batchStats := s.staker.BabylonBatchStats()
+	resp.BabylonDelegationTxs = BabylonBatchStatsDetails{
+		Transactions:     strconv.FormatUint(batchStats.Transactions, 10),
+		Delegations:      strconv.FormatUint(batchStats.Delegations, 10),
+		AverageBatchSize: strconv.FormatFloat(batchStats.AverageSize(), 'f', 2, 64),
+		FallbackBatches:  strconv.FormatUint(batchStats.FallbackBatches, 10),
+	}
+	resp.StakedValueCapRejections = strconv.FormatUint(s.staker.StakedValueCap().Rejections, 10)
+
+	for i, state := range states {
+		details := StateStatsDetails{
+			State: state.String(),
+			Count: "0",
+		}
+
+		if stateStats, ok := stats.States[state]; ok {
+			details.Count = strconv.FormatUint(stateStats.Count, 10)
+
+			if !stateStats.OldestSince.IsZero() {
+				details.OldestSince = stateStats.OldestSince.UTC().Format(time.RFC3339)
+				details.OldestAgeSeconds = strconv.FormatInt(int64(stateStats.OldestAge.Seconds()), 10)
+			}
+		}
+
+		resp.States[i] = details
+	}
+
+	for _, state := range states {
+		if count, ok := stats.Archived[state]; ok {
+			resp.Archived = append(resp.Archived, StateStatsDetails{
+				State: state.String(),
+				Count: strconv.FormatUint(count, 10),
+			})
+		}
+	}
+
+	return resp, nil
+}
+
+func (s *StakerService) stateMachine(_ *rpctypes.Context) (*StateMachineResponse, error) {
+	definition := stakerdb.StateMachine()
+
+	resp := &StateMachineResponse{
+		InitialState: stakerdb.InitialTransactionState.String(),
+		States:       make([]StateMachineStateDetails, len(definition)),
+	}
+
+	for i, state := range definition {
+		transitions := make([]string, len(state.AllowedTransitions))
+
+		for j, to := range state.AllowedTransitions {
+			transitions[j] = to.String()
+		}
+
+		resp.States[i] = StateMachineStateDetails{
+			State:       state.State.String(),
+			Terminal:    state.Terminal(),
+			Transitions: transitions,
+		}
+
+		if state.RevertsTo != nil {
+			resp.States[i].RevertsTo = state.RevertsTo.String()
+		}
+	}
+
+	return resp, nil
+}
+
+func (s *StakerService) pendingChange(_ *rpctypes.Context) (*PendingChangeResponse, error) {
+	pending, err := s.staker.PendingChange()
+
+	if err != nil {
+		return nil, err
+	}
+
+	transactions := make([]PendingChangeDetails, len(pending.Transactions))
+
+	for i, tx := range pending.Transactions {
+		transactions[i] = PendingChangeDetails{
+			StakingTxHash: tx.StakingTx.TxHash().String(),
+			Change:        changeOutputToDetails(tx.ChangeOutput),
+		}
+	}
+
+	return &PendingChangeResponse{
+		TotalPendingChange: pending.Total.String(),
+		Transactions:       transactions,
+	}, nil
+}
+
+func (s *StakerService) consolidateUtxos(_ *rpctypes.Context, targetCount int, feeRate *int) (*ConsolidateUtxosResponse, error) {
+	var feeRateBtc *btcutil.Amount = nil
+
+	if feeRate != nil {
+		amt := btcutil.Amount(*feeRate)
+		feeRateBtc = &amt
+	}
+
+	result, err := s.staker.ConsolidateUtxos(targetCount, feeRateBtc)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &ConsolidateUtxosResponse{
+		TxHash:    result.TxHash.String(),
+		NumInputs: strconv.Itoa(result.NumInputs),
+		Amount:    result.Amount.String(),
+		Fee:       result.Fee.String(),
+	}, nil
+}
+
+// listStakingTransactions returns stored staking transactions. Raw transactions
+// and scripts are only included if verbosity is greater than 0, to keep default
+// responses small.
+func (s *StakerService) listStakingTransactions(
+	_ *rpctypes.Context,
+	offset, limit, verbosity *int,
+	label, state *string,
+	includeArchived *bool,
+) (*ListStakingTransactionsResponse, error) {
+	pageParams := getPageParams(offset, limit)
+
+	var labelFilter string
+
+	if label != nil {
+		if err := stakerdb.ValidateLabel(*label); err != nil {
+			return nil, err
+		}
+
+		labelFilter = *label
+	}
+
+	var stateFilter *proto.TransactionState
+
+	if state != nil {
+		parsed, err := parseTransactionState(*state)
+
+		if err != nil {
+			return nil, err
+		}
+
+		stateFilter = &parsed
+	}
+
+	txResult, err := s.staker.StoredTransactions(
+		pageParams.Limit,
+		pageParams.Offset,
+		labelFilter,
+		stateFilter,
+		includeArchived != nil && *includeArchived,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	verbose := verbosity != nil && *verbosity > 0
+
+	var stakingDetails []StakingDetails
+
+	for _, tx := range txResult.Transactions {
+		tx := tx
+		details := storedTxToStakingDetails(&tx)
+
+		if verbose {
+			if err := s.addRawTransactionDetails(&details, &tx); err != nil {
+				return nil, err
+			}
+		}
+
+		stakingDetails = append(stakingDetails, details)
+	}
+
+	totalCount := strconv.FormatUint(txResult.Total, 10)
+
+	return &ListStakingTransactionsResponse{
+		Transactions:          stakingDetails,
+		TotalTransactionCount: totalCount,
+	}, nil
+}
+
+func (s *StakerService) withdrawableTransactions(_ *rpctypes.Context, offset, limit *int) (*WithdrawableTransactionsResponse, error) {
+	pageParams := getPageParams(offset, limit)
+
+	txResult, err := s.staker.WithdrawableTransactions(pageParams.Limit, pageParams.Offset)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var stakingDetails []StakingDetails
+
+	for _, tx := range txResult.Transactions {
+		stakingDetails = append(stakingDetails, storedTxToStakingDetails(&tx))
+	}
+
+	var lastIdx string = "0"
+	if len(stakingDetails) > 0 {
+		// this should ease up pagination i.e in case when whe have 1000 transactions, and we limit query to 50
+		// due to filetring we can retrun  response with 50 transactions when last one have index 400,
+		// then caller can specify offset=400 and get next withdrawable transactions.
+		lastIdx = stakingDetails[len(stakingDetails)-1].TransactionIdx
+	}
+
+	totalCount := strconv.FormatUint(txResult.Total, 10)
+
+	return &WithdrawableTransactionsResponse{
+		Transactions:                     stakingDetails,
+		LastWithdrawableTransactionIndex: lastIdx,
+		TotalTransactionCount:            totalCount,
+	}, nil
+}
+
+func decodeBtcTx(txHex string) (*wire.MsgTx, error) {
+	txBytes, err := hex.DecodeString(txHex)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var txMsg wire.MsgTx
+
+	err = txMsg.Deserialize(bytes.NewReader(txBytes))
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &txMsg, nil
+}
+
+func decodeBtcPk(pkHex string) (*btcec.PublicKey, error) {
+	pkBytes, err := hex.DecodeString(pkHex)
+
+	if err != nil {
+		return nil, err
+	}
+
+	pk, err := schnorr.ParsePubKey(pkBytes)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return pk, nil
+}
+
+func parseTimeBtcLock(timelockTime int) (uint16, error) {
+	if timelockTime <= 0 {
+		return 0, fmt.Errorf("staking time must be positive")
+	}
+
+	if timelockTime > math.MaxUint16 {
+		return 0, fmt.Errorf("staking time %d is too big", timelockTime)
+	}
+
+	return uint16(timelockTime), nil
+}
+
+func parseStakingValue(stakingValue int) (btcutil.Amount, error) {
+	if stakingValue <= 0 {
+		return 0, fmt.Errorf("staking value must be positive")
+	}
+
+	return btcutil.Amount(stakingValue), nil
+}
+
+func (s *StakerService) watchStaking(
+	_ *rpctypes.Context,
+	stakingTx string,
+	stakingTime int,
+	stakingValue int,
+	stakerBtcPk string,
+	fpBtcPks []string,
+	slashingTx string,
+	slashingTxSig string,
+	stakerBabylonPk string,
+	stakerAddress string,
+	stakerBabylonSig string,
+	stakerBtcSig string,
+	unbondingTx string,
+	slashUnbondingTx string,
+	slashUnbondingTxSig string,
+	unbondingTime int,
+	popType int,
+	rescanStartHeight *int,
+	label *string,
+	requiredDepth *int,
+	inclusionHeightHint *int,
+) (*ResultStake, error) {
+	var delegationLabel string
+
+	if label != nil {
+		if err := stakerdb.ValidateLabel(*label); err != nil {
+			return nil, err
+		}
+
+		delegationLabel = *label
+	}
+
+	stkTx, err := decodeBtcTx(stakingTx)
+	if err != nil {
+		return nil, err
+	}
+
+	slshTx, err := decodeBtcTx(slashingTx)
+	if err != nil {
+		return nil, err
+	}
+
+	stakerBtcPkParsed, err := decodeBtcPk(stakerBtcPk)
+
+	if err != nil {
+		return nil, err
+	}
+
+	fpPubKeys, err := str.ParseFinalityProviderPks(fpBtcPks)
+	if err != nil {
+		return nil, err
+	}
+
+	stakingTimeUint16, err := parseTimeBtcLock(stakingTime)
+
+	if err != nil {
+		return nil, err
+	}
+
+	stakingValueBtc, err := parseStakingValue(stakingValue)
+
+	if err != nil {
+		return nil, err
+	}
+
+	stakerAddr, err := btcutil.DecodeAddress(stakerAddress, &s.config.ActiveNetParams)
+	if err != nil {
+		return nil, err
+	}
+
+	slashTxSigBytes, err := hex.DecodeString(slashingTxSig)
+	if err != nil {
+		return nil, err
+	}
+
+	slashingTxSchnorSig, err := schnorr.ParseSignature(slashTxSigBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	stakerBabylonPubkeyBytes, err := hex.DecodeString(stakerBabylonPk)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(stakerBabylonPubkeyBytes) != secp256k1.PubKeySize {
+		return nil, fmt.Errorf("babylon public key must have %d bytes", secp256k1.PubKeySize)
+	}
+
+	stakerBabylonPubKey := secp256k1.PubKey{
+		Key: stakerBabylonPubkeyBytes,
+	}
+
+	stakerBabylonSigBytes, err := hex.DecodeString(stakerBabylonSig)
+	if err != nil {
+		return nil, err
+	}
+
+	stakerBtcSigBytes, err := hex.DecodeString(stakerBtcSig)
+	if err != nil {
+		return nil, err
+	}
+
+	btcPopType, err := babylonclient.IntToPopType(popType)
+	if err != nil {
+		return nil, err
+	}
+
+	proofOfPossesion, err := babylonclient.NewBabylonPop(btcPopType, stakerBabylonSigBytes, stakerBtcSigBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unbonding related data
+	unbTx, err := decodeBtcTx(unbondingTx)
+	if err != nil {
+		return nil, err
+	}
+
+	slshUnbTx, err := decodeBtcTx(slashUnbondingTx)
+	if err != nil {
+		return nil, err
+	}
+
+	slashUnbTxSigBytes, err := hex.DecodeString(slashUnbondingTxSig)
+	if err != nil {
+		return nil, err
+	}
+
+	slashUnbTxSig, err := schnorr.ParseSignature(slashUnbTxSigBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	unbTime, err := parseTimeBtcLock(unbondingTime)
+
+	if err != nil {
+		return nil, err
+	}
+
+	rescanHeight, err := parseRescanStartHeight(rescanStartHeight)
+
+	if err != nil {
+		return nil, err
+	}
+
+	depth, err := parseRequiredDepth(requiredDepth)
+
+	if err != nil {
+		return nil, err
+	}
+
+	inclusionHeight, err := parseInclusionHeightHint(inclusionHeightHint)
+
+	if err != nil {
+		return nil, err
+	}
+
+	hash, err := s.staker.WatchStaking(
+		stkTx,
+		stakingTimeUint16,
+		stakingValueBtc,
+		fpPubKeys,
+		slshTx,
+		slashingTxSchnorSig,
+		&stakerBabylonPubKey,
+		stakerBtcPkParsed,
+		stakerAddr,
+		proofOfPossesion,
+		unbTx,
+		slshUnbTx,
+		slashUnbTxSig,
+		unbTime,
+		rescanHeight,
+		inclusionHeight,
+		delegationLabel,
+		depth,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ResultStake{
+		TxHash:                          hash.String(),
+		ConfirmationRegistrationPending: s.staker.ConfirmationRegistrationPending(hash),
+		FinalityProviderPks:             xOnlyKeysHex(fpPubKeys),
+	}, nil
+}
+
+// parseUnbondingFeeRate returns fee rate per kvB given either per kvB by feeRate,
+// or per vB by feeRateSatPerVbyte. Returns nil if neither is set.
+func parseUnbondingFeeRate(feeRate *int, feeRateSatPerVbyte *float64) (*btcutil.Amount, error) {
+	var rate btcutil.Amount
+
+	switch {
+	case feeRate != nil && feeRateSatPerVbyte != nil:
+		return nil, fmt.Errorf("only one of feeRate (sat/kvB) and feeRateSatPerVbyte (sat/vB) can be set")
+	case feeRate != nil:
+		rate = btcutil.Amount(*feeRate)
+	case feeRateSatPerVbyte != nil:
+		perKb, err := utils.SatPerVbyteToPerKb(*feeRateSatPerVbyte)
+
+		if err != nil {
+			return nil, err
+		}
+
+		rate = perKb
+	default:
+		return nil, nil
+	}
+
+	if rate < str.MinFeePerKb {
+		return nil, fmt.Errorf(
+			"fee rate %d sat/kvB (%s sat/vB) is below minimum relay fee rate %d sat/kvB. feeRate is per 1000 vbytes, use feeRateSatPerVbyte for rate per vbyte",
+			rate, formatSatPerVbyte(rate), str.MinFeePerKb,
+		)
+	}
+
+	return &rate, nil
+}
+
+func formatSatPerVbyte(perKb btcutil.Amount) string {
+	return strconv.FormatFloat(utils.PerKbToSatPerVbyte(perKb), 'f', -1, 64)
+}
+
+func (s *StakerService) unbondStaking(
+	ctx *rpctypes.Context,
+	stakingTxHash string,
+	feeRate *int,
+	autoSweep *bool,
+	idempotencyKey *string,
+	feeRateSatPerVbyte *float64,
+) (*UnbondingResponse, error) {
+	return runIdempotent(s.idempotency, idempotencyKey, "unbond_staking", func() (*UnbondingResponse, error) {
+		txHash, err := chainhash.NewHashFromStr(stakingTxHash)
+
+		if err != nil {
+			return nil, err
+		}
+
+		feeRateBtc, err := parseUnbondingFeeRate(feeRate, feeRateSatPerVbyte)
+
+		if err != nil {
+			return nil, err
+		}
+
+		args := auditArgs{
+			"stakingTxHash":      stakingTxHash,
+			"feeRate":            feeRate,
+			"feeRateSatPerVbyte": feeRateSatPerVbyte,
+			"autoSweep":          autoSweep,
+		}
+
+		var unbondingTxHash *chainhash.Hash
+
+		err = s.runAudited(ctx, "unbond_staking", args, func() (*str.AuditOperationOutcome, error) {
+			unbondingTxHash, err = s.staker.UnbondStaking(*txHash, feeRateBtc, autoSweep)
+			return &str.AuditOperationOutcome{TxHash: unbondingTxHash}, err
+		})
+
+		if err != nil {
+			return nil, err
+		}
+
+		storedTx, err := s.staker.GetStoredTransaction(txHash)
+
+		if err != nil {
+			return nil, err
+		}
+
+		fee, actualFeeRate, err := str.UnbondingTxFee(storedTx)
+
+		if err != nil {
+			return nil, err
+		}
+
+		resp := &UnbondingResponse{
+			UnbondingTxHash:    unbondingTxHash.String(),
+			UnbondingFee:       fee.String(),
+			FeeRateSatPerKb:    strconv.FormatInt(int64(actualFeeRate), 10),
+			FeeRateSatPerVbyte: formatSatPerVbyte(actualFeeRate),
+		}
+
+		if feeRateBtc != nil {
+			resp.Warning = fmt.Sprintf(
+				"requested fee rate %d sat/kvB was ignored, unbonding transaction fee was fixed when delegation was sent to babylon",
+				int64(*feeRateBtc),
+			)
+		}
+
+		return resp, nil
+	})
 }
 
-func (s *StakerService) health(_ *rpctypes.Context) (*ResultHealth, error) {
-	return &ResultHealth{}, nil
+func (s *StakerService) previewUnbonding(
+	_ *rpctypes.Context,
+	stakingTxHash string,
+	feeRate *int,
+	feeRateSatPerVbyte *float64,
+) (*UnbondingPreviewResponse, error) {
+	txHash, err := chainhash.NewHashFromStr(stakingTxHash)
+
+	if err != nil {
+		return nil, err
+	}
+
+	feeRateBtc, err := parseUnbondingFeeRate(feeRate, feeRateSatPerVbyte)
+
+	if err != nil {
+		return nil, err
+	}
+
+	preview, err := s.staker.PreviewUnbonding(*txHash, feeRateBtc)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return unbondingPreviewToResponse(preview), nil
 }
 
-func (s *StakerService) getStakeOutput(_ *rpctypes.Context,
-	stakerPk string,
-	stakingAmount int64,
-	fpBtcPks []string,
-	stakingTimeBlocks int64,
-) (*ResultStakeOutput, error) {
-	if stakingAmount <= 0 {
-		return nil, fmt.Errorf("staking amount must be positive")
+func unbondingPreviewToResponse(preview *str.UnbondingPreview) *UnbondingPreviewResponse {
+	return &UnbondingPreviewResponse{
+		UnbondingValue:          preview.UnbondingValue.String(),
+		UnbondingFee:            preview.UnbondingFee.String(),
+		UnbondingFeeFixed:       preview.UnbondingFeeFixed,
+		WithdrawalFee:           preview.WithdrawalFee.String(),
+		NetAmount:               preview.NetAmount.String(),
+		UnbondingTimeBlocks:     strconv.FormatUint(uint64(preview.UnbondingTimeBlocks), 10),
+		ConfirmationBlocks:      strconv.FormatUint(uint64(preview.ConfirmationBlocks), 10),
+		CovenantSigningEstimate: preview.CovenantSigningEstimate.String(),
+		ConfirmationEstimate:    preview.ConfirmationEstimate.String(),
+		SpendableEstimate:       preview.SpendableEstimate.String(),
 	}
+}
 
-	amount := btcutil.Amount(stakingAmount)
+// compareExitOptions compares unbonding of delegation with waiting until its
+// staking timelock expires
+func (s *StakerService) compareExitOptions(_ *rpctypes.Context, stakingTxHash string) (*CompareExitOptionsResponse, error) {
+	txHash, err := chainhash.NewHashFromStr(stakingTxHash)
 
-	stakerPkBytes, err := hex.DecodeString(stakerPk)
 	if err != nil {
 		return nil, err
 	}
-	stakerPubKey, err := schnorr.ParsePubKey(stakerPkBytes)
+
+	options, err := s.staker.CompareExitOptions(*txHash)
+
 	if err != nil {
 		return nil, err
 	}
 
-	var fpPubKeys []*btcec.PublicKey = make([]*btcec.PublicKey, 0)
+	resp := &CompareExitOptionsResponse{
+		StakingTxHash: txHash.String(),
+		StakingValue:  options.StakingValue.String(),
+		Expiry: ExpiryExitOptionDetails{
+			StakingTxConfirmed: options.Expiry.StakingTxConfirmed,
+			RemainingBlocks:    strconv.FormatUint(uint64(options.Expiry.RemainingBlocks), 10),
+			RemainingEstimate:  options.Expiry.RemainingEstimate.String(),
+			WithdrawalFee:      options.Expiry.WithdrawalFee.String(),
+			NetAmount:          options.Expiry.NetAmount.String(),
+		},
+	}
+
+	if options.Unbonding != nil {
+		resp.Unbonding = unbondingPreviewToResponse(options.Unbonding)
+	}
 
-	for _, fpPk := range fpBtcPks {
-		fpPkBytes, err := hex.DecodeString(fpPk)
-		if err != nil {
-			return nil, err
+	return resp, nil
+}
+
+func reconciliationReportToResponse(r *stakerdb.ReconciliationReport) *ReconciliationReportResponse {
+	var missingOnBabylon, statusMismatch, undelegationNotTracked, delegationExpired uint64
+	discrepancies := make([]ReconciliationDiscrepancyDetails, len(r.Discrepancies))
+
+	for i, d := range r.Discrepancies {
+		switch d.Type {
+		case proto.DiscrepancyType_MISSING_ON_BABYLON:
+			missingOnBabylon++
+		case proto.DiscrepancyType_STATUS_MISMATCH:
+			statusMismatch++
+		case proto.DiscrepancyType_UNDELEGATION_NOT_TRACKED:
+			undelegationNotTracked++
+		case proto.DiscrepancyType_DELEGATION_EXPIRED_ON_BABYLON:
+			delegationExpired++
 		}
 
-		fpSchnorrKey, err := schnorr.ParsePubKey(fpPkBytes)
-		if err != nil {
-			return nil, err
+		discrepancies[i] = ReconciliationDiscrepancyDetails{
+			StakingTxHash:   d.StakingTxHash.String(),
+			LocalState:      d.LocalState.String(),
+			DiscrepancyType: d.Type.String(),
+			Description:     d.Description,
 		}
+	}
 
-		fpPubKeys = append(fpPubKeys, fpSchnorrKey)
+	return &ReconciliationReportResponse{
+		CreatedAt:                   r.CreatedAt.UTC().Format(time.RFC3339),
+		CheckedTransactionsCount:    strconv.FormatUint(r.NumCheckedTransactions, 10),
+		MissingOnBabylonCount:       strconv.FormatUint(missingOnBabylon, 10),
+		StatusMismatchCount:         strconv.FormatUint(statusMismatch, 10),
+		UndelegationNotTrackedCount: strconv.FormatUint(undelegationNotTracked, 10),
+		DelegationExpiredCount:      strconv.FormatUint(delegationExpired, 10),
+		Discrepancies:               discrepancies,
 	}
+}
 
-	if stakingTimeBlocks <= 0 || stakingTimeBlocks > math.MaxUint16 {
-		return nil, fmt.Errorf("staking time must be positive and lower than %d", math.MaxUint16)
+// NewDbIntegrityResponse converts integrity issues found in staker db to rpc
+// response
+func NewDbIntegrityResponse(issues []stakerdb.IntegrityIssue) *DbIntegrityResponse {
+	details := make([]DbIntegrityIssueDetails, len(issues))
+
+	for i, issue := range issues {
+		details[i] = DbIntegrityIssueDetails{
+			StakingTxHash: issue.StakingTxHash.String(),
+			StakingState:  issue.State.String(),
+			Corrupted:     issue.Corrupted,
+			Description:   issue.Description,
+		}
 	}
 
-	stakingTimeUint16 := uint16(stakingTimeBlocks)
+	return &DbIntegrityResponse{
+		IssuesCount: strconv.Itoa(len(issues)),
+		Issues:      details,
+	}
+}
+
+func (s *StakerService) dbIntegrity(_ *rpctypes.Context) (*DbIntegrityResponse, error) {
+	issues, err := s.staker.CheckDbIntegrity()
 
-	taprootAddr, err := s.staker.GetStakeOutput(stakerPubKey, amount, fpPubKeys, stakingTimeUint16)
 	if err != nil {
 		return nil, err
 	}
 
-	return &ResultStakeOutput{
-		OutputAddress: taprootAddr.EncodeAddress(),
+	return NewDbIntegrityResponse(issues), nil
+}
+
+func (s *StakerService) repairStakingOutput(_ *rpctypes.Context, stakingTxHash string) (*RepairStakingOutputResponse, error) {
+	txHash, err := chainhash.NewHashFromStr(stakingTxHash)
+	if err != nil {
+		return nil, err
+	}
+
+	outputIdx, err := s.staker.RepairStakingOutputIndex(txHash)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RepairStakingOutputResponse{
+		StakingTxHash:    stakingTxHash,
+		StakingOutputIdx: strconv.FormatUint(uint64(outputIdx), 10),
 	}, nil
 }
 
-func (s *StakerService) stake(_ *rpctypes.Context,
-	stakerAddress string,
-	stakingAmount int64,
-	fpBtcPks []string,
-	stakingTimeBlocks int64,
-) (*ResultStake, error) {
+// retryUnbondingSignatures resumes frequent checks of covenant unbonding
+// signatures of delegation which timed out waiting for them
+func (s *StakerService) retryUnbondingSignatures(_ *rpctypes.Context, stakingTxHash string) (*RetryUnbondingSignaturesResponse, error) {
+	txHash, err := chainhash.NewHashFromStr(stakingTxHash)
+	if err != nil {
+		return nil, err
+	}
 
-	if stakingAmount <= 0 {
-		return nil, fmt.Errorf("staking amount must be positive")
+	if err := s.staker.RetryUnbondingSignatures(txHash); err != nil {
+		return nil, err
 	}
 
-	amount := btcutil.Amount(stakingAmount)
+	return &RetryUnbondingSignaturesResponse{
+		StakingTxHash: stakingTxHash,
+		StakingState:  proto.TransactionState_SENT_TO_BABYLON.String(),
+	}, nil
+}
+
+// abortUnbonding stops waiting for covenant unbonding signatures of delegation
+// which timed out waiting for them
+func (s *StakerService) abortUnbonding(_ *rpctypes.Context, stakingTxHash string) (*AbortUnbondingResponse, error) {
+	txHash, err := chainhash.NewHashFromStr(stakingTxHash)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.staker.AbortUnbonding(txHash); err != nil {
+		return nil, err
+	}
+
+	return &AbortUnbondingResponse{
+		StakingTxHash: stakingTxHash,
+		StakingState:  proto.TransactionState_SENT_TO_BABYLON.String(),
+	}, nil
+}
+
+func (s *StakerService) reconcile(_ *rpctypes.Context) (*ReconciliationReportResponse, error) {
+	report, err := s.staker.Reconcile()
 
-	stakerAddr, err := btcutil.DecodeAddress(stakerAddress, &s.config.ActiveNetParams)
 	if err != nil {
 		return nil, err
 	}
 
-	var fpPubKeys []*btcec.PublicKey = make([]*btcec.PublicKey, 0)
+	return reconciliationReportToResponse(report), nil
+}
 
-	for _, fpPk := range fpBtcPks {
-		fpPkBytes, err := hex.DecodeString(fpPk)
-		if err != nil {
-			return nil, err
+func (s *StakerService) reconciliationReport(_ *rpctypes.Context) (*ReconciliationReportResponse, error) {
+	report, err := s.staker.LastReconciliationReport()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return reconciliationReportToResponse(report), nil
+}
+
+// parseRequiredDepth validates required depth override requested by the caller.
+// Whether override is allowed is checked by staker, as it depends on config and
+// current babylon params.
+func parseRequiredDepth(requiredDepth *int) (*uint32, error) {
+	if requiredDepth == nil {
+		return nil, nil
+	}
+
+	if *requiredDepth <= 0 || int64(*requiredDepth) > math.MaxUint32 {
+		return nil, fmt.Errorf("required depth must be positive, got: %d", *requiredDepth)
+	}
+
+	depth := uint32(*requiredDepth)
+	return &depth, nil
+}
+
+// parseTransactionState parses name of transaction state e.g DELEGATION_ACTIVE,
+// case insensitive
+func parseTransactionState(state string) (proto.TransactionState, error) {
+	value, ok := proto.TransactionState_value[strings.ToUpper(state)]
+
+	if !ok {
+		return 0, fmt.Errorf("unknown transaction state: %s", state)
+	}
+
+	return proto.TransactionState(value), nil
+}
+
+// archiveStateAliases are short names of states accepted by archive rpc
+var archiveStateAliases = map[string]proto.TransactionState{
+	"spent":      proto.TransactionState_SPENT_ON_BTC,
+	"conflicted": proto.TransactionState_CONFLICTED,
+}
+
+// parseArchiveStates parses comma separated states given either by their short
+// names e.g spent,conflicted or by full names
+func parseArchiveStates(states string) ([]proto.TransactionState, error) {
+	var parsed []proto.TransactionState
+
+	for _, name := range strings.Split(states, ",") {
+		name = strings.TrimSpace(name)
+
+		if name == "" {
+			continue
+		}
+
+		if state, ok := archiveStateAliases[strings.ToLower(name)]; ok {
+			parsed = append(parsed, state)
+			continue
 		}
 
-		fpSchnorrKey, err := schnorr.ParsePubKey(fpPkBytes)
+		state, err := parseTransactionState(name)
+
 		if err != nil {
 			return nil, err
 		}
 
-		fpPubKeys = append(fpPubKeys, fpSchnorrKey)
+		parsed = append(parsed, state)
 	}
 
-	if stakingTimeBlocks <= 0 || stakingTimeBlocks > math.MaxUint16 {
-		return nil, fmt.Errorf("staking time must be positive and lower than %d", math.MaxUint16)
+	return parsed, nil
+}
+
+// parseRescanStartHeight validates height from which wallet should be rescanned
+// for transactions not found on btc. Nil is returned if caller did not provide
+// it, so that it is estimated by staker.
+func parseRescanStartHeight(rescanStartHeight *int) (*uint32, error) {
+	if rescanStartHeight == nil {
+		return nil, nil
 	}
 
-	stakingTimeUint16 := uint16(stakingTimeBlocks)
+	if *rescanStartHeight < 0 || int64(*rescanStartHeight) > math.MaxUint32 {
+		return nil, fmt.Errorf("invalid rescan start height: %d", *rescanStartHeight)
+	}
+
+	height := uint32(*rescanStartHeight)
+	return &height, nil
+}
+
+// parseInclusionHeightHint validates height from which watched staking
+// transaction, which can't be found by its hash, is awaited on btc
+func parseInclusionHeightHint(inclusionHeightHint *int) (*uint32, error) {
+	if inclusionHeightHint == nil {
+		return nil, nil
+	}
 
-	stakingTxHash, err := s.staker.StakeFunds(stakerAddr, amount, fpPubKeys, stakingTimeUint16)
+	if *inclusionHeightHint < 0 || int64(*inclusionHeightHint) > math.MaxUint32 {
+		return nil, fmt.Errorf("invalid inclusion height hint: %d", *inclusionHeightHint)
+	}
+
+	height := uint32(*inclusionHeightHint)
+	return &height, nil
+}
+
+func (s *StakerService) recoverDb(
+	_ *rpctypes.Context,
+	stakerAddress string,
+	targetDbPath string,
+	dryRun bool,
+	rescanStartHeight *int,
+) (*RecoverDbResponse, error) {
+	stakerAddr, err := btcutil.DecodeAddress(stakerAddress, &s.config.ActiveNetParams)
 	if err != nil {
 		return nil, err
 	}
 
-	return &ResultStake{
-		TxHash: stakingTxHash.String(),
+	rescanHeight, err := parseRescanStartHeight(rescanStartHeight)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := s.staker.RecoverDb(stakerAddr, targetDbPath, dryRun, rescanHeight)
+
+	if err != nil {
+		return nil, err
+	}
+
+	recovered := make([]RecoveredTransactionDetails, len(result.Recovered))
+
+	for i, r := range result.Recovered {
+		recovered[i] = RecoveredTransactionDetails{
+			StakingTxHash: r.StakingTxHash.String(),
+			StakingState:  r.State.String(),
+		}
+	}
+
+	skipped := make([]SkippedDelegationDetails, len(result.Skipped))
+
+	for i, sk := range result.Skipped {
+		skipped[i] = SkippedDelegationDetails{
+			StakingTxHash: sk.StakingTxHash.String(),
+			Reason:        sk.Reason,
+		}
+	}
+
+	return &RecoverDbResponse{
+		DryRun:       result.DryRun,
+		TargetDbPath: result.TargetDbPath,
+		Recovered:    recovered,
+		Skipped:      skipped,
 	}, nil
 }
 
-func (s *StakerService) stakingDetails(_ *rpctypes.Context,
-	stakingTxHash string) (*StakingDetails, error) {
-
+func (s *StakerService) buildDelegationMsg(_ *rpctypes.Context, stakingTxHash string, signer string) (*BuildDelegationMsgResponse, error) {
 	txHash, err := chainhash.NewHashFromStr(stakingTxHash)
 	if err != nil {
 		return nil, err
 	}
 
-	storedTx, err := s.staker.GetStoredTransaction(txHash)
+	externalDelegation, err := s.staker.BuildDelegationMsg(txHash, signer)
+
 	if err != nil {
 		return nil, err
 	}
 
-	details := storedTxToStakingDetails(storedTx)
-	return &details, nil
-}
+	delegation := externalDelegation.Delegation
 
-func (s *StakerService) spendStake(_ *rpctypes.Context,
-	stakingTxHash string) (*SpendTxDetails, error) {
-	txHash, err := chainhash.NewHashFromStr(stakingTxHash)
+	slashingTx, err := utils.SerializeBtcTransaction(delegation.SlashingTransaction)
 
 	if err != nil {
 		return nil, err
 	}
 
-	spendTxHash, value, err := s.staker.SpendStake(txHash)
+	return &BuildDelegationMsgResponse{
+		StakingTxHash:               stakingTxHash,
+		DelegationMsg:               hex.EncodeToString(externalDelegation.DelegationMsg),
+		StakingTxInclusionBlockHash: delegation.StakingTransactionInclusionBlockHash.String(),
+		StakingTxInclusionProof:     hex.EncodeToString(delegation.StakingTransactionInclusionProof),
+		SlashingTx:                  hex.EncodeToString(slashingTx),
+		SlashingTxSig:               hex.EncodeToString(delegation.SlashingTransactionSig.Serialize()),
+		PopBtcSigType:               strconv.FormatUint(uint64(delegation.BabylonPop.BtcSigType), 10),
+		PopBabylonSig:               hex.EncodeToString(delegation.BabylonPop.BabylonSigOverBtcPk),
+		PopBtcSig:                   hex.EncodeToString(delegation.BabylonPop.BtcSigOverBabylonSig),
+	}, nil
+}
 
+func (s *StakerService) markDelegationSubmitted(_ *rpctypes.Context, stakingTxHash string, babylonTxHash string) (*MarkDelegationSubmittedResponse, error) {
+	txHash, err := chainhash.NewHashFromStr(stakingTxHash)
 	if err != nil {
 		return nil, err
 	}
 
-	txValue := strconv.FormatInt(int64(*value), 10)
+	if err := s.staker.MarkDelegationSubmitted(txHash, babylonTxHash); err != nil {
+		return nil, err
+	}
 
-	return &SpendTxDetails{
-		TxHash:  spendTxHash.String(),
-		TxValue: txValue,
+	return &MarkDelegationSubmittedResponse{
+		StakingTxHash: stakingTxHash,
 	}, nil
 }
 
-func (s *StakerService) listOutputs(_ *rpctypes.Context) (*OutputsResponse, error) {
+// cancelPendingDelegation cancels delegation of staking transaction which was not
+// sent to babylon yet. Staked funds can be withdrawn once staking timelock
+// expires.
+func (s *StakerService) cancelPendingDelegation(ctx *rpctypes.Context, stakingTxHash string) (*CancelPendingDelegationResponse, error) {
+	txHash, err := chainhash.NewHashFromStr(stakingTxHash)
+	if err != nil {
+		return nil, err
+	}
 
-	outputs, err := s.staker.ListUnspentOutputs()
+	args := auditArgs{
+		"stakingTxHash": stakingTxHash,
+	}
+
+	err = s.runAudited(ctx, "cancel_pending_delegation", args, func() (*str.AuditOperationOutcome, error) {
+		return nil, s.staker.CancelPendingDelegation(txHash)
+	})
 
 	if err != nil {
 		return nil, err
 	}
 
-	var outputDetails []OutputDetail
+	resp := &CancelPendingDelegationResponse{
+		StakingTxHash: stakingTxHash,
+		StakingState:  proto.TransactionState_DELEGATION_CANCELLED.String(),
+	}
 
-	for _, output := range outputs {
-		outputDetails = append(outputDetails, OutputDetail{
-			Address: output.Address,
-			Amount:  output.Amount.String(),
-		})
+	tx, err := s.staker.GetStoredTransaction(txHash)
+	if err != nil {
+		return nil, err
 	}
 
-	return &OutputsResponse{
-		Outputs: outputDetails,
-	}, nil
-}
+	if withdrawableHeight, ok := tx.WithdrawableHeight(); ok {
+		resp.WithdrawableHeight = strconv.FormatUint(uint64(withdrawableHeight), 10)
+	}
 
-type PageParams struct {
-	Offset uint64
-	Limit  uint64
+	return resp, nil
 }
 
-func getPageParams(offsetPtr *int, limitPtr *int) PageParams {
-	var limit uint64
+// setTransactionLabel assigns label to tracked staking transaction. Empty label
+// removes label from transaction.
+func (s *StakerService) setTransactionLabel(_ *rpctypes.Context, stakingTxHash string, label string) (*SetTransactionLabelResponse, error) {
+	txHash, err := chainhash.NewHashFromStr(stakingTxHash)
+	if err != nil {
+		return nil, err
+	}
 
-	if limitPtr == nil {
-		limit = defaultLimit
-	} else {
-		limit = uint64(*limitPtr)
+	if err := stakerdb.ValidateLabel(label); err != nil {
+		return nil, err
 	}
 
-	if limit > maxLimit {
-		limit = maxLimit
+	if err := s.staker.SetTransactionLabel(txHash, label); err != nil {
+		return nil, err
 	}
 
-	var offset uint64
+	return &SetTransactionLabelResponse{
+		StakingTxHash: stakingTxHash,
+		Label:         label,
+	}, nil
+}
 
-	if offsetPtr == nil {
-		offset = defaultOffset
-	} else {
-		offset = uint64(*offsetPtr)
+// setTransactionNote assigns free-form note to tracked staking transaction.
+// Empty note removes note from transaction. Changes of notes are recorded in
+// audit log.
+func (s *StakerService) setTransactionNote(ctx *rpctypes.Context, stakingTxHash string, note string) (*TransactionNoteResponse, error) {
+	txHash, err := chainhash.NewHashFromStr(stakingTxHash)
+	if err != nil {
+		return nil, err
 	}
 
-	return PageParams{
-		Offset: offset,
-		Limit:  limit,
+	if err := stakerdb.ValidateNote(note); err != nil {
+		return nil, err
 	}
-}
-
-func (s *StakerService) providers(_ *rpctypes.Context, offset, limit *int) (*FinalityProvidersResponse, error) {
 
-	pageParams := getPageParams(offset, limit)
+	args := auditArgs{
+		"stakingTxHash": stakingTxHash,
+		"note":          note,
+	}
 
-	providersResp, err := s.staker.ListActiveFinalityProviders(pageParams.Limit, pageParams.Offset)
+	err = s.runAudited(ctx, "set_transaction_note", args, func() (*str.AuditOperationOutcome, error) {
+		return nil, s.staker.SetTransactionNote(txHash, note)
+	})
 
 	if err != nil {
 		return nil, err
 	}
 
-	var providerInfos []FinalityProviderInfoResponse
-
-	for _, provider := range providersResp.FinalityProviders {
-		v := FinalityProviderInfoResponse{
-			BabylonPublicKey: hex.EncodeToString(provider.BabylonPk.Key),
-			BtcPublicKey:     hex.EncodeToString(schnorr.SerializePubKey(&provider.BtcPk)),
-		}
+	return &TransactionNoteResponse{
+		StakingTxHash: stakingTxHash,
+		Note:          note,
+	}, nil
+}
 
-		providerInfos = append(providerInfos, v)
+func (s *StakerService) getTransactionNote(_ *rpctypes.Context, stakingTxHash string) (*TransactionNoteResponse, error) {
+	txHash, err := chainhash.NewHashFromStr(stakingTxHash)
+	if err != nil {
+		return nil, err
 	}
 
-	totalCount := strconv.FormatUint(providersResp.Total, 10)
+	note, err := s.staker.GetTransactionNote(txHash)
+	if err != nil {
+		return nil, err
+	}
 
-	return &FinalityProvidersResponse{
-		FinalityProviders:           providerInfos,
-		TotalFinalityProvidersCount: totalCount,
+	return &TransactionNoteResponse{
+		StakingTxHash: stakingTxHash,
+		Note:          note,
 	}, nil
 }
 
-func (s *StakerService) listStakingTransactions(_ *rpctypes.Context, offset, limit *int) (*ListStakingTransactionsResponse, error) {
-	pageParams := getPageParams(offset, limit)
-
-	txResult, err := s.staker.StoredTransactions(pageParams.Limit, pageParams.Offset)
-
+// searchTransactions returns tracked transactions, including archived ones,
+// which label or note contains query, ignoring case
+func (s *StakerService) searchTransactions(_ *rpctypes.Context, query string) (*SearchTransactionsResponse, error) {
+	matches, err := s.staker.SearchTransactions(query)
 	if err != nil {
 		return nil, err
 	}
 
-	var stakingDetails []StakingDetails
+	details := make([]TransactionSearchMatchDetails, len(matches))
 
-	for _, tx := range txResult.Transactions {
-		tx := tx
-		stakingDetails = append(stakingDetails, storedTxToStakingDetails(&tx))
+	for i, m := range matches {
+		details[i] = TransactionSearchMatchDetails{
+			StakingTxHash: m.StakingTxHash.String(),
+			Label:         m.Label,
+			Note:          m.Note,
+			Archived:      m.Archived,
+			MatchedLabel:  m.MatchedLabel,
+			MatchedNote:   m.MatchedNote,
+		}
 	}
 
-	totalCount := strconv.FormatUint(txResult.Total, 10)
-
-	return &ListStakingTransactionsResponse{
-		Transactions:          stakingDetails,
-		TotalTransactionCount: totalCount,
+	return &SearchTransactionsResponse{
+		Query:        query,
+		Transactions: details,
 	}, nil
 }
 
-func (s *StakerService) withdrawableTransactions(_ *rpctypes.Context, offset, limit *int) (*WithdrawableTransactionsResponse, error) {
-	pageParams := getPageParams(offset, limit)
-
-	txResult, err := s.staker.WithdrawableTransactions(pageParams.Limit, pageParams.Offset)
-
+// setAutoRenew enables or disables automatic renewal of tracked staking
+// transaction
+func (s *StakerService) setAutoRenew(_ *rpctypes.Context, stakingTxHash string, autoRenew bool) (*SetAutoRenewResponse, error) {
+	txHash, err := chainhash.NewHashFromStr(stakingTxHash)
 	if err != nil {
 		return nil, err
 	}
 
-	var stakingDetails []StakingDetails
-
-	for _, tx := range txResult.Transactions {
-		stakingDetails = append(stakingDetails, storedTxToStakingDetails(&tx))
+	if err := s.staker.SetAutoRenew(txHash, autoRenew); err != nil {
+		return nil, err
 	}
 
-	var lastIdx string = "0"
-	if len(stakingDetails) > 0 {
-		// this should ease up pagination i.e in case when whe have 1000 transactions, and we limit query to 50
-		// due to filetring we can retrun  response with 50 transactions when last one have index 400,
-		// then caller can specify offset=400 and get next withdrawable transactions.
-		lastIdx = stakingDetails[len(stakingDetails)-1].TransactionIdx
-	}
+	return &SetAutoRenewResponse{
+		StakingTxHash: stakingTxHash,
+		AutoRenew:     autoRenew,
+	}, nil
+}
 
-	totalCount := strconv.FormatUint(txResult.Total, 10)
+func (s *StakerService) recoveryStatus(_ *rpctypes.Context) (*RecoveryStatusResponse, error) {
+	failedChecks := s.staker.FailedStartupChecks()
 
-	return &WithdrawableTransactionsResponse{
-		Transactions:                     stakingDetails,
-		LastWithdrawableTransactionIndex: lastIdx,
-		TotalTransactionCount:            totalCount,
+	pending := make([]PendingRecoveryDetails, len(failedChecks))
+
+	for i, c := range failedChecks {
+		pending[i] = PendingRecoveryDetails{
+			StakingTxHash: c.StakingTxHash.String(),
+			StakingState:  c.State.String(),
+			Attempts:      strconv.FormatUint(uint64(c.Attempts), 10),
+			LastError:     c.LastError,
+			NextAttemptAt: c.NextAttempt.UTC().Format(time.RFC3339),
+		}
+	}
+
+	return &RecoveryStatusResponse{
+		PendingTransactions: pending,
 	}, nil
 }
 
-func decodeBtcTx(txHex string) (*wire.MsgTx, error) {
-	txBytes, err := hex.DecodeString(txHex)
+func (s *StakerService) stakingParams(_ *rpctypes.Context) (*StakingParamsResponse, error) {
+	info, err := s.staker.StakingParams()
 
 	if err != nil {
 		return nil, err
 	}
 
-	var txMsg wire.MsgTx
+	return &StakingParamsResponse{
+		ConfirmationTimeBlocks:    strconv.FormatUint(uint64(info.Params.ConfirmationTimeBlocks), 10),
+		FinalizationTimeoutBlocks: strconv.FormatUint(uint64(info.Params.FinalizationTimeoutBlocks), 10),
+		MinStakingTimeBlocks:      strconv.FormatUint(uint64(info.MinStakingTime), 10),
+		MinUnbondingTimeBlocks:    strconv.FormatUint(uint64(info.Params.MinUnbondingTime), 10),
+		MinSlashingTxFeeSat:       strconv.FormatInt(int64(info.Params.MinSlashingTxFeeSat), 10),
+		CovenantQuorum:            strconv.FormatUint(uint64(info.Params.CovenantQuruomThreshold), 10),
+		FeeRateSatPerKb:           strconv.FormatInt(int64(info.FeeRate), 10),
+		EstimatedStakingTxFeeSat:  strconv.FormatInt(int64(info.EstimatedStakingTxFee), 10),
+		ExpectedBlocksPerHour:     strconv.FormatUint(uint64(s.config.StakerConfig.BlocksPerHour), 10),
+		ActiveDelegations:         strconv.FormatUint(info.ActiveDelegations, 10),
+		MaxActiveDelegations:      strconv.FormatUint(uint64(info.MaxActiveDelegations), 10),
+		MaxStakingTimeBlocks:      strconv.FormatUint(uint64(info.MaxStakingTimeBlocks), 10),
+	}, nil
+}
 
-	err = txMsg.Deserialize(bytes.NewReader(txBytes))
+func (s *StakerService) estimateStakeFunding(
+	_ *rpctypes.Context,
+	stakerAddress string,
+	stakingAmount int64,
+	confTarget *int,
+) (*StakeFundingEstimateResponse, error) {
+	if stakingAmount <= 0 {
+		return nil, fmt.Errorf("staking amount must be positive")
+	}
+
+	stakerAddr, err := btcutil.DecodeAddress(stakerAddress, &s.config.ActiveNetParams)
 
 	if err != nil {
 		return nil, err
 	}
 
-	return &txMsg, nil
-}
-
-func decodeBtcPk(pkHex string) (*btcec.PublicKey, error) {
-	pkBytes, err := hex.DecodeString(pkHex)
+	target, err := parseConfTarget(confTarget)
 
 	if err != nil {
 		return nil, err
 	}
 
-	pk, err := schnorr.ParsePubKey(pkBytes)
+	estimate, err := s.staker.EstimateStakeFunding(stakerAddr, btcutil.Amount(stakingAmount), target)
 
 	if err != nil {
 		return nil, err
 	}
 
-	return pk, nil
+	inputs := make([]FundingInputDetails, len(estimate.Inputs))
+	for i, in := range estimate.Inputs {
+		inputs[i] = FundingInputDetails{
+			Outpoint:  in.OutPoint.String(),
+			Address:   in.Address,
+			AmountSat: strconv.FormatInt(int64(in.Amount), 10),
+		}
+	}
+
+	return &StakeFundingEstimateResponse{
+		Inputs:          inputs,
+		FeeSat:          strconv.FormatInt(int64(estimate.Fee), 10),
+		ChangeSat:       strconv.FormatInt(int64(estimate.Change), 10),
+		FeeRateSatPerKb: strconv.FormatInt(int64(estimate.FeeRate), 10),
+		MaxVsize:        strconv.FormatUint(uint64(estimate.MaxVsize), 10),
+	}, nil
 }
 
-func parseTimeBtcLock(timelockTime int) (uint16, error) {
-	if timelockTime <= 0 {
-		return 0, fmt.Errorf("staking time must be positive")
+func (s *StakerService) pendingOperations(_ *rpctypes.Context) (*PendingOperationsResponse, error) {
+	pending := s.staker.PendingOperations()
+
+	waits := make([]ConfirmationWaitDetails, len(pending.ConfirmationWaits))
+	for i, w := range pending.ConfirmationWaits {
+		waits[i] = ConfirmationWaitDetails{
+			StakingTxHash:               w.StakingTxHash.String(),
+			ConfirmationProgressDetails: confirmationProgressToDetails([]str.ConfirmationProgress{w.ConfirmationProgress})[0],
+		}
 	}
 
-	if timelockTime > math.MaxUint16 {
-		return 0, fmt.Errorf("staking time %d is too big", timelockTime)
+	loops := make([]RetryLoopDetails, len(pending.RetryLoops))
+	for i, l := range pending.RetryLoops {
+		loops[i] = RetryLoopDetails{
+			Operation:     l.Operation,
+			StakingTxHash: l.StakingTxHash.String(),
+			Attempts:      strconv.FormatUint(uint64(l.Attempts), 10),
+			LastError:     l.LastError,
+			StartedAt:     l.StartedAt.UTC().Format(time.RFC3339),
+		}
 	}
 
-	return uint16(timelockTime), nil
+	subscriptions := make([]NotificationSubscriptionDetails, len(pending.NotificationSubscriptions))
+	for i, sub := range pending.NotificationSubscriptions {
+		subscriptions[i] = NotificationSubscriptionDetails{
+			Purpose:       string(sub.Purpose),
+			TxHash:        sub.TxHash.String(),
+			StakingTxHash: sub.StakingTxHash.String(),
+			RegisteredAt:  sub.RegisteredAt.UTC().Format(time.RFC3339),
+		}
+	}
+
+	return &PendingOperationsResponse{
+		InFlightDelegations:       strconv.Itoa(pending.InFlightDelegations),
+		BackloggedDelegations:     strconv.Itoa(pending.BackloggedDelegations),
+		PendingConfRegistrations:  strconv.Itoa(pending.PendingConfRegistrations),
+		PendingStartupChecks:      strconv.Itoa(pending.PendingStartupChecks),
+		ConfirmationWaits:         waits,
+		RetryLoops:                loops,
+		NotificationSubscriptions: subscriptions,
+	}, nil
 }
 
-func parseStakingValue(stakingValue int) (btcutil.Amount, error) {
-	if stakingValue <= 0 {
-		return 0, fmt.Errorf("staking value must be positive")
+func (s *StakerService) queryAuditLog(_ *rpctypes.Context, fromSeq, limit *int) (*AuditLogResponse, error) {
+	var from uint64 = 1
+
+	if fromSeq != nil {
+		if *fromSeq < 0 {
+			return nil, fmt.Errorf("sequence number must not be negative")
+		}
+
+		from = uint64(*fromSeq)
 	}
 
-	return btcutil.Amount(stakingValue), nil
-}
+	pageParams := getPageParams(nil, limit)
 
-func (s *StakerService) watchStaking(
-	_ *rpctypes.Context,
-	stakingTx string,
-	stakingTime int,
-	stakingValue int,
-	stakerBtcPk string,
-	fpBtcPks []string,
-	slashingTx string,
-	slashingTxSig string,
-	stakerBabylonPk string,
-	stakerAddress string,
-	stakerBabylonSig string,
-	stakerBtcSig string,
-	unbondingTx string,
-	slashUnbondingTx string,
-	slashUnbondingTxSig string,
-	unbondingTime int,
-	popType int,
-) (*ResultStake, error) {
+	entries, err := s.staker.QueryAuditLog(from, pageParams.Limit)
 
-	stkTx, err := decodeBtcTx(stakingTx)
 	if err != nil {
 		return nil, err
 	}
 
-	slshTx, err := decodeBtcTx(slashingTx)
-	if err != nil {
-		return nil, err
+	details := make([]AuditLogEntryDetails, len(entries))
+
+	for i, e := range entries {
+		var requestSeq string
+		if e.RequestSeq != 0 {
+			requestSeq = strconv.FormatUint(e.RequestSeq, 10)
+		}
+
+		var txHash string
+		if e.TxHash != nil {
+			txHash = e.TxHash.String()
+		}
+
+		details[i] = AuditLogEntryDetails{
+			Seq:              strconv.FormatUint(e.Seq, 10),
+			Type:             e.Type.String(),
+			Timestamp:        e.Timestamp.UTC().Format(time.RFC3339),
+			Caller:           e.Caller,
+			Method:           e.Method,
+			Args:             e.Args,
+			RequestSeq:       requestSeq,
+			TxHash:           txHash,
+			StakingRequestId: e.StakingRequestId,
+			Error:            e.Error,
+			PrevHash:         hex.EncodeToString(e.PrevHash),
+			Hash:             hex.EncodeToString(e.Hash),
+		}
 	}
 
-	stakerBtcPkParsed, err := decodeBtcPk(stakerBtcPk)
+	nextSeq := from
+	if len(entries) > 0 {
+		nextSeq = entries[len(entries)-1].Seq + 1
+	}
+
+	return &AuditLogResponse{
+		Entries: details,
+		NextSeq: strconv.FormatUint(nextSeq, 10),
+	}, nil
+}
+
+func (s *StakerService) rescanStatus(_ *rpctypes.Context) (*RescanStatusResponse, error) {
+	status, err := s.staker.RescanStatus()
 
 	if err != nil {
 		return nil, err
 	}
 
-	var fpPubKeys []*btcec.PublicKey = make([]*btcec.PublicKey, 0)
+	rescans := make([]WalletRescanDetails, len(status.Rescans))
 
-	for _, fpPk := range fpBtcPks {
-		fpPkBytes, err := hex.DecodeString(fpPk)
-		if err != nil {
-			return nil, err
+	for i, r := range status.Rescans {
+		rescans[i] = WalletRescanDetails{
+			TxHash:      r.TxHash.String(),
+			StartHeight: strconv.FormatUint(uint64(r.StartHeight), 10),
+			StartedAt:   r.StartedAt.UTC().Format(time.RFC3339),
 		}
+	}
 
-		fpSchnorrKey, err := schnorr.ParsePubKey(fpPkBytes)
-		if err != nil {
-			return nil, err
-		}
+	resp := &RescanStatusResponse{
+		Rescans: rescans,
+	}
 
-		fpPubKeys = append(fpPubKeys, fpSchnorrKey)
+	if status.WalletProgress != nil {
+		resp.WalletProgressPercent = strconv.FormatFloat(status.WalletProgress.Progress*100, 'f', 2, 64)
+		resp.WalletRescanDuration = status.WalletProgress.Duration.String()
 	}
 
-	stakingTimeUint16, err := parseTimeBtcLock(stakingTime)
+	return resp, nil
+}
 
-	if err != nil {
-		return nil, err
+func parseHistoryTime(t *string) (time.Time, error) {
+	if t == nil || *t == "" {
+		return time.Time{}, nil
 	}
 
-	stakingValueBtc, err := parseStakingValue(stakingValue)
+	parsed, err := time.Parse(time.RFC3339, *t)
 
 	if err != nil {
-		return nil, err
+		return time.Time{}, fmt.Errorf("invalid time %s, expected RFC3339 format: %w", *t, err)
 	}
 
-	stakerAddr, err := btcutil.DecodeAddress(stakerAddress, &s.config.ActiveNetParams)
+	return parsed, nil
+}
+
+func (s *StakerService) history(_ *rpctypes.Context, from, to *string) (*HistoryResponse, error) {
+	fromTime, err := parseHistoryTime(from)
+
 	if err != nil {
 		return nil, err
 	}
 
-	slashTxSigBytes, err := hex.DecodeString(slashingTxSig)
+	toTime, err := parseHistoryTime(to)
+
 	if err != nil {
 		return nil, err
 	}
 
-	slashingTxSchnorSig, err := schnorr.ParseSignature(slashTxSigBytes)
-	if err != nil {
-		return nil, err
+	if !fromTime.IsZero() && !toTime.IsZero() && !fromTime.Before(toTime) {
+		return nil, fmt.Errorf("start of time range must be before its end")
 	}
 
-	stakerBabylonPubkeyBytes, err := hex.DecodeString(stakerBabylonPk)
+	events, err := s.staker.History(fromTime, toTime)
+
 	if err != nil {
 		return nil, err
 	}
 
-	if len(stakerBabylonPubkeyBytes) != secp256k1.PubKeySize {
-		return nil, fmt.Errorf("babylon public key must have %d bytes", secp256k1.PubKeySize)
-	}
+	details := make([]HistoryEventDetails, len(events))
 
-	stakerBabylonPubKey := secp256k1.PubKey{
-		Key: stakerBabylonPubkeyBytes,
+	for i, e := range events {
+		timeSource := "local"
+		if e.IsBlockTime {
+			timeSource = "block"
+		}
+
+		var txHash string
+		if e.TxHash != nil {
+			txHash = e.TxHash.String()
+		}
+
+		var blockHeight string
+		if e.BlockHeight != 0 {
+			blockHeight = strconv.FormatUint(uint64(e.BlockHeight), 10)
+		}
+
+		var fee string
+		if e.Fee != nil {
+			fee = strconv.FormatInt(int64(*e.Fee), 10)
+		}
+
+		details[i] = HistoryEventDetails{
+			Type:          string(e.Type),
+			StakingTxHash: e.StakingTxHash.String(),
+			TxHash:        txHash,
+			Time:          e.Time.UTC().Format(time.RFC3339),
+			TimeSource:    timeSource,
+			BlockHeight:   blockHeight,
+			Amount:        strconv.FormatInt(int64(e.Amount), 10),
+			Fee:           fee,
+		}
 	}
 
-	stakerBabylonSigBytes, err := hex.DecodeString(stakerBabylonSig)
-	if err != nil {
+	return &HistoryResponse{
+		Events: details,
+	}, nil
+}
+
+func (s *StakerService) archiveTransactions(
+	ctx *rpctypes.Context,
+	olderThan string,
+	states string,
+) (*ArchiveTransactionsResponse, error) {
+	if err := requireAuthenticated(ctx); err != nil {
 		return nil, err
 	}
 
-	stakerBtcSigBytes, err := hex.DecodeString(stakerBtcSig)
+	age, err := str.ParseArchiveAge(olderThan)
+
 	if err != nil {
 		return nil, err
 	}
 
-	btcPopType, err := babylonclient.IntToPopType(popType)
+	parsedStates, err := parseArchiveStates(states)
+
 	if err != nil {
 		return nil, err
 	}
 
-	proofOfPossesion, err := babylonclient.NewBabylonPop(btcPopType, stakerBabylonSigBytes, stakerBtcSigBytes)
-	if err != nil {
-		return nil, err
+	args := auditArgs{
+		"olderThan": olderThan,
+		"states":    states,
 	}
 
-	// Unbonding related data
-	unbTx, err := decodeBtcTx(unbondingTx)
-	if err != nil {
+	var job *stakerdb.ArchiveJob
+
+	err = s.runAudited(ctx, "archive_transactions", args, func() (*str.AuditOperationOutcome, error) {
+		var err error
+		job, err = s.staker.ArchiveTransactions(age, parsedStates)
 		return nil, err
-	}
+	})
 
-	slshUnbTx, err := decodeBtcTx(slashUnbondingTx)
 	if err != nil {
 		return nil, err
 	}
 
-	slashUnbTxSigBytes, err := hex.DecodeString(slashUnbondingTxSig)
-	if err != nil {
-		return nil, err
+	stateNames := make([]string, len(job.States))
+	for i, state := range job.States {
+		stateNames[i] = state.String()
 	}
 
-	slashUnbTxSig, err := schnorr.ParseSignature(slashUnbTxSigBytes)
+	return &ArchiveTransactionsResponse{
+		OlderThan: job.OlderThan.UTC().Format(time.RFC3339),
+		States:    stateNames,
+		Archived:  strconv.FormatUint(job.Archived, 10),
+	}, nil
+}
+
+func (s *StakerService) latencyReport(_ *rpctypes.Context, from, to *string) (*LatencyReportResponse, error) {
+	fromTime, err := parseHistoryTime(from)
+
 	if err != nil {
 		return nil, err
 	}
 
-	unbTime, err := parseTimeBtcLock(unbondingTime)
+	toTime, err := parseHistoryTime(to)
 
 	if err != nil {
 		return nil, err
 	}
 
-	hash, err := s.staker.WatchStaking(
-		stkTx,
-		stakingTimeUint16,
-		stakingValueBtc,
-		fpPubKeys,
-		slshTx,
-		slashingTxSchnorSig,
-		&stakerBabylonPubKey,
-		stakerBtcPkParsed,
-		stakerAddr,
-		proofOfPossesion,
-		unbTx,
-		slshUnbTx,
-		slashUnbTxSig,
-		unbTime,
-	)
+	if !fromTime.IsZero() && !toTime.IsZero() && !fromTime.Before(toTime) {
+		return nil, fmt.Errorf("start of time range must be before its end")
+	}
+
+	stats, err := s.staker.LatencyReport(fromTime, toTime)
+
 	if err != nil {
 		return nil, err
 	}
 
-	return &ResultStake{
-		TxHash: hash.String(),
+	details := make([]LatencyStatsDetails, len(stats))
+
+	for i, st := range stats {
+		details[i] = LatencyStatsDetails{
+			Interval:   string(st.Interval),
+			Count:      strconv.Itoa(st.Count),
+			P50Seconds: strconv.FormatFloat(st.P50.Seconds(), 'f', 0, 64),
+			P95Seconds: strconv.FormatFloat(st.P95.Seconds(), 'f', 0, 64),
+		}
+	}
+
+	return &LatencyReportResponse{
+		Intervals: details,
 	}, nil
 }
 
-func (s *StakerService) unbondStaking(_ *rpctypes.Context, stakingTxHash string, feeRate *int) (*UnbondingResponse, error) {
-	txHash, err := chainhash.NewHashFromStr(stakingTxHash)
+func (s *StakerService) debugState(_ *rpctypes.Context) (*DebugStateResponse, error) {
+	state := s.staker.DebugState()
 
-	if err != nil {
-		return nil, err
+	channels := make([]ChannelStateDetails, len(state.Channels))
+	for i, c := range state.Channels {
+		channels[i] = ChannelStateDetails{
+			Name: c.Name,
+			Len:  strconv.Itoa(c.Len),
+			Cap:  strconv.Itoa(c.Cap),
+		}
 	}
 
-	var feeRateBtc *btcutil.Amount = nil
-
-	if feeRate != nil {
-		amt := btcutil.Amount(*feeRate)
-		feeRateBtc = &amt
+	goroutines := make(map[string]string, len(state.Goroutines))
+	for purpose, n := range state.Goroutines {
+		goroutines[purpose] = strconv.Itoa(n)
 	}
 
-	unbondingTxHash, err := s.staker.UnbondStaking(*txHash, feeRateBtc)
+	ops := make([]OldestOperationDetails, len(state.OldestOperations))
+	for i, op := range state.OldestOperations {
+		ops[i] = OldestOperationDetails{
+			Category:      op.Category,
+			StakingTxHash: op.StakingTxHash.String(),
+			StartedAt:     op.StartedAt.UTC().Format(time.RFC3339),
+		}
+	}
 
-	if err != nil {
-		return nil, err
+	var lastBlockEventAt string
+	if !state.LastBlockEventAt.IsZero() {
+		lastBlockEventAt = state.LastBlockEventAt.UTC().Format(time.RFC3339)
 	}
 
-	return &UnbondingResponse{
-		UnbondingTxHash: unbondingTxHash.String(),
+	return &DebugStateResponse{
+		Channels:         channels,
+		Goroutines:       goroutines,
+		OldestOperations: ops,
+		BestBlockHeight:  strconv.FormatUint(uint64(state.BestBlockHeight), 10),
+		LastBlockEventAt: lastBlockEventAt,
 	}, nil
 }
 
 func (s *StakerService) GetRoutes() RoutesMap {
-	return RoutesMap{
+	routes := RoutesMap{
 		// info AP
-		"health": rpc.NewRPCFunc(s.health, ""),
+		"health":        rpc.NewRPCFunc(s.health, ""),
+		"state_machine": rpc.NewRPCFunc(s.stateMachine, ""),
+		// control API
+		"set_broadcast_enabled":      rpc.NewRPCFunc(s.setBroadcastEnabled, "enabled"),
+		"spend_whitelist":            rpc.NewRPCFunc(s.spendWhitelist, ""),
+		"add_whitelisted_address":    rpc.NewRPCFunc(s.addWhitelistedAddress, "address"),
+		"remove_whitelisted_address": rpc.NewRPCFunc(s.removeWhitelistedAddress, "address"),
+		"staked_value_cap":           rpc.NewRPCFunc(s.stakedValueCap, ""),
+		"override_staked_value_cap":  rpc.NewRPCFunc(s.overrideStakedValueCap, "maxValue,windowBlocks"),
 		// staking API
 		"getStakeOutput":            rpc.NewRPCFunc(s.getStakeOutput, "stakerKey,stakingAmount,fpBtcPks,stakingTimeBlocks"),
-		"stake":                     rpc.NewRPCFunc(s.stake, "stakerAddress,stakingAmount,fpBtcPks,stakingTimeBlocks"),
+		"stake":                     rpc.NewRPCFunc(s.stake, "stakerAddress,stakingAmount,fpBtcPks,stakingTimeBlocks,confTarget,stakingDuration,memo,label,requiredDepth,acknowledgeLongLock,autoRenew,freshPop,idempotencyKey"),
+		"stake_async":               rpc.NewRPCFunc(s.stakeAsync, "stakerAddress,stakingAmount,fpBtcPks,stakingTimeBlocks,confTarget,stakingDuration,memo,label,requiredDepth,acknowledgeLongLock,autoRenew,freshPop,idempotencyKey"),
+		"staking_request_status":    rpc.NewRPCFunc(s.stakingRequestStatus, "requestId"),
+		"staking_params":            rpc.NewRPCFunc(s.stakingParams, ""),
+		"estimate_stake_funding":    rpc.NewRPCFunc(s.estimateStakeFunding, "stakerAddress,stakingAmount,confTarget"),
 		"staking_details":           rpc.NewRPCFunc(s.stakingDetails, "stakingTxHash"),
-		"spend_stake":               rpc.NewRPCFunc(s.spendStake, "stakingTxHash"),
-		"list_staking_transactions": rpc.NewRPCFunc(s.listStakingTransactions, "offset,limit"),
-		"unbond_staking":            rpc.NewRPCFunc(s.unbondStaking, "stakingTxHash,feeRate"),
+		"transaction_details":       rpc.NewRPCFunc(s.transactionDetails, "stakingTxHash"),
+		"confirmation_progress":     rpc.NewRPCFunc(s.confirmationProgress, "stakingTxHash"),
+		"babylon_delegation_info":   rpc.NewRPCFunc(s.babylonDelegationInfo, "stakingTxHash"),
+		"spend_stake":               rpc.NewRPCFunc(s.spendStake, "stakingTxHash,maxFee,confTarget,idempotencyKey"),
+		"list_staking_transactions": rpc.NewRPCFunc(s.listStakingTransactions, "offset,limit,verbosity,label,state,includeArchived"),
+		"unbond_staking":            rpc.NewRPCFunc(s.unbondStaking, "stakingTxHash,feeRate,autoSweep,idempotencyKey,feeRateSatPerVbyte"),
+		"preview_unbonding":         rpc.NewRPCFunc(s.previewUnbonding, "stakingTxHash,feeRate,feeRateSatPerVbyte"),
+		"compare_exit_options":      rpc.NewRPCFunc(s.compareExitOptions, "stakingTxHash"),
 		"withdrawable_transactions": rpc.NewRPCFunc(s.withdrawableTransactions, "offset,limit"),
+		"build_delegation_msg":      rpc.NewRPCFunc(s.buildDelegationMsg, "stakingTxHash,signer"),
+		"mark_delegation_submitted": rpc.NewRPCFunc(s.markDelegationSubmitted, "stakingTxHash,babylonTxHash"),
+		"cancel_pending_delegation": rpc.NewRPCFunc(s.cancelPendingDelegation, "stakingTxHash"),
+		"set_transaction_label":     rpc.NewRPCFunc(s.setTransactionLabel, "stakingTxHash,label"),
+		"set_transaction_note":      rpc.NewRPCFunc(s.setTransactionNote, "stakingTxHash,note"),
+		"get_transaction_note":      rpc.NewRPCFunc(s.getTransactionNote, "stakingTxHash"),
+		"search_transactions":       rpc.NewRPCFunc(s.searchTransactions, "query"),
+		"set_auto_renew":            rpc.NewRPCFunc(s.setAutoRenew, "stakingTxHash,autoRenew"),
 		// watch api
-		"watch_staking_tx": rpc.NewRPCFunc(s.watchStaking, "stakingTx,stakingTime,stakingValue,stakerBtcPk,fpBtcPks,slashingTx,slashingTxSig,stakerBabylonPk,stakerAddress,stakerBabylonSig,stakerBtcSig,unbondingTx,slashUnbondingTx,slashUnbondingTxSig,unbondingTime,popType"),
+		"watch_staking_tx": rpc.NewRPCFunc(s.watchStaking, "stakingTx,stakingTime,stakingValue,stakerBtcPk,fpBtcPks,slashingTx,slashingTxSig,stakerBabylonPk,stakerAddress,stakerBabylonSig,stakerBtcSig,unbondingTx,slashUnbondingTx,slashUnbondingTxSig,unbondingTime,popType,rescanStartHeight,label,requiredDepth,inclusionHeightHint"),
 
 		// Wallet api
-		"list_outputs": rpc.NewRPCFunc(s.listOutputs, ""),
+		"list_outputs":      rpc.NewRPCFunc(s.listOutputs, ""),
+		"pending_change":    rpc.NewRPCFunc(s.pendingChange, ""),
+		"consolidate_utxos": rpc.NewRPCFunc(s.consolidateUtxos, "targetCount,feeRate"),
+		"keys":              rpc.NewRPCFunc(s.keys, ""),
 
 		// Babylon api
-		"babylon_finality_providers": rpc.NewRPCFunc(s.providers, "offset,limit"),
-	}
+		"babylon_finality_providers": rpc.NewRPCFunc(s.providers, "offset,limit,pageKey,includeSlashed"),
+		"provider_exposure":          rpc.NewRPCFunc(s.providerExposure, "fpBtcPk"),
+		"stats":                      rpc.NewRPCFunc(s.stats, ""),
+
+		// Maintenance api
+		"reconcile":             rpc.NewRPCFunc(s.reconcile, ""),
+		"reconciliation_report": rpc.NewRPCFunc(s.reconciliationReport, ""),
+		"recover_db":            rpc.NewRPCFunc(s.recoverDb, "stakerAddress,targetDbPath,dryRun,rescanStartHeight"),
+		"rescan_status":         rpc.NewRPCFunc(s.rescanStatus, ""),
+		"pending_operations":    rpc.NewRPCFunc(s.pendingOperations, ""),
+		"recovery_status":       rpc.NewRPCFunc(s.recoveryStatus, ""),
+		"audit_log":             rpc.NewRPCFunc(s.queryAuditLog, "fromSeq,limit"),
+		"history":               rpc.NewRPCFunc(s.history, "from,to"),
+		"latency_report":        rpc.NewRPCFunc(s.latencyReport, "from,to"),
+		"archive_transactions":  rpc.NewRPCFunc(s.archiveTransactions, "olderThan,states"),
+		"db_integrity":          rpc.NewRPCFunc(s.dbIntegrity, ""),
+		"repair_staking_output": rpc.NewRPCFunc(s.repairStakingOutput, "stakingTxHash"),
+
+		"retry_unbonding_signatures": rpc.NewRPCFunc(s.retryUnbondingSignatures, "stakingTxHash"),
+		"abort_unbonding":            rpc.NewRPCFunc(s.abortUnbonding, "stakingTxHash"),
+	}
+
+	// Debug api
+	if s.config.JsonRpcServerConfig.DebugRpcEnabled {
+		routes["debug_state"] = rpc.NewRPCFunc(s.debugState, "")
+	}
+
+	return routes
 }
 
 func (s *StakerService) RunUntilShutdown() error {
@@ -649,6 +2808,8 @@ func (s *StakerService) RunUntilShutdown() error {
 
 	err := s.staker.Start()
 	if err != nil {
+		// release whatever was acquired before start failed
+		_ = s.staker.Stop()
 		return mkErr("error starting staker: %w", err)
 	}
 
@@ -664,22 +2825,21 @@ func (s *StakerService) RunUntilShutdown() error {
 	// TODO: investigate if we can use logrus directly to pass it to rpcserver
 	rpcLogger := log.NewTMLogger(s.logger.Writer())
 
-	listeners := make([]net.Listener, len(s.config.RpcListeners))
-	for i, listenAddr := range s.config.RpcListeners {
-		listenAddressStr := listenAddr.Network() + "://" + listenAddr.String()
+	for _, rpcListener := range s.rpcListeners() {
+		rpcListener := rpcListener
+		listenAddressStr := rpcListener.address()
 		mux := http.NewServeMux()
 		rpc.RegisterRPCFuncs(mux, routes, rpcLogger)
+		handler := s.handler(&rpcListener, mux)
 
-		listener, err := rpc.Listen(
-			listenAddressStr,
-			config.MaxOpenConnections,
-		)
+		listener, err := s.listen(&rpcListener, config.MaxOpenConnections)
 
 		if err != nil {
 			return mkErr("unable to listen on %s: %v",
 				listenAddressStr, err)
 		}
 
+		// closing unix socket listener also removes its socket file
 		defer func() {
 			err := listener.Close()
 			if err != nil {
@@ -688,22 +2848,64 @@ func (s *StakerService) RunUntilShutdown() error {
 		}()
 
 		// Start standard HTTP server serving json-rpc
-		// TODO: Add additional middleware, like CORS, TLS, etc.
+		// TODO: Add additional middleware, like CORS, etc.
 		// TODO: Consider we need some websockets for some notications
 		go func() {
 			s.logger.Debug("Starting Json RPC HTTP server ", "address", listenAddressStr)
 
-			err := rpc.Serve(
-				listener,
-				mux,
-				rpcLogger,
-				config,
-			)
+			var err error
+			if rpcListener.useTLS {
+				err = rpc.ServeTLS(
+					listener,
+					handler,
+					s.config.JsonRpcServerConfig.TLSCertPath,
+					s.config.JsonRpcServerConfig.TLSKeyPath,
+					rpcLogger,
+					config,
+				)
+			} else {
+				err = rpc.Serve(
+					listener,
+					handler,
+					rpcLogger,
+					config,
+				)
+			}
 
 			s.logger.Error("Json RPC HTTP server stopped ", "err", err)
 		}()
+	}
+
+	if s.config.DashboardListener != nil {
+		listener, err := s.startDashboard(routes, rpcLogger, config)
+
+		if err != nil {
+			return mkErr("unable to start dashboard on %s: %v",
+				s.config.DashboardListener, err)
+		}
+
+		defer func() {
+			err := listener.Close()
+			if err != nil {
+				s.logger.Error("Error closing dashboard listener", "err", err)
+			}
+		}()
+	}
+
+	if s.config.MetricsListener != nil {
+		listener, err := s.startMetrics(rpcLogger, config)
+
+		if err != nil {
+			return mkErr("unable to start metrics on %s: %v",
+				s.config.MetricsListener, err)
+		}
 
-		listeners[i] = listener
+		defer func() {
+			err := listener.Close()
+			if err != nil {
+				s.logger.Error("Error closing metrics listener", "err", err)
+			}
+		}()
 	}
 
 	s.logger.Info("Staker Service fully started")
@@ -714,5 +2916,8 @@ func (s *StakerService) RunUntilShutdown() error {
 
 	s.logger.Info("Received shutdown signal. Stopping...")
 
+	// log what is going to be interrupted, before stopping staker cancels it
+	s.staker.LogPendingOperations()
+
 	return nil
 }
@@ -1,21 +1,245 @@
 package stakerservice
 
-type ResultHealth struct{}
+type ResultHealth struct {
+	// true if operator paused sending transactions to btc
+	BroadcastsPaused bool `json:"broadcasts_paused"`
+	// last known balance of babylon account paying fees, empty if not known yet
+	BabylonBalance             string `json:"babylon_balance,omitempty"`
+	BabylonBalanceCheckedAt    string `json:"babylon_balance_checked_at,omitempty"`
+	BabylonLowBalanceThreshold string `json:"babylon_low_balance_threshold"`
+	BabylonBalanceLow          bool   `json:"babylon_balance_low"`
+	// delegations waiting until babylon account is funded
+	ParkedDelegations []ParkedDelegation `json:"parked_delegations"`
+	// circuit breaker guarding sending of transactions to babylon
+	BabylonCircuit BabylonCircuitDetails `json:"babylon_circuit"`
+	// sync status of btc wallet, stakes and spends are rejected until it is synced
+	WalletSync WalletSyncDetails `json:"wallet_sync"`
+}
+
+type WalletSyncDetails struct {
+	Synced bool `json:"synced"`
+	// empty if wallet sync status could not be retrieved
+	WalletBestBlockHeight string `json:"wallet_best_block_height,omitempty"`
+	WalletBestBlockHash   string `json:"wallet_best_block_hash,omitempty"`
+	NodeBestBlockHeight   string `json:"node_best_block_height,omitempty"`
+	InitialBlockDownload  bool   `json:"initial_block_download"`
+	Rescanning            bool   `json:"rescanning"`
+	// reason why wallet is not synced, or error of sync status query
+	Error string `json:"error,omitempty"`
+}
+
+type BabylonCircuitDetails struct {
+	// closed, open or half-open
+	State               string `json:"state"`
+	ConsecutiveFailures string `json:"consecutive_failures"`
+	TotalFailures       string `json:"total_failures"`
+	// empty if circuit was never opened
+	LastOpenedAt string `json:"last_opened_at,omitempty"`
+}
+
+type ParkedDelegation struct {
+	StakingTxHash string `json:"staking_tx_hash"`
+	Reason        string `json:"reason"`
+}
+
+type BroadcastStatusResponse struct {
+	BroadcastsPaused bool `json:"broadcasts_paused"`
+}
+
+type WhitelistedAddressDetails struct {
+	Address string `json:"address"`
+	// config or db
+	Source string `json:"source"`
+}
+
+type SpendWhitelistResponse struct {
+	Addresses []WhitelistedAddressDetails `json:"addresses"`
+}
+
+type StakedValueCapResponse struct {
+	// amounts in satoshis, cap is 0 if disabled
+	Cap          string `json:"cap"`
+	WindowBlocks string `json:"window_blocks"`
+	// value of staking transactions sent within the window
+	InWindow string `json:"in_window"`
+	// true if cap was overridden by operator, until daemon restarts
+	Overridden bool `json:"overridden"`
+	// stakes rejected since daemon start
+	Rejections string `json:"rejections"`
+}
 
 type ResultStake struct {
 	TxHash string `json:"tx_hash"`
+	// true if transaction was sent, but staker is still retrying registration
+	// for its confirmation. Staking request must not be repeated in that case.
+	ConfirmationRegistrationPending bool   `json:"confirmation_registration_pending,omitempty"`
+	StakingTimeBlocks               string `json:"staking_time_blocks"`
+	// estimated assuming staking transaction is included in the next block
+	ApproximateEndTime string `json:"approximate_end_time"`
+	// finality provider keys in canonical x-only form
+	FinalityProviderPks []string `json:"finality_provider_pks"`
+}
+
+type ResultStakeAsync struct {
+	RequestId          string `json:"request_id"`
+	StakingTimeBlocks  string `json:"staking_time_blocks"`
+	ApproximateEndTime string `json:"approximate_end_time"`
+	// finality provider keys in canonical x-only form
+	FinalityProviderPks []string `json:"finality_provider_pks"`
+}
+
+type StakingRequestStatusResponse struct {
+	RequestId string `json:"request_id"`
+	Status    string `json:"status"`
+	TxHash    string `json:"tx_hash,omitempty"`
+	Error     string `json:"error,omitempty"`
+	UpdatedAt string `json:"updated_at"`
 }
 
 type ResultStakeOutput struct {
 	OutputAddress string `json:"output_address"`
+	// finality provider keys in canonical x-only form
+	FinalityProviderPks []string `json:"finality_provider_pks"`
+}
+
+type TxLabelDetails struct {
+	TxHash string `json:"tx_hash"`
+	Label  string `json:"label"`
+}
+
+type BuildDelegationMsgResponse struct {
+	StakingTxHash               string `json:"staking_tx_hash"`
+	DelegationMsg               string `json:"delegation_msg"`
+	StakingTxInclusionBlockHash string `json:"staking_tx_inclusion_block_hash"`
+	StakingTxInclusionProof     string `json:"staking_tx_inclusion_proof"`
+	SlashingTx                  string `json:"slashing_tx"`
+	SlashingTxSig               string `json:"slashing_tx_sig"`
+	PopBtcSigType               string `json:"pop_btc_sig_type"`
+	PopBabylonSig               string `json:"pop_babylon_sig"`
+	PopBtcSig                   string `json:"pop_btc_sig"`
+}
+
+type MarkDelegationSubmittedResponse struct {
+	StakingTxHash string `json:"staking_tx_hash"`
+}
+
+type CancelPendingDelegationResponse struct {
+	StakingTxHash string `json:"staking_tx_hash"`
+	StakingState  string `json:"staking_state"`
+	// height of the first block which can include withdrawal of staked funds
+	WithdrawableHeight string `json:"withdrawable_height,omitempty"`
+}
+
+type ChangeOutputDetails struct {
+	OutputIdx string `json:"output_idx"`
+	Amount    string `json:"amount"`
+	Address   string `json:"address"`
+}
+
+type PendingChangeDetails struct {
+	StakingTxHash string              `json:"staking_tx_hash"`
+	Change        ChangeOutputDetails `json:"change"`
+}
+
+type PendingChangeResponse struct {
+	TotalPendingChange string                 `json:"total_pending_change"`
+	Transactions       []PendingChangeDetails `json:"transactions"`
+}
+
+type ConsolidateUtxosResponse struct {
+	TxHash    string `json:"tx_hash"`
+	NumInputs string `json:"num_inputs"`
+	Amount    string `json:"amount"`
+	Fee       string `json:"fee"`
 }
 
 type StakingDetails struct {
-	StakingTxHash  string `json:"staking_tx_hash"`
-	StakerAddress  string `json:"staker_address"`
-	StakingState   string `json:"staking_state"`
-	Watched        bool   `json:"watched"`
-	TransactionIdx string `json:"transaction_idx"`
+	StakingTxHash  string           `json:"staking_tx_hash"`
+	StakerAddress  string           `json:"staker_address"`
+	StakingState   string           `json:"staking_state"`
+	Watched        bool             `json:"watched"`
+	TransactionIdx string           `json:"transaction_idx"`
+	TxLabels       []TxLabelDetails `json:"tx_labels,omitempty"`
+	// label assigned by user, empty if delegation is not labeled
+	Label string `json:"label,omitempty"`
+	// reason why record is flagged as corrupted, empty if it is not corrupted
+	CorruptionReason string `json:"corruption_reason,omitempty"`
+	AutoRenew        bool   `json:"auto_renew,omitempty"`
+	// reason why automatic renewal was skipped, empty if it was not skipped
+	AutoRenewSkipReason string `json:"auto_renew_skip_reason,omitempty"`
+	// renewal transaction which spent staking output, and staking transaction
+	// renewed by this one
+	RenewalTxHash     string `json:"renewal_tx_hash,omitempty"`
+	RenewedFromTxHash string `json:"renewed_from_tx_hash,omitempty"`
+	// fee estimates used to build transactions, empty if they were not recorded
+	StakingFeeEstimate   *FeeEstimateDetails `json:"staking_fee_estimate,omitempty"`
+	UnbondingFeeEstimate *FeeEstimateDetails `json:"unbonding_fee_estimate,omitempty"`
+	SpendFeeEstimate     *FeeEstimateDetails `json:"spend_fee_estimate,omitempty"`
+	// last rejection of delegation by babylon, empty if delegation was not
+	// rejected or was accepted afterwards
+	BabylonSubmitFailure *BabylonSubmitFailureDetails `json:"babylon_submit_failure,omitempty"`
+	// true if operator aborted waiting for covenant unbonding signatures
+	UnbondingWaitAborted bool `json:"unbonding_wait_aborted,omitempty"`
+	// action which owner of watched transaction must take, as staker cannot
+	// sign for it. Empty if staker progresses transaction on its own
+	ExternalAction string `json:"external_action,omitempty"`
+	// archived transactions are immutable and are only listed on request
+	Archived bool `json:"archived,omitempty"`
+	// transaction which double spent input of staking transaction, empty if
+	// staking transaction is not conflicted
+	ConflictingTxHash string `json:"conflicting_tx_hash,omitempty"`
+	// change output of staking transaction, empty if transaction has no change
+	Change *ChangeOutputDetails `json:"change,omitempty"`
+	// confirmation waits in progress, only filled in staking details response
+	ConfirmationProgress []ConfirmationProgressDetails `json:"confirmation_progress,omitempty"`
+	// raw transaction details, only filled in verbose responses
+	StakingTxHex        string `json:"staking_tx_hex,omitempty"`
+	StakingScriptHex    string `json:"staking_script_hex,omitempty"`
+	StakingOutputIdx    string `json:"staking_output_idx,omitempty"`
+	StakingValue        string `json:"staking_value,omitempty"`
+	UnbondingTxHex      string `json:"unbonding_tx_hex,omitempty"`
+	UnbondingScriptHex  string `json:"unbonding_script_hex,omitempty"`
+	StakerBabylonPubKey string `json:"staker_babylon_pub_key,omitempty"`
+}
+
+type ConfirmationProgressDetails struct {
+	// one of: staking, unbonding, spend
+	Type                  string `json:"type"`
+	TxHash                string `json:"tx_hash"`
+	Confirmations         string `json:"confirmations"`
+	RequiredConfirmations string `json:"required_confirmations"`
+	LastUpdate            string `json:"last_update"`
+}
+
+type ConfirmationProgressResponse struct {
+	StakingTxHash string                        `json:"staking_tx_hash"`
+	Progress      []ConfirmationProgressDetails `json:"progress"`
+}
+
+type BabylonUndelegationDetails struct {
+	UnbondingTxHash             string `json:"unbonding_tx_hash"`
+	UnbondingTimeBlocks         string `json:"unbonding_time_blocks"`
+	CovenantUnbondingSignatures string `json:"covenant_unbonding_signatures"`
+}
+
+type BabylonDelegationDetails struct {
+	Status             string `json:"status"`
+	Active             bool   `json:"active"`
+	StartHeight        string `json:"start_height"`
+	EndHeight          string `json:"end_height"`
+	TotalSat           string `json:"total_sat"`
+	CovenantSignatures string `json:"covenant_signatures"`
+	// nil if babylon does not have undelegation data of the delegation
+	Undelegation *BabylonUndelegationDetails `json:"undelegation,omitempty"`
+}
+
+type BabylonDelegationInfoResponse struct {
+	StakingTxHash string `json:"staking_tx_hash"`
+	// empty if transaction is not tracked by staker
+	LocalState     string `json:"local_state,omitempty"`
+	FoundOnBabylon bool   `json:"found_on_babylon"`
+	// only set if delegation is found on babylon
+	Delegation *BabylonDelegationDetails `json:"delegation,omitempty"`
 }
 
 type OutputDetail struct {
@@ -26,9 +250,29 @@ type OutputDetail struct {
 type OutputsResponse struct {
 	Outputs []OutputDetail `json:"outputs"`
 }
+
+type WalletAddressDetail struct {
+	Address string `json:"address"`
+	// false for watch-only addresses, for which wallet cannot sign
+	Spendable bool `json:"spendable"`
+}
+
+type KeysResponse struct {
+	BabylonKeyName string `json:"babylon_key_name"`
+	// Hex encoded Babylon public secp256k1 key in compressed format
+	BabylonPublicKey string `json:"babylon_public_key"`
+	BabylonAddress   string `json:"babylon_address"`
+	// true if daemon runs without btc wallet, wallet fields are empty then
+	WalletDisabled  bool                  `json:"wallet_disabled"`
+	WalletLocked    bool                  `json:"wallet_locked"`
+	WalletAddresses []WalletAddressDetail `json:"wallet_addresses"`
+}
 type SpendTxDetails struct {
 	TxHash  string `json:"tx_hash"`
 	TxValue string `json:"tx_value"`
+	// true if transaction was sent, but staker is still retrying registration
+	// for its confirmation
+	ConfirmationRegistrationPending bool `json:"confirmation_registration_pending,omitempty"`
 }
 
 type FinalityProviderInfoResponse struct {
@@ -36,11 +280,155 @@ type FinalityProviderInfoResponse struct {
 	BabylonPublicKey string `json:"babylon_public_Key"`
 	// Hex encoded Bitcoin public secp256k1 key in BIP340 format
 	BtcPublicKey string `json:"bitcoin_public_Key"`
+	Moniker      string `json:"moniker,omitempty"`
+	Commission   string `json:"commission"`
+	// active or slashed
+	Status string `json:"status"`
 }
 
 type FinalityProvidersResponse struct {
-	FinalityProviders           []FinalityProviderInfoResponse `json:"finality_providers"`
-	TotalFinalityProvidersCount string                         `json:"total_finality_providers_count"`
+	FinalityProviders []FinalityProviderInfoResponse `json:"finality_providers"`
+	// counted only when finality providers are queried by offset
+	TotalFinalityProvidersCount string `json:"total_finality_providers_count"`
+	// base64 encoded key of the next page, empty if there are no more finality
+	// providers
+	NextPageKey string `json:"next_page_key,omitempty"`
+}
+
+type ProviderExposureDetails struct {
+	// Hex encoded Bitcoin public secp256k1 key in BIP340 format
+	BtcPublicKey string `json:"bitcoin_public_key"`
+	// empty if finality provider is not known to babylon or did not register moniker
+	Moniker string `json:"moniker,omitempty"`
+	// sum of staked amounts in satoshis
+	TotalStaked    string `json:"total_staked"`
+	NumDelegations string `json:"num_delegations"`
+}
+
+type ProviderExposureResponse struct {
+	Providers []ProviderExposureDetails `json:"providers"`
+}
+
+type StateStatsDetails struct {
+	State string `json:"state"`
+	Count string `json:"count"`
+	// empty if there are no transactions in this state, for terminal states and
+	// if time of state change is not known
+	OldestSince      string `json:"oldest_since,omitempty"`
+	OldestAgeSeconds string `json:"oldest_age_seconds,omitempty"`
+}
+
+type DaemonStatsResponse struct {
+	States []StateStatsDetails `json:"states"`
+	// archived transactions, only states with archived transactions are listed
+	Archived []StateStatsDetails `json:"archived"`
+	// amounts in satoshis
+	LockedInStaking   string `json:"locked_in_staking"`
+	LockedInUnbonding string `json:"locked_in_unbonding"`
+	Withdrawn         string `json:"withdrawn"`
+	// amounts and ages are refreshed every few seconds
+	UpdatedAt string `json:"updated_at"`
+	// babylon transactions sent since daemon start
+	BabylonDelegationTxs BabylonBatchStatsDetails `json:"babylon_delegation_txs"`
+	// stakes rejected by staked value cap since daemon start
+	StakedValueCapRejections string `json:"staked_value_cap_rejections"`
+	// fee estimates made during the last 24 hours, oldest first
+	FeeEstimates []FeeEstimateDetails `json:"fee_estimates"`
+}
+
+type FeeEstimateDetails struct {
+	Estimator  string `json:"estimator"`
+	ConfTarget string `json:"conf_target"`
+	// fee rates in sat/kvB. Fallback fee rate is configured rate used instead
+	// of estimated one, empty if estimated fee rate was used
+	FeeRate          string `json:"fee_rate"`
+	EstimatedFeeRate string `json:"estimated_fee_rate"`
+	FallbackFeeRate  string `json:"fallback_fee_rate,omitempty"`
+	EstimatedAt      string `json:"estimated_at"`
+}
+
+// BabylonSubmitFailureDetails is the last rejection of delegation by babylon,
+// with raw ABCI error returned by babylon node
+type BabylonSubmitFailureDetails struct {
+	Operation string `json:"operation"`
+	Codespace string `json:"codespace"`
+	Code      string `json:"code"`
+	RawLog    string `json:"raw_log"`
+	// true if babylon transaction failed execution in block, false if it was
+	// rejected before entering mempool
+	Executed bool `json:"executed"`
+	// retryable, terminal or unknown if code is not known to staker
+	Class         string `json:"class"`
+	BabylonTxHash string `json:"babylon_tx_hash,omitempty"`
+	BabylonHeight string `json:"babylon_height,omitempty"`
+	FailedAt      string `json:"failed_at"`
+}
+
+type BabylonBatchStatsDetails struct {
+	Transactions string `json:"transactions"`
+	Delegations  string `json:"delegations"`
+	// average number of delegations in single babylon transaction
+	AverageBatchSize string `json:"average_batch_size"`
+	// batches which failed and were resent as individual transactions
+	FallbackBatches string `json:"fallback_batches"`
+}
+
+type StateMachineStateDetails struct {
+	State string `json:"state"`
+	// true if transaction cannot leave this state
+	Terminal bool `json:"terminal"`
+	// states to which transaction can move from this state
+	Transitions []string `json:"transitions"`
+	// state to which operator can revert transaction, empty if it cannot be
+	// reverted
+	RevertsTo string `json:"reverts_to,omitempty"`
+}
+
+type SetTransactionLabelResponse struct {
+	StakingTxHash string `json:"staking_tx_hash"`
+	// empty if label was removed
+	Label string `json:"label"`
+}
+
+type TransactionNoteResponse struct {
+	StakingTxHash string `json:"staking_tx_hash"`
+	// empty if transaction has no note
+	Note string `json:"note"`
+}
+
+type TransactionSearchMatchDetails struct {
+	StakingTxHash string `json:"staking_tx_hash"`
+	Label         string `json:"label,omitempty"`
+	Note          string `json:"note,omitempty"`
+	Archived      bool   `json:"archived,omitempty"`
+	MatchedLabel  bool   `json:"matched_label"`
+	MatchedNote   bool   `json:"matched_note"`
+}
+
+type SearchTransactionsResponse struct {
+	Query        string                          `json:"query"`
+	Transactions []TransactionSearchMatchDetails `json:"transactions"`
+}
+
+type SetAutoRenewResponse struct {
+	StakingTxHash string `json:"staking_tx_hash"`
+	AutoRenew     bool   `json:"auto_renew"`
+}
+
+type RetryUnbondingSignaturesResponse struct {
+	StakingTxHash string `json:"staking_tx_hash"`
+	StakingState  string `json:"staking_state"`
+}
+
+type AbortUnbondingResponse struct {
+	StakingTxHash string `json:"staking_tx_hash"`
+	StakingState  string `json:"staking_state"`
+}
+
+type StateMachineResponse struct {
+	// state in which staking transactions start to be tracked
+	InitialState string                     `json:"initial_state"`
+	States       []StateMachineStateDetails `json:"states"`
 }
 
 type ListStakingTransactionsResponse struct {
@@ -48,8 +436,119 @@ type ListStakingTransactionsResponse struct {
 	TotalTransactionCount string           `json:"total_transaction_count"`
 }
 
+type TransactionDetailsResponse struct {
+	StakingTxHash    string `json:"staking_tx_hash"`
+	StakingState     string `json:"staking_state"`
+	Watched          bool   `json:"watched"`
+	StakerAddress    string `json:"staker_address"`
+	StakingValue     string `json:"staking_value"`
+	StakingOutputIdx string `json:"staking_output_idx"`
+	// change output of staking transaction, empty if transaction has no change
+	Change            *ChangeOutputDetails         `json:"change,omitempty"`
+	FinalityProviders []FinalityProviderKeyDetails `json:"finality_providers"`
+	// empty until staking transaction is confirmed on btc
+	Confirmation *BtcConfirmationDetails `json:"confirmation,omitempty"`
+	// empty if delegation was not sent to babylon by staker
+	BabylonTxHash string `json:"babylon_tx_hash,omitempty"`
+	// true if delegation was not sent to babylon, because daemon runs in babylon
+	// dry run mode
+	BabylonDryRun bool `json:"babylon_dry_run,omitempty"`
+	// memo of babylon transaction, or requested memo if delegation was not
+	// sent yet
+	BabylonMemo string `json:"babylon_memo,omitempty"`
+	// last rejection of delegation by babylon, empty if delegation was not
+	// rejected or was accepted afterwards
+	BabylonSubmitFailure *BabylonSubmitFailureDetails `json:"babylon_submit_failure,omitempty"`
+	// label assigned by user, empty if delegation is not labeled
+	Label string `json:"label,omitempty"`
+	// note of operator, empty if delegation has no note
+	Note string `json:"note,omitempty"`
+	// reason why record is flagged as corrupted, empty if it is not corrupted.
+	// Staking output of corrupted transaction is not spent until it is repaired
+	CorruptionReason string            `json:"corruption_reason,omitempty"`
+	Unbonding        *UnbondingDetails `json:"unbonding,omitempty"`
+	// height of the first block which can include withdrawal of staked funds,
+	// empty if funds are not locked in confirmed staking or unbonding output
+	WithdrawableHeight string `json:"withdrawable_height,omitempty"`
+	// only filled if current btc height is known
+	Withdrawable     *bool                `json:"withdrawable,omitempty"`
+	CurrentBtcHeight string               `json:"current_btc_height,omitempty"`
+	StakingScript    StakingScriptDetails `json:"staking_script"`
+}
+
+type FinalityProviderKeyDetails struct {
+	BtcPk string `json:"btc_pk"`
+	// empty if not known
+	Moniker string `json:"moniker,omitempty"`
+}
+
+type BtcConfirmationDetails struct {
+	BlockHash   string `json:"block_hash"`
+	BlockHeight string `json:"block_height"`
+}
+
+type UnbondingDetails struct {
+	UnbondingTxHash     string `json:"unbonding_tx_hash"`
+	UnbondingValue      string `json:"unbonding_value"`
+	UnbondingTimeBlocks string `json:"unbonding_time_blocks"`
+	CovenantSignatures  string `json:"covenant_signatures"`
+	// empty until unbonding transaction is confirmed on btc
+	Confirmation *BtcConfirmationDetails `json:"confirmation,omitempty"`
+}
+
+// StakingScriptDetails are values committed to in staking output script
+type StakingScriptDetails struct {
+	PkScriptHex string `json:"pk_script_hex"`
+	// empty if staker key is not known
+	StakerBtcPk         string   `json:"staker_btc_pk,omitempty"`
+	FinalityProviderPks []string `json:"finality_provider_pks"`
+	StakingTimeBlocks   string   `json:"staking_time_blocks"`
+	ParamsVersion       string   `json:"params_version"`
+	CovenantPks         []string `json:"covenant_pks,omitempty"`
+	CovenantQuorum      string   `json:"covenant_quorum,omitempty"`
+	// whether script rebuilt from above values matches staking output, empty if
+	// it could not be checked
+	Verified *bool `json:"verified,omitempty"`
+}
+
 type UnbondingResponse struct {
 	UnbondingTxHash string `json:"unbonding_tx_hash"`
+	// fee paid by unbonding transaction and its fee rate in both units. Fee is
+	// fixed when delegation is sent to babylon
+	UnbondingFee       string `json:"unbonding_fee"`
+	FeeRateSatPerKb    string `json:"fee_rate_sat_per_kb"`
+	FeeRateSatPerVbyte string `json:"fee_rate_sat_per_vbyte"`
+	// set if requested fee rate was ignored
+	Warning string `json:"warning,omitempty"`
+}
+
+type UnbondingPreviewResponse struct {
+	UnbondingValue          string `json:"unbonding_value"`
+	UnbondingFee            string `json:"unbonding_fee"`
+	UnbondingFeeFixed       bool   `json:"unbonding_fee_fixed"`
+	WithdrawalFee           string `json:"withdrawal_fee"`
+	NetAmount               string `json:"net_amount"`
+	UnbondingTimeBlocks     string `json:"unbonding_time_blocks"`
+	ConfirmationBlocks      string `json:"confirmation_blocks"`
+	CovenantSigningEstimate string `json:"covenant_signing_estimate"`
+	ConfirmationEstimate    string `json:"confirmation_estimate"`
+	SpendableEstimate       string `json:"spendable_estimate"`
+}
+
+type ExpiryExitOptionDetails struct {
+	StakingTxConfirmed bool   `json:"staking_tx_confirmed"`
+	RemainingBlocks    string `json:"remaining_blocks"`
+	RemainingEstimate  string `json:"remaining_estimate"`
+	WithdrawalFee      string `json:"withdrawal_fee"`
+	NetAmount          string `json:"net_amount"`
+}
+
+type CompareExitOptionsResponse struct {
+	StakingTxHash string                  `json:"staking_tx_hash"`
+	StakingValue  string                  `json:"staking_value"`
+	Expiry        ExpiryExitOptionDetails `json:"expiry"`
+	// nil if delegation cannot be unbonded anymore
+	Unbonding *UnbondingPreviewResponse `json:"unbonding,omitempty"`
 }
 
 type WithdrawableTransactionsResponse struct {
@@ -57,3 +556,227 @@ type WithdrawableTransactionsResponse struct {
 	LastWithdrawableTransactionIndex string           `json:"last_transaction_index"`
 	TotalTransactionCount            string           `json:"total_transaction_count"`
 }
+
+type ReconciliationDiscrepancyDetails struct {
+	StakingTxHash   string `json:"staking_tx_hash"`
+	LocalState      string `json:"local_state"`
+	DiscrepancyType string `json:"discrepancy_type"`
+	Description     string `json:"description"`
+}
+
+type ReconciliationReportResponse struct {
+	CreatedAt                   string                             `json:"created_at"`
+	CheckedTransactionsCount    string                             `json:"checked_transactions_count"`
+	MissingOnBabylonCount       string                             `json:"missing_on_babylon_count"`
+	StatusMismatchCount         string                             `json:"status_mismatch_count"`
+	UndelegationNotTrackedCount string                             `json:"undelegation_not_tracked_count"`
+	DelegationExpiredCount      string                             `json:"delegation_expired_count"`
+	Discrepancies               []ReconciliationDiscrepancyDetails `json:"discrepancies"`
+}
+
+type DbIntegrityIssueDetails struct {
+	StakingTxHash string `json:"staking_tx_hash"`
+	StakingState  string `json:"staking_state"`
+	Corrupted     bool   `json:"corrupted"`
+	Description   string `json:"description"`
+}
+
+type DbIntegrityResponse struct {
+	IssuesCount string                    `json:"issues_count"`
+	Issues      []DbIntegrityIssueDetails `json:"issues"`
+}
+
+type RepairStakingOutputResponse struct {
+	StakingTxHash    string `json:"staking_tx_hash"`
+	StakingOutputIdx string `json:"staking_output_idx"`
+}
+
+type RecoveredTransactionDetails struct {
+	StakingTxHash string `json:"staking_tx_hash"`
+	StakingState  string `json:"staking_state"`
+}
+
+type SkippedDelegationDetails struct {
+	StakingTxHash string `json:"staking_tx_hash"`
+	Reason        string `json:"reason"`
+}
+
+type RecoverDbResponse struct {
+	DryRun bool `json:"dry_run"`
+	// empty in dry run
+	TargetDbPath string                        `json:"target_db_path"`
+	Recovered    []RecoveredTransactionDetails `json:"recovered"`
+	Skipped      []SkippedDelegationDetails    `json:"skipped"`
+}
+
+type StakingParamsResponse struct {
+	ConfirmationTimeBlocks    string `json:"confirmation_time_blocks"`
+	FinalizationTimeoutBlocks string `json:"finalization_timeout_blocks"`
+	MinStakingTimeBlocks      string `json:"min_staking_time_blocks"`
+	MinUnbondingTimeBlocks    string `json:"min_unbonding_time_blocks"`
+	MinSlashingTxFeeSat       string `json:"min_slashing_tx_fee_sat"`
+	CovenantQuorum            string `json:"covenant_quorum"`
+	FeeRateSatPerKb           string `json:"fee_rate_sat_per_kb"`
+	// estimate for staking transaction funded by single wallet output
+	EstimatedStakingTxFeeSat string `json:"estimated_staking_tx_fee_sat"`
+	// used to convert staking durations to blocks
+	ExpectedBlocksPerHour string `json:"expected_blocks_per_hour"`
+	// new stakes are rejected once active delegations reach the maximum
+	ActiveDelegations    string `json:"active_delegations"`
+	MaxActiveDelegations string `json:"max_active_delegations"`
+	// stakes above the limit must acknowledge long lock of funds, 0 if disabled
+	MaxStakingTimeBlocks string `json:"max_staking_time_blocks"`
+}
+
+type FundingInputDetails struct {
+	Outpoint  string `json:"outpoint"`
+	Address   string `json:"address"`
+	AmountSat string `json:"amount_sat"`
+}
+
+type StakeFundingEstimateResponse struct {
+	// wallet utxos which would fund staking transaction
+	Inputs []FundingInputDetails `json:"inputs"`
+	FeeSat string                `json:"fee_sat"`
+	// 0 if change would be dust and is left to fee
+	ChangeSat       string `json:"change_sat"`
+	FeeRateSatPerKb string `json:"fee_rate_sat_per_kb"`
+	// upper bound of virtual size of signed staking transaction
+	MaxVsize string `json:"max_vsize"`
+}
+
+type ConfirmationWaitDetails struct {
+	StakingTxHash string `json:"staking_tx_hash"`
+	ConfirmationProgressDetails
+}
+
+type RetryLoopDetails struct {
+	Operation     string `json:"operation"`
+	StakingTxHash string `json:"staking_tx_hash"`
+	Attempts      string `json:"attempts"`
+	LastError     string `json:"last_error,omitempty"`
+	StartedAt     string `json:"started_at"`
+}
+
+type NotificationSubscriptionDetails struct {
+	Purpose       string `json:"purpose"`
+	TxHash        string `json:"tx_hash"`
+	StakingTxHash string `json:"staking_tx_hash"`
+	RegisteredAt  string `json:"registered_at"`
+}
+
+type PendingOperationsResponse struct {
+	InFlightDelegations       string                            `json:"in_flight_delegations"`
+	BackloggedDelegations     string                            `json:"backlogged_delegations"`
+	PendingConfRegistrations  string                            `json:"pending_conf_registrations"`
+	PendingStartupChecks      string                            `json:"pending_startup_checks"`
+	ConfirmationWaits         []ConfirmationWaitDetails         `json:"confirmation_waits"`
+	RetryLoops                []RetryLoopDetails                `json:"retry_loops"`
+	NotificationSubscriptions []NotificationSubscriptionDetails `json:"notification_subscriptions"`
+}
+
+type ChannelStateDetails struct {
+	Name string `json:"name"`
+	Len  string `json:"len"`
+	Cap  string `json:"cap"`
+}
+
+type OldestOperationDetails struct {
+	Category      string `json:"category"`
+	StakingTxHash string `json:"staking_tx_hash"`
+	StartedAt     string `json:"started_at"`
+}
+
+type DebugStateResponse struct {
+	Channels []ChannelStateDetails `json:"channels"`
+	// number of running background goroutines by purpose
+	Goroutines       map[string]string        `json:"goroutines"`
+	OldestOperations []OldestOperationDetails `json:"oldest_operations"`
+	BestBlockHeight  string                   `json:"best_block_height"`
+	// empty if no block epoch event was received yet
+	LastBlockEventAt string `json:"last_block_event_at"`
+}
+
+type PendingRecoveryDetails struct {
+	StakingTxHash string `json:"staking_tx_hash"`
+	StakingState  string `json:"staking_state"`
+	Attempts      string `json:"attempts"`
+	LastError     string `json:"last_error"`
+	NextAttemptAt string `json:"next_attempt_at"`
+}
+
+type RecoveryStatusResponse struct {
+	PendingTransactions []PendingRecoveryDetails `json:"pending_transactions"`
+}
+
+type AuditLogEntryDetails struct {
+	Seq              string `json:"seq"`
+	Type             string `json:"type"`
+	Timestamp        string `json:"timestamp"`
+	Caller           string `json:"caller"`
+	Method           string `json:"method"`
+	Args             string `json:"args,omitempty"`
+	RequestSeq       string `json:"request_seq,omitempty"`
+	TxHash           string `json:"tx_hash,omitempty"`
+	StakingRequestId string `json:"staking_request_id,omitempty"`
+	Error            string `json:"error,omitempty"`
+	// hex encoded sha256 hashes, linking each entry to the previous one
+	PrevHash string `json:"prev_hash,omitempty"`
+	Hash     string `json:"hash"`
+}
+
+type AuditLogResponse struct {
+	Entries []AuditLogEntryDetails `json:"entries"`
+	// sequence number from which next page of entries should be queried
+	NextSeq string `json:"next_seq"`
+}
+
+type HistoryEventDetails struct {
+	Type          string `json:"type"`
+	StakingTxHash string `json:"staking_tx_hash"`
+	TxHash        string `json:"tx_hash,omitempty"`
+	Time          string `json:"time"`
+	// "block" if time comes from header of inclusion block, "local" if it is time
+	// when staker processed the event
+	TimeSource  string `json:"time_source"`
+	BlockHeight string `json:"block_height,omitempty"`
+	// amounts in satoshis
+	Amount string `json:"amount"`
+	Fee    string `json:"fee,omitempty"`
+}
+
+type HistoryResponse struct {
+	Events []HistoryEventDetails `json:"events"`
+}
+
+type ArchiveTransactionsResponse struct {
+	OlderThan string   `json:"older_than"`
+	States    []string `json:"states"`
+	// number of transactions moved to archive
+	Archived string `json:"archived"`
+}
+
+type LatencyStatsDetails struct {
+	Interval string `json:"interval"`
+	// number of intervals which ended within requested time range
+	Count      string `json:"count"`
+	P50Seconds string `json:"p50_seconds"`
+	P95Seconds string `json:"p95_seconds"`
+}
+
+type LatencyReportResponse struct {
+	Intervals []LatencyStatsDetails `json:"intervals"`
+}
+
+type WalletRescanDetails struct {
+	TxHash      string `json:"tx_hash"`
+	StartHeight string `json:"start_height"`
+	StartedAt   string `json:"started_at"`
+}
+
+type RescanStatusResponse struct {
+	Rescans []WalletRescanDetails `json:"rescans"`
+	// only filled if wallet is rescanning and reports its progress
+	WalletProgressPercent string `json:"wallet_progress_percent,omitempty"`
+	WalletRescanDuration  string `json:"wallet_rescan_duration,omitempty"`
+}
@@ -0,0 +1,135 @@
+package stakerservice
+
+import (
+	"encoding/hex"
+	"strconv"
+
+	"github.com/babylonchain/btc-staker/babylonclient"
+	str "github.com/babylonchain/btc-staker/staker"
+	"github.com/babylonchain/btc-staker/stakerdb"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	rpctypes "github.com/cometbft/cometbft/rpc/jsonrpc/types"
+)
+
+func schnorrKeysToHex(keys []*btcec.PublicKey) []string {
+	result := make([]string, len(keys))
+
+	for i, key := range keys {
+		result[i] = hex.EncodeToString(schnorr.SerializePubKey(key))
+	}
+
+	return result
+}
+
+func confirmationInfoToDetails(ci *stakerdb.BtcConfirmationInfo) *BtcConfirmationDetails {
+	if ci == nil {
+		return nil
+	}
+
+	return &BtcConfirmationDetails{
+		BlockHash:   ci.BlockHash.String(),
+		BlockHeight: strconv.FormatUint(uint64(ci.Height), 10),
+	}
+}
+
+// NewTransactionDetailsResponse renders transaction details. It is used both by
+// the daemon and by offline reads of staker database.
+func NewTransactionDetailsResponse(d *str.TransactionDetails) *TransactionDetailsResponse {
+	tx := d.Tx
+	stakingOutput := tx.StakingTx.TxOut[tx.StakingOutputIndex]
+
+	resp := &TransactionDetailsResponse{
+		StakingTxHash:    tx.StakingTx.TxHash().String(),
+		StakingState:     tx.State.String(),
+		Watched:          tx.Watched,
+		StakerAddress:    tx.StakerAddress,
+		StakingValue:     strconv.FormatInt(stakingOutput.Value, 10),
+		StakingOutputIdx: strconv.FormatUint(uint64(tx.StakingOutputIndex), 10),
+		Confirmation:     confirmationInfoToDetails(tx.StakingTxConfirmationInfo),
+		BabylonTxHash:    tx.BabylonTxHash,
+		BabylonDryRun:    babylonclient.IsDryRunTxHash(tx.BabylonTxHash),
+		BabylonMemo:      tx.BabylonMemo,
+		Label:            tx.Label,
+		Note:             d.Note,
+		CorruptionReason: tx.CorruptionReason,
+		StakingScript: StakingScriptDetails{
+			PkScriptHex:         hex.EncodeToString(stakingOutput.PkScript),
+			FinalityProviderPks: schnorrKeysToHex(tx.FinalityProvidersBtcPks),
+			StakingTimeBlocks:   strconv.FormatUint(uint64(tx.StakingTime), 10),
+			ParamsVersion:       strconv.FormatUint(uint64(tx.ParamsVersion), 10),
+			Verified:            d.ScriptVerified,
+		},
+	}
+
+	resp.BabylonSubmitFailure = optionalBabylonSubmitFailureDetails(tx.BabylonSubmitFailure)
+
+	if tx.ChangeOutput != nil {
+		change := changeOutputToDetails(tx.ChangeOutput)
+		resp.Change = &change
+	}
+
+	for i, pk := range resp.StakingScript.FinalityProviderPks {
+		fp := FinalityProviderKeyDetails{BtcPk: pk}
+
+		if i < len(d.FpMonikers) {
+			fp.Moniker = d.FpMonikers[i]
+		}
+
+		resp.FinalityProviders = append(resp.FinalityProviders, fp)
+	}
+
+	if d.StakerBtcPk != nil {
+		resp.StakingScript.StakerBtcPk = hex.EncodeToString(schnorr.SerializePubKey(d.StakerBtcPk))
+	}
+
+	if d.Params != nil {
+		resp.StakingScript.CovenantPks = schnorrKeysToHex(d.Params.CovenantPks)
+		resp.StakingScript.CovenantQuorum = strconv.FormatUint(uint64(d.Params.CovenantQuorum), 10)
+	}
+
+	if tx.UnbondingTxData != nil {
+		unbondingTx := tx.UnbondingTxData.UnbondingTx
+
+		resp.Unbonding = &UnbondingDetails{
+			UnbondingTxHash:     unbondingTx.TxHash().String(),
+			UnbondingValue:      strconv.FormatInt(tx.UnbondingTxData.UnbondingOutput().Value, 10),
+			UnbondingTimeBlocks: strconv.FormatUint(uint64(tx.UnbondingTxData.UnbondingTime), 10),
+			CovenantSignatures:  strconv.Itoa(len(tx.UnbondingTxData.CovenantSignatures)),
+			Confirmation:        confirmationInfoToDetails(tx.UnbondingTxData.UnbondingTxConfirmationInfo),
+		}
+	}
+
+	withdrawableHeight, lockedInOutput := tx.WithdrawableHeight()
+
+	if lockedInOutput {
+		resp.WithdrawableHeight = strconv.FormatUint(uint64(withdrawableHeight), 10)
+	}
+
+	if d.CurrentBtcHeight > 0 {
+		resp.CurrentBtcHeight = strconv.FormatUint(uint64(d.CurrentBtcHeight), 10)
+
+		if lockedInOutput {
+			// transaction can be included only in the next block
+			withdrawable := d.CurrentBtcHeight+1 >= withdrawableHeight
+			resp.Withdrawable = &withdrawable
+		}
+	}
+
+	return resp
+}
+
+func (s *StakerService) transactionDetails(_ *rpctypes.Context, stakingTxHash string) (*TransactionDetailsResponse, error) {
+	txHash, err := chainhash.NewHashFromStr(stakingTxHash)
+	if err != nil {
+		return nil, err
+	}
+
+	details, err := s.staker.TransactionDetails(txHash)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewTransactionDetailsResponse(details), nil
+}
@@ -0,0 +1,118 @@
+package utils
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/btcsuite/btcd/btcutil"
+)
+
+const (
+	btcUnit  = "btc"
+	satUnit  = "sat"
+	satsUnit = "sats"
+)
+
+// ParseBtcAmount parses amount with optional unit suffix e.g `0.5btc`, `50000000sat`
+// or `50000000sats`. Amount without unit is interpreted as satoshis.
+func ParseBtcAmount(s string) (btcutil.Amount, error) {
+	amountStr := strings.ToLower(strings.TrimSpace(s))
+
+	if amountStr == "" {
+		return 0, fmt.Errorf("empty amount")
+	}
+
+	var amount btcutil.Amount
+
+	switch {
+	case strings.HasSuffix(amountStr, btcUnit):
+		value, err := strconv.ParseFloat(strings.TrimSpace(strings.TrimSuffix(amountStr, btcUnit)), 64)
+
+		if err != nil {
+			return 0, fmt.Errorf("invalid btc amount %s: %w", s, err)
+		}
+
+		amount, err = btcutil.NewAmount(value)
+
+		if err != nil {
+			return 0, fmt.Errorf("invalid btc amount %s: %w", s, err)
+		}
+
+	default:
+		// check longer suffix first, as sats also ends with sat
+		satStr := strings.TrimSuffix(amountStr, satsUnit)
+		satStr = strings.TrimSpace(strings.TrimSuffix(satStr, satUnit))
+
+		value, err := strconv.ParseInt(satStr, 10, 64)
+
+		if err != nil {
+			return 0, fmt.Errorf("invalid satoshi amount %s: %w", s, err)
+		}
+
+		amount = btcutil.Amount(value)
+	}
+
+	if amount < 0 {
+		return 0, fmt.Errorf("amount %s must be non-negative", s)
+	}
+
+	if amount > btcutil.MaxSatoshi {
+		return 0, fmt.Errorf("amount %s exceeds maximum amount of bitcoin", s)
+	}
+
+	return amount, nil
+}
+
+// satPerVbyteUnit is unit of fee rates in satoshis per virtual byte, as opposed
+// to satoshis per 1000 virtual bytes used internally
+const satPerVbyteUnit = "sat/vb"
+
+// SatPerVbyteToPerKb converts fee rate in sat/vB to fee rate per kvB, rounding to
+// whole satoshis
+func SatPerVbyteToPerKb(satPerVbyte float64) (btcutil.Amount, error) {
+	if math.IsNaN(satPerVbyte) || math.IsInf(satPerVbyte, 0) {
+		return 0, fmt.Errorf("invalid fee rate %v sat/vB", satPerVbyte)
+	}
+
+	if satPerVbyte < 0 {
+		return 0, fmt.Errorf("fee rate %v sat/vB must be non-negative", satPerVbyte)
+	}
+
+	perKb := math.Round(satPerVbyte * 1000)
+
+	if perKb > float64(btcutil.MaxSatoshi) {
+		return 0, fmt.Errorf("fee rate %v sat/vB exceeds maximum amount of bitcoin", satPerVbyte)
+	}
+
+	return btcutil.Amount(perKb), nil
+}
+
+// PerKbToSatPerVbyte converts fee rate per kvB to sat/vB
+func PerKbToSatPerVbyte(perKb btcutil.Amount) float64 {
+	return float64(perKb) / 1000
+}
+
+// ParseFeeRateSatPerVbyte parses fee rate given with sat/vb unit e.g `5sat/vb` or
+// `2.5 sat/vB`. Returns false if fee rate is given without this unit.
+func ParseFeeRateSatPerVbyte(s string) (float64, bool, error) {
+	rateStr := strings.ToLower(strings.TrimSpace(s))
+
+	if !strings.HasSuffix(rateStr, satPerVbyteUnit) {
+		return 0, false, nil
+	}
+
+	value, err := strconv.ParseFloat(strings.TrimSpace(strings.TrimSuffix(rateStr, satPerVbyteUnit)), 64)
+
+	if err != nil {
+		return 0, true, fmt.Errorf("invalid fee rate %s: %w", s, err)
+	}
+
+	// validates the same way as conversion done by daemon
+	if _, err := SatPerVbyteToPerKb(value); err != nil {
+		return 0, true, err
+	}
+
+	return value, true, nil
+}
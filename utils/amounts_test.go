@@ -0,0 +1,100 @@
+package utils_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/babylonchain/btc-staker/utils"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseBtcAmount(t *testing.T) {
+	valid := []struct {
+		input    string
+		expected btcutil.Amount
+	}{
+		{"50000000", 50000000},
+		{"50000000sat", 50000000},
+		{"50000000sats", 50000000},
+		{"50000000 SAT", 50000000},
+		{"0.5btc", 50000000},
+		{"0.5 BTC", 50000000},
+		{"1btc", btcutil.SatoshiPerBitcoin},
+		{"0.00000001btc", 1},
+		{"0", 0},
+	}
+
+	for _, tc := range valid {
+		amount, err := utils.ParseBtcAmount(tc.input)
+		require.NoError(t, err, tc.input)
+		require.Equal(t, tc.expected, amount, tc.input)
+	}
+
+	invalid := []string{"", "btc", "sat", "-1", "-0.5btc", "0.5", "1.5sat", "0.5eth", "22000000btc"}
+
+	for _, input := range invalid {
+		_, err := utils.ParseBtcAmount(input)
+		require.Error(t, err, input)
+	}
+}
+
+func TestFeeRateConversion(t *testing.T) {
+	valid := []struct {
+		satPerVbyte float64
+		perKb       btcutil.Amount
+	}{
+		{0, 0},
+		{1, 1000},
+		{5, 5000},
+		{2.5, 2500},
+		{1.0004, 1000},
+		{1.0005, 1001},
+	}
+
+	for _, tc := range valid {
+		perKb, err := utils.SatPerVbyteToPerKb(tc.satPerVbyte)
+		require.NoError(t, err)
+		require.Equal(t, tc.perKb, perKb, tc.satPerVbyte)
+	}
+
+	require.Equal(t, 5.0, utils.PerKbToSatPerVbyte(5000))
+	require.Equal(t, 2.5, utils.PerKbToSatPerVbyte(2500))
+	require.Equal(t, 0.005, utils.PerKbToSatPerVbyte(5))
+
+	for _, rate := range []float64{-1, math.NaN(), math.Inf(1), 1e20} {
+		_, err := utils.SatPerVbyteToPerKb(rate)
+		require.Error(t, err, rate)
+	}
+}
+
+func TestParseFeeRateSatPerVbyte(t *testing.T) {
+	valid := []struct {
+		input    string
+		expected float64
+	}{
+		{"5sat/vb", 5},
+		{"5 sat/vB", 5},
+		{"2.5SAT/VB", 2.5},
+	}
+
+	for _, tc := range valid {
+		rate, ok, err := utils.ParseFeeRateSatPerVbyte(tc.input)
+		require.NoError(t, err, tc.input)
+		require.True(t, ok, tc.input)
+		require.Equal(t, tc.expected, rate, tc.input)
+	}
+
+	// rates without the unit are left to other parsers
+	for _, input := range []string{"5", "5000sat", "0.00005btc"} {
+		_, ok, err := utils.ParseFeeRateSatPerVbyte(input)
+		require.NoError(t, err, input)
+		require.False(t, ok, input)
+	}
+
+	for _, input := range []string{"sat/vb", "-1sat/vb", "abcsat/vb"} {
+		_, ok, err := utils.ParseFeeRateSatPerVbyte(input)
+		require.Error(t, err, input)
+		require.True(t, ok, input)
+	}
+}
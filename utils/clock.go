@@ -0,0 +1,182 @@
+package utils
+
+import (
+	"sync"
+	"time"
+)
+
+// Ticker is the part of time.Ticker used by the staker
+type Ticker interface {
+	Chan() <-chan time.Time
+	Stop()
+}
+
+// Clock abstracts time related operations, so that time based behaviours can be
+// tested deterministically.
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+	NewTicker(d time.Duration) Ticker
+}
+
+type realClock struct{}
+
+var _ Clock = (*realClock)(nil)
+
+// NewRealClock returns clock backed by the time package
+func NewRealClock() Clock {
+	return &realClock{}
+}
+
+func (c *realClock) Now() time.Time {
+	return time.Now()
+}
+
+func (c *realClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+func (c *realClock) NewTicker(d time.Duration) Ticker {
+	return &realTicker{time.NewTicker(d)}
+}
+
+type realTicker struct {
+	*time.Ticker
+}
+
+func (t *realTicker) Chan() <-chan time.Time {
+	return t.C
+}
+
+type fakeWaiter struct {
+	deadline time.Time
+	c        chan time.Time
+}
+
+// FakeClock is Clock which time only moves when Advance is called. It should
+// only be used in tests.
+type FakeClock struct {
+	mu      sync.Mutex
+	now     time.Time
+	waiters []*fakeWaiter
+	tickers []*fakeTicker
+}
+
+var _ Clock = (*FakeClock)(nil)
+
+func NewFakeClock(now time.Time) *FakeClock {
+	return &FakeClock{
+		now: now,
+	}
+}
+
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *FakeClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	ch := make(chan time.Time, 1)
+
+	if d <= 0 {
+		ch <- c.now
+		return ch
+	}
+
+	c.waiters = append(c.waiters, &fakeWaiter{
+		deadline: c.now.Add(d),
+		c:        ch,
+	})
+
+	return ch
+}
+
+func (c *FakeClock) NewTicker(d time.Duration) Ticker {
+	if d <= 0 {
+		panic("non-positive interval for NewTicker")
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	t := &fakeTicker{
+		clock:    c,
+		interval: d,
+		next:     c.now.Add(d),
+		c:        make(chan time.Time, 1),
+	}
+
+	c.tickers = append(c.tickers, t)
+
+	return t
+}
+
+// NumWaiters returns number of pending After calls and active tickers. It allows
+// tests to wait until tested code starts waiting on the clock before advancing it.
+func (c *FakeClock) NumWaiters() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.waiters) + len(c.tickers)
+}
+
+// Advance moves clock forward by d, firing all timers and tickers which deadlines
+// have passed.
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = c.now.Add(d)
+
+	var pending []*fakeWaiter
+	for _, w := range c.waiters {
+		if w.deadline.After(c.now) {
+			pending = append(pending, w)
+			continue
+		}
+		w.c <- c.now
+	}
+	c.waiters = pending
+
+	for _, t := range c.tickers {
+		if t.next.After(c.now) {
+			continue
+		}
+
+		// as with time.Ticker, ticks are dropped if receiver is too slow
+		select {
+		case t.c <- c.now:
+		default:
+		}
+
+		for !t.next.After(c.now) {
+			t.next = t.next.Add(t.interval)
+		}
+	}
+}
+
+type fakeTicker struct {
+	clock    *FakeClock
+	interval time.Duration
+	next     time.Time
+	c        chan time.Time
+}
+
+func (t *fakeTicker) Chan() <-chan time.Time {
+	return t.c
+}
+
+func (t *fakeTicker) Stop() {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+
+	for i, ticker := range t.clock.tickers {
+		if ticker == t {
+			t.clock.tickers = append(t.clock.tickers[:i], t.clock.tickers[i+1:]...)
+			return
+		}
+	}
+}
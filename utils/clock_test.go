@@ -0,0 +1,66 @@
+package utils_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/babylonchain/btc-staker/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func fired(c <-chan time.Time) bool {
+	select {
+	case <-c:
+		return true
+	default:
+		return false
+	}
+}
+
+func TestFakeClockAfter(t *testing.T) {
+	start := time.Unix(1700000000, 0)
+
+	tests := []struct {
+		name      string
+		wait      time.Duration
+		advance   time.Duration
+		expectNow bool
+	}{
+		{"non positive duration fires immediately", 0, 0, true},
+		{"not enough time passed", time.Minute, 59 * time.Second, false},
+		{"exact deadline", time.Minute, time.Minute, true},
+		{"past deadline", time.Minute, time.Hour, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := utils.NewFakeClock(start)
+			ch := c.After(tt.wait)
+			c.Advance(tt.advance)
+			require.Equal(t, tt.expectNow, fired(ch))
+			require.Equal(t, start.Add(tt.advance), c.Now())
+		})
+	}
+}
+
+func TestFakeClockTicker(t *testing.T) {
+	c := utils.NewFakeClock(time.Unix(1700000000, 0))
+	ticker := c.NewTicker(time.Second)
+	require.Equal(t, 1, c.NumWaiters())
+
+	c.Advance(500 * time.Millisecond)
+	require.False(t, fired(ticker.Chan()))
+
+	c.Advance(500 * time.Millisecond)
+	require.True(t, fired(ticker.Chan()))
+
+	// slow receiver misses ticks, as with real ticker
+	c.Advance(3 * time.Second)
+	require.True(t, fired(ticker.Chan()))
+	require.False(t, fired(ticker.Chan()))
+
+	ticker.Stop()
+	require.Equal(t, 0, c.NumWaiters())
+	c.Advance(time.Second)
+	require.False(t, fired(ticker.Chan()))
+}
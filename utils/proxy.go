@@ -0,0 +1,219 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/btcsuite/go-socks/socks"
+)
+
+var (
+	// ErrProxyUnreachable connection to the proxy itself could not be established
+	ErrProxyUnreachable = errors.New("proxy is unreachable")
+	// ErrProxyRejected proxy refused to serve the connection e.g due to invalid credentials
+	ErrProxyRejected = errors.New("proxy rejected connection")
+	// ErrProxyTargetUnreachable proxy is working, but could not connect to the target
+	ErrProxyTargetUnreachable = errors.New("target is unreachable through proxy")
+)
+
+// ProxyDialError is returned when connection through proxy fails. It unwraps to
+// one of ErrProxyUnreachable, ErrProxyRejected or ErrProxyTargetUnreachable, so
+// that callers can tell which side of the proxy failed.
+type ProxyDialError struct {
+	Proxy  string
+	Target string
+	Kind   error
+	Err    error
+}
+
+func (e *ProxyDialError) Error() string {
+	return fmt.Sprintf("failed to connect to %s through proxy %s: %s: %s", e.Target, e.Proxy, e.Kind, e.Err)
+}
+
+func (e *ProxyDialError) Unwrap() error {
+	return e.Kind
+}
+
+// ProxyDialer dials connections through SOCKS5 proxy e.g Tor. Target host names
+// are always passed to the proxy and resolved by it, they are never resolved
+// locally.
+type ProxyDialer struct {
+	proxy socks.Proxy
+}
+
+func NewProxyDialer(address, user, pass string) *ProxyDialer {
+	return &ProxyDialer{
+		proxy: socks.Proxy{
+			Addr:     address,
+			Username: user,
+			Password: pass,
+		},
+	}
+}
+
+// Address returns address of the proxy
+func (d *ProxyDialer) Address() string {
+	return d.proxy.Addr
+}
+
+func (d *ProxyDialer) Dial(network, addr string) (net.Conn, error) {
+	return d.DialTimeout(network, addr, 0)
+}
+
+// DialTimeout connects to addr through the proxy. Timeout limits only
+// establishing connection to the proxy, zero means no timeout.
+func (d *ProxyDialer) DialTimeout(network, addr string, timeout time.Duration) (net.Conn, error) {
+	conn, err := d.proxy.DialTimeout(network, addr, timeout)
+
+	if err != nil {
+		return nil, &ProxyDialError{
+			Proxy:  d.proxy.Addr,
+			Target: addr,
+			Kind:   classifyProxyError(err),
+			Err:    err,
+		}
+	}
+
+	return conn, nil
+}
+
+// DialContext connects to addr through the proxy, context deadline is used as
+// timeout of connecting to the proxy
+func (d *ProxyDialer) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	var timeout time.Duration
+	if deadline, ok := ctx.Deadline(); ok {
+		timeout = time.Until(deadline)
+
+		if timeout <= 0 {
+			return nil, context.DeadlineExceeded
+		}
+	}
+
+	return d.DialTimeout(network, addr, timeout)
+}
+
+func classifyProxyError(err error) error {
+	var opErr *net.OpError
+
+	switch {
+	case errors.As(err, &opErr) && opErr.Op == "dial":
+		return ErrProxyUnreachable
+	case errors.Is(err, socks.ErrAuthFailed),
+		errors.Is(err, socks.ErrNoAcceptableAuthMethod),
+		errors.Is(err, socks.ErrInvalidProxyResponse):
+		return ErrProxyRejected
+	case errors.As(err, &opErr),
+		errors.Is(err, io.EOF),
+		errors.Is(err, io.ErrUnexpectedEOF):
+		// connection to the proxy broke during handshake
+		return ErrProxyUnreachable
+	default:
+		// proxy replied with failure status of the connect request
+		return ErrProxyTargetUnreachable
+	}
+}
+
+// ProxyForwarder listens on local address and forwards every accepted connection
+// to the target through the proxy. It is used with clients which connect on their
+// own and can't be given a dialer.
+type ProxyForwarder struct {
+	listener net.Listener
+	dialer   *ProxyDialer
+	target   string
+	onError  func(error)
+	wg       sync.WaitGroup
+	quit     chan struct{}
+}
+
+// Forward starts forwarding connections accepted on random local port to target.
+// onError is called with errors of connections which could not be forwarded.
+func (d *ProxyDialer) Forward(target string, onError func(error)) (*ProxyForwarder, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to start proxy forwarder for %s: %w", target, err)
+	}
+
+	f := &ProxyForwarder{
+		listener: listener,
+		dialer:   d,
+		target:   target,
+		onError:  onError,
+		quit:     make(chan struct{}),
+	}
+
+	f.wg.Add(1)
+	go f.acceptLoop()
+
+	return f, nil
+}
+
+// Addr returns local address, connections to which are forwarded to the target
+func (f *ProxyForwarder) Addr() string {
+	return f.listener.Addr().String()
+}
+
+func (f *ProxyForwarder) Close() error {
+	close(f.quit)
+	err := f.listener.Close()
+	f.wg.Wait()
+	return err
+}
+
+func (f *ProxyForwarder) acceptLoop() {
+	defer f.wg.Done()
+
+	for {
+		conn, err := f.listener.Accept()
+
+		if err != nil {
+			select {
+			case <-f.quit:
+				return
+			default:
+			}
+
+			f.onError(fmt.Errorf("proxy forwarder for %s failed to accept connection: %w", f.target, err))
+			return
+		}
+
+		f.wg.Add(1)
+		go f.forward(conn)
+	}
+}
+
+func (f *ProxyForwarder) forward(local net.Conn) {
+	defer f.wg.Done()
+	defer local.Close()
+
+	remote, err := f.dialer.Dial("tcp", f.target)
+
+	if err != nil {
+		f.onError(err)
+		return
+	}
+	defer remote.Close()
+
+	done := make(chan struct{}, 2)
+
+	go func() {
+		_, _ = io.Copy(remote, local)
+		done <- struct{}{}
+	}()
+
+	go func() {
+		_, _ = io.Copy(local, remote)
+		done <- struct{}{}
+	}()
+
+	// closing both connections unblocks the other copy
+	select {
+	case <-done:
+	case <-f.quit:
+	}
+}
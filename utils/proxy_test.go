@@ -0,0 +1,190 @@
+package utils_test
+
+import (
+	"encoding/binary"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/babylonchain/btc-staker/utils"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	socksHostUnreachable = 4
+	socksGranted         = 0
+)
+
+// fakeSocksServer is minimal SOCKS5 server, which replies to every connect request
+// with given status. Granted connections are echoed back to the client.
+type fakeSocksServer struct {
+	listener net.Listener
+	// required password, empty if authentication is not required
+	pass   string
+	status byte
+	// host names of received connect requests
+	hosts chan string
+}
+
+func newFakeSocksServer(t *testing.T, pass string, status byte) *fakeSocksServer {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	s := &fakeSocksServer{
+		listener: listener,
+		pass:     pass,
+		status:   status,
+		hosts:    make(chan string, 10),
+	}
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+
+	return s
+}
+
+func (s *fakeSocksServer) serve(conn net.Conn) {
+	defer conn.Close()
+
+	buf := make([]byte, 512)
+
+	// greeting: version, number of methods, methods
+	if _, err := io.ReadFull(conn, buf[:2]); err != nil {
+		return
+	}
+	if _, err := io.ReadFull(conn, buf[:buf[1]]); err != nil {
+		return
+	}
+
+	if s.pass == "" {
+		_, _ = conn.Write([]byte{5, 0})
+	} else {
+		_, _ = conn.Write([]byte{5, 2})
+
+		// version, user length, user, password length, password
+		if _, err := io.ReadFull(conn, buf[:2]); err != nil {
+			return
+		}
+		userLen := buf[1]
+		if _, err := io.ReadFull(conn, buf[:userLen+1]); err != nil {
+			return
+		}
+		passLen := buf[userLen]
+		if _, err := io.ReadFull(conn, buf[:passLen]); err != nil {
+			return
+		}
+
+		if string(buf[:passLen]) != s.pass {
+			_, _ = conn.Write([]byte{1, 1})
+			return
+		}
+		_, _ = conn.Write([]byte{1, 0})
+	}
+
+	// connect request with domain address type
+	if _, err := io.ReadFull(conn, buf[:5]); err != nil {
+		return
+	}
+	if buf[3] != 3 {
+		return
+	}
+	hostLen := int(buf[4])
+	if _, err := io.ReadFull(conn, buf[:hostLen+2]); err != nil {
+		return
+	}
+	s.hosts <- string(buf[:hostLen])
+
+	// reply with ipv4 bound address
+	reply := []byte{5, s.status, 0, 1, 127, 0, 0, 1, 0, 0}
+	binary.BigEndian.PutUint16(reply[8:], 1)
+	_, _ = conn.Write(reply)
+
+	if s.status != socksGranted {
+		return
+	}
+
+	_, _ = io.Copy(conn, conn)
+}
+
+func closedPortAddress(t *testing.T) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := listener.Addr().String()
+	require.NoError(t, listener.Close())
+	return addr
+}
+
+func TestProxyDialerPassesHostNameToProxy(t *testing.T) {
+	server := newFakeSocksServer(t, "", socksGranted)
+	dialer := utils.NewProxyDialer(server.listener.Addr().String(), "", "")
+
+	conn, err := dialer.Dial("tcp", "somenode.onion:8332")
+	require.NoError(t, err)
+	defer conn.Close()
+
+	require.Equal(t, "somenode.onion", <-server.hosts)
+
+	_, err = conn.Write([]byte("ping"))
+	require.NoError(t, err)
+	resp := make([]byte, 4)
+	_, err = io.ReadFull(conn, resp)
+	require.NoError(t, err)
+	require.Equal(t, "ping", string(resp))
+}
+
+func TestProxyDialerErrors(t *testing.T) {
+	unreachable := utils.NewProxyDialer(closedPortAddress(t), "", "")
+	_, err := unreachable.Dial("tcp", "somenode.onion:8332")
+	require.ErrorIs(t, err, utils.ErrProxyUnreachable)
+
+	var dialErr *utils.ProxyDialError
+	require.ErrorAs(t, err, &dialErr)
+	require.Equal(t, "somenode.onion:8332", dialErr.Target)
+
+	authServer := newFakeSocksServer(t, "secret", socksGranted)
+	wrongPass := utils.NewProxyDialer(authServer.listener.Addr().String(), "user", "wrong")
+	_, err = wrongPass.Dial("tcp", "somenode.onion:8332")
+	require.ErrorIs(t, err, utils.ErrProxyRejected)
+
+	goodPass := utils.NewProxyDialer(authServer.listener.Addr().String(), "user", "secret")
+	conn, err := goodPass.Dial("tcp", "somenode.onion:8332")
+	require.NoError(t, err)
+	conn.Close()
+
+	failingServer := newFakeSocksServer(t, "", socksHostUnreachable)
+	dialer := utils.NewProxyDialer(failingServer.listener.Addr().String(), "", "")
+	_, err = dialer.Dial("tcp", "somenode.onion:8332")
+	require.ErrorIs(t, err, utils.ErrProxyTargetUnreachable)
+}
+
+func TestProxyForwarder(t *testing.T) {
+	server := newFakeSocksServer(t, "", socksGranted)
+	dialer := utils.NewProxyDialer(server.listener.Addr().String(), "", "")
+
+	errs := make(chan error, 1)
+	forwarder, err := dialer.Forward("somenode.onion:8332", func(err error) { errs <- err })
+	require.NoError(t, err)
+
+	conn, err := net.Dial("tcp", forwarder.Addr())
+	require.NoError(t, err)
+
+	_, err = conn.Write([]byte("ping"))
+	require.NoError(t, err)
+	resp := make([]byte, 4)
+	_, err = io.ReadFull(conn, resp)
+	require.NoError(t, err)
+	require.Equal(t, "ping", string(resp))
+	require.Equal(t, "somenode.onion", <-server.hosts)
+
+	require.NoError(t, forwarder.Close())
+	conn.Close()
+	require.Empty(t, errs)
+}
@@ -0,0 +1,81 @@
+package walletcontroller
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/txscript"
+)
+
+// ValidateStakerAddress checks whether address can be used as staker address.
+// Staker key is retrieved from the wallet through the address, so only single key
+// segwit addresses are supported i.e P2WPKH and BIP-86 P2TR.
+func ValidateStakerAddress(address btcutil.Address) error {
+	switch address.(type) {
+	case *btcutil.AddressWitnessPubKeyHash, *btcutil.AddressTaproot:
+		return nil
+	default:
+		return fmt.Errorf("unsupported staker address type %T for address %s. Only p2wpkh and p2tr addresses are supported", address, address)
+	}
+}
+
+// KeyMatchesAddress checks whether provided public key controls given address.
+// For P2TR addresses both internal key with BIP-86 tweak and already tweaked
+// output key are accepted, as wallets differ in which key is exported. Legacy
+// single key addresses are supported as well, as stakes created with them must
+// remain spendable.
+func KeyMatchesAddress(pubKey *btcec.PublicKey, address btcutil.Address) error {
+	switch addr := address.(type) {
+	case *btcutil.AddressWitnessPubKeyHash:
+		if !bytes.Equal(btcutil.Hash160(pubKey.SerializeCompressed()), addr.WitnessProgram()) {
+			return fmt.Errorf("public key does not match p2wpkh address %s", address)
+		}
+		return nil
+	case *btcutil.AddressTaproot:
+		outputKey := txscript.ComputeTaprootKeyNoScript(pubKey)
+
+		if bytes.Equal(schnorr.SerializePubKey(outputKey), addr.WitnessProgram()) {
+			return nil
+		}
+
+		if bytes.Equal(schnorr.SerializePubKey(pubKey), addr.WitnessProgram()) {
+			return nil
+		}
+
+		return fmt.Errorf("public key does not match p2tr address %s", address)
+	case *btcutil.AddressPubKeyHash:
+		hash := addr.Hash160()[:]
+
+		if bytes.Equal(btcutil.Hash160(pubKey.SerializeCompressed()), hash) ||
+			bytes.Equal(btcutil.Hash160(pubKey.SerializeUncompressed()), hash) {
+			return nil
+		}
+
+		return fmt.Errorf("public key does not match p2pkh address %s", address)
+	case *btcutil.AddressScriptHash:
+		// only p2wpkh nested in p2sh is controlled by single key
+		redeemScript, err := txscript.NewScriptBuilder().
+			AddOp(txscript.OP_0).
+			AddData(btcutil.Hash160(pubKey.SerializeCompressed())).
+			Script()
+
+		if err != nil {
+			return err
+		}
+
+		if !bytes.Equal(btcutil.Hash160(redeemScript), addr.Hash160()[:]) {
+			return fmt.Errorf("public key does not match p2sh-p2wpkh address %s", address)
+		}
+		return nil
+	case *btcutil.AddressPubKey:
+		if !addr.PubKey().IsEqual(pubKey) {
+			return fmt.Errorf("public key does not match p2pk address %s", address)
+		}
+		return nil
+	default:
+		return fmt.Errorf("cannot check whether public key controls address %s of type %T", address, address)
+	}
+}
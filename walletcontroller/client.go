@@ -1,12 +1,17 @@
 package walletcontroller
 
 import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"sort"
+	"time"
 
 	"github.com/babylonchain/btc-staker/stakercfg"
 	"github.com/babylonchain/btc-staker/types"
 	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcjson"
 	"github.com/btcsuite/btcd/btcutil"
 	"github.com/btcsuite/btcd/chaincfg"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
@@ -20,6 +25,7 @@ type RpcWalletController struct {
 	*rpcclient.Client
 	walletPassphrase string
 	network          string
+	params           *chaincfg.Params
 	backend          types.SupportedWalletBackend
 }
 
@@ -28,6 +34,9 @@ var _ WalletController = (*RpcWalletController)(nil)
 const (
 	txNotFoundErrMsgBtcd     = "No information available about transaction"
 	txNotFoundErrMsgBitcoind = "No such mempool or blockchain transaction"
+
+	listTransactionsPageSize = 1000
+	sendTxCategory           = "send"
 )
 
 func NewRpcWalletController(scfg *stakercfg.Config) (*RpcWalletController, error) {
@@ -41,6 +50,7 @@ func NewRpcWalletController(scfg *stakercfg.Config) (*RpcWalletController, error
 		&scfg.ActiveNetParams,
 		// TODO for now just disable tls
 		true,
+		scfg.ProxyConfig.WalletProxy(),
 	)
 }
 
@@ -53,7 +63,14 @@ func NewRpcWalletControllerFromArgs(
 	nodeBackend types.SupportedWalletBackend,
 	params *chaincfg.Params,
 	disableTls bool,
+	// nil if wallet is connected directly
+	proxy *stakercfg.ProxyConfig,
 ) (*RpcWalletController, error) {
+	host, err := stakercfg.NormalizeRpcHost(host, stakercfg.DefaultWalletRpcPort(params.Name))
+
+	if err != nil {
+		return nil, fmt.Errorf("invalid wallet host: %w", err)
+	}
 
 	connCfg := &rpcclient.ConnConfig{
 		Host:                 host,
@@ -67,6 +84,10 @@ func NewRpcWalletControllerFromArgs(
 		HTTPPostMode: true,
 	}
 
+	if proxy != nil {
+		proxy.ApplyToRpcClient(connCfg)
+	}
+
 	rpcclient, err := rpcclient.New(connCfg, nil)
 
 	if err != nil {
@@ -77,12 +98,22 @@ func NewRpcWalletControllerFromArgs(
 		Client:           rpcclient,
 		walletPassphrase: walletPassphrase,
 		network:          params.Name,
+		params:           params,
 		backend:          nodeBackend,
 	}, nil
 }
 
-func (w *RpcWalletController) UnlockWallet(timoutSec int64) error {
-	return w.WalletPassphrase(w.walletPassphrase, timoutSec)
+func (w *RpcWalletController) Ping() error {
+	// supported by all wallet backends, and does not depend on wallet state
+	_, err := w.GetBlockCount()
+	return err
+}
+
+func (w *RpcWalletController) UnlockWallet(ctx context.Context, timoutSec int64) error {
+	_, err := callWithContext(ctx, "walletpassphrase", func() (struct{}, error) {
+		return struct{}{}, w.WalletPassphrase(w.walletPassphrase, timoutSec)
+	}, nil)
+	return err
 }
 
 func (w *RpcWalletController) AddressPublicKey(address btcutil.Address) (*btcec.PublicKey, error) {
@@ -92,11 +123,19 @@ func (w *RpcWalletController) AddressPublicKey(address btcutil.Address) (*btcec.
 		return nil, err
 	}
 
+	// only public key is needed, clear private key material
+	defer privKey.PrivKey.Zero()
+
 	return privKey.PrivKey.PubKey(), nil
 }
 
-func (w *RpcWalletController) DumpPrivateKey(address btcutil.Address) (*btcec.PrivateKey, error) {
-	privKey, err := w.DumpPrivKey(address)
+func (w *RpcWalletController) DumpPrivateKey(ctx context.Context, address btcutil.Address) (*btcec.PrivateKey, error) {
+	privKey, err := callWithContext(ctx, "dumpprivkey", func() (*btcutil.WIF, error) {
+		return w.DumpPrivKey(address)
+	}, func(privKey *btcutil.WIF) {
+		// key returned after deadline is never used
+		privKey.PrivKey.Zero()
+	})
 
 	if err != nil {
 		return nil, err
@@ -109,11 +148,9 @@ func (w *RpcWalletController) NetworkName() string {
 	return w.network
 }
 
-func (w *RpcWalletController) CreateTransaction(
-	outputs []*wire.TxOut,
-	feeRatePerKb btcutil.Amount,
-	changeAddres btcutil.Address) (*wire.MsgTx, error) {
-
+// fundingUtxos returns spendable wallet utxos in order in which they are used to
+// fund transactions
+func (w *RpcWalletController) fundingUtxos() ([]Utxo, error) {
 	utxoResults, err := w.ListUnspent()
 
 	if err != nil {
@@ -130,6 +167,20 @@ func (w *RpcWalletController) CreateTransaction(
 	// largest inputs first
 	sort.Sort(sort.Reverse(byAmount(utxos)))
 
+	return utxos, nil
+}
+
+func (w *RpcWalletController) CreateTransaction(
+	outputs []*wire.TxOut,
+	feeRatePerKb btcutil.Amount,
+	changeAddres btcutil.Address) (*wire.MsgTx, error) {
+
+	utxos, err := w.fundingUtxos()
+
+	if err != nil {
+		return nil, err
+	}
+
 	changeScript, err := txscript.PayToAddrScript(changeAddres)
 
 	if err != nil {
@@ -145,7 +196,37 @@ func (w *RpcWalletController) CreateTransaction(
 	return tx, err
 }
 
+// FundTxEstimate runs the same coin selection as CreateTransaction, but only
+// reports its result. For the same wallet state, transaction created by
+// CreateAndSignTx spends the same inputs and pays the same fee.
+func (w *RpcWalletController) FundTxEstimate(
+	outputs []*wire.TxOut,
+	feeRatePerKb btcutil.Amount,
+	changeAddress btcutil.Address) (*FundingEstimate, error) {
+
+	utxos, err := w.fundingUtxos()
+
+	if err != nil {
+		return nil, err
+	}
+
+	changeScript, err := txscript.PayToAddrScript(changeAddress)
+
+	if err != nil {
+		return nil, err
+	}
+
+	_, estimate, err := fundTx(utxos, outputs, feeRatePerKb, changeScript)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return estimate, nil
+}
+
 func (w *RpcWalletController) CreateAndSignTx(
+	ctx context.Context,
 	outputs []*wire.TxOut,
 	feeRatePerKb btcutil.Amount,
 	changeAddress btcutil.Address,
@@ -156,7 +237,7 @@ func (w *RpcWalletController) CreateAndSignTx(
 		return nil, err
 	}
 
-	fundedTx, signed, err := w.SignRawTransaction(tx)
+	fundedTx, signed, err := w.SignRawTransaction(ctx, tx)
 
 	if err != nil {
 		return nil, err
@@ -171,15 +252,33 @@ func (w *RpcWalletController) CreateAndSignTx(
 	return fundedTx, nil
 }
 
-func (w *RpcWalletController) SignRawTransaction(tx *wire.MsgTx) (*wire.MsgTx, bool, error) {
+func (w *RpcWalletController) SignRawTransaction(ctx context.Context, tx *wire.MsgTx) (*wire.MsgTx, bool, error) {
+	type signResult struct {
+		tx     *wire.MsgTx
+		signed bool
+	}
+
+	var sign func() (*wire.MsgTx, bool, error)
+
 	switch w.backend {
 	case types.BitcoindWalletBackend:
-		return w.Client.SignRawTransactionWithWallet(tx)
+		sign = func() (*wire.MsgTx, bool, error) { return w.Client.SignRawTransactionWithWallet(tx) }
 	case types.BtcwalletWalletBackend:
-		return w.Client.SignRawTransaction(tx)
+		sign = func() (*wire.MsgTx, bool, error) { return w.Client.SignRawTransaction(tx) }
 	default:
 		return nil, false, fmt.Errorf("invalid bitcoin backend")
 	}
+
+	result, err := callWithContext(ctx, "signrawtransaction", func() (signResult, error) {
+		signedTx, signed, err := sign()
+		return signResult{tx: signedTx, signed: signed}, err
+	}, nil)
+
+	if err != nil {
+		return nil, false, err
+	}
+
+	return result.tx, result.signed, nil
 }
 
 func (w *RpcWalletController) SendRawTransaction(tx *wire.MsgTx, allowHighFees bool) (*chainhash.Hash, error) {
@@ -202,6 +301,51 @@ func (w *RpcWalletController) ListOutputs(onlySpendable bool) ([]Utxo, error) {
 	return utxos, nil
 }
 
+func (w *RpcWalletController) ListSentTransactions() ([]chainhash.Hash, error) {
+	seen := make(map[chainhash.Hash]struct{})
+	var hashes []chainhash.Hash
+
+	for from := 0; ; from += listTransactionsPageSize {
+		results, err := w.ListTransactionsCountFrom("*", listTransactionsPageSize, from)
+
+		if err != nil {
+			return nil, err
+		}
+
+		for _, result := range results {
+			// wallet returns separate entry for every output of the transaction
+			if result.Category != sendTxCategory {
+				continue
+			}
+
+			hash, err := chainhash.NewHashFromStr(result.TxID)
+
+			if err != nil {
+				return nil, err
+			}
+
+			if _, ok := seen[*hash]; ok {
+				continue
+			}
+
+			seen[*hash] = struct{}{}
+			hashes = append(hashes, *hash)
+		}
+
+		if len(results) < listTransactionsPageSize {
+			break
+		}
+	}
+
+	return hashes, nil
+}
+
+// SetTxLabel is not supported by any of currently supported backends, as neither
+// bitcoind nor btcwallet rpc allow labeling transactions, only addresses
+func (w *RpcWalletController) SetTxLabel(_ *chainhash.Hash, _ string) error {
+	return ErrTxLabelsNotSupported
+}
+
 func nofitierStateToWalletState(state notifier.TxConfStatus) TxStatus {
 	switch state {
 	case notifier.TxNotFoundIndex:
@@ -219,8 +363,21 @@ func nofitierStateToWalletState(state notifier.TxConfStatus) TxStatus {
 	}
 }
 
-func (w *RpcWalletController) getTxDetails(req notifier.ConfRequest, msg string) (*notifier.TxConfirmation, TxStatus, error) {
-	res, state, err := notifier.ConfDetailsFromTxIndex(w.Client, req, msg)
+// txDetails fetches info about transaction from mempool or blockchain of the node
+// behind the client, requires node to have enabled transaction index
+func txDetails(
+	client *rpcclient.Client,
+	txHash *chainhash.Hash,
+	pkScript []byte,
+	txNotFoundErrMsg string,
+) (*notifier.TxConfirmation, TxStatus, error) {
+	req, err := notifier.NewConfRequest(txHash, pkScript)
+
+	if err != nil {
+		return nil, TxNotFound, err
+	}
+
+	res, state, err := notifier.ConfDetailsFromTxIndex(client, req, txNotFoundErrMsg)
 
 	if err != nil {
 		return nil, TxNotFound, err
@@ -231,18 +388,314 @@ func (w *RpcWalletController) getTxDetails(req notifier.ConfRequest, msg string)
 
 // Fetch info about transaction from mempool or blockchain, requires node to have enabled  transaction index
 func (w *RpcWalletController) TxDetails(txHash *chainhash.Hash, pkScript []byte) (*notifier.TxConfirmation, TxStatus, error) {
-	req, err := notifier.NewConfRequest(txHash, pkScript)
+	switch w.backend {
+	case types.BitcoindWalletBackend:
+		return txDetails(w.Client, txHash, pkScript, txNotFoundErrMsgBitcoind)
+	case types.BtcwalletWalletBackend:
+		return txDetails(w.Client, txHash, pkScript, txNotFoundErrMsgBtcd)
+	default:
+		return nil, TxNotFound, fmt.Errorf("invalid bitcoin backend")
+	}
+}
+
+func rawParams(params ...interface{}) ([]json.RawMessage, error) {
+	raw := make([]json.RawMessage, len(params))
+
+	for i, p := range params {
+		marshalled, err := json.Marshal(p)
+
+		if err != nil {
+			return nil, err
+		}
+
+		raw[i] = marshalled
+	}
+
+	return raw, nil
+}
+
+// RescanBlocks is only supported by bitcoind, as btcwallet rpc does not allow
+// rescanning from arbitrary height. Call blocks until rescan is finished.
+func (w *RpcWalletController) RescanBlocks(startHeight uint32) error {
+	if w.backend != types.BitcoindWalletBackend {
+		return ErrRescanNotSupported
+	}
+
+	params, err := rawParams(startHeight)
 
 	if err != nil {
-		return nil, TxNotFound, err
+		return err
 	}
 
+	_, err = w.RawRequest("rescanblockchain", params)
+	return err
+}
+
+// ImportAddressWithRescan imports script to the wallet as watch-only. In case of
+// bitcoind, chain is then rescanned from startHeight. Btcwallet rescans chain from
+// its birthday as part of import, so startHeight is not used.
+func (w *RpcWalletController) ImportAddressWithRescan(pkScript []byte, startHeight uint32) error {
 	switch w.backend {
 	case types.BitcoindWalletBackend:
-		return w.getTxDetails(req, txNotFoundErrMsgBitcoind)
+		// bitcoind accepts raw scripts, which covers also taproot outputs. Rescan is
+		// done separately, as import would rescan whole chain
+		params, err := rawParams(hex.EncodeToString(pkScript), "", false, false)
+
+		if err != nil {
+			return err
+		}
+
+		if _, err := w.RawRequest("importaddress", params); err != nil {
+			return fmt.Errorf("failed to import script: %w", err)
+		}
+
+		return w.RescanBlocks(startHeight)
 	case types.BtcwalletWalletBackend:
-		return w.getTxDetails(req, txNotFoundErrMsgBtcd)
+		_, addresses, _, err := txscript.ExtractPkScriptAddrs(pkScript, w.params)
+
+		if err != nil {
+			return err
+		}
+
+		if len(addresses) != 1 {
+			return fmt.Errorf("cannot import script which does not pay to single address")
+		}
+
+		return w.ImportAddressRescan(addresses[0].EncodeAddress(), "", true)
 	default:
-		return nil, TxNotFound, fmt.Errorf("invalid bitcoin backend")
+		return fmt.Errorf("invalid bitcoin backend")
+	}
+}
+
+func (w *RpcWalletController) RescanProgress() (*RescanProgress, error) {
+	if w.backend != types.BitcoindWalletBackend {
+		// btcwallet does not report rescan progress
+		return nil, nil
+	}
+
+	info, err := w.GetWalletInfo()
+
+	if err != nil {
+		return nil, err
+	}
+
+	progress, ok := info.Scanning.Value.(btcjson.ScanProgress)
+
+	if !ok {
+		// wallet reports false if it is not rescanning
+		return nil, nil
+	}
+
+	return &RescanProgress{
+		Duration: time.Duration(progress.Duration) * time.Second,
+		Progress: progress.Progress,
+	}, nil
+}
+
+func (w *RpcWalletController) SyncStatus() (*SyncStatus, error) {
+	switch w.backend {
+	case types.BitcoindWalletBackend:
+		status, err := chainSyncStatus(w.Client)
+
+		if err != nil {
+			return nil, err
+		}
+
+		progress, err := w.RescanProgress()
+
+		if err != nil {
+			return nil, err
+		}
+
+		status.Rescanning = progress != nil
+
+		return status, nil
+	case types.BtcwalletWalletBackend:
+		// btcwallet reports block up to which it processed the chain, it does not
+		// report initial block download nor rescans
+		hash, height, err := w.GetBestBlock()
+
+		if err != nil {
+			return nil, err
+		}
+
+		return &SyncStatus{
+			BestBlockHeight: uint32(height),
+			BestBlockHash:   *hash,
+		}, nil
+	default:
+		return nil, fmt.Errorf("invalid bitcoin backend")
+	}
+}
+
+// chainSyncStatus returns sync status of the chain of the node
+func chainSyncStatus(client *rpcclient.Client) (*SyncStatus, error) {
+	info, err := client.GetBlockChainInfo()
+
+	if err != nil {
+		return nil, err
+	}
+
+	hash, err := chainhash.NewHashFromStr(info.BestBlockHash)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &SyncStatus{
+		BestBlockHeight:      uint32(info.Blocks),
+		BestBlockHash:        *hash,
+		InitialBlockDownload: info.InitialBlockDownload,
+	}, nil
+}
+
+func (w *RpcWalletController) UnspentOutput(outpoint *wire.OutPoint) (*wire.TxOut, error) {
+	return unspentOutput(w.Client, outpoint)
+}
+
+func unspentOutput(client *rpcclient.Client, outpoint *wire.OutPoint) (*wire.TxOut, error) {
+	result, err := client.GetTxOut(&outpoint.Hash, outpoint.Index, true)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// node returns null for unknown and spent outputs
+	if result == nil {
+		return nil, ErrOutputNotFound
+	}
+
+	value, err := btcutil.NewAmount(result.Value)
+
+	if err != nil {
+		return nil, err
+	}
+
+	pkScript, err := hex.DecodeString(result.ScriptPubKey.Hex)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return wire.NewTxOut(int64(value), pkScript), nil
+}
+
+// ListAddresses returns addresses reported by listreceivedbyaddress, including
+// addresses which did not receive any funds yet
+func (w *RpcWalletController) ListAddresses() ([]WalletAddress, error) {
+	var results []btcjson.ListReceivedByAddressResult
+
+	switch w.backend {
+	case types.BitcoindWalletBackend:
+		// bitcoind lists watch-only addresses only if asked to
+		params, err := rawParams(0, true, true)
+
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err := w.RawRequest("listreceivedbyaddress", params)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if err := json.Unmarshal(resp, &results); err != nil {
+			return nil, err
+		}
+	case types.BtcwalletWalletBackend:
+		res, err := w.ListReceivedByAddressIncludeEmpty(0, true)
+
+		if err != nil {
+			return nil, err
+		}
+
+		results = res
+	default:
+		return nil, fmt.Errorf("invalid bitcoin backend")
+	}
+
+	addresses := make([]WalletAddress, 0, len(results))
+
+	for _, result := range results {
+		address, err := btcutil.DecodeAddress(result.Address, w.params)
+
+		if err != nil {
+			return nil, err
+		}
+
+		spendable, err := w.addressSpendable(address)
+
+		if err != nil {
+			return nil, err
+		}
+
+		addresses = append(addresses, WalletAddress{
+			Address:   address,
+			Spendable: spendable,
+		})
+	}
+
+	sort.Slice(addresses, func(i, j int) bool {
+		return addresses[i].Address.EncodeAddress() < addresses[j].Address.EncodeAddress()
+	})
+
+	return addresses, nil
+}
+
+// addressSpendable returns whether wallet holds private key of the address
+func (w *RpcWalletController) addressSpendable(address btcutil.Address) (bool, error) {
+	switch w.backend {
+	case types.BitcoindWalletBackend:
+		info, err := w.GetAddressInfo(address.EncodeAddress())
+
+		if err != nil {
+			return false, err
+		}
+
+		return info.IsMine && !info.IsWatchOnly, nil
+	case types.BtcwalletWalletBackend:
+		// btcwallet does not report watch-only flag, addresses imported as
+		// watch-only are stored as scripts without keys
+		info, err := w.ValidateAddress(address)
+
+		if err != nil {
+			return false, err
+		}
+
+		return info.IsMine && !info.IsScript && info.PubKey != "", nil
+	default:
+		return false, fmt.Errorf("invalid bitcoin backend")
+	}
+}
+
+func (w *RpcWalletController) WalletLocked() (bool, error) {
+	switch w.backend {
+	case types.BitcoindWalletBackend:
+		info, err := w.GetWalletInfo()
+
+		if err != nil {
+			return false, err
+		}
+
+		// unlocked_until is only reported by encrypted wallets, and is zero when
+		// wallet is locked
+		return info.UnlockedUntil != nil && *info.UnlockedUntil == 0, nil
+	case types.BtcwalletWalletBackend:
+		resp, err := w.RawRequest("walletislocked", nil)
+
+		if err != nil {
+			return false, err
+		}
+
+		var locked bool
+
+		if err := json.Unmarshal(resp, &locked); err != nil {
+			return false, err
+		}
+
+		return locked, nil
+	default:
+		return false, fmt.Errorf("invalid bitcoin backend")
 	}
 }
@@ -0,0 +1,92 @@
+package walletcontroller
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcwallet/wallet/txrules"
+	"github.com/btcsuite/btcwallet/wallet/txsizes"
+)
+
+// MaxConsolidationInputs is the maximum number of inputs of single consolidation
+// transaction. It keeps consolidation transaction well below standard size
+// limits, wallets with more utxos need multiple consolidations.
+const MaxConsolidationInputs = 500
+
+// ErrNothingToConsolidate wallet does not hold more utxos than requested
+var ErrNothingToConsolidate = errors.New("nothing to consolidate")
+
+// BuildConsolidationTx builds unsigned transaction which spends the smallest of
+// given utxos into single output, so that at most targetCount utxos remain
+// afterwards. Funds are sent to the script of the largest spent utxo, so they
+// stay in the wallet under the same address. Fee is estimated from the number
+// and types of spent utxos.
+func BuildConsolidationTx(
+	utxos []Utxo,
+	targetCount int,
+	feeRatePerKb btcutil.Amount,
+) (*wire.MsgTx, error) {
+	if targetCount < 1 {
+		return nil, fmt.Errorf("target utxo count must be at least 1")
+	}
+
+	if len(utxos) <= targetCount {
+		return nil, fmt.Errorf("%w: wallet holds %d utxos, target is %d", ErrNothingToConsolidate, len(utxos), targetCount)
+	}
+
+	sorted := make([]Utxo, len(utxos))
+	copy(sorted, utxos)
+	sort.Sort(byAmount(sorted))
+
+	// consolidation output is one of the remaining utxos
+	numInputs := len(sorted) - targetCount + 1
+
+	if numInputs > MaxConsolidationInputs {
+		numInputs = MaxConsolidationInputs
+	}
+
+	inputs := sorted[:numInputs]
+	destScript := inputs[len(inputs)-1].PkScript
+
+	tx := wire.NewMsgTx(wire.TxVersion)
+
+	var total btcutil.Amount
+	var nested, p2wpkh, p2tr, p2pkh int
+
+	for i := range inputs {
+		tx.AddTxIn(wire.NewTxIn(&inputs[i].OutPoint, nil, nil))
+		total += inputs[i].Amount
+
+		// the same classification of inputs as used by txauthor
+		switch pkScript := inputs[i].PkScript; {
+		case txscript.IsPayToScriptHash(pkScript):
+			nested++
+		case txscript.IsPayToWitnessPubKeyHash(pkScript):
+			p2wpkh++
+		case txscript.IsPayToTaproot(pkScript):
+			p2tr++
+		default:
+			p2pkh++
+		}
+	}
+
+	vsize := txsizes.EstimateVirtualSize(p2pkh, p2tr, p2wpkh, nested, nil, len(destScript))
+	fee := txrules.FeeForSerializeSize(feeRatePerKb, vsize)
+
+	output := wire.NewTxOut(int64(total-fee), destScript)
+
+	if total <= fee || txrules.IsDustOutput(output, txrules.DefaultRelayFeePerKb) {
+		return nil, fmt.Errorf(
+			"value of consolidated utxos %d does not cover fee %d of consolidation transaction",
+			total, fee,
+		)
+	}
+
+	tx.AddTxOut(output)
+
+	return tx, nil
+}
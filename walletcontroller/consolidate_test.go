@@ -0,0 +1,71 @@
+package walletcontroller
+
+import (
+	"testing"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/mempool"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcwallet/wallet/txrules"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildConsolidationTxSpendsSmallestUtxos(t *testing.T) {
+	amounts := append(repeatAmount(100_000, 40), 5_000_000, 3_000_000)
+	utxos := testUtxos(t, amounts...)
+	feeRate := btcutil.Amount(2000)
+
+	tx, err := BuildConsolidationTx(utxos, 3, feeRate)
+	require.NoError(t, err)
+
+	// 40 small utxos are consolidated into single output, which together with
+	// 2 large utxos leaves 3 utxos in wallet
+	require.Len(t, tx.TxIn, 40)
+	require.Len(t, tx.TxOut, 1)
+
+	spent := make(map[wire.OutPoint]struct{})
+	for _, in := range tx.TxIn {
+		spent[in.PreviousOutPoint] = struct{}{}
+	}
+
+	for i := 0; i < 40; i++ {
+		require.Contains(t, spent, utxos[i].OutPoint)
+	}
+
+	// funds stay under address of the consolidated utxos
+	require.Equal(t, utxos[39].PkScript, tx.TxOut[0].PkScript)
+
+	fee := btcutil.Amount(40*100_000 - tx.TxOut[0].Value)
+	require.Greater(t, fee, btcutil.Amount(0))
+
+	// after signing, paid fee rate is at least the requested one
+	for _, in := range tx.TxIn {
+		in.Witness = wire.TxWitness{make([]byte, 73), make([]byte, 33)}
+	}
+
+	vsize := mempool.GetTxVirtualSize(btcutil.NewTx(tx))
+	require.GreaterOrEqual(t, fee, txrules.FeeForSerializeSize(feeRate, int(vsize)))
+}
+
+func TestBuildConsolidationTxCapsNumberOfInputs(t *testing.T) {
+	utxos := testUtxos(t, repeatAmount(10_000, MaxConsolidationInputs+100)...)
+
+	tx, err := BuildConsolidationTx(utxos, 1, btcutil.Amount(1000))
+	require.NoError(t, err)
+	require.Len(t, tx.TxIn, MaxConsolidationInputs)
+}
+
+func TestBuildConsolidationTxRejectsInvalidRequests(t *testing.T) {
+	utxos := testUtxos(t, 100_000, 200_000, 300_000)
+
+	_, err := BuildConsolidationTx(utxos, 3, btcutil.Amount(1000))
+	require.ErrorIs(t, err, ErrNothingToConsolidate)
+
+	_, err = BuildConsolidationTx(utxos, 0, btcutil.Amount(1000))
+	require.Error(t, err)
+
+	// utxos are not worth fee of spending them
+	dust := testUtxos(t, 600, 700, 800)
+	_, err = BuildConsolidationTx(dust, 1, btcutil.Amount(20_000))
+	require.Error(t, err)
+}
@@ -0,0 +1,69 @@
+package walletcontroller
+
+import (
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcwallet/wallet/txauthor"
+	"github.com/btcsuite/btcwallet/wallet/txsizes"
+)
+
+// FundingEstimate is result of coin selection funding transaction outputs. It is
+// computed without signing the transaction, so it does not require wallet to be
+// unlocked.
+type FundingEstimate struct {
+	// selected wallet utxos, in order of transaction inputs
+	Inputs []Utxo
+	Fee    btcutil.Amount
+	// zero if there is no change output, as change would be dust and is left to
+	// fee instead
+	Change btcutil.Amount
+	// upper bound of virtual size of signed transaction, which fee is based on
+	MaxVsize uint32
+}
+
+func newFundingEstimate(utxos []Utxo, authoredTx *txauthor.AuthoredTx, changeScriptSize int) *FundingEstimate {
+	// input source always takes utxos from the front
+	inputs := make([]Utxo, len(authoredTx.Tx.TxIn))
+	copy(inputs, utxos)
+
+	var outputsTotal btcutil.Amount
+	for _, out := range authoredTx.Tx.TxOut {
+		outputsTotal += btcutil.Amount(out.Value)
+	}
+
+	var change btcutil.Amount
+	outputs := authoredTx.Tx.TxOut
+
+	if authoredTx.ChangeIndex >= 0 {
+		change = btcutil.Amount(outputs[authoredTx.ChangeIndex].Value)
+		outputs = outputs[:authoredTx.ChangeIndex]
+	}
+
+	// the same estimation txauthor uses to compute fee
+	var nested, p2wpkh, p2tr, p2pkh int
+	for _, pkScript := range authoredTx.PrevScripts {
+		switch {
+		case txscript.IsPayToScriptHash(pkScript):
+			nested++
+		case txscript.IsPayToWitnessPubKeyHash(pkScript):
+			p2wpkh++
+		case txscript.IsPayToTaproot(pkScript):
+			p2tr++
+		default:
+			p2pkh++
+		}
+	}
+
+	return &FundingEstimate{
+		Inputs:   inputs,
+		Fee:      authoredTx.TotalInput - outputsTotal,
+		Change:   change,
+		MaxVsize: uint32(txsizes.EstimateVirtualSize(p2pkh, p2tr, p2wpkh, nested, outputs, changeScriptSize)),
+	}
+}
+
+// CheckLimits checks that transaction funded by the estimated selection will not
+// exceed limits once signed
+func (e *FundingEstimate) CheckLimits(limits TxLimits) error {
+	return limits.check(uint32(len(e.Inputs)), e.MaxVsize)
+}
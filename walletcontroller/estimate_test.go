@@ -0,0 +1,85 @@
+package walletcontroller
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/mempool"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/stretchr/testify/require"
+)
+
+// estimateTestTx estimates funding of outputs the same way RpcWalletController
+// does
+func estimateTestTx(t *testing.T, utxos []Utxo, outputs []*wire.TxOut) *FundingEstimate {
+	sorted := make([]Utxo, len(utxos))
+	copy(sorted, utxos)
+	sort.Sort(sort.Reverse(byAmount(sorted)))
+
+	_, estimate, err := fundTx(sorted, outputs, btcutil.Amount(2000), testP2WPKHScript(t, 0xff))
+	require.NoError(t, err)
+
+	return estimate
+}
+
+func TestFundingEstimateMatchesSignedTx(t *testing.T) {
+	tests := []struct {
+		name    string
+		amounts []btcutil.Amount
+		staked  int64
+	}{
+		{"single input with change", []btcutil.Amount{1_000_000, 5_000_000, 20_000}, 3_000_000},
+		{"many inputs with change", repeatAmount(100_000, 45), 4_000_000},
+		{"dust change left to fee", []btcutil.Amount{1_000_200}, 1_000_000 - 200},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			utxos := testUtxos(t, tc.amounts...)
+			stakingOutput := wire.NewTxOut(tc.staked, testP2WPKHScript(t, 0xaa))
+
+			estimate := estimateTestTx(t, utxos, []*wire.TxOut{stakingOutput})
+			tx := fundTestTx(t, utxos, []*wire.TxOut{stakingOutput})
+
+			require.Len(t, estimate.Inputs, len(tx.TxIn))
+			for i, in := range tx.TxIn {
+				require.Equal(t, in.PreviousOutPoint, estimate.Inputs[i].OutPoint)
+			}
+
+			var inputsTotal, outputsTotal btcutil.Amount
+			for _, in := range estimate.Inputs {
+				inputsTotal += in.Amount
+			}
+			for _, out := range tx.TxOut {
+				outputsTotal += btcutil.Amount(out.Value)
+			}
+
+			require.Equal(t, inputsTotal-outputsTotal, estimate.Fee)
+			require.Equal(t, inputsTotal-estimate.Fee-btcutil.Amount(tc.staked), estimate.Change)
+
+			if estimate.Change == 0 {
+				require.Len(t, tx.TxOut, 1)
+			} else {
+				require.Len(t, tx.TxOut, 2)
+			}
+
+			vsize := uint32(mempool.GetTxVirtualSize(btcutil.NewTx(tx)))
+			require.LessOrEqual(t, vsize, estimate.MaxVsize)
+		})
+	}
+}
+
+func TestFundingEstimateCheckLimits(t *testing.T) {
+	utxos := testUtxos(t, repeatAmount(100_000, 45)...)
+	stakingOutput := wire.NewTxOut(4_000_000, testP2WPKHScript(t, 0xaa))
+
+	estimate := estimateTestTx(t, utxos, []*wire.TxOut{stakingOutput})
+	tx := fundTestTx(t, utxos, []*wire.TxOut{stakingOutput})
+
+	// estimate rejects every transaction rejected once signed
+	require.ErrorIs(t, estimate.CheckLimits(TxLimits{MaxInputs: 20}), ErrTxTooLarge)
+	require.ErrorIs(t, CheckTxLimits(tx, TxLimits{MaxInputs: 20}), ErrTxTooLarge)
+	require.ErrorIs(t, estimate.CheckLimits(TxLimits{MaxVsize: 2000}), ErrTxTooLarge)
+	require.NoError(t, estimate.CheckLimits(TxLimits{MaxInputs: 45, MaxVsize: estimate.MaxVsize}))
+}
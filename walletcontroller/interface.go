@@ -1,6 +1,10 @@
 package walletcontroller
 
 import (
+	"context"
+	"errors"
+	"time"
+
 	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/btcsuite/btcd/btcutil"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
@@ -8,6 +12,12 @@ import (
 	notifier "github.com/lightningnetwork/lnd/chainntnfs"
 )
 
+var ErrTxLabelsNotSupported = errors.New("wallet backend does not support transaction labels")
+
+var ErrRescanNotSupported = errors.New("wallet backend does not support rescanning from given height")
+
+var ErrOutputNotFound = errors.New("output not found in utxo set")
+
 type TxStatus int
 
 const (
@@ -16,24 +26,89 @@ const (
 	TxInChain
 )
 
+// RescanProgress is progress of the rescan reported by wallet backend
+type RescanProgress struct {
+	Duration time.Duration
+	// fraction of blocks already scanned, between 0 and 1
+	Progress float64
+}
+
+// SyncStatus is chain sync status reported by wallet backend
+type SyncStatus struct {
+	BestBlockHeight uint32
+	BestBlockHash   chainhash.Hash
+	// whether node backing the wallet is in initial block download
+	InitialBlockDownload bool
+	// whether wallet is rescanning the chain for its transactions
+	Rescanning bool
+}
+
+// WalletAddress is address known to the wallet
+type WalletAddress struct {
+	Address btcutil.Address
+	// Spendable is false for watch-only addresses, for which wallet cannot sign
+	Spendable bool
+}
+
 type WalletController interface {
-	UnlockWallet(timeoutSecs int64) error
+	// Ping checks that wallet backend is reachable
+	Ping() error
+	// UnlockWallet, DumpPrivateKey, SignRawTransaction and CreateAndSignTx return
+	// ErrWalletTimeout if wallet does not respond before deadline of the context
+	UnlockWallet(ctx context.Context, timeoutSecs int64) error
 	AddressPublicKey(address btcutil.Address) (*btcec.PublicKey, error)
-	DumpPrivateKey(address btcutil.Address) (*btcec.PrivateKey, error)
+	DumpPrivateKey(ctx context.Context, address btcutil.Address) (*btcec.PrivateKey, error)
 	ImportPrivKey(privKeyWIF *btcutil.WIF) error
 	NetworkName() string
 	CreateTransaction(
 		outputs []*wire.TxOut,
 		feeRatePerKb btcutil.Amount,
 		changeScript btcutil.Address) (*wire.MsgTx, error)
-	SignRawTransaction(tx *wire.MsgTx) (*wire.MsgTx, bool, error)
+	// FundTxEstimate selects wallet utxos funding outputs and fee, the same way as
+	// CreateTransaction, without building signed transaction. Does not require
+	// wallet to be unlocked.
+	FundTxEstimate(
+		outputs []*wire.TxOut,
+		feeRatePerKb btcutil.Amount,
+		changeAddress btcutil.Address) (*FundingEstimate, error)
+	SignRawTransaction(ctx context.Context, tx *wire.MsgTx) (*wire.MsgTx, bool, error)
 	// requires wallet to be unlocked
 	CreateAndSignTx(
+		ctx context.Context,
 		output []*wire.TxOut,
 		feeRatePerKb btcutil.Amount,
 		changeAddress btcutil.Address,
 	) (*wire.MsgTx, error)
 	SendRawTransaction(tx *wire.MsgTx, allowHighFees bool) (*chainhash.Hash, error)
 	ListOutputs(onlySpendable bool) ([]Utxo, error)
+	// ListSentTransactions returns hashes of all wallet transactions which send
+	// wallet funds
+	ListSentTransactions() ([]chainhash.Hash, error)
 	TxDetails(txHash *chainhash.Hash, pkScript []byte) (*notifier.TxConfirmation, TxStatus, error)
+	// SetTxLabel attaches label to wallet transaction. Returns ErrTxLabelsNotSupported
+	// if wallet backend does not support transaction labels
+	SetTxLabel(txHash *chainhash.Hash, label string) error
+	// RescanBlocks rescans chain for wallet transactions starting from block at
+	// startHeight. Returns ErrRescanNotSupported if wallet backend cannot rescan
+	// from given height
+	RescanBlocks(startHeight uint32) error
+	// ImportAddressWithRescan imports output script as watch-only and rescans chain
+	// for its transactions starting from block at startHeight
+	ImportAddressWithRescan(pkScript []byte, startHeight uint32) error
+	// RescanProgress returns progress of the rescan wallet is currently performing,
+	// or nil if wallet is not rescanning or backend does not report progress
+	RescanProgress() (*RescanProgress, error)
+	// SyncStatus returns best block known to the wallet and whether it is still
+	// syncing the chain. Results of wallet queries are not reliable until wallet
+	// is synced.
+	SyncStatus() (*SyncStatus, error)
+	// UnspentOutput returns output from utxo set of the node, including outputs
+	// created by mempool transactions. Returns ErrOutputNotFound if output does not
+	// exist or is already spent.
+	UnspentOutput(outpoint *wire.OutPoint) (*wire.TxOut, error)
+	// ListAddresses returns addresses of the wallet, including watch-only ones.
+	// Does not require wallet to be unlocked.
+	ListAddresses() ([]WalletAddress, error)
+	// WalletLocked returns whether wallet is encrypted and currently locked
+	WalletLocked() (bool, error)
 }
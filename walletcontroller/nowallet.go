@@ -0,0 +1,210 @@
+package walletcontroller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/babylonchain/btc-staker/stakercfg"
+	"github.com/babylonchain/btc-staker/types"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/rpcclient"
+	"github.com/btcsuite/btcd/wire"
+	notifier "github.com/lightningnetwork/lnd/chainntnfs"
+)
+
+// ErrWalletDisabled is returned by operations which require btc wallet when daemon
+// runs without one
+var ErrWalletDisabled = errors.New("btc wallet is disabled, daemon runs in watch-only mode")
+
+// NodeWalletController is used in place of wallet when daemon runs without btc
+// wallet. Operations which only need chain data are served by btc node backend,
+// while operations which need wallet keys or funds return ErrWalletDisabled.
+type NodeWalletController struct {
+	*rpcclient.Client
+	network     string
+	nodeBackend types.SupportedNodeBackend
+}
+
+var _ WalletController = (*NodeWalletController)(nil)
+
+func NewNodeWalletController(scfg *stakercfg.Config) (*NodeWalletController, error) {
+	var connCfg *rpcclient.ConnConfig
+
+	switch scfg.BtcNodeBackendConfig.ActiveNodeBackend {
+	case types.BitcoindNodeBackend:
+		connCfg = &rpcclient.ConnConfig{
+			Host:                 scfg.BtcNodeBackendConfig.Bitcoind.RPCHost,
+			User:                 scfg.BtcNodeBackendConfig.Bitcoind.RPCUser,
+			Pass:                 scfg.BtcNodeBackendConfig.Bitcoind.RPCPass,
+			DisableTLS:           true,
+			DisableConnectOnNew:  true,
+			DisableAutoReconnect: false,
+			HTTPPostMode:         true,
+		}
+	case types.BtcdNodeBackend:
+		cert, err := stakercfg.ReadCertFile(
+			scfg.BtcNodeBackendConfig.Btcd.RawRPCCert,
+			scfg.BtcNodeBackendConfig.Btcd.RPCCert,
+		)
+
+		if err != nil {
+			return nil, err
+		}
+
+		connCfg = &rpcclient.ConnConfig{
+			Host:                 scfg.BtcNodeBackendConfig.Btcd.RPCHost,
+			User:                 scfg.BtcNodeBackendConfig.Btcd.RPCUser,
+			Pass:                 scfg.BtcNodeBackendConfig.Btcd.RPCPass,
+			Certificates:         cert,
+			DisableTLS:           false,
+			DisableConnectOnNew:  true,
+			DisableAutoReconnect: false,
+			HTTPPostMode:         true,
+		}
+	default:
+		return nil, fmt.Errorf("invalid node backend")
+	}
+
+	host, err := stakercfg.NormalizeRpcHost(
+		connCfg.Host,
+		stakercfg.DefaultNodeRpcPort(scfg.BtcNodeBackendConfig.ActiveNodeBackend, scfg.ActiveNetParams.Name),
+	)
+
+	if err != nil {
+		return nil, fmt.Errorf("invalid btc node host: %w", err)
+	}
+
+	connCfg.Host = host
+
+	if proxy := scfg.ProxyConfig.BtcNodeProxy(); proxy != nil {
+		proxy.ApplyToRpcClient(connCfg)
+	}
+
+	client, err := rpcclient.New(connCfg, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &NodeWalletController{
+		Client:      client,
+		network:     scfg.ActiveNetParams.Name,
+		nodeBackend: scfg.BtcNodeBackendConfig.ActiveNodeBackend,
+	}, nil
+}
+
+// Ping checks that btc node is reachable
+func (w *NodeWalletController) Ping() error {
+	_, err := w.GetBlockCount()
+	return err
+}
+
+func (w *NodeWalletController) UnlockWallet(_ context.Context, _ int64) error {
+	return ErrWalletDisabled
+}
+
+func (w *NodeWalletController) AddressPublicKey(_ btcutil.Address) (*btcec.PublicKey, error) {
+	return nil, ErrWalletDisabled
+}
+
+func (w *NodeWalletController) DumpPrivateKey(_ context.Context, _ btcutil.Address) (*btcec.PrivateKey, error) {
+	return nil, ErrWalletDisabled
+}
+
+func (w *NodeWalletController) ImportPrivKey(_ *btcutil.WIF) error {
+	return ErrWalletDisabled
+}
+
+func (w *NodeWalletController) NetworkName() string {
+	return w.network
+}
+
+func (w *NodeWalletController) CreateTransaction(
+	_ []*wire.TxOut,
+	_ btcutil.Amount,
+	_ btcutil.Address) (*wire.MsgTx, error) {
+	return nil, ErrWalletDisabled
+}
+
+func (w *NodeWalletController) FundTxEstimate(
+	_ []*wire.TxOut,
+	_ btcutil.Amount,
+	_ btcutil.Address) (*FundingEstimate, error) {
+	return nil, ErrWalletDisabled
+}
+
+func (w *NodeWalletController) SignRawTransaction(_ context.Context, _ *wire.MsgTx) (*wire.MsgTx, bool, error) {
+	return nil, false, ErrWalletDisabled
+}
+
+func (w *NodeWalletController) CreateAndSignTx(
+	_ context.Context,
+	_ []*wire.TxOut,
+	_ btcutil.Amount,
+	_ btcutil.Address,
+) (*wire.MsgTx, error) {
+	return nil, ErrWalletDisabled
+}
+
+func (w *NodeWalletController) SendRawTransaction(tx *wire.MsgTx, allowHighFees bool) (*chainhash.Hash, error) {
+	return w.Client.SendRawTransaction(tx, allowHighFees)
+}
+
+func (w *NodeWalletController) ListOutputs(_ bool) ([]Utxo, error) {
+	return nil, ErrWalletDisabled
+}
+
+func (w *NodeWalletController) ListSentTransactions() ([]chainhash.Hash, error) {
+	return nil, ErrWalletDisabled
+}
+
+// TxDetails fetches info about transaction from node mempool or blockchain,
+// requires node to have enabled transaction index
+func (w *NodeWalletController) TxDetails(txHash *chainhash.Hash, pkScript []byte) (*notifier.TxConfirmation, TxStatus, error) {
+	switch w.nodeBackend {
+	case types.BitcoindNodeBackend:
+		return txDetails(w.Client, txHash, pkScript, txNotFoundErrMsgBitcoind)
+	case types.BtcdNodeBackend:
+		return txDetails(w.Client, txHash, pkScript, txNotFoundErrMsgBtcd)
+	default:
+		return nil, TxNotFound, fmt.Errorf("invalid node backend")
+	}
+}
+
+func (w *NodeWalletController) SetTxLabel(_ *chainhash.Hash, _ string) error {
+	return ErrTxLabelsNotSupported
+}
+
+func (w *NodeWalletController) RescanBlocks(_ uint32) error {
+	return ErrWalletDisabled
+}
+
+func (w *NodeWalletController) ImportAddressWithRescan(_ []byte, _ uint32) error {
+	return ErrWalletDisabled
+}
+
+// RescanProgress returns nil, as there is no wallet which could be rescanning
+func (w *NodeWalletController) RescanProgress() (*RescanProgress, error) {
+	return nil, nil
+}
+
+// SyncStatus returns sync status of the chain of the node backend, as there is
+// no wallet which could be syncing
+func (w *NodeWalletController) SyncStatus() (*SyncStatus, error) {
+	return chainSyncStatus(w.Client)
+}
+
+func (w *NodeWalletController) UnspentOutput(outpoint *wire.OutPoint) (*wire.TxOut, error) {
+	return unspentOutput(w.Client, outpoint)
+}
+
+func (w *NodeWalletController) ListAddresses() ([]WalletAddress, error) {
+	return nil, ErrWalletDisabled
+}
+
+func (w *NodeWalletController) WalletLocked() (bool, error) {
+	return false, ErrWalletDisabled
+}
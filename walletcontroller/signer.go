@@ -0,0 +1,132 @@
+package walletcontroller
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/btcsuite/btcd/btcutil"
+)
+
+// SignerSession provides access to staker key for the duration of one high level
+// operation i.e staking, unbonding or spending staking output. Key material
+// must not be used after session is closed.
+// Signers which do not export private keys can implement this interface by
+// returning error from PrivateKey and doing signing in SignSchnorr.
+type SignerSession interface {
+	PubKey() (*btcec.PublicKey, error)
+	PrivateKey() (*btcec.PrivateKey, error)
+	SignSchnorr(msgHash []byte) (*schnorr.Signature, error)
+	// Close clears all key material retrieved during the session. It is safe
+	// to call it multiple times.
+	Close()
+}
+
+// DumpKeySignerSession is SignerSession backed by wallet DumpPrivateKey rpc.
+// Private key is retrieved from the wallet at most once per session, and zeroed
+// when session is closed. Each wallet request is abandoned when session context
+// is done or when wallet does not respond in rpcTimeout, in which case
+// ErrWalletTimeout is returned and key is retrieved again on next use.
+type DumpKeySignerSession struct {
+	mu            sync.Mutex
+	ctx           context.Context
+	wc            WalletController
+	address       btcutil.Address
+	unlockTimeout int64
+	rpcTimeout    time.Duration
+	privKey       *btcec.PrivateKey
+	closed        bool
+}
+
+var _ SignerSession = (*DumpKeySignerSession)(nil)
+
+func NewDumpKeySignerSession(
+	ctx context.Context,
+	wc WalletController,
+	address btcutil.Address,
+	unlockTimeoutSecs int64,
+	rpcTimeout time.Duration,
+) *DumpKeySignerSession {
+	return &DumpKeySignerSession{
+		ctx:           ctx,
+		wc:            wc,
+		address:       address,
+		unlockTimeout: unlockTimeoutSecs,
+		rpcTimeout:    rpcTimeout,
+	}
+}
+
+func (s *DumpKeySignerSession) PrivateKey() (*btcec.PrivateKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return nil, fmt.Errorf("signer session for address %s is already closed", s.address)
+	}
+
+	if s.privKey != nil {
+		return s.privKey, nil
+	}
+
+	unlockCtx, cancelUnlock := context.WithTimeout(s.ctx, s.rpcTimeout)
+	err := s.wc.UnlockWallet(unlockCtx, s.unlockTimeout)
+	cancelUnlock()
+
+	if err != nil {
+		return nil, err
+	}
+
+	dumpCtx, cancelDump := context.WithTimeout(s.ctx, s.rpcTimeout)
+	privKey, err := s.wc.DumpPrivateKey(dumpCtx, s.address)
+	cancelDump()
+
+	if err != nil {
+		return nil, err
+	}
+
+	// make sure wallet returned key which controls the address, otherwise
+	// pop and all signatures would be done by wrong key
+	if err := KeyMatchesAddress(privKey.PubKey(), s.address); err != nil {
+		privKey.Zero()
+		return nil, err
+	}
+
+	s.privKey = privKey
+
+	return privKey, nil
+}
+
+func (s *DumpKeySignerSession) PubKey() (*btcec.PublicKey, error) {
+	privKey, err := s.PrivateKey()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return privKey.PubKey(), nil
+}
+
+func (s *DumpKeySignerSession) SignSchnorr(msgHash []byte) (*schnorr.Signature, error) {
+	privKey, err := s.PrivateKey()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return schnorr.Sign(privKey, msgHash)
+}
+
+func (s *DumpKeySignerSession) Close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.privKey != nil {
+		s.privKey.Zero()
+		s.privKey = nil
+	}
+
+	s.closed = true
+}
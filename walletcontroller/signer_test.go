@@ -0,0 +1,136 @@
+package walletcontroller_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/babylonchain/btc-staker/walletcontroller"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/stretchr/testify/require"
+)
+
+// countingWalletController counts calls to key related methods, all other methods
+// panic as they are not implemented.
+type countingWalletController struct {
+	walletcontroller.WalletController
+	privKey         *btcec.PrivateKey
+	numUnlockCalls  int
+	numDumpKeyCalls int
+}
+
+func (w *countingWalletController) UnlockWallet(_ context.Context, _ int64) error {
+	w.numUnlockCalls++
+	return nil
+}
+
+func (w *countingWalletController) DumpPrivateKey(_ context.Context, _ btcutil.Address) (*btcec.PrivateKey, error) {
+	w.numDumpKeyCalls++
+	// return copy, as session zeroes returned key on close
+	privKey, _ := btcec.PrivKeyFromBytes(w.privKey.Serialize())
+	return privKey, nil
+}
+
+func newCountingWalletController(t *testing.T) (*countingWalletController, btcutil.Address) {
+	privKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	addr, err := btcutil.NewAddressTaproot(
+		schnorr.SerializePubKey(privKey.PubKey()),
+		&chaincfg.SimNetParams,
+	)
+	require.NoError(t, err)
+
+	return &countingWalletController{privKey: privKey}, addr
+}
+
+func TestSignerSessionDumpsKeyOnce(t *testing.T) {
+	wc, addr := newCountingWalletController(t)
+	session := walletcontroller.NewDumpKeySignerSession(context.Background(), wc, addr, 15, time.Second)
+
+	pubKey, err := session.PubKey()
+	require.NoError(t, err)
+	require.True(t, pubKey.IsEqual(wc.privKey.PubKey()))
+
+	msgHash := chainhash.HashB([]byte("msg"))
+	sig, err := session.SignSchnorr(msgHash)
+	require.NoError(t, err)
+	require.True(t, sig.Verify(msgHash, wc.privKey.PubKey()))
+
+	privKey, err := session.PrivateKey()
+	require.NoError(t, err)
+
+	require.Equal(t, 1, wc.numUnlockCalls)
+	require.Equal(t, 1, wc.numDumpKeyCalls)
+
+	session.Close()
+
+	// key returned from the session is cleared after close
+	require.True(t, privKey.Key.IsZero())
+
+	_, err = session.PrivateKey()
+	require.Error(t, err)
+	require.Equal(t, 1, wc.numDumpKeyCalls)
+
+	// closing session twice is safe
+	session.Close()
+}
+
+func TestSignerSessionClosedWithoutKey(t *testing.T) {
+	wc, addr := newCountingWalletController(t)
+	session := walletcontroller.NewDumpKeySignerSession(context.Background(), wc, addr, 15, time.Second)
+	session.Close()
+
+	_, err := session.PubKey()
+	require.Error(t, err)
+	require.Equal(t, 0, wc.numDumpKeyCalls)
+}
+
+func TestKeyMatchesAddress(t *testing.T) {
+	privKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+	pubKey := privKey.PubKey()
+	net := &chaincfg.SimNetParams
+
+	p2wpkh, err := btcutil.NewAddressWitnessPubKeyHash(btcutil.Hash160(pubKey.SerializeCompressed()), net)
+	require.NoError(t, err)
+	require.NoError(t, walletcontroller.KeyMatchesAddress(pubKey, p2wpkh))
+
+	// BIP-86 address commits to tweaked internal key
+	bip86, err := btcutil.NewAddressTaproot(
+		schnorr.SerializePubKey(txscript.ComputeTaprootKeyNoScript(pubKey)),
+		net,
+	)
+	require.NoError(t, err)
+	require.NoError(t, walletcontroller.KeyMatchesAddress(pubKey, bip86))
+
+	otherKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+	require.Error(t, walletcontroller.KeyMatchesAddress(otherKey.PubKey(), p2wpkh))
+	require.Error(t, walletcontroller.KeyMatchesAddress(otherKey.PubKey(), bip86))
+
+	// legacy addresses cannot be used for new stakes, but stakes already made
+	// with them must remain spendable
+	p2pkh, err := btcutil.NewAddressPubKeyHash(btcutil.Hash160(pubKey.SerializeCompressed()), net)
+	require.NoError(t, err)
+	require.Error(t, walletcontroller.ValidateStakerAddress(p2pkh))
+	require.NoError(t, walletcontroller.KeyMatchesAddress(pubKey, p2pkh))
+	require.Error(t, walletcontroller.KeyMatchesAddress(otherKey.PubKey(), p2pkh))
+
+	uncompressedP2pkh, err := btcutil.NewAddressPubKeyHash(btcutil.Hash160(pubKey.SerializeUncompressed()), net)
+	require.NoError(t, err)
+	require.NoError(t, walletcontroller.KeyMatchesAddress(pubKey, uncompressedP2pkh))
+
+	redeemScript, err := txscript.PayToAddrScript(p2wpkh)
+	require.NoError(t, err)
+	nestedP2wpkh, err := btcutil.NewAddressScriptHash(redeemScript, net)
+	require.NoError(t, err)
+	require.Error(t, walletcontroller.ValidateStakerAddress(nestedP2wpkh))
+	require.NoError(t, walletcontroller.KeyMatchesAddress(pubKey, nestedP2wpkh))
+	require.Error(t, walletcontroller.KeyMatchesAddress(otherKey.PubKey(), nestedP2wpkh))
+}
@@ -0,0 +1,81 @@
+package walletcontroller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrWalletTimeout is returned when wallet does not respond to the request before
+// deadline of the request context
+type ErrWalletTimeout struct {
+	Method string
+}
+
+func (e *ErrWalletTimeout) Error() string {
+	return fmt.Sprintf("wallet did not respond to %s request in time", e.Method)
+}
+
+func (e *ErrWalletTimeout) Unwrap() error { return context.DeadlineExceeded }
+
+// IsWalletTimeout returns true if error was caused by wallet not responding in time
+func IsWalletTimeout(err error) bool {
+	var timeoutErr *ErrWalletTimeout
+	return errors.As(err, &timeoutErr)
+}
+
+// callWithContext waits for result of wallet rpc call until context is done.
+// Rpc client does not support cancelling requests, so abandoned call keeps running
+// in background. Its result is passed to discard, if it is not nil, so that e.g.
+// key material returned after deadline can be cleared.
+func callWithContext[T any](
+	ctx context.Context,
+	method string,
+	call func() (T, error),
+	discard func(T),
+) (T, error) {
+	var zero T
+
+	if err := contextErr(ctx, method); err != nil {
+		return zero, err
+	}
+
+	type result struct {
+		value T
+		err   error
+	}
+
+	// buffered, so that abandoned call does not leak goroutine
+	resultChan := make(chan result, 1)
+
+	go func() {
+		value, err := call()
+		resultChan <- result{value: value, err: err}
+	}()
+
+	select {
+	case r := <-resultChan:
+		return r.value, r.err
+
+	case <-ctx.Done():
+		if discard != nil {
+			go func() {
+				if r := <-resultChan; r.err == nil {
+					discard(r.value)
+				}
+			}()
+		}
+
+		return zero, contextErr(ctx, method)
+	}
+}
+
+func contextErr(ctx context.Context, method string) error {
+	err := ctx.Err()
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		return &ErrWalletTimeout{Method: method}
+	}
+
+	return err
+}
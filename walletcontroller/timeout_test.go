@@ -0,0 +1,77 @@
+package walletcontroller_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/babylonchain/btc-staker/types"
+	"github.com/babylonchain/btc-staker/walletcontroller"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/stretchr/testify/require"
+)
+
+// newHungWalletController returns wallet controller connected to rpc server which
+// never responds
+func newHungWalletController(t *testing.T) *walletcontroller.RpcWalletController {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {
+		<-release
+	}))
+	// cleanups run in reverse order, so hung requests are released before server
+	// is closed
+	t.Cleanup(server.Close)
+	t.Cleanup(func() { close(release) })
+
+	wc, err := walletcontroller.NewRpcWalletControllerFromArgs(
+		strings.TrimPrefix(server.URL, "http://"),
+		"user",
+		"pass",
+		chaincfg.SimNetParams.Name,
+		"walletpass",
+		types.BitcoindWalletBackend,
+		&chaincfg.SimNetParams,
+		true,
+		nil,
+	)
+	require.NoError(t, err)
+	t.Cleanup(wc.Shutdown)
+
+	return wc
+}
+
+func TestWalletRequestTimeout(t *testing.T) {
+	wc := newHungWalletController(t)
+	_, addr := newCountingWalletController(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := wc.DumpPrivateKey(ctx, addr)
+	var timeoutErr *walletcontroller.ErrWalletTimeout
+	require.ErrorAs(t, err, &timeoutErr)
+	require.Equal(t, "dumpprivkey", timeoutErr.Method)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	// cancelled request is not reported as timeout
+	ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+
+	_, _, err = wc.SignRawTransaction(ctx, nil)
+	require.ErrorIs(t, err, context.Canceled)
+	require.False(t, walletcontroller.IsWalletTimeout(err))
+}
+
+func TestSignerSessionWalletTimeout(t *testing.T) {
+	wc := newHungWalletController(t)
+	_, addr := newCountingWalletController(t)
+
+	session := walletcontroller.NewDumpKeySignerSession(context.Background(), wc, addr, 15, 50*time.Millisecond)
+	defer session.Close()
+
+	_, err := session.PrivateKey()
+	require.True(t, walletcontroller.IsWalletTimeout(err))
+}
@@ -98,13 +98,30 @@ func buildTxFromOutputs(
 	outputs []*wire.TxOut,
 	feeRatePerKb btcutil.Amount,
 	changeScript []byte) (*wire.MsgTx, error) {
+	tx, _, err := fundTx(utxos, outputs, feeRatePerKb, changeScript)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return tx, nil
+}
+
+// fundTx selects utxos, in given order, funding outputs and fee, and builds
+// unsigned transaction from them. Estimate describes the selection, so that
+// estimates and signed transactions built from the same utxos always match.
+func fundTx(
+	utxos []Utxo,
+	outputs []*wire.TxOut,
+	feeRatePerKb btcutil.Amount,
+	changeScript []byte) (*wire.MsgTx, *FundingEstimate, error) {
 
 	if len(utxos) == 0 {
-		return nil, fmt.Errorf("there must be at least 1 usable UTXO to build transaction")
+		return nil, nil, fmt.Errorf("there must be at least 1 usable UTXO to build transaction")
 	}
 
 	if len(outputs) == 0 {
-		return nil, fmt.Errorf("there must be at least 1 output in transaction")
+		return nil, nil, fmt.Errorf("there must be at least 1 output in transaction")
 	}
 
 	ch := txauthor.ChangeSource{
@@ -124,8 +141,8 @@ func buildTxFromOutputs(
 	)
 
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	return authoredTx.Tx, nil
+	return authoredTx.Tx, newFundingEstimate(utxos, authoredTx, len(changeScript)), nil
 }
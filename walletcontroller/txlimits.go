@@ -0,0 +1,74 @@
+package walletcontroller
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/mempool"
+	"github.com/btcsuite/btcd/wire"
+)
+
+// ErrTxTooLarge transaction funded by wallet exceeds configured limits
+var ErrTxTooLarge = errors.New("transaction funded by wallet is too large")
+
+// TxLimits are limits of transaction funded by wallet. Zero value of a limit
+// disables it.
+type TxLimits struct {
+	MaxInputs uint32
+	MaxVsize  uint32
+}
+
+// TxLimitsError is returned when transaction funded by wallet exceeds TxLimits.
+// It reports actual size of the transaction next to the limits.
+type TxLimitsError struct {
+	NumInputs uint32
+	Vsize     uint32
+	MaxInputs uint32
+	MaxVsize  uint32
+}
+
+func (e *TxLimitsError) Error() string {
+	return fmt.Sprintf(
+		"%s: transaction has %d inputs (max %s) and virtual size of %d vbytes (max %s). Consolidate wallet utxos first, e.g. with consolidate_utxos",
+		ErrTxTooLarge, e.NumInputs, formatLimit(e.MaxInputs), e.Vsize, formatLimit(e.MaxVsize),
+	)
+}
+
+func formatLimit(limit uint32) string {
+	if limit == 0 {
+		return "unlimited"
+	}
+
+	return fmt.Sprintf("%d", limit)
+}
+
+func (e *TxLimitsError) Unwrap() error {
+	return ErrTxTooLarge
+}
+
+// CheckTxLimits checks that transaction does not exceed limits. Transaction
+// should be signed, as virtual size of unsigned transaction does not include
+// witness data.
+func CheckTxLimits(tx *wire.MsgTx, limits TxLimits) error {
+	return limits.check(
+		uint32(len(tx.TxIn)),
+		uint32(mempool.GetTxVirtualSize(btcutil.NewTx(tx))),
+	)
+}
+
+func (limits TxLimits) check(numInputs, vsize uint32) error {
+	inputsExceeded := limits.MaxInputs > 0 && numInputs > limits.MaxInputs
+	vsizeExceeded := limits.MaxVsize > 0 && vsize > limits.MaxVsize
+
+	if !inputsExceeded && !vsizeExceeded {
+		return nil
+	}
+
+	return &TxLimitsError{
+		NumInputs: numInputs,
+		Vsize:     vsize,
+		MaxInputs: limits.MaxInputs,
+		MaxVsize:  limits.MaxVsize,
+	}
+}
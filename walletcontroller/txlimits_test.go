@@ -0,0 +1,97 @@
+package walletcontroller
+
+import (
+	"bytes"
+	"sort"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/stretchr/testify/require"
+)
+
+func testP2WPKHScript(t *testing.T, seed byte) []byte {
+	addr, err := btcutil.NewAddressWitnessPubKeyHash(bytes.Repeat([]byte{seed}, 20), &chaincfg.RegressionNetParams)
+	require.NoError(t, err)
+
+	script, err := txscript.PayToAddrScript(addr)
+	require.NoError(t, err)
+
+	return script
+}
+
+// testUtxos returns utxos with given amounts, all locked by p2wpkh scripts
+func testUtxos(t *testing.T, amounts ...btcutil.Amount) []Utxo {
+	utxos := make([]Utxo, len(amounts))
+
+	for i, amount := range amounts {
+		utxos[i] = Utxo{
+			Amount:   amount,
+			OutPoint: wire.OutPoint{Hash: chainhash.Hash{byte(i), byte(i >> 8)}, Index: uint32(i)},
+			PkScript: testP2WPKHScript(t, byte(i)),
+		}
+	}
+
+	return utxos
+}
+
+// fundTestTx funds outputs with utxos the same way RpcWalletController does and
+// adds p2wpkh witnesses of the maximal size, to simulate signing by the wallet
+func fundTestTx(t *testing.T, utxos []Utxo, outputs []*wire.TxOut) *wire.MsgTx {
+	sorted := make([]Utxo, len(utxos))
+	copy(sorted, utxos)
+	sort.Sort(sort.Reverse(byAmount(sorted)))
+
+	tx, err := buildTxFromOutputs(sorted, outputs, btcutil.Amount(2000), testP2WPKHScript(t, 0xff))
+	require.NoError(t, err)
+
+	for _, in := range tx.TxIn {
+		in.Witness = wire.TxWitness{make([]byte, 73), make([]byte, 33)}
+	}
+
+	return tx
+}
+
+func repeatAmount(amount btcutil.Amount, count int) []btcutil.Amount {
+	amounts := make([]btcutil.Amount, count)
+	for i := range amounts {
+		amounts[i] = amount
+	}
+	return amounts
+}
+
+func TestCheckTxLimitsAcceptsTxFundedByFewUtxos(t *testing.T) {
+	utxos := testUtxos(t, 1_000_000, 5_000_000, 20_000)
+	stakingOutput := wire.NewTxOut(3_000_000, testP2WPKHScript(t, 0xaa))
+
+	tx := fundTestTx(t, utxos, []*wire.TxOut{stakingOutput})
+	require.Len(t, tx.TxIn, 1)
+
+	require.NoError(t, CheckTxLimits(tx, TxLimits{MaxInputs: 20, MaxVsize: 2000}))
+}
+
+func TestCheckTxLimitsRejectsTxFundedByManySmallUtxos(t *testing.T) {
+	utxos := testUtxos(t, repeatAmount(100_000, 45)...)
+	stakingOutput := wire.NewTxOut(4_000_000, testP2WPKHScript(t, 0xaa))
+
+	tx := fundTestTx(t, utxos, []*wire.TxOut{stakingOutput})
+	require.Len(t, tx.TxIn, 41)
+
+	err := CheckTxLimits(tx, TxLimits{MaxInputs: 20, MaxVsize: 2000})
+	require.ErrorIs(t, err, ErrTxTooLarge)
+
+	var limitsErr *TxLimitsError
+	require.ErrorAs(t, err, &limitsErr)
+	require.Equal(t, uint32(41), limitsErr.NumInputs)
+	require.Greater(t, limitsErr.Vsize, uint32(2000))
+	require.Contains(t, err.Error(), "consolidate")
+
+	// each limit is enforced on its own
+	require.ErrorIs(t, CheckTxLimits(tx, TxLimits{MaxInputs: 20}), ErrTxTooLarge)
+	require.ErrorIs(t, CheckTxLimits(tx, TxLimits{MaxVsize: 2000}), ErrTxTooLarge)
+	require.NoError(t, CheckTxLimits(tx, TxLimits{}))
+	require.NoError(t, CheckTxLimits(tx, TxLimits{MaxInputs: 41, MaxVsize: limitsErr.Vsize}))
+}